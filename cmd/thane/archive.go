@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
+)
+
+// runArchive dispatches the `thane archive <subcommand>` family. Only
+// prune exists today.
+func runArchive(stdout, stderr io.Writer, configPath, outputFmt string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: thane archive prune [--dry-run] [--retention-days N] [--max-messages N]")
+	}
+	switch args[0] {
+	case "prune":
+		return runArchivePrune(stdout, stderr, configPath, outputFmt, args[1:])
+	default:
+		return fmt.Errorf("unknown archive command: %s", args[0])
+	}
+}
+
+// runArchivePrune implements `thane archive prune`. The policy comes
+// from archive.retention_days and archive.max_messages in the config;
+// --retention-days and --max-messages override them for one run. With
+// --dry-run it reports what would be deleted and leaves the database
+// untouched. Active sessions and sessions tagged "keep" are never
+// pruned.
+func runArchivePrune(stdout, stderr io.Writer, configPath, outputFmt string, args []string) error {
+	cfg, _, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	policy, err := parseArchivePruneArgs(cfg.Archive, args)
+	if err != nil {
+		return err
+	}
+	if policy.MaxAge <= 0 && policy.MaxMessages <= 0 {
+		return fmt.Errorf("no retention limit set: configure archive.retention_days or archive.max_messages, or pass --retention-days / --max-messages")
+	}
+
+	store, closeStore, err := openArchiveStore(stderr, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	report, err := store.Prune(policy)
+	if err != nil {
+		return err
+	}
+
+	if outputFmt == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	writeArchivePruneText(stdout, report)
+	return nil
+}
+
+// openArchiveStore opens the archive in the configured data directory
+// for an offline CLI command. It refuses to create a fresh database so a
+// mistyped data_dir fails loudly instead of reporting an empty archive.
+// The returned func closes the underlying database.
+func openArchiveStore(stderr io.Writer, cfg *config.Config) (*memory.ArchiveStore, func(), error) {
+	dbPath := cfg.DataDir + "/thane.db"
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, nil, fmt.Errorf("open archive: %w", err)
+	}
+	logger := newLogger(stderr, slog.LevelWarn, "text")
+	mem, err := memory.NewSQLiteStoreWithLogger(dbPath, 100, logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open working store: %w", err)
+	}
+	store, err := memory.NewArchiveStoreFromDB(mem.DB(), nil, logger)
+	if err != nil {
+		mem.Close()
+		return nil, nil, fmt.Errorf("open archive store: %w", err)
+	}
+	return store, func() { mem.Close() }, nil
+}
+
+// parseArchivePruneArgs builds a prune policy from the archive config,
+// applying any command-line overrides.
+func parseArchivePruneArgs(cfg config.ArchiveConfig, args []string) (memory.PrunePolicy, error) {
+	days := cfg.RetentionDays
+	policy := memory.PrunePolicy{MaxMessages: cfg.MaxMessages}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--dry-run", "-n":
+			policy.DryRun = true
+			continue
+		case "--retention-days", "--max-messages":
+		default:
+			return policy, fmt.Errorf("unknown archive prune flag: %s", arg)
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return policy, fmt.Errorf("%s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return policy, fmt.Errorf("%s: %q is not a non-negative integer", name, value)
+		}
+		if name == "--retention-days" {
+			days = n
+		} else {
+			policy.MaxMessages = n
+		}
+	}
+
+	policy.MaxAge = time.Duration(days) * 24 * time.Hour
+	return policy, nil
+}
+
+// writeArchivePruneText prints one line per pruned session followed by
+// the totals.
+func writeArchivePruneText(w io.Writer, report *memory.PruneReport) {
+	verb := "Pruned"
+	if report.DryRun {
+		verb = "Would prune"
+	}
+	if len(report.Sessions) == 0 {
+		fmt.Fprintln(w, "Nothing to prune.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SESSION\tSTARTED\tENDED\tMESSAGES\tREASON")
+	for _, s := range report.Sessions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n",
+			memory.ShortID(s.ID),
+			s.StartedAt.Local().Format("2006-01-02 15:04"),
+			s.EndedAt.Local().Format("2006-01-02 15:04"),
+			s.Messages,
+			s.Reason,
+		)
+	}
+	_ = tw.Flush()
+	fmt.Fprintf(w, "\n%s %d sessions: %d messages, %d tool calls, %d iterations.\n",
+		verb, len(report.Sessions), report.Messages, report.ToolCalls, report.Iterations)
+}
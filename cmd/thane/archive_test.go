@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+)
+
+func TestParseArchivePruneArgs(t *testing.T) {
+	cfg := config.ArchiveConfig{RetentionDays: 90, MaxMessages: 1000}
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantAge   time.Duration
+		wantCap   int
+		wantDry   bool
+		wantError string
+	}{
+		{name: "config defaults", wantAge: 90 * 24 * time.Hour, wantCap: 1000},
+		{name: "dry run", args: []string{"--dry-run"}, wantAge: 90 * 24 * time.Hour, wantCap: 1000, wantDry: true},
+		{name: "overrides", args: []string{"--retention-days", "7", "--max-messages=50"}, wantAge: 7 * 24 * time.Hour, wantCap: 50},
+		{name: "disable cap", args: []string{"--max-messages", "0"}, wantAge: 90 * 24 * time.Hour},
+		{name: "missing value", args: []string{"--retention-days"}, wantError: "requires a value"},
+		{name: "negative", args: []string{"--retention-days=-1"}, wantError: "non-negative"},
+		{name: "unknown flag", args: []string{"--force"}, wantError: "unknown archive prune flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := parseArchivePruneArgs(cfg, tt.args)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if policy.MaxAge != tt.wantAge || policy.MaxMessages != tt.wantCap || policy.DryRun != tt.wantDry {
+				t.Errorf("policy = %+v, want age %v cap %d dry %v", policy, tt.wantAge, tt.wantCap, tt.wantDry)
+			}
+		})
+	}
+}
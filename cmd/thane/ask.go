@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/model/talents"
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+	"github.com/nugget/thane-ai-agent/internal/runtime/agent"
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
+)
+
+const askUsage = "usage: thane ask [--stream] [--conversation <id>] <question>"
+
+// askConversationID is the conversation a one-shot ask runs in when no
+// --conversation is given. Nothing is kept, so every ask starts fresh.
+const askConversationID = "cli-test"
+
+// askArgs holds the parsed arguments of `thane ask`.
+type askArgs struct {
+	Question       string
+	ConversationID string
+	Stream         bool
+}
+
+// runAsk handles the "thane ask <question>" subcommand. It boots a
+// minimal agent (no router, no scheduler) and processes a single
+// question, printing the response to stdout. Useful for quick smoke
+// tests and debugging without starting the server.
+//
+// By default the conversation lives in a throwaway in-memory store.
+// With --conversation it is read from and written to thane.db in the
+// data directory, with session archiving as the server does it, so
+// follow-up asks with the same ID continue the conversation (and the
+// server sees it too). With --stream, tokens are written to stdout as
+// the model produces them and logs go to stderr so they don't
+// interleave with the answer.
+func runAsk(ctx context.Context, stdout io.Writer, stderr io.Writer, configPath string, args []string) error {
+	parsed, err := parseAskArgs(args)
+	if err != nil {
+		return err
+	}
+
+	logOut := stdout
+	if parsed.Stream {
+		logOut = stderr
+	}
+	logger := newLogger(logOut, slog.LevelInfo, "text")
+
+	cfg, cfgPath, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	logger.Info("config loaded", "path", cfgPath)
+
+	// Home Assistant client (optional — ask works without it)
+	var ha *homeassistant.Client
+	if cfg.HomeAssistant.Configured() {
+		ha = homeassistant.NewClient(cfg.HomeAssistant.URL, cfg.HomeAssistant.Token, logger)
+	}
+
+	llmSetup, err := createLLMSetup(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+	logLLMSetup(logger, llmSetup)
+
+	talentLoader := talents.NewLoader(cfg.TalentsDir)
+	cliTalents, _ := talentLoader.Talents()
+
+	convID := askConversationID
+	var mem agent.MemoryStore
+	var archiver agent.SessionArchiver
+	if parsed.ConversationID != "" {
+		convID = parsed.ConversationID
+		store, adapter, err := openAskConversation(cfg, logger)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		mem, archiver = store, adapter
+	} else {
+		// In-memory store is fine for a single question — nothing to persist.
+		mem = memory.NewStore(100)
+	}
+
+	// Minimal loop: no router, no scheduler, no compactor. The default
+	// model handles everything for CLI one-shots.
+	var haInject homeassistant.StateFetcher
+	if ha != nil {
+		haInject = ha
+	}
+	loop, err := agent.NewLoop(agent.LoopOptions{
+		Logger:        logger,
+		Memory:        mem,
+		Archiver:      archiver,
+		HomeAssistant: ha,
+		LLM:           llmSetup.Client,
+		Model:         llmSetup.Catalog.DefaultModel,
+		ParsedTalents: cliTalents,
+		HAInject:      haInject,
+	})
+	if err != nil {
+		return fmt.Errorf("build agent loop: %w", err)
+	}
+
+	var stream agent.StreamCallback
+	streamed := false
+	if parsed.Stream {
+		stream = func(ev llm.StreamEvent) {
+			if ev.Kind == llm.KindToken && ev.Token != "" {
+				streamed = true
+				io.WriteString(stdout, ev.Token)
+			}
+		}
+	}
+
+	resp, err := loop.Run(ctx, &agent.Request{
+		ConversationID: convID,
+		Messages:       []agent.Message{{Role: "user", Content: parsed.Question}},
+	}, stream)
+	if err != nil {
+		if streamed {
+			fmt.Fprintln(stdout)
+		}
+		return fmt.Errorf("ask: %w", err)
+	}
+
+	// A model that doesn't stream delivers its answer only at the end.
+	if streamed {
+		fmt.Fprintln(stdout)
+	} else {
+		fmt.Fprintln(stdout, resp.Content)
+	}
+	return nil
+}
+
+// openAskConversation opens the persistent conversation store in the
+// data directory and an archive adapter over it, wired the way the
+// server wires them. The caller closes the returned store.
+func openAskConversation(cfg *config.Config, logger *slog.Logger) (*memory.SQLiteStore, *memory.ArchiveAdapter, error) {
+	mem, err := memory.NewSQLiteStoreWithLogger(cfg.DataDir+"/thane.db", 100, logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open conversation store: %w", err)
+	}
+	archiveStore, err := memory.NewArchiveStoreFromDB(mem.DB(), nil, logger)
+	if err != nil {
+		mem.Close()
+		return nil, nil, fmt.Errorf("open archive store: %w", err)
+	}
+	adapter := memory.NewArchiveAdapter(archiveStore, mem, mem, logger)
+	if cfg.Archive.IncrementalEnabled() {
+		mem.SetSessionResolver(adapter.ActiveSessionID)
+	}
+	return mem, adapter, nil
+}
+
+// parseAskArgs parses the arguments of `thane ask`. Everything that is
+// not a flag is joined into the question.
+func parseAskArgs(args []string) (askArgs, error) {
+	var parsed askArgs
+	var words []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			words = append(words, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--stream":
+			if !hasValue {
+				parsed.Stream = true
+				continue
+			}
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return parsed, fmt.Errorf("--stream: %q is not a boolean", value)
+			}
+			parsed.Stream = b
+			continue
+		case "--conversation":
+		default:
+			return parsed, fmt.Errorf("unknown ask flag: %s", arg)
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return parsed, fmt.Errorf("%s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		if value == "" {
+			return parsed, fmt.Errorf("%s requires a value", name)
+		}
+		parsed.ConversationID = value
+	}
+
+	parsed.Question = strings.Join(words, " ")
+	if strings.TrimSpace(parsed.Question) == "" {
+		return parsed, fmt.Errorf("%s", askUsage)
+	}
+	return parsed, nil
+}
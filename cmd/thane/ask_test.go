@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAskArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		want      askArgs
+		wantError string
+	}{
+		{name: "question", args: []string{"what", "time", "is", "it?"}, want: askArgs{Question: "what time is it?"}},
+		{name: "stream", args: []string{"--stream", "hello"}, want: askArgs{Question: "hello", Stream: true}},
+		{name: "stream false", args: []string{"hello", "--stream=false"}, want: askArgs{Question: "hello"}},
+		{name: "conversation", args: []string{"--conversation", "garage", "is", "it", "open?"}, want: askArgs{Question: "is it open?", ConversationID: "garage"}},
+		{name: "conversation inline", args: []string{"hello", "--conversation=garage", "--stream"}, want: askArgs{Question: "hello", ConversationID: "garage", Stream: true}},
+		{name: "no question", args: []string{"--stream"}, wantError: "usage"},
+		{name: "missing value", args: []string{"hello", "--conversation"}, wantError: "requires a value"},
+		{name: "empty value", args: []string{"hello", "--conversation="}, wantError: "requires a value"},
+		{name: "bad bool", args: []string{"hello", "--stream=maybe"}, wantError: "not a boolean"},
+		{name: "unknown flag", args: []string{"--json", "hello"}, wantError: "unknown ask flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAskArgs(tt.args)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("parseAskArgs(%q) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
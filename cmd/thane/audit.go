@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/audit"
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+)
+
+// auditUsage is returned for a malformed `thane audit` call.
+const auditUsage = "usage: thane audit [--tool NAME] [--conversation ID] [--since T] [--until T] [--limit N] [--redact]"
+
+// auditArgsPreview caps the argument column of the text listing.
+const auditArgsPreview = 80
+
+// auditArgs are the parsed flags of `thane audit`.
+type auditArgs struct {
+	filter audit.Filter
+	redact bool
+}
+
+// runAudit implements `thane audit`, which lists tool executions from
+// the audit log in thane.db, newest first. Like `thane usage report` it
+// reads the database directly, so it works whether or not the server is
+// running. --redact replaces every argument value in the output, for
+// sharing a listing without its contents; tools listed in
+// audit.redact_tools are already stored redacted.
+func runAudit(ctx context.Context, stdout, stderr io.Writer, configPath, outputFmt string, args []string) error {
+	cfg, _, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	loc := time.Local
+	if cfg.Timezone != "" {
+		loc, _ = time.LoadLocation(cfg.Timezone) // already validated
+	}
+
+	parsed, err := parseAuditArgs(args, time.Now(), loc)
+	if err != nil {
+		return err
+	}
+
+	dbPath := cfg.DataDir + "/thane.db"
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	db, err := database.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	defer db.Close()
+	store, err := audit.NewStore(db, newLogger(stderr, slog.LevelWarn, "text"), nil)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+
+	entries, err := store.Query(ctx, parsed.filter)
+	if err != nil {
+		return err
+	}
+	if parsed.redact {
+		for i := range entries {
+			if !entries[i].Redacted {
+				entries[i].Arguments = audit.RedactArguments(entries[i].Arguments)
+				entries[i].Redacted = true
+			}
+		}
+	}
+
+	if outputFmt == "json" {
+		if entries == nil {
+			entries = []audit.Entry{}
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	writeAuditText(stdout, entries, loc)
+	return nil
+}
+
+// parseAuditArgs parses the flags of `thane audit`. now and loc anchor
+// relative and date-only times, as in `thane usage report`.
+func parseAuditArgs(args []string, now time.Time, loc *time.Location) (auditArgs, error) {
+	var parsed auditArgs
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--redact":
+			if hasValue {
+				return parsed, fmt.Errorf("--redact takes no value")
+			}
+			parsed.redact = true
+			continue
+		case "--tool", "--conversation", "--since", "--until", "--limit":
+		default:
+			return parsed, fmt.Errorf("unknown audit flag: %s\n%s", arg, auditUsage)
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return parsed, fmt.Errorf("%s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		switch name {
+		case "--tool":
+			parsed.filter.Tool = value
+		case "--conversation":
+			parsed.filter.ConversationID = value
+		case "--since", "--until":
+			t, err := parseUsageTime(value, now, loc)
+			if err != nil {
+				return parsed, fmt.Errorf("%s: %w", name, err)
+			}
+			if name == "--since" {
+				parsed.filter.Since = t
+			} else {
+				parsed.filter.Until = t
+			}
+		case "--limit":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return parsed, fmt.Errorf("--limit: %q is not a positive number", value)
+			}
+			parsed.filter.Limit = n
+		}
+	}
+	if !parsed.filter.Since.IsZero() && !parsed.filter.Until.IsZero() && !parsed.filter.Since.Before(parsed.filter.Until) {
+		return parsed, fmt.Errorf("--since must be before --until")
+	}
+	return parsed, nil
+}
+
+// writeAuditText prints one line per audited call.
+func writeAuditText(w io.Writer, entries []audit.Entry, loc *time.Location) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No audited tool calls match.")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tTOOL\tDURATION\tSTATUS\tCONVERSATION\tSOURCE\tARGUMENTS")
+	for _, e := range entries {
+		status := "ok"
+		switch {
+		case e.CompletedAt == nil:
+			status = "incomplete"
+		case e.ErrorKind != "":
+			status = "error:" + e.ErrorKind
+		case e.Error != "":
+			status = "error"
+		}
+		source := e.Source
+		if e.Channel != "" && e.Channel != source {
+			source = strings.TrimPrefix(source+"/"+e.Channel, "/")
+		}
+		if e.Sender != "" {
+			source += " (" + e.Sender + ")"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.StartedAt.In(loc).Format("2006-01-02 15:04:05"),
+			e.Tool,
+			(time.Duration(e.DurationMS) * time.Millisecond).String(),
+			status,
+			e.ConversationID,
+			source,
+			previewArguments(e.Arguments),
+		)
+	}
+	_ = tw.Flush()
+}
+
+// previewArguments shortens argsJSON to one line of at most
+// auditArgsPreview characters.
+func previewArguments(argsJSON string) string {
+	preview := strings.Join(strings.Fields(argsJSON), " ")
+	if r := []rune(preview); len(r) > auditArgsPreview {
+		preview = string(r[:auditArgsPreview-1]) + "…"
+	}
+	return preview
+}
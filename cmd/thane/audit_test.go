@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/audit"
+)
+
+func TestParseAuditArgs(t *testing.T) {
+	loc := time.UTC
+	now := time.Date(2026, 3, 14, 15, 0, 0, 0, loc)
+
+	tests := []struct {
+		name       string
+		args       []string
+		want       audit.Filter
+		wantRedact bool
+		wantError  string
+	}{
+		{name: "defaults"},
+		{name: "filters", args: []string{"--tool", "shell_exec", "--since=7d", "--conversation", "signal-1", "--limit", "20"},
+			want: audit.Filter{Tool: "shell_exec", ConversationID: "signal-1", Since: now.AddDate(0, 0, -7), Limit: 20}},
+		{name: "range", args: []string{"--since", "2026-03-01", "--until=2026-03-08", "--redact"},
+			want:       audit.Filter{Since: time.Date(2026, 3, 1, 0, 0, 0, 0, loc), Until: time.Date(2026, 3, 8, 0, 0, 0, 0, loc)},
+			wantRedact: true},
+		{name: "bad limit", args: []string{"--limit", "0"}, wantError: "positive number"},
+		{name: "bad time", args: []string{"--since", "yesterday"}, wantError: "is not a date"},
+		{name: "inverted", args: []string{"--since", "2026-03-10", "--until", "2026-03-01"}, wantError: "before --until"},
+		{name: "missing value", args: []string{"--tool"}, wantError: "requires a value"},
+		{name: "redact value", args: []string{"--redact=yes"}, wantError: "takes no value"},
+		{name: "unknown flag", args: []string{"--csv"}, wantError: "unknown audit flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseAuditArgs(tt.args, now, loc)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			f := parsed.filter
+			if f.Tool != tt.want.Tool || f.ConversationID != tt.want.ConversationID || f.Limit != tt.want.Limit ||
+				!f.Since.Equal(tt.want.Since) || !f.Until.Equal(tt.want.Until) || parsed.redact != tt.wantRedact {
+				t.Errorf("parsed = %+v, want %+v redact %v", parsed, tt.want, tt.wantRedact)
+			}
+		})
+	}
+}
+
+func TestWriteAuditText(t *testing.T) {
+	var buf bytes.Buffer
+	writeAuditText(&buf, nil, time.UTC)
+	if !strings.Contains(buf.String(), "No audited tool calls") {
+		t.Errorf("empty output = %q", buf.String())
+	}
+
+	buf.Reset()
+	done := time.Date(2026, 3, 14, 12, 0, 1, 0, time.UTC)
+	writeAuditText(&buf, []audit.Entry{
+		{Tool: "shell_exec", StartedAt: time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC), CompletedAt: &done,
+			DurationMS: 1250, Error: "[error:permanent] exit status 1", ErrorKind: "permanent", ConversationID: "signal-1", Source: "signal",
+			Channel: "signal", Sender: "Alice", Arguments: `{"command": "` + strings.Repeat("x", 200) + `"}`},
+		{Tool: "web_search", StartedAt: time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC)},
+	}, time.UTC)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("lines = %q, want header and 2 rows", lines)
+	}
+	for _, want := range []string{"2026-03-14 12:00:00", "shell_exec", "1.25s", "error:permanent", "signal-1", "signal (Alice)", "…"} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("row 1 = %q, missing %q", lines[1], want)
+		}
+	}
+	if !strings.Contains(lines[2], "incomplete") {
+		t.Errorf("row 2 = %q, want incomplete status", lines[2])
+	}
+}
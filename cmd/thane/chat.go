@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+
+	"github.com/nugget/thane-ai-agent/internal/app"
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+	"github.com/nugget/thane-ai-agent/internal/runtime/agent"
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+const chatUsage = "usage: thane chat [--conversation <id>] [--verbose]"
+
+// chatConversationID is the conversation thane chat continues when no
+// --conversation is given.
+const chatConversationID = "cli-chat"
+
+// chatHelp lists the REPL's meta-commands.
+const chatHelp = `Commands:
+  /reset         Archive this conversation and start over
+  /tokens        Show context usage and the last reply's token counts
+  /model [name]  Show the default model, or switch to another configured one
+  /tools         List the available tools
+  /compact       Compact the conversation now
+  /help          Show this help
+  /quit          Leave (Ctrl-D works too)
+Ctrl-C while a reply is running stops that reply.`
+
+// chatArgs holds the parsed arguments of `thane chat`.
+type chatArgs struct {
+	ConversationID string
+	Verbose        bool
+}
+
+// runChat implements `thane chat`: an interactive conversation in the
+// terminal with the agent as the server builds it — persistent memory,
+// the full tool set, the model router, compaction, and session
+// archiving — without starting the HTTP servers. Background workers
+// (scheduler, channels, summarizer) don't run, so a chat does not
+// compete with a running server for Signal messages or scheduled
+// tasks. Replies stream token by token. Logs still reach the configured
+// log files; --verbose also writes them to stderr.
+func runChat(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, configPath string, args []string) error {
+	parsed, err := parseChatArgs(args)
+	if err != nil {
+		return err
+	}
+
+	logOut := io.Discard
+	if parsed.Verbose {
+		logOut = stderr
+	}
+	logger := newLogger(logOut, slog.LevelInfo, "text")
+
+	cfg, _, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	llmSetup, err := createLLMSetup(ctx, cfg, logger)
+	if err != nil {
+		return err
+	}
+
+	a, err := app.New(ctx, cfg, logger, logOut, llmSetup.Client, llmSetup.OllamaClients, llmSetup.HealthClients, llmSetup.ModelRuntime)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	repl := &chatREPL{
+		loop:   a.Loop(),
+		convID: parsed.ConversationID,
+		models: chatModelNames(cfg.Models),
+		in:     stdin,
+		out:    stdout,
+		turnContext: func(ctx context.Context) (context.Context, context.CancelFunc) {
+			return signal.NotifyContext(ctx, os.Interrupt)
+		},
+	}
+	return repl.run(ctx)
+}
+
+// chatModelNames returns the configured model names /model accepts.
+func chatModelNames(models config.ModelsConfig) []string {
+	var names []string
+	for _, m := range models.Available {
+		if m.Name != "" && !slices.Contains(names, m.Name) {
+			names = append(names, m.Name)
+		}
+	}
+	return names
+}
+
+// chatLoop is the part of [agent.Loop] the chat REPL drives.
+type chatLoop interface {
+	Run(ctx context.Context, req *agent.Request, stream agent.StreamCallback) (*agent.Response, error)
+	ResetConversation(conversationID string) error
+	TriggerCompaction(ctx context.Context, conversationID string) error
+	GetTokenCount(conversationID string) int
+	GetContextWindow() int
+	DefaultModel() string
+	SetDefaultModel(name string)
+	Tools() *tools.Registry
+}
+
+// chatREPL reads lines from in, sends each to the agent as a turn in
+// convID, and streams the reply to out. Lines starting with "/" are
+// meta-commands (see chatHelp).
+type chatREPL struct {
+	loop   chatLoop
+	convID string
+	models []string // names /model accepts
+	in     io.Reader
+	out    io.Writer
+
+	// turnContext derives the context for one turn. runChat cancels it
+	// on Ctrl-C so an interrupt stops the reply, not the chat. Nil uses
+	// the chat's context unchanged.
+	turnContext func(context.Context) (context.Context, context.CancelFunc)
+
+	last *agent.Response // most recent reply, for /tokens
+}
+
+// run reads and handles input until EOF, /quit, or ctx is cancelled.
+func (r *chatREPL) run(ctx context.Context) error {
+	fmt.Fprintf(r.out, "Chatting in conversation %q with %s. /help lists commands.\n", r.convID, r.loop.DefaultModel())
+
+	scanner := bufio.NewScanner(r.in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024) // room for pasted text
+	for {
+		fmt.Fprint(r.out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(r.out)
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "/") {
+			if quit := r.command(ctx, line); quit {
+				return nil
+			}
+			continue
+		}
+		r.turn(ctx, line)
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// turn sends one user message and streams the reply. Errors are
+// reported and the chat carries on.
+func (r *chatREPL) turn(ctx context.Context, message string) {
+	turnCtx, cancel := ctx, context.CancelFunc(func() {})
+	if r.turnContext != nil {
+		turnCtx, cancel = r.turnContext(ctx)
+	}
+	defer cancel()
+
+	streamed := false
+	resp, err := r.loop.Run(turnCtx, &agent.Request{
+		ConversationID: r.convID,
+		Messages:       []agent.Message{{Role: "user", Content: message}},
+	}, func(ev llm.StreamEvent) {
+		if ev.Kind == llm.KindToken && ev.Token != "" {
+			streamed = true
+			io.WriteString(r.out, ev.Token)
+		}
+	})
+	if streamed {
+		fmt.Fprintln(r.out)
+	}
+	switch {
+	case err != nil && turnCtx.Err() != nil && ctx.Err() == nil:
+		fmt.Fprintln(r.out, "(interrupted)")
+	case err != nil:
+		fmt.Fprintf(r.out, "error: %v\n", err)
+	default:
+		r.last = resp
+		// A model that doesn't stream delivers its answer only at the end.
+		if !streamed {
+			fmt.Fprintln(r.out, resp.Content)
+		}
+	}
+}
+
+// command runs a meta-command and reports whether the chat should end.
+func (r *chatREPL) command(ctx context.Context, line string) (quit bool) {
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch name {
+	case "/quit", "/exit":
+		return true
+	case "/help":
+		fmt.Fprintln(r.out, chatHelp)
+	case "/reset":
+		if err := r.loop.ResetConversation(r.convID); err != nil {
+			fmt.Fprintf(r.out, "error: %v\n", err)
+			return false
+		}
+		r.last = nil
+		fmt.Fprintln(r.out, "Conversation reset.")
+	case "/tokens":
+		fmt.Fprintf(r.out, "Context: %d tokens", r.loop.GetTokenCount(r.convID))
+		if window := r.loop.GetContextWindow(); window > 0 {
+			fmt.Fprintf(r.out, " of %d", window)
+		}
+		fmt.Fprintln(r.out)
+		if r.last != nil {
+			fmt.Fprintf(r.out, "Last reply: %d in, %d out (%s)\n", r.last.InputTokens, r.last.OutputTokens, r.last.Model)
+		}
+	case "/model":
+		r.model(arg)
+	case "/tools":
+		names := r.loop.Tools().AllToolNames()
+		fmt.Fprintf(r.out, "%d tools:\n", len(names))
+		for _, n := range names {
+			fmt.Fprintf(r.out, "  %s\n", n)
+		}
+	case "/compact":
+		if err := r.loop.TriggerCompaction(ctx, r.convID); err != nil {
+			fmt.Fprintf(r.out, "error: %v\n", err)
+			return false
+		}
+		fmt.Fprintf(r.out, "Conversation compacted (%d tokens).\n", r.loop.GetTokenCount(r.convID))
+	default:
+		fmt.Fprintf(r.out, "unknown command %s (try /help)\n", name)
+	}
+	return false
+}
+
+// model shows or switches the default model. Only configured models
+// are accepted, as with the Home Assistant model select.
+func (r *chatREPL) model(name string) {
+	if name == "" {
+		fmt.Fprintf(r.out, "Default model: %s\n", r.loop.DefaultModel())
+		if len(r.models) > 0 {
+			fmt.Fprintf(r.out, "Available: %s\n", strings.Join(r.models, ", "))
+		}
+		return
+	}
+	if !slices.Contains(r.models, name) {
+		fmt.Fprintf(r.out, "unknown model %q; available: %s\n", name, strings.Join(r.models, ", "))
+		return
+	}
+	r.loop.SetDefaultModel(name)
+	fmt.Fprintf(r.out, "Default model: %s\n", r.loop.DefaultModel())
+}
+
+// parseChatArgs parses the arguments of `thane chat`.
+func parseChatArgs(args []string) (chatArgs, error) {
+	parsed := chatArgs{ConversationID: chatConversationID}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--verbose", "-v":
+			parsed.Verbose = true
+			continue
+		case "--conversation":
+		default:
+			return parsed, fmt.Errorf("unexpected argument: %s\n%s", arg, chatUsage)
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return parsed, fmt.Errorf("%s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		if value == "" {
+			return parsed, fmt.Errorf("%s requires a value", name)
+		}
+		parsed.ConversationID = value
+	}
+	return parsed, nil
+}
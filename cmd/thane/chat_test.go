@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/runtime/agent"
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+// fakeChatLoop answers every turn with reply, streaming it when stream
+// is set.
+type fakeChatLoop struct {
+	reply  string
+	stream bool
+	err    error
+
+	model     string
+	tokens    int
+	resets    int
+	compacts  int
+	turns     []string
+	toolsList *tools.Registry
+}
+
+func (f *fakeChatLoop) Run(_ context.Context, req *agent.Request, stream agent.StreamCallback) (*agent.Response, error) {
+	f.turns = append(f.turns, req.Messages[0].Content)
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.stream && stream != nil {
+		for _, tok := range strings.SplitAfter(f.reply, " ") {
+			stream(llm.StreamEvent{Kind: llm.KindToken, Token: tok})
+		}
+	}
+	f.tokens += 10
+	return &agent.Response{Content: f.reply, Model: f.model, InputTokens: 100, OutputTokens: 7}, nil
+}
+
+func (f *fakeChatLoop) ResetConversation(string) error { f.resets++; f.tokens = 0; return nil }
+func (f *fakeChatLoop) TriggerCompaction(context.Context, string) error {
+	f.compacts++
+	return nil
+}
+func (f *fakeChatLoop) GetTokenCount(string) int    { return f.tokens }
+func (f *fakeChatLoop) GetContextWindow() int       { return 8192 }
+func (f *fakeChatLoop) DefaultModel() string        { return f.model }
+func (f *fakeChatLoop) SetDefaultModel(name string) { f.model = name }
+func (f *fakeChatLoop) Tools() *tools.Registry      { return f.toolsList }
+
+func runTestChat(t *testing.T, loop *fakeChatLoop, input string) string {
+	t.Helper()
+	var out strings.Builder
+	repl := &chatREPL{
+		loop:   loop,
+		convID: "test",
+		models: []string{"small", "large"},
+		in:     strings.NewReader(input),
+		out:    &out,
+	}
+	if err := repl.run(context.Background()); err != nil {
+		t.Fatalf("run() error: %v", err)
+	}
+	return out.String()
+}
+
+func TestChatREPL_StreamsReplies(t *testing.T) {
+	loop := &fakeChatLoop{reply: "the door is closed", stream: true, model: "small"}
+	out := runTestChat(t, loop, "is the door open?\n\n")
+
+	if len(loop.turns) != 1 || loop.turns[0] != "is the door open?" {
+		t.Errorf("turns = %q, want one, blank lines skipped", loop.turns)
+	}
+	if strings.Count(out, "the door is closed") != 1 {
+		t.Errorf("output = %q, want the streamed reply exactly once", out)
+	}
+}
+
+func TestChatREPL_PrintsUnstreamedReply(t *testing.T) {
+	loop := &fakeChatLoop{reply: "done", model: "small"}
+	out := runTestChat(t, loop, "hi\n")
+	if !strings.Contains(out, "> done\n") {
+		t.Errorf("output = %q, want the reply printed after the turn", out)
+	}
+}
+
+func TestChatREPL_TurnErrorKeepsGoing(t *testing.T) {
+	loop := &fakeChatLoop{err: errors.New("model unavailable"), model: "small"}
+	out := runTestChat(t, loop, "one\ntwo\n")
+	if len(loop.turns) != 2 {
+		t.Errorf("turns = %d, want 2", len(loop.turns))
+	}
+	if !strings.Contains(out, "error: model unavailable") {
+		t.Errorf("output = %q, want the error reported", out)
+	}
+}
+
+func TestChatREPL_Commands(t *testing.T) {
+	reg := tools.NewRegistry(nil, nil, nil)
+	reg.Register(&tools.Tool{Name: "get_state", Handler: func(context.Context, map[string]any) (string, error) { return "", nil }})
+	loop := &fakeChatLoop{reply: "ok", model: "small", toolsList: reg}
+
+	out := runTestChat(t, loop, strings.Join([]string{
+		"hello",
+		"/tokens",
+		"/model large",
+		"/model huge",
+		"/tools",
+		"/compact",
+		"/reset",
+		"/bogus",
+		"/quit",
+		"never sent",
+	}, "\n"))
+
+	for _, want := range []string{
+		"Context: 10 tokens of 8192",
+		"Last reply: 100 in, 7 out (small)",
+		"Default model: large",
+		`unknown model "huge"`,
+		"  get_state",
+		"Conversation compacted",
+		"Conversation reset.",
+		"unknown command /bogus",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if loop.model != "large" || loop.resets != 1 || loop.compacts != 1 {
+		t.Errorf("model = %q, resets = %d, compacts = %d", loop.model, loop.resets, loop.compacts)
+	}
+	if len(loop.turns) != 1 {
+		t.Errorf("turns = %q, want input after /quit ignored", loop.turns)
+	}
+}
+
+func TestParseChatArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		want      chatArgs
+		wantError string
+	}{
+		{name: "defaults", want: chatArgs{ConversationID: chatConversationID}},
+		{name: "conversation", args: []string{"--conversation", "garage"}, want: chatArgs{ConversationID: "garage"}},
+		{name: "conversation inline", args: []string{"--conversation=garage", "-v"}, want: chatArgs{ConversationID: "garage", Verbose: true}},
+		{name: "missing value", args: []string{"--conversation"}, wantError: "requires a value"},
+		{name: "stray argument", args: []string{"hello"}, wantError: "unexpected argument"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseChatArgs(tt.args)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("parseChatArgs(%q) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
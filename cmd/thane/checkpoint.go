@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nugget/thane-ai-agent/internal/platform/checkpoint"
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/platform/httpkit"
+	"github.com/nugget/thane-ai-agent/internal/platform/scheduler"
+	"github.com/nugget/thane-ai-agent/internal/state/knowledge"
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
+)
+
+// checkpointUsage is returned for a malformed `thane checkpoint` call.
+const checkpointUsage = "usage: thane checkpoint list [--limit N] | thane checkpoint restore [--dry-run] <checkpoint-id>"
+
+// runCheckpoint dispatches the `thane checkpoint <subcommand>` family.
+func runCheckpoint(ctx context.Context, stdout, stderr io.Writer, configPath, outputFmt string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", checkpointUsage)
+	}
+	switch args[0] {
+	case "list":
+		return runCheckpointList(stdout, stderr, configPath, outputFmt, args[1:])
+	case "restore":
+		return runCheckpointRestore(ctx, stdout, stderr, configPath, outputFmt, args[1:])
+	default:
+		return fmt.Errorf("unknown checkpoint command: %s", args[0])
+	}
+}
+
+// runCheckpointList implements `thane checkpoint list`: the newest
+// snapshots in the configured data directory, with what triggered them
+// and how much they hold. It only reads, so it runs alongside the
+// server.
+func runCheckpointList(stdout, stderr io.Writer, configPath, outputFmt string, args []string) error {
+	limit, err := parseCheckpointListArgs(args)
+	if err != nil {
+		return err
+	}
+	cfg, _, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	mem, err := openCheckpointMemory(stderr, cfg)
+	if err != nil {
+		return err
+	}
+	defer mem.Close()
+	store, err := checkpoint.NewStore(mem.DB(), newLogger(stderr, slog.LevelWarn, "text"))
+	if err != nil {
+		return fmt.Errorf("open checkpoint store: %w", err)
+	}
+
+	checkpoints, err := store.List(limit)
+	if err != nil {
+		return fmt.Errorf("list checkpoints: %w", err)
+	}
+
+	if outputFmt == "json" {
+		if checkpoints == nil {
+			checkpoints = []*checkpoint.Checkpoint{}
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(checkpoints)
+	}
+	writeCheckpointListText(stdout, checkpoints)
+	return nil
+}
+
+// runCheckpointRestore implements `thane checkpoint restore`. It
+// rewinds working memory, the fact store, and the scheduler's tasks to
+// a snapshot, each store in its own transaction. A restore rewrites
+// databases the server holds open, so it refuses while a server
+// answers on the configured listen port; --dry-run only reads and runs
+// either way.
+func runCheckpointRestore(ctx context.Context, stdout, stderr io.Writer, configPath, outputFmt string, args []string) error {
+	id, dryRun, err := parseCheckpointRestoreArgs(args)
+	if err != nil {
+		return err
+	}
+	cfg, _, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if !dryRun {
+		if addr := checkpointServerAddr(cfg); serverRunning(ctx, addr) {
+			return fmt.Errorf("thane is running on %s; stop it before restoring a checkpoint (or pass --dry-run)", addr)
+		}
+	}
+
+	logger := newLogger(stderr, slog.LevelWarn, "text")
+	mem, err := openCheckpointMemory(stderr, cfg)
+	if err != nil {
+		return err
+	}
+	defer mem.Close()
+	store, err := checkpoint.NewStore(mem.DB(), logger)
+	if err != nil {
+		return fmt.Errorf("open checkpoint store: %w", err)
+	}
+	cp, err := store.Resolve(id)
+	if err != nil {
+		return fmt.Errorf("load checkpoint %s: %w", id, err)
+	}
+
+	factDB, err := openExistingDB(cfg.DataDir + "/knowledge.db")
+	if err != nil {
+		return fmt.Errorf("open fact store: %w", err)
+	}
+	defer factDB.Close()
+	facts, err := knowledge.NewStore(factDB, logger)
+	if err != nil {
+		return fmt.Errorf("open fact store: %w", err)
+	}
+
+	schedDB, err := openExistingDB(cfg.DataDir + "/scheduler.db")
+	if err != nil {
+		return fmt.Errorf("open scheduler store: %w", err)
+	}
+	defer schedDB.Close()
+	tasks, err := scheduler.NewStore(schedDB, logger)
+	if err != nil {
+		return fmt.Errorf("open scheduler store: %w", err)
+	}
+
+	report, restoreErr := checkpoint.Restore(cp, []checkpoint.Restorer{mem, facts, tasks}, dryRun)
+	if restoreErr != nil && len(report.Stores) == 0 {
+		return restoreErr
+	}
+
+	if outputFmt == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		writeCheckpointRestoreText(stdout, report, restoreErr != nil)
+	}
+	return restoreErr
+}
+
+// openCheckpointMemory opens the working-memory store in thane.db,
+// which also holds the checkpoints. Like openArchiveStore it refuses to
+// create a fresh database.
+func openCheckpointMemory(stderr io.Writer, cfg *config.Config) (*memory.SQLiteStore, error) {
+	dbPath := cfg.DataDir + "/thane.db"
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("open checkpoint store: %w", err)
+	}
+	mem, err := memory.NewSQLiteStoreWithLogger(dbPath, 100, newLogger(stderr, slog.LevelWarn, "text"))
+	if err != nil {
+		return nil, fmt.Errorf("open working store: %w", err)
+	}
+	return mem, nil
+}
+
+// openExistingDB opens a database in the data directory, failing if it
+// does not exist rather than restoring into an empty one.
+func openExistingDB(path string) (*sql.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	return database.Open(path)
+}
+
+// checkpointServerAddr is a local address for the configured API
+// listener, for probing whether the server is up.
+func checkpointServerAddr(cfg *config.Config) string {
+	host := cfg.Listen.Address
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, strconv.Itoa(cfg.Listen.Port))
+}
+
+// serverRunning reports whether anything answers HTTP on addr's /health
+// endpoint. Any response counts: a restore must not race a live server,
+// whatever state its health is in.
+func serverRunning(ctx context.Context, addr string) bool {
+	client := httpkit.NewClient(httpkit.WithTimeout(2 * time.Second))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+"/health", nil)
+	if err != nil {
+		return false
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	httpkit.DrainAndClose(resp.Body, 4096)
+	return true
+}
+
+// parseCheckpointListArgs parses the flags of `thane checkpoint list`.
+func parseCheckpointListArgs(args []string) (int, error) {
+	limit := 20
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		if name != "--limit" {
+			return 0, fmt.Errorf("unknown checkpoint list flag: %s", arg)
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return 0, fmt.Errorf("%s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("%s: %q is not a positive integer", name, value)
+		}
+		limit = n
+	}
+	return limit, nil
+}
+
+// parseCheckpointRestoreArgs parses `thane checkpoint restore`: one
+// checkpoint ID and an optional --dry-run.
+func parseCheckpointRestoreArgs(args []string) (uuid.UUID, bool, error) {
+	var id uuid.UUID
+	var dryRun, haveID bool
+	for _, arg := range args {
+		switch {
+		case arg == "--dry-run" || arg == "-n":
+			dryRun = true
+		case strings.HasPrefix(arg, "-"):
+			return id, false, fmt.Errorf("unknown checkpoint restore flag: %s", arg)
+		case haveID:
+			return id, false, fmt.Errorf("%s", checkpointUsage)
+		default:
+			parsed, err := uuid.Parse(arg)
+			if err != nil {
+				return id, false, fmt.Errorf("invalid checkpoint id %q", arg)
+			}
+			id, haveID = parsed, true
+		}
+	}
+	if !haveID {
+		return id, false, fmt.Errorf("%s", checkpointUsage)
+	}
+	return id, dryRun, nil
+}
+
+// writeCheckpointListText prints one row per checkpoint, newest first.
+func writeCheckpointListText(w io.Writer, checkpoints []*checkpoint.Checkpoint) {
+	if len(checkpoints) == 0 {
+		fmt.Fprintln(w, "No checkpoints.")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tCREATED\tTRIGGER\tKIND\tMESSAGES\tFACTS\tBYTES\tNOTE")
+	for _, cp := range checkpoints {
+		kind := "full"
+		if cp.Incremental() {
+			kind = "delta of " + cp.BaseID.String()[:8]
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%s\n",
+			cp.ID, cp.CreatedAt.Local().Format("2006-01-02 15:04"), cp.Trigger, kind,
+			cp.MessageCount, cp.FactCount, cp.ByteSize, cp.Note)
+	}
+	_ = tw.Flush()
+}
+
+// writeCheckpointRestoreText prints the per-store changes of a restore.
+// partial marks a restore that failed after some stores were written.
+func writeCheckpointRestoreText(w io.Writer, report *checkpoint.RestoreReport, partial bool) {
+	fmt.Fprintf(w, "Checkpoint %s (%s, %s)\n",
+		report.ID, report.CreatedAt.Local().Format("2006-01-02 15:04"), report.Trigger)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STORE\tADDED\tUPDATED\tREMOVED\tUNCHANGED")
+	for _, s := range report.Stores {
+		if s.Skipped != "" {
+			fmt.Fprintf(tw, "%s\tskipped: %s\t\t\t\n", s.Store, s.Skipped)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\n", s.Store, s.Added, s.Updated, s.Removed, s.Unchanged)
+	}
+	_ = tw.Flush()
+
+	switch {
+	case report.DryRun:
+		fmt.Fprintln(w, "Dry run: nothing was written.")
+	case partial:
+		fmt.Fprintln(w, "Restore stopped part-way: the stores listed above were restored.")
+	default:
+		fmt.Fprintln(w, "Restored.")
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+)
+
+func TestParseCheckpointRestoreArgs(t *testing.T) {
+	const id = "0190b6a2-7c1e-7d3a-9f00-4e5d6c7b8a90"
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantDry   bool
+		wantError string
+	}{
+		{name: "id only", args: []string{id}},
+		{name: "dry run first", args: []string{"--dry-run", id}, wantDry: true},
+		{name: "dry run after", args: []string{id, "-n"}, wantDry: true},
+		{name: "missing id", args: []string{"--dry-run"}, wantError: "usage:"},
+		{name: "two ids", args: []string{id, id}, wantError: "usage:"},
+		{name: "bad id", args: []string{"abc123"}, wantError: "invalid checkpoint id"},
+		{name: "unknown flag", args: []string{"--force", id}, wantError: "unknown checkpoint restore flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dryRun, err := parseCheckpointRestoreArgs(tt.args)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != id || dryRun != tt.wantDry {
+				t.Errorf("parsed = %s dry %v, want %s dry %v", got, dryRun, id, tt.wantDry)
+			}
+		})
+	}
+}
+
+func TestParseCheckpointListArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		want      int
+		wantError string
+	}{
+		{name: "default", want: 20},
+		{name: "limit", args: []string{"--limit", "5"}, want: 5},
+		{name: "limit equals", args: []string{"--limit=50"}, want: 50},
+		{name: "zero", args: []string{"--limit=0"}, wantError: "positive integer"},
+		{name: "missing value", args: []string{"--limit"}, wantError: "requires a value"},
+		{name: "unknown flag", args: []string{"--all"}, wantError: "unknown checkpoint list flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCheckpointListArgs(tt.args)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("limit = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckpointServerAddr(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Listen.Port = 8080
+	if got := checkpointServerAddr(cfg); got != "127.0.0.1:8080" {
+		t.Errorf("wildcard listener probe = %q, want loopback", got)
+	}
+	cfg.Listen.Address = "192.168.1.10"
+	if got := checkpointServerAddr(cfg); got != "192.168.1.10:8080" {
+		t.Errorf("bound listener probe = %q", got)
+	}
+}
+
+func TestServerRunning(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	addr := strings.TrimPrefix(srv.URL, "http://")
+
+	if !serverRunning(context.Background(), addr) {
+		t.Error("unhealthy server not detected as running")
+	}
+	srv.Close()
+	if serverRunning(context.Background(), addr) {
+		t.Error("closed listener detected as running")
+	}
+}
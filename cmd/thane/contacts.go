@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/state/contacts"
+)
+
+// contactsImportUsage is returned when `thane contacts import` is
+// called without a file.
+const contactsImportUsage = "usage: thane contacts import [--dry-run] [--no-merge] [--country-code N] <file.vcf>"
+
+// runContacts dispatches the `thane contacts <subcommand>` family. Only
+// import exists today.
+func runContacts(stdout, stderr io.Writer, configPath, outputFmt string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", contactsImportUsage)
+	}
+	switch args[0] {
+	case "import":
+		return runContactsImport(stdout, stderr, configPath, outputFmt, args[1:])
+	default:
+		return fmt.Errorf("unknown contacts command: %s", args[0])
+	}
+}
+
+// contactsImportArgs are the parsed flags of `thane contacts import`.
+type contactsImportArgs struct {
+	path string
+	opts contacts.VCardImportOptions
+}
+
+// runContactsImport implements `thane contacts import`. It reads a
+// vCard file (as exported from a phone or address book) into the
+// contact directory, merging each card into an existing contact that
+// shares its phone number, email, or name so re-importing the same
+// file does not create duplicates. --country-code sets the calling
+// code assumed for numbers written without one; without it, such
+// numbers are stored as bare digits and will not match the E.164
+// senders Signal reports.
+func runContactsImport(stdout, stderr io.Writer, configPath, outputFmt string, args []string) error {
+	parsed, err := parseContactsImportArgs(args)
+	if err != nil {
+		return err
+	}
+
+	cfg, _, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(parsed.path)
+	if err != nil {
+		return fmt.Errorf("open vcard file: %w", err)
+	}
+	defer f.Close()
+
+	if err := os.MkdirAll(cfg.DataDir, 0755); err != nil {
+		return fmt.Errorf("create data directory: %w", err)
+	}
+	db, err := database.Open(cfg.DataDir + "/contacts.db")
+	if err != nil {
+		return fmt.Errorf("open contacts database: %w", err)
+	}
+	defer db.Close()
+
+	store, err := contacts.NewStore(db, newLogger(stderr, slog.LevelWarn, "text"))
+	if err != nil {
+		return fmt.Errorf("open contact store: %w", err)
+	}
+
+	result, err := store.ImportVCard(f, parsed.opts)
+	if err != nil {
+		return err
+	}
+
+	if outputFmt == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	writeContactsImportText(stdout, result)
+	return nil
+}
+
+// parseContactsImportArgs parses the flags and file argument of
+// `thane contacts import`.
+func parseContactsImportArgs(args []string) (contactsImportArgs, error) {
+	var parsed contactsImportArgs
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--dry-run", "-n":
+			parsed.opts.DryRun = true
+		case "--no-merge":
+			parsed.opts.NoMerge = true
+		case "--country-code":
+			if !hasValue {
+				if i+1 >= len(args) {
+					return parsed, fmt.Errorf("%s requires a value", name)
+				}
+				i++
+				value = args[i]
+			}
+			value = strings.TrimPrefix(value, "+")
+			if value == "" || strings.Trim(value, "0123456789") != "" {
+				return parsed, fmt.Errorf("%s: %q is not a calling code", name, value)
+			}
+			parsed.opts.DefaultCountryCode = value
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return parsed, fmt.Errorf("unknown contacts import flag: %s", arg)
+			}
+			if parsed.path != "" {
+				return parsed, fmt.Errorf("%s", contactsImportUsage)
+			}
+			parsed.path = arg
+		}
+	}
+	if parsed.path == "" {
+		return parsed, fmt.Errorf("%s", contactsImportUsage)
+	}
+	return parsed, nil
+}
+
+// writeContactsImportText prints one line per card followed by the
+// totals.
+func writeContactsImportText(w io.Writer, result *contacts.VCardImportResult) {
+	if len(result.Entries) == 0 {
+		fmt.Fprintln(w, "No vCards found.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tACTION\tDETAIL")
+	for _, e := range result.Entries {
+		detail := e.Reason
+		if e.Action == contacts.ImportMerged {
+			detail = fmt.Sprintf("into %q by %s", e.MergedInto, e.MatchedBy)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", e.Name, e.Action, detail)
+	}
+	_ = tw.Flush()
+
+	verb := "Imported"
+	if result.DryRun {
+		verb = "Would import"
+	}
+	fmt.Fprintf(w, "\n%s %d contacts: %d created, %d merged, %d skipped.\n",
+		verb, result.Created+result.Merged, result.Created, result.Merged, result.Skipped)
+}
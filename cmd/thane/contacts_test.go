@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseContactsImportArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantPath  string
+		wantCC    string
+		wantDry   bool
+		wantNoMrg bool
+		wantError string
+	}{
+		{name: "file only", args: []string{"phone.vcf"}, wantPath: "phone.vcf"},
+		{name: "flags", args: []string{"--dry-run", "--no-merge", "phone.vcf"}, wantPath: "phone.vcf", wantDry: true, wantNoMrg: true},
+		{name: "country code", args: []string{"phone.vcf", "--country-code", "+44"}, wantPath: "phone.vcf", wantCC: "44"},
+		{name: "country code inline", args: []string{"--country-code=1", "phone.vcf"}, wantPath: "phone.vcf", wantCC: "1"},
+		{name: "missing file", args: []string{"--dry-run"}, wantError: "usage"},
+		{name: "two files", args: []string{"a.vcf", "b.vcf"}, wantError: "usage"},
+		{name: "bad country code", args: []string{"--country-code", "uk", "a.vcf"}, wantError: "not a calling code"},
+		{name: "missing value", args: []string{"a.vcf", "--country-code"}, wantError: "requires a value"},
+		{name: "unknown flag", args: []string{"--force", "a.vcf"}, wantError: "unknown contacts import flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseContactsImportArgs(tt.args)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.path != tt.wantPath || parsed.opts.DefaultCountryCode != tt.wantCC ||
+				parsed.opts.DryRun != tt.wantDry || parsed.opts.NoMerge != tt.wantNoMrg {
+				t.Errorf("parsed = %+v", parsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/httpkit"
+	"github.com/nugget/thane-ai-agent/internal/runtime/delegate"
+)
+
+// delegateUsage is returned for a malformed `thane delegate` call.
+const delegateUsage = "usage: thane delegate replay <session-id> [--model name]"
+
+// delegateReplayTimeout bounds a replay request. The daemon answers
+// only once the rerun finishes, which can take as long as the
+// delegation did.
+const delegateReplayTimeout = 15 * time.Minute
+
+// runDelegate dispatches the `thane delegate <subcommand>` family.
+func runDelegate(ctx context.Context, stdout io.Writer, configPath, outputFmt string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", delegateUsage)
+	}
+	switch args[0] {
+	case "replay":
+		return runDelegateReplay(ctx, stdout, configPath, outputFmt, args[1:])
+	default:
+		return fmt.Errorf("unknown delegate command: %s", args[0])
+	}
+}
+
+// runDelegateReplay implements `thane delegate replay`: it asks the
+// running daemon to rerun an archived delegate execution, optionally on
+// another model, and prints the original and replay side by side. The
+// replay runs in the daemon, against the original's recorded tool
+// results.
+func runDelegateReplay(ctx context.Context, stdout io.Writer, configPath, outputFmt string, args []string) error {
+	id, model, err := parseDelegateReplayArgs(args)
+	if err != nil {
+		return err
+	}
+	cfg, _, err := loadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if cfg.Listen.Port == 0 {
+		return fmt.Errorf("listen.port is not configured")
+	}
+
+	endpoint := "http://" + checkpointServerAddr(cfg) + "/v1/archive/sessions/" + url.PathEscape(id) + "/replay"
+	body, err := delegateReplayPost(ctx, endpoint, cfg.Listen.AdminToken, model)
+	if err != nil {
+		return fmt.Errorf("replay %s: %w", id, err)
+	}
+
+	if outputFmt == "json" {
+		_, _ = stdout.Write(body)
+		if len(body) > 0 && body[len(body)-1] != '\n' {
+			fmt.Fprintln(stdout)
+		}
+		return nil
+	}
+	var result delegate.ReplayResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("decode replay: %w", err)
+	}
+	writeDelegateReplayText(stdout, &result)
+	return nil
+}
+
+// parseDelegateReplayArgs parses `thane delegate replay`: one session
+// ID and an optional --model.
+func parseDelegateReplayArgs(args []string) (id, model string, err error) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch {
+		case name == "--model" || name == "-m":
+			if !hasValue {
+				if i+1 >= len(args) {
+					return "", "", fmt.Errorf("%s requires a value", name)
+				}
+				i++
+				value = args[i]
+			}
+			model = value
+		case strings.HasPrefix(arg, "-"):
+			return "", "", fmt.Errorf("unknown delegate replay flag: %s", arg)
+		case id != "":
+			return "", "", fmt.Errorf("%s", delegateUsage)
+		default:
+			id = arg
+		}
+	}
+	if id == "" {
+		return "", "", fmt.Errorf("%s", delegateUsage)
+	}
+	return id, model, nil
+}
+
+// delegateReplayPost sends the replay request, with the admin token
+// when one is configured, and returns the response body.
+func delegateReplayPost(ctx context.Context, endpoint, adminToken, model string) ([]byte, error) {
+	payload, err := json.Marshal(map[string]string{"model": model})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if adminToken != "" {
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+	}
+
+	client := httpkit.NewClient(httpkit.WithTimeout(delegateReplayTimeout))
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w (is the daemon running?)", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// writeDelegateReplayText prints the original execution and its replay
+// as a comparison table followed by both outputs.
+func writeDelegateReplayText(w io.Writer, r *delegate.ReplayResult) {
+	orig, replay := r.Original, r.Replay
+	fmt.Fprintf(w, "Task: %s\n", orig.Task)
+	if orig.Guidance != "" {
+		fmt.Fprintf(w, "Guidance: %s\n", orig.Guidance)
+	}
+	fmt.Fprintln(w)
+
+	origTools := strings.Join(orig.ToolCalls, ", ")
+	replayNames := make([]string, 0, len(replay.ToolCalls))
+	for _, tc := range replay.ToolCalls {
+		replayNames = append(replayNames, tc.Name)
+	}
+	outcome := func(exhausted bool, reason string) string {
+		if exhausted {
+			return "exhausted: " + reason
+		}
+		return "completed"
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\tORIGINAL\tREPLAY")
+	fmt.Fprintf(tw, "session\t%s\t%s\n", r.SessionID, replay.SessionID)
+	fmt.Fprintf(tw, "model\t%s\t%s\n", orig.Model, replay.Model)
+	fmt.Fprintf(tw, "outcome\t%s\t%s\n", outcome(orig.Exhausted, orig.ExhaustReason), outcome(replay.Exhausted, replay.ExhaustReason))
+	fmt.Fprintf(tw, "iterations\t%d\t%d\n", orig.Iterations, replay.Iterations)
+	fmt.Fprintf(tw, "tokens in/out\t%d/%d\t%d/%d\n", orig.InputTokens, orig.OutputTokens, replay.InputTokens, replay.OutputTokens)
+	fmt.Fprintf(tw, "duration\t%s\t%s\n",
+		(time.Duration(orig.DurationMs) * time.Millisecond).Round(10*time.Millisecond),
+		replay.Duration.Round(10*time.Millisecond))
+	fmt.Fprintf(tw, "tools\t%s\t%s\n", origTools, strings.Join(replayNames, ", "))
+	_ = tw.Flush()
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "--- original (%s)\n%s\n\n", orig.Model, strings.TrimSpace(orig.ResultContent))
+	fmt.Fprintf(w, "--- replay (%s)\n%s\n", replay.Model, strings.TrimSpace(replay.Content))
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/delegate"
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
+)
+
+func TestParseDelegateReplayArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantID    string
+		wantModel string
+		wantError string
+	}{
+		{name: "id only", args: []string{"019e7460"}, wantID: "019e7460"},
+		{name: "model after", args: []string{"019e7460", "--model", "spark/gpt-oss:20b"}, wantID: "019e7460", wantModel: "spark/gpt-oss:20b"},
+		{name: "model equals first", args: []string{"--model=small", "019e7460"}, wantID: "019e7460", wantModel: "small"},
+		{name: "missing id", args: []string{"--model", "small"}, wantError: "usage:"},
+		{name: "two ids", args: []string{"a", "b"}, wantError: "usage:"},
+		{name: "missing model", args: []string{"019e7460", "--model"}, wantError: "requires a value"},
+		{name: "unknown flag", args: []string{"--force", "019e7460"}, wantError: "unknown delegate replay flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, model, err := parseDelegateReplayArgs(tt.args)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id != tt.wantID || model != tt.wantModel {
+				t.Errorf("parsed = %q on %q, want %q on %q", id, model, tt.wantID, tt.wantModel)
+			}
+		})
+	}
+}
+
+func TestDelegateReplayPost(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Model string }
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			http.Error(w, `{"error":"admin token required"}`, http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"model": body.Model})
+	}))
+	defer srv.Close()
+
+	body, err := delegateReplayPost(context.Background(), srv.URL, "s3cret", "small")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), `"small"`) {
+		t.Errorf("body = %s, want the model echoed", body)
+	}
+	if _, err := delegateReplayPost(context.Background(), srv.URL, "", "small"); err == nil || !strings.Contains(err.Error(), "HTTP 401") {
+		t.Errorf("error without token = %v, want HTTP 401", err)
+	}
+}
+
+func TestWriteDelegateReplayText(t *testing.T) {
+	var sb strings.Builder
+	writeDelegateReplayText(&sb, &delegate.ReplayResult{
+		SessionID: "orig-session",
+		Original: &memory.DelegationMetadata{
+			Task: "Is the garage door closed?", Model: "cloud/big-model",
+			Iterations: 2, DurationMs: 4200, ToolCalls: []string{"ha_get_state"},
+			ResultContent: "The garage door is closed.",
+		},
+		Replay: &delegate.Result{
+			Model: "local/small-model", Iterations: 4, Duration: 9 * time.Second,
+			Exhausted: true, ExhaustReason: "max_iterations", SessionID: "replay-session",
+			ToolCalls: []delegate.ToolCallOutcome{{Name: "ha_get_state", Success: true}},
+		},
+	})
+	out := sb.String()
+	for _, want := range []string{
+		"Task: Is the garage door closed?",
+		"orig-session", "replay-session",
+		"cloud/big-model", "local/small-model",
+		"exhausted: max_iterations",
+		"4.2s", "9s",
+		"--- original (cloud/big-model)\nThe garage door is closed.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
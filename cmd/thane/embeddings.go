@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/nugget/thane-ai-agent/internal/state/contacts"
+	"github.com/nugget/thane-ai-agent/internal/state/knowledge"
+)
+
+// embeddingsBackfillUsage is returned for a malformed `thane
+// embeddings` call.
+const embeddingsBackfillUsage = "usage: thane embeddings backfill [--batch-size N]"
+
+// runEmbeddings dispatches the `thane embeddings <subcommand>` family.
+// Only backfill exists today.
+func runEmbeddings(ctx context.Context, stdout, stderr io.Writer, configPath, outputFmt string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", embeddingsBackfillUsage)
+	}
+	switch args[0] {
+	case "backfill":
+		return runEmbeddingsBackfill(ctx, stdout, stderr, configPath, outputFmt, args[1:])
+	default:
+		return fmt.Errorf("unknown embeddings command: %s", args[0])
+	}
+}
+
+// embeddingsBackfillReport is the `thane embeddings backfill` result,
+// one entry per store. A store whose database doesn't exist yet is
+// omitted.
+type embeddingsBackfillReport struct {
+	Facts    *knowledge.BackfillProgress `json:"facts,omitempty"`
+	Contacts *knowledge.BackfillProgress `json:"contacts,omitempty"`
+}
+
+// runEmbeddingsBackfill implements `thane embeddings backfill`. It
+// embeds every active fact and contact that has no vector yet — the
+// situation after turning embeddings on for stores that already hold
+// thousands of records — or whose vector came from a different
+// embedding model, sending --batch-size texts per request to the
+// configured model. Progress goes to stderr after each batch.
+// Records that fail to embed are skipped and counted; rerunning the
+// command picks them up again. It works alongside a running server.
+func runEmbeddingsBackfill(ctx context.Context, stdout, stderr io.Writer, configPath, outputFmt string, args []string) error {
+	batchSize, err := parseEmbeddingsBackfillArgs(args)
+	if err != nil {
+		return err
+	}
+
+	cfg, _, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if !cfg.Embeddings.Enabled {
+		return fmt.Errorf("embeddings are not enabled (set embeddings.enabled in config)")
+	}
+	client := knowledge.New(knowledge.Config{
+		BaseURL: cfg.Embeddings.BaseURL,
+		Model:   cfg.Embeddings.Model,
+	})
+	logger := newLogger(stderr, slog.LevelWarn, "text")
+
+	// Learn the model's dimension so vectors written before models were
+	// tracked can be kept when they already match it.
+	if _, err := client.Generate(ctx, "embedding dimension probe"); err != nil {
+		return fmt.Errorf("probe embedding model %s: %w", client.Model(), err)
+	}
+
+	progress := func(label string) func(knowledge.BackfillProgress) {
+		return func(p knowledge.BackfillProgress) {
+			fmt.Fprintf(stderr, "%s: %d/%d embedded, %d failed\n", label, p.Done(), p.Total, p.Failed)
+		}
+	}
+
+	var report embeddingsBackfillReport
+
+	factDB, err := openExistingDB(cfg.DataDir + "/knowledge.db")
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("open knowledge database: %w", err)
+	default:
+		defer factDB.Close()
+		store, err := knowledge.NewStore(factDB, logger)
+		if err != nil {
+			return fmt.Errorf("open fact store: %w", err)
+		}
+		store.SetEmbeddingModel(client.Model())
+		if _, err := store.AdoptLegacyEmbeddings(client.Dimension()); err != nil {
+			return fmt.Errorf("adopt legacy fact embeddings: %w", err)
+		}
+		facts, err := store.GetFactsWithoutEmbeddings()
+		if err != nil {
+			return fmt.Errorf("list facts without embeddings: %w", err)
+		}
+		items := make([]knowledge.BackfillItem, len(facts))
+		for i, f := range facts {
+			items[i] = knowledge.BackfillItem{ID: f.ID, Text: knowledge.FactEmbeddingText(f)}
+		}
+		p, err := knowledge.Backfill(ctx, client, items, batchSize, store.SetEmbedding, progress("facts"))
+		report.Facts = &p
+		if err != nil {
+			return fmt.Errorf("backfill facts: %w", err)
+		}
+	}
+
+	contactDB, err := openExistingDB(cfg.DataDir + "/contacts.db")
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("open contacts database: %w", err)
+	default:
+		defer contactDB.Close()
+		store, err := contacts.NewStore(contactDB, logger)
+		if err != nil {
+			return fmt.Errorf("open contact store: %w", err)
+		}
+		store.SetEmbeddingModel(client.Model())
+		if _, err := store.AdoptLegacyEmbeddings(client.Dimension()); err != nil {
+			return fmt.Errorf("adopt legacy contact embeddings: %w", err)
+		}
+		list, err := store.GetContactsWithoutEmbeddings()
+		if err != nil {
+			return fmt.Errorf("list contacts without embeddings: %w", err)
+		}
+		items := make([]knowledge.BackfillItem, len(list))
+		for i, c := range list {
+			props, _ := store.GetProperties(c.ID)
+			items[i] = knowledge.BackfillItem{ID: c.ID, Text: contacts.EmbeddingText(c, props)}
+		}
+		p, err := knowledge.Backfill(ctx, client, items, batchSize, store.SetEmbedding, progress("contacts"))
+		report.Contacts = &p
+		if err != nil {
+			return fmt.Errorf("backfill contacts: %w", err)
+		}
+	}
+
+	if outputFmt == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	writeEmbeddingsBackfillText(stdout, report)
+	return nil
+}
+
+// parseEmbeddingsBackfillArgs parses the flags of `thane embeddings
+// backfill`, returning the batch size.
+func parseEmbeddingsBackfillArgs(args []string) (int, error) {
+	batchSize := knowledge.DefaultBackfillBatchSize
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		switch name {
+		case "--batch-size":
+			if !hasValue {
+				if i+1 >= len(args) {
+					return 0, fmt.Errorf("%s requires a value", name)
+				}
+				i++
+				value = args[i]
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("%s: %q is not a positive number", name, value)
+			}
+			batchSize = n
+		default:
+			return 0, fmt.Errorf("%s", embeddingsBackfillUsage)
+		}
+	}
+	return batchSize, nil
+}
+
+// writeEmbeddingsBackfillText prints one summary line per store.
+func writeEmbeddingsBackfillText(w io.Writer, report embeddingsBackfillReport) {
+	if report.Facts == nil && report.Contacts == nil {
+		fmt.Fprintln(w, "No fact or contact database found.")
+		return
+	}
+	for _, s := range []struct {
+		label string
+		p     *knowledge.BackfillProgress
+	}{{"Facts", report.Facts}, {"Contacts", report.Contacts}} {
+		if s.p == nil {
+			continue
+		}
+		if s.p.Total == 0 {
+			fmt.Fprintf(w, "%s: all embedded.\n", s.label)
+			continue
+		}
+		fmt.Fprintf(w, "%s: embedded %d of %d, %d failed.\n", s.label, s.p.Embedded, s.p.Total, s.p.Failed)
+	}
+}
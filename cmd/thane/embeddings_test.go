@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/state/knowledge"
+)
+
+func TestParseEmbeddingsBackfillArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		want      int
+		wantError string
+	}{
+		{name: "defaults", want: knowledge.DefaultBackfillBatchSize},
+		{name: "batch size", args: []string{"--batch-size", "64"}, want: 64},
+		{name: "batch size inline", args: []string{"--batch-size=8"}, want: 8},
+		{name: "zero", args: []string{"--batch-size", "0"}, wantError: "not a positive number"},
+		{name: "missing value", args: []string{"--batch-size"}, wantError: "requires a value"},
+		{name: "unknown flag", args: []string{"--facts"}, wantError: "usage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEmbeddingsBackfillArgs(tt.args)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("batch size = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
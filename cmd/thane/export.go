@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
+)
+
+const exportUsage = "usage: thane export <session-id> | --conversation <id> [-o file] [--with-tool-calls=false]"
+
+// exportArgs holds the parsed arguments of `thane export`.
+type exportArgs struct {
+	SessionID      string
+	ConversationID string
+	OutPath        string
+	WithToolCalls  bool
+}
+
+// runExport implements `thane export`. It renders one archived session,
+// or every session of a conversation oldest first, as markdown and
+// writes it to stdout or the file named by -o. Session IDs may be given
+// as any unique prefix, such as the 8-character short form shown in logs
+// and tool output.
+func runExport(stdout, stderr io.Writer, configPath string, args []string) error {
+	parsed, err := parseExportArgs(args)
+	if err != nil {
+		return err
+	}
+
+	cfg, _, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	store, closeStore, err := openArchiveStore(stderr, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	opts := memory.MarkdownExportOptions{OmitToolCalls: !parsed.WithToolCalls}
+	var md string
+	if parsed.ConversationID != "" {
+		md, err = store.ExportConversationMarkdown(parsed.ConversationID, opts)
+	} else {
+		var sessionID string
+		sessionID, err = store.ResolveSessionID(parsed.SessionID)
+		if err != nil {
+			return err
+		}
+		md, err = store.ExportSessionMarkdown(sessionID, opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	if parsed.OutPath == "" || parsed.OutPath == "-" {
+		_, err = io.WriteString(stdout, md)
+		return err
+	}
+	// Transcripts can hold anything the agent saw; keep them private.
+	if err := os.WriteFile(parsed.OutPath, []byte(md), 0o600); err != nil {
+		return fmt.Errorf("write export: %w", err)
+	}
+	return nil
+}
+
+// parseExportArgs parses the arguments of `thane export`. Exactly one of
+// a session ID or --conversation is required.
+func parseExportArgs(args []string) (exportArgs, error) {
+	parsed := exportArgs{WithToolCalls: true}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			if parsed.SessionID != "" {
+				return parsed, fmt.Errorf("unexpected argument: %s\n%s", arg, exportUsage)
+			}
+			parsed.SessionID = arg
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--with-tool-calls":
+			if !hasValue {
+				parsed.WithToolCalls = true
+				continue
+			}
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return parsed, fmt.Errorf("--with-tool-calls: %q is not a boolean", value)
+			}
+			parsed.WithToolCalls = b
+			continue
+		case "--conversation", "-o", "--out":
+		default:
+			return parsed, fmt.Errorf("unknown export flag: %s", arg)
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return parsed, fmt.Errorf("%s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		if value == "" {
+			return parsed, fmt.Errorf("%s requires a value", name)
+		}
+		if name == "--conversation" {
+			parsed.ConversationID = value
+		} else {
+			parsed.OutPath = value
+		}
+	}
+
+	switch {
+	case parsed.SessionID == "" && parsed.ConversationID == "":
+		return parsed, fmt.Errorf("%s", exportUsage)
+	case parsed.SessionID != "" && parsed.ConversationID != "":
+		return parsed, fmt.Errorf("give a session ID or --conversation, not both")
+	}
+	return parsed, nil
+}
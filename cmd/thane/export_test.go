@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseExportArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		want      exportArgs
+		wantError string
+	}{
+		{name: "session", args: []string{"019abcde"}, want: exportArgs{SessionID: "019abcde", WithToolCalls: true}},
+		{name: "conversation", args: []string{"--conversation", "signal-42"}, want: exportArgs{ConversationID: "signal-42", WithToolCalls: true}},
+		{name: "output file", args: []string{"019abcde", "-o", "out.md"}, want: exportArgs{SessionID: "019abcde", OutPath: "out.md", WithToolCalls: true}},
+		{name: "long output", args: []string{"--out=out.md", "019abcde"}, want: exportArgs{SessionID: "019abcde", OutPath: "out.md", WithToolCalls: true}},
+		{name: "without tool calls", args: []string{"019abcde", "--with-tool-calls=false"}, want: exportArgs{SessionID: "019abcde"}},
+		{name: "bare tool calls flag", args: []string{"019abcde", "--with-tool-calls"}, want: exportArgs{SessionID: "019abcde", WithToolCalls: true}},
+		{name: "nothing to export", wantError: "usage"},
+		{name: "both targets", args: []string{"019abcde", "--conversation=c1"}, wantError: "not both"},
+		{name: "two sessions", args: []string{"a", "b"}, wantError: "unexpected argument"},
+		{name: "missing value", args: []string{"019abcde", "-o"}, wantError: "requires a value"},
+		{name: "bad bool", args: []string{"019abcde", "--with-tool-calls=nope"}, wantError: "not a boolean"},
+		{name: "unknown flag", args: []string{"--json"}, wantError: "unknown export flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExportArgs(tt.args)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("parseExportArgs(%q) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
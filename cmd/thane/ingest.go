@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/state/knowledge"
+)
+
+// ingestUsage is returned for a malformed `thane ingest` call.
+const ingestUsage = "usage: thane ingest <file.md|file.txt|file.pdf|dir> [--exclude GLOB]... [--dry-run]"
+
+// ingestArgs are the parsed arguments of `thane ingest`.
+type ingestArgs struct {
+	path    string
+	exclude []string
+	dryRun  bool
+}
+
+// runIngest handles the "thane ingest <path>" subcommand. A file is
+// parsed — as markdown, plain text, or PDF, chosen by extension — into
+// discrete facts in the fact database, optionally with embeddings for
+// semantic search. A directory is walked recursively and kept in sync:
+// only files whose content changed since the last run are re-ingested,
+// and facts from files that have gone away are removed.
+func runIngest(ctx context.Context, stdout, stderr io.Writer, configPath, outputFmt string, args []string) error {
+	parsed, err := parseIngestArgs(args)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(parsed.path)
+	if err != nil {
+		return fmt.Errorf("ingest: %w", err)
+	}
+	if !info.IsDir() {
+		if len(parsed.exclude) > 0 || parsed.dryRun {
+			return fmt.Errorf("--exclude and --dry-run apply only to directory ingest")
+		}
+		if _, err := knowledge.IngestFormatForPath(parsed.path); err != nil {
+			return err
+		}
+	}
+
+	logger := newLogger(stdout, slog.LevelInfo, "text")
+	if outputFmt == "json" {
+		logger = newLogger(stderr, slog.LevelWarn, "text")
+	}
+
+	cfg, _, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	dbPath := cfg.DataDir + "/knowledge.db"
+	if parsed.dryRun {
+		return previewIngest(ctx, stdout, logger, dbPath, outputFmt, parsed)
+	}
+
+	if err := os.MkdirAll(cfg.DataDir, 0755); err != nil {
+		return fmt.Errorf("create data directory: %w", err)
+	}
+
+	factDB, err := database.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("open knowledge database: %w", err)
+	}
+	defer factDB.Close()
+	factStore, err := knowledge.NewStore(factDB, logger)
+	if err != nil {
+		return fmt.Errorf("open fact store: %w", err)
+	}
+
+	// Embeddings are optional. When enabled, each ingested fact gets a
+	// vector embedding for later semantic search.
+	var embClient knowledge.EmbeddingClient
+	if cfg.Embeddings.Enabled {
+		client := knowledge.New(knowledge.Config{
+			BaseURL: cfg.Embeddings.BaseURL,
+			Model:   cfg.Embeddings.Model,
+		})
+		factStore.SetEmbeddingModel(client.Model())
+		embClient = client
+		logger.Info("embeddings enabled", "model", cfg.Embeddings.Model)
+	}
+
+	if info.IsDir() {
+		logger.Info("ingesting directory", "dir", parsed.path)
+		result, err := knowledge.IngestDir(ctx, factStore, embClient, parsed.path, knowledge.DirIngestOptions{
+			Category: knowledge.CategoryArchitecture,
+			Exclude:  parsed.exclude,
+		})
+		if err != nil {
+			return fmt.Errorf("ingestion failed: %w", err)
+		}
+		return writeIngestDirResult(stdout, outputFmt, result, false)
+	}
+
+	// A single file is always re-ingested. Dropping any directory-sync
+	// record for it makes the next directory run rewrite its facts
+	// under that run's keys.
+	source := knowledge.FileSource(parsed.path)
+	logger.Info("ingesting document", "file", parsed.path)
+	if err := factStore.ForgetIngest(source); err != nil {
+		return fmt.Errorf("reset ingest record: %w", err)
+	}
+	// Earlier releases keyed a file's facts by the path as given on
+	// the command line. Re-ingesting the same path replaces those
+	// facts rather than leaving them behind under the old source.
+	if legacy := "file:" + parsed.path; legacy != source {
+		if err := factStore.DeleteBySource(legacy); err != nil {
+			return fmt.Errorf("remove facts from %s: %w", legacy, err)
+		}
+	}
+	ingester := knowledge.NewIngester(factStore, embClient, source, knowledge.CategoryArchitecture)
+
+	count, err := ingester.IngestFile(ctx, parsed.path)
+	if err != nil {
+		return fmt.Errorf("ingestion failed: %w", err)
+	}
+
+	logger.Info("ingestion complete", "facts_created", count, "source", source)
+	if outputFmt == "json" {
+		return json.NewEncoder(stdout).Encode(map[string]any{"source": source, "facts": count})
+	}
+	fmt.Fprintf(stdout, "Successfully ingested %d facts from %s\n", count, parsed.path)
+	return nil
+}
+
+// previewIngest handles `thane ingest <dir> --dry-run`. It reads the
+// ingest records from an existing knowledge.db through a read-only
+// connection, so a preview never creates the data directory or the
+// database, or migrates its schema.
+func previewIngest(ctx context.Context, stdout io.Writer, logger *slog.Logger, dbPath, outputFmt string, parsed ingestArgs) error {
+	var factDB *sql.DB
+	if _, err := os.Stat(dbPath); err == nil {
+		factDB, err = database.OpenReadOnly(dbPath)
+		if err != nil {
+			return fmt.Errorf("open knowledge database: %w", err)
+		}
+		defer factDB.Close()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("open knowledge database: %w", err)
+	}
+
+	logger.Info("previewing directory ingest", "dir", parsed.path)
+	result, err := knowledge.PreviewIngestDir(ctx, factDB, parsed.path, knowledge.DirIngestOptions{
+		Exclude: parsed.exclude,
+	})
+	if err != nil {
+		return fmt.Errorf("ingest preview failed: %w", err)
+	}
+	return writeIngestDirResult(stdout, outputFmt, result, true)
+}
+
+// writeIngestDirResult prints a directory ingest result as JSON or
+// text, per outputFmt.
+func writeIngestDirResult(w io.Writer, outputFmt string, result knowledge.DirIngestResult, dryRun bool) error {
+	if outputFmt == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	writeIngestDirText(w, result, dryRun)
+	return nil
+}
+
+// parseIngestArgs parses the path and flags of `thane ingest`.
+func parseIngestArgs(args []string) (ingestArgs, error) {
+	var parsed ingestArgs
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		switch {
+		case name == "--exclude":
+			if !hasValue {
+				if i+1 >= len(args) {
+					return parsed, fmt.Errorf("%s requires a value", name)
+				}
+				i++
+				value = args[i]
+			}
+			parsed.exclude = append(parsed.exclude, value)
+		case name == "--dry-run" && !hasValue:
+			parsed.dryRun = true
+		case strings.HasPrefix(args[i], "-") || parsed.path != "":
+			return parsed, fmt.Errorf("%s", ingestUsage)
+		default:
+			parsed.path = args[i]
+		}
+	}
+	if parsed.path == "" {
+		return parsed, fmt.Errorf("%s", ingestUsage)
+	}
+	return parsed, nil
+}
+
+// writeIngestDirText prints a directory ingest result, one line per
+// changed file and a summary.
+func writeIngestDirText(w io.Writer, r knowledge.DirIngestResult, dryRun bool) {
+	ingested, removed := "ingested", "removed"
+	if dryRun {
+		ingested, removed = "would ingest", "would remove"
+	}
+	for _, rel := range r.Ingested {
+		fmt.Fprintf(w, "%s  %s\n", ingested, rel)
+	}
+	for _, rel := range r.Removed {
+		fmt.Fprintf(w, "%s  %s\n", removed, rel)
+	}
+	failed := make([]string, 0, len(r.Failed))
+	for rel := range r.Failed {
+		failed = append(failed, rel)
+	}
+	sort.Strings(failed)
+	for _, rel := range failed {
+		fmt.Fprintf(w, "failed  %s: %s\n", rel, r.Failed[rel])
+	}
+
+	summary := fmt.Sprintf("%d %s, %d unchanged, %d %s", len(r.Ingested), ingested, len(r.Unchanged), len(r.Removed), removed)
+	if len(r.Failed) > 0 {
+		summary += fmt.Sprintf(", %d failed", len(r.Failed))
+	}
+	if !dryRun {
+		summary += fmt.Sprintf(" (%d facts written)", r.Facts)
+	}
+	fmt.Fprintln(w, summary+".")
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/state/knowledge"
+)
+
+func TestParseIngestArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		want      ingestArgs
+		wantError string
+	}{
+		{name: "file", args: []string{"notes.md"}, want: ingestArgs{path: "notes.md"}},
+		{
+			name: "directory with flags",
+			args: []string{"kb", "--exclude", "drafts", "--exclude=*.pdf", "--dry-run"},
+			want: ingestArgs{path: "kb", exclude: []string{"drafts", "*.pdf"}, dryRun: true},
+		},
+		{name: "flags first", args: []string{"--dry-run", "kb"}, want: ingestArgs{path: "kb", dryRun: true}},
+		{name: "missing path", args: []string{"--dry-run"}, wantError: "usage"},
+		{name: "two paths", args: []string{"a", "b"}, wantError: "usage"},
+		{name: "missing exclude", args: []string{"kb", "--exclude"}, wantError: "requires a value"},
+		{name: "unknown flag", args: []string{"kb", "--force"}, wantError: "usage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseIngestArgs(tt.args)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.path != tt.want.path || got.dryRun != tt.want.dryRun || !slices.Equal(got.exclude, tt.want.exclude) {
+				t.Errorf("parseIngestArgs = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteIngestDirText(t *testing.T) {
+	var buf bytes.Buffer
+	writeIngestDirText(&buf, knowledge.DirIngestResult{
+		Ingested:  []string{"network.md"},
+		Unchanged: []string{"a.md", "b.md"},
+		Removed:   []string{"old.txt"},
+		Failed:    map[string]string{"broken.pdf": "read pdf: malformed"},
+	}, true)
+	out := buf.String()
+	for _, want := range []string{
+		"would ingest  network.md",
+		"would remove  old.txt",
+		"failed  broken.pdf: read pdf: malformed",
+		"1 would ingest, 2 unchanged, 1 would remove, 1 failed.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
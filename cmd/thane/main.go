@@ -9,8 +9,15 @@
 //
 //	thane serve              Start the API server
 //	thane init [dir]         Initialize a working directory with defaults
-//	thane ask <question>     Ask a single question (for testing)
-//	thane ingest <file.md>   Import a markdown document into the fact store
+//	thane ask <question>     Ask a question (--stream, --conversation <id> to continue one)
+//	thane chat               Chat with the fully configured agent in the terminal
+//	thane ingest <path>      Import a markdown, text, or PDF document (or a directory of them) into the fact store
+//	thane archive prune      Apply the archive retention policy (--dry-run to preview)
+//	thane export <session>   Export an archived session (or --conversation) as markdown
+//	thane usage report       Report LLM spend by model, provider, role, task, or day
+//	thane audit              List audited tool executions (--tool, --conversation, --since, --until)
+//	thane tools policy list  Show conversation tool allowlists; set and delete manage them
+//	thane checkpoint list    List state snapshots; restore <id> rewinds to one (--dry-run to preview)
 //	thane version            Print version and build information
 //	thane -o json version    Output version information as JSON
 //
@@ -32,29 +39,24 @@ import (
 	"time"
 
 	"github.com/nugget/thane-ai-agent/internal/app"
-	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
 	"github.com/nugget/thane-ai-agent/internal/model/fleet"
 	modelproviders "github.com/nugget/thane-ai-agent/internal/model/fleet/providers"
 	"github.com/nugget/thane-ai-agent/internal/model/llm"
-	"github.com/nugget/thane-ai-agent/internal/model/talents"
 	"github.com/nugget/thane-ai-agent/internal/platform/buildinfo"
 	"github.com/nugget/thane-ai-agent/internal/platform/config"
-	"github.com/nugget/thane-ai-agent/internal/platform/database"
 	"github.com/nugget/thane-ai-agent/internal/platform/httpkit"
 	"github.com/nugget/thane-ai-agent/internal/platform/logging"
-	"github.com/nugget/thane-ai-agent/internal/runtime/agent"
-	"github.com/nugget/thane-ai-agent/internal/state/knowledge"
-	"github.com/nugget/thane-ai-agent/internal/state/memory"
 )
 
 // main is intentionally minimal. It constructs the OS-level environment
 // (context, stdio, argv) and delegates immediately to [run]. This keeps
-// os.Exit, os.Stdout, and os.Args out of the application logic so that
-// the full startup-to-shutdown lifecycle can be driven from tests.
+// os.Exit, os.Stdin, os.Stdout, and os.Args out of the application
+// logic so that the full startup-to-shutdown lifecycle can be driven
+// from tests.
 func main() {
 	ctx := context.Background()
 
-	if err := run(ctx, os.Stdout, os.Stderr, os.Args[1:]); err != nil {
+	if err := run(ctx, os.Stdin, os.Stdout, os.Stderr, os.Args[1:]); err != nil {
 		fmt.Fprintf(os.Stderr, "%s\n", err)
 		os.Exit(1)
 	}
@@ -65,6 +67,7 @@ func main() {
 //
 //   - ctx controls the lifetime of the process. Cancelling it triggers
 //     graceful shutdown of all servers and background goroutines.
+//   - stdin is read by interactive commands (chat).
 //   - stdout and stderr receive all program output. Structured logs go
 //     to stdout; fatal error messages go to stderr.
 //   - args is os.Args[1:] — the command-line arguments after the program
@@ -73,7 +76,7 @@ func main() {
 //
 // run returns nil on clean shutdown and a non-nil error for any failure.
 // The caller (main) is responsible for printing the error and exiting.
-func run(ctx context.Context, stdout io.Writer, stderr io.Writer, args []string) error {
+func run(ctx context.Context, stdin io.Reader, stdout io.Writer, stderr io.Writer, args []string) error {
 	// Parse arguments by hand. The flag package relies on package-level
 	// globals (flag.CommandLine), which makes it impossible to call run()
 	// concurrently from tests. Our argument surface is small enough that
@@ -90,6 +93,10 @@ func run(ctx context.Context, stdout io.Writer, stderr io.Writer, args []string)
 			i++ // skip the value
 		case strings.HasPrefix(args[i], "-config="):
 			configPath = strings.TrimPrefix(args[i], "-config=")
+		case command == "export" && (args[i] == "-o" || strings.HasPrefix(args[i], "-o=")):
+			// export claims -o for its output file, so leave it (and
+			// the value that follows) to the subcommand.
+			cmdArgs = append(cmdArgs, args[i])
 		case (args[i] == "-o" || args[i] == "--output") && i+1 < len(args):
 			outputFmt = args[i+1]
 			i++
@@ -131,21 +138,35 @@ func run(ctx context.Context, stdout io.Writer, stderr io.Writer, args []string)
 	case "validate":
 		return runValidate(stdout, configPath, outputFmt)
 	case "ask":
-		if len(cmdArgs) == 0 {
-			return fmt.Errorf("usage: thane ask <question>")
-		}
 		return runAsk(ctx, stdout, stderr, configPath, cmdArgs)
+	case "chat":
+		return runChat(ctx, stdin, stdout, stderr, configPath, cmdArgs)
 	case "ingest":
-		if len(cmdArgs) == 0 {
-			return fmt.Errorf("usage: thane ingest <file.md>")
-		}
-		return runIngest(ctx, stdout, stderr, configPath, cmdArgs[0])
+		return runIngest(ctx, stdout, stderr, configPath, outputFmt, cmdArgs)
 	case "version":
 		return runVersion(stdout, outputFmt)
 	case "health":
 		return runHealth(ctx, stdout, cmdArgs)
 	case "caps":
 		return runCaps(ctx, stdout, configPath, outputFmt, cmdArgs)
+	case "archive":
+		return runArchive(stdout, stderr, configPath, outputFmt, cmdArgs)
+	case "export":
+		return runExport(stdout, stderr, configPath, cmdArgs)
+	case "contacts":
+		return runContacts(stdout, stderr, configPath, outputFmt, cmdArgs)
+	case "embeddings":
+		return runEmbeddings(ctx, stdout, stderr, configPath, outputFmt, cmdArgs)
+	case "usage":
+		return runUsage(stdout, stderr, configPath, outputFmt, cmdArgs)
+	case "audit":
+		return runAudit(ctx, stdout, stderr, configPath, outputFmt, cmdArgs)
+	case "tools":
+		return runTools(stdout, stderr, configPath, outputFmt, cmdArgs)
+	case "checkpoint":
+		return runCheckpoint(ctx, stdout, stderr, configPath, outputFmt, cmdArgs)
+	case "delegate":
+		return runDelegate(ctx, stdout, configPath, outputFmt, cmdArgs)
 	case "":
 		return printUsage(stdout)
 	default:
@@ -211,9 +232,19 @@ func printUsage(w io.Writer) error {
 	fmt.Fprintln(w, "  serve        Start the API server")
 	fmt.Fprintln(w, "  init [dir]   Initialize working directory with defaults (default: .)")
 	fmt.Fprintln(w, "  validate     Parse and validate the config without starting services")
-	fmt.Fprintln(w, "  ask          Ask a single question (for testing)")
-	fmt.Fprintln(w, "  ingest       Import markdown docs into fact store")
+	fmt.Fprintln(w, "  ask          Ask a question: [--stream] [--conversation ID] <question>")
+	fmt.Fprintln(w, "  chat         Interactive chat with the full agent: [--conversation ID] [--verbose]")
+	fmt.Fprintln(w, "  ingest       Import markdown, text, or PDF docs (file or directory) into fact store")
 	fmt.Fprintln(w, "  caps         Show resolved capability tags from a running daemon")
+	fmt.Fprintln(w, "  archive      Archive maintenance: prune [--dry-run] applies the retention policy")
+	fmt.Fprintln(w, "  export       Export an archived session or --conversation as markdown [-o file]")
+	fmt.Fprintln(w, "  contacts     Contact directory: import [--dry-run] [--country-code N] <file.vcf>")
+	fmt.Fprintln(w, "  embeddings   Semantic search index: backfill [--batch-size N] embeds facts and contacts")
+	fmt.Fprintln(w, "  usage        Spend report: report [--since T] [--until T] [--group-by model|provider|role|task|day]")
+	fmt.Fprintln(w, "  audit        Tool execution log: [--tool NAME] [--conversation ID] [--since T] [--until T] [--redact]")
+	fmt.Fprintln(w, "  tools        Conversation tool allowlists: policy list | set <selector> <tools> | delete <selector>")
+	fmt.Fprintln(w, "  checkpoint   State snapshots: list [--limit N], restore [--dry-run] <id> (server stopped)")
+	fmt.Fprintln(w, "  delegate     Delegate executions: replay <session-id> [--model name] reruns one on a daemon")
 	fmt.Fprintln(w, "  health [url] Probe a running daemon's /health endpoint (exit 0 if healthy)")
 	fmt.Fprintln(w, "  version      Show version information")
 	fmt.Fprintln(w)
@@ -227,117 +258,6 @@ func printUsage(w io.Writer) error {
 	return nil
 }
 
-// runAsk handles the "thane ask <question>" subcommand. It boots a
-// minimal agent (in-memory conversation store, no router, no scheduler)
-// and processes a single question, printing the response to stdout.
-// Useful for quick smoke tests and debugging without starting the server.
-func runAsk(ctx context.Context, stdout io.Writer, stderr io.Writer, configPath string, args []string) error {
-	logger := newLogger(stdout, slog.LevelInfo, "text")
-
-	question := strings.Join(args, " ")
-
-	cfg, cfgPath, err := loadConfig(configPath)
-	if err != nil {
-		return err
-	}
-	logger.Info("config loaded", "path", cfgPath)
-
-	// Home Assistant client (optional — ask works without it)
-	var ha *homeassistant.Client
-	if cfg.HomeAssistant.Configured() {
-		ha = homeassistant.NewClient(cfg.HomeAssistant.URL, cfg.HomeAssistant.Token, logger)
-	}
-
-	llmSetup, err := createLLMSetup(ctx, cfg, logger)
-	if err != nil {
-		return err
-	}
-	logLLMSetup(logger, llmSetup)
-
-	talentLoader := talents.NewLoader(cfg.TalentsDir)
-	cliTalents, _ := talentLoader.Talents()
-
-	// In-memory store is fine for a single question — nothing to persist.
-	mem := memory.NewStore(100)
-
-	// Minimal loop: no router, no scheduler, no compactor. The default
-	// model handles everything for CLI one-shots.
-	var haInject homeassistant.StateFetcher
-	if ha != nil {
-		haInject = ha
-	}
-	loop, err := agent.NewLoop(agent.LoopOptions{
-		Logger:        logger,
-		Memory:        mem,
-		HomeAssistant: ha,
-		LLM:           llmSetup.Client,
-		Model:         llmSetup.Catalog.DefaultModel,
-		ParsedTalents: cliTalents,
-		HAInject:      haInject,
-	})
-	if err != nil {
-		return fmt.Errorf("build agent loop: %w", err)
-	}
-
-	response, err := loop.Process(ctx, "cli-test", question)
-	if err != nil {
-		return fmt.Errorf("ask: %w", err)
-	}
-
-	fmt.Fprintln(stdout, response)
-	return nil
-}
-
-// runIngest handles the "thane ingest <file.md>" subcommand. It parses
-// a markdown document into discrete facts and stores them in the fact
-// database, optionally generating embeddings for semantic search.
-func runIngest(ctx context.Context, stdout io.Writer, stderr io.Writer, configPath string, filePath string) error {
-	logger := newLogger(stdout, slog.LevelInfo, "text")
-	logger.Info("ingesting markdown document", "file", filePath)
-
-	cfg, _, err := loadConfig(configPath)
-	if err != nil {
-		return err
-	}
-
-	if err := os.MkdirAll(cfg.DataDir, 0755); err != nil {
-		return fmt.Errorf("create data directory: %w", err)
-	}
-
-	factDB, err := database.Open(cfg.DataDir + "/knowledge.db")
-	if err != nil {
-		return fmt.Errorf("open knowledge database: %w", err)
-	}
-	defer factDB.Close()
-	factStore, err := knowledge.NewStore(factDB, logger)
-	if err != nil {
-		return fmt.Errorf("open fact store: %w", err)
-	}
-
-	// Embeddings are optional. When enabled, each ingested fact gets a
-	// vector embedding for later semantic search.
-	var embClient knowledge.EmbeddingClient
-	if cfg.Embeddings.Enabled {
-		embClient = knowledge.New(knowledge.Config{
-			BaseURL: cfg.Embeddings.BaseURL,
-			Model:   cfg.Embeddings.Model,
-		})
-		logger.Info("embeddings enabled", "model", cfg.Embeddings.Model)
-	}
-
-	source := "file:" + filePath
-	ingester := knowledge.NewMarkdownIngester(factStore, embClient, source, knowledge.CategoryArchitecture)
-
-	count, err := ingester.IngestFile(ctx, filePath)
-	if err != nil {
-		return fmt.Errorf("ingestion failed: %w", err)
-	}
-
-	logger.Info("ingestion complete", "facts_created", count, "source", source)
-	fmt.Fprintf(stdout, "Successfully ingested %d facts from %s\n", count, filePath)
-	return nil
-}
-
 // runServe handles the "thane serve" subcommand. It loads config,
 // constructs the App via [app.New], and then runs [app.Serve] which
 // blocks until a shutdown signal arrives.
@@ -412,6 +332,22 @@ func runServe(ctx context.Context, stdout io.Writer, stderr io.Writer, configPat
 	defer cancel()
 	defer stopSignals()
 
+	// SIGHUP re-reads the config file and applies what can change in
+	// place (see [app.App.Reload]); everything else waits for a restart.
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+	go func() {
+		for {
+			select {
+			case <-hupCh:
+				reloadConfig(ctx, a, cfgPath)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
 	logLLMSetup(a.Logger(), llmSetup)
 
 	// Log with the fully-configured logger (file handler, index handler,
@@ -432,6 +368,30 @@ func runServe(ctx context.Context, stdout io.Writer, stderr io.Writer, configPat
 	return a.Serve(ctx)
 }
 
+// reloadConfig handles SIGHUP for the serve command: it reloads the
+// config from cfgPath, normalizes model references against the new
+// catalog the same way startup does, and hands the result to
+// [app.App.Reload]. A config that fails to load or validate is logged
+// and the running config is left untouched.
+func reloadConfig(ctx context.Context, a *app.App, cfgPath string) {
+	logger := a.Logger()
+	logger.Info("received SIGHUP, reloading config", "path", cfgPath)
+
+	next, _, err := loadConfig(cfgPath)
+	if err != nil {
+		logger.Error("config reload failed; keeping running config", "path", cfgPath, "error", err)
+		return
+	}
+	base, err := fleet.BuildCatalog(next)
+	if err != nil {
+		logger.Error("config reload failed; keeping running config", "path", cfgPath, "error", fmt.Errorf("build model catalog: %w", err))
+		return
+	}
+	normalizeConfiguredModelRefs(next, base)
+
+	a.Reload(ctx, next, base)
+}
+
 // newHandler creates a structured [slog.Handler] that writes to w at
 // the given level and format. This is the shared handler construction
 // used by [newLogger] and (with optional wrapping) by the serve command.
@@ -579,4 +539,16 @@ func normalizeConfiguredModelRefs(cfg *config.Config, catalog *fleet.Catalog) {
 	cfg.Extraction.Model = resolve(cfg.Extraction.Model)
 	cfg.Media.SummarizeModel = resolve(cfg.Media.SummarizeModel)
 	cfg.Attachments.Vision.Model = resolve(cfg.Attachments.Vision.Model)
+
+	if len(cfg.Models.FallbackChains) > 0 {
+		chains := make(map[string][]string, len(cfg.Models.FallbackChains))
+		for primary, chain := range cfg.Models.FallbackChains {
+			resolved := make([]string, len(chain))
+			for i, hop := range chain {
+				resolved[i] = resolve(hop)
+			}
+			chains[resolve(primary)] = resolved
+		}
+		cfg.Models.FallbackChains = chains
+	}
 }
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/platform/opstate"
+	"github.com/nugget/thane-ai-agent/internal/runtime/agent"
+)
+
+// toolPolicyUsage is returned for a malformed `thane tools policy` call.
+const toolPolicyUsage = "usage: thane tools policy list | set <selector> <tool,tool,...> [--reason TEXT] | delete <selector>"
+
+// runTools dispatches the `thane tools <subcommand>` family. Only
+// policy exists today.
+func runTools(stdout, stderr io.Writer, configPath, outputFmt string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", toolPolicyUsage)
+	}
+	switch args[0] {
+	case "policy":
+		return runToolPolicy(stdout, stderr, configPath, outputFmt, args[1:])
+	default:
+		return fmt.Errorf("unknown tools command: %s", args[0])
+	}
+}
+
+// runToolPolicy implements `thane tools policy`, which manages the
+// conversation tool allowlists in thane.db. A selector is a
+// conversation ID prefix ("signal-") or a channel ("channel:signal").
+// The running server reads policies on every turn, so changes apply
+// without a restart.
+func runToolPolicy(stdout, stderr io.Writer, configPath, outputFmt string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", toolPolicyUsage)
+	}
+	cfg, _, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cfg.DataDir, 0755); err != nil {
+		return fmt.Errorf("create data directory: %w", err)
+	}
+	db, err := database.Open(cfg.DataDir + "/thane.db")
+	if err != nil {
+		return fmt.Errorf("open operational state: %w", err)
+	}
+	defer db.Close()
+	state, err := opstate.NewStore(db, newLogger(stderr, slog.LevelWarn, "text"))
+	if err != nil {
+		return fmt.Errorf("open operational state: %w", err)
+	}
+	store := agent.NewOpstateToolPolicyStore(state)
+
+	switch args[0] {
+	case "list":
+		if len(args) != 1 {
+			return fmt.Errorf("%s", toolPolicyUsage)
+		}
+		policies, err := store.List()
+		if err != nil {
+			return err
+		}
+		if outputFmt == "json" {
+			enc := json.NewEncoder(stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(policies)
+		}
+		writeToolPoliciesText(stdout, policies)
+		return nil
+	case "set":
+		selector, policy, err := parseToolPolicySet(args[1:])
+		if err != nil {
+			return err
+		}
+		if err := store.Set(selector, policy); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Set tool policy %s: %s\n", selector, strings.Join(policy.Allow, ", "))
+		return nil
+	case "delete":
+		if len(args) != 2 {
+			return fmt.Errorf("%s", toolPolicyUsage)
+		}
+		if err := store.Delete(args[1]); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Deleted tool policy %s\n", args[1])
+		return nil
+	default:
+		return fmt.Errorf("unknown tools policy command: %s", args[0])
+	}
+}
+
+// parseToolPolicySet parses the arguments of `thane tools policy set`.
+func parseToolPolicySet(args []string) (string, agent.ToolPolicy, error) {
+	var policy agent.ToolPolicy
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		switch {
+		case name == "--reason":
+			if !hasValue {
+				if i+1 >= len(args) {
+					return "", policy, fmt.Errorf("%s requires a value", name)
+				}
+				i++
+				value = args[i]
+			}
+			policy.Reason = value
+		case strings.HasPrefix(args[i], "-"):
+			return "", policy, fmt.Errorf("%s", toolPolicyUsage)
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+	if len(positional) != 2 {
+		return "", policy, fmt.Errorf("%s", toolPolicyUsage)
+	}
+	policy.Allow = strings.Split(positional[1], ",")
+	return positional[0], policy, nil
+}
+
+// writeToolPoliciesText prints one line per policy, ordered by selector.
+func writeToolPoliciesText(w io.Writer, policies map[string]agent.ToolPolicy) {
+	if len(policies) == 0 {
+		fmt.Fprintln(w, "No conversation tool policies.")
+		return
+	}
+	selectors := make([]string, 0, len(policies))
+	for selector := range policies {
+		selectors = append(selectors, selector)
+	}
+	sort.Strings(selectors)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SELECTOR\tALLOWED TOOLS\tREASON")
+	for _, selector := range selectors {
+		p := policies[selector]
+		allow := strings.Join(p.Allow, ",")
+		if allow == "" {
+			allow = "(none)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", selector, allow, p.Reason)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/agent"
+)
+
+func TestParseToolPolicySet(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		wantSelector string
+		wantAllow    []string
+		wantReason   string
+		wantError    string
+	}{
+		{name: "basic", args: []string{"channel:signal", "web_search,recall_fact"},
+			wantSelector: "channel:signal", wantAllow: []string{"web_search", "recall_fact"}},
+		{name: "reason", args: []string{"--reason", "external senders", "signal-", "web_search"},
+			wantSelector: "signal-", wantAllow: []string{"web_search"}, wantReason: "external senders"},
+		{name: "reason equals", args: []string{"kiosk", "web_search", "--reason=lobby tablet"},
+			wantSelector: "kiosk", wantAllow: []string{"web_search"}, wantReason: "lobby tablet"},
+		{name: "missing tools", args: []string{"kiosk"}, wantError: "usage:"},
+		{name: "missing reason", args: []string{"kiosk", "web_search", "--reason"}, wantError: "requires a value"},
+		{name: "unknown flag", args: []string{"kiosk", "web_search", "--force"}, wantError: "usage:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, policy, err := parseToolPolicySet(tt.args)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if selector != tt.wantSelector || !reflect.DeepEqual(policy.Allow, tt.wantAllow) || policy.Reason != tt.wantReason {
+				t.Errorf("got %q %+v, want %q allow %v reason %q", selector, policy, tt.wantSelector, tt.wantAllow, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestWriteToolPoliciesText(t *testing.T) {
+	var buf bytes.Buffer
+	writeToolPoliciesText(&buf, nil)
+	if !strings.Contains(buf.String(), "No conversation tool policies") {
+		t.Errorf("empty output = %q", buf.String())
+	}
+
+	buf.Reset()
+	writeToolPoliciesText(&buf, map[string]agent.ToolPolicy{
+		"signal-":        {Allow: []string{"web_search"}},
+		"channel:signal": {Allow: nil, Reason: "lockdown"},
+	})
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("lines = %q, want header and 2 rows", lines)
+	}
+	if !strings.HasPrefix(lines[1], "channel:signal") || !strings.Contains(lines[1], "(none)") || !strings.Contains(lines[1], "lockdown") {
+		t.Errorf("row 1 = %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "signal-") || !strings.Contains(lines[2], "web_search") {
+		t.Errorf("row 2 = %q", lines[2])
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/platform/usage"
+)
+
+// usageReportUsage is returned for a malformed `thane usage` call.
+const usageReportUsage = "usage: thane usage report [--since T] [--until T] [--group-by model|provider|role|task|day]"
+
+// usageReportGroups are the --group-by values, in the order a full
+// report prints them. "day" is a time series rather than a breakdown,
+// so it is only printed when asked for.
+var usageReportGroups = []string{"model", "provider", "role", "task"}
+
+// runUsage dispatches the `thane usage <subcommand>` family. Only
+// report exists today.
+func runUsage(stdout, stderr io.Writer, configPath, outputFmt string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", usageReportUsage)
+	}
+	switch args[0] {
+	case "report":
+		return runUsageReport(stdout, stderr, configPath, outputFmt, args[1:])
+	default:
+		return fmt.Errorf("unknown usage command: %s", args[0])
+	}
+}
+
+// usageReportArgs are the parsed flags of `thane usage report`.
+type usageReportArgs struct {
+	since   time.Time
+	until   time.Time
+	groupBy string // empty for every breakdown
+}
+
+// usageReport is the `thane usage report` result. Breakdowns are
+// ordered by cost, highest first; ByDay is chronological.
+type usageReport struct {
+	Since      time.Time              `json:"since"`
+	Until      time.Time              `json:"until"`
+	Total      usage.Summary          `json:"total"`
+	ByModel    []usage.GroupedSummary `json:"by_model,omitempty"`
+	ByProvider []usage.GroupedSummary `json:"by_provider,omitempty"`
+	ByRole     []usage.GroupedSummary `json:"by_role,omitempty"`
+	ByTask     []usage.GroupedSummary `json:"by_task,omitempty"`
+	ByDay      []usage.GroupedSummary `json:"by_day,omitempty"`
+}
+
+// runUsageReport implements `thane usage report`. It reads the usage
+// store in the configured data directory — the same records the
+// cost_summary tool reports to the agent — without starting the
+// server, so it can run from cron while the daemon is up. The window
+// defaults to the last 30 days; without --group-by it breaks spend down
+// by model, provider, role, and task.
+func runUsageReport(stdout, stderr io.Writer, configPath, outputFmt string, args []string) error {
+	cfg, _, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	loc := time.Local
+	if cfg.Timezone != "" {
+		loc, _ = time.LoadLocation(cfg.Timezone) // already validated
+	}
+
+	parsed, err := parseUsageReportArgs(args, time.Now(), loc)
+	if err != nil {
+		return err
+	}
+
+	dbPath := cfg.DataDir + "/thane.db"
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("open usage store: %w", err)
+	}
+	db, err := database.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("open usage store: %w", err)
+	}
+	defer db.Close()
+	store, err := usage.NewStore(db, newLogger(stderr, slog.LevelWarn, "text"))
+	if err != nil {
+		return fmt.Errorf("open usage store: %w", err)
+	}
+
+	report, err := buildUsageReport(store, parsed, loc)
+	if err != nil {
+		return err
+	}
+
+	if outputFmt == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	writeUsageReportText(stdout, report, loc)
+	return nil
+}
+
+// buildUsageReport runs the total and the requested breakdowns.
+func buildUsageReport(store *usage.Store, args usageReportArgs, loc *time.Location) (*usageReport, error) {
+	total, err := store.Summary(args.since, args.until)
+	if err != nil {
+		return nil, fmt.Errorf("query usage summary: %w", err)
+	}
+	report := &usageReport{Since: args.since, Until: args.until, Total: *total}
+
+	if args.groupBy == "day" {
+		report.ByDay, err = store.SummaryByDay(args.since, args.until, loc)
+		return report, err
+	}
+	for _, group := range usageReportGroups {
+		if args.groupBy != "" && args.groupBy != group {
+			continue
+		}
+		grouped, err := store.SummaryByGroup(group, args.since, args.until)
+		if err != nil {
+			return nil, err
+		}
+		switch group {
+		case "model":
+			report.ByModel = grouped
+		case "provider":
+			report.ByProvider = grouped
+		case "role":
+			report.ByRole = grouped
+		case "task":
+			report.ByTask = grouped
+		}
+	}
+	return report, nil
+}
+
+// parseUsageReportArgs parses the flags of `thane usage report`. now
+// and loc anchor relative and date-only times.
+func parseUsageReportArgs(args []string, now time.Time, loc *time.Location) (usageReportArgs, error) {
+	parsed := usageReportArgs{since: now.AddDate(0, 0, -30), until: now}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--since", "--until", "--group-by":
+		default:
+			return parsed, fmt.Errorf("unknown usage report flag: %s", arg)
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return parsed, fmt.Errorf("%s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		switch name {
+		case "--since", "--until":
+			t, err := parseUsageTime(value, now, loc)
+			if err != nil {
+				return parsed, fmt.Errorf("%s: %w", name, err)
+			}
+			if name == "--since" {
+				parsed.since = t
+			} else {
+				parsed.until = t
+			}
+		case "--group-by":
+			value = strings.ToLower(value)
+			if value != "day" && !slices.Contains(usageReportGroups, value) {
+				return parsed, fmt.Errorf("--group-by: %q is not one of model, provider, role, task, day", value)
+			}
+			parsed.groupBy = value
+		}
+	}
+	if !parsed.since.Before(parsed.until) {
+		return parsed, fmt.Errorf("--since must be before --until")
+	}
+	return parsed, nil
+}
+
+// parseUsageTime accepts a date (2006-01-02, midnight in loc), an
+// RFC 3339 timestamp, or an age before now: a Go duration ("36h") or a
+// whole number of days ("7d").
+func parseUsageTime(value string, now time.Time, loc *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date (2006-01-02), RFC 3339 time, or age (7d, 36h)", value)
+}
+
+// writeUsageReportText prints the total followed by one table per
+// breakdown.
+func writeUsageReportText(w io.Writer, report *usageReport, loc *time.Location) {
+	const stamp = "2006-01-02 15:04"
+	fmt.Fprintf(w, "Usage %s to %s (%s)\n",
+		report.Since.In(loc).Format(stamp), report.Until.In(loc).Format(stamp), loc)
+	fmt.Fprintf(w, "Total: $%.4f across %d requests (%d input / %d output tokens)\n",
+		report.Total.TotalCostUSD, report.Total.TotalRecords,
+		report.Total.TotalInputTokens, report.Total.TotalOutputTokens)
+
+	sections := []struct {
+		heading string
+		rows    []usage.GroupedSummary
+	}{
+		{"MODEL", report.ByModel},
+		{"PROVIDER", report.ByProvider},
+		{"ROLE", report.ByRole},
+		{"TASK", report.ByTask},
+		{"DAY", report.ByDay},
+	}
+	for _, sec := range sections {
+		if len(sec.rows) == 0 {
+			continue
+		}
+		fmt.Fprintln(w)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "%s\tCOST\tREQUESTS\tINPUT\tOUTPUT\n", sec.heading)
+		for _, row := range sec.rows {
+			key := row.Key
+			if key == "" {
+				key = "(none)"
+			}
+			fmt.Fprintf(tw, "%s\t$%.4f\t%d\t%d\t%d\n", key,
+				row.Summary.TotalCostUSD, row.Summary.TotalRecords,
+				row.Summary.TotalInputTokens, row.Summary.TotalOutputTokens)
+		}
+		_ = tw.Flush()
+	}
+}
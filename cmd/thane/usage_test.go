@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/platform/usage"
+)
+
+func TestParseUsageReportArgs(t *testing.T) {
+	loc := time.UTC
+	now := time.Date(2026, 3, 14, 15, 0, 0, 0, loc)
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantSince time.Time
+		wantUntil time.Time
+		wantGroup string
+		wantError string
+	}{
+		{name: "defaults", wantSince: now.AddDate(0, 0, -30), wantUntil: now},
+		{name: "dates", args: []string{"--since", "2026-03-01", "--until=2026-03-08"},
+			wantSince: time.Date(2026, 3, 1, 0, 0, 0, 0, loc), wantUntil: time.Date(2026, 3, 8, 0, 0, 0, 0, loc)},
+		{name: "relative", args: []string{"--since=7d", "--group-by", "day"},
+			wantSince: now.AddDate(0, 0, -7), wantUntil: now, wantGroup: "day"},
+		{name: "duration", args: []string{"--since", "36h", "--group-by=Provider"},
+			wantSince: now.Add(-36 * time.Hour), wantUntil: now, wantGroup: "provider"},
+		{name: "rfc3339", args: []string{"--since", "2026-03-14T06:00:00Z"},
+			wantSince: time.Date(2026, 3, 14, 6, 0, 0, 0, loc), wantUntil: now},
+		{name: "bad time", args: []string{"--since", "last tuesday"}, wantError: "is not a date"},
+		{name: "bad group", args: []string{"--group-by", "week"}, wantError: "--group-by"},
+		{name: "inverted", args: []string{"--since", "2026-03-10", "--until", "2026-03-01"}, wantError: "before --until"},
+		{name: "missing value", args: []string{"--since"}, wantError: "requires a value"},
+		{name: "unknown flag", args: []string{"--csv"}, wantError: "unknown usage report flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseUsageReportArgs(tt.args, now, loc)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !parsed.since.Equal(tt.wantSince) || !parsed.until.Equal(tt.wantUntil) || parsed.groupBy != tt.wantGroup {
+				t.Errorf("parsed = %+v, want since %v until %v group %q", parsed, tt.wantSince, tt.wantUntil, tt.wantGroup)
+			}
+		})
+	}
+}
+
+func TestBuildUsageReport(t *testing.T) {
+	db, err := database.OpenMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := usage.NewStore(db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	for _, rec := range []usage.Record{
+		{Timestamp: day, RequestID: "r1", Model: "opus", Provider: "anthropic", Role: "interactive", CostUSD: 2.0},
+		{Timestamp: day.Add(time.Hour), RequestID: "r2", Model: "sonnet", Provider: "anthropic", Role: "scheduled", TaskName: "email_poll", CostUSD: 0.5},
+		{Timestamp: day.AddDate(0, 0, 1), RequestID: "r3", Model: "qwen", Provider: "ollama", Role: "auxiliary"},
+	} {
+		if err := store.Record(context.Background(), rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	window := usageReportArgs{since: day.AddDate(0, 0, -1), until: day.AddDate(0, 0, 2)}
+
+	report, err := buildUsageReport(store, window, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total.TotalRecords != 3 || report.Total.TotalCostUSD != 2.5 {
+		t.Errorf("total = %+v, want 3 records costing $2.50", report.Total)
+	}
+	if len(report.ByModel) != 3 || report.ByModel[0].Key != "opus" {
+		t.Errorf("by model = %+v, want opus first", report.ByModel)
+	}
+	if len(report.ByProvider) != 2 || len(report.ByRole) != 3 || len(report.ByTask) != 2 || report.ByDay != nil {
+		t.Errorf("breakdowns = %+v", report)
+	}
+
+	var out bytes.Buffer
+	writeUsageReportText(&out, report, time.UTC)
+	for _, want := range []string{"Total: $2.5000 across 3 requests", "PROVIDER", "email_poll", "(none)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("text report missing %q:\n%s", want, out.String())
+		}
+	}
+
+	window.groupBy = "day"
+	report, err = buildUsageReport(store, window, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.ByDay) != 4 || report.ByDay[1].Key != "2026-03-14" || report.ByDay[1].Summary.TotalCostUSD != 2.5 {
+		t.Errorf("by day = %+v, want four days with $2.50 on 2026-03-14", report.ByDay)
+	}
+	if report.ByModel != nil {
+		t.Error("day series should not include other breakdowns")
+	}
+}
@@ -4,7 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"sort"
+	"strings"
 
+	"github.com/nugget/thane-ai-agent/internal/model/fleet"
+	"github.com/nugget/thane-ai-agent/internal/model/toolcatalog"
 	"github.com/nugget/thane-ai-agent/internal/platform/config"
 )
 
@@ -18,10 +23,18 @@ import (
 // signals "config is invalid" so the binary exits non-zero, which
 // makes `thane validate && thane serve` a usable deploy guard.
 //
-// Output mode "text" prints a one-line confirmation followed by a
-// short structural summary. Mode "json" emits a single object with
-// path, valid, error (if any), and summary fields, suitable for
-// piping into jq.
+// Beyond what [config.Load] checks, the model catalog is built exactly
+// as `thane serve` builds it, so a model list the router would reject
+// also fails here. The report then describes what the config will do:
+// which integrations are configured, which models route to which
+// providers, which native tools each capability tag carries, and any
+// warnings — settings that load fine but probably do not do what the
+// operator meant. Warnings never fail validation.
+//
+// Output mode "text" prints a one-line confirmation followed by the
+// report. Mode "json" emits a single object with path, valid, error
+// (if any), summary, and the report fields, suitable for piping into
+// jq.
 func runValidate(w io.Writer, configPath, outputFmt string) error {
 	cfg, cfgPath, loadErr := loadConfig(configPath)
 	// When discovery fails before a path is resolved, fall back to
@@ -31,11 +44,15 @@ func runValidate(w io.Writer, configPath, outputFmt string) error {
 	if cfgPath == "" {
 		cfgPath = configPath
 	}
+	var report *validateReport
+	if loadErr == nil {
+		report, loadErr = buildValidateReport(cfg)
+	}
 	if outputFmt == "json" {
 		// Always emit JSON to stdout, even on failure — scripts may
 		// want the structured error. The error is still returned so
 		// the exit code reflects validity.
-		if err := writeValidateJSON(w, cfgPath, cfg, loadErr); err != nil {
+		if err := writeValidateJSON(w, cfgPath, cfg, report, loadErr); err != nil {
 			return err
 		}
 		return loadErr
@@ -45,9 +62,219 @@ func runValidate(w io.Writer, configPath, outputFmt string) error {
 	}
 	fmt.Fprintf(w, "✓ Config valid: %s\n\n", cfgPath)
 	writeValidateText(w, cfg)
+	writeValidateReportText(w, report)
 	return nil
 }
 
+// validateReport is the part of `thane validate` output that goes
+// beyond counts: what the loaded config will actually wire up.
+type validateReport struct {
+	// Integrations names each optional integration whose config is
+	// complete enough for serve to start it.
+	Integrations []string `json:"integrations"`
+
+	// Models lists every configured model deployment and the provider
+	// and resource requests for it are routed to.
+	Models []validateModelRoute `json:"models"`
+
+	// CapabilityTags maps each capability tag to the native tools it
+	// will carry: the compiled-in catalog membership, minus tags whose
+	// integration is not configured, plus include and minus exclude
+	// from capability_tags. MCP tools are discovered at runtime and
+	// only appear here when named in an include.
+	CapabilityTags map[string][]string `json:"capability_tags"`
+
+	// Warnings describe settings that load but are likely mistakes.
+	Warnings []string `json:"warnings"`
+}
+
+// validateModelRoute is one model deployment in a [validateReport].
+type validateModelRoute struct {
+	Model    string `json:"model"`
+	Provider string `json:"provider"`
+	Resource string `json:"resource,omitempty"`
+	Routable bool   `json:"routable"`
+	Default  bool   `json:"default,omitempty"`
+}
+
+// buildValidateReport derives a [validateReport] from a loaded config.
+// It returns an error only when the model catalog cannot be built,
+// since serve refuses to start in that case too.
+func buildValidateReport(cfg *config.Config) (*validateReport, error) {
+	catalog, err := fleet.BuildCatalog(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("build model catalog: %w", err)
+	}
+
+	report := &validateReport{
+		Integrations:   configuredIntegrations(cfg),
+		Models:         []validateModelRoute{},
+		CapabilityTags: map[string][]string{},
+		Warnings:       []string{},
+	}
+	for _, dep := range catalog.Deployments {
+		report.Models = append(report.Models, validateModelRoute{
+			Model:    dep.ModelName,
+			Provider: dep.Provider,
+			Resource: dep.ResourceID,
+			Routable: dep.Routable,
+			Default:  dep.ID == catalog.DefaultModel || dep.ModelName == catalog.DefaultModel,
+		})
+	}
+
+	report.CapabilityTags, report.Warnings = expectedCapabilityTools(cfg)
+	report.Warnings = append(report.Warnings, pathWarnings(cfg)...)
+	if (cfg.MQTT.Broker == "") != (cfg.MQTT.DeviceName == "") {
+		report.Warnings = append(report.Warnings,
+			"mqtt: broker and device_name must both be set; MQTT publishing is disabled")
+	}
+	if cfg.Signal.Enabled && !cfg.Signal.Configured() {
+		report.Warnings = append(report.Warnings,
+			"signal: enabled but command or account is empty; the Signal bridge is disabled")
+	}
+	return report, nil
+}
+
+// configuredIntegrations returns the names of the optional integrations
+// serve will start, in a stable order.
+func configuredIntegrations(cfg *config.Config) []string {
+	checks := []struct {
+		name string
+		on   bool
+	}{
+		{"anthropic", cfg.Anthropic.Configured()},
+		{"homeassistant", cfg.HomeAssistant.Configured()},
+		{"mqtt", cfg.MQTT.Configured()},
+		{"signal", cfg.Signal.Configured()},
+		{"email", cfg.Email.Configured()},
+		{"forge", cfg.Forge.Configured()},
+		{"carddav", cfg.CardDAV.Configured()},
+		{"companion", cfg.Companion.Configured()},
+		{"unifi", cfg.Unifi.Configured()},
+		{"search", cfg.Search.Configured()},
+		{"embeddings", cfg.Embeddings.Enabled},
+		{"shell_exec", cfg.ShellExec.Enabled},
+		{"mcp", len(cfg.MCP.Servers) > 0},
+	}
+	out := []string{}
+	for _, c := range checks {
+		if c.on {
+			out = append(out, c.name)
+		}
+	}
+	return out
+}
+
+// integrationTags maps capability tags whose tools are only registered
+// when a particular integration is configured.
+func integrationTags(cfg *config.Config) map[string]bool {
+	return map[string]bool{
+		"ha":        cfg.HomeAssistant.Configured(),
+		"signal":    cfg.Signal.Configured(),
+		"email":     cfg.Email.Configured(),
+		"forge":     cfg.Forge.Configured(),
+		"companion": cfg.Companion.Configured(),
+		"files":     cfg.Workspace.Path != "",
+		"shell":     cfg.ShellExec.Enabled,
+	}
+}
+
+// expectedCapabilityTools resolves the native tool membership of each
+// capability tag from the compiled-in catalog and the capability_tags
+// overlay, and warns about overlay entries that name tools the catalog
+// does not know. Names with the MCP prefix are trusted, since MCP
+// tools are only discovered once serve connects to the server.
+func expectedCapabilityTools(cfg *config.Config) (map[string][]string, []string) {
+	enabled := integrationTags(cfg)
+	members := make(map[string]map[string]bool)
+	add := func(tag, tool string) {
+		if members[tag] == nil {
+			members[tag] = make(map[string]bool)
+		}
+		members[tag][tool] = true
+	}
+
+	for name, spec := range toolcatalog.BuiltinToolSpecs() {
+		for _, tag := range spec.Tags {
+			if on, gated := enabled[tag]; gated && !on {
+				continue
+			}
+			add(tag, name)
+		}
+	}
+
+	warnings := []string{}
+	known := func(tag, field, tool string) bool {
+		if _, ok := toolcatalog.LookupBuiltinToolSpec(tool); ok || strings.HasPrefix(tool, "mcp_") {
+			return true
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"capability_tags.%s.%s: %q is not a known tool", tag, field, tool))
+		return false
+	}
+	for tag, tagCfg := range cfg.CapabilityTags {
+		if members[tag] == nil {
+			members[tag] = make(map[string]bool)
+		}
+		for _, tool := range tagCfg.Include {
+			if tool = strings.TrimSpace(tool); tool != "" && known(tag, "include", tool) {
+				add(tag, tool)
+			}
+		}
+		for _, tool := range tagCfg.Exclude {
+			if tool = strings.TrimSpace(tool); tool != "" && known(tag, "exclude", tool) {
+				delete(members[tag], tool)
+			}
+		}
+	}
+
+	out := make(map[string][]string, len(members))
+	for tag, set := range members {
+		names := make([]string, 0, len(set))
+		for name := range set {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		out[tag] = names
+	}
+	sort.Strings(warnings)
+	return out, warnings
+}
+
+// pathWarnings reports configured directories that do not exist yet.
+// Serve creates some of them on demand, but a missing workspace or
+// document root is far more often a typo than an intent.
+func pathWarnings(cfg *config.Config) []string {
+	var warnings []string
+	missing := func(key, path string) {
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			warnings = append(warnings, fmt.Sprintf("%s: %s does not exist", key, path))
+		case !info.IsDir():
+			warnings = append(warnings, fmt.Sprintf("%s: %s is not a directory", key, path))
+		}
+	}
+
+	if cfg.Workspace.Path == "" {
+		warnings = append(warnings, "workspace.path: not set; file tools are disabled")
+	} else {
+		missing("workspace.path", cfg.Workspace.Path)
+	}
+	if cfg.TalentsDir != "" {
+		missing("talents_dir", cfg.TalentsDir)
+	}
+	roots := make([]string, 0, len(cfg.Paths))
+	for name := range cfg.Paths {
+		roots = append(roots, name)
+	}
+	sort.Strings(roots)
+	for _, name := range roots {
+		missing("roots."+name, cfg.Paths[name])
+	}
+	return warnings
+}
+
 // writeValidateText prints the per-section structural summary used by
 // the default text output mode. Counts and presence checks are enough
 // to confirm "is the config I edited really the one that loaded?"
@@ -68,9 +295,62 @@ func writeValidateText(w io.Writer, cfg *config.Config) {
 	fmt.Fprintf(w, "  Ego loop:             %v\n", cfg.Ego.Enabled)
 }
 
-// writeValidateJSON emits the structured validation report. cfg may be
-// nil when load failed; loadErr is non-nil when validation failed.
-func writeValidateJSON(w io.Writer, cfgPath string, cfg *config.Config, loadErr error) error {
+// writeValidateReportText prints the integration, model, capability
+// tag, and warning sections of the text output.
+func writeValidateReportText(w io.Writer, report *validateReport) {
+	fmt.Fprintf(w, "\nIntegrations: %s\n", listOrNone(report.Integrations))
+
+	fmt.Fprintln(w, "\nModels:")
+	for _, m := range report.Models {
+		var notes []string
+		if m.Default {
+			notes = append(notes, "default")
+		}
+		if !m.Routable {
+			notes = append(notes, "not routable")
+		}
+		target := m.Provider
+		if m.Resource != "" {
+			target += "/" + m.Resource
+		}
+		line := fmt.Sprintf("  %s → %s", m.Model, target)
+		if len(notes) > 0 {
+			line += " [" + strings.Join(notes, ", ") + "]"
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	fmt.Fprintln(w, "\nCapability tags (native tools):")
+	tags := make([]string, 0, len(report.CapabilityTags))
+	for tag := range report.CapabilityTags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		fmt.Fprintf(w, "  %-20s %d\n", tag, len(report.CapabilityTags[tag]))
+	}
+
+	if len(report.Warnings) == 0 {
+		fmt.Fprintln(w, "\nNo warnings.")
+		return
+	}
+	fmt.Fprintf(w, "\nWarnings (%d):\n", len(report.Warnings))
+	for _, warning := range report.Warnings {
+		fmt.Fprintf(w, "  ⚠ %s\n", warning)
+	}
+}
+
+func listOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
+
+// writeValidateJSON emits the structured validation report. cfg and
+// report may be nil when load failed; loadErr is non-nil when
+// validation failed.
+func writeValidateJSON(w io.Writer, cfgPath string, cfg *config.Config, report *validateReport, loadErr error) error {
 	// Path always emits (no omitempty) so the JSON schema is stable
 	// for scripts piping into jq — even discovery-failure cases get
 	// a path field, possibly empty.
@@ -79,6 +359,7 @@ func writeValidateJSON(w io.Writer, cfgPath string, cfg *config.Config, loadErr
 		Valid   bool           `json:"valid"`
 		Error   string         `json:"error,omitempty"`
 		Summary map[string]any `json:"summary,omitempty"`
+		*validateReport
 	}{
 		Path:  cfgPath,
 		Valid: loadErr == nil,
@@ -100,6 +381,7 @@ func writeValidateJSON(w io.Writer, cfgPath string, cfg *config.Config, loadErr
 			"metacognitive_enabled":    cfg.Metacognitive.Enabled,
 			"ego_enabled":              cfg.Ego.Enabled,
 		}
+		result.validateReport = report
 	}
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -200,3 +201,86 @@ channel_tags:
 		t.Errorf("json error field should name the offending tag, got: %q", got.Error)
 	}
 }
+
+func TestRunValidate_ReportWarnings(t *testing.T) {
+	body := minimalValidConfig + `
+mqtt:
+  broker: tcp://broker.local:1883
+capability_tags:
+  web:
+    include:
+      - not_a_real_tool
+      - mcp_github_search
+`
+	path := writeConfig(t, body)
+	var buf bytes.Buffer
+
+	// Warnings describe likely mistakes; they must not fail validation.
+	if err := runValidate(&buf, path, "text"); err != nil {
+		t.Fatalf("runValidate: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"test-model → ollama",
+		`capability_tags.web.include: "not_a_real_tool" is not a known tool`,
+		"mqtt: broker and device_name must both be set",
+		"workspace.path: not set",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "mcp_github_search") {
+		t.Errorf("MCP tool names should not be flagged as unknown:\n%s", out)
+	}
+}
+
+func TestRunValidate_JSONReport(t *testing.T) {
+	dir := t.TempDir()
+	body := minimalValidConfig + `
+workspace:
+  path: ` + dir + `
+talents_dir: ` + dir + `
+capability_tags:
+  web:
+    exclude:
+      - web_search
+`
+	path := writeConfig(t, body)
+	var buf bytes.Buffer
+
+	if err := runValidate(&buf, path, "json"); err != nil {
+		t.Fatalf("runValidate: %v", err)
+	}
+
+	var got struct {
+		Integrations   []string            `json:"integrations"`
+		CapabilityTags map[string][]string `json:"capability_tags"`
+		Warnings       []string            `json:"warnings"`
+		Models         []struct {
+			Model    string `json:"model"`
+			Provider string `json:"provider"`
+			Default  bool   `json:"default"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json output is not valid JSON: %v\nbody:\n%s", err, buf.String())
+	}
+	if got.Integrations == nil || got.Warnings == nil {
+		t.Errorf("integrations and warnings should be present even when empty:\n%s", buf.String())
+	}
+	if len(got.Warnings) != 0 {
+		t.Errorf("warnings = %v, want none", got.Warnings)
+	}
+	if len(got.Models) != 1 || got.Models[0].Model != "test-model" || got.Models[0].Provider != "ollama" || !got.Models[0].Default {
+		t.Errorf("models = %+v, want test-model on ollama as default", got.Models)
+	}
+	web := got.CapabilityTags["web"]
+	if !slices.Contains(web, "web_fetch") || slices.Contains(web, "web_search") {
+		t.Errorf("web tag tools = %v, want web_fetch without the excluded web_search", web)
+	}
+	if _, ok := got.CapabilityTags["ha"]; ok {
+		t.Error("ha tag should be absent when Home Assistant is not configured")
+	}
+}
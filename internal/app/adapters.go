@@ -33,7 +33,13 @@ import (
 // factSetterFunc adapts knowledge.Store to the memory.FactSetter interface,
 // adding confidence reinforcement: if a fact already exists, its confidence
 // is bumped by 0.1 (capped at 1.0) rather than overwritten. This rewards
-// the model for re-extracting known knowledge.
+// the model for re-extracting known knowledge. Re-setting an ephemeral
+// fact also renews its original lifetime (see [knowledge.Fact.Lifetime]),
+// so knowledge that keeps being observed outlives its first expiry
+// without extraction ever making it permanent.
+// A changed value for a high-confidence fact is not applied; it is
+// recorded as a conflict for the agent to reconcile (see
+// [knowledge.Store.SetWithConflictCheck]).
 type factSetterFunc struct {
 	store  *knowledge.Store
 	logger *slog.Logger
@@ -50,7 +56,9 @@ func (f *factSetterFunc) SetFact(category, key, value, source string, confidence
 			"category", category, "key", key, "error", err)
 		return err
 	}
+	var ttl time.Duration
 	if err == nil && existing != nil {
+		ttl = existing.Lifetime()
 		if existing.Value == value {
 			// Same fact re-observed — reinforce confidence.
 			reinforced := min(existing.Confidence+0.1, 1.0)
@@ -61,9 +69,10 @@ func (f *factSetterFunc) SetFact(category, key, value, source string, confidence
 				"category", category, "key", key,
 				"old_confidence", existing.Confidence,
 				"new_confidence", confidence)
-		} else {
-			// Value changed — this is a correction, not a reinforcement.
-			// Use the incoming confidence as-is.
+		} else if existing.Confidence < knowledge.ConflictConfidence {
+			// Value changed on a low-confidence fact — this is a
+			// correction, not a reinforcement. Use the incoming
+			// confidence as-is.
 			f.logger.Debug("updating fact value (correction)",
 				"category", category, "key", key,
 				"old_value", existing.Value, "new_value", value,
@@ -71,7 +80,13 @@ func (f *factSetterFunc) SetFact(category, key, value, source string, confidence
 		}
 	}
 
-	_, err = f.store.Set(knowledge.Category(category), key, value, source, confidence, nil, "")
+	_, conflict, err := f.store.SetWithConflictCheck(knowledge.Category(category), key, value, source, confidence, nil, "", ttl)
+	if err == nil && conflict != nil {
+		f.logger.Info("held back conflicting fact update for review",
+			"category", category, "key", key,
+			"conflict_id", conflict.ID,
+			"stored_value", conflict.CurrentValue, "observed_value", value)
+	}
 	return err
 }
 
@@ -575,6 +590,16 @@ func resolveContactByChannelLink(store *contacts.Store, channel, address string)
 
 	switch channel {
 	case "signal":
+		// Group chats are addressed as "group:<id>". A contact that
+		// names the group carries an IMPP of "signal:group:<id>"; there
+		// is no phone-number fallback.
+		if strings.HasPrefix(address, sigcli.GroupAddressPrefix) {
+			matches, err := store.FindByPropertyExact("IMPP", "signal:"+address)
+			if err == nil && len(matches) == 1 {
+				return matches[0].ID, "impp", true
+			}
+			return nilID, "", false
+		}
 		// Signal conversation IDs use sanitizePhone which strips the "+"
 		// prefix (e.g., "+15551234567" → "15551234567"), but contact
 		// properties store the canonical form with "+". Try both forms.
@@ -946,6 +971,11 @@ func (a *loopAdapter) Run(ctx context.Context, req looppkg.Request, stream loopp
 					}
 					req.OnProgress(events.KindLoopToolStart, data)
 				}
+			case agent.KindToolCallProgress:
+				req.OnProgress(events.KindLoopToolProgress, map[string]any{
+					"tool":    e.ToolName,
+					"message": e.Progress,
+				})
 			case agent.KindToolCallDone:
 				data := map[string]any{"tool": e.ToolName}
 				if e.ToolError != "" {
@@ -1085,6 +1115,7 @@ func compileLoopAgentRequest(req looppkg.Request) *agent.Request {
 		SystemPrompt:          req.SystemPrompt,
 		PromptMode:            req.PromptMode,
 		SuppressAlwaysContext: req.SuppressAlwaysContext,
+		ClientStream:          req.ClientStream,
 	}
 }
 
@@ -8,6 +8,7 @@
 package app
 
 import (
+	"context"
 	"database/sql"
 	"io"
 	"log/slog"
@@ -24,15 +25,20 @@ import (
 	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
 	"github.com/nugget/thane-ai-agent/internal/integrations/mcp"
 	"github.com/nugget/thane-ai-agent/internal/integrations/media"
+	"github.com/nugget/thane-ai-agent/internal/integrations/search"
 	"github.com/nugget/thane-ai-agent/internal/integrations/unifi"
 	"github.com/nugget/thane-ai-agent/internal/model/fleet"
 	modelproviders "github.com/nugget/thane-ai-agent/internal/model/fleet/providers"
 	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/model/prompts"
 	"github.com/nugget/thane-ai-agent/internal/model/router"
+	"github.com/nugget/thane-ai-agent/internal/model/talents"
 	"github.com/nugget/thane-ai-agent/internal/model/toolcatalog"
+	"github.com/nugget/thane-ai-agent/internal/platform/audit"
 	"github.com/nugget/thane-ai-agent/internal/platform/checkout"
 	"github.com/nugget/thane-ai-agent/internal/platform/checkpoint"
 	"github.com/nugget/thane-ai-agent/internal/platform/config"
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
 	"github.com/nugget/thane-ai-agent/internal/platform/events"
 	"github.com/nugget/thane-ai-agent/internal/platform/logging"
 	"github.com/nugget/thane-ai-agent/internal/platform/opstate"
@@ -62,13 +68,29 @@ import (
 // (file handler, index handler, level, format) for subsequent log lines.
 func (a *App) Logger() *slog.Logger { return a.logger }
 
+// Loop returns the agent loop built by [New]. Front ends that run in
+// place of [App.Serve], such as the terminal chat, drive it directly.
+func (a *App) Loop() *agent.Loop { return a.loop }
+
 // capSurfaceGetter returns a closure that reads the current capability
 // surface at call time. Adapters use this instead of capturing the
 // slice at construction time because the surface is finalized in a
 // late init phase ([finalizeCapabilityTags]) that runs after most
 // adapters have already been wired.
 func (a *App) capSurfaceGetter() func() []toolcatalog.CapabilitySurface {
-	return func() []toolcatalog.CapabilitySurface { return a.capSurface }
+	return func() []toolcatalog.CapabilitySurface {
+		a.capSurfaceMu.RLock()
+		defer a.capSurfaceMu.RUnlock()
+		return a.capSurface
+	}
+}
+
+// setCapSurface replaces the shared capability surface. It is set once
+// at startup and again when a config reload changes tags or talents.
+func (a *App) setCapSurface(surface []toolcatalog.CapabilitySurface) {
+	a.capSurfaceMu.Lock()
+	defer a.capSurfaceMu.Unlock()
+	a.capSurface = surface
 }
 
 // App holds all long-lived application state for the Thane server. It is
@@ -89,9 +111,11 @@ type App struct {
 
 	// Core subsystems
 	mem                       *memory.SQLiteStore
+	memStatus                 memoryDBStatus // how thane.db came up, for /health
 	archiveStore              *memory.ArchiveStore
 	archiveAdapter            *memory.ArchiveAdapter
 	wmStore                   *memory.WorkingMemoryStore
+	convEgoStore              *memory.ConversationEgoStore
 	factStore                 *knowledge.Store
 	documentStore             *documents.Store
 	documentTools             *documents.Tools
@@ -107,28 +131,59 @@ type App struct {
 	loopDefinitionStore       *loopDefinitionStore
 	loopDefinitionPolicyStore *loopDefinitionPolicyStore
 	usageStore                *usage.Store
+	toolAudit                 *audit.Store
 	schedStore                *scheduler.Store
 	sched                     *scheduler.Scheduler
+	// maintenanceJobs are the housekeeping jobs behind
+	// [scheduler.PayloadMaintenance] tasks, keyed by task target.
+	// Registered during init, before the scheduler starts.
+	maintenanceJobs map[string]func(context.Context) error
 
 	// Agent loop and router
 	loop *agent.Loop
 	rtr  *router.Router
-	// Shared capability surface used by prompt renderers and dashboard views.
-	capSurface []toolcatalog.CapabilitySurface
+	// Shared capability surface used by prompt renderers and dashboard
+	// views. Guarded by capSurfaceMu; read through capSurfaceGetter.
+	capSurfaceMu sync.RWMutex
+	capSurface   []toolcatalog.CapabilitySurface
+	// Tag context assembler built by finalizeCapabilityTags; nil when
+	// no capability tags are configured.
+	tagCtxAssembler *agent.TagContextAssembler
+	// Talents as loaded from talents_dir, before the generated manifest
+	// talent is prepended. Replaced by Reload.
+	talents []talents.Talent
 
 	// Compaction and summarization
 	compactor     *memory.Compactor
 	summaryWorker *memory.SummarizerWorker
+	// Prompt templates for fact extraction and session metadata, with
+	// any overrides from prompts.override_dir applied.
+	prompts *prompts.Registry
 
 	// External service clients
 	ha   *homeassistant.Client
 	haWS *homeassistant.WSClient
+	// Secondary Home Assistant instances (homeassistant.instances);
+	// haInstances is nil when only the primary is configured.
+	haInstances *homeassistant.Instances
+	haRemote    []haRemoteInstance
+
+	// Web search and fetch; nil searchMgr when no provider is configured.
+	searchMgr  *search.Manager
+	webFetcher *search.Fetcher
 
 	// Companion app registry
 	companionRegistry *companion.Registry
 
+	// walCheckpointer truncates the WAL of every file-backed SQLite
+	// database periodically and reports WAL sizes for /health.
+	walCheckpointer *database.WALCheckpointer
+
 	// Connection health
 	connMgr *connwatch.Manager
+	// resourceWatchers maps model resource IDs to their connection
+	// watchers. Written once during init, read-only afterwards.
+	resourceWatchers map[string]*connwatch.Watcher
 
 	// Delegated execution
 	delegateExec *delegate.Executor
@@ -158,6 +213,8 @@ type App struct {
 	mcpClients []*mcp.Client
 
 	// Logging infrastructure
+	logLevel            *slog.LevelVar // dataset level; adjusted by Reload
+	stdoutLogLevel      *slog.LevelVar // stdout level; adjusted by Reload
 	indexDB             *sql.DB
 	indexHandler        *logging.IndexHandler
 	datasetWriter       *logging.DatasetWriter
@@ -211,6 +268,10 @@ type App struct {
 	// Metacognitive config (stored for loop-definition hydration)
 	metacogCfg *metacognitive.Config
 
+	// Metacognitive iteration journal, filled by the hydrated loop's
+	// PostIterate hook and read by the API and metacognitive_history.
+	metacogJournal *metacognitive.Journal
+
 	// Ego loop config (stored for loop-definition hydration)
 	egoCfg *ego.Config
 
@@ -239,6 +300,11 @@ type App struct {
 	// stops (loopRegistry, connMgr).
 	closers []closer
 
+	// reloadMu serializes Reload. reloadBase is the config currently in
+	// effect: a.cfg plus whatever reloads have applied since startup.
+	reloadMu   sync.Mutex
+	reloadBase *config.Config
+
 	// closeOnce ensures shutdown runs exactly once across Close and Serve.
 	closeOnce sync.Once
 }
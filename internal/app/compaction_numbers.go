@@ -0,0 +1,47 @@
+package app
+
+import (
+	"math"
+
+	"github.com/nugget/thane-ai-agent/internal/channels/mqtt"
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
+)
+
+// Bounds for the HA number entities that tune compaction at runtime.
+// The ratio floor keeps compaction from firing on nearly every turn;
+// the ceiling leaves headroom for the summarizer before the budget is
+// exhausted. KeepRecent stays well under the compaction minimum so
+// there is always something to fold.
+const (
+	compactionTriggerRatioMin  = 0.3
+	compactionTriggerRatioMax  = 0.95
+	compactionTriggerRatioStep = 0.05
+	compactionKeepRecentMin    = 2
+	compactionKeepRecentMax    = 50
+)
+
+// registerCompactionNumbers exposes the compactor's TriggerRatio and
+// KeepRecent as HA number entities. Values set in HA apply to the live
+// compactor without a restart; they are not persisted, so a restart
+// returns to the built-in defaults and republishes them.
+func registerCompactionNumbers(pub *mqtt.Publisher, compactor *memory.Compactor) {
+	current := compactor.Config()
+
+	ratio := pub.RegisterNumber("Compaction Trigger Ratio",
+		compactionTriggerRatioMin, compactionTriggerRatioMax, compactionTriggerRatioStep,
+		func(v float64) {
+			cfg := compactor.Config()
+			cfg.TriggerRatio = v
+			compactor.SetConfig(cfg)
+		})
+	ratio.SetValue(current.TriggerRatio)
+
+	keep := pub.RegisterNumber("Compaction Keep Recent",
+		compactionKeepRecentMin, compactionKeepRecentMax, 1,
+		func(v float64) {
+			cfg := compactor.Config()
+			cfg.KeepRecent = int(math.Round(v))
+			compactor.SetConfig(cfg)
+		})
+	keep.SetValue(float64(current.KeepRecent))
+}
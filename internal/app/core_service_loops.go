@@ -81,6 +81,9 @@ var metacognitiveRegistration = coreServiceRegistration{
 			return err
 		}
 		a.metacogCfg = &cfg
+		if a.metacogJournal == nil {
+			a.metacogJournal = metacognitive.NewJournal(metacognitive.DefaultJournalSize)
+		}
 		return nil
 	},
 	DefinitionSpec: func(a *App) looppkg.Spec {
@@ -95,6 +98,7 @@ var metacognitiveRegistration = coreServiceRegistration{
 		return metacognitive.HydrateSpec(spec, *a.metacogCfg, metacognitive.Opts{
 			StateFilePath: stateFilePath,
 			StateFileName: stateFileName,
+			Journal:       a.metacogJournal,
 		}), nil
 	},
 }
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"slices"
+
+	"github.com/nugget/thane-ai-agent/internal/channels/mqtt"
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+	"github.com/nugget/thane-ai-agent/internal/runtime/agent"
+)
+
+// registerDefaultModelSelect exposes the loop's default model as an HA
+// select entity whose options are the configured models. A choice in
+// HA applies to the live loop without a restart; it is not persisted,
+// so a restart returns to models.default and republishes it. Only
+// options from models.available reach the loop — the select drops
+// anything else with a warning.
+func registerDefaultModelSelect(pub *mqtt.Publisher, loop *agent.Loop, models config.ModelsConfig) {
+	var options []string
+	for _, m := range models.Available {
+		if m.Name != "" && !slices.Contains(options, m.Name) {
+			options = append(options, m.Name)
+		}
+	}
+	if len(options) == 0 {
+		return
+	}
+
+	sel := pub.RegisterSelect("Default Model", options, loop.SetDefaultModel)
+	if current := loop.DefaultModel(); slices.Contains(options, current) {
+		sel.SetValue(current)
+	}
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/nugget/thane-ai-agent/internal/state/knowledge"
+)
+
+// embeddingIndexProbe is the text embedded at startup to learn the
+// configured model's vector dimension.
+const embeddingIndexProbe = "embedding dimension probe"
+
+// checkEmbeddingIndexes compares the vectors in each store against the
+// configured embedding model. Switching embedding models silently
+// invalidates every stored vector — similarity between vectors from
+// different models is noise — so stale vectors are reported loudly
+// with the command that re-embeds them. Search already ignores them.
+func checkEmbeddingIndexes(ctx context.Context, client *knowledge.Client, indexes map[string]knowledge.EmbeddingIndex, logger *slog.Logger) {
+	if _, err := client.Generate(ctx, embeddingIndexProbe); err != nil {
+		logger.Warn("embedding model unreachable; skipping embedding index check",
+			"model", client.Model(), "error", err)
+		return
+	}
+	dim := client.Dimension()
+
+	for name, idx := range indexes {
+		status, err := knowledge.CheckEmbeddingIndex(idx, client.Model(), dim)
+		if err != nil {
+			logger.Warn("embedding index check failed", "store", name, "error", err)
+			continue
+		}
+		if status.Adopted > 0 {
+			logger.Info("tagged legacy embeddings with current model",
+				"store", name, "model", status.Model, "dimension", dim, "count", status.Adopted)
+		}
+		if status.Stale == 0 {
+			continue
+		}
+		for _, m := range status.StaleModels {
+			model := m.Model
+			if model == "" {
+				model = "unknown"
+			}
+			logger.Error("EMBEDDING MODEL CHANGED: stored vectors do not match the configured model and are excluded from semantic search; run `thane embeddings backfill` to re-embed them",
+				"store", name,
+				"configured_model", status.Model, "configured_dimension", dim,
+				"stored_model", model, "stored_dimension", m.Dimension,
+				"count", m.Count)
+		}
+	}
+}
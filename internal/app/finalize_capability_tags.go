@@ -38,67 +38,21 @@ func (a *App) finalizeCapabilityTags(s *newState) error {
 	logger := a.logger
 
 	resolved := resolveCapabilityTags(a.loop.Tools(), cfg.CapabilityTags)
-	resolvedCapTags := resolved.Configs
-	if len(resolvedCapTags) == 0 {
+	if len(resolved.Configs) == 0 {
 		return nil
 	}
 
-	delegateExec := a.delegateExec
-
-	// parsedTalents was loaded earlier in startup; copy the slice
-	// header so the manifest prepend below doesn't modify the outer
-	// variable.
-	capTalents := append([]talents.Talent(nil), s.parsedTalents...)
-
-	// Core tags on the delegate executor. Moved here (from
-	// initDelegation) so the tag set is taken from the finalized
-	// snapshot, not the mid-init snapshot that preceded initServers.
-	var coreTags []string
-	for tag, tagCfg := range resolvedCapTags {
-		if tagCfg.Core {
-			coreTags = append(coreTags, tag)
-		}
-	}
-	if len(coreTags) > 0 && delegateExec != nil {
-		delegateExec.SetCoreTags(coreTags)
-	}
-
-	// Warn about tools referenced in config but not registered.
-	// This catches typos, missing MCP servers, and tools gated by
-	// config (e.g., shell_exec disabled). Non-fatal: skip the missing
-	// tool.
-	//
-	// Every tool a config tag can reference is registered by an init
-	// phase before this finalizer runs: synchronously (e.g.
-	// macos_calendar_events and mqtt_wake_* in initServers) or declared
-	// up front via tools.Provider (Signal, watchlist). Provider tools
-	// whose runtime has not bound yet are still present here; only
-	// invocation surfaces tools.ErrUnavailable until Bind supplies the
-	// runtime.
-	for tag, tagCfg := range resolvedCapTags {
-		for _, toolName := range tagCfg.Tools {
-			if a.loop.Tools().Get(toolName) == nil {
-				logger.Warn("capability tag references unregistered tool",
-					"tag", tag, "tool", toolName)
-			}
-		}
-	}
-
-	// The complementary check: a native tool registered but missing from the
-	// tool catalog carries no capability tag and is silently never offered to
-	// the model. This runs against the fully-assembled registry, so it catches
-	// the gap for every tool from every package (see uncataloguedNativeTools).
+	// The complementary check to the unregistered-tool warning in
+	// applyCapabilityTags: a native tool registered but missing from the
+	// tool catalog carries no capability tag and is silently never
+	// offered to the model. This runs against the fully-assembled
+	// registry, so it catches the gap for every tool from every package
+	// (see uncataloguedNativeTools).
 	if missing := uncataloguedNativeTools(a.loop.Tools()); len(missing) > 0 {
 		logger.Error("native tools registered but absent from the tool catalog; they carry no capability tag and will NOT be offered to the model — add them to internal/model/toolcatalog/catalog.go with the right tags",
 			"tools", missing, "count", len(missing))
 	}
 
-	// Audit operator-excluded tools that downstream wiring expects to
-	// be available. Personas/talents and the orchestrator allowlist
-	// are the most common silent-breakage paths when an exclude turns
-	// off a tool another subsystem assumed was present.
-	auditExcludedToolReferences(logger, resolved, cfg.Agent.OrchestratorTools, s.parsedTalents)
-
 	// Build the shared tag context assembler. KB article counts feed
 	// the manifest; live providers (registered during initAwareness
 	// and here for forge) feed the liveTags map.
@@ -118,7 +72,7 @@ func (a *App) finalizeCapabilityTags(s *newState) error {
 	}
 
 	tagCtxAssembler := agent.NewTagContextAssembler(agent.TagContextAssemblerConfig{
-		CapTags:  resolvedCapTags,
+		CapTags:  resolved.Configs,
 		KBDir:    kbDir,
 		Resolver: s.resolver,
 		Verifier: contextVerifier,
@@ -131,6 +85,7 @@ func (a *App) finalizeCapabilityTags(s *newState) error {
 	// staged during initAwareness) flush directly into the assembler
 	// instead of staying in the pending bucket.
 	a.loop.SetTagContextAssembler(tagCtxAssembler)
+	a.tagCtxAssembler = tagCtxAssembler
 
 	// Register forge as a tag context provider so its account config
 	// and recent operations appear/disappear with the forge capability
@@ -139,6 +94,88 @@ func (a *App) finalizeCapabilityTags(s *newState) error {
 		a.loop.RegisterTagContextProvider("forge", forge.NewContextProvider(a.forgeMgr, s.forgeOpLog))
 	}
 
+	a.loop.ConfigureCapabilityWiring(agent.CapabilityWiring{
+		Store:            agent.NewOpstateCapabilityTagStore(a.opStore),
+		ContextAssembler: tagCtxAssembler,
+	})
+	// Conversation tool policies narrow the tool surface for matching
+	// conversations regardless of active tags. They live in opstate and
+	// are read per run, so edits take effect on the next turn.
+	a.loop.SetToolPolicyStore(agent.NewOpstateToolPolicyStore(a.opStore))
+	kbCounts := a.applyCapabilityTags(resolved, s.parsedTalents)
+
+	var activeTagNames []string
+	for tag := range a.loop.LastRunTags() {
+		activeTagNames = append(activeTagNames, tag)
+	}
+	logger.Info("capability tags enabled",
+		"tags", len(resolved.Configs),
+		"core_tags", activeTagNames,
+		"talents", len(s.parsedTalents),
+		"kb_tagged_articles", kbCounts,
+	)
+
+	return nil
+}
+
+// applyCapabilityTags wires resolved capability tags and talents into
+// the loop, delegate executor, and capability tools, rebuilding the
+// capability surface and manifest talent from them. It needs the tag
+// context assembler built by [App.finalizeCapabilityTags], and runs
+// again from [App.Reload] when tag definitions or talent content
+// change. It returns the KB article counts per tag.
+func (a *App) applyCapabilityTags(resolved resolvedCapabilityTags, parsedTalents []talents.Talent) map[string]int {
+	cfg := a.cfg
+	logger := a.logger
+	resolvedCapTags := resolved.Configs
+	tagCtxAssembler := a.tagCtxAssembler
+
+	// Copy the slice header so the manifest prepend below doesn't
+	// modify the caller's slice.
+	capTalents := append([]talents.Talent(nil), parsedTalents...)
+
+	// Core tags on the delegate executor. Set here (rather than in
+	// initDelegation) so the tag set is taken from the finalized
+	// snapshot, not the mid-init snapshot that preceded initServers.
+	var coreTags []string
+	for tag, tagCfg := range resolvedCapTags {
+		if tagCfg.Core {
+			coreTags = append(coreTags, tag)
+		}
+	}
+	// Set even when empty so a reload that drops every core tag
+	// stops pinning the old ones.
+	if a.delegateExec != nil {
+		a.delegateExec.SetCoreTags(coreTags)
+	}
+
+	// Warn about tools referenced in config but not registered.
+	// This catches typos, missing MCP servers, and tools gated by
+	// config (e.g., shell_exec disabled). Non-fatal: skip the missing
+	// tool.
+	//
+	// Every tool a config tag can reference is registered by an init
+	// phase before this runs: synchronously (e.g.
+	// macos_calendar_events and mqtt_wake_* in initServers) or declared
+	// up front via tools.Provider (Signal, watchlist). Provider tools
+	// whose runtime has not bound yet are still present here; only
+	// invocation surfaces tools.ErrUnavailable until Bind supplies the
+	// runtime.
+	for tag, tagCfg := range resolvedCapTags {
+		for _, toolName := range tagCfg.Tools {
+			if a.loop.Tools().Get(toolName) == nil {
+				logger.Warn("capability tag references unregistered tool",
+					"tag", tag, "tool", toolName)
+			}
+		}
+	}
+
+	// Audit operator-excluded tools that downstream wiring expects to
+	// be available. Personas/talents and the orchestrator allowlist
+	// are the most common silent-breakage paths when an exclude turns
+	// off a tool another subsystem assumed was present.
+	auditExcludedToolReferences(logger, resolved, cfg.Agent.OrchestratorTools, parsedTalents)
+
 	// Build manifest entries with enriched context info.
 	kbCounts := tagCtxAssembler.KBArticleTags()
 	menuHints := mergeTalentMenuHints(tagCtxAssembler.KBMenuHints(), capTalents)
@@ -171,31 +208,18 @@ func (a *App) finalizeCapabilityTags(s *newState) error {
 	}
 
 	capSurface := buildCapabilitySurface(resolved, kbCounts, menuHints, liveTags, adHocTags)
-	a.capSurface = capSurface
+	a.setCapSurface(capSurface)
 
 	if manifestTalent := talents.GenerateManifest(capSurface); manifestTalent != nil {
 		capTalents = append([]talents.Talent{*manifestTalent}, capTalents...)
 	}
 
 	a.loop.ConfigureCapabilityWiring(agent.CapabilityWiring{
-		Tags:             resolvedCapTags,
-		ParsedTalents:    capTalents,
-		Surface:          capSurface,
-		Store:            agent.NewOpstateCapabilityTagStore(a.opStore),
-		ContextAssembler: tagCtxAssembler,
+		Tags:          resolvedCapTags,
+		ParsedTalents: capTalents,
+		Surface:       capSurface,
 	})
 	a.loop.Tools().SetCapabilityTools(a.loop, capSurface)
 
-	var activeTagNames []string
-	for tag := range a.loop.LastRunTags() {
-		activeTagNames = append(activeTagNames, tag)
-	}
-	logger.Info("capability tags enabled",
-		"tags", len(resolvedCapTags),
-		"core_tags", activeTagNames,
-		"talents", len(s.parsedTalents),
-		"kb_tagged_articles", kbCounts,
-	)
-
-	return nil
+	return kbCounts
 }
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/connwatch"
+	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
+	"github.com/nugget/thane-ai-agent/internal/state/contacts"
+)
+
+// haRemoteInstance is one secondary Home Assistant instance in a
+// federated household. The primary instance lives in a.ha / a.haWS.
+type haRemoteInstance struct {
+	name   string
+	client *homeassistant.Client
+	ws     *homeassistant.WSClient
+}
+
+// initHAInstances connects every configured secondary Home Assistant
+// instance and builds the instance set that resolves qualified entity
+// IDs. It must run after the primary client is created; with no
+// secondary instances it leaves a.haInstances nil.
+func (a *App) initHAInstances(ctx context.Context) error {
+	cfg := a.cfg
+	if a.ha == nil || len(cfg.HomeAssistant.Instances) == 0 {
+		return nil
+	}
+
+	instances := homeassistant.NewInstances(cfg.HomeAssistant.Name, a.ha)
+	names := make([]string, 0, len(cfg.HomeAssistant.Instances))
+	for name := range cfg.HomeAssistant.Instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		instCfg := cfg.HomeAssistant.Instances[name]
+		instLogger := a.logger.With("ha_instance", name)
+
+		client := homeassistant.NewClient(instCfg.URL, instCfg.Token, instLogger)
+		client.UseFloorMetadataAlias(instCfg.FloorAlias)
+		ws := homeassistant.NewWSClient(instCfg.URL, instCfg.Token, instLogger)
+		client.UseWSClient(ws)
+		a.onCloseErr("ha-websocket-"+name, ws.Close)
+
+		ws.Start(ctx)
+		if err := ws.Subscribe(ctx, "state_changed"); err != nil {
+			instLogger.Warn("failed to record HA state_changed subscription intent", "error", err)
+		}
+		if err := ws.SubscribeRegistryUpdates(ctx); err != nil {
+			instLogger.Warn("failed to record HA registry update subscription intent", "error", err)
+		}
+		if err := instances.Add(name, client); err != nil {
+			return fmt.Errorf("home assistant instance %s: %w", name, err)
+		}
+		a.haRemote = append(a.haRemote, haRemoteInstance{name: name, client: client, ws: ws})
+		instLogger.Debug("Home Assistant instance configured", "url", instCfg.URL)
+	}
+
+	a.haInstances = instances
+	a.logger.Info("federated Home Assistant instances configured",
+		"primary", instances.Primary(),
+		"instances", instances.Names(),
+	)
+	return nil
+}
+
+// haPresenceSource returns the state getter the person tracker
+// initializes from: the instance set when secondary instances are
+// configured (so qualified person IDs resolve), otherwise the primary
+// client.
+func (a *App) haPresenceSource() contacts.StateGetter {
+	if a.haInstances != nil {
+		return a.haInstances
+	}
+	return a.ha
+}
+
+// watchHAInstances registers a connwatch health watcher per secondary
+// instance. Like the primary watcher, OnReady nudges the instance's
+// WebSocket supervisor and refreshes person presence.
+func (a *App) watchHAInstances(s *newState, connMgr *connwatch.Manager) {
+	for _, inst := range a.haRemote {
+		inst := inst
+		instLogger := a.logger.With("ha_instance", inst.name)
+		watcher := connMgr.Watch(s.ctx, connwatch.WatcherConfig{
+			Name:    "homeassistant:" + inst.name,
+			Probe:   func(pCtx context.Context) error { return inst.client.Ping(pCtx) },
+			Backoff: connwatch.DefaultBackoffConfig(),
+			OnReady: func() {
+				instLogger.Info("connected to Home Assistant instance")
+				inst.ws.NotifyReachable()
+				if s.personTracker != nil {
+					initCtx, initCancel := context.WithTimeout(context.Background(), 10*time.Second)
+					defer initCancel()
+					if err := s.personTracker.Initialize(initCtx, a.haPresenceSource()); err != nil {
+						instLogger.Warn("person tracker initialization incomplete", "error", err)
+					}
+				}
+			},
+			Logger: instLogger,
+		})
+		inst.client.SetWatcher(watcher)
+	}
+}
+
+// startHAInstanceWatchers starts one state watcher per secondary
+// instance. Each qualifies its entity IDs with the instance name and
+// feeds the same filter, rate limiter, and handler chain as the
+// primary watcher, so the state window, person tracker, and wake
+// feeder aggregate across instances. The returned watchers share the
+// primary's ingest filter rebuilds.
+func (a *App) startHAInstanceWatchers(ctx context.Context, filter *homeassistant.EntityFilter, limiter *homeassistant.EntityRateLimiter, handler homeassistant.StateWatchHandler, logger *slog.Logger) []*homeassistant.StateWatcher {
+	watchers := make([]*homeassistant.StateWatcher, 0, len(a.haRemote))
+	for _, inst := range a.haRemote {
+		watcher := homeassistant.NewStateWatcher(inst.ws.Events(), filter, limiter, handler, logger.With("ha_instance", inst.name))
+		watcher.SetInstance(inst.name)
+		go watcher.Run(ctx)
+		watchers = append(watchers, watcher)
+	}
+	return watchers
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/nugget/thane-ai-agent/internal/channels/email"
 	mqtt "github.com/nugget/thane-ai-agent/internal/channels/mqtt"
 	"github.com/nugget/thane-ai-agent/internal/integrations/media"
+	"github.com/nugget/thane-ai-agent/internal/integrations/unifi"
 	"github.com/nugget/thane-ai-agent/internal/model/router"
 	"github.com/nugget/thane-ai-agent/internal/platform/config"
 	looppkg "github.com/nugget/thane-ai-agent/internal/runtime/loop"
@@ -115,6 +116,28 @@ func builtInServiceDefinitionSpecs(cfg *config.Config) []looppkg.Spec {
 				"category":  "poller",
 			},
 		})
+
+		if cfg.Unifi.SecurityWake {
+			// Landing zone for unrecognized-device wakes from the
+			// poller. Event-driven so it sits idle until a newcomer
+			// joins the network.
+			specs = append(specs, looppkg.Spec{
+				Name:       unifi.DefaultSecurityLoopName,
+				Enabled:    true,
+				ParentName: pollersContainerName,
+				Task:       "Assess unrecognized devices that joined the network: judge from name, manufacturer, and access point whether each looks benign (a guest's phone, a new appliance) or warrants attention, and notify the owner about anything suspicious.",
+				Operation:  looppkg.OperationEventDriven,
+				Completion: looppkg.CompletionNone,
+				Profile: router.LoopProfile{
+					Mission:    "security_triage",
+					ExtraHints: map[string]string{"source": "unifi"},
+				},
+				Metadata: map[string]string{
+					"subsystem": "unifi",
+					"category":  "default_handler",
+				},
+			})
+		}
 	}
 
 	if cfg.HomeAssistant.Configured() {
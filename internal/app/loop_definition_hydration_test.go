@@ -285,7 +285,7 @@ func TestBuildLoopDefinitionBaseSpecs_GroupingContainers(t *testing.T) {
 		Telemetry:          config.TelemetryConfig{Enabled: true, Interval: 60},
 	}
 	// Enable the pollers members so the pollers container and its members appear.
-	cfg.Unifi = config.UnifiConfig{URL: "https://unifi.local", APIKey: "key", PollIntervalSec: 30}
+	cfg.Unifi = config.UnifiConfig{URL: "https://unifi.local", APIKey: "key", PollIntervalSec: 30, SecurityWake: true}
 	cfg.Person = config.PersonConfig{Track: []string{"person.dan"}}
 	cfg.Email = emailcfg.Config{
 		PollIntervalSec: 300,
@@ -341,9 +341,9 @@ func TestBuildLoopDefinitionBaseSpecs_GroupingContainers(t *testing.T) {
 
 	assertNested(cognitionContainerName, ego.DefinitionName, metacognitive.DefinitionName, archivist.DefinitionName)
 	assertNested(homeAssistantContainerName, haStateWatcherDefinitionName, mqttPublisherDefinitionName, telemetryDefinitionName, mqtt.DefaultHandlerLoopName)
-	// The four pollers and the two triage handlers that used to hang off core.
+	// The four pollers and their triage handlers.
 	assertNested(pollersContainerName,
-		unifiPollerDefinitionName, emailPollerDefinitionName, emailcfg.DefaultHandlerLoopName,
+		unifiPollerDefinitionName, unifi.DefaultSecurityLoopName, emailPollerDefinitionName, emailcfg.DefaultHandlerLoopName,
 		forgeSubPollerDefinitionName, mediaFeedPollerDefinitionName, media.DefaultHandlerLoopName)
 }
 
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/nugget/thane-ai-agent/internal/platform/audit"
 	"github.com/nugget/thane-ai-agent/internal/platform/events"
 	"github.com/nugget/thane-ai-agent/internal/platform/usage"
 	looppkg "github.com/nugget/thane-ai-agent/internal/runtime/loop"
@@ -46,5 +47,11 @@ func (a *App) initLoopUsageStores(db *sql.DB, logger *slog.Logger) error {
 		return fmt.Errorf("initialize usage store: %w", err)
 	}
 	a.usageStore = usageStore
+
+	auditStore, err := audit.NewStore(db, logger, a.cfg.Audit.RedactTools)
+	if err != nil {
+		return fmt.Errorf("initialize tool audit store: %w", err)
+	}
+	a.toolAudit = auditStore
 	return nil
 }
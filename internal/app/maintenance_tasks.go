@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/scheduler"
+)
+
+// factExpirySweepTaskName names the recurring scheduler task that
+// retires expired knowledge facts.
+const factExpirySweepTaskName = "fact_expiry_sweep"
+
+// archivePruneTaskName names the recurring scheduler task that applies
+// the archive retention policy.
+const archivePruneTaskName = "archive_prune"
+
+// registerMaintenanceTask installs job as the housekeeping job behind a
+// [scheduler.PayloadMaintenance] task called name, and keeps that
+// persisted task in line with every: created when missing and
+// rescheduled when the interval changes. Running through the scheduler
+// gives the job execution history and missed-run catch-up like any
+// other task. Best-effort: scheduler errors are logged so they do not
+// block startup.
+func (a *App) registerMaintenanceTask(name string, every time.Duration, job func(context.Context) error, logger *slog.Logger) {
+	if a.maintenanceJobs == nil {
+		a.maintenanceJobs = make(map[string]func(context.Context) error)
+	}
+	a.maintenanceJobs[name] = job
+
+	if a.schedStore == nil || a.sched == nil {
+		return
+	}
+	existing, err := a.schedStore.GetTaskByName(name)
+	if err != nil {
+		logger.Warn("maintenance task lookup failed", "task", name, "error", err)
+		return
+	}
+
+	schedule := scheduler.Schedule{
+		Kind:  scheduler.ScheduleEvery,
+		Every: &scheduler.Duration{Duration: every},
+	}
+	payload := scheduler.Payload{Kind: scheduler.PayloadMaintenance, Target: name}
+	if existing == nil {
+		task := &scheduler.Task{
+			Name:      name,
+			Schedule:  schedule,
+			Payload:   payload,
+			Enabled:   true,
+			CreatedBy: "system",
+		}
+		if err := a.sched.CreateTask(task); err != nil {
+			logger.Warn("failed to create maintenance task", "task", name, "error", err)
+			return
+		}
+		logger.Info("maintenance task created", "task", name, "interval", every)
+		return
+	}
+
+	if existing.Enabled && existing.Payload.Kind == payload.Kind && existing.Payload.Target == name &&
+		existing.Schedule.Kind == scheduler.ScheduleEvery &&
+		existing.Schedule.Every != nil && existing.Schedule.Every.Duration == every {
+		return
+	}
+	existing.Schedule = schedule
+	existing.Payload = payload
+	existing.Enabled = true
+	if err := a.sched.UpdateTask(existing); err != nil {
+		logger.Warn("failed to update maintenance task", "task", name, "id", existing.ID, "error", err)
+		return
+	}
+	logger.Info("maintenance task rescheduled", "task", name, "interval", every)
+}
+
+// unregisterMaintenanceTask deletes the persisted task called name, if
+// any, for a housekeeping job that configuration has turned off.
+// Best-effort like [App.registerMaintenanceTask].
+func (a *App) unregisterMaintenanceTask(name string, logger *slog.Logger) {
+	if a.schedStore == nil || a.sched == nil {
+		return
+	}
+	existing, err := a.schedStore.GetTaskByName(name)
+	if err != nil {
+		logger.Warn("maintenance task lookup failed", "task", name, "error", err)
+		return
+	}
+	if existing == nil {
+		return
+	}
+	if err := a.sched.DeleteTask(existing.ID); err != nil {
+		logger.Warn("failed to delete maintenance task", "task", name, "id", existing.ID, "error", err)
+		return
+	}
+	logger.Info("maintenance task removed", "task", name)
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/platform/scheduler"
+)
+
+func TestMaintenanceTask_RegisterAndUnregister(t *testing.T) {
+	db, err := database.OpenMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := scheduler.NewStore(db, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sched := scheduler.New(slog.Default(), store, func(context.Context, *scheduler.Task, *scheduler.Execution) error { return nil })
+	t.Cleanup(sched.Stop)
+	a := &App{schedStore: store, sched: sched}
+
+	a.registerMaintenanceTask(archivePruneTaskName, archivePruneInterval, func(context.Context) error { return nil }, slog.Default())
+	task, err := store.GetTaskByName(archivePruneTaskName)
+	if err != nil || task == nil {
+		t.Fatalf("task = %v, %v; want it created", task, err)
+	}
+	if task.Payload.Kind != scheduler.PayloadMaintenance || task.Payload.Target != archivePruneTaskName ||
+		task.Schedule.Every == nil || task.Schedule.Every.Duration != 24*time.Hour {
+		t.Errorf("task = %+v, want a daily maintenance task targeting %s", task, archivePruneTaskName)
+	}
+	if _, ok := a.maintenanceJobs[archivePruneTaskName]; !ok {
+		t.Error("job not registered for the task's target")
+	}
+
+	a.unregisterMaintenanceTask(archivePruneTaskName, slog.Default())
+	if task, err := store.GetTaskByName(archivePruneTaskName); err != nil || task != nil {
+		t.Errorf("task after unregister = %v, %v; want it deleted", task, err)
+	}
+}
@@ -0,0 +1,183 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/server/api"
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
+)
+
+// memoryDependency is the /health dependency key that reports the
+// memory store's own database trouble.
+const memoryDependency = "memory"
+
+// memoryDBSubsystems lists everything that keeps its state in thane.db.
+// They all share the memory store's connection, so when that store
+// falls back to an in-memory database none of them persist, and each
+// gets its own /health entry saying so.
+var memoryDBSubsystems = []struct{ key, name string }{
+	{memoryDependency, "Conversation memory"},
+	{"archive", "Session archive"},
+	{"working_memory", "Working memory"},
+	{"opstate", "Operational state"},
+	{"usage", "Token usage records"},
+	{"checkpoints", "Checkpoints"},
+	{"loop_queue", "Loop queue"},
+	{"mqtt_subscriptions", "MQTT subscriptions"},
+	{"watchlist", "Entity watchlist"},
+	{"notifications", "Notification records"},
+	{"documents", "Document index"},
+}
+
+// memoryDBStatus describes how thane.db came up, for the health
+// endpoint. The zero value means it opened normally.
+type memoryDBStatus struct {
+	// Fallback is why the store runs on an in-memory database; empty
+	// when thane.db opened.
+	Fallback string
+	// MovedTo is where a corrupt thane.db was renamed before a fresh
+	// one was created; empty when no recovery happened.
+	MovedTo string
+	// Lost lists the tables that could not be copied out of the
+	// corrupt file, and Partial the ones copied with unreadable rows
+	// left behind. SalvageErr is set when nothing could be read.
+	Lost       []string
+	Partial    []string
+	SalvageErr error
+}
+
+// dependencies returns the /health entries for s, or nil when thane.db
+// is healthy.
+func (s memoryDBStatus) dependencies() map[string]api.DependencyStatus {
+	deps := make(map[string]api.DependencyStatus)
+	if s.Fallback != "" {
+		for _, sub := range memoryDBSubsystems {
+			deps[sub.key] = api.DependencyStatus{
+				Name:      sub.name,
+				Ready:     false,
+				LastError: "thane.db unavailable, state is kept in memory and lost on restart: " + s.Fallback,
+			}
+		}
+		return deps
+	}
+	if s.MovedTo == "" {
+		return nil
+	}
+
+	// A recovery is worth a look even when everything was copied, but
+	// only lost data keeps the status degraded.
+	msg := "thane.db was corrupt and moved to " + s.MovedTo
+	switch {
+	case s.SalvageErr != nil:
+		msg += fmt.Sprintf("; nothing could be recovered: %v", s.SalvageErr)
+	case len(s.Lost) > 0 || len(s.Partial) > 0:
+		if len(s.Lost) > 0 {
+			msg += "; tables lost: " + strings.Join(s.Lost, ", ")
+		}
+		if len(s.Partial) > 0 {
+			msg += "; tables missing rows: " + strings.Join(s.Partial, ", ")
+		}
+	default:
+		msg += "; all tables recovered"
+	}
+	deps[memoryDependency] = api.DependencyStatus{
+		Name:      "Conversation memory",
+		Ready:     s.SalvageErr == nil && len(s.Lost) == 0 && len(s.Partial) == 0,
+		LastError: msg,
+	}
+	return deps
+}
+
+// openMemoryStore opens the conversation memory database at dbPath.
+//
+// A file that fails SQLite's quick check is renamed aside and whatever
+// can still be read from it — conversations, the session archive, usage
+// history, and the rest of what lives in thane.db — is copied into a
+// fresh database, so a damaged file is neither retried on every start
+// nor thrown away. If the database still cannot be opened (locked by
+// another process, unreadable directory, ...), the store falls back to
+// an in-memory database so the agent keeps running. Every store that
+// shares the connection then runs without persistence, which the
+// returned status reports on /health.
+func openMemoryStore(dbPath string, logger *slog.Logger) (*memory.SQLiteStore, memoryDBStatus, error) {
+	status := recoverMemoryDatabase(dbPath, logger)
+
+	mem, openErr := memory.NewSQLiteStoreWithLogger(dbPath, 100, logger)
+	if openErr == nil {
+		return mem, status, nil
+	}
+
+	names := make([]string, 0, len(memoryDBSubsystems))
+	for _, sub := range memoryDBSubsystems {
+		names = append(names, sub.key)
+	}
+	logger.Error("memory database unavailable; running on an in-memory database, nothing stored in thane.db will survive a restart",
+		"path", dbPath, "error", openErr, "affected", strings.Join(names, ","))
+	mem, err := memory.NewInMemorySQLiteStore(100, logger)
+	if err != nil {
+		return nil, status, fmt.Errorf("open memory database %s: %w (in-memory fallback: %v)", dbPath, openErr, err)
+	}
+	status.Fallback = openErr.Error()
+	return mem, status, nil
+}
+
+// recoverMemoryDatabase runs a quick check on dbPath and, when the file
+// is corrupt, moves it aside and salvages its readable tables into a
+// fresh file at dbPath. Anything else that goes wrong is left for the
+// store's own open to report.
+func recoverMemoryDatabase(dbPath string, logger *slog.Logger) memoryDBStatus {
+	var status memoryDBStatus
+
+	db, err := database.Open(dbPath)
+	if err != nil {
+		return status
+	}
+	checkErr := database.CheckIntegrity(db)
+	db.Close()
+	if !errors.Is(checkErr, database.ErrCorrupt) {
+		if checkErr != nil {
+			logger.Warn("memory database integrity check did not run", "path", dbPath, "error", checkErr)
+		}
+		return status
+	}
+
+	moved, err := database.SetAside(dbPath, time.Now())
+	if err != nil {
+		logger.Error("memory database is corrupt and could not be moved aside",
+			"path", dbPath, "integrity", checkErr, "error", err)
+		return status
+	}
+	status.MovedTo = moved
+
+	fresh, err := database.Open(dbPath)
+	if err != nil {
+		status.SalvageErr = err
+	} else {
+		var res database.SalvageResult
+		res, status.SalvageErr = database.Salvage(context.Background(), fresh, moved)
+		status.Lost = res.Lost
+		status.Partial = res.Partial
+		fresh.Close()
+		if status.SalvageErr == nil {
+			logger.Info("memory database salvage finished",
+				"recovered", len(res.Recovered), "partial", strings.Join(res.Partial, ","),
+				"lost", strings.Join(res.Lost, ","))
+		}
+	}
+
+	if status.SalvageErr != nil {
+		logger.Error("memory database is corrupt and nothing could be salvaged; started a fresh one",
+			"path", dbPath, "moved_to", moved, "integrity", checkErr, "error", status.SalvageErr)
+	} else {
+		logger.Error("memory database is corrupt; moved it aside and copied what was readable into a fresh one",
+			"path", dbPath, "moved_to", moved, "integrity", checkErr, "lost_tables", strings.Join(status.Lost, ","),
+			"partial_tables", strings.Join(status.Partial, ","))
+	}
+	return status
+}
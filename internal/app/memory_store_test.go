@@ -0,0 +1,84 @@
+package app
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenMemoryStore_MovesCorruptFileAside(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "thane.db")
+	if err := os.WriteFile(dbPath, []byte(strings.Repeat("not a database ", 512)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	mem, status, err := openMemoryStore(dbPath, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("openMemoryStore() error = %v", err)
+	}
+	defer mem.Close()
+	if status.Fallback != "" {
+		t.Errorf("Fallback = %q, want a fresh file-backed store", status.Fallback)
+	}
+
+	aside, _ := filepath.Glob(dbPath + ".corrupt-*")
+	if len(aside) != 1 {
+		t.Fatalf("corrupt copies = %v, want exactly one", aside)
+	}
+	if status.MovedTo != aside[0] {
+		t.Errorf("MovedTo = %q, want %q", status.MovedTo, aside[0])
+	}
+	// Nothing in a file that isn't a database can be salvaged, and
+	// health says so.
+	if status.SalvageErr == nil {
+		t.Error("SalvageErr = nil, want the salvage failure")
+	}
+	dep, ok := status.dependencies()[memoryDependency]
+	if !ok || dep.Ready || !strings.Contains(dep.LastError, aside[0]) {
+		t.Errorf("memory dependency = %+v, want not ready and naming %s", dep, aside[0])
+	}
+	if _, err := mem.GetOrCreateConversation("conv-1"); err != nil {
+		t.Fatalf("fresh store unusable: %v", err)
+	}
+}
+
+func TestOpenMemoryStore_FallsBackToInMemory(t *testing.T) {
+	// A path under a missing directory cannot be opened at all.
+	dbPath := filepath.Join(t.TempDir(), "missing", "thane.db")
+
+	mem, status, err := openMemoryStore(dbPath, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("openMemoryStore() error = %v", err)
+	}
+	defer mem.Close()
+	if status.Fallback == "" {
+		t.Fatal("Fallback is empty, want the open failure reason")
+	}
+	if _, err := mem.GetOrCreateConversation("conv-1"); err != nil {
+		t.Fatalf("in-memory store unusable: %v", err)
+	}
+
+	// Every store sharing the connection lost persistence, not just
+	// conversations.
+	deps := status.dependencies()
+	for _, sub := range memoryDBSubsystems {
+		dep, ok := deps[sub.key]
+		if !ok {
+			t.Errorf("no health entry for %s", sub.key)
+			continue
+		}
+		if dep.Ready {
+			t.Errorf("%s reported ready on an in-memory database", sub.key)
+		}
+	}
+}
+
+func TestMemoryDBStatus_HealthyHasNoDependencies(t *testing.T) {
+	if deps := (memoryDBStatus{}).dependencies(); len(deps) != 0 {
+		t.Errorf("dependencies() = %v, want none", deps)
+	}
+}
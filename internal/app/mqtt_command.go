@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/channels/mqtt"
+	"github.com/nugget/thane-ai-agent/internal/model/router"
+	looppkg "github.com/nugget/thane-ai-agent/internal/runtime/loop"
+)
+
+// newMQTTCommandHandler returns the handler for the MQTT command wake
+// topic. Each command compiles into a transient request/reply loop
+// launch, the same shape scheduled tasks use, and the response text is
+// returned for the optional result topic.
+func newMQTTCommandHandler(deps taskExecDeps) mqtt.WakeCommandHandler {
+	return func(ctx context.Context, topic string, cmd mqtt.WakeCommand) (string, error) {
+		if deps.launch == nil || deps.runner == nil {
+			return "", fmt.Errorf("mqtt command: loop launcher is not configured")
+		}
+		result, err := deps.launch(ctx, buildMQTTCommandLaunch(topic, cmd), looppkg.Deps{
+			Runner:   deps.runner,
+			Logger:   deps.logger,
+			EventBus: deps.eventBus,
+		})
+		if err != nil {
+			return "", fmt.Errorf("mqtt command: %w", err)
+		}
+		if result.Response == nil {
+			return "", nil
+		}
+		return result.Response.Content, nil
+	}
+}
+
+// buildMQTTCommandLaunch compiles one wake command into a loop launch.
+// Like scheduled tasks, commands run as automation work with
+// delegation disabled; quality_floor from the payload raises the
+// routing floor when present.
+func buildMQTTCommandLaunch(topic string, cmd mqtt.WakeCommand) looppkg.Launch {
+	qualityFloor := cmd.QualityFloor
+	if qualityFloor == 0 {
+		qualityFloor = 1
+	}
+	return looppkg.Launch{
+		Spec: looppkg.Spec{
+			Name:       "mqtt-command:wake",
+			Task:       "Handle the Home Assistant automation request exactly as written.",
+			Operation:  looppkg.OperationRequestReply,
+			Completion: looppkg.CompletionNone,
+			Profile: router.LoopProfile{
+				QualityFloor:     qualityFloor,
+				Mission:          "automation",
+				DelegationGating: "disabled",
+				ExtraHints: map[string]string{
+					"source": "mqtt_command",
+					"topic":  topic,
+				},
+			},
+			Metadata: map[string]string{
+				"subsystem": "mqtt",
+				"category":  "command",
+				"topic":     topic,
+			},
+		},
+		Task:           cmd.Message,
+		ConversationID: fmt.Sprintf("mqtt-cmd-%d", time.Now().UnixNano()),
+		UsageRole:      "mqtt_command",
+		UsageTaskName:  "wake",
+	}
+}
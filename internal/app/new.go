@@ -14,6 +14,7 @@ import (
 	modelproviders "github.com/nugget/thane-ai-agent/internal/model/fleet/providers"
 	"github.com/nugget/thane-ai-agent/internal/model/llm"
 	"github.com/nugget/thane-ai-agent/internal/platform/config"
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
 	"github.com/nugget/thane-ai-agent/internal/platform/logging"
 	"github.com/nugget/thane-ai-agent/internal/platform/paths"
 )
@@ -89,6 +90,10 @@ func New(ctx context.Context, cfg *config.Config, logger *slog.Logger, stdout io
 	// configured (the initial logger is Info-level so Debug would be lost).
 	augmentedDirs := augmentPath(cfg.ExtraPath)
 
+	// Every store opens SQLite through database.Open; apply the shared
+	// connection settings before the first one (the log index) opens.
+	database.SetBusyTimeout(cfg.Database.BusyTimeout)
+
 	if err := a.initLogging(augmentedDirs); err != nil {
 		return nil, err
 	}
@@ -132,7 +137,8 @@ func New(ctx context.Context, cfg *config.Config, logger *slog.Logger, stdout io
 // newHandler creates a structured [slog.Handler] that writes to w at
 // the given level and format. This is the shared handler construction
 // used by newLogger and (with optional wrapping) by the serve command.
-func newHandler(w io.Writer, level slog.Level, format string) slog.Handler {
+// Passing a [slog.LevelVar] lets [App.Reload] change the level later.
+func newHandler(w io.Writer, level slog.Leveler, format string) slog.Handler {
 	opts := &slog.HandlerOptions{
 		Level:     level,
 		AddSource: true,
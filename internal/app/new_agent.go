@@ -1,12 +1,14 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
 	"log/slog"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
 	"github.com/nugget/thane-ai-agent/internal/platform/paths"
@@ -114,9 +116,40 @@ func (a *App) initAgentLoop(s *newState) error {
 		return fmt.Errorf("build agent loop: %w", err)
 	}
 	a.loop = loop
+	loop.SetStreamHeartbeat(cfg.Agent.StreamHeartbeat)
+	loop.SetToolErrorReflection(cfg.Agent.ToolErrorReflection)
+	loop.SetParallelToolCalls(cfg.Agent.ParallelToolCalls)
+	loop.SetMaxIterations(cfg.Agent.MaxIterations, cfg.Agent.ChannelMaxIterations)
+	loop.SetContextBudget(cfg.Agent.ContextBudget.Fraction, cfg.Agent.ContextBudget.TrimOrder)
+	loop.SetToolResultLimits(cfg.Agent.MaxToolResultBytes, cfg.Agent.ToolResultLimits)
+	loop.SetEventBus(a.eventBus)
+	if a.haInstances != nil {
+		loop.Tools().SetHomeAssistantInstances(a.haInstances)
+	}
 	if recoveryModel != "" {
 		logger.Info("LLM timeout recovery enabled", "recovery_model", recoveryModel)
 	}
+	if cfg.Debug.PromptEndpoint {
+		if cfg.Listen.AdminToken == "" {
+			logger.Warn("debug.prompt_endpoint ignored: listen.admin_token is not set")
+		} else {
+			loop.EnablePromptCapture()
+			logger.Info("assembled system prompts retained for GET /debug/prompt")
+		}
+	}
+
+	// Generate persona-voiced replies for the greeting fast-path in the
+	// background; the built-in fallbacks serve until (or unless) they land.
+	a.deferWorker("greeting-cache", func(ctx context.Context) error {
+		go func() {
+			genCtx, cancel := context.WithTimeout(ctx, time.Minute)
+			defer cancel()
+			if err := loop.RefreshGreetings(genCtx); err != nil {
+				logger.Warn("persona greeting generation failed, using fallbacks", "error", err)
+			}
+		}()
+		return nil
+	})
 
 	// Start initial session
 	a.archiveAdapter.EnsureSession("default")
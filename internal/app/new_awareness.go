@@ -63,9 +63,13 @@ func (a *App) initAwareness(s *newState) error {
 		HistoryTokens: cfg.Episodic.HistoryTokens,
 	})
 	a.loop.RegisterAlwaysContextProvider(episodicProvider)
+	if cfg.Episodic.DailyDir != "" {
+		a.loop.Tools().SetEpisodicProvider(episodicProvider)
+	}
 
 	wmProvider := memory.NewWorkingMemoryProvider(a.wmStore, tools.ConversationIDFromContext)
 	a.loop.RegisterAlwaysContextProvider(wmProvider)
+	a.loop.RegisterAlwaysContextProvider(memory.NewConversationEgoProvider(a.convEgoStore, tools.ConversationIDFromContext))
 
 	// Message-channel older-sessions catalog. Gated on the
 	// message_channel capability tag, asserted by Signal (and future
@@ -196,6 +200,7 @@ func (a *App) initAwareness(s *newState) error {
 		logger,
 	)
 	a.loop.RegisterAlwaysContextProvider(stateWindowProvider)
+	s.stateWindow = stateWindowProvider
 
 	// The window's per-entity retention rings back the subscription
 	// transition logs (#1210): declared logs render from here, and
@@ -221,7 +226,9 @@ func (a *App) initAwareness(s *newState) error {
 	// so a redundant call from OnReady is harmless.
 	if len(cfg.Person.Track) > 0 {
 		s.personTracker = contacts.NewPresenceTracker(cfg.Person.Track, cfg.Timezone, logger)
+		s.personTracker.SetPrimaryInstance(cfg.HomeAssistant.Name)
 		a.loop.RegisterAlwaysContextProvider(s.personTracker)
+		a.loop.Tools().SetPresenceTracker(s.personTracker)
 
 		// Configure device MAC addresses from config.
 		for entityID, devices := range cfg.Person.Devices {
@@ -236,7 +243,7 @@ func (a *App) initAwareness(s *newState) error {
 
 		if a.ha != nil {
 			initCtx, initCancel := context.WithTimeout(s.ctx, 10*time.Second)
-			if err := s.personTracker.Initialize(initCtx, a.ha); err != nil {
+			if err := s.personTracker.Initialize(initCtx, a.haPresenceSource()); err != nil {
 				logger.Warn("person tracker initial sync incomplete", "error", err)
 			}
 			initCancel()
@@ -259,14 +266,18 @@ func (a *App) initAwareness(s *newState) error {
 		}
 
 		pollInterval := time.Duration(cfg.Unifi.PollIntervalSec) * time.Second
-		poller := unifi.NewPoller(unifi.PollerConfig{
+		pollerCfg := unifi.PollerConfig{
 			Locator:      unifiClient,
 			Updater:      s.personTracker,
 			PollInterval: pollInterval,
 			DeviceOwners: deviceOwners,
 			APRooms:      cfg.Person.APRooms,
 			Logger:       logger,
-		})
+		}
+		if cfg.Unifi.SecurityWake {
+			pollerCfg.Bus = a.messageBus
+		}
+		poller := unifi.NewPoller(pollerCfg)
 		a.unifiPoller = poller
 
 		// Register UniFi with connwatch for health endpoint visibility.
@@ -282,6 +293,7 @@ func (a *App) initAwareness(s *newState) error {
 			"poll_interval", pollInterval,
 			"tracked_macs", len(deviceOwners),
 			"ap_rooms", len(cfg.Person.APRooms),
+			"security_wake", cfg.Unifi.SecurityWake,
 		)
 	} else if cfg.Unifi.Configured() && s.personTracker == nil {
 		logger.Warn("unifi configured but person tracking disabled (no person.track entries)")
@@ -307,10 +319,20 @@ func (a *App) initAwareness(s *newState) error {
 		if cfg.Prewarm.MaxFacts > 0 {
 			subjectProvider.SetMaxFacts(cfg.Prewarm.MaxFacts)
 		}
+		if watchlistStore != nil {
+			subjectProvider.AddSubjectSource(watchlistSubjects(watchlistStore, logger))
+		}
+		if s.personTracker != nil {
+			subjectProvider.AddSubjectSource(personSubjects(s.personTracker, a.contactStore, logger))
+		}
 		a.loop.RegisterAlwaysContextProvider(subjectProvider)
 		logger.Info("context pre-warming enabled", "max_facts", cfg.Prewarm.MaxFacts)
 	}
 
+	// Fact conflict nudges — remind the agent to reconcile extracted
+	// observations that contradicted a high-confidence fact.
+	a.loop.RegisterAlwaysContextProvider(knowledge.NewConflictContextProvider(a.factStore))
+
 	// Archive retrieval injection — pre-warm cold-start loops with
 	// relevant past conversation excerpts so the model has experiential
 	// judgment alongside Layer 1 knowledge. See issue #404. The
@@ -409,6 +431,7 @@ func (a *App) initAwareness(s *newState) error {
 
 		watcher := homeassistant.NewStateWatcher(a.haWS.Events(), filter, limiter, handler, logger)
 		a.haStateWatcher = watcher
+		remoteWatchers := a.startHAInstanceWatchers(s.ctx, filter, limiter, handler, logger)
 		a.ingestFilterRebuild = func() {
 			rebuilt, err := buildIngestFilter()
 			if err != nil {
@@ -418,6 +441,9 @@ func (a *App) initAwareness(s *newState) error {
 				logger.Warn("ingest registry read failed; keeping the previous ingestion filter", "error", err)
 			} else {
 				watcher.SetFilter(rebuilt)
+				for _, remote := range remoteWatchers {
+					remote.SetFilter(rebuilt)
+				}
 			}
 			// The wake index derives from the same registry state, so
 			// it rides the same rebuild signal (#1211).
@@ -427,6 +453,7 @@ func (a *App) initAwareness(s *newState) error {
 		}
 		logger.Info("state watcher configured",
 			"ingest_rate_limit_per_minute", cfg.HomeAssistant.IngestRateLimitPerMinute,
+			"remote_instances", len(remoteWatchers),
 		)
 	}
 
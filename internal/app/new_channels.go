@@ -25,6 +25,7 @@ import (
 	"github.com/nugget/thane-ai-agent/internal/platform/database"
 	"github.com/nugget/thane-ai-agent/internal/runtime/agent"
 	looppkg "github.com/nugget/thane-ai-agent/internal/runtime/loop"
+	"github.com/nugget/thane-ai-agent/internal/runtime/metacognitive"
 	"github.com/nugget/thane-ai-agent/internal/state/contacts"
 	"github.com/nugget/thane-ai-agent/internal/state/documents"
 	"github.com/nugget/thane-ai-agent/internal/state/knowledge"
@@ -47,6 +48,29 @@ func toMCPToolOverrides(cfg map[string]config.MCPToolConfig) map[string]mcp.Tool
 	return out
 }
 
+// warnUnlistedMCPResources logs configured resource URIs the server
+// does not list. Servers with resource templates can serve URIs they do
+// not list, so this is a hint for typos rather than an error.
+func warnUnlistedMCPResources(ctx context.Context, client *mcp.Client, serverName string, uris []string, logger *slog.Logger) {
+	listCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	listed, err := client.ListResources(listCtx)
+	if err != nil {
+		logger.Warn("MCP resource list failed", "server", serverName, "error", err)
+		return
+	}
+	known := make(map[string]bool, len(listed))
+	for _, r := range listed {
+		known[r.URI] = true
+	}
+	for _, uri := range uris {
+		if !known[uri] {
+			logger.Warn("configured MCP resource not listed by server",
+				"server", serverName, "uri", uri)
+		}
+	}
+}
+
 // initChannels wires tools and external channels into the agent loop.
 // Sections include fact store, contact directory, notifications, email,
 // forge, working memory, fact extraction, provenance,
@@ -61,6 +85,7 @@ func (a *App) initChannels(s *newState) error {
 		return fmt.Errorf("open knowledge database: %w", err)
 	}
 	a.onCloseErr("facts", factDB.Close)
+	a.walCheckpointer.Add("knowledge", a.cfg.DataDir+"/knowledge.db", factDB)
 	factStore, err := knowledge.NewStore(factDB, a.logger)
 	if err != nil {
 		return fmt.Errorf("open fact store: %w", err)
@@ -71,6 +96,19 @@ func (a *App) initChannels(s *newState) error {
 	a.loop.Tools().SetFactTools(factTools)
 	a.logger.Info("fact store initialized", "path", a.cfg.DataDir+"/knowledge.db")
 
+	// Expired facts are already hidden from reads; retire them hourly
+	// so they stop counting as stored knowledge.
+	a.registerMaintenanceTask(factExpirySweepTaskName, factExpirySweepInterval, func(context.Context) error {
+		n, err := factStore.SweepExpired()
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			a.logger.Info("expired facts retired", "count", n)
+		}
+		return nil
+	}, a.logger)
+
 	// --- Contact directory ---
 	// Structured storage for people and organizations. Separate database
 	// from facts to keep concerns isolated.
@@ -79,6 +117,7 @@ func (a *App) initChannels(s *newState) error {
 		return fmt.Errorf("open contacts database: %w", err)
 	}
 	a.onCloseErr("contacts", contactDB.Close)
+	a.walCheckpointer.Add("contacts", a.cfg.DataDir+"/contacts.db", contactDB)
 	contactStore, err := contacts.NewStore(contactDB, a.logger)
 	if err != nil {
 		return fmt.Errorf("open contact store: %w", err)
@@ -140,6 +179,36 @@ func (a *App) initChannels(s *newState) error {
 		a.logger.Info("notification router initialized", "providers", "ha_push")
 	}
 
+	// --- Autonomous-action confidence gate ---
+	// Defers low-confidence mutating tool calls on autonomous runs and
+	// asks the operator for approval through the notification router.
+	if gateCfg := a.cfg.Agent.ConfidenceGate; gateCfg.Enabled {
+		gate := &agent.ConfidenceGate{
+			Threshold:         gateCfg.Threshold,
+			MutatingTools:     gateCfg.MutatingTools,
+			AutonomousSources: gateCfg.AutonomousSources,
+		}
+		if a.notifRouter != nil {
+			notifRouter := a.notifRouter
+			gate.Notify = func(ctx context.Context, action agent.DeferredAction) error {
+				return notifRouter.SendNotification(ctx, notifications.NotificationRequest{
+					Recipient: gateCfg.NotifyRecipient,
+					Title:     "Approval needed: " + action.Tool,
+					Message:   action.Summary(),
+					Priority:  "normal",
+				})
+			}
+		} else {
+			a.logger.Warn("confidence gate enabled without a notification router; deferred actions will not be delivered for approval")
+		}
+		a.loop.ConfigureConfidenceGate(gate)
+		a.logger.Info("autonomous-action confidence gate enabled",
+			"threshold", gateCfg.Threshold,
+			"tools", len(gateCfg.MutatingTools),
+			"sources", gateCfg.AutonomousSources,
+		)
+	}
+
 	// --- Email ---
 	// Native IMAP/SMTP email. Replaces the MCP email server approach
 	// with direct IMAP connections for reading and SMTP for sending,
@@ -152,18 +221,6 @@ func (a *App) initChannels(s *newState) error {
 		emailTools := email.NewTools(emailMgr, &emailContactResolver{store: contactStore})
 		a.loop.Tools().SetEmailTools(emailTools)
 
-		// Register each account with connwatch for health monitoring.
-		for _, name := range emailMgr.AccountNames() {
-			acctName := name // capture for closure
-			acct, _ := emailMgr.Account(acctName)
-			a.connMgr.Watch(s.ctx, connwatch.WatcherConfig{
-				Name:    "email-" + acctName,
-				Probe:   func(pCtx context.Context) error { return acct.Ping(pCtx) },
-				Backoff: connwatch.DefaultBackoffConfig(),
-				Logger:  a.logger,
-			})
-		}
-
 		// --- Email polling ---
 		// Periodic IMAP check for new mail. The poller advances the
 		// per-account high-water mark and dispatches an event-source
@@ -173,22 +230,56 @@ func (a *App) initChannels(s *newState) error {
 		// sender's email into a trust zone so each event ships with an
 		// owner/trusted/household/known/stranger tag, letting the
 		// handler loop adapt depth without forking the route.
+		//
+		// On servers that advertise IMAP IDLE, a push connection per
+		// account runs the same check the moment new mail arrives and
+		// the interval poll skips that account while the connection is
+		// healthy.
+		var idleWatcher *email.IdleWatcher
 		if a.cfg.Email.PollIntervalSec > 0 {
 			poller := email.NewPoller(emailMgr, a.opStore, a.logger,
 				email.WithMessageBus(a.messageBus),
 				email.WithContactResolver(&emailContactResolver{store: contactStore}),
 			)
 			a.emailPoller = poller
+
+			if a.cfg.Email.IdleEnabled() {
+				idleWatcher = email.NewIdleWatcher(poller, a.logger)
+				idleWatcher.Start(s.ctx)
+				a.onClose("email-idle", idleWatcher.Stop)
+			}
+		}
+
+		// Register each account with connwatch for health monitoring.
+		// The probe also catches an IDLE connection that died silently
+		// and forces it to reconnect.
+		for _, name := range emailMgr.AccountNames() {
+			acctName := name // capture for closure
+			acct, _ := emailMgr.Account(acctName)
+			a.connMgr.Watch(s.ctx, connwatch.WatcherConfig{
+				Name: "email-" + acctName,
+				Probe: func(pCtx context.Context) error {
+					if err := acct.Ping(pCtx); err != nil {
+						return err
+					}
+					if idleWatcher != nil {
+						return idleWatcher.Probe(acctName)
+					}
+					return nil
+				},
+				Backoff: connwatch.DefaultBackoffConfig(),
+				Logger:  a.logger,
+			})
 		}
 
-		a.logger.Info("email enabled", "accounts", emailMgr.AccountNames(), "poll_interval", a.cfg.Email.PollIntervalSec)
+		a.logger.Info("email enabled", "accounts", emailMgr.AccountNames(), "poll_interval", a.cfg.Email.PollIntervalSec, "idle", idleWatcher != nil)
 	} else {
 		a.logger.Info("email disabled (not configured)")
 	}
 
 	// --- Forge integration ---
-	// Native GitHub (and future Gitea/GitLab) integration. Replaces the
-	// MCP github server with direct API calls via go-github.
+	// Native GitHub and GitLab (and future Gitea) integration. Replaces
+	// the MCP github server with direct API calls.
 	var forgeOpLog *forge.OperationLog
 	if a.cfg.Forge.Configured() {
 		var err error
@@ -221,6 +312,7 @@ func (a *App) initChannels(s *newState) error {
 	// Gives the agent a read/write scratchpad for experiential context
 	// that survives compaction. Auto-injected via context provider below.
 	a.loop.Tools().SetWorkingMemoryStore(a.wmStore)
+	a.loop.Tools().SetConversationEgoStore(a.convEgoStore)
 
 	// --- Fact extraction ---
 	// Automatic extraction of facts from conversations. Runs async after
@@ -246,7 +338,7 @@ func (a *App) initChannels(s *newState) error {
 				transcript.WriteString(line)
 			}
 
-			prompt := prompts.FactExtractionPrompt(userMsg, assistantResp, transcript.String())
+			prompt, promptVersion := a.prompts.FactExtractionPrompt(userMsg, assistantResp, transcript.String())
 			msgs := []llm.Message{{Role: "user", Content: prompt}}
 
 			start := time.Now()
@@ -260,8 +352,10 @@ func (a *App) initChannels(s *newState) error {
 			}
 			a.logger.Debug("fact extraction LLM call complete",
 				"model", extractionModel,
+				"prompt_version", promptVersion,
 				"elapsed_ms", time.Since(start).Milliseconds(),
 				"response_len", len(resp.Message.Content))
+			a.loop.RecordPromptUsage(ctx, prompts.FactExtraction, extractionModel, promptVersion, resp)
 
 			// Parse JSON (strip code fences, same pattern as metadata gen)
 			content := resp.Message.Content
@@ -286,6 +380,56 @@ func (a *App) initChannels(s *newState) error {
 		a.loop.ConfigureSessionStores(agent.SessionStoreWiring{Extractor: extractor})
 	}
 
+	// --- Conversation titles ---
+	// Running titles for live conversations, surfaced in the
+	// conversation-list API. Runs async after each interaction using a
+	// local model and is throttled per conversation. Opt-in via config.
+	if a.cfg.ConversationTitles.Enabled {
+		titleCfg := a.cfg.ConversationTitles
+		a.logger.Info("conversation titles enabled",
+			"model", titleCfg.Model,
+			"check_every_turns", titleCfg.CheckEveryTurns,
+			"min_interval_seconds", titleCfg.MinIntervalSeconds)
+
+		titler := memory.NewTitler(a.mem, a.logger, memory.TitlerConfig{
+			CheckEvery:  titleCfg.CheckEveryTurns,
+			MinInterval: time.Duration(titleCfg.MinIntervalSeconds) * time.Second,
+			Timeout:     time.Duration(titleCfg.TimeoutSeconds) * time.Second,
+		})
+		titler.SetTitleFunc(func(ctx context.Context, currentTitle string, history []memory.Message) (*memory.TitleResult, error) {
+			var transcript strings.Builder
+			for _, m := range history {
+				line := fmt.Sprintf("[%s] %s\n", m.Role, m.Content)
+				if transcript.Len()+len(line) > 4000 {
+					break
+				}
+				transcript.WriteString(line)
+			}
+
+			prompt := prompts.ConversationTitlePrompt(currentTitle, transcript.String())
+			msgs := []llm.Message{{Role: "user", Content: prompt}}
+
+			resp, err := a.llmClient.Chat(ctx, titleCfg.Model, msgs, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			content := resp.Message.Content
+			content = strings.TrimPrefix(content, "```json\n")
+			content = strings.TrimPrefix(content, "```\n")
+			content = strings.TrimSuffix(content, "\n```")
+			content = strings.TrimSpace(content)
+
+			var result memory.TitleResult
+			if err := json.Unmarshal([]byte(content), &result); err != nil {
+				return nil, fmt.Errorf("parse title result: %w", err)
+			}
+			return &result, nil
+		})
+
+		a.loop.ConfigureSessionStores(agent.SessionStoreWiring{Titler: titler})
+	}
+
 	// Git provenance is initialized per managed document root below when
 	// doc_roots.<root>.git enables signed commits. The legacy top-level
 	// provenance block is retained only for older config compatibility.
@@ -367,11 +511,12 @@ func (a *App) initChannels(s *newState) error {
 	if a.documentStore != nil {
 		talentVerifier = a.documentStore.VerifyPath
 	}
-	parsedTalents, err := a.loadTalents(s.ctx, talentVerifier)
+	parsedTalents, err := a.loadTalents(s.ctx, a.cfg.TalentsDir, talentVerifier)
 	if err != nil {
 		return err
 	}
 	s.parsedTalents = parsedTalents
+	a.talents = parsedTalents
 	a.loop.ConfigureCapabilityWiring(agent.CapabilityWiring{ParsedTalents: parsedTalents})
 
 	// --- Temp file store ---
@@ -402,15 +547,20 @@ func (a *App) initChannels(s *newState) error {
 		a.logger.Info("content resolver enabled for tool arguments")
 	}
 
-	// --- Usage recording ---
-	// Wire persistent token usage recording into the agent loop and
-	// register the cost_summary tool so the agent can query its own spend.
+	// --- Usage recording and tool audit ---
+	// Wire persistent token usage recording and the tool audit log into
+	// the agent loop, and register the cost_summary tool so the agent
+	// can query its own spend.
 	a.loop.ConfigureSessionStores(agent.SessionStoreWiring{
 		UsageStore:   a.usageStore,
 		Pricing:      a.cfg.Pricing,
 		UsageCatalog: a.modelCatalog,
+		ToolAudit:    a.toolAudit,
 	})
 	a.loop.Tools().SetUsageStore(a.usageStore)
+	a.summaryWorker.SetUsageRecorder(func(ctx context.Context, model, promptVersion string, resp *llm.ChatResponse) {
+		a.loop.RecordPromptUsage(ctx, prompts.SessionMetadata, model, promptVersion, resp)
+	})
 	if a.loopDefinitionRuntime == nil {
 		a.loopDefinitionRuntime = newAppLoopDefinitionRuntime(a)
 	}
@@ -468,6 +618,14 @@ func (a *App) initChannels(s *newState) error {
 		Registry:   a.loopRegistry,
 		LaunchLoop: a.launchLoop,
 	})
+	if a.metacogJournal != nil {
+		a.loop.Tools().Register(&tools.Tool{
+			Name:        "metacognitive_history",
+			Description: metacognitive.JournalToolDescription,
+			Parameters:  metacognitive.JournalToolDefinition(),
+			Handler:     metacognitive.JournalToolHandler(a.metacogJournal),
+		})
+	}
 	a.initMessageBus()
 	a.loop.Tools().ConfigureMessageTools(tools.MessageToolDeps{
 		Bus: a.messageBus,
@@ -505,32 +663,30 @@ func (a *App) initChannels(s *newState) error {
 	// Optional web search tool. Supports multiple providers; the first
 	// configured provider becomes the default if none is specified.
 	if a.cfg.Search.Configured() {
-		primary := a.cfg.Search.Default
-		mgr := search.NewManager(primary)
-
+		var providers []search.Provider
 		if a.cfg.Search.SearXNG.Configured() {
-			mgr.Register(search.NewSearXNG(a.cfg.Search.SearXNG.URL))
-			if primary == "" {
-				primary = "searxng"
-			}
+			providers = append(providers, search.NewSearXNG(a.cfg.Search.SearXNG.URL))
 		}
 		if a.cfg.Search.Brave.Configured() {
-			mgr.Register(search.NewBrave(a.cfg.Search.Brave.APIKey))
-			if primary == "" {
-				primary = "brave"
-			}
+			providers = append(providers, search.NewBrave(a.cfg.Search.Brave.APIKey))
+		}
+		if a.cfg.Search.Google.Configured() {
+			providers = append(providers, search.NewGoogle(a.cfg.Search.Google.APIKey, a.cfg.Search.Google.CX))
+		}
+		if a.cfg.Search.DuckDuckGo.Configured() {
+			providers = append(providers, search.NewDuckDuckGo(a.logger))
 		}
 
-		// Re-create manager with resolved primary if it was empty.
-		if a.cfg.Search.Default == "" && primary != "" {
-			mgr = search.NewManager(primary)
-			if a.cfg.Search.SearXNG.Configured() {
-				mgr.Register(search.NewSearXNG(a.cfg.Search.SearXNG.URL))
-			}
-			if a.cfg.Search.Brave.Configured() {
-				mgr.Register(search.NewBrave(a.cfg.Search.Brave.APIKey))
-			}
+		primary := a.cfg.Search.Default
+		if primary == "" {
+			primary = providers[0].Name()
+		}
+		mgr := search.NewManager(primary)
+		for _, p := range providers {
+			mgr.Register(p)
 		}
+		mgr.EnableCache(a.cfg.Search.Cache.ParsedTTL(), a.cfg.Search.Cache.EntryLimit())
+		a.searchMgr = mgr
 
 		a.loop.Tools().SetSearchManager(mgr)
 		a.logger.Info("web search enabled", "primary", primary, "providers", mgr.Providers())
@@ -540,8 +696,10 @@ func (a *App) initChannels(s *newState) error {
 
 	// --- Web Fetch ---
 	// Always available — no configuration needed. Fetches web pages and
-	// extracts readable text content.
-	a.loop.Tools().SetFetcher(search.NewFetcher())
+	// extracts readable text content. Shares search.cache settings.
+	a.webFetcher = search.NewFetcher()
+	a.webFetcher.EnableCache(a.cfg.Search.Cache.ParsedTTL(), a.cfg.Search.Cache.EntryLimit())
+	a.loop.Tools().SetFetcher(a.webFetcher)
 
 	// --- Media transcript ---
 	// Wraps yt-dlp for on-demand transcript retrieval from YouTube,
@@ -652,7 +810,45 @@ func (a *App) initChannels(s *newState) error {
 		factTools.SetEmbeddingClient(embClient)
 		contactTools.SetEmbeddingClient(embClient)
 		s.embClient = embClient
+		factStore.SetEmbeddingModel(embClient.Model())
+		contactStore.SetEmbeddingModel(embClient.Model())
 		a.logger.Info("embeddings enabled", "model", a.cfg.Embeddings.Model, "url", a.cfg.Embeddings.BaseURL)
+
+		// Detect vectors left behind by a previous embedding model.
+		a.deferWorker("embedding-index-check", func(ctx context.Context) error {
+			go checkEmbeddingIndexes(ctx, embClient, map[string]knowledge.EmbeddingIndex{
+				"facts":    factStore,
+				"contacts": contactStore,
+			}, a.logger)
+			return nil
+		})
+
+		// Semantic archive search: embed archived messages in the
+		// background, newest first, so enabling it needs no upfront
+		// reindex. Each pass picks up whatever is still unembedded.
+		if a.cfg.Archive.SemanticSearch {
+			a.archiveStore.SetEmbeddingClient(embClient, a.cfg.Embeddings.Model)
+			a.deferWorker("archive-embedder", func(ctx context.Context) error {
+				go func() {
+					ticker := time.NewTicker(archiveEmbedInterval)
+					defer ticker.Stop()
+					for {
+						if n, err := a.archiveStore.EmbedPending(ctx, archiveEmbedBatch); err != nil {
+							a.logger.Warn("archive embedding backfill failed", "embedded", n, "error", err)
+						} else if n > 0 {
+							a.logger.Debug("archive embedding backfill", "embedded", n)
+						}
+						select {
+						case <-ctx.Done():
+							return
+						case <-ticker.C:
+						}
+					}
+				}()
+				return nil
+			})
+			a.logger.Info("semantic archive search enabled", "model", a.cfg.Embeddings.Model)
+		}
 	}
 
 	// --- MCP servers ---
@@ -670,7 +866,22 @@ func (a *App) initChannels(s *newState) error {
 				Logger:  a.logger,
 			})
 		case "http":
-			transport = mcp.NewHTTPTransport(mcp.HTTPConfig{
+			httpCfg := mcp.HTTPConfig{
+				URL:     serverCfg.URL,
+				Headers: serverCfg.Headers,
+				Logger:  a.logger,
+			}
+			if o := serverCfg.OAuth2; o != nil {
+				httpCfg.OAuth2 = &mcp.OAuth2Config{
+					TokenURL:     o.TokenURL,
+					ClientID:     o.ClientID,
+					ClientSecret: o.ClientSecret,
+					Scopes:       o.Scopes,
+				}
+			}
+			transport = mcp.NewHTTPTransport(httpCfg)
+		case "sse":
+			transport = mcp.NewSSETransport(mcp.SSEConfig{
 				URL:     serverCfg.URL,
 				Headers: serverCfg.Headers,
 				Logger:  a.logger,
@@ -691,18 +902,23 @@ func (a *App) initChannels(s *newState) error {
 			continue
 		}
 
-		bridgeCtx, bridgeCancel := context.WithTimeout(s.ctx, 30*time.Second)
-		count, err := mcp.BridgeTools(
-			bridgeCtx,
-			client, serverCfg.Name, a.loop.Tools(),
-			mcp.BridgeOptions{
+		// The supervisor owns the bridged tool set: after a transport
+		// failure it reconnects and re-bridges, so the registry tracks
+		// what the server offers now.
+		supervisor := mcp.NewSupervisor(client, mcp.SupervisorConfig{
+			ServerName: serverCfg.Name,
+			Registry:   a.loop.Tools(),
+			Bridge: mcp.BridgeOptions{
 				Include:       serverCfg.IncludeTools,
 				Exclude:       serverCfg.ExcludeTools,
 				Tags:          serverCfg.Tags,
 				ToolOverrides: toMCPToolOverrides(serverCfg.Tools),
 			},
-			a.logger,
-		)
+			Logger: a.logger,
+		})
+
+		bridgeCtx, bridgeCancel := context.WithTimeout(s.ctx, 30*time.Second)
+		count, err := supervisor.Bridge(bridgeCtx)
 		bridgeCancel()
 		if err != nil {
 			a.logger.Error("MCP tool bridge failed",
@@ -716,7 +932,13 @@ func (a *App) initChannels(s *newState) error {
 		a.mcpClients = append(a.mcpClients, client)
 		mcpName := serverCfg.Name // capture for closure
 		a.onCloseErr("mcp-"+mcpName, client.Close)
+		a.deferWorker("mcp-"+mcpName, func(ctx context.Context) error {
+			go supervisor.Run(ctx)
+			return nil
+		})
 
+		// Ping fails while the supervisor is reconnecting, so the
+		// watcher reports the server degraded for the outage.
 		a.connMgr.Watch(s.ctx, connwatch.WatcherConfig{
 			Name:    "mcp-" + serverCfg.Name,
 			Probe:   func(pCtx context.Context) error { return client.Ping(pCtx) },
@@ -724,9 +946,15 @@ func (a *App) initChannels(s *newState) error {
 			Logger:  a.logger,
 		})
 
+		if len(serverCfg.Resources) > 0 {
+			warnUnlistedMCPResources(s.ctx, client, serverCfg.Name, serverCfg.Resources, a.logger)
+			a.loop.RegisterAlwaysContextProvider(mcp.NewResourceProvider(client, serverCfg.Name, serverCfg.Resources, a.logger))
+		}
+
 		a.logger.Info("MCP server connected",
 			"server", serverCfg.Name,
 			"tools", count,
+			"resources", len(serverCfg.Resources),
 		)
 	}
 
@@ -766,6 +994,7 @@ func (a *App) initChannels(s *newState) error {
 				Logger:        a.logger,
 				RateLimit:     a.cfg.Signal.RateLimitPerMinute,
 				HandleTimeout: a.cfg.Signal.HandleTimeout,
+				DisableTyping: !a.cfg.Signal.TypingIndicatorsEnabled(),
 				Routing:       a.cfg.Signal.Routing,
 				Resolver: &contactChannelBindingResolver{
 					store:            contactStore,
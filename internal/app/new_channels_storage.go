@@ -19,6 +19,7 @@ func (a *App) initAttachmentRuntime() error {
 			return fmt.Errorf("open attachments database: %w", err)
 		}
 		a.onCloseErr("attachments", attachDB.Close)
+		a.walCheckpointer.Add("attachments", attachDBPath, attachDB)
 		a.attachmentStore, err = attachments.NewStore(attachDB, storeDir, a.logger)
 		if err != nil {
 			return fmt.Errorf("init attachment store: %w", err)
@@ -52,8 +52,8 @@ func (a *App) initDelegation(s *newState) error {
 	if tfs := a.loop.Tools().TempFileStore(); tfs != nil {
 		delegateExec.SetTempFileStore(tfs)
 	}
-	// thane_now and thane_assign are Core: delegation is a primitive
-	// operation, not a capability. A loop with a narrow tag scope
+	// thane_now, thane_fanout, and thane_assign are Core: delegation is
+	// a primitive operation, not a capability. A loop with a narrow tag scope
 	// (forge only, ha only) still needs to be able to spawn a sub-loop
 	// for a side investigation or background task — restricting
 	// delegate spawning to a tag would create cases where the model
@@ -70,6 +70,13 @@ func (a *App) initDelegation(s *newState) error {
 		Handler:     delegate.NowToolHandler(delegateExec),
 		Core:        true,
 	})
+	a.loop.Tools().Register(&tools.Tool{
+		Name:        "thane_fanout",
+		Description: delegate.FanoutToolDescription,
+		Parameters:  delegate.FanoutToolDefinition(),
+		Handler:     delegate.FanoutToolHandler(delegateExec),
+		Core:        true,
+	})
 	a.loop.Tools().Register(&tools.Tool{
 		Name:        "thane_assign",
 		Description: delegate.AssignToolDescription,
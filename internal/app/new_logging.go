@@ -24,12 +24,18 @@ func (a *App) initLogging(augmentedDirs []string) error {
 	logger := a.logger
 	stdout := a.stdout
 
+	// Levels live in LevelVars so a config reload can change them
+	// without rebuilding the handler chain.
 	level, _ := config.ParseLogLevel(cfg.Logging.Level)
 	stdoutLevel, _ := config.ParseLogLevel(cfg.Logging.StdoutLevelValue())
+	a.logLevel = new(slog.LevelVar)
+	a.logLevel.Set(level)
+	a.stdoutLogLevel = new(slog.LevelVar)
+	a.stdoutLogLevel.Set(stdoutLevel)
 
 	var stdoutHandler slog.Handler
 	if cfg.Logging.StdoutEnabled() {
-		stdoutHandler = newHandler(stdout, stdoutLevel, cfg.Logging.StdoutFormatValue())
+		stdoutHandler = newHandler(stdout, a.stdoutLogLevel, cfg.Logging.StdoutFormatValue())
 	}
 
 	logRoot := cfg.Logging.RootPath()
@@ -48,8 +54,8 @@ func (a *App) initLogging(augmentedDirs []string) error {
 	}
 
 	var handler slog.Handler = logging.NewDatasetHandler(stdoutHandler, datasetWriter, logging.DatasetHandlerOptions{
-		DatasetLevel:    level,
-		StdoutLevel:     stdoutLevel,
+		DatasetLevel:    a.logLevel,
+		StdoutLevel:     a.stdoutLogLevel,
 		StdoutEnabled:   cfg.Logging.StdoutEnabled(),
 		EventsEnabled:   cfg.Logging.DatasetEnabled(logging.DatasetEvents),
 		RequestsEnabled: cfg.Logging.DatasetEnabled(logging.DatasetRequests),
@@ -12,6 +12,7 @@ import (
 	"github.com/nugget/thane-ai-agent/internal/channels/mqtt"
 	"github.com/nugget/thane-ai-agent/internal/connwatch"
 	"github.com/nugget/thane-ai-agent/internal/integrations/companion"
+	"github.com/nugget/thane-ai-agent/internal/integrations/search"
 	"github.com/nugget/thane-ai-agent/internal/model/fleet"
 	"github.com/nugget/thane-ai-agent/internal/platform/checkpoint"
 	"github.com/nugget/thane-ai-agent/internal/platform/config"
@@ -51,6 +52,41 @@ func (a *App) initServers(s *newState) error {
 	)
 	server.SetMemoryStore(a.mem)
 	server.SetArchiveStore(a.archiveStore)
+	server.SetAdminToken(cfg.Listen.AdminToken)
+	if a.delegateExec != nil {
+		server.ConfigureDelegateReplay(a.delegateExec.Replay)
+	}
+	if a.metacogJournal != nil {
+		server.ConfigureMetacognitiveJournal(a.metacogJournal.Report)
+	}
+
+	// --- Sensor webhook receiver ---
+	// Push-only sensors post readings to the API server; each becomes a
+	// virtual entity transition in the state window (and so in watchlist
+	// transition logs) and reaches subscription wakes, the same taps a
+	// real HA state change passes through.
+	if cfg.SensorWebhook.Enabled && s.stateWindow != nil {
+		sensors := make(map[string]api.WebhookSensor, len(cfg.SensorWebhook.Sensors))
+		for name, sc := range cfg.SensorWebhook.Sensors {
+			sensors[name] = api.WebhookSensor{
+				EntityID:    sc.EntityID,
+				Field:       sc.Field,
+				DeviceClass: sc.DeviceClass,
+			}
+		}
+		stateWindow := s.stateWindow
+		server.ConfigureSensorWebhook(cfg.SensorWebhook.Path, cfg.SensorWebhook.Secret, sensors,
+			func(entityID, oldState, newState, deviceClass string) {
+				stateWindow.HandleStateChange(entityID, oldState, newState, deviceClass)
+				if a.subWakeFeeder != nil {
+					a.subWakeFeeder.HandleStateChange(entityID, oldState, newState, deviceClass)
+				}
+			})
+		logger.Info("sensor webhook receiver configured",
+			"path", cfg.SensorWebhook.Path,
+			"sensors", len(sensors),
+		)
+	}
 	server.UseContactStore(a.contactStore)
 	server.UseLoopDefinitionRegistry(a.loopDefinitionRegistry)
 	server.ConfigureLoopDefinitionView(a.loopDefinitionView)
@@ -76,17 +112,44 @@ func (a *App) initServers(s *newState) error {
 		result := make(map[string]api.DependencyStatus, len(status))
 		for name, st := range status {
 			ds := api.DependencyStatus{
-				Name:      st.Name,
-				Ready:     st.Ready,
-				LastError: st.LastError,
+				Name:         st.Name,
+				Ready:        st.Ready,
+				LastError:    st.LastError,
+				LastProbeMs:  st.LastProbeDuration.Milliseconds(),
+				RecentProbes: st.RecentProbes,
 			}
 			if !st.LastCheck.IsZero() {
 				ds.LastCheck = st.LastCheck.Format(time.RFC3339)
 			}
+			if !st.LastSuccess.IsZero() {
+				ds.LastSuccess = st.LastSuccess.Format(time.RFC3339)
+			}
+			if st.RecentProbes > 0 {
+				rate := st.SuccessRate
+				ds.SuccessRate = &rate
+			}
+			result[name] = ds
+		}
+		for name, ds := range a.memStatus.dependencies() {
 			result[name] = ds
 		}
 		return result
 	})
+	server.SetDatabaseStats(a.walCheckpointer.Stats)
+	server.SetWebCacheStats(func() map[string]search.CacheStats {
+		stats := make(map[string]search.CacheStats, 2)
+		if a.searchMgr != nil {
+			if st, ok := a.searchMgr.CacheStats(); ok {
+				stats["search"] = st
+			}
+		}
+		if a.webFetcher != nil {
+			if st, ok := a.webFetcher.CacheStats(); ok {
+				stats["fetch"] = st
+			}
+		}
+		return stats
+	})
 	server.ConfigureAnthropicRateLimitSnapshotSource(func() *fleet.AnthropicRateLimitSnapshot {
 		if a.modelRuntime == nil {
 			return nil
@@ -101,6 +164,8 @@ func (a *App) initServers(s *newState) error {
 	// on clean shutdown and before model failover. Shares thane.db.
 	checkpointCfg := checkpoint.Config{
 		PeriodicMessages: 50, // Snapshot every 50 messages
+		Incremental:      a.cfg.Checkpoints.Incremental,
+		FullEvery:        a.cfg.Checkpoints.FullEvery,
 	}
 	checkpointer, err := checkpoint.NewCheckpointer(a.mem.DB(), checkpointCfg, logger)
 	if err != nil {
@@ -117,9 +182,11 @@ func (a *App) initServers(s *newState) error {
 				msgs := make([]checkpoint.SourceMessage, len(c.Messages))
 				for j, m := range c.Messages {
 					msgs[j] = checkpoint.SourceMessage{
-						Role:      m.Role,
-						Content:   m.Content,
-						Timestamp: m.Timestamp,
+						Role:       m.Role,
+						Content:    m.Content,
+						Timestamp:  m.Timestamp,
+						ToolCalls:  m.ToolCalls,
+						ToolCallID: m.ToolCallID,
 					}
 				}
 				conv, err := checkpoint.ConvertConversation(c.ID, c.CreatedAt, c.UpdatedAt, msgs)
@@ -146,6 +213,8 @@ func (a *App) initServers(s *newState) error {
 					CreatedAt:  f.CreatedAt,
 					UpdatedAt:  f.UpdatedAt,
 					Confidence: f.Confidence,
+					Subjects:   f.Subjects,
+					ExpiresAt:  f.ExpiresAt,
 				}
 			}
 			return result, nil
@@ -157,6 +226,10 @@ func (a *App) initServers(s *newState) error {
 			}
 			result := make([]checkpoint.Task, len(tasks))
 			for i, t := range tasks {
+				def, err := json.Marshal(t)
+				if err != nil {
+					return nil, fmt.Errorf("marshal task %s: %w", t.ID, err)
+				}
 				result[i] = checkpoint.Task{
 					ID:          checkpoint.ParseUUID(t.ID),
 					Name:        t.Name,
@@ -165,6 +238,8 @@ func (a *App) initServers(s *newState) error {
 					Action:      string(t.Payload.Kind),
 					Enabled:     t.Enabled,
 					CreatedAt:   t.CreatedAt,
+					UpdatedAt:   t.UpdatedAt,
+					Definition:  def,
 				}
 			}
 			return result, nil
@@ -172,7 +247,10 @@ func (a *App) initServers(s *newState) error {
 	)
 	server.SetCheckpointer(checkpointer)
 	a.loop.SetFailoverHandler(checkpointer)
-	logger.Info("checkpointing enabled", "periodic_messages", checkpointCfg.PeriodicMessages)
+	logger.Info("checkpointing enabled",
+		"periodic_messages", checkpointCfg.PeriodicMessages,
+		"incremental", checkpointCfg.Incremental,
+	)
 
 	checkpointer.LogStartupStatus()
 
@@ -314,6 +392,7 @@ func (a *App) initServers(s *newState) error {
 		}
 
 		mqttPub := mqtt.New(cfg.MQTT, a.mqttInstanceID, dailyTokens, statsAdapter, logger)
+		mqttPub.SetDiscoveryStateFile(filepath.Join(cfg.DataDir, "mqtt_discovery.json"))
 		a.mqttPub = mqttPub
 
 		// --- MQTT wake subscription store ---
@@ -396,6 +475,40 @@ func (a *App) initServers(s *newState) error {
 			a.mqttWakeDispatch,
 		))
 
+		// Command wake topic: Home Assistant automations publish a
+		// JSON prompt and the agent runs it as a request/reply loop.
+		if cfg.MQTT.CommandWake.Enabled {
+			mqttPub.SetWakeCommandHandler(newMQTTCommandHandler(taskExecDeps{
+				launch:   a.loopRegistry.Launch,
+				runner:   &loopAdapter{agentLoop: a.loop, router: a.rtr, capSurface: a.capSurfaceGetter()},
+				eventBus: a.eventBus,
+				logger:   logger,
+			}))
+			logger.Info("MQTT command wake topic enabled",
+				"topic", mqttPub.CommandWakeTopic(),
+				"debounce", cfg.MQTT.CommandWake.Debounce,
+				"publish_result", cfg.MQTT.CommandWake.PublishResult,
+			)
+		}
+
+		// Manual reflection button. Always published so an
+		// unavailable button explains itself in HA.
+		reflectBtn := reflectionButton(cfg, a.requestReflection)
+		mqttPub.RegisterButton(reflectBtn)
+		if reflectBtn.UnavailableReason != "" {
+			logger.Info("MQTT reflection button published as unavailable",
+				"reason", reflectBtn.UnavailableReason,
+			)
+		}
+
+		// Live compaction tuning from HA number entities.
+		if a.compactor != nil {
+			registerCompactionNumbers(mqttPub, a.compactor)
+		}
+
+		// Runtime default-model switch from an HA select entity.
+		registerDefaultModelSelect(mqttPub, a.loop, cfg.Models)
+
 		// Register MQTT wake subscription tools via the provider.
 		// loopRegistry doubles as the LoopResolver so wake_loop
 		// arguments are verified against live loops at add time.
@@ -528,6 +641,8 @@ func (a *App) initServers(s *newState) error {
 		}
 
 		a.mqttPub.RegisterSensors(telBuilder.StaticSensors())
+		// Per-loop sensors register as loops are first seen.
+		a.mqttPub.PreserveSensorPrefix(telemetry.LoopSensorPrefix)
 
 		dbPaths := map[string]string{
 			"main":  filepath.Join(cfg.DataDir, "thane.db"),
@@ -668,5 +783,39 @@ func (a *App) initServers(s *newState) error {
 		a.deferWorker("mqtt-connect", mqttConnectWorker)
 	}
 
+	// --- Tool follow-ups ---
+	// Declared here, after the last global tool registration, so every
+	// configured tool name can resolve. Unknown names warn rather than
+	// fail: a tool may belong to an integration that is not configured.
+	for name, fu := range cfg.Agent.FollowUps {
+		if err := a.loop.Tools().DeclareFollowUp(name, tools.FollowUp{Window: fu.Window, Expect: fu.Expect, Check: fu.Check}); err != nil {
+			logger.Warn("tool follow-up not declared", "tool", name, "error", err)
+			continue
+		}
+		logger.Info("tool follow-up declared", "tool", name, "window", fu.Window)
+	}
+
+	// --- Tool rate limits ---
+	a.applyToolRateLimits(cfg.Agent.ToolRateLimits)
+
 	return nil
 }
+
+// applyToolRateLimits installs limits on the shared registry, removing
+// any limit the map no longer names. They are held there, so they apply
+// across every run and to tools (MCP, companion) that register after
+// startup. Called at startup and again on config reload.
+func (a *App) applyToolRateLimits(limits map[string]config.ToolRateLimitConfig) {
+	reg := a.loop.Tools()
+	for name := range reg.ToolRateLimits() {
+		if _, ok := limits[name]; !ok {
+			reg.SetToolRateLimit(name, 0, 0)
+		}
+	}
+	for name, limit := range limits {
+		reg.SetToolRateLimit(name, limit.Calls, limit.Per)
+		if limit.Calls > 0 {
+			a.logger.Debug("tool rate limit set", "tool", name, "calls", limit.Calls, "per", limit.Per)
+		}
+	}
+}
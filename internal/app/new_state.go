@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/nugget/thane-ai-agent/internal/integrations/forge"
+	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
 	"github.com/nugget/thane-ai-agent/internal/model/talents"
 	"github.com/nugget/thane-ai-agent/internal/platform/paths"
 	"github.com/nugget/thane-ai-agent/internal/state/contacts"
@@ -38,4 +39,8 @@ type newState struct {
 
 	// Built in initChannels, used by initDelegation.
 	forgeOpLog *forge.OperationLog
+
+	// Built in initAwareness, used by initServers to feed sensor
+	// webhook pushes into the same window as HA state changes.
+	stateWindow *homeassistant.StateWindowProvider
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
 	"github.com/nugget/thane-ai-agent/internal/model/fleet"
 	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/model/prompts"
 	"github.com/nugget/thane-ai-agent/internal/model/router"
 	"github.com/nugget/thane-ai-agent/internal/platform/database"
 	"github.com/nugget/thane-ai-agent/internal/platform/events"
@@ -26,6 +28,21 @@ import (
 const (
 	modelInventoryRefreshInterval  = 5 * time.Minute
 	modelExperiencePersistInterval = 5 * time.Second
+
+	// archiveEmbedInterval and archiveEmbedBatch pace the semantic
+	// archive backfill: at most archiveEmbedBatch messages per pass, so
+	// a large history trickles through the embedding model rather than
+	// monopolizing it.
+	archiveEmbedInterval = time.Minute
+	archiveEmbedBatch    = 100
+
+	// factExpirySweepInterval is how often the fact_expiry_sweep task
+	// retires expired facts from the knowledge store.
+	factExpirySweepInterval = time.Hour
+
+	// archivePruneInterval is how often the archive_prune task applies
+	// the archive retention policy.
+	archivePruneInterval = 24 * time.Hour
 )
 
 // initStores creates data stores, background infrastructure, and the
@@ -54,6 +71,19 @@ func (a *App) initStores(s *newState) error {
 	a.initMessageInfrastructure(logger)
 	a.initDatasetSinks()
 
+	// --- WAL checkpointing ---
+	// SQLite never shrinks a WAL file on its own, so each file-backed
+	// database registers here as it opens and is checkpointed and
+	// truncated periodically. Sizes are reported on /health.
+	a.walCheckpointer = database.NewWALCheckpointer(logger.With("component", "wal_checkpoint"))
+	if a.indexDB != nil {
+		a.walCheckpointer.Add("logs", filepath.Join(cfg.Logging.RootPath(), "logs.db"), a.indexDB)
+	}
+	a.deferWorker("wal-checkpoint", func(ctx context.Context) error {
+		go a.walCheckpointer.Run(ctx, cfg.Database.WALCheckpointInterval)
+		return nil
+	})
+
 	// --- Loop registry ---
 	// Tracks all persistent background loops (metacognitive, pollers,
 	// watchers). Created early so component init blocks can register
@@ -105,15 +135,22 @@ func (a *App) initStores(s *newState) error {
 
 	// --- Memory store ---
 	// SQLite-backed conversation memory. Persists across restarts so the
-	// agent can resume in-progress conversations.
+	// agent can resume in-progress conversations. A corrupt file is moved
+	// aside and salvaged; an unopenable one degrades every store sharing
+	// this connection to in-memory (reported on /health) rather than
+	// keeping the server down.
 	dbPath := cfg.DataDir + "/thane.db"
-	mem, err := memory.NewSQLiteStoreWithLogger(dbPath, 100, logger.With("component", "memory_store"))
+	mem, memStatus, err := openMemoryStore(dbPath, logger.With("component", "memory_store"))
 	if err != nil {
-		return fmt.Errorf("open memory database %s: %w", dbPath, err)
+		return err
 	}
 	a.mem = mem
+	a.memStatus = memStatus
 	a.onCloseErr("memory", mem.Close)
-	logger.Info("memory database opened", "path", dbPath)
+	if memStatus.Fallback == "" {
+		a.walCheckpointer.Add("main", dbPath, mem.DB())
+		logger.Info("memory database opened", "path", dbPath)
+	}
 
 	// --- Entity watchlist store ---
 	// Constructed early so later init phases (initChannels →
@@ -165,7 +202,14 @@ func (a *App) initStores(s *newState) error {
 		if err := a.haWS.Subscribe(s.ctx, "state_changed"); err != nil {
 			logger.Warn("failed to record HA state_changed subscription intent", "error", err)
 		}
+		if err := a.haWS.SubscribeRegistryUpdates(s.ctx); err != nil {
+			logger.Warn("failed to record HA registry update subscription intent", "error", err)
+		}
 		logger.Debug("Home Assistant configured", "url", cfg.HomeAssistant.URL)
+
+		if err := a.initHAInstances(s.ctx); err != nil {
+			return err
+		}
 	} else {
 		logger.Warn("Home Assistant not configured - tools will be limited")
 	}
@@ -258,7 +302,7 @@ func (a *App) initStores(s *newState) error {
 				if s.personTracker != nil {
 					initCtx, initCancel := context.WithTimeout(context.Background(), 10*time.Second)
 					defer initCancel()
-					if err := s.personTracker.Initialize(initCtx, a.ha); err != nil {
+					if err := s.personTracker.Initialize(initCtx, a.haPresenceSource()); err != nil {
 						logger.Warn("person tracker initialization incomplete", "error", err)
 					} else {
 						logger.Info("person tracker initialized")
@@ -268,6 +312,7 @@ func (a *App) initStores(s *newState) error {
 			Logger: logger,
 		})
 		a.ha.SetWatcher(haWatcher)
+		a.watchHAInstances(s, connMgr)
 	}
 
 	for _, res := range a.modelCatalog.Resources {
@@ -291,6 +336,10 @@ func (a *App) initStores(s *newState) error {
 			Logger:  logger.With("resource", res.ID, "provider", res.Provider),
 		})
 		c.AttachWatcher(resourceWatcher)
+		if a.resourceWatchers == nil {
+			a.resourceWatchers = make(map[string]*connwatch.Watcher)
+		}
+		a.resourceWatchers[res.ID] = resourceWatcher
 	}
 
 	if a.modelRuntime != nil && a.modelRuntime.InventoryClientCount() > 0 {
@@ -333,15 +382,30 @@ func (a *App) initStores(s *newState) error {
 	a.wmStore = wmStore
 	logger.Info("working memory store initialized")
 
+	// --- Conversation ego ---
+	// Per-conversation self-notes that refine the global ego.md for a
+	// single thread.
+	convEgoStore, err := memory.NewConversationEgoStore(mem.DB())
+	if err != nil {
+		return fmt.Errorf("create conversation ego store: %w", err)
+	}
+	a.convEgoStore = convEgoStore
+
 	archiveAdapter := memory.NewArchiveAdapter(archiveStore, mem, mem, logger)
 	a.archiveAdapter = archiveAdapter
+	if cfg.Archive.IncrementalEnabled() {
+		mem.SetSessionResolver(archiveAdapter.ActiveSessionID)
+		logger.Info("incremental archiving enabled")
+	}
 
 	// --- Model router ---
 	// Selects the best model for each request based on complexity, cost,
 	// and capability requirements. Falls back to the default model.
 	routerCfg := a.modelRegistry.Catalog().RouterConfig(1000)
 	rtr := router.NewRouter(logger, routerCfg)
+	rtr.SetResourceReady(a.modelResourceReady)
 	a.rtr = rtr
+	a.configureFallbackChains(a.cfg.Models.FallbackChains)
 	logger.Info("model router initialized",
 		"models", len(routerCfg.Models),
 		"default", routerCfg.DefaultModel,
@@ -387,6 +451,19 @@ func (a *App) initStores(s *newState) error {
 	compactor.SetWorkingMemoryStore(wmStore)
 	a.compactor = compactor
 
+	// --- Prompt templates ---
+	// Overrides replace built-in prompts so they can be tuned without a
+	// rebuild. Every override is validated here; a malformed one fails
+	// startup rather than the first call that renders it.
+	promptRegistry, err := prompts.LoadRegistry(cfg.Prompts.OverrideDir)
+	if err != nil {
+		return fmt.Errorf("load prompt templates: %w", err)
+	}
+	for _, t := range promptRegistry.Overrides() {
+		logger.Info("prompt template overridden", "template", t.Name, "version", t.Version, "path", t.Source)
+	}
+	a.prompts = promptRegistry
+
 	// --- Session metadata summarizer ---
 	// Background worker that generates titles, tags, and summaries for
 	// sessions that ended without metadata (e.g., during shutdown).
@@ -404,6 +481,7 @@ func (a *App) initStores(s *newState) error {
 		IdleTimeout:     time.Duration(idleTimeoutMinutes) * time.Minute,
 	}
 	summaryWorker := memory.NewSummarizerWorker(archiveStore, a.llmClient, rtr, logger, summarizerCfg)
+	summaryWorker.SetPrompts(a.prompts)
 	a.summaryWorker = summaryWorker
 
 	// --- Scheduler ---
@@ -414,6 +492,7 @@ func (a *App) initStores(s *newState) error {
 		return fmt.Errorf("open scheduler database: %w", err)
 	}
 	a.onCloseErr("scheduler-db", schedDB.Close)
+	a.walCheckpointer.Add("scheduler", cfg.DataDir+"/scheduler.db", schedDB)
 	schedStore, err := scheduler.NewStore(schedDB, logger)
 	if err != nil {
 		return fmt.Errorf("initialize scheduler store: %w", err)
@@ -500,6 +579,7 @@ func (a *App) initStores(s *newState) error {
 	if err := a.initLoopUsageStores(mem.DB(), logger); err != nil {
 		return err
 	}
+	a.configureRouterBudget(a.cfg.Models.Budget)
 
 	// Task execution dependencies. The runner reads a.loop at call time
 	// (not capture time) so it sees the loop constructed by initAgentLoop.
@@ -507,6 +587,23 @@ func (a *App) initStores(s *newState) error {
 	deps.launch = a.loopRegistry.Launch
 	deps.logger = logger
 	deps.eventBus = a.eventBus
+	usageWatcher := a.newUsageWatcher(logger)
+	if usageWatcher != nil {
+		deps.usageCheck = usageWatcher.Check
+	}
+	deps.maintenance = func(target string) (func(context.Context) error, bool) {
+		job, ok := a.maintenanceJobs[target]
+		return job, ok
+	}
+	if a.ha != nil {
+		deps.followUpCheck = func(ctx context.Context, template string) (bool, error) {
+			rendered, err := a.ha.RenderTemplate(ctx, template)
+			if err != nil {
+				return false, err
+			}
+			return templateTrue(rendered), nil
+		}
+	}
 
 	executeTask := func(ctx context.Context, task *scheduler.Task, exec *scheduler.Execution) error {
 		deps.runner = &loopAdapter{agentLoop: a.loop, router: a.rtr, capSurface: a.capSurfaceGetter()}
@@ -514,7 +611,40 @@ func (a *App) initStores(s *newState) error {
 	}
 
 	sched := scheduler.New(logger, schedStore, executeTask)
+	if cfg.Timezone != "" {
+		loc, _ := time.LoadLocation(cfg.Timezone) // already validated
+		sched.SetLocation(loc)
+	}
 	a.sched = sched
+	a.syncUsageAlertTask(usageWatcher != nil, logger)
+
+	// Archive retention: prune ended sessions past the configured age or
+	// message cap once a day. Sessions tagged "keep" are never pruned.
+	if cfg.Archive.PruneEnabled() {
+		policy := memory.PrunePolicy{
+			MaxAge:      time.Duration(cfg.Archive.RetentionDays) * 24 * time.Hour,
+			MaxMessages: cfg.Archive.MaxMessages,
+		}
+		a.registerMaintenanceTask(archivePruneTaskName, archivePruneInterval, func(context.Context) error {
+			report, err := a.archiveStore.Prune(policy)
+			if err != nil {
+				return err
+			}
+			if len(report.Sessions) > 0 {
+				logger.Info("archive pruned",
+					"sessions", len(report.Sessions),
+					"messages", report.Messages,
+				)
+			}
+			return nil
+		}, logger)
+		logger.Info("archive retention enabled",
+			"retention_days", cfg.Archive.RetentionDays,
+			"max_messages", cfg.Archive.MaxMessages,
+		)
+	} else {
+		a.unregisterMaintenanceTask(archivePruneTaskName, logger)
+	}
 	a.deferWorker("scheduler", func(ctx context.Context) error {
 		if err := sched.Start(ctx); err != nil {
 			return fmt.Errorf("start scheduler: %w", err)
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nugget/thane-ai-agent/internal/channels/messages"
+	"github.com/nugget/thane-ai-agent/internal/channels/mqtt"
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+	"github.com/nugget/thane-ai-agent/internal/runtime/ego"
+)
+
+// reflectButtonSuffix is the MQTT entity suffix of the manual
+// reflection button (button.<device>_reflect in Home Assistant).
+const reflectButtonSuffix = "reflect"
+
+// reflectButtonMessage is the wake message the ego loop sees when the
+// operator presses the reflection button.
+const reflectButtonMessage = "The operator pressed the Reflect Now button in Home Assistant. Run a reflection pass now instead of waiting for your next scheduled iteration."
+
+// reflectionButton builds the Home Assistant button that forces a
+// self-reflection pass. Self-reflection is the ego service loop (it
+// replaced the periodic_reflection scheduled task), so a press wakes
+// that loop immediately. When a prerequisite is missing the button is
+// still published, but unavailable with the reason attached, so the
+// operator can see in HA why it does not work.
+func reflectionButton(cfg *config.Config, press mqtt.ButtonPressHandler) mqtt.Button {
+	b := mqtt.Button{
+		EntitySuffix: reflectButtonSuffix,
+		Name:         "Reflect Now",
+		Icon:         "mdi:head-lightbulb-outline",
+	}
+	switch {
+	case cfg.Workspace.Path == "":
+		b.UnavailableReason = "workspace.path is not configured, so the ego loop has no core/ego.md to reflect into"
+	case !cfg.Ego.Enabled:
+		b.UnavailableReason = "the ego loop is disabled (ego.enabled is false)"
+	default:
+		b.Press = press
+	}
+	return b
+}
+
+// requestReflection wakes the ego loop through the message bus with a
+// manual reflection request.
+func (a *App) requestReflection(ctx context.Context) error {
+	if a.messageBus == nil {
+		return fmt.Errorf("message bus is not configured")
+	}
+	_, err := a.messageBus.Send(ctx, messages.Envelope{
+		From: messages.Identity{Kind: messages.IdentitySystem, Name: "mqtt_button"},
+		To: messages.Destination{
+			Kind:     messages.DestinationLoop,
+			Target:   ego.DefinitionName,
+			Selector: messages.SelectorName,
+		},
+		Type:  messages.TypeSignal,
+		Scope: []string{"loop_wake"},
+		Payload: messages.LoopNotifyPayload{
+			Kind:    "loop_wake",
+			Message: reflectButtonMessage,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("wake %s loop: %w", ego.DefinitionName, err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+)
+
+func TestReflectionButton_Availability(t *testing.T) {
+	press := func(context.Context) error { return nil }
+	tests := []struct {
+		name          string
+		workspace     string
+		egoEnabled    bool
+		wantAvailable bool
+	}{
+		{name: "ready", workspace: "/srv/thane", egoEnabled: true, wantAvailable: true},
+		{name: "no workspace", egoEnabled: true},
+		{name: "ego disabled", workspace: "/srv/thane"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Workspace.Path = tt.workspace
+			cfg.Ego.Enabled = tt.egoEnabled
+
+			b := reflectionButton(cfg, press)
+			if b.EntitySuffix != reflectButtonSuffix {
+				t.Errorf("EntitySuffix = %q", b.EntitySuffix)
+			}
+			if available := b.UnavailableReason == ""; available != tt.wantAvailable {
+				t.Errorf("available = %v (reason %q), want %v", available, b.UnavailableReason, tt.wantAvailable)
+			}
+			if (b.Press != nil) != tt.wantAvailable {
+				t.Errorf("Press set = %v, want %v", b.Press != nil, tt.wantAvailable)
+			}
+		})
+	}
+}
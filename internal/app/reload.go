@@ -0,0 +1,234 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/nugget/thane-ai-agent/internal/model/fleet"
+	"github.com/nugget/thane-ai-agent/internal/model/talents"
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+	"github.com/nugget/thane-ai-agent/internal/runtime/agent"
+)
+
+// ReloadResult describes what an [App.Reload] did.
+type ReloadResult struct {
+	// Changed lists the config keys that differ from the running
+	// config, as reported by [config.Diff].
+	Changed []string `json:"changed"`
+
+	// Applied names what took effect in place: config keys such as
+	// "pricing", plus "talents" and "persona" when their file content
+	// changed.
+	Applied []string `json:"applied"`
+
+	// Ignored lists changed config keys that need a restart.
+	Ignored []string `json:"ignored"`
+}
+
+// Config keys whose changes [App.Reload] applies in place. Everything
+// else under models is applied as a catalog swap unless it touches the
+// provider resources, whose clients are built once at startup.
+const (
+	reloadKeyLogLevel       = "logging.level"
+	reloadKeyStdoutLogLevel = "logging.stdout.level"
+	reloadKeyPricing        = "pricing"
+	reloadKeyCapabilityTags = "capability_tags"
+	reloadKeyToolRateLimits = "agent.tool_rate_limits"
+	reloadKeyTalentsDir     = "talents_dir"
+	reloadKeyBudgetPrefix   = "models.budget."
+	reloadKeyModelsPrefix   = "models."
+)
+
+// restartOnlyModelKeys are the models settings that determine the
+// provider clients and so cannot change without a restart.
+var restartOnlyModelKeys = map[string]bool{
+	"models.resources":  true,
+	"models.ollama_url": true,
+}
+
+// Reload applies the hot-reloadable subset of next, a freshly loaded
+// and validated config, to the running app. base is the model catalog
+// built from next. The reloadable subset is:
+//
+//   - logging.level and logging.stdout.level
+//   - pricing
+//   - the spend ceilings under models.budget
+//   - the model list under models (router deployments, default and
+//     recovery models, local_first, fallback_chains), as long as
+//     models.resources and models.ollama_url are unchanged
+//   - capability_tags, when tagging was enabled at startup
+//   - agent.tool_rate_limits
+//   - talent content, re-read from talents_dir on every reload
+//   - persona content, which is read fresh on every turn anyway; a
+//     change regenerates the persona-voiced greeting cache
+//
+// Every other changed key is logged as ignored and keeps its running
+// value until the next restart. Reload always logs a summary of what
+// changed and returns it.
+func (a *App) Reload(ctx context.Context, next *config.Config, base *fleet.Catalog) ReloadResult {
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+
+	logger := a.logger
+	if a.reloadBase == nil {
+		a.reloadBase = a.cfg
+	}
+	// applied accumulates the running config as it now stands, so the
+	// next reload diffs against what is actually in effect and keeps
+	// reporting ignored keys until a restart picks them up.
+	current := a.reloadBase
+	applied := *current
+
+	result := ReloadResult{Changed: config.Diff(current, next)}
+	capTagsChanged := false
+	budgetChanged := false
+	modelsChanged := false
+	for _, key := range result.Changed {
+		switch {
+		case key == reloadKeyLogLevel || key == reloadKeyStdoutLogLevel:
+			applied.Logging.Level = next.Logging.Level
+			applied.Logging.Stdout.Level = next.Logging.Stdout.Level
+			result.Applied = append(result.Applied, key)
+		case key == reloadKeyPricing:
+			a.loop.SetPricing(next.Pricing)
+			if a.server != nil {
+				a.server.SetPricing(next.Pricing)
+			}
+			applied.Pricing = next.Pricing
+			result.Applied = append(result.Applied, key)
+		case key == reloadKeyCapabilityTags && a.tagCtxAssembler != nil:
+			capTagsChanged = true
+		case key == reloadKeyCapabilityTags:
+			// Tagging was off at startup, so there is no assembler or
+			// capability tool wiring to update.
+			result.Ignored = append(result.Ignored, key)
+		case key == reloadKeyToolRateLimits:
+			a.applyToolRateLimits(next.Agent.ToolRateLimits)
+			applied.Agent.ToolRateLimits = next.Agent.ToolRateLimits
+			result.Applied = append(result.Applied, key)
+		case key == reloadKeyTalentsDir:
+			// Applied below, once the talents load from the new dir.
+		case strings.HasPrefix(key, reloadKeyBudgetPrefix):
+			budgetChanged = true
+			applied.Models.Budget = next.Models.Budget
+			result.Applied = append(result.Applied, key)
+		case strings.HasPrefix(key, reloadKeyModelsPrefix) && !restartOnlyModelKeys[key]:
+			modelsChanged = true
+		default:
+			result.Ignored = append(result.Ignored, key)
+		}
+	}
+
+	if a.logLevel != nil {
+		level, _ := config.ParseLogLevel(applied.Logging.Level)
+		stdoutLevel, _ := config.ParseLogLevel(applied.Logging.StdoutLevelValue())
+		a.logLevel.Set(level)
+		a.stdoutLogLevel.Set(stdoutLevel)
+	}
+
+	if budgetChanged {
+		a.configureRouterBudget(next.Models.Budget)
+	}
+
+	if modelsChanged {
+		if err := a.reloadModels(base); err != nil {
+			logger.Warn("model list reload failed; keeping current models", "error", err)
+			for _, key := range result.Changed {
+				if strings.HasPrefix(key, reloadKeyModelsPrefix) && !strings.HasPrefix(key, reloadKeyBudgetPrefix) && !restartOnlyModelKeys[key] {
+					result.Ignored = append(result.Ignored, key)
+				}
+			}
+		} else {
+			resources, ollamaURL := applied.Models.Resources, applied.Models.OllamaURL
+			applied.Models = next.Models
+			applied.Models.Resources, applied.Models.OllamaURL = resources, ollamaURL
+			result.Applied = append(result.Applied, "models")
+		}
+	}
+
+	talentsChanged, err := a.reloadTalents(ctx, next.TalentsDir)
+	if err != nil {
+		logger.Warn("talent reload failed; keeping current talents", "dir", next.TalentsDir, "error", err)
+	} else {
+		if applied.TalentsDir != next.TalentsDir {
+			applied.TalentsDir = next.TalentsDir
+			result.Applied = append(result.Applied, reloadKeyTalentsDir)
+		}
+		if talentsChanged {
+			result.Applied = append(result.Applied, "talents")
+		}
+	}
+
+	if (capTagsChanged || talentsChanged) && a.tagCtxAssembler != nil {
+		tagCfg := current.CapabilityTags
+		if capTagsChanged {
+			tagCfg = next.CapabilityTags
+		}
+		if resolved := resolveCapabilityTags(a.loop.Tools(), tagCfg); len(resolved.Configs) > 0 {
+			a.applyCapabilityTags(resolved, a.talents)
+			if capTagsChanged {
+				applied.CapabilityTags = next.CapabilityTags
+				result.Applied = append(result.Applied, reloadKeyCapabilityTags)
+			}
+		} else if capTagsChanged {
+			// Turning tagging off entirely unwinds state wired at
+			// startup; leave that to a restart.
+			result.Ignored = append(result.Ignored, reloadKeyCapabilityTags)
+		}
+	}
+
+	if a.loop.RefreshPersona(ctx) {
+		result.Applied = append(result.Applied, "persona")
+	}
+
+	a.reloadBase = &applied
+
+	for _, key := range result.Ignored {
+		logger.Warn("config change ignored — restart required", "key", key)
+	}
+	logger.Info("config reloaded",
+		"changed", result.Changed,
+		"applied", result.Applied,
+		"ignored", result.Ignored,
+	)
+	return result
+}
+
+// reloadModels swaps in the new base model catalog and resyncs the
+// router from the resulting effective catalog.
+func (a *App) reloadModels(base *fleet.Catalog) error {
+	if a.modelRuntime == nil {
+		return fmt.Errorf("no model runtime")
+	}
+	if err := a.modelRuntime.ReplaceBaseCatalog(base); err != nil {
+		return err
+	}
+	a.syncRouterConfig()
+	return nil
+}
+
+// reloadTalents re-reads talents from dir and, when their content
+// differs from the running set, installs them on the loop. Capability
+// wiring that embeds talents is refreshed by the caller.
+func (a *App) reloadTalents(ctx context.Context, dir string) (bool, error) {
+	var verifier talents.VerifyPathFunc
+	if a.documentStore != nil {
+		verifier = a.documentStore.VerifyPath
+	}
+	loaded, err := a.loadTalents(ctx, dir, verifier)
+	if err != nil {
+		return false, err
+	}
+	if reflect.DeepEqual(loaded, a.talents) {
+		return false, nil
+	}
+	a.talents = loaded
+	if a.tagCtxAssembler == nil {
+		a.loop.ConfigureCapabilityWiring(agent.CapabilityWiring{
+			ParsedTalents: append([]talents.Talent{}, loaded...),
+		})
+	}
+	return true, nil
+}
@@ -1,5 +1,12 @@
 package app
 
+import (
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/router"
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+)
+
 // syncRouterConfig refreshes the live router from the current effective
 // model registry catalog. It is safe to call repeatedly; audit history
 // and stats stay intact while the model list/default are swapped.
@@ -13,3 +20,66 @@ func (a *App) syncRouterConfig() {
 	}
 	a.rtr.UpdateConfig(cat.RouterConfig(0))
 }
+
+// configureRouterBudget installs the spend ceilings from cfg on the
+// router, measuring spend from the usage store in the configured
+// timezone. A config with no ceilings clears any budget in effect.
+func (a *App) configureRouterBudget(cfg config.ModelBudgetConfig) {
+	if a == nil || a.rtr == nil {
+		return
+	}
+	if !cfg.Enabled() || a.usageStore == nil {
+		a.rtr.SetBudget(router.Budget{})
+		return
+	}
+
+	var loc *time.Location
+	if a.cfg.Timezone != "" {
+		loc, _ = time.LoadLocation(a.cfg.Timezone) // already validated
+	}
+	var margin float64
+	if cfg.Margin != nil {
+		margin = *cfg.Margin
+	}
+	store := a.usageStore
+	a.rtr.SetBudget(router.Budget{
+		DailyUSD:   cfg.DailyUSD,
+		MonthlyUSD: cfg.MonthlyUSD,
+		Margin:     margin,
+		Location:   loc,
+		Spend: func(start, end time.Time) (float64, error) {
+			summary, err := store.Summary(start, end)
+			if err != nil {
+				return 0, err
+			}
+			return summary.TotalCostUSD, nil
+		},
+	})
+	a.logger.Info("model spend budget enabled",
+		"daily_usd", cfg.DailyUSD,
+		"monthly_usd", cfg.MonthlyUSD,
+		"margin", margin,
+	)
+}
+
+// configureFallbackChains installs the model fallback chains from
+// config on the router. The loop walks a model's chain when a call to
+// it fails, skipping models on resources [App.modelResourceReady]
+// reports as down.
+func (a *App) configureFallbackChains(chains map[string][]string) {
+	if a == nil || a.rtr == nil {
+		return
+	}
+	a.rtr.SetFallbackChains(chains)
+	if len(chains) > 0 {
+		a.logger.Info("model fallback chains configured", "chains", len(chains))
+	}
+}
+
+// modelResourceReady reports whether the model resource with the given
+// ID is reachable according to its connection watcher. Resources
+// without a watcher are assumed reachable.
+func (a *App) modelResourceReady(resourceID string) bool {
+	w, ok := a.resourceWatchers[resourceID]
+	return !ok || w.IsReady()
+}
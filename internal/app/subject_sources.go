@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/agentctx"
+	looppkg "github.com/nugget/thane-ai-agent/internal/runtime/loop"
+	"github.com/nugget/thane-ai-agent/internal/state/awareness"
+	"github.com/nugget/thane-ai-agent/internal/state/contacts"
+	"github.com/nugget/thane-ai-agent/internal/state/knowledge"
+)
+
+// watchlistSubjects links facts to the entities the always-visible
+// watchlist renders this turn, so facts about a watched entity arrive
+// alongside its live state. Glob and registry-target subscriptions are
+// skipped: they expand to entity sets, not a single linkable subject.
+func watchlistSubjects(store *awareness.WatchlistStore, logger *slog.Logger) knowledge.SubjectSource {
+	return func(_ context.Context, req agentctx.ContextRequest) []string {
+		rows, err := store.ListOwner(looppkg.CoreLoopName)
+		if err != nil {
+			logger.Warn("watchlist subjects unavailable", "error", err)
+			return nil
+		}
+		var subjects []string
+		for _, row := range rows {
+			if !row.RendersState() || !row.GateOpen(req.ActiveTags) {
+				continue
+			}
+			if awareness.ParseSubscriptionTarget(row.EntityID).Kind != awareness.TargetEntity {
+				continue
+			}
+			subjects = append(subjects, "entity:"+row.EntityID)
+		}
+		return subjects
+	}
+}
+
+// personSubjects links facts to the tracked household members whose
+// presence is injected every turn. Each person contributes their
+// entity subject and, when a contact is linked to the person entity
+// (contact_save's ha_person), that contact's subject too, since facts
+// about people are usually keyed to their contact.
+func personSubjects(tracker *contacts.PresenceTracker, store *contacts.Store, logger *slog.Logger) knowledge.SubjectSource {
+	return func(context.Context, agentctx.ContextRequest) []string {
+		ids := tracker.EntityIDs()
+		subjects := make([]string, 0, 2*len(ids))
+		for _, id := range ids {
+			subjects = append(subjects, "entity:"+id)
+		}
+		if store == nil {
+			return subjects
+		}
+		linked, err := store.ContactsForPersonEntities(ids)
+		if err != nil {
+			logger.Warn("person contact subjects unavailable", "error", err)
+			return subjects
+		}
+		for _, id := range ids {
+			if contactID, ok := linked[id]; ok {
+				subjects = append(subjects, "contact:"+contactID.String())
+			}
+		}
+		return subjects
+	}
+}
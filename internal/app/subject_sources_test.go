@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/runtime/agentctx"
+	"github.com/nugget/thane-ai-agent/internal/state/contacts"
+)
+
+func TestPersonSubjects_IncludesLinkedContacts(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db, err := database.Open(filepath.Join(t.TempDir(), "contacts.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := contacts.NewStore(db, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, err := store.Upsert(&contacts.Contact{FormattedName: "Alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.LinkPersonEntity(alice.ID, "person.alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker := contacts.NewPresenceTracker([]string{"person.alice", "person.bob"}, "", logger)
+	got := personSubjects(tracker, store, logger)(context.Background(), agentctx.ContextRequest{})
+
+	want := []string{"entity:person.alice", "entity:person.bob", "contact:" + alice.ID.String()}
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("subjects = %v, want %v", got, want)
+	}
+}
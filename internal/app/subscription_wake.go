@@ -26,11 +26,15 @@ const subWakePartitionPrefix = "sub-wake:"
 // debounce asks are folded into the owner's partition registration at
 // index-build time (the twitchiest governs), so the match path
 // carries no timing state. Glob targets match at event time via the
-// shared glob primitive.
+// shared glob primitive. A watch with a condition is an alert: it
+// fires only on the change that makes the condition true, and at most
+// once per cooldown for each entity it matches.
 type wakeWatch struct {
-	owner  string
-	target string // entity id or glob (registry targets are rejected upstream)
-	isGlob bool
+	owner    string
+	target   string // entity id or glob (registry targets are rejected upstream)
+	isGlob   bool
+	when     *looppkg.WakeCondition
+	cooldown time.Duration
 }
 
 // subscriptionWakeFeeder implements the #1211 wake feed: state
@@ -58,6 +62,11 @@ type subscriptionWakeFeeder struct {
 
 	mu      sync.RWMutex
 	watches []wakeWatch
+
+	// alertMu guards lastAlert, the time each (owner, entity) pair
+	// last fired a conditional wake, for the per-subscription cooldown.
+	alertMu   sync.Mutex
+	lastAlert map[string]time.Time
 }
 
 func newSubscriptionWakeFeeder(
@@ -76,6 +85,7 @@ func newSubscriptionWakeFeeder(
 		loops:     loops,
 		translate: translate,
 		logger:    logger,
+		lastAlert: make(map[string]time.Time),
 	}
 	f.dispatch = newQueuedWakeDispatcher(queue, bus, subWakePartitionPrefix, "subscription_wake", decorateWakeEvent, logger)
 	return f
@@ -84,14 +94,19 @@ func newSubscriptionWakeFeeder(
 // decorateWakeEvent renders the wake payload's delivery-relative
 // summary: {entity, from, to, ago} in the class-aware vocabulary,
 // with ago computed at delivery time so a record that waited out a
-// debounce (or a crash) reports how stale it actually is.
+// debounce (or a crash) reports how stale it actually is. Alert wakes
+// also carry the condition that fired.
 func decorateWakeEvent(event *messages.LoopEventPayload) {
-	event.Summary = promptfmt.MarshalCompact(map[string]any{
+	summary := map[string]any{
 		"entity": event.Metadata["entity"],
 		"from":   event.Metadata["from"],
 		"to":     event.Metadata["to"],
 		"ago":    promptfmt.FormatDeltaOnly(event.ObservedAt, time.Now()),
-	})
+	}
+	if cond := event.Metadata["condition"]; cond != "" {
+		summary["condition"] = cond
+	}
+	event.Summary = promptfmt.MarshalCompact(summary)
 }
 
 // Rebuild recompiles the wake index from the subscription registry:
@@ -133,11 +148,19 @@ func (f *subscriptionWakeFeeder) Rebuild() {
 				"owner", owner, "entity_id", row.EntityID)
 			continue
 		}
-		watches = append(watches, wakeWatch{
+		w := wakeWatch{
 			owner:  owner,
 			target: row.EntityID,
 			isGlob: homeassistant.IsEntityGlob(row.EntityID),
-		})
+			when:   row.WakeWhen,
+		}
+		if w.when != nil {
+			w.cooldown = time.Duration(row.WakeCooldownSeconds) * time.Second
+			if w.cooldown <= 0 {
+				w.cooldown = looppkg.DefaultWakeCooldown
+			}
+		}
+		watches = append(watches, w)
 		effective := time.Duration(row.WakeDebounceSeconds) * time.Second
 		if effective <= 0 {
 			effective = f.defaultDebounce
@@ -179,7 +202,9 @@ func (f *subscriptionWakeFeeder) Rebuild() {
 // changes are guaranteed to arrive here). Each matching wake
 // subscription enqueues one entity-deduped record for its owner —
 // latest change wins while a wake is pending, and the partition's
-// debounced drain does the rest.
+// debounced drain does the rest. A subscription with a wake condition
+// enqueues only when the change trips it ([looppkg.WakeCondition.Fires])
+// and its cooldown has elapsed.
 func (f *subscriptionWakeFeeder) HandleStateChange(entityID, oldState, newState, deviceClass string) {
 	if oldState == newState {
 		return
@@ -214,6 +239,19 @@ func (f *subscriptionWakeFeeder) HandleStateChange(entityID, oldState, newState,
 			translated = true
 		}
 
+		var condition string
+		if w.when != nil {
+			if !w.when.Fires([]string{oldState, from}, []string{newState, to}) {
+				continue
+			}
+			if !f.claimAlert(w.owner, entityID, w.cooldown, now) {
+				f.logger.Debug("subscription wake alert suppressed by cooldown",
+					"owner", w.owner, "entity_id", entityID, "condition", w.when.String())
+				continue
+			}
+			condition = w.when.String()
+		}
+
 		event := messages.LoopEventPayload{
 			Source:     "subscription_wake",
 			Type:       "state_change",
@@ -226,6 +264,9 @@ func (f *subscriptionWakeFeeder) HandleStateChange(entityID, oldState, newState,
 				"to":     to,
 			},
 		}
+		if condition != "" {
+			event.Metadata["condition"] = condition
+		}
 		record := queuedWakeRecord{
 			Target: messages.LoopWakeTarget{Name: w.owner},
 			Event:  event,
@@ -238,6 +279,20 @@ func (f *subscriptionWakeFeeder) HandleStateChange(entityID, oldState, newState,
 	}
 }
 
+// claimAlert reports whether owner may be alerted about entityID now,
+// recording the alert when it may. An alert inside cooldown of the
+// previous one for the same pair is refused.
+func (f *subscriptionWakeFeeder) claimAlert(owner, entityID string, cooldown time.Duration, now time.Time) bool {
+	key := owner + "\x00" + entityID
+	f.alertMu.Lock()
+	defer f.alertMu.Unlock()
+	if last, ok := f.lastAlert[key]; ok && now.Sub(last) < cooldown {
+		return false
+	}
+	f.lastAlert[key] = now
+	return true
+}
+
 // Sweep drains sub-wake partitions left pending by a crash while
 // their debounce was armed. Call after the loop registry has hydrated
 // so targets resolve.
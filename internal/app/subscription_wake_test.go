@@ -241,3 +241,64 @@ func TestSubscriptionWakeDebounceDerivation(t *testing.T) {
 		t.Error("partition still registered after its wake subscriptions were removed")
 	}
 }
+
+// TestSubscriptionWakeAlertCondition covers alert wakes: only the
+// change that enters the condition fires, the payload names the
+// condition, and a flap back across the threshold inside the cooldown
+// stays quiet.
+func TestSubscriptionWakeAlertCondition(t *testing.T) {
+	bus, captured := captureBus()
+	f, store, _ := newTestWakeFeeder(t, bus)
+
+	limit := 30.0
+	if err := store.Upsert("freezer_watch", looppkg.EntitySubscription{
+		EntityID: "sensor.freezer_temp",
+		Wake:     true,
+		WakeWhen: &looppkg.WakeCondition{Above: &limit},
+	}); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if err := store.Upsert("door_watch", looppkg.EntitySubscription{
+		EntityID: "binary_sensor.garage_bay_3",
+		Wake:     true,
+		WakeWhen: &looppkg.WakeCondition{State: "open"},
+	}); err != nil {
+		t.Fatalf("upsert door: %v", err)
+	}
+	f.Rebuild()
+
+	// Below the threshold, and moving around while above it, never fire.
+	f.HandleStateChange("sensor.freezer_temp", "10", "20", "")
+	f.HandleStateChange("sensor.freezer_temp", "20", "31", "")
+	f.HandleStateChange("sensor.freezer_temp", "31", "33", "")
+	// Dropping back and crossing again inside the cooldown is suppressed.
+	f.HandleStateChange("sensor.freezer_temp", "33", "25", "")
+	f.HandleStateChange("sensor.freezer_temp", "25", "34", "")
+	// State conditions match the class-aware vocabulary.
+	f.HandleStateChange("binary_sensor.garage_bay_3", "off", "on", "garage_door")
+
+	waitFor(t, captured, 2, 2*time.Second)
+	time.Sleep(150 * time.Millisecond)
+	got := captured()
+	if len(got) != 2 {
+		t.Fatalf("delivered %d wakes, want one per alert", len(got))
+	}
+	summaries := make(map[string]string)
+	for _, env := range got {
+		payload := env.Payload.(messages.LoopNotifyPayload)
+		if len(payload.Events) != 1 {
+			t.Fatalf("%s got %d events, want 1", env.To.Target, len(payload.Events))
+		}
+		summaries[env.To.Target] = payload.Events[0].Summary
+	}
+	for _, want := range []string{`"to":"31"`, `"condition":"above 30"`} {
+		if !strings.Contains(summaries["freezer_watch"], want) {
+			t.Errorf("freezer summary = %q, missing %s", summaries["freezer_watch"], want)
+		}
+	}
+	for _, want := range []string{`"to":"open"`, `"condition":"state is open"`} {
+		if !strings.Contains(summaries["door_watch"], want) {
+			t.Errorf("door summary = %q, missing %s", summaries["door_watch"], want)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/nugget/thane-ai-agent/internal/platform/logging"
 	"github.com/nugget/thane-ai-agent/internal/platform/scheduler"
 	looppkg "github.com/nugget/thane-ai-agent/internal/runtime/loop"
+	"github.com/nugget/thane-ai-agent/internal/tools"
 )
 
 // taskExecDeps holds all dependencies needed by the scheduled task
@@ -23,11 +24,25 @@ type taskExecDeps struct {
 	runner   looppkg.Runner
 	eventBus *events.Bus
 	logger   *slog.Logger
+
+	// usageCheck runs [scheduler.PayloadUsageCheck] tasks. Nil when
+	// usage alerts are not configured.
+	usageCheck func(context.Context) error
+
+	// maintenance looks up the job a [scheduler.PayloadMaintenance]
+	// task runs by its target.
+	maintenance func(target string) (func(context.Context) error, bool)
+
+	// followUpCheck reports whether a tool follow-up's check template
+	// renders true. Nil when Home Assistant is not configured, which
+	// runs every follow-up wake.
+	followUpCheck func(ctx context.Context, template string) (bool, error)
 }
 
-// runScheduledTask handles execution of a scheduled task by compiling it
-// into a transient loop launch. Unsupported payload kinds are logged
-// and silently ignored (returning nil, not an error).
+// runScheduledTask handles execution of a scheduled task. Wake tasks
+// compile into a transient loop launch; usage checks and maintenance
+// jobs run directly, without a model call. Unsupported payload
+// kinds are logged and silently ignored (returning nil, not an error).
 func runScheduledTask(ctx context.Context, task *scheduler.Task, exec *scheduler.Execution, deps taskExecDeps) error {
 	log := deps.logger.With(
 		"subsystem", logging.SubsystemScheduler,
@@ -39,7 +54,35 @@ func runScheduledTask(ctx context.Context, task *scheduler.Task, exec *scheduler
 	log.Debug("task executing",
 		"payload_kind", task.Payload.Kind,
 	)
+	deps.eventBus.Publish(events.Event{
+		Timestamp: time.Now(),
+		Source:    events.SourceScheduler,
+		Kind:      events.KindTaskFired,
+		Data: map[string]any{
+			"task_id":      task.ID,
+			"task_name":    task.Name,
+			"execution_id": exec.ID,
+			"payload_kind": string(task.Payload.Kind),
+		},
+	})
 
+	if task.Payload.Kind == scheduler.PayloadUsageCheck {
+		if deps.usageCheck == nil {
+			return fmt.Errorf("scheduled task %q: usage alerts are not configured", task.Name)
+		}
+		return deps.usageCheck(ctx)
+	}
+	if task.Payload.Kind == scheduler.PayloadMaintenance {
+		var job func(context.Context) error
+		ok := false
+		if deps.maintenance != nil {
+			job, ok = deps.maintenance(task.Payload.Target)
+		}
+		if !ok {
+			return fmt.Errorf("scheduled task %q: no maintenance job %q", task.Name, task.Payload.Target)
+		}
+		return job(ctx)
+	}
 	if task.Payload.Kind != scheduler.PayloadWake {
 		deps.logger.Warn("unsupported task payload kind", "kind", task.Payload.Kind)
 		return nil
@@ -50,6 +93,9 @@ func runScheduledTask(ctx context.Context, task *scheduler.Task, exec *scheduler
 	if deps.runner == nil {
 		return fmt.Errorf("scheduled task %q: loop runner is not configured", task.Name)
 	}
+	if skipFollowUpWake(ctx, task, exec, deps) {
+		return nil
+	}
 
 	launch := buildScheduledTaskLaunch(ctx, task, exec)
 	result, err := deps.launch(ctx, launch, looppkg.Deps{
@@ -73,16 +119,61 @@ func runScheduledTask(ctx context.Context, task *scheduler.Task, exec *scheduler
 	return nil
 }
 
+// skipFollowUpWake evaluates a tool follow-up's check template and
+// reports whether the expected outcome already holds, in which case
+// the verification wake is not needed. A failed check runs the wake,
+// since verifying is the safe side.
+func skipFollowUpWake(ctx context.Context, task *scheduler.Task, exec *scheduler.Execution, deps taskExecDeps) bool {
+	check, _ := task.Payload.Data[tools.FollowUpCheckKey].(string)
+	if check == "" || deps.followUpCheck == nil {
+		return false
+	}
+	log := logging.Logger(ctx)
+	met, err := deps.followUpCheck(ctx, check)
+	if err != nil {
+		log.Warn("follow-up check failed; running verification wake", "error", err)
+		return false
+	}
+	if !met {
+		return false
+	}
+	log.Info("follow-up outcome confirmed; verification wake skipped")
+	exec.Result = "Follow-up outcome confirmed by its check; no wake needed."
+	return true
+}
+
+// templateTrue reports whether a rendered Home Assistant template reads
+// as true.
+func templateTrue(rendered string) bool {
+	switch strings.ToLower(strings.TrimSpace(rendered)) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
+
 // buildScheduledTaskLaunch compiles a persisted scheduler task and one
 // execution record into a loop launch with scheduler-specific
-// routing, metadata, and timeout inheritance.
+// routing, metadata, and timeout inheritance. A wake whose payload
+// names a conversation_id, such as a tool follow-up check, runs in that
+// conversation so the result lands where the action was taken; other
+// runs get a conversation of their own.
 func buildScheduledTaskLaunch(ctx context.Context, task *scheduler.Task, exec *scheduler.Execution) looppkg.Launch {
 	msg, _ := task.Payload.Data["message"].(string)
 	if msg == "" {
 		msg = "Scheduled wake: " + task.Name
 	}
+	if late, _ := task.Payload.Data["late"].(bool); late {
+		scheduledFor, _ := task.Payload.Data["scheduled_for"].(string)
+		msg = fmt.Sprintf("[Running late: this was scheduled for %s and missed while Thane was down. Acknowledge the delay where it matters.]\n\n%s", scheduledFor, msg)
+	}
 
 	profile := buildScheduledTaskLoopProfile(task)
+	convID, _ := task.Payload.Data["conversation_id"].(string)
+	if convID == "" {
+		convID = fmt.Sprintf("sched-%s-%s", task.ID, exec.ID)
+	}
 
 	launch := looppkg.Launch{
 		Spec: looppkg.Spec{
@@ -99,7 +190,7 @@ func buildScheduledTaskLaunch(ctx context.Context, task *scheduler.Task, exec *s
 			},
 		},
 		Task:           msg,
-		ConversationID: fmt.Sprintf("sched-%s-%s", task.ID, exec.ID),
+		ConversationID: convID,
 		Metadata: map[string]string{
 			"execution_id": exec.ID,
 		},
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/nugget/thane-ai-agent/internal/model/router"
 	"github.com/nugget/thane-ai-agent/internal/platform/scheduler"
 	looppkg "github.com/nugget/thane-ai-agent/internal/runtime/loop"
+	"github.com/nugget/thane-ai-agent/internal/tools"
 )
 
 type mockTaskLauncher struct {
@@ -162,6 +164,120 @@ func TestRunScheduledTask_DefaultMessage(t *testing.T) {
 	}
 }
 
+func TestRunScheduledTask_LateRunAnnotatesMessage(t *testing.T) {
+	launcher := &mockTaskLauncher{
+		result: looppkg.LaunchResult{Response: &looppkg.Response{Content: "ok"}},
+	}
+
+	task := &scheduler.Task{
+		ID:   "task-late",
+		Name: "reminder",
+		Payload: scheduler.Payload{
+			Kind: scheduler.PayloadWake,
+			Data: map[string]any{
+				"message":       "Remind the owner to call the vet.",
+				"late":          true,
+				"scheduled_for": "2026-03-10T15:00:00-05:00",
+			},
+		},
+	}
+
+	err := runScheduledTask(context.Background(), task, &scheduler.Execution{}, taskExecDeps{
+		launch: launcher.Launch,
+		runner: stubLoopRunner{},
+		logger: slog.Default(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := launcher.launch.Task
+	if !strings.Contains(got, "scheduled for 2026-03-10T15:00:00-05:00") || !strings.HasSuffix(got, "Remind the owner to call the vet.") {
+		t.Errorf("late message = %q, want a late notice followed by the original message", got)
+	}
+}
+
+func TestRunScheduledTask_FollowUpReturnsToConversation(t *testing.T) {
+	launcher := &mockTaskLauncher{
+		result: looppkg.LaunchResult{Response: &looppkg.Response{Content: "ok"}},
+	}
+
+	task := &scheduler.Task{
+		ID:   "task-fu",
+		Name: "follow_up:ha_call_service:1",
+		Payload: scheduler.Payload{
+			Kind: scheduler.PayloadWake,
+			Data: map[string]any{
+				"message":         "Follow-up check: verify the dishwasher finished.",
+				"follow_up_tool":  "ha_call_service",
+				"conversation_id": "signal-15551234567",
+			},
+		},
+	}
+
+	err := runScheduledTask(context.Background(), task, &scheduler.Execution{ID: "exec-fu"}, taskExecDeps{
+		launch: launcher.Launch,
+		runner: stubLoopRunner{},
+		logger: slog.Default(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if launcher.launch.ConversationID != "signal-15551234567" {
+		t.Errorf("ConversationID = %q, want the originating conversation", launcher.launch.ConversationID)
+	}
+}
+
+func TestRunScheduledTask_FollowUpCheckGatesWake(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		rendered string
+		err      error
+		wantWake bool
+	}{
+		{"outcome confirmed", "True", nil, false},
+		{"outcome missing", "False", nil, true},
+		{"check fails", "", errors.New("home assistant unreachable"), true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			launcher := &mockTaskLauncher{
+				result: looppkg.LaunchResult{Response: &looppkg.Response{Content: "ok"}},
+			}
+			task := &scheduler.Task{
+				ID:   "task-fu",
+				Name: "follow_up:start_dishwasher:1",
+				Payload: scheduler.Payload{
+					Kind: scheduler.PayloadWake,
+					Data: map[string]any{
+						"message":              "Follow-up check: verify the dishwasher finished.",
+						tools.FollowUpCheckKey: "{{ is_state('sensor.dishwasher', 'complete') }}",
+					},
+				},
+			}
+
+			var checked string
+			err := runScheduledTask(context.Background(), task, &scheduler.Execution{ID: "exec-fu"}, taskExecDeps{
+				launch: launcher.Launch,
+				runner: stubLoopRunner{},
+				logger: slog.Default(),
+				followUpCheck: func(_ context.Context, template string) (bool, error) {
+					checked = template
+					return templateTrue(tc.rendered), tc.err
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if checked == "" {
+				t.Error("follow-up check was not evaluated")
+			}
+			if woke := launcher.launch != nil; woke != tc.wantWake {
+				t.Errorf("wake launched = %v, want %v", woke, tc.wantWake)
+			}
+		})
+	}
+}
+
 func TestRunScheduledTask_NilData(t *testing.T) {
 	launcher := &mockTaskLauncher{
 		result: looppkg.LaunchResult{Response: &looppkg.Response{Content: "ok"}},
@@ -217,6 +333,73 @@ func TestRunScheduledTask_UnsupportedPayload(t *testing.T) {
 	}
 }
 
+func TestRunScheduledTask_UsageCheck(t *testing.T) {
+	launcher := &mockTaskLauncher{}
+	task := &scheduler.Task{
+		ID:      "task-usage",
+		Name:    usageAlertTaskName,
+		Payload: scheduler.Payload{Kind: scheduler.PayloadUsageCheck},
+	}
+
+	checks := 0
+	err := runScheduledTask(context.Background(), task, &scheduler.Execution{}, taskExecDeps{
+		launch:     launcher.Launch,
+		runner:     stubLoopRunner{},
+		logger:     slog.Default(),
+		usageCheck: func(context.Context) error { checks++; return nil },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checks != 1 {
+		t.Errorf("usage checks = %d, want 1", checks)
+	}
+	if launcher.launch != nil {
+		t.Error("usage check should not launch a loop")
+	}
+
+	err = runScheduledTask(context.Background(), task, &scheduler.Execution{}, taskExecDeps{logger: slog.Default()})
+	if err == nil {
+		t.Error("usage check without a watcher should fail")
+	}
+}
+
+func TestRunScheduledTask_Maintenance(t *testing.T) {
+	launcher := &mockTaskLauncher{}
+	task := &scheduler.Task{
+		ID:      "task-sweep",
+		Name:    factExpirySweepTaskName,
+		Payload: scheduler.Payload{Kind: scheduler.PayloadMaintenance, Target: factExpirySweepTaskName},
+	}
+
+	runs := 0
+	deps := taskExecDeps{
+		launch: launcher.Launch,
+		runner: stubLoopRunner{},
+		logger: slog.Default(),
+		maintenance: func(target string) (func(context.Context) error, bool) {
+			if target != factExpirySweepTaskName {
+				return nil, false
+			}
+			return func(context.Context) error { runs++; return nil }, true
+		},
+	}
+	if err := runScheduledTask(context.Background(), task, &scheduler.Execution{}, deps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs != 1 {
+		t.Errorf("maintenance runs = %d, want 1", runs)
+	}
+	if launcher.launch != nil {
+		t.Error("maintenance job should not launch a loop")
+	}
+
+	task.Payload.Target = "unknown_job"
+	if err := runScheduledTask(context.Background(), task, &scheduler.Execution{}, deps); err == nil {
+		t.Error("maintenance task without a registered job should fail")
+	}
+}
+
 func TestRunScheduledTask_LauncherError(t *testing.T) {
 	launcher := &mockTaskLauncher{
 		err: errors.New("launch unavailable"),
@@ -0,0 +1,159 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/channels/notifications"
+	"github.com/nugget/thane-ai-agent/internal/platform/scheduler"
+	"github.com/nugget/thane-ai-agent/internal/platform/usage"
+)
+
+// usageAlertTaskName names the recurring scheduler task that runs the
+// usage alert watcher.
+const usageAlertTaskName = "usage_alerts"
+
+// usageAlertEvent is the MQTT event kind usage alerts publish as, on
+// thane/{device_name}/event/usage_alert.
+const usageAlertEvent = "usage_alert"
+
+// newUsageWatcher builds the usage alert watcher from config, or
+// returns nil when no alert budget is set. Channels are resolved when
+// an alert fires rather than here: Signal and MQTT both connect after
+// the stores are initialized.
+func (a *App) newUsageWatcher(logger *slog.Logger) *usage.Watcher {
+	cfg := a.cfg.UsageAlerts
+	if !cfg.Enabled() || a.usageStore == nil || a.opStore == nil {
+		return nil
+	}
+
+	var notifiers []usage.AlertNotifier
+	if a.cfg.Signal.Configured() && cfg.Recipient != "" {
+		notifiers = append(notifiers, a.signalUsageAlert(cfg.Recipient, logger))
+	}
+	if a.cfg.MQTT.Configured() {
+		notifiers = append(notifiers, a.mqttUsageAlert)
+	}
+	if len(notifiers) == 0 {
+		logger.Warn("usage alerts configured without a delivery channel; set up signal (with a recipient) or mqtt")
+		return nil
+	}
+
+	var loc *time.Location
+	if a.cfg.Timezone != "" {
+		loc, _ = time.LoadLocation(a.cfg.Timezone) // already validated
+	}
+	logger.Info("usage alerts enabled",
+		"daily_usd", cfg.DailyUSD,
+		"monthly_usd", cfg.MonthlyUSD,
+		"thresholds", cfg.Thresholds,
+		"channels", len(notifiers),
+	)
+	store := a.usageStore
+	return usage.NewWatcher(usage.WatcherConfig{
+		DailyUSD:   cfg.DailyUSD,
+		MonthlyUSD: cfg.MonthlyUSD,
+		Thresholds: cfg.Thresholds,
+		Location:   loc,
+		Spend: func(start, end time.Time) (float64, error) {
+			summary, err := store.Summary(start, end)
+			if err != nil {
+				return 0, err
+			}
+			return summary.TotalCostUSD, nil
+		},
+		State:     a.opStore,
+		Notifiers: notifiers,
+		Logger:    logger,
+	})
+}
+
+// signalUsageAlert returns a notifier that messages recipient over
+// Signal. The client is read at send time because the Signal bridge
+// starts after the scheduler.
+func (a *App) signalUsageAlert(recipient string, logger *slog.Logger) usage.AlertNotifier {
+	return func(ctx context.Context, alert usage.Alert) error {
+		if a.signalClient == nil || a.contactStore == nil {
+			return fmt.Errorf("signal: not connected")
+		}
+		sp := notifications.NewSignalProvider(a.signalClient, a.contactStore, logger)
+		sp.SetRecorder(&signalMemoryRecorder{mem: a.mem})
+		return sp.Send(ctx, notifications.NotificationRequest{
+			Recipient: recipient,
+			Title:     "Usage budget alert",
+			Message:   alert.Message(),
+			Priority:  "normal",
+		})
+	}
+}
+
+// mqttUsageAlert publishes alert as a usage_alert MQTT event.
+func (a *App) mqttUsageAlert(ctx context.Context, alert usage.Alert) error {
+	if a.mqttPub == nil {
+		return fmt.Errorf("mqtt: not connected")
+	}
+	return a.mqttPub.PublishEvent(ctx, usageAlertEvent, alert)
+}
+
+// syncUsageAlertTask keeps the persisted usage_alerts scheduler task in
+// line with config: created when alerts are enabled, rescheduled when
+// the interval changes, and removed when alerts are turned off.
+// Best-effort: errors are logged so a scheduler problem does not block
+// startup.
+func (a *App) syncUsageAlertTask(enabled bool, logger *slog.Logger) {
+	if a.schedStore == nil || a.sched == nil {
+		return
+	}
+	existing, err := a.schedStore.GetTaskByName(usageAlertTaskName)
+	if err != nil {
+		logger.Warn("usage alert task lookup failed", "error", err)
+		return
+	}
+
+	if !enabled {
+		if existing == nil {
+			return
+		}
+		if err := a.sched.DeleteTask(existing.ID); err != nil {
+			logger.Warn("failed to delete usage alert task", "id", existing.ID, "error", err)
+		}
+		return
+	}
+
+	interval := a.cfg.UsageAlerts.ParsedInterval()
+	schedule := scheduler.Schedule{
+		Kind:  scheduler.ScheduleEvery,
+		Every: &scheduler.Duration{Duration: interval},
+	}
+	if existing == nil {
+		task := &scheduler.Task{
+			Name:      usageAlertTaskName,
+			Schedule:  schedule,
+			Payload:   scheduler.Payload{Kind: scheduler.PayloadUsageCheck},
+			Enabled:   true,
+			CreatedBy: "system",
+		}
+		if err := a.sched.CreateTask(task); err != nil {
+			logger.Warn("failed to create usage alert task", "error", err)
+			return
+		}
+		logger.Info("usage alert task created", "interval", interval)
+		return
+	}
+
+	if existing.Enabled && existing.Payload.Kind == scheduler.PayloadUsageCheck &&
+		existing.Schedule.Kind == scheduler.ScheduleEvery &&
+		existing.Schedule.Every != nil && existing.Schedule.Every.Duration == interval {
+		return
+	}
+	existing.Schedule = schedule
+	existing.Payload = scheduler.Payload{Kind: scheduler.PayloadUsageCheck}
+	existing.Enabled = true
+	if err := a.sched.UpdateTask(existing); err != nil {
+		logger.Warn("failed to update usage alert task", "id", existing.ID, "error", err)
+		return
+	}
+	logger.Info("usage alert task rescheduled", "interval", interval)
+}
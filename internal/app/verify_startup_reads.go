@@ -46,11 +46,11 @@ func (a *App) verifyStartupReads(ctx context.Context, store *documents.Store, co
 	return nil
 }
 
-func (a *App) loadTalents(ctx context.Context, verifier talents.VerifyPathFunc) ([]talents.Talent, error) {
+func (a *App) loadTalents(ctx context.Context, dir string, verifier talents.VerifyPathFunc) ([]talents.Talent, error) {
 	if a == nil || a.cfg == nil {
 		return nil, nil
 	}
-	loader := talents.NewLoader(a.cfg.TalentsDir)
+	loader := talents.NewLoader(dir)
 	parsedTalents, err := loader.TalentsVerified(ctx, verifier, "talents")
 	if err != nil {
 		return nil, fmt.Errorf("load talents: %w", err)
@@ -64,7 +64,7 @@ func (a *App) loadTalents(ctx context.Context, verifier talents.VerifyPathFunc)
 		if logger == nil {
 			logger = slog.Default()
 		}
-		logger.Info("talents loaded", "dir", a.cfg.TalentsDir, "count", len(parsedTalents), "talents", names)
+		logger.Info("talents loaded", "dir", dir, "count", len(parsedTalents), "talents", names)
 	}
 	return parsedTalents, nil
 }
@@ -226,7 +226,7 @@ func TestLoadTalents_BlocksUnsignedTalent(t *testing.T) {
 	}, verifier)
 
 	a := &App{cfg: &config.Config{TalentsDir: talentDir}}
-	_, err := a.loadTalents(context.Background(), store.VerifyPath)
+	_, err := a.loadTalents(context.Background(), a.cfg.TalentsDir, store.VerifyPath)
 	if err == nil {
 		t.Fatal("loadTalents should block unsigned talent under required mode")
 	}
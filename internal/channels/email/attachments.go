@@ -0,0 +1,280 @@
+package email
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"strconv"
+	"strings"
+
+	"github.com/emersion/go-imap/v2"
+	"github.com/emersion/go-imap/v2/imapclient"
+)
+
+// ErrAttachmentTooLarge is returned when an attachment exceeds the
+// configured maximum attachment size.
+var ErrAttachmentTooLarge = errors.New("attachment exceeds the maximum attachment size")
+
+// Attachment describes a file attached to a message. Listing uses the
+// server's BODYSTRUCTURE, so nothing is downloaded to produce it.
+type Attachment struct {
+	// Part is the IMAP part path (e.g., "2" or "1.3") that identifies
+	// the attachment for download.
+	Part string
+
+	// Filename is the attachment's file name, or empty if the sender
+	// did not supply one.
+	Filename string
+
+	// ContentType is the MIME type (e.g., "application/pdf").
+	ContentType string
+
+	// Size is the encoded size in bytes as stored on the server. Base64
+	// content decodes to roughly three quarters of this.
+	Size int64
+
+	// encoding is the Content-Transfer-Encoding, lowercased.
+	encoding string
+
+	// path is Part in the numeric form go-imap expects.
+	path []int
+}
+
+// MessageAttachments is the attachment listing for one message.
+type MessageAttachments struct {
+	// UID is the message's IMAP UID.
+	UID uint32
+
+	// From is the message sender, for trust checks on the content.
+	From string
+
+	// Attachments lists the attachments in MIME order.
+	Attachments []Attachment
+}
+
+// DecodedSizeEstimate returns the approximate size of the attachment
+// once its transfer encoding is removed.
+func (a Attachment) DecodedSizeEstimate() int64 {
+	if a.encoding == "base64" {
+		return a.Size * 3 / 4
+	}
+	return a.Size
+}
+
+// ListAttachments returns the sender and attachments of the message
+// with the given UID. The message is not marked as seen.
+func (c *Client) ListAttachments(ctx context.Context, folder string, uid uint32) (*MessageAttachments, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.listAttachmentsLocked(ctx, folder, uid)
+}
+
+// listAttachmentsLocked implements ListAttachments. The caller must
+// hold c.mu.
+func (c *Client) listAttachmentsLocked(ctx context.Context, folder string, uid uint32) (*MessageAttachments, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+
+	if folder == "" {
+		folder = "INBOX"
+	}
+
+	if _, err := c.selectFolder(folder); err != nil {
+		return nil, err
+	}
+
+	uidSet := imap.UIDSet{}
+	uidSet.AddNum(imap.UID(uid))
+
+	fetchCmd := c.client.Fetch(uidSet, &imap.FetchOptions{
+		UID:           true,
+		Envelope:      true,
+		BodyStructure: &imap.FetchItemBodyStructure{Extended: true},
+	})
+
+	msg := fetchCmd.Next()
+	if msg == nil {
+		_ = fetchCmd.Close()
+		return nil, fmt.Errorf("message UID %d not found in %s", uid, folder)
+	}
+
+	result := &MessageAttachments{UID: uid}
+	for {
+		item := msg.Next()
+		if item == nil {
+			break
+		}
+		switch data := item.(type) {
+		case imapclient.FetchItemDataEnvelope:
+			if data.Envelope != nil && len(data.Envelope.From) > 0 {
+				result.From = formatAddress(data.Envelope.From[0])
+			}
+		case imapclient.FetchItemDataBodyStructure:
+			if data.BodyStructure != nil {
+				result.Attachments = attachmentsFromStructure(data.BodyStructure)
+			}
+		}
+	}
+
+	if err := fetchCmd.Close(); err != nil {
+		return nil, fmt.Errorf("fetch structure of message UID %d: %w", uid, err)
+	}
+
+	return result, nil
+}
+
+// DownloadAttachment streams the decoded content of the attachment at
+// part into w and returns its metadata and the number of bytes
+// written. An attachment larger than maxBytes returns
+// [ErrAttachmentTooLarge]; if that is only discovered mid-stream, w
+// has received a partial prefix the caller must discard. A maxBytes
+// of zero or less means no limit. The message is not marked as seen.
+func (c *Client) DownloadAttachment(ctx context.Context, folder string, uid uint32, part string, w io.Writer, maxBytes int64) (Attachment, int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	listing, err := c.listAttachmentsLocked(ctx, folder, uid)
+	if err != nil {
+		return Attachment{}, 0, err
+	}
+
+	var att Attachment
+	found := false
+	for _, a := range listing.Attachments {
+		if a.Part == part {
+			att, found = a, true
+			break
+		}
+	}
+	if !found {
+		return Attachment{}, 0, fmt.Errorf("message UID %d has no attachment at part %q", uid, part)
+	}
+	if maxBytes > 0 && att.DecodedSizeEstimate() > maxBytes {
+		return att, 0, fmt.Errorf("%s is about %d bytes: %w (%d bytes)", att.displayName(), att.DecodedSizeEstimate(), ErrAttachmentTooLarge, maxBytes)
+	}
+
+	uidSet := imap.UIDSet{}
+	uidSet.AddNum(imap.UID(uid))
+
+	fetchCmd := c.client.Fetch(uidSet, &imap.FetchOptions{
+		UID: true,
+		BodySection: []*imap.FetchItemBodySection{
+			{Part: att.path, Peek: true},
+		},
+	})
+
+	msg := fetchCmd.Next()
+	if msg == nil {
+		_ = fetchCmd.Close()
+		return att, 0, fmt.Errorf("message UID %d not found", uid)
+	}
+
+	var written int64
+	var copyErr error
+	for {
+		item := msg.Next()
+		if item == nil {
+			break
+		}
+		data, ok := item.(imapclient.FetchItemDataBodySection)
+		if !ok || data.Literal == nil {
+			continue
+		}
+		// Stream rather than buffer: the decoded bytes go straight to
+		// w, and reading stops one byte past the cap.
+		src := decodeTransferEncoding(data.Literal, att.encoding)
+		if maxBytes > 0 {
+			src = io.LimitReader(src, maxBytes+1)
+		}
+		written, copyErr = io.Copy(w, src)
+		// Drain any remaining data so the IMAP stream stays in sync.
+		drainLiteral(data.Literal)
+		if copyErr == nil && maxBytes > 0 && written > maxBytes {
+			copyErr = fmt.Errorf("%s: %w (%d bytes)", att.displayName(), ErrAttachmentTooLarge, maxBytes)
+		}
+	}
+
+	if err := fetchCmd.Close(); err != nil {
+		return att, written, fmt.Errorf("fetch attachment of message UID %d: %w", uid, err)
+	}
+	if copyErr != nil {
+		return att, written, copyErr
+	}
+
+	return att, written, nil
+}
+
+// attachmentsFromStructure collects the parts of bs that are
+// attachments: those with an "attachment" disposition or a file name.
+func attachmentsFromStructure(bs imap.BodyStructure) []Attachment {
+	var attachments []Attachment
+	bs.Walk(func(path []int, part imap.BodyStructure) bool {
+		single, ok := part.(*imap.BodyStructureSinglePart)
+		if !ok {
+			return true
+		}
+		filename := decodeFilename(single.Filename())
+		disposition := single.Disposition()
+		isAttachment := disposition != nil && strings.EqualFold(disposition.Value, "attachment")
+		if !isAttachment && filename == "" {
+			return true
+		}
+		attachments = append(attachments, Attachment{
+			Part:        formatPartPath(path),
+			Filename:    filename,
+			ContentType: single.MediaType(),
+			Size:        int64(single.Size),
+			encoding:    strings.ToLower(single.Encoding),
+			path:        append([]int(nil), path...),
+		})
+		return true
+	})
+	return attachments
+}
+
+// displayName returns the filename, or a placeholder naming the part
+// for unnamed attachments.
+func (a Attachment) displayName() string {
+	if a.Filename != "" {
+		return a.Filename
+	}
+	return "attachment " + a.Part
+}
+
+// formatPartPath renders an IMAP part path as dotted notation.
+func formatPartPath(path []int) string {
+	parts := make([]string, len(path))
+	for i, n := range path {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+// decodeFilename decodes RFC 2047 encoded words in an attachment file
+// name, returning the raw name if it does not decode.
+func decodeFilename(name string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(name)
+	if err != nil {
+		return name
+	}
+	return decoded
+}
+
+// decodeTransferEncoding wraps r to undo a Content-Transfer-Encoding.
+// Identity encodings (7bit, 8bit, binary) pass through unchanged.
+func decodeTransferEncoding(r io.Reader, encoding string) io.Reader {
+	switch encoding {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	default:
+		return r
+	}
+}
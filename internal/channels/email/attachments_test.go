@@ -0,0 +1,140 @@
+package email
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emersion/go-imap/v2"
+)
+
+func TestAttachmentsFromStructure(t *testing.T) {
+	bs := &imap.BodyStructureMultiPart{
+		Subtype: "mixed",
+		Children: []imap.BodyStructure{
+			&imap.BodyStructureMultiPart{
+				Subtype: "alternative",
+				Children: []imap.BodyStructure{
+					&imap.BodyStructureSinglePart{Type: "text", Subtype: "plain", Size: 120},
+					&imap.BodyStructureSinglePart{Type: "text", Subtype: "html", Size: 480},
+				},
+			},
+			&imap.BodyStructureSinglePart{
+				Type:     "application",
+				Subtype:  "pdf",
+				Encoding: "BASE64",
+				Size:     4000,
+				Extended: &imap.BodyStructureSinglePartExt{
+					Disposition: &imap.BodyStructureDisposition{
+						Value:  "attachment",
+						Params: map[string]string{"filename": "=?UTF-8?Q?Rechnung_M=C3=A4rz.pdf?="},
+					},
+				},
+			},
+			&imap.BodyStructureSinglePart{
+				Type:     "image",
+				Subtype:  "png",
+				Encoding: "base64",
+				Size:     800,
+				Params:   map[string]string{"name": "logo.png"},
+			},
+		},
+	}
+
+	got := attachmentsFromStructure(bs)
+	if len(got) != 2 {
+		t.Fatalf("got %d attachments, want 2: %+v", len(got), got)
+	}
+
+	pdf := got[0]
+	if pdf.Part != "2" || pdf.Filename != "Rechnung März.pdf" || pdf.ContentType != "application/pdf" {
+		t.Errorf("pdf = %+v", pdf)
+	}
+	if pdf.DecodedSizeEstimate() != 3000 {
+		t.Errorf("DecodedSizeEstimate() = %d, want 3000", pdf.DecodedSizeEstimate())
+	}
+
+	if logo := got[1]; logo.Part != "3" || logo.Filename != "logo.png" || logo.ContentType != "image/png" {
+		t.Errorf("logo = %+v", logo)
+	}
+}
+
+func TestDecodeTransferEncoding(t *testing.T) {
+	tests := []struct {
+		encoding, in, want string
+	}{
+		{"base64", "aGVsbG8gd29ybGQ=", "hello world"},
+		{"quoted-printable", "caf=C3=A9", "café"},
+		{"7bit", "plain text", "plain text"},
+	}
+	for _, tt := range tests {
+		got, err := io.ReadAll(decodeTransferEncoding(strings.NewReader(tt.in), tt.encoding))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.encoding, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.encoding, got, tt.want)
+		}
+	}
+}
+
+func TestFindAttachment(t *testing.T) {
+	attachments := []Attachment{
+		{Part: "2", Filename: "Invoice.pdf"},
+		{Part: "3", Filename: "logo.png"},
+	}
+
+	if a, err := findAttachment(attachments, "3", ""); err != nil || a.Filename != "logo.png" {
+		t.Errorf("by part = %+v, %v", a, err)
+	}
+	if a, err := findAttachment(attachments, "", "invoice.pdf"); err != nil || a.Part != "2" {
+		t.Errorf("by filename = %+v, %v", a, err)
+	}
+	if _, err := findAttachment(attachments, "9", ""); err == nil {
+		t.Error("missing part found")
+	}
+}
+
+// mapFileStore is a [FileStore] over fixed label → path/name entries.
+type mapFileStore map[string][2]string
+
+func (m mapFileStore) Create(context.Context, string, string, string, func(io.Writer) error) (string, error) {
+	return "", nil
+}
+
+func (m mapFileStore) Path(_ context.Context, label string) (string, string) {
+	e := m[label]
+	return e[0], e[1]
+}
+
+func TestResolveAttachments_KeepsSourceName(t *testing.T) {
+	dir := t.TempDir()
+	downloaded := filepath.Join(dir, "conv_attachment_7_2_ab12cd34.pdf")
+	report := filepath.Join(dir, "conv_report_ef56ab78.md")
+	for _, p := range []string{downloaded, report} {
+		if err := os.WriteFile(p, []byte("data"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tools := NewTools(NewManager(Config{}, slog.Default()), nil)
+	tools.SetFileStore(mapFileStore{
+		"attachment_7_2": {downloaded, "Rechnung März.pdf"},
+		"report":         {report, ""},
+	})
+
+	got, err := tools.resolveAttachments(context.Background(), []string{"temp:attachment_7_2", "report"})
+	if err != nil {
+		t.Fatalf("resolveAttachments: %v", err)
+	}
+	if len(got) != 2 || got[0].Filename != "Rechnung März.pdf" || got[1].Filename != "report.md" {
+		t.Errorf("filenames = %+v, want the downloaded name kept and report.md", got)
+	}
+	if got[0].ContentType != "application/pdf" {
+		t.Errorf("content type = %q, want application/pdf", got[0].ContentType)
+	}
+}
@@ -20,6 +20,10 @@ type Client struct {
 	cfg    IMAPConfig
 	logger *slog.Logger
 
+	// tokens, when set, authenticates with XOAUTH2 instead of the
+	// configured password.
+	tokens *tokenSource
+
 	mu     sync.Mutex
 	client *imapclient.Client
 }
@@ -50,37 +54,68 @@ func (c *Client) connectLocked(ctx context.Context) error {
 		c.client = nil
 	}
 
-	addr := net.JoinHostPort(c.cfg.Host, fmt.Sprintf("%d", c.cfg.Port))
+	client, err := dialIMAP(ctx, c.cfg, c.tokens, nil, c.logger)
+	if err != nil {
+		return err
+	}
+
+	c.client = client
+	c.logger.Info("IMAP connected", "host", c.cfg.Host, "user", c.cfg.Username)
+	return nil
+}
+
+// dialIMAP connects to the server described by cfg and logs in, with
+// XOAUTH2 when tokens is set and the configured password otherwise.
+// opts may carry handlers for unsolicited server data; nil uses
+// defaults.
+func dialIMAP(ctx context.Context, cfg IMAPConfig, tokens *tokenSource, opts *imapclient.Options, logger *slog.Logger) (*imapclient.Client, error) {
+	addr := net.JoinHostPort(cfg.Host, fmt.Sprintf("%d", cfg.Port))
 
-	var opts imapclient.Options
-	if c.cfg.TLS {
+	if opts == nil {
+		opts = &imapclient.Options{}
+	}
+	if cfg.TLS {
 		opts.TLSConfig = &tls.Config{
-			ServerName: c.cfg.Host,
+			ServerName: cfg.Host,
 		}
 	}
 
-	c.logger.Debug("connecting to IMAP server", "host", c.cfg.Host, "port", c.cfg.Port, "tls", c.cfg.TLS)
+	logger.Debug("connecting to IMAP server", "host", cfg.Host, "port", cfg.Port, "tls", cfg.TLS)
 
 	var client *imapclient.Client
 	var err error
-	if c.cfg.TLS {
-		client, err = imapclient.DialTLS(addr, &opts)
+	if cfg.TLS {
+		client, err = imapclient.DialTLS(addr, opts)
 	} else {
-		client, err = imapclient.DialInsecure(addr, &opts)
+		client, err = imapclient.DialInsecure(addr, opts)
 	}
 	if err != nil {
-		return fmt.Errorf("dial IMAP %s: %w", addr, err)
+		return nil, fmt.Errorf("dial IMAP %s: %w", addr, err)
+	}
+
+	if tokens != nil {
+		token, err := tokens.Token(ctx)
+		if err != nil {
+			_ = client.Close()
+			return nil, err
+		}
+		if err := client.Authenticate(&xoauth2Client{username: cfg.Username, token: token}); err != nil {
+			// The server may have revoked the token early; refresh on
+			// the next attempt.
+			tokens.Invalidate(token)
+			_ = client.Close()
+			return nil, fmt.Errorf("XOAUTH2 login as %s: %w", cfg.Username, err)
+		}
+		return client, nil
 	}
 
-	loginCmd := client.Login(c.cfg.Username, c.cfg.Password)
+	loginCmd := client.Login(cfg.Username, cfg.Password)
 	if err := loginCmd.Wait(); err != nil {
 		_ = client.Close()
-		return fmt.Errorf("login as %s: %w", c.cfg.Username, err)
+		return nil, fmt.Errorf("login as %s: %w", cfg.Username, err)
 	}
 
-	c.client = client
-	c.logger.Info("IMAP connected", "host", c.cfg.Host, "user", c.cfg.Username)
-	return nil
+	return client, nil
 }
 
 // ensureConnected checks the connection and reconnects if needed.
@@ -97,10 +132,17 @@ func (c *Client) ensureConnected(ctx context.Context) error {
 }
 
 // Ping checks that the IMAP connection is alive. Used by connwatch
-// for health monitoring.
+// for health monitoring. For XOAUTH2 accounts it also checks that the
+// access token can still be refreshed, so a revoked refresh token
+// marks the account unhealthy before the open connection ages out.
 func (c *Client) Ping(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if c.tokens != nil {
+		if _, err := c.tokens.Token(ctx); err != nil {
+			return err
+		}
+	}
 	return c.ensureConnected(ctx)
 }
 
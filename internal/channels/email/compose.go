@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"os"
 	"regexp"
 	"strings"
 	"time"
@@ -38,11 +39,28 @@ type ComposeOptions struct {
 
 	// References is the full References chain (for threading).
 	References []string
+
+	// Attachments are files attached after the body.
+	Attachments []OutboundAttachment
+}
+
+// OutboundAttachment is a file to attach to an outgoing message.
+type OutboundAttachment struct {
+	// Filename is the name the recipient sees.
+	Filename string
+
+	// ContentType is the MIME type. Empty means
+	// application/octet-stream.
+	ContentType string
+
+	// Path is the file on disk whose content is attached.
+	Path string
 }
 
 // ComposeMessage builds a complete RFC 5322 MIME message from the given
 // options. The body markdown is converted to both text/plain and
-// text/html parts in a multipart/alternative structure.
+// text/html parts in a multipart/alternative structure, followed by
+// any attachments as base64 parts.
 func ComposeMessage(opts ComposeOptions) ([]byte, error) {
 	var buf bytes.Buffer
 
@@ -137,6 +155,12 @@ func ComposeMessage(opts ComposeOptions) ([]byte, error) {
 	if err := tw.Close(); err != nil {
 		return nil, fmt.Errorf("close inline writer: %w", err)
 	}
+
+	for _, att := range opts.Attachments {
+		if err := writeAttachment(mw, att); err != nil {
+			return nil, fmt.Errorf("attach %s: %w", att.Filename, err)
+		}
+	}
 	if err := mw.Close(); err != nil {
 		return nil, fmt.Errorf("close mail writer: %w", err)
 	}
@@ -144,6 +168,34 @@ func ComposeMessage(opts ComposeOptions) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// writeAttachment streams the file at att.Path into a new base64
+// attachment part of mw.
+func writeAttachment(mw *mail.Writer, att OutboundAttachment) error {
+	f, err := os.Open(att.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	contentType := att.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	var ah mail.AttachmentHeader
+	ah.Set("Content-Type", contentType)
+	ah.Set("Content-Transfer-Encoding", "base64")
+	ah.SetFilename(att.Filename)
+
+	aw, err := mw.CreateAttachment(ah)
+	if err != nil {
+		return fmt.Errorf("create attachment part: %w", err)
+	}
+	if _, err := io.Copy(aw, f); err != nil {
+		return fmt.Errorf("write attachment: %w", err)
+	}
+	return aw.Close()
+}
+
 // parseAddressList parses a slice of email address strings into
 // mail.Address values. Each string can be "Name <addr>" or just "addr".
 func parseAddressList(addrs []string) ([]*mail.Address, error) {
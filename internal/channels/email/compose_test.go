@@ -1,8 +1,14 @@
 package email
 
 import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/emersion/go-message/mail"
 )
 
 func TestMarkdownToPlain(t *testing.T) {
@@ -128,6 +134,74 @@ func TestComposeMessage(t *testing.T) {
 	}
 }
 
+func TestComposeMessage_WithAttachment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invoice.pdf")
+	content := []byte("%PDF-1.7 invoice")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := ComposeMessage(ComposeOptions{
+		From:    "Test User <test@example.com>",
+		To:      []string{"recipient@example.com"},
+		Subject: "Invoice",
+		Body:    "Attached.",
+		Attachments: []OutboundAttachment{
+			{Filename: "invoice.pdf", ContentType: "application/pdf", Path: path},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ComposeMessage() error: %v", err)
+	}
+
+	mr, err := mail.CreateReader(bytes.NewReader(msg))
+	if err != nil {
+		t.Fatalf("CreateReader: %v", err)
+	}
+	var found bool
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart: %v", err)
+		}
+		ah, ok := part.Header.(*mail.AttachmentHeader)
+		if !ok {
+			continue
+		}
+		found = true
+		if name, _ := ah.Filename(); name != "invoice.pdf" {
+			t.Errorf("filename = %q, want invoice.pdf", name)
+		}
+		if ct, _, _ := ah.ContentType(); ct != "application/pdf" {
+			t.Errorf("content type = %q, want application/pdf", ct)
+		}
+		got, err := io.ReadAll(part.Body)
+		if err != nil {
+			t.Fatalf("read attachment: %v", err)
+		}
+		if !bytes.Equal(got, content) {
+			t.Errorf("attachment content = %q, want %q", got, content)
+		}
+	}
+	if !found {
+		t.Error("message has no attachment part")
+	}
+
+	_, err = ComposeMessage(ComposeOptions{
+		From:        "test@example.com",
+		To:          []string{"recipient@example.com"},
+		Subject:     "Missing",
+		Body:        "Attached.",
+		Attachments: []OutboundAttachment{{Filename: "gone.pdf", Path: filepath.Join(t.TempDir(), "gone.pdf")}},
+	})
+	if err == nil {
+		t.Error("ComposeMessage() with a missing attachment file succeeded")
+	}
+}
+
 func TestComposeMessage_WithThreading(t *testing.T) {
 	msg, err := ComposeMessage(ComposeOptions{
 		From:       "Test User <test@example.com>",
@@ -15,6 +15,19 @@ type Config struct {
 	// Set to 0 to disable email polling.
 	PollIntervalSec int `yaml:"poll_interval"`
 
+	// Idle controls whether accounts whose server advertises IMAP IDLE
+	// get a push connection that reports new mail as it arrives. Those
+	// accounts skip interval polling while the connection is healthy;
+	// the rest, and any whose IDLE connection drops, keep polling every
+	// PollIntervalSec. Requires polling to be enabled. Default: true.
+	Idle *bool `yaml:"idle"`
+
+	// MaxAttachmentMB caps the size, in megabytes, of an attachment
+	// downloaded from or attached to a message. Downloads stream to a
+	// temp file and stop at the cap rather than decoding into memory.
+	// Default: 25.
+	MaxAttachmentMB int `yaml:"max_attachment_mb"`
+
 	// Accounts lists the email accounts to connect to at startup.
 	Accounts []AccountConfig `yaml:"accounts"`
 }
@@ -36,6 +49,9 @@ func (c *Config) ApplyDefaults() {
 	if c.PollIntervalSec == 0 && c.Configured() {
 		c.PollIntervalSec = 300 // 5 minutes
 	}
+	if c.MaxAttachmentMB == 0 && c.Configured() {
+		c.MaxAttachmentMB = 25
+	}
 
 	for i := range c.Accounts {
 		if c.Accounts[i].IMAP.Port == 0 {
@@ -64,6 +80,10 @@ func (c *Config) ApplyDefaults() {
 // Validate checks that the email configuration is internally consistent.
 // Returns an error describing the first problem found.
 func (c Config) Validate() error {
+	if c.MaxAttachmentMB < 0 {
+		return fmt.Errorf("email.max_attachment_mb %d must not be negative", c.MaxAttachmentMB)
+	}
+
 	names := make(map[string]bool, len(c.Accounts))
 	for i, a := range c.Accounts {
 		if a.Name == "" {
@@ -84,12 +104,18 @@ func (c Config) Validate() error {
 			return fmt.Errorf("email.accounts[%d] (%s): imap.port %d out of range (1-65535)", i, a.Name, a.IMAP.Port)
 		}
 
+		if a.OAuth2 != nil {
+			if err := a.OAuth2.Validate(); err != nil {
+				return fmt.Errorf("email.accounts[%d] (%s): %w", i, a.Name, err)
+			}
+		}
+
 		// Validate SMTP if configured.
 		if a.SMTP.Host != "" {
 			if a.SMTP.Username == "" {
 				return fmt.Errorf("email.accounts[%d] (%s): smtp.username is required when smtp.host is set", i, a.Name)
 			}
-			if a.SMTP.Password == "" {
+			if a.SMTP.Password == "" && a.OAuth2 == nil {
 				return fmt.Errorf("email.accounts[%d] (%s): smtp.password is required when smtp.host is set", i, a.Name)
 			}
 			if a.SMTP.Port < 1 || a.SMTP.Port > 65535 {
@@ -103,6 +129,20 @@ func (c Config) Validate() error {
 	return nil
 }
 
+// IdleEnabled reports whether IMAP IDLE push is enabled. Default: true.
+func (c Config) IdleEnabled() bool {
+	if c.Idle == nil {
+		return true
+	}
+	return *c.Idle
+}
+
+// MaxAttachmentBytes returns MaxAttachmentMB in bytes. Zero means no
+// limit.
+func (c Config) MaxAttachmentBytes() int64 {
+	return int64(c.MaxAttachmentMB) * 1024 * 1024
+}
+
 // AccountConfig describes a single email account with its IMAP
 // and optional SMTP connection parameters.
 type AccountConfig struct {
@@ -126,6 +166,12 @@ type AccountConfig struct {
 	// stored after successful SMTP delivery (e.g., "Sent", "[Gmail]/Sent Mail").
 	// When empty, sent messages are not stored via IMAP APPEND.
 	SentFolder string `yaml:"sent_folder"`
+
+	// OAuth2 switches IMAP and SMTP authentication from passwords to
+	// XOAUTH2, as Gmail and Office 365 require. The imap and smtp
+	// usernames are still required; their passwords are ignored.
+	// Omit for password logins to self-hosted servers.
+	OAuth2 *OAuth2Config `yaml:"oauth2,omitempty"`
 }
 
 // SMTPConfigured reports whether this account has SMTP send capability.
@@ -165,7 +211,8 @@ type SMTPConfig struct {
 	Username string `yaml:"username"`
 
 	// Password is the SMTP login password. Supports environment variable
-	// expansion via the config loader (e.g., ${SMTP_PASSWORD}).
+	// expansion via the config loader (e.g., ${SMTP_PASSWORD}). Not
+	// needed when the account uses OAuth2.
 	Password string `yaml:"password"`
 
 	// StartTLS controls whether to upgrade the connection with STARTTLS.
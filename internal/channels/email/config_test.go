@@ -59,6 +59,9 @@ func TestConfig_ApplyDefaults(t *testing.T) {
 	if cfg.PollIntervalSec != 300 {
 		t.Errorf("default poll interval = %d, want 300", cfg.PollIntervalSec)
 	}
+	if cfg.MaxAttachmentMB != 25 {
+		t.Errorf("default max attachment = %d MB, want 25", cfg.MaxAttachmentMB)
+	}
 }
 
 func TestConfig_ApplyDefaults_PollIntervalExplicit(t *testing.T) {
@@ -199,6 +202,44 @@ func TestConfig_Validate(t *testing.T) {
 			}}},
 			wantErr: true,
 		},
+		{
+			name: "oauth2 without smtp password",
+			cfg: Config{Accounts: []AccountConfig{{
+				Name:        "test",
+				IMAP:        IMAPConfig{Host: "imap.gmail.com", Port: 993, Username: "user"},
+				SMTP:        SMTPConfig{Host: "smtp.gmail.com", Port: 587, Username: "user"},
+				DefaultFrom: "User <user@gmail.com>",
+				OAuth2: &OAuth2Config{
+					TokenURL:     "https://oauth2.googleapis.com/token",
+					ClientID:     "client",
+					RefreshToken: "refresh",
+				},
+			}}},
+			wantErr: false,
+		},
+		{
+			name: "oauth2 missing refresh token",
+			cfg: Config{Accounts: []AccountConfig{{
+				Name:   "test",
+				IMAP:   IMAPConfig{Host: "imap.gmail.com", Port: 993, Username: "user"},
+				OAuth2: &OAuth2Config{TokenURL: "https://oauth2.googleapis.com/token", ClientID: "client"},
+			}}},
+			wantErr: true,
+		},
+		{
+			name: "oauth2 relative token url",
+			cfg: Config{Accounts: []AccountConfig{{
+				Name:   "test",
+				IMAP:   IMAPConfig{Host: "imap.gmail.com", Port: 993, Username: "user"},
+				OAuth2: &OAuth2Config{TokenURL: "/token", ClientID: "client", RefreshToken: "refresh"},
+			}}},
+			wantErr: true,
+		},
+		{
+			name:    "negative max attachment",
+			cfg:     Config{MaxAttachmentMB: -1},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -313,3 +354,13 @@ func TestConfig_BccOwner(t *testing.T) {
 		t.Error("Configured() should be true when BccOwner is set with valid account")
 	}
 }
+
+func TestConfig_IdleEnabled(t *testing.T) {
+	if !(Config{}).IdleEnabled() {
+		t.Error("IdleEnabled() should default to true")
+	}
+	off := false
+	if (Config{Idle: &off}).IdleEnabled() {
+		t.Error("IdleEnabled() = true with idle: false")
+	}
+}
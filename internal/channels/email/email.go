@@ -188,6 +188,9 @@ type SendOptions struct {
 
 	// Account is the account name. Empty uses the primary account.
 	Account string
+
+	// Attachments lists temp file labels to attach.
+	Attachments []string
 }
 
 // ReplyOptions describes a reply to an existing message. The tool
@@ -207,6 +210,9 @@ type ReplyOptions struct {
 
 	// Account is the account name. Empty uses the primary account.
 	Account string
+
+	// Attachments lists temp file labels to attach.
+	Attachments []string
 }
 
 // MoveOptions describes an IMAP message move operation.
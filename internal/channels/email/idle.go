@@ -0,0 +1,336 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/emersion/go-imap/v2"
+	"github.com/emersion/go-imap/v2/imapclient"
+)
+
+const (
+	// idleRefresh is how long an IDLE command runs before it is stopped
+	// and the connection checked with NOOP. A connection that died
+	// without a FIN looks exactly like a quiet mailbox while idling, so
+	// the refresh is what proves the session is still alive.
+	idleRefresh = 5 * time.Minute
+
+	// idleStaleAfter is how long a session may go without a successful
+	// refresh before [IdleWatcher.Probe] declares it dead and forces a
+	// reconnect.
+	idleStaleAfter = 2*idleRefresh + time.Minute
+
+	// idleRetryMin and idleRetryMax bound the reconnect backoff after
+	// an IDLE session drops. The account is polled at the regular
+	// interval in the meantime.
+	idleRetryMin = 5 * time.Second
+	idleRetryMax = 5 * time.Minute
+)
+
+// errIdleUnsupported is returned by runSession when the server does
+// not advertise IDLE. The account stays on interval polling.
+var errIdleUnsupported = errors.New("server does not support IDLE")
+
+// idleState is the lifecycle of one account's IDLE session.
+type idleState int
+
+const (
+	// idleDown means no IDLE session is established: connecting,
+	// backing off after a drop, or stopped.
+	idleDown idleState = iota
+
+	// idleActive means the account is idling and new mail is pushed.
+	idleActive
+
+	// idleUnsupported means the server lacks IDLE; the account is only
+	// polled.
+	idleUnsupported
+)
+
+// idleSession tracks one account's IDLE connection.
+type idleSession struct {
+	state     idleState
+	lastAlive time.Time
+	client    *imapclient.Client
+}
+
+// IdleWatcher holds an IMAP IDLE connection to each account's INBOX
+// and runs the [Poller] check for that account as soon as the server
+// reports new mail. It is a push front end to the poller: the
+// high-water mark in opstate still decides what is new, so a message
+// seen by both IDLE and an interval poll is dispatched once. Accounts
+// whose server lacks IDLE, or whose IDLE connection has dropped, fall
+// back to the poller's regular interval.
+type IdleWatcher struct {
+	poller *Poller
+	logger *slog.Logger
+
+	// refresh is idleRefresh, shortened in tests.
+	refresh time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*idleSession
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewIdleWatcher creates an IDLE watcher for every account of the
+// poller's manager and attaches it to the poller, which then skips
+// interval checks for accounts with an active IDLE session. Call
+// [IdleWatcher.Start] to connect.
+func NewIdleWatcher(poller *Poller, logger *slog.Logger) *IdleWatcher {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	w := &IdleWatcher{
+		poller:   poller,
+		logger:   logger,
+		refresh:  idleRefresh,
+		sessions: make(map[string]*idleSession),
+	}
+	for _, name := range poller.manager.AccountNames() {
+		w.sessions[name] = &idleSession{}
+	}
+	poller.idle = w
+	return w
+}
+
+// Start launches one IDLE session per account. Sessions reconnect with
+// backoff until ctx is cancelled or [IdleWatcher.Stop] is called.
+func (w *IdleWatcher) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	w.mu.Lock()
+	w.cancel = cancel
+	w.mu.Unlock()
+
+	for name := range w.sessions {
+		w.wg.Add(1)
+		go func(name string) {
+			defer w.wg.Done()
+			w.watchAccount(ctx, name)
+		}(name)
+	}
+}
+
+// Stop ends all IDLE sessions and waits for them to exit.
+func (w *IdleWatcher) Stop() {
+	w.mu.Lock()
+	cancel := w.cancel
+	for _, s := range w.sessions {
+		if s.client != nil {
+			_ = s.client.Close()
+		}
+	}
+	w.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	w.wg.Wait()
+}
+
+// Active reports whether account has a live IDLE session, meaning new
+// mail is pushed and interval polling can skip it.
+func (w *IdleWatcher) Active(account string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	s, ok := w.sessions[account]
+	return ok && s.state == idleActive && time.Since(s.lastAlive) < idleStaleAfter
+}
+
+// Probe reports whether account's IDLE session is healthy, for use in
+// the account's connwatch probe. A session that has not refreshed
+// within [idleStaleAfter] died silently; Probe closes its connection
+// so the session reconnects, and returns an error. Accounts that are
+// not idling (unsupported, or between reconnects) report no error —
+// interval polling covers them.
+func (w *IdleWatcher) Probe(account string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	s, ok := w.sessions[account]
+	if !ok || s.state != idleActive {
+		return nil
+	}
+	if since := time.Since(s.lastAlive); since >= idleStaleAfter {
+		if s.client != nil {
+			_ = s.client.Close()
+		}
+		s.state = idleDown
+		return fmt.Errorf("IMAP IDLE session for %s silent for %s; reconnecting", account, since.Round(time.Second))
+	}
+	return nil
+}
+
+// watchAccount keeps an IDLE session running for account, reconnecting
+// with exponential backoff when it drops.
+func (w *IdleWatcher) watchAccount(ctx context.Context, account string) {
+	backoff := idleRetryMin
+	for ctx.Err() == nil {
+		started := time.Now()
+		err := w.runSession(ctx, account)
+		w.setDown(account, err)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, errIdleUnsupported) {
+			w.logger.Info("IMAP IDLE not supported; using interval polling",
+				"account", account,
+			)
+			return
+		}
+
+		// A session that ran for a while earns a fresh backoff.
+		if time.Since(started) > w.refresh {
+			backoff = idleRetryMin
+		}
+		w.logger.Warn("IMAP IDLE session ended; polling until reconnected",
+			"account", account,
+			"error", err,
+			"retry_in", backoff,
+		)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, idleRetryMax)
+	}
+}
+
+// runSession connects, selects INBOX, catches up on anything missed
+// while disconnected, and then idles until the connection fails or ctx
+// ends. New-mail notifications trigger a poller check for the account.
+func (w *IdleWatcher) runSession(ctx context.Context, account string) error {
+	acctCfg, err := w.poller.manager.AccountConfig(account)
+	if err != nil {
+		return err
+	}
+
+	// The unilateral data handler runs on the client's read loop and
+	// must not block, so it only signals; a separate goroutine checks.
+	wake := make(chan struct{}, 1)
+	opts := &imapclient.Options{
+		UnilateralDataHandler: &imapclient.UnilateralDataHandler{
+			Mailbox: func(data *imapclient.UnilateralDataMailbox) {
+				if data.NumMessages != nil {
+					select {
+					case wake <- struct{}{}:
+					default:
+					}
+				}
+			},
+		},
+	}
+
+	client, err := dialIMAP(ctx, acctCfg.IMAP, w.poller.manager.tokenSource(account), opts, w.logger)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if !client.Caps().Has(imap.CapIdle) {
+		return errIdleUnsupported
+	}
+	if _, err := client.Select("INBOX", nil).Wait(); err != nil {
+		return fmt.Errorf("select INBOX: %w", err)
+	}
+
+	w.setAlive(account, client)
+	w.logger.Info("IMAP IDLE session established", "account", account)
+
+	checkDone := make(chan struct{})
+	defer close(checkDone)
+	go func() {
+		for {
+			select {
+			case <-wake:
+				w.check(ctx, account)
+			case <-checkDone:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Catch up on mail that arrived while the session was down.
+	w.check(ctx, account)
+
+	for {
+		idle, err := client.Idle()
+		if err != nil {
+			return fmt.Errorf("start IDLE: %w", err)
+		}
+
+		waitErr := make(chan error, 1)
+		go func() { waitErr <- idle.Wait() }()
+
+		select {
+		case <-ctx.Done():
+			_ = idle.Close()
+			return ctx.Err()
+		case err := <-waitErr:
+			// IDLE only ends on its own when the connection does.
+			if err == nil {
+				err = errors.New("connection closed")
+			}
+			return fmt.Errorf("IDLE ended: %w", err)
+		case <-time.After(w.refresh):
+		}
+
+		if err := idle.Close(); err != nil {
+			return fmt.Errorf("stop IDLE: %w", err)
+		}
+		if err := <-waitErr; err != nil {
+			return fmt.Errorf("stop IDLE: %w", err)
+		}
+		if err := client.Noop().Wait(); err != nil {
+			return fmt.Errorf("NOOP: %w", err)
+		}
+		w.setAlive(account, client)
+	}
+}
+
+// check runs the poller for one account in response to new mail.
+func (w *IdleWatcher) check(ctx context.Context, account string) {
+	count, delivered, err := w.poller.checkAccount(ctx, account)
+	if err != nil {
+		w.logger.Warn("email IDLE check failed", "account", account, "error", err)
+		return
+	}
+	if count > 0 {
+		w.logger.Debug("email IDLE check complete",
+			"account", account,
+			"new_messages", count,
+			"delivered_events", delivered,
+		)
+	}
+}
+
+// setAlive records a healthy session for account.
+func (w *IdleWatcher) setAlive(account string, client *imapclient.Client) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	s := w.sessions[account]
+	s.state = idleActive
+	s.lastAlive = time.Now()
+	s.client = client
+}
+
+// setDown records that account's session ended with err.
+func (w *IdleWatcher) setDown(account string, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	s := w.sessions[account]
+	s.client = nil
+	if errors.Is(err, errIdleUnsupported) {
+		s.state = idleUnsupported
+		return
+	}
+	s.state = idleDown
+}
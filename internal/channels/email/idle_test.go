@@ -0,0 +1,138 @@
+package email
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-imap/v2"
+	"github.com/emersion/go-imap/v2/imapserver"
+	"github.com/emersion/go-imap/v2/imapserver/imapmemserver"
+)
+
+// startMemIMAP serves an in-memory IMAP mailbox for user "me" and
+// returns its address and the server, which advertises IDLE.
+func startMemIMAP(t *testing.T) (IMAPConfig, *imapserver.Server) {
+	t.Helper()
+	mem := imapmemserver.New()
+	user := imapmemserver.NewUser("me", "secret")
+	if err := user.Create("INBOX", nil); err != nil {
+		t.Fatalf("create INBOX: %v", err)
+	}
+	mem.AddUser(user)
+
+	srv := imapserver.New(&imapserver.Options{
+		NewSession: func(*imapserver.Conn) (imapserver.Session, *imapserver.GreetingData, error) {
+			return mem.NewSession(), nil, nil
+		},
+		Caps:         imap.CapSet{imap.CapIMAP4rev1: {}},
+		InsecureAuth: true,
+	})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	return IMAPConfig{Host: "127.0.0.1", Port: port, Username: "me", Password: "secret"}, srv
+}
+
+// deliver appends a message to the account's INBOX, as an MTA would.
+func deliver(t *testing.T, cfg IMAPConfig, subject string) {
+	t.Helper()
+	c := NewClient(cfg, slog.Default())
+	defer c.Close()
+	msg := "From: friend@example.com\r\nTo: me@example.com\r\nSubject: " + subject + "\r\n\r\nhello\r\n"
+	if err := c.AppendMessage(context.Background(), "INBOX", []byte(msg)); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func newIdleTestPoller(t *testing.T, imapCfg IMAPConfig) (*Poller, func() int) {
+	t.Helper()
+	mgr := NewManager(Config{Accounts: []AccountConfig{{Name: "personal", IMAP: imapCfg}}}, slog.Default())
+	t.Cleanup(mgr.Close)
+	bus, delivered := recordingBus()
+	p := NewPoller(mgr, testOpstate(t), slog.Default(), WithMessageBus(bus))
+	return p, func() int { return len(delivered()) }
+}
+
+func TestIdleWatcher_PushesNewMail(t *testing.T) {
+	imapCfg, _ := startMemIMAP(t)
+	deliver(t, imapCfg, "already here")
+
+	p, deliveredCount := newIdleTestPoller(t, imapCfg)
+	w := NewIdleWatcher(p, slog.Default())
+	w.refresh = 50 * time.Millisecond // exercise the NOOP refresh path
+	w.Start(context.Background())
+	defer w.Stop()
+
+	// The catch-up check on connect seeds the high-water mark.
+	waitFor(t, "IDLE session and seeded mark", func() bool {
+		mark, _ := p.state.Get(pollNamespace, "personal:INBOX")
+		return w.Active("personal") && mark != ""
+	})
+
+	deliver(t, imapCfg, "fresh mail")
+	waitFor(t, "pushed wake", func() bool { return deliveredCount() == 1 })
+
+	// With IDLE live, the interval poll leaves the account alone.
+	if sent, err := p.CheckNewMessages(context.Background()); err != nil || sent != 0 {
+		t.Errorf("CheckNewMessages = %d, %v; want the IDLE account skipped", sent, err)
+	}
+	if err := w.Probe("personal"); err != nil {
+		t.Errorf("Probe() = %v, want healthy", err)
+	}
+}
+
+func TestIdleWatcher_FallsBackWhenConnectionDrops(t *testing.T) {
+	imapCfg, srv := startMemIMAP(t)
+
+	p, _ := newIdleTestPoller(t, imapCfg)
+	w := NewIdleWatcher(p, slog.Default())
+	w.Start(context.Background())
+	defer w.Stop()
+
+	waitFor(t, "IDLE session", func() bool { return w.Active("personal") })
+
+	// The server going away ends the session; the account is polled
+	// again until IDLE reconnects.
+	_ = srv.Close()
+	waitFor(t, "session down", func() bool { return !w.Active("personal") })
+}
+
+func TestIdleWatcher_ProbeDetectsSilentSession(t *testing.T) {
+	p, _ := newIdleTestPoller(t, IMAPConfig{Host: "imap.test.com", Port: 993, Username: "me"})
+	w := NewIdleWatcher(p, slog.Default())
+
+	w.sessions["personal"].state = idleActive
+	w.sessions["personal"].lastAlive = time.Now().Add(-idleStaleAfter)
+
+	if w.Active("personal") {
+		t.Error("Active() = true for a stale session")
+	}
+	if err := w.Probe("personal"); err == nil {
+		t.Fatal("Probe() = nil, want an error for a stale session")
+	}
+	if w.sessions["personal"].state != idleDown {
+		t.Error("stale session not marked down")
+	}
+	if err := w.Probe("personal"); err != nil {
+		t.Errorf("second Probe() = %v, want nil once marked down", err)
+	}
+}
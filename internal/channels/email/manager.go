@@ -1,8 +1,12 @@
 package email
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/textproto"
 )
 
 // Manager holds multiple named IMAP email clients and routes requests
@@ -14,6 +18,14 @@ type Manager struct {
 	bccOwner string
 	primary  string
 	logger   *slog.Logger
+
+	// tokens holds the XOAUTH2 token source of each account that uses
+	// OAuth2, shared by its IMAP connections and SMTP sends.
+	tokens map[string]*tokenSource
+
+	// maxAttachment caps attachment downloads and uploads, in bytes.
+	// Zero means no limit.
+	maxAttachment int64
 }
 
 // NewManager creates a manager from the email configuration. Each
@@ -23,12 +35,20 @@ func NewManager(cfg Config, logger *slog.Logger) *Manager {
 	m := &Manager{
 		clients:  make(map[string]*Client, len(cfg.Accounts)),
 		configs:  make(map[string]AccountConfig, len(cfg.Accounts)),
+		tokens:   make(map[string]*tokenSource),
 		bccOwner: cfg.BccOwner,
 		logger:   logger,
+
+		maxAttachment: cfg.MaxAttachmentBytes(),
 	}
 
 	for i, acct := range cfg.Accounts {
 		client := NewClient(acct.IMAP, logger.With("email_account", acct.Name))
+		if acct.OAuth2 != nil {
+			ts := newTokenSource(*acct.OAuth2, nil)
+			client.tokens = ts
+			m.tokens[acct.Name] = ts
+		}
 		m.clients[acct.Name] = client
 		m.configs[acct.Name] = acct
 		if i == 0 {
@@ -72,6 +92,70 @@ func (m *Manager) BccOwner() string {
 	return m.bccOwner
 }
 
+// MaxAttachmentBytes returns the configured per-attachment size cap in
+// bytes. Zero means no limit.
+func (m *Manager) MaxAttachmentBytes() int64 {
+	return m.maxAttachment
+}
+
+// ListAttachments returns the attachment metadata (file name, MIME
+// type, size) and sender of a message on the named account, or the
+// primary account if account is empty.
+func (m *Manager) ListAttachments(ctx context.Context, account, folder string, uid uint32) (*MessageAttachments, error) {
+	client, err := m.Account(account)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListAttachments(ctx, folder, uid)
+}
+
+// DownloadAttachment streams one attachment of a message into w,
+// enforcing the configured maximum attachment size. See
+// [Client.DownloadAttachment].
+func (m *Manager) DownloadAttachment(ctx context.Context, account, folder string, uid uint32, part string, w io.Writer) (Attachment, int64, error) {
+	client, err := m.Account(account)
+	if err != nil {
+		return Attachment{}, 0, err
+	}
+	return client.DownloadAttachment(ctx, folder, uid, part, w, m.maxAttachment)
+}
+
+// SendMail delivers msg over the named account's SMTP server (or the
+// primary account's if account is empty), authenticating with XOAUTH2
+// when the account uses OAuth2 and with its password otherwise.
+func (m *Manager) SendMail(ctx context.Context, account, from string, recipients []string, msg []byte) error {
+	cfg, err := m.AccountConfig(account)
+	if err != nil {
+		return err
+	}
+
+	ts := m.tokenSource(cfg.Name)
+	if ts == nil {
+		return SendMail(ctx, cfg.SMTP, from, recipients, msg)
+	}
+
+	token, err := ts.Token(ctx)
+	if err != nil {
+		return err
+	}
+	auth := &xoauth2Auth{username: cfg.SMTP.Username, token: token, host: cfg.SMTP.Host}
+	if err := sendMail(ctx, cfg.SMTP, auth, from, recipients, msg); err != nil {
+		var smtpErr *textproto.Error
+		if errors.As(err, &smtpErr) && smtpErr.Code == 535 {
+			// Rejected credentials: refresh before the next attempt.
+			ts.Invalidate(token)
+		}
+		return err
+	}
+	return nil
+}
+
+// tokenSource returns the named account's XOAUTH2 token source, or nil
+// if the account authenticates with a password.
+func (m *Manager) tokenSource(account string) *tokenSource {
+	return m.tokens[account]
+}
+
 // Primary returns the default account name.
 func (m *Manager) Primary() string {
 	return m.primary
@@ -0,0 +1,247 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emersion/go-sasl"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/httpkit"
+)
+
+// tokenRefreshMargin is how long before expiry a cached access token is
+// refreshed, so a token never expires between login and first command.
+const tokenRefreshMargin = 60 * time.Second
+
+// OAuth2Config configures XOAUTH2 authentication for an account, used
+// by providers such as Gmail and Office 365 that disable password
+// logins. Access tokens are obtained from a long-lived refresh token
+// and renewed automatically; the same token serves IMAP and SMTP.
+type OAuth2Config struct {
+	// TokenURL is the provider's token endpoint (e.g.,
+	// "https://oauth2.googleapis.com/token" for Gmail or
+	// "https://login.microsoftonline.com/common/oauth2/v2.0/token"
+	// for Office 365). Required.
+	TokenURL string `yaml:"token_url"`
+
+	// ClientID identifies the OAuth2 client registered with the
+	// provider. Required.
+	ClientID string `yaml:"client_id"`
+
+	// ClientSecret authenticates the client. Optional for public
+	// clients. Supports environment variable expansion.
+	ClientSecret string `yaml:"client_secret"`
+
+	// RefreshToken is the long-lived token granted when the account
+	// owner authorized the client. Required. Supports environment
+	// variable expansion (e.g., ${GMAIL_REFRESH_TOKEN}).
+	RefreshToken string `yaml:"refresh_token"`
+
+	// Scopes are requested with each refresh. Optional; most providers
+	// reuse the scopes of the original grant.
+	Scopes []string `yaml:"scopes"`
+}
+
+// Validate checks that the required fields are set.
+func (c OAuth2Config) Validate() error {
+	switch {
+	case c.TokenURL == "":
+		return errors.New("oauth2.token_url is required")
+	case c.ClientID == "":
+		return errors.New("oauth2.client_id is required")
+	case c.RefreshToken == "":
+		return errors.New("oauth2.refresh_token is required")
+	}
+	if u, err := url.Parse(c.TokenURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("oauth2.token_url %q is not an absolute URL", c.TokenURL)
+	}
+	return nil
+}
+
+// tokenSource exchanges the refresh token for access tokens and caches
+// each until shortly before it expires. Providers that rotate refresh
+// tokens are followed in memory; the configured token must stay valid
+// across restarts.
+type tokenSource struct {
+	cfg        OAuth2Config
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu           sync.Mutex
+	refreshToken string
+	token        string
+	expiry       time.Time // zero when the server gave no lifetime
+}
+
+// tokenResponse is the token endpoint's success or error payload.
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func newTokenSource(cfg OAuth2Config, httpClient *http.Client) *tokenSource {
+	if httpClient == nil {
+		httpClient = httpkit.NewClient(httpkit.WithTimeout(30 * time.Second))
+	}
+	return &tokenSource{
+		cfg:          cfg,
+		httpClient:   httpClient,
+		now:          time.Now,
+		refreshToken: cfg.RefreshToken,
+	}
+}
+
+// Token returns a valid access token, refreshing it when none is
+// cached or the cached one is about to expire.
+func (ts *tokenSource) Token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token != "" && (ts.expiry.IsZero() || ts.now().Before(ts.expiry.Add(-tokenRefreshMargin))) {
+		return ts.token, nil
+	}
+
+	tr, err := ts.fetch(ctx)
+	if err != nil {
+		return "", fmt.Errorf("refresh oauth2 token: %w", err)
+	}
+	ts.token = tr.AccessToken
+	ts.expiry = time.Time{}
+	if tr.ExpiresIn > 0 {
+		ts.expiry = ts.now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+	if tr.RefreshToken != "" {
+		ts.refreshToken = tr.RefreshToken
+	}
+	return ts.token, nil
+}
+
+// Invalidate drops token from the cache if it is still the cached
+// token, forcing the next [tokenSource.Token] call to refresh. Used
+// when a server rejects a token before its expiry.
+func (ts *tokenSource) Invalidate(token string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.token == token {
+		ts.token = ""
+		ts.expiry = time.Time{}
+	}
+}
+
+// fetch performs the refresh-token grant. The caller must hold ts.mu.
+func (ts *tokenSource) fetch(ctx context.Context) (tokenResponse, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {ts.refreshToken},
+		"client_id":     {ts.cfg.ClientID},
+	}
+	if ts.cfg.ClientSecret != "" {
+		form.Set("client_secret", ts.cfg.ClientSecret)
+	}
+	if len(ts.cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(ts.cfg.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ts.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return tokenResponse{}, fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := ts.httpClient.Do(req)
+	if err != nil {
+		return tokenResponse{}, fmt.Errorf("token request to %s: %w", ts.cfg.TokenURL, err)
+	}
+	defer httpkit.DrainAndClose(resp.Body, 1<<20)
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return tokenResponse{}, fmt.Errorf("read token response: %w", err)
+	}
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return tokenResponse{}, fmt.Errorf("token endpoint returned %d: unmarshal response: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || tr.Error != "" {
+		if tr.ErrorDescription != "" {
+			return tokenResponse{}, fmt.Errorf("token endpoint returned %d: %s: %s", resp.StatusCode, tr.Error, tr.ErrorDescription)
+		}
+		return tokenResponse{}, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, tr.Error)
+	}
+	if tr.AccessToken == "" {
+		return tokenResponse{}, fmt.Errorf("token endpoint returned no access_token")
+	}
+	if tr.TokenType != "" && !strings.EqualFold(tr.TokenType, "bearer") {
+		return tokenResponse{}, fmt.Errorf("token endpoint returned unsupported token_type %q", tr.TokenType)
+	}
+	return tr, nil
+}
+
+// xoauth2InitialResponse builds the XOAUTH2 initial client response
+// defined by Google and Microsoft for IMAP and SMTP.
+func xoauth2InitialResponse(username, token string) []byte {
+	return []byte("user=" + username + "\x01auth=Bearer " + token + "\x01\x01")
+}
+
+// xoauth2Client is a [sasl.Client] for the XOAUTH2 mechanism.
+type xoauth2Client struct {
+	username, token string
+}
+
+var _ sasl.Client = (*xoauth2Client)(nil)
+
+func (c *xoauth2Client) Start() (string, []byte, error) {
+	return "XOAUTH2", xoauth2InitialResponse(c.username, c.token), nil
+}
+
+// Next answers the server's error challenge. On a rejected token the
+// server sends a JSON error description and expects an empty response
+// before it completes the exchange with a failure.
+func (c *xoauth2Client) Next(challenge []byte) ([]byte, error) {
+	return []byte{}, nil
+}
+
+// xoauth2Auth is an [smtp.Auth] for the XOAUTH2 mechanism. Like
+// [smtp.PlainAuth], it refuses to send the token over an unencrypted
+// connection to anything but localhost.
+type xoauth2Auth struct {
+	username, token, host string
+}
+
+var _ smtp.Auth = (*xoauth2Auth)(nil)
+
+func (a *xoauth2Auth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	return "XOAUTH2", xoauth2InitialResponse(a.username, a.token), nil
+}
+
+func (a *xoauth2Auth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if more {
+		return []byte{}, nil
+	}
+	return nil, nil
+}
+
+// isLocalhost reports whether host names the local machine.
+func isLocalhost(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
@@ -0,0 +1,167 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeTokenEndpoint serves refresh-token grants, issuing numbered access
+// tokens and recording the refresh token each request presented.
+type fakeTokenEndpoint struct {
+	mu        sync.Mutex
+	presented []string
+	rotate    bool
+	fail      bool
+}
+
+func (f *fakeTokenEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.presented = append(f.presented, r.PostForm.Get("refresh_token"))
+	n := len(f.presented)
+
+	w.Header().Set("Content-Type", "application/json")
+	if f.fail || r.PostForm.Get("grant_type") != "refresh_token" || r.PostForm.Get("client_id") != "client" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error":             "invalid_grant",
+			"error_description": "Token has been expired or revoked.",
+		})
+		return
+	}
+	resp := map[string]any{
+		"access_token": "access-" + strconv.Itoa(n),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	}
+	if f.rotate {
+		resp["refresh_token"] = "rotated-" + strconv.Itoa(n)
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func newTestTokenSource(t *testing.T, endpoint *fakeTokenEndpoint) (*tokenSource, *time.Time) {
+	t.Helper()
+	srv := httptest.NewServer(endpoint)
+	t.Cleanup(srv.Close)
+
+	ts := newTokenSource(OAuth2Config{
+		TokenURL:     srv.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RefreshToken: "refresh",
+	}, srv.Client())
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ts.now = func() time.Time { return now }
+	return ts, &now
+}
+
+func TestTokenSource_CachesUntilExpiry(t *testing.T) {
+	endpoint := &fakeTokenEndpoint{}
+	ts, now := newTestTokenSource(t, endpoint)
+	ctx := context.Background()
+
+	first, err := ts.Token(ctx)
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if again, _ := ts.Token(ctx); again != first {
+		t.Errorf("second Token = %q, want cached %q", again, first)
+	}
+
+	// Inside the refresh margin the token is renewed.
+	*now = now.Add(time.Hour - tokenRefreshMargin/2)
+	renewed, err := ts.Token(ctx)
+	if err != nil {
+		t.Fatalf("Token after expiry: %v", err)
+	}
+	if renewed == first {
+		t.Errorf("Token = %q near expiry, want a refreshed token", renewed)
+	}
+
+	// A server rejection invalidates the cached token.
+	ts.Invalidate(renewed)
+	if third, _ := ts.Token(ctx); third == renewed {
+		t.Error("Token returned an invalidated token")
+	}
+	if got := len(endpoint.presented); got != 3 {
+		t.Errorf("token endpoint called %d times, want 3", got)
+	}
+}
+
+func TestTokenSource_FollowsRotatedRefreshToken(t *testing.T) {
+	endpoint := &fakeTokenEndpoint{rotate: true}
+	ts, _ := newTestTokenSource(t, endpoint)
+	ctx := context.Background()
+
+	token, err := ts.Token(ctx)
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	ts.Invalidate(token)
+	if _, err := ts.Token(ctx); err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	want := []string{"refresh", "rotated-1"}
+	if strings.Join(endpoint.presented, ",") != strings.Join(want, ",") {
+		t.Errorf("refresh tokens presented = %v, want %v", endpoint.presented, want)
+	}
+}
+
+func TestTokenSource_RefreshFailure(t *testing.T) {
+	ts, _ := newTestTokenSource(t, &fakeTokenEndpoint{fail: true})
+
+	_, err := ts.Token(context.Background())
+	if err == nil {
+		t.Fatal("Token succeeded against a failing endpoint")
+	}
+	if !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("error = %v, want the endpoint's invalid_grant", err)
+	}
+}
+
+func TestClientPing_SurfacesTokenFailure(t *testing.T) {
+	ts, _ := newTestTokenSource(t, &fakeTokenEndpoint{fail: true})
+	c := NewClient(IMAPConfig{Host: "imap.test.com", Port: 993, Username: "me"}, nil)
+	c.tokens = ts
+
+	if err := c.Ping(context.Background()); err == nil || !strings.Contains(err.Error(), "oauth2") {
+		t.Errorf("Ping() = %v, want the token refresh failure", err)
+	}
+}
+
+func TestXOAUTH2InitialResponse(t *testing.T) {
+	got := string(xoauth2InitialResponse("me@gmail.com", "ya29.token"))
+	want := "user=me@gmail.com\x01auth=Bearer ya29.token\x01\x01"
+	if got != want {
+		t.Errorf("xoauth2InitialResponse = %q, want %q", got, want)
+	}
+}
+
+func TestXOAUTH2Auth_RequiresTLS(t *testing.T) {
+	auth := &xoauth2Auth{username: "me", token: "tok", host: "smtp.gmail.com"}
+
+	if _, _, err := auth.Start(&smtp.ServerInfo{Name: "smtp.gmail.com"}); err == nil {
+		t.Error("Start sent a token over an unencrypted connection")
+	}
+	if _, _, err := auth.Start(&smtp.ServerInfo{Name: "smtp.evil.com", TLS: true}); err == nil {
+		t.Error("Start sent a token to the wrong host")
+	}
+	mech, resp, err := auth.Start(&smtp.ServerInfo{Name: "smtp.gmail.com", TLS: true})
+	if err != nil || mech != "XOAUTH2" || !strings.Contains(string(resp), "auth=Bearer tok") {
+		t.Errorf("Start = %q, %q, %v; want XOAUTH2 with the bearer token", mech, resp, err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/nugget/thane-ai-agent/internal/channels/messages"
 	"github.com/nugget/thane-ai-agent/internal/platform/opstate"
@@ -26,7 +27,8 @@ const (
 
 // Poller checks configured email accounts for new messages by comparing
 // IMAP UIDs against a persisted high-water mark. It is not a tool — it
-// runs as infrastructure code called by the scheduler task executor.
+// runs as infrastructure code called by the scheduler task executor,
+// and by an [IdleWatcher] when the server pushes new mail.
 type Poller struct {
 	manager   *Manager
 	state     *opstate.Store
@@ -35,6 +37,15 @@ type Poller struct {
 	contacts  ContactResolver
 	wakeLoop  messages.LoopWakeTarget
 	wakeReady bool
+
+	// idle, when set, pushes new mail for accounts with a live IMAP
+	// IDLE session; CheckNewMessages skips those accounts.
+	idle *IdleWatcher
+
+	// checkMu serializes account checks so an IDLE-triggered check and
+	// an interval poll cannot both dispatch the same messages before
+	// either advances the high-water mark.
+	checkMu sync.Mutex
 }
 
 // PollerOption customizes poller behavior.
@@ -123,8 +134,14 @@ func (p *Poller) CheckNewMessages(ctx context.Context) (int, error) {
 	var failed int
 	var totalNew int
 	var delivered int
+	var pushed int
 
 	for _, name := range accounts {
+		if p.idle != nil && p.idle.Active(name) {
+			p.logger.Debug("email poll skipping account with live IDLE session", "account", name)
+			pushed++
+			continue
+		}
 		p.logger.Debug("email poll checking account", "account", name)
 
 		count, sent, err := p.checkAccount(ctx, name)
@@ -145,6 +162,7 @@ func (p *Poller) CheckNewMessages(ctx context.Context) (int, error) {
 		"new_messages", totalNew,
 		"delivered_events", delivered,
 		"failed", failed,
+		"idle", pushed,
 	)
 
 	if summary := loop.IterationSummary(ctx); summary != nil {
@@ -156,6 +174,9 @@ func (p *Poller) CheckNewMessages(ctx context.Context) (int, error) {
 		if failed > 0 {
 			summary["failed"] = failed
 		}
+		if pushed > 0 {
+			summary["idle_accounts"] = pushed
+		}
 	}
 
 	return delivered, nil
@@ -170,6 +191,9 @@ func (p *Poller) CheckNewMessages(ctx context.Context) (int, error) {
 // did succeed, so the next poll picks up from the last delivered UID
 // instead of replaying or losing the whole window.
 func (p *Poller) checkAccount(ctx context.Context, accountName string) (int, int, error) {
+	p.checkMu.Lock()
+	defer p.checkMu.Unlock()
+
 	client, err := p.manager.Account(accountName)
 	if err != nil {
 		return 0, 0, fmt.Errorf("get account %q: %w", accountName, err)
@@ -18,6 +18,16 @@ const smtpDialTimeout = 30 * time.Second
 // message (as returned by ComposeMessage). The context controls the
 // overall deadline for the entire send operation.
 func SendMail(ctx context.Context, cfg SMTPConfig, from string, recipients []string, msg []byte) error {
+	var auth smtp.Auth
+	if cfg.Username != "" && cfg.Password != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return sendMail(ctx, cfg, auth, from, recipients, msg)
+}
+
+// sendMail implements [SendMail] with the given authentication. A nil
+// auth skips AUTH.
+func sendMail(ctx context.Context, cfg SMTPConfig, auth smtp.Auth, from string, recipients []string, msg []byte) error {
 	addr := net.JoinHostPort(cfg.Host, fmt.Sprintf("%d", cfg.Port))
 
 	// Use context deadline for the dial timeout, falling back to the
@@ -81,8 +91,7 @@ func SendMail(ctx context.Context, cfg SMTPConfig, from string, recipients []str
 	}
 
 	// Authenticate if credentials are provided.
-	if cfg.Username != "" && cfg.Password != "" {
-		auth := smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	if auth != nil {
 		if err := client.Auth(auth); err != nil {
 			return fmt.Errorf("AUTH: %w", err)
 		}
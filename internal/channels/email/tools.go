@@ -3,7 +3,11 @@ package email
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"mime"
+	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"time"
@@ -18,6 +22,23 @@ import (
 type Tools struct {
 	manager  *Manager
 	contacts ContactResolver
+	files    FileStore
+}
+
+// FileStore saves downloaded attachments and resolves files to attach
+// to outgoing mail. Implementations wrap the conversation-scoped temp
+// file store without requiring the email package to import the tools
+// package.
+type FileStore interface {
+	// Create writes a file under label by calling write, replacing any
+	// file the label referred to, and returns its path. name is the
+	// file's original name, or "" if it has none; ext is the file
+	// extension including the dot. A failed write leaves no file behind.
+	Create(ctx context.Context, label, name, ext string, write func(io.Writer) error) (string, error)
+
+	// Path returns the file path for label, or "" if there is none,
+	// and the original name it was created with.
+	Path(ctx context.Context, label string) (path, name string)
 }
 
 // NewTools creates email tools backed by the given manager and optional
@@ -27,6 +48,13 @@ func NewTools(mgr *Manager, contacts ContactResolver) *Tools {
 	return &Tools{manager: mgr, contacts: contacts}
 }
 
+// SetFileStore configures where downloaded attachments are saved and
+// where outgoing attachments are read from. Without one, attachment
+// downloads and outgoing attachments are unavailable.
+func (t *Tools) SetFileStore(fs FileStore) {
+	t.files = fs
+}
+
 // HandleList lists recent emails in a folder.
 func (t *Tools) HandleList(ctx context.Context, args map[string]any) (string, error) {
 	opts := ListOptions{
@@ -201,6 +229,8 @@ func (t *Tools) HandleSend(ctx context.Context, args map[string]any) (string, er
 		Subject: toolargs.String(args, "subject"),
 		Body:    toolargs.String(args, "body"),
 		Account: toolargs.String(args, "account"),
+
+		Attachments: toolargs.StringSlice(args, "attachments"),
 	}
 
 	if len(opts.To) == 0 {
@@ -213,7 +243,12 @@ func (t *Tools) HandleSend(ctx context.Context, args map[string]any) (string, er
 		return "", fmt.Errorf("body is required")
 	}
 
-	return t.sendEmail(ctx, opts.Account, opts.To, opts.Cc, opts.Subject, opts.Body, "", nil)
+	attachments, err := t.resolveAttachments(ctx, opts.Attachments)
+	if err != nil {
+		return "", err
+	}
+
+	return t.sendEmail(ctx, opts.Account, opts.To, opts.Cc, opts.Subject, opts.Body, "", nil, attachments)
 }
 
 // HandleReply replies to an existing message with threading headers.
@@ -224,6 +259,8 @@ func (t *Tools) HandleReply(ctx context.Context, args map[string]any) (string, e
 		Body:     toolargs.String(args, "body"),
 		ReplyAll: toolargs.Bool(args, "reply_all"),
 		Account:  toolargs.String(args, "account"),
+
+		Attachments: toolargs.StringSlice(args, "attachments"),
 	}
 
 	if opts.UID == 0 {
@@ -233,6 +270,11 @@ func (t *Tools) HandleReply(ctx context.Context, args map[string]any) (string, e
 		return "", fmt.Errorf("body is required")
 	}
 
+	attachments, err := t.resolveAttachments(ctx, opts.Attachments)
+	if err != nil {
+		return "", err
+	}
+
 	// Fetch the original message for threading info.
 	client, err := t.manager.Account(opts.Account)
 	if err != nil {
@@ -285,7 +327,7 @@ func (t *Tools) HandleReply(ctx context.Context, args map[string]any) (string, e
 		refs = append(refs, original.MessageID)
 	}
 
-	return t.sendEmail(ctx, opts.Account, to, cc, subject, opts.Body, original.MessageID, refs)
+	return t.sendEmail(ctx, opts.Account, to, cc, subject, opts.Body, original.MessageID, refs, attachments)
 }
 
 // HandleMove moves messages between folders.
@@ -338,9 +380,156 @@ func (t *Tools) HandleMove(ctx context.Context, args map[string]any) (string, er
 	return fmt.Sprintf("Moved %d message(s) from %s to %s", len(opts.UIDs), folder, opts.Destination), nil
 }
 
+// HandleAttachments lists the attachments of a message without
+// downloading them.
+func (t *Tools) HandleAttachments(ctx context.Context, args map[string]any) (string, error) {
+	uid := toolargs.Uint32(args, "uid")
+	folder := toolargs.String(args, "folder")
+	account := toolargs.String(args, "account")
+
+	if uid == 0 {
+		return "", fmt.Errorf("uid is required")
+	}
+
+	listing, err := t.manager.ListAttachments(ctx, account, folder, uid)
+	if err != nil {
+		return "", err
+	}
+
+	if len(listing.Attachments) == 0 {
+		return fmt.Sprintf("Message %d has no attachments", uid), nil
+	}
+
+	return formatAttachmentList(listing, t.manager.MaxAttachmentBytes()), nil
+}
+
+// HandleDownloadAttachment saves one attachment of a message to the
+// temp file store. The attachment is identified by part, or by filename
+// when part is omitted. The message sender must pass the same trust
+// zone gating as outbound recipients.
+func (t *Tools) HandleDownloadAttachment(ctx context.Context, args map[string]any) (string, error) {
+	uid := toolargs.Uint32(args, "uid")
+	part := toolargs.String(args, "part")
+	filename := toolargs.String(args, "filename")
+	label := toolargs.String(args, "label")
+	folder := toolargs.String(args, "folder")
+	account := toolargs.String(args, "account")
+
+	if uid == 0 {
+		return "", fmt.Errorf("uid is required")
+	}
+	if part == "" && filename == "" {
+		return "", fmt.Errorf("part or filename is required")
+	}
+	if t.files == nil {
+		return "", fmt.Errorf("attachment downloads require the temp file store")
+	}
+
+	listing, err := t.manager.ListAttachments(ctx, account, folder, uid)
+	if err != nil {
+		return "", err
+	}
+
+	if err := CheckSenderTrust(t.contacts, listing.From); err != nil {
+		return "", fmt.Errorf("attachment not downloaded: %w", err)
+	}
+
+	att, err := findAttachment(listing.Attachments, part, filename)
+	if err != nil {
+		return "", fmt.Errorf("message %d: %w", uid, err)
+	}
+
+	if label == "" {
+		label = fmt.Sprintf("attachment_%d_%s", uid, strings.ReplaceAll(att.Part, ".", "_"))
+	}
+
+	var written int64
+	path, err := t.files.Create(ctx, label, att.Filename, attachmentExt(att), func(w io.Writer) error {
+		var err error
+		_, written, err = t.manager.DownloadAttachment(ctx, account, folder, uid, att.Part, w)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("download attachment: %w", err)
+	}
+
+	return fmt.Sprintf("Saved %s (%s, %d bytes) as temp file '%s' at %s",
+		att.displayName(), att.ContentType, written, label, path), nil
+}
+
+// resolveAttachments maps temp file labels to outgoing attachments,
+// enforcing the configured maximum attachment size. A file keeps the
+// name it was downloaded under; other temp files are named after their
+// label.
+func (t *Tools) resolveAttachments(ctx context.Context, labels []string) ([]OutboundAttachment, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	if t.files == nil {
+		return nil, fmt.Errorf("attachments require the temp file store")
+	}
+
+	maxBytes := t.manager.MaxAttachmentBytes()
+	attachments := make([]OutboundAttachment, 0, len(labels))
+	for _, label := range labels {
+		label = strings.TrimPrefix(label, "temp:")
+		path, name := t.files.Path(ctx, label)
+		if path == "" {
+			return nil, fmt.Errorf("unknown temp file label %q", label)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("attachment %q: %w", label, err)
+		}
+		if maxBytes > 0 && info.Size() > maxBytes {
+			return nil, fmt.Errorf("attachment %q is %d bytes: %w (%d bytes)", label, info.Size(), ErrAttachmentTooLarge, maxBytes)
+		}
+
+		ext := filepath.Ext(path)
+		if name = filepath.Base(name); name == "." || name == string(filepath.Separator) {
+			name = label + ext
+		}
+		attachments = append(attachments, OutboundAttachment{
+			Filename:    name,
+			ContentType: mime.TypeByExtension(ext),
+			Path:        path,
+		})
+	}
+	return attachments, nil
+}
+
+// findAttachment picks the attachment at part, or failing that the
+// first whose file name matches filename case-insensitively.
+func findAttachment(attachments []Attachment, part, filename string) (Attachment, error) {
+	for _, a := range attachments {
+		if part != "" && a.Part == part {
+			return a, nil
+		}
+		if part == "" && strings.EqualFold(a.Filename, filename) {
+			return a, nil
+		}
+	}
+	if part != "" {
+		return Attachment{}, fmt.Errorf("no attachment at part %q", part)
+	}
+	return Attachment{}, fmt.Errorf("no attachment named %q", filename)
+}
+
+// attachmentExt returns the file extension to save att under: the one
+// in its file name, else one registered for its MIME type.
+func attachmentExt(att Attachment) string {
+	if ext := filepath.Ext(att.Filename); ext != "" {
+		return strings.ToLower(ext)
+	}
+	if exts, _ := mime.ExtensionsByType(att.ContentType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
+
 // sendEmail is the shared send path for HandleSend and HandleReply.
 // It handles trust zone gating, auto-Bcc, message composition, and SMTP delivery.
-func (t *Tools) sendEmail(ctx context.Context, account string, to, cc []string, subject, body, inReplyTo string, references []string) (string, error) {
+func (t *Tools) sendEmail(ctx context.Context, account string, to, cc []string, subject, body, inReplyTo string, references []string, attachments []OutboundAttachment) (string, error) {
 	acctCfg, err := t.manager.AccountConfig(account)
 	if err != nil {
 		return "", err
@@ -383,6 +572,8 @@ func (t *Tools) sendEmail(ctx context.Context, account string, to, cc []string,
 		Body:       body,
 		InReplyTo:  inReplyTo,
 		References: references,
+
+		Attachments: attachments,
 	})
 	if err != nil {
 		return "", fmt.Errorf("compose message: %w", err)
@@ -393,7 +584,7 @@ func (t *Tools) sendEmail(ctx context.Context, account string, to, cc []string,
 
 	// Send via SMTP.
 	fromAddr := extractAddress(acctCfg.DefaultFrom)
-	if err := SendMail(ctx, acctCfg.SMTP, fromAddr, smtpRecipients, msg); err != nil {
+	if err := t.manager.SendMail(ctx, acctCfg.Name, fromAddr, smtpRecipients, msg); err != nil {
 		return "", fmt.Errorf("send email: %w", err)
 	}
 
@@ -422,6 +613,9 @@ func (t *Tools) sendEmail(ctx context.Context, account string, to, cc []string,
 		}
 	}
 
+	if len(attachments) > 0 {
+		return fmt.Sprintf("Email sent to %s — subject: %s (%d attachment(s))", strings.Join(to, ", "), subject, len(attachments)), nil
+	}
 	return fmt.Sprintf("Email sent to %s — subject: %s", strings.Join(to, ", "), subject), nil
 }
 
@@ -494,3 +688,21 @@ func formatFolderList(folders []Folder) string {
 
 	return sb.String()
 }
+
+func formatAttachmentList(listing *MessageAttachments, maxBytes int64) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Message %d from %s has %d attachment(s):\n\n", listing.UID, listing.From, len(listing.Attachments)))
+
+	for _, a := range listing.Attachments {
+		sb.WriteString(fmt.Sprintf("Part: %s\n", a.Part))
+		sb.WriteString(fmt.Sprintf("Filename: %s\n", a.displayName()))
+		sb.WriteString(fmt.Sprintf("Type: %s\n", a.ContentType))
+		sb.WriteString(fmt.Sprintf("Size: about %d bytes", a.DecodedSizeEstimate()))
+		if maxBytes > 0 && a.DecodedSizeEstimate() > maxBytes {
+			sb.WriteString(" (over the download limit)")
+		}
+		sb.WriteString("\n\n")
+	}
+
+	return sb.String()
+}
@@ -54,10 +54,10 @@ func CheckRecipientTrust(cr ContactResolver, addresses []string) TrustResult {
 			continue
 		}
 
-		switch zone {
-		case "admin", "household", "trusted":
+		switch {
+		case trustedZone(zone):
 			result.Allowed = append(result.Allowed, addr)
-		case "known":
+		case zone == "known":
 			result.Warnings = append(result.Warnings,
 				fmt.Sprintf("Contact for %s is 'known' trust level — confirm with user before sending.", bare))
 		default:
@@ -69,6 +69,45 @@ func CheckRecipientTrust(cr ContactResolver, addresses []string) TrustResult {
 	return result
 }
 
+// CheckSenderTrust evaluates the sender of a message before its content
+// (such as an attachment) is fetched for the agent. It applies the same
+// zones as [CheckRecipientTrust]: admin, household, and trusted
+// contacts pass; known contacts need user confirmation; anyone else is
+// refused. If cr is nil, every sender passes.
+func CheckSenderTrust(cr ContactResolver, sender string) error {
+	if cr == nil {
+		return nil
+	}
+
+	bare := extractAddress(sender)
+	zone, found, err := cr.ResolveTrustZone(bare)
+	if err != nil {
+		return fmt.Errorf("cannot verify sender %s: contact lookup failed: %w", bare, err)
+	}
+	if !found {
+		return fmt.Errorf("sender %s has no contact record; add with contact_save first", bare)
+	}
+
+	switch {
+	case trustedZone(zone):
+		return nil
+	case zone == "known":
+		return fmt.Errorf("sender %s is 'known' trust level — confirm with user before downloading", bare)
+	default:
+		return fmt.Errorf("sender %s has unrecognized trust zone %q", bare, zone)
+	}
+}
+
+// trustedZone reports whether a contact in zone can be emailed, or have
+// its messages' content fetched, without user confirmation.
+func trustedZone(zone string) bool {
+	switch zone {
+	case "admin", "household", "trusted":
+		return true
+	}
+	return false
+}
+
 // HasIssues reports whether the trust check found any warnings or
 // blocked addresses that prevent immediate sending.
 func (tr TrustResult) HasIssues() bool {
@@ -129,6 +129,28 @@ func TestCheckRecipientTrust_Error(t *testing.T) {
 	}
 }
 
+func TestCheckSenderTrust(t *testing.T) {
+	resolver := &mockResolver{
+		zones: map[string]string{
+			"trusted@example.com": "trusted",
+			"known@example.com":   "known",
+			"broken@example.com":  "error",
+		},
+	}
+
+	if err := CheckSenderTrust(resolver, "Billing <trusted@example.com>"); err != nil {
+		t.Errorf("trusted sender: %v", err)
+	}
+	for _, sender := range []string{"known@example.com", "stranger@example.com", "broken@example.com"} {
+		if err := CheckSenderTrust(resolver, sender); err == nil {
+			t.Errorf("CheckSenderTrust(%q) = nil, want error", sender)
+		}
+	}
+	if err := CheckSenderTrust(nil, "stranger@example.com"); err != nil {
+		t.Errorf("nil resolver: %v", err)
+	}
+}
+
 func TestTrustResult_HasIssues(t *testing.T) {
 	clean := TrustResult{Allowed: []string{"a@test.com"}}
 	if clean.HasIssues() {
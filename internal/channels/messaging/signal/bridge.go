@@ -10,6 +10,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -45,7 +46,7 @@ type ContactResolver interface {
 // configured.
 const defaultHandleTimeout = 10 * time.Minute
 
-// rateWindow is the sliding window for per-sender rate limiting.
+// rateWindow is the sliding window for per-chat rate limiting.
 const rateWindow = time.Minute
 
 // cleanupInterval controls how often stale rate-limit entries are
@@ -59,10 +60,11 @@ const typingRefreshInterval = 10 * time.Second
 
 const signalMailboxRehydratePeekLimit = 16
 
-// lastMessage tracks a sender's most recent inbound message timestamp
-// along with when we received it, for bounded cleanup.
+// lastMessage tracks a chat's most recent inbound message timestamp
+// and author along with when we received it, for bounded cleanup.
 type lastMessage struct {
 	signalTS   int64     // signal-cli message timestamp
+	author     string    // sender's phone number; differs from the chat in groups
 	receivedAt time.Time // wall clock when we stored it
 }
 
@@ -95,8 +97,9 @@ type BridgeConfig struct {
 	Client           *Client
 	Runner           AgentRunner
 	Logger           *slog.Logger
-	RateLimit        int                                                               // per sender per minute; 0 = unlimited
+	RateLimit        int                                                               // per chat per minute; 0 = unlimited
 	HandleTimeout    time.Duration                                                     // per-message processing timeout; 0 = defaultHandleTimeout
+	DisableTyping    bool                                                              // true suppresses typing indicators while a turn runs
 	Routing          config.SignalRoutingConfig                                        // model selection and routing hints
 	Resolver         ContactResolver                                                   // nil disables phone→name resolution
 	BindConversation func(conversationID string, binding *memory.ChannelBinding) error // nil disables conversation binding persistence
@@ -116,6 +119,7 @@ type Bridge struct {
 	logger           *slog.Logger
 	rateLimit        int
 	handleTimeout    time.Duration
+	disableTyping    bool
 	routing          config.SignalRoutingConfig
 	resolver         ContactResolver
 	bindConversation func(conversationID string, binding *memory.ChannelBinding) error
@@ -129,9 +133,10 @@ type Bridge struct {
 	mu            sync.Mutex
 	senderTimes   map[string][]time.Time
 	lastCleanup   time.Time
-	lastInboundTS map[string]lastMessage // most recent message per sender
-	senderLoops   map[string]string      // sender -> child loop ID
-	parentID      string                 // loop ID of the parent signal node
+	lastInboundTS map[string]lastMessage   // most recent message per chat
+	unread        map[string][]lastMessage // chat -> messages awaiting a read receipt
+	senderLoops   map[string]string        // chat address -> child loop ID
+	parentID      string                   // loop ID of the parent signal node
 }
 
 // NewBridge creates a Signal message bridge.
@@ -159,6 +164,7 @@ func NewBridge(cfg BridgeConfig) *Bridge {
 		logger:           logger,
 		rateLimit:        cfg.RateLimit,
 		handleTimeout:    handleTimeout,
+		disableTyping:    cfg.DisableTyping,
 		routing:          cfg.Routing,
 		resolver:         cfg.Resolver,
 		bindConversation: cfg.BindConversation,
@@ -170,20 +176,33 @@ func NewBridge(cfg BridgeConfig) *Bridge {
 		eventBus:         cfg.EventBus,
 		senderTimes:      make(map[string][]time.Time),
 		lastInboundTS:    make(map[string]lastMessage),
+		unread:           make(map[string][]lastMessage),
 		senderLoops:      make(map[string]string),
 	}
 }
 
 // LastInboundTimestamp returns the most recent message timestamp
-// received from the given sender. The tool handler uses this to
-// resolve the "latest" sentinel for reactions.
-func (b *Bridge) LastInboundTimestamp(sender string) (int64, bool) {
+// received in the given chat (a phone number or group address). The
+// tool handler uses this to resolve the "latest" sentinel for
+// reactions.
+func (b *Bridge) LastInboundTimestamp(chat string) (int64, bool) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	lm, ok := b.lastInboundTS[sender]
+	lm, ok := b.lastInboundTS[chat]
 	return lm.signalTS, ok
 }
 
+// LastInboundAuthor returns the phone number of whoever sent the most
+// recent message in the given chat. For a direct chat that is the
+// chat itself; for a group it is the member a "latest" reaction
+// targets.
+func (b *Bridge) LastInboundAuthor(chat string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	lm, ok := b.lastInboundTS[chat]
+	return lm.author, ok && lm.author != ""
+}
+
 // Register spawns the parent signal loop and returns. Inbound messages
 // are received event-driven via the signal-cli client and dispatched
 // to per-sender child loops. Returns an error if the parent loop
@@ -262,7 +281,9 @@ func (b *Bridge) Start(ctx context.Context) {
 
 // dispatch is the parent loop handler. It filters envelopes
 // (empty source, reactions, no content, rate limits) and fans out
-// valid messages to per-sender child loop mailboxes.
+// valid messages to per-chat child loop mailboxes. A chat is the
+// sender for a direct message and the group for a group message, so
+// each group gets its own loop, conversation, and rate limit.
 func (b *Bridge) dispatch(ctx context.Context, event any) error {
 	env, ok := event.(*Envelope)
 	if !ok {
@@ -281,6 +302,7 @@ func (b *Bridge) dispatch(ctx context.Context, event any) error {
 		}
 		return nil
 	}
+	chat := chatAddress(env)
 
 	// Reactions are carried inside dataMessage but have no
 	// text. Intercept them before the content filter.
@@ -294,9 +316,10 @@ func (b *Bridge) dispatch(ctx context.Context, event any) error {
 			}
 			return nil
 		}
-		if !b.allowSender(env.Source) {
+		if !b.allowSender(chat) {
 			b.logger.Warn("signal reaction rate-limited",
 				"sender", env.Source,
+				"chat", chat,
 			)
 			if summary != nil {
 				summary["action"] = "rate_limited"
@@ -327,8 +350,9 @@ func (b *Bridge) dispatch(ctx context.Context, event any) error {
 			ts = env.DataMessage.Timestamp
 		}
 		b.mu.Lock()
-		b.lastInboundTS[env.Source] = lastMessage{
+		b.lastInboundTS[chat] = lastMessage{
 			signalTS:   ts,
+			author:     env.Source,
 			receivedAt: time.Now(),
 		}
 		b.mu.Unlock()
@@ -345,9 +369,10 @@ func (b *Bridge) dispatch(ctx context.Context, event any) error {
 		return nil
 	}
 
-	if !b.allowSender(env.Source) {
+	if !b.allowSender(chat) {
 		b.logger.Warn("signal message rate-limited",
 			"sender", env.Source,
+			"chat", chat,
 		)
 		if summary != nil {
 			summary["action"] = "rate_limited"
@@ -356,9 +381,9 @@ func (b *Bridge) dispatch(ctx context.Context, event any) error {
 		return nil
 	}
 
-	// Fan out to per-sender child loop.
+	// Fan out to the chat's child loop. The read receipt follows the
+	// reply; see [Bridge.sendReadReceipts].
 	if !b.enqueueSenderEnvelope(ctx, env) {
-		// Don't send a read receipt for messages we failed to enqueue.
 		return nil
 	}
 
@@ -366,21 +391,6 @@ func (b *Bridge) dispatch(ctx context.Context, event any) error {
 		summary["action"] = "dispatched"
 		summary["sender"] = env.Source
 	}
-
-	// Acknowledge receipt only after successful enqueue so we don't
-	// ack messages that were silently dropped.
-	receiptTS := env.Timestamp
-	if env.DataMessage != nil && env.DataMessage.Timestamp != 0 {
-		receiptTS = env.DataMessage.Timestamp
-	}
-
-	if err := b.client.SendReceipt(ctx, env.Source, receiptTS); err != nil {
-		b.logger.Warn("signal read receipt failed",
-			"sender", env.Source,
-			"error", err,
-		)
-	}
-
 	return nil
 }
 
@@ -390,11 +400,10 @@ func (b *Bridge) enqueueSenderEnvelope(ctx context.Context, env *Envelope) bool
 	}
 	if b.registry == nil || b.mailbox == nil {
 		// Legacy path with no durable mailbox: run the turn inline and
-		// only report success when it actually handled the message, so
-		// the caller does not send a read receipt for a turn we could
-		// not process (there is no queue here to retry from).
+		// only report success when it actually handled the message
+		// (there is no queue here to retry from).
 		if err := b.handleEnvelope(ctx, env, nil); err != nil {
-			b.logger.Warn("signal legacy turn failed, not acking message",
+			b.logger.Warn("signal legacy turn failed",
 				"sender", env.Source,
 				"error", err,
 			)
@@ -410,7 +419,8 @@ func (b *Bridge) enqueueSenderEnvelope(ctx context.Context, env *Envelope) bool
 		)
 		return false
 	}
-	loopName := signalLoopName(env.Source)
+	chat := chatAddress(env)
+	loopName := signalLoopName(chat)
 	if _, err := b.mailbox.Enqueue(ctx, loopName, "signal", payload); err != nil {
 		b.logger.Warn("signal mailbox enqueue failed, dropping message",
 			"sender", env.Source,
@@ -419,7 +429,7 @@ func (b *Bridge) enqueueSenderEnvelope(ctx context.Context, env *Envelope) bool
 		)
 		return false
 	}
-	loopID, _, ok := b.ensureSenderLoop(ctx, env.Source)
+	loopID, _, ok := b.ensureSenderLoop(ctx, chat)
 	if !ok {
 		b.logger.Warn("signal mailbox item queued without live sender loop",
 			"sender", env.Source,
@@ -433,8 +443,8 @@ func (b *Bridge) enqueueSenderEnvelope(ctx context.Context, env *Envelope) bool
 			"loop_id", loopID,
 			"error", err,
 		)
-		b.forgetSenderLoop(env.Source, loopID)
-		if retryID, _, retryOK := b.ensureSenderLoop(ctx, env.Source); retryOK {
+		b.forgetSenderLoop(chat, loopID)
+		if retryID, _, retryOK := b.ensureSenderLoop(ctx, chat); retryOK {
 			if retryErr := b.registry.WakeLoop(ctx, retryID); retryErr != nil {
 				b.logger.Warn("signal sender loop retry wake failed",
 					"sender", env.Source,
@@ -447,8 +457,9 @@ func (b *Bridge) enqueueSenderEnvelope(ctx context.Context, env *Envelope) bool
 	return true
 }
 
-// ensureSenderLoop creates a per-sender child loop if one does not
-// already exist. The child loop wakes from its durable mailbox and
+// ensureSenderLoop creates a per-chat child loop if one does not
+// already exist. sender is the chat address: a phone number or a
+// [GroupAddress]. The child loop wakes from its durable mailbox and
 // processes all drained envelopes in one TurnBuilder pass.
 func (b *Bridge) ensureSenderLoop(ctx context.Context, sender string) (string, string, bool) {
 	loopName := signalLoopName(sender)
@@ -622,6 +633,8 @@ func (b *Bridge) rehydrateSenderLoops(ctx context.Context) error {
 	return nil
 }
 
+// firstSignalMailboxSender returns the chat address of the first
+// decodable envelope in a mailbox partition.
 func firstSignalMailboxSender(items []loop.MailboxItem) string {
 	for _, item := range items {
 		var env Envelope
@@ -629,12 +642,33 @@ func firstSignalMailboxSender(items []loop.MailboxItem) string {
 			continue
 		}
 		if strings.TrimSpace(env.Source) != "" {
-			return env.Source
+			return chatAddress(&env)
 		}
 	}
 	return ""
 }
 
+// chatAddress returns the address of the chat an envelope belongs to:
+// the [GroupAddress] for a group message, otherwise the sender's
+// phone number. Replies, typing indicators, loops, and rate limits
+// are all keyed by chat.
+func chatAddress(env *Envelope) string {
+	if env.DataMessage != nil && env.DataMessage.GroupInfo != nil && env.DataMessage.GroupInfo.GroupID != "" {
+		return GroupAddress(env.DataMessage.GroupInfo.GroupID)
+	}
+	return env.Source
+}
+
+// signalConversationID returns the conversation ID for a chat. Group
+// chats get their own namespace so a group never shares history or
+// idle-session rotation with a member's direct chat.
+func signalConversationID(chat string) string {
+	if groupID, ok := groupIDFromAddress(chat); ok {
+		return "signal-group-" + sanitizePhone(groupID)
+	}
+	return "signal-" + sanitizePhone(chat)
+}
+
 // handleMessage processes a single inbound Signal message through the
 // loop-facing request path. The progressFn, if non-nil, is used by the
 // legacy no-registry path to forward in-flight events to loop telemetry.
@@ -664,9 +698,8 @@ func (b *Bridge) handleReaction(ctx context.Context, env *Envelope) {
 
 // handleEnvelope prepares and runs one Signal turn on the legacy
 // no-mailbox path. It returns an error when the turn could not be
-// prepared or the runner failed, so callers gate the read receipt on
-// actual handling instead of acking a message they could not process.
-// A nil turn (nothing to answer) is a successful no-op.
+// prepared or the runner failed. A nil turn (nothing to answer) is a
+// successful no-op.
 func (b *Bridge) handleEnvelope(ctx context.Context, env *Envelope, progressFn func(string, map[string]any)) error {
 	turn, err := b.prepareSignalTurn(ctx, env)
 	if err != nil {
@@ -697,7 +730,7 @@ func (b *Bridge) prepareSignalTurn(ctx context.Context, env *Envelope) (*loop.Ag
 		}
 		return b.prepareReactionTurn(ctx, env)
 	}
-	scaffold := b.prepareSignalTurnScaffold(env.Source)
+	scaffold := b.prepareSignalTurnScaffold(chatAddress(env))
 	msg, summary, ok, err := b.renderEnvelope(ctx, scaffold, env)
 	if err != nil || !ok {
 		return nil, err
@@ -791,7 +824,7 @@ func (b *Bridge) prepareLoopNotificationTurn(ctx context.Context, sender string,
 	if summary == "" {
 		return nil, nil
 	}
-	convID := fmt.Sprintf("signal-%s", sanitizePhone(sender))
+	convID := signalConversationID(sender)
 	channelBinding := b.resolveBinding(sender)
 	if b.bindConversation != nil && channelBinding != nil {
 		if err := b.bindConversation(convID, channelBinding); err != nil {
@@ -827,7 +860,8 @@ func (b *Bridge) prepareLoopNotificationTurn(ctx context.Context, sender string,
 }
 
 type signalTurnScaffold struct {
-	sender         string
+	sender         string // chat address
+	groupName      string // friendly group name from the contact resolver
 	convID         string
 	log            *slog.Logger
 	channelBinding *memory.ChannelBinding
@@ -835,8 +869,12 @@ type signalTurnScaffold struct {
 }
 
 func (b *Bridge) prepareSignalTurnScaffold(sender string) signalTurnScaffold {
-	convID := fmt.Sprintf("signal-%s", sanitizePhone(sender))
+	convID := signalConversationID(sender)
 	channelBinding := b.resolveBinding(sender)
+	var groupName string
+	if _, ok := groupIDFromAddress(sender); ok && channelBinding != nil {
+		groupName = channelBinding.ContactName
+	}
 	log := b.logger.With(
 		"subsystem", logging.SubsystemSignal,
 		"conversation_id", convID,
@@ -849,6 +887,7 @@ func (b *Bridge) prepareSignalTurnScaffold(sender string) signalTurnScaffold {
 	}
 	return signalTurnScaffold{
 		sender:         sender,
+		groupName:      groupName,
 		convID:         convID,
 		log:            log,
 		channelBinding: channelBinding,
@@ -925,10 +964,10 @@ func (b *Bridge) renderMessageEnvelope(ctx context.Context, scaffold signalTurnS
 				msgTS = env.DataMessage.Timestamp
 			}
 			receivedAt := time.UnixMilli(msgTS)
-			attachmentDescs = b.processAttachments(ctx, env.DataMessage.Attachments, scaffold.sender, scaffold.convID, receivedAt)
+			attachmentDescs = b.processAttachments(ctx, env.DataMessage.Attachments, env.Source, scaffold.convID, receivedAt)
 		}
 	}
-	content := formatMessage(env, attachmentDescs)
+	content := formatMessage(env, scaffold.groupName, attachmentDescs)
 	scaffold.log.Info("signal message received",
 		"message_len", len(env.DataMessage.Message),
 		"attachments", len(env.DataMessage.Attachments),
@@ -941,8 +980,14 @@ func (b *Bridge) renderMessageEnvelope(ctx context.Context, scaffold signalTurnS
 	b.mu.Lock()
 	b.lastInboundTS[scaffold.sender] = lastMessage{
 		signalTS:   ts,
+		author:     env.Source,
 		receivedAt: time.Now(),
 	}
+	if !slices.ContainsFunc(b.unread[scaffold.sender], func(m lastMessage) bool {
+		return m.signalTS == ts && m.author == env.Source
+	}) {
+		b.unread[scaffold.sender] = append(b.unread[scaffold.sender], lastMessage{signalTS: ts, author: env.Source})
+	}
 	b.mu.Unlock()
 
 	return loop.Message{Role: "user", Content: content}, map[string]any{
@@ -998,12 +1043,13 @@ func intSummary(summary map[string]any, key string) int {
 }
 
 func (b *Bridge) prepareReactionTurn(_ context.Context, env *Envelope) (*loop.AgentTurn, error) {
-	scaffold := b.prepareSignalTurnScaffold(env.Source)
+	chat := chatAddress(env)
+	scaffold := b.prepareSignalTurnScaffold(chat)
 	reaction := signalReactionEvent(env)
 	hints := reaction.Hints()
 	hints["source"] = "signal"
-	hints["sender"] = env.Source
-	scaffold.opts = b.requestOptions(env.Source, hints)
+	hints["sender"] = chat
+	scaffold.opts = b.requestOptions(chat, hints)
 	msg, summary, ok, err := b.renderReactionEnvelope(scaffold, env)
 	if err != nil || !ok {
 		return nil, err
@@ -1087,9 +1133,11 @@ func (r signalResponseRunner) Run(ctx context.Context, req loop.Request, stream
 
 	if agentAlreadySent(resp.ToolsUsed) {
 		log.Info("signal reply already sent by agent tool call")
+		b.sendReadReceipts(runCtx, sender)
 		return resp, nil
 	}
 	if resp.Content == "" || sender == "" {
+		b.sendReadReceipts(runCtx, sender)
 		return resp, nil
 	}
 
@@ -1106,10 +1154,39 @@ func (r signalResponseRunner) Run(ctx context.Context, req loop.Request, stream
 	}
 
 	log.Info("signal reply sent")
+	b.sendReadReceipts(runCtx, sender)
 	return resp, nil
 }
 
+// sendReadReceipts marks the messages a completed turn in chat answered
+// as read. It runs only once the turn has finished and its reply, if
+// any, is sent, so a message that failed is left unread. Receipts go to
+// each message's author even in a group.
+func (b *Bridge) sendReadReceipts(ctx context.Context, chat string) {
+	b.mu.Lock()
+	unread := b.unread[chat]
+	delete(b.unread, chat)
+	b.mu.Unlock()
+
+	if b.client == nil {
+		return
+	}
+	for _, m := range unread {
+		if err := b.client.SendReceipt(ctx, m.author, m.signalTS); err != nil {
+			b.logger.Warn("signal read receipt failed",
+				"sender", m.author,
+				"error", err,
+			)
+		}
+	}
+}
+
+// activityIndicator returns the typing indicator shown in the chat
+// while a turn runs. It is a no-op when typing indicators are disabled.
 func (b *Bridge) activityIndicator(recipient string) messages.ActivityIndicator {
+	if b.disableTyping {
+		return messages.ActivityIndicator{}
+	}
 	sendTyping := func(ctx context.Context, stop bool) error {
 		if b.client == nil || recipient == "" {
 			return nil
@@ -1132,6 +1209,9 @@ func (b *Bridge) activityIndicator(recipient string) messages.ActivityIndicator
 	}
 }
 
+// requestOptions builds the routing options for a turn in the given
+// chat. Group chats carry group_id, and group_name when the contact
+// resolver knows the group, in place of sender_name.
 func (b *Bridge) requestOptions(sender string, extraHints map[string]string) router.RequestOptions {
 	seed := b.routing.LoopProfile()
 	opts := seed.RequestOptions()
@@ -1144,12 +1224,24 @@ func (b *Bridge) requestOptions(sender string, extraHints map[string]string) rou
 		}
 	}
 
+	groupID, isGroup := groupIDFromAddress(sender)
+	if isGroup {
+		if opts.RoutingFactors == nil {
+			opts.RoutingFactors = make(map[string]string, 2)
+		}
+		opts.RoutingFactors["group_id"] = groupID
+	}
+
 	if b.resolver != nil {
 		if binding := b.resolveBinding(sender); binding != nil && binding.ContactName != "" {
 			if opts.RoutingFactors == nil {
 				opts.RoutingFactors = make(map[string]string, 1)
 			}
-			opts.RoutingFactors["sender_name"] = binding.ContactName
+			if isGroup {
+				opts.RoutingFactors["group_name"] = binding.ContactName
+			} else {
+				opts.RoutingFactors["sender_name"] = binding.ContactName
+			}
 		}
 	}
 
@@ -1184,8 +1276,9 @@ func bindingValue(binding *memory.ChannelBinding, pick func(*memory.ChannelBindi
 	return pick(binding)
 }
 
-// allowSender checks whether the sender is within the per-minute rate
-// limit. Returns true if the message should be processed.
+// allowSender checks whether the chat (a sender, or a whole group) is
+// within the per-minute rate limit. Returns true if the message should
+// be processed.
 func (b *Bridge) allowSender(senderID string) bool {
 	if b.rateLimit <= 0 {
 		return true
@@ -1262,8 +1355,10 @@ func sanitizePhone(phone string) string {
 // formatMessage builds the user-facing message content for the agent
 // loop from a received Signal envelope. The [ts:...] tag provides the
 // message timestamp so the agent can reference it for reactions.
-// Attachment descriptions are prepended before the message text.
-func formatMessage(env *Envelope, attachmentDescs []string) string {
+// Attachment descriptions are prepended before the message text. For
+// group messages, groupName is the resolver's friendly name for the
+// group; when empty, the name signal-cli reports is used if any.
+func formatMessage(env *Envelope, groupName string, attachmentDescs []string) string {
 	var sb strings.Builder
 	sender := env.Source
 	if env.SourceName != "" {
@@ -1277,8 +1372,15 @@ func formatMessage(env *Envelope, attachmentDescs []string) string {
 		ts = env.DataMessage.Timestamp
 	}
 
-	if env.DataMessage.GroupInfo != nil {
-		fmt.Fprintf(&sb, "Signal message from %s in group %s [ts:%d]:\n\n", sender, env.DataMessage.GroupInfo.GroupID, ts)
+	if group := env.DataMessage.GroupInfo; group != nil {
+		if groupName == "" {
+			groupName = group.GroupName
+		}
+		if groupName != "" {
+			fmt.Fprintf(&sb, "Signal message from %s in group %q (%s) [ts:%d]:\n\n", sender, groupName, group.GroupID, ts)
+		} else {
+			fmt.Fprintf(&sb, "Signal message from %s in group %s [ts:%d]:\n\n", sender, group.GroupID, ts)
+		}
 	} else {
 		fmt.Fprintf(&sb, "Signal message from %s [ts:%d]:\n\n", sender, ts)
 	}
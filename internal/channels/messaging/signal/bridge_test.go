@@ -12,6 +12,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -752,6 +753,187 @@ func TestBridge_GroupMessageIncludesGroupInfo(t *testing.T) {
 	}
 }
 
+func TestBridge_GroupMessageRoutesToGroupChat(t *testing.T) {
+	resolver := &mockResolver{contacts: map[string]string{
+		"+15551234567":              "Alice Smith",
+		GroupAddress("family+id/="): "Family",
+	}}
+	bridge, stdout, stdin, runner := bridgeHelper(t, func(cfg *BridgeConfig) {
+		cfg.Resolver = resolver
+	})
+
+	var mu sync.Mutex
+	var sent []map[string]any
+	go func() {
+		reader := bufio.NewReader(stdin)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if err != nil {
+				return
+			}
+			var req rpcRequest
+			if err := json.Unmarshal(line, &req); err != nil {
+				continue
+			}
+			if req.Method == "send" {
+				raw, _ := json.Marshal(req.Params)
+				var p map[string]any
+				_ = json.Unmarshal(raw, &p)
+				mu.Lock()
+				sent = append(sent, p)
+				mu.Unlock()
+			}
+			resp := `{"jsonrpc":"2.0","id":` + itoa(req.ID) + `,"result":{}}` + "\n"
+			if _, err := io.WriteString(stdout, resp); err != nil {
+				return
+			}
+		}
+	}()
+
+	env := &Envelope{
+		Source:    "+15551234567",
+		Timestamp: 1700000000000,
+		DataMessage: &DataMessage{
+			Message:   "Dinner at six?",
+			GroupInfo: &GroupInfo{GroupID: "family+id/=", Type: "DELIVER"},
+		},
+	}
+	bridge.handleMessage(context.Background(), env, nil)
+
+	req := runner.getLastReq()
+	if req == nil {
+		t.Fatal("runner.Run was not called")
+	}
+	if req.ConversationID != "signal-group-familyid" {
+		t.Errorf("ConversationID = %q, want signal-group-familyid", req.ConversationID)
+	}
+	if got := req.RoutingFactors["sender"]; got != GroupAddress("family+id/=") {
+		t.Errorf("sender hint = %q, want group address", got)
+	}
+	if got := req.RoutingFactors["group_name"]; got != "Family" {
+		t.Errorf("group_name hint = %q, want Family", got)
+	}
+	if _, exists := req.RoutingFactors["sender_name"]; exists {
+		t.Errorf("sender_name hint should not be set for a group chat, got %q", req.RoutingFactors["sender_name"])
+	}
+	if !strings.Contains(req.Messages[0].Content, `in group "Family" (family+id/=)`) {
+		t.Errorf("message should name the group: %q", req.Messages[0].Content)
+	}
+	if author, ok := bridge.LastInboundAuthor(GroupAddress("family+id/=")); !ok || author != "+15551234567" {
+		t.Errorf("LastInboundAuthor = %q, %v; want the sending member", author, ok)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sent) != 1 {
+		t.Fatalf("send calls = %d, want 1", len(sent))
+	}
+	if sent[0]["groupId"] != "family+id/=" {
+		t.Errorf("reply groupId = %v, want family+id/=", sent[0]["groupId"])
+	}
+	if _, ok := sent[0]["recipient"]; ok {
+		t.Errorf("group reply should not set recipient: %v", sent[0]["recipient"])
+	}
+}
+
+func TestBridge_GroupRateLimitIsPerGroup(t *testing.T) {
+	bridge, stdout, stdin, runner := bridgeHelper(t, func(cfg *BridgeConfig) {
+		cfg.RateLimit = 1
+	})
+	go drainRPCRequests(t, stdin, stdout)
+
+	groupMsg := func(sender, groupID string) *Envelope {
+		return &Envelope{
+			Source:    sender,
+			Timestamp: 1700000000000,
+			DataMessage: &DataMessage{
+				Message:   "hi",
+				GroupInfo: &GroupInfo{GroupID: groupID},
+			},
+		}
+	}
+
+	ctx := context.Background()
+	runs := func() string {
+		if req := runner.getLastReq(); req != nil {
+			return req.ConversationID
+		}
+		return ""
+	}
+
+	if err := bridge.dispatch(ctx, groupMsg("+15551111111", "g1")); err != nil {
+		t.Fatal(err)
+	}
+	if got := runs(); got != "signal-group-g1" {
+		t.Fatalf("first group message ran in %q", got)
+	}
+
+	// A second member in the same group shares the group's budget.
+	runner.mu.Lock()
+	runner.lastReq = nil
+	runner.mu.Unlock()
+	if err := bridge.dispatch(ctx, groupMsg("+15552222222", "g1")); err != nil {
+		t.Fatal(err)
+	}
+	if got := runs(); got != "" {
+		t.Errorf("second message in a rate-limited group ran in %q", got)
+	}
+
+	// Another group and the member's direct chat are unaffected.
+	if err := bridge.dispatch(ctx, groupMsg("+15552222222", "g2")); err != nil {
+		t.Fatal(err)
+	}
+	if got := runs(); got != "signal-group-g2" {
+		t.Errorf("other group ran in %q, want signal-group-g2", got)
+	}
+	direct := &Envelope{
+		Source:      "+15551111111",
+		Timestamp:   1700000000000,
+		DataMessage: &DataMessage{Message: "hi"},
+	}
+	if err := bridge.dispatch(ctx, direct); err != nil {
+		t.Fatal(err)
+	}
+	if got := runs(); got != "signal-15551111111" {
+		t.Errorf("direct chat ran in %q, want signal-15551111111", got)
+	}
+}
+
+func TestFirstSignalMailboxSender_Group(t *testing.T) {
+	payload, err := json.Marshal(&Envelope{
+		Source: "+15551234567",
+		DataMessage: &DataMessage{
+			Message:   "hi",
+			GroupInfo: &GroupInfo{GroupID: "g1"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := firstSignalMailboxSender([]loop.MailboxItem{{Payload: payload}})
+	if got != GroupAddress("g1") {
+		t.Errorf("firstSignalMailboxSender = %q, want %q", got, GroupAddress("g1"))
+	}
+	if signalLoopName(got) == signalLoopName("+15551234567") {
+		t.Error("group and member direct chat share a loop name")
+	}
+}
+
+func TestSignalConversationID(t *testing.T) {
+	tests := []struct {
+		chat string
+		want string
+	}{
+		{chat: "+15551234567", want: "signal-15551234567"},
+		{chat: GroupAddress("abc+/="), want: "signal-group-abc"},
+	}
+	for _, tt := range tests {
+		if got := signalConversationID(tt.chat); got != tt.want {
+			t.Errorf("signalConversationID(%q) = %q, want %q", tt.chat, got, tt.want)
+		}
+	}
+}
+
 func TestBridge_RateLimitDropsMessages(t *testing.T) {
 	bridge, _, _, _ := bridgeHelper(t, func(cfg *BridgeConfig) {
 		cfg.RateLimit = 2
@@ -840,6 +1022,99 @@ func TestBridge_AgentAlreadySentSkipsDuplicateReply(t *testing.T) {
 	}
 }
 
+// recordRPCMethods answers every RPC request with an empty result and
+// returns a func reporting the methods called so far, in order.
+func recordRPCMethods(stdin io.Reader, stdout io.Writer) func() []string {
+	var mu sync.Mutex
+	var methods []string
+	go func() {
+		reader := bufio.NewReader(stdin)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if err != nil {
+				return
+			}
+			var req rpcRequest
+			if err := json.Unmarshal(line, &req); err != nil {
+				continue
+			}
+			mu.Lock()
+			methods = append(methods, req.Method)
+			mu.Unlock()
+			resp := `{"jsonrpc":"2.0","id":` + itoa(req.ID) + `,"result":{}}` + "\n"
+			if _, err := io.WriteString(stdout, resp); err != nil {
+				return
+			}
+		}
+	}()
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(methods)
+	}
+}
+
+func TestBridge_DisableTypingSkipsIndicator(t *testing.T) {
+	bridge, stdout, stdin, _ := bridgeHelper(t, func(cfg *BridgeConfig) {
+		cfg.DisableTyping = true
+	})
+	recorded := recordRPCMethods(stdin, stdout)
+
+	env := &Envelope{
+		Source:      "+15551234567",
+		Timestamp:   1700000000000,
+		DataMessage: &DataMessage{Message: "Hello"},
+	}
+	if err := bridge.dispatch(context.Background(), env); err != nil {
+		t.Fatal(err)
+	}
+
+	methods := recorded()
+	if containsString(methods, "sendTyping") {
+		t.Errorf("sendTyping called with typing indicators disabled: %v", methods)
+	}
+	if !containsString(methods, "send") || !containsString(methods, "sendReceipt") {
+		t.Errorf("methods = %v, want reply and read receipt", methods)
+	}
+}
+
+func TestBridge_ReadReceiptFollowsReply(t *testing.T) {
+	env := &Envelope{
+		Source:      "+15551234567",
+		Timestamp:   1700000000000,
+		DataMessage: &DataMessage{Timestamp: 1700000000000, Message: "Hello"},
+	}
+
+	t.Run("receipt after reply", func(t *testing.T) {
+		bridge, stdout, stdin, _ := bridgeHelper(t)
+		recorded := recordRPCMethods(stdin, stdout)
+		if err := bridge.dispatch(context.Background(), env); err != nil {
+			t.Fatal(err)
+		}
+		methods := recorded()
+		send, receipt := slices.Index(methods, "send"), slices.Index(methods, "sendReceipt")
+		if send < 0 || receipt < send {
+			t.Errorf("methods = %v, want the read receipt after the reply", methods)
+		}
+	})
+
+	t.Run("failed turn stays unread", func(t *testing.T) {
+		client, stdout, stdin := pipeClient(t)
+		recorded := recordRPCMethods(stdin, stdout)
+		bridge := NewBridge(BridgeConfig{
+			Client: client,
+			Runner: &testRunner{err: errors.New("runner down")},
+			Logger: slog.Default(),
+		})
+		if err := bridge.dispatch(context.Background(), env); err != nil {
+			t.Fatal(err)
+		}
+		if methods := recorded(); containsString(methods, "sendReceipt") {
+			t.Errorf("methods = %v, want no read receipt for a failed turn", methods)
+		}
+	})
+}
+
 func TestSanitizePhone(t *testing.T) {
 	tests := []struct {
 		input string
@@ -867,7 +1142,7 @@ func TestFormatMessage_DirectMessage(t *testing.T) {
 			Message:   "Hello Thane",
 		},
 	}
-	got := formatMessage(env, nil)
+	got := formatMessage(env, "", nil)
 	if !strings.Contains(got, "+15551234567") {
 		t.Error("should contain sender number")
 	}
@@ -888,7 +1163,7 @@ func TestFormatMessage_WithSourceName(t *testing.T) {
 		SourceName:  "Alice",
 		DataMessage: &DataMessage{Message: "Hello"},
 	}
-	got := formatMessage(env, nil)
+	got := formatMessage(env, "", nil)
 	if !strings.Contains(got, "Alice") {
 		t.Error("should contain source name")
 	}
@@ -907,7 +1182,7 @@ func TestFormatMessage_GroupMessage(t *testing.T) {
 			GroupInfo: &GroupInfo{GroupID: "family-group-id"},
 		},
 	}
-	got := formatMessage(env, nil)
+	got := formatMessage(env, "", nil)
 	if !strings.Contains(got, "family-group-id") {
 		t.Error("should contain group ID")
 	}
@@ -919,6 +1194,23 @@ func TestFormatMessage_GroupMessage(t *testing.T) {
 	}
 }
 
+func TestFormatMessage_GroupName(t *testing.T) {
+	env := &Envelope{
+		Source:    "+15551234567",
+		Timestamp: 1700000000000,
+		DataMessage: &DataMessage{
+			Message:   "Group update",
+			GroupInfo: &GroupInfo{GroupID: "family-group-id", GroupName: "Smiths"},
+		},
+	}
+	if got := formatMessage(env, "", nil); !strings.Contains(got, `in group "Smiths" (family-group-id)`) {
+		t.Errorf("should fall back to signal-cli group name, got: %q", got)
+	}
+	if got := formatMessage(env, "Family", nil); !strings.Contains(got, `in group "Family" (family-group-id)`) {
+		t.Errorf("resolved group name should win, got: %q", got)
+	}
+}
+
 func TestAgentAlreadySent(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -1005,7 +1297,7 @@ func TestFormatMessage_PrefersDataMessageTimestamp(t *testing.T) {
 			Message:   "Hello",
 		},
 	}
-	got := formatMessage(env, nil)
+	got := formatMessage(env, "", nil)
 	if !strings.Contains(got, "[ts:2000]") {
 		t.Errorf("should prefer DataMessage.Timestamp, got: %q", got)
 	}
@@ -1023,7 +1315,7 @@ func TestFormatMessage_FallsBackToEnvelopeTimestamp(t *testing.T) {
 			Message:   "Hello",
 		},
 	}
-	got := formatMessage(env, nil)
+	got := formatMessage(env, "", nil)
 	if !strings.Contains(got, "[ts:3000]") {
 		t.Errorf("should fall back to envelope timestamp, got: %q", got)
 	}
@@ -1955,7 +2247,7 @@ func TestFormatMessage_WithAttachments(t *testing.T) {
 		},
 	}
 	descs := []string{"[Attachment: image/jpeg, 1000 bytes]"}
-	got := formatMessage(env, descs)
+	got := formatMessage(env, "", descs)
 
 	if !strings.Contains(got, "[Attachment: image/jpeg") {
 		t.Errorf("should contain attachment description, got: %q", got)
@@ -1975,7 +2267,7 @@ func TestFormatMessage_AttachmentOnlyNoText(t *testing.T) {
 		},
 	}
 	descs := []string{"[Attachment: audio/ogg, 5000 bytes]"}
-	got := formatMessage(env, descs)
+	got := formatMessage(env, "", descs)
 
 	if !strings.Contains(got, "[Attachment: audio/ogg") {
 		t.Errorf("should contain attachment description, got: %q", got)
@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"os"
 	"os/exec"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -165,13 +166,47 @@ func (c *Client) Messages() <-chan *Envelope {
 	return c.messages
 }
 
-// Send sends a text message to a recipient and returns the server
-// timestamp of the sent message.
+// GroupAddressPrefix marks a Signal address that names a group rather
+// than a phone number. The bridge keys group chats by
+// "group:<groupId>", and the client's send methods accept such an
+// address anywhere they take a recipient.
+const GroupAddressPrefix = "group:"
+
+// GroupAddress returns the address for the group with the given
+// signal-cli group ID.
+func GroupAddress(groupID string) string {
+	return GroupAddressPrefix + groupID
+}
+
+// groupIDFromAddress returns the group ID of a group address.
+func groupIDFromAddress(address string) (string, bool) {
+	id, ok := strings.CutPrefix(address, GroupAddressPrefix)
+	return id, ok && id != ""
+}
+
+// setRecipient addresses params to recipient: groupId for a group
+// address, otherwise the phone number. The send method takes a list
+// of recipients; the others take a single one.
+func setRecipient(params map[string]any, recipient string, list bool) {
+	if groupID, ok := groupIDFromAddress(recipient); ok {
+		params["groupId"] = groupID
+		return
+	}
+	if list {
+		params["recipient"] = []string{recipient}
+		return
+	}
+	params["recipient"] = recipient
+}
+
+// Send sends a text message to a phone number or group address and
+// returns the server timestamp of the sent message.
 func (c *Client) Send(ctx context.Context, recipient, message string) (int64, error) {
-	raw, err := c.call(ctx, "send", map[string]any{
-		"recipient": []string{recipient},
-		"message":   message,
-	})
+	params := map[string]any{
+		"message": message,
+	}
+	setRecipient(params, recipient, true)
+	raw, err := c.call(ctx, "send", params)
 	if err != nil {
 		return 0, fmt.Errorf("signal send: %w", err)
 	}
@@ -196,11 +231,11 @@ func (c *Client) SendReceipt(ctx context.Context, recipient string, timestamp in
 	return nil
 }
 
-// SendTyping sends a typing indicator start or stop.
+// SendTyping sends a typing indicator start or stop to a phone number
+// or group address.
 func (c *Client) SendTyping(ctx context.Context, recipient string, stop bool) error {
-	params := map[string]any{
-		"recipient": recipient,
-	}
+	params := map[string]any{}
+	setRecipient(params, recipient, false)
 	if stop {
 		params["stop"] = true
 	}
@@ -212,14 +247,15 @@ func (c *Client) SendTyping(ctx context.Context, recipient string, stop bool) er
 }
 
 // SendReaction sends or removes an emoji reaction on a specific
-// message identified by author and timestamp.
+// message identified by author and timestamp. The recipient is the
+// chat the message was sent in: a phone number or group address.
 func (c *Client) SendReaction(ctx context.Context, recipient, emoji, targetAuthor string, targetTimestamp int64, remove bool) error {
 	params := map[string]any{
-		"recipient":       recipient,
 		"emoji":           emoji,
 		"targetAuthor":    targetAuthor,
 		"targetTimestamp": targetTimestamp,
 	}
+	setRecipient(params, recipient, false)
 	if remove {
 		params["remove"] = true
 	}
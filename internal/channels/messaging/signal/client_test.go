@@ -200,6 +200,54 @@ func TestClient_SendMessage(t *testing.T) {
 	wg.Wait()
 }
 
+func TestClient_SendGroupMessage(t *testing.T) {
+	client, stdout, stdin := pipeClient(t)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		reader := bufio.NewReader(stdin)
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			t.Errorf("read request: %v", err)
+			return
+		}
+
+		var req rpcRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			t.Errorf("unmarshal request: %v", err)
+			return
+		}
+
+		params, _ := json.Marshal(req.Params)
+		var p map[string]any
+		json.Unmarshal(params, &p)
+
+		if p["groupId"] != "abc123groupid" {
+			t.Errorf("groupId = %v, want abc123groupid", p["groupId"])
+		}
+		if _, ok := p["recipient"]; ok {
+			t.Errorf("recipient = %v, want unset for a group send", p["recipient"])
+		}
+
+		resp := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{"timestamp":1631458509000}}`, req.ID) + "\n"
+		if _, err := io.WriteString(stdout, resp); err != nil {
+			t.Errorf("write response: %v", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.Send(ctx, GroupAddress("abc123groupid"), "Hello group"); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	wg.Wait()
+}
+
 func TestClient_SendReaction(t *testing.T) {
 	client, stdout, stdin := pipeClient(t)
 
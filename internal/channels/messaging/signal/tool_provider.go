@@ -56,20 +56,24 @@ func (p *ToolProvider) Tools() []*tools.Tool {
 	return []*tools.Tool{
 		{
 			Name:        "signal_send_message",
-			Description: "Send a Signal message to a phone number for proactive or out-of-band Signal delivery. Do not use this as the normal reply path inside an inbound Signal conversation; the Signal bridge sends final response text automatically.",
+			Description: "Send a Signal message to a phone number or group for proactive or out-of-band Signal delivery. Give exactly one of recipient or group_id. Do not use this as the normal reply path inside an inbound Signal conversation; the Signal bridge sends final response text automatically.",
 			Parameters: map[string]any{
 				"type": "object",
 				"properties": map[string]any{
 					"recipient": map[string]any{
 						"type":        "string",
-						"description": "Phone number including country code (e.g., +15551234567)",
+						"description": "Phone number including country code (e.g., +15551234567). Mutually exclusive with group_id.",
+					},
+					"group_id": map[string]any{
+						"type":        "string",
+						"description": "Signal group ID, as shown in inbound group messages. Mutually exclusive with recipient.",
 					},
 					"message": map[string]any{
 						"type":        "string",
 						"description": "Message text to send",
 					},
 				},
-				"required": []string{"recipient", "message"},
+				"required": []string{"message"},
 			},
 			Handler: p.handleSendMessage,
 		},
@@ -115,9 +119,27 @@ func (p *ToolProvider) handleSendMessage(ctx context.Context, args map[string]an
 	}
 
 	recipient, _ := args["recipient"].(string)
+	groupID, _ := args["group_id"].(string)
 	message, _ := args["message"].(string)
-	if recipient == "" || message == "" {
-		return "", fmt.Errorf("recipient and message are required")
+	recipient = strings.TrimSpace(recipient)
+	groupID = strings.TrimSpace(groupID)
+	if message == "" {
+		return "", fmt.Errorf("message is required")
+	}
+	switch {
+	case recipient != "" && groupID != "":
+		return "", fmt.Errorf("recipient and group_id are mutually exclusive")
+	case recipient == "" && groupID == "":
+		return "", fmt.Errorf("one of recipient or group_id is required")
+	case strings.HasPrefix(recipient, GroupAddressPrefix):
+		return "", fmt.Errorf("use group_id to send to a group")
+	}
+
+	if groupID != "" {
+		if _, err := client.Send(ctx, GroupAddress(groupID), message); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Message sent to group %s", groupID), nil
 	}
 	if _, err := client.Send(ctx, recipient, message); err != nil {
 		return "", err
@@ -130,7 +152,9 @@ func (p *ToolProvider) handleSendReaction(ctx context.Context, args map[string]a
 }
 
 // HandleChannelReaction adapts the normalized message-channel reaction
-// tool to Signal's native recipient/author/timestamp shape.
+// tool to Signal's native recipient/author/timestamp shape. In a group
+// chat the author is not the chat itself, so only the latest message
+// can be targeted and its author is taken from the bridge.
 func (p *ToolProvider) HandleChannelReaction(ctx context.Context, req tools.ChannelReactionRequest) (string, error) {
 	recipient := strings.TrimSpace(req.Recipient)
 	if recipient == "" {
@@ -140,11 +164,28 @@ func (p *ToolProvider) HandleChannelReaction(ctx context.Context, req tools.Chan
 	if emoji == "" {
 		return "", fmt.Errorf("emoji is required")
 	}
+	target := normalizeSignalReactionTarget(req.Target)
+	author := recipient
+	if _, ok := groupIDFromAddress(recipient); ok {
+		if target != "latest" {
+			return "", fmt.Errorf("in a Signal group, only the latest message can be reacted to")
+		}
+		p.mu.RLock()
+		bridge := p.bridge
+		p.mu.RUnlock()
+		if bridge != nil {
+			last, ok := bridge.LastInboundAuthor(recipient)
+			if !ok {
+				return "", fmt.Errorf("no recent inbound message in this Signal group to react to")
+			}
+			author = last
+		}
+	}
 	return p.sendReaction(ctx, map[string]any{
 		"recipient":        recipient,
 		"emoji":            emoji,
-		"target_author":    recipient,
-		"target_timestamp": normalizeSignalReactionTarget(req.Target),
+		"target_author":    author,
+		"target_timestamp": target,
 	})
 }
 
@@ -1,6 +1,10 @@
 package signal
 
-import "testing"
+import (
+	"context"
+	"strings"
+	"testing"
+)
 
 func TestNormalizeSignalReactionTarget(t *testing.T) {
 	tests := []struct {
@@ -22,3 +26,29 @@ func TestNormalizeSignalReactionTarget(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleSendMessage_RecipientOrGroup(t *testing.T) {
+	client, _, _ := pipeClient(t)
+	p := NewToolProvider()
+	p.Bind(client, nil)
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		wantErr string
+	}{
+		{name: "missing message", args: map[string]any{"recipient": "+15551234567"}, wantErr: "message is required"},
+		{name: "neither target", args: map[string]any{"message": "hi"}, wantErr: "one of recipient or group_id"},
+		{name: "both targets", args: map[string]any{"recipient": "+15551234567", "group_id": "g1", "message": "hi"}, wantErr: "mutually exclusive"},
+		{name: "group address as recipient", args: map[string]any{"recipient": GroupAddress("g1"), "message": "hi"}, wantErr: "use group_id"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := p.handleSendMessage(context.Background(), tc.args)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("handleSendMessage() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -53,9 +53,10 @@ type Attachment struct {
 
 // GroupInfo identifies the group a message was sent to.
 type GroupInfo struct {
-	GroupID  string `json:"groupId"`
-	Revision int    `json:"revision"`
-	Type     string `json:"type"` // e.g., "DELIVER"
+	GroupID   string `json:"groupId"`
+	GroupName string `json:"groupName,omitempty"` // sent by newer signal-cli releases
+	Revision  int    `json:"revision"`
+	Type      string `json:"type"` // e.g., "DELIVER"
 }
 
 // TypingMessage indicates that a contact started or stopped typing.
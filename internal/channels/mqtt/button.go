@@ -0,0 +1,204 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+)
+
+// buttonPressPayload is the payload Home Assistant publishes to a
+// button's command topic when it is pressed.
+const buttonPressPayload = "PRESS"
+
+// ButtonPressHandler runs when a registered button is pressed. It is
+// called off the MQTT receive path with the connection lifecycle
+// context.
+type ButtonPressHandler func(ctx context.Context) error
+
+// Button describes a Home Assistant button entity published via MQTT
+// discovery under the publisher's device. Register it with
+// [Publisher.RegisterButton].
+type Button struct {
+	// EntitySuffix is the unique suffix used in topic paths and
+	// entity IDs (e.g., "reflect" produces command topic
+	// thane/{device}/reflect/press).
+	EntitySuffix string
+
+	// Name is the entity name shown in the HA UI.
+	Name string
+
+	// Icon is an optional MDI icon (e.g., "mdi:head-lightbulb").
+	Icon string
+
+	// Press runs when the button is pressed.
+	Press ButtonPressHandler
+
+	// UnavailableReason, when non-empty, publishes the button as
+	// unavailable in HA with this reason as an attribute, and presses
+	// are ignored. Use it when a prerequisite is missing, so the
+	// operator can see why the button does not work instead of the
+	// entity silently never appearing.
+	UnavailableReason string
+}
+
+// ButtonConfig is the HA MQTT discovery payload for a button entity.
+// A button is available only while both the device availability topic
+// and its own availability topic report online.
+type ButtonConfig struct {
+	Name                string              `json:"name"`
+	ObjectID            string              `json:"object_id,omitempty"`
+	HasEntityName       bool                `json:"has_entity_name,omitempty"`
+	UniqueID            string              `json:"unique_id"`
+	CommandTopic        string              `json:"command_topic"`
+	PayloadPress        string              `json:"payload_press"`
+	Availability        []AvailabilityTopic `json:"availability"`
+	AvailabilityMode    string              `json:"availability_mode"`
+	JsonAttributesTopic string              `json:"json_attributes_topic,omitempty"`
+	Device              DeviceInfo          `json:"device"`
+	Icon                string              `json:"icon,omitempty"`
+	EntityCategory      string              `json:"entity_category,omitempty"`
+}
+
+// AvailabilityTopic is one entry in a discovery payload's availability
+// list.
+type AvailabilityTopic struct {
+	Topic string `json:"topic"`
+}
+
+// RegisterButton adds a button entity that is published via MQTT
+// discovery alongside the sensors and whose command topic is
+// subscribed on every (re-)connect. Must be called before
+// [Publisher.Connect].
+func (p *Publisher) RegisterButton(b Button) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.buttons = append(p.buttons, b)
+}
+
+// ButtonCommandTopic returns the topic HA publishes presses of the
+// given button to.
+func (p *Publisher) ButtonCommandTopic(entity string) string {
+	return p.baseTopic() + "/" + entity + "/press"
+}
+
+// buttonAvailabilityTopic returns the per-button availability topic
+// that carries the button's own online/offline status.
+func (p *Publisher) buttonAvailabilityTopic(entity string) string {
+	return p.baseTopic() + "/" + entity + "/availability"
+}
+
+// registeredButtons returns a snapshot of the registered buttons.
+func (p *Publisher) registeredButtons() []Button {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]Button, len(p.buttons))
+	copy(out, p.buttons)
+	return out
+}
+
+// buttonForTopic returns the button whose command topic is topic.
+func (p *Publisher) buttonForTopic(topic string) (Button, bool) {
+	for _, b := range p.registeredButtons() {
+		if p.ButtonCommandTopic(b.EntitySuffix) == topic {
+			return b, true
+		}
+	}
+	return Button{}, false
+}
+
+// buttonConfig builds the discovery payload for b.
+func (p *Publisher) buttonConfig(b Button) ButtonConfig {
+	return ButtonConfig{
+		Name:          b.Name,
+		ObjectID:      p.ObjectIDPrefix() + b.EntitySuffix,
+		HasEntityName: true,
+		UniqueID:      p.instanceID + "_" + b.EntitySuffix,
+		CommandTopic:  p.ButtonCommandTopic(b.EntitySuffix),
+		PayloadPress:  buttonPressPayload,
+		Availability: []AvailabilityTopic{
+			{Topic: p.AvailabilityTopic()},
+			{Topic: p.buttonAvailabilityTopic(b.EntitySuffix)},
+		},
+		AvailabilityMode:    "all",
+		JsonAttributesTopic: p.AttributesTopic(b.EntitySuffix),
+		Device:              p.device,
+		Icon:                b.Icon,
+	}
+}
+
+// publishButtonDiscovery publishes each button's discovery config, its
+// availability, and an attributes payload carrying the reason when it
+// is unavailable. It reports whether every discovery config was
+// published.
+func (p *Publisher) publishButtonDiscovery(ctx context.Context, cm *autopaho.ConnectionManager) bool {
+	ok := true
+	for _, b := range p.registeredButtons() {
+		if !p.publishRetained(ctx, cm, b.EntitySuffix, p.discoveryTopic("button", b.EntitySuffix), p.buttonConfig(b)) {
+			ok = false
+		}
+
+		status := "online"
+		if b.UnavailableReason != "" {
+			status = "offline"
+		}
+		p.publishRetained(ctx, cm, b.EntitySuffix, p.buttonAvailabilityTopic(b.EntitySuffix), status)
+		p.publishRetained(ctx, cm, b.EntitySuffix, p.AttributesTopic(b.EntitySuffix), map[string]string{
+			"unavailable_reason": b.UnavailableReason,
+		})
+	}
+	return ok
+}
+
+// publishRetained publishes a retained QoS 1 message. Strings are sent
+// verbatim; anything else is JSON-encoded. Failures are logged; the
+// result reports whether the publish succeeded.
+func (p *Publisher) publishRetained(ctx context.Context, cm *autopaho.ConnectionManager, entity, topic string, v any) bool {
+	var payload []byte
+	if s, ok := v.(string); ok {
+		payload = []byte(s)
+	} else {
+		data, err := json.Marshal(v)
+		if err != nil {
+			p.logger.Error("mqtt marshal payload", "entity", entity, "topic", topic, "error", err)
+			return false
+		}
+		payload = data
+	}
+	if _, err := cm.Publish(ctx, &paho.Publish{
+		Topic:   topic,
+		Payload: payload,
+		QoS:     1,
+		Retain:  true,
+	}); err != nil {
+		p.logger.Warn("mqtt publish failed", "entity", entity, "topic", topic, "error", err)
+		return false
+	}
+	p.logger.Debug("mqtt published", "entity", entity, "topic", topic)
+	return true
+}
+
+// handleButtonPress runs a button's press handler in its own
+// goroutine. Presses of an unavailable button are logged and dropped.
+func (p *Publisher) handleButtonPress(b Button, payload []byte) {
+	if string(payload) != buttonPressPayload {
+		p.logger.Debug("mqtt button ignored unexpected payload",
+			"entity", b.EntitySuffix, "payload", string(payload))
+		return
+	}
+	if b.UnavailableReason != "" || b.Press == nil {
+		p.logger.Warn("mqtt button pressed while unavailable",
+			"entity", b.EntitySuffix, "reason", b.UnavailableReason)
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(p.lifecycleContext(), time.Minute)
+		defer cancel()
+		p.logger.Info("mqtt button pressed", "entity", b.EntitySuffix)
+		if err := b.Press(ctx); err != nil {
+			p.logger.Error("mqtt button press failed", "entity", b.EntitySuffix, "error", err)
+		}
+	}()
+}
@@ -0,0 +1,104 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+)
+
+func TestPublisher_ButtonConfig(t *testing.T) {
+	p := New(config.MQTTConfig{DeviceName: "aimee-thane", DiscoveryPrefix: "homeassistant"}, "instance-123", NewDailyTokens(time.UTC), nil, nil)
+	b := Button{EntitySuffix: "reflect", Name: "Reflect Now", Icon: "mdi:head-lightbulb-outline"}
+
+	if got := p.discoveryTopic("button", b.EntitySuffix); got != "homeassistant/button/aimee-thane/reflect/config" {
+		t.Errorf("discovery topic = %q", got)
+	}
+
+	raw, err := json.Marshal(p.buttonConfig(b))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	want := map[string]any{
+		"object_id":         "aimee_thane_reflect",
+		"unique_id":         "instance-123_reflect",
+		"command_topic":     "thane/aimee-thane/reflect/press",
+		"payload_press":     "PRESS",
+		"availability_mode": "all",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+	avail, _ := got["availability"].([]any)
+	if len(avail) != 2 {
+		t.Fatalf("availability = %v, want device and button topics", got["availability"])
+	}
+	if topic := avail[0].(map[string]any)["topic"]; topic != p.AvailabilityTopic() {
+		t.Errorf("availability[0] = %v, want the shared device availability topic", topic)
+	}
+	device, _ := got["device"].(map[string]any)
+	if ids, _ := device["identifiers"].([]any); len(ids) != 1 || ids[0] != "instance-123" {
+		t.Errorf("device identifiers = %v, want the publisher's device", device["identifiers"])
+	}
+}
+
+func TestPublisher_ButtonSubscribeAndRouting(t *testing.T) {
+	cfg := config.MQTTConfig{Broker: "mqtt://localhost:1883", DeviceName: "test-thane"}
+	p := New(cfg, "instance-123", NewDailyTokens(time.UTC), nil, nil)
+
+	pressed := make(chan struct{}, 2)
+	p.RegisterButton(Button{
+		EntitySuffix: "reflect",
+		Name:         "Reflect Now",
+		Press: func(context.Context) error {
+			pressed <- struct{}{}
+			return nil
+		},
+	})
+	p.RegisterButton(Button{EntitySuffix: "broken", Name: "Broken", UnavailableReason: "missing prerequisite"})
+
+	topics := p.collectSubscribeTopics()
+	if len(topics) != 1 || topics[0] != p.ButtonCommandTopic("reflect") {
+		t.Errorf("collectSubscribeTopics() = %v, want only the available button's command topic", topics)
+	}
+
+	brokerURL, err := url.Parse(cfg.Broker)
+	if err != nil {
+		t.Fatalf("parse broker URL: %v", err)
+	}
+	pahoCfg := p.buildClientConfig(brokerURL)
+	if len(pahoCfg.OnPublishReceived) == 0 {
+		t.Fatal("OnPublishReceived should be registered when buttons are registered")
+	}
+	deliver := func(topic, payload string) {
+		t.Helper()
+		pr := paho.PublishReceived{Packet: &paho.Publish{Topic: topic, Payload: []byte(payload)}}
+		if _, err := pahoCfg.OnPublishReceived[0](pr); err != nil {
+			t.Fatalf("OnPublishReceived: %v", err)
+		}
+	}
+	deliver(p.ButtonCommandTopic("broken"), "PRESS")
+	deliver(p.ButtonCommandTopic("reflect"), "nonsense")
+	deliver(p.ButtonCommandTopic("reflect"), "PRESS")
+
+	select {
+	case <-pressed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("press handler was not invoked")
+	}
+	select {
+	case <-pressed:
+		t.Error("press handler ran more than once")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,209 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+)
+
+// WakeCommand is the JSON payload accepted on the command wake topic,
+// e.g. {"message": "Check the garage", "quality_floor": "5"}. It lets
+// a Home Assistant automation hand the agent a prompt directly.
+type WakeCommand struct {
+	// Message is the prompt the agent runs with. Required.
+	Message string
+
+	// QualityFloor is the minimum model quality rating (1–10) for the
+	// run. Zero leaves routing at its default. The payload may carry
+	// it as a number or a string, since HA templates render strings.
+	QualityFloor int
+}
+
+// ParseWakeCommand decodes and validates a command wake payload.
+func ParseWakeCommand(payload []byte) (WakeCommand, error) {
+	var raw struct {
+		Message      string          `json:"message"`
+		QualityFloor json.RawMessage `json:"quality_floor"`
+	}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return WakeCommand{}, fmt.Errorf("invalid wake command JSON: %w", err)
+	}
+	cmd := WakeCommand{Message: strings.TrimSpace(raw.Message)}
+	if cmd.Message == "" {
+		return WakeCommand{}, fmt.Errorf("wake command message is required")
+	}
+	if len(raw.QualityFloor) > 0 && string(raw.QualityFloor) != "null" {
+		text := strings.Trim(string(raw.QualityFloor), `"`)
+		n, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil {
+			return WakeCommand{}, fmt.Errorf("wake command quality_floor %s is not an integer", raw.QualityFloor)
+		}
+		if n < 1 || n > 10 {
+			return WakeCommand{}, fmt.Errorf("wake command quality_floor must be 1–10, got %d", n)
+		}
+		cmd.QualityFloor = n
+	}
+	return cmd, nil
+}
+
+// WakeCommandHandler runs a parsed wake command through the agent and
+// returns the response text. topic is the originating MQTT topic.
+// Implementations may block for the duration of the run; the
+// publisher calls them off the MQTT receive path.
+type WakeCommandHandler func(ctx context.Context, topic string, cmd WakeCommand) (string, error)
+
+// WakeCommandResult is the JSON payload published to the command
+// result topic after a wake command finishes.
+type WakeCommandResult struct {
+	Status   string `json:"status"` // "ok" or "error"
+	Message  string `json:"message"`
+	Response string `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// SetWakeCommandHandler enables the command wake topic
+// ([Publisher.CommandWakeTopic]). The publisher subscribes to it on
+// every (re-)connect, suppresses duplicate payloads within the
+// configured debounce window, and runs each command through h. When
+// mqtt.command_wake.publish_result is set, the outcome is published to
+// [Publisher.CommandWakeResultTopic]. Must be called before
+// [Publisher.Connect].
+func (p *Publisher) SetWakeCommandHandler(h WakeCommandHandler) {
+	p.wakeCommand = h
+	p.commandDebounce = newCommandDebouncer(p.cfg.CommandWake.Debounce)
+}
+
+// CommandWakeTopic returns the topic Home Assistant automations
+// publish wake commands to: thane/{device_name}/command/wake.
+func (p *Publisher) CommandWakeTopic() string {
+	return p.baseTopic() + "/command/wake"
+}
+
+// CommandWakeResultTopic returns the topic wake command outcomes are
+// published to: thane/{device_name}/command/wake/result.
+func (p *Publisher) CommandWakeResultTopic() string {
+	return p.CommandWakeTopic() + "/result"
+}
+
+// handleWakeCommand is the receive-path entry point for the command
+// topic. It debounces and then runs the command in its own goroutine
+// so a long agent run never stalls MQTT message delivery.
+func (p *Publisher) handleWakeCommand(topic string, payload []byte) {
+	if !p.commandDebounce.allow(payload, time.Now()) {
+		p.logger.Info("mqtt wake command debounced",
+			"topic", topic,
+			"window", p.commandDebounce.window,
+		)
+		return
+	}
+	go func() {
+		ctx := p.lifecycleContext()
+		result := p.runWakeCommand(ctx, topic, payload)
+		if p.cfg.CommandWake.PublishResult {
+			p.publishCommandResult(ctx, result)
+		}
+	}()
+}
+
+// runWakeCommand parses and executes one wake command, returning the
+// outcome for the result topic.
+func (p *Publisher) runWakeCommand(ctx context.Context, topic string, payload []byte) WakeCommandResult {
+	cmd, err := ParseWakeCommand(payload)
+	if err != nil {
+		p.logger.Warn("mqtt wake command rejected", "topic", topic, "error", err)
+		return WakeCommandResult{Status: "error", Error: err.Error()}
+	}
+
+	p.logger.Info("mqtt wake command received",
+		"topic", topic,
+		"message_len", len(cmd.Message),
+		"quality_floor", cmd.QualityFloor,
+	)
+	resp, err := p.wakeCommand(ctx, topic, cmd)
+	if err != nil {
+		p.logger.Error("mqtt wake command failed", "topic", topic, "error", err)
+		return WakeCommandResult{Status: "error", Message: cmd.Message, Error: err.Error()}
+	}
+	p.logger.Info("mqtt wake command completed", "topic", topic, "response_len", len(resp))
+	return WakeCommandResult{Status: "ok", Message: cmd.Message, Response: resp}
+}
+
+// publishCommandResult publishes a wake command outcome. Failures are
+// logged; the automation simply sees no result.
+func (p *Publisher) publishCommandResult(ctx context.Context, result WakeCommandResult) {
+	cm := p.getCM()
+	if cm == nil {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		p.logger.Error("marshal mqtt wake command result", "error", err)
+		return
+	}
+	pubCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if _, err := cm.Publish(pubCtx, &paho.Publish{
+		Topic:   p.CommandWakeResultTopic(),
+		Payload: data,
+		QoS:     0,
+	}); err != nil {
+		p.logger.Warn("mqtt wake command result publish failed",
+			"topic", p.CommandWakeResultTopic(), "error", err)
+	}
+}
+
+// lifecycleContext returns the connection lifecycle context recorded
+// by connect, or a background context before the first connect.
+func (p *Publisher) lifecycleContext() context.Context {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ctx == nil {
+		return context.Background()
+	}
+	return p.ctx
+}
+
+// commandDebouncer suppresses repeats of an identical payload within a
+// window, so a flapping automation or a retried publish does not wake
+// the agent twice for the same command.
+type commandDebouncer struct {
+	window time.Duration
+	mu     sync.Mutex
+	seen   map[[sha256.Size]byte]time.Time
+}
+
+func newCommandDebouncer(window time.Duration) *commandDebouncer {
+	return &commandDebouncer{
+		window: window,
+		seen:   make(map[[sha256.Size]byte]time.Time),
+	}
+}
+
+// allow reports whether payload should run at now, recording it when
+// it does. A non-positive window disables debouncing.
+func (d *commandDebouncer) allow(payload []byte, now time.Time) bool {
+	if d.window <= 0 {
+		return true
+	}
+	key := sha256.Sum256(payload)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for k, at := range d.seen {
+		if now.Sub(at) >= d.window {
+			delete(d.seen, k)
+		}
+	}
+	if _, dup := d.seen[key]; dup {
+		return false
+	}
+	d.seen[key] = now
+	return true
+}
@@ -0,0 +1,172 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+)
+
+func TestParseWakeCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     string
+		wantMessage string
+		wantFloor   int
+		wantErr     bool
+	}{
+		{name: "string floor", payload: `{"message":"Check the garage","quality_floor":"5"}`, wantMessage: "Check the garage", wantFloor: 5},
+		{name: "numeric floor", payload: `{"message":"hi","quality_floor":7}`, wantMessage: "hi", wantFloor: 7},
+		{name: "no floor", payload: `{"message":"  hi  "}`, wantMessage: "hi"},
+		{name: "null floor", payload: `{"message":"hi","quality_floor":null}`, wantMessage: "hi"},
+		{name: "missing message", payload: `{"quality_floor":"5"}`, wantErr: true},
+		{name: "floor out of range", payload: `{"message":"hi","quality_floor":"11"}`, wantErr: true},
+		{name: "floor not a number", payload: `{"message":"hi","quality_floor":"high"}`, wantErr: true},
+		{name: "not JSON", payload: `wake up`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := ParseWakeCommand([]byte(tt.payload))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseWakeCommand(%s) = %+v, want error", tt.payload, cmd)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWakeCommand(%s): %v", tt.payload, err)
+			}
+			if cmd.Message != tt.wantMessage || cmd.QualityFloor != tt.wantFloor {
+				t.Errorf("ParseWakeCommand(%s) = %+v, want message %q floor %d", tt.payload, cmd, tt.wantMessage, tt.wantFloor)
+			}
+		})
+	}
+}
+
+func TestCommandDebouncer(t *testing.T) {
+	d := newCommandDebouncer(10 * time.Second)
+	now := time.Now()
+	a := []byte(`{"message":"a"}`)
+	b := []byte(`{"message":"b"}`)
+
+	if !d.allow(a, now) {
+		t.Fatal("first payload should be allowed")
+	}
+	if d.allow(a, now.Add(5*time.Second)) {
+		t.Error("duplicate within window should be debounced")
+	}
+	if !d.allow(b, now.Add(5*time.Second)) {
+		t.Error("different payload should be allowed")
+	}
+	if !d.allow(a, now.Add(10*time.Second)) {
+		t.Error("duplicate after window should be allowed")
+	}
+
+	disabled := newCommandDebouncer(-1)
+	if !disabled.allow(a, now) || !disabled.allow(a, now) {
+		t.Error("negative window should disable debouncing")
+	}
+}
+
+func TestPublisher_CommandWakeTopics(t *testing.T) {
+	p := New(config.MQTTConfig{DeviceName: "test-thane"}, "instance-123", NewDailyTokens(time.UTC), nil, nil)
+
+	if got := p.CommandWakeTopic(); got != "thane/test-thane/command/wake" {
+		t.Errorf("CommandWakeTopic() = %q", got)
+	}
+	if got := p.CommandWakeResultTopic(); got != "thane/test-thane/command/wake/result" {
+		t.Errorf("CommandWakeResultTopic() = %q", got)
+	}
+
+	if topics := p.collectSubscribeTopics(); len(topics) != 0 {
+		t.Errorf("collectSubscribeTopics() = %v before a command handler is set", topics)
+	}
+	p.SetWakeCommandHandler(func(context.Context, string, WakeCommand) (string, error) { return "", nil })
+	topics := p.collectSubscribeTopics()
+	if len(topics) != 1 || topics[0] != p.CommandWakeTopic() {
+		t.Errorf("collectSubscribeTopics() = %v, want [%s]", topics, p.CommandWakeTopic())
+	}
+}
+
+func TestPublisher_BuildClientConfig_RoutesCommandTopic(t *testing.T) {
+	cfg := config.MQTTConfig{
+		Broker:      "mqtt://localhost:1883",
+		DeviceName:  "test-thane",
+		CommandWake: config.MQTTCommandWakeConfig{Enabled: true, Debounce: time.Minute},
+	}
+	p := New(cfg, "instance-123", NewDailyTokens(time.UTC), nil, nil)
+
+	var generic []string
+	p.SetMessageHandler(func(topic string, _ []byte) { generic = append(generic, topic) })
+	commands := make(chan WakeCommand, 2)
+	p.SetWakeCommandHandler(func(_ context.Context, topic string, cmd WakeCommand) (string, error) {
+		if topic != p.CommandWakeTopic() {
+			t.Errorf("handler topic = %q", topic)
+		}
+		commands <- cmd
+		return "done", nil
+	})
+
+	brokerURL, err := url.Parse(cfg.Broker)
+	if err != nil {
+		t.Fatalf("parse broker URL: %v", err)
+	}
+	pahoCfg := p.buildClientConfig(brokerURL)
+	if len(pahoCfg.OnPublishReceived) == 0 {
+		t.Fatal("OnPublishReceived should be registered when the command topic is enabled")
+	}
+
+	deliver := func(topic, payload string) {
+		t.Helper()
+		pr := paho.PublishReceived{Packet: &paho.Publish{Topic: topic, Payload: []byte(payload)}}
+		if _, err := pahoCfg.OnPublishReceived[0](pr); err != nil {
+			t.Fatalf("OnPublishReceived: %v", err)
+		}
+	}
+	deliver(p.CommandWakeTopic(), `{"message":"Check the garage","quality_floor":"5"}`)
+	deliver(p.CommandWakeTopic(), `{"message":"Check the garage","quality_floor":"5"}`)
+	deliver("other/topic", `{}`)
+
+	select {
+	case cmd := <-commands:
+		if cmd.Message != "Check the garage" || cmd.QualityFloor != 5 {
+			t.Errorf("command = %+v", cmd)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("command handler was not invoked")
+	}
+	select {
+	case cmd := <-commands:
+		t.Errorf("duplicate payload within the debounce window ran again: %+v", cmd)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if len(generic) != 1 || generic[0] != "other/topic" {
+		t.Errorf("generic handler saw %v, want only other/topic", generic)
+	}
+}
+
+func TestPublisher_RunWakeCommandResult(t *testing.T) {
+	p := New(config.MQTTConfig{DeviceName: "test-thane"}, "instance-123", NewDailyTokens(time.UTC), nil, nil)
+	p.SetWakeCommandHandler(func(_ context.Context, _ string, cmd WakeCommand) (string, error) {
+		if cmd.Message == "fail" {
+			return "", errors.New("boom")
+		}
+		return "ok: " + cmd.Message, nil
+	})
+	ctx := context.Background()
+	topic := p.CommandWakeTopic()
+
+	if got := p.runWakeCommand(ctx, topic, []byte(`{"message":"hi"}`)); got.Status != "ok" || got.Response != "ok: hi" {
+		t.Errorf("success result = %+v", got)
+	}
+	if got := p.runWakeCommand(ctx, topic, []byte(`{"message":"fail"}`)); got.Status != "error" || got.Error != "boom" || got.Message != "fail" {
+		t.Errorf("handler error result = %+v", got)
+	}
+	if got := p.runWakeCommand(ctx, topic, []byte(`{}`)); got.Status != "error" || got.Error == "" {
+		t.Errorf("invalid payload result = %+v", got)
+	}
+}
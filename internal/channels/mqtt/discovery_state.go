@@ -0,0 +1,187 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/eclipse/paho.golang/autopaho"
+)
+
+// discoveryStateFile is the persisted record of the discovery topics a
+// publisher last published, used to find entities that have since
+// been removed.
+type discoveryStateFile struct {
+	DiscoveryTopics []string `json:"discovery_topics"`
+}
+
+// SetDiscoveryStateFile enables pruning of removed entities. The
+// publisher records every discovery topic it publishes in path, and on
+// each (re-)connect clears the retained config of any recorded topic
+// no longer registered, so HA drops the orphaned entity. Must be
+// called before [Publisher.Connect].
+func (p *Publisher) SetDiscoveryStateFile(path string) {
+	p.discoveryStatePath = path
+}
+
+// PreserveSensorPrefix exempts entities whose suffix starts with
+// prefix from pruning. Use it for sensor families registered on first
+// use after connect (per-loop or per-provider sensors), which are
+// absent at connect time but not removed. Must be called before
+// [Publisher.Connect].
+func (p *Publisher) PreserveSensorPrefix(prefix string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.preservedPrefixes = append(p.preservedPrefixes, prefix)
+}
+
+// PruneRemovedSensors clears the retained discovery config of every
+// entity recorded in the discovery state file that is no longer
+// registered, by publishing an empty retained payload to its topic.
+// Call it before publishing the current discovery configs so HA sees
+// the removal first. It returns the recorded topics to carry into the
+// next saved state: preserved entities and any whose removal failed,
+// so it is retried on the next connect. Does nothing when no state
+// file is set.
+func (p *Publisher) PruneRemovedSensors(ctx context.Context, cm *autopaho.ConnectionManager) []string {
+	if p.discoveryStatePath == "" {
+		return nil
+	}
+	previous, err := loadDiscoveryState(p.discoveryStatePath)
+	if err != nil {
+		p.logger.Warn("mqtt discovery state unreadable; skipping prune",
+			"path", p.discoveryStatePath, "error", err)
+		return nil
+	}
+
+	remove, carried := p.staleDiscoveryTopics(previous, p.currentDiscoveryTopics())
+	for _, topic := range remove {
+		entity := discoveryTopicEntity(topic)
+		if !p.publishRetained(ctx, cm, entity, topic, "") {
+			carried = append(carried, topic)
+			continue
+		}
+		p.logger.Info("mqtt pruned removed entity", "entity", entity, "topic", topic)
+	}
+	return carried
+}
+
+// staleDiscoveryTopics splits the previously published topics absent
+// from current into those to remove and those to carry forward
+// because their entity suffix is preserved.
+func (p *Publisher) staleDiscoveryTopics(previous, current []string) (remove, carried []string) {
+	p.mu.Lock()
+	prefixes := slices.Clone(p.preservedPrefixes)
+	p.mu.Unlock()
+
+	for _, topic := range previous {
+		if slices.Contains(current, topic) {
+			continue
+		}
+		entity := discoveryTopicEntity(topic)
+		preserved := slices.ContainsFunc(prefixes, func(prefix string) bool {
+			return strings.HasPrefix(entity, prefix)
+		})
+		if preserved {
+			carried = append(carried, topic)
+		} else {
+			remove = append(remove, topic)
+		}
+	}
+	return remove, carried
+}
+
+// currentDiscoveryTopics returns the discovery topic of every
+// registered entity.
+func (p *Publisher) currentDiscoveryTopics() []string {
+	var topics []string
+	for _, s := range p.sensorDefinitions() {
+		topics = append(topics, p.discoveryTopic("sensor", s.entitySuffix))
+	}
+	p.mu.Lock()
+	for _, ds := range p.dynamicSensors {
+		topics = append(topics, p.discoveryTopic("sensor", ds.EntitySuffix))
+	}
+	p.mu.Unlock()
+	for _, b := range p.registeredButtons() {
+		topics = append(topics, p.discoveryTopic("button", b.EntitySuffix))
+	}
+	for _, n := range p.registeredNumbers() {
+		topics = append(topics, p.discoveryTopic("number", n.suffix))
+	}
+	for _, s := range p.registeredSelects() {
+		topics = append(topics, p.discoveryTopic("select", s.suffix))
+	}
+	return topics
+}
+
+// saveDiscoveryState records the current discovery topics, plus
+// carried (which replaces any earlier carried set when non-nil), in
+// the discovery state file. Failures are logged; the next successful
+// publish cycle rewrites the file.
+func (p *Publisher) saveDiscoveryState(carried []string) {
+	if p.discoveryStatePath == "" {
+		return
+	}
+	p.discoveryMu.Lock()
+	defer p.discoveryMu.Unlock()
+
+	if carried != nil {
+		p.carriedTopics = carried
+	}
+	topics := append(p.currentDiscoveryTopics(), p.carriedTopics...)
+	slices.Sort(topics)
+	topics = slices.Compact(topics)
+
+	if err := writeDiscoveryState(p.discoveryStatePath, topics); err != nil {
+		p.logger.Warn("mqtt discovery state not saved",
+			"path", p.discoveryStatePath, "error", err)
+	}
+}
+
+// discoveryTopicEntity returns the entity suffix of a discovery topic
+// ({prefix}/{component}/{device}/{entity}/config).
+func discoveryTopicEntity(topic string) string {
+	parts := strings.Split(topic, "/")
+	if len(parts) < 2 {
+		return topic
+	}
+	return parts[len(parts)-2]
+}
+
+// loadDiscoveryState reads the recorded discovery topics from path. A
+// missing file is an empty record.
+func loadDiscoveryState(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state discoveryStateFile
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return state.DiscoveryTopics, nil
+}
+
+// writeDiscoveryState replaces the record at path with topics,
+// writing to a temporary file first so a crash never leaves a
+// truncated record.
+func writeDiscoveryState(path string, topics []string) error {
+	data, err := json.MarshalIndent(discoveryStateFile{DiscoveryTopics: topics}, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,99 @@
+package mqtt
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+)
+
+func newDiscoveryTestPublisher(statePath string) *Publisher {
+	cfg := config.MQTTConfig{Broker: "mqtt://localhost:1883", DeviceName: "test-thane", DiscoveryPrefix: "homeassistant"}
+	p := New(cfg, "instance-123", NewDailyTokens(time.UTC), nil, nil)
+	p.SetDiscoveryStateFile(statePath)
+	return p
+}
+
+func TestDiscoveryState_RemovedEntitiesPruned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mqtt_discovery.json")
+
+	// First run: a number, an AP sensor, and a provider token sensor.
+	before := newDiscoveryTestPublisher(path)
+	before.RegisterNumber("Compaction Keep Recent", 2, 50, 1, nil)
+	before.RegisterSensors([]DynamicSensor{{EntitySuffix: "office_ap"}})
+	before.addProviderTokenSensor("anthropic")
+	before.saveDiscoveryState(nil)
+
+	recorded, err := loadDiscoveryState(path)
+	if err != nil {
+		t.Fatalf("loadDiscoveryState: %v", err)
+	}
+	numberTopic := before.discoveryTopic("number", "compaction_keep_recent")
+	apTopic := before.discoveryTopic("sensor", "office_ap")
+	tokenTopic := before.discoveryTopic("sensor", "tokens_today_anthropic")
+	for _, want := range []string{numberTopic, apTopic, tokenTopic, before.discoveryTopic("sensor", "uptime")} {
+		if !slices.Contains(recorded, want) {
+			t.Errorf("recorded topics missing %s: %v", want, recorded)
+		}
+	}
+
+	// Restart with the AP sensor removed from config. The provider
+	// sensor is not registered yet — it appears on first use.
+	after := newDiscoveryTestPublisher(path)
+	after.RegisterNumber("Compaction Keep Recent", 2, 50, 1, nil)
+
+	remove, carried := after.staleDiscoveryTopics(recorded, after.currentDiscoveryTopics())
+	if !slices.Equal(remove, []string{apTopic}) {
+		t.Errorf("remove = %v, want only the removed AP sensor %s", remove, apTopic)
+	}
+	if !slices.Equal(carried, []string{tokenTopic}) {
+		t.Errorf("carried = %v, want the preserved provider sensor %s", carried, tokenTopic)
+	}
+
+	// The saved state drops the pruned topic but keeps the carried one.
+	after.saveDiscoveryState(carried)
+	recorded, err = loadDiscoveryState(path)
+	if err != nil {
+		t.Fatalf("loadDiscoveryState: %v", err)
+	}
+	if slices.Contains(recorded, apTopic) {
+		t.Errorf("pruned topic still recorded: %v", recorded)
+	}
+	if !slices.Contains(recorded, tokenTopic) || !slices.Contains(recorded, numberTopic) {
+		t.Errorf("recorded = %v, want carried and current topics", recorded)
+	}
+}
+
+func TestDiscoveryState_MissingAndCorruptFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	topics, err := loadDiscoveryState(filepath.Join(dir, "absent.json"))
+	if err != nil || topics != nil {
+		t.Errorf("missing file = %v, %v; want empty record", topics, err)
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadDiscoveryState(corrupt); err == nil {
+		t.Error("corrupt file loaded without error")
+	}
+}
+
+func TestDiscoveryState_DisabledWithoutPath(t *testing.T) {
+	p := newDiscoveryTestPublisher("")
+	if carried := p.PruneRemovedSensors(t.Context(), nil); carried != nil {
+		t.Errorf("PruneRemovedSensors without a state file = %v, want nil", carried)
+	}
+	p.saveDiscoveryState(nil) // must not panic or write anywhere
+}
+
+func TestDiscoveryTopicEntity(t *testing.T) {
+	if got := discoveryTopicEntity("homeassistant/sensor/thane/office_ap/config"); got != "office_ap" {
+		t.Errorf("discoveryTopicEntity() = %q, want office_ap", got)
+	}
+}
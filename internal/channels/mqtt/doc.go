@@ -5,6 +5,8 @@
 //
 // Phase 1 (publish): Sensor entities and availability.
 // Phase 2 (subscribe): Topic subscriptions with structured logging.
+// Phase 3 (wake): Subscriptions route matching messages to a wake_loop
+// target loop as event-source notifications (see [WakeSubscription]).
 //
 // The publisher uses Eclipse Paho v2's [autopaho] package for
 // connection management with automatic reconnection. On every
@@ -0,0 +1,218 @@
+package mqtt
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+)
+
+// Number is a Home Assistant number entity registered with
+// [Publisher.RegisterNumber]. HA renders it as a box the operator can
+// set; each set is clamped to [min, max], handed to the registered
+// callback, and echoed back as retained state.
+type Number struct {
+	p      *Publisher
+	suffix string
+	name   string
+	min    float64
+	max    float64
+	step   float64
+	onSet  func(float64)
+
+	mu       sync.Mutex
+	value    float64
+	hasValue bool
+}
+
+// NumberConfig is the HA MQTT discovery payload for a number entity.
+type NumberConfig struct {
+	Name              string     `json:"name"`
+	ObjectID          string     `json:"object_id,omitempty"`
+	HasEntityName     bool       `json:"has_entity_name,omitempty"`
+	UniqueID          string     `json:"unique_id"`
+	CommandTopic      string     `json:"command_topic"`
+	StateTopic        string     `json:"state_topic"`
+	AvailabilityTopic string     `json:"availability_topic"`
+	Min               float64    `json:"min"`
+	Max               float64    `json:"max"`
+	Step              float64    `json:"step"`
+	Mode              string     `json:"mode"`
+	Device            DeviceInfo `json:"device"`
+	EntityCategory    string     `json:"entity_category,omitempty"`
+}
+
+// RegisterNumber adds a number entity named name under the publisher's
+// device, with its entity suffix derived from the name (e.g.,
+// "Compaction Trigger Ratio" becomes compaction_trigger_ratio). Its
+// command topic is subscribed on every (re-)connect; onSet receives
+// each value set from HA after clamping to [min, max]. Report the
+// value currently in effect with [Number.SetValue] so HA shows it
+// after either side restarts. Must be called before
+// [Publisher.Connect].
+func (p *Publisher) RegisterNumber(name string, min, max, step float64, onSet func(float64)) *Number {
+	n := &Number{
+		p:      p,
+		suffix: entitySuffix(name),
+		name:   name,
+		min:    min,
+		max:    max,
+		step:   step,
+		onSet:  onSet,
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.numbers = append(p.numbers, n)
+	return n
+}
+
+// SetValue records the value currently in effect and publishes it as
+// retained state when connected. It does not call the onSet callback.
+func (n *Number) SetValue(v float64) {
+	n.mu.Lock()
+	n.value, n.hasValue = v, true
+	n.mu.Unlock()
+	if cm := n.p.getCM(); cm != nil {
+		go n.publishState(n.p.lifecycleContext(), cm)
+	}
+}
+
+// Value returns the last value recorded by [Number.SetValue] or set
+// from HA, and whether one has been recorded.
+func (n *Number) Value() (float64, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.value, n.hasValue
+}
+
+// CommandTopic returns the topic HA publishes new values to.
+func (n *Number) CommandTopic() string {
+	return n.p.baseTopic() + "/" + n.suffix + "/set"
+}
+
+// StateTopic returns the retained topic carrying the current value.
+func (n *Number) StateTopic() string {
+	return n.p.StateTopic(n.suffix)
+}
+
+// discoveryConfig builds the discovery payload for n.
+func (n *Number) discoveryConfig() NumberConfig {
+	return NumberConfig{
+		Name:              n.name,
+		ObjectID:          n.p.ObjectIDPrefix() + n.suffix,
+		HasEntityName:     true,
+		UniqueID:          n.p.instanceID + "_" + n.suffix,
+		CommandTopic:      n.CommandTopic(),
+		StateTopic:        n.StateTopic(),
+		AvailabilityTopic: n.p.AvailabilityTopic(),
+		Min:               n.min,
+		Max:               n.max,
+		Step:              n.step,
+		Mode:              "box",
+		Device:            n.p.device,
+		EntityCategory:    "config",
+	}
+}
+
+// clamp bounds v to [min, max], reporting whether it changed.
+func (n *Number) clamp(v float64) (float64, bool) {
+	switch {
+	case v < n.min:
+		return n.min, true
+	case v > n.max:
+		return n.max, true
+	}
+	return v, false
+}
+
+// handleSet applies a value published to the command topic. Values
+// outside [min, max] are clamped and logged rather than applied as
+// sent; unparseable payloads are logged and dropped.
+func (n *Number) handleSet(payload []byte) {
+	raw := strings.TrimSpace(string(payload))
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(v) {
+		n.p.logger.Warn("mqtt number ignored non-numeric value",
+			"entity", n.suffix, "payload", raw)
+		return
+	}
+	if clamped, changed := n.clamp(v); changed {
+		n.p.logger.Warn("mqtt number value out of range, clamped",
+			"entity", n.suffix, "requested", v, "applied", clamped,
+			"min", n.min, "max", n.max)
+		v = clamped
+	}
+	if n.onSet != nil {
+		n.onSet(v)
+	}
+	n.p.logger.Info("mqtt number set", "entity", n.suffix, "value", v)
+	n.SetValue(v)
+}
+
+// publishState publishes the recorded value, if any, as retained state.
+func (n *Number) publishState(ctx context.Context, cm *autopaho.ConnectionManager) {
+	v, ok := n.Value()
+	if !ok {
+		return
+	}
+	pubCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	n.p.publishRetained(pubCtx, cm, n.suffix, n.StateTopic(), strconv.FormatFloat(v, 'f', -1, 64))
+}
+
+// registeredNumbers returns a snapshot of the registered numbers.
+func (p *Publisher) registeredNumbers() []*Number {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]*Number, len(p.numbers))
+	copy(out, p.numbers)
+	return out
+}
+
+// numberForTopic returns the number whose command topic is topic.
+func (p *Publisher) numberForTopic(topic string) (*Number, bool) {
+	for _, n := range p.registeredNumbers() {
+		if n.CommandTopic() == topic {
+			return n, true
+		}
+	}
+	return nil, false
+}
+
+// publishNumberDiscovery publishes each number's discovery config and
+// current state. It reports whether every discovery config was
+// published.
+func (p *Publisher) publishNumberDiscovery(ctx context.Context, cm *autopaho.ConnectionManager) bool {
+	ok := true
+	for _, n := range p.registeredNumbers() {
+		if !p.publishRetained(ctx, cm, n.suffix, p.discoveryTopic("number", n.suffix), n.discoveryConfig()) {
+			ok = false
+		}
+		n.publishState(ctx, cm)
+	}
+	return ok
+}
+
+// entitySuffix converts a display name into an entity suffix:
+// lowercase ASCII letters and digits, with every other run of
+// characters collapsed to a single underscore.
+func entitySuffix(name string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			underscore = false
+			continue
+		}
+		if b.Len() > 0 && !underscore {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
@@ -0,0 +1,82 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+)
+
+func TestEntitySuffix(t *testing.T) {
+	tests := map[string]string{
+		"Compaction Trigger Ratio": "compaction_trigger_ratio",
+		"Keep  Recent (msgs)":      "keep_recent_msgs",
+		"already_snake":            "already_snake",
+	}
+	for in, want := range tests {
+		if got := entitySuffix(in); got != want {
+			t.Errorf("entitySuffix(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPublisher_RegisterNumber(t *testing.T) {
+	cfg := config.MQTTConfig{Broker: "mqtt://localhost:1883", DeviceName: "test-thane", DiscoveryPrefix: "homeassistant"}
+	p := New(cfg, "instance-123", NewDailyTokens(time.UTC), nil, nil)
+
+	var applied []float64
+	n := p.RegisterNumber("Compaction Trigger Ratio", 0.3, 0.95, 0.05, func(v float64) {
+		applied = append(applied, v)
+	})
+	n.SetValue(0.7)
+
+	if got := n.CommandTopic(); got != "thane/test-thane/compaction_trigger_ratio/set" {
+		t.Errorf("CommandTopic() = %q", got)
+	}
+	raw, err := json.Marshal(n.discoveryConfig())
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var disc map[string]any
+	if err := json.Unmarshal(raw, &disc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if disc["min"] != 0.3 || disc["max"] != 0.95 || disc["step"] != 0.05 || disc["state_topic"] != n.StateTopic() {
+		t.Errorf("discovery payload = %s", raw)
+	}
+
+	if topics := p.collectSubscribeTopics(); len(topics) != 1 || topics[0] != n.CommandTopic() {
+		t.Errorf("collectSubscribeTopics() = %v, want [%s]", topics, n.CommandTopic())
+	}
+
+	brokerURL, err := url.Parse(cfg.Broker)
+	if err != nil {
+		t.Fatalf("parse broker URL: %v", err)
+	}
+	pahoCfg := p.buildClientConfig(brokerURL)
+	if len(pahoCfg.OnPublishReceived) == 0 {
+		t.Fatal("OnPublishReceived should be registered when numbers are registered")
+	}
+	for _, payload := range []string{"0.5", "2", "0.1", "banana"} {
+		pr := paho.PublishReceived{Packet: &paho.Publish{Topic: n.CommandTopic(), Payload: []byte(payload)}}
+		if _, err := pahoCfg.OnPublishReceived[0](pr); err != nil {
+			t.Fatalf("OnPublishReceived(%s): %v", payload, err)
+		}
+	}
+
+	want := []float64{0.5, 0.95, 0.3}
+	if len(applied) != len(want) {
+		t.Fatalf("applied = %v, want %v (out-of-range clamped, non-numeric dropped)", applied, want)
+	}
+	for i := range want {
+		if applied[i] != want[i] {
+			t.Errorf("applied[%d] = %v, want %v", i, applied[i], want[i])
+		}
+	}
+	if v, ok := n.Value(); !ok || v != 0.3 {
+		t.Errorf("Value() = %v, %v; want the last applied value", v, ok)
+	}
+}
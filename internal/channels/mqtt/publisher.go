@@ -61,7 +61,23 @@ type Publisher struct {
 	rateLimiter    *messageRateLimiter
 	mu             sync.Mutex
 	dynamicSensors []DynamicSensor
+	buttons        []Button
+	numbers        []*Number
+	selects        []*Select
 	dynamicTopics  func() []string // returns extra topics to subscribe on (re-)connect
+
+	discoveryStatePath string   // previously published discovery topics; "" disables pruning
+	preservedPrefixes  []string // entity suffix prefixes registered after connect; never pruned
+	discoveryMu        sync.Mutex
+	carriedTopics      []string // stale-but-kept topics carried into every saved state
+
+	tlsCfg       *tls.Config
+	tlsErr       error // deferred from New; returned by Connect
+	connFailures connectFailures
+
+	wakeCommand     WakeCommandHandler // nil = command wake topic disabled
+	commandDebounce *commandDebouncer
+	ctx             context.Context // connection lifecycle context, set by connect
 }
 
 // New creates a Publisher but does not connect. Call [Publisher.Start]
@@ -72,14 +88,23 @@ func New(cfg config.MQTTConfig, instanceID string, tokens *DailyTokens, stats St
 	if logger == nil {
 		logger = slog.Default()
 	}
-	return &Publisher{
+	tlsCfg, tlsErr := newTLSConfig(cfg.TLS)
+	p := &Publisher{
 		cfg:        cfg,
 		instanceID: instanceID,
 		device:     NewDeviceInfo(instanceID, cfg.DeviceName),
 		tokens:     tokens,
 		stats:      stats,
 		logger:     logger,
+		tlsCfg:     tlsCfg,
+		tlsErr:     tlsErr,
 	}
+	// Provider token sensors appear on first use, after connect.
+	p.PreserveSensorPrefix(providerTokenSuffix(""))
+	if tokens != nil {
+		tokens.OnNewProvider(p.addProviderTokenSensor)
+	}
+	return p
 }
 
 // SetMessageHandler registers a callback for inbound MQTT messages
@@ -174,6 +199,29 @@ func (p *Publisher) PublishDynamicState(ctx context.Context, entitySuffix, state
 	return nil
 }
 
+// PublishEvent publishes v, JSON-encoded, to [Publisher.EventTopic]
+// for kind. Events are one-shot notifications rather than state, so
+// they are not retained: a subscriber that is offline when one is
+// published never sees it. Safe for concurrent use from any goroutine.
+func (p *Publisher) PublishEvent(ctx context.Context, kind string, v any) error {
+	cm := p.getCM()
+	if cm == nil {
+		return fmt.Errorf("mqtt publisher not started")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal %s event: %w", kind, err)
+	}
+	if _, err := cm.Publish(ctx, &paho.Publish{
+		Topic:   p.EventTopic(kind),
+		Payload: data,
+		QoS:     1,
+	}); err != nil {
+		return fmt.Errorf("publish %s event: %w", kind, err)
+	}
+	return nil
+}
+
 // Connect establishes the MQTT broker connection, publishes discovery
 // configs, and configures subscriptions. It does not start the periodic
 // publish loop — use [Publisher.PublishStates] in a loop infrastructure
@@ -226,25 +274,29 @@ func (p *Publisher) buildClientConfig(brokerURL *url.URL) autopaho.ClientConfig
 		},
 		OnConnectionUp: func(cm *autopaho.ConnectionManager, _ *paho.Connack) {
 			p.logger.Info("mqtt connected to broker", "broker", p.cfg.Broker)
+			p.connFailures.clear()
 			publishCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 			defer cancel()
-			p.publishDiscovery(publishCtx, cm)
+			carried := p.PruneRemovedSensors(publishCtx, cm)
+			if p.publishDiscovery(publishCtx, cm) {
+				p.saveDiscoveryState(carried)
+			}
 			p.publishAvailability(publishCtx, cm, "online")
 			p.subscribe(publishCtx, cm)
 		},
 		OnConnectError: func(err error) {
 			p.logger.Warn("mqtt connection error", "error", err)
+			p.connFailures.record(err)
 		},
 		ClientConfig: paho.ClientConfig{
 			ClientID: "thane-" + p.instanceID[:8],
 		},
 	}
 
-	// Enable TLS for mqtts:// or ssl:// schemes.
-	if brokerURL.Scheme == "mqtts" || brokerURL.Scheme == "ssl" {
-		pahoCfg.TlsCfg = &tls.Config{
-			MinVersion: tls.VersionTLS12,
-		}
+	// Enable TLS for every scheme autopaho dials over TLS, including
+	// any configured CA bundle and client certificate.
+	if config.MQTTSchemeUsesTLS(brokerURL.Scheme) {
+		pahoCfg.TlsCfg = p.tlsCfg
 	}
 
 	// Wire inbound message handler into the paho.ClientConfig so it is
@@ -253,7 +305,7 @@ func (p *Publisher) buildClientConfig(brokerURL *url.URL) autopaho.ClientConfig
 	// In contrast, cm.AddOnPublishReceived() only registers on the
 	// *current* paho.Client instance and is lost on reconnect — and if
 	// the connection isn't up yet (c.cli == nil) it silently no-ops.
-	hasSubs := len(p.cfg.Subscriptions) > 0 || p.dynamicTopics != nil
+	hasSubs := len(p.cfg.Subscriptions) > 0 || p.dynamicTopics != nil || p.wakeCommand != nil || len(p.registeredButtons()) > 0 || len(p.registeredNumbers()) > 0 || len(p.registeredSelects()) > 0
 	if hasSubs {
 		if p.handler == nil {
 			p.handler = defaultMessageHandler(p.logger)
@@ -266,6 +318,22 @@ func (p *Publisher) buildClientConfig(brokerURL *url.URL) autopaho.ClientConfig
 				if !p.rateLimiter.allow() {
 					return true, nil
 				}
+				if p.wakeCommand != nil && pr.Packet.Topic == p.CommandWakeTopic() {
+					p.handleWakeCommand(pr.Packet.Topic, pr.Packet.Payload)
+					return true, nil
+				}
+				if b, ok := p.buttonForTopic(pr.Packet.Topic); ok {
+					p.handleButtonPress(b, pr.Packet.Payload)
+					return true, nil
+				}
+				if n, ok := p.numberForTopic(pr.Packet.Topic); ok {
+					n.handleSet(pr.Packet.Payload)
+					return true, nil
+				}
+				if s, ok := p.selectForTopic(pr.Packet.Topic); ok {
+					s.handleSelect(pr.Packet.Payload)
+					return true, nil
+				}
 				func() {
 					defer func() {
 						if r := recover(); r != nil {
@@ -295,6 +363,9 @@ func (p *Publisher) connect(ctx context.Context) error {
 	if p.stats == nil {
 		return fmt.Errorf("mqtt publisher: stats must not be nil")
 	}
+	if p.tlsErr != nil {
+		return fmt.Errorf("mqtt tls: %w", p.tlsErr)
+	}
 
 	brokerURL, err := url.Parse(p.cfg.Broker)
 	if err != nil {
@@ -308,6 +379,9 @@ func (p *Publisher) connect(ctx context.Context) error {
 		return fmt.Errorf("mqtt connect: %w", err)
 	}
 	p.setCM(cm)
+	p.mu.Lock()
+	p.ctx = ctx
+	p.mu.Unlock()
 
 	// Start the rate limiter after NewConnection succeeds to avoid
 	// leaking a goroutine on the error path.
@@ -318,9 +392,9 @@ func (p *Publisher) connect(ctx context.Context) error {
 	// Wait for the initial connection before starting the publish loop.
 	connCtx, connCancel := context.WithTimeout(ctx, 30*time.Second)
 	defer connCancel()
-	if err := cm.AwaitConnection(connCtx); err != nil {
+	if err := p.AwaitConnection(connCtx); err != nil {
 		// Log but don't fail — autopaho will keep retrying in the background.
-		p.logger.Warn("mqtt initial connection timed out, will retry in background", "error", err)
+		p.logger.Warn("mqtt initial connection failed, will retry in background", "error", err)
 	}
 
 	return nil
@@ -352,12 +426,74 @@ func (p *Publisher) Stop(ctx context.Context) error {
 
 // AwaitConnection blocks until the MQTT broker connection is
 // established or ctx expires. Useful for connwatch health probes.
+//
+// autopaho retries failed connection attempts in the background, so a
+// failing TLS handshake (bad client certificate, expired CA) would
+// otherwise look like a slow broker. AwaitConnection instead returns
+// the attempt's error as soon as one fails, and immediately when the
+// most recent attempt since the last successful connection failed.
 func (p *Publisher) AwaitConnection(ctx context.Context) error {
 	cm := p.getCM()
 	if cm == nil {
 		return fmt.Errorf("mqtt publisher not started")
 	}
-	return cm.AwaitConnection(ctx)
+
+	failed, lastErr := p.connFailures.state()
+	if lastErr != nil {
+		return fmt.Errorf("mqtt connection failed: %w", lastErr)
+	}
+
+	awaitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	up := make(chan error, 1)
+	go func() { up <- cm.AwaitConnection(awaitCtx) }()
+
+	select {
+	case err := <-up:
+		return err
+	case <-failed:
+		_, lastErr := p.connFailures.state()
+		return fmt.Errorf("mqtt connection failed: %w", lastErr)
+	}
+}
+
+// connectFailures records the error from the most recent failed
+// connection attempt, cleared when a connection comes up. Because a
+// failure is only possible while disconnected, a recorded error means
+// the publisher is not currently connected.
+type connectFailures struct {
+	mu     sync.Mutex
+	last   error
+	notify chan struct{} // closed and replaced on each recorded failure
+}
+
+// record stores err as the latest failure and wakes any waiters.
+func (f *connectFailures) record(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.last = err
+	if f.notify != nil {
+		close(f.notify)
+	}
+	f.notify = make(chan struct{})
+}
+
+// clear forgets the latest failure after a successful connection.
+func (f *connectFailures) clear() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.last = nil
+}
+
+// state returns a channel closed on the next recorded failure along
+// with the latest failure, if any.
+func (f *connectFailures) state() (<-chan struct{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.notify == nil {
+		f.notify = make(chan struct{})
+	}
+	return f.notify, f.last
 }
 
 // getCM returns the connection manager under the mutex, safe for
@@ -409,6 +545,12 @@ func (p *Publisher) AttributesTopic(entity string) string {
 	return p.baseTopic() + "/" + entity + "/attributes"
 }
 
+// EventTopic returns the topic events of the given kind are published
+// to: thane/{device_name}/event/{kind}.
+func (p *Publisher) EventTopic(kind string) string {
+	return p.baseTopic() + "/event/" + kind
+}
+
 func (p *Publisher) discoveryTopic(component, entity string) string {
 	return p.cfg.DiscoveryPrefix + "/" + component + "/" + p.cfg.DeviceName + "/" + entity + "/config"
 }
@@ -498,10 +640,72 @@ func (p *Publisher) sensorDefinitions() []sensorDef {
 	}
 }
 
-func (p *Publisher) publishDiscovery(ctx context.Context, cm *autopaho.ConnectionManager) {
+// providerTokenSuffix returns the entity suffix of provider's daily
+// token sensor (e.g., "tokens_today_anthropic").
+func providerTokenSuffix(provider string) string {
+	return "tokens_today_" + entitySuffix(provider)
+}
+
+// addProviderTokenSensor registers the daily token sensor for a
+// provider the first time [DailyTokens] records it. When already
+// connected, its discovery config is published immediately so HA
+// picks it up without a restart; otherwise the next (re-)connect
+// publishes it with the other dynamic sensors. Its state is published
+// with the other sensor states on the next publish interval.
+func (p *Publisher) addProviderTokenSensor(provider string) {
+	suffix := providerTokenSuffix(provider)
+	sensor := DynamicSensor{
+		EntitySuffix: suffix,
+		Config: SensorConfig{
+			Name:              "Tokens Today (" + provider + ")",
+			ObjectID:          p.ObjectIDPrefix() + suffix,
+			HasEntityName:     true,
+			UniqueID:          p.instanceID + "_" + suffix,
+			StateTopic:        p.StateTopic(suffix),
+			AvailabilityTopic: p.AvailabilityTopic(),
+			Device:            p.device,
+			Icon:              "mdi:counter",
+			StateClass:        "measurement",
+			UnitOfMeasurement: "tokens",
+		},
+	}
+
+	p.mu.Lock()
+	for _, ds := range p.dynamicSensors {
+		if ds.EntitySuffix == suffix {
+			p.mu.Unlock()
+			return
+		}
+	}
+	p.mu.Unlock()
+	p.RegisterSensors([]DynamicSensor{sensor})
+
+	cm := p.getCM()
+	if cm == nil {
+		return
+	}
+	p.logger.Info("mqtt registering token sensor for new provider", "provider", provider)
+	// Asynchronous: this runs on the token-recording path, which must
+	// never wait on the broker.
+	go func() {
+		ctx, cancel := context.WithTimeout(p.lifecycleContext(), 10*time.Second)
+		defer cancel()
+		if p.publishSensorDiscovery(ctx, cm, suffix, sensor.Config) {
+			p.saveDiscoveryState(nil)
+		}
+	}()
+}
+
+// publishDiscovery publishes every entity's discovery config and
+// reports whether all of them were published.
+func (p *Publisher) publishDiscovery(ctx context.Context, cm *autopaho.ConnectionManager) bool {
+	ok := true
+
 	// Static (built-in) sensors.
 	for _, s := range p.sensorDefinitions() {
-		p.publishSensorDiscovery(ctx, cm, s.entitySuffix, s.config)
+		if !p.publishSensorDiscovery(ctx, cm, s.entitySuffix, s.config) {
+			ok = false
+		}
 	}
 
 	// Dynamic sensors registered by external packages.
@@ -511,17 +715,31 @@ func (p *Publisher) publishDiscovery(ctx context.Context, cm *autopaho.Connectio
 	p.mu.Unlock()
 
 	for _, ds := range dynCopy {
-		p.publishSensorDiscovery(ctx, cm, ds.EntitySuffix, ds.Config)
+		if !p.publishSensorDiscovery(ctx, cm, ds.EntitySuffix, ds.Config) {
+			ok = false
+		}
+	}
+
+	// Button, number, and select entities.
+	if !p.publishButtonDiscovery(ctx, cm) {
+		ok = false
 	}
+	if !p.publishNumberDiscovery(ctx, cm) {
+		ok = false
+	}
+	if !p.publishSelectDiscovery(ctx, cm) {
+		ok = false
+	}
+	return ok
 }
 
-func (p *Publisher) publishSensorDiscovery(ctx context.Context, cm *autopaho.ConnectionManager, entitySuffix string, cfg SensorConfig) {
+func (p *Publisher) publishSensorDiscovery(ctx context.Context, cm *autopaho.ConnectionManager, entitySuffix string, cfg SensorConfig) bool {
 	topic := p.discoveryTopic("sensor", entitySuffix)
 	payload, err := json.Marshal(cfg)
 	if err != nil {
 		p.logger.Error("mqtt marshal discovery payload",
 			"entity", entitySuffix, "error", err)
-		return
+		return false
 	}
 
 	if _, err := cm.Publish(ctx, &paho.Publish{
@@ -532,10 +750,11 @@ func (p *Publisher) publishSensorDiscovery(ctx context.Context, cm *autopaho.Con
 	}); err != nil {
 		p.logger.Warn("mqtt discovery publish failed",
 			"entity", entitySuffix, "topic", topic, "error", err)
-	} else {
-		p.logger.Debug("mqtt discovery published",
-			"entity", entitySuffix, "topic", topic)
+		return false
 	}
+	p.logger.Debug("mqtt discovery published",
+		"entity", entitySuffix, "topic", topic)
+	return true
 }
 
 func (p *Publisher) publishAvailability(ctx context.Context, cm *autopaho.ConnectionManager, status string) {
@@ -595,9 +814,11 @@ func (p *Publisher) subscribe(ctx context.Context, cm *autopaho.ConnectionManage
 	}
 }
 
-// collectSubscribeTopics merges config-defined and dynamic topic
-// filters, deduplicating by topic string. Order is config first, then
-// dynamic.
+// collectSubscribeTopics merges config-defined, command, button,
+// number, select, and dynamic topic filters, deduplicating by topic
+// string. Order is config first, then the command wake topic when
+// enabled, then the command topics of available buttons, numbers, and
+// selects, then dynamic.
 func (p *Publisher) collectSubscribeTopics() []string {
 	seen := make(map[string]struct{})
 	var topics []string
@@ -610,6 +831,38 @@ func (p *Publisher) collectSubscribeTopics() []string {
 		topics = append(topics, sub.Topic)
 	}
 
+	if p.wakeCommand != nil {
+		if _, dup := seen[p.CommandWakeTopic()]; !dup {
+			seen[p.CommandWakeTopic()] = struct{}{}
+			topics = append(topics, p.CommandWakeTopic())
+		}
+	}
+
+	for _, b := range p.registeredButtons() {
+		if b.UnavailableReason != "" {
+			continue
+		}
+		topic := p.ButtonCommandTopic(b.EntitySuffix)
+		if _, dup := seen[topic]; !dup {
+			seen[topic] = struct{}{}
+			topics = append(topics, topic)
+		}
+	}
+
+	for _, n := range p.registeredNumbers() {
+		if _, dup := seen[n.CommandTopic()]; !dup {
+			seen[n.CommandTopic()] = struct{}{}
+			topics = append(topics, n.CommandTopic())
+		}
+	}
+
+	for _, s := range p.registeredSelects() {
+		if _, dup := seen[s.CommandTopic()]; !dup {
+			seen[s.CommandTopic()] = struct{}{}
+			topics = append(topics, s.CommandTopic())
+		}
+	}
+
 	if p.dynamicTopics != nil {
 		for _, t := range p.dynamicTopics() {
 			if _, dup := seen[t]; dup {
@@ -664,6 +917,9 @@ func (p *Publisher) publishStates(ctx context.Context) {
 
 	input, output, _ := p.tokens.Snapshot()
 	states["tokens_today"] = strconv.FormatInt(input+output, 10)
+	for provider, c := range p.tokens.ProviderSnapshot() {
+		states[providerTokenSuffix(provider)] = strconv.FormatInt(c.Input+c.Output, 10)
+	}
 
 	lastReq := p.stats.LastRequestTime()
 	if !lastReq.IsZero() {
@@ -102,6 +102,7 @@ func TestPublisher_TopicPaths(t *testing.T) {
 		{"baseTopic", p.baseTopic(), "thane/aimee-thane"},
 		{"AvailabilityTopic", p.AvailabilityTopic(), "thane/aimee-thane/availability"},
 		{"StateTopic uptime", p.StateTopic("uptime"), "thane/aimee-thane/uptime/state"},
+		{"EventTopic usage_alert", p.EventTopic("usage_alert"), "thane/aimee-thane/event/usage_alert"},
 		{"discoveryTopic sensor uptime", p.discoveryTopic("sensor", "uptime"), "homeassistant/sensor/aimee-thane/uptime/config"},
 	}
 
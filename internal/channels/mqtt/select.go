@@ -0,0 +1,174 @@
+package mqtt
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+)
+
+// Select is a Home Assistant select entity registered with
+// [Publisher.RegisterSelect]. HA renders it as a dropdown of fixed
+// options; each choice that names a known option is handed to the
+// registered callback and echoed back as retained state.
+type Select struct {
+	p        *Publisher
+	suffix   string
+	name     string
+	options  []string
+	onSelect func(string)
+
+	mu       sync.Mutex
+	value    string
+	hasValue bool
+}
+
+// SelectConfig is the HA MQTT discovery payload for a select entity.
+type SelectConfig struct {
+	Name              string     `json:"name"`
+	ObjectID          string     `json:"object_id,omitempty"`
+	HasEntityName     bool       `json:"has_entity_name,omitempty"`
+	UniqueID          string     `json:"unique_id"`
+	CommandTopic      string     `json:"command_topic"`
+	StateTopic        string     `json:"state_topic"`
+	AvailabilityTopic string     `json:"availability_topic"`
+	Options           []string   `json:"options"`
+	Device            DeviceInfo `json:"device"`
+	EntityCategory    string     `json:"entity_category,omitempty"`
+}
+
+// RegisterSelect adds a select entity named name under the publisher's
+// device, with its entity suffix derived from the name (e.g.,
+// "Default Model" becomes default_model). Its command topic is
+// subscribed on every (re-)connect; onSelect receives each option
+// chosen in HA that is one of options. Report the option currently in
+// effect with [Select.SetValue] so HA shows it after either side
+// restarts. Must be called before [Publisher.Connect].
+func (p *Publisher) RegisterSelect(name string, options []string, onSelect func(string)) *Select {
+	s := &Select{
+		p:        p,
+		suffix:   entitySuffix(name),
+		name:     name,
+		options:  slices.Clone(options),
+		onSelect: onSelect,
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.selects = append(p.selects, s)
+	return s
+}
+
+// SetValue records the option currently in effect and publishes it as
+// retained state when connected. It does not call the onSelect
+// callback.
+func (s *Select) SetValue(option string) {
+	s.mu.Lock()
+	s.value, s.hasValue = option, true
+	s.mu.Unlock()
+	if cm := s.p.getCM(); cm != nil {
+		go s.publishState(s.p.lifecycleContext(), cm)
+	}
+}
+
+// Value returns the last option recorded by [Select.SetValue] or
+// chosen in HA, and whether one has been recorded.
+func (s *Select) Value() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.value, s.hasValue
+}
+
+// CommandTopic returns the topic HA publishes chosen options to.
+func (s *Select) CommandTopic() string {
+	return s.p.baseTopic() + "/" + s.suffix + "/set"
+}
+
+// StateTopic returns the retained topic carrying the current option.
+func (s *Select) StateTopic() string {
+	return s.p.StateTopic(s.suffix)
+}
+
+// discoveryConfig builds the discovery payload for s.
+func (s *Select) discoveryConfig() SelectConfig {
+	return SelectConfig{
+		Name:              s.name,
+		ObjectID:          s.p.ObjectIDPrefix() + s.suffix,
+		HasEntityName:     true,
+		UniqueID:          s.p.instanceID + "_" + s.suffix,
+		CommandTopic:      s.CommandTopic(),
+		StateTopic:        s.StateTopic(),
+		AvailabilityTopic: s.p.AvailabilityTopic(),
+		Options:           s.options,
+		Device:            s.p.device,
+		EntityCategory:    "config",
+	}
+}
+
+// handleSelect applies an option published to the command topic.
+// Options not in the registered set are logged and dropped, and the
+// option in effect is republished so HA does not keep showing the
+// rejected choice.
+func (s *Select) handleSelect(payload []byte) {
+	option := strings.TrimSpace(string(payload))
+	if !slices.Contains(s.options, option) {
+		s.p.logger.Warn("mqtt select ignored unknown option",
+			"entity", s.suffix, "option", option, "options", s.options)
+		if cm := s.p.getCM(); cm != nil {
+			go s.publishState(s.p.lifecycleContext(), cm)
+		}
+		return
+	}
+	if s.onSelect != nil {
+		s.onSelect(option)
+	}
+	s.p.logger.Info("mqtt select set", "entity", s.suffix, "option", option)
+	s.SetValue(option)
+}
+
+// publishState publishes the recorded option, if any, as retained
+// state.
+func (s *Select) publishState(ctx context.Context, cm *autopaho.ConnectionManager) {
+	v, ok := s.Value()
+	if !ok {
+		return
+	}
+	pubCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	s.p.publishRetained(pubCtx, cm, s.suffix, s.StateTopic(), v)
+}
+
+// registeredSelects returns a snapshot of the registered selects.
+func (p *Publisher) registeredSelects() []*Select {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]*Select, len(p.selects))
+	copy(out, p.selects)
+	return out
+}
+
+// selectForTopic returns the select whose command topic is topic.
+func (p *Publisher) selectForTopic(topic string) (*Select, bool) {
+	for _, s := range p.registeredSelects() {
+		if s.CommandTopic() == topic {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// publishSelectDiscovery publishes each select's discovery config and
+// current state. It reports whether every discovery config was
+// published.
+func (p *Publisher) publishSelectDiscovery(ctx context.Context, cm *autopaho.ConnectionManager) bool {
+	ok := true
+	for _, s := range p.registeredSelects() {
+		if !p.publishRetained(ctx, cm, s.suffix, p.discoveryTopic("select", s.suffix), s.discoveryConfig()) {
+			ok = false
+		}
+		s.publishState(ctx, cm)
+	}
+	return ok
+}
@@ -0,0 +1,67 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+)
+
+func TestPublisher_RegisterSelect(t *testing.T) {
+	cfg := config.MQTTConfig{Broker: "mqtt://localhost:1883", DeviceName: "test-thane", DiscoveryPrefix: "homeassistant"}
+	p := New(cfg, "instance-123", NewDailyTokens(time.UTC), nil, nil)
+
+	var applied []string
+	s := p.RegisterSelect("Default Model", []string{"qwen3:8b", "claude-sonnet"}, func(v string) {
+		applied = append(applied, v)
+	})
+	s.SetValue("qwen3:8b")
+
+	if got := s.CommandTopic(); got != "thane/test-thane/default_model/set" {
+		t.Errorf("CommandTopic() = %q", got)
+	}
+	raw, err := json.Marshal(s.discoveryConfig())
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var disc struct {
+		Options    []string `json:"options"`
+		StateTopic string   `json:"state_topic"`
+	}
+	if err := json.Unmarshal(raw, &disc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(disc.Options) != 2 || disc.Options[1] != "claude-sonnet" || disc.StateTopic != s.StateTopic() {
+		t.Errorf("discovery payload = %s", raw)
+	}
+
+	if topics := p.collectSubscribeTopics(); len(topics) != 1 || topics[0] != s.CommandTopic() {
+		t.Errorf("collectSubscribeTopics() = %v, want [%s]", topics, s.CommandTopic())
+	}
+
+	brokerURL, err := url.Parse(cfg.Broker)
+	if err != nil {
+		t.Fatalf("parse broker URL: %v", err)
+	}
+	pahoCfg := p.buildClientConfig(brokerURL)
+	if len(pahoCfg.OnPublishReceived) == 0 {
+		t.Fatal("OnPublishReceived should be registered when selects are registered")
+	}
+	for _, payload := range []string{"claude-sonnet", "gpt-unknown", " qwen3:8b\n"} {
+		pr := paho.PublishReceived{Packet: &paho.Publish{Topic: s.CommandTopic(), Payload: []byte(payload)}}
+		if _, err := pahoCfg.OnPublishReceived[0](pr); err != nil {
+			t.Fatalf("OnPublishReceived(%q): %v", payload, err)
+		}
+	}
+
+	want := []string{"claude-sonnet", "qwen3:8b"}
+	if len(applied) != len(want) || applied[0] != want[0] || applied[1] != want[1] {
+		t.Fatalf("applied = %v, want %v (unknown option dropped)", applied, want)
+	}
+	if v, ok := s.Value(); !ok || v != "qwen3:8b" {
+		t.Errorf("Value() = %q, %v; want the last applied option", v, ok)
+	}
+}
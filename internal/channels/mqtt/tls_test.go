@@ -0,0 +1,212 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
+)
+
+// writeTestCert generates a self-signed certificate for 127.0.0.1 and
+// writes it and its key as PEM files under dir.
+func writeTestCert(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile = filepath.Join(dir, name+".pem")
+	keyFile = filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestNewTLSConfig_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	caFile, _ := writeTestCert(t, dir, "ca")
+	certFile, keyFile := writeTestCert(t, dir, "client")
+
+	tlsCfg, err := newTLSConfig(config.MQTTTLSConfig{CAFile: caFile, CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("newTLSConfig: %v", err)
+	}
+	if tlsCfg.RootCAs == nil {
+		t.Error("RootCAs not set from ca_file")
+	}
+	if len(tlsCfg.Certificates) != 1 {
+		t.Errorf("Certificates = %d, want 1 client certificate", len(tlsCfg.Certificates))
+	}
+	if tlsCfg.MinVersion != tls.VersionTLS12 {
+		t.Errorf("MinVersion = %x, want TLS 1.2", tlsCfg.MinVersion)
+	}
+	if tlsCfg.InsecureSkipVerify {
+		t.Error("InsecureSkipVerify set without opt-in")
+	}
+}
+
+func TestNewTLSConfig_Errors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	certFile, _ := writeTestCert(t, dir, "client")
+
+	tests := []struct {
+		name    string
+		cfg     config.MQTTTLSConfig
+		wantErr string
+	}{
+		{"ca_not_pem", config.MQTTTLSConfig{CAFile: notPEM}, "no PEM certificates"},
+		{"key_mismatch", config.MQTTTLSConfig{CertFile: certFile, KeyFile: notPEM}, "client certificate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTLSConfig(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("newTLSConfig() = %v, want error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPublisher_BuildClientConfig_TLSOnlyForTLSSchemes(t *testing.T) {
+	dir := t.TempDir()
+	caFile, _ := writeTestCert(t, dir, "ca")
+	cfg := config.MQTTConfig{DeviceName: "test-thane", TLS: config.MQTTTLSConfig{CAFile: caFile}}
+	p := New(cfg, "instance-123", NewDailyTokens(time.UTC), nil, nil)
+
+	for scheme, wantTLS := range map[string]bool{"mqtts": true, "ssl": true, "wss": true, "mqtt": false, "ws": false} {
+		brokerURL, err := url.Parse(scheme + "://broker:8883")
+		if err != nil {
+			t.Fatal(err)
+		}
+		pahoCfg := p.buildClientConfig(brokerURL)
+		if got := pahoCfg.TlsCfg != nil; got != wantTLS {
+			t.Errorf("%s: TlsCfg set = %v, want %v", scheme, got, wantTLS)
+		}
+		if wantTLS && pahoCfg.TlsCfg.RootCAs == nil {
+			t.Errorf("%s: TlsCfg missing configured CA", scheme)
+		}
+	}
+}
+
+func TestPublisher_ConnectReturnsTLSConfigError(t *testing.T) {
+	cfg := config.MQTTConfig{
+		Broker:     "mqtts://127.0.0.1:8883",
+		DeviceName: "test-thane",
+		TLS:        config.MQTTTLSConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+	}
+	p := New(cfg, "instance-123", NewDailyTokens(time.UTC), tlsTestStats{}, nil)
+	err := p.Connect(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "mqtt tls") {
+		t.Fatalf("Connect() = %v, want mqtt tls error", err)
+	}
+}
+
+func TestPublisher_AwaitConnectionReportsHandshakeFailure(t *testing.T) {
+	dir := t.TempDir()
+	serverCert, serverKey := writeTestCert(t, dir, "server")
+	otherCA, _ := writeTestCert(t, dir, "other-ca")
+
+	cert, err := tls.LoadX509KeyPair(serverCert, serverKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = conn.(*tls.Conn).Handshake()
+			}()
+		}
+	}()
+
+	// The client trusts a different CA, so every handshake fails.
+	cfg := config.MQTTConfig{
+		Broker:     "mqtts://" + ln.Addr().String(),
+		DeviceName: "test-thane",
+		TLS:        config.MQTTTLSConfig{CAFile: otherCA},
+	}
+	p := New(cfg, "instance-123", NewDailyTokens(time.UTC), tlsTestStats{}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Now()
+	if err := p.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Connect waited %v on a failing handshake", elapsed)
+	}
+
+	probeCtx, probeCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer probeCancel()
+	err = p.AwaitConnection(probeCtx)
+	if err == nil {
+		t.Fatal("AwaitConnection succeeded against an untrusted broker")
+	}
+	if !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("AwaitConnection error = %v, want the certificate failure", err)
+	}
+	if probeCtx.Err() != nil {
+		t.Error("AwaitConnection waited for its deadline instead of reporting the failure")
+	}
+}
+
+// tlsTestStats is a minimal [StatsSource] for tests that connect.
+type tlsTestStats struct{}
+
+func (tlsTestStats) Uptime() time.Duration      { return 0 }
+func (tlsTestStats) Version() string            { return "test" }
+func (tlsTestStats) DefaultModel() string       { return "test" }
+func (tlsTestStats) LastRequestTime() time.Time { return time.Time{} }
@@ -1,21 +1,33 @@
 package mqtt
 
 import (
+	"sort"
 	"sync"
 	"time"
 )
 
-// DailyTokens tracks token usage that resets at local midnight. It is
-// safe for concurrent use from multiple goroutines. The accumulator
+// TokenCounts is one provider's token usage for the current day.
+type TokenCounts struct {
+	Input    int64
+	Output   int64
+	Requests int64
+}
+
+// DailyTokens tracks token usage that resets at local midnight, in
+// total and per provider (e.g., "ollama" vs "anthropic"). It is safe
+// for concurrent use from multiple goroutines. The accumulator
 // implements the TokenObserver interface so it can be wired directly
 // into the API server's token recording path.
 type DailyTokens struct {
-	mu       sync.Mutex
-	input    int64
-	output   int64
-	requests int64
-	resetDay int // day-of-year of last reset
-	loc      *time.Location
+	mu        sync.Mutex
+	input     int64
+	output    int64
+	requests  int64
+	providers map[string]*TokenCounts
+	resetDay  int // day-of-year of last reset
+	loc       *time.Location
+
+	onNewProvider func(provider string)
 }
 
 // NewDailyTokens creates a new accumulator using the given timezone for
@@ -25,23 +37,54 @@ func NewDailyTokens(loc *time.Location) *DailyTokens {
 		loc = time.Local
 	}
 	return &DailyTokens{
-		resetDay: time.Now().In(loc).YearDay(),
-		loc:      loc,
+		providers: make(map[string]*TokenCounts),
+		resetDay:  time.Now().In(loc).YearDay(),
+		loc:       loc,
 	}
 }
 
-// OnTokens records token counts from a completed LLM request. If the
-// local date has changed since the last recording, counters are reset
-// before the new values are added. This method satisfies the
-// api.TokenObserver interface.
-func (d *DailyTokens) OnTokens(inputTokens, outputTokens int) {
+// OnTokens records token counts from a completed LLM request against
+// the total and the named provider's bucket. If the local date has
+// changed since the last recording, all counters are reset before the
+// new values are added. The first time a provider is seen, the
+// callback set with [DailyTokens.OnNewProvider] is invoked. This
+// method satisfies the api.TokenObserver interface.
+func (d *DailyTokens) OnTokens(provider string, inputTokens, outputTokens int) {
 	d.mu.Lock()
-	defer d.mu.Unlock()
-
 	d.maybeReset()
 	d.input += int64(inputTokens)
 	d.output += int64(outputTokens)
 	d.requests++
+
+	bucket, known := d.providers[provider]
+	if !known {
+		bucket = &TokenCounts{}
+		d.providers[provider] = bucket
+	}
+	bucket.Input += int64(inputTokens)
+	bucket.Output += int64(outputTokens)
+	bucket.Requests++
+	notify := d.onNewProvider
+	d.mu.Unlock()
+
+	// Outside the lock: the callback may publish over MQTT.
+	if !known && notify != nil {
+		notify(provider)
+	}
+}
+
+// OnNewProvider registers fn to be called once for each provider as it
+// is first recorded, and immediately for every provider already seen.
+// fn runs outside the accumulator's lock.
+func (d *DailyTokens) OnNewProvider(fn func(provider string)) {
+	d.mu.Lock()
+	d.onNewProvider = fn
+	known := d.providerNames()
+	d.mu.Unlock()
+
+	for _, provider := range known {
+		fn(provider)
+	}
 }
 
 // Snapshot returns the current accumulated totals after checking for
@@ -55,14 +98,45 @@ func (d *DailyTokens) Snapshot() (input, output, requests int64) {
 	return d.input, d.output, d.requests
 }
 
-// maybeReset zeroes the accumulators if the local day-of-year has
-// changed. Must be called with d.mu held.
+// ProviderSnapshot returns each seen provider's counts for the current
+// day after checking for midnight rollover. Providers seen on an
+// earlier day are kept with zero counts so their sensors report 0
+// rather than going stale.
+func (d *DailyTokens) ProviderSnapshot() map[string]TokenCounts {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.maybeReset()
+	out := make(map[string]TokenCounts, len(d.providers))
+	for name, c := range d.providers {
+		out[name] = *c
+	}
+	return out
+}
+
+// maybeReset zeroes the total and every provider bucket if the local
+// day-of-year has changed. Must be called with d.mu held, which makes
+// the reset atomic with respect to recording and snapshots.
 func (d *DailyTokens) maybeReset() {
 	today := time.Now().In(d.loc).YearDay()
 	if today != d.resetDay {
 		d.input = 0
 		d.output = 0
 		d.requests = 0
+		for _, c := range d.providers {
+			*c = TokenCounts{}
+		}
 		d.resetDay = today
 	}
 }
+
+// providerNames returns the seen provider names in sorted order. Must
+// be called with d.mu held.
+func (d *DailyTokens) providerNames() []string {
+	names := make([]string, 0, len(d.providers))
+	for name := range d.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -4,12 +4,14 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
 )
 
 func TestDailyTokens_Record(t *testing.T) {
 	dt := NewDailyTokens(time.UTC)
-	dt.OnTokens(100, 200)
-	dt.OnTokens(50, 75)
+	dt.OnTokens("ollama", 100, 200)
+	dt.OnTokens("ollama", 50, 75)
 
 	input, output, requests := dt.Snapshot()
 	if input != 150 {
@@ -39,7 +41,7 @@ func TestDailyTokens_Concurrent(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			dt.OnTokens(10, 20)
+			dt.OnTokens("ollama", 10, 20)
 		}()
 	}
 	wg.Wait()
@@ -58,7 +60,7 @@ func TestDailyTokens_Concurrent(t *testing.T) {
 
 func TestDailyTokens_MidnightReset(t *testing.T) {
 	dt := NewDailyTokens(time.UTC)
-	dt.OnTokens(500, 600)
+	dt.OnTokens("ollama", 500, 600)
 
 	// Simulate date change by manipulating the resetDay field directly.
 	dt.mu.Lock()
@@ -84,9 +86,105 @@ func TestDailyTokens_NilLocation(t *testing.T) {
 		t.Error("nil location should default to time.Local")
 	}
 	// Verify it works without panic.
-	dt.OnTokens(1, 1)
+	dt.OnTokens("ollama", 1, 1)
 	input, _, _ := dt.Snapshot()
 	if input != 1 {
 		t.Errorf("input = %d, want 1", input)
 	}
 }
+
+func TestDailyTokens_PerProvider(t *testing.T) {
+	dt := NewDailyTokens(time.UTC)
+	dt.OnTokens("ollama", 100, 200)
+	dt.OnTokens("anthropic", 10, 20)
+	dt.OnTokens("ollama", 1, 2)
+
+	got := dt.ProviderSnapshot()
+	if want := (TokenCounts{Input: 101, Output: 202, Requests: 2}); got["ollama"] != want {
+		t.Errorf("ollama = %+v, want %+v", got["ollama"], want)
+	}
+	if want := (TokenCounts{Input: 10, Output: 20, Requests: 1}); got["anthropic"] != want {
+		t.Errorf("anthropic = %+v, want %+v", got["anthropic"], want)
+	}
+
+	input, output, requests := dt.Snapshot()
+	if input != 111 || output != 222 || requests != 3 {
+		t.Errorf("total = (%d, %d, %d), want (111, 222, 3)", input, output, requests)
+	}
+}
+
+func TestDailyTokens_MidnightResetClearsProviders(t *testing.T) {
+	dt := NewDailyTokens(time.UTC)
+	dt.OnTokens("ollama", 500, 600)
+	dt.OnTokens("anthropic", 5, 6)
+
+	dt.mu.Lock()
+	dt.resetDay = time.Now().In(dt.loc).YearDay() - 1
+	dt.mu.Unlock()
+
+	got := dt.ProviderSnapshot()
+	if len(got) != 2 {
+		t.Fatalf("providers after reset = %v, want both kept", got)
+	}
+	for provider, c := range got {
+		if c != (TokenCounts{}) {
+			t.Errorf("%s after reset = %+v, want zero", provider, c)
+		}
+	}
+	if input, output, requests := dt.Snapshot(); input != 0 || output != 0 || requests != 0 {
+		t.Errorf("total after reset = (%d, %d, %d), want zero", input, output, requests)
+	}
+}
+
+func TestDailyTokens_OnNewProvider(t *testing.T) {
+	dt := NewDailyTokens(time.UTC)
+	dt.OnTokens("ollama", 1, 1)
+
+	var seen []string
+	dt.OnNewProvider(func(provider string) { seen = append(seen, provider) })
+	if len(seen) != 1 || seen[0] != "ollama" {
+		t.Fatalf("replayed providers = %v, want [ollama]", seen)
+	}
+
+	dt.OnTokens("anthropic", 1, 1)
+	dt.OnTokens("anthropic", 1, 1)
+	dt.OnTokens("ollama", 1, 1)
+	if len(seen) != 2 || seen[1] != "anthropic" {
+		t.Errorf("providers = %v, want anthropic reported once", seen)
+	}
+}
+
+func TestPublisher_RegistersProviderTokenSensor(t *testing.T) {
+	tokens := NewDailyTokens(time.UTC)
+	tokens.OnTokens("ollama", 1, 1)
+	p := New(config.MQTTConfig{DeviceName: "aimee-thane"}, "instance-123", tokens, nil, nil)
+
+	// Seen before New, and seen for the first time afterwards (mid-day).
+	tokens.OnTokens("anthropic", 1, 1)
+	tokens.OnTokens("anthropic", 1, 1)
+
+	p.mu.Lock()
+	sensors := append([]DynamicSensor{}, p.dynamicSensors...)
+	p.mu.Unlock()
+
+	got := make(map[string]SensorConfig)
+	for _, s := range sensors {
+		got[s.EntitySuffix] = s.Config
+	}
+	if len(sensors) != 2 {
+		t.Fatalf("dynamic sensors = %d, want one per provider", len(sensors))
+	}
+	cfg, ok := got["tokens_today_anthropic"]
+	if !ok {
+		t.Fatalf("missing tokens_today_anthropic sensor; got %v", got)
+	}
+	if cfg.ObjectID != "aimee_thane_tokens_today_anthropic" {
+		t.Errorf("ObjectID = %q", cfg.ObjectID)
+	}
+	if cfg.StateTopic != "thane/aimee-thane/tokens_today_anthropic/state" {
+		t.Errorf("StateTopic = %q", cfg.StateTopic)
+	}
+	if _, ok := got["tokens_today_ollama"]; !ok {
+		t.Error("missing tokens_today_ollama sensor for provider seen before New")
+	}
+}
@@ -81,6 +81,10 @@ type WatcherConfig struct {
 	Logger *slog.Logger
 }
 
+// SuccessWindow is the number of most recent probes [ServiceStatus]
+// computes its success rate over.
+const SuccessWindow = 20
+
 // ServiceStatus is the health status of a watched service, suitable for
 // JSON serialization in health endpoints.
 type ServiceStatus struct {
@@ -88,6 +92,21 @@ type ServiceStatus struct {
 	Ready     bool      `json:"ready"`
 	LastCheck time.Time `json:"last_check"`
 	LastError string    `json:"last_error,omitempty"`
+
+	// LastSuccess is when a probe last succeeded; zero if none has.
+	LastSuccess time.Time `json:"last_success"`
+
+	// LastProbeDuration is how long the most recent probe took.
+	LastProbeDuration time.Duration `json:"last_probe_duration"`
+
+	// RecentProbes is the number of probes in the success-rate window,
+	// up to [SuccessWindow].
+	RecentProbes int `json:"recent_probes"`
+
+	// SuccessRate is the fraction (0–1) of the last RecentProbes probes
+	// that succeeded. A rate strictly between 0 and 1 means the service
+	// is flapping. Meaningless when RecentProbes is zero.
+	SuccessRate float64 `json:"success_rate"`
 }
 
 // Watcher monitors a single service's health.
@@ -97,9 +116,14 @@ type Watcher struct {
 	cancel context.CancelFunc
 	done   chan struct{}
 
-	mu        sync.Mutex
-	lastErr   error
-	lastCheck time.Time
+	mu           sync.Mutex
+	lastErr      error
+	lastCheck    time.Time
+	lastSuccess  time.Time
+	lastDuration time.Duration
+	history      [SuccessWindow]bool // ring of recent probe outcomes
+	historyLen   int
+	historyNext  int
 }
 
 // IsReady reports whether the watched service is currently reachable.
@@ -120,13 +144,25 @@ func (w *Watcher) Status() ServiceStatus {
 	defer w.mu.Unlock()
 
 	s := ServiceStatus{
-		Name:      w.config.Name,
-		Ready:     w.ready.Load(),
-		LastCheck: w.lastCheck,
+		Name:              w.config.Name,
+		Ready:             w.ready.Load(),
+		LastCheck:         w.lastCheck,
+		LastSuccess:       w.lastSuccess,
+		LastProbeDuration: w.lastDuration,
+		RecentProbes:      w.historyLen,
 	}
 	if w.lastErr != nil {
 		s.LastError = w.lastErr.Error()
 	}
+	if w.historyLen > 0 {
+		ok := 0
+		for _, success := range w.history[:w.historyLen] {
+			if success {
+				ok++
+			}
+		}
+		s.SuccessRate = float64(ok) / float64(w.historyLen)
+	}
 	return s
 }
 
@@ -152,8 +188,9 @@ func (w *Watcher) run(ctx context.Context) {
 	// Phase 1: startup probe with exponential backoff.
 	delay := cfg.InitialDelay
 	for attempt := 1; attempt <= cfg.MaxRetries; attempt++ {
+		start := time.Now()
 		err := w.probe(ctx)
-		w.recordResult(err)
+		w.recordResult(err, time.Since(start))
 
 		if err == nil {
 			// Connected on startup.
@@ -205,8 +242,9 @@ func (w *Watcher) run(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			start := time.Now()
 			err := w.probe(ctx)
-			w.recordResult(err)
+			w.recordResult(err, time.Since(start))
 			wasReady := w.ready.Load()
 
 			if wasReady && err != nil {
@@ -251,12 +289,24 @@ func (w *Watcher) probe(ctx context.Context) error {
 	return w.config.Probe(probeCtx)
 }
 
-// recordResult stores the probe outcome under the mutex.
-func (w *Watcher) recordResult(err error) {
+// recordResult stores the probe outcome and how long it took under the
+// mutex.
+func (w *Watcher) recordResult(err error, elapsed time.Duration) {
 	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
 	w.lastErr = err
-	w.lastCheck = time.Now()
-	w.mu.Unlock()
+	w.lastCheck = now
+	w.lastDuration = elapsed
+	if err == nil {
+		w.lastSuccess = now
+	}
+	w.history[w.historyNext] = err == nil
+	w.historyNext = (w.historyNext + 1) % SuccessWindow
+	if w.historyLen < SuccessWindow {
+		w.historyLen++
+	}
 }
 
 // sleepCtx sleeps for d or until ctx is cancelled. Returns false if cancelled.
@@ -457,6 +457,47 @@ func TestManager_Status(t *testing.T) {
 	}
 }
 
+func TestWatcher_StatusProbeHistory(t *testing.T) {
+	t.Parallel()
+	w := &Watcher{config: WatcherConfig{Name: "flappy"}}
+
+	if s := w.Status(); s.RecentProbes != 0 || s.SuccessRate != 0 || !s.LastSuccess.IsZero() {
+		t.Fatalf("fresh watcher status = %+v, want no probe history", s)
+	}
+
+	w.recordResult(nil, 40*time.Millisecond)
+	succeededAt := w.Status().LastSuccess
+	if succeededAt.IsZero() {
+		t.Fatal("LastSuccess not set after a successful probe")
+	}
+	w.recordResult(errors.New("refused"), 3*time.Second)
+
+	s := w.Status()
+	if s.LastSuccess != succeededAt {
+		t.Errorf("LastSuccess = %v, want %v (a failure must not move it)", s.LastSuccess, succeededAt)
+	}
+	if s.LastProbeDuration != 3*time.Second {
+		t.Errorf("LastProbeDuration = %v, want 3s", s.LastProbeDuration)
+	}
+	if s.RecentProbes != 2 || s.SuccessRate != 0.5 {
+		t.Errorf("RecentProbes = %d, SuccessRate = %v, want 2, 0.5", s.RecentProbes, s.SuccessRate)
+	}
+
+	// Fill the window with failures; the early successes roll off.
+	for range SuccessWindow {
+		w.recordResult(errors.New("refused"), time.Millisecond)
+	}
+	s = w.Status()
+	if s.RecentProbes != SuccessWindow || s.SuccessRate != 0 {
+		t.Errorf("RecentProbes = %d, SuccessRate = %v, want %d, 0", s.RecentProbes, s.SuccessRate, SuccessWindow)
+	}
+
+	w.recordResult(nil, time.Millisecond)
+	if got, want := w.Status().SuccessRate, 1.0/SuccessWindow; got != want {
+		t.Errorf("SuccessRate = %v, want %v", got, want)
+	}
+}
+
 func TestManager_Stop(t *testing.T) {
 	t.Parallel()
 
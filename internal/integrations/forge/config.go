@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"strings"
 	"time"
 
@@ -27,7 +28,7 @@ type AccountConfig struct {
 	// Name is a short identifier (e.g., "github-primary").
 	Name string `yaml:"name"`
 
-	// Provider selects the forge backend: "github" or "gitea".
+	// Provider selects the forge backend: "github", "gitlab", or "gitea".
 	Provider string `yaml:"provider"`
 
 	// Token is the API authentication token.
@@ -40,7 +41,8 @@ type AccountConfig struct {
 	Username string `yaml:"username"`
 
 	// URL is the API base URL. Required for gitea. Optional for GitHub
-	// (defaults to https://api.github.com).
+	// (defaults to https://api.github.com). For gitlab, the instance URL
+	// of a self-hosted server (defaults to https://gitlab.com).
 	URL string `yaml:"url"`
 }
 
@@ -95,6 +97,9 @@ func (c *Config) ApplyDefaults() {
 		if c.Accounts[i].Provider == "github" && c.Accounts[i].URL == "" {
 			c.Accounts[i].URL = "https://api.github.com"
 		}
+		if c.Accounts[i].Provider == "gitlab" && c.Accounts[i].URL == "" {
+			c.Accounts[i].URL = gitlabDefaultURL
+		}
 	}
 }
 
@@ -122,11 +127,12 @@ func NewManager(cfg Config, logger *slog.Logger) (*Manager, error) {
 
 		switch acct.Provider {
 		case "github":
-			httpClient := httpkit.NewClient(
-				httpkit.WithTimeout(30*time.Second),
-				httpkit.WithTruthfulUserAgent(httpkit.AgentSurfaceForge),
-			)
-			provider, err = NewGitHub(httpClient, acct.Token, acct.URL, logger)
+			provider, err = NewGitHub(newForgeHTTPClient(), acct.Token, acct.URL, logger)
+			if err != nil {
+				return nil, fmt.Errorf("forge account %q: %w", acct.Name, err)
+			}
+		case "gitlab":
+			provider, err = NewGitLab(newForgeHTTPClient(), acct.Token, acct.URL, logger)
 			if err != nil {
 				return nil, fmt.Errorf("forge account %q: %w", acct.Name, err)
 			}
@@ -148,6 +154,14 @@ func NewManager(cfg Config, logger *slog.Logger) (*Manager, error) {
 	return m, nil
 }
 
+// newForgeHTTPClient returns the HTTP client used by forge providers.
+func newForgeHTTPClient() *http.Client {
+	return httpkit.NewClient(
+		httpkit.WithTimeout(30*time.Second),
+		httpkit.WithTruthfulUserAgent(httpkit.AgentSurfaceForge),
+	)
+}
+
 // Account returns the forge provider for the named account. If name is
 // empty, the primary (first configured) account is used.
 func (m *Manager) Account(name string) (ForgeProvider, error) {
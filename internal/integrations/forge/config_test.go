@@ -184,6 +184,8 @@ func TestApplyDefaults(t *testing.T) {
 			{Name: "gh-no-url", Provider: "github", Token: "tok"},
 			{Name: "gh-custom-url", Provider: "github", Token: "tok", URL: "https://github.corp.example.com"},
 			{Name: "gitea-with-url", Provider: "gitea", Token: "tok", URL: "https://gitea.example.com"},
+			{Name: "gl-no-url", Provider: "gitlab", Token: "tok"},
+			{Name: "gl-self-hosted", Provider: "gitlab", Token: "tok", URL: "https://gitlab.example.com"},
 			{Name: "other-no-url", Provider: "other", Token: "tok"},
 		},
 	}
@@ -194,6 +196,8 @@ func TestApplyDefaults(t *testing.T) {
 		"gh-no-url":      "https://api.github.com",
 		"gh-custom-url":  "https://github.corp.example.com",
 		"gitea-with-url": "https://gitea.example.com",
+		"gl-no-url":      "https://gitlab.com",
+		"gl-self-hosted": "https://gitlab.example.com",
 		"other-no-url":   "",
 	}
 
@@ -255,6 +259,28 @@ func TestNewManager(t *testing.T) {
 	}
 }
 
+func TestNewManagerGitLab(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{
+		Accounts: []AccountConfig{
+			{Name: "work", Provider: "gitlab", Token: "glpat_test", URL: "https://gitlab.example.com", Owner: "team"},
+		},
+	}
+
+	m, err := NewManager(cfg, discardLogger())
+	if err != nil {
+		t.Fatalf("NewManager() unexpected error: %v", err)
+	}
+	p, err := m.Account("work")
+	if err != nil {
+		t.Fatalf("Account(\"work\") unexpected error: %v", err)
+	}
+	if p.Name() != "gitlab" {
+		t.Errorf("Account(\"work\").Name() = %q, want %q", p.Name(), "gitlab")
+	}
+}
+
 func TestNewManagerUnsupportedProvider(t *testing.T) {
 	t.Parallel()
 
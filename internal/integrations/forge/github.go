@@ -303,8 +303,9 @@ func (g *GitHub) ListIssues(ctx context.Context, repo string, opts *ListOptions)
 	return issues, nil
 }
 
-// AddComment posts a comment on an issue or pull request.
-func (g *GitHub) AddComment(ctx context.Context, repo string, number int, body string) (*Comment, error) {
+// AddComment posts a comment on an issue or pull request. GitHub
+// numbers both from one sequence, so kind is not needed to find it.
+func (g *GitHub) AddComment(ctx context.Context, repo string, _ ItemKind, number int, body string) (*Comment, error) {
 	owner, name, err := splitRepo(repo)
 	if err != nil {
 		return nil, err
@@ -661,8 +662,9 @@ func (g *GitHub) MergePR(ctx context.Context, repo string, number int, opts *Mer
 
 // --- Reactions ---
 
-// AddReaction adds an emoji reaction to an issue/PR or a specific comment.
-func (g *GitHub) AddReaction(ctx context.Context, repo string, number int, commentID int64, emoji string) error {
+// AddReaction adds an emoji reaction to an issue/PR or a specific
+// comment. As with [GitHub.AddComment], kind is not needed.
+func (g *GitHub) AddReaction(ctx context.Context, repo string, _ ItemKind, number int, commentID int64, emoji string) error {
 	owner, name, err := splitRepo(repo)
 	if err != nil {
 		return err
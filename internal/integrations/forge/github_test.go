@@ -358,7 +358,7 @@ func TestGitHubAddReaction_Issue(t *testing.T) {
 	})
 
 	gh := newTestGitHub(t, mux)
-	err := gh.AddReaction(context.Background(), "owner/repo", 5, 0, "+1")
+	err := gh.AddReaction(context.Background(), "owner/repo", ItemIssue, 5, 0, "+1")
 	if err != nil {
 		t.Fatalf("AddReaction: %v", err)
 	}
@@ -391,7 +391,7 @@ func TestGitHubAddReaction_Comment(t *testing.T) {
 	})
 
 	gh := newTestGitHub(t, mux)
-	err := gh.AddReaction(context.Background(), "owner/repo", 5, 999, "heart")
+	err := gh.AddReaction(context.Background(), "owner/repo", ItemIssue, 5, 999, "heart")
 	if err != nil {
 		t.Fatalf("AddReaction: %v", err)
 	}
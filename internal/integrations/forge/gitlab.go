@@ -0,0 +1,1321 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/httpkit"
+)
+
+// gitlabDefaultURL is the GitLab.com instance used when an account
+// configures no URL.
+const gitlabDefaultURL = "https://gitlab.com"
+
+// gitlabMaxDiffPages caps how many pages of merge request diffs are
+// fetched (100 files per page), bounding the work for enormous merge
+// requests. The tool layer truncates the assembled diff further.
+const gitlabMaxDiffPages = 20
+
+// GitLab implements [ForgeProvider] for GitLab.com and self-hosted
+// GitLab instances using the REST API (v4).
+//
+// GitLab calls pull requests merge requests and numbers them
+// separately from issues, so issue #5 and merge request !5 can both
+// exist. Operations that accept either (comments, reactions) target
+// the issue when one exists with that number and fall back to the
+// merge request otherwise.
+type GitLab struct {
+	client  *http.Client
+	baseURL string // API root, ending in /api/v4
+	token   string
+	logger  *slog.Logger
+}
+
+// NewGitLab creates a GitLab forge provider. The httpClient should be
+// constructed via httpkit.NewClient. baseURL is the instance URL
+// (e.g., "https://gitlab.example.com"), with or without the /api/v4
+// suffix; empty means GitLab.com.
+func NewGitLab(httpClient *http.Client, token, baseURL string, logger *slog.Logger) (*GitLab, error) {
+	if baseURL == "" {
+		baseURL = gitlabDefaultURL
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid gitlab URL %q", baseURL)
+	}
+	root := strings.TrimSuffix(u.String(), "/")
+	if !strings.HasSuffix(root, "/api/v4") {
+		root += "/api/v4"
+	}
+	return &GitLab{client: httpClient, baseURL: root, token: token, logger: logger}, nil
+}
+
+// Name returns "gitlab".
+func (g *GitLab) Name() string { return "gitlab" }
+
+// gitlabError is a non-2xx response from the GitLab API.
+type gitlabError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *gitlabError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("gitlab API returned %d", e.StatusCode)
+	}
+	return fmt.Sprintf("gitlab API returned %d: %s", e.StatusCode, e.Message)
+}
+
+// isGitLabNotFound reports whether err is a GitLab 404.
+func isGitLabNotFound(err error) bool {
+	var ge *gitlabError
+	return errors.As(err, &ge) && ge.StatusCode == http.StatusNotFound
+}
+
+// projectPath returns the URL path prefix for repo. GitLab addresses
+// projects by their URL-encoded full path, which may include nested
+// groups ("group/subgroup/project").
+func projectPath(repo string) (string, error) {
+	if _, _, err := splitRepo(repo); err != nil {
+		return "", err
+	}
+	return "/projects/" + url.PathEscape(repo), nil
+}
+
+// do sends an API request and decodes the JSON response into out
+// (which may be nil). It returns the response headers for pagination.
+func (g *GitLab) do(ctx context.Context, method, apiPath string, query url.Values, body, out any) (http.Header, error) {
+	target := g.baseURL + apiPath
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", g.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpkit.DrainAndClose(resp.Body, 1<<20)
+	g.checkRate(resp.Header)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, parseGitLabError(resp)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+	}
+	return resp.Header, nil
+}
+
+// parseGitLabError extracts the message from a GitLab error response,
+// which carries it in "message" (a string, list, or field map) or
+// "error".
+func parseGitLabError(resp *http.Response) error {
+	ge := &gitlabError{StatusCode: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var payload struct {
+		Message json.RawMessage `json:"message"`
+		Error   string          `json:"error"`
+	}
+	if json.Unmarshal(data, &payload) == nil {
+		var s string
+		switch {
+		case len(payload.Message) > 0 && json.Unmarshal(payload.Message, &s) == nil:
+			ge.Message = s
+		case len(payload.Message) > 0:
+			ge.Message = string(payload.Message)
+		default:
+			ge.Message = payload.Error
+		}
+	}
+	if ge.Message == "" {
+		ge.Message = http.StatusText(resp.StatusCode)
+	}
+	return ge
+}
+
+// checkRate logs a warning when the API rate limit is getting low.
+func (g *GitLab) checkRate(h http.Header) {
+	remaining, err := strconv.Atoi(h.Get("RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	if remaining > 0 && remaining < rateLimitWarningThreshold {
+		attrs := []any{"remaining", remaining, "limit", h.Get("RateLimit-Limit")}
+		if reset, err := strconv.ParseInt(h.Get("RateLimit-Reset"), 10, 64); err == nil {
+			attrs = append(attrs, "reset", time.Unix(reset, 0).Format(time.RFC3339))
+		}
+		g.logger.Warn("gitlab rate limit low", attrs...)
+	}
+}
+
+// gitlabPerPage clamps limit to GitLab's page size bounds.
+func gitlabPerPage(limit int) string {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return strconv.Itoa(limit)
+}
+
+// --- API payloads ---
+
+type gitlabUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+type gitlabProject struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+	DefaultBranch     string `json:"default_branch"`
+	WebURL            string `json:"web_url"`
+	HTTPURLToRepo     string `json:"http_url_to_repo"`
+}
+
+type gitlabRelease struct {
+	TagName    string     `json:"tag_name"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReleasedAt *time.Time `json:"released_at"`
+	Upcoming   bool       `json:"upcoming_release"`
+	Links      struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+type gitlabCommit struct {
+	ID            string    `json:"id"`
+	ShortID       string    `json:"short_id"`
+	Title         string    `json:"title"`
+	Message       string    `json:"message"`
+	AuthorName    string    `json:"author_name"`
+	AuthoredDate  time.Time `json:"authored_date"`
+	CommitterName string    `json:"committer_name"`
+	CommittedDate time.Time `json:"committed_date"`
+	WebURL        string    `json:"web_url"`
+}
+
+type gitlabIssue struct {
+	IID            int          `json:"iid"`
+	Title          string       `json:"title"`
+	Description    string       `json:"description"`
+	State          string       `json:"state"`
+	Labels         []string     `json:"labels"`
+	Assignees      []gitlabUser `json:"assignees"`
+	Author         gitlabUser   `json:"author"`
+	WebURL         string       `json:"web_url"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+	UserNotesCount int          `json:"user_notes_count"`
+}
+
+type gitlabMergeRequest struct {
+	IID                 int             `json:"iid"`
+	Title               string          `json:"title"`
+	Description         string          `json:"description"`
+	State               string          `json:"state"`
+	Draft               bool            `json:"draft"`
+	Author              gitlabUser      `json:"author"`
+	SourceBranch        string          `json:"source_branch"`
+	TargetBranch        string          `json:"target_branch"`
+	MergeStatus         string          `json:"merge_status"`
+	DetailedMergeStatus string          `json:"detailed_merge_status"`
+	Labels              []string        `json:"labels"`
+	Assignees           []gitlabUser    `json:"assignees"`
+	Reviewers           []gitlabUser    `json:"reviewers"`
+	UserNotesCount      int             `json:"user_notes_count"`
+	ChangesCount        string          `json:"changes_count"`
+	WebURL              string          `json:"web_url"`
+	CreatedAt           time.Time       `json:"created_at"`
+	UpdatedAt           time.Time       `json:"updated_at"`
+	SHA                 string          `json:"sha"`
+	MergeCommitSHA      string          `json:"merge_commit_sha"`
+	SquashCommitSHA     string          `json:"squash_commit_sha"`
+	DiffRefs            *gitlabDiffRefs `json:"diff_refs"`
+	HeadPipeline        *gitlabPipeline `json:"head_pipeline"`
+}
+
+type gitlabDiffRefs struct {
+	BaseSHA  string `json:"base_sha"`
+	HeadSHA  string `json:"head_sha"`
+	StartSHA string `json:"start_sha"`
+}
+
+type gitlabPipeline struct {
+	ID int64 `json:"id"`
+}
+
+type gitlabDiff struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	Diff        string `json:"diff"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+}
+
+type gitlabNote struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	Body      string          `json:"body"`
+	Author    gitlabUser      `json:"author"`
+	CreatedAt time.Time       `json:"created_at"`
+	System    bool            `json:"system"`
+	Position  *gitlabPosition `json:"position"`
+}
+
+type gitlabPosition struct {
+	PositionType string `json:"position_type"`
+	BaseSHA      string `json:"base_sha,omitempty"`
+	StartSHA     string `json:"start_sha,omitempty"`
+	HeadSHA      string `json:"head_sha,omitempty"`
+	OldPath      string `json:"old_path,omitempty"`
+	NewPath      string `json:"new_path,omitempty"`
+	OldLine      *int   `json:"old_line,omitempty"`
+	NewLine      *int   `json:"new_line,omitempty"`
+}
+
+type gitlabJob struct {
+	Name         string     `json:"name"`
+	Status       string     `json:"status"`
+	AllowFailure bool       `json:"allow_failure"`
+	StartedAt    *time.Time `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at"`
+	WebURL       string     `json:"web_url"`
+}
+
+// --- Repositories ---
+
+// GetRepository retrieves repository metadata.
+func (g *GitLab) GetRepository(ctx context.Context, repo string) (*Repository, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var p gitlabProject
+	if _, err := g.do(ctx, http.MethodGet, pp, nil, nil, &p); err != nil {
+		return nil, fmt.Errorf("get repository: %w", err)
+	}
+
+	return &Repository{
+		FullName:      p.PathWithNamespace,
+		DefaultBranch: p.DefaultBranch,
+		URL:           p.WebURL,
+		CloneURL:      p.HTTPURLToRepo,
+	}, nil
+}
+
+// ListReleases returns recent repository releases, newest first.
+// GitLab releases have no numeric ID, so releases are identified by
+// tag.
+func (g *GitLab) ListReleases(ctx context.Context, repo string, limit int) ([]*Release, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var glReleases []gitlabRelease
+	query := url.Values{"per_page": {gitlabPerPage(limit)}}
+	if _, err := g.do(ctx, http.MethodGet, pp+"/releases", query, nil, &glReleases); err != nil {
+		return nil, fmt.Errorf("list releases: %w", err)
+	}
+
+	releases := make([]*Release, 0, len(glReleases))
+	for _, r := range glReleases {
+		release := &Release{
+			TagName:    r.TagName,
+			Name:       r.Name,
+			URL:        r.Links.Self,
+			Prerelease: r.Upcoming,
+			CreatedAt:  r.CreatedAt,
+		}
+		if r.ReleasedAt != nil {
+			release.PublishedAt = *r.ReleasedAt
+		}
+		releases = append(releases, release)
+	}
+	return releases, nil
+}
+
+// ListCommits returns recent commits on a repository branch/ref, newest first.
+func (g *GitLab) ListCommits(ctx context.Context, repo, branch string, limit int) ([]*Commit, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"per_page": {gitlabPerPage(limit)}}
+	if branch != "" {
+		query.Set("ref_name", branch)
+	}
+	var glCommits []gitlabCommit
+	if _, err := g.do(ctx, http.MethodGet, pp+"/repository/commits", query, nil, &glCommits); err != nil {
+		return nil, fmt.Errorf("list commits: %w", err)
+	}
+
+	commits := make([]*Commit, 0, len(glCommits))
+	for _, c := range glCommits {
+		commit := mapGitLabCommit(c)
+		commit.SHA = c.ID
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// --- Issues ---
+
+// CreateIssue creates a new issue on the repository.
+func (g *GitLab) CreateIssue(ctx context.Context, repo string, issue *Issue) (*Issue, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	req := map[string]any{
+		"title":       issue.Title,
+		"description": issue.Body,
+	}
+	if len(issue.Labels) > 0 {
+		req["labels"] = strings.Join(issue.Labels, ",")
+	}
+	if len(issue.Assignees) > 0 {
+		ids, err := g.userIDs(ctx, issue.Assignees)
+		if err != nil {
+			return nil, fmt.Errorf("create issue: %w", err)
+		}
+		req["assignee_ids"] = ids
+	}
+
+	var gi gitlabIssue
+	if _, err := g.do(ctx, http.MethodPost, pp+"/issues", nil, req, &gi); err != nil {
+		return nil, fmt.Errorf("create issue: %w", err)
+	}
+	return mapGitLabIssue(&gi), nil
+}
+
+// UpdateIssue applies a partial update to an existing issue.
+func (g *GitLab) UpdateIssue(ctx context.Context, repo string, number int, update *IssueUpdate) (*Issue, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	req := map[string]any{}
+	if update.Title != nil {
+		req["title"] = *update.Title
+	}
+	if update.Body != nil {
+		req["description"] = *update.Body
+	}
+	if update.State != nil {
+		switch *update.State {
+		case "closed":
+			req["state_event"] = "close"
+		case "open":
+			req["state_event"] = "reopen"
+		default:
+			return nil, fmt.Errorf("update issue #%d: unsupported state %q", number, *update.State)
+		}
+	}
+	if update.Labels != nil {
+		req["labels"] = strings.Join(*update.Labels, ",")
+	}
+	if update.Assignees != nil {
+		ids, err := g.userIDs(ctx, *update.Assignees)
+		if err != nil {
+			return nil, fmt.Errorf("update issue #%d: %w", number, err)
+		}
+		req["assignee_ids"] = ids
+	}
+
+	var gi gitlabIssue
+	if _, err := g.do(ctx, http.MethodPut, fmt.Sprintf("%s/issues/%d", pp, number), nil, req, &gi); err != nil {
+		return nil, fmt.Errorf("update issue #%d: %w", number, err)
+	}
+	return mapGitLabIssue(&gi), nil
+}
+
+// GetIssue retrieves a single issue by number.
+func (g *GitLab) GetIssue(ctx context.Context, repo string, number int) (*Issue, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var gi gitlabIssue
+	if _, err := g.do(ctx, http.MethodGet, fmt.Sprintf("%s/issues/%d", pp, number), nil, nil, &gi); err != nil {
+		return nil, fmt.Errorf("get issue #%d: %w", number, err)
+	}
+	return mapGitLabIssue(&gi), nil
+}
+
+// ListIssues returns issues matching the given filters.
+func (g *GitLab) ListIssues(ctx context.Context, repo string, opts *ListOptions) ([]*Issue, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	query := gitlabListQuery(opts)
+	if opts != nil {
+		if opts.Labels != "" {
+			query.Set("labels", opts.Labels)
+		}
+		if opts.Assignee != "" {
+			query.Set("assignee_username", opts.Assignee)
+		}
+	}
+
+	var glIssues []gitlabIssue
+	if _, err := g.do(ctx, http.MethodGet, pp+"/issues", query, nil, &glIssues); err != nil {
+		return nil, fmt.Errorf("list issues: %w", err)
+	}
+
+	issues := make([]*Issue, 0, len(glIssues))
+	for i := range glIssues {
+		issues = append(issues, mapGitLabIssue(&glIssues[i]))
+	}
+	return issues, nil
+}
+
+// AddComment posts a comment on an issue or merge request. GitLab
+// numbers the two separately, so kind picks which one number names.
+func (g *GitLab) AddComment(ctx context.Context, repo string, kind ItemKind, number int, body string) (*Comment, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	collection, webURL, err := g.noteable(ctx, pp, kind, number)
+	if err != nil {
+		return nil, fmt.Errorf("add comment to %s: %w", gitlabRef(kind, number), err)
+	}
+
+	var note gitlabNote
+	if _, err := g.do(ctx, http.MethodPost, fmt.Sprintf("%s/%s/%d/notes", pp, collection, number), nil, map[string]any{"body": body}, &note); err != nil {
+		return nil, fmt.Errorf("add comment to %s: %w", gitlabRef(kind, number), err)
+	}
+
+	return &Comment{
+		ID:        note.ID,
+		Body:      note.Body,
+		Author:    note.Author.Username,
+		URL:       fmt.Sprintf("%s#note_%d", webURL, note.ID),
+		CreatedAt: note.CreatedAt,
+	}, nil
+}
+
+// noteable looks up the issue or merge request that kind and number
+// name, returning its API collection ("issues" or "merge_requests")
+// and web URL. A project can have both an issue #N and a merge request
+// !N, so the kind is never guessed.
+func (g *GitLab) noteable(ctx context.Context, pp string, kind ItemKind, number int) (collection, webURL string, err error) {
+	switch kind {
+	case ItemIssue:
+		collection = "issues"
+	case ItemPR:
+		collection = "merge_requests"
+	default:
+		return "", "", fmt.Errorf("unknown item kind %q", kind)
+	}
+	var obj struct {
+		WebURL string `json:"web_url"`
+	}
+	if _, err := g.do(ctx, http.MethodGet, fmt.Sprintf("%s/%s/%d", pp, collection, number), nil, nil, &obj); err != nil {
+		if isGitLabNotFound(err) {
+			return "", "", fmt.Errorf("no such %s", gitlabRef(kind, number))
+		}
+		return "", "", err
+	}
+	return collection, obj.WebURL, nil
+}
+
+// gitlabRef renders kind and number the way GitLab writes references:
+// "issue #N" or "merge request !N".
+func gitlabRef(kind ItemKind, number int) string {
+	if kind == ItemPR {
+		return fmt.Sprintf("merge request !%d", number)
+	}
+	return fmt.Sprintf("issue #%d", number)
+}
+
+// --- Pull Requests ---
+
+// ListPRs returns merge requests matching the given filters.
+func (g *GitLab) ListPRs(ctx context.Context, repo string, opts *ListOptions) ([]*PullRequest, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	query := gitlabListQuery(opts)
+	if opts != nil {
+		if opts.Base != "" {
+			query.Set("target_branch", opts.Base)
+		}
+		if opts.Head != "" {
+			query.Set("source_branch", opts.Head)
+		}
+	}
+
+	var glMRs []gitlabMergeRequest
+	if _, err := g.do(ctx, http.MethodGet, pp+"/merge_requests", query, nil, &glMRs); err != nil {
+		return nil, fmt.Errorf("list PRs: %w", err)
+	}
+
+	prs := make([]*PullRequest, 0, len(glMRs))
+	for i := range glMRs {
+		prs = append(prs, mapGitLabMR(&glMRs[i]))
+	}
+	return prs, nil
+}
+
+// GetPR retrieves a single merge request by number.
+func (g *GitLab) GetPR(ctx context.Context, repo string, number int) (*PullRequest, error) {
+	mr, err := g.getMR(ctx, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("get PR #%d: %w", number, err)
+	}
+	return mapGitLabMR(mr), nil
+}
+
+// getMR fetches the raw merge request.
+func (g *GitLab) getMR(ctx context.Context, repo string, number int) (*gitlabMergeRequest, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+	var mr gitlabMergeRequest
+	if _, err := g.do(ctx, http.MethodGet, fmt.Sprintf("%s/merge_requests/%d", pp, number), nil, nil, &mr); err != nil {
+		return nil, err
+	}
+	return &mr, nil
+}
+
+// listMRDiffs pages through a merge request's per-file diffs.
+func (g *GitLab) listMRDiffs(ctx context.Context, repo string, number int) ([]gitlabDiff, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []gitlabDiff
+	page := "1"
+	for i := 0; i < gitlabMaxDiffPages && page != ""; i++ {
+		var batch []gitlabDiff
+		query := url.Values{"per_page": {"100"}, "page": {page}}
+		h, err := g.do(ctx, http.MethodGet, fmt.Sprintf("%s/merge_requests/%d/diffs", pp, number), query, nil, &batch)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, batch...)
+		page = h.Get("X-Next-Page")
+	}
+	return diffs, nil
+}
+
+// GetPRFiles returns the files changed in a merge request.
+func (g *GitLab) GetPRFiles(ctx context.Context, repo string, number int) ([]*ChangedFile, error) {
+	diffs, err := g.listMRDiffs(ctx, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("list PR #%d files: %w", number, err)
+	}
+
+	files := make([]*ChangedFile, 0, len(diffs))
+	for _, d := range diffs {
+		file := &ChangedFile{
+			Filename: d.NewPath,
+			Status:   "modified",
+			Patch:    d.Diff,
+		}
+		switch {
+		case d.NewFile:
+			file.Status = "added"
+		case d.DeletedFile:
+			file.Status = "removed"
+			file.Filename = d.OldPath
+		case d.RenamedFile:
+			file.Status = "renamed"
+		}
+		file.Additions, file.Deletions = countDiffLines(d.Diff)
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// GetPRDiff returns the unified diff for a merge request, assembled
+// from its per-file diffs.
+func (g *GitLab) GetPRDiff(ctx context.Context, repo string, number int) (string, error) {
+	diffs, err := g.listMRDiffs(ctx, repo, number)
+	if err != nil {
+		return "", fmt.Errorf("get PR #%d diff: %w", number, err)
+	}
+
+	var sb strings.Builder
+	for _, d := range diffs {
+		oldName, newName := "a/"+d.OldPath, "b/"+d.NewPath
+		fmt.Fprintf(&sb, "diff --git %s %s\n", oldName, newName)
+		if d.NewFile {
+			oldName = "/dev/null"
+		}
+		if d.DeletedFile {
+			newName = "/dev/null"
+		}
+		fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+		sb.WriteString(d.Diff)
+		if d.Diff != "" && !strings.HasSuffix(d.Diff, "\n") {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String(), nil
+}
+
+// ListPRCommits returns commits in a merge request.
+func (g *GitLab) ListPRCommits(ctx context.Context, repo string, number int) ([]*Commit, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var glCommits []gitlabCommit
+	query := url.Values{"per_page": {"100"}}
+	if _, err := g.do(ctx, http.MethodGet, fmt.Sprintf("%s/merge_requests/%d/commits", pp, number), query, nil, &glCommits); err != nil {
+		return nil, fmt.Errorf("list PR #%d commits: %w", number, err)
+	}
+
+	commits := make([]*Commit, 0, len(glCommits))
+	for _, c := range glCommits {
+		commits = append(commits, mapGitLabCommit(c))
+	}
+	return commits, nil
+}
+
+// ListPRReviews returns reviews for a merge request. GitLab has no
+// review objects, so each approval is reported as an "APPROVED" review
+// and each user's diff comments are grouped into a "COMMENTED" review.
+func (g *GitLab) ListPRReviews(ctx context.Context, repo string, number int) ([]*Review, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+	mrPath := fmt.Sprintf("%s/merge_requests/%d", pp, number)
+
+	var approvals struct {
+		ApprovedBy []struct {
+			User gitlabUser `json:"user"`
+		} `json:"approved_by"`
+	}
+	if _, err := g.do(ctx, http.MethodGet, mrPath+"/approvals", nil, nil, &approvals); err != nil {
+		return nil, fmt.Errorf("list PR #%d reviews: %w", number, err)
+	}
+
+	var reviews []*Review
+	for _, a := range approvals.ApprovedBy {
+		reviews = append(reviews, &Review{Author: a.User.Username, State: "APPROVED"})
+	}
+
+	var notes []gitlabNote
+	query := url.Values{"per_page": {"100"}, "sort": {"asc"}}
+	if _, err := g.do(ctx, http.MethodGet, mrPath+"/notes", query, nil, &notes); err != nil {
+		g.logger.Warn("failed to fetch merge request notes",
+			"number", number,
+			"error", err,
+		)
+		return reviews, nil
+	}
+
+	byAuthor := make(map[string]*Review)
+	for _, n := range notes {
+		if n.System || n.Type != "DiffNote" || n.Position == nil {
+			continue
+		}
+		review, ok := byAuthor[n.Author.Username]
+		if !ok {
+			review = &Review{
+				ID:          n.ID,
+				Author:      n.Author.Username,
+				State:       "COMMENTED",
+				SubmittedAt: n.CreatedAt,
+			}
+			byAuthor[n.Author.Username] = review
+			reviews = append(reviews, review)
+		}
+		review.InlineComments = append(review.InlineComments, mapGitLabDiffNote(n))
+	}
+
+	return reviews, nil
+}
+
+// SubmitReview approves a merge request or comments on it. GitLab has
+// no API for requesting changes, so REQUEST_CHANGES posts the body as
+// a comment and reports the review as "COMMENTED".
+func (g *GitLab) SubmitReview(ctx context.Context, repo string, number int, review *ReviewSubmission) (*Review, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+	mrPath := fmt.Sprintf("%s/merge_requests/%d", pp, number)
+
+	result := &Review{State: "COMMENTED", Body: review.Body}
+	if review.Event == "APPROVE" {
+		if _, err := g.do(ctx, http.MethodPost, mrPath+"/approve", nil, nil, nil); err != nil {
+			return nil, fmt.Errorf("submit review on PR #%d: %w", number, err)
+		}
+		result.State = "APPROVED"
+	} else if review.Body == "" {
+		return nil, fmt.Errorf("submit review on PR #%d: body is required for %s", number, review.Event)
+	}
+
+	if review.Body != "" {
+		var note gitlabNote
+		if _, err := g.do(ctx, http.MethodPost, mrPath+"/notes", nil, map[string]any{"body": review.Body}, &note); err != nil {
+			return nil, fmt.Errorf("submit review on PR #%d: %w", number, err)
+		}
+		result.ID = note.ID
+		result.Author = note.Author.Username
+		result.SubmittedAt = note.CreatedAt
+	}
+
+	return result, nil
+}
+
+// AddReviewComment posts an inline comment on a merge request diff.
+func (g *GitLab) AddReviewComment(ctx context.Context, repo string, number int, comment *ReviewComment) (*ReviewComment, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	mr, err := g.getMR(ctx, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("add review comment on PR #%d: %w", number, err)
+	}
+	if mr.DiffRefs == nil {
+		return nil, fmt.Errorf("add review comment on PR #%d: merge request has no diff", number)
+	}
+
+	line := comment.Line
+	pos := gitlabPosition{
+		PositionType: "text",
+		BaseSHA:      mr.DiffRefs.BaseSHA,
+		StartSHA:     mr.DiffRefs.StartSHA,
+		HeadSHA:      mr.DiffRefs.HeadSHA,
+		OldPath:      comment.Path,
+		NewPath:      comment.Path,
+	}
+	if comment.Side == "LEFT" {
+		pos.OldLine = &line
+	} else {
+		pos.NewLine = &line
+	}
+
+	var discussion struct {
+		Notes []gitlabNote `json:"notes"`
+	}
+	req := map[string]any{"body": comment.Body, "position": pos}
+	if _, err := g.do(ctx, http.MethodPost, fmt.Sprintf("%s/merge_requests/%d/discussions", pp, number), nil, req, &discussion); err != nil {
+		return nil, fmt.Errorf("add review comment on PR #%d: %w", number, err)
+	}
+	if len(discussion.Notes) == 0 {
+		return nil, fmt.Errorf("add review comment on PR #%d: empty discussion in response", number)
+	}
+
+	return mapGitLabDiffNote(discussion.Notes[0]), nil
+}
+
+// ListChecks returns the jobs of a merge request's head pipeline as
+// check runs. A merge request without a pipeline has no checks.
+func (g *GitLab) ListChecks(ctx context.Context, repo string, number int) ([]*CheckRun, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	mr, err := g.getMR(ctx, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("get PR #%d for checks: %w", number, err)
+	}
+	if mr.HeadPipeline == nil {
+		return []*CheckRun{}, nil
+	}
+
+	var jobs []gitlabJob
+	query := url.Values{"per_page": {"100"}}
+	if _, err := g.do(ctx, http.MethodGet, fmt.Sprintf("%s/pipelines/%d/jobs", pp, mr.HeadPipeline.ID), query, nil, &jobs); err != nil {
+		return nil, fmt.Errorf("list checks for PR #%d: %w", number, err)
+	}
+
+	checks := make([]*CheckRun, 0, len(jobs))
+	for _, j := range jobs {
+		status, conclusion := mapGitLabJobStatus(j.Status, j.AllowFailure)
+		checks = append(checks, &CheckRun{
+			Name:        j.Name,
+			Status:      status,
+			Conclusion:  conclusion,
+			StartedAt:   j.StartedAt,
+			CompletedAt: j.FinishedAt,
+			DetailsURL:  j.WebURL,
+		})
+	}
+	return checks, nil
+}
+
+// MergePR merges a merge request. "squash" (the default) squashes the
+// commits; "merge" keeps them. Whether GitLab creates a merge commit or
+// fast-forwards is a project setting, so "rebase" is not supported.
+func (g *GitLab) MergePR(ctx context.Context, repo string, number int, opts *MergeOptions) (*MergeResult, error) {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	method := "squash"
+	if opts != nil && opts.Method != "" {
+		method = opts.Method
+	}
+	if method != "squash" && method != "merge" {
+		return nil, fmt.Errorf("merge PR #%d: gitlab does not support merge method %q; use squash or merge", number, method)
+	}
+
+	req := map[string]any{"squash": method == "squash"}
+	if opts != nil && (opts.CommitTitle != "" || opts.CommitMessage != "") {
+		msg := strings.TrimSpace(opts.CommitTitle + "\n\n" + opts.CommitMessage)
+		if method == "squash" {
+			req["squash_commit_message"] = msg
+		} else {
+			req["merge_commit_message"] = msg
+		}
+	}
+
+	var mr gitlabMergeRequest
+	if _, err := g.do(ctx, http.MethodPut, fmt.Sprintf("%s/merge_requests/%d/merge", pp, number), nil, req, &mr); err != nil {
+		return nil, fmt.Errorf("merge PR #%d: %w", number, err)
+	}
+
+	sha := mr.MergeCommitSHA
+	if sha == "" {
+		sha = mr.SquashCommitSHA
+	}
+	if sha == "" {
+		sha = mr.SHA
+	}
+	return &MergeResult{
+		SHA:     sha,
+		Message: fmt.Sprintf("Merge request !%d merged", number),
+	}, nil
+}
+
+// --- Reactions ---
+
+// gitlabEmoji maps the GitHub reaction names accepted by forge_react to
+// GitLab award emoji names.
+var gitlabEmoji = map[string]string{
+	"+1":     "thumbsup",
+	"-1":     "thumbsdown",
+	"laugh":  "laughing",
+	"hooray": "tada",
+}
+
+// AddReaction awards an emoji to an issue/merge request or a specific
+// comment.
+func (g *GitLab) AddReaction(ctx context.Context, repo string, kind ItemKind, number int, commentID int64, emoji string) error {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return err
+	}
+
+	collection, _, err := g.noteable(ctx, pp, kind, number)
+	if err != nil {
+		return fmt.Errorf("add reaction to %s: %w", gitlabRef(kind, number), err)
+	}
+	if name, ok := gitlabEmoji[emoji]; ok {
+		emoji = name
+	}
+
+	target := fmt.Sprintf("%s/%s/%d", pp, collection, number)
+	if commentID > 0 {
+		target = fmt.Sprintf("%s/notes/%d", target, commentID)
+	}
+	if _, err := g.do(ctx, http.MethodPost, target+"/award_emoji", nil, map[string]any{"name": emoji}, nil); err != nil {
+		if commentID > 0 {
+			return fmt.Errorf("add reaction to comment %d: %w", commentID, err)
+		}
+		return fmt.Errorf("add reaction to %s: %w", gitlabRef(kind, number), err)
+	}
+	return nil
+}
+
+// --- Review Management ---
+
+// RequestReview adds the specified users to the merge request's
+// reviewers, keeping existing ones.
+func (g *GitLab) RequestReview(ctx context.Context, repo string, number int, reviewers []string) error {
+	pp, err := projectPath(repo)
+	if err != nil {
+		return err
+	}
+
+	mr, err := g.getMR(ctx, repo, number)
+	if err != nil {
+		return fmt.Errorf("request review on PR #%d: %w", number, err)
+	}
+	added, err := g.userIDs(ctx, reviewers)
+	if err != nil {
+		return fmt.Errorf("request review on PR #%d: %w", number, err)
+	}
+
+	ids := make([]int64, 0, len(mr.Reviewers)+len(added))
+	seen := make(map[int64]bool)
+	for _, r := range mr.Reviewers {
+		ids = append(ids, r.ID)
+		seen[r.ID] = true
+	}
+	for _, id := range added {
+		if !seen[id] {
+			ids = append(ids, id)
+		}
+	}
+
+	if _, err := g.do(ctx, http.MethodPut, fmt.Sprintf("%s/merge_requests/%d", pp, number), nil, map[string]any{"reviewer_ids": ids}, nil); err != nil {
+		return fmt.Errorf("request review on PR #%d: %w", number, err)
+	}
+	return nil
+}
+
+// userIDs resolves usernames to GitLab user IDs.
+func (g *GitLab) userIDs(ctx context.Context, usernames []string) ([]int64, error) {
+	ids := make([]int64, 0, len(usernames))
+	for _, name := range usernames {
+		var users []gitlabUser
+		if _, err := g.do(ctx, http.MethodGet, "/users", url.Values{"username": {name}}, nil, &users); err != nil {
+			return nil, fmt.Errorf("look up user %q: %w", name, err)
+		}
+		if len(users) == 0 {
+			return nil, fmt.Errorf("gitlab user %q not found", name)
+		}
+		ids = append(ids, users[0].ID)
+	}
+	return ids, nil
+}
+
+// --- Search ---
+
+// Search performs a GitLab search of the given kind. A "repo:owner/name"
+// term in the query scopes the search to that project; code and commit
+// search across all projects requires GitLab advanced search, so scope
+// those searches when the instance lacks it. Issue searches include
+// merge requests.
+func (g *GitLab) Search(ctx context.Context, query string, kind SearchKind, limit int) ([]SearchResult, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	repo, terms := splitRepoQualifier(query)
+	base := "/search"
+	webURL := ""
+	if repo != "" {
+		pp, err := projectPath(repo)
+		if err != nil {
+			return nil, err
+		}
+		base = pp + "/search"
+		if kind == SearchCode {
+			if p, err := g.GetRepository(ctx, repo); err == nil {
+				webURL = p.URL
+			}
+		}
+	}
+
+	search := func(scope string, out any) error {
+		q := url.Values{"scope": {scope}, "search": {terms}, "per_page": {gitlabPerPage(limit)}}
+		_, err := g.do(ctx, http.MethodGet, base, q, nil, out)
+		return err
+	}
+
+	switch kind {
+	case SearchIssues:
+		var issues, mrs []gitlabIssue
+		if err := search("issues", &issues); err != nil {
+			return nil, fmt.Errorf("search issues: %w", err)
+		}
+		if err := search("merge_requests", &mrs); err != nil {
+			return nil, fmt.Errorf("search issues: %w", err)
+		}
+
+		results := make([]SearchResult, 0, len(issues)+len(mrs))
+		for _, i := range append(issues, mrs...) {
+			if len(results) == limit {
+				break
+			}
+			results = append(results, SearchResult{
+				Number: i.IID,
+				Title:  i.Title,
+				URL:    i.WebURL,
+				Body:   truncate(i.Description, 200),
+			})
+		}
+		return results, nil
+
+	case SearchCode:
+		var blobs []struct {
+			Path      string `json:"path"`
+			Ref       string `json:"ref"`
+			Startline int    `json:"startline"`
+		}
+		if err := search("blobs", &blobs); err != nil {
+			return nil, fmt.Errorf("search code: %w", err)
+		}
+
+		results := make([]SearchResult, 0, len(blobs))
+		for _, b := range blobs {
+			result := SearchResult{Title: path.Base(b.Path), Body: b.Path}
+			if webURL != "" {
+				result.URL = fmt.Sprintf("%s/-/blob/%s/%s#L%d", webURL, b.Ref, b.Path, b.Startline)
+			}
+			results = append(results, result)
+		}
+		return results, nil
+
+	case SearchCommits:
+		var glCommits []gitlabCommit
+		if err := search("commits", &glCommits); err != nil {
+			return nil, fmt.Errorf("search commits: %w", err)
+		}
+
+		results := make([]SearchResult, 0, len(glCommits))
+		for _, c := range glCommits {
+			results = append(results, SearchResult{
+				Title: shortSHA(c.ID),
+				URL:   c.WebURL,
+				Body:  truncate(c.Message, 200),
+			})
+		}
+		return results, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported search kind %q", kind)
+	}
+}
+
+// splitRepoQualifier removes a "repo:owner/name" term from a GitHub
+// style query, returning the repo and the remaining search terms.
+func splitRepoQualifier(query string) (repo, terms string) {
+	fields := strings.Fields(query)
+	kept := fields[:0]
+	for _, f := range fields {
+		if r, ok := strings.CutPrefix(f, "repo:"); ok && repo == "" {
+			repo = r
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return repo, strings.Join(kept, " ")
+}
+
+// --- Mapping helpers ---
+
+// gitlabListQuery translates the shared list filters to GitLab query
+// parameters.
+func gitlabListQuery(opts *ListOptions) url.Values {
+	query := url.Values{"per_page": {"30"}, "page": {"1"}}
+	if opts == nil {
+		return query
+	}
+	if opts.Limit > 0 && opts.Limit <= 100 {
+		query.Set("per_page", strconv.Itoa(opts.Limit))
+	}
+	if opts.Page > 0 {
+		query.Set("page", strconv.Itoa(opts.Page))
+	}
+	switch opts.State {
+	case "":
+	case "open":
+		query.Set("state", "opened")
+	default:
+		query.Set("state", opts.State)
+	}
+	switch opts.Sort {
+	case "created":
+		query.Set("order_by", "created_at")
+	case "updated":
+		query.Set("order_by", "updated_at")
+	}
+	if opts.Direction != "" {
+		query.Set("sort", opts.Direction)
+	}
+	return query
+}
+
+// gitlabState maps GitLab's "opened" to the shared "open".
+func gitlabState(state string) string {
+	if state == "opened" {
+		return "open"
+	}
+	return state
+}
+
+func mapGitLabIssue(gi *gitlabIssue) *Issue {
+	issue := &Issue{
+		Number:       gi.IID,
+		Title:        gi.Title,
+		Body:         gi.Description,
+		State:        gitlabState(gi.State),
+		Labels:       gi.Labels,
+		Author:       gi.Author.Username,
+		URL:          gi.WebURL,
+		CreatedAt:    gi.CreatedAt,
+		UpdatedAt:    gi.UpdatedAt,
+		CommentCount: gi.UserNotesCount,
+	}
+	for _, a := range gi.Assignees {
+		issue.Assignees = append(issue.Assignees, a.Username)
+	}
+	return issue
+}
+
+func mapGitLabMR(mr *gitlabMergeRequest) *PullRequest {
+	pr := &PullRequest{
+		Number:       mr.IID,
+		Title:        mr.Title,
+		Body:         mr.Description,
+		State:        gitlabState(mr.State),
+		Draft:        mr.Draft,
+		Author:       mr.Author.Username,
+		Head:         mr.SourceBranch,
+		Base:         mr.TargetBranch,
+		Labels:       mr.Labels,
+		CommentCount: mr.UserNotesCount,
+		URL:          mr.WebURL,
+		CreatedAt:    mr.CreatedAt,
+		UpdatedAt:    mr.UpdatedAt,
+	}
+	// changes_count is a string and reads "1000+" past GitLab's limit.
+	pr.ChangedFiles, _ = strconv.Atoi(strings.TrimSuffix(mr.ChangesCount, "+"))
+
+	switch {
+	case mr.MergeStatus == "can_be_merged" || mr.DetailedMergeStatus == "mergeable":
+		mergeable := true
+		pr.Mergeable = &mergeable
+	case strings.HasPrefix(mr.MergeStatus, "cannot_be_merged") || mr.DetailedMergeStatus == "conflict":
+		mergeable := false
+		pr.Mergeable = &mergeable
+	}
+
+	for _, a := range mr.Assignees {
+		pr.Assignees = append(pr.Assignees, a.Username)
+	}
+	for _, r := range mr.Reviewers {
+		pr.RequestedReviewers = append(pr.RequestedReviewers, r.Username)
+	}
+	return pr
+}
+
+func mapGitLabCommit(c gitlabCommit) *Commit {
+	commit := &Commit{
+		SHA:     shortSHA(c.ID),
+		Message: c.Message,
+		Author:  c.AuthorName,
+		Date:    c.AuthoredDate,
+		URL:     c.WebURL,
+	}
+	if commit.Author == "" {
+		commit.Author = c.CommitterName
+		commit.Date = c.CommittedDate
+	}
+	return commit
+}
+
+func mapGitLabDiffNote(n gitlabNote) *ReviewComment {
+	rc := &ReviewComment{ID: n.ID, Body: n.Body}
+	if p := n.Position; p != nil {
+		switch {
+		case p.NewLine != nil:
+			rc.Path, rc.Line, rc.Side = p.NewPath, *p.NewLine, "RIGHT"
+		case p.OldLine != nil:
+			rc.Path, rc.Line, rc.Side = p.OldPath, *p.OldLine, "LEFT"
+		default:
+			rc.Path = p.NewPath
+		}
+	}
+	return rc
+}
+
+// mapGitLabJobStatus maps a GitLab job status to check run status and
+// conclusion.
+func mapGitLabJobStatus(status string, allowFailure bool) (string, string) {
+	switch status {
+	case "running":
+		return "in_progress", ""
+	case "success":
+		return "completed", "success"
+	case "failed":
+		if allowFailure {
+			return "completed", "neutral"
+		}
+		return "completed", "failure"
+	case "canceled":
+		return "completed", "cancelled"
+	case "skipped":
+		return "completed", "skipped"
+	case "manual":
+		return "completed", "action_required"
+	default: // created, pending, preparing, scheduled, waiting_for_resource
+		return "queued", ""
+	}
+}
+
+// countDiffLines counts added and removed lines in a diff body. GitLab
+// diffs start at the first hunk, without ---/+++ file headers.
+func countDiffLines(diff string) (additions, deletions int) {
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
+
+// shortSHA abbreviates a commit hash to 7 characters.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,317 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestGitLab creates a GitLab provider backed by the given handler.
+// The test server is closed automatically when the test finishes.
+func newTestGitLab(t *testing.T, handler http.Handler) *GitLab {
+	t.Helper()
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	gl, err := NewGitLab(ts.Client(), "test-token", ts.URL, logger)
+	if err != nil {
+		t.Fatalf("NewGitLab: %v", err)
+	}
+	return gl
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestGitLabGetIssue(t *testing.T) {
+	var gotToken, gotProject string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{project}/issues/42", func(w http.ResponseWriter, r *http.Request) {
+		gotToken = r.Header.Get("PRIVATE-TOKEN")
+		gotProject = r.PathValue("project")
+		writeJSON(w, map[string]any{
+			"iid":              42,
+			"title":            "Test issue",
+			"description":      "Issue body text",
+			"state":            "opened",
+			"web_url":          "https://gitlab.com/group/sub/repo/-/issues/42",
+			"user_notes_count": 3,
+			"created_at":       "2025-01-15T10:00:00.000Z",
+			"updated_at":       "2025-01-16T12:00:00.000Z",
+			"author":           map[string]any{"id": 1, "username": "alice"},
+			"labels":           []string{"bug", "urgent"},
+			"assignees":        []map[string]any{{"id": 2, "username": "bob"}},
+		})
+	})
+
+	gl := newTestGitLab(t, mux)
+	issue, err := gl.GetIssue(context.Background(), "group/sub/repo", 42)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+
+	if gotToken != "test-token" {
+		t.Errorf("PRIVATE-TOKEN = %q, want %q", gotToken, "test-token")
+	}
+	if gotProject != "group/sub/repo" {
+		t.Errorf("project = %q, want the nested path encoded as one segment", gotProject)
+	}
+	if issue.Number != 42 || issue.Title != "Test issue" || issue.Body != "Issue body text" {
+		t.Errorf("issue = %+v", issue)
+	}
+	if issue.State != "open" {
+		t.Errorf("State = %q, want %q", issue.State, "open")
+	}
+	if issue.Author != "alice" || issue.CommentCount != 3 {
+		t.Errorf("Author = %q, CommentCount = %d; want alice, 3", issue.Author, issue.CommentCount)
+	}
+	if len(issue.Assignees) != 1 || issue.Assignees[0] != "bob" {
+		t.Errorf("Assignees = %v, want [bob]", issue.Assignees)
+	}
+}
+
+func TestGitLabErrorMessage(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{project}/issues/9", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"404 Issue Not Found"}`))
+	})
+
+	gl := newTestGitLab(t, mux)
+	_, err := gl.GetIssue(context.Background(), "owner/repo", 9)
+	if !isGitLabNotFound(err) {
+		t.Fatalf("GetIssue error = %v, want a gitlab 404", err)
+	}
+	if !strings.Contains(err.Error(), "404 Issue Not Found") {
+		t.Errorf("error = %q, want the API message", err)
+	}
+}
+
+func TestGitLabGetPR(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{project}/merge_requests/7", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{
+			"iid":           7,
+			"title":         "Add feature",
+			"state":         "opened",
+			"draft":         true,
+			"source_branch": "feature",
+			"target_branch": "main",
+			"merge_status":  "can_be_merged",
+			"changes_count": "1000+",
+			"author":        map[string]any{"username": "alice"},
+			"reviewers":     []map[string]any{{"id": 3, "username": "carol"}},
+			"web_url":       "https://gitlab.com/owner/repo/-/merge_requests/7",
+		})
+	})
+
+	gl := newTestGitLab(t, mux)
+	pr, err := gl.GetPR(context.Background(), "owner/repo", 7)
+	if err != nil {
+		t.Fatalf("GetPR: %v", err)
+	}
+
+	if pr.State != "open" || !pr.Draft || pr.Head != "feature" || pr.Base != "main" {
+		t.Errorf("pr = %+v", pr)
+	}
+	if pr.Mergeable == nil || !*pr.Mergeable {
+		t.Errorf("Mergeable = %v, want true", pr.Mergeable)
+	}
+	if pr.ChangedFiles != 1000 {
+		t.Errorf("ChangedFiles = %d, want 1000", pr.ChangedFiles)
+	}
+	if len(pr.RequestedReviewers) != 1 || pr.RequestedReviewers[0] != "carol" {
+		t.Errorf("RequestedReviewers = %v, want [carol]", pr.RequestedReviewers)
+	}
+}
+
+func TestGitLabGetPRDiff(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{project}/merge_requests/7/diffs", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set("X-Next-Page", "2")
+			writeJSON(w, []map[string]any{{
+				"old_path": "main.go",
+				"new_path": "main.go",
+				"diff":     "@@ -1,3 +1,3 @@\n package main\n-func old() {}\n+func new() {}\n",
+			}})
+			return
+		}
+		writeJSON(w, []map[string]any{{
+			"old_path": "README.md",
+			"new_path": "README.md",
+			"new_file": true,
+			"diff":     "@@ -0,0 +1 @@\n+# Title",
+		}})
+	})
+
+	gl := newTestGitLab(t, mux)
+	diff, err := gl.GetPRDiff(context.Background(), "owner/repo", 7)
+	if err != nil {
+		t.Fatalf("GetPRDiff: %v", err)
+	}
+
+	wantDiff := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n" +
+		"@@ -1,3 +1,3 @@\n package main\n-func old() {}\n+func new() {}\n" +
+		"diff --git a/README.md b/README.md\n--- /dev/null\n+++ b/README.md\n" +
+		"@@ -0,0 +1 @@\n+# Title\n"
+	if diff != wantDiff {
+		t.Errorf("diff mismatch:\ngot:  %q\nwant: %q", diff, wantDiff)
+	}
+
+	files, err := gl.GetPRFiles(context.Background(), "owner/repo", 7)
+	if err != nil {
+		t.Fatalf("GetPRFiles: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("len(files) = %d, want 2", len(files))
+	}
+	if files[0].Status != "modified" || files[0].Additions != 1 || files[0].Deletions != 1 {
+		t.Errorf("files[0] = %+v, want modified +1 -1", files[0])
+	}
+	if files[1].Status != "added" || files[1].Additions != 1 {
+		t.Errorf("files[1] = %+v, want added +1", files[1])
+	}
+}
+
+func TestGitLabAddComment_TargetsRequestedKind(t *testing.T) {
+	// Issue #7 and merge request !7 both exist; the comment must land
+	// on the merge request the caller named.
+	var posted, issuePosted string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{project}/issues/7", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{"iid": 7, "web_url": "https://gitlab.com/owner/repo/-/issues/7"})
+	})
+	mux.HandleFunc("POST /api/v4/projects/{project}/issues/7/notes", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		issuePosted = req["body"]
+		writeJSON(w, map[string]any{"id": 1, "body": issuePosted, "author": map[string]any{"username": "thane"}})
+	})
+	mux.HandleFunc("GET /api/v4/projects/{project}/merge_requests/7", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{"iid": 7, "web_url": "https://gitlab.com/owner/repo/-/merge_requests/7"})
+	})
+	mux.HandleFunc("POST /api/v4/projects/{project}/merge_requests/7/notes", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		posted = req["body"]
+		writeJSON(w, map[string]any{"id": 555, "body": posted, "author": map[string]any{"username": "thane"}})
+	})
+
+	gl := newTestGitLab(t, mux)
+	comment, err := gl.AddComment(context.Background(), "owner/repo", ItemPR, 7, "Looks good")
+	if err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+
+	if posted != "Looks good" || issuePosted != "" {
+		t.Errorf("merge request body = %q, issue body = %q; want only the merge request commented", posted, issuePosted)
+	}
+	if comment.ID != 555 || comment.URL != "https://gitlab.com/owner/repo/-/merge_requests/7#note_555" {
+		t.Errorf("comment = %+v", comment)
+	}
+
+	if _, err := gl.AddComment(context.Background(), "owner/repo", ItemIssue, 8, "Missing"); err == nil || !strings.Contains(err.Error(), "no such issue #8") {
+		t.Errorf("AddComment on a missing issue: err = %v, want no such issue #8", err)
+	}
+}
+
+func TestGitLabListChecks(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{project}/merge_requests/7", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{"iid": 7, "head_pipeline": map[string]any{"id": 99}})
+	})
+	mux.HandleFunc("GET /api/v4/projects/{project}/pipelines/99/jobs", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, []map[string]any{
+			{"name": "lint", "status": "success", "web_url": "https://gitlab.com/jobs/1"},
+			{"name": "flaky", "status": "failed", "allow_failure": true},
+			{"name": "test", "status": "running"},
+			{"name": "deploy", "status": "pending"},
+		})
+	})
+
+	gl := newTestGitLab(t, mux)
+	checks, err := gl.ListChecks(context.Background(), "owner/repo", 7)
+	if err != nil {
+		t.Fatalf("ListChecks: %v", err)
+	}
+
+	want := []struct{ status, conclusion string }{
+		{"completed", "success"},
+		{"completed", "neutral"},
+		{"in_progress", ""},
+		{"queued", ""},
+	}
+	if len(checks) != len(want) {
+		t.Fatalf("len(checks) = %d, want %d", len(checks), len(want))
+	}
+	for i, w := range want {
+		if checks[i].Status != w.status || checks[i].Conclusion != w.conclusion {
+			t.Errorf("checks[%d] = %s/%s, want %s/%s", i, checks[i].Status, checks[i].Conclusion, w.status, w.conclusion)
+		}
+	}
+}
+
+func TestGitLabSearch_ScopesToRepo(t *testing.T) {
+	var gotScopes []string
+	var gotSearch string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{project}/search", func(w http.ResponseWriter, r *http.Request) {
+		gotScopes = append(gotScopes, r.URL.Query().Get("scope"))
+		gotSearch = r.URL.Query().Get("search")
+		if r.URL.Query().Get("scope") == "issues" {
+			writeJSON(w, []map[string]any{{"iid": 3, "title": "Crash on start", "web_url": "https://gitlab.com/o/r/-/issues/3"}})
+			return
+		}
+		writeJSON(w, []map[string]any{{"iid": 4, "title": "Fix crash", "web_url": "https://gitlab.com/o/r/-/merge_requests/4"}})
+	})
+
+	gl := newTestGitLab(t, mux)
+	results, err := gl.Search(context.Background(), "crash repo:o/r", SearchIssues, 10)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if gotSearch != "crash" {
+		t.Errorf("search = %q, want the repo qualifier removed", gotSearch)
+	}
+	if strings.Join(gotScopes, ",") != "issues,merge_requests" {
+		t.Errorf("scopes = %v, want issues and merge_requests", gotScopes)
+	}
+	if len(results) != 2 || results[0].Number != 3 || results[1].Number != 4 {
+		t.Errorf("results = %+v", results)
+	}
+}
+
+func TestNewGitLabBaseURL(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "https://gitlab.com/api/v4"},
+		{"https://gitlab.example.com/", "https://gitlab.example.com/api/v4"},
+		{"https://gitlab.example.com/api/v4", "https://gitlab.example.com/api/v4"},
+	}
+	for _, tt := range tests {
+		gl, err := NewGitLab(http.DefaultClient, "tok", tt.in, discardLogger())
+		if err != nil {
+			t.Fatalf("NewGitLab(%q): %v", tt.in, err)
+		}
+		if gl.baseURL != tt.want {
+			t.Errorf("NewGitLab(%q).baseURL = %q, want %q", tt.in, gl.baseURL, tt.want)
+		}
+	}
+	if _, err := NewGitLab(http.DefaultClient, "tok", "gitlab.example.com", discardLogger()); err == nil {
+		t.Error("NewGitLab accepted a URL without a scheme")
+	}
+}
@@ -11,7 +11,7 @@ import "context"
 // repository parameters use the "owner/repo" format — bare repo name
 // resolution happens in the tool layer, not the provider.
 type ForgeProvider interface {
-	// Name returns the provider identifier (e.g., "github", "gitea").
+	// Name returns the provider identifier (e.g., "github", "gitlab").
 	Name() string
 
 	// --- Repositories ---
@@ -43,8 +43,9 @@ type ForgeProvider interface {
 	// ListIssues returns issues matching the given filters.
 	ListIssues(ctx context.Context, repo string, opts *ListOptions) ([]*Issue, error)
 
-	// AddComment posts a comment on an issue or pull request.
-	AddComment(ctx context.Context, repo string, number int, body string) (*Comment, error)
+	// AddComment posts a comment on the issue or pull request that
+	// kind and number name.
+	AddComment(ctx context.Context, repo string, kind ItemKind, number int, body string) (*Comment, error)
 
 	// --- Pull Requests ---
 
@@ -82,9 +83,9 @@ type ForgeProvider interface {
 	// --- Reactions ---
 
 	// AddReaction adds an emoji reaction to an issue/PR or a specific
-	// comment. When commentID is 0, the reaction targets the
+	// comment on it. When commentID is 0, the reaction targets the
 	// issue/PR itself.
-	AddReaction(ctx context.Context, repo string, number int, commentID int64, emoji string) error
+	AddReaction(ctx context.Context, repo string, kind ItemKind, number int, commentID int64, emoji string) error
 
 	// --- Review Management ---
 
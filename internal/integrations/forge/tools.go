@@ -502,11 +502,16 @@ func (t *Tools) HandleIssueComment(ctx context.Context, args map[string]any) (st
 		return "", fmt.Errorf("number is required")
 	}
 
+	kind, err := ParseItemKind(stringArg(args, "kind"))
+	if err != nil {
+		return "", err
+	}
+
 	body := stringArg(args, "body")
 	if body == "" {
 		return "", fmt.Errorf("body is required")
 	}
-	comment, err := provider.AddComment(ctx, repo, number, body)
+	comment, err := provider.AddComment(ctx, repo, kind, number, body)
 	if err != nil {
 		return "", err
 	}
@@ -985,6 +990,11 @@ func (t *Tools) HandleReact(ctx context.Context, args map[string]any) (string, e
 		return "", fmt.Errorf("number is required")
 	}
 
+	kind, err := ParseItemKind(stringArg(args, "kind"))
+	if err != nil {
+		return "", err
+	}
+
 	emoji := stringArg(args, "emoji")
 	if emoji == "" {
 		return "", fmt.Errorf("emoji is required (+1, -1, laugh, confused, heart, hooray, rocket, eyes)")
@@ -992,7 +1002,7 @@ func (t *Tools) HandleReact(ctx context.Context, args map[string]any) (string, e
 
 	commentID := int64Arg(args, "comment_id")
 
-	if err := provider.AddReaction(ctx, repo, number, commentID, emoji); err != nil {
+	if err := provider.AddReaction(ctx, repo, kind, number, commentID, emoji); err != nil {
 		return "", err
 	}
 
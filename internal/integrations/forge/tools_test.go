@@ -108,8 +108,8 @@ func (m *mockProvider) ListIssues(_ context.Context, repo string, opts *ListOpti
 	return m.listIssuesResult, m.listIssuesErr
 }
 
-func (m *mockProvider) AddComment(_ context.Context, repo string, number int, body string) (*Comment, error) {
-	m.record("AddComment", repo, number, body)
+func (m *mockProvider) AddComment(_ context.Context, repo string, kind ItemKind, number int, body string) (*Comment, error) {
+	m.record("AddComment", repo, kind, number, body)
 	return m.addCommentResult, m.addCommentErr
 }
 
@@ -163,8 +163,8 @@ func (m *mockProvider) MergePR(_ context.Context, repo string, number int, opts
 	return m.mergePRResult, m.mergePRErr
 }
 
-func (m *mockProvider) AddReaction(_ context.Context, repo string, number int, commentID int64, emoji string) error {
-	m.record("AddReaction", repo, number, commentID, emoji)
+func (m *mockProvider) AddReaction(_ context.Context, repo string, kind ItemKind, number int, commentID int64, emoji string) error {
+	m.record("AddReaction", repo, kind, number, commentID, emoji)
 	return m.addReactionErr
 }
 
@@ -838,6 +838,7 @@ func TestHandleIssueComment(t *testing.T) {
 		args := map[string]any{
 			"repo":   "repo",
 			"number": float64(1),
+			"kind":   "pr",
 			"body":   "LGTM",
 		}
 		got, err := tools.HandleIssueComment(context.Background(), args)
@@ -853,7 +854,7 @@ func TestHandleIssueComment(t *testing.T) {
 		mp := &mockProvider{name: "test"}
 		tools := newTestTools(mp, "owner")
 
-		args := map[string]any{"repo": "repo", "number": float64(1)}
+		args := map[string]any{"repo": "repo", "number": float64(1), "kind": "issue"}
 		_, err := tools.HandleIssueComment(context.Background(), args)
 		if err == nil {
 			t.Fatal("expected error for missing body")
@@ -862,6 +863,20 @@ func TestHandleIssueComment(t *testing.T) {
 			t.Errorf("error = %q, want 'body is required'", err.Error())
 		}
 	})
+
+	t.Run("missing_kind", func(t *testing.T) {
+		mp := &mockProvider{name: "test"}
+		tools := newTestTools(mp, "owner")
+
+		args := map[string]any{"repo": "repo", "number": float64(1), "body": "LGTM"}
+		_, err := tools.HandleIssueComment(context.Background(), args)
+		if err == nil || !strings.Contains(err.Error(), "kind is required") {
+			t.Fatalf("error = %v, want 'kind is required'", err)
+		}
+		if len(mp.calls) != 0 {
+			t.Errorf("calls = %v, want none without a kind", mp.calls)
+		}
+	})
 }
 
 // --- HandleReact tests ---
@@ -874,6 +889,7 @@ func TestHandleReact(t *testing.T) {
 		args := map[string]any{
 			"repo":   "repo",
 			"number": float64(5),
+			"kind":   "issue",
 			"emoji":  "+1",
 		}
 		got, err := tools.HandleReact(context.Background(), args)
@@ -898,6 +914,7 @@ func TestHandleReact(t *testing.T) {
 		args := map[string]any{
 			"repo":       "repo",
 			"number":     float64(5),
+			"kind":       "issue",
 			"emoji":      "heart",
 			"comment_id": float64(123),
 		}
@@ -917,7 +934,7 @@ func TestHandleReact(t *testing.T) {
 		mp := &mockProvider{name: "test"}
 		tools := newTestTools(mp, "owner")
 
-		args := map[string]any{"repo": "repo", "number": float64(5)}
+		args := map[string]any{"repo": "repo", "number": float64(5), "kind": "issue"}
 		_, err := tools.HandleReact(context.Background(), args)
 		if err == nil {
 			t.Fatal("expected error for missing emoji")
@@ -1384,6 +1401,20 @@ func TestHandlePRReview(t *testing.T) {
 			t.Errorf("error = %q, want 'body is required'", err.Error())
 		}
 	})
+
+	t.Run("missing_kind", func(t *testing.T) {
+		mp := &mockProvider{name: "test"}
+		tools := newTestTools(mp, "owner")
+
+		args := map[string]any{"repo": "repo", "number": float64(1), "body": "LGTM"}
+		_, err := tools.HandleIssueComment(context.Background(), args)
+		if err == nil || !strings.Contains(err.Error(), "kind is required") {
+			t.Fatalf("error = %v, want 'kind is required'", err)
+		}
+		if len(mp.calls) != 0 {
+			t.Errorf("calls = %v, want none without a kind", mp.calls)
+		}
+	})
 }
 
 // --- HandlePRReviewComment tests ---
@@ -1,6 +1,10 @@
 package forge
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // SearchKind identifies the type of forge search to perform.
 type SearchKind string
@@ -14,6 +18,34 @@ const (
 	SearchCommits SearchKind = "commits"
 )
 
+// ItemKind says whether a number names an issue or a pull request.
+// Forges that number the two separately (GitLab's #N issues and !N
+// merge requests) need it to address the right object.
+type ItemKind string
+
+const (
+	// ItemIssue is an issue.
+	ItemIssue ItemKind = "issue"
+	// ItemPR is a pull request (a GitLab merge request).
+	ItemPR ItemKind = "pr"
+)
+
+// ParseItemKind reads a model-supplied item kind. It accepts "issue"
+// and "pr", plus "pull_request", "merge_request", and "mr" for pull
+// requests.
+func ParseItemKind(raw string) (ItemKind, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "issue":
+		return ItemIssue, nil
+	case "pr", "pull_request", "merge_request", "mr":
+		return ItemPR, nil
+	case "":
+		return "", fmt.Errorf("kind is required: \"issue\" or \"pr\"")
+	default:
+		return "", fmt.Errorf("unknown kind %q: want \"issue\" or \"pr\"", raw)
+	}
+}
+
 // Repository describes forge repository metadata relevant to subscriptions.
 type Repository struct {
 	// FullName is the canonical owner/repo name.
@@ -0,0 +1,101 @@
+package homeassistant
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UnknownAreaError is returned when an area reference matches no area
+// ID, name, or alias. Known lists the configured area names so the
+// caller can correct the reference instead of guessing again.
+type UnknownAreaError struct {
+	Area  string
+	Known []string
+}
+
+func (e *UnknownAreaError) Error() string {
+	return fmt.Sprintf("unknown area %q", e.Area)
+}
+
+// ResolveArea finds the area an operator-facing reference names. An
+// exact area ID wins; otherwise the reference matches an area name or
+// alias case-insensitively, so "kitchen", "Kitchen", and a configured
+// alias like "cook room" all resolve. Served from the registry cache.
+func (c *Client) ResolveArea(ctx context.Context, area string) (*Area, error) {
+	needle := strings.TrimSpace(area)
+	if needle == "" {
+		return nil, fmt.Errorf("area is required")
+	}
+	areas, err := c.GetAreas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get areas: %w", err)
+	}
+	for i := range areas {
+		if areas[i].AreaID == needle {
+			return &areas[i], nil
+		}
+	}
+	for i := range areas {
+		if strings.EqualFold(areas[i].Name, needle) {
+			return &areas[i], nil
+		}
+		for _, alias := range areas[i].Aliases {
+			if strings.EqualFold(alias, needle) {
+				return &areas[i], nil
+			}
+		}
+	}
+
+	known := make([]string, 0, len(areas))
+	for _, a := range areas {
+		known = append(known, a.Name)
+	}
+	sort.Strings(known)
+	return nil, &UnknownAreaError{Area: needle, Known: known}
+}
+
+// EntitiesInArea returns the sorted entity IDs assigned to an area,
+// resolved with [Client.ResolveArea]. Membership follows Home
+// Assistant's own rule: an entity's area assignment wins, and an
+// entity without one inherits its device's area. Disabled and hidden
+// entities are skipped, matching what an area target in a service
+// call would act on. Backed by the registry cache, which is dropped on
+// registry-updated events and WebSocket reconnects.
+func (c *Client) EntitiesInArea(ctx context.Context, area string) ([]string, error) {
+	resolved, err := c.ResolveArea(ctx, area)
+	if err != nil {
+		return nil, err
+	}
+	entities, err := c.GetEntityRegistry(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get entity registry: %w", err)
+	}
+
+	// The device registry is WebSocket-only; without it, entities
+	// resolve by their own area assignment alone.
+	devicesByID := make(map[string]*DeviceRegistryEntry)
+	if c.HasWSClient() {
+		devices, err := c.GetDeviceRegistry(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get device registry: %w", err)
+		}
+		for i := range devices {
+			devicesByID[devices[i].ID] = &devices[i]
+		}
+	}
+
+	var ids []string
+	for i := range entities {
+		entry := &entities[i]
+		if entry.IsDisabled() || entry.HiddenBy != "" {
+			continue
+		}
+		if entityAreaID(entry, devicesByID[entry.DeviceID]) == resolved.AreaID {
+			ids = append(ids, entry.EntityID)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
@@ -0,0 +1,57 @@
+package homeassistant
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+// TestWSClient_RegistryChangeHooks covers both invalidation triggers:
+// a registry-updated event runs the hooks (and is not forwarded to
+// Events), and so does every successful connect.
+func TestWSClient_RegistryChangeHooks(t *testing.T) {
+	f := newFakeHA()
+	srv := f.start(t)
+	ws := newFastWS(t, srv.URL)
+
+	var calls atomic.Int32
+	ws.OnRegistryChange(func() { calls.Add(1) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := ws.SubscribeRegistryUpdates(ctx); err != nil {
+		t.Fatalf("SubscribeRegistryUpdates: %v", err)
+	}
+	ws.Start(ctx)
+	for range RegistryUpdateEvents {
+		select {
+		case <-f.subscribed:
+		case <-ctx.Done():
+		}
+	}
+	waitUntil(t, func() bool { return calls.Load() == 1 }, "connect should run the registry hooks")
+
+	f.pushEvent("area_registry_updated")
+	waitUntil(t, func() bool { return calls.Load() == 2 }, "registry event should run the registry hooks")
+	select {
+	case ev := <-ws.Events():
+		t.Fatalf("registry event forwarded to Events: %+v", ev)
+	default:
+	}
+
+	f.dropCurrent()
+	waitUntil(t, func() bool { return calls.Load() == 3 }, "reconnect should run the registry hooks")
+}
+
+func (f *fakeHA) pushEvent(eventType string) {
+	f.mu.Lock()
+	conn := f.cur
+	f.mu.Unlock()
+	if conn == nil {
+		return
+	}
+	_ = f.write(conn, map[string]any{
+		"type":  "event",
+		"event": map[string]any{"event_type": eventType, "data": map[string]any{}},
+	})
+}
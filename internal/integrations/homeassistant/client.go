@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -49,9 +50,15 @@ func (c *Client) SetWatcher(w readyChecker) {
 }
 
 // UseWSClient sets the shared Home Assistant WebSocket client used for
-// registry/config APIs that are only exposed over WebSocket.
+// registry/config APIs that are only exposed over WebSocket. The
+// client's registry cache is dropped whenever ws reports a registry
+// change or reconnects; pair with [WSClient.SubscribeRegistryUpdates]
+// so edits made in HA are seen without waiting out the TTL.
 func (c *Client) UseWSClient(ws *WSClient) {
 	c.ws = ws
+	if ws != nil {
+		ws.OnRegistryChange(c.InvalidateRegistryCache)
+	}
 }
 
 // HasWSClient reports whether a WebSocket client is available for
@@ -314,22 +321,77 @@ func normalizeWeatherForecastType(value string) (string, error) {
 // CallService calls a Home Assistant service.
 func (c *Client) CallService(ctx context.Context, domain, service string, data map[string]any) error {
 	path := fmt.Sprintf("/api/services/%s/%s", domain, service)
-	return c.post(ctx, path, data, nil)
+	if err := c.post(ctx, path, data, nil); err != nil {
+		return serviceResponseError(domain, service, err)
+	}
+	return nil
 }
 
-// CallServiceWithResponse invokes a service and returns the states HA
+// CallServiceChangedStates invokes a service and returns the states HA
 // reports as changed by the call. For target-addressed calls (area,
 // floor, label, device) this is the caller's only direct evidence of
 // fan-out — which entities the call actually touched.
-func (c *Client) CallServiceWithResponse(ctx context.Context, domain, service string, data map[string]any) ([]State, error) {
+func (c *Client) CallServiceChangedStates(ctx context.Context, domain, service string, data map[string]any) ([]State, error) {
 	var changed []State
 	path := fmt.Sprintf("/api/services/%s/%s", domain, service)
 	if err := c.post(ctx, path, data, &changed); err != nil {
-		return nil, err
+		return nil, serviceResponseError(domain, service, err)
 	}
 	return changed, nil
 }
 
+// ErrNoServiceResponse is returned by [Client.CallServiceWithResponse]
+// when the service does not return response data. Such services only
+// act (turn_on, notify, ...); they must be called with
+// [Client.CallService] instead.
+var ErrNoServiceResponse = errors.New("service does not return response data")
+
+// ErrServiceResponseRequired is returned by [Client.CallService] and
+// [Client.CallServiceChangedStates] when the service only runs as a
+// data query (weather.get_forecasts, calendar.get_events, ...) and
+// must be called with [Client.CallServiceWithResponse].
+var ErrServiceResponseRequired = errors.New("service returns response data and must be called for its response")
+
+// CallServiceWithResponse invokes a service through the
+// ?return_response endpoint and returns the raw service_response
+// object — the structured data query services such as
+// weather.get_forecasts and calendar.get_events produce, typically
+// keyed by entity ID. A service that does not support response data
+// fails with [ErrNoServiceResponse] rather than an empty result, so a
+// caller never reads "the service returned nothing" as "there is
+// nothing".
+func (c *Client) CallServiceWithResponse(ctx context.Context, domain, service string, data map[string]any) (json.RawMessage, error) {
+	var response struct {
+		ServiceResponse json.RawMessage `json:"service_response"`
+	}
+	path := fmt.Sprintf("/api/services/%s/%s?return_response", domain, service)
+	if err := c.post(ctx, path, data, &response); err != nil {
+		return nil, serviceResponseError(domain, service, err)
+	}
+	if len(response.ServiceResponse) == 0 || string(response.ServiceResponse) == "null" {
+		return nil, fmt.Errorf("%s.%s: %w", domain, service, ErrNoServiceResponse)
+	}
+	return response.ServiceResponse, nil
+}
+
+// serviceResponseError maps HA's 400 replies about response support
+// onto [ErrNoServiceResponse] and [ErrServiceResponseRequired]. Other
+// errors pass through unchanged.
+func serviceResponseError(domain, service string, err error) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		return err
+	}
+	switch {
+	case strings.Contains(apiErr.Body, "does not support responses"):
+		return fmt.Errorf("%s.%s: %w", domain, service, ErrNoServiceResponse)
+	case strings.Contains(apiErr.Body, "requires responses"):
+		return fmt.Errorf("%s.%s: %w", domain, service, ErrServiceResponseRequired)
+	default:
+		return err
+	}
+}
+
 // Area represents a Home Assistant area.
 type Area struct {
 	AreaID              string   `json:"area_id"`
@@ -0,0 +1,82 @@
+package homeassistant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_CallServiceWithResponse(t *testing.T) {
+	var capturedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedPath = r.URL.RequestURI()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"changed_states":[],"service_response":{"calendar.family":{"events":[]}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", nil)
+	resp, err := client.CallServiceWithResponse(context.Background(), "calendar", "get_events", map[string]any{"entity_id": "calendar.family"})
+	if err != nil {
+		t.Fatalf("CallServiceWithResponse: %v", err)
+	}
+	if capturedPath != "/api/services/calendar/get_events?return_response" {
+		t.Errorf("path = %q", capturedPath)
+	}
+	// An empty agenda is real data, not a missing response.
+	if string(resp) != `{"calendar.family":{"events":[]}}` {
+		t.Errorf("response = %s", resp)
+	}
+}
+
+func TestClient_CallServiceWithResponse_NoResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{
+			name:   "service without response support",
+			status: http.StatusBadRequest,
+			body:   `{"message":"Service does not support responses. Remove return_response from request."}`,
+			want:   ErrNoServiceResponse,
+		},
+		{
+			name:   "null response",
+			status: http.StatusOK,
+			body:   `{"changed_states":[],"service_response":null}`,
+			want:   ErrNoServiceResponse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "token", nil)
+			_, err := client.CallServiceWithResponse(context.Background(), "light", "turn_on", nil)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_CallService_ResponseRequired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"Service call requires responses but caller did not ask for responses. Add ?return_response to query parameters."}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", nil)
+	if err := client.CallService(context.Background(), "weather", "get_forecasts", nil); !errors.Is(err, ErrServiceResponseRequired) {
+		t.Errorf("error = %v, want ErrServiceResponseRequired", err)
+	}
+}
@@ -0,0 +1,142 @@
+package homeassistant
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// InstanceSeparator joins an instance name to an entity ID in a
+// qualified entity ID such as "cabin:light.kitchen". HA entity IDs
+// never contain a colon, so the split is unambiguous.
+const InstanceSeparator = ":"
+
+// QualifyEntityID prefixes entityID with its instance name. An empty
+// instance returns entityID unchanged.
+func QualifyEntityID(instance, entityID string) string {
+	if instance == "" {
+		return entityID
+	}
+	return instance + InstanceSeparator + entityID
+}
+
+// SplitEntityID separates a possibly qualified entity ID into its
+// instance name and bare entity ID. An unqualified ID returns an empty
+// instance.
+func SplitEntityID(id string) (instance, entityID string) {
+	if i := strings.Index(id, InstanceSeparator); i > 0 {
+		return id[:i], id[i+len(InstanceSeparator):]
+	}
+	return "", id
+}
+
+// Instances is the set of named Home Assistant connections for a
+// federated household (e.g., "home" and "cabin"). The primary
+// instance keeps bare entity IDs so single-instance deployments see
+// no change; every other instance's entities are addressed with
+// qualified IDs like "cabin:light.kitchen". A qualified ID naming the
+// primary instance also resolves to it.
+//
+// Instances is built once at startup and is read-only afterwards.
+type Instances struct {
+	primary string
+	clients map[string]*Client
+}
+
+// NewInstances creates an instance set whose primary connection is
+// client under the given name.
+func NewInstances(primary string, client *Client) *Instances {
+	return &Instances{
+		primary: primary,
+		clients: map[string]*Client{primary: client},
+	}
+}
+
+// Add registers an additional named instance. Names must be unique
+// and must not contain [InstanceSeparator].
+func (i *Instances) Add(name string, client *Client) error {
+	if name == "" || strings.Contains(name, InstanceSeparator) {
+		return fmt.Errorf("invalid home assistant instance name %q", name)
+	}
+	if _, exists := i.clients[name]; exists {
+		return fmt.Errorf("duplicate home assistant instance %q", name)
+	}
+	if client == nil {
+		return fmt.Errorf("home assistant instance %q has no client", name)
+	}
+	i.clients[name] = client
+	return nil
+}
+
+// Primary returns the name of the primary instance.
+func (i *Instances) Primary() string {
+	return i.primary
+}
+
+// Names returns every instance name, primary first and the rest
+// sorted.
+func (i *Instances) Names() []string {
+	names := make([]string, 0, len(i.clients))
+	for name := range i.clients {
+		if name != i.primary {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{i.primary}, names...)
+}
+
+// Client returns the client for the named instance. An empty name
+// selects the primary.
+func (i *Instances) Client(name string) (*Client, error) {
+	if name == "" {
+		name = i.primary
+	}
+	client, ok := i.clients[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown home assistant instance %q (known: %s)", name, strings.Join(i.Names(), ", "))
+	}
+	return client, nil
+}
+
+// Resolve maps a possibly qualified entity ID to the client that owns
+// it, the canonical instance name, and the bare entity ID to send to
+// that instance.
+func (i *Instances) Resolve(entityID string) (client *Client, instance, bare string, err error) {
+	instance, bare = SplitEntityID(entityID)
+	if instance == "" {
+		instance = i.primary
+	}
+	client, err = i.Client(instance)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return client, instance, bare, nil
+}
+
+// Qualify renders entityID as seen from outside its instance: bare for
+// the primary, qualified for every other instance.
+func (i *Instances) Qualify(instance, entityID string) string {
+	if instance == "" || instance == i.primary {
+		return entityID
+	}
+	return QualifyEntityID(instance, entityID)
+}
+
+// GetState fetches the state of a possibly qualified entity ID from
+// the instance that owns it. The returned state carries the
+// instance-qualified ID (bare for the primary), matching the keys the
+// person tracker and state window use for cross-instance entities.
+func (i *Instances) GetState(ctx context.Context, entityID string) (*State, error) {
+	client, instance, bare, err := i.Resolve(entityID)
+	if err != nil {
+		return nil, err
+	}
+	state, err := client.GetState(ctx, bare)
+	if err != nil {
+		return nil, err
+	}
+	state.EntityID = i.Qualify(instance, state.EntityID)
+	return state, nil
+}
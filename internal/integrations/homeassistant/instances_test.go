@@ -0,0 +1,113 @@
+package homeassistant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/agentctx"
+)
+
+func TestSplitEntityID(t *testing.T) {
+	tests := []struct {
+		id, instance, entityID string
+	}{
+		{"light.kitchen", "", "light.kitchen"},
+		{"cabin:light.kitchen", "cabin", "light.kitchen"},
+		{":light.kitchen", "", ":light.kitchen"},
+	}
+	for _, tt := range tests {
+		instance, entityID := SplitEntityID(tt.id)
+		if instance != tt.instance || entityID != tt.entityID {
+			t.Errorf("SplitEntityID(%q) = (%q, %q), want (%q, %q)", tt.id, instance, entityID, tt.instance, tt.entityID)
+		}
+		if tt.instance != "" && QualifyEntityID(instance, entityID) != tt.id {
+			t.Errorf("QualifyEntityID(%q, %q) does not round-trip to %q", instance, entityID, tt.id)
+		}
+	}
+}
+
+// stateServer serves GET /api/states/<id> for a single entity.
+func stateServer(t *testing.T, entityID, state string) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimPrefix(r.URL.Path, "/api/states/") != entityID {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"entity_id":"` + entityID + `","state":"` + state + `","attributes":{}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL, "test-token", nil)
+}
+
+func TestInstances_ResolveAndGetState(t *testing.T) {
+	instances := NewInstances("home", stateServer(t, "person.alice", "home"))
+	if err := instances.Add("cabin", stateServer(t, "person.alice", "not_home")); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := instances.Add("cabin", stateServer(t, "person.bob", "home")); err == nil {
+		t.Error("Add(duplicate) = nil, want error")
+	}
+
+	ctx := context.Background()
+	tests := []struct {
+		id, wantID, wantState string
+	}{
+		{"person.alice", "person.alice", "home"},
+		{"home:person.alice", "person.alice", "home"},
+		{"cabin:person.alice", "cabin:person.alice", "not_home"},
+	}
+	for _, tt := range tests {
+		st, err := instances.GetState(ctx, tt.id)
+		if err != nil {
+			t.Fatalf("GetState(%q): %v", tt.id, err)
+		}
+		if st.EntityID != tt.wantID || st.State != tt.wantState {
+			t.Errorf("GetState(%q) = %s/%s, want %s/%s", tt.id, st.EntityID, st.State, tt.wantID, tt.wantState)
+		}
+	}
+
+	if _, _, _, err := instances.Resolve("barn:person.alice"); err == nil {
+		t.Error("Resolve(unknown instance) = nil error")
+	}
+	if got := instances.Names(); len(got) != 2 || got[0] != "home" || got[1] != "cabin" {
+		t.Errorf("Names() = %v, want [home cabin]", got)
+	}
+}
+
+// Events from a secondary instance's watcher reach the shared state
+// window under qualified IDs, alongside the primary's bare IDs. An
+// unqualified glob watches the entity on every instance.
+func TestStateWatcher_InstanceQualifiesCrossInstanceState(t *testing.T) {
+	window := NewStateWindowProvider(10, 30*time.Minute, nil, nil)
+	filter := NewEntityFilter([]string{"binary_sensor.*"}, nil)
+
+	home := NewStateWatcher(nil, filter, nil, window.HandleStateChange, nil)
+	cabin := NewStateWatcher(nil, filter, nil, window.HandleStateChange, nil)
+	cabin.SetInstance("cabin")
+
+	if !home.HandleEvent(makeStateEvent(t, "binary_sensor.front_door", "off", "on")) {
+		t.Fatal("primary event was filtered")
+	}
+	if !cabin.HandleEvent(makeStateEvent(t, "binary_sensor.front_door", "on", "off")) {
+		t.Fatal("cabin event was filtered")
+	}
+
+	got, err := window.TagContext(context.Background(), agentctx.ContextRequest{})
+	if err != nil {
+		t.Fatalf("TagContext: %v", err)
+	}
+	for _, want := range []string{
+		`"entity":"binary_sensor.front_door","from":"off","to":"on"`,
+		`"entity":"cabin:binary_sensor.front_door","from":"on","to":"off"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("state window missing %s:\n%s", want, got)
+		}
+	}
+}
@@ -40,6 +40,18 @@ type ServiceDescription struct {
 	// Target is the raw target-selector block when the service is
 	// target-addressable; nil when it takes no target.
 	Target map[string]any `json:"target,omitempty"`
+
+	// Response is non-nil when the service can return response data
+	// through [Client.CallServiceWithResponse].
+	Response *ServiceResponseSupport `json:"response,omitempty"`
+}
+
+// ServiceResponseSupport describes how a service returns response
+// data. Optional services (script.turn_on, ...) act and may also
+// return data; the rest (weather.get_forecasts, calendar.get_events)
+// only run as a query for their response.
+type ServiceResponseSupport struct {
+	Optional bool `json:"optional"`
 }
 
 // ServiceDomain groups one domain's callable services, as returned by
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 )
@@ -48,6 +49,11 @@ func NewEntityFilter(globs []string, logger *slog.Logger) *EntityFilter {
 
 // Match reports whether the entity ID matches at least one pattern.
 // If no patterns are configured, Match always returns true.
+//
+// A pattern without an instance qualifier applies to every Home
+// Assistant instance, so "light.*" matches both "light.kitchen" and
+// "cabin:light.kitchen"; a qualified pattern such as "cabin:light.*"
+// matches only that instance's entities.
 func (f *EntityFilter) Match(entityID string) bool {
 	if f.matchNone {
 		return false
@@ -55,8 +61,13 @@ func (f *EntityFilter) Match(entityID string) bool {
 	if len(f.patterns) == 0 {
 		return true
 	}
+	instance, bare := SplitEntityID(entityID)
 	for _, pat := range f.patterns {
-		matched, err := MatchEntityGlob(pat, entityID)
+		target := entityID
+		if instance != "" && !strings.Contains(pat, InstanceSeparator) {
+			target = bare
+		}
+		matched, err := MatchEntityGlob(pat, target)
 		if err != nil {
 			f.logger.Debug("glob match error", "pattern", pat, "entity_id", entityID, "error", err)
 			continue
@@ -149,6 +160,7 @@ func (r *EntityRateLimiter) Cleanup() {
 // and dispatches matching events to a handler.
 type StateWatcher struct {
 	events   <-chan Event
+	instance string
 	filterMu sync.RWMutex
 	filter   *EntityFilter
 	limiter  *EntityRateLimiter
@@ -169,6 +181,15 @@ func (w *StateWatcher) SetFilter(filter *EntityFilter) {
 	w.filterMu.Unlock()
 }
 
+// SetInstance qualifies every entity ID this watcher sees with the
+// named instance (see [QualifyEntityID]) before filtering, rate
+// limiting, and dispatch, so a secondary instance's events reach the
+// shared handler as "cabin:light.kitchen". The primary instance's
+// watcher leaves it unset. Call before Run.
+func (w *StateWatcher) SetInstance(name string) {
+	w.instance = name
+}
+
 // NewStateWatcher creates a state watcher that consumes events from the
 // given channel. The filter and limiter control which events reach the
 // handler. A nil filter or limiter disables that stage.
@@ -266,15 +287,17 @@ func (w *StateWatcher) handleEvent(ev Event) bool {
 		return false
 	}
 
+	entityID := QualifyEntityID(w.instance, data.EntityID)
+
 	w.filterMu.RLock()
 	filter := w.filter
 	w.filterMu.RUnlock()
-	if !filter.Match(data.EntityID) {
+	if !filter.Match(entityID) {
 		return false
 	}
 
-	if !w.limiter.Allow(data.EntityID) {
-		w.logger.Debug("rate limited state change", "entity_id", data.EntityID)
+	if !w.limiter.Allow(entityID) {
+		w.logger.Debug("rate limited state change", "entity_id", entityID)
 		return false
 	}
 
@@ -288,6 +311,6 @@ func (w *StateWatcher) handleEvent(ev Event) bool {
 		deviceClass = dc
 	}
 
-	w.handler(data.EntityID, oldState, data.NewState.State, deviceClass)
+	w.handler(entityID, oldState, data.NewState.State, deviceClass)
 	return true
 }
@@ -24,6 +24,10 @@ func TestEntityFilter_Match(t *testing.T) {
 		{"multiple patterns first match", []string{"person.*", "light.*"}, "person.dan", true},
 		{"multiple patterns second match", []string{"person.*", "light.*"}, "light.kitchen", true},
 		{"multiple patterns no match", []string{"person.*", "light.*"}, "switch.garage", false},
+		{"unqualified pattern matches every instance", []string{"light.*"}, "cabin:light.kitchen", true},
+		{"qualified pattern matches its instance", []string{"cabin:light.*"}, "cabin:light.kitchen", true},
+		{"qualified pattern skips other instances", []string{"cabin:light.*"}, "barn:light.kitchen", false},
+		{"qualified pattern skips the primary", []string{"cabin:light.*"}, "light.kitchen", false},
 	}
 
 	for _, tt := range tests {
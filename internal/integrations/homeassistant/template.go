@@ -0,0 +1,109 @@
+package homeassistant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/httpkit"
+)
+
+const (
+	// templateTimeout bounds one render. Templates that iterate the
+	// whole state machine are cheap for HA, so anything slower than
+	// this is almost certainly a runaway loop.
+	templateTimeout = 10 * time.Second
+
+	// MaxTemplateOutputBytes caps a rendered template. A template that
+	// dumps every state renders megabytes; the cap turns that into an
+	// error the caller can act on instead of a truncated answer.
+	MaxTemplateOutputBytes = 32 * 1024
+)
+
+// ErrTemplateOutputTooLarge is returned by [Client.RenderTemplate]
+// when the rendered output exceeds [MaxTemplateOutputBytes].
+var ErrTemplateOutputTooLarge = fmt.Errorf("rendered template exceeds %d bytes", MaxTemplateOutputBytes)
+
+// TemplateError is a template HA rejected or failed to render. Message
+// is HA's own explanation (e.g. "UndefinedError: 'sensor' is
+// undefined"), suitable for showing to whoever wrote the template so
+// they can correct it.
+type TemplateError struct {
+	Message string
+}
+
+func (e *TemplateError) Error() string {
+	return "template error: " + e.Message
+}
+
+// RenderTemplate evaluates a Jinja template server-side through
+// /api/template and returns the rendered text. A template HA cannot
+// render fails with a [*TemplateError] carrying HA's message; output
+// larger than [MaxTemplateOutputBytes] fails with
+// [ErrTemplateOutputTooLarge]. Each render is bounded by a 10-second
+// timeout on top of ctx.
+func (c *Client) RenderTemplate(ctx context.Context, template string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, templateTimeout)
+	defer cancel()
+
+	reqBody, err := json.Marshal(map[string]string{"template": template})
+	if err != nil {
+		return "", fmt.Errorf("marshal template: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/template", bytes.NewReader(reqBody))
+	if err != nil {
+		return "", fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return "", fmt.Errorf("template render timed out after %s", templateTimeout)
+		}
+		return "", fmt.Errorf("request /api/template: %w", err)
+	}
+	defer httpkit.DrainAndClose(resp.Body, 4096)
+
+	if resp.StatusCode != http.StatusOK {
+		body := httpkit.ReadErrorBody(resp.Body, 1024)
+		if resp.StatusCode == http.StatusBadRequest {
+			return "", &TemplateError{Message: templateErrorMessage(body)}
+		}
+		return "", &APIError{StatusCode: resp.StatusCode, Body: body}
+	}
+
+	out, err := io.ReadAll(io.LimitReader(resp.Body, MaxTemplateOutputBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("read rendered template: %w", err)
+	}
+	if len(out) > MaxTemplateOutputBytes {
+		return "", ErrTemplateOutputTooLarge
+	}
+	return string(out), nil
+}
+
+// templateErrorMessage extracts HA's explanation from a 400 body
+// ({"message": "Error rendering template: ..."}), dropping the generic
+// prefix. Bodies that are not the expected JSON are returned trimmed.
+func templateErrorMessage(body string) string {
+	var parsed struct {
+		Message string `json:"message"`
+	}
+	msg := strings.TrimSpace(body)
+	if err := json.Unmarshal([]byte(body), &parsed); err == nil && parsed.Message != "" {
+		msg = parsed.Message
+	}
+	msg = strings.TrimPrefix(msg, "Error rendering template: ")
+	if msg == "" {
+		msg = "Home Assistant rejected the template"
+	}
+	return msg
+}
@@ -0,0 +1,89 @@
+package homeassistant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_RenderTemplate(t *testing.T) {
+	var captured map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/template" || r.Method != http.MethodPost {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte("71.3"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", nil)
+	out, err := client.RenderTemplate(context.Background(), "{{ 71.25 | round(1) }}")
+	if err != nil {
+		t.Fatalf("RenderTemplate: %v", err)
+	}
+	if out != "71.3" {
+		t.Errorf("output = %q, want 71.3", out)
+	}
+	if captured["template"] != "{{ 71.25 | round(1) }}" {
+		t.Errorf("request body = %v", captured)
+	}
+}
+
+func TestClient_RenderTemplate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantMsg string
+		wantErr error
+	}{
+		{
+			name:    "template error",
+			status:  http.StatusBadRequest,
+			body:    `{"message":"Error rendering template: UndefinedError: 'sensorz' is undefined"}`,
+			wantMsg: "UndefinedError: 'sensorz' is undefined",
+		},
+		{
+			name:    "non-json error body",
+			status:  http.StatusBadRequest,
+			body:    "TemplateSyntaxError: unexpected '}'",
+			wantMsg: "TemplateSyntaxError: unexpected '}'",
+		},
+		{
+			name:    "oversized output",
+			status:  http.StatusOK,
+			body:    strings.Repeat("x", MaxTemplateOutputBytes+1),
+			wantErr: ErrTemplateOutputTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := NewClient(server.URL, "token", nil).RenderTemplate(context.Background(), "{{ x }}")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			var tmplErr *TemplateError
+			if !errors.As(err, &tmplErr) {
+				t.Fatalf("error = %v, want *TemplateError", err)
+			}
+			if tmplErr.Message != tt.wantMsg {
+				t.Errorf("Message = %q, want %q", tmplErr.Message, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -64,6 +64,11 @@ type WSClient struct {
 	desired   map[string]struct{}
 	desiredMu sync.Mutex
 
+	// registryHooks run when a topology registry may have changed: on
+	// a *_registry_updated event and on every (re)connect.
+	registryHooks   []func()
+	registryHooksMu sync.Mutex
+
 	// Supervisor plumbing.
 	startOnce sync.Once
 	lost      chan struct{} // readLoop signals genuine connection loss
@@ -77,6 +82,16 @@ type WSClient struct {
 	logger *slog.Logger
 }
 
+// RegistryUpdateEvents are the events Home Assistant fires when the
+// area, device, entity, floor, or label registry changes.
+var RegistryUpdateEvents = []string{
+	"area_registry_updated",
+	"device_registry_updated",
+	"entity_registry_updated",
+	"floor_registry_updated",
+	"label_registry_updated",
+}
+
 // Event represents a Home Assistant event received via WebSocket.
 type Event struct {
 	Type      string          `json:"event_type"`
@@ -346,6 +361,10 @@ func (c *WSClient) Connect(ctx context.Context) error {
 	// Start read loop.
 	go c.readLoop(conn)
 
+	// Registry edits made while disconnected were never announced, so
+	// anything cached from the previous connection is suspect.
+	c.notifyRegistryChange()
+
 	// (Re)apply desired subscriptions on the fresh connection.
 	c.applyDesiredSubscriptions()
 
@@ -435,6 +454,47 @@ func (c *WSClient) applyDesiredSubscriptions() {
 	}
 }
 
+// SubscribeRegistryUpdates records sticky subscriptions to every
+// [RegistryUpdateEvents] type. The events are consumed internally to
+// run the [WSClient.OnRegistryChange] hooks and are not forwarded to
+// [WSClient.Events].
+func (c *WSClient) SubscribeRegistryUpdates(ctx context.Context) error {
+	for _, eventType := range RegistryUpdateEvents {
+		if err := c.Subscribe(ctx, eventType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OnRegistryChange registers fn to run whenever a topology registry
+// may have changed: on each registry-updated event and on every
+// successful (re)connect. Hooks run on the read loop, so they must be
+// quick and must not call back into the WebSocket client.
+func (c *WSClient) OnRegistryChange(fn func()) {
+	c.registryHooksMu.Lock()
+	defer c.registryHooksMu.Unlock()
+	c.registryHooks = append(c.registryHooks, fn)
+}
+
+func (c *WSClient) notifyRegistryChange() {
+	c.registryHooksMu.Lock()
+	hooks := append([]func(){}, c.registryHooks...)
+	c.registryHooksMu.Unlock()
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+func isRegistryUpdateEvent(eventType string) bool {
+	for _, t := range RegistryUpdateEvents {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
 // GetAreaRegistry retrieves the area registry.
 func (c *WSClient) GetAreaRegistry(ctx context.Context) ([]Area, error) {
 	id := c.msgID.Add(1)
@@ -571,6 +631,11 @@ func (c *WSClient) readLoop(conn *websocket.Conn) {
 		case "event":
 			// Subscribed event.
 			if msg.Event != nil {
+				if isRegistryUpdateEvent(msg.Event.Type) {
+					c.logger.Debug("HA registry updated", "event_type", msg.Event.Type)
+					c.notifyRegistryChange()
+					break
+				}
 				select {
 				case c.events <- *msg.Event:
 				default:
@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/url"
@@ -41,19 +42,26 @@ type BridgeOptions struct {
 //
 // BridgeTools returns the number of tools registered.
 func BridgeTools(ctx context.Context, client *Client, serverName string, registry *tools.Registry, opts BridgeOptions, logger *slog.Logger) (int, error) {
+	names, err := bridgeTools(ctx, client, serverName, registry, opts, logger)
+	return len(names), err
+}
+
+// bridgeTools implements [BridgeTools], returning the registry names of
+// the tools it registered.
+func bridgeTools(ctx context.Context, client *Client, serverName string, registry *tools.Registry, opts BridgeOptions, logger *slog.Logger) ([]string, error) {
 	if logger == nil {
 		logger = slog.Default()
 	}
 
 	mcpTools, err := client.ListTools(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("list tools from %s: %w", serverName, err)
+		return nil, fmt.Errorf("list tools from %s: %w", serverName, err)
 	}
 
 	includeSet := toSet(opts.Include)
 	excludeSet := toSet(opts.Exclude)
 
-	count := 0
+	var names []string
 	for _, td := range mcpTools {
 		override := opts.ToolOverrides[td.Name]
 		if override.Enabled != nil && !*override.Enabled {
@@ -79,7 +87,7 @@ func BridgeTools(ctx context.Context, client *Client, serverName string, registr
 
 		name := ToolName(serverName, td.Name)
 		registry.Register(bridgeTool(client, serverName, name, td, opts.Tags, override))
-		count++
+		names = append(names, name)
 
 		logger.Debug("bridged MCP tool",
 			"mcp_name", td.Name,
@@ -88,7 +96,7 @@ func BridgeTools(ctx context.Context, client *Client, serverName string, registr
 		)
 	}
 
-	return count, nil
+	return names, nil
 }
 
 // ToolName generates a namespaced Thane tool name from an MCP server
@@ -122,7 +130,14 @@ func bridgeTool(client *Client, serverName, name string, td ToolDefinition, defa
 		Origin:      serverName,
 		Tags:        append([]string(nil), tags...),
 		Handler: func(ctx context.Context, args map[string]any) (string, error) {
-			return client.CallTool(ctx, mcpName, args)
+			out, err := client.CallTool(ctx, mcpName, args)
+			if errors.Is(err, ErrDisconnected) {
+				return "", tools.ErrUnavailable{
+					Tool:   name,
+					Reason: fmt.Sprintf("MCP server %q is disconnected; reconnecting", serverName),
+				}
+			}
+			return out, err
 		},
 	}
 }
@@ -3,6 +3,7 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -15,6 +16,11 @@ import (
 // protocolVersion is the MCP protocol version we advertise during initialization.
 const protocolVersion = "2024-11-05"
 
+// ErrDisconnected is returned by [Client.CallTool], [Client.Ping], and
+// the resource methods while the client is reconnecting or closed, and
+// by requests that were in flight when the connection was torn down.
+var ErrDisconnected = errors.New("MCP server disconnected")
+
 // ToolDefinition is an MCP tool as returned by tools/list.
 type ToolDefinition struct {
 	Name        string         `json:"name"`
@@ -39,6 +45,34 @@ type toolsListResult struct {
 	Tools []ToolDefinition `json:"tools"`
 }
 
+// ResourceDefinition is an MCP resource as returned by resources/list.
+type ResourceDefinition struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContent is one content item in a resources/read response.
+// Exactly one of Text or Blob is set; Blob holds base64-encoded binary
+// data.
+type ResourceContent struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// resourcesListResult is the result payload of a resources/list response.
+type resourcesListResult struct {
+	Resources []ResourceDefinition `json:"resources"`
+}
+
+// readResourceResult is the result payload of a resources/read response.
+type readResourceResult struct {
+	Contents []ResourceContent `json:"contents"`
+}
+
 // serverInfo is returned in the initialize response.
 type serverInfo struct {
 	Name    string `json:"name"`
@@ -65,11 +99,20 @@ type Client struct {
 	logger    *slog.Logger
 	nextID    atomic.Int64
 
-	mu          sync.RWMutex
-	initialized bool
-	serverName  string
-	serverVer   string
-	tools       []ToolDefinition
+	mu           sync.RWMutex
+	initialized  bool
+	serverName   string
+	serverVer    string
+	tools        []ToolDefinition
+	reconnecting bool
+	closed       bool
+
+	// connCtx is cancelled when the current connection is torn down
+	// (reconnect or close) so in-flight requests return promptly
+	// instead of waiting on a dead transport.
+	connCtx    context.Context
+	cancelConn context.CancelFunc
+	inFlight   atomic.Int64 // tools/call requests awaiting a response
 }
 
 // NewClient creates an MCP client for the given server. The transport
@@ -84,6 +127,7 @@ func NewClient(name string, transport Transport, logger *slog.Logger) *Client {
 		logger:    logger.With("mcp_server", name),
 	}
 	c.nextID.Store(0)
+	c.connCtx, c.cancelConn = context.WithCancel(context.Background())
 	return c
 }
 
@@ -166,6 +210,12 @@ func (c *Client) ListTools(ctx context.Context) ([]ToolDefinition, error) {
 // is extracted from the response content blocks as a single string.
 // Non-text content blocks are described inline (e.g., "[image]").
 func (c *Client) CallTool(ctx context.Context, name string, args map[string]any) (string, error) {
+	if err := c.available(); err != nil {
+		return "", fmt.Errorf("tools/call %s: %w", name, err)
+	}
+	c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+
 	params := map[string]any{
 		"name":      name,
 		"arguments": args,
@@ -190,26 +240,140 @@ func (c *Client) CallTool(ctx context.Context, name string, args map[string]any)
 	return text, nil
 }
 
+// ListResources calls resources/list and returns the resources the
+// server offers. Unlike tools, resources are not cached: servers may
+// add and remove them at any time.
+func (c *Client) ListResources(ctx context.Context) ([]ResourceDefinition, error) {
+	if err := c.available(); err != nil {
+		return nil, fmt.Errorf("resources/list: %w", err)
+	}
+
+	resp, err := c.send(ctx, "resources/list", nil)
+	if err != nil {
+		return nil, fmt.Errorf("resources/list: %w", err)
+	}
+
+	var result resourcesListResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		return nil, fmt.Errorf("unmarshal resources/list result: %w", err)
+	}
+	return result.Resources, nil
+}
+
+// ReadResource calls resources/read for uri and returns its contents.
+// A single resource may yield several content items (e.g., the files
+// of a directory resource).
+func (c *Client) ReadResource(ctx context.Context, uri string) ([]ResourceContent, error) {
+	if err := c.available(); err != nil {
+		return nil, fmt.Errorf("resources/read %s: %w", uri, err)
+	}
+
+	resp, err := c.send(ctx, "resources/read", map[string]any{"uri": uri})
+	if err != nil {
+		return nil, fmt.Errorf("resources/read %s: %w", uri, err)
+	}
+
+	var result readResourceResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		return nil, fmt.Errorf("unmarshal resources/read result: %w", err)
+	}
+	return result.Contents, nil
+}
+
 // Ping checks whether the MCP server is responsive. Used by connwatch
-// for health monitoring.
+// for health monitoring, so a reconnecting client reports the service
+// as down.
 func (c *Client) Ping(ctx context.Context) error {
+	if err := c.available(); err != nil {
+		return err
+	}
 	_, err := c.send(ctx, "ping", nil)
 	return err
 }
 
-// Close shuts down the client and its transport.
+// Reconnect tears down the current connection and establishes a new
+// one: in-flight requests fail with [ErrDisconnected], the transport
+// is closed (a stdio subprocess is stopped and relaunched on the next
+// request, an HTTP session is dropped), and the initialize handshake
+// and tools/list are re-run. Until it succeeds, requests other than the
+// handshake fail fast with [ErrDisconnected].
+func (c *Client) Reconnect(ctx context.Context) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrDisconnected
+	}
+	c.reconnecting = true
+	c.cancelConn()
+	c.connCtx, c.cancelConn = context.WithCancel(context.Background())
+	c.initialized = false
+	c.tools = nil
+	c.mu.Unlock()
+
+	if err := c.transport.Close(); err != nil {
+		c.logger.Debug("MCP transport close during reconnect failed", "error", err)
+	}
+	if err := c.Initialize(ctx); err != nil {
+		return err
+	}
+	if _, err := c.ListTools(ctx); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.reconnecting = false
+	c.mu.Unlock()
+	return nil
+}
+
+// Close shuts down the client and its transport. In-flight requests
+// fail with [ErrDisconnected] rather than holding up shutdown.
 func (c *Client) Close() error {
 	c.logger.Info("closing MCP client")
+	c.mu.Lock()
+	c.closed = true
+	c.cancelConn()
+	c.mu.Unlock()
 	return c.transport.Close()
 }
 
+// available returns [ErrDisconnected] while the client is reconnecting
+// or closed.
+func (c *Client) available() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.reconnecting || c.closed {
+		return ErrDisconnected
+	}
+	return nil
+}
+
+// callsInFlight reports how many tools/call requests are awaiting a
+// response.
+func (c *Client) callsInFlight() int64 {
+	return c.inFlight.Load()
+}
+
 // send issues a JSON-RPC request and checks for protocol-level errors.
+// The request is abandoned with [ErrDisconnected] if the connection is
+// torn down while it is in flight.
 func (c *Client) send(ctx context.Context, method string, params any) (*Response, error) {
 	id := c.nextID.Add(1)
 	req := NewRequest(id, method, params)
 
+	c.mu.RLock()
+	connCtx := c.connCtx
+	c.mu.RUnlock()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(connCtx, cancel)
+	defer stop()
+
 	resp, err := c.transport.Send(ctx, req)
 	if err != nil {
+		if connCtx.Err() != nil {
+			return nil, ErrDisconnected
+		}
 		return nil, err
 	}
 
@@ -23,6 +23,11 @@ type HTTPConfig struct {
 	// (e.g., Authorization).
 	Headers map[string]string
 
+	// OAuth2, when set, obtains bearer tokens with the client-credentials
+	// grant and sends them as the Authorization header, overriding any
+	// static Authorization in Headers. Optional.
+	OAuth2 *OAuth2Config
+
 	// Logger is the structured logger for transport diagnostics.
 	Logger *slog.Logger
 }
@@ -34,6 +39,7 @@ type HTTPTransport struct {
 	url        string
 	headers    map[string]string
 	httpClient *http.Client
+	tokens     *tokenSource // nil without OAuth2
 	logger     *slog.Logger
 
 	mu        sync.RWMutex
@@ -52,12 +58,16 @@ func NewHTTPTransport(cfg HTTPConfig) *HTTPTransport {
 		httpkit.WithLogger(logger),
 	)
 
-	return &HTTPTransport{
+	t := &HTTPTransport{
 		url:        cfg.URL,
 		headers:    cfg.Headers,
 		httpClient: client,
 		logger:     logger,
 	}
+	if cfg.OAuth2 != nil {
+		t.tokens = newTokenSource(*cfg.OAuth2, client)
+	}
+	return t
 }
 
 // Send sends a JSON-RPC request via HTTP POST and returns the response.
@@ -67,39 +77,12 @@ func (t *HTTPTransport) Send(ctx context.Context, req *Request) (*Response, erro
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("create HTTP request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/json")
-
-	// Apply configured headers (auth, etc.).
-	for k, v := range t.headers {
-		httpReq.Header.Set(k, v)
-	}
-
-	// Include session ID if we have one from a previous response.
-	t.mu.RLock()
-	if t.sessionID != "" {
-		httpReq.Header.Set("Mcp-Session", t.sessionID)
-	}
-	t.mu.RUnlock()
-
-	httpResp, err := t.httpClient.Do(httpReq)
+	httpResp, err := t.post(ctx, body, true)
 	if err != nil {
 		return nil, fmt.Errorf("HTTP request to %s: %w", t.url, err)
 	}
 	defer httpkit.DrainAndClose(httpResp.Body, 1<<20)
 
-	// Capture session ID from response.
-	if sid := httpResp.Header.Get("Mcp-Session"); sid != "" {
-		t.mu.Lock()
-		t.sessionID = sid
-		t.mu.Unlock()
-	}
-
 	if httpResp.StatusCode != http.StatusOK {
 		errBody := httpkit.ReadErrorBody(httpResp.Body, 1<<20)
 		return nil, fmt.Errorf("MCP server returned %d: %s", httpResp.StatusCode, errBody)
@@ -126,17 +109,70 @@ func (t *HTTPTransport) Notify(ctx context.Context, notif *Notification) error {
 		return fmt.Errorf("marshal notification: %w", err)
 	}
 
+	httpResp, err := t.post(ctx, body, false)
+	if err != nil {
+		return fmt.Errorf("HTTP notification to %s: %w", t.url, err)
+	}
+	defer httpkit.DrainAndClose(httpResp.Body, 1<<20)
+
+	// Accept 200 and 202 (accepted) for notifications.
+	if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusAccepted {
+		errBody := httpkit.ReadErrorBody(httpResp.Body, 1<<20)
+		return fmt.Errorf("MCP server returned %d for notification: %s", httpResp.StatusCode, errBody)
+	}
+
+	return nil
+}
+
+// post POSTs body to the server with the configured headers, session
+// ID, and bearer token, capturing any session ID the server returns.
+// With OAuth2 configured, a 401 invalidates the token and the request
+// is retried once with a fresh one; a token that cannot be obtained is
+// returned as an error.
+func (t *HTTPTransport) post(ctx context.Context, body []byte, wantResponse bool) (*http.Response, error) {
+	httpResp, token, err := t.postOnce(ctx, body, wantResponse)
+	if err != nil {
+		return nil, err
+	}
+	if httpResp.StatusCode == http.StatusUnauthorized && t.tokens != nil {
+		httpkit.DrainAndClose(httpResp.Body, 1<<20)
+		t.logger.Debug("MCP server rejected bearer token; refreshing", "url", t.url)
+		t.tokens.Invalidate(token)
+		httpResp, _, err = t.postOnce(ctx, body, wantResponse)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return httpResp, nil
+}
+
+// postOnce sends a single POST, returning the bearer token it used.
+func (t *HTTPTransport) postOnce(ctx context.Context, body []byte, wantResponse bool) (*http.Response, string, error) {
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("create HTTP request: %w", err)
+		return nil, "", fmt.Errorf("create HTTP request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	if wantResponse {
+		httpReq.Header.Set("Accept", "application/json")
+	}
 
+	// Apply configured headers (auth, etc.).
 	for k, v := range t.headers {
 		httpReq.Header.Set(k, v)
 	}
 
+	var token string
+	if t.tokens != nil {
+		token, err = t.tokens.Token(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	// Include session ID if we have one from a previous response.
 	t.mu.RLock()
 	if t.sessionID != "" {
 		httpReq.Header.Set("Mcp-Session", t.sessionID)
@@ -145,21 +181,25 @@ func (t *HTTPTransport) Notify(ctx context.Context, notif *Notification) error {
 
 	httpResp, err := t.httpClient.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("HTTP notification to %s: %w", t.url, err)
+		return nil, "", err
 	}
-	defer httpkit.DrainAndClose(httpResp.Body, 1<<20)
 
-	// Accept 200 and 202 (accepted) for notifications.
-	if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusAccepted {
-		errBody := httpkit.ReadErrorBody(httpResp.Body, 1<<20)
-		return fmt.Errorf("MCP server returned %d for notification: %s", httpResp.StatusCode, errBody)
+	// Capture session ID from response.
+	if sid := httpResp.Header.Get("Mcp-Session"); sid != "" {
+		t.mu.Lock()
+		t.sessionID = sid
+		t.mu.Unlock()
 	}
 
-	return nil
+	return httpResp, token, nil
 }
 
-// Close is a no-op for HTTP transports. The underlying HTTP client
-// manages its own connection pool via httpkit.
+// Close drops the MCP session, so the next request (normally a fresh
+// initialize after a reconnect) starts a new one. The underlying HTTP
+// client manages its own connection pool via httpkit.
 func (t *HTTPTransport) Close() error {
+	t.mu.Lock()
+	t.sessionID = ""
+	t.mu.Unlock()
 	return nil
 }
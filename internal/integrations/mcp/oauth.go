@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/httpkit"
+)
+
+// tokenRefreshMargin is how long before expiry a cached access token is
+// refreshed, so a token never expires mid-request.
+const tokenRefreshMargin = 60 * time.Second
+
+// OAuth2Config configures the OAuth2 client-credentials grant an
+// [HTTPTransport] uses to obtain short-lived bearer tokens.
+type OAuth2Config struct {
+	// TokenURL is the authorization server's token endpoint.
+	TokenURL string
+
+	// ClientID and ClientSecret authenticate the client to the token
+	// endpoint (HTTP Basic).
+	ClientID     string
+	ClientSecret string
+
+	// Scopes are requested with each token. Optional.
+	Scopes []string
+}
+
+// tokenSource fetches access tokens with the client-credentials grant
+// and caches each until shortly before it expires.
+type tokenSource struct {
+	cfg        OAuth2Config
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time // zero when the server gave no lifetime
+}
+
+// tokenResponse is the token endpoint's success or error payload.
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func newTokenSource(cfg OAuth2Config, httpClient *http.Client) *tokenSource {
+	return &tokenSource{cfg: cfg, httpClient: httpClient, now: time.Now}
+}
+
+// Token returns a valid access token, fetching a new one when none is
+// cached or the cached one is about to expire.
+func (ts *tokenSource) Token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token != "" && (ts.expiry.IsZero() || ts.now().Before(ts.expiry.Add(-tokenRefreshMargin))) {
+		return ts.token, nil
+	}
+
+	token, lifetime, err := ts.fetch(ctx)
+	if err != nil {
+		return "", fmt.Errorf("oauth2 token: %w", err)
+	}
+	ts.token = token
+	ts.expiry = time.Time{}
+	if lifetime > 0 {
+		ts.expiry = ts.now().Add(lifetime)
+	}
+	return token, nil
+}
+
+// Invalidate drops token from the cache if it is still the cached
+// token, forcing the next [tokenSource.Token] call to fetch a new one.
+// Used when the resource server rejects a token before its expiry.
+func (ts *tokenSource) Invalidate(token string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.token == token {
+		ts.token = ""
+		ts.expiry = time.Time{}
+	}
+}
+
+// fetch requests a token from the token endpoint.
+func (ts *tokenSource) fetch(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(ts.cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(ts.cfg.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ts.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(ts.cfg.ClientID), url.QueryEscape(ts.cfg.ClientSecret))
+
+	resp, err := ts.httpClient.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("token request to %s: %w", ts.cfg.TokenURL, err)
+	}
+	defer httpkit.DrainAndClose(resp.Body, 1<<20)
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", 0, fmt.Errorf("read token response: %w", err)
+	}
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return "", 0, fmt.Errorf("token endpoint returned %d: unmarshal response: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || tr.Error != "" {
+		if tr.ErrorDescription != "" {
+			return "", 0, fmt.Errorf("token endpoint returned %d: %s: %s", resp.StatusCode, tr.Error, tr.ErrorDescription)
+		}
+		return "", 0, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, tr.Error)
+	}
+	if tr.AccessToken == "" {
+		return "", 0, fmt.Errorf("token endpoint returned no access_token")
+	}
+	if tr.TokenType != "" && !strings.EqualFold(tr.TokenType, "bearer") {
+		return "", 0, fmt.Errorf("token endpoint returned unsupported token_type %q", tr.TokenType)
+	}
+	return tr.AccessToken, time.Duration(tr.ExpiresIn) * time.Second, nil
+}
@@ -0,0 +1,228 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// oauthServer is a client-credentials token endpoint plus an MCP
+// endpoint that only accepts the most recently issued token.
+type oauthServer struct {
+	ts *httptest.Server
+
+	mu        sync.Mutex
+	issued    int
+	current   string
+	expiresIn int64
+	failToken bool
+	scopes    []string
+	authSeen  []string
+}
+
+func newOAuthServer(t *testing.T) *oauthServer {
+	s := &oauthServer{expiresIn: 3600}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", s.handleToken)
+	mux.HandleFunc("POST /mcp", s.handleMCP)
+	s.ts = httptest.NewServer(mux)
+	t.Cleanup(s.ts.Close)
+	return s
+}
+
+func (s *oauthServer) handleToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, secret, ok := r.BasicAuth()
+	w.Header().Set("Content-Type", "application/json")
+	if s.failToken || !ok || id != "thane" || secret != "s3cret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"invalid_client","error_description":"bad credentials"}`))
+		return
+	}
+	if r.FormValue("grant_type") != "client_credentials" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"unsupported_grant_type"}`))
+		return
+	}
+	s.scopes = strings.Fields(r.FormValue("scope"))
+	s.issued++
+	s.current = fmt.Sprintf("token-%d", s.issued)
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": s.current,
+		"token_type":   "Bearer",
+		"expires_in":   s.expiresIn,
+	})
+}
+
+func (s *oauthServer) handleMCP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	auth := r.Header.Get("Authorization")
+	s.authSeen = append(s.authSeen, auth)
+	valid := s.current != "" && auth == "Bearer "+s.current
+	s.mu.Unlock()
+	if !valid {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(Response{JSONRPC: jsonrpcVersion, ID: req.ID, Result: json.RawMessage(`{}`)})
+}
+
+// revoke makes the MCP endpoint reject the current token without the
+// client knowing, as when an authorization server rotates keys.
+func (s *oauthServer) revoke() {
+	s.mu.Lock()
+	s.current = "revoked"
+	s.mu.Unlock()
+}
+
+func (s *oauthServer) newTransport() *HTTPTransport {
+	return NewHTTPTransport(HTTPConfig{
+		URL: s.ts.URL + "/mcp",
+		OAuth2: &OAuth2Config{
+			TokenURL:     s.ts.URL + "/token",
+			ClientID:     "thane",
+			ClientSecret: "s3cret",
+			Scopes:       []string{"mcp.read", "mcp.call"},
+		},
+	})
+}
+
+func TestHTTPTransport_OAuth2CachesToken(t *testing.T) {
+	srv := newOAuthServer(t)
+	transport := srv.newTransport()
+
+	ctx := context.Background()
+	for i := range 3 {
+		if _, err := transport.Send(ctx, NewRequest(int64(i+1), "ping", nil)); err != nil {
+			t.Fatalf("Send %d: %v", i, err)
+		}
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.issued != 1 {
+		t.Errorf("tokens issued = %d, want 1 (cached)", srv.issued)
+	}
+	if strings.Join(srv.scopes, " ") != "mcp.read mcp.call" {
+		t.Errorf("requested scopes = %v", srv.scopes)
+	}
+	for _, auth := range srv.authSeen {
+		if auth != "Bearer token-1" {
+			t.Errorf("Authorization = %q, want Bearer token-1", auth)
+		}
+	}
+}
+
+func TestHTTPTransport_OAuth2RefreshesBeforeExpiry(t *testing.T) {
+	srv := newOAuthServer(t)
+	srv.expiresIn = 300
+	transport := srv.newTransport()
+	now := time.Now()
+	transport.tokens.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	if _, err := transport.Send(ctx, NewRequest(1, "ping", nil)); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	// Inside the refresh margin but before the token actually expires.
+	now = now.Add(300*time.Second - tokenRefreshMargin + time.Second)
+	if _, err := transport.Send(ctx, NewRequest(2, "ping", nil)); err != nil {
+		t.Fatalf("Send after expiry: %v", err)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.issued != 2 {
+		t.Errorf("tokens issued = %d, want 2 (refreshed before expiry)", srv.issued)
+	}
+}
+
+func TestHTTPTransport_OAuth2RetriesOnUnauthorized(t *testing.T) {
+	srv := newOAuthServer(t)
+	transport := srv.newTransport()
+
+	ctx := context.Background()
+	if _, err := transport.Send(ctx, NewRequest(1, "ping", nil)); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	srv.revoke()
+	resp, err := transport.Send(ctx, NewRequest(2, "ping", nil))
+	if err != nil {
+		t.Fatalf("Send after revocation: %v", err)
+	}
+	if resp.ID != 2 {
+		t.Errorf("response ID = %d, want 2", resp.ID)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.issued != 2 {
+		t.Errorf("tokens issued = %d, want 2 (refreshed after 401)", srv.issued)
+	}
+}
+
+func TestHTTPTransport_OAuth2FailedRefreshFailsPing(t *testing.T) {
+	srv := newOAuthServer(t)
+	srv.expiresIn = 300
+	transport := srv.newTransport()
+	now := time.Now()
+	transport.tokens.now = func() time.Time { return now }
+
+	client := NewClient("gateway", transport, nil)
+	ctx := context.Background()
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+
+	srv.mu.Lock()
+	srv.failToken = true
+	srv.mu.Unlock()
+	now = now.Add(time.Hour)
+
+	err := client.Ping(ctx)
+	if err == nil {
+		t.Fatal("Ping succeeded with a token that could not be refreshed")
+	}
+	if !strings.Contains(err.Error(), "invalid_client") {
+		t.Errorf("Ping error = %v, want the token endpoint's error", err)
+	}
+}
+
+func TestHTTPTransport_StaticHeadersWithoutOAuth2(t *testing.T) {
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		var req Request
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(Response{JSONRPC: jsonrpcVersion, ID: req.ID, Result: json.RawMessage(`{}`)})
+	}))
+	defer srv.Close()
+
+	transport := NewHTTPTransport(HTTPConfig{
+		URL:     srv.URL,
+		Headers: map[string]string{"Authorization": "Bearer static"},
+	})
+	if _, err := transport.Send(context.Background(), NewRequest(1, "ping", nil)); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if auth != "Bearer static" {
+		t.Errorf("Authorization = %q, want the static header", auth)
+	}
+}
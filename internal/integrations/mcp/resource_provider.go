@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/agentctx"
+)
+
+// maxResourceContextBytes caps the resource content one provider
+// injects per turn, matching the tag-context bucket limit.
+const maxResourceContextBytes = 64 * 1024
+
+// resourceTruncationMarker is appended when injected resources exceed
+// [maxResourceContextBytes].
+const resourceTruncationMarker = "\n\n[MCP resources truncated: exceeded 64 KB limit]\n"
+
+// ResourceProvider injects the contents of an allowlisted set of MCP
+// resources into the system prompt on every turn, so a server's README,
+// configuration notes, or live data reach the model without a tool
+// call. It implements [agent.TagContextProvider] structurally.
+//
+// Resources are re-read each turn. Text content is injected verbatim;
+// binary (blob) content is summarized by MIME type and size, since
+// base64 is of no use to the model.
+type ResourceProvider struct {
+	client     *Client
+	serverName string
+	uris       []string
+	logger     *slog.Logger
+}
+
+// NewResourceProvider creates a provider that reads uris from client.
+// serverName labels the injected section.
+func NewResourceProvider(client *Client, serverName string, uris []string, logger *slog.Logger) *ResourceProvider {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ResourceProvider{
+		client:     client,
+		serverName: serverName,
+		uris:       append([]string(nil), uris...),
+		logger:     logger.With("mcp_server", serverName),
+	}
+}
+
+// TagContextBucket places resources in live state: they are re-read
+// every turn and may change between turns, so they must not thrash the
+// cached prompt prefix.
+func (p *ResourceProvider) TagContextBucket() agentctx.ContextBucket {
+	return agentctx.ContextBucketLiveState
+}
+
+// TagContext reads each allowlisted resource and renders its contents.
+// Resources that cannot be read are logged and skipped; while the
+// server is disconnected the provider injects nothing.
+func (p *ResourceProvider) TagContext(ctx context.Context, _ agentctx.ContextRequest) (string, error) {
+	var body strings.Builder
+	for _, uri := range p.uris {
+		contents, err := p.client.ReadResource(ctx, uri)
+		if errors.Is(err, ErrDisconnected) {
+			return "", nil
+		}
+		if err != nil {
+			p.logger.Warn("MCP resource read failed", "uri", uri, "error", err)
+			continue
+		}
+		for _, c := range contents {
+			writeResourceContent(&body, uri, c)
+		}
+	}
+	if body.Len() == 0 {
+		return "", nil
+	}
+
+	out := fmt.Sprintf("### MCP Resources (%s)\n\n", p.serverName) + body.String()
+	if len(out) > maxResourceContextBytes {
+		out = truncateUTF8(out, maxResourceContextBytes-len(resourceTruncationMarker)) + resourceTruncationMarker
+	}
+	return out, nil
+}
+
+// writeResourceContent renders one content item under a heading naming
+// its URI (falling back to the requested URI).
+func writeResourceContent(b *strings.Builder, requested string, c ResourceContent) {
+	uri := c.URI
+	if uri == "" {
+		uri = requested
+	}
+	b.WriteString("#### " + uri)
+	if c.MimeType != "" {
+		b.WriteString(" (" + c.MimeType + ")")
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case c.Text != "":
+		b.WriteString(strings.TrimRight(c.Text, "\n"))
+	case c.Blob != "":
+		mime := c.MimeType
+		if mime == "" {
+			mime = "application/octet-stream"
+		}
+		fmt.Fprintf(b, "[binary resource: %s, %d bytes]", mime, blobSize(c.Blob))
+	default:
+		b.WriteString("[empty resource]")
+	}
+	b.WriteString("\n\n")
+}
+
+// blobSize returns the decoded size of base64 data without decoding it.
+func blobSize(blob string) int {
+	blob = strings.TrimSpace(blob)
+	return base64.StdEncoding.DecodedLen(len(blob)) - strings.Count(blob[max(0, len(blob)-2):], "=")
+}
+
+// truncateUTF8 returns the longest prefix of s that is at most maxBytes
+// long and does not split a multi-byte character.
+func truncateUTF8(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	for maxBytes > 0 && !utf8.RuneStart(s[maxBytes]) {
+		maxBytes--
+	}
+	return s[:maxBytes]
+}
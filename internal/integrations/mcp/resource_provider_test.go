@@ -0,0 +1,118 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/agentctx"
+)
+
+func TestClient_ListResources(t *testing.T) {
+	mt := newMockTransport()
+	mt.addResponse("resources/list", resourcesListResult{
+		Resources: []ResourceDefinition{
+			{URI: "file:///README.md", Name: "README", MimeType: "text/markdown"},
+		},
+	})
+
+	client := NewClient("docs", mt, nil)
+	resources, err := client.ListResources(context.Background())
+	if err != nil {
+		t.Fatalf("ListResources: %v", err)
+	}
+	if len(resources) != 1 || resources[0].URI != "file:///README.md" {
+		t.Errorf("resources = %+v, want the README", resources)
+	}
+}
+
+func TestClient_ReadResourceSendsURI(t *testing.T) {
+	mt := newMockTransport()
+	mt.addResponse("resources/read", readResourceResult{
+		Contents: []ResourceContent{{URI: "file:///README.md", Text: "# Hello"}},
+	})
+
+	client := NewClient("docs", mt, nil)
+	contents, err := client.ReadResource(context.Background(), "file:///README.md")
+	if err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+	if len(contents) != 1 || contents[0].Text != "# Hello" {
+		t.Errorf("contents = %+v", contents)
+	}
+	params, _ := mt.sent[0].Params.(map[string]any)
+	if params["uri"] != "file:///README.md" {
+		t.Errorf("resources/read params = %v, want the uri", mt.sent[0].Params)
+	}
+}
+
+func TestResourceProvider_TextAndBlob(t *testing.T) {
+	mt := newMockTransport()
+	mt.addResponse("resources/read", readResourceResult{
+		Contents: []ResourceContent{
+			{URI: "file:///README.md", MimeType: "text/markdown", Text: "# Hello\n"},
+			{URI: "file:///logo.png", MimeType: "image/png", Blob: "aGVsbG8="},
+		},
+	})
+
+	p := NewResourceProvider(NewClient("docs", mt, nil), "docs", []string{"file:///README.md"}, nil)
+	if got := p.TagContextBucket(); got != agentctx.ContextBucketLiveState {
+		t.Errorf("bucket = %q, want live state", got)
+	}
+	out, err := p.TagContext(context.Background(), agentctx.ContextRequest{})
+	if err != nil {
+		t.Fatalf("TagContext: %v", err)
+	}
+	for _, want := range []string{
+		"### MCP Resources (docs)",
+		"#### file:///README.md (text/markdown)\n\n# Hello\n",
+		"[binary resource: image/png, 5 bytes]",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("context missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "aGVsbG8=") {
+		t.Error("blob base64 injected into the prompt")
+	}
+}
+
+func TestResourceProvider_CapsInjectedSize(t *testing.T) {
+	mt := newMockTransport()
+	mt.addResponse("resources/read", readResourceResult{
+		Contents: []ResourceContent{{URI: "file:///big.txt", Text: strings.Repeat("é", maxResourceContextBytes)}},
+	})
+
+	p := NewResourceProvider(NewClient("docs", mt, nil), "docs", []string{"file:///big.txt"}, nil)
+	out, err := p.TagContext(context.Background(), agentctx.ContextRequest{})
+	if err != nil {
+		t.Fatalf("TagContext: %v", err)
+	}
+	if len(out) > maxResourceContextBytes {
+		t.Errorf("len = %d, want at most %d", len(out), maxResourceContextBytes)
+	}
+	if !strings.HasSuffix(out, resourceTruncationMarker) {
+		t.Error("truncated context lacks the truncation marker")
+	}
+	if !strings.HasPrefix(strings.TrimSuffix(out, resourceTruncationMarker), "### MCP Resources") {
+		t.Error("truncation dropped the section heading")
+	}
+}
+
+func TestResourceProvider_SkipsFailedAndDisconnected(t *testing.T) {
+	mt := newMockTransport()
+	mt.addError("resources/read", -32002, "resource not found")
+
+	client := NewClient("docs", mt, nil)
+	p := NewResourceProvider(client, "docs", []string{"file:///missing.md"}, nil)
+	out, err := p.TagContext(context.Background(), agentctx.ContextRequest{})
+	if err != nil || out != "" {
+		t.Errorf("TagContext = %q, %v; want empty for an unreadable resource", out, err)
+	}
+
+	client.Close()
+	out, err = p.TagContext(context.Background(), agentctx.ContextRequest{})
+	if err != nil || out != "" {
+		t.Errorf("TagContext = %q, %v; want empty while disconnected", out, err)
+	}
+}
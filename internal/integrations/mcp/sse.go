@@ -0,0 +1,402 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/httpkit"
+)
+
+// errSSEStreamClosed is returned for requests on an SSE session whose
+// event stream has ended. The session is gone with the stream, so the
+// transport stays failed until [SSETransport.Close] (which
+// [Client.Reconnect] calls) lets the next request open a new one.
+var errSSEStreamClosed = errors.New("SSE event stream closed")
+
+// SSEConfig configures an SSE MCP transport that communicates with a
+// remote MCP server over the HTTP+SSE transport (protocol 2024-11-05):
+// responses arrive on a server-sent event stream and requests are
+// POSTed to an endpoint the stream announces.
+type SSEConfig struct {
+	// URL is the MCP server's SSE endpoint.
+	URL string
+
+	// Headers are additional HTTP headers sent with every request,
+	// including the event stream request (e.g., Authorization).
+	Headers map[string]string
+
+	// Logger is the structured logger for transport diagnostics.
+	Logger *slog.Logger
+}
+
+// SSETransport communicates with an MCP server over HTTP+SSE. A GET to
+// the configured URL opens the event stream; its first "endpoint"
+// event names the URL to POST JSON-RPC messages to, and responses come
+// back as "message" events correlated by request ID. The stream is
+// opened on the first request.
+type SSETransport struct {
+	url          string
+	headers      map[string]string
+	streamClient *http.Client
+	postClient   *http.Client
+	logger       *slog.Logger
+
+	mu      sync.Mutex
+	session *sseSession // nil until the first request or after Close
+}
+
+// sseSession is one open event stream and the requests awaiting
+// responses on it.
+type sseSession struct {
+	endpoint string
+	cancel   context.CancelFunc
+	done     chan struct{} // closed when the stream reader exits
+
+	mu      sync.Mutex
+	pending map[int64]chan *Response
+	err     error // why the stream ended; nil while open
+}
+
+// NewSSETransport creates an SSE transport for the given config. The
+// underlying HTTP clients are constructed via httpkit; the event
+// stream's client has no overall timeout since the stream is
+// long-lived.
+func NewSSETransport(cfg SSEConfig) *SSETransport {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SSETransport{
+		url:          cfg.URL,
+		headers:      cfg.Headers,
+		streamClient: httpkit.NewClient(httpkit.WithLogger(logger), httpkit.WithTimeout(0)),
+		postClient:   httpkit.NewClient(httpkit.WithLogger(logger)),
+		logger:       logger,
+	}
+}
+
+// Send POSTs a JSON-RPC request to the session endpoint and waits for
+// the matching response on the event stream.
+func (t *SSETransport) Send(ctx context.Context, req *Request) (*Response, error) {
+	sess, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := sess.register(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer sess.unregister(req.ID)
+
+	resp, err := t.post(ctx, sess.endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil && resp.ID == req.ID {
+		// Some servers answer inline instead of on the stream.
+		return resp, nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case resp, ok := <-ch:
+		if !ok {
+			return nil, sess.closedErr()
+		}
+		return resp, nil
+	}
+}
+
+// Notify POSTs a JSON-RPC notification to the session endpoint. No
+// response is expected.
+func (t *SSETransport) Notify(ctx context.Context, notif *Notification) error {
+	sess, err := t.connect(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = t.post(ctx, sess.endpoint, notif)
+	return err
+}
+
+// Close ends the event stream. The next request opens a new stream
+// and session.
+func (t *SSETransport) Close() error {
+	t.mu.Lock()
+	sess := t.session
+	t.session = nil
+	t.mu.Unlock()
+
+	if sess != nil {
+		sess.cancel()
+		<-sess.done
+	}
+	return nil
+}
+
+// connect returns the current session, opening the event stream and
+// waiting for its endpoint event if there is none. A session whose
+// stream has ended is returned as an error, not replaced.
+func (t *SSETransport) connect(ctx context.Context) (*sseSession, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.session != nil {
+		if t.session.ended() {
+			return nil, t.session.closedErr()
+		}
+		return t.session, nil
+	}
+
+	streamCtx, cancel := context.WithCancel(context.Background())
+	// The stream context lives on with the session; every path that
+	// fails to open one must release it.
+	opened := false
+	defer func() {
+		if !opened {
+			cancel()
+		}
+	}()
+	httpReq, err := http.NewRequestWithContext(streamCtx, http.MethodGet, t.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create SSE request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+	httpReq.Header.Set("Cache-Control", "no-cache")
+	for k, v := range t.headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	// The handshake honors ctx; once the endpoint arrives the stream
+	// outlives it.
+	stopHandshake := context.AfterFunc(ctx, cancel)
+	defer stopHandshake()
+
+	httpResp, err := t.streamClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("SSE request to %s: %w", t.url, err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		errBody := httpkit.ReadErrorBody(httpResp.Body, 1<<20)
+		httpResp.Body.Close()
+		return nil, fmt.Errorf("MCP server returned %d for SSE stream: %s", httpResp.StatusCode, errBody)
+	}
+
+	sess := &sseSession{
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		pending: make(map[int64]chan *Response),
+	}
+	endpoint := make(chan string, 1)
+	go t.readStream(sess, httpResp.Body, endpoint)
+
+	select {
+	case ep := <-endpoint:
+		resolved, err := t.resolveEndpoint(ep)
+		if err != nil {
+			cancel()
+			<-sess.done
+			return nil, err
+		}
+		sess.endpoint = resolved
+	case <-sess.done:
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("SSE stream from %s ended before endpoint event: %w", t.url, sess.closedErr())
+	}
+
+	t.logger.Info("MCP SSE stream opened", "url", t.url, "endpoint", sess.endpoint)
+	t.session = sess
+	opened = true
+	return sess, nil
+}
+
+// resolveEndpoint resolves the endpoint event's (usually relative) URI
+// against the stream URL.
+func (t *SSETransport) resolveEndpoint(ep string) (string, error) {
+	base, err := url.Parse(t.url)
+	if err != nil {
+		return "", fmt.Errorf("parse SSE url: %w", err)
+	}
+	ref, err := url.Parse(strings.TrimSpace(ep))
+	if err != nil {
+		return "", fmt.Errorf("parse SSE endpoint %q: %w", ep, err)
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// readStream parses server-sent events from body until it ends,
+// delivering the endpoint event to endpoint and message events to the
+// requests awaiting them. When the stream ends every pending request is
+// released with [errSSEStreamClosed].
+func (t *SSETransport) readStream(sess *sseSession, body io.ReadCloser, endpoint chan<- string) {
+	defer close(sess.done)
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10<<20) // 10 MiB, matching HTTP responses
+	var event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				t.dispatch(sess, event, strings.Join(data, "\n"), endpoint)
+			}
+			event, data = "", data[:0]
+		case strings.HasPrefix(line, ":"):
+			// Comment or keep-alive.
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+
+	err := scanner.Err()
+	if err == nil {
+		err = io.EOF
+	}
+	sess.end(err)
+	t.logger.Debug("MCP SSE stream ended", "url", t.url, "error", err)
+}
+
+// dispatch handles one complete event.
+func (t *SSETransport) dispatch(sess *sseSession, event, data string, endpoint chan<- string) {
+	switch event {
+	case "endpoint":
+		select {
+		case endpoint <- data:
+		default:
+			t.logger.Debug("ignoring repeated MCP SSE endpoint event", "endpoint", data)
+		}
+	case "", "message":
+		var resp Response
+		if err := json.Unmarshal([]byte(data), &resp); err != nil {
+			t.logger.Debug("skipping non-JSON MCP SSE message", "data", data)
+			return
+		}
+		if !sess.deliver(&resp) {
+			t.logger.Debug("skipping unmatched MCP message", "id", resp.ID)
+		}
+	default:
+		t.logger.Debug("skipping MCP SSE event", "event", event)
+	}
+}
+
+// post sends msg to the session endpoint. Servers normally accept with
+// 202 and answer on the stream; a 200 carrying a JSON-RPC response is
+// returned to the caller.
+func (t *SSETransport) post(ctx context.Context, endpoint string, msg any) (*Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("marshal message: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range t.headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	httpResp, err := t.postClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request to %s: %w", endpoint, err)
+	}
+	defer httpkit.DrainAndClose(httpResp.Body, 1<<20)
+
+	switch httpResp.StatusCode {
+	case http.StatusAccepted, http.StatusNoContent:
+		return nil, nil
+	case http.StatusOK:
+		respBody, err := io.ReadAll(io.LimitReader(httpResp.Body, 10<<20))
+		if err != nil {
+			return nil, fmt.Errorf("read response body: %w", err)
+		}
+		var resp Response
+		if len(bytes.TrimSpace(respBody)) == 0 || json.Unmarshal(respBody, &resp) != nil {
+			return nil, nil
+		}
+		return &resp, nil
+	default:
+		errBody := httpkit.ReadErrorBody(httpResp.Body, 1<<20)
+		return nil, fmt.Errorf("MCP server returned %d: %s", httpResp.StatusCode, errBody)
+	}
+}
+
+// register reserves a response slot for id. It fails once the stream
+// has ended.
+func (s *sseSession) register(id int64) (chan *Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, fmt.Errorf("%w: %w", errSSEStreamClosed, s.err)
+	}
+	ch := make(chan *Response, 1)
+	s.pending[id] = ch
+	return ch, nil
+}
+
+// unregister releases the response slot for id.
+func (s *sseSession) unregister(id int64) {
+	s.mu.Lock()
+	delete(s.pending, id)
+	s.mu.Unlock()
+}
+
+// deliver hands resp to the request awaiting it, reporting whether one
+// was.
+func (s *sseSession) deliver(resp *Response) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch, ok := s.pending[resp.ID]
+	if ok {
+		delete(s.pending, resp.ID)
+		ch <- resp
+	}
+	return ok
+}
+
+// end records why the stream ended and releases every pending request.
+func (s *sseSession) end(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+	for id, ch := range s.pending {
+		close(ch)
+		delete(s.pending, id)
+	}
+}
+
+// ended reports whether the stream has ended.
+func (s *sseSession) ended() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err != nil
+}
+
+// closedErr returns the error for requests on an ended stream.
+func (s *sseSession) closedErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		return errSSEStreamClosed
+	}
+	return fmt.Errorf("%w: %w", errSSEStreamClosed, s.err)
+}
@@ -0,0 +1,212 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+// sseServer is a minimal HTTP+SSE MCP server. Each GET opens a session
+// whose endpoint event points at /messages?session=N; POSTed requests
+// are answered on that session's stream.
+type sseServer struct {
+	t  *testing.T
+	ts *httptest.Server
+
+	mu       sync.Mutex
+	sessions map[string]chan []byte
+	drop     map[string]chan struct{}
+	opened   int
+	inline   bool // answer POSTs in the response body instead
+}
+
+func newSSEServer(t *testing.T) *sseServer {
+	s := &sseServer{t: t, sessions: map[string]chan []byte{}, drop: map[string]chan struct{}{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sse", s.handleStream)
+	mux.HandleFunc("POST /messages", s.handleMessage)
+	s.ts = httptest.NewServer(mux)
+	t.Cleanup(s.ts.Close)
+	return s
+}
+
+func (s *sseServer) handleStream(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.opened++
+	id := fmt.Sprint(s.opened)
+	out := make(chan []byte, 16)
+	drop := make(chan struct{})
+	s.sessions[id] = out
+	s.drop[id] = drop
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	fmt.Fprintf(w, ": keep-alive\n\nevent: endpoint\ndata: /messages?session=%s\n\n", id)
+	w.(http.Flusher).Flush()
+	for {
+		select {
+		case msg := <-out:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", msg)
+			w.(http.Flusher).Flush()
+		case <-drop:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *sseServer) handleMessage(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	out, ok := s.sessions[r.URL.Query().Get("session")]
+	inline := s.inline
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+	if req.ID == 0 { // notification
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var result any
+	switch req.Method {
+	case "initialize":
+		result = initializeResult{ProtocolVersion: protocolVersion, ServerInfo: serverInfo{Name: "gateway"}}
+	case "tools/list":
+		result = toolsListResult{Tools: []ToolDefinition{{Name: "lookup", InputSchema: map[string]any{"type": "object"}}}}
+	default:
+		result = map[string]any{}
+	}
+	data, _ := json.Marshal(result)
+	msg, _ := json.Marshal(Response{JSONRPC: jsonrpcVersion, ID: req.ID, Result: data})
+	if inline {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(msg)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	out <- msg
+}
+
+// dropSessions ends every open event stream.
+func (s *sseServer) dropSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, ch := range s.drop {
+		close(ch)
+		delete(s.drop, id)
+		delete(s.sessions, id)
+	}
+}
+
+func TestSSETransport_BridgesTools(t *testing.T) {
+	srv := newSSEServer(t)
+	transport := NewSSETransport(SSEConfig{URL: srv.ts.URL + "/sse"})
+	defer transport.Close()
+
+	client := NewClient("gateway", transport, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	registry := tools.NewEmptyRegistry()
+	count, err := BridgeTools(ctx, client, "gateway", registry, BridgeOptions{}, nil)
+	if err != nil {
+		t.Fatalf("BridgeTools: %v", err)
+	}
+	if count != 1 || registry.Get("mcp_gateway_lookup") == nil {
+		t.Errorf("bridged %d tools, want mcp_gateway_lookup", count)
+	}
+}
+
+func TestSSETransport_InlineResponse(t *testing.T) {
+	srv := newSSEServer(t)
+	srv.inline = true
+	transport := NewSSETransport(SSEConfig{URL: srv.ts.URL + "/sse"})
+	defer transport.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := transport.Send(ctx, NewRequest(7, "ping", nil))
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if resp.ID != 7 {
+		t.Errorf("response ID = %d, want 7", resp.ID)
+	}
+}
+
+func TestSSETransport_StreamDropFailsUntilReconnect(t *testing.T) {
+	srv := newSSEServer(t)
+	transport := NewSSETransport(SSEConfig{URL: srv.ts.URL + "/sse"})
+	defer transport.Close()
+
+	client := NewClient("gateway", transport, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	srv.dropSessions()
+	waitFor(t, "stream drop to fail requests", func() bool {
+		return errors.Is(client.Ping(ctx), errSSEStreamClosed)
+	})
+
+	if err := client.Reconnect(ctx); err != nil {
+		t.Fatalf("Reconnect: %v", err)
+	}
+	if err := client.Ping(ctx); err != nil {
+		t.Errorf("Ping after reconnect: %v", err)
+	}
+	srv.mu.Lock()
+	opened := srv.opened
+	srv.mu.Unlock()
+	if opened != 2 {
+		t.Errorf("streams opened = %d, want 2 (original and reconnect)", opened)
+	}
+}
+
+func TestSSETransport_StreamDropReleasesPendingRequest(t *testing.T) {
+	srv := newSSEServer(t)
+	transport := NewSSETransport(SSEConfig{URL: srv.ts.URL + "/sse"})
+	defer transport.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	sess, err := transport.connect(ctx)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	ch, err := sess.register(42)
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	srv.dropSessions()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("pending request received a response from a dropped stream")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("pending request not released when the stream dropped")
+	}
+}
@@ -0,0 +1,212 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+// Supervisor defaults, applied to zero-value [SupervisorConfig] fields.
+const (
+	defaultCheckInterval  = 30 * time.Second
+	defaultPingTimeout    = 10 * time.Second
+	defaultInitialBackoff = 2 * time.Second
+	defaultMaxBackoff     = 60 * time.Second
+	defaultStepTimeout    = 30 * time.Second
+)
+
+// SupervisorConfig configures a [Supervisor].
+type SupervisorConfig struct {
+	// ServerName is the configured MCP server name, used to namespace
+	// bridged tool names.
+	ServerName string
+
+	// Registry receives the bridged tools.
+	Registry *tools.Registry
+
+	// Bridge controls which tools are bridged and how, reapplied on
+	// every re-bridge.
+	Bridge BridgeOptions
+
+	// CheckInterval is how often the server is pinged (default: 30s).
+	CheckInterval time.Duration
+
+	// PingTimeout limits each health ping (default: 10s).
+	PingTimeout time.Duration
+
+	// InitialBackoff is the delay after the first failed reconnect
+	// attempt, doubling up to MaxBackoff (defaults: 2s and 60s).
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// StepTimeout limits each reconnect attempt, including the
+	// re-bridge (default: 30s).
+	StepTimeout time.Duration
+
+	// Logger is the structured logger for supervision events.
+	Logger *slog.Logger
+}
+
+// Supervisor keeps one MCP server's client connected and its tools
+// bridged. It pings the server periodically; when a ping fails it
+// reconnects with exponential backoff ([Client.Reconnect] restarts a
+// stdio subprocess or re-dials an HTTP server) and re-bridges the
+// server's tools, unregistering any the server no longer offers.
+type Supervisor struct {
+	client *Client
+	cfg    SupervisorConfig
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	bridged []string // registry names of the currently bridged tools
+}
+
+// NewSupervisor creates a supervisor for an initialized client. Call
+// [Supervisor.Bridge] to register the server's tools, then run
+// [Supervisor.Run] in a goroutine.
+func NewSupervisor(client *Client, cfg SupervisorConfig) *Supervisor {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = defaultCheckInterval
+	}
+	if cfg.PingTimeout <= 0 {
+		cfg.PingTimeout = defaultPingTimeout
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = defaultInitialBackoff
+	}
+	if cfg.MaxBackoff < cfg.InitialBackoff {
+		cfg.MaxBackoff = max(defaultMaxBackoff, cfg.InitialBackoff)
+	}
+	if cfg.StepTimeout <= 0 {
+		cfg.StepTimeout = defaultStepTimeout
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Supervisor{
+		client: client,
+		cfg:    cfg,
+		logger: logger.With("mcp_server", cfg.ServerName),
+	}
+}
+
+// Bridge registers the server's tools on the registry and unregisters
+// previously bridged tools the server no longer lists. It returns the
+// number of tools bridged.
+func (s *Supervisor) Bridge(ctx context.Context) (int, error) {
+	names, err := bridgeTools(ctx, s.client, s.cfg.ServerName, s.cfg.Registry, s.cfg.Bridge, s.logger)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	previous := s.bridged
+	s.bridged = names
+	s.mu.Unlock()
+
+	var removed []string
+	for _, name := range previous {
+		if !slices.Contains(names, name) {
+			s.cfg.Registry.Unregister(name)
+			removed = append(removed, name)
+		}
+	}
+	if len(removed) > 0 {
+		s.logger.Info("unregistered MCP tools no longer offered by server",
+			"tools", removed,
+		)
+	}
+	return len(names), nil
+}
+
+// Run supervises the connection until ctx is cancelled.
+func (s *Supervisor) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := s.ping(ctx)
+		if err == nil || ctx.Err() != nil {
+			continue
+		}
+		// Only the supervisor reconnects, so a disconnected client
+		// outside reconnect has been closed.
+		if errors.Is(err, ErrDisconnected) {
+			return
+		}
+		// stdio serves one request at a time, so a ping can time out
+		// behind a slow tool call on a healthy server.
+		if errors.Is(err, context.DeadlineExceeded) && s.client.callsInFlight() > 0 {
+			s.logger.Debug("MCP health ping timed out behind in-flight tool call")
+			continue
+		}
+
+		s.logger.Warn("MCP server unresponsive, reconnecting", "error", err)
+		s.reconnect(ctx)
+	}
+}
+
+// ping checks server health with the configured timeout.
+func (s *Supervisor) ping(ctx context.Context) error {
+	pingCtx, cancel := context.WithTimeout(ctx, s.cfg.PingTimeout)
+	defer cancel()
+	return s.client.Ping(pingCtx)
+}
+
+// reconnect retries [Supervisor.attempt] with exponential backoff until
+// it succeeds or ctx is cancelled.
+func (s *Supervisor) reconnect(ctx context.Context) {
+	delay := s.cfg.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		count, err := s.attempt(ctx)
+		if err == nil {
+			s.logger.Info("MCP server reconnected",
+				"attempts", attempt,
+				"tools", count,
+			)
+			return
+		}
+		if errors.Is(err, ErrDisconnected) {
+			return // client closed
+		}
+
+		s.logger.Warn("MCP server reconnect failed",
+			"attempt", attempt,
+			"next_delay", delay.String(),
+			"error", err,
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		delay = min(delay*2, s.cfg.MaxBackoff)
+	}
+}
+
+// attempt makes one reconnect attempt and re-bridges the server's
+// tools, returning the number bridged.
+func (s *Supervisor) attempt(ctx context.Context) (int, error) {
+	stepCtx, cancel := context.WithTimeout(ctx, s.cfg.StepTimeout)
+	defer cancel()
+
+	if err := s.client.Reconnect(stepCtx); err != nil {
+		return 0, err
+	}
+	return s.Bridge(stepCtx)
+}
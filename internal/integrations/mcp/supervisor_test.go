@@ -0,0 +1,234 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+// serverTransport simulates a restartable MCP server: its tool list can
+// change between connections, it can be taken down, and tools/call can
+// be made to block until the request context ends.
+type serverTransport struct {
+	mu        sync.Mutex
+	tools     []ToolDefinition
+	down      bool
+	blockCall bool
+	closes    int
+}
+
+func (st *serverTransport) setTools(names ...string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.tools = nil
+	for _, n := range names {
+		st.tools = append(st.tools, ToolDefinition{Name: n, InputSchema: map[string]any{"type": "object"}})
+	}
+}
+
+func (st *serverTransport) setDown(down bool) {
+	st.mu.Lock()
+	st.down = down
+	st.mu.Unlock()
+}
+
+func (st *serverTransport) Send(ctx context.Context, req *Request) (*Response, error) {
+	st.mu.Lock()
+	down, block := st.down, st.blockCall
+	var result any
+	switch req.Method {
+	case "initialize":
+		result = initializeResult{ServerInfo: serverInfo{Name: "test"}}
+	case "tools/list":
+		result = toolsListResult{Tools: st.tools}
+	case "tools/call":
+		result = callToolResult{Content: []ContentBlock{{Type: "text", Text: "ok"}}}
+	default:
+		result = map[string]any{}
+	}
+	st.mu.Unlock()
+
+	if down {
+		return nil, errors.New("broken pipe")
+	}
+	if block && req.Method == "tools/call" {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	data, _ := json.Marshal(result)
+	return &Response{JSONRPC: jsonrpcVersion, ID: req.ID, Result: data}, nil
+}
+
+func (st *serverTransport) Notify(context.Context, *Notification) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.down {
+		return errors.New("broken pipe")
+	}
+	return nil
+}
+
+func (st *serverTransport) Close() error {
+	st.mu.Lock()
+	st.closes++
+	st.mu.Unlock()
+	return nil
+}
+
+func newTestSupervisor(t *testing.T, st *serverTransport) (*Supervisor, *Client, *tools.Registry) {
+	t.Helper()
+	client := NewClient("srv", st, nil)
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	registry := tools.NewEmptyRegistry()
+	sup := NewSupervisor(client, SupervisorConfig{
+		ServerName:     "srv",
+		Registry:       registry,
+		CheckInterval:  5 * time.Millisecond,
+		InitialBackoff: 5 * time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	})
+	if _, err := sup.Bridge(context.Background()); err != nil {
+		t.Fatalf("Bridge: %v", err)
+	}
+	return sup, client, registry
+}
+
+func TestSupervisor_ReconnectRebridgesTools(t *testing.T) {
+	st := &serverTransport{}
+	st.setTools("alpha", "beta")
+	sup, _, registry := newTestSupervisor(t, st)
+
+	st.setTools("beta", "gamma")
+	count, err := sup.attempt(context.Background())
+	if err != nil {
+		t.Fatalf("attempt: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("bridged %d tools, want 2", count)
+	}
+	if registry.Get("mcp_srv_alpha") != nil {
+		t.Error("mcp_srv_alpha still registered after the server dropped it")
+	}
+	for _, name := range []string{"mcp_srv_beta", "mcp_srv_gamma"} {
+		if registry.Get(name) == nil {
+			t.Errorf("%s not registered after re-bridge", name)
+		}
+	}
+	if st.closes != 1 {
+		t.Errorf("transport closed %d times, want 1", st.closes)
+	}
+}
+
+func TestSupervisor_CallsFailCleanlyWhileReconnecting(t *testing.T) {
+	st := &serverTransport{}
+	st.setTools("alpha")
+	_, client, registry := newTestSupervisor(t, st)
+
+	st.setDown(true)
+	if err := client.Reconnect(context.Background()); err == nil {
+		t.Fatal("Reconnect succeeded against a down server")
+	}
+
+	_, err := registry.Get("mcp_srv_alpha").Handler(context.Background(), nil)
+	var unavailable tools.ErrUnavailable
+	if !errors.As(err, &unavailable) {
+		t.Fatalf("handler error = %v, want tools.ErrUnavailable", err)
+	}
+	if err := client.Ping(context.Background()); !errors.Is(err, ErrDisconnected) {
+		t.Errorf("Ping error = %v, want ErrDisconnected so the watcher reports degraded", err)
+	}
+}
+
+func TestClient_ReconnectUnblocksInFlightCall(t *testing.T) {
+	st := &serverTransport{blockCall: true}
+	st.setTools("alpha")
+	_, client, _ := newTestSupervisor(t, st)
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := client.CallTool(context.Background(), "alpha", nil)
+		errc <- err
+	}()
+	for client.callsInFlight() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := client.Reconnect(context.Background()); err != nil {
+		t.Fatalf("Reconnect: %v", err)
+	}
+	select {
+	case err := <-errc:
+		if !errors.Is(err, ErrDisconnected) {
+			t.Errorf("in-flight call error = %v, want ErrDisconnected", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("in-flight call still blocked after reconnect")
+	}
+}
+
+func TestSupervisor_RunRecoversFromOutage(t *testing.T) {
+	st := &serverTransport{}
+	st.setTools("alpha")
+	sup, client, registry := newTestSupervisor(t, st)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		sup.Run(ctx)
+		close(done)
+	}()
+
+	st.setDown(true)
+	waitFor(t, "client to report disconnected", func() bool {
+		return errors.Is(client.Ping(context.Background()), ErrDisconnected)
+	})
+
+	st.setTools("alpha", "beta")
+	st.setDown(false)
+	waitFor(t, "re-bridged tools", func() bool {
+		return client.Ping(context.Background()) == nil && registry.Get("mcp_srv_beta") != nil
+	})
+
+	cancel()
+	<-done
+}
+
+func TestSupervisor_RunStopsWhenClientClosed(t *testing.T) {
+	st := &serverTransport{}
+	st.setTools("alpha")
+	sup, client, _ := newTestSupervisor(t, st)
+
+	done := make(chan struct{})
+	go func() {
+		sup.Run(context.Background())
+		close(done)
+	}()
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the client was closed")
+	}
+}
+
+// waitFor polls cond until it holds or the test times out.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+}
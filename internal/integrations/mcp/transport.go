@@ -14,6 +14,8 @@ type Transport interface {
 	Notify(ctx context.Context, notif *Notification) error
 
 	// Close shuts down the transport and releases resources.
-	// For stdio transports this terminates the subprocess.
+	// For stdio transports this terminates the subprocess. A closed
+	// transport may be reused: the next request reconnects, which is
+	// how [Client.Reconnect] re-dials.
 	Close() error
 }
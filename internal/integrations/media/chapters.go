@@ -0,0 +1,133 @@
+package media
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Chapter is a titled section of a video, taken from the chapter
+// markers its source publishes.
+type Chapter struct {
+	Title string `json:"title"`
+
+	// Start is the chapter's start time as "MM:SS" or "H:MM:SS".
+	Start string `json:"start"`
+}
+
+// ytdlpChapter is one entry of the chapters list in yt-dlp's JSON
+// output. Times are in seconds.
+type ytdlpChapter struct {
+	StartTime float64 `json:"start_time"`
+	EndTime   float64 `json:"end_time"`
+	Title     string  `json:"title"`
+}
+
+// chapterHeadingRe matches the heading that opens each chapter of a
+// chaptered transcript, as written by [chapteredTranscript].
+var chapterHeadingRe = regexp.MustCompile(`^## \[(\d+:\d{2}(?::\d{2})?)\] (.+)$`)
+
+// chapterSection is one chapter of a chaptered transcript.
+type chapterSection struct {
+	Chapter
+	Text string
+}
+
+// chapteredTranscript cleans raw VTT content into transcript text. When
+// the video has at least two chapters, each chapter's paragraphs are
+// grouped under a "## [14:20] Title" heading, with paragraph breaks
+// forced at chapter boundaries; chapters without captions are left out.
+// Otherwise the result is [CleanVTTWithParagraphs].
+func chapteredTranscript(raw string, chapters []ytdlpChapter) string {
+	if len(chapters) < 2 {
+		return CleanVTTWithParagraphs(raw)
+	}
+	chapters = append([]ytdlpChapter(nil), chapters...)
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return chapters[i].StartTime < chapters[j].StartTime
+	})
+
+	startsMs := make([]int, len(chapters))
+	for i, ch := range chapters {
+		startsMs[i] = int(ch.StartTime * 1000)
+	}
+	paragraphs := vttParagraphs(raw, startsMs[1:])
+
+	grouped := make([][]string, len(chapters))
+	idx := 0
+	for _, p := range paragraphs {
+		for idx+1 < len(chapters) && p.StartMs >= startsMs[idx+1] {
+			idx++
+		}
+		grouped[idx] = append(grouped[idx], p.Text)
+	}
+
+	var sections []string
+	for i, ch := range chapters {
+		if len(grouped[i]) == 0 {
+			continue
+		}
+		sections = append(sections, chapterHeading(ch)+"\n\n"+strings.Join(grouped[i], "\n\n"))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// chapterHeading formats the heading line for a yt-dlp chapter.
+func chapterHeading(ch ytdlpChapter) string {
+	title := strings.Join(strings.Fields(ch.Title), " ")
+	if title == "" {
+		title = "Untitled"
+	}
+	return Chapter{Title: title, Start: formatDuration(ch.StartTime)}.heading()
+}
+
+// heading returns the line that opens the chapter in a chaptered
+// transcript or summary.
+func (ch Chapter) heading() string {
+	return fmt.Sprintf("## [%s] %s", ch.Start, ch.Title)
+}
+
+// transcriptChapters splits a transcript written by
+// [chapteredTranscript] into its chapters. It returns nil for a
+// transcript that does not open with a chapter heading.
+func transcriptChapters(transcript string) []chapterSection {
+	var sections []chapterSection
+	var body []string
+	flush := func() {
+		if len(sections) > 0 {
+			sections[len(sections)-1].Text = strings.TrimSpace(strings.Join(body, "\n"))
+		}
+		body = nil
+	}
+	for _, line := range strings.Split(transcript, "\n") {
+		if m := chapterHeadingRe.FindStringSubmatch(line); m != nil {
+			flush()
+			sections = append(sections, chapterSection{Chapter: Chapter{Start: m[1], Title: m[2]}})
+			continue
+		}
+		if len(sections) == 0 {
+			if strings.TrimSpace(line) != "" {
+				return nil
+			}
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+	return sections
+}
+
+// chapterList returns the chapters of a transcript written by
+// [chapteredTranscript], or nil when it has none.
+func chapterList(transcript string) []Chapter {
+	sections := transcriptChapters(transcript)
+	if len(sections) == 0 {
+		return nil
+	}
+	chapters := make([]Chapter, len(sections))
+	for i, s := range sections {
+		chapters[i] = s.Chapter
+	}
+	return chapters
+}
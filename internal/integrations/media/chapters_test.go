@@ -0,0 +1,185 @@
+package media
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// chapterVTT has captions running without a pause across the chapter
+// boundaries at 0:05 and 14:20, and one long gap inside the chapter
+// between them.
+const chapterVTT = `WEBVTT
+
+00:00:01.000 --> 00:00:03.000
+Welcome to the show.
+
+00:00:03.000 --> 00:00:05.500
+Today we talk about batteries.
+
+00:00:05.500 --> 00:00:07.000
+First, lithium chemistry.
+
+00:14:19.000 --> 00:14:20.500
+That covers chemistry.
+
+00:14:20.500 --> 00:14:22.000
+Now, recycling.`
+
+var testChapters = []ytdlpChapter{
+	{StartTime: 860, EndTime: 1200, Title: "Recycling"},
+	{StartTime: 0, EndTime: 5, Title: "Intro"},
+	{StartTime: 5, EndTime: 860, Title: "  Lithium\nchemistry "},
+}
+
+func TestChapteredTranscript(t *testing.T) {
+	got := chapteredTranscript(chapterVTT, testChapters)
+	want := "## [0:00] Intro\n\nWelcome to the show. Today we talk about batteries.\n\n" +
+		"## [0:05] Lithium chemistry\n\nFirst, lithium chemistry.\n\nThat covers chemistry.\n\n" +
+		"## [14:20] Recycling\n\nNow, recycling."
+	if got != want {
+		t.Errorf("chapteredTranscript:\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestChapteredTranscript_SkipsEmptyChapters(t *testing.T) {
+	chapters := append(slices.Clone(testChapters), ytdlpChapter{StartTime: 1800, Title: "Outro"})
+	got := chapteredTranscript(chapterVTT, chapters)
+	if strings.Contains(got, "Outro") {
+		t.Errorf("chapteredTranscript included a chapter without captions:\n%s", got)
+	}
+}
+
+func TestChapteredTranscript_NoChapters(t *testing.T) {
+	for _, chapters := range [][]ytdlpChapter{nil, testChapters[:1]} {
+		if got, want := chapteredTranscript(chapterVTT, chapters), CleanVTTWithParagraphs(chapterVTT); got != want {
+			t.Errorf("chapteredTranscript(%d chapters) = %q, want %q", len(chapters), got, want)
+		}
+	}
+}
+
+func TestTranscriptChapters(t *testing.T) {
+	sections := transcriptChapters(chapteredTranscript(chapterVTT, testChapters))
+	want := []chapterSection{
+		{Chapter: Chapter{Title: "Intro", Start: "0:00"}, Text: "Welcome to the show. Today we talk about batteries."},
+		{Chapter: Chapter{Title: "Lithium chemistry", Start: "0:05"}, Text: "First, lithium chemistry.\n\nThat covers chemistry."},
+		{Chapter: Chapter{Title: "Recycling", Start: "14:20"}, Text: "Now, recycling."},
+	}
+	if !slices.Equal(sections, want) {
+		t.Errorf("transcriptChapters = %+v, want %+v", sections, want)
+	}
+
+	if got := transcriptChapters("Plain transcript.\n\n## [1:00] Not a chapter"); got != nil {
+		t.Errorf("transcriptChapters(unchaptered) = %+v, want nil", got)
+	}
+}
+
+func TestFinishResult_ListsChapters(t *testing.T) {
+	c := &Client{cfg: Config{MaxTranscriptChars: 50000}, logger: slog.Default()}
+	result := c.finishResult(context.Background(), &Result{},
+		chapteredTranscript(chapterVTT, testChapters), "", DetailFull, "https://example.com")
+
+	want := []Chapter{
+		{Title: "Intro", Start: "0:00"},
+		{Title: "Lithium chemistry", Start: "0:05"},
+		{Title: "Recycling", Start: "14:20"},
+	}
+	if !slices.Equal(result.Chapters, want) {
+		t.Errorf("Chapters = %+v, want %+v", result.Chapters, want)
+	}
+	if !strings.Contains(result.Transcript, "## [14:20] Recycling") {
+		t.Errorf("transcript lacks chapter headings:\n%s", result.Transcript)
+	}
+}
+
+func TestSummarizeTranscript_Chapters(t *testing.T) {
+	var mu sync.Mutex
+	var mapPrompts []string
+	var reducePrompt string
+	c := &Client{
+		cfg:    Config{MaxTranscriptChars: 50000},
+		logger: slog.Default(),
+		summarize: func(_ context.Context, prompt string) (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if strings.Contains(prompt, "Combine these chapter summaries") {
+				reducePrompt = prompt
+				return "chaptered summary", nil
+			}
+			mapPrompts = append(mapPrompts, prompt)
+			for _, title := range []string{"Intro", "Lithium chemistry", "Recycling"} {
+				if strings.Contains(prompt, `"`+title+`"`) {
+					return "summary of " + title, nil
+				}
+			}
+			return "unexpected", nil
+		},
+	}
+
+	var reports []string
+	ctx := WithProgress(context.Background(), func(msg string) {
+		mu.Lock()
+		reports = append(reports, msg)
+		mu.Unlock()
+	})
+
+	got, err := c.summarizeTranscript(ctx, chapteredTranscript(chapterVTT, testChapters), "", DetailSummary)
+	if err != nil {
+		t.Fatalf("summarizeTranscript: %v", err)
+	}
+	if got != "chaptered summary" {
+		t.Errorf("summary = %q, want %q", got, "chaptered summary")
+	}
+	if len(mapPrompts) != 3 {
+		t.Fatalf("map calls = %d, want one per chapter (3)", len(mapPrompts))
+	}
+	wantCombined := "## [0:00] Intro\n\nsummary of Intro\n\n" +
+		"## [0:05] Lithium chemistry\n\nsummary of Lithium chemistry\n\n" +
+		"## [14:20] Recycling\n\nsummary of Recycling"
+	if !strings.Contains(reducePrompt, wantCombined) {
+		t.Errorf("reduce prompt lacks chapter summaries in order:\n%s", reducePrompt)
+	}
+	if reports[0] != "summarizing 3 chapters…" || reports[len(reports)-1] != "combining summaries…" {
+		t.Errorf("progress reports = %q", reports)
+	}
+}
+
+func TestSummarizeTranscript_LongChapterSplit(t *testing.T) {
+	var mu sync.Mutex
+	var mapPrompts []string
+	c := &Client{
+		cfg:    Config{MaxTranscriptChars: 50000},
+		logger: slog.Default(),
+		summarize: func(_ context.Context, prompt string) (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if !strings.Contains(prompt, "Combine these chapter summaries") {
+				mapPrompts = append(mapPrompts, prompt)
+			}
+			return "summary", nil
+		},
+	}
+
+	long := strings.Repeat("Long chapter sentence here. ", 300)
+	transcript := "## [0:00] Short\n\nA short chapter.\n\n" +
+		"## [2:00] Long\n\n" + long + "\n\n" + long
+
+	if _, err := c.summarizeTranscript(context.Background(), transcript, "", DetailBrief); err != nil {
+		t.Fatalf("summarizeTranscript: %v", err)
+	}
+	if len(mapPrompts) != 3 {
+		t.Fatalf("map calls = %d, want 3 (short chapter + long chapter in 2 parts)", len(mapPrompts))
+	}
+	var parts int
+	for _, p := range mapPrompts {
+		if strings.Contains(p, `"Long"`) && strings.Contains(p, "of 2)") {
+			parts++
+		}
+	}
+	if parts != 2 {
+		t.Errorf("long chapter prompts with part labels = %d, want 2", parts)
+	}
+}
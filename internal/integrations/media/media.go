@@ -3,8 +3,12 @@ package media
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"net/http"
 	"net/url"
@@ -46,7 +50,9 @@ type Config struct {
 	WhisperModel string
 
 	// TranscriptDir is the directory for durable transcript storage.
-	// Each transcript is saved as a markdown file with YAML frontmatter.
+	// Each transcript is saved as a markdown file with YAML frontmatter,
+	// one per video and subtitle language, and doubles as a cache: a
+	// later request for the same video and language is served from disk.
 	// If empty, transcripts are returned in-context only.
 	TranscriptDir string
 
@@ -64,20 +70,23 @@ type Client struct {
 
 // Result holds the fetched transcript and associated metadata.
 type Result struct {
-	Title            string `json:"title"`
-	Channel          string `json:"channel,omitempty"`
-	Duration         string `json:"duration,omitempty"`
-	UploadDate       string `json:"upload_date,omitempty"`
-	Description      string `json:"description,omitempty"`
-	Transcript       string `json:"transcript"`
-	Source           string `json:"source"`
-	ID               string `json:"id"`
-	TranscriptPath   string `json:"transcript_path,omitempty"`
-	Truncated        bool   `json:"truncated,omitempty"`
-	Summarized       bool   `json:"summarized,omitempty"`
-	DetailLevel      string `json:"detail_level,omitempty"`
-	Focus            string `json:"focus,omitempty"`
-	AnalysisGuidance string `json:"analysis_guidance,omitempty"`
+	Title            string    `json:"title"`
+	Channel          string    `json:"channel,omitempty"`
+	Duration         string    `json:"duration,omitempty"`
+	UploadDate       string    `json:"upload_date,omitempty"`
+	Description      string    `json:"description,omitempty"`
+	Chapters         []Chapter `json:"chapters,omitempty"`
+	Transcript       string    `json:"transcript"`
+	Source           string    `json:"source"`
+	ID               string    `json:"id"`
+	Language         string    `json:"language,omitempty"`
+	TranscriptPath   string    `json:"transcript_path,omitempty"`
+	Cached           bool      `json:"cached,omitempty"`
+	Truncated        bool      `json:"truncated,omitempty"`
+	Summarized       bool      `json:"summarized,omitempty"`
+	DetailLevel      string    `json:"detail_level,omitempty"`
+	Focus            string    `json:"focus,omitempty"`
+	AnalysisGuidance string    `json:"analysis_guidance,omitempty"`
 }
 
 // New creates a media transcript client. The yt-dlp binary path is
@@ -126,26 +135,45 @@ type ytdlpJSON struct {
 	UploadDate  string  `json:"upload_date"`
 	Description string  `json:"description"`
 	Extractor   string  `json:"extractor_key"`
+
+	Chapters []ytdlpChapter `json:"chapters"`
 }
 
 // GetTranscript fetches the transcript for the given media URL.
 // It prefers manual subtitles over auto-generated, and falls back to
 // Whisper transcription via Ollama when no subtitles are available.
 //
+// When a TranscriptDir is configured, a transcript already saved for
+// the same video and language is returned without contacting the
+// source. Saved transcripts never expire; forceRefresh skips the saved
+// copy and fetches again, replacing it (for livestreams or edited
+// uploads).
+//
 // The focus parameter, when non-empty, guides summarization to emphasize
 // content related to the topic. The detail parameter controls processing:
 // DetailFull returns the raw transcript, DetailSummary produces a map-reduce
 // summary, and DetailBrief produces an aggressive ~500-char summary.
-func (c *Client) GetTranscript(ctx context.Context, rawURL, language, focus string, detail DetailLevel) (*Result, error) {
+func (c *Client) GetTranscript(ctx context.Context, rawURL, language, focus string, detail DetailLevel, forceRefresh bool) (*Result, error) {
 	if rawURL == "" {
 		return nil, fmt.Errorf("media_transcript: url is required")
 	}
 	if language == "" {
 		language = c.cfg.SubtitleLanguage
 	}
+	language = strings.ToLower(strings.TrimSpace(language))
 	if detail == "" {
 		detail = DetailFull
 	}
+
+	source, id := extractSource(rawURL)
+	if !forceRefresh && id != "" {
+		if cached := c.loadTranscript(rawURL, source, id, language); cached != nil {
+			c.logger.Debug("transcript served from cache",
+				"url", rawURL, "path", cached.TranscriptPath)
+			return c.finishResult(ctx, cached, cached.Transcript, focus, detail, rawURL), nil
+		}
+	}
+
 	if c.cfg.YtDlpPath == "" {
 		return nil, fmt.Errorf("media_transcript: yt-dlp not found (install yt-dlp or set media.yt_dlp_path)")
 	}
@@ -158,13 +186,14 @@ func (c *Client) GetTranscript(ctx context.Context, rawURL, language, focus stri
 	defer os.RemoveAll(tmpDir)
 
 	// Run yt-dlp to fetch metadata and subtitles.
+	reportProgress(ctx, "downloading…")
 	meta, err := c.runYtDlp(ctx, rawURL, language, tmpDir)
 	if err != nil {
 		return nil, fmt.Errorf("media_transcript: yt-dlp: %w", err)
 	}
 
 	// Find subtitle file in tmpDir.
-	transcript, err := c.findAndCleanSubtitles(tmpDir, meta.ID, language)
+	transcript, err := c.findAndCleanSubtitles(tmpDir, meta.ID, language, meta.Chapters)
 	if err != nil {
 		c.logger.Warn("no subtitles found, attempting whisper fallback",
 			"url", rawURL, "error", err)
@@ -175,7 +204,6 @@ func (c *Client) GetTranscript(ctx context.Context, rawURL, language, focus stri
 	}
 
 	// Build result.
-	source, id := extractSource(rawURL)
 	if id == "" {
 		id = meta.ID
 	}
@@ -193,6 +221,7 @@ func (c *Client) GetTranscript(ctx context.Context, rawURL, language, focus stri
 		Description: desc,
 		Source:      source,
 		ID:          id,
+		Language:    language,
 	}
 
 	// Save the raw transcript to disk before any truncation or
@@ -210,7 +239,16 @@ func (c *Client) GetTranscript(ctx context.Context, rawURL, language, focus stri
 		}
 	}
 
-	// Apply summarization or truncation based on detail level.
+	return c.finishResult(ctx, result, rawTranscript, focus, detail, rawURL), nil
+}
+
+// finishResult fills result.Transcript from rawTranscript according to
+// the detail level: a summary when one is requested and a summarizer
+// is configured, otherwise the transcript truncated to
+// MaxTranscriptChars. It also lists the transcript's chapters.
+func (c *Client) finishResult(ctx context.Context, result *Result, rawTranscript, focus string, detail DetailLevel, rawURL string) *Result {
+	result.Chapters = chapterList(rawTranscript)
+
 	needsSummary := (detail == DetailSummary || detail == DetailBrief) && c.summarize != nil
 	if needsSummary {
 		summary, sumErr := c.summarizeTranscript(ctx, rawTranscript, focus, detail)
@@ -225,7 +263,7 @@ func (c *Client) GetTranscript(ctx context.Context, rawURL, language, focus stri
 			if focus != "" {
 				result.Focus = focus
 			}
-			return result, nil
+			return result
 		}
 	}
 
@@ -236,8 +274,7 @@ func (c *Client) GetTranscript(ctx context.Context, rawURL, language, focus stri
 		result.Truncated = true
 	}
 	result.Transcript = rawTranscript
-
-	return result, nil
+	return result
 }
 
 // runYtDlp executes yt-dlp and returns parsed metadata.
@@ -377,7 +414,9 @@ func (c *Client) checkCookieStatus(stderrOutput string) {
 
 // findAndCleanSubtitles looks for VTT subtitle files in the temp directory,
 // preferring manual subs in the requested language over auto-generated ones.
-func (c *Client) findAndCleanSubtitles(tmpDir, videoID, language string) (string, error) {
+// The cleaned text is grouped under chapter headings when the video has
+// chapters; see [chapteredTranscript].
+func (c *Client) findAndCleanSubtitles(tmpDir, videoID, language string, chapters []ytdlpChapter) (string, error) {
 	// yt-dlp names subtitle files with predictable patterns:
 	//   {id}.{lang}.vtt            — manual subtitles
 	//   {id}.{lang}.auto.vtt       — auto-generated (some versions)
@@ -426,7 +465,7 @@ func (c *Client) findAndCleanSubtitles(tmpDir, videoID, language string) (string
 		return "", fmt.Errorf("read subtitle file: %w", err)
 	}
 
-	cleaned := CleanVTTWithParagraphs(string(raw))
+	cleaned := chapteredTranscript(string(raw), chapters)
 	if strings.TrimSpace(cleaned) == "" {
 		return "", fmt.Errorf("subtitle file empty after cleaning")
 	}
@@ -440,25 +479,38 @@ func (c *Client) findAndCleanSubtitles(tmpDir, videoID, language string) (string
 	return cleaned, nil
 }
 
-// saveTranscript writes the transcript to disk as a markdown file with
-// YAML frontmatter. Returns the absolute path to the saved file.
-func (c *Client) saveTranscript(r *Result, originalURL string) (string, error) {
+// transcriptDir returns the configured transcript directory with a
+// leading ~ expanded.
+func (c *Client) transcriptDir() string {
 	dir := c.cfg.TranscriptDir
-
-	// Expand ~ to home directory.
 	if strings.HasPrefix(dir, "~/") {
 		home, err := os.UserHomeDir()
 		if err == nil {
 			dir = filepath.Join(home, dir[2:])
 		}
 	}
+	return dir
+}
 
-	if err := os.MkdirAll(dir, 0o755); err != nil {
+// transcriptPath returns where the transcript for a URL and subtitle
+// language is stored. The file is named by [transcriptKey], so
+// different URLs for the same platform video share one file.
+func (c *Client) transcriptPath(rawURL, source, id, language string) string {
+	name := source + "-" + transcriptKey(rawURL, source, id)
+	if language != "" {
+		name += "." + language
+	}
+	return filepath.Join(c.transcriptDir(), sanitizeFilename(name)+".md")
+}
+
+// saveTranscript writes the transcript to disk as a markdown file with
+// YAML frontmatter. Returns the absolute path to the saved file.
+func (c *Client) saveTranscript(r *Result, originalURL string) (string, error) {
+	if err := os.MkdirAll(c.transcriptDir(), 0o755); err != nil {
 		return "", fmt.Errorf("create transcript dir: %w", err)
 	}
 
-	filename := sanitizeFilename(r.Source + "-" + r.ID)
-	path := filepath.Join(dir, filename+".md")
+	path := c.transcriptPath(originalURL, r.Source, r.ID, r.Language)
 
 	var buf strings.Builder
 	buf.WriteString("---\n")
@@ -468,12 +520,19 @@ func (c *Client) saveTranscript(r *Result, originalURL string) (string, error) {
 	}
 	buf.WriteString(fmt.Sprintf("url: %s\n", originalURL))
 	buf.WriteString(fmt.Sprintf("source: %s\n", r.Source))
+	buf.WriteString(fmt.Sprintf("id: %q\n", r.ID))
+	if r.Language != "" {
+		buf.WriteString(fmt.Sprintf("language: %s\n", r.Language))
+	}
 	if r.UploadDate != "" {
 		buf.WriteString(fmt.Sprintf("date: %s\n", r.UploadDate))
 	}
 	if r.Duration != "" {
 		buf.WriteString(fmt.Sprintf("duration: %q\n", r.Duration))
 	}
+	if r.Description != "" {
+		buf.WriteString(fmt.Sprintf("description: %q\n", r.Description))
+	}
 	buf.WriteString(fmt.Sprintf("fetched_at: %s\n", time.Now().UTC().Format(time.RFC3339)))
 	buf.WriteString("---\n\n")
 	buf.WriteString(r.Transcript)
@@ -486,6 +545,70 @@ func (c *Client) saveTranscript(r *Result, originalURL string) (string, error) {
 	return path, nil
 }
 
+// loadTranscript returns the transcript saved by [Client.saveTranscript]
+// for a URL and subtitle language, or nil when there is none. The
+// source and ID come from [extractSource]. A file that cannot be read
+// or parsed is treated as absent, so the transcript is fetched again
+// and the file replaced.
+func (c *Client) loadTranscript(rawURL, source, id, language string) *Result {
+	if c.cfg.TranscriptDir == "" {
+		return nil
+	}
+	path := c.transcriptPath(rawURL, source, id, language)
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			c.logger.Warn("cached transcript unreadable", "path", path, "error", err)
+		}
+		return nil
+	}
+
+	rest, ok := strings.CutPrefix(string(raw), "---\n")
+	var front, body string
+	if ok {
+		front, body, ok = strings.Cut(rest, "\n---\n")
+	}
+	if !ok {
+		c.logger.Warn("cached transcript has no frontmatter", "path", path)
+		return nil
+	}
+	fields := make(map[string]string)
+	for _, line := range strings.Split(front, "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				c.logger.Warn("cached transcript has malformed frontmatter",
+					"path", path, "field", key, "error", err)
+				return nil
+			}
+			value = unquoted
+		}
+		fields[key] = value
+	}
+
+	transcript := strings.TrimSuffix(strings.TrimPrefix(body, "\n"), "\n")
+	if strings.TrimSpace(transcript) == "" {
+		return nil
+	}
+	return &Result{
+		Title:          fields["title"],
+		Channel:        fields["channel"],
+		Duration:       fields["duration"],
+		UploadDate:     fields["date"],
+		Description:    fields["description"],
+		Transcript:     transcript,
+		Source:         source,
+		ID:             id,
+		Language:       language,
+		TranscriptPath: path,
+		Cached:         true,
+	}
+}
+
 // extractSource parses a URL to determine the source platform and video ID.
 func extractSource(rawURL string) (source, id string) {
 	u, err := url.Parse(rawURL)
@@ -503,12 +626,25 @@ func extractSource(rawURL string) (source, id string) {
 		} else {
 			id = u.Query().Get("v")
 		}
+		if id == "" {
+			// Shorts, live, and embed URLs carry the ID in the path.
+			for _, prefix := range []string{"/shorts/", "/live/", "/embed/"} {
+				if rest, ok := strings.CutPrefix(u.Path, prefix); ok {
+					id = strings.Trim(rest, "/")
+					break
+				}
+			}
+		}
 	case strings.Contains(host, "vimeo.com"):
 		source = "vimeo"
 		id = strings.TrimPrefix(u.Path, "/")
 	case strings.Contains(host, "twitch.tv"):
 		source = "twitch"
-		id = strings.TrimPrefix(u.Path, "/videos/")
+		// Only past broadcasts have a stable ID. A channel URL names
+		// whatever is live right now, so it gets none.
+		if rest, ok := strings.CutPrefix(u.Path, "/videos/"); ok {
+			id = strings.Trim(rest, "/")
+		}
 	default:
 		source = strings.TrimPrefix(host, "www.")
 		// Use last path segment as ID.
@@ -521,6 +657,27 @@ func extractSource(rawURL string) (source, id string) {
 	return source, id
 }
 
+// transcriptKey returns the name under which the transcript for rawURL
+// is saved. YouTube, Vimeo, and Twitch IDs identify a video whatever URL
+// names it, so they are used as is. On other hosts the last path
+// segment need not identify anything (…/episode?id=1 and ?id=2 share
+// it), so the key is a hash of the whole URL, query included.
+func transcriptKey(rawURL, source, id string) string {
+	switch source {
+	case "youtube", "vimeo", "twitch":
+		return id
+	}
+	canonical := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		u.Host = strings.ToLower(u.Host)
+		u.Fragment = ""
+		u.RawQuery = u.Query().Encode()
+		canonical = u.String()
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:8])
+}
+
 // sanitizeFilename replaces characters that are unsafe in filenames.
 var unsafeFilenameRe = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
 
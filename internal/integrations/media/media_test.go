@@ -26,6 +26,11 @@ func TestExtractSource(t *testing.T) {
 			wantSource: "youtube",
 			wantID:     "dQw4w9WgXcQ",
 		},
+		{
+			url:        "https://www.youtube.com/shorts/dQw4w9WgXcQ",
+			wantSource: "youtube",
+			wantID:     "dQw4w9WgXcQ",
+		},
 		{
 			url:        "https://vimeo.com/123456789",
 			wantSource: "vimeo",
@@ -36,6 +41,11 @@ func TestExtractSource(t *testing.T) {
 			wantSource: "twitch",
 			wantID:     "987654321",
 		},
+		{
+			url:        "https://www.twitch.tv/somechannel",
+			wantSource: "twitch",
+			wantID:     "",
+		},
 		{
 			url:        "https://example.com/podcasts/episode-42",
 			wantSource: "example.com",
@@ -230,6 +240,123 @@ func TestSaveTranscript_TildeExpansion(t *testing.T) {
 	}
 }
 
+func TestLoadTranscript_RoundTrip(t *testing.T) {
+	c := &Client{
+		cfg:    Config{TranscriptDir: t.TempDir()},
+		logger: slog.Default(),
+	}
+
+	saved := &Result{
+		Title:       `A "quoted" title`,
+		Channel:     "Test Channel",
+		Duration:    "1:02:03",
+		UploadDate:  "2026-02-22",
+		Description: "line one\nline two",
+		Source:      "youtube",
+		ID:          "abc123",
+		Language:    "en",
+		Transcript:  "First paragraph.\n\nSecond paragraph.",
+	}
+	path, err := c.saveTranscript(saved, "https://youtu.be/abc123")
+	if err != nil {
+		t.Fatalf("saveTranscript() error: %v", err)
+	}
+	if got := filepath.Base(path); got != "youtube-abc123.en.md" {
+		t.Errorf("filename = %q, want %q", got, "youtube-abc123.en.md")
+	}
+
+	got := c.loadTranscript("https://youtu.be/abc123", "youtube", "abc123", "en")
+	if got == nil {
+		t.Fatal("loadTranscript() = nil, want saved transcript")
+	}
+	if got.Title != saved.Title || got.Channel != saved.Channel ||
+		got.Duration != saved.Duration || got.UploadDate != saved.UploadDate ||
+		got.Description != saved.Description {
+		t.Errorf("metadata = %+v, want %+v", got, saved)
+	}
+	if got.Transcript != saved.Transcript {
+		t.Errorf("transcript = %q, want %q", got.Transcript, saved.Transcript)
+	}
+	if !got.Cached || got.TranscriptPath != path {
+		t.Errorf("Cached = %v, TranscriptPath = %q; want true, %q", got.Cached, got.TranscriptPath, path)
+	}
+
+	if other := c.loadTranscript("https://youtu.be/abc123", "youtube", "abc123", "de"); other != nil {
+		t.Errorf("loadTranscript(de) = %+v, want nil for another language", other)
+	}
+}
+
+func TestTranscriptKey(t *testing.T) {
+	// Platform IDs are shared by every URL for the video.
+	if a, b := transcriptKey("https://youtu.be/abc123", "youtube", "abc123"),
+		transcriptKey("https://www.youtube.com/watch?v=abc123&t=42", "youtube", "abc123"); a != b || a != "abc123" {
+		t.Errorf("youtube keys = %q, %q; want both abc123", a, b)
+	}
+
+	// Generic hosts are keyed by the whole URL, query included.
+	one := transcriptKey("https://example.com/episode?id=1", "example.com", "episode")
+	two := transcriptKey("https://example.com/episode?id=2", "example.com", "episode")
+	if one == two {
+		t.Errorf("keys for ?id=1 and ?id=2 both %q, want distinct", one)
+	}
+	if same := transcriptKey("https://EXAMPLE.com/episode?id=1#t=5", "example.com", "episode"); same != one {
+		t.Errorf("key with host case and fragment = %q, want %q", same, one)
+	}
+}
+
+func TestLoadTranscript_Malformed(t *testing.T) {
+	dir := t.TempDir()
+	c := &Client{
+		cfg:    Config{TranscriptDir: dir},
+		logger: slog.Default(),
+	}
+	path := filepath.Join(dir, "youtube-abc123.en.md")
+	if err := os.WriteFile(path, []byte("no frontmatter here\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.loadTranscript("https://youtu.be/abc123", "youtube", "abc123", "en"); got != nil {
+		t.Errorf("loadTranscript() = %+v, want nil for a file without frontmatter", got)
+	}
+}
+
+func TestGetTranscript_Cache(t *testing.T) {
+	c := New(Config{TranscriptDir: t.TempDir(), MaxTranscriptChars: 10}, slog.Default())
+	// No yt-dlp: any request that misses the cache fails.
+	c.cfg.YtDlpPath = ""
+
+	saved := &Result{
+		Title:      "Cached Video",
+		Duration:   "5:30",
+		Source:     "youtube",
+		ID:         "abc123",
+		Language:   "en",
+		Transcript: "0123456789abcdef",
+	}
+	if _, err := c.saveTranscript(saved, "https://www.youtube.com/watch?v=abc123"); err != nil {
+		t.Fatalf("saveTranscript() error: %v", err)
+	}
+
+	// A different URL for the same video, in the default language.
+	got, err := c.GetTranscript(context.Background(), "https://youtu.be/abc123?t=42", "", "", DetailFull, false)
+	if err != nil {
+		t.Fatalf("GetTranscript() error: %v", err)
+	}
+	if !got.Cached || got.Title != "Cached Video" || got.Duration != "5:30" {
+		t.Errorf("result = %+v, want cached metadata", got)
+	}
+	if got.Transcript != "0123456789" || !got.Truncated {
+		t.Errorf("transcript = %q (truncated %v), want first 10 chars truncated", got.Transcript, got.Truncated)
+	}
+
+	if _, err := c.GetTranscript(context.Background(), "https://youtu.be/abc123", "EN", "", DetailFull, true); err == nil ||
+		!strings.Contains(err.Error(), "yt-dlp not found") {
+		t.Errorf("GetTranscript(force_refresh) error = %v, want a fetch attempt", err)
+	}
+	if _, err := c.GetTranscript(context.Background(), "https://youtu.be/abc123", "fr", "", DetailFull, false); err == nil {
+		t.Error("GetTranscript(fr) succeeded, want a cache miss for another language")
+	}
+}
+
 func TestResultTruncation(t *testing.T) {
 	// Verify that transcripts longer than MaxTranscriptChars get truncated.
 	longText := strings.Repeat("a", 100)
@@ -0,0 +1,26 @@
+package media
+
+import (
+	"context"
+	"fmt"
+)
+
+type progressKey struct{}
+
+// WithProgress attaches a progress callback to ctx. [Client.GetTranscript]
+// reports its phases ("downloading…", "summarizing chunk 3/12…") through
+// it so a caller can relay live status for long fetches.
+func WithProgress(ctx context.Context, fn func(msg string)) context.Context {
+	if fn == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// reportProgress sends a formatted status message to the callback
+// attached by [WithProgress], if any.
+func reportProgress(ctx context.Context, format string, args ...any) {
+	if fn, ok := ctx.Value(progressKey{}).(func(string)); ok {
+		fn(fmt.Sprintf(format, args...))
+	}
+}
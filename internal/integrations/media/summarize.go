@@ -205,14 +205,21 @@ func hasOversizedChunk(chunks []string, limit int) bool {
 }
 
 // summarizeTranscript runs the map-reduce pipeline on the given transcript.
-// Each chunk is summarized in parallel (capped at maxParallelChunks), then
-// the chunk summaries are combined in a reduce step. The focus string, when
-// non-empty, guides both phases to emphasize relevant content.
+// A chaptered transcript (see [chapteredTranscript]) is summarized per
+// chapter by [Client.summarizeChapters]; any other is split into chunks of
+// about defaultChunkSize. Each chunk is summarized in parallel (capped at
+// maxParallelChunks), then the chunk summaries are combined in a reduce
+// step. The focus string, when non-empty, guides both phases to emphasize
+// relevant content.
 func (c *Client) summarizeTranscript(ctx context.Context, transcript, focus string, detail DetailLevel) (string, error) {
 	if c.summarize == nil {
 		return "", fmt.Errorf("summarizer not configured")
 	}
 
+	if sections := transcriptChapters(transcript); len(sections) > 1 {
+		return c.summarizeChapters(ctx, sections, focus, detail)
+	}
+
 	chunks := chunkTranscript(transcript, defaultChunkSize)
 	if len(chunks) == 0 {
 		return "", fmt.Errorf("no content to summarize")
@@ -224,18 +231,86 @@ func (c *Client) summarizeTranscript(ctx context.Context, transcript, focus stri
 		"has_focus", focus != "",
 	)
 
-	// Map phase: summarize each chunk in parallel.
+	mapPrompts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		mapPrompts[i] = prompts.TranscriptChunkSummaryPrompt(chunk, focus, i+1, len(chunks))
+	}
+	reportProgress(ctx, "summarizing %d chunks…", len(chunks))
+	summaries, err := c.mapSummaries(ctx, mapPrompts, "chunk")
+	if err != nil {
+		return "", fmt.Errorf("map phase: %w", err)
+	}
+
+	// Reduce phase: combine chunk summaries into a final result.
+	combined := strings.Join(summaries, "\n\n---\n\n")
+	return c.reduceSummaries(ctx, prompts.TranscriptReducePrompt(combined, focus, string(detail)), len(combined), detail)
+}
+
+// summarizeChapters is the map-reduce pipeline for a chaptered
+// transcript. The map phase summarizes each chapter, split into parts
+// of about defaultChunkSize when it is longer; the reduce phase combines
+// the summaries under their chapter headings, so the result keeps the
+// chapter structure and start times.
+func (c *Client) summarizeChapters(ctx context.Context, sections []chapterSection, focus string, detail DetailLevel) (string, error) {
+	var mapPrompts []string
+	var owners []int
+	for i, sec := range sections {
+		parts := chunkTranscript(sec.Text, defaultChunkSize)
+		for j, part := range parts {
+			mapPrompts = append(mapPrompts, prompts.TranscriptChapterSummaryPrompt(part, focus, sec.Title, sec.Start, j+1, len(parts)))
+			owners = append(owners, i)
+		}
+	}
+	if len(mapPrompts) == 0 {
+		return "", fmt.Errorf("no content to summarize")
+	}
+
+	c.logger.Info("starting chapter summarization",
+		"chapters", len(sections),
+		"sections", len(mapPrompts),
+		"detail", string(detail),
+		"has_focus", focus != "",
+	)
+
+	reportProgress(ctx, "summarizing %d chapters…", len(sections))
+	summaries, err := c.mapSummaries(ctx, mapPrompts, "section")
+	if err != nil {
+		return "", fmt.Errorf("map phase: %w", err)
+	}
+
+	byChapter := make([][]string, len(sections))
+	for i, summary := range summaries {
+		byChapter[owners[i]] = append(byChapter[owners[i]], summary)
+	}
+	var blocks []string
+	for i, sec := range sections {
+		if len(byChapter[i]) == 0 {
+			continue
+		}
+		blocks = append(blocks, sec.heading()+"\n\n"+strings.Join(byChapter[i], "\n\n"))
+	}
+	combined := strings.Join(blocks, "\n\n")
+	return c.reduceSummaries(ctx, prompts.TranscriptChapterReducePrompt(combined, focus, string(detail)), len(combined), detail)
+}
+
+// mapSummaries sends each prompt to the summarizer in parallel, capped
+// at maxParallelChunks, and returns the responses in prompt order. The
+// first failure cancels the rest. unit names a prompt in progress
+// reports and errors ("chunk", "section").
+func (c *Client) mapSummaries(ctx context.Context, mapPrompts []string, unit string) ([]string, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	summaries := make([]string, len(chunks))
+	summaries := make([]string, len(mapPrompts))
+	var progressMu sync.Mutex
+	completed := 0
 	sem := make(chan struct{}, maxParallelChunks)
-	errs := make(chan error, len(chunks))
+	errs := make(chan error, len(mapPrompts))
 	var wg sync.WaitGroup
 
-	for i, chunk := range chunks {
+	for i, prompt := range mapPrompts {
 		wg.Add(1)
-		go func(idx int, text string) {
+		go func(idx int, prompt string) {
 			defer wg.Done()
 
 			// Acquire semaphore slot.
@@ -247,15 +322,19 @@ func (c *Client) summarizeTranscript(ctx context.Context, transcript, focus stri
 				return
 			}
 
-			prompt := prompts.TranscriptChunkSummaryPrompt(text, focus, idx+1, len(chunks))
 			result, err := c.summarize(ctx, prompt)
 			if err != nil {
-				errs <- fmt.Errorf("chunk %d: %w", idx+1, err)
+				errs <- fmt.Errorf("%s %d: %w", unit, idx+1, err)
 				cancel()
 				return
 			}
 			summaries[idx] = result
-		}(i, chunk)
+
+			progressMu.Lock()
+			completed++
+			reportProgress(ctx, "summarized %s %d/%d…", unit, completed, len(mapPrompts))
+			progressMu.Unlock()
+		}(i, prompt)
 	}
 
 	wg.Wait()
@@ -263,15 +342,17 @@ func (c *Client) summarizeTranscript(ctx context.Context, transcript, focus stri
 
 	// Check for the first error.
 	if err := <-errs; err != nil {
-		return "", fmt.Errorf("map phase: %w", err)
+		return nil, err
 	}
+	return summaries, nil
+}
 
-	// Reduce phase: combine chunk summaries into a final result.
-	combined := strings.Join(summaries, "\n\n---\n\n")
-	reducePrompt := prompts.TranscriptReducePrompt(combined, focus, string(detail))
-
+// reduceSummaries runs the reduce phase with the given prompt.
+// combinedLen is the length of the summaries it combines, for logging.
+func (c *Client) reduceSummaries(ctx context.Context, reducePrompt string, combinedLen int, detail DetailLevel) (string, error) {
+	reportProgress(ctx, "combining summaries…")
 	c.logger.Info("running reduce phase",
-		"combined_length", len(combined),
+		"combined_length", combinedLen,
 		"detail", string(detail),
 	)
 
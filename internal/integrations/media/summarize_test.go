@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 )
@@ -423,3 +425,40 @@ func TestSummarizeTranscript_ContextCancellation(t *testing.T) {
 		t.Fatal("expected error for cancelled context")
 	}
 }
+
+func TestSummarizeTranscript_ReportsProgress(t *testing.T) {
+	c := &Client{
+		cfg:    Config{MaxTranscriptChars: 50000},
+		logger: slog.Default(),
+		summarize: func(context.Context, string) (string, error) {
+			return "summary", nil
+		},
+	}
+
+	var mu sync.Mutex
+	var reports []string
+	ctx := WithProgress(context.Background(), func(msg string) {
+		mu.Lock()
+		reports = append(reports, msg)
+		mu.Unlock()
+	})
+
+	paragraphs := make([]string, 3)
+	for i := range paragraphs {
+		paragraphs[i] = strings.Repeat(fmt.Sprintf("Paragraph %d content. ", i+1), 300)
+	}
+	if _, err := c.summarizeTranscript(ctx, strings.Join(paragraphs, "\n\n"), "", DetailSummary); err != nil {
+		t.Fatalf("summarizeTranscript: %v", err)
+	}
+
+	want := []string{
+		"summarizing 3 chunks…",
+		"summarized chunk 1/3…",
+		"summarized chunk 2/3…",
+		"summarized chunk 3/3…",
+		"combining summaries…",
+	}
+	if !slices.Equal(reports, want) {
+		t.Errorf("progress reports = %q, want %q", reports, want)
+	}
+}
@@ -20,6 +20,7 @@ func ToolHandler(c *Client) func(ctx context.Context, args map[string]any) (stri
 		language, _ := args["language"].(string)
 		focus, _ := args["focus"].(string)
 		detailStr, _ := args["detail"].(string)
+		forceRefresh, _ := args["force_refresh"].(bool)
 
 		trustZone, _ := args["trust_zone"].(string)
 		if trustZone == "" {
@@ -41,7 +42,7 @@ func ToolHandler(c *Client) func(ctx context.Context, args map[string]any) (stri
 			return "", fmt.Errorf("media_transcript: invalid detail level %q (use full, summary, or brief)", detailStr)
 		}
 
-		result, err := c.GetTranscript(ctx, rawURL, language, focus, detail)
+		result, err := c.GetTranscript(ctx, rawURL, language, focus, detail, forceRefresh)
 		if err != nil {
 			return "", err
 		}
@@ -83,7 +84,11 @@ func ToolDefinition() map[string]any {
 			"detail": map[string]any{
 				"type":        "string",
 				"enum":        []string{"full", "summary", "brief"},
-				"description": "Detail level: \"full\" returns the raw transcript (default), \"summary\" produces a map-reduce summary (~2-3K chars), \"brief\" produces a very concise summary (~500 chars).",
+				"description": "Detail level: \"full\" returns the raw transcript (default), \"summary\" produces a map-reduce summary (~2-3K chars), \"brief\" produces a very concise summary (~500 chars). For videos with chapters, the transcript and summaries are organized under \"## [MM:SS] Title\" chapter headings, and the chapters are listed in the result.",
+			},
+			"force_refresh": map[string]any{
+				"type":        "boolean",
+				"description": "Fetch the transcript again even if a saved copy exists for this video and language. Use for livestreams or uploads edited since the last fetch.",
 			},
 			"trust_zone": map[string]any{
 				"type":        "string",
@@ -92,37 +92,73 @@ func CleanVTT(raw string) string {
 // exceeds 2 seconds. This produces more readable output for long
 // transcripts where topic shifts align with speaker pauses.
 func CleanVTTWithParagraphs(raw string) string {
+	paragraphs := vttParagraphs(raw, nil)
+	texts := make([]string, len(paragraphs))
+	for i, p := range paragraphs {
+		texts[i] = p.Text
+	}
+	return strings.TrimSpace(strings.Join(texts, "\n\n"))
+}
+
+// timedParagraph is a paragraph of cleaned caption text and the start
+// time of its first cue.
+type timedParagraph struct {
+	StartMs int
+	Text    string
+}
+
+// vttParagraphs cleans raw VTT content into paragraphs, breaking on
+// cue gaps over 2 seconds as [CleanVTTWithParagraphs] does. It also
+// breaks before the first cue starting at or after each of breaksMs,
+// which must be ascending, so no paragraph spans one of those times.
+func vttParagraphs(raw string, breaksMs []int) []timedParagraph {
 	if raw == "" {
-		return ""
+		return nil
 	}
 
 	lines := strings.Split(raw, "\n")
-	var paragraphs []string
+	var paragraphs []timedParagraph
 	var currentPara []string
 	prevLine := ""
 	prevEndMs := 0
 	currentStartMs := 0
+	paraStartMs := 0
+	nextBreak := 0
 
 	const paragraphGapMs = 2000
 
+	flush := func() {
+		if len(currentPara) > 0 {
+			paragraphs = append(paragraphs, timedParagraph{
+				StartMs: paraStartMs,
+				Text:    strings.Join(currentPara, " "),
+			})
+			currentPara = nil
+		}
+	}
+
 	for _, line := range lines {
 		line = strings.TrimRight(line, "\r")
 
-		// Parse timing lines to detect gaps.
+		// Parse timing lines to detect gaps and breaks.
 		if timingLineRe.MatchString(line) {
 			times := strings.SplitN(line, "-->", 2)
 			if len(times) == 2 {
 				startMs := parseTimestampMs(strings.TrimSpace(times[0]))
 				endMs := parseTimestampMs(strings.TrimSpace(strings.Fields(times[1])[0]))
 
+				crossed := false
+				for nextBreak < len(breaksMs) && startMs >= breaksMs[nextBreak] {
+					nextBreak++
+					crossed = true
+				}
+
 				// Check for paragraph-worthy gap.
-				if prevEndMs > 0 && startMs-prevEndMs > paragraphGapMs && len(currentPara) > 0 {
-					paragraphs = append(paragraphs, strings.Join(currentPara, " "))
-					currentPara = nil
+				if crossed || (prevEndMs > 0 && startMs-prevEndMs > paragraphGapMs) {
+					flush()
 				}
 
 				currentStartMs = startMs
-				_ = currentStartMs // used for future gap analysis
 				prevEndMs = endMs
 			}
 			continue
@@ -144,16 +180,17 @@ func CleanVTTWithParagraphs(raw string) string {
 			continue
 		}
 
+		if len(currentPara) == 0 {
+			paraStartMs = currentStartMs
+		}
 		currentPara = append(currentPara, line)
 		prevLine = line
 	}
 
 	// Flush last paragraph.
-	if len(currentPara) > 0 {
-		paragraphs = append(paragraphs, strings.Join(currentPara, " "))
-	}
+	flush()
 
-	return strings.TrimSpace(strings.Join(paragraphs, "\n\n"))
+	return paragraphs
 }
 
 // parseTimestampMs parses a VTT timestamp "HH:MM:SS.mmm" into milliseconds.
@@ -0,0 +1,169 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBraveAnswerFromInfobox(t *testing.T) {
+	var br braveResponse
+	raw := `{
+		"web": {"results": [{"title": "Eiffel Tower - Wikipedia", "url": "https://en.wikipedia.org/wiki/Eiffel_Tower", "description": "..."}]},
+		"infobox": {"results": [{"title": "Eiffel Tower", "url": "https://en.wikipedia.org/wiki/Eiffel_Tower", "description": "Tower in Paris", "long_desc": "The Eiffel Tower is 330 metres tall."}]}
+	}`
+	if err := json.Unmarshal([]byte(raw), &br); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	got := NewBrave("key").answer(br)
+	if got == nil {
+		t.Fatal("answer = nil, want the infobox")
+	}
+	if got.Text != "The Eiffel Tower is 330 metres tall." || got.Title != "Eiffel Tower" || got.Source != "brave" {
+		t.Errorf("answer = %+v", got)
+	}
+
+	if got := NewBrave("key").answer(braveResponse{}); got != nil {
+		t.Errorf("answer without infobox = %+v, want nil", got)
+	}
+}
+
+func TestSearXNGAnswer(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		wantText string
+		wantURL  string
+	}{
+		{
+			name:     "string answers",
+			raw:      `{"answers": ["42"], "infoboxes": [{"infobox": "Life", "content": "ignored"}]}`,
+			wantText: "42",
+		},
+		{
+			name:     "object answers",
+			raw:      `{"answers": [{"answer": "1 USD = 0.92 EUR", "url": "https://example.com/fx"}]}`,
+			wantText: "1 USD = 0.92 EUR",
+			wantURL:  "https://example.com/fx",
+		},
+		{
+			name:     "infobox fallback",
+			raw:      `{"answers": [], "infoboxes": [{"infobox": "Go", "id": "https://go.dev", "content": "Go is a programming language."}]}`,
+			wantText: "Go is a programming language.",
+			wantURL:  "https://go.dev",
+		},
+		{
+			name: "no answer",
+			raw:  `{"results": [{"title": "x", "url": "https://x"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sr searxngResponse
+			if err := json.Unmarshal([]byte(tt.raw), &sr); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			got := NewSearXNG("http://searx").answer(sr)
+			if tt.wantText == "" {
+				if got != nil {
+					t.Errorf("answer = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.Text != tt.wantText || got.URL != tt.wantURL || got.Source != "searxng" {
+				t.Errorf("answer = %+v, want text %q url %q", got, tt.wantText, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestToolHandler_SurfacesInstantAnswer(t *testing.T) {
+	mgr := NewManager("stub")
+	mgr.Register(&mockProvider{
+		name:    "stub",
+		answer:  &Answer{Text: "330 metres", Title: "Eiffel Tower", Source: "stub"},
+		results: []Result{{Title: "Eiffel Tower", URL: "https://example.com"}},
+	})
+
+	out, err := ToolHandler(mgr)(context.Background(), map[string]any{"query": "how tall is the eiffel tower"})
+	if err != nil {
+		t.Fatalf("ToolHandler: %v", err)
+	}
+	if !strings.HasPrefix(out, `{"answer":{"text":"330 metres"`) {
+		t.Errorf("output should lead with the answer:\n%s", out)
+	}
+	var resp Response
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp.Answer == nil || resp.Answer.Source != "stub" || len(resp.Results) != 1 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestToolHandler_ShapeIsStableWithoutAnswer(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		results []Result
+	}{
+		{name: "results", results: []Result{{Title: "A", URL: "https://a.example"}}},
+		{name: "no results"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr := NewManager("stub")
+			mgr.Register(&mockProvider{name: "stub", results: tt.results})
+
+			out, err := ToolHandler(mgr)(context.Background(), map[string]any{"query": "anything"})
+			if err != nil {
+				t.Fatalf("ToolHandler: %v", err)
+			}
+			var obj map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(out), &obj); err != nil {
+				t.Fatalf("output is not a JSON object: %v\n%s", err, out)
+			}
+			if _, ok := obj["answer"]; ok {
+				t.Errorf("answer present without an instant answer:\n%s", out)
+			}
+			var results []Result
+			if err := json.Unmarshal(obj["results"], &results); err != nil || results == nil || len(results) != len(tt.results) {
+				t.Errorf("results = %s, want an array of %d", obj["results"], len(tt.results))
+			}
+		})
+	}
+}
+
+func TestToolHandler_ReportsPagingPosition(t *testing.T) {
+	mgr := NewManager("stub")
+	mgr.Register(&mockProvider{name: "stub", results: []Result{{Title: "A", URL: "https://a.example"}}})
+
+	out, err := ToolHandler(mgr)(context.Background(), map[string]any{"query": "anything"})
+	if err != nil {
+		t.Fatalf("ToolHandler: %v", err)
+	}
+	var res toolResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out)
+	}
+	if res.Offset != 0 || res.NextOffset != 1 || len(res.Results) != 1 || res.Results[0].Title != "A" {
+		t.Errorf("result = %+v, want offset 0, next_offset 1", res)
+	}
+}
+
+func TestToolHandler_ExhaustedHasNoNextOffset(t *testing.T) {
+	mgr := NewManager("duckduckgo")
+	mgr.Register(NewDuckDuckGo(nil))
+
+	// DuckDuckGo cannot page; a non-zero offset must not re-run page one.
+	out, err := ToolHandler(mgr)(context.Background(), map[string]any{"query": "anything", "offset": float64(5)})
+	if err != nil {
+		t.Fatalf("ToolHandler: %v", err)
+	}
+	var res toolResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out)
+	}
+	if res.Offset != 5 || len(res.Results) != 0 || res.NextOffset != 0 || !strings.Contains(res.Note, "No further results") {
+		t.Errorf("result = %+v, want an empty exhausted page at offset 5", res)
+	}
+}
@@ -12,16 +12,22 @@ import (
 	"github.com/nugget/thane-ai-agent/internal/platform/httpkit"
 )
 
+// braveMaxPage is the largest page index Brave's offset parameter
+// accepts; offset counts pages of count results, not results.
+const braveMaxPage = 9
+
 // Brave implements the Provider interface for the Brave Search API.
 type Brave struct {
 	apiKey     string
+	baseURL    string
 	httpClient *http.Client
 }
 
 // NewBrave creates a Brave Search provider.
 func NewBrave(apiKey string) *Brave {
 	return &Brave{
-		apiKey: apiKey,
+		apiKey:  apiKey,
+		baseURL: "https://api.search.brave.com/res/v1/web/search",
 		httpClient: httpkit.NewClient(
 			httpkit.WithTimeout(15 * time.Second),
 		),
@@ -35,6 +41,18 @@ type braveResponse struct {
 	Web struct {
 		Results []braveResult `json:"results"`
 	} `json:"web"`
+	Infobox struct {
+		Results []braveInfobox `json:"results"`
+	} `json:"infobox"`
+}
+
+// braveInfobox is one entry in Brave's infobox, the knowledge panel
+// shown for entity and factual queries.
+type braveInfobox struct {
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Description string `json:"description"`
+	LongDesc    string `json:"long_desc"`
 }
 
 type braveResult struct {
@@ -43,25 +61,35 @@ type braveResult struct {
 	Description string `json:"description"`
 }
 
-func (b *Brave) Search(ctx context.Context, query string, opts Options) ([]Result, error) {
+func (b *Brave) Search(ctx context.Context, query string, opts Options) (Response, error) {
 	count := opts.Count
 	if count == 0 {
 		count = 5
 	}
 
+	// Brave pages in whole pages of count results, so an offset that is
+	// not a multiple of count rounds down to the page containing it.
+	page := opts.Offset / count
+	if page > braveMaxPage {
+		return Response{Offset: opts.Offset, Results: []Result{}, Exhausted: true}, nil
+	}
+
 	params := url.Values{
 		"q":     {query},
 		"count": {strconv.Itoa(count)},
 	}
+	if page > 0 {
+		params.Set("offset", strconv.Itoa(page))
+	}
 
 	if opts.Language != "" {
 		params.Set("search_lang", opts.Language)
 	}
 
-	reqURL := "https://api.search.brave.com/res/v1/web/search?" + params.Encode()
+	reqURL := b.baseURL + "?" + params.Encode()
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("brave: build request: %w", err)
+		return Response{}, fmt.Errorf("brave: build request: %w", err)
 	}
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -69,18 +97,18 @@ func (b *Brave) Search(ctx context.Context, query string, opts Options) ([]Resul
 
 	resp, err := b.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("brave: request failed: %w", err)
+		return Response{}, fmt.Errorf("brave: request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body := httpkit.ReadErrorBody(resp.Body, 512)
-		return nil, fmt.Errorf("brave: HTTP %d: %s", resp.StatusCode, body)
+		return Response{}, fmt.Errorf("brave: HTTP %d: %s", resp.StatusCode, body)
 	}
 
 	var br braveResponse
 	if err := json.NewDecoder(resp.Body).Decode(&br); err != nil {
-		return nil, fmt.Errorf("brave: decode response: %w", err)
+		return Response{}, fmt.Errorf("brave: decode response: %w", err)
 	}
 
 	results := make([]Result, 0, len(br.Web.Results))
@@ -92,7 +120,29 @@ func (b *Brave) Search(ctx context.Context, query string, opts Options) ([]Resul
 		})
 	}
 
-	return results, nil
+	return Response{
+		Answer:    b.answer(br),
+		Offset:    page * count,
+		Results:   results,
+		Exhausted: len(results) < count || page == braveMaxPage,
+	}, nil
+}
+
+// answer extracts the first infobox entry as an instant answer,
+// preferring its long description. It returns nil when the response
+// carries no infobox.
+func (b *Brave) answer(br braveResponse) *Answer {
+	for _, box := range br.Infobox.Results {
+		text := box.LongDesc
+		if text == "" {
+			text = box.Description
+		}
+		if text == "" {
+			continue
+		}
+		return &Answer{Text: text, Title: box.Title, URL: box.URL, Source: b.Name()}
+	}
+	return nil
 }
 
 // BraveConfig holds configuration for the Brave Search provider.
@@ -0,0 +1,96 @@
+package search
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// CacheStats reports a result cache's effectiveness for the health
+// endpoint. Lookups that bypass the cache (no_cache) count as neither
+// a hit nor a miss.
+type CacheStats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+}
+
+// resultCache is a bounded LRU cache whose entries also expire after
+// a fixed TTL. It is shared by every conversation using the owning
+// [Manager] or [Fetcher], so cached values are handed out by value and
+// must not be mutated through shared slices.
+type resultCache[V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	order      *list.List // front = most recently used
+	entries    map[string]*list.Element
+	hits       uint64
+	misses     uint64
+	now        func() time.Time
+}
+
+type cacheEntry[V any] struct {
+	key     string
+	value   V
+	expires time.Time
+}
+
+func newResultCache[V any](ttl time.Duration, maxEntries int) *resultCache[V] {
+	return &resultCache[V]{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// get returns the cached value for key when present and unexpired,
+// marking it most recently used.
+func (c *resultCache[V]) get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*cacheEntry[V])
+		if c.now().Before(entry.expires) {
+			c.order.MoveToFront(el)
+			c.hits++
+			return entry.value, true
+		}
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+	c.misses++
+	var zero V
+	return zero, false
+}
+
+// put stores value under key, evicting the least recently used entry
+// when the cache is full.
+func (c *resultCache[V]) put(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(c.ttl)
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*cacheEntry[V])
+		entry.value = value
+		entry.expires = expires
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry[V]{key: key, value: value, expires: expires})
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry[V]).key)
+	}
+}
+
+func (c *resultCache[V]) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Entries: c.order.Len()}
+}
@@ -0,0 +1,158 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResultCache_LRUAndTTL(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	c := newResultCache[int](time.Minute, 2)
+	c.now = func() time.Time { return now }
+
+	c.put("a", 1)
+	c.put("b", 2)
+	if _, ok := c.get("a"); !ok { // a is now most recently used
+		t.Fatal("a missing")
+	}
+	c.put("c", 3) // evicts b, the least recently used
+	if _, ok := c.get("b"); ok {
+		t.Error("b survived eviction")
+	}
+	if v, ok := c.get("c"); !ok || v != 3 {
+		t.Errorf("c = %d, %v", v, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := c.get("a"); ok {
+		t.Error("a served after its TTL")
+	}
+
+	st := c.stats()
+	if st.Hits != 2 || st.Misses != 2 || st.Entries != 1 {
+		t.Errorf("stats = %+v, want 2 hits, 2 misses, 1 entry", st)
+	}
+}
+
+// countingProvider counts backend calls so cache hits are observable.
+// A query of "degraded" gets an empty response and "broken" an error.
+type countingProvider struct {
+	calls atomic.Int32
+}
+
+func (p *countingProvider) Name() string { return "counting" }
+func (p *countingProvider) Search(_ context.Context, query string, _ Options) (Response, error) {
+	p.calls.Add(1)
+	switch query {
+	case "degraded":
+		return Response{Results: []Result{}, Exhausted: true}, nil
+	case "broken":
+		return Response{}, errors.New("backend down")
+	}
+	return Response{Results: []Result{{Title: query}}}, nil
+}
+
+func TestManagerCache(t *testing.T) {
+	p := &countingProvider{}
+	mgr := NewManager("counting")
+	mgr.Register(p)
+	mgr.EnableCache(time.Minute, 16)
+	ctx := context.Background()
+
+	if _, err := mgr.Search(ctx, "Home  Assistant", Options{}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := mgr.Search(ctx, "home assistant", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.calls.Load() != 1 || resp.Results[0].Title != "Home  Assistant" {
+		t.Errorf("calls = %d, results = %+v; want a normalized-query cache hit", p.calls.Load(), resp.Results)
+	}
+
+	if _, err := mgr.Search(ctx, "home assistant", Options{NoCache: true}); err != nil {
+		t.Fatal(err)
+	}
+	if p.calls.Load() != 2 {
+		t.Errorf("calls = %d, want no_cache to reach the provider", p.calls.Load())
+	}
+
+	st, ok := mgr.CacheStats()
+	if !ok || st.Hits != 1 || st.Misses != 1 {
+		t.Errorf("stats = %+v, %v; want 1 hit, 1 miss (no_cache uncounted)", st, ok)
+	}
+}
+
+func TestManagerCache_SkipsEmptyAndErrors(t *testing.T) {
+	p := &countingProvider{}
+	mgr := NewManager("counting")
+	mgr.Register(p)
+	mgr.EnableCache(time.Minute, 16)
+	ctx := context.Background()
+
+	for i := range 2 {
+		if _, err := mgr.Search(ctx, "degraded", Options{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := mgr.Search(ctx, "broken", Options{}); err == nil {
+			t.Fatalf("attempt %d: expected the provider error", i)
+		}
+	}
+	if p.calls.Load() != 4 {
+		t.Errorf("calls = %d, want every empty or failed search to reach the provider", p.calls.Load())
+	}
+	if st, _ := mgr.CacheStats(); st.Entries != 0 {
+		t.Errorf("entries = %d, want nothing cached", st.Entries)
+	}
+}
+
+func TestFetcherCacheMatchesLivePath(t *testing.T) {
+	var hits atomic.Int32
+	page := "<html><head><title>Cached</title></head><body><p>" + strings.Repeat("word ", 200) + "</p></body></html>"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(page))
+	}))
+	defer ts.Close()
+
+	live := NewFetcher()
+	cached := NewFetcher()
+	cached.EnableCache(time.Minute, 16)
+	ctx := context.Background()
+
+	for _, maxChars := range []int{0, 100} {
+		want, err := live.Fetch(ctx, ts.URL, maxChars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cached.Fetch(ctx, ts.URL+"#section", 0); err != nil {
+			t.Fatal(err)
+		}
+		got, err := cached.Fetch(ctx, ts.URL, maxChars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("max_chars=%d: cached = %+v, live = %+v", maxChars, got, want)
+		}
+	}
+	// Two live fetches plus one cached-fetcher download; the rest hit.
+	if hits.Load() != 3 {
+		t.Errorf("server hits = %d, want 3", hits.Load())
+	}
+
+	if _, err := cached.FetchFresh(ctx, ts.URL, 0); err != nil {
+		t.Fatal(err)
+	}
+	if hits.Load() != 4 {
+		t.Errorf("server hits = %d, want FetchFresh to bypass the cache", hits.Load())
+	}
+}
@@ -0,0 +1,200 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/httpkit"
+)
+
+// duckDuckGoURL is the no-JavaScript HTML endpoint. It needs no API
+// key but is a scraped page, not an API: its markup can change
+// without notice.
+const duckDuckGoURL = "https://html.duckduckgo.com/html/"
+
+// DuckDuckGo implements the Provider interface by scraping DuckDuckGo's
+// HTML results page. It is best-effort: when the page no longer parses
+// the way the scraper expects, Search logs a warning and returns an
+// empty result instead of failing the search.
+type DuckDuckGo struct {
+	baseURL    string
+	httpClient *http.Client
+	logger     *slog.Logger
+}
+
+// NewDuckDuckGo creates a DuckDuckGo provider.
+func NewDuckDuckGo(logger *slog.Logger) *DuckDuckGo {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &DuckDuckGo{
+		baseURL: duckDuckGoURL,
+		httpClient: httpkit.NewClient(
+			httpkit.WithTimeout(15 * time.Second),
+		),
+		logger: logger,
+	}
+}
+
+func (d *DuckDuckGo) Name() string { return "duckduckgo" }
+
+func (d *DuckDuckGo) Search(ctx context.Context, query string, opts Options) (Response, error) {
+	count := opts.Count
+	if count == 0 {
+		count = 5
+	}
+
+	// The HTML endpoint's next page needs form state from the previous
+	// one, so only the first page is reachable. Say so rather than
+	// returning it again.
+	if opts.Offset > 0 {
+		return Response{Offset: opts.Offset, Results: []Result{}, Exhausted: true}, nil
+	}
+
+	// opts.Language is not forwarded: DuckDuckGo's kl parameter takes a
+	// region-language pair ("de-de"), which a bare language code cannot
+	// supply without guessing the region.
+	form := url.Values{"q": {query}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.baseURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Response{}, fmt.Errorf("duckduckgo: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return Response{}, fmt.Errorf("duckduckgo: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body := httpkit.ReadErrorBody(resp.Body, 512)
+		return Response{}, fmt.Errorf("duckduckgo: HTTP %d: %s", resp.StatusCode, body)
+	}
+
+	doc, err := html.Parse(resp.Body)
+	if err != nil {
+		d.logger.Warn("duckduckgo: unparseable results page; returning no results", "error", err)
+		return Response{Results: []Result{}, Exhausted: true}, nil
+	}
+
+	results, recognized := parseDuckDuckGo(doc, count)
+	if !recognized {
+		d.logger.Warn("duckduckgo: results page layout not recognized; returning no results",
+			"query", query)
+	}
+	return Response{Results: results, Exhausted: true}, nil
+}
+
+// parseDuckDuckGo extracts up to count organic results from the HTML
+// results page, skipping ads. recognized is false when the page has
+// neither result blocks nor DuckDuckGo's no-results marker — the
+// signature of a layout change (or a bot challenge) rather than a
+// query that genuinely matched nothing.
+func parseDuckDuckGo(doc *html.Node, count int) (results []Result, recognized bool) {
+	results = make([]Result, 0, count)
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if len(results) >= count {
+			return
+		}
+		if n.Type == html.ElementNode {
+			switch {
+			case hasClass(n, "no-results"):
+				recognized = true
+			case hasClass(n, "result") && !hasClass(n, "result--ad"):
+				recognized = true
+				if r, ok := duckDuckGoResult(n); ok {
+					results = append(results, r)
+				}
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return results, recognized
+}
+
+// duckDuckGoResult reads one result block: the result__a title link
+// and the result__snippet text.
+func duckDuckGoResult(block *html.Node) (Result, bool) {
+	var r Result
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch {
+			case n.DataAtom == atom.A && hasClass(n, "result__a") && r.URL == "":
+				r.Title = strings.Join(strings.Fields(getTextContent(n)), " ")
+				r.URL = duckDuckGoTarget(attrValue(n, "href"))
+				return
+			case hasClass(n, "result__snippet") && r.Snippet == "":
+				r.Snippet = strings.Join(strings.Fields(getTextContent(n)), " ")
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(block)
+	return r, r.Title != "" && r.URL != ""
+}
+
+// duckDuckGoTarget unwraps DuckDuckGo's click-tracking redirect
+// (//duckduckgo.com/l/?uddg=<target>) to the destination URL. Links
+// that are not redirects pass through.
+func duckDuckGoTarget(href string) string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	if target := u.Query().Get("uddg"); target != "" {
+		return target
+	}
+	if u.Scheme == "" && u.Host != "" {
+		u.Scheme = "https"
+	}
+	return u.String()
+}
+
+func hasClass(n *html.Node, class string) bool {
+	for _, c := range strings.Fields(attrValue(n, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func attrValue(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// DuckDuckGoConfig holds configuration for the DuckDuckGo provider.
+// It needs no credentials, so it is opt-in.
+type DuckDuckGoConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// Configured reports whether the DuckDuckGo provider is enabled.
+func (c DuckDuckGoConfig) Configured() bool {
+	return c.Enabled
+}
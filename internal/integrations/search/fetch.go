@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -36,6 +37,7 @@ type FetchResult struct {
 type Fetcher struct {
 	client   *http.Client
 	maxBytes int64
+	cache    *resultCache[FetchResult] // untruncated results; nil when disabled
 }
 
 // NewFetcher creates a Fetcher with default settings.
@@ -48,9 +50,43 @@ func NewFetcher() *Fetcher {
 	}
 }
 
+// EnableCache puts an LRU cache in front of Fetch, shared across all
+// callers. Successful (2xx) pages are cached after text extraction and
+// before truncation, so a cached result is exactly what a live fetch
+// would return at any max_chars. Entries live for ttl; at most
+// maxEntries are kept. A non-positive ttl or maxEntries leaves caching
+// disabled.
+func (f *Fetcher) EnableCache(ttl time.Duration, maxEntries int) {
+	if ttl <= 0 || maxEntries <= 0 {
+		f.cache = nil
+		return
+	}
+	f.cache = newResultCache[FetchResult](ttl, maxEntries)
+}
+
+// CacheStats reports fetch cache hits, misses, and size. ok is false
+// when caching is disabled.
+func (f *Fetcher) CacheStats() (stats CacheStats, ok bool) {
+	if f.cache == nil {
+		return CacheStats{}, false
+	}
+	return f.cache.stats(), true
+}
+
 // Fetch downloads the URL and extracts readable text content.
 // maxChars limits the output length; 0 uses DefaultMaxChars.
 func (f *Fetcher) Fetch(ctx context.Context, rawURL string, maxChars int) (*FetchResult, error) {
+	return f.fetch(ctx, rawURL, maxChars, false)
+}
+
+// FetchFresh is [Fetcher.Fetch] without the cache read, for pages
+// where freshness matters. The fresh result still replaces any cached
+// one.
+func (f *Fetcher) FetchFresh(ctx context.Context, rawURL string, maxChars int) (*FetchResult, error) {
+	return f.fetch(ctx, rawURL, maxChars, true)
+}
+
+func (f *Fetcher) fetch(ctx context.Context, rawURL string, maxChars int, fresh bool) (*FetchResult, error) {
 	if rawURL == "" {
 		return nil, fmt.Errorf("web_fetch: url is required")
 	}
@@ -64,6 +100,26 @@ func (f *Fetcher) Fetch(ctx context.Context, rawURL string, maxChars int) (*Fetc
 		maxChars = DefaultMaxChars
 	}
 
+	key := fetchCacheKey(rawURL)
+	if f.cache != nil && !fresh {
+		if cached, ok := f.cache.get(key); ok {
+			cached.URL = rawURL // the key may have matched a different fragment or case
+			return cached.truncate(maxChars), nil
+		}
+	}
+
+	result, err := f.download(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if f.cache != nil && result.StatusCode >= 200 && result.StatusCode < 300 {
+		f.cache.put(key, *result)
+	}
+	return result.truncate(maxChars), nil
+}
+
+// download fetches rawURL and extracts its readable text, untruncated.
+func (f *Fetcher) download(ctx context.Context, rawURL string) (*FetchResult, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("web_fetch: invalid url: %w", err)
@@ -108,24 +164,41 @@ func (f *Fetcher) Fetch(ctx context.Context, rawURL string, maxChars int) (*Fetc
 		}
 	}
 
-	// Truncate if needed
-	truncated := false
-	if len(content) > maxChars {
-		content = truncateUTF8(content, maxChars)
-		truncated = true
-	}
-
 	return &FetchResult{
 		URL:         rawURL,
 		Title:       title,
 		Content:     content,
 		ContentType: contentType,
-		Truncated:   truncated,
 		Length:      len(content),
 		StatusCode:  resp.StatusCode,
 	}, nil
 }
 
+// truncate returns a copy of r with Content cut to maxChars.
+func (r FetchResult) truncate(maxChars int) *FetchResult {
+	if len(r.Content) > maxChars {
+		r.Content = truncateUTF8(r.Content, maxChars)
+		r.Truncated = true
+		r.Length = len(r.Content)
+	}
+	return &r
+}
+
+// fetchCacheKey identifies a page for caching: scheme and host are
+// case-insensitive and the fragment never reaches the server, so
+// neither distinguishes entries.
+func fetchCacheKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String()
+}
+
 func isHTML(ct string) bool {
 	ct = strings.ToLower(ct)
 	return strings.Contains(ct, "text/html") || strings.Contains(ct, "application/xhtml")
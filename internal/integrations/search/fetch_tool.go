@@ -20,7 +20,11 @@ func FetchToolHandler(f *Fetcher) func(ctx context.Context, args map[string]any)
 			maxChars = int(mc)
 		}
 
-		result, err := f.Fetch(ctx, url, maxChars)
+		fetch := f.Fetch
+		if noCache, _ := args["no_cache"].(bool); noCache {
+			fetch = f.FetchFresh
+		}
+		result, err := fetch(ctx, url, maxChars)
 		if err != nil {
 			return "", err
 		}
@@ -48,6 +52,10 @@ func FetchToolDefinition() map[string]any {
 				"type":        "integer",
 				"description": "Maximum characters to return. Default: 50000.",
 			},
+			"no_cache": map[string]any{
+				"type":        "boolean",
+				"description": "Skip the cached copy and fetch live. Use when the page changes often (news, status pages); repeat fetches are otherwise served from a short-lived cache.",
+			},
 		},
 		"required": []string{"url"},
 	}
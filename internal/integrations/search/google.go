@@ -0,0 +1,142 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/httpkit"
+)
+
+// googleMaxCount is the most results the Custom Search JSON API
+// returns per request.
+const googleMaxCount = 10
+
+// googleMaxResults is how deep the Custom Search JSON API pages: start
+// plus num may not exceed 100.
+const googleMaxResults = 100
+
+// Google implements the Provider interface for the Google
+// Programmable Search Engine (Custom Search JSON API).
+type Google struct {
+	apiKey     string
+	cx         string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewGoogle creates a Google Programmable Search provider. cx is the
+// search engine ID from the Programmable Search control panel.
+func NewGoogle(apiKey, cx string) *Google {
+	return &Google{
+		apiKey:  apiKey,
+		cx:      cx,
+		baseURL: "https://www.googleapis.com/customsearch/v1",
+		httpClient: httpkit.NewClient(
+			httpkit.WithTimeout(15 * time.Second),
+		),
+	}
+}
+
+func (g *Google) Name() string { return "google" }
+
+// googleResponse is the JSON response from the Custom Search API.
+type googleResponse struct {
+	Items []googleItem `json:"items"`
+}
+
+type googleItem struct {
+	Title   string `json:"title"`
+	Link    string `json:"link"`
+	Snippet string `json:"snippet"`
+}
+
+func (g *Google) Search(ctx context.Context, query string, opts Options) (Response, error) {
+	count := opts.Count
+	if count == 0 {
+		count = 5
+	}
+	if count > googleMaxCount {
+		count = googleMaxCount
+	}
+	if opts.Offset >= googleMaxResults {
+		return Response{Offset: opts.Offset, Results: []Result{}, Exhausted: true}, nil
+	}
+	if opts.Offset+count > googleMaxResults {
+		count = googleMaxResults - opts.Offset
+	}
+
+	params := url.Values{
+		"key": {g.apiKey},
+		"cx":  {g.cx},
+		"q":   {query},
+		"num": {strconv.Itoa(count)},
+	}
+	if opts.Offset > 0 {
+		params.Set("start", strconv.Itoa(opts.Offset+1)) // 1-based
+	}
+	if opts.Language != "" {
+		params.Set("lr", "lang_"+opts.Language)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return Response{}, fmt.Errorf("google: build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		// The request URL carries the API key; keep it out of the error.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return Response{}, fmt.Errorf("google: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body := httpkit.ReadErrorBody(resp.Body, 512)
+		return Response{}, fmt.Errorf("google: HTTP %d: %s", resp.StatusCode, body)
+	}
+
+	var gr googleResponse
+	if err := json.NewDecoder(resp.Body).Decode(&gr); err != nil {
+		return Response{}, fmt.Errorf("google: decode response: %w", err)
+	}
+
+	results := make([]Result, 0, len(gr.Items))
+	for _, item := range gr.Items {
+		results = append(results, Result{
+			Title:   item.Title,
+			URL:     item.Link,
+			Snippet: item.Snippet,
+		})
+	}
+
+	return Response{
+		Offset:    opts.Offset,
+		Results:   results,
+		Exhausted: len(results) < count || opts.Offset+count >= googleMaxResults,
+	}, nil
+}
+
+// GoogleConfig holds configuration for the Google Programmable Search
+// provider.
+type GoogleConfig struct {
+	APIKey string `yaml:"api_key"`
+
+	// CX is the Programmable Search Engine ID.
+	CX string `yaml:"cx"`
+}
+
+// Configured reports whether both the API key and engine ID are set.
+func (c GoogleConfig) Configured() bool {
+	return c.APIKey != "" && c.CX != ""
+}
@@ -0,0 +1,245 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const duckDuckGoPage = `<html><body>
+<div class="result results_links result--ad"><h2 class="result__title"><a class="result__a" href="https://ads.example">Sponsored</a></h2></div>
+<div class="result results_links web-result">
+  <h2 class="result__title"><a rel="nofollow" class="result__a" href="//duckduckgo.com/l/?uddg=https%3A%2F%2Fgo.dev%2F&amp;rut=abc">The Go <b>Programming</b> Language</a></h2>
+  <a class="result__snippet" href="//duckduckgo.com/l/?uddg=https%3A%2F%2Fgo.dev%2F">Go is an open source   programming language.</a>
+</div>
+<div class="result results_links web-result">
+  <h2 class="result__title"><a class="result__a" href="https://pkg.go.dev/">Go Packages</a></h2>
+</div>
+</body></html>`
+
+func TestDuckDuckGoSearch(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		gotQuery = r.PostForm.Get("q")
+		_, _ = w.Write([]byte(duckDuckGoPage))
+	}))
+	defer server.Close()
+
+	d := NewDuckDuckGo(nil)
+	d.baseURL = server.URL
+	resp, err := d.Search(context.Background(), "golang", Options{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if gotQuery != "golang" {
+		t.Errorf("q = %q, want golang", gotQuery)
+	}
+	if len(resp.Results) != 2 {
+		t.Fatalf("results = %+v, want 2 organic results (ad skipped)", resp.Results)
+	}
+	first := resp.Results[0]
+	if first.Title != "The Go Programming Language" || first.URL != "https://go.dev/" || first.Snippet != "Go is an open source programming language." {
+		t.Errorf("first result = %+v", first)
+	}
+	if resp.Results[1].URL != "https://pkg.go.dev/" {
+		t.Errorf("second result = %+v", resp.Results[1])
+	}
+}
+
+func TestDuckDuckGoSearch_UnrecognizedLayoutDegrades(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body><div class="brand-new-layout">results moved</div></body></html>`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	d := NewDuckDuckGo(slog.New(slog.NewTextHandler(&logs, nil)))
+	d.baseURL = server.URL
+	resp, err := d.Search(context.Background(), "golang", Options{})
+	if err != nil {
+		t.Fatalf("Search = %v, want an empty result, not an error", err)
+	}
+	if len(resp.Results) != 0 {
+		t.Errorf("results = %+v, want none", resp.Results)
+	}
+	if !strings.Contains(logs.String(), "layout not recognized") {
+		t.Errorf("logs = %q, want a layout warning", logs.String())
+	}
+}
+
+func TestDuckDuckGoSearch_NoResultsIsQuiet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body><div class="no-results">No results.</div></body></html>`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	d := NewDuckDuckGo(slog.New(slog.NewTextHandler(&logs, nil)))
+	d.baseURL = server.URL
+	if _, err := d.Search(context.Background(), "xyzzy", Options{}); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("logs = %q, want no warning for a genuine empty result", logs.String())
+	}
+}
+
+func TestGoogleSearch(t *testing.T) {
+	var gotQuery map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[{"title":"Go","link":"https://go.dev/","snippet":"Build simple, secure, scalable systems."}]}`))
+	}))
+	defer server.Close()
+
+	g := NewGoogle("key", "engine")
+	g.baseURL = server.URL
+	resp, err := g.Search(context.Background(), "golang", Options{Count: 25, Language: "en"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if gotQuery["key"][0] != "key" || gotQuery["cx"][0] != "engine" || gotQuery["num"][0] != "10" || gotQuery["lr"][0] != "lang_en" {
+		t.Errorf("query = %v, want key, cx, num capped at 10, lr", gotQuery)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].URL != "https://go.dev/" || resp.Results[0].Snippet == "" {
+		t.Errorf("results = %+v", resp.Results)
+	}
+}
+
+func TestGoogleSearch_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error":{"message":"API key not valid"}}`))
+	}))
+	defer server.Close()
+
+	g := NewGoogle("bad", "engine")
+	g.baseURL = server.URL
+	_, err := g.Search(context.Background(), "golang", Options{})
+	if err == nil || !strings.Contains(err.Error(), "HTTP 403") {
+		t.Errorf("error = %v, want HTTP 403", err)
+	}
+}
+
+func TestProviderPaging(t *testing.T) {
+	var gotQuery map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Query().Get("cx") != "": // Google
+			_, _ = w.Write([]byte(`{"items":[{"title":"a","link":"https://a"},{"title":"b","link":"https://b"}]}`))
+		default: // Brave
+			_, _ = w.Write([]byte(`{"web":{"results":[{"title":"a","url":"https://a"},{"title":"b","url":"https://b"}]}}`))
+		}
+	}))
+	defer server.Close()
+
+	google := NewGoogle("key", "engine")
+	google.baseURL = server.URL
+	brave := NewBrave("key")
+	brave.baseURL = server.URL
+
+	tests := []struct {
+		name       string
+		provider   Provider
+		offset     int
+		wantParam  string
+		wantValue  string
+		wantOffset int
+		wantExh    bool
+	}{
+		{"google second page", google, 2, "start", "3", 2, false},
+		{"brave rounds to page", brave, 3, "offset", "1", 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.provider.Search(context.Background(), "q", Options{Count: 2, Offset: tt.offset})
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if got := gotQuery[tt.wantParam]; len(got) != 1 || got[0] != tt.wantValue {
+				t.Errorf("%s = %v, want %s", tt.wantParam, got, tt.wantValue)
+			}
+			if resp.Offset != tt.wantOffset || resp.Exhausted != tt.wantExh || len(resp.Results) != 2 {
+				t.Errorf("response = %+v, want offset %d exhausted %v", resp, tt.wantOffset, tt.wantExh)
+			}
+		})
+	}
+}
+
+func TestSearXNGPaging(t *testing.T) {
+	// Pages of three results, unlike the requested count, and seven
+	// results in all; page 3 repeats one from page 2.
+	pages := map[string]string{
+		"":  `{"results":[{"url":"https://r0"},{"url":"https://r1"},{"url":"https://r2"}]}`,
+		"2": `{"results":[{"url":"https://r3"},{"url":"https://r4"},{"url":"https://r5"}]}`,
+		"3": `{"results":[{"url":"https://r5"},{"url":"https://r6"}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		body, ok := pages[r.URL.Query().Get("pageno")]
+		if !ok {
+			body = `{"results":[]}`
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	urls := func(results []Result) string {
+		var out []string
+		for _, r := range results {
+			out = append(out, strings.TrimPrefix(r.URL, "https://"))
+		}
+		return strings.Join(out, ",")
+	}
+	tests := []struct {
+		offset, count int
+		want          string
+		wantExh       bool
+	}{
+		{0, 2, "r0,r1", false},
+		{2, 2, "r2,r3", false},
+		{4, 2, "r4,r5", false},
+		{5, 4, "r5,r6", true},
+		{9, 2, "", true},
+	}
+	for _, tt := range tests {
+		resp, err := NewSearXNG(server.URL).Search(context.Background(), "q", Options{Count: tt.count, Offset: tt.offset})
+		if err != nil {
+			t.Fatalf("offset %d: %v", tt.offset, err)
+		}
+		if got := urls(resp.Results); got != tt.want || resp.Offset != tt.offset || resp.Exhausted != tt.wantExh {
+			t.Errorf("offset %d count %d: results %q offset %d exhausted %v; want %q, %d, %v",
+				tt.offset, tt.count, got, resp.Offset, resp.Exhausted, tt.want, tt.offset, tt.wantExh)
+		}
+	}
+}
+
+func TestProviderPaging_PastTheEnd(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request past the paging limit: %s", r.URL)
+	}))
+	defer server.Close()
+
+	google := NewGoogle("key", "engine")
+	google.baseURL = server.URL
+	brave := NewBrave("key")
+	brave.baseURL = server.URL
+
+	for _, p := range []Provider{google, brave} {
+		resp, err := p.Search(context.Background(), "q", Options{Count: 10, Offset: 100})
+		if err != nil {
+			t.Fatalf("%s: %v", p.Name(), err)
+		}
+		if !resp.Exhausted || len(resp.Results) != 0 {
+			t.Errorf("%s: response = %+v, want empty and exhausted", p.Name(), resp)
+		}
+	}
+}
@@ -9,6 +9,9 @@ package search
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Result is a single search result.
@@ -18,6 +21,43 @@ type Result struct {
 	Snippet string `json:"snippet,omitempty"`
 }
 
+// Answer is a direct answer a provider extracted for the query, such
+// as Brave's infobox or SearXNG's answers. It is vetted by the provider,
+// so for factual queries the model can use it instead of synthesizing
+// one from snippets.
+type Answer struct {
+	// Text is the answer itself.
+	Text string `json:"text"`
+
+	// Title names the answered subject, when the provider supplies one.
+	Title string `json:"title,omitempty"`
+
+	// URL is the answer's source page, when the provider supplies one.
+	URL string `json:"url,omitempty"`
+
+	// Source is the name of the provider that supplied the answer.
+	Source string `json:"source"`
+}
+
+// Response is the outcome of one search: the ranked results plus an
+// optional instant answer.
+type Response struct {
+	// Answer is nil when the provider returned no answer box.
+	Answer *Answer `json:"answer,omitempty"`
+
+	// Offset is the zero-based rank of the first result actually
+	// returned. It can differ from the requested [Options.Offset] when
+	// a provider pages in fixed steps and rounds down to a boundary.
+	Offset int `json:"offset"`
+
+	Results []Result `json:"results"`
+
+	// Exhausted reports that there is nothing past this page: the
+	// provider returned a short page, hit its paging limit, or cannot
+	// page at all. Re-running the same query will not surface more.
+	Exhausted bool `json:"exhausted,omitempty"`
+}
+
 // Options are optional parameters for a search query.
 type Options struct {
 	// Count is the maximum number of results to return.
@@ -26,6 +66,17 @@ type Options struct {
 
 	// Language is an ISO 639-1 language code (e.g., "en", "de").
 	Language string `json:"language,omitempty"`
+
+	// Offset is the number of leading results to skip, for fetching
+	// the page after one that missed. Zero is the first page.
+	// Providers that cannot page return an empty, exhausted response
+	// for any non-zero offset rather than repeating the first page.
+	Offset int `json:"offset,omitempty"`
+
+	// NoCache skips the result cache for this query, for searches
+	// where freshness matters (news). The fresh response still
+	// replaces any cached one.
+	NoCache bool `json:"no_cache,omitempty"`
 }
 
 // Provider is the interface that search backends implement.
@@ -33,14 +84,16 @@ type Provider interface {
 	// Name returns the provider identifier (e.g., "searxng", "brave").
 	Name() string
 
-	// Search executes a query and returns results.
-	Search(ctx context.Context, query string, opts Options) ([]Result, error)
+	// Search executes a query and returns results, along with an
+	// instant answer when the backend supplies one.
+	Search(ctx context.Context, query string, opts Options) (Response, error)
 }
 
 // Manager holds configured providers and routes searches.
 type Manager struct {
 	providers map[string]Provider
 	primary   string
+	cache     *resultCache[Response] // nil when caching is disabled
 }
 
 // NewManager creates a search manager. The primary provider name
@@ -57,22 +110,69 @@ func (m *Manager) Register(p Provider) {
 	m.providers[p.Name()] = p
 }
 
-// Search runs a query against the primary provider.
-func (m *Manager) Search(ctx context.Context, query string, opts Options) ([]Result, error) {
-	p, ok := m.providers[m.primary]
-	if !ok {
-		return nil, fmt.Errorf("search provider %q not configured", m.primary)
+// EnableCache puts an LRU cache in front of every provider, shared
+// across all callers. Responses with results or an answer are reused
+// for ttl; errors and empty responses are not cached. Entries are keyed
+// by provider and normalized query; at most maxEntries are kept. A
+// non-positive ttl or maxEntries leaves caching disabled.
+func (m *Manager) EnableCache(ttl time.Duration, maxEntries int) {
+	if ttl <= 0 || maxEntries <= 0 {
+		m.cache = nil
+		return
 	}
-	return p.Search(ctx, query, opts)
+	m.cache = newResultCache[Response](ttl, maxEntries)
+}
+
+// CacheStats reports result cache hits, misses, and size. ok is false
+// when caching is disabled.
+func (m *Manager) CacheStats() (stats CacheStats, ok bool) {
+	if m.cache == nil {
+		return CacheStats{}, false
+	}
+	return m.cache.stats(), true
+}
+
+// Search runs a query against the primary provider.
+func (m *Manager) Search(ctx context.Context, query string, opts Options) (Response, error) {
+	return m.SearchWith(ctx, m.primary, query, opts)
 }
 
 // SearchWith runs a query against a specific named provider.
-func (m *Manager) SearchWith(ctx context.Context, provider, query string, opts Options) ([]Result, error) {
+func (m *Manager) SearchWith(ctx context.Context, provider, query string, opts Options) (Response, error) {
 	p, ok := m.providers[provider]
 	if !ok {
-		return nil, fmt.Errorf("search provider %q not configured", provider)
+		return Response{}, fmt.Errorf("search provider %q not configured", provider)
+	}
+	if m.cache == nil {
+		return p.Search(ctx, query, opts)
 	}
-	return p.Search(ctx, query, opts)
+
+	key := searchCacheKey(provider, query, opts)
+	if !opts.NoCache {
+		if resp, ok := m.cache.get(key); ok {
+			return resp, nil
+		}
+	}
+	resp, err := p.Search(ctx, query, opts)
+	if err != nil {
+		return Response{}, err
+	}
+	// An empty response is as likely a degraded backend (a DuckDuckGo
+	// page that no longer parses, a rate-limit interstitial) as a true
+	// no-match, so it is retried next time rather than served for ttl.
+	if len(resp.Results) > 0 || resp.Answer != nil {
+		m.cache.put(key, resp)
+	}
+	return resp, nil
+}
+
+// searchCacheKey identifies a query for caching. Case and whitespace
+// differences in the query do not change what a provider returns, so
+// they collapse to one entry; count, offset, and language do, so they
+// stay.
+func searchCacheKey(provider, query string, opts Options) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	return provider + "\x00" + strconv.Itoa(opts.Count) + "\x00" + strconv.Itoa(opts.Offset) + "\x00" + opts.Language + "\x00" + normalized
 }
 
 // Providers returns the names of all registered providers.
@@ -9,12 +9,13 @@ import (
 type mockProvider struct {
 	name    string
 	results []Result
+	answer  *Answer
 	err     error
 }
 
 func (m *mockProvider) Name() string { return m.name }
-func (m *mockProvider) Search(_ context.Context, _ string, _ Options) ([]Result, error) {
-	return m.results, m.err
+func (m *mockProvider) Search(_ context.Context, _ string, _ Options) (Response, error) {
+	return Response{Answer: m.answer, Results: m.results}, m.err
 }
 
 func TestManagerSearch(t *testing.T) {
@@ -26,10 +27,11 @@ func TestManagerSearch(t *testing.T) {
 		},
 	})
 
-	results, err := mgr.Search(context.Background(), "test", Options{})
+	resp, err := mgr.Search(context.Background(), "test", Options{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	results := resp.Results
 	if len(results) != 1 {
 		t.Fatalf("expected 1 result, got %d", len(results))
 	}
@@ -43,12 +45,12 @@ func TestManagerSearchWith(t *testing.T) {
 	mgr.Register(&mockProvider{name: "primary", results: []Result{{Title: "Primary"}}})
 	mgr.Register(&mockProvider{name: "secondary", results: []Result{{Title: "Secondary"}}})
 
-	results, err := mgr.SearchWith(context.Background(), "secondary", "test", Options{})
+	resp, err := mgr.SearchWith(context.Background(), "secondary", "test", Options{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if results[0].Title != "Secondary" {
-		t.Errorf("expected 'Secondary', got %q", results[0].Title)
+	if resp.Results[0].Title != "Secondary" {
+		t.Errorf("expected 'Secondary', got %q", resp.Results[0].Title)
 	}
 }
 
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nugget/thane-ai-agent/internal/platform/httpkit"
@@ -33,7 +35,35 @@ func (s *SearXNG) Name() string { return "searxng" }
 
 // searxngResponse is the JSON response from SearXNG's /search endpoint.
 type searxngResponse struct {
-	Results []searxngResult `json:"results"`
+	Results   []searxngResult  `json:"results"`
+	Answers   []searxngAnswer  `json:"answers"`
+	Infoboxes []searxngInfobox `json:"infoboxes"`
+}
+
+// searxngAnswer is one entry in SearXNG's answers list. Older
+// instances return bare strings; newer ones return objects with the
+// answer text and its source URL. UnmarshalJSON accepts both.
+type searxngAnswer struct {
+	Answer string `json:"answer"`
+	URL    string `json:"url"`
+}
+
+func (a *searxngAnswer) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		a.Answer = text
+		return nil
+	}
+	type plain searxngAnswer
+	return json.Unmarshal(data, (*plain)(a))
+}
+
+// searxngInfobox is one entry in SearXNG's infoboxes list, the
+// knowledge panel engines such as Wikipedia supply.
+type searxngInfobox struct {
+	Infobox string `json:"infobox"`
+	ID      string `json:"id"`
+	Content string `json:"content"`
 }
 
 type searxngResult struct {
@@ -42,7 +72,11 @@ type searxngResult struct {
 	Content string `json:"content"`
 }
 
-func (s *SearXNG) Search(ctx context.Context, query string, opts Options) ([]Result, error) {
+// searxngMaxPages bounds how many result pages one search walks to
+// reach a deep offset.
+const searxngMaxPages = 5
+
+func (s *SearXNG) Search(ctx context.Context, query string, opts Options) (Response, error) {
 	params := url.Values{
 		"q":      {query},
 		"format": {"json"},
@@ -57,42 +91,106 @@ func (s *SearXNG) Search(ctx context.Context, query string, opts Options) ([]Res
 		count = 5
 	}
 
+	// SearXNG pages by page number, and a page holds however many
+	// results its engines returned rather than count. Walk the pages
+	// from the first, collecting distinct results, until the requested
+	// window is covered or the results run out, then slice it out.
+	want := opts.Offset + count
+	var (
+		collected []Result
+		answer    *Answer
+		stopped   bool
+	)
+	seen := make(map[string]bool)
+	for pageno := 1; len(collected) < want; pageno++ {
+		if pageno > searxngMaxPages {
+			stopped = true
+			break
+		}
+		sr, err := s.fetchPage(ctx, params, pageno)
+		if err != nil {
+			return Response{}, err
+		}
+		if pageno == 1 {
+			answer = s.answer(sr)
+		}
+		added := 0
+		for _, r := range sr.Results {
+			if seen[r.URL] {
+				continue
+			}
+			seen[r.URL] = true
+			collected = append(collected, Result{
+				Title:   r.Title,
+				URL:     r.URL,
+				Snippet: r.Content,
+			})
+			added++
+		}
+		if added == 0 {
+			stopped = true
+			break
+		}
+	}
+
+	results := []Result{}
+	if opts.Offset < len(collected) {
+		results = append(results, collected[opts.Offset:min(len(collected), want)]...)
+	}
+	return Response{
+		Answer:    answer,
+		Offset:    opts.Offset,
+		Results:   results,
+		Exhausted: stopped && len(collected) <= want,
+	}, nil
+}
+
+// fetchPage requests one page of results for the query in params.
+func (s *SearXNG) fetchPage(ctx context.Context, params url.Values, pageno int) (searxngResponse, error) {
+	if pageno > 1 {
+		params = maps.Clone(params)
+		params.Set("pageno", strconv.Itoa(pageno))
+	}
 	reqURL := fmt.Sprintf("%s/search?%s", s.baseURL, params.Encode())
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("searxng: build request: %w", err)
+		return searxngResponse{}, fmt.Errorf("searxng: build request: %w", err)
 	}
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("searxng: request failed: %w", err)
+		return searxngResponse{}, fmt.Errorf("searxng: request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body := httpkit.ReadErrorBody(resp.Body, 512)
-		return nil, fmt.Errorf("searxng: HTTP %d: %s", resp.StatusCode, body)
+		return searxngResponse{}, fmt.Errorf("searxng: HTTP %d: %s", resp.StatusCode, body)
 	}
 
 	var sr searxngResponse
 	if err := json.NewDecoder(resp.Body).Decode(&sr); err != nil {
-		return nil, fmt.Errorf("searxng: decode response: %w", err)
+		return searxngResponse{}, fmt.Errorf("searxng: decode response: %w", err)
 	}
+	return sr, nil
+}
 
-	results := make([]Result, 0, count)
-	for i, r := range sr.Results {
-		if i >= count {
-			break
+// answer extracts an instant answer, preferring SearXNG's direct
+// answers over its infobox content. It returns nil when the response
+// carries neither.
+func (s *SearXNG) answer(sr searxngResponse) *Answer {
+	for _, a := range sr.Answers {
+		if text := strings.TrimSpace(a.Answer); text != "" {
+			return &Answer{Text: text, URL: a.URL, Source: s.Name()}
 		}
-		results = append(results, Result{
-			Title:   r.Title,
-			URL:     r.URL,
-			Snippet: r.Content,
-		})
 	}
-
-	return results, nil
+	for _, box := range sr.Infoboxes {
+		if text := strings.TrimSpace(box.Content); text != "" {
+			return &Answer{Text: text, Title: box.Infobox, URL: box.ID, Source: s.Name()}
+		}
+	}
+	return nil
 }
 
 // SearXNGConfig holds configuration for the SearXNG provider.
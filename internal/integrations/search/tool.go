@@ -23,28 +23,63 @@ func ToolHandler(mgr *Manager) func(ctx context.Context, args map[string]any) (s
 		if lang, ok := args["language"].(string); ok {
 			opts.Language = lang
 		}
+		if offset, ok := args["offset"].(float64); ok && offset > 0 {
+			opts.Offset = int(offset)
+		}
+		if noCache, ok := args["no_cache"].(bool); ok {
+			opts.NoCache = noCache
+		}
 
 		// Allow explicit provider selection, fall back to primary.
-		var results []Result
+		var resp Response
 		var err error
 		if provider, ok := args["provider"].(string); ok && provider != "" {
-			results, err = mgr.SearchWith(ctx, provider, query, opts)
+			resp, err = mgr.SearchWith(ctx, provider, query, opts)
 		} else {
-			results, err = mgr.Search(ctx, query, opts)
+			resp, err = mgr.Search(ctx, query, opts)
 		}
 		if err != nil {
 			return "", err
 		}
 
-		// Return JSON for structured consumption by the agent.
-		out, err := json.Marshal(results)
+		// Return JSON for structured consumption by the agent, always
+		// the same object whether or not there is an instant answer.
+		// The answer, when there is one, leads so the model sees it
+		// first; paging position follows so a miss leads to the next
+		// page rather than the same query again.
+		out, err := json.Marshal(newToolResult(resp))
 		if err != nil {
-			return FormatResults(results, len(results)), nil
+			return "", fmt.Errorf("web_search: encode results: %w", err)
 		}
 		return string(out), nil
 	}
 }
 
+// toolResult is the web_search payload: [Response] plus where the
+// next page starts, or a note that there is none. Results is always
+// present, empty rather than null when nothing matched; Answer is
+// omitted when the provider has none.
+type toolResult struct {
+	Answer     *Answer  `json:"answer,omitempty"`
+	Offset     int      `json:"offset"`
+	Results    []Result `json:"results"`
+	NextOffset int      `json:"next_offset,omitempty"`
+	Note       string   `json:"note,omitempty"`
+}
+
+func newToolResult(resp Response) toolResult {
+	out := toolResult{Answer: resp.Answer, Offset: resp.Offset, Results: resp.Results}
+	if out.Results == nil {
+		out.Results = []Result{}
+	}
+	if resp.Exhausted {
+		out.Note = "No further results for this query; rephrase it or try another provider instead of paging."
+	} else {
+		out.NextOffset = resp.Offset + len(resp.Results)
+	}
+	return out
+}
+
 // ToolDefinition returns the JSON Schema parameters for the web_search tool.
 func ToolDefinition() map[string]any {
 	return map[string]any{
@@ -62,10 +97,18 @@ func ToolDefinition() map[string]any {
 				"type":        "string",
 				"description": "ISO 639-1 language code for results (e.g., 'en', 'de').",
 			},
+			"offset": map[string]any{
+				"type":        "integer",
+				"description": "Number of results to skip, to get the next page when the first one missed. Use next_offset from the previous result. Default: 0.",
+			},
 			"provider": map[string]any{
 				"type":        "string",
 				"description": "Search provider to use. Omit for default.",
 			},
+			"no_cache": map[string]any{
+				"type":        "boolean",
+				"description": "Skip cached results and search live. Use when freshness matters (news, live scores); repeat searches are otherwise served from a short-lived cache.",
+			},
 		},
 		"required": []string{"query"},
 	}
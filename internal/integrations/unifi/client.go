@@ -12,10 +12,13 @@ import (
 )
 
 // ClientStation represents a wireless client from the UniFi controller
-// API. Only fields relevant to room presence detection are included.
+// API. Only fields relevant to room presence detection and device
+// labeling are included.
 type ClientStation struct {
 	MAC            string `json:"mac"`
+	Name           string `json:"name"` // alias set in the controller
 	Hostname       string `json:"hostname"`
+	OUI            string `json:"oui"`              // manufacturer from the MAC prefix
 	LastUplinkName string `json:"last_uplink_name"` // AP name
 	Signal         int    `json:"signal"`           // RSSI in dBm
 	LastSeen       int64  `json:"last_seen"`        // Unix timestamp
@@ -121,7 +124,9 @@ func (c *Client) Ping(ctx context.Context) error {
 }
 
 // LocateDevices implements DeviceLocator by querying the UniFi station
-// list and converting to DeviceLocation structs.
+// list and converting to DeviceLocation structs. The station list
+// carries each client's name and manufacturer, so labeling devices
+// costs no extra requests.
 func (c *Client) LocateDevices(ctx context.Context) ([]DeviceLocation, error) {
 	stations, err := c.GetClientStations(ctx)
 	if err != nil {
@@ -131,10 +136,13 @@ func (c *Client) LocateDevices(ctx context.Context) ([]DeviceLocation, error) {
 	locations := make([]DeviceLocation, len(stations))
 	for i, s := range stations {
 		locations[i] = DeviceLocation{
-			MAC:      s.MAC,
-			APName:   s.LastUplinkName,
-			Signal:   s.Signal,
-			LastSeen: s.LastSeen,
+			MAC:          s.MAC,
+			APName:       s.LastUplinkName,
+			Signal:       s.Signal,
+			LastSeen:     s.LastSeen,
+			Name:         s.Name,
+			Hostname:     s.Hostname,
+			Manufacturer: s.OUI,
 		}
 	}
 	return locations, nil
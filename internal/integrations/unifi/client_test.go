@@ -147,7 +147,7 @@ func TestLocateDevices(t *testing.T) {
 			Data []ClientStation `json:"data"`
 		}{
 			Data: []ClientStation{
-				{MAC: "aa:bb:cc:dd:ee:ff", LastUplinkName: "ap-office", Signal: -45, LastSeen: 1000},
+				{MAC: "aa:bb:cc:dd:ee:ff", Hostname: "alice-phone", OUI: "Apple", LastUplinkName: "ap-office", Signal: -45, LastSeen: 1000},
 			},
 		}
 		w.Header().Set("Content-Type", "application/json")
@@ -174,4 +174,24 @@ func TestLocateDevices(t *testing.T) {
 	if loc.Signal != -45 {
 		t.Errorf("expected Signal -45, got %d", loc.Signal)
 	}
+	if loc.Hostname != "alice-phone" || loc.Manufacturer != "Apple" {
+		t.Errorf("expected hostname and manufacturer carried through, got %+v", loc)
+	}
+}
+
+func TestDeviceLocationLabel(t *testing.T) {
+	tests := []struct {
+		loc  DeviceLocation
+		want string
+	}{
+		{DeviceLocation{MAC: "aa:bb", Name: "Kitchen iPad", Hostname: "ipad", Manufacturer: "Apple"}, "Kitchen iPad (Apple)"},
+		{DeviceLocation{MAC: "aa:bb", Hostname: "ipad"}, "ipad"},
+		{DeviceLocation{MAC: "aa:bb", Manufacturer: "Espressif"}, "Espressif device aa:bb"},
+		{DeviceLocation{MAC: "aa:bb"}, "aa:bb"},
+	}
+	for _, tt := range tests {
+		if got := tt.loc.Label(); got != tt.want {
+			t.Errorf("Label(%+v) = %q, want %q", tt.loc, got, tt.want)
+		}
+	}
 }
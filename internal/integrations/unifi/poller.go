@@ -8,9 +8,15 @@ import (
 	"sync"
 	"time"
 
+	"github.com/nugget/thane-ai-agent/internal/channels/messages"
 	"github.com/nugget/thane-ai-agent/internal/runtime/loop"
 )
 
+// DefaultSecurityLoopName is the built-in event-driven loop that
+// receives unrecognized-device wakes when [PollerConfig.Bus] is set
+// and no other WakeLoop is configured.
+const DefaultSecurityLoopName = "unifi-security-handler"
+
 // RoomUpdater is the interface the person tracker must satisfy for the
 // poller to push room updates. Keeps the unifi package decoupled from
 // the person package.
@@ -44,6 +50,15 @@ type PollerConfig struct {
 	// Defaults to defaultFailureThreshold when <= 0.
 	FailureThreshold int
 
+	// Bus, when set, receives an event-source wake for unrecognized
+	// devices joining the network, addressed to WakeLoop. Nil disables
+	// security wakes; newcomers are still logged.
+	Bus *messages.Bus
+
+	// WakeLoop is the target for unrecognized-device wakes. Defaults
+	// to [DefaultSecurityLoopName] when empty.
+	WakeLoop messages.LoopWakeTarget
+
 	// Logger for structured logging.
 	Logger *slog.Logger
 }
@@ -69,6 +84,18 @@ const debounceThreshold = 2
 // a real outage.
 const defaultFailureThreshold = 3
 
+// deviceMemory is how long a device stays in the poller's cache after
+// it was last seen. A device that returns within this window is not
+// reported as a newcomer again.
+const deviceMemory = 7 * 24 * time.Hour
+
+// knownDevice is one cache entry: the device's most recent location
+// and labeling data, refreshed on every poll it appears in.
+type knownDevice struct {
+	loc      DeviceLocation
+	lastSeen time.Time
+}
+
 // Poller periodically queries a DeviceLocator and updates the person
 // tracker with room-level presence. It requires two consecutive polls
 // showing the same AP before updating a room (debounce), preventing
@@ -79,6 +106,13 @@ type Poller struct {
 	mu                  sync.Mutex
 	pending             map[string]*pendingRoom // entity_id → pending room change
 	consecutiveFailures int                     // streak of failed polls; gates the alarm
+
+	// devices caches every device the controller has reported, keyed
+	// by lowercase MAC, so newcomers can be told apart from devices
+	// that merely re-associated. baselined is set after the first
+	// successful poll; the devices present then are not newcomers.
+	devices   map[string]*knownDevice
+	baselined bool
 }
 
 // failureThreshold is the configured consecutive-failure budget, or the default.
@@ -97,6 +131,7 @@ func NewPoller(cfg PollerConfig) *Poller {
 	return &Poller{
 		cfg:     cfg,
 		pending: make(map[string]*pendingRoom),
+		devices: make(map[string]*knownDevice),
 	}
 }
 
@@ -113,6 +148,20 @@ func (p *Poller) Poll(ctx context.Context) error {
 		return p.tolerateFailure(err, summary)
 	}
 
+	newcomers := p.refreshDevices(locations, time.Now())
+	for _, d := range newcomers {
+		p.cfg.Logger.Info("unrecognized device joined the network",
+			"mac", d.MAC,
+			"device", d.Label(),
+			"hostname", d.Hostname,
+			"manufacturer", d.Manufacturer,
+			"ap", d.APName,
+		)
+	}
+	if len(newcomers) > 0 {
+		p.wakeForNewcomers(ctx, newcomers)
+	}
+
 	// Build MAC → DeviceLocation index, keeping only tracked MACs.
 	macIndex := make(map[string]DeviceLocation, len(p.cfg.DeviceOwners))
 	for _, loc := range locations {
@@ -208,11 +257,106 @@ func (p *Poller) Poll(ctx context.Context) error {
 		summary["devices_located"] = len(macIndex)
 		summary["rooms_updated"] = roomsUpdated
 		summary["pending_changes"] = pendingCount
+		summary["devices_known"] = len(p.devices)
+		if len(newcomers) > 0 {
+			summary["unrecognized_devices"] = len(newcomers)
+		}
 	}
 
 	return nil
 }
 
+// refreshDevices updates the device cache from one poll's locations and
+// returns the devices that are new to it: not seen within
+// [deviceMemory] and not owned by a tracked person. Nothing is new on
+// the first poll, which only establishes the baseline.
+func (p *Poller) refreshDevices(locations []DeviceLocation, now time.Time) []DeviceLocation {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var newcomers []DeviceLocation
+	for _, loc := range locations {
+		mac := strings.ToLower(loc.MAC)
+		if mac == "" {
+			continue
+		}
+		loc.MAC = mac
+		if d, ok := p.devices[mac]; ok {
+			d.loc = loc
+			d.lastSeen = now
+			continue
+		}
+		p.devices[mac] = &knownDevice{loc: loc, lastSeen: now}
+		if _, owned := p.cfg.DeviceOwners[mac]; p.baselined && !owned {
+			newcomers = append(newcomers, loc)
+		}
+	}
+	for mac, d := range p.devices {
+		if now.Sub(d.lastSeen) > deviceMemory {
+			delete(p.devices, mac)
+		}
+	}
+	p.baselined = true
+	return newcomers
+}
+
+// wakeForNewcomers dispatches an event-source wake describing the
+// unrecognized devices to the security handler loop. Delivery failures
+// are logged, not returned: room presence must not stall on them.
+func (p *Poller) wakeForNewcomers(ctx context.Context, newcomers []DeviceLocation) {
+	if p.cfg.Bus == nil {
+		return
+	}
+	target := p.cfg.WakeLoop
+	if target.Name == "" && target.LoopID == "" {
+		target.Name = DefaultSecurityLoopName
+	}
+
+	now := time.Now()
+	for start := 0; start < len(newcomers); start += messages.MaxLoopEventsPerWake {
+		end := min(start+messages.MaxLoopEventsPerWake, len(newcomers))
+		events := make([]messages.LoopEventPayload, 0, end-start)
+		for _, d := range newcomers[start:end] {
+			meta := map[string]string{
+				"mac":          d.MAC,
+				"name":         d.Name,
+				"hostname":     d.Hostname,
+				"manufacturer": d.Manufacturer,
+				"ap":           d.APName,
+				"room":         p.cfg.APRooms[d.APName],
+			}
+			for k, v := range meta {
+				if v == "" {
+					delete(meta, k)
+				}
+			}
+			events = append(events, messages.LoopEventPayload{
+				Source:     "unifi",
+				Type:       "unrecognized_device",
+				ID:         fmt.Sprintf("unifi-%s-%d", d.MAC, now.UnixMilli()),
+				Title:      "Unrecognized device joined the network: " + d.Label(),
+				ObservedAt: now,
+				Metadata:   meta,
+			})
+		}
+		env, err := messages.NewEventSourceEnvelope(
+			messages.Identity{Kind: messages.IdentitySystem, Name: "unifi_poller"},
+			target,
+			"unifi_poll",
+			events,
+		)
+		if err == nil {
+			_, err = p.cfg.Bus.Send(ctx, env)
+		}
+		if err != nil {
+			p.cfg.Logger.Warn("unrecognized device wake failed",
+				"devices", end-start,
+				"error", err,
+			)
+		}
+	}
+}
+
 // tolerateFailure records a failed poll and decides whether to surface it. A
 // single transient gateway error (the UniFi controller 5xx-ing on /stat/sta) is
 // absorbed silently — Poll returns nil so the iteration succeeds and no operator
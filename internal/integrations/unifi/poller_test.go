@@ -7,6 +7,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/channels/messages"
 )
 
 type mockLocator struct {
@@ -358,3 +360,81 @@ func TestPoller_ClearsPendingWhenDeviceGone(t *testing.T) {
 		t.Error("expected pending entry cleared when device gone")
 	}
 }
+
+func TestPoller_UnrecognizedDeviceWake(t *testing.T) {
+	bus := messages.NewBus(nil)
+	var (
+		mu       sync.Mutex
+		captured []messages.Envelope
+	)
+	bus.RegisterRoute(messages.DestinationLoop, func(_ context.Context, env messages.Envelope) (messages.DeliveryResult, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		captured = append(captured, env)
+		return messages.DeliveryResult{Envelope: env, Status: messages.DeliveryDelivered}, nil
+	})
+
+	owned := DeviceLocation{MAC: "aa:bb:cc:dd:ee:ff", APName: "ap-office", LastSeen: 1000}
+	locator := &mockLocator{locations: []DeviceLocation{
+		owned,
+		{MAC: "11:22:33:44:55:66", Hostname: "printer", APName: "ap-office", LastSeen: 1000},
+	}}
+	p := NewPoller(PollerConfig{
+		Locator:      locator,
+		Updater:      &mockUpdater{},
+		PollInterval: time.Hour,
+		DeviceOwners: map[string]string{"aa:bb:cc:dd:ee:ff": "person.alice"},
+		APRooms:      map[string]string{"ap-office": "office"},
+		Bus:          bus,
+	})
+
+	// The first poll is the baseline: nothing present is new.
+	mustPoll(t, p)
+	// An owned device and a returning one are not newcomers.
+	locator.setLocations([]DeviceLocation{owned})
+	mustPoll(t, p)
+	locator.setLocations([]DeviceLocation{
+		owned,
+		{MAC: "11:22:33:44:55:66", Hostname: "printer", APName: "ap-office", LastSeen: 1001},
+		{MAC: "DE:AD:BE:EF:00:01", Hostname: "unknown-laptop", Manufacturer: "Dell", APName: "ap-office", LastSeen: 1001},
+	})
+	mustPoll(t, p)
+	mustPoll(t, p)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(captured) != 1 {
+		t.Fatalf("delivered %d wakes, want 1 for the single newcomer", len(captured))
+	}
+	env := captured[0]
+	if env.To.Target != DefaultSecurityLoopName {
+		t.Errorf("target = %q, want %q", env.To.Target, DefaultSecurityLoopName)
+	}
+	payload, ok := env.Payload.(messages.LoopNotifyPayload)
+	if !ok || len(payload.Events) != 1 {
+		t.Fatalf("payload = %#v, want one event", env.Payload)
+	}
+	ev := payload.Events[0]
+	if ev.Type != "unrecognized_device" || !strings.Contains(ev.Title, "unknown-laptop (Dell)") {
+		t.Errorf("event = %+v, want the labeled newcomer", ev)
+	}
+	if ev.Metadata["mac"] != "de:ad:be:ef:00:01" || ev.Metadata["room"] != "office" {
+		t.Errorf("metadata = %v, want normalized MAC and resolved room", ev.Metadata)
+	}
+}
+
+func TestPoller_UnrecognizedDeviceWithoutBus(t *testing.T) {
+	locator := &mockLocator{}
+	p := NewPoller(PollerConfig{
+		Locator:      locator,
+		Updater:      &mockUpdater{},
+		PollInterval: time.Hour,
+	})
+	mustPoll(t, p)
+	locator.setLocations([]DeviceLocation{{MAC: "de:ad:be:ef:00:01", Hostname: "unknown-laptop"}})
+	// Without a bus the newcomer is only logged; the poll still succeeds.
+	mustPoll(t, p)
+	if len(p.devices) != 1 {
+		t.Errorf("device cache = %d entries, want the newcomer cached", len(p.devices))
+	}
+}
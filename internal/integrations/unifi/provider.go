@@ -3,17 +3,44 @@
 // AP client associations.
 package unifi
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 // DeviceLocation represents a wireless device's current network location
 // as reported by a network controller. The MAC identifies the device,
 // APName indicates which access point it is associated with, and Signal
-// provides the RSSI in dBm.
+// provides the RSSI in dBm. Name, Hostname, and Manufacturer describe
+// the device when the controller knows them.
 type DeviceLocation struct {
-	MAC      string // device MAC address (lowercase, colon-separated)
-	APName   string // name of the AP the device is connected to
-	Signal   int    // RSSI in dBm
-	LastSeen int64  // Unix timestamp of last activity
+	MAC          string // device MAC address (lowercase, colon-separated)
+	APName       string // name of the AP the device is connected to
+	Signal       int    // RSSI in dBm
+	LastSeen     int64  // Unix timestamp of last activity
+	Name         string // alias assigned in the controller, if any
+	Hostname     string // hostname the device reported over DHCP
+	Manufacturer string // vendor resolved from the MAC's OUI prefix
+}
+
+// Label returns a human-readable name for the device: its controller
+// alias or hostname, qualified by manufacturer, falling back to the
+// MAC when the controller knows nothing else.
+func (d DeviceLocation) Label() string {
+	name := d.Name
+	if name == "" {
+		name = d.Hostname
+	}
+	switch {
+	case name != "" && d.Manufacturer != "":
+		return fmt.Sprintf("%s (%s)", name, d.Manufacturer)
+	case name != "":
+		return name
+	case d.Manufacturer != "":
+		return fmt.Sprintf("%s device %s", d.Manufacturer, d.MAC)
+	default:
+		return d.MAC
+	}
 }
 
 // DeviceLocator provides wireless device location data from a network
@@ -67,6 +67,7 @@ type Deployment struct {
 	Quality                   int
 	CostTier                  int
 	MinComplexity             string
+	Timeout                   time.Duration
 	Source                    DeploymentSource
 	Routable                  bool
 
@@ -211,6 +212,7 @@ func BuildCatalog(cfg *config.Config) (*Catalog, error) {
 		Quality               int
 		CostTier              int
 		MinComplexity         string
+		Timeout               time.Duration
 		Source                DeploymentSource
 		Routable              bool
 		AlwaysQualify         bool
@@ -246,7 +248,7 @@ func BuildCatalog(cfg *config.Config) (*Catalog, error) {
 			if provider == "" {
 				provider = "ollama"
 			}
-			if provider == "ollama" || provider == "lmstudio" {
+			if provider == "ollama" || provider == "lmstudio" || provider == "openai" {
 				providerResourceIDs := resourceIDsByProvider[provider]
 				switch {
 				case hasProviderResource(resourceByID, "default", provider):
@@ -291,6 +293,7 @@ func BuildCatalog(cfg *config.Config) (*Catalog, error) {
 			Quality:               m.Quality,
 			CostTier:              m.CostTier,
 			MinComplexity:         m.MinComplexity,
+			Timeout:               m.Timeout,
 			Source:                DeploymentSourceConfig,
 			Routable:              true,
 		})
@@ -325,6 +328,7 @@ func BuildCatalog(cfg *config.Config) (*Catalog, error) {
 			Quality:                   p.Quality,
 			CostTier:                  p.CostTier,
 			MinComplexity:             p.MinComplexity,
+			Timeout:                   p.Timeout,
 			Source:                    p.Source,
 			Routable:                  p.Routable,
 			Family:                    p.Family,
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sort"
+	"time"
 
 	modelproviders "github.com/nugget/thane-ai-agent/internal/model/fleet/providers"
 	"github.com/nugget/thane-ai-agent/internal/model/llm"
@@ -20,11 +21,16 @@ type ClientBundle struct {
 	HealthClients   map[string]ResourceHealthClient
 	OllamaClients   map[string]*modelproviders.OllamaClient
 	LMStudioClients map[string]*modelproviders.LMStudioClient
+	OpenAIClients   map[string]*modelproviders.OpenAIClient
 	// AnthropicClient is the singleton Anthropic provider shared across
 	// all anthropic-backed resources, retained here so late-bind
 	// machinery (e.g., Runtime.SetLogger) can find it without scanning
 	// ResourceClients for the *AnthropicClient type.
 	AnthropicClient *modelproviders.AnthropicClient
+
+	// logger is handed to each routed client built from the bundle
+	// for reporting calls cut off by a route timeout.
+	logger *slog.Logger
 }
 
 // ResourceHealthClient is the minimal health/watch surface that app
@@ -46,6 +52,7 @@ func BuildClients(cat *Catalog, cfg *config.Config, logger *slog.Logger) (*Clien
 
 	ollamaClients := make(map[string]*modelproviders.OllamaClient)
 	lmstudioClients := make(map[string]*modelproviders.LMStudioClient)
+	openaiClients := make(map[string]*modelproviders.OpenAIClient)
 	resourceClients := make(map[string]llm.Client, len(cat.Resources))
 	healthClients := make(map[string]ResourceHealthClient, len(cat.Resources))
 
@@ -70,12 +77,20 @@ func BuildClients(cat *Catalog, cfg *config.Config, logger *slog.Logger) (*Clien
 				AttachWatcher: lc.AttachWatcher,
 			}
 			client = lc
+		case "openai":
+			oc := modelproviders.NewOpenAIClientWithRetry(serverAPIKey(cfg, res.ID), res.URL, logger.With("resource", res.ID), retryPolicy(cfg))
+			openaiClients[res.ID] = oc
+			healthClients[res.ID] = ResourceHealthClient{
+				Ping:          oc.Ping,
+				AttachWatcher: oc.AttachWatcher,
+			}
+			client = oc
 		case "anthropic":
 			if !cfg.Anthropic.Configured() {
 				return nil, fmt.Errorf("resource %q requires anthropic config", res.ID)
 			}
 			if anthropicClient == nil {
-				anthropicClient = modelproviders.NewAnthropicClient(cfg.Anthropic.APIKey, logger)
+				anthropicClient = modelproviders.NewAnthropicClientWithRetry(cfg.Anthropic.APIKey, logger, retryPolicy(cfg))
 			}
 			client = anthropicClient
 		default:
@@ -90,7 +105,9 @@ func BuildClients(cat *Catalog, cfg *config.Config, logger *slog.Logger) (*Clien
 		HealthClients:   healthClients,
 		OllamaClients:   ollamaClients,
 		LMStudioClients: lmstudioClients,
+		OpenAIClients:   openaiClients,
 		AnthropicClient: anthropicClient,
+		logger:          logger,
 	}
 	client, err := bundle.BuildRoutedClient(cat)
 	if err != nil {
@@ -110,6 +127,52 @@ func serverAPIKey(cfg *config.Config, id string) string {
 	return ""
 }
 
+// defaultOllamaTimeout bounds Ollama calls for deployments without a
+// configured timeout. It matches the overall HTTP timeout the Ollama
+// client used before timeouts moved to the routed client, except that
+// streaming calls now measure it between tokens rather than end to end.
+const defaultOllamaTimeout = 5 * time.Minute
+
+// deploymentTimeout returns the route timeout for dep: its configured
+// timeout, else the provider default.
+func deploymentTimeout(dep Deployment) time.Duration {
+	if dep.Timeout > 0 {
+		return dep.Timeout
+	}
+	return providerDefaultTimeout(dep.Provider)
+}
+
+// providerDefaultTimeout returns the call timeout for a provider's
+// calls that have no configured one. Providers other than Ollama have
+// no default and rely on the caller's context and their transport
+// timeouts.
+func providerDefaultTimeout(provider string) time.Duration {
+	if provider == "ollama" {
+		return defaultOllamaTimeout
+	}
+	return 0
+}
+
+// retryPolicy returns the cloud-provider retry policy from
+// models.retry, falling back to provider defaults for unset fields.
+func retryPolicy(cfg *config.Config) modelproviders.RetryPolicy {
+	policy := modelproviders.DefaultRetryPolicy()
+	if cfg == nil {
+		return policy
+	}
+	r := cfg.Models.Retry
+	if r.MaxRetries != nil {
+		policy.MaxRetries = *r.MaxRetries
+	}
+	if r.InitialDelay > 0 {
+		policy.InitialDelay = r.InitialDelay
+	}
+	if r.MaxDelay > 0 {
+		policy.MaxDelay = r.MaxDelay
+	}
+	return policy
+}
+
 // BuildRoutedClient constructs a routed llm.Client for the provided
 // effective catalog using the bundle's stable per-resource clients.
 func (b *ClientBundle) BuildRoutedClient(cat *Catalog) (llm.Client, error) {
@@ -120,12 +183,16 @@ func (b *ClientBundle) BuildRoutedClient(cat *Catalog) (llm.Client, error) {
 		return nil, fmt.Errorf("nil model catalog")
 	}
 
-	fallback, err := b.fallbackClient(cat)
+	fallback, fallbackResource, err := b.fallbackClient(cat)
 	if err != nil {
 		return nil, err
 	}
 
 	multi := llm.NewMultiClient(fallback)
+	multi.SetLogger(b.logger)
+	if res, ok := cat.ResourceByID(fallbackResource); ok {
+		multi.SetFallbackTimeout(providerDefaultTimeout(res.Provider))
+	}
 	for id, client := range b.ResourceClients {
 		multi.AddProvider(id, client)
 	}
@@ -136,6 +203,7 @@ func (b *ClientBundle) BuildRoutedClient(cat *Catalog) (llm.Client, error) {
 			upstreamModel = dep.LoadedInstanceID
 		}
 		multi.AddRoute(dep.ID, dep.ResourceID, upstreamModel)
+		multi.SetRouteTimeout(dep.ID, deploymentTimeout(dep))
 	}
 	for alias, target := range cat.aliases {
 		if alias != target {
@@ -149,14 +217,16 @@ func (b *ClientBundle) BuildRoutedClient(cat *Catalog) (llm.Client, error) {
 	return multi, nil
 }
 
-func (b *ClientBundle) fallbackClient(cat *Catalog) (llm.Client, error) {
+// fallbackClient picks the client for models that match no route,
+// along with the ID of the resource it serves.
+func (b *ClientBundle) fallbackClient(cat *Catalog) (llm.Client, string, error) {
 	if cat == nil {
-		return nil, fmt.Errorf("nil model catalog")
+		return nil, "", fmt.Errorf("nil model catalog")
 	}
 	if preferred := cat.preferredRoutedDefault(); preferred != "" {
 		if dep, ok := cat.byID[preferred]; ok {
 			if client, ok := b.ResourceClients[dep.ResourceID]; ok {
-				return client, nil
+				return client, dep.ResourceID, nil
 			}
 		}
 	}
@@ -166,20 +236,20 @@ func (b *ClientBundle) fallbackClient(cat *Catalog) (llm.Client, error) {
 				continue
 			}
 			if client, ok := b.ResourceClients[res.ID]; ok {
-				return client, nil
+				return client, res.ID, nil
 			}
 		}
 	}
 	if client, ok := b.ResourceClients["default"]; ok {
-		return client, nil
+		return client, "default", nil
 	}
 	if len(b.ResourceClients) == 0 {
-		return nil, fmt.Errorf("no resource clients configured")
+		return nil, "", fmt.Errorf("no resource clients configured")
 	}
 	ids := make([]string, 0, len(b.ResourceClients))
 	for id := range b.ResourceClients {
 		ids = append(ids, id)
 	}
 	sort.Strings(ids)
-	return b.ResourceClients[ids[0]], nil
+	return b.ResourceClients[ids[0]], ids[0], nil
 }
@@ -3,8 +3,11 @@ package fleet
 import (
 	"context"
 	"testing"
+	"time"
 
+	modelproviders "github.com/nugget/thane-ai-agent/internal/model/fleet/providers"
 	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/platform/config"
 )
 
 type testBundleClient struct {
@@ -109,3 +112,50 @@ func TestClientBundleBuildRoutedClient_UsesLMStudioLoadedInstanceForUpstream(t *
 		t.Fatalf("resp.Model = %q, want stable deployment id", resp.Model)
 	}
 }
+
+func TestRetryPolicy_FromConfig(t *testing.T) {
+	if got := retryPolicy(nil); got != modelproviders.DefaultRetryPolicy() {
+		t.Fatalf("retryPolicy(nil) = %+v, want defaults", got)
+	}
+
+	zero := 0
+	cfg := &config.Config{}
+	cfg.Models.Retry = config.ModelRetryConfig{
+		MaxRetries: &zero,
+		MaxDelay:   10 * time.Second,
+	}
+	got := retryPolicy(cfg)
+	want := modelproviders.RetryPolicy{
+		MaxRetries:   0,
+		InitialDelay: modelproviders.DefaultRetryPolicy().InitialDelay,
+		MaxDelay:     10 * time.Second,
+	}
+	if got != want {
+		t.Fatalf("retryPolicy = %+v, want %+v", got, want)
+	}
+}
+
+func TestDeploymentTimeout_ConfiguredOrProviderDefault(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Models.OllamaURL = "http://localhost:11434"
+	cfg.Models.Available = []config.ModelConfig{
+		{Name: "llama3.3:70b", Timeout: 15 * time.Minute},
+		{Name: "qwen3:4b"},
+		{Name: "claude-sonnet-4-20250514", Provider: "anthropic"},
+	}
+
+	cat, err := BuildCatalog(cfg)
+	if err != nil {
+		t.Fatalf("BuildCatalog() error = %v", err)
+	}
+	want := map[string]time.Duration{
+		"llama3.3:70b":             15 * time.Minute,
+		"qwen3:4b":                 defaultOllamaTimeout,
+		"claude-sonnet-4-20250514": 0,
+	}
+	for _, dep := range cat.Deployments {
+		if got := deploymentTimeout(dep); got != want[dep.ModelName] {
+			t.Errorf("deploymentTimeout(%s) = %v, want %v", dep.ModelName, got, want[dep.ModelName])
+		}
+	}
+}
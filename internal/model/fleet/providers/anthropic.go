@@ -63,8 +63,15 @@ type RateLimitSnapshot struct {
 	RetryAfter time.Duration
 }
 
-// NewAnthropicClient creates a new Anthropic client.
+// NewAnthropicClient creates a new Anthropic client using
+// [DefaultRetryPolicy].
 func NewAnthropicClient(apiKey string, logger *slog.Logger) *AnthropicClient {
+	return NewAnthropicClientWithRetry(apiKey, logger, DefaultRetryPolicy())
+}
+
+// NewAnthropicClientWithRetry creates a new Anthropic client that
+// retries transient failures according to retry.
+func NewAnthropicClientWithRetry(apiKey string, logger *slog.Logger, retry RetryPolicy) *AnthropicClient {
 	if logger == nil {
 		logger = slog.Default()
 	}
@@ -84,23 +91,21 @@ func NewAnthropicClient(apiKey string, logger *slog.Logger) *AnthropicClient {
 		// has no setter and rebuilding the client would drop the
 		// connection pool. Retry logs from the bootstrap logger may be
 		// suppressed; request-level Debug/Info/Warn flow through c.logger.
-		httpClient: httpkit.NewClient(
+		httpClient: httpkit.NewClient(append([]httpkit.ClientOption{
 			// No global timeout — streaming responses can be long-lived.
 			// Rely on ctx deadlines/cancellation for timeout control.
 			httpkit.WithTimeout(0),
 			httpkit.WithTransport(t),
+			httpkit.WithLogger(providerLogger),
 			// Retry transient connection failures (matches the Ollama
 			// and LMStudio clients) plus transient Anthropic-side HTTP
-			// statuses: 429 for rate limiting, 500/502/503/504 for
-			// upstream hiccups. Streaming is safe — retryTransport
+			// statuses (see cloudRetryStatuses) with jittered
+			// exponential backoff. Streaming is safe — retryTransport
 			// only retries while the response body is still unread;
 			// once RoundTrip returns the body to the caller, a
 			// mid-stream failure propagates to the agent loop as a
 			// normal error.
-			httpkit.WithRetry(3, 2*time.Second),
-			httpkit.WithRetryOnStatus(429, 500, 502, 503, 504),
-			httpkit.WithLogger(providerLogger),
-		),
+		}, retry.clientOptions()...)...),
 	}
 }
 
@@ -402,7 +407,7 @@ func (c *AnthropicClient) ChatStream(ctx context.Context, model string, messages
 	if !stream {
 		return c.handleNonStreaming(ctx, resp.Body, upstreamRequestID)
 	}
-	return c.handleStreaming(ctx, resp.Body, callback, upstreamRequestID)
+	return c.handleStreaming(ctx, llm.ActivityReader(ctx, resp.Body), callback, upstreamRequestID)
 }
 
 // logRateLimitSnapshot emits a structured Debug line on every response
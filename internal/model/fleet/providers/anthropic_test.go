@@ -851,6 +851,103 @@ func TestApplyCacheBreakpointGuards_ReturnsToolCapDrop(t *testing.T) {
 	}
 }
 
+// fakePromptCache stands in for Anthropic's prompt cache: the request
+// prefix through the last cache_control marker (tools, then system
+// blocks) is the cache key, and a repeated key is reported as a cache
+// read instead of a cache write.
+type fakePromptCache struct {
+	seen map[string]bool
+}
+
+func (f *fakePromptCache) roundTrip(req *http.Request) (*http.Response, error) {
+	var body struct {
+		Tools  []anthropicTool    `json:"tools"`
+		System []anthropicContent `json:"system"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	prefix, _ := json.Marshal(body.Tools)
+	cached := prefix
+	for _, block := range body.System {
+		b, _ := json.Marshal(block)
+		prefix = append(prefix, b...)
+		if block.CacheControl != nil {
+			cached = append([]byte(nil), prefix...)
+		}
+	}
+	tokens := len(cached) / 4
+	var usage anthropicUsage
+	if f.seen[string(cached)] {
+		usage.CacheReadInputTokens = tokens
+	} else {
+		f.seen[string(cached)] = true
+		usage.CacheCreationInputTokens = tokens
+	}
+	usage.InputTokens = (len(prefix) - len(cached)) / 4
+	usage.OutputTokens = 5
+	out, _ := json.Marshal(anthropicResponse{
+		ID:         "msg_01",
+		Type:       "message",
+		Role:       "assistant",
+		Content:    []anthropicContent{{Type: "text", Text: "ok"}},
+		StopReason: "end_turn",
+		Usage:      usage,
+	})
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewReader(out)),
+		Request:    req,
+	}, nil
+}
+
+func TestAnthropicClient_PromptCacheHitsAcrossTurns(t *testing.T) {
+	cache := &fakePromptCache{seen: map[string]bool{}}
+	c := NewAnthropicClient("k", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	c.httpClient = &http.Client{Transport: roundTripFunc(cache.roundTrip)}
+
+	tools := []map[string]any{{
+		"type":     "function",
+		"function": map[string]any{"name": "ha_get_state", "description": "Get entity state", "parameters": map[string]any{"type": "object"}},
+	}}
+	system := func(now string) llm.Message {
+		return llm.Message{Role: "system", Sections: []llm.PromptSection{
+			{Name: "PERSONA", Content: strings.Repeat("You are Thane, a household agent. ", 400), CacheTTL: "1h"},
+			{Name: "TALENTS TAGGED", Content: strings.Repeat("Use ha_get_state for device state. ", 100), CacheTTL: "5m"},
+			{Name: "CURRENT CONDITIONS", Content: now},
+		}}
+	}
+
+	first, err := c.Chat(context.Background(), "claude-sonnet-4-6", []llm.Message{
+		system("It is 14:00."),
+		{Role: "user", Content: "Is the garage closed?"},
+	}, tools)
+	if err != nil {
+		t.Fatalf("first turn: %v", err)
+	}
+	// The cached prefix must cover the persona and talents, not just the
+	// tool definitions.
+	if first.CacheCreationInputTokens < 4000 || first.CacheReadInputTokens != 0 {
+		t.Fatalf("first turn cache write/read = %d/%d, want the system prefix written and no read",
+			first.CacheCreationInputTokens, first.CacheReadInputTokens)
+	}
+
+	second, err := c.Chat(context.Background(), "claude-sonnet-4-6", []llm.Message{
+		system("It is 14:05."),
+		{Role: "user", Content: "Is the garage closed?"},
+		{Role: "assistant", Content: "Yes."},
+		{Role: "user", Content: "And the front door?"},
+	}, tools)
+	if err != nil {
+		t.Fatalf("second turn: %v", err)
+	}
+	if second.CacheReadInputTokens != first.CacheCreationInputTokens || second.CacheCreationInputTokens != 0 {
+		t.Errorf("second turn cache write/read = %d/%d, want the first turn's %d-token prefix read from cache",
+			second.CacheCreationInputTokens, second.CacheReadInputTokens, first.CacheCreationInputTokens)
+	}
+}
+
 func TestParseRateLimitHeaders_AllFieldsPresent(t *testing.T) {
 	h := http.Header{}
 	h.Set("anthropic-ratelimit-requests-limit", "5000")
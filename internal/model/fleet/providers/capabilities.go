@@ -41,6 +41,14 @@ func CapabilitiesForProvider(provider string) Capabilities {
 			SupportsImages:    true,
 			SupportsInventory: false,
 		}
+	case "openai":
+		return Capabilities{
+			SupportsChat:      true,
+			SupportsStreaming: true,
+			SupportsTools:     true,
+			SupportsImages:    true,
+			SupportsInventory: false,
+		}
 	default:
 		return Capabilities{}
 	}
@@ -61,6 +69,16 @@ func SupportsImagesForModel(provider, name, family string, families []string, ca
 		return true
 	case "ollama", "lmstudio":
 		return looksLikeVisionModel(name, family, families)
+	case "openai":
+		// OpenAI-compatible endpoints also serve text-only open models,
+		// so only OpenAI's own multimodal families count by name.
+		lower := strings.ToLower(strings.TrimSpace(name))
+		for _, prefix := range []string{"gpt-4o", "gpt-4.1", "gpt-5", "o3", "o4"} {
+			if strings.HasPrefix(lower, prefix) {
+				return true
+			}
+		}
+		return looksLikeVisionModel(name, family, families)
 	default:
 		return false
 	}
@@ -44,6 +44,20 @@ func TestSupportsImagesForModel(t *testing.T) {
 			caps:     Capabilities{SupportsImages: true},
 			want:     true,
 		},
+		{
+			name:     "openai gpt-4o is vision capable",
+			provider: "openai",
+			model:    "gpt-4o-mini",
+			caps:     Capabilities{SupportsImages: true},
+			want:     true,
+		},
+		{
+			name:     "openai-compatible llama is text only",
+			provider: "openai",
+			model:    "llama-3.3-70b-versatile",
+			caps:     Capabilities{SupportsImages: true},
+			want:     false,
+		},
 		{
 			name:     "provider without image transport is false",
 			provider: "lmstudio",
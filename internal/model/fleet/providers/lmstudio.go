@@ -173,7 +173,7 @@ func (c *LMStudioClient) ChatStream(ctx context.Context, model string, messages
 		return result, nil
 	}
 
-	return c.handleStreaming(ctx, model, validToolNames, resp.Body, callback)
+	return c.handleStreaming(ctx, model, validToolNames, llm.ActivityReader(ctx, resp.Body), callback)
 }
 
 func (c *LMStudioClient) setAuth(req *http.Request) {
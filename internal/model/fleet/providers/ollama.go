@@ -48,6 +48,9 @@ func NewOllamaClient(baseURL string, logger *slog.Logger) *OllamaClient {
 	}
 	// Large local models can take significant time before sending headers
 	// (loading, thinking). Override the default 15s ResponseHeaderTimeout.
+	// There is no overall timeout: a slow model streaming steadily must
+	// not be cut off mid-response. The routed client bounds each call
+	// with the deployment's timeout instead (see llm.MultiClient).
 	t := httpkit.NewTransport()
 	t.ResponseHeaderTimeout = 5 * time.Minute
 
@@ -55,7 +58,7 @@ func NewOllamaClient(baseURL string, logger *slog.Logger) *OllamaClient {
 		baseURL: baseURL,
 		logger:  logger.With("provider", "ollama"),
 		httpClient: httpkit.NewClient(
-			httpkit.WithTimeout(5*time.Minute),
+			httpkit.WithTimeout(0),
 			httpkit.WithTransport(t),
 			httpkit.WithRetry(3, 2*time.Second),
 			httpkit.WithLogger(logger),
@@ -271,7 +274,7 @@ func (c *OllamaClient) ChatStream(ctx context.Context, model string, messages []
 	var toolCalls []llm.ToolCall
 	var contentBuilder strings.Builder
 	toolCallBufferFlushed := false // tracks whether we've started streaming to client
-	decoder := json.NewDecoder(resp.Body)
+	decoder := json.NewDecoder(llm.ActivityReader(ctx, resp.Body))
 
 	for {
 		var wire ollamaWireResponse
@@ -0,0 +1,424 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/platform/httpkit"
+)
+
+// DefaultOpenAIBaseURL is the OpenAI API root used when a resource
+// does not configure one.
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAIClient is a client for the OpenAI chat-completions API and
+// compatible endpoints (Groq, Together, vLLM, ...). The base URL is the
+// API root including its version segment, e.g.
+// "https://api.groq.com/openai/v1"; requests go to
+// baseURL+"/chat/completions".
+//
+// Messages and tool calls use the same OpenAI-compatible wire shapes as
+// [LMStudioClient].
+type OpenAIClient struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	logger     *slog.Logger
+	watcher    llm.ReadyWatcher
+}
+
+// NewOpenAIClient creates a new OpenAI client using
+// [DefaultRetryPolicy]. An empty baseURL uses [DefaultOpenAIBaseURL].
+func NewOpenAIClient(apiKey, baseURL string, logger *slog.Logger) *OpenAIClient {
+	return NewOpenAIClientWithRetry(apiKey, baseURL, logger, DefaultRetryPolicy())
+}
+
+// NewOpenAIClientWithRetry creates a new OpenAI client that retries
+// transient failures according to retry.
+func NewOpenAIClientWithRetry(apiKey, baseURL string, logger *slog.Logger, retry RetryPolicy) *OpenAIClient {
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	t := httpkit.NewTransport()
+	t.ResponseHeaderTimeout = 5 * time.Minute
+
+	return &OpenAIClient{
+		baseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		apiKey:  strings.TrimSpace(apiKey),
+		logger:  logger.With("provider", "openai"),
+		httpClient: httpkit.NewClient(append([]httpkit.ClientOption{
+			httpkit.WithTimeout(0),
+			httpkit.WithTransport(t),
+			httpkit.WithLogger(logger),
+		}, retry.clientOptions()...)...),
+	}
+}
+
+// SetLogger rebinds the request-level logger. See AnthropicClient.SetLogger
+// for the late-bind rationale; the same caveat about httpkit retries applies.
+//
+// Not safe to call concurrently with in-flight requests; intended to be
+// invoked once during init.
+func (c *OpenAIClient) SetLogger(logger *slog.Logger) {
+	if c == nil || logger == nil {
+		return
+	}
+	c.logger = logger.With("provider", "openai")
+}
+
+// AttachWatcher sets the connection watcher for health status queries.
+func (c *OpenAIClient) AttachWatcher(w llm.ReadyWatcher) {
+	c.watcher = w
+}
+
+// IsReady reports whether the endpoint is currently reachable.
+func (c *OpenAIClient) IsReady() bool {
+	if c.watcher == nil {
+		return true
+	}
+	return c.watcher.IsReady()
+}
+
+// Ping checks that the endpoint is reachable and accepts the API key by
+// listing its models.
+func (c *OpenAIClient) Ping(ctx context.Context) error {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/models", nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	c.setAuth(httpReq)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errBody := httpkit.ReadErrorBody(resp.Body, 4096)
+		return fmt.Errorf("openai API error %d: %s", resp.StatusCode, errBody)
+	}
+	return nil
+}
+
+// Chat sends a non-streaming chat completion request.
+func (c *OpenAIClient) Chat(ctx context.Context, model string, messages []llm.Message, tools []map[string]any) (*llm.ChatResponse, error) {
+	return c.ChatStream(ctx, model, messages, tools, nil)
+}
+
+// ChatStream sends a chat request. If callback is non-nil, tokens are
+// streamed via server-sent events.
+func (c *OpenAIClient) ChatStream(ctx context.Context, model string, messages []llm.Message, tools []map[string]any, callback llm.StreamCallback) (*llm.ChatResponse, error) {
+	stream := callback != nil
+
+	wireMessages, err := toLMStudioMessages(messages)
+	if err != nil {
+		return nil, fmt.Errorf("encode messages: %w", err)
+	}
+
+	req := openAIChatRequest{
+		Model:    model,
+		Messages: wireMessages,
+		Stream:   stream,
+		Tools:    convertToolsToOpenAI(tools),
+	}
+	if stream {
+		req.StreamOptions = &lmStudioStreamOptions{IncludeUsage: true}
+	}
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	c.logger.Debug("preparing request",
+		"model", model,
+		"messages", len(messages),
+		"tools", len(tools),
+		"stream", stream,
+	)
+	c.logger.Log(ctx, llm.LevelTrace, "request payload", "json", string(jsonData))
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	c.setAuth(httpReq)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	upstreamRequestID := resp.Header.Get("x-request-id")
+
+	if resp.StatusCode != http.StatusOK {
+		errBody := httpkit.ReadErrorBody(resp.Body, 4096)
+		c.logger.Error("API error", "status", resp.StatusCode, "body", errBody, "upstream_request_id", upstreamRequestID)
+		return nil, fmt.Errorf("openai API error %d: %s", resp.StatusCode, errBody)
+	}
+
+	validToolNames := extractToolNames(tools)
+	var result *llm.ChatResponse
+	if stream {
+		result, err = c.handleStreaming(model, validToolNames, llm.ActivityReader(ctx, resp.Body), callback)
+	} else {
+		var wire openAIChatResponse
+		if err := json.NewDecoder(resp.Body).Decode(&wire); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+		result, err = openAIChatResponseFromWire(&wire, validToolNames)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.UpstreamRequestID = upstreamRequestID
+
+	c.logger.Debug("response received",
+		"model", result.Model,
+		"stream", stream,
+		"input_tokens", result.InputTokens,
+		"output_tokens", result.OutputTokens,
+		"cache_read_input_tokens", result.CacheReadInputTokens,
+		"tool_calls", len(result.Message.ToolCalls),
+		"stop_reason", result.StopReason,
+		"upstream_request_id", upstreamRequestID,
+	)
+	c.logger.Log(ctx, llm.LevelTrace, "response content", "content", result.Message.Content)
+	return result, nil
+}
+
+func (c *OpenAIClient) setAuth(req *http.Request) {
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+}
+
+func (c *OpenAIClient) handleStreaming(requestedModel string, validToolNames []string, body io.Reader, callback llm.StreamCallback) (*llm.ChatResponse, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 2*1024*1024)
+
+	var (
+		content      strings.Builder
+		model        = requestedModel
+		role         = "assistant"
+		createdAt    time.Time
+		usage        *openAIUsage
+		finishReason string
+		toolAcc      = make(map[int]*lmStudioToolAccumulator)
+	)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "" {
+			continue
+		}
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk openAIChatResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("decode stream chunk: %w", err)
+		}
+		if chunk.Model != "" {
+			model = chunk.Model
+		}
+		if chunk.Created > 0 {
+			createdAt = time.Unix(chunk.Created, 0).UTC()
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.FinishReason != nil && *choice.FinishReason != "" {
+				finishReason = *choice.FinishReason
+			}
+			if choice.Delta == nil {
+				continue
+			}
+			if choice.Delta.Role != "" {
+				role = choice.Delta.Role
+			}
+			if choice.Delta.Content != "" {
+				content.WriteString(choice.Delta.Content)
+				callback(llm.StreamEvent{Kind: llm.KindToken, Token: choice.Delta.Content})
+			}
+			for _, tc := range choice.Delta.ToolCalls {
+				acc := toolAcc[tc.Index]
+				if acc == nil {
+					acc = &lmStudioToolAccumulator{}
+					toolAcc[tc.Index] = acc
+				}
+				if tc.ID != "" {
+					acc.ID = tc.ID
+				}
+				if tc.Function.Name != "" {
+					acc.Name = tc.Function.Name
+				}
+				acc.Args.WriteString(tc.Function.Arguments)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read stream: %w", err)
+	}
+
+	toolCalls, err := decodeLMStudioToolCalls(toolAcc)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &llm.ChatResponse{
+		Model:      model,
+		CreatedAt:  createdAt,
+		Done:       true,
+		StopReason: openAIStopReason(finishReason),
+	}
+	usage.apply(result)
+	result.Message.Role = normalizeLMStudioMessageRole(role)
+	result.Message.Content = content.String()
+	result.Message.ToolCalls = toolCalls
+	applyTextToolFallback(result, validToolNames)
+	return result, nil
+}
+
+func openAIChatResponseFromWire(wire *openAIChatResponse, validToolNames []string) (*llm.ChatResponse, error) {
+	if len(wire.Choices) == 0 || wire.Choices[0].Message == nil {
+		return nil, fmt.Errorf("response contained no choices")
+	}
+	choice := wire.Choices[0]
+
+	toolCalls, err := decodeLMStudioToolCallsFromSlice(choice.Message.ToolCalls)
+	if err != nil {
+		return nil, err
+	}
+	result := &llm.ChatResponse{
+		Model: wire.Model,
+		Done:  true,
+	}
+	if wire.Created > 0 {
+		result.CreatedAt = time.Unix(wire.Created, 0).UTC()
+	}
+	if choice.FinishReason != nil {
+		result.StopReason = openAIStopReason(*choice.FinishReason)
+	}
+	wire.Usage.apply(result)
+	result.Message.Role = normalizeLMStudioMessageRole(choice.Message.Role)
+	result.Message.Content = lmStudioContentText(choice.Message.Content)
+	result.Message.ToolCalls = toolCalls
+	applyTextToolFallback(result, validToolNames)
+	return result, nil
+}
+
+// openAIStopReason maps an OpenAI finish_reason onto the
+// provider-neutral stop reasons carried by [llm.ChatResponse], which
+// follow Anthropic's vocabulary. Unknown values pass through.
+func openAIStopReason(finishReason string) string {
+	switch finishReason {
+	case "stop":
+		return "end_turn"
+	case "tool_calls", "function_call":
+		return "tool_use"
+	case "length":
+		return "max_tokens"
+	case "content_filter":
+		return "refusal"
+	default:
+		return finishReason
+	}
+}
+
+// convertToolsToOpenAI strips top-level composition keywords from each
+// tool's parameter schema, which OpenAI rejects for function
+// parameters. Tools are otherwise already in OpenAI format.
+func convertToolsToOpenAI(tools []map[string]any) []map[string]any {
+	if len(tools) == 0 {
+		return nil
+	}
+	out := make([]map[string]any, 0, len(tools))
+	for _, tool := range tools {
+		fn, ok := tool["function"].(map[string]any)
+		if !ok {
+			out = append(out, tool)
+			continue
+		}
+		schema, ok := fn["parameters"].(map[string]any)
+		if !ok {
+			out = append(out, tool)
+			continue
+		}
+		stripped, _ := llm.StripTopLevelCompositionKeywords(schema)
+		fnCopy := make(map[string]any, len(fn))
+		for k, v := range fn {
+			fnCopy[k] = v
+		}
+		fnCopy["parameters"] = stripped
+		toolCopy := make(map[string]any, len(tool))
+		for k, v := range tool {
+			toolCopy[k] = v
+		}
+		toolCopy["function"] = fnCopy
+		out = append(out, toolCopy)
+	}
+	return out
+}
+
+type openAIChatRequest struct {
+	Model         string                 `json:"model"`
+	Messages      []lmStudioMessage      `json:"messages"`
+	Stream        bool                   `json:"stream,omitempty"`
+	Tools         []map[string]any       `json:"tools,omitempty"`
+	StreamOptions *lmStudioStreamOptions `json:"stream_options,omitempty"`
+}
+
+type openAIChatResponse struct {
+	ID      string               `json:"id,omitempty"`
+	Created int64                `json:"created,omitempty"`
+	Model   string               `json:"model,omitempty"`
+	Choices []lmStudioChatChoice `json:"choices"`
+	Usage   *openAIUsage         `json:"usage,omitempty"`
+}
+
+type openAIUsage struct {
+	PromptTokens        int `json:"prompt_tokens"`
+	CompletionTokens    int `json:"completion_tokens"`
+	PromptTokensDetails *struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details,omitempty"`
+}
+
+// apply copies token usage onto resp. OpenAI counts cached prompt
+// tokens inside prompt_tokens; they are split out as cache reads so
+// cost accounting prices them at the cache-read rate, matching the
+// Anthropic convention that InputTokens is uncached input only.
+func (u *openAIUsage) apply(resp *llm.ChatResponse) {
+	if u == nil {
+		return
+	}
+	cached := 0
+	if u.PromptTokensDetails != nil {
+		cached = min(u.PromptTokensDetails.CachedTokens, u.PromptTokens)
+	}
+	resp.InputTokens = u.PromptTokens - cached
+	resp.CacheReadInputTokens = cached
+	resp.OutputTokens = u.CompletionTokens
+}
@@ -0,0 +1,193 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+)
+
+var _ llm.Client = (*OpenAIClient)(nil)
+
+func newTestOpenAIClient(baseURL string) *OpenAIClient {
+	return NewOpenAIClient("sk-test", baseURL, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestNewOpenAIClient_DefaultBaseURL(t *testing.T) {
+	c := newTestOpenAIClient("")
+	if c.baseURL != DefaultOpenAIBaseURL {
+		t.Fatalf("baseURL = %q, want %q", c.baseURL, DefaultOpenAIBaseURL)
+	}
+	c = newTestOpenAIClient(" https://api.groq.com/openai/v1/ ")
+	if c.baseURL != "https://api.groq.com/openai/v1" {
+		t.Fatalf("baseURL = %q, want trimmed Groq URL", c.baseURL)
+	}
+}
+
+func TestOpenAIClient_Ping(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			t.Errorf("path = %q, want /v1/models", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer sk-test" {
+			t.Errorf("Authorization = %q, want Bearer sk-test", got)
+		}
+		_, _ = io.WriteString(w, `{"data":[{"id":"gpt-4o-mini"}]}`)
+	}))
+	defer srv.Close()
+
+	if err := newTestOpenAIClient(srv.URL + "/v1").Ping(context.Background()); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+}
+
+func TestOpenAIClient_ChatNonStreaming(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %q, want /v1/chat/completions", r.URL.Path)
+		}
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if req["model"] != "gpt-4o-mini" {
+			t.Errorf("model = %v, want gpt-4o-mini", req["model"])
+		}
+		if _, ok := req["stream_options"]; ok {
+			t.Errorf("stream_options should be omitted for non-streaming requests")
+		}
+		w.Header().Set("x-request-id", "req_abc")
+		_, _ = io.WriteString(w, `{
+			"id":"chatcmpl-1",
+			"created":1700000000,
+			"model":"gpt-4o-mini-2024-07-18",
+			"choices":[{"index":0,"finish_reason":"tool_calls","message":{
+				"role":"assistant",
+				"content":null,
+				"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_state","arguments":"{\"entity_id\":\"light.kitchen\"}"}}]
+			}}],
+			"usage":{"prompt_tokens":1200,"completion_tokens":30,"prompt_tokens_details":{"cached_tokens":1024}}
+		}`)
+	}))
+	defer srv.Close()
+
+	tools := []map[string]any{{
+		"type": "function",
+		"function": map[string]any{
+			"name":       "get_state",
+			"parameters": map[string]any{"type": "object"},
+		},
+	}}
+	resp, err := newTestOpenAIClient(srv.URL+"/v1").Chat(context.Background(), "gpt-4o-mini",
+		[]llm.Message{{Role: "user", Content: "Is the kitchen light on?"}}, tools)
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Model != "gpt-4o-mini-2024-07-18" {
+		t.Errorf("Model = %q", resp.Model)
+	}
+	if resp.StopReason != "tool_use" {
+		t.Errorf("StopReason = %q, want tool_use", resp.StopReason)
+	}
+	if resp.UpstreamRequestID != "req_abc" {
+		t.Errorf("UpstreamRequestID = %q, want req_abc", resp.UpstreamRequestID)
+	}
+	if resp.InputTokens != 176 || resp.CacheReadInputTokens != 1024 || resp.OutputTokens != 30 {
+		t.Errorf("usage = in %d / cache %d / out %d, want 176 / 1024 / 30",
+			resp.InputTokens, resp.CacheReadInputTokens, resp.OutputTokens)
+	}
+	if len(resp.Message.ToolCalls) != 1 {
+		t.Fatalf("ToolCalls = %d, want 1", len(resp.Message.ToolCalls))
+	}
+	tc := resp.Message.ToolCalls[0]
+	if tc.ID != "call_1" || tc.Function.Name != "get_state" || tc.Function.Arguments["entity_id"] != "light.kitchen" {
+		t.Errorf("tool call = %+v", tc)
+	}
+}
+
+func TestOpenAIClient_ChatStream(t *testing.T) {
+	t.Parallel()
+
+	chunks := []string{
+		`{"model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"Checking"}}]}`,
+		`{"model":"gpt-4o","choices":[{"index":0,"delta":{"content":" now."}}]}`,
+		`{"model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_9","function":{"name":"get_state","arguments":"{\"entity_"}}]}}]}`,
+		`{"model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"id\":\"sensor.temp\"}"}}]}}]}`,
+		`{"model":"gpt-4o","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+		`{"model":"gpt-4o","choices":[],"usage":{"prompt_tokens":50,"completion_tokens":12}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if req["stream"] != true {
+			t.Errorf("stream = %v, want true", req["stream"])
+		}
+		opts, _ := req["stream_options"].(map[string]any)
+		if opts["include_usage"] != true {
+			t.Errorf("stream_options = %v, want include_usage", req["stream_options"])
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, c := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", c)
+		}
+		_, _ = io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	var tokens []string
+	resp, err := newTestOpenAIClient(srv.URL).ChatStream(context.Background(), "gpt-4o",
+		[]llm.Message{{Role: "user", Content: "temperature?"}}, nil,
+		func(ev llm.StreamEvent) {
+			if ev.Kind == llm.KindToken {
+				tokens = append(tokens, ev.Token)
+			}
+		})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	if got := strings.Join(tokens, ""); got != "Checking now." {
+		t.Errorf("streamed tokens = %q", got)
+	}
+	if resp.Message.Content != "Checking now." {
+		t.Errorf("Content = %q", resp.Message.Content)
+	}
+	if resp.StopReason != "tool_use" {
+		t.Errorf("StopReason = %q, want tool_use", resp.StopReason)
+	}
+	if resp.InputTokens != 50 || resp.OutputTokens != 12 {
+		t.Errorf("usage = %d/%d, want 50/12", resp.InputTokens, resp.OutputTokens)
+	}
+	if len(resp.Message.ToolCalls) != 1 || resp.Message.ToolCalls[0].Function.Arguments["entity_id"] != "sensor.temp" {
+		t.Errorf("ToolCalls = %+v", resp.Message.ToolCalls)
+	}
+}
+
+func TestOpenAIStopReason(t *testing.T) {
+	tests := map[string]string{
+		"stop":           "end_turn",
+		"tool_calls":     "tool_use",
+		"function_call":  "tool_use",
+		"length":         "max_tokens",
+		"content_filter": "refusal",
+		"":               "",
+		"something_new":  "something_new",
+	}
+	for in, want := range tests {
+		if got := openAIStopReason(in); got != want {
+			t.Errorf("openAIStopReason(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
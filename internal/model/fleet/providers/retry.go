@@ -0,0 +1,61 @@
+package providers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/httpkit"
+)
+
+// RetryPolicy controls how cloud provider clients retry transient
+// failures before an error reaches the agent loop, where it would
+// trigger failover to another model. Retried failures are dial-level
+// connection errors plus the HTTP statuses in [cloudRetryStatuses];
+// everything else (auth, bad request, context overflow) surfaces
+// immediately. A server-supplied Retry-After is honored up to
+// MaxDelay; longer waits are not retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	// Zero disables retries.
+	MaxRetries int
+
+	// InitialDelay is the base backoff before the first retry. It
+	// doubles on each subsequent attempt, with jitter.
+	InitialDelay time.Duration
+
+	// MaxDelay caps each backoff wait and the Retry-After delay that
+	// will be honored.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured:
+// three retries backing off from 2s, capped at 30s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:   3,
+		InitialDelay: 2 * time.Second,
+		MaxDelay:     30 * time.Second,
+	}
+}
+
+// cloudRetryStatuses are the HTTP statuses a hosted model API returns
+// for conditions that usually clear on their own: 429 for rate
+// limiting, 500/502/503/504 for upstream hiccups, and Anthropic's 529
+// when the API is overloaded.
+var cloudRetryStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+	529,
+}
+
+// clientOptions translates the policy into httpkit retry options.
+func (p RetryPolicy) clientOptions() []httpkit.ClientOption {
+	return []httpkit.ClientOption{
+		httpkit.WithRetry(max(p.MaxRetries, 0), p.InitialDelay),
+		httpkit.WithRetryOnStatus(cloudRetryStatuses...),
+		httpkit.WithRetryBackoff(p.MaxDelay),
+	}
+}
@@ -0,0 +1,71 @@
+package providers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+)
+
+func TestOpenAIClient_RetriesTransientStatusBeforeFailing(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(529)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = io.WriteString(w, `{"model":"gpt-4o","choices":[{"finish_reason":"stop","message":{"role":"assistant","content":"ok"}}]}`)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewOpenAIClientWithRetry("sk-test", srv.URL, nil, RetryPolicy{
+		MaxRetries:   3,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     10 * time.Millisecond,
+	})
+	resp, err := c.Chat(context.Background(), "gpt-4o", []llm.Message{{Role: "user", Content: "hi"}}, nil)
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Message.Content != "ok" {
+		t.Errorf("Content = %q, want ok", resp.Message.Content)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("calls = %d, want 3", n)
+	}
+}
+
+func TestOpenAIClient_DoesNotRetryPermanentStatus(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = io.WriteString(w, `{"error":{"message":"bad key"}}`)
+	}))
+	defer srv.Close()
+
+	c := NewOpenAIClientWithRetry("sk-bad", srv.URL, nil, RetryPolicy{
+		MaxRetries:   3,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     10 * time.Millisecond,
+	})
+	if _, err := c.Chat(context.Background(), "gpt-4o", []llm.Message{{Role: "user", Content: "hi"}}, nil); err == nil {
+		t.Fatal("Chat succeeded, want 401 error")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("calls = %d, want 1 (no retry on 401)", n)
+	}
+}
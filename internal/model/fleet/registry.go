@@ -216,6 +216,37 @@ func (r *Registry) ApplyInventory(inv *Inventory, refreshedAt time.Time) error {
 	return nil
 }
 
+// ReplaceBase swaps in a new config-defined base catalog, as after a
+// config reload, and recomputes the effective catalog against the
+// current discovered inventory and policies. The provider resources
+// must match the running set because their clients are built once at
+// startup; changing them requires a restart.
+func (r *Registry) ReplaceBase(base *Catalog, updatedAt time.Time) error {
+	if r == nil {
+		return fmt.Errorf("nil registry")
+	}
+	if base == nil {
+		return fmt.Errorf("nil base catalog")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !reflect.DeepEqual(base.Resources, r.base.Resources) {
+		return fmt.Errorf("model resources changed; restart required")
+	}
+	effective, err := buildEffectiveCatalog(base, r.overlay, r.policies, r.resourcePolicies)
+	if err != nil {
+		return err
+	}
+	r.base = base
+	r.effective = effective
+	r.generation++
+	if !updatedAt.IsZero() {
+		r.updatedAt = updatedAt.UTC()
+	}
+	return nil
+}
+
 // Snapshot returns a JSON-friendly view of the registry state.
 func (r *Registry) Snapshot() *RegistrySnapshot {
 	if r == nil {
@@ -771,3 +771,52 @@ func TestRegistryReplaceDeploymentPolicies_ReappliesWhenDiscoveredDeploymentRetu
 		t.Fatalf("RoutableSource = %q, want %q", dep.RoutableSource, DeploymentPolicySourceOverlay)
 	}
 }
+
+func TestRegistryReplaceBase(t *testing.T) {
+	t.Parallel()
+
+	resources := []Resource{{ID: "spark", Provider: "ollama", URL: "http://spark.example"}}
+	deployment := func(model string) Deployment {
+		return Deployment{
+			ID:         "spark/" + model,
+			ModelName:  model,
+			Provider:   "ollama",
+			ResourceID: "spark",
+			Server:     "spark",
+			Source:     DeploymentSourceConfig,
+			Routable:   true,
+		}
+	}
+	catalog := func(res []Resource, deps ...Deployment) *Catalog {
+		t.Helper()
+		cat := &Catalog{DefaultModel: deps[0].ID, Resources: res, Deployments: deps}
+		if err := cat.reindex(cat.DefaultModel, cat.RecoveryModel); err != nil {
+			t.Fatalf("reindex: %v", err)
+		}
+		return cat
+	}
+
+	reg, err := NewRegistry(catalog(resources, deployment("gpt-oss:20b")))
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	before := reg.Snapshot().Generation
+
+	if err := reg.ReplaceBase(catalog(resources, deployment("gpt-oss:20b"), deployment("qwen3:8b")), time.Now()); err != nil {
+		t.Fatalf("ReplaceBase: %v", err)
+	}
+	if _, err := reg.Catalog().ResolveModelRef("spark/qwen3:8b"); err != nil {
+		t.Errorf("added deployment not in effective catalog: %v", err)
+	}
+	if got := reg.Snapshot().Generation; got != before+1 {
+		t.Errorf("generation = %d, want %d", got, before+1)
+	}
+
+	moved := []Resource{{ID: "spark", Provider: "ollama", URL: "http://elsewhere.example"}}
+	if err := reg.ReplaceBase(catalog(moved, deployment("gpt-oss:20b")), time.Now()); err == nil {
+		t.Fatal("ReplaceBase accepted a changed resource set")
+	}
+	if _, err := reg.Catalog().ResolveModelRef("spark/qwen3:8b"); err != nil {
+		t.Errorf("rejected replacement still modified the catalog: %v", err)
+	}
+}
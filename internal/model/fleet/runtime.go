@@ -113,9 +113,16 @@ func (r *Runtime) SetLogger(logger *slog.Logger) {
 	for id, lc := range r.bundle.LMStudioClients {
 		lc.SetLogger(logger.With("resource", id))
 	}
+	for id, oc := range r.bundle.OpenAIClients {
+		oc.SetLogger(logger.With("resource", id))
+	}
 	if r.bundle.AnthropicClient != nil {
 		r.bundle.AnthropicClient.SetLogger(logger)
 	}
+	r.bundle.logger = logger
+	if multi, ok := r.bundle.Client.(*llm.MultiClient); ok {
+		multi.SetLogger(logger)
+	}
 }
 
 // Client returns the swappable llm.Client.
@@ -252,6 +259,28 @@ func (r *Runtime) refreshLocked(ctx context.Context) (*RefreshResult, error) {
 	}, nil
 }
 
+// ReplaceBaseCatalog swaps the registry's config-defined base catalog
+// (see [Registry.ReplaceBase]) and swaps in a routed client built from
+// the new effective catalog for future requests.
+func (r *Runtime) ReplaceBaseCatalog(base *Catalog) error {
+	if r == nil {
+		return fmt.Errorf("nil runtime")
+	}
+
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+
+	if err := r.registry.ReplaceBase(base, time.Now()); err != nil {
+		return err
+	}
+	nextClient, err := r.bundle.BuildRoutedClient(r.registry.Catalog())
+	if err != nil {
+		return err
+	}
+	r.bundle.Client = nextClient
+	return r.client.Swap(nextClient)
+}
+
 // PrepareExplicitModel asks the backing provider to ready an explicit
 // deployment for the requested context size, then refreshes the live
 // registry snapshot when the provider state changes. Today this is used
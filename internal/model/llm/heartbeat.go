@@ -0,0 +1,80 @@
+package llm
+
+import (
+	"sync"
+	"time"
+)
+
+// WithHeartbeat wraps cb so that a [KindHeartbeat] event is delivered
+// whenever interval passes without a real event, keeping slow streaming
+// connections (SSE, NDJSON) from being closed by idle-timeout
+// proxies during long gaps such as the wait for a slow model's first
+// token. Heartbeats stop after a [KindDone] event passes through or
+// when the returned stop function is called; stop is idempotent and
+// must be called once the stream is finished.
+//
+// Calls to cb are serialized, so heartbeats never interleave with real
+// events. A nil cb or non-positive interval returns cb unchanged and a
+// no-op stop.
+func WithHeartbeat(cb StreamCallback, interval time.Duration) (StreamCallback, func()) {
+	if cb == nil || interval <= 0 {
+		return cb, func() {}
+	}
+
+	var (
+		mu       sync.Mutex
+		stopped  bool
+		stopOnce sync.Once
+	)
+	activity := make(chan struct{}, 1)
+	done := make(chan struct{})
+	stop := func() {
+		stopOnce.Do(func() {
+			mu.Lock()
+			stopped = true
+			mu.Unlock()
+			close(done)
+		})
+	}
+
+	go func() {
+		timer := time.NewTimer(interval)
+		defer timer.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-activity:
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(interval)
+			case <-timer.C:
+				mu.Lock()
+				if !stopped {
+					cb(StreamEvent{Kind: KindHeartbeat})
+				}
+				mu.Unlock()
+				timer.Reset(interval)
+			}
+		}
+	}()
+
+	wrapped := func(event StreamEvent) {
+		mu.Lock()
+		cb(event)
+		mu.Unlock()
+		if event.Kind == KindDone {
+			stop()
+			return
+		}
+		select {
+		case activity <- struct{}{}:
+		default:
+		}
+	}
+	return wrapped, stop
+}
@@ -0,0 +1,87 @@
+package llm
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// eventRecorder collects stream events from concurrent callers.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []StreamEvent
+}
+
+func (r *eventRecorder) record(e StreamEvent) {
+	r.mu.Lock()
+	r.events = append(r.events, e)
+	r.mu.Unlock()
+}
+
+func (r *eventRecorder) count(kind StreamEventKind) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, e := range r.events {
+		if e.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func TestWithHeartbeat_EmitsDuringSilenceAndStopsOnDone(t *testing.T) {
+	rec := &eventRecorder{}
+	cb, stop := WithHeartbeat(rec.record, 10*time.Millisecond)
+	defer stop()
+
+	// Simulated slow generation: a long wait before the first token.
+	time.Sleep(60 * time.Millisecond)
+	if got := rec.count(KindHeartbeat); got < 2 {
+		t.Fatalf("heartbeats during silence = %d, want >= 2", got)
+	}
+
+	cb(StreamEvent{Kind: KindToken, Token: "hello"})
+	cb(StreamEvent{Kind: KindDone})
+	afterDone := rec.count(KindHeartbeat)
+
+	time.Sleep(50 * time.Millisecond)
+	if got := rec.count(KindHeartbeat); got != afterDone {
+		t.Errorf("heartbeats after KindDone grew from %d to %d", afterDone, got)
+	}
+	if got := rec.count(KindToken); got != 1 {
+		t.Errorf("tokens = %d, want 1", got)
+	}
+}
+
+func TestWithHeartbeat_ActivitySuppressesHeartbeats(t *testing.T) {
+	rec := &eventRecorder{}
+	cb, stop := WithHeartbeat(rec.record, 40*time.Millisecond)
+	defer stop()
+
+	// Tokens arriving faster than the interval keep the line busy.
+	for range 8 {
+		cb(StreamEvent{Kind: KindToken, Token: "x"})
+		time.Sleep(10 * time.Millisecond)
+	}
+	stop()
+	if got := rec.count(KindHeartbeat); got != 0 {
+		t.Errorf("heartbeats while streaming = %d, want 0", got)
+	}
+}
+
+func TestWithHeartbeat_DisabledPassesThrough(t *testing.T) {
+	if cb, stop := WithHeartbeat(nil, time.Second); cb != nil {
+		stop()
+		t.Error("WithHeartbeat(nil) returned a non-nil callback")
+	}
+
+	rec := &eventRecorder{}
+	cb, stop := WithHeartbeat(rec.record, 0)
+	defer stop()
+	cb(StreamEvent{Kind: KindToken, Token: "x"})
+	time.Sleep(20 * time.Millisecond)
+	if rec.count(KindToken) != 1 || rec.count(KindHeartbeat) != 0 {
+		t.Errorf("events = %+v, want the single token only", rec.events)
+	}
+}
@@ -2,8 +2,11 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"sort"
+	"time"
 )
 
 // AmbiguousModelError reports that a model selector matches multiple
@@ -20,15 +23,18 @@ func (e *AmbiguousModelError) Error() string {
 type route struct {
 	providerName string
 	modelName    string
+	timeout      time.Duration
 }
 
 // MultiClient routes requests to the appropriate provider based on model name.
 type MultiClient struct {
-	clients   map[string]Client   // provider/resource name → client
-	routes    map[string]route    // route target → provider/resource + upstream model
-	aliases   map[string]string   // alias → route target
-	ambiguous map[string][]string // ambiguous alias → valid route targets
-	fallback  Client              // default client for unknown models
+	clients         map[string]Client   // provider/resource name → client
+	routes          map[string]route    // route target → provider/resource + upstream model
+	aliases         map[string]string   // alias → route target
+	ambiguous       map[string][]string // ambiguous alias → valid route targets
+	fallback        Client              // default client for unknown models
+	fallbackTimeout time.Duration       // route timeout for calls sent to fallback
+	logger          *slog.Logger
 }
 
 // NewMultiClient creates a client that routes to multiple providers.
@@ -39,6 +45,15 @@ func NewMultiClient(fallback Client) *MultiClient {
 		aliases:   make(map[string]string),
 		ambiguous: make(map[string][]string),
 		fallback:  fallback,
+		logger:    slog.Default(),
+	}
+}
+
+// SetLogger sets the logger used to report calls terminated by a
+// route timeout. A nil logger is ignored.
+func (m *MultiClient) SetLogger(logger *slog.Logger) {
+	if logger != nil {
+		m.logger = logger
 	}
 }
 
@@ -62,6 +77,28 @@ func (m *MultiClient) AddRoute(target, providerName, modelName string) {
 	m.aliases[target] = target
 }
 
+// SetRouteTimeout bounds calls to a route target. Non-streaming calls
+// get timeout as an overall deadline when the caller's context has
+// none. Streaming calls instead use it as an idle timeout that resets
+// on every stream event, so a model steadily producing output is never
+// cut off mid-response. Zero removes the bound. Unknown targets are
+// ignored.
+func (m *MultiClient) SetRouteTimeout(target string, timeout time.Duration) {
+	r, ok := m.routes[target]
+	if !ok {
+		return
+	}
+	r.timeout = timeout
+	m.routes[target] = r
+}
+
+// SetFallbackTimeout bounds calls for models that match no route and
+// go to the fallback client, with the same semantics as
+// [MultiClient.SetRouteTimeout].
+func (m *MultiClient) SetFallbackTimeout(timeout time.Duration) {
+	m.fallbackTimeout = timeout
+}
+
 // AddAlias maps an alternate selector to a concrete route target.
 func (m *MultiClient) AddAlias(alias, target string) {
 	m.aliases[alias] = target
@@ -76,12 +113,12 @@ func (m *MultiClient) MarkAmbiguous(alias string, targets []string) {
 	m.ambiguous[alias] = out
 }
 
-func (m *MultiClient) resolve(model string) (Client, string, string, error) {
+func (m *MultiClient) resolve(model string) (Client, route, string, error) {
 	target := model
 	if routes, ok := m.ambiguous[model]; ok {
 		out := make([]string, len(routes))
 		copy(out, routes)
-		return nil, "", "", &AmbiguousModelError{Model: model, Targets: out}
+		return nil, route{}, "", &AmbiguousModelError{Model: model, Targets: out}
 	}
 	if alias, ok := m.aliases[model]; ok {
 		target = alias
@@ -89,24 +126,28 @@ func (m *MultiClient) resolve(model string) (Client, string, string, error) {
 	if r, ok := m.routes[target]; ok {
 		client, ok := m.clients[r.providerName]
 		if !ok {
-			return nil, "", "", fmt.Errorf("no provider configured for route %q", target)
+			return nil, route{}, "", fmt.Errorf("no provider configured for route %q", target)
 		}
-		return client, r.modelName, target, nil
+		return client, r, target, nil
 	}
 	if m.fallback != nil {
-		return m.fallback, model, model, nil
+		return m.fallback, route{modelName: model, timeout: m.fallbackTimeout}, model, nil
 	}
-	return nil, "", "", fmt.Errorf("no provider configured for model %q", model)
+	return nil, route{}, "", fmt.Errorf("no provider configured for model %q", model)
 }
 
 // Chat sends a request to the appropriate provider for the model.
 func (m *MultiClient) Chat(ctx context.Context, model string, messages []Message, tools []map[string]any) (*ChatResponse, error) {
-	client, routedModel, routeTarget, err := m.resolve(model)
+	client, r, routeTarget, err := m.resolve(model)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := client.Chat(ctx, routedModel, messages, tools)
+	ctx, cancel, routeDeadline := r.withDeadline(ctx)
+	defer cancel()
+	start := time.Now()
+	resp, err := client.Chat(ctx, r.modelName, messages, tools)
 	if err != nil {
+		m.logTimeout(ctx, routeTarget, err, start, routeDeadline, false)
 		return nil, err
 	}
 	if resp != nil {
@@ -117,7 +158,7 @@ func (m *MultiClient) Chat(ctx context.Context, model string, messages []Message
 
 // ChatStream sends a streaming request to the appropriate provider.
 func (m *MultiClient) ChatStream(ctx context.Context, model string, messages []Message, tools []map[string]any, callback StreamCallback) (*ChatResponse, error) {
-	client, routedModel, routeTarget, err := m.resolve(model)
+	client, r, routeTarget, err := m.resolve(model)
 	if err != nil {
 		return nil, err
 	}
@@ -130,8 +171,23 @@ func (m *MultiClient) ChatStream(ctx context.Context, model string, messages []M
 			callback(event)
 		}
 	}
-	resp, err := client.ChatStream(ctx, routedModel, messages, tools, wrapped)
+	// Without a callback nothing is streamed, so the route timeout
+	// falls back to an overall deadline as in Chat.
+	routeDeadline := false
+	if callback != nil {
+		var stop func()
+		ctx, wrapped, stop = WithIdleTimeout(ctx, wrapped, r.timeout)
+		defer stop()
+	} else {
+		var cancel context.CancelFunc
+		ctx, cancel, routeDeadline = r.withDeadline(ctx)
+		defer cancel()
+	}
+	start := time.Now()
+	resp, err := client.ChatStream(ctx, r.modelName, messages, tools, wrapped)
 	if err != nil {
+		err = IdleTimeoutErr(ctx, err)
+		m.logTimeout(ctx, routeTarget, err, start, routeDeadline, callback != nil)
 		return nil, err
 	}
 	if resp != nil {
@@ -140,6 +196,38 @@ func (m *MultiClient) ChatStream(ctx context.Context, model string, messages []M
 	return resp, nil
 }
 
+// withDeadline applies the route timeout as an overall deadline when
+// ctx has none, reporting whether it did.
+func (r route) withDeadline(ctx context.Context) (context.Context, context.CancelFunc, bool) {
+	if _, ok := ctx.Deadline(); ok || r.timeout <= 0 {
+		return ctx, func() {}, false
+	}
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	return ctx, cancel, true
+}
+
+// logTimeout reports a call that ended because of a timeout, naming
+// whether the stream's idle timeout, the route's overall deadline, or
+// the caller's own deadline cut it off. Other errors are not logged
+// here; the caller handles them.
+func (m *MultiClient) logTimeout(ctx context.Context, target string, err error, start time.Time, routeDeadline, stream bool) {
+	attrs := []any{
+		"model", target,
+		"stream", stream,
+		"elapsed", time.Since(start).Round(time.Millisecond),
+	}
+	switch {
+	case errors.Is(err, ErrIdleTimeout):
+		m.logger.Warn("model call terminated by idle timeout", attrs...)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		source := "caller"
+		if routeDeadline {
+			source = "model"
+		}
+		m.logger.Warn("model call terminated by overall deadline", append(attrs, "deadline", source)...)
+	}
+}
+
 // Ping checks the fallback provider.
 func (m *MultiClient) Ping(ctx context.Context) error {
 	if m.fallback != nil {
@@ -3,8 +3,10 @@ package llm
 import (
 	"context"
 	"errors"
+	"io"
 	"strings"
 	"testing"
+	"time"
 )
 
 type recordingClient struct {
@@ -116,3 +118,128 @@ func TestMultiClientChatStream_RewritesResponseModelToRouteTarget(t *testing.T)
 		t.Fatalf("final response model = %q, want %q", resp.Model, "edge/qwen3:4b")
 	}
 }
+
+// pacedClient streams tokens with a fixed gap between them, honoring
+// context cancellation the way a provider reading an HTTP body would.
+type pacedClient struct {
+	tokens   int
+	gap      time.Duration
+	deadline bool // whether the last call's ctx carried a deadline
+	// silent reads each chunk from the wire without forwarding it,
+	// like a provider buffering tool-call JSON.
+	silent bool
+}
+
+func (c *pacedClient) Chat(ctx context.Context, model string, messages []Message, tools []map[string]any) (*ChatResponse, error) {
+	return c.ChatStream(ctx, model, messages, tools, nil)
+}
+
+func (c *pacedClient) ChatStream(ctx context.Context, model string, _ []Message, _ []map[string]any, callback StreamCallback) (*ChatResponse, error) {
+	_, c.deadline = ctx.Deadline()
+	for range c.tokens {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(c.gap):
+		}
+		if c.silent {
+			_, _ = io.ReadAll(ActivityReader(ctx, strings.NewReader("x")))
+			continue
+		}
+		if callback != nil {
+			callback(StreamEvent{Kind: KindToken, Token: "x"})
+		}
+	}
+	return &ChatResponse{Model: model, Done: true}, nil
+}
+
+func (c *pacedClient) Ping(context.Context) error { return nil }
+
+func TestMultiClientChatStream_IdleTimeoutResetsOnTokens(t *testing.T) {
+	// Total stream time (~200ms) well exceeds the 80ms route timeout,
+	// but no single gap does, so the call must succeed.
+	client := &pacedClient{tokens: 10, gap: 20 * time.Millisecond}
+	multi := NewMultiClient(nil)
+	multi.AddProvider("spark", client)
+	multi.AddRoute("spark/llama3:70b", "spark", "llama3:70b")
+	multi.SetRouteTimeout("spark/llama3:70b", 80*time.Millisecond)
+
+	if _, err := multi.ChatStream(context.Background(), "spark/llama3:70b", nil, nil, func(StreamEvent) {}); err != nil {
+		t.Fatalf("ChatStream() error = %v, want steady stream to finish", err)
+	}
+}
+
+func TestMultiClientChatStream_IdleTimeoutCancelsStalledStream(t *testing.T) {
+	client := &pacedClient{tokens: 2, gap: time.Second}
+	multi := NewMultiClient(nil)
+	multi.AddProvider("spark", client)
+	multi.AddRoute("spark/llama3:70b", "spark", "llama3:70b")
+	multi.SetRouteTimeout("spark/llama3:70b", 20*time.Millisecond)
+
+	_, err := multi.ChatStream(context.Background(), "spark/llama3:70b", nil, nil, func(StreamEvent) {})
+	if !errors.Is(err, ErrIdleTimeout) {
+		t.Fatalf("ChatStream() error = %v, want ErrIdleTimeout", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ChatStream() error = %v, want it to match context.DeadlineExceeded", err)
+	}
+}
+
+func TestMultiClientChat_RouteTimeoutAppliesOnlyWithoutCallerDeadline(t *testing.T) {
+	client := &pacedClient{tokens: 1, gap: time.Second}
+	multi := NewMultiClient(nil)
+	multi.AddProvider("spark", client)
+	multi.AddRoute("spark/llama3:70b", "spark", "llama3:70b")
+	multi.SetRouteTimeout("spark/llama3:70b", 20*time.Millisecond)
+
+	_, err := multi.Chat(context.Background(), "spark/llama3:70b", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrIdleTimeout) {
+		t.Fatalf("Chat() error = %v, want route deadline exceeded", err)
+	}
+
+	// A caller deadline wins over the route timeout.
+	client.gap = 40 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := multi.Chat(ctx, "spark/llama3:70b", nil, nil); err != nil {
+		t.Fatalf("Chat() with caller deadline error = %v", err)
+	}
+}
+
+func TestMultiClientSetRouteTimeout_IgnoresUnknownTarget(t *testing.T) {
+	client := &pacedClient{}
+	multi := NewMultiClient(client)
+	multi.SetRouteTimeout("missing", time.Millisecond)
+
+	if _, err := multi.Chat(context.Background(), "missing", nil, nil); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if client.deadline {
+		t.Fatal("fallback call got a deadline from an unknown route's timeout")
+	}
+}
+
+func TestMultiClientChatStream_IdleTimeoutResetsOnUnforwardedChunks(t *testing.T) {
+	// The provider reads steadily but forwards nothing to the callback,
+	// as when it is generating a long tool call.
+	client := &pacedClient{tokens: 10, gap: 20 * time.Millisecond, silent: true}
+	multi := NewMultiClient(nil)
+	multi.AddProvider("spark", client)
+	multi.AddRoute("spark/llama3:70b", "spark", "llama3:70b")
+	multi.SetRouteTimeout("spark/llama3:70b", 80*time.Millisecond)
+
+	if _, err := multi.ChatStream(context.Background(), "spark/llama3:70b", nil, nil, func(StreamEvent) {}); err != nil {
+		t.Fatalf("ChatStream() error = %v, want wire activity to keep the stream alive", err)
+	}
+}
+
+func TestMultiClientChat_FallbackTimeout(t *testing.T) {
+	client := &pacedClient{tokens: 1, gap: time.Second}
+	multi := NewMultiClient(client)
+	multi.SetFallbackTimeout(20 * time.Millisecond)
+
+	_, err := multi.Chat(context.Background(), "unrouted-model", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Chat() error = %v, want the fallback timeout to apply", err)
+	}
+}
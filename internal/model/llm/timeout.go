@@ -0,0 +1,92 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrIdleTimeout reports that a streaming call was cancelled because
+// no stream event arrived within the route's idle timeout. Errors
+// carrying it also match [context.DeadlineExceeded], so callers that
+// treat deadlines as timeouts handle both the same way.
+var ErrIdleTimeout = errors.New("stream idle timeout")
+
+// WithIdleTimeout derives a context that is cancelled when idle passes
+// without stream activity, and wraps cb so every stream event counts
+// as activity. Providers also report each chunk they read from the
+// wire through [ActivityReader], including chunks they never forward
+// to cb (buffered tool-call JSON, tool-input deltas), so a model that
+// is steadily producing output is never cut off, while a stalled
+// stream is. The returned stop function releases the watchdog and must
+// be called once the stream is finished. A non-positive idle returns
+// ctx unchanged with a no-op stop.
+//
+// After the call returns, [IdleTimeoutErr] converts a cancellation
+// caused by the watchdog into an [ErrIdleTimeout] error.
+func WithIdleTimeout(ctx context.Context, cb StreamCallback, idle time.Duration) (context.Context, StreamCallback, func()) {
+	if idle <= 0 {
+		return ctx, cb, func() {}
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	timer := time.AfterFunc(idle, func() {
+		cancel(fmt.Errorf("%w: no stream activity for %s", ErrIdleTimeout, idle))
+	})
+	touch := func() { timer.Reset(idle) }
+	ctx = context.WithValue(ctx, idleTouchKey{}, touch)
+	wrapped := func(event StreamEvent) {
+		touch()
+		if cb != nil {
+			cb(event)
+		}
+	}
+	stop := func() {
+		timer.Stop()
+		cancel(nil)
+	}
+	return ctx, wrapped, stop
+}
+
+// idleTouchKey carries the idle watchdog's touch function in a
+// context returned by [WithIdleTimeout].
+type idleTouchKey struct{}
+
+// ActivityReader wraps a provider's streaming response body so that
+// every successful read counts as stream activity for the idle
+// watchdog in ctx. Without a watchdog it returns r unchanged.
+func ActivityReader(ctx context.Context, r io.Reader) io.Reader {
+	touch, ok := ctx.Value(idleTouchKey{}).(func())
+	if !ok {
+		return r
+	}
+	return &activityReader{r: r, touch: touch}
+}
+
+type activityReader struct {
+	r     io.Reader
+	touch func()
+}
+
+func (a *activityReader) Read(p []byte) (int, error) {
+	n, err := a.r.Read(p)
+	if n > 0 {
+		a.touch()
+	}
+	return n, err
+}
+
+// IdleTimeoutErr returns err rewritten as an [ErrIdleTimeout] error
+// when ctx (as returned by [WithIdleTimeout]) was cancelled by its idle
+// watchdog, and err unchanged otherwise.
+func IdleTimeoutErr(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	cause := context.Cause(ctx)
+	if cause == nil || !errors.Is(cause, ErrIdleTimeout) {
+		return err
+	}
+	return fmt.Errorf("%w (%w)", cause, context.DeadlineExceeded)
+}
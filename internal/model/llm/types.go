@@ -129,6 +129,10 @@ type StreamEvent struct {
 	ToolResult string
 	ToolError  string
 
+	// Progress is a short status message from a running tool, set for
+	// KindToolCallProgress events along with ToolName.
+	Progress string
+
 	// Response is set for KindDone events (final summary).
 	Response *ChatResponse
 
@@ -163,6 +167,16 @@ const (
 	// Response.Model carries the selected model name so consumers
 	// can display it before the call completes.
 	KindLLMStart
+
+	// KindHeartbeat is a no-op keepalive emitted during long silences
+	// between real events (see [WithHeartbeat]). It carries no data;
+	// transports that can, forward it as a comment or ping frame.
+	KindHeartbeat
+
+	// KindToolCallProgress fires when a running tool reports progress
+	// (see tools.ReportProgress). It always falls between the call's
+	// KindToolCallStart and KindToolCallDone events.
+	KindToolCallProgress
 )
 
 // StreamCallback receives streaming events.
@@ -12,6 +12,12 @@ const EmptyResponseNudge = "You executed tool calls but did not provide a respon
 // max-iterations recovery).
 const EmptyResponseFallback = "I processed your request but wasn't able to compose a response. Please try again."
 
+// ToolErrorReflection is the nudge injected after several consecutive
+// tool errors in one turn. It asks the model to diagnose the failures
+// before acting again instead of retrying the same call blindly. The
+// "%d" placeholder is replaced with the consecutive error count.
+const ToolErrorReflection = "[System] Your last %d tool calls failed. Before calling another tool, stop and reflect: read the error messages above and diagnose why the calls failed. Then decide on one of: retry with corrected arguments, use a different tool that can achieve the goal, or stop and explain the problem to the user. Do not repeat a call that has already failed the same way."
+
 // InteractiveEmptyResponseFallback is a safer user-visible fallback for
 // interactive loops that must return something even when the model ends
 // the turn without content.
@@ -0,0 +1,37 @@
+package prompts
+
+import "fmt"
+
+// ConfidenceGateSystem is the system prompt for the pre-action
+// confidence check run before a mutating tool call on an autonomous
+// (non-user) turn.
+const ConfidenceGateSystem = "You are auditing an action an autonomous agent is about to take without a human in the loop. Judge whether the action is clearly warranted by the task and safe to take unattended. Reply with a single number between 0.0 and 1.0 and nothing else."
+
+// confidenceGateTemplate is the user-role prompt for the confidence
+// check. Format verbs: task text, tool name, tool arguments JSON.
+const confidenceGateTemplate = `Task that triggered this run:
+%s
+
+Proposed action:
+tool: %s
+arguments: %s
+
+How confident are you that this exact action is correct, intended, and safe to take now without asking a human? Reply with one number from 0.0 (not at all) to 1.0 (certain).`
+
+// ConfidenceGatePrompt returns the fully interpolated confidence-check
+// prompt for one proposed tool call.
+func ConfidenceGatePrompt(task, tool, argsJSON string) string {
+	return fmt.Sprintf(confidenceGateTemplate, task, tool, argsJSON)
+}
+
+// ConfidenceGateDeferred returns the tool result injected in place of a
+// deferred action, so the model knows the call did not run and must not
+// be retried in this turn. notifyErr is empty when the approval request
+// was delivered.
+func ConfidenceGateDeferred(tool string, confidence, threshold float64, notifyErr string) string {
+	msg := fmt.Sprintf("Action deferred: %s was NOT executed. Confidence %.2f is below the autonomous-action threshold %.2f, so the action needs human approval.", tool, confidence, threshold)
+	if notifyErr != "" {
+		return msg + " The approval request could not be delivered (" + notifyErr + "). Do not retry this action in this run; record what you intended instead."
+	}
+	return msg + " An approval request has been sent. Do not retry this action in this run."
+}
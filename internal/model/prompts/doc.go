@@ -9,4 +9,11 @@
 // Convention: each prompt category gets its own file (extraction.go,
 // metadata.go, compaction.go) with an exported function that accepts the
 // dynamic parts and returns the fully interpolated prompt string.
+//
+// The fact extraction and session metadata prompts are tuned by
+// experiment, so they resolve through a [Registry] instead: a file named
+// after the template (fact_extraction.txt, session_metadata.txt) in the
+// configured override directory replaces the built-in text at startup,
+// and each rendered prompt carries a version tag that is recorded with
+// the call's usage.
 package prompts
@@ -1,10 +1,9 @@
 package prompts
 
-import "fmt"
-
-// factExtractionTemplate is the prompt sent to a local LLM to extract
-// noteworthy facts from a single interaction. The three format verbs are
-// user message, assistant response, and recent conversation transcript.
+// factExtractionTemplate is the built-in prompt sent to a local LLM to
+// extract noteworthy facts from a single interaction. The three format
+// verbs are user message, assistant response, and recent conversation
+// transcript.
 const factExtractionTemplate = `Extract noteworthy facts from this interaction that would be useful to
 remember for future conversations. Focus on:
 - User preferences (temperature, lighting, schedules, routines)
@@ -39,8 +38,9 @@ Recent context:
 JSON:`
 
 // FactExtractionPrompt returns the fully interpolated prompt for automatic
-// fact extraction. The caller passes the current user message, assistant
-// response, and a recent conversation transcript for additional context.
-func FactExtractionPrompt(userMsg, assistantResp, transcript string) string {
-	return fmt.Sprintf(factExtractionTemplate, userMsg, assistantResp, transcript)
+// fact extraction and the version of the template that produced it. The
+// caller passes the current user message, assistant response, and a
+// recent conversation transcript for additional context.
+func (r *Registry) FactExtractionPrompt(userMsg, assistantResp, transcript string) (prompt, version string) {
+	return r.render(FactExtraction, userMsg, assistantResp, transcript)
 }
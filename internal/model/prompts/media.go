@@ -59,6 +59,47 @@ const reduceSummarySection = `
 Produce a thorough summary of 2000-3000 characters. Cover all major topics
 and preserve key details.`
 
+// chapterSummaryTemplate is the map-phase prompt for one chapter of a
+// chaptered transcript. Format verbs: 1: chapter title, 2: chapter start
+// time, 3: part label (empty unless the chapter is split), 4: chapter
+// text.
+const chapterSummaryTemplate = `Summarize the chapter %q of a media transcript, which starts at %s%s.
+
+Extract the key points, arguments, and noteworthy details. Preserve specific
+numbers, names, dates, and claims. Aim for roughly 1/5 the length of the input.
+
+Chapter transcript:
+%s
+
+Summary:`
+
+// chapterReduceTemplate is the reduce-phase prompt for a chaptered
+// transcript. The single format verb is the chapter summaries, each
+// under its heading line.
+const chapterReduceTemplate = `Combine these chapter summaries into a summary of the full media transcript,
+organized by chapter. Keep each chapter's heading line exactly as given,
+including its [timestamp], in the original order, and follow it with that
+chapter's summary. Eliminate redundancy between chapters.
+
+Chapter summaries:
+%s
+
+Chapter-by-chapter summary:`
+
+// chapterBriefSection is appended to the chapter reduce prompt when the
+// caller requests a brief summary.
+const chapterBriefSection = `
+
+Be very concise. Keep the whole summary to roughly 500 characters: each
+heading followed by one short line, leaving out chapters with nothing notable.`
+
+// chapterSummarySection is appended to the chapter reduce prompt for the
+// default summary detail level.
+const chapterSummarySection = `
+
+Produce a thorough summary of 2000-3000 characters in total, giving each
+chapter space in proportion to its content and preserving key details.`
+
 // TranscriptChunkSummaryPrompt returns the prompt for summarizing a single
 // chunk of a transcript during the map phase. When focus is non-empty, the
 // prompt instructs the model to emphasize content related to the focus topic.
@@ -88,3 +129,39 @@ func TranscriptReducePrompt(chunkSummaries, focus, detail string) string {
 	}
 	return sb.String()
 }
+
+// TranscriptChapterSummaryPrompt returns the map-phase prompt for one
+// chapter of a transcript. A chapter too long for one call is split,
+// and partIndex and totalParts number the parts; totalParts of 1 means
+// the whole chapter. When focus is non-empty, the prompt instructs the
+// model to emphasize content related to the focus topic.
+func TranscriptChapterSummaryPrompt(text, focus, title, start string, partIndex, totalParts int) string {
+	part := ""
+	if totalParts > 1 {
+		part = fmt.Sprintf(" (part %d of %d)", partIndex, totalParts)
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(chapterSummaryTemplate, title, start, part, text))
+	if focus != "" {
+		sb.WriteString(fmt.Sprintf(chunkFocusSection, focus))
+	}
+	return sb.String()
+}
+
+// TranscriptChapterReducePrompt returns the reduce-phase prompt that
+// combines chapter summaries, each under its "## [MM:SS] Title" heading,
+// into a chapter-structured summary. The detail and focus parameters
+// behave as in [TranscriptReducePrompt].
+func TranscriptChapterReducePrompt(chapterSummaries, focus, detail string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(chapterReduceTemplate, chapterSummaries))
+	if focus != "" {
+		sb.WriteString(fmt.Sprintf(reduceFocusSection, focus))
+	}
+	if detail == "brief" {
+		sb.WriteString(chapterBriefSection)
+	} else {
+		sb.WriteString(chapterSummarySection)
+	}
+	return sb.String()
+}
@@ -146,3 +146,36 @@ func TestTranscriptReducePrompt(t *testing.T) {
 		})
 	}
 }
+
+func TestTranscriptChapterSummaryPrompt(t *testing.T) {
+	got := TranscriptChapterSummaryPrompt("chapter text", "", "Recycling", "14:20", 1, 1)
+	for _, want := range []string{`"Recycling"`, "starts at 14:20.", "chapter text"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+	if strings.Contains(got, "part ") || strings.Contains(got, "Focus on:") {
+		t.Errorf("whole-chapter prompt without focus has a part label or focus:\n%s", got)
+	}
+
+	got = TranscriptChapterSummaryPrompt("chapter text", "costs", "Recycling", "14:20", 2, 3)
+	for _, want := range []string{"starts at 14:20 (part 2 of 3).", "Focus on: costs"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+}
+
+func TestTranscriptChapterReducePrompt(t *testing.T) {
+	summaries := "## [0:00] Intro\n\nhello"
+	brief := TranscriptChapterReducePrompt(summaries, "", "brief")
+	if !strings.Contains(brief, summaries) || !strings.Contains(brief, "roughly 500 characters") {
+		t.Errorf("brief prompt:\n%s", brief)
+	}
+	full := TranscriptChapterReducePrompt(summaries, "batteries", "summary")
+	for _, want := range []string{"[timestamp]", "2000-3000 characters", "Focus on: batteries"} {
+		if !strings.Contains(full, want) {
+			t.Errorf("summary prompt missing %q", want)
+		}
+	}
+}
@@ -1,10 +1,8 @@
 package prompts
 
-import "fmt"
-
-// metadataTemplate is the prompt sent to an LLM to generate structured session
-// metadata from a conversation transcript. The single format verb is the
-// transcript text.
+// metadataTemplate is the built-in prompt sent to an LLM to generate
+// structured session metadata from a conversation transcript. The single
+// format verb is the transcript text.
 const metadataTemplate = `Analyze this conversation session and produce structured metadata as JSON. 
 The JSON must have exactly these fields:
 
@@ -27,7 +25,8 @@ Conversation:
 JSON:`
 
 // MetadataPrompt returns the fully interpolated prompt for session metadata
-// generation. The caller passes the conversation transcript to be analyzed.
-func MetadataPrompt(transcript string) string {
-	return fmt.Sprintf(metadataTemplate, transcript)
+// generation and the version of the template that produced it. The
+// caller passes the conversation transcript to be analyzed.
+func (r *Registry) MetadataPrompt(transcript string) (prompt, version string) {
+	return r.render(SessionMetadata, transcript)
 }
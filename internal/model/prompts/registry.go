@@ -0,0 +1,222 @@
+package prompts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Names of the templates a [Registry] resolves. An override file for a
+// template is named after it with a .txt extension, e.g.
+// fact_extraction.txt.
+const (
+	FactExtraction  = "fact_extraction"
+	SessionMetadata = "session_metadata"
+)
+
+// overrideExt is the extension of override template files.
+const overrideExt = ".txt"
+
+// versionHeader, when it starts the first line of an override file,
+// names the file's version tag. The line is not part of the template.
+const versionHeader = "# version:"
+
+// builtins are the compiled-in templates and the number of %s
+// placeholders each takes.
+var builtins = map[string]struct {
+	text         string
+	placeholders int
+}{
+	FactExtraction:  {factExtractionTemplate, 3},
+	SessionMetadata: {metadataTemplate, 1},
+}
+
+// Template is a named prompt template. Text is a fmt format string
+// whose only verbs are %s placeholders and %% escapes.
+type Template struct {
+	Name string
+
+	// Version identifies the exact text. Built-in templates are
+	// "builtin-" plus a content hash; overrides use the tag from their
+	// version header, or "override-" plus a content hash without one.
+	// Usage records carry it so results can be compared across prompt
+	// revisions.
+	Version string
+
+	// Source is "builtin" or the path of the override file.
+	Source string
+
+	Text string
+}
+
+// Registry resolves prompt templates by name. It starts from the
+// built-in templates and replaces any that have an override file. A
+// nil *Registry resolves the built-ins.
+type Registry struct {
+	templates map[string]Template
+}
+
+// builtinRegistry backs nil registries.
+var builtinRegistry = NewRegistry()
+
+// NewRegistry returns a registry of the built-in templates.
+func NewRegistry() *Registry {
+	r := &Registry{templates: make(map[string]Template, len(builtins))}
+	for name, b := range builtins {
+		r.templates[name] = Template{
+			Name:    name,
+			Version: "builtin-" + contentHash(b.text),
+			Source:  "builtin",
+			Text:    b.text,
+		}
+	}
+	return r
+}
+
+// LoadRegistry returns a registry of the built-in templates with those
+// overridden by dir replaced. Each <name>.txt file in dir overrides the
+// template of that name; other files are ignored. An empty dir loads no
+// overrides. Every override is validated here, so a template with the
+// wrong placeholders, or a .txt file naming no template, fails the load
+// instead of the first call that uses it.
+func LoadRegistry(dir string) (*Registry, error) {
+	r := NewRegistry()
+	if dir == "" {
+		return r, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read prompt override directory: %w", err)
+	}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), overrideExt)
+		if !ok || e.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		b, known := builtins[name]
+		if !known {
+			return nil, fmt.Errorf("prompt override %s: unknown template %q (known: %s)",
+				e.Name(), name, strings.Join(Names(), ", "))
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read prompt override: %w", err)
+		}
+		tmpl, err := parseOverride(name, path, string(data), b.placeholders)
+		if err != nil {
+			return nil, fmt.Errorf("prompt override %s: %w", path, err)
+		}
+		r.templates[name] = tmpl
+	}
+	return r, nil
+}
+
+// parseOverride builds the override template for name from the
+// contents of the file at path.
+func parseOverride(name, path, data string, placeholders int) (Template, error) {
+	text := data
+	version := ""
+	first, rest, _ := strings.Cut(data, "\n")
+	if tag, ok := strings.CutPrefix(first, versionHeader); ok {
+		version = strings.TrimSpace(tag)
+		if version == "" {
+			return Template{}, fmt.Errorf("empty version header")
+		}
+		text = rest
+	}
+	text = strings.TrimRight(text, "\r\n")
+	if strings.TrimSpace(text) == "" {
+		return Template{}, fmt.Errorf("template is empty")
+	}
+	if err := checkPlaceholders(text, placeholders); err != nil {
+		return Template{}, err
+	}
+	if version == "" {
+		version = "override-" + contentHash(text)
+	}
+	return Template{Name: name, Version: version, Source: path, Text: text}, nil
+}
+
+// checkPlaceholders reports whether text interpolates exactly want
+// arguments. Only %s and %% are accepted so an override cannot produce
+// fmt's %!verb(MISSING) noise at call time.
+func checkPlaceholders(text string, want int) error {
+	got := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] != '%' {
+			continue
+		}
+		if i+1 == len(text) {
+			return fmt.Errorf("trailing %% at end of template; use %%%% for a literal percent sign")
+		}
+		switch text[i+1] {
+		case 's':
+			got++
+		case '%':
+		default:
+			line := strings.Count(text[:i], "\n") + 1
+			return fmt.Errorf("unsupported format directive %q on line %d; only %%s placeholders are allowed (use %%%% for a literal percent sign)",
+				text[i:i+2], line)
+		}
+		i++
+	}
+	if got != want {
+		return fmt.Errorf("template has %d %%s placeholders, want %d", got, want)
+	}
+	return nil
+}
+
+// contentHash returns a short stable hash of text for version tags.
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:4])
+}
+
+// Names returns the names of the templates a registry resolves, sorted.
+func Names() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Template returns the template registered under name.
+func (r *Registry) Template(name string) (Template, bool) {
+	if r == nil {
+		r = builtinRegistry
+	}
+	t, ok := r.templates[name]
+	return t, ok
+}
+
+// Overrides returns the templates loaded from override files, sorted by
+// name.
+func (r *Registry) Overrides() []Template {
+	if r == nil {
+		return nil
+	}
+	var out []Template
+	for _, name := range Names() {
+		if t := r.templates[name]; t.Source != "builtin" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// render interpolates the named template and returns it with the
+// template's version.
+func (r *Registry) render(name string, args ...any) (prompt, version string) {
+	t, ok := r.Template(name)
+	if !ok {
+		panic("prompts: unregistered template " + name)
+	}
+	return fmt.Sprintf(t.Text, args...), t.Version
+}
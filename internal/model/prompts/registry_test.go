@@ -0,0 +1,101 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeOverride(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuiltinTemplatesValidate(t *testing.T) {
+	for name, b := range builtins {
+		if err := checkPlaceholders(b.text, b.placeholders); err != nil {
+			t.Errorf("builtin %s: %v", name, err)
+		}
+	}
+}
+
+func TestNilRegistryUsesBuiltins(t *testing.T) {
+	var r *Registry
+	prompt, version := r.FactExtractionPrompt("I like tea", "Noted.", "[user] hi")
+	if !strings.Contains(prompt, "User: I like tea\nAssistant: Noted.") {
+		t.Errorf("prompt missing interpolated exchange:\n%s", prompt)
+	}
+	if !strings.HasPrefix(version, "builtin-") {
+		t.Errorf("version = %q, want builtin-<hash>", version)
+	}
+	if _, v := NewRegistry().FactExtractionPrompt("", "", ""); v != version {
+		t.Errorf("builtin version unstable: %q vs %q", v, version)
+	}
+}
+
+func TestLoadRegistry(t *testing.T) {
+	dir := t.TempDir()
+	writeOverride(t, dir, "fact_extraction.txt", "# version: extract-b\nFacts from %s / %s, 100%% sure.\n%s\n")
+	writeOverride(t, dir, "session_metadata.txt", "Summarize:\n%s\n")
+	writeOverride(t, dir, "README.md", "notes about these prompts")
+
+	r, err := LoadRegistry(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prompt, version := r.FactExtractionPrompt("u", "a", "ctx")
+	if prompt != "Facts from u / a, 100% sure.\nctx" || version != "extract-b" {
+		t.Errorf("fact extraction = %q (%s), want the override tagged extract-b", prompt, version)
+	}
+	prompt, version = r.MetadataPrompt("t")
+	if prompt != "Summarize:\nt" || !strings.HasPrefix(version, "override-") {
+		t.Errorf("metadata = %q (%s), want the untagged override", prompt, version)
+	}
+	if got := len(r.Overrides()); got != 2 {
+		t.Errorf("overrides = %d, want 2", got)
+	}
+}
+
+func TestLoadRegistry_Empty(t *testing.T) {
+	r, err := LoadRegistry("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Overrides()) != 0 {
+		t.Error("empty override dir loaded overrides")
+	}
+	if _, err := LoadRegistry(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("missing override dir loaded without error")
+	}
+}
+
+func TestLoadRegistry_RejectsMalformed(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		content   string
+		wantError string
+	}{
+		{name: "too few placeholders", file: "fact_extraction.txt", content: "%s and %s", wantError: "has 2 %s placeholders, want 3"},
+		{name: "too many placeholders", file: "session_metadata.txt", content: "%s %s", wantError: "has 2 %s placeholders, want 1"},
+		{name: "other verb", file: "session_metadata.txt", content: "Rate 0-100%.\n%s", wantError: `unsupported format directive "%." on line 1`},
+		{name: "trailing percent", file: "session_metadata.txt", content: "%s at 100%", wantError: "trailing %"},
+		{name: "empty", file: "session_metadata.txt", content: "# version: v2\n\n", wantError: "template is empty"},
+		{name: "empty version", file: "session_metadata.txt", content: "# version:\n%s", wantError: "empty version header"},
+		{name: "unknown template", file: "title.txt", content: "%s", wantError: `unknown template "title"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeOverride(t, dir, tt.file, tt.content)
+			_, err := LoadRegistry(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Fatalf("error = %v, want %q", err, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package prompts
+
+import "fmt"
+
+// conversationTitleTemplate is the prompt sent to a local LLM to name a
+// live conversation, or to decide whether an existing title still fits.
+// The two format verbs are the current title (or a placeholder when the
+// conversation is untitled) and the recent conversation transcript.
+const conversationTitleTemplate = `Give this conversation a short title (3-8 words) that describes what it is
+about. Use plain words, no quotes, no trailing punctuation.
+
+Current title: %s
+
+If there is a current title, keep it unless the conversation has clearly
+moved on to a different subject. Small follow-ups, clarifications, and
+related questions are NOT a topic shift.
+
+Return JSON only. Examples:
+
+{"title": "Living room lighting schedule", "topic_shifted": false}
+
+{"title": "Planning the garden irrigation", "topic_shifted": true}
+
+Recent conversation:
+%s
+
+JSON:`
+
+// ConversationTitlePrompt returns the fully interpolated prompt for
+// running conversation titles. An empty current title asks for a fresh
+// title; otherwise the model reports whether the topic has shifted.
+func ConversationTitlePrompt(currentTitle, transcript string) string {
+	if currentTitle == "" {
+		currentTitle = "(none)"
+	}
+	return fmt.Sprintf(conversationTitleTemplate, currentTitle, transcript)
+}
@@ -0,0 +1,283 @@
+package router
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FactorCritical marks a request as critical when "true". Critical
+// requests keep their normal routing while spend is within the budget
+// margin; only the hard ceiling restricts them.
+const FactorCritical = "critical"
+
+// Budget states reported in [BudgetStatus.State] and
+// [Decision.BudgetState].
+const (
+	// BudgetStateOK means spend is below every ceiling's margin, or no
+	// ceiling is configured.
+	BudgetStateOK = "ok"
+	// BudgetStateDownshift means spend is within the margin of a
+	// ceiling: non-critical requests are routed as local_only and may
+	// not use paid models.
+	BudgetStateDownshift = "downshift"
+	// BudgetStateBlocked means a ceiling has been reached and paid
+	// models are not routed to at all.
+	BudgetStateBlocked = "blocked"
+)
+
+// budgetSpendTTL bounds how often the router asks the spend source for
+// fresh totals. Routing happens on every turn; spend moves slowly
+// enough that a short cache keeps the usage database out of the hot
+// path without letting a runaway loop get far past a ceiling.
+const budgetSpendTTL = 30 * time.Second
+
+// SpendFunc reports the USD spent on model calls in [start, end).
+type SpendFunc func(start, end time.Time) (float64, error)
+
+// Budget caps spend on paid models (CostTier > 0). Ceilings are
+// calendar-based in Location: the daily ceiling resets at midnight and
+// the monthly ceiling on the first of the month. A zero ceiling is
+// unlimited.
+type Budget struct {
+	DailyUSD   float64
+	MonthlyUSD float64
+
+	// Margin is the fraction of a ceiling, 0 to 1, below it at which
+	// routing starts downshifting. With a $10 ceiling and a margin of
+	// 0.1, downshifting starts at $9 of spend.
+	Margin float64
+
+	// Spend reports spend-to-date, normally from the usage store.
+	Spend SpendFunc
+
+	// Location sets the day and month boundaries. Nil means local time.
+	Location *time.Location
+}
+
+// Enabled reports whether at least one ceiling is set and spend can be
+// measured.
+func (b Budget) Enabled() bool {
+	return b.Spend != nil && (b.DailyUSD > 0 || b.MonthlyUSD > 0)
+}
+
+// BudgetStatus is the router's view of spend against its [Budget].
+type BudgetStatus struct {
+	State             string    `json:"state"`
+	DailySpentUSD     float64   `json:"daily_spent_usd"`
+	DailyCeilingUSD   float64   `json:"daily_ceiling_usd,omitempty"`
+	MonthlySpentUSD   float64   `json:"monthly_spent_usd"`
+	MonthlyCeilingUSD float64   `json:"monthly_ceiling_usd,omitempty"`
+	Margin            float64   `json:"margin"`
+	CheckedAt         time.Time `json:"checked_at"`
+	Error             string    `json:"error,omitempty"`
+}
+
+// budgetGuard holds the configured budget and the cached spend status.
+type budgetGuard struct {
+	mu     sync.Mutex
+	budget Budget
+	status BudgetStatus
+}
+
+// SetBudget installs or replaces the spend budget. A budget with no
+// ceilings or no spend source disables enforcement.
+func (r *Router) SetBudget(b Budget) {
+	r.budget.mu.Lock()
+	defer r.budget.mu.Unlock()
+	r.budget.budget = b
+	r.budget.status = BudgetStatus{}
+}
+
+// BudgetStatus returns current spend against the configured budget,
+// refreshing it when the cached totals are stale. ok is false when no
+// budget is configured.
+func (r *Router) BudgetStatus() (status BudgetStatus, ok bool) {
+	return r.budget.current(time.Now())
+}
+
+func (g *budgetGuard) current(now time.Time) (BudgetStatus, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	b := g.budget
+	if !b.Enabled() {
+		return BudgetStatus{State: BudgetStateOK}, false
+	}
+	if !g.status.CheckedAt.IsZero() && now.Sub(g.status.CheckedAt) < budgetSpendTTL {
+		return g.status, true
+	}
+
+	loc := b.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	local := now.In(loc)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	monthStart := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, loc)
+
+	status := BudgetStatus{
+		State:             BudgetStateOK,
+		DailyCeilingUSD:   b.DailyUSD,
+		MonthlyCeilingUSD: b.MonthlyUSD,
+		Margin:            b.Margin,
+		CheckedAt:         now,
+	}
+	var err error
+	if b.DailyUSD > 0 {
+		status.DailySpentUSD, err = b.Spend(dayStart, now)
+	}
+	if err == nil && b.MonthlyUSD > 0 {
+		status.MonthlySpentUSD, err = b.Spend(monthStart, now)
+	}
+	if err != nil {
+		// Fail open: a broken usage store must not take routing down
+		// with it. The error surfaces on the health endpoint instead.
+		status.Error = err.Error()
+		g.status = status
+		return status, true
+	}
+
+	status.State = budgetState(status.DailySpentUSD, b.DailyUSD, b.Margin)
+	if monthly := budgetState(status.MonthlySpentUSD, b.MonthlyUSD, b.Margin); budgetRank(monthly) > budgetRank(status.State) {
+		status.State = monthly
+	}
+	g.status = status
+	return status, true
+}
+
+// budgetState classifies spend against one ceiling.
+func budgetState(spent, ceiling, margin float64) string {
+	switch {
+	case ceiling <= 0:
+		return BudgetStateOK
+	case spent >= ceiling:
+		return BudgetStateBlocked
+	case spent >= ceiling*(1-margin):
+		return BudgetStateDownshift
+	default:
+		return BudgetStateOK
+	}
+}
+
+func budgetRank(state string) int {
+	switch state {
+	case BudgetStateBlocked:
+		return 2
+	case BudgetStateDownshift:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// budgetRejection returns why the budget rules out model m for req, or
+// "" when it does not. Free models are never ruled out. Under a
+// downshift req should already carry [budgetFactors], so every
+// non-critical request is local_only and rules out paid models.
+func budgetRejection(status BudgetStatus, req Request, m Model) string {
+	if m.CostTier == 0 {
+		return ""
+	}
+	switch status.State {
+	case BudgetStateBlocked:
+		return "budget ceiling reached"
+	case BudgetStateDownshift:
+		if req.RoutingFactors[FactorLocalOnly] == "true" {
+			return "budget downshift: paid models reserved for critical requests"
+		}
+	}
+	return ""
+}
+
+// BudgetRejection returns why the budget's hard ceiling rules out
+// model, or "" when it does not. It guards the models chosen outside
+// [Router.Route] — an explicit request, the pinned default, recovery
+// and failover models — so a reached ceiling keeps every paid model
+// out, not just routed ones. Free models and models the router does
+// not know are never ruled out.
+func (r *Router) BudgetRejection(model string) string {
+	status, ok := r.BudgetStatus()
+	if !ok || status.State != BudgetStateBlocked {
+		return ""
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, m := range r.config.Models {
+		if m.Name == model {
+			return budgetRejection(status, Request{}, m)
+		}
+	}
+	return ""
+}
+
+// BudgetFallback returns the model to use in place of one the budget
+// ruled out: the default model when it is free, else the
+// highest-quality free model.
+func (r *Router) BudgetFallback() string {
+	return budgetFallback(r.configSnapshot())
+}
+
+// budgetFactors returns the routing factors to route req with under
+// status. Within the margin, non-critical requests are forced to
+// local_only, which keeps paid models out of the candidates. The
+// caller's map is never modified.
+func budgetFactors(status BudgetStatus, req Request) map[string]string {
+	if status.State != BudgetStateDownshift || req.RoutingFactors[FactorCritical] == "true" {
+		return req.RoutingFactors
+	}
+	factors := make(map[string]string, len(req.RoutingFactors)+1)
+	for k, v := range req.RoutingFactors {
+		factors[k] = v
+	}
+	factors[FactorLocalOnly] = "true"
+	return factors
+}
+
+// budgetFallback picks the model to use when the budget has ruled out
+// every candidate and the default model is itself a paid one: the
+// highest-quality free model, or the default when none is configured.
+func budgetFallback(cfg Config) string {
+	best := -1
+	for i, m := range cfg.Models {
+		if m.Name == cfg.DefaultModel && m.CostTier == 0 {
+			return cfg.DefaultModel
+		}
+		if m.CostTier == 0 && (best < 0 || m.Quality > cfg.Models[best].Quality) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return cfg.DefaultModel
+	}
+	return cfg.Models[best].Name
+}
+
+// budgetReasoning describes the budget state for [Decision.Reasoning].
+func budgetReasoning(status BudgetStatus) string {
+	var b strings.Builder
+	switch status.State {
+	case BudgetStateBlocked:
+		b.WriteString("Budget ceiling reached")
+	case BudgetStateDownshift:
+		b.WriteString("Budget downshift")
+	}
+	b.WriteString(" (")
+	if status.DailyCeilingUSD > 0 {
+		b.WriteString("daily " + formatUSD(status.DailySpentUSD) + " of " + formatUSD(status.DailyCeilingUSD))
+	}
+	if status.MonthlyCeilingUSD > 0 {
+		if status.DailyCeilingUSD > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("monthly " + formatUSD(status.MonthlySpentUSD) + " of " + formatUSD(status.MonthlyCeilingUSD))
+	}
+	b.WriteString(").")
+	return b.String()
+}
+
+// formatUSD renders a dollar amount for decision reasoning.
+func formatUSD(v float64) string {
+	return "$" + strconv.FormatFloat(v, 'f', 2, 64)
+}
@@ -0,0 +1,213 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newBudgetTestRouter(defaultModel string) *Router {
+	return NewRouter(slog.Default(), Config{
+		DefaultModel: defaultModel,
+		Models: []Model{
+			{Name: "local-model", Provider: "ollama", SupportsTools: true, Speed: 8, Quality: 5, CostTier: 0, ContextWindow: 8192},
+			{Name: "cloud-model", Provider: "anthropic", SupportsTools: true, Speed: 6, Quality: 10, CostTier: 3, ContextWindow: 200000},
+		},
+		MaxAuditLog: 10,
+	})
+}
+
+func fixedSpend(usd float64) SpendFunc {
+	return func(start, end time.Time) (float64, error) { return usd, nil }
+}
+
+// complexInteractive is a request the cloud model wins on merit when
+// no budget is in play.
+var complexInteractive = Request{
+	Query:      "explain why the energy usage pattern changed",
+	NeedsTools: true,
+	Priority:   PriorityInteractive,
+}
+
+func TestBudgetState(t *testing.T) {
+	tests := []struct {
+		spent, ceiling, margin float64
+		want                   string
+	}{
+		{spent: 5, ceiling: 0, margin: 0.1, want: BudgetStateOK},
+		{spent: 8.99, ceiling: 10, margin: 0.1, want: BudgetStateOK},
+		{spent: 9, ceiling: 10, margin: 0.1, want: BudgetStateDownshift},
+		{spent: 10, ceiling: 10, margin: 0.1, want: BudgetStateBlocked},
+		{spent: 9.99, ceiling: 10, margin: 0, want: BudgetStateOK},
+	}
+	for _, tt := range tests {
+		if got := budgetState(tt.spent, tt.ceiling, tt.margin); got != tt.want {
+			t.Errorf("budgetState(%v, %v, %v) = %q, want %q", tt.spent, tt.ceiling, tt.margin, got, tt.want)
+		}
+	}
+}
+
+func TestRoute_BudgetOKLeavesRoutingAlone(t *testing.T) {
+	r := newBudgetTestRouter("local-model")
+	r.SetBudget(Budget{DailyUSD: 10, Margin: 0.1, Spend: fixedSpend(1)})
+
+	model, decision := r.Route(context.Background(), complexInteractive)
+	if model != "cloud-model" {
+		t.Errorf("Route() = %q, want cloud-model (reasoning: %s)", model, decision.Reasoning)
+	}
+	if decision.BudgetState != "" {
+		t.Errorf("BudgetState = %q, want empty under budget", decision.BudgetState)
+	}
+}
+
+func TestRoute_BudgetDownshift(t *testing.T) {
+	r := newBudgetTestRouter("local-model")
+	r.SetBudget(Budget{DailyUSD: 10, Margin: 0.1, Spend: fixedSpend(9.5)})
+
+	model, decision := r.Route(context.Background(), complexInteractive)
+	if model != "local-model" {
+		t.Errorf("non-critical Route() = %q, want local-model", model)
+	}
+	if decision.BudgetState != BudgetStateDownshift {
+		t.Errorf("BudgetState = %q, want %q", decision.BudgetState, BudgetStateDownshift)
+	}
+	if !strings.Contains(decision.Reasoning, "daily $9.50 of $10.00") {
+		t.Errorf("Reasoning should report spend, got: %s", decision.Reasoning)
+	}
+	if reasons := decision.RejectedModels["cloud-model"]; len(reasons) == 0 {
+		t.Errorf("non-critical request should rule out cloud-model, rejected = %v", decision.RejectedModels)
+	}
+
+	// Even when only the paid model fits, a non-critical request is
+	// not routed to it.
+	large := complexInteractive
+	large.ContextSize = 100000
+	if model, decision := r.Route(context.Background(), large); model != "local-model" || !decision.NoEligible {
+		t.Errorf("non-critical Route() needing the paid model = %q (no eligible %v), want local-model fallback (reasoning: %s)", model, decision.NoEligible, decision.Reasoning)
+	}
+
+	critical := complexInteractive
+	critical.RoutingFactors = map[string]string{FactorCritical: "true"}
+	if model, decision := r.Route(context.Background(), critical); model != "cloud-model" {
+		t.Errorf("critical Route() = %q, want cloud-model (reasoning: %s)", model, decision.Reasoning)
+	}
+
+	background := Request{
+		Query:          "explain the overnight pattern",
+		NeedsTools:     true,
+		Priority:       PriorityBackground,
+		RoutingFactors: map[string]string{FactorLocalOnly: "false"},
+	}
+	_, decision = r.Route(context.Background(), background)
+	if reasons := decision.RejectedModels["cloud-model"]; len(reasons) == 0 {
+		t.Errorf("background request should reject cloud-model outright, rejected = %v", decision.RejectedModels)
+	}
+	if background.RoutingFactors[FactorLocalOnly] != "false" {
+		t.Error("budget downshift must not modify the caller's routing factors")
+	}
+}
+
+func TestRoute_BudgetBlockedFallsBackToLocal(t *testing.T) {
+	r := newBudgetTestRouter("cloud-model")
+	r.SetBudget(Budget{MonthlyUSD: 100, Margin: 0.1, Spend: fixedSpend(120)})
+
+	critical := complexInteractive
+	critical.RoutingFactors = map[string]string{FactorCritical: "true"}
+	critical.ContextSize = 100000 // only the cloud model fits
+	model, decision := r.Route(context.Background(), critical)
+	if model != "local-model" {
+		t.Errorf("Route() at ceiling = %q, want local-model fallback", model)
+	}
+	if !decision.NoEligible || decision.BudgetState != BudgetStateBlocked {
+		t.Errorf("decision = %+v, want no eligible models under a blocked budget", decision)
+	}
+	if !strings.Contains(decision.Reasoning, "monthly $120.00 of $100.00") {
+		t.Errorf("Reasoning should report spend, got: %s", decision.Reasoning)
+	}
+}
+
+func TestBudgetStatus_CalendarWindows(t *testing.T) {
+	var windows [][2]time.Time
+	r := newBudgetTestRouter("local-model")
+	r.SetBudget(Budget{DailyUSD: 10, MonthlyUSD: 100, Location: time.UTC, Spend: func(start, end time.Time) (float64, error) {
+		windows = append(windows, [2]time.Time{start, end})
+		return 0, nil
+	}})
+
+	now := time.Date(2026, 3, 15, 12, 30, 0, 0, time.UTC)
+	if _, ok := r.budget.current(now); !ok {
+		t.Fatal("current() ok = false with a budget configured")
+	}
+	want := [][2]time.Time{
+		{time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), now},
+		{time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), now},
+	}
+	if len(windows) != len(want) || windows[0] != want[0] || windows[1] != want[1] {
+		t.Errorf("spend windows = %v, want %v", windows, want)
+	}
+
+	// Within the cache TTL the spend source is not queried again.
+	r.budget.current(now.Add(budgetSpendTTL / 2))
+	if len(windows) != 2 {
+		t.Errorf("spend queried %d times within TTL, want 2", len(windows))
+	}
+}
+
+func TestBudgetStatus_SpendErrorFailsOpen(t *testing.T) {
+	r := newBudgetTestRouter("local-model")
+	r.SetBudget(Budget{DailyUSD: 10, Spend: func(start, end time.Time) (float64, error) {
+		return 0, errors.New("database is locked")
+	}})
+
+	status, ok := r.BudgetStatus()
+	if !ok {
+		t.Fatal("BudgetStatus() ok = false with a budget configured")
+	}
+	if status.State != BudgetStateOK || status.Error == "" {
+		t.Errorf("status = %+v, want ok state carrying the spend error", status)
+	}
+	if model, _ := r.Route(context.Background(), complexInteractive); model != "cloud-model" {
+		t.Errorf("Route() = %q, want normal routing when spend is unknown", model)
+	}
+}
+
+func TestBudgetStatus_Disabled(t *testing.T) {
+	r := newBudgetTestRouter("local-model")
+	if _, ok := r.BudgetStatus(); ok {
+		t.Error("BudgetStatus() ok = true with no budget configured")
+	}
+	r.SetBudget(Budget{Spend: fixedSpend(50)})
+	if _, ok := r.BudgetStatus(); ok {
+		t.Error("BudgetStatus() ok = true with no ceilings set")
+	}
+}
+
+func TestBudgetRejection(t *testing.T) {
+	r := newBudgetTestRouter("cloud-model")
+	if reason := r.BudgetRejection("cloud-model"); reason != "" {
+		t.Errorf("BudgetRejection() with no budget = %q, want empty", reason)
+	}
+
+	// Within the margin only routed requests are steered; a model picked
+	// some other way is still allowed.
+	r.SetBudget(Budget{DailyUSD: 10, Margin: 0.1, Spend: fixedSpend(9.5)})
+	if reason := r.BudgetRejection("cloud-model"); reason != "" {
+		t.Errorf("BudgetRejection() in downshift = %q, want empty", reason)
+	}
+
+	r.SetBudget(Budget{DailyUSD: 10, Margin: 0.1, Spend: fixedSpend(10)})
+	if reason := r.BudgetRejection("cloud-model"); reason == "" {
+		t.Error("BudgetRejection(cloud-model) at ceiling = empty, want a reason")
+	}
+	for _, model := range []string{"local-model", "unknown-model"} {
+		if reason := r.BudgetRejection(model); reason != "" {
+			t.Errorf("BudgetRejection(%s) at ceiling = %q, want empty", model, reason)
+		}
+	}
+	if got := r.BudgetFallback(); got != "local-model" {
+		t.Errorf("BudgetFallback() = %q, want local-model", got)
+	}
+}
@@ -0,0 +1,81 @@
+package router
+
+// ResourceReadyFunc reports whether the provider resource with the
+// given ID is currently reachable. It is normally backed by the
+// resource's connection watcher.
+type ResourceReadyFunc func(resourceID string) bool
+
+// SetFallbackChains installs the ordered failover chains, keyed by
+// primary model. Chains are held apart from [Config] so that
+// [Router.UpdateConfig] catalog swaps leave them in place. A nil or
+// empty map removes every chain.
+func (r *Router) SetFallbackChains(chains map[string][]string) {
+	copied := make(map[string][]string, len(chains))
+	for primary, chain := range chains {
+		copied[primary] = append([]string(nil), chain...)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallbackChains = copied
+}
+
+// SetResourceReady installs the health check [Router.FallbackChain]
+// uses to skip models on unreachable resources. Nil treats every
+// resource as reachable.
+func (r *Router) SetResourceReady(fn ResourceReadyFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resourceReady = fn
+}
+
+// FallbackChain returns the models to fail over to, in order, when a
+// call to model errors. Models whose resource is currently unreachable
+// are skipped, as are paid models once the budget ceiling has been
+// reached, repeats, and model itself. The result does not include the
+// default model; callers append their own last resort and check it
+// with [Router.BudgetRejection]. It is empty when model has no
+// configured chain.
+func (r *Router) FallbackChain(model string) []string {
+	r.mu.RLock()
+	configured := r.fallbackChains[model]
+	ready := r.resourceReady
+	models := r.config.Models
+	r.mu.RUnlock()
+
+	if len(configured) == 0 {
+		return nil
+	}
+	byName := make(map[string]Model, len(models))
+	for _, m := range models {
+		byName[m.Name] = m
+	}
+	budget, _ := r.BudgetStatus()
+
+	chain := make([]string, 0, len(configured))
+	seen := map[string]bool{model: true}
+	for _, hop := range configured {
+		if seen[hop] {
+			continue
+		}
+		seen[hop] = true
+		m := byName[hop]
+		if res := m.ResourceID; res != "" && ready != nil && !ready(res) {
+			r.logger.Debug("skipping fallback model on unreachable resource",
+				"model", hop,
+				"resource", res,
+			)
+			continue
+		}
+		if budget.State == BudgetStateBlocked {
+			if reason := budgetRejection(budget, Request{}, m); reason != "" {
+				r.logger.Debug("skipping fallback model over budget",
+					"model", hop,
+					"reason", reason,
+				)
+				continue
+			}
+		}
+		chain = append(chain, hop)
+	}
+	return chain
+}
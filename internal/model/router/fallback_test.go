@@ -0,0 +1,81 @@
+package router
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+func newFallbackTestRouter() *Router {
+	return NewRouter(slog.Default(), Config{
+		DefaultModel: "local-model",
+		Models: []Model{
+			{Name: "opus", Provider: "anthropic", ResourceID: "anthropic"},
+			{Name: "sonnet", Provider: "anthropic", ResourceID: "anthropic"},
+			{Name: "spark/qwen", Provider: "ollama", ResourceID: "spark"},
+			{Name: "local-model", Provider: "ollama", ResourceID: "default"},
+		},
+	})
+}
+
+func TestFallbackChain(t *testing.T) {
+	r := newFallbackTestRouter()
+	if got := r.FallbackChain("opus"); len(got) != 0 {
+		t.Errorf("FallbackChain() with no chains = %v, want empty", got)
+	}
+
+	r.SetFallbackChains(map[string][]string{
+		"opus": {"sonnet", "opus", "spark/qwen", "sonnet", "unlisted"},
+	})
+	want := []string{"sonnet", "spark/qwen", "unlisted"}
+	if got := r.FallbackChain("opus"); !slices.Equal(got, want) {
+		t.Errorf("FallbackChain(opus) = %v, want %v", got, want)
+	}
+	if got := r.FallbackChain("sonnet"); len(got) != 0 {
+		t.Errorf("FallbackChain(sonnet) = %v, want empty", got)
+	}
+}
+
+func TestFallbackChain_SkipsUnreachableResources(t *testing.T) {
+	r := newFallbackTestRouter()
+	r.SetFallbackChains(map[string][]string{"opus": {"sonnet", "spark/qwen"}})
+	r.SetResourceReady(func(resourceID string) bool { return resourceID != "spark" })
+
+	if got, want := r.FallbackChain("opus"), []string{"sonnet"}; !slices.Equal(got, want) {
+		t.Errorf("FallbackChain(opus) = %v, want %v", got, want)
+	}
+}
+
+func TestFallbackChain_SurvivesUpdateConfig(t *testing.T) {
+	r := newFallbackTestRouter()
+	r.SetFallbackChains(map[string][]string{"opus": {"sonnet"}})
+	r.UpdateConfig(Config{DefaultModel: "sonnet", Models: r.GetModels()})
+
+	if got, want := r.FallbackChain("opus"), []string{"sonnet"}; !slices.Equal(got, want) {
+		t.Errorf("FallbackChain(opus) after UpdateConfig = %v, want %v", got, want)
+	}
+}
+
+func TestFallbackChain_SkipsPaidModelsAtBudgetCeiling(t *testing.T) {
+	r := NewRouter(slog.Default(), Config{
+		DefaultModel: "local-model",
+		Models: []Model{
+			{Name: "opus", CostTier: 4},
+			{Name: "sonnet", CostTier: 3},
+			{Name: "spark/qwen", CostTier: 0},
+			{Name: "local-model", CostTier: 0},
+		},
+	})
+	r.SetFallbackChains(map[string][]string{"opus": {"sonnet", "spark/qwen"}})
+
+	// Within the margin the chain is untouched; only the ceiling blocks.
+	r.SetBudget(Budget{DailyUSD: 10, Margin: 0.5, Spend: fixedSpend(6)})
+	if got, want := r.FallbackChain("opus"), []string{"sonnet", "spark/qwen"}; !slices.Equal(got, want) {
+		t.Errorf("FallbackChain(opus) in downshift = %v, want %v", got, want)
+	}
+
+	r.SetBudget(Budget{DailyUSD: 10, Spend: fixedSpend(10)})
+	if got, want := r.FallbackChain("opus"), []string{"spark/qwen"}; !slices.Equal(got, want) {
+		t.Errorf("FallbackChain(opus) at ceiling = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package router
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HintMaxLatencyMs is the latency budget, in milliseconds, the caller
+// wants a reply within. Models whose observed latency (see
+// [DeploymentStats.LatencyEWMAMs]) exceeds it are avoided when another
+// candidate that meets the quality floor is within budget. Models with
+// no recorded latency count as within budget.
+const HintMaxLatencyMs = "max_latency_ms"
+
+// latencyEWMAAlpha weights the newest sample in the latency moving
+// average. At 0.3 a model that turns slow dominates its average within
+// a handful of requests, while a single outlier moves it only part way.
+const latencyEWMAAlpha = 0.3
+
+// updateLatencyEWMA folds a new latency sample into the moving average.
+// The first sample seeds it.
+func updateLatencyEWMA(current, sample int64) int64 {
+	if current <= 0 {
+		return sample
+	}
+	return int64(latencyEWMAAlpha*float64(sample) + (1-latencyEWMAAlpha)*float64(current))
+}
+
+// observedLatency returns the best estimate of a deployment's latency:
+// the moving average when one exists, else the lifetime average (for
+// experience persisted before the moving average was tracked). Zero
+// means unknown.
+func observedLatency(meta DeploymentStats) int64 {
+	if meta.LatencyEWMAMs > 0 {
+		return meta.LatencyEWMAMs
+	}
+	return meta.AvgLatencyMs
+}
+
+// latencyBudget parses [HintMaxLatencyMs] from req. Zero means no
+// budget.
+func latencyBudget(req Request) int64 {
+	raw := strings.TrimSpace(req.RoutingFactors[HintMaxLatencyMs])
+	if raw == "" {
+		return 0
+	}
+	ms, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || ms <= 0 {
+		return 0
+	}
+	return ms
+}
+
+// overLatencyBudget returns the candidates to steer away from under the
+// request's latency budget: those observed slower than the budget, but
+// only when some other candidate meeting the quality floor is within
+// it. With no faster alternative the slow models stay in contention,
+// since a slow reply beats no reply.
+func (r *Router) overLatencyBudget(candidates []Model, req Request) map[string]int64 {
+	budget := latencyBudget(req)
+	if budget == 0 {
+		return nil
+	}
+	floor, _ := strconv.Atoi(req.RoutingFactors[FactorQualityFloor])
+
+	slow := make(map[string]int64)
+	fasterExists := false
+	for _, m := range candidates {
+		if latency := observedLatency(r.deploymentExperience(m.Name)); latency > budget {
+			slow[m.Name] = latency
+			continue
+		}
+		if m.Quality >= floor {
+			fasterExists = true
+		}
+	}
+	if !fasterExists || len(slow) == 0 {
+		return nil
+	}
+	return slow
+}
+
+// summarizeLatencies renders slow models and their observed latency
+// for [Decision.Reasoning], sorted by name.
+func summarizeLatencies(slow map[string]int64) string {
+	names := make([]string, 0, len(slow))
+	for name := range slow {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+" ("+strconv.FormatInt(slow[name], 10)+"ms)")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,113 @@
+package router
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func newLatencyTestRouter() *Router {
+	return NewRouter(slog.Default(), Config{
+		DefaultModel: "local-slow",
+		LocalFirst:   true,
+		Models: []Model{
+			{Name: "local-slow", Provider: "ollama", SupportsTools: true, Speed: 6, Quality: 7, CostTier: 0, ContextWindow: 32768},
+			{Name: "cloud-fast", Provider: "anthropic", SupportsTools: true, Speed: 6, Quality: 8, CostTier: 1, ContextWindow: 200000},
+			{Name: "local-tiny", Provider: "ollama", SupportsTools: true, Speed: 6, Quality: 3, CostTier: 0, ContextWindow: 8192},
+		},
+		MaxAuditLog: 10,
+	})
+}
+
+// signalRequest is an interactive request the local model wins on
+// score when latency is not in play.
+func signalRequest(maxLatency string) Request {
+	factors := map[string]string{FactorQualityFloor: "6"}
+	if maxLatency != "" {
+		factors[HintMaxLatencyMs] = maxLatency
+	}
+	return Request{
+		Query:          "did anyone ring the doorbell",
+		NeedsTools:     true,
+		Priority:       PriorityInteractive,
+		RoutingFactors: factors,
+	}
+}
+
+func TestUpdateLatencyEWMA(t *testing.T) {
+	if got := updateLatencyEWMA(0, 2000); got != 2000 {
+		t.Errorf("first sample = %d, want 2000", got)
+	}
+	if got := updateLatencyEWMA(1000, 11000); got != 4000 {
+		t.Errorf("updateLatencyEWMA(1000, 11000) = %d, want 4000", got)
+	}
+}
+
+func TestRecordOutcome_TracksLatencyEWMA(t *testing.T) {
+	r := newLatencyTestRouter()
+	for _, latency := range []int64{1000, 1000, 11000} {
+		_, decision := r.Route(context.Background(), signalRequest(""))
+		r.RecordOutcome(decision.RequestID, latency, 100, true)
+	}
+	meta := r.GetStats().DeploymentStats["local-slow"]
+	if meta.LatencyEWMAMs != 4000 {
+		t.Errorf("LatencyEWMAMs = %d, want 4000", meta.LatencyEWMAMs)
+	}
+	if meta.AvgLatencyMs != 4333 {
+		t.Errorf("AvgLatencyMs = %d, want 4333", meta.AvgLatencyMs)
+	}
+}
+
+func TestRoute_LatencyBudgetAvoidsSlowModel(t *testing.T) {
+	r := newLatencyTestRouter()
+	if model, decision := r.Route(context.Background(), signalRequest("")); model != "local-slow" {
+		t.Fatalf("baseline Route() = %q, want local-slow (reasoning: %s)", model, decision.Reasoning)
+	}
+
+	r.ReplaceExperience(map[string]DeploymentStats{
+		"local-slow": {Requests: 5, Successes: 5, LatencyEWMAMs: 40000},
+	})
+
+	// Without a budget the slow model is only nudged, not displaced.
+	if model, _ := r.Route(context.Background(), signalRequest("")); model != "local-slow" {
+		t.Errorf("Route() without budget = %q, want local-slow", model)
+	}
+
+	// cloud-fast has no history and counts as within budget;
+	// local-tiny is faster but below the quality floor.
+	model, decision := r.Route(context.Background(), signalRequest("15000"))
+	if model != "cloud-fast" {
+		t.Fatalf("Route() with budget = %q, want cloud-fast (reasoning: %s)", model, decision.Reasoning)
+	}
+	if !strings.Contains(decision.Reasoning, "local-slow (40000ms)") {
+		t.Errorf("Reasoning should name the avoided model, got: %s", decision.Reasoning)
+	}
+}
+
+func TestRoute_LatencyBudgetKeepsSlowModelWithoutAlternative(t *testing.T) {
+	r := newLatencyTestRouter()
+	r.ReplaceExperience(map[string]DeploymentStats{
+		"local-slow": {Requests: 5, Successes: 5, LatencyEWMAMs: 40000},
+		"cloud-fast": {Requests: 5, Successes: 5, LatencyEWMAMs: 20000},
+	})
+
+	// The only model within budget is below the quality floor, so
+	// nothing is avoided.
+	model, decision := r.Route(context.Background(), signalRequest("15000"))
+	if model != "local-slow" {
+		t.Errorf("Route() = %q, want local-slow (reasoning: %s)", model, decision.Reasoning)
+	}
+	if strings.Contains(decision.Reasoning, "latency budget") {
+		t.Errorf("Reasoning should not mention the latency budget, got: %s", decision.Reasoning)
+	}
+}
+
+func TestLatencyBudget(t *testing.T) {
+	for raw, want := range map[string]int64{"": 0, "8000": 8000, " 500 ": 500, "-1": 0, "fast": 0} {
+		req := Request{RoutingFactors: map[string]string{HintMaxLatencyMs: raw}}
+		if got := latencyBudget(req); got != want {
+			t.Errorf("latencyBudget(%q) = %d, want %d", raw, got, want)
+		}
+	}
+}
@@ -43,6 +43,10 @@ const (
 	// tasks where latency and resource efficiency matter more than maximum
 	// output quality.
 	FactorPreferSpeed = "prefer_speed"
+	// HintRecovery marks a timeout-recovery call when "true": a short,
+	// tool-less summary of work already done after the primary model
+	// timed out. Speed dominates scoring so the summary arrives quickly.
+	HintRecovery = "recovery"
 )
 
 // Priority indicates latency requirements.
@@ -74,6 +78,7 @@ type Decision struct {
 	RejectedModels map[string][]string `json:"rejected_models,omitempty"`
 	Scores         map[string]int      `json:"scores,omitempty"`
 	NoEligible     bool                `json:"no_eligible,omitempty"`
+	BudgetState    string              `json:"budget_state,omitempty"`
 
 	// Outcome
 	ModelSelected         string `json:"model_selected"`
@@ -147,6 +152,10 @@ type Router struct {
 	stats                 Stats
 	experienceVersion     int64
 	resourceCooldownUntil map[string]time.Time
+	fallbackChains        map[string][]string
+	resourceReady         ResourceReadyFunc
+
+	budget budgetGuard
 }
 
 func cloneModels(in []Model) []Model {
@@ -190,7 +199,11 @@ type DeploymentStats struct {
 	Successes     int64  `json:"successes"`
 	Failures      int64  `json:"failures"`
 	AvgLatencyMs  int64  `json:"avg_latency_ms,omitempty"`
-	AvgTokensUsed int64  `json:"avg_tokens_used,omitempty"`
+	// LatencyEWMAMs is an exponentially weighted moving average of
+	// observed latency, tracking recent behavior more closely than
+	// AvgLatencyMs. Zero means no latency has been observed.
+	LatencyEWMAMs int64 `json:"latency_ewma_ms,omitempty"`
+	AvgTokensUsed int64 `json:"avg_tokens_used,omitempty"`
 }
 
 // ResourceHealth exposes request-plane routing health for one resource.
@@ -278,13 +291,26 @@ func (r *Router) Route(ctx context.Context, req Request) (string, *Decision) {
 	decision.DetectedIntent = r.detectIntent(req.Query)
 
 	// Evaluate rules and select model
-	model := r.selectModel(cfg, req, decision)
+	budget, _ := r.BudgetStatus()
+	model := r.selectModel(cfg, req, decision, budget)
 	decision.ModelSelected = model
 	r.populateSelectionMetadata(cfg, decision, model)
 
 	// Log the decision
 	r.recordDecision(*decision)
 
+	if budget.State != BudgetStateOK {
+		r.logger.Warn("model routing limited by budget",
+			"request_id", decision.RequestID,
+			"model", model,
+			"budget_state", budget.State,
+			"daily_spent_usd", budget.DailySpentUSD,
+			"daily_ceiling_usd", budget.DailyCeilingUSD,
+			"monthly_spent_usd", budget.MonthlySpentUSD,
+			"monthly_ceiling_usd", budget.MonthlyCeilingUSD,
+		)
+	}
+
 	r.logger.Info("model routed",
 		"request_id", decision.RequestID,
 		"model", model,
@@ -316,7 +342,8 @@ func (r *Router) ExplainRequest(req Request) *Decision {
 	decision.Complexity = r.analyzeComplexity(req.Query)
 	decision.DetectedIntent = r.detectIntent(req.Query)
 
-	model := r.selectModel(cfg, req, decision)
+	budget, _ := r.BudgetStatus()
+	model := r.selectModel(cfg, req, decision, budget)
 	decision.ModelSelected = model
 	r.populateSelectionMetadata(cfg, decision, model)
 	return decision
@@ -395,12 +422,22 @@ func (r *Router) detectIntent(query string) string {
 	}
 }
 
-// selectModel picks the best model based on analysis.
-func (r *Router) selectModel(cfg Config, req Request, decision *Decision) string {
+// selectModel picks the best model based on analysis. budget is the
+// current spend status; outside [BudgetStateOK] it rules paid models
+// out for the requests it limits (see [Budget]).
+func (r *Router) selectModel(cfg Config, req Request, decision *Decision, budget BudgetStatus) string {
 	var rulesEvaluated, rulesMatched []string
 	var reasoning strings.Builder
 	rejected := make(map[string][]string)
 	now := time.Now()
+	budgetRejected := false
+	if budget.State != BudgetStateOK {
+		decision.BudgetState = budget.State
+	}
+
+	// Within the budget margin, non-critical requests are routed as
+	// local_only, which rules paid models out of the candidates below.
+	req.RoutingFactors = budgetFactors(budget, req)
 
 	// Find eligible models
 	var candidates []Model
@@ -433,6 +470,12 @@ func (r *Router) selectModel(cfg Config, req Request, decision *Decision) string
 			reasons = append(reasons, "context window too small")
 		}
 
+		// Must fit the spend budget.
+		if reason := budgetRejection(budget, req, m); reason != "" {
+			reasons = append(reasons, reason)
+			budgetRejected = true
+		}
+
 		if len(reasons) > 0 {
 			rejected[m.Name] = reasons
 			continue
@@ -449,13 +492,24 @@ func (r *Router) selectModel(cfg Config, req Request, decision *Decision) string
 
 	if len(candidates) == 0 {
 		decision.NoEligible = true
-		reasoning.WriteString("No eligible models, using default.")
+		fallback := cfg.DefaultModel
+		if budgetRejected {
+			// The default may be the paid model the budget just ruled
+			// out; fall back to a free one instead.
+			fallback = budgetFallback(cfg)
+			reasoning.WriteString("No eligible models within budget, using " + fallback + ".")
+		} else {
+			reasoning.WriteString("No eligible models, using default.")
+		}
 		if summary := summarizeRejectedModels(rejected); summary != "" {
 			reasoning.WriteString(" Rejected: " + summary + ".")
 		}
+		if budget.State != BudgetStateOK {
+			reasoning.WriteString(" " + budgetReasoning(budget))
+		}
 		decision.RulesMatched = rulesMatched
 		decision.Reasoning = reasoning.String()
-		return cfg.DefaultModel
+		return fallback
 	}
 
 	// Score candidates
@@ -472,6 +526,10 @@ func (r *Router) selectModel(cfg Config, req Request, decision *Decision) string
 	// frontier models.
 	explicitlyNotLocal := req.RoutingFactors != nil && req.RoutingFactors[FactorLocalOnly] == "false"
 
+	// Models observed slower than the caller's latency budget, when a
+	// faster candidate meets the quality floor.
+	tooSlow := r.overLatencyBudget(candidates, req)
+
 	scores := make(map[string]int)
 	for _, m := range candidates {
 		score := 0
@@ -606,6 +664,13 @@ func (r *Router) selectModel(cfg Config, req Request, decision *Decision) string
 				score += 15
 				rulesMatched = append(rulesMatched, "prefer_speed_bonus_"+m.Name)
 			}
+
+			// Recovery: the caller is salvaging a timed-out run, so the
+			// fastest model wins; quality barely matters for a summary.
+			if req.RoutingFactors[HintRecovery] == "true" {
+				score += m.Speed * 5
+				rulesMatched = append(rulesMatched, "recovery_speed_"+m.Name)
+			}
 		}
 
 		if until := r.resourceCooldownDeadline(m.ResourceID); !until.IsZero() && now.Before(until) {
@@ -613,6 +678,11 @@ func (r *Router) selectModel(cfg Config, req Request, decision *Decision) string
 			rulesMatched = append(rulesMatched, "resource_timeout_cooldown_"+m.Name)
 		}
 
+		if _, slow := tooSlow[m.Name]; slow {
+			score -= 100 // effectively disqualify, like the quality floor
+			rulesMatched = append(rulesMatched, "over_latency_budget_"+m.Name)
+		}
+
 		if delta, reasons := experienceScore(r.deploymentExperience(m.Name), req); delta != 0 {
 			score += delta
 			rulesMatched = append(rulesMatched, reasons...)
@@ -652,6 +722,12 @@ func (r *Router) selectModel(cfg Config, req Request, decision *Decision) string
 	if cfg.LocalFirst && best.CostTier == 0 {
 		reasoning.WriteString(" Local-first preference applied.")
 	}
+	if len(tooSlow) > 0 {
+		reasoning.WriteString(" Avoided models over the " + strconv.FormatInt(latencyBudget(req), 10) + "ms latency budget: " + summarizeLatencies(tooSlow) + ".")
+	}
+	if budget.State != BudgetStateOK {
+		reasoning.WriteString(" " + budgetReasoning(budget))
+	}
 
 	decision.RulesMatched = rulesMatched
 	decision.Reasoning = reasoning.String()
@@ -687,6 +763,7 @@ func (r *Router) RecordOutcome(requestID string, latencyMs int64, tokensUsed int
 			outcomes := meta.Successes + meta.Failures
 			r.stats.AvgLatencyMs[model] = weightedAverage(r.stats.AvgLatencyMs[model], outcomes, latencyMs)
 			meta.AvgLatencyMs = weightedAverage(meta.AvgLatencyMs, outcomes, latencyMs)
+			meta.LatencyEWMAMs = updateLatencyEWMA(meta.LatencyEWMAMs, latencyMs)
 			meta.AvgTokensUsed = weightedAverage(meta.AvgTokensUsed, outcomes, int64(tokensUsed))
 			r.stats.DeploymentStats[model] = meta
 			r.experienceVersion++
@@ -718,6 +795,11 @@ func (r *Router) RecordFailure(requestID string, latencyMs int64, tokensUsed int
 			r.stats.AvgLatencyMs[model] = weightedAverage(r.stats.AvgLatencyMs[model], outcomes, latencyMs)
 			meta.AvgLatencyMs = weightedAverage(meta.AvgLatencyMs, outcomes, latencyMs)
 			meta.AvgTokensUsed = weightedAverage(meta.AvgTokensUsed, outcomes, int64(tokensUsed))
+			if resourceTimeout {
+				// A timeout is how long the caller actually waited. Other
+				// failures tend to be fast and say nothing about speed.
+				meta.LatencyEWMAMs = updateLatencyEWMA(meta.LatencyEWMAMs, latencyMs)
+			}
 			r.stats.DeploymentStats[model] = meta
 
 			if resourceTimeout && resource != "" {
@@ -976,18 +1058,18 @@ func experienceScore(meta DeploymentStats, req Request) (int, []string) {
 		}
 	}
 
-	if meta.AvgLatencyMs > 0 && (req.Priority == PriorityInteractive || req.RoutingFactors[FactorPreferSpeed] == "true") {
+	if latency := observedLatency(meta); latency > 0 && (req.Priority == PriorityInteractive || req.RoutingFactors[FactorPreferSpeed] == "true") {
 		switch {
-		case meta.AvgLatencyMs <= 4000:
+		case latency <= 4000:
 			score += 4
 			reasons = append(reasons, "experience_latency_fast")
-		case meta.AvgLatencyMs <= 8000:
+		case latency <= 8000:
 			score += 2
 			reasons = append(reasons, "experience_latency_ok")
-		case meta.AvgLatencyMs >= 30000:
+		case latency >= 30000:
 			score -= 8
 			reasons = append(reasons, "experience_latency_slow")
-		case meta.AvgLatencyMs >= 15000:
+		case latency >= 15000:
 			score -= 4
 			reasons = append(reasons, "experience_latency_sluggish")
 		}
@@ -956,3 +956,33 @@ func TestExperienceVersionTracksRestoredAndRecordedExperience(t *testing.T) {
 		t.Fatalf("ExperienceVersion = %d, want > %d after RecordOutcome", got, afterRestore)
 	}
 }
+
+func TestRoute_RecoveryHintPrefersFastest(t *testing.T) {
+	r := NewRouter(slog.Default(), Config{
+		DefaultModel: "deliberate",
+		Models: []Model{
+			{Name: "deliberate", Provider: "anthropic", SupportsTools: true, Speed: 4, Quality: 10, CostTier: 3, ContextWindow: 200000},
+			{Name: "quick", Provider: "anthropic", SupportsTools: true, Speed: 9, Quality: 6, CostTier: 3, ContextWindow: 200000},
+		},
+		MaxAuditLog: 10,
+	})
+
+	query := "explain why the garage door automation failed and propose a fix"
+	modelWithout, _ := r.Route(context.Background(), Request{
+		Query:          query,
+		Priority:       PriorityBackground,
+		RoutingFactors: map[string]string{FactorQualityFloor: "8"},
+	})
+	if modelWithout != "deliberate" {
+		t.Fatalf("Route() without recovery hint selected %q, want deliberate", modelWithout)
+	}
+
+	modelWith, decision := r.Route(context.Background(), Request{
+		Query:          query,
+		Priority:       PriorityInteractive,
+		RoutingFactors: map[string]string{HintRecovery: "true"},
+	})
+	if modelWith != "quick" {
+		t.Errorf("Route() with recovery hint selected %q, want quick (scores %v)", modelWith, decision.Scores)
+	}
+}
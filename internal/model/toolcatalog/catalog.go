@@ -146,6 +146,7 @@ var builtinToolSpecs = map[string]BuiltinToolSpec{
 	"lens_activate":               {CanonicalID: "native:lens_activate", Source: NativeToolSource},
 	"archive_range":               {CanonicalID: "native:archive_range", Source: NativeToolSource, Tags: []string{"archive"}},
 	"archive_search":              {CanonicalID: "native:archive_search", Source: NativeToolSource, Tags: []string{"archive"}},
+	"episodic_search":             {CanonicalID: "native:episodic_search", Source: NativeToolSource, Tags: []string{"archive"}},
 	"archive_session_transcript":  {CanonicalID: "native:archive_session_transcript", Source: NativeToolSource, Tags: []string{"archive"}},
 	"archive_sessions":            {CanonicalID: "native:archive_sessions", Source: NativeToolSource, Tags: []string{"archive"}},
 	"attachment_describe":         {CanonicalID: "native:attachment_describe", Source: NativeToolSource, Tags: []string{"attachments"}},
@@ -153,6 +154,7 @@ var builtinToolSpecs = map[string]BuiltinToolSpec{
 	"attachment_search":           {CanonicalID: "native:attachment_search", Source: NativeToolSource, Tags: []string{"attachments"}},
 	"ha_call_service":             {CanonicalID: "native:ha_call_service", Source: NativeToolSource, Tags: []string{"ha"}},
 	"task_cancel":                 {CanonicalID: "native:task_cancel", Source: NativeToolSource, Tags: []string{"scheduler"}},
+	"create_reminder":             {CanonicalID: "native:create_reminder", Source: NativeToolSource, Tags: []string{"scheduler"}},
 	"ha_control_device":           {CanonicalID: "native:ha_control_device", Source: NativeToolSource, Tags: []string{"ha"}},
 	"conversation_reset":          {CanonicalID: "native:conversation_reset", Source: NativeToolSource, Tags: []string{"session"}},
 	"cost_summary":                {CanonicalID: "native:cost_summary", Source: NativeToolSource, Tags: []string{"diagnostics"}},
@@ -181,6 +183,8 @@ var builtinToolSpecs = map[string]BuiltinToolSpec{
 	"doc_section":                 {CanonicalID: "native:doc_section", Source: NativeToolSource, Tags: []string{"documents"}},
 	"doc_values":                  {CanonicalID: "native:doc_values", Source: NativeToolSource, Tags: []string{"documents"}},
 	"doc_write":                   {CanonicalID: "native:doc_write", Source: NativeToolSource, Tags: []string{"documents"}},
+	"email_attachment_download":   {CanonicalID: "native:email_attachment_download", Source: NativeToolSource, Tags: []string{"email"}},
+	"email_attachments":           {CanonicalID: "native:email_attachments", Source: NativeToolSource, Tags: []string{"email"}},
 	"email_folders":               {CanonicalID: "native:email_folders", Source: NativeToolSource, Tags: []string{"email"}},
 	"email_list":                  {CanonicalID: "native:email_list", Source: NativeToolSource, Tags: []string{"email"}},
 	"email_mark":                  {CanonicalID: "native:email_mark", Source: NativeToolSource, Tags: []string{"email"}},
@@ -203,7 +207,9 @@ var builtinToolSpecs = map[string]BuiltinToolSpec{
 	"file_write":                  {CanonicalID: "native:file_write", Source: NativeToolSource, Tags: []string{"files"}},
 	"ha_find_entity":              {CanonicalID: "native:ha_find_entity", Source: NativeToolSource, Tags: []string{"ha"}},
 	"contact_forget":              {CanonicalID: "native:contact_forget", Source: NativeToolSource, Tags: []string{"contacts"}},
+	"contact_merge":               {CanonicalID: "native:contact_merge", Source: NativeToolSource, Tags: []string{"contacts"}},
 	"forget_fact":                 {CanonicalID: "native:forget_fact", Source: NativeToolSource, Tags: []string{"memory"}},
+	"list_fact_conflicts":         {CanonicalID: "native:list_fact_conflicts", Source: NativeToolSource, Tags: []string{"memory"}},
 	"forge_issue_comment":         {CanonicalID: "native:forge_issue_comment", Source: NativeToolSource, Tags: []string{"forge"}},
 	"forge_issue_create":          {CanonicalID: "native:forge_issue_create", Source: NativeToolSource, Tags: []string{"forge"}},
 	"forge_issue_get":             {CanonicalID: "native:forge_issue_get", Source: NativeToolSource, Tags: []string{"forge"}},
@@ -240,11 +246,15 @@ var builtinToolSpecs = map[string]BuiltinToolSpec{
 	"ha_automation_traces":        {CanonicalID: "native:ha_automation_traces", Source: NativeToolSource, Tags: []string{"ha"}},
 	"ha_automation_vocabulary":    {CanonicalID: "native:ha_automation_vocabulary", Source: NativeToolSource, Tags: []string{"ha"}},
 	"ha_list_services":            {CanonicalID: "native:ha_list_services", Source: NativeToolSource, Tags: []string{"ha"}},
+	"ha_get_service_response":     {CanonicalID: "native:ha_get_service_response", Source: NativeToolSource, Tags: []string{"ha"}},
+	"ha_render_template":          {CanonicalID: "native:ha_render_template", Source: NativeToolSource, Tags: []string{"ha"}},
+	"ha_area_entities":            {CanonicalID: "native:ha_area_entities", Source: NativeToolSource, Tags: []string{"ha"}},
 	"ha_search_states":            {CanonicalID: "native:ha_search_states", Source: NativeToolSource, Tags: []string{"ha"}},
 	"get_area_activity":           {CanonicalID: "native:get_area_activity", Source: NativeToolSource, Tags: []string{"ha"}},
 	"ha_device":                   {CanonicalID: "native:ha_device", Source: NativeToolSource, Tags: []string{"ha"}},
 	"ha_history":                  {CanonicalID: "native:ha_history", Source: NativeToolSource, Tags: []string{"ha"}},
 	"ha_home_snapshot":            {CanonicalID: "native:ha_home_snapshot", Source: NativeToolSource, Tags: []string{"ha"}},
+	"person_presence":             {CanonicalID: "native:person_presence", Source: NativeToolSource, Tags: []string{"ha"}},
 	"lens_list":                   {CanonicalID: "native:lens_list", Source: NativeToolSource},
 	"tag_inspect":                 {CanonicalID: "native:tag_inspect", Source: NativeToolSource},
 	"tag_reset":                   {CanonicalID: "native:tag_reset", Source: NativeToolSource},
@@ -256,6 +266,7 @@ var builtinToolSpecs = map[string]BuiltinToolSpec{
 	"watch_entity":                {CanonicalID: "native:watch_entity", Source: NativeToolSource, Tags: []string{"loops"}},
 	"unwatch_entity":              {CanonicalID: "native:unwatch_entity", Source: NativeToolSource, Tags: []string{"loops"}},
 	"loop_status":                 {CanonicalID: "native:loop_status", Source: NativeToolSource, Tags: []string{"loops"}},
+	"metacognitive_history":       {CanonicalID: "native:metacognitive_history", Source: NativeToolSource, Tags: []string{"loops"}},
 	"loop_containers":             {CanonicalID: "native:loop_containers", Source: NativeToolSource, Tags: []string{"loops"}},
 	"loop_wake":                   {CanonicalID: "native:loop_wake", Source: NativeToolSource, Tags: []string{"loops"}},
 	"loop_definition_delete":      {CanonicalID: "native:loop_definition_delete", Source: NativeToolSource, Tags: []string{"loops"}},
@@ -271,6 +282,7 @@ var builtinToolSpecs = map[string]BuiltinToolSpec{
 	"spawn_loop":                  {CanonicalID: "native:spawn_loop", Source: NativeToolSource, Tags: []string{"loops"}},
 	"stop_loop":                   {CanonicalID: "native:stop_loop", Source: NativeToolSource, Tags: []string{"loops"}},
 	"thane_assign":                {CanonicalID: "native:thane_assign", Source: NativeToolSource},
+	"thane_fanout":                {CanonicalID: "native:thane_fanout", Source: NativeToolSource},
 	"thane_loop_create":           {CanonicalID: "native:thane_loop_create", Source: NativeToolSource},
 	"thane_now":                   {CanonicalID: "native:thane_now", Source: NativeToolSource},
 	"macos_calendar_events":       {CanonicalID: "native:macos_calendar_events", Source: NativeToolSource, Tags: []string{"companion"}},
@@ -295,6 +307,7 @@ var builtinToolSpecs = map[string]BuiltinToolSpec{
 	"request_human_decision":      {CanonicalID: "native:request_human_decision", Source: NativeToolSource, Tags: []string{"notifications"}},
 	"request_human_escalation":    {CanonicalID: "native:request_human_escalation", Source: NativeToolSource, Tags: []string{"notifications"}},
 	"resolve_actionable":          {CanonicalID: "native:resolve_actionable", Source: NativeToolSource, Tags: []string{"notifications"}},
+	"resolve_fact_conflict":       {CanonicalID: "native:resolve_fact_conflict", Source: NativeToolSource, Tags: []string{"memory"}},
 	"contact_save":                {CanonicalID: "native:contact_save", Source: NativeToolSource, Tags: []string{"contacts"}},
 	"task_schedule":               {CanonicalID: "native:task_schedule", Source: NativeToolSource, Tags: []string{"scheduler"}},
 	"send_reaction":               {CanonicalID: "native:send_reaction", Source: NativeToolSource, Tags: []string{"message_channel"}},
@@ -302,6 +315,7 @@ var builtinToolSpecs = map[string]BuiltinToolSpec{
 	"session_close":               {CanonicalID: "native:session_close", Source: NativeToolSource, Tags: []string{"session"}},
 	"session_split":               {CanonicalID: "native:session_split", Source: NativeToolSource, Tags: []string{"session"}},
 	"session_working_memory":      {CanonicalID: "native:session_working_memory", Source: NativeToolSource, Tags: []string{"memory"}},
+	"session_ego":                 {CanonicalID: "native:session_ego", Source: NativeToolSource, Tags: []string{"memory"}},
 	"send_notification":           {CanonicalID: "native:send_notification", Source: NativeToolSource, Tags: []string{"notifications"}},
 	"signal_send_message":         {CanonicalID: "native:signal_send_message", Source: NativeToolSource, Tags: []string{"signal"}},
 	"signal_send_reaction":        {CanonicalID: "native:signal_send_reaction", Source: NativeToolSource, Tags: []string{"signal"}},
@@ -119,6 +119,7 @@ func TestBuiltinToolCatalogIncludesCoreTools(t *testing.T) {
 		"lens_deactivate",
 		"lens_list",
 		"thane_now",
+		"thane_fanout",
 		"thane_assign",
 		"request_core_attention",
 		"logs_query",
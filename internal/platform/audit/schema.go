@@ -0,0 +1,50 @@
+package audit
+
+import "github.com/nugget/thane-ai-agent/internal/platform/database"
+
+// schema declares the tool_audit table. Unlike the archive's
+// tool_calls, rows are never compacted, archived, or pruned with their
+// session, so the log answers "every shell_exec last week" no matter
+// what happened to the conversations involved.
+var schema = database.Schema{
+	Name: "audit",
+	Steps: []database.MigrationStep{
+		database.TableCreate{
+			Table: "tool_audit",
+			SQL: `CREATE TABLE IF NOT EXISTS tool_audit (
+				id              TEXT PRIMARY KEY,
+				started_at      TEXT NOT NULL,
+				completed_at    TEXT,
+				duration_ms     INTEGER,
+				tool_name       TEXT NOT NULL,
+				arguments       TEXT NOT NULL DEFAULT '',
+				redacted        INTEGER NOT NULL DEFAULT 0,
+				result          TEXT NOT NULL DEFAULT '',
+				error           TEXT NOT NULL DEFAULT '',
+				conversation_id TEXT NOT NULL DEFAULT '',
+				session_id      TEXT NOT NULL DEFAULT '',
+				request_id      TEXT NOT NULL DEFAULT '',
+				loop_id         TEXT NOT NULL DEFAULT '',
+				iteration       INTEGER NOT NULL DEFAULT 0,
+				source          TEXT NOT NULL DEFAULT '',
+				channel         TEXT NOT NULL DEFAULT '',
+				sender          TEXT NOT NULL DEFAULT ''
+			)`,
+		},
+		// The failure classification ("transient", "bad_input", ...)
+		// of a call that errored; empty on success.
+		database.ColumnAdd{Table: "tool_audit", Column: "error_kind", Typedef: "TEXT NOT NULL DEFAULT ''"},
+		database.IndexCreate{
+			Name: "idx_tool_audit_started",
+			SQL:  `CREATE INDEX IF NOT EXISTS idx_tool_audit_started ON tool_audit(started_at)`,
+		},
+		database.IndexCreate{
+			Name: "idx_tool_audit_tool",
+			SQL:  `CREATE INDEX IF NOT EXISTS idx_tool_audit_tool ON tool_audit(tool_name, started_at)`,
+		},
+		database.IndexCreate{
+			Name: "idx_tool_audit_conversation",
+			SQL:  `CREATE INDEX IF NOT EXISTS idx_tool_audit_conversation ON tool_audit(conversation_id, started_at)`,
+		},
+	},
+}
@@ -0,0 +1,244 @@
+// Package audit keeps a persistent, queryable log of every tool the
+// agent executes: what ran, with which arguments, what came back, how
+// long it took, and which conversation, request, and channel triggered
+// it. Records are written by the agent loop when a tool call starts
+// and completed when it returns, and are read back by `thane audit`.
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+)
+
+// timeFormat is the fixed-width UTC layout for stored timestamps.
+// Millisecond precision keeps durations meaningful, and the fixed
+// width keeps string comparison in time-range queries correct.
+const timeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// maxResultBytes caps the stored result of one call. Tool output can
+// be arbitrarily large (file reads, web pages); the audit log needs
+// enough to see what happened, not a second copy of the content.
+const maxResultBytes = 16 << 10
+
+// RedactedValue replaces each argument value of a call to a tool
+// configured for redaction.
+const RedactedValue = "[redacted]"
+
+// Entry is one audited tool call.
+type Entry struct {
+	ID          string     `json:"id"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	DurationMS  int64      `json:"duration_ms,omitempty"`
+	Tool        string     `json:"tool"`
+	// Arguments is the call's JSON arguments. For a redacted tool the
+	// keys are kept and every value is replaced with [RedactedValue].
+	Arguments string `json:"arguments,omitempty"`
+	Redacted  bool   `json:"redacted,omitempty"`
+	Result    string `json:"result,omitempty"`
+	Error     string `json:"error,omitempty"`
+	// ErrorKind classifies a failed call ("transient", "not_found",
+	// "bad_input", ...) as reported to the model.
+	ErrorKind string `json:"error_kind,omitempty"`
+
+	ConversationID string `json:"conversation_id,omitempty"`
+	SessionID      string `json:"session_id,omitempty"`
+	RequestID      string `json:"request_id,omitempty"`
+	LoopID         string `json:"loop_id,omitempty"`
+	Iteration      int    `json:"iteration"`
+	// Source is the request's routing source ("signal", "email",
+	// "scheduler", ...); Channel and Sender come from the
+	// conversation's channel binding when there is one.
+	Source  string `json:"source,omitempty"`
+	Channel string `json:"channel,omitempty"`
+	Sender  string `json:"sender,omitempty"`
+}
+
+// Filter selects entries for [Store.Query]. Zero fields don't filter.
+type Filter struct {
+	Tool           string
+	ConversationID string
+	Since          time.Time // inclusive
+	Until          time.Time // exclusive
+	Limit          int       // default 100
+}
+
+// Store is a SQLite store for tool call audit records. All public
+// methods are safe for concurrent use (SQLite serializes writes).
+type Store struct {
+	db     *sql.DB
+	redact map[string]bool
+}
+
+// NewStore creates an audit store using the given database connection.
+// The caller owns the connection — Store does not close it. The schema
+// is created automatically on first use. Calls to the tools named in
+// redactTools are recorded with their argument values redacted.
+func NewStore(db *sql.DB, logger *slog.Logger, redactTools []string) (*Store, error) {
+	if db == nil {
+		return nil, fmt.Errorf("nil database connection")
+	}
+	if err := database.Migrate(db, schema, logger); err != nil {
+		return nil, err
+	}
+	redact := make(map[string]bool, len(redactTools))
+	for _, name := range redactTools {
+		redact[name] = true
+	}
+	return &Store{db: db, redact: redact}, nil
+}
+
+// Start records the beginning of a tool call. e.ID must be unique; it
+// is the key [Store.Complete] uses. A zero StartedAt means now.
+func (s *Store) Start(ctx context.Context, e Entry) error {
+	if e.ID == "" {
+		return fmt.Errorf("audit entry has no ID")
+	}
+	if e.StartedAt.IsZero() {
+		e.StartedAt = time.Now()
+	}
+	if s.redact[e.Tool] {
+		e.Arguments = RedactArguments(e.Arguments)
+		e.Redacted = true
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO tool_audit (id, started_at, tool_name, arguments, redacted,
+			conversation_id, session_id, request_id, loop_id, iteration, source, channel, sender)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.ID, e.StartedAt.UTC().Format(timeFormat), e.Tool, e.Arguments, e.Redacted,
+		e.ConversationID, e.SessionID, e.RequestID, e.LoopID, e.Iteration, e.Source, e.Channel, e.Sender,
+	)
+	if err != nil {
+		return fmt.Errorf("insert audit entry: %w", err)
+	}
+	return nil
+}
+
+// Complete records the outcome of the tool call started with id.
+// errKind and errMsg are empty when the call succeeded.
+func (s *Store) Complete(ctx context.Context, id, result, errKind, errMsg string) error {
+	var startedStr string
+	err := s.db.QueryRowContext(ctx, `SELECT started_at FROM tool_audit WHERE id = ?`, id).Scan(&startedStr)
+	if err != nil {
+		return fmt.Errorf("audit entry %s: %w", id, err)
+	}
+	now := time.Now()
+	var durationMS int64
+	if started, err := time.Parse(timeFormat, startedStr); err == nil {
+		durationMS = now.Sub(started).Milliseconds()
+	}
+	_, err = s.db.ExecContext(ctx, `
+		UPDATE tool_audit SET completed_at = ?, duration_ms = ?, result = ?, error = ?, error_kind = ?
+		WHERE id = ?`,
+		now.UTC().Format(timeFormat), durationMS, truncateResult(result), errMsg, errKind, id,
+	)
+	if err != nil {
+		return fmt.Errorf("complete audit entry: %w", err)
+	}
+	return nil
+}
+
+// Query returns entries matching f, newest first.
+func (s *Store) Query(ctx context.Context, f Filter) ([]Entry, error) {
+	var where []string
+	var args []any
+	if f.Tool != "" {
+		where = append(where, "tool_name = ?")
+		args = append(args, f.Tool)
+	}
+	if f.ConversationID != "" {
+		where = append(where, "conversation_id = ?")
+		args = append(args, f.ConversationID)
+	}
+	if !f.Since.IsZero() {
+		where = append(where, "started_at >= ?")
+		args = append(args, f.Since.UTC().Format(timeFormat))
+	}
+	if !f.Until.IsZero() {
+		where = append(where, "started_at < ?")
+		args = append(args, f.Until.UTC().Format(timeFormat))
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := `SELECT id, started_at, completed_at, duration_ms, tool_name, arguments, redacted,
+		result, error, error_kind, conversation_id, session_id, request_id, loop_id, iteration, source, channel, sender
+		FROM tool_audit`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY started_at DESC, rowid DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var e Entry
+		var startedStr string
+		var completedStr sql.NullString
+		var duration sql.NullInt64
+		if err := rows.Scan(&e.ID, &startedStr, &completedStr, &duration, &e.Tool, &e.Arguments, &e.Redacted,
+			&e.Result, &e.Error, &e.ErrorKind, &e.ConversationID, &e.SessionID, &e.RequestID, &e.LoopID, &e.Iteration,
+			&e.Source, &e.Channel, &e.Sender); err != nil {
+			return nil, fmt.Errorf("scan audit entry: %w", err)
+		}
+		e.StartedAt, _ = time.Parse(timeFormat, startedStr)
+		if completedStr.Valid {
+			if t, err := time.Parse(timeFormat, completedStr.String); err == nil {
+				e.CompletedAt = &t
+			}
+		}
+		e.DurationMS = duration.Int64
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// RedactArguments replaces every value in a JSON argument object with
+// [RedactedValue], keeping the keys so the audit log still shows the
+// shape of the call. Arguments that aren't a JSON object are replaced
+// wholesale.
+func RedactArguments(argsJSON string) string {
+	if argsJSON == "" {
+		return ""
+	}
+	var args map[string]any
+	if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+		return RedactedValue
+	}
+	for k := range args {
+		args[k] = RedactedValue
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return RedactedValue
+	}
+	return string(data)
+}
+
+// truncateResult caps result at maxResultBytes, noting how much was cut.
+func truncateResult(result string) string {
+	if len(result) <= maxResultBytes {
+		return result
+	}
+	cut := maxResultBytes
+	for cut > 0 && !utf8.RuneStart(result[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s\n[truncated %d bytes]", result[:cut], len(result)-cut)
+}
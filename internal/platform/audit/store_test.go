@@ -0,0 +1,198 @@
+package audit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	_ "modernc.org/sqlite"
+)
+
+func testStore(t *testing.T, redact ...string) *Store {
+	t.Helper()
+	db, err := database.OpenMemory()
+	if err != nil {
+		t.Fatalf("database.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	s, err := NewStore(db, nil, redact)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	return s
+}
+
+func TestStartCompleteQuery(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	started := time.Now().Add(-time.Second)
+	if err := s.Start(ctx, Entry{
+		ID:             "tc-1",
+		StartedAt:      started,
+		Tool:           "shell_exec",
+		Arguments:      `{"command":"uptime"}`,
+		ConversationID: "signal-1",
+		RequestID:      "r_1",
+		Iteration:      2,
+		Source:         "signal",
+		Channel:        "signal",
+		Sender:         "Alice",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Complete(ctx, "tc-1", "up 3 days", "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := s.Query(ctx, Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Tool != "shell_exec" || e.Arguments != `{"command":"uptime"}` || e.Result != "up 3 days" {
+		t.Errorf("entry = %+v", e)
+	}
+	if e.ConversationID != "signal-1" || e.RequestID != "r_1" || e.Iteration != 2 || e.Sender != "Alice" {
+		t.Errorf("entry attribution = %+v", e)
+	}
+	if e.CompletedAt == nil || e.DurationMS < 1000 {
+		t.Errorf("CompletedAt = %v, DurationMS = %d; want completed with >= 1000ms", e.CompletedAt, e.DurationMS)
+	}
+	if !e.StartedAt.Equal(started.Truncate(time.Millisecond)) {
+		t.Errorf("StartedAt = %v, want %v", e.StartedAt, started.Truncate(time.Millisecond))
+	}
+}
+
+func TestCompleteRecordsErrorKind(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	if err := s.Start(ctx, Entry{ID: "tc-1", Tool: "file_read"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Complete(ctx, "tc-1", "", "not_found", "[error:not_found] file not found: a.md"); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := s.Query(ctx, Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].ErrorKind != "not_found" || entries[0].Error != "[error:not_found] file not found: a.md" {
+		t.Errorf("entries = %+v", entries)
+	}
+}
+
+func TestCompleteUnknownID(t *testing.T) {
+	s := testStore(t)
+	if err := s.Complete(context.Background(), "missing", "", "", ""); err == nil {
+		t.Error("Complete for unknown ID should fail")
+	}
+}
+
+func TestQueryFilters(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	base := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+
+	for i, e := range []Entry{
+		{Tool: "shell_exec", ConversationID: "a", StartedAt: base.AddDate(0, 0, -10)},
+		{Tool: "shell_exec", ConversationID: "b", StartedAt: base.AddDate(0, 0, -2)},
+		{Tool: "web_search", ConversationID: "a", StartedAt: base.AddDate(0, 0, -1)},
+		{Tool: "shell_exec", ConversationID: "a", StartedAt: base},
+	} {
+		e.ID = string(rune('a' + i))
+		if err := s.Start(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"all newest first", Filter{}, []string{"d", "c", "b", "a"}},
+		{"tool", Filter{Tool: "shell_exec"}, []string{"d", "b", "a"}},
+		{"conversation", Filter{ConversationID: "a"}, []string{"d", "c", "a"}},
+		{"last week", Filter{Tool: "shell_exec", Since: base.AddDate(0, 0, -7)}, []string{"d", "b"}},
+		{"until exclusive", Filter{Until: base}, []string{"c", "b", "a"}},
+		{"limit", Filter{Limit: 2}, []string{"d", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := s.Query(ctx, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("IDs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactTools(t *testing.T) {
+	s := testStore(t, "send_email")
+	ctx := context.Background()
+
+	if err := s.Start(ctx, Entry{ID: "1", Tool: "send_email", Arguments: `{"to":"bob@example.com","body":"secret"}`}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(ctx, Entry{ID: "2", Tool: "web_search", Arguments: `{"query":"weather"}`}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := s.Query(ctx, Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := map[string]Entry{}
+	for _, e := range entries {
+		byID[e.ID] = e
+	}
+	if got := byID["1"]; !got.Redacted || got.Arguments != `{"body":"[redacted]","to":"[redacted]"}` {
+		t.Errorf("redacted entry = %+v", got)
+	}
+	if got := byID["2"]; got.Redacted || got.Arguments != `{"query":"weather"}` {
+		t.Errorf("unredacted entry = %+v", got)
+	}
+}
+
+func TestRedactArguments(t *testing.T) {
+	tests := map[string]string{
+		"":                    "",
+		`{"a":1,"b":{"c":2}}`: `{"a":"[redacted]","b":"[redacted]"}`,
+		`not json`:            RedactedValue,
+		`["list"]`:            RedactedValue,
+	}
+	for in, want := range tests {
+		if got := RedactArguments(in); got != want {
+			t.Errorf("RedactArguments(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTruncateResult(t *testing.T) {
+	if got := truncateResult("short"); got != "short" {
+		t.Errorf("truncateResult(short) = %q", got)
+	}
+	long := strings.Repeat("é", maxResultBytes) // 2 bytes per rune
+	got := truncateResult(long)
+	if !strings.Contains(got, "[truncated ") {
+		t.Errorf("long result not marked truncated")
+	}
+	body, _, _ := strings.Cut(got, "\n[truncated")
+	if len(body) > maxResultBytes || !strings.HasPrefix(long, body) {
+		t.Errorf("truncated body is %d bytes or not a prefix", len(body))
+	}
+}
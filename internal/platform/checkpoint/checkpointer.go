@@ -31,16 +31,38 @@ type Checkpointer struct {
 	tasks         TaskFunc
 
 	// Config
-	periodicInterval int // Create checkpoint every N messages (0 = disabled)
+	periodicInterval int  // Create checkpoint every N messages (0 = disabled)
+	incremental      bool // Periodic checkpoints are deltas
+	fullEvery        int  // Increments between full snapshots
 
 	// State
 	mu            sync.Mutex
-	messagesSince int // Messages since last checkpoint
+	messagesSince int        // Messages since last checkpoint
+	base          *deltaBase // Full snapshot increments apply to; nil until one is taken
+}
+
+// deltaBase is the full snapshot this checkpointer's increments are
+// taken against. Only snapshots taken by this process serve as bases,
+// so the first periodic checkpoint after a restart is always full.
+type deltaBase struct {
+	id          uuid.UUID
+	collectedAt time.Time // when collection of the base began
+	marks       map[string]conversationMark
+	increments  int
 }
 
 // Config for the checkpointer.
 type Config struct {
 	PeriodicMessages int // Checkpoint every N messages (0 = disabled)
+
+	// Incremental makes periodic checkpoints store only the records
+	// changed since the last full snapshot. Manual, pre-failover, and
+	// shutdown checkpoints are always full.
+	Incremental bool
+
+	// FullEvery is how many increments are taken before the next
+	// periodic checkpoint is full again. Default 10.
+	FullEvery int
 }
 
 // NewCheckpointer creates a new checkpointer.
@@ -50,10 +72,16 @@ func NewCheckpointer(db *sql.DB, cfg Config, log *slog.Logger) (*Checkpointer, e
 		return nil, err
 	}
 
+	fullEvery := cfg.FullEvery
+	if fullEvery <= 0 {
+		fullEvery = 10
+	}
 	return &Checkpointer{
 		store:            store,
 		log:              log,
 		periodicInterval: cfg.PeriodicMessages,
+		incremental:      cfg.Incremental,
+		fullEvery:        fullEvery,
 	}, nil
 }
 
@@ -88,8 +116,16 @@ func (c *Checkpointer) OnMessage() {
 	}
 }
 
-// Create makes a new checkpoint with the given trigger and optional note.
+// Create makes a new checkpoint with the given trigger and optional
+// note. In incremental mode a periodic checkpoint is a delta against
+// the last full snapshot, unless none has been taken yet or FullEvery
+// increments already build on it.
 func (c *Checkpointer) Create(trigger Trigger, note string) (*Checkpoint, error) {
+	if base := c.nextBase(trigger); base != nil {
+		return c.createDelta(base, trigger, note)
+	}
+
+	collectedAt := time.Now()
 	state, err := c.collectState()
 	if err != nil {
 		return nil, fmt.Errorf("collect state: %w", err)
@@ -100,6 +136,12 @@ func (c *Checkpointer) Create(trigger Trigger, note string) (*Checkpoint, error)
 		return nil, fmt.Errorf("store: %w", err)
 	}
 
+	if c.incremental {
+		c.mu.Lock()
+		c.base = &deltaBase{id: cp.ID, collectedAt: collectedAt, marks: state.markConversations()}
+		c.mu.Unlock()
+	}
+
 	c.log.Info("checkpoint created",
 		"id", cp.ID.String()[:8],
 		"trigger", trigger,
@@ -111,6 +153,47 @@ func (c *Checkpointer) Create(trigger Trigger, note string) (*Checkpoint, error)
 	return cp, nil
 }
 
+// nextBase claims an increment on the current base for a checkpoint
+// with trigger, or returns nil when the checkpoint should be full.
+func (c *Checkpointer) nextBase(trigger Trigger) *deltaBase {
+	if !c.incremental || trigger != TriggerPeriodic {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.base == nil || c.base.increments >= c.fullEvery {
+		return nil
+	}
+	c.base.increments++
+	return c.base
+}
+
+// createDelta stores the changes since base as an incremental
+// checkpoint.
+func (c *Checkpointer) createDelta(base *deltaBase, trigger Trigger, note string) (*Checkpoint, error) {
+	state, err := c.collectState()
+	if err != nil {
+		return nil, fmt.Errorf("collect state: %w", err)
+	}
+
+	cp, err := c.store.CreateDelta(base.id, trigger, note, state.diff(base.collectedAt, base.marks))
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+
+	c.log.Info("incremental checkpoint created",
+		"id", cp.ID.String()[:8],
+		"base", base.id.String()[:8],
+		"trigger", trigger,
+		"changed_conversations", len(cp.State.Conversations),
+		"changed_facts", len(cp.State.Facts),
+		"changed_tasks", len(cp.State.Tasks),
+		"bytes", cp.ByteSize,
+	)
+
+	return cp, nil
+}
+
 // CreatePreFailover creates a checkpoint before switching models.
 func (c *Checkpointer) CreatePreFailover(fromModel, toModel string) (*Checkpoint, error) {
 	note := fmt.Sprintf("failover: %s → %s", fromModel, toModel)
@@ -128,7 +211,7 @@ func (c *Checkpointer) OnFailover(ctx context.Context, fromModel, toModel, reaso
 	return err
 }
 
-// Get retrieves a checkpoint by ID.
+// Get retrieves a checkpoint by ID as stored.
 func (c *Checkpointer) Get(id uuid.UUID) (*Checkpoint, error) {
 	return c.store.Get(id)
 }
@@ -153,27 +236,6 @@ func (c *Checkpointer) Prune(olderThan time.Duration, minKeep int) (int, error)
 	return c.store.Prune(olderThan, minKeep)
 }
 
-// Restore applies a checkpoint's state to the providers.
-// This is a placeholder — actual restoration depends on provider implementations.
-func (c *Checkpointer) Restore(id uuid.UUID) error {
-	cp, err := c.store.Get(id)
-	if err != nil {
-		return fmt.Errorf("get checkpoint: %w", err)
-	}
-
-	c.log.Info("restoring checkpoint",
-		"id", cp.ID.String()[:8],
-		"created", cp.CreatedAt.Format(time.RFC3339),
-		"messages", cp.MessageCount,
-		"facts", cp.FactCount,
-	)
-
-	// TODO: Implement actual restoration by calling provider restore methods
-	// For now, we just validate the checkpoint can be loaded
-
-	return nil
-}
-
 // StartupStatus returns info about persisted state for logging at startup.
 // Since SQLite persists automatically, this just reports what exists.
 type StartupStatus struct {
@@ -0,0 +1,135 @@
+package checkpoint
+
+import (
+	"hash/fnv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// conversationMark fingerprints a conversation's messages when a full
+// snapshot was taken. Compaction and resets rewrite a conversation's
+// active messages without touching its UpdatedAt, so an increment also
+// treats a conversation whose fingerprint moved as changed.
+type conversationMark struct {
+	messages int
+	hash     uint64
+}
+
+func markConversation(c Conversation) conversationMark {
+	h := fnv.New64a()
+	for _, m := range c.Messages {
+		h.Write([]byte(m.Role))
+		h.Write([]byte{0})
+		h.Write([]byte(m.Content))
+		h.Write([]byte{0})
+		h.Write([]byte(m.Timestamp.UTC().Format(time.RFC3339Nano)))
+		h.Write([]byte{0})
+	}
+	return conversationMark{messages: len(c.Messages), hash: h.Sum64()}
+}
+
+// markConversations fingerprints every conversation in s.
+func (s *State) markConversations() map[string]conversationMark {
+	marks := make(map[string]conversationMark, len(s.Conversations))
+	for _, c := range s.Conversations {
+		marks[c.ID] = markConversation(c)
+	}
+	return marks
+}
+
+// diff returns the increment of s against a full snapshot collected at
+// since: the conversations, facts, and tasks updated after since, plus
+// conversations whose fingerprint moved from marks, and an index of
+// every record present so replay can drop the ones removed.
+func (s *State) diff(since time.Time, marks map[string]conversationMark) *State {
+	d := &State{
+		Config: s.Config,
+		Delta: &DeltaIndex{
+			ConversationIDs: make([]string, 0, len(s.Conversations)),
+			FactIDs:         make([]uuid.UUID, 0, len(s.Facts)),
+			TaskIDs:         make([]uuid.UUID, 0, len(s.Tasks)),
+		},
+	}
+	for _, c := range s.Conversations {
+		d.Delta.ConversationIDs = append(d.Delta.ConversationIDs, c.ID)
+		mark, known := marks[c.ID]
+		if c.UpdatedAt.After(since) || !known || mark != markConversation(c) {
+			d.Conversations = append(d.Conversations, c)
+		}
+	}
+	for _, f := range s.Facts {
+		d.Delta.FactIDs = append(d.Delta.FactIDs, f.ID)
+		if f.UpdatedAt.After(since) {
+			d.Facts = append(d.Facts, f)
+		}
+	}
+	for _, t := range s.Tasks {
+		d.Delta.TaskIDs = append(d.Delta.TaskIDs, t.ID)
+		if t.UpdatedAt.After(since) {
+			d.Tasks = append(d.Tasks, t)
+		}
+	}
+	return d
+}
+
+// apply returns the state that results from replaying increment d on
+// s: d's records replace s's by ID, and records d's index does not name
+// are dropped. A d without an index is a full state and replaces s.
+func (s *State) apply(d *State) *State {
+	if d.Delta == nil {
+		return d
+	}
+	out := &State{Config: s.Config}
+	if d.Config != nil {
+		out.Config = d.Config
+	}
+
+	convs := make(map[string]Conversation, len(s.Conversations))
+	for _, c := range s.Conversations {
+		convs[c.ID] = c
+	}
+	for _, c := range d.Conversations {
+		convs[c.ID] = c
+	}
+	for _, id := range d.Delta.ConversationIDs {
+		if c, ok := convs[id]; ok {
+			out.Conversations = append(out.Conversations, c)
+		}
+	}
+
+	facts := make(map[uuid.UUID]Fact, len(s.Facts))
+	for _, f := range s.Facts {
+		facts[f.ID] = f
+	}
+	for _, f := range d.Facts {
+		facts[f.ID] = f
+	}
+	for _, id := range d.Delta.FactIDs {
+		if f, ok := facts[id]; ok {
+			out.Facts = append(out.Facts, f)
+		}
+	}
+
+	tasks := make(map[uuid.UUID]Task, len(s.Tasks))
+	for _, t := range s.Tasks {
+		tasks[t.ID] = t
+	}
+	for _, t := range d.Tasks {
+		tasks[t.ID] = t
+	}
+	for _, id := range d.Delta.TaskIDs {
+		if t, ok := tasks[id]; ok {
+			out.Tasks = append(out.Tasks, t)
+		}
+	}
+	return out
+}
+
+// counts returns the messages and facts s holds.
+func (s *State) counts() (messages, facts int) {
+	for _, c := range s.Conversations {
+		messages += len(c.Messages)
+	}
+	return messages, len(s.Facts)
+}
@@ -0,0 +1,157 @@
+package checkpoint
+
+import (
+	"database/sql"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite"
+)
+
+// liveState is a mutable provider set for checkpointer tests.
+type liveState struct {
+	convs []Conversation
+	facts []Fact
+	tasks []Task
+}
+
+func (l *liveState) providers(c *Checkpointer) {
+	c.SetProviders(
+		func() ([]Conversation, error) { return append([]Conversation(nil), l.convs...), nil },
+		func() ([]Fact, error) { return append([]Fact(nil), l.facts...), nil },
+		func() ([]Task, error) { return append([]Task(nil), l.tasks...), nil },
+	)
+}
+
+func newIncrementalCheckpointer(t *testing.T, fullEvery int) (*Checkpointer, *liveState) {
+	t.Helper()
+	db, err := sql.Open("sqlite-thane", filepath.Join(t.TempDir(), "checkpoints.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	c, err := NewCheckpointer(db, Config{Incremental: true, FullEvery: fullEvery}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	live := &liveState{}
+	live.providers(c)
+	return c, live
+}
+
+func message(content string, at time.Time) Message {
+	return Message{ID: uuid.New(), Role: "user", Content: content, Timestamp: at}
+}
+
+func TestIncrementalCheckpoints(t *testing.T) {
+	c, live := newIncrementalCheckpointer(t, 2)
+	old := time.Now().Add(-time.Hour)
+	keptFact := Fact{ID: uuid.New(), Category: "user", Key: "name", Value: "Ada", UpdatedAt: old}
+	droppedFact := Fact{ID: uuid.New(), Category: "home", Key: "rooms", Value: "4", UpdatedAt: old}
+	live.convs = []Conversation{
+		{ID: "quiet", UpdatedAt: old, Messages: []Message{message("hi", old)}},
+		{ID: "busy", UpdatedAt: old, Messages: []Message{message("one", old)}},
+	}
+	live.facts = []Fact{keptFact, droppedFact}
+
+	full, err := c.Create(TriggerPeriodic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if full.Incremental() {
+		t.Fatal("first periodic checkpoint is incremental, want full")
+	}
+
+	now := time.Now()
+	live.convs[1].Messages = append(live.convs[1].Messages, message("two", now))
+	live.convs[1].UpdatedAt = now
+	live.facts = []Fact{keptFact, {ID: uuid.New(), Category: "user", Key: "pet", Value: "cat", UpdatedAt: now}}
+
+	delta, err := c.Create(TriggerPeriodic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !delta.Incremental() || *delta.BaseID != full.ID {
+		t.Fatalf("second periodic checkpoint base = %v, want delta of %s", delta.BaseID, full.ID)
+	}
+	if len(delta.State.Conversations) != 1 || delta.State.Conversations[0].ID != "busy" || len(delta.State.Facts) != 1 {
+		t.Errorf("delta state = %+v, want only the busy conversation and the new fact", delta.State)
+	}
+
+	// Compaction rewrites messages without bumping UpdatedAt.
+	live.convs[0].Messages = []Message{message("[summary]", old)}
+	second, err := c.Create(TriggerPeriodic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !second.Incremental() || len(second.State.Conversations) != 2 {
+		t.Errorf("reshaped conversation missing from increment: %+v", second.State.Conversations)
+	}
+
+	resolved, err := c.store.Resolve(second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved.MessageCount != 3 || resolved.FactCount != 2 || resolved.State.Delta != nil {
+		t.Fatalf("resolved = %d messages, %d facts; want 3 and 2", resolved.MessageCount, resolved.FactCount)
+	}
+	if got := resolved.State.Conversations[0].Messages[0].Content; got != "[summary]" {
+		t.Errorf("resolved quiet conversation = %q, want the compacted summary", got)
+	}
+	for _, f := range resolved.State.Facts {
+		if f.ID == droppedFact.ID {
+			t.Error("resolved state kept a fact removed after the base")
+		}
+	}
+
+	third, err := c.Create(TriggerPeriodic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.Incremental() {
+		t.Error("checkpoint after FullEvery increments is incremental, want full")
+	}
+	shutdown, err := c.CreateShutdown()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shutdown.Incremental() {
+		t.Error("shutdown checkpoint is incremental, want full")
+	}
+
+	if err := c.Delete(full.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(delta.ID); err == nil {
+		t.Error("increment survived deleting its base")
+	}
+}
+
+func TestIncrementalDisabledByDefault(t *testing.T) {
+	db, err := sql.Open("sqlite-thane", filepath.Join(t.TempDir(), "checkpoints.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	c, err := NewCheckpointer(db, Config{}, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	(&liveState{}).providers(c)
+
+	for range 3 {
+		cp, err := c.Create(TriggerPeriodic, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cp.Incremental() {
+			t.Fatal("incremental checkpoint taken without opting in")
+		}
+	}
+}
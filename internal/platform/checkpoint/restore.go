@@ -0,0 +1,92 @@
+package checkpoint
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StoreChange describes what restoring a checkpoint changes in one
+// store. Counts are in the store's own records: conversations for
+// memory, facts for the fact store, tasks for the scheduler.
+type StoreChange struct {
+	Store     string `json:"store"`
+	Added     int    `json:"added"`
+	Updated   int    `json:"updated"`
+	Removed   int    `json:"removed"`
+	Unchanged int    `json:"unchanged"`
+
+	// Skipped explains why the store was left untouched, such as a
+	// checkpoint that predates the data the store needs.
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// Changed reports whether restoring would modify the store.
+func (c StoreChange) Changed() bool {
+	return c.Skipped == "" && c.Added+c.Updated+c.Removed > 0
+}
+
+// Restorer is a store that can be rewound to a checkpoint's state.
+// RestoreCheckpoint applies the store's part of state in a single
+// transaction, so a failure leaves the store as it was. With dryRun
+// it computes the same change without writing.
+type Restorer interface {
+	RestoreCheckpoint(state *State, dryRun bool) (StoreChange, error)
+}
+
+// RestoreReport is the outcome of [Restore].
+type RestoreReport struct {
+	ID        uuid.UUID     `json:"id"`
+	CreatedAt time.Time     `json:"created_at"`
+	Trigger   Trigger       `json:"trigger"`
+	Note      string        `json:"note,omitempty"`
+	DryRun    bool          `json:"dry_run"`
+	Stores    []StoreChange `json:"stores"`
+}
+
+// Restore rewinds stores to the state captured in cp, which must have
+// been loaded with its complete state (see [Store.Resolve]). Every
+// store is planned with a dry run before any is written, so a snapshot
+// one store cannot apply fails before the others change. Stores are
+// then restored in order, each in its own transaction; stores live in
+// separate databases, so a failure part-way reports the stores already
+// restored alongside the error.
+func Restore(cp *Checkpoint, stores []Restorer, dryRun bool) (*RestoreReport, error) {
+	if cp.State == nil || cp.State.Delta != nil {
+		return nil, fmt.Errorf("checkpoint %s has no complete state", cp.ID)
+	}
+	report := &RestoreReport{
+		ID:        cp.ID,
+		CreatedAt: cp.CreatedAt,
+		Trigger:   cp.Trigger,
+		Note:      cp.Note,
+		DryRun:    dryRun,
+	}
+
+	planned := make([]StoreChange, len(stores))
+	for i, s := range stores {
+		change, err := s.RestoreCheckpoint(cp.State, true)
+		if err != nil {
+			return report, fmt.Errorf("plan restore: %w", err)
+		}
+		planned[i] = change
+	}
+	if dryRun {
+		report.Stores = planned
+		return report, nil
+	}
+
+	for i, s := range stores {
+		if !planned[i].Changed() {
+			report.Stores = append(report.Stores, planned[i])
+			continue
+		}
+		change, err := s.RestoreCheckpoint(cp.State, false)
+		if err != nil {
+			return report, fmt.Errorf("restore %s: %w", planned[i].Store, err)
+		}
+		report.Stores = append(report.Stores, change)
+	}
+	return report, nil
+}
@@ -0,0 +1,85 @@
+package checkpoint
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeRestorer records how it was called.
+type fakeRestorer struct {
+	name     string
+	change   StoreChange
+	planErr  error
+	applyErr error
+	applied  bool
+}
+
+func (f *fakeRestorer) RestoreCheckpoint(_ *State, dryRun bool) (StoreChange, error) {
+	change := f.change
+	change.Store = f.name
+	if dryRun {
+		return change, f.planErr
+	}
+	if f.applyErr != nil {
+		return change, f.applyErr
+	}
+	f.applied = true
+	return change, nil
+}
+
+func TestRestore(t *testing.T) {
+	cp := &Checkpoint{Trigger: TriggerManual, State: &State{}}
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		a := &fakeRestorer{name: "a", change: StoreChange{Added: 2}}
+		report, err := Restore(cp, []Restorer{a}, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.applied || !report.DryRun || len(report.Stores) != 1 || report.Stores[0].Added != 2 {
+			t.Errorf("report = %+v, applied = %v", report, a.applied)
+		}
+	})
+
+	t.Run("plan failure stops every store", func(t *testing.T) {
+		a := &fakeRestorer{name: "a", change: StoreChange{Added: 1}}
+		b := &fakeRestorer{name: "b", planErr: errors.New("bad snapshot")}
+		if _, err := Restore(cp, []Restorer{a, b}, false); err == nil {
+			t.Fatal("Restore succeeded with a store that cannot plan")
+		}
+		if a.applied {
+			t.Error("store written before every store was planned")
+		}
+	})
+
+	t.Run("unchanged and skipped stores are not written", func(t *testing.T) {
+		a := &fakeRestorer{name: "a", change: StoreChange{Unchanged: 3}}
+		b := &fakeRestorer{name: "b", change: StoreChange{Added: 1, Skipped: "too old"}}
+		c := &fakeRestorer{name: "c", change: StoreChange{Removed: 1}}
+		report, err := Restore(cp, []Restorer{a, b, c}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.applied || b.applied || !c.applied || len(report.Stores) != 3 {
+			t.Errorf("applied = %v %v %v, stores = %+v", a.applied, b.applied, c.applied, report.Stores)
+		}
+	})
+
+	t.Run("apply failure reports restored stores", func(t *testing.T) {
+		a := &fakeRestorer{name: "a", change: StoreChange{Updated: 1}}
+		b := &fakeRestorer{name: "b", change: StoreChange{Updated: 1}, applyErr: errors.New("disk full")}
+		report, err := Restore(cp, []Restorer{a, b}, false)
+		if err == nil {
+			t.Fatal("Restore succeeded with a failing store")
+		}
+		if len(report.Stores) != 1 || report.Stores[0].Store != "a" {
+			t.Errorf("stores = %+v, want only a", report.Stores)
+		}
+	})
+
+	t.Run("missing state", func(t *testing.T) {
+		if _, err := Restore(&Checkpoint{}, nil, true); err == nil {
+			t.Error("Restore accepted a checkpoint without state")
+		}
+	})
+}
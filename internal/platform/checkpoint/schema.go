@@ -27,5 +27,12 @@ var schema = database.Schema{
 			Name: "idx_checkpoints_trigger",
 			SQL:  `CREATE INDEX IF NOT EXISTS idx_checkpoints_trigger ON checkpoints(trigger)`,
 		},
+		// base_id links an incremental checkpoint to the full snapshot
+		// it applies to; NULL for full snapshots.
+		database.ColumnAdd{Table: "checkpoints", Column: "base_id", Typedef: "TEXT"},
+		database.IndexCreate{
+			Name: "idx_checkpoints_base",
+			SQL:  `CREATE INDEX IF NOT EXISTS idx_checkpoints_base ON checkpoints(base_id)`,
+		},
 	},
 }
@@ -28,8 +28,28 @@ func NewStore(db *sql.DB, logger *slog.Logger) (*Store, error) {
 	return &Store{db: db}, nil
 }
 
-// Create saves a new checkpoint and returns it with ID populated.
+// Column lists for full and metadata-only checkpoint reads.
+const (
+	fullColumns = "id, created_at, trigger, note, state_gz, byte_size, message_count, fact_count, base_id"
+	metaColumns = "id, created_at, trigger, note, byte_size, message_count, fact_count, base_id"
+)
+
+// Create saves a new full checkpoint and returns it with ID populated.
 func (s *Store) Create(trigger Trigger, note string, state *State) (*Checkpoint, error) {
+	return s.create(trigger, note, state, nil)
+}
+
+// CreateDelta saves an incremental checkpoint: state holds only the
+// records changed since the full checkpoint base, with state.Delta
+// naming every record present.
+func (s *Store) CreateDelta(base uuid.UUID, trigger Trigger, note string, state *State) (*Checkpoint, error) {
+	if state.Delta == nil {
+		return nil, fmt.Errorf("incremental checkpoint has no delta index")
+	}
+	return s.create(trigger, note, state, &base)
+}
+
+func (s *Store) create(trigger Trigger, note string, state *State, base *uuid.UUID) (*Checkpoint, error) {
 	id, err := uuid.NewV7()
 	if err != nil {
 		return nil, fmt.Errorf("generate id: %w", err)
@@ -53,12 +73,7 @@ func (s *Store) Create(trigger Trigger, note string, state *State) (*Checkpoint,
 	compressed := buf.Bytes()
 	now := time.Now().UTC()
 
-	// Count items
-	msgCount := 0
-	for _, conv := range state.Conversations {
-		msgCount += len(conv.Messages)
-	}
-	factCount := len(state.Facts)
+	msgCount, factCount := state.counts()
 
 	cp := &Checkpoint{
 		ID:           id,
@@ -66,15 +81,21 @@ func (s *Store) Create(trigger Trigger, note string, state *State) (*Checkpoint,
 		Trigger:      trigger,
 		Note:         note,
 		State:        state,
+		BaseID:       base,
 		ByteSize:     int64(len(compressed)),
 		MessageCount: msgCount,
 		FactCount:    factCount,
 	}
 
+	var baseID *string
+	if base != nil {
+		v := base.String()
+		baseID = &v
+	}
 	_, err = s.db.Exec(`
-		INSERT INTO checkpoints (id, created_at, trigger, note, state_gz, byte_size, message_count, fact_count)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`, id.String(), now.Format(time.RFC3339), trigger, note, compressed, len(compressed), msgCount, factCount)
+		INSERT INTO checkpoints (id, created_at, trigger, note, state_gz, byte_size, message_count, fact_count, base_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, id.String(), now.Format(time.RFC3339), trigger, note, compressed, len(compressed), msgCount, factCount, baseID)
 	if err != nil {
 		return nil, fmt.Errorf("insert: %w", err)
 	}
@@ -82,16 +103,61 @@ func (s *Store) Create(trigger Trigger, note string, state *State) (*Checkpoint,
 	return cp, nil
 }
 
-// Get retrieves a checkpoint by ID, including full state.
+// Get retrieves a checkpoint by ID, including its stored state. For an
+// incremental checkpoint that is only the changes since its base; use
+// [Store.Resolve] for the complete state.
 func (s *Store) Get(id uuid.UUID) (*Checkpoint, error) {
-	row := s.db.QueryRow(`
-		SELECT id, created_at, trigger, note, state_gz, byte_size, message_count, fact_count
-		FROM checkpoints WHERE id = ?
-	`, id.String())
+	row := s.db.QueryRow(`SELECT `+fullColumns+` FROM checkpoints WHERE id = ?`, id.String())
 
 	return s.scanFull(row)
 }
 
+// Resolve retrieves a checkpoint by ID with its complete state. A full
+// snapshot is returned as stored. An incremental checkpoint is rebuilt
+// by replaying its base snapshot and then every increment on that base,
+// in order, up to and including it.
+func (s *Store) Resolve(id uuid.UUID) (*Checkpoint, error) {
+	cp, err := s.Get(id)
+	if err != nil || !cp.Incremental() {
+		return cp, err
+	}
+
+	base, err := s.Get(*cp.BaseID)
+	if err != nil {
+		return nil, fmt.Errorf("load base checkpoint %s: %w", cp.BaseID, err)
+	}
+	if base.Incremental() {
+		return nil, fmt.Errorf("base checkpoint %s is itself incremental", base.ID)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT `+fullColumns+` FROM checkpoints
+		WHERE base_id = ? AND id <= ?
+		ORDER BY id
+	`, base.ID.String(), cp.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("query increments: %w", err)
+	}
+	defer rows.Close()
+
+	state := base.State
+	for rows.Next() {
+		delta, err := s.scanFull(rows)
+		if err != nil {
+			return nil, err
+		}
+		state = state.apply(delta.State)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query increments: %w", err)
+	}
+
+	resolved := *cp
+	resolved.State = state
+	resolved.MessageCount, resolved.FactCount = state.counts()
+	return &resolved, nil
+}
+
 // List returns checkpoints ordered by creation time (newest first).
 // Does not include full state to keep response small.
 func (s *Store) List(limit int) ([]*Checkpoint, error) {
@@ -100,7 +166,7 @@ func (s *Store) List(limit int) ([]*Checkpoint, error) {
 	}
 
 	rows, err := s.db.Query(`
-		SELECT id, created_at, trigger, note, byte_size, message_count, fact_count
+		SELECT `+metaColumns+`
 		FROM checkpoints
 		ORDER BY created_at DESC
 		LIMIT ?
@@ -124,7 +190,7 @@ func (s *Store) List(limit int) ([]*Checkpoint, error) {
 // Latest returns the most recent checkpoint, or nil if none exist.
 func (s *Store) Latest() (*Checkpoint, error) {
 	row := s.db.QueryRow(`
-		SELECT id, created_at, trigger, note, state_gz, byte_size, message_count, fact_count
+		SELECT ` + fullColumns + `
 		FROM checkpoints
 		ORDER BY created_at DESC
 		LIMIT 1
@@ -137,9 +203,11 @@ func (s *Store) Latest() (*Checkpoint, error) {
 	return cp, err
 }
 
-// Delete removes a checkpoint by ID.
+// Delete removes a checkpoint by ID. Deleting a full snapshot also
+// deletes the increments built on it, which cannot be restored without
+// it.
 func (s *Store) Delete(id uuid.UUID) error {
-	result, err := s.db.Exec(`DELETE FROM checkpoints WHERE id = ?`, id.String())
+	result, err := s.db.Exec(`DELETE FROM checkpoints WHERE id = ? OR base_id = ?`, id.String(), id.String())
 	if err != nil {
 		return fmt.Errorf("delete: %w", err)
 	}
@@ -181,19 +249,35 @@ func (s *Store) Prune(olderThan time.Duration, minKeep int) (int, error) {
 	}
 
 	deleted, _ := result.RowsAffected()
-	return int(deleted), nil
+
+	// Increments whose base was pruned can no longer be restored.
+	orphans, err := s.db.Exec(`
+		DELETE FROM checkpoints
+		WHERE base_id IS NOT NULL AND base_id NOT IN (SELECT id FROM checkpoints)
+	`)
+	if err != nil {
+		return int(deleted), fmt.Errorf("delete orphaned increments: %w", err)
+	}
+	orphaned, _ := orphans.RowsAffected()
+	return int(deleted + orphaned), nil
+}
+
+// rowScanner is the Scan method shared by [sql.Row] and [sql.Rows].
+type rowScanner interface {
+	Scan(dest ...any) error
 }
 
-func (s *Store) scanFull(row *sql.Row) (*Checkpoint, error) {
+func (s *Store) scanFull(row rowScanner) (*Checkpoint, error) {
 	var cp Checkpoint
 	var idStr, createdStr, triggerStr string
-	var note sql.NullString
+	var note, baseID sql.NullString
 	var stateGz []byte
 
-	err := row.Scan(&idStr, &createdStr, &triggerStr, &note, &stateGz, &cp.ByteSize, &cp.MessageCount, &cp.FactCount)
+	err := row.Scan(&idStr, &createdStr, &triggerStr, &note, &stateGz, &cp.ByteSize, &cp.MessageCount, &cp.FactCount, &baseID)
 	if err != nil {
 		return nil, err
 	}
+	cp.BaseID = parseBaseID(baseID)
 
 	cp.ID, _ = uuid.Parse(idStr)
 	cp.CreatedAt, err = database.ParseTimestamp(createdStr)
@@ -227,12 +311,13 @@ func (s *Store) scanFull(row *sql.Row) (*Checkpoint, error) {
 func (s *Store) scanMeta(rows *sql.Rows) (*Checkpoint, error) {
 	var cp Checkpoint
 	var idStr, createdStr, triggerStr string
-	var note sql.NullString
+	var note, baseID sql.NullString
 
-	err := rows.Scan(&idStr, &createdStr, &triggerStr, &note, &cp.ByteSize, &cp.MessageCount, &cp.FactCount)
+	err := rows.Scan(&idStr, &createdStr, &triggerStr, &note, &cp.ByteSize, &cp.MessageCount, &cp.FactCount, &baseID)
 	if err != nil {
 		return nil, err
 	}
+	cp.BaseID = parseBaseID(baseID)
 
 	cp.ID, _ = uuid.Parse(idStr)
 	cp.CreatedAt, err = database.ParseTimestamp(createdStr)
@@ -246,3 +331,16 @@ func (s *Store) scanMeta(rows *sql.Rows) (*Checkpoint, error) {
 
 	return &cp, nil
 }
+
+// parseBaseID returns the base snapshot ID of an incremental
+// checkpoint, or nil for a full one.
+func parseBaseID(v sql.NullString) *uuid.UUID {
+	if !v.Valid || v.String == "" {
+		return nil
+	}
+	id, err := uuid.Parse(v.String)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
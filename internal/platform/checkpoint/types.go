@@ -2,6 +2,7 @@
 package checkpoint
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"time"
@@ -22,11 +23,17 @@ type SourceMessage struct {
 	Role      string
 	Content   string
 	Timestamp time.Time
+
+	// ToolCalls is the JSON array of tool calls an assistant message
+	// made, as stored; ToolCallID names the call a tool message
+	// answers.
+	ToolCalls  string
+	ToolCallID string
 }
 
 // ConvertConversation builds a checkpoint Conversation from external
 // data, generating fresh UUIDs for each message. Returns an error if
-// UUID generation fails.
+// UUID generation fails or a message's tool calls are not valid JSON.
 func ConvertConversation(id string, createdAt, updatedAt time.Time, msgs []SourceMessage) (Conversation, error) {
 	converted := make([]Message, len(msgs))
 	for i, m := range msgs {
@@ -34,11 +41,17 @@ func ConvertConversation(id string, createdAt, updatedAt time.Time, msgs []Sourc
 		if err != nil {
 			return Conversation{}, fmt.Errorf("generate message UUID: %w", err)
 		}
+		toolCalls, err := parseToolCalls(m.ToolCalls)
+		if err != nil {
+			return Conversation{}, fmt.Errorf("message %d: %w", i, err)
+		}
 		converted[i] = Message{
 			ID:        msgID,
 			Role:      m.Role,
 			Content:   m.Content,
 			Timestamp: m.Timestamp,
+			ToolCalls: toolCalls,
+			ToolID:    m.ToolCallID,
 		}
 	}
 	return Conversation{
@@ -49,6 +62,46 @@ func ConvertConversation(id string, createdAt, updatedAt time.Time, msgs []Sourc
 	}, nil
 }
 
+// parseToolCalls decodes a stored tool-call array. It accepts both the
+// flat checkpoint shape and the OpenAI-style shape that nests the name
+// and arguments under "function".
+func parseToolCalls(raw string) ([]ToolCall, error) {
+	if raw == "" || raw == "null" {
+		return nil, nil
+	}
+	var stored []struct {
+		ID        string          `json:"id"`
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+		Function  *struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+		return nil, fmt.Errorf("parse tool calls: %w", err)
+	}
+	calls := make([]ToolCall, len(stored))
+	for i, c := range stored {
+		name, args := c.Name, c.Arguments
+		if c.Function != nil {
+			name, args = c.Function.Name, c.Function.Arguments
+		}
+		calls[i] = ToolCall{ID: c.ID, Name: name, Arguments: rawArguments(args)}
+	}
+	return calls, nil
+}
+
+// rawArguments returns tool-call arguments as a JSON string, unwrapping
+// arguments that were stored as a JSON-encoded string.
+func rawArguments(args json.RawMessage) string {
+	var s string
+	if json.Unmarshal(args, &s) == nil {
+		return s
+	}
+	return string(args)
+}
+
 // Trigger describes what caused a checkpoint to be created.
 type Trigger string
 
@@ -67,6 +120,10 @@ type Checkpoint struct {
 	Trigger   Trigger   `json:"trigger"`
 	Note      string    `json:"note,omitempty"` // Optional human description
 
+	// BaseID is set on an incremental checkpoint: the full snapshot
+	// its changes apply to. Nil for full snapshots.
+	BaseID *uuid.UUID `json:"base_id,omitempty"`
+
 	// Captured state
 	State *State `json:"state"`
 
@@ -89,6 +146,19 @@ type State struct {
 
 	// Agent configuration at checkpoint time
 	Config *ConfigSnapshot `json:"config,omitempty"`
+
+	// Delta is set when this state is an increment: the records above
+	// are only those changed since the base snapshot, and Delta names
+	// every record that existed, so removals replay too.
+	Delta *DeltaIndex `json:"delta,omitempty"`
+}
+
+// DeltaIndex lists the IDs of every record present when an incremental
+// checkpoint was taken, changed or not.
+type DeltaIndex struct {
+	ConversationIDs []string    `json:"conversation_ids"`
+	FactIDs         []uuid.UUID `json:"fact_ids"`
+	TaskIDs         []uuid.UUID `json:"task_ids"`
 }
 
 // Conversation represents a chat session.
@@ -129,6 +199,10 @@ type Fact struct {
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 	Confidence float64   `json:"confidence,omitempty"` // 0-1, how sure we are
+	Subjects   []string  `json:"subjects,omitempty"`   // Subject keys (e.g., "entity:foo")
+	// ExpiresAt is when an ephemeral fact stops being true. Nil means
+	// the fact never expires.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
 // Task is a scheduled action.
@@ -140,6 +214,12 @@ type Task struct {
 	Action      string    `json:"action"`   // What to do
 	Enabled     bool      `json:"enabled"`
 	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+
+	// Definition is the task as the scheduler stores it, schedule and
+	// payload included. Restoring tasks requires it; checkpoints taken
+	// before it was captured hold only the summary fields above.
+	Definition json.RawMessage `json:"definition,omitempty"`
 }
 
 // ConfigSnapshot captures relevant config at checkpoint time.
@@ -149,6 +229,12 @@ type ConfigSnapshot struct {
 	TalentCount  int    `json:"talent_count"`
 }
 
+// Incremental reports whether the checkpoint holds only changes since
+// its base snapshot.
+func (c *Checkpoint) Incremental() bool {
+	return c.BaseID != nil
+}
+
 // Summary returns a human-readable summary of the checkpoint.
 func (c *Checkpoint) Summary() string {
 	return c.ID.String()[:8] + " | " +
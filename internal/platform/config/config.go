@@ -30,6 +30,8 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -215,9 +217,19 @@ type Config struct {
 	// Archive configures session archive behavior.
 	Archive ArchiveConfig `yaml:"archive"`
 
+	// Audit configures the tool execution audit log.
+	Audit AuditConfig `yaml:"audit"`
+
 	// Extraction configures automatic fact extraction from conversations.
 	Extraction ExtractionConfig `yaml:"extraction"`
 
+	// ConversationTitles configures running titles for live
+	// conversations.
+	ConversationTitles ConversationTitlesConfig `yaml:"conversation_titles"`
+
+	// Prompts configures overrides for internal prompt templates.
+	Prompts PromptsConfig `yaml:"prompts"`
+
 	// Search configures web search providers.
 	Search SearchConfig `yaml:"search"`
 
@@ -274,7 +286,7 @@ type Config struct {
 	// Signal configures native Signal message routing through signal-cli jsonRpc.
 	Signal SignalConfig `yaml:"signal"`
 
-	// Forge configures code forge integrations (GitHub, Gitea). When
+	// Forge configures code forge integrations (GitHub, GitLab, Gitea). When
 	// configured, Thane can interact with issues, pull requests, and
 	// code review directly without an MCP forge server subprocess.
 	Forge forge.Config `yaml:"forge"`
@@ -315,6 +327,12 @@ type Config struct {
 	// state changes injected into the agent's system prompt on every run.
 	StateWindow StateWindowConfig `yaml:"state_window"`
 
+	// SensorWebhook configures the API server's push receiver for
+	// sensors that report to a webhook instead of maintaining Home
+	// Assistant entity state. Payloads become virtual sensor transitions
+	// in the state window, exactly like real HA state changes.
+	SensorWebhook SensorWebhookConfig `yaml:"sensor_webhook"`
+
 	// Unifi configures the UniFi network controller connection for
 	// room-level presence detection via wireless AP client associations.
 	Unifi UnifiConfig `yaml:"unifi"`
@@ -375,15 +393,119 @@ type Config struct {
 	// Local/Ollama models not listed here default to $0.
 	Pricing map[string]PricingEntry `yaml:"pricing"`
 
+	// UsageAlerts notifies the owner when LLM spend crosses a share of
+	// a daily or monthly budget. See [UsageAlertsConfig].
+	UsageAlerts UsageAlertsConfig `yaml:"usage_alerts"`
+
+	// Checkpoints configures the state snapshots taken every 50
+	// messages, before model failover, and on shutdown. See
+	// [CheckpointsConfig].
+	Checkpoints CheckpointsConfig `yaml:"checkpoints"`
+
 	// Logging configures Thane's filesystem datasets, stdout policy, and
 	// queryable request/log retention.
 	Logging LoggingConfig `yaml:"logging"`
+
+	// Database tunes the SQLite connections shared by every store
+	// (lock waits, WAL checkpointing). See [DatabaseConfig].
+	Database DatabaseConfig `yaml:"database"`
 }
 
 // PricingEntry defines per-million-token costs for a model in USD.
+//
+// The cache fields price prompt-cache traffic separately from ordinary
+// input. Each one left at zero is derived from InputPerMillion using
+// the provider's published multipliers. Anthropic's are 0.1× for cache
+// reads, 1.25× for 5-minute cache writes, and 2× for 1-hour cache
+// writes. OpenAI deployments use 0.5× for reads and 1× for writes.
 type PricingEntry struct {
 	InputPerMillion  float64 `yaml:"input_per_million"`
 	OutputPerMillion float64 `yaml:"output_per_million"`
+
+	// CacheReadPerMillion is the cost of input tokens served from the
+	// prompt cache.
+	CacheReadPerMillion float64 `yaml:"cache_read_per_million"`
+
+	// CacheWritePerMillion is the cost of input tokens written to the
+	// prompt cache with the default 5-minute TTL.
+	CacheWritePerMillion float64 `yaml:"cache_write_per_million"`
+
+	// CacheWrite1hPerMillion is the cost of input tokens written to
+	// the prompt cache with the 1-hour TTL.
+	CacheWrite1hPerMillion float64 `yaml:"cache_write_1h_per_million"`
+}
+
+// UsageAlertsConfig configures spend alerts. A scheduler task
+// measures spend from the usage store every Interval and, when it
+// crosses one of Thresholds in the configured timezone's day or month,
+// sends a Signal message to Recipient (when Signal is configured) and
+// publishes a usage_alert MQTT event (when MQTT is configured). Each
+// threshold fires at most once per period. These budgets only alert;
+// to make the router act on spend, see [ModelBudgetConfig].
+type UsageAlertsConfig struct {
+	// DailyUSD is the budget for spend since local midnight. Zero
+	// disables daily alerts.
+	DailyUSD float64 `yaml:"daily_usd"`
+
+	// MonthlyUSD is the budget for spend since the first of the month.
+	// Zero disables monthly alerts.
+	MonthlyUSD float64 `yaml:"monthly_usd"`
+
+	// Thresholds are the percentages of a budget that trigger an
+	// alert. Default: [50, 80, 100].
+	Thresholds []float64 `yaml:"thresholds,omitempty"`
+
+	// Interval is how often spend is checked, as a Go duration.
+	// Default: "5m".
+	Interval string `yaml:"interval,omitempty"`
+
+	// Recipient is the contact name Signal alerts go to. Default:
+	// identity.owner_contact_name.
+	Recipient string `yaml:"recipient,omitempty"`
+}
+
+// Enabled reports whether any alert budget is set.
+func (c UsageAlertsConfig) Enabled() bool {
+	return c.DailyUSD > 0 || c.MonthlyUSD > 0
+}
+
+// ParsedInterval returns Interval as a duration, falling back to five
+// minutes when it is empty or invalid.
+func (c UsageAlertsConfig) ParsedInterval() time.Duration {
+	if d, err := time.ParseDuration(c.Interval); err == nil && d > 0 {
+		return d
+	}
+	return 5 * time.Minute
+}
+
+// CheckpointsConfig configures state snapshots. By default every
+// checkpoint is a full snapshot of conversations, facts, and tasks.
+// With Incremental, periodic checkpoints store only the records changed
+// since the last full one, and a full snapshot is taken every FullEvery
+// increments, on shutdown, and for manual and pre-failover checkpoints.
+type CheckpointsConfig struct {
+	// Incremental makes periodic checkpoints deltas against the last
+	// full snapshot. Default: false.
+	Incremental bool `yaml:"incremental"`
+
+	// FullEvery is how many incremental checkpoints are taken between
+	// full snapshots. Default: 10.
+	FullEvery int `yaml:"full_every,omitempty"`
+}
+
+// DatabaseConfig tunes the SQLite databases under data_dir (thane.db,
+// knowledge.db, contacts.db, scheduler.db, ...) and the log index.
+type DatabaseConfig struct {
+	// BusyTimeout is how long a write waits for another connection's
+	// lock before failing with "database is locked". Raise it if
+	// concurrent background work (fact extraction, archiving, usage
+	// recording) logs lock errors. Default: 5s.
+	BusyTimeout time.Duration `yaml:"busy_timeout"`
+
+	// WALCheckpointInterval is how often each database's write-ahead
+	// log is checkpointed and truncated, so WAL files don't grow without
+	// bound on long-running instances. Default: 10m.
+	WALCheckpointInterval time.Duration `yaml:"wal_checkpoint_interval"`
 }
 
 // LoggingConfig configures Thane's structured filesystem log datasets,
@@ -624,6 +746,12 @@ type ListenConfig struct {
 
 	// Port is the TCP port to listen on. Default: 8080.
 	Port int `yaml:"port"`
+
+	// AdminToken, when set, guards destructive native API endpoints
+	// (such as DELETE /v1/conversations/{id}) behind
+	// "Authorization: Bearer <token>". Empty leaves them open behind
+	// the network boundary, like the rest of the native API.
+	AdminToken string `yaml:"admin_token"`
 }
 
 // OllamaAPIConfig configures the optional Ollama-compatible API server.
@@ -1061,7 +1189,9 @@ type HomeAssistantConfig struct {
 	// short window collapses the repeated (multi-MB at scale) registry
 	// pulls that metadata-bearing tool calls would otherwise issue.
 	// Accepts a Go duration string ("30s", "1m"); empty uses the 30s
-	// default; "0" disables caching (always refetch). Live entity state
+	// default; "0" disables caching (always refetch). Registry-updated
+	// events and WebSocket reconnects drop the cache early, so the TTL
+	// is the backstop, not the freshness guarantee. Live entity state
 	// is never cached.
 	RegistryCacheTTL string `yaml:"registry_cache_ttl,omitempty"`
 
@@ -1071,6 +1201,31 @@ type HomeAssistantConfig struct {
 	// entity subscriptions, #1192); this protective limit stays
 	// operator policy in config.
 	IngestRateLimitPerMinute int `yaml:"ingest_rate_limit_per_minute"`
+
+	// Name is the instance name of the connection above when further
+	// instances are configured. Its entities keep bare IDs
+	// (light.kitchen); "<name>:light.kitchen" also addresses them.
+	// Default: "home".
+	Name string `yaml:"name,omitempty"`
+
+	// Instances adds further named Home Assistant instances for a
+	// federated household (e.g., a cabin alongside the main house),
+	// keyed by instance name. Each gets its own WebSocket watcher, and
+	// its entities are namespaced as "<name>:<entity_id>" in tools, the
+	// state window, and the person tracker. The connection above
+	// remains the primary and must be configured.
+	Instances map[string]HomeAssistantInstanceConfig `yaml:"instances,omitempty"`
+}
+
+// HomeAssistantInstanceConfig configures one additional named Home
+// Assistant instance.
+type HomeAssistantInstanceConfig struct {
+	URL   string `yaml:"url"`
+	Token string `yaml:"token"`
+
+	// FloorAlias is the per-instance equivalent of
+	// [HomeAssistantConfig.FloorAlias].
+	FloorAlias string `yaml:"floor_alias,omitempty"`
 }
 
 // Configured reports whether both URL and Token are set. A partial
@@ -1080,6 +1235,17 @@ func (c HomeAssistantConfig) Configured() bool {
 	return c.URL != "" && c.Token != ""
 }
 
+// CanonicalEntityID returns id in the form the state watchers report
+// it: an ID qualified with the primary instance's name
+// ("home:person.alice") becomes bare, and every other ID is returned
+// unchanged.
+func (c HomeAssistantConfig) CanonicalEntityID(id string) string {
+	if instance, bare, ok := strings.Cut(id, ":"); ok && instance != "" && instance == c.Name {
+		return bare
+	}
+	return id
+}
+
 // AnthropicConfig configures the Anthropic (Claude) API provider.
 type AnthropicConfig struct {
 	APIKey string `yaml:"api_key"`
@@ -1113,29 +1279,96 @@ type ModelsConfig struct {
 
 	// RecoveryModel is a fast, cheap model used to generate summaries
 	// when the primary model times out after completing tool calls.
-	// When empty, timeout recovery falls back to a static message
-	// listing the tools that were used.
+	// When empty, the model router picks the fastest available model
+	// for the summary; with no router, recovery falls back to a static
+	// message listing the tools that were used.
 	RecoveryModel string `yaml:"recovery_model"`
 
 	// Available lists all models that Thane can route to. Each entry
 	// maps a model name to a provider and declares its capabilities.
 	Available []ModelConfig `yaml:"available"`
+
+	// Budget caps spend on paid (cost_tier > 0) models chosen by the
+	// model router. See [ModelBudgetConfig].
+	Budget ModelBudgetConfig `yaml:"budget"`
+
+	// FallbackChains maps a primary model to the ordered models the
+	// agent fails over to when a call to it errors, e.g.
+	// claude-opus → claude-sonnet → a local model. Each hop is tried in
+	// turn, skipping models whose resource is currently down; the
+	// default model is always the last resort, and models without a
+	// chain fail over straight to it.
+	FallbackChains map[string][]string `yaml:"fallback_chains,omitempty"`
+
+	// Retry controls how cloud providers (anthropic, openai) retry
+	// transient errors before the agent fails over to another model.
+	// See [ModelRetryConfig].
+	Retry ModelRetryConfig `yaml:"retry"`
+}
+
+// ModelRetryConfig tunes retries of transient cloud-provider failures:
+// connection errors, HTTP 429, 500, 502, 503, 504, and Anthropic's 529
+// overloaded status. Retries back off exponentially with jitter and
+// honor Retry-After up to MaxDelay. Only once retries are exhausted
+// does the error reach the agent loop and trigger failover, so a
+// brief rate-limit blip doesn't downshift to a weaker model.
+type ModelRetryConfig struct {
+	// MaxRetries is the number of retries after the first attempt.
+	// Default: 3. Set 0 to fail over on the first error.
+	MaxRetries *int `yaml:"max_retries,omitempty"`
+
+	// InitialDelay is the backoff before the first retry; it doubles
+	// on each later attempt. Accepts Go duration strings (e.g., "2s").
+	// Default: 2s.
+	InitialDelay time.Duration `yaml:"initial_delay"`
+
+	// MaxDelay caps each backoff wait. A Retry-After longer than this
+	// is not waited out; the error surfaces for failover instead.
+	// Default: 30s.
+	MaxDelay time.Duration `yaml:"max_delay"`
+}
+
+// ModelBudgetConfig sets USD ceilings on routed spend, measured from
+// the usage store in the configured timezone. When spend comes within
+// Margin of a ceiling, the router stops routing non-critical requests
+// to paid models. At a ceiling, paid models are not routed to at all. Explicit
+// model selection bypasses the router and is not limited.
+type ModelBudgetConfig struct {
+	// DailyUSD is the ceiling on spend since local midnight. Zero
+	// means no daily ceiling.
+	DailyUSD float64 `yaml:"daily_usd"`
+
+	// MonthlyUSD is the ceiling on spend since the first of the month.
+	// Zero means no monthly ceiling.
+	MonthlyUSD float64 `yaml:"monthly_usd"`
+
+	// Margin is the fraction of a ceiling (0.0–1.0) below it at which
+	// routing starts downshifting. Default: 0.1, so a $10 ceiling
+	// starts downshifting at $9. Set 0 to go straight from normal
+	// routing to blocked.
+	Margin *float64 `yaml:"margin,omitempty"`
+}
+
+// Enabled reports whether any ceiling is set.
+func (c ModelBudgetConfig) Enabled() bool {
+	return c.DailyUSD > 0 || c.MonthlyUSD > 0
 }
 
 // ModelConfig describes a single LLM model's identity and capabilities.
 // The model router uses these fields to select the best model for each
 // request.
 type ModelConfig struct {
-	Name              string `yaml:"name"`               // Model identifier (e.g., "claude-opus-4-8")
-	Provider          string `yaml:"provider"`           // Provider name: ollama, anthropic, lmstudio. Defaults to ollama when no resource is set
-	Resource          string `yaml:"resource"`           // Named provider resource from models.resources for this deployment
-	SupportsTools     bool   `yaml:"supports_tools"`     // Optional per-deployment tool-use override. When omitted, runtime/provider capability is used.
-	SupportsStreaming *bool  `yaml:"supports_streaming"` // Optional per-deployment streaming override. Nil inherits observed runtime/provider capability.
-	ContextWindow     int    `yaml:"context_window"`     // Optional per-deployment context-window override. Zero inherits observed runtime metadata.
-	Speed             int    `yaml:"speed"`              // Relative speed rating, 1 (slow) to 10 (fast)
-	Quality           int    `yaml:"quality"`            // Relative quality rating, 1 (low) to 10 (high)
-	CostTier          int    `yaml:"cost_tier"`          // 0=local/free, 1=cheap, 2=moderate, 3=expensive
-	MinComplexity     string `yaml:"min_complexity"`     // Minimum task complexity: simple, moderate, complex
+	Name              string        `yaml:"name"`               // Model identifier (e.g., "claude-opus-4-8")
+	Provider          string        `yaml:"provider"`           // Provider name: ollama, anthropic, lmstudio, openai. Defaults to ollama when no resource is set
+	Resource          string        `yaml:"resource"`           // Named provider resource from models.resources for this deployment
+	SupportsTools     bool          `yaml:"supports_tools"`     // Optional per-deployment tool-use override. When omitted, runtime/provider capability is used.
+	SupportsStreaming *bool         `yaml:"supports_streaming"` // Optional per-deployment streaming override. Nil inherits observed runtime/provider capability.
+	ContextWindow     int           `yaml:"context_window"`     // Optional per-deployment context-window override. Zero inherits observed runtime metadata.
+	Speed             int           `yaml:"speed"`              // Relative speed rating, 1 (slow) to 10 (fast)
+	Quality           int           `yaml:"quality"`            // Relative quality rating, 1 (low) to 10 (high)
+	CostTier          int           `yaml:"cost_tier"`          // 0=local/free, 1=cheap, 2=moderate, 3=expensive
+	MinComplexity     string        `yaml:"min_complexity"`     // Minimum task complexity: simple, moderate, complex
+	Timeout           time.Duration `yaml:"timeout"`            // Optional call timeout (e.g., "10m"): an overall deadline for non-streaming calls, and for streaming calls the longest gap allowed between tokens. Zero uses the provider default
 
 	supportsToolsSet bool `yaml:"-"`
 }
@@ -1172,8 +1405,14 @@ func (m *ModelConfig) UnmarshalYAML(node *yaml.Node) error {
 
 // ModelServerConfig describes a named model provider resource.
 type ModelServerConfig struct {
+	// URL is the resource's base URL. For openai resources it is the
+	// API root including its version segment, e.g.
+	// https://api.groq.com/openai/v1. Default for openai:
+	// https://api.openai.com/v1.
 	URL string `yaml:"url"`
-	// Provider name for this resource. Default: ollama.
+	// Provider name for this resource: ollama, lmstudio, or openai (the
+	// OpenAI chat-completions API or any compatible endpoint such as
+	// Groq, Together, or vLLM). Default: ollama.
 	Provider string `yaml:"provider"`
 	// APIKey is an optional bearer/API key for providers that require auth.
 	APIKey string `yaml:"api_key"`
@@ -1223,11 +1462,65 @@ type SearchConfig struct {
 
 	// Brave configures the Brave Search API provider.
 	Brave search.BraveConfig `yaml:"brave"`
+
+	// DuckDuckGo enables the keyless DuckDuckGo provider. It scrapes
+	// the HTML results page, so it is best-effort: a layout change
+	// yields empty results (with a logged warning), not an error.
+	DuckDuckGo search.DuckDuckGoConfig `yaml:"duckduckgo"`
+
+	// Google configures the Google Programmable Search provider. Both
+	// api_key and cx (the search engine ID) are required.
+	Google search.GoogleConfig `yaml:"google"`
+
+	// Cache configures the result cache shared by web_search and
+	// web_fetch across all conversations.
+	Cache SearchCacheConfig `yaml:"cache"`
+}
+
+// SearchCacheConfig configures the LRU result cache in front of web
+// search and page fetches. Repeat queries inside the TTL skip the
+// provider round trip (and, for metered APIs, the quota); callers that
+// need fresh results pass no_cache per request.
+type SearchCacheConfig struct {
+	// TTL is how long a result is reused (Go duration). Empty uses
+	// 10m; "0" disables the cache.
+	TTL string `yaml:"ttl,omitempty"`
+
+	// MaxEntries bounds each cache (search and fetch separately);
+	// least recently used entries are evicted first. Zero uses 256.
+	MaxEntries int `yaml:"max_entries,omitempty"`
+}
+
+// ParsedTTL returns the cache TTL as a [time.Duration], defaulting to
+// 10 minutes when empty. Invalid durations are caught by
+// [Config.Validate]; this method falls back to the default on any
+// parse error.
+func (c SearchCacheConfig) ParsedTTL() time.Duration {
+	if c.TTL == "" {
+		return 10 * time.Minute
+	}
+	d, err := time.ParseDuration(c.TTL)
+	if err != nil {
+		return 10 * time.Minute
+	}
+	return d
+}
+
+// EntryLimit returns MaxEntries, defaulting to 256 when unset.
+func (c SearchCacheConfig) EntryLimit() int {
+	if c.MaxEntries <= 0 {
+		return 256
+	}
+	return c.MaxEntries
 }
 
+// searchProviderNames are the values search.default accepts.
+var searchProviderNames = []string{"searxng", "brave", "duckduckgo", "google"}
+
 // Configured reports whether at least one search provider is configured.
 func (c SearchConfig) Configured() bool {
-	return c.SearXNG.Configured() || c.Brave.Configured()
+	return c.SearXNG.Configured() || c.Brave.Configured() ||
+		c.DuckDuckGo.Configured() || c.Google.Configured()
 }
 
 // EmbeddingsConfig configures vector embedding generation for semantic
@@ -1240,6 +1533,8 @@ type EmbeddingsConfig struct {
 	Enabled bool `yaml:"enabled"`
 
 	// Model is the embedding model name. Default: "nomic-embed-text".
+	// Changing it makes every stored vector stale; run `thane
+	// embeddings backfill` afterwards to re-embed them.
 	Model string `yaml:"model"`
 
 	// BaseURL overrides the Ollama endpoint used for embeddings. Empty
@@ -1247,6 +1542,18 @@ type EmbeddingsConfig struct {
 	BaseURL string `yaml:"baseurl"`
 }
 
+// AuditConfig configures the tool execution audit log. Every tool call
+// the agent makes is recorded in thane.db with its arguments, result,
+// duration, and the conversation that triggered it; `thane audit`
+// queries it.
+type AuditConfig struct {
+	// RedactTools lists tools whose argument values are replaced with
+	// "[redacted]" before they are written, for tools whose inputs are
+	// themselves sensitive (credentials, message bodies). The argument
+	// names, result, and everything else are still recorded.
+	RedactTools []string `yaml:"redact_tools"`
+}
+
 // ArchiveConfig configures session archive behavior.
 type ArchiveConfig struct {
 	// MetadataModel is a soft preference for the LLM model used when
@@ -1276,6 +1583,44 @@ type ArchiveConfig struct {
 	// signal.session_idle_minutes) from "explicitly set to 0"
 	// (disabled). A positive value overrides the inherited default.
 	SessionIdleMinutes *int `yaml:"session_idle_minutes"`
+
+	// RetentionDays prunes ended sessions older than this many days,
+	// along with their messages, tool calls, and iterations. Sessions
+	// tagged "keep" are exempt. Default: 0 (keep everything).
+	RetentionDays int `yaml:"retention_days"`
+
+	// MaxMessages caps the number of archived messages; when exceeded,
+	// the oldest ended sessions are pruned first. Sessions tagged "keep"
+	// are exempt. Default: 0 (no cap).
+	MaxMessages int `yaml:"max_messages"`
+
+	// SemanticSearch builds a vector index over archived messages so
+	// archive_search can rank by meaning (mode semantic or hybrid).
+	// Messages are embedded lazily in the background, newest first.
+	// Requires embeddings.enabled. Default: false.
+	SemanticSearch bool `yaml:"semantic_search"`
+
+	// Incremental attributes each message and tool call to its session
+	// as soon as it is stored, instead of in one batch when the session
+	// is archived on compaction, reset, or shutdown. A crash mid-session
+	// then loses none of the session's transcript. Set false to keep the
+	// batched behavior. Default: true.
+	Incremental *bool `yaml:"incremental"`
+}
+
+// PruneEnabled reports whether any archive retention limit is set.
+func (a ArchiveConfig) PruneEnabled() bool {
+	return a.RetentionDays > 0 || a.MaxMessages > 0
+}
+
+// IncrementalEnabled returns whether messages are attributed to their
+// session as they are stored. Defaults to true when incremental is
+// omitted.
+func (a ArchiveConfig) IncrementalEnabled() bool {
+	if a.Incremental == nil {
+		return true
+	}
+	return *a.Incremental
 }
 
 // ExtractionConfig configures automatic fact extraction from conversations.
@@ -1302,6 +1647,48 @@ type ExtractionConfig struct {
 	TimeoutSeconds int `yaml:"timeout_seconds"`
 }
 
+// ConversationTitlesConfig configures automatic titling of live
+// conversations. When enabled, the agent asynchronously names each new
+// conversation after its first turn and periodically re-checks whether
+// the topic has shifted enough to retitle it. Titles appear in the
+// conversation-list API. Like extraction, this is a background call
+// meant for a local model.
+type ConversationTitlesConfig struct {
+	// Enabled controls whether conversation titles are generated.
+	// Default: false (opt-in).
+	Enabled bool `yaml:"enabled"`
+
+	// Model is the LLM model used for titling.
+	// Default: falls back to archive.metadata_model, then models.default.
+	Model string `yaml:"model"`
+
+	// CheckEveryTurns is how many turns pass between re-checks of an
+	// already-titled conversation. Default: 4.
+	CheckEveryTurns int `yaml:"check_every_turns"`
+
+	// MinIntervalSeconds is the minimum time between title calls for
+	// one conversation, so a rapid exchange does not churn the title.
+	// Default: 300.
+	MinIntervalSeconds int `yaml:"min_interval_seconds"`
+
+	// TimeoutSeconds is the maximum time allowed for a single title
+	// call. Default: 30.
+	TimeoutSeconds int `yaml:"timeout_seconds"`
+}
+
+// PromptsConfig configures the prompt templates behind fact extraction
+// and session metadata. Each <name>.txt file in OverrideDir replaces the
+// built-in template of that name (fact_extraction, session_metadata). A
+// first line of "# version: <tag>" names the override's version, which
+// is recorded with the usage of every call that renders it. Overrides
+// are validated at startup; one with the wrong %s placeholders stops
+// Thane from starting.
+type PromptsConfig struct {
+	// OverrideDir is the directory holding override templates. Empty
+	// uses the built-in templates.
+	OverrideDir string `yaml:"override_dir,omitempty"`
+}
+
 // CompactionConfig controls when conversation compaction runs.
 type CompactionConfig struct {
 	// MaxTokens is the conversation token budget compaction defends;
@@ -1325,6 +1712,7 @@ type EpisodicConfig struct {
 
 	// LookbackDays is how many days of daily memory files to include.
 	// Today and the previous (LookbackDays-1) days are checked.
+	// Older notes stay reachable through the episodic_search tool.
 	// Default: 2 (today + yesterday).
 	LookbackDays int `yaml:"lookback_days"`
 
@@ -1349,6 +1737,163 @@ type AgentConfig struct {
 	// DelegationRequired enables orchestrator tool gating. When false
 	// (the default), all tools are available on every iteration.
 	DelegationRequired bool `yaml:"delegation_required"`
+
+	// StreamHeartbeat is the silence interval after which a streaming
+	// response emits a keepalive (an SSE comment on the OpenAI-compatible
+	// API, an empty chunk on the Ollama-compatible API) so proxies do
+	// not drop the connection while a slow model works. Non-streaming
+	// requests never send heartbeats, and the realtime WebSocket, which
+	// carries no reply stream, keeps itself alive with its own pings.
+	// Accepts Go duration strings. Default: 15s; a negative value
+	// disables.
+	StreamHeartbeat time.Duration `yaml:"stream_heartbeat"`
+
+	// ToolErrorReflection is the number of consecutive failed tool
+	// calls in one turn after which the model is explicitly prompted
+	// to diagnose the failures and choose whether to retry
+	// differently, try an alternative tool, or give up. A successful
+	// call resets the count. Default: 0 (disabled).
+	ToolErrorReflection int `yaml:"tool_error_reflection"`
+
+	// ParallelToolCalls is the maximum number of tool calls from one
+	// model response that run concurrently. Only batches made up
+	// entirely of read-only tools (state reads, file reads, searches)
+	// run in parallel; a batch containing any tool with side effects
+	// runs sequentially. Results are returned to the model in call
+	// order either way. Default: 0 (sequential).
+	ParallelToolCalls int `yaml:"parallel_tool_calls"`
+
+	// MaxIterations is the default number of model iterations (LLM
+	// calls) one run may make before a final text response is forced.
+	// A request's own limit takes precedence. Default: 50.
+	MaxIterations int `yaml:"max_iterations"`
+
+	// ChannelMaxIterations overrides MaxIterations for runs from a
+	// source channel, keyed like channel_tags (e.g., "signal",
+	// "scheduler"). Use it to keep quick replies and background wakes
+	// on a short leash. A request's own limit still takes precedence.
+	ChannelMaxIterations map[string]int `yaml:"channel_max_iterations"`
+
+	// ContextBudget trims requests that would crowd the selected
+	// model's context window, such as a long conversation routed to a
+	// small local model.
+	ContextBudget ContextBudgetConfig `yaml:"context_budget"`
+
+	// ConfidenceGate configures the pre-action confidence check for
+	// autonomous (non-user) runs.
+	ConfidenceGate ConfidenceGateConfig `yaml:"confidence_gate"`
+
+	// FollowUps opts tools into automatic outcome verification, keyed
+	// by tool name. After a successful call, a one-shot wake is
+	// scheduled Window later to check that the expected outcome
+	// actually happened. A follow-up with a Check template only wakes
+	// the agent when the check does not confirm the outcome.
+	FollowUps map[string]FollowUpConfig `yaml:"follow_ups"`
+
+	// ToolRateLimits caps how often a tool may run, keyed by tool
+	// name, counted across all conversations. It stops a model from
+	// hammering a tool with slightly varied arguments, which the
+	// identical-call loop detector does not catch. An over-limit call
+	// is not executed; the model gets a "rate limited, try again
+	// later" result instead. Defaults cap web_search (20 per minute),
+	// web_fetch (30 per minute), and exec (30 per minute); entries
+	// here override them, and calls: 0 removes a limit.
+	ToolRateLimits map[string]ToolRateLimitConfig `yaml:"tool_rate_limits"`
+
+	// MaxToolResultBytes caps how much of a single tool result is
+	// added to the conversation, so one huge response (a full entity
+	// dump, a long web page) cannot crowd out the context window. A
+	// longer result is cut with a marker noting the original size; when
+	// a workspace is configured, the full result is first saved as a
+	// temp file and the marker gives its path so the model can read the
+	// rest in ranges with file_read. Default: 65536 (64 KB); a negative
+	// value disables the cap.
+	MaxToolResultBytes int `yaml:"max_tool_result_bytes"`
+
+	// ToolResultLimits overrides MaxToolResultBytes for individual
+	// tools, keyed by tool name, for tools that legitimately return
+	// large results. A value of 0 lifts the cap for that tool.
+	ToolResultLimits map[string]int `yaml:"tool_result_limits"`
+}
+
+// ToolRateLimitConfig limits one tool to Calls executions per Per.
+type ToolRateLimitConfig struct {
+	// Calls is the number of executions allowed in any window of
+	// length Per. Zero removes the limit.
+	Calls int `yaml:"calls"`
+
+	// Per is the sliding window length. Accepts Go duration strings
+	// (e.g., "1m", "1h"). Required when Calls is positive.
+	Per time.Duration `yaml:"per"`
+}
+
+// FollowUpConfig declares the delayed expected outcome of one tool.
+type FollowUpConfig struct {
+	// Window is how long after the action the outcome is verified.
+	// Accepts Go duration strings (e.g., "30m", "2h"). Required.
+	Window time.Duration `yaml:"window"`
+
+	// Expect describes the outcome to verify, e.g. "the dishwasher
+	// reports its cycle complete".
+	Expect string `yaml:"expect"`
+
+	// Check is an optional Home Assistant template that renders true
+	// once the outcome has occurred, e.g.
+	// "{{ is_state('sensor.dishwasher_status', 'complete') }}". When
+	// it is true at wake time the verification wake is skipped; when
+	// it is false or fails to render, the wake runs as usual.
+	Check string `yaml:"check"`
+}
+
+// ContextBudgetConfig configures context-budget trimming. Sizes are
+// the same rough four-bytes-per-token estimate used for routing.
+type ContextBudgetConfig struct {
+	// Fraction is the share (0.0–1.0) of the selected model's context
+	// window that the system prompt and conversation history may fill.
+	// The remainder is headroom for tool definitions and the response.
+	// Default: 0 (disabled).
+	Fraction float64 `yaml:"fraction"`
+
+	// TrimOrder lists what may be trimmed to fit, lowest priority
+	// first. Each entry is trimmed only as far as needed before the
+	// next is touched. Entries are a context bucket (tagged_guidance,
+	// continuity_context, related_context, live_state),
+	// dynamic_context for all four buckets trimmed in proportion to
+	// their size, or history to drop the oldest messages. Default:
+	// [dynamic_context, history].
+	TrimOrder []string `yaml:"trim_order"`
+}
+
+// ConfidenceGateConfig configures the autonomous-action confidence
+// gate. When enabled, a mutating tool call on an autonomous run (a
+// loop wake, scheduled task, or service loop) first asks the model to
+// rate its confidence in the action. Below Threshold, the action is
+// deferred and the operator is notified for approval instead.
+// Interactive requests are never gated.
+type ConfidenceGateConfig struct {
+	// Enabled turns the gate on. Default: false.
+	Enabled bool `yaml:"enabled"`
+
+	// Threshold is the minimum self-rated confidence (0.0–1.0) an
+	// autonomous action needs to proceed unattended. Default: 0.7.
+	Threshold float64 `yaml:"threshold"`
+
+	// MutatingTools lists the tools subject to the gate. Default: the
+	// Home Assistant control and automation-authoring tools, outbound
+	// email, pull-request merge, and shell exec.
+	MutatingTools []string `yaml:"mutating_tools"`
+
+	// AutonomousSources lists the run "source" hints treated as
+	// autonomous. Default: loop, scheduler, metacognitive, ego,
+	// archivist, email_poll, media_feed, mqtt_wake, mqtt_command,
+	// unifi. Delegate and fanout runs are checked against the source
+	// of the run that launched them.
+	AutonomousSources []string `yaml:"autonomous_sources"`
+
+	// NotifyRecipient is the contact name that receives approval
+	// requests for deferred actions (e.g., "nugget"). Required when
+	// Enabled is true.
+	NotifyRecipient string `yaml:"notify_recipient"`
 }
 
 // DelegateConfig configures the thane_* delegation tools' split-model
@@ -1425,6 +1970,12 @@ type CapabilityTagConfig struct {
 	Tools []string `yaml:"-"`
 }
 
+// capabilityTagNameRe is the syntax every capability tag name must
+// match. Tag names travel through tool arguments, KB frontmatter, and
+// log fields, so whitespace and case variants are rejected at load
+// rather than silently failing to match at runtime.
+var capabilityTagNameRe = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
 // Validate checks that the capability tag configuration is internally
 // consistent. A description is required for non-builtin tags so the
 // operator-defined intent is documented in the capability menu. Tools
@@ -1473,6 +2024,11 @@ type MQTTConfig struct {
 	// Password for MQTT broker authentication.
 	Password string `yaml:"password"`
 
+	// TLS configures certificate verification and client certificates
+	// for brokers dialed over TLS (mqtts://, ssl://, tls://,
+	// mqtt+ssl://, tcps://, and wss://).
+	TLS MQTTTLSConfig `yaml:"tls"`
+
 	// DiscoveryPrefix is the Home Assistant MQTT discovery topic
 	// prefix. Default: "homeassistant".
 	DiscoveryPrefix string `yaml:"discovery_prefix"`
@@ -1485,15 +2041,109 @@ type MQTTConfig struct {
 	// re-published to the broker. Default: 60. Minimum: 10.
 	PublishIntervalSec int `yaml:"publish_interval"`
 
-	// Subscriptions lists MQTT topics to subscribe to for ambient
-	// awareness. Messages are received and logged but not autonomously
-	// acted upon. Supports MQTT wildcard characters (+ and #).
+	// Subscriptions lists MQTT topics to subscribe to. Each routes
+	// matching messages to its wake_loop target loop as event-source
+	// notifications; see [SubscriptionConfig]. Supports MQTT wildcard
+	// characters (+ and #).
 	Subscriptions []SubscriptionConfig `yaml:"subscriptions"`
 
 	// Telemetry configures operational metric publishing. When enabled,
 	// a separate mqtt-telemetry loop publishes system health, token usage,
 	// loop states, and other operational data as native HA sensors.
 	Telemetry TelemetryConfig `yaml:"telemetry"`
+
+	// CommandWake exposes a command topic that Home Assistant
+	// automations publish to in order to run the agent directly.
+	CommandWake MQTTCommandWakeConfig `yaml:"command_wake"`
+}
+
+// MQTTTLSConfig configures TLS for the broker connection. All fields
+// are optional: with none set, a TLS broker is verified against the
+// system roots. Set CertFile and KeyFile together for brokers that
+// require mutual TLS.
+type MQTTTLSConfig struct {
+	// CAFile is a PEM bundle of CA certificates used to verify the
+	// broker instead of the system roots.
+	CAFile string `yaml:"ca_file"`
+
+	// CertFile is the PEM client certificate presented to the broker.
+	CertFile string `yaml:"cert_file"`
+
+	// KeyFile is the PEM private key for CertFile.
+	KeyFile string `yaml:"key_file"`
+
+	// InsecureSkipVerify disables broker certificate verification.
+	// Only for testing against brokers with self-signed certificates
+	// when no CAFile is available.
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
+}
+
+// Broker URL schemes the MQTT client (autopaho) dials in the clear and
+// over TLS. Validation and the publisher both read these lists, so a
+// scheme that passes validation is dialed the way its TLS settings
+// assume.
+var (
+	mqttPlainSchemes = []string{"mqtt", "tcp", "ws"}
+	mqttTLSSchemes   = []string{"mqtts", "ssl", "tls", "mqtt+ssl", "tcps", "wss"}
+)
+
+// MQTTSchemeUsesTLS reports whether the MQTT client dials a broker URL
+// with the given scheme over TLS.
+func MQTTSchemeUsesTLS(scheme string) bool {
+	return slices.Contains(mqttTLSSchemes, strings.ToLower(scheme))
+}
+
+// Configured reports whether any TLS setting is present.
+func (c MQTTTLSConfig) Configured() bool {
+	return c.CAFile != "" || c.CertFile != "" || c.KeyFile != "" || c.InsecureSkipVerify
+}
+
+// validate checks that TLS settings apply to the broker scheme and
+// that every referenced file exists, so a bad path fails at startup
+// rather than on the first connection attempt.
+func (c MQTTTLSConfig) validate(scheme string) error {
+	if !c.Configured() {
+		return nil
+	}
+	if !MQTTSchemeUsesTLS(scheme) {
+		return fmt.Errorf("mqtt.tls is set but mqtt.broker scheme %q is not TLS (expected one of %s)", scheme, strings.Join(mqttTLSSchemes, ", "))
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("mqtt.tls.cert_file and mqtt.tls.key_file must be set together")
+	}
+	for _, f := range []struct{ key, path string }{
+		{"ca_file", c.CAFile},
+		{"cert_file", c.CertFile},
+		{"key_file", c.KeyFile},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			return fmt.Errorf("mqtt.tls.%s %q: %w", f.key, f.path, err)
+		}
+	}
+	return nil
+}
+
+// MQTTCommandWakeConfig configures the command wake topic,
+// thane/{device_name}/command/wake. A JSON payload such as
+// {"message": "...", "quality_floor": "5"} published there runs the
+// agent with that message.
+type MQTTCommandWakeConfig struct {
+	// Enabled subscribes to the command topic on every broker
+	// (re-)connect.
+	Enabled bool `yaml:"enabled"`
+
+	// Debounce drops an identical payload repeated within this window.
+	// Accepts Go duration strings. Default: 10s; a negative value
+	// disables debouncing.
+	Debounce time.Duration `yaml:"debounce"`
+
+	// PublishResult publishes each command's outcome as JSON to
+	// thane/{device_name}/command/wake/result so the automation can
+	// react to the response.
+	PublishResult bool `yaml:"publish_result"`
 }
 
 // SubscriptionConfig describes a single MQTT topic subscription.
@@ -1566,7 +2216,9 @@ func (c MQTTConfig) Configured() bool {
 type PersonConfig struct {
 	// Track is a list of Home Assistant person entity IDs to monitor
 	// (e.g., ["person.nugget", "person.dan"]). Each entry must begin
-	// with "person.". An empty list disables person tracking.
+	// with "person.", qualified with its instance name for a secondary
+	// Home Assistant instance; a primary-qualified entry is stored
+	// bare. An empty list disables person tracking.
 	Track []string `yaml:"track"`
 
 	// Devices maps tracked person entity IDs to their wireless device
@@ -1601,6 +2253,13 @@ type UnifiConfig struct {
 	// PollIntervalSec is how often (in seconds) to poll for wireless
 	// client station data. Default: 30. Minimum: 10.
 	PollIntervalSec int `yaml:"poll_interval"`
+
+	// SecurityWake wakes the unifi-security-handler loop when a device
+	// the poller has not seen in the past week, and that belongs to no
+	// tracked person, joins the network. Unrecognized devices are
+	// logged with their name and manufacturer either way. Default:
+	// false.
+	SecurityWake bool `yaml:"security_wake"`
 }
 
 // Configured reports whether both URL and APIKey are set, indicating
@@ -1615,6 +2274,14 @@ type DebugConfig struct {
 	// (categories, parent/child, error states, node churn) so the
 	// dashboard can be iterated on without real service dependencies.
 	DemoLoops bool `yaml:"demo_loops"`
+
+	// PromptEndpoint serves GET /debug/prompt, which returns the system
+	// prompt most recently assembled for a conversation along with the
+	// size of each section. The agent keeps the last prompt of recent
+	// conversations in memory while this is on. The endpoint stays
+	// disabled unless listen.admin_token is set, and requires it as a
+	// bearer token. Default: false.
+	PromptEndpoint bool `yaml:"prompt_endpoint"`
 }
 
 // ShellExecConfig configures the agent's ability to execute shell
@@ -1657,7 +2324,8 @@ type MCPServerConfig struct {
 	// (e.g., "home-assistant", "github"). Required.
 	Name string `yaml:"name"`
 
-	// Transport is the connection type: "stdio" or "http". Required.
+	// Transport is the connection type: "stdio", "http" (streamable
+	// HTTP), or "sse" (the older HTTP+SSE transport). Required.
 	Transport string `yaml:"transport"`
 
 	// Command is the executable to spawn (stdio transport only).
@@ -1670,13 +2338,20 @@ type MCPServerConfig struct {
 	// (stdio transport only). Format: "KEY=VALUE".
 	Env []string `yaml:"env"`
 
-	// URL is the MCP server endpoint (http transport only).
+	// URL is the MCP server endpoint (http and sse transports only).
+	// For sse it is the event stream URL.
 	URL string `yaml:"url"`
 
 	// Headers are additional HTTP headers sent with every request
-	// (http transport only). Useful for authentication tokens.
+	// (http and sse transports only). Useful for authentication tokens.
 	Headers map[string]string `yaml:"headers"`
 
+	// OAuth2 configures an OAuth2 client-credentials grant whose bearer
+	// tokens authenticate every request (http transport only). Tokens
+	// are cached and refreshed before expiry. Optional; static Headers
+	// still apply.
+	OAuth2 *MCPOAuth2Config `yaml:"oauth2,omitempty"`
+
 	// IncludeTools is an optional allowlist of MCP tool names to
 	// bridge. When non-empty, only tools in this list are registered.
 	// Cannot be used together with ExcludeTools.
@@ -1695,6 +2370,29 @@ type MCPServerConfig struct {
 	// Tools contains optional metadata overrides keyed by the raw MCP tool
 	// name reported by the server.
 	Tools map[string]MCPToolConfig `yaml:"tools"`
+
+	// Resources is an optional allowlist of MCP resource URIs whose
+	// contents are read from the server and injected into the system
+	// prompt on every turn (e.g., a README or configuration document).
+	// Binary resources are summarized rather than injected, and the
+	// injected total is capped at 64 KB per server.
+	Resources []string `yaml:"resources"`
+}
+
+// MCPOAuth2Config configures the OAuth2 client-credentials grant for an
+// HTTP MCP server.
+type MCPOAuth2Config struct {
+	// TokenURL is the authorization server's token endpoint. Required.
+	TokenURL string `yaml:"token_url"`
+
+	// ClientID identifies this client to the token endpoint. Required.
+	ClientID string `yaml:"client_id"`
+
+	// ClientSecret authenticates the client to the token endpoint.
+	ClientSecret string `yaml:"client_secret"`
+
+	// Scopes are requested with each token. Optional.
+	Scopes []string `yaml:"scopes"`
 }
 
 // MCPToolConfig configures operator-supplied metadata for a bridged MCP tool.
@@ -1765,6 +2463,21 @@ type SignalConfig struct {
 	// enough to cover tool execution (e.g., media_transcript) plus
 	// the subsequent LLM response. Default: 10m.
 	HandleTimeout time.Duration `yaml:"handle_timeout"`
+
+	// TypingIndicators controls whether the bridge shows a typing
+	// indicator, refreshed until the reply is sent, while a message
+	// is being processed. Default: true.
+	TypingIndicators *bool `yaml:"typing_indicators"`
+}
+
+// TypingIndicatorsEnabled returns whether the Signal bridge sends
+// typing indicators. Defaults to true when typing_indicators is
+// omitted.
+func (s SignalConfig) TypingIndicatorsEnabled() bool {
+	if s.TypingIndicators == nil {
+		return true
+	}
+	return *s.TypingIndicators
 }
 
 // SignalRoutingConfig controls model selection for Signal messages.
@@ -1787,6 +2500,12 @@ type SignalRoutingConfig struct {
 	// DelegationGating controls whether delegation-first tool gating
 	// is active. Default: "disabled".
 	DelegationGating string `yaml:"delegation_gating"`
+
+	// MaxLatencyMs is the reply latency budget in milliseconds. When
+	// set, the router avoids models whose recently observed latency
+	// exceeds it, as long as a faster model meets the quality floor.
+	// Zero disables the budget.
+	MaxLatencyMs int `yaml:"max_latency_ms"`
 }
 
 // LoopProfile converts the Signal routing config into the shared
@@ -1805,12 +2524,16 @@ func (c SignalRoutingConfig) LoopProfile() router.LoopProfile {
 	// time so this conversion never sees a "high"-shaped string in
 	// a validated config.
 	floor, _ := strconv.Atoi(strings.TrimSpace(c.QualityFloor))
-	return router.LoopProfile{
+	profile := router.LoopProfile{
 		Model:            c.Model,
 		QualityFloor:     floor,
 		Mission:          c.Mission,
 		DelegationGating: c.DelegationGating,
 	}
+	if c.MaxLatencyMs > 0 {
+		profile.ExtraHints = map[string]string{router.HintMaxLatencyMs: strconv.Itoa(c.MaxLatencyMs)}
+	}
+	return profile
 }
 
 // Configured reports whether the Signal bridge has the minimum
@@ -1889,8 +2612,10 @@ type MediaConfig struct {
 	WhisperModel string `yaml:"whisper_model"`
 
 	// TranscriptDir is the directory for durable transcript storage.
-	// Each transcript is saved as a markdown file with YAML frontmatter.
-	// If empty, transcripts are returned in-context only (not persisted).
+	// Each transcript is saved as a markdown file with YAML frontmatter,
+	// one per video and subtitle language, and is reused when the same
+	// video is requested again. If empty, transcripts are returned
+	// in-context only (not persisted).
 	// This is typically a generated/artifact root rather than a curated
 	// knowledge root.
 	TranscriptDir string `yaml:"transcript_dir"`
@@ -2050,6 +2775,43 @@ type StateWindowConfig struct {
 	MaxAgeMinutes int `yaml:"max_age_minutes"`
 }
 
+// SensorWebhookConfig configures the sensor webhook receiver. Each
+// sensor is served at POST <path>/<name>; the JSON body's Field value
+// becomes the virtual entity's new state.
+type SensorWebhookConfig struct {
+	// Enabled turns on the receiver. Requires Secret and at least one
+	// sensor.
+	Enabled bool `yaml:"enabled"`
+
+	// Path is the URL prefix the receiver listens under on the API
+	// server. Default: /v1/webhooks/sensors.
+	Path string `yaml:"path"`
+
+	// Secret is the shared secret callers must send in the
+	// X-Webhook-Secret header. Requests without it are rejected.
+	Secret string `yaml:"secret"`
+
+	// Sensors maps a webhook sensor name (the final path segment) to
+	// the virtual entity it feeds.
+	Sensors map[string]WebhookSensorConfig `yaml:"sensors"`
+}
+
+// WebhookSensorConfig maps one webhook sensor name to a virtual
+// entity.
+type WebhookSensorConfig struct {
+	// EntityID is the virtual entity ID reported in the state window.
+	// Default: sensor.webhook_<name>.
+	EntityID string `yaml:"entity_id"`
+
+	// Field is the dotted path of the state value in the JSON payload
+	// (e.g. "state" or "data.temperature"). Default: state.
+	Field string `yaml:"field"`
+
+	// DeviceClass is an optional HA device class used to render the
+	// state semantically (e.g. "door" renders on/off as open/closed).
+	DeviceClass string `yaml:"device_class,omitempty"`
+}
+
 // Load reads a YAML configuration file, expands environment variables,
 // applies defaults for any unset fields, and validates the result.
 //
@@ -2362,11 +3124,43 @@ func (c *Config) applyDefaults() {
 	if c.Models.OllamaURL == "" && len(c.Models.Resources) == 0 {
 		c.Models.OllamaURL = "http://localhost:11434"
 	}
+	if c.Models.Budget.Margin == nil {
+		margin := 0.1
+		c.Models.Budget.Margin = &margin
+	}
+	if c.Models.Retry.MaxRetries == nil {
+		retries := 3
+		c.Models.Retry.MaxRetries = &retries
+	}
+	if c.Models.Retry.InitialDelay == 0 {
+		c.Models.Retry.InitialDelay = 2 * time.Second
+	}
+	if c.Models.Retry.MaxDelay == 0 {
+		c.Models.Retry.MaxDelay = 30 * time.Second
+	}
+	if len(c.UsageAlerts.Thresholds) == 0 {
+		c.UsageAlerts.Thresholds = []float64{50, 80, 100}
+	}
+	if c.UsageAlerts.Recipient == "" {
+		c.UsageAlerts.Recipient = c.Identity.OwnerContactName
+	}
+	if c.Checkpoints.FullEvery == 0 {
+		c.Checkpoints.FullEvery = 10
+	}
+	if c.Database.BusyTimeout == 0 {
+		c.Database.BusyTimeout = 5 * time.Second
+	}
+	if c.Database.WALCheckpointInterval == 0 {
+		c.Database.WALCheckpointInterval = 10 * time.Minute
+	}
 	for name, srv := range c.Models.Resources {
 		srv.Provider = strings.ToLower(strings.TrimSpace(srv.Provider))
 		if srv.Provider == "" {
 			srv.Provider = "ollama"
 		}
+		if srv.Provider == "openai" && strings.TrimSpace(srv.URL) == "" {
+			srv.URL = "https://api.openai.com/v1"
+		}
 		c.Models.Resources[name] = srv
 	}
 	if c.OllamaAPI.Port == 0 {
@@ -2416,6 +3210,18 @@ func (c *Config) applyDefaults() {
 	if c.Extraction.TimeoutSeconds == 0 {
 		c.Extraction.TimeoutSeconds = 30
 	}
+	if c.ConversationTitles.Model == "" {
+		c.ConversationTitles.Model = c.Archive.MetadataModel
+	}
+	if c.ConversationTitles.CheckEveryTurns == 0 {
+		c.ConversationTitles.CheckEveryTurns = 4
+	}
+	if c.ConversationTitles.MinIntervalSeconds == 0 {
+		c.ConversationTitles.MinIntervalSeconds = 300
+	}
+	if c.ConversationTitles.TimeoutSeconds == 0 {
+		c.ConversationTitles.TimeoutSeconds = 30
+	}
 
 	if c.MQTT.DiscoveryPrefix == "" {
 		c.MQTT.DiscoveryPrefix = "homeassistant"
@@ -2423,6 +3229,9 @@ func (c *Config) applyDefaults() {
 	if c.MQTT.PublishIntervalSec == 0 {
 		c.MQTT.PublishIntervalSec = 60
 	}
+	if c.MQTT.CommandWake.Debounce == 0 {
+		c.MQTT.CommandWake.Debounce = 10 * time.Second
+	}
 	if c.MQTT.Telemetry.Interval == 0 {
 		c.MQTT.Telemetry.Interval = 60
 	}
@@ -2508,6 +3317,7 @@ func (c *Config) applyDefaults() {
 	if c.Agent.DelegationRequired && len(c.Agent.OrchestratorTools) == 0 {
 		c.Agent.OrchestratorTools = []string{
 			"thane_now",
+			"thane_fanout",
 			"thane_assign",
 			"recall_fact",
 			"remember_fact",
@@ -2520,6 +3330,71 @@ func (c *Config) applyDefaults() {
 		}
 	}
 
+	if c.Agent.StreamHeartbeat == 0 {
+		c.Agent.StreamHeartbeat = 15 * time.Second
+	}
+
+	if c.Agent.MaxIterations == 0 {
+		c.Agent.MaxIterations = 50
+	}
+
+	// Default caps for the tools that reach outside the host or cost
+	// real time per call. Explicit entries, including calls: 0 to
+	// lift a cap, win.
+	if c.Agent.ToolRateLimits == nil {
+		c.Agent.ToolRateLimits = make(map[string]ToolRateLimitConfig)
+	}
+	for name, limit := range map[string]ToolRateLimitConfig{
+		"web_search": {Calls: 20, Per: time.Minute},
+		"web_fetch":  {Calls: 30, Per: time.Minute},
+		"exec":       {Calls: 30, Per: time.Minute},
+	} {
+		if _, ok := c.Agent.ToolRateLimits[name]; !ok {
+			c.Agent.ToolRateLimits[name] = limit
+		}
+	}
+
+	if c.Agent.MaxToolResultBytes == 0 {
+		c.Agent.MaxToolResultBytes = 64 * 1024
+	}
+
+	if c.Agent.ContextBudget.Fraction > 0 && len(c.Agent.ContextBudget.TrimOrder) == 0 {
+		c.Agent.ContextBudget.TrimOrder = []string{"dynamic_context", "history"}
+	}
+
+	if c.Agent.ConfidenceGate.Enabled {
+		if c.Agent.ConfidenceGate.Threshold == 0 {
+			c.Agent.ConfidenceGate.Threshold = 0.7
+		}
+		if len(c.Agent.ConfidenceGate.MutatingTools) == 0 {
+			c.Agent.ConfidenceGate.MutatingTools = []string{
+				"ha_call_service",
+				"ha_control_device",
+				"ha_automation_create",
+				"ha_automation_update",
+				"ha_automation_delete",
+				"email_send",
+				"email_reply",
+				"forge_pr_merge",
+				"exec",
+			}
+		}
+		if len(c.Agent.ConfidenceGate.AutonomousSources) == 0 {
+			c.Agent.ConfidenceGate.AutonomousSources = []string{
+				"loop",
+				"scheduler",
+				"metacognitive",
+				"ego",
+				"archivist",
+				"email_poll",
+				"media_feed",
+				"mqtt_wake",
+				"mqtt_command",
+				"unifi",
+			}
+		}
+	}
+
 	// Signal session idle timeout: 0 disables idle rotation (no default override).
 	// Users who want idle rotation must set a positive value explicitly.
 
@@ -2552,6 +3427,23 @@ func (c *Config) applyDefaults() {
 
 	c.Email.ApplyDefaults()
 
+	if c.HomeAssistant.Name == "" {
+		c.HomeAssistant.Name = "home"
+	}
+	// Tracked people are registered under the IDs the state watchers
+	// report, so "home:person.alice" on the primary becomes
+	// person.alice.
+	for i, id := range c.Person.Track {
+		c.Person.Track[i] = c.HomeAssistant.CanonicalEntityID(id)
+	}
+	if len(c.Person.Devices) > 0 {
+		devices := make(map[string][]DeviceMapping, len(c.Person.Devices))
+		for id, devs := range c.Person.Devices {
+			id = c.HomeAssistant.CanonicalEntityID(id)
+			devices[id] = append(devices[id], devs...)
+		}
+		c.Person.Devices = devices
+	}
 	if c.StateWindow.MaxEntries == 0 {
 		c.StateWindow.MaxEntries = 50
 	}
@@ -2559,6 +3451,21 @@ func (c *Config) applyDefaults() {
 		c.StateWindow.MaxAgeMinutes = 30
 	}
 
+	if c.SensorWebhook.Enabled {
+		if c.SensorWebhook.Path == "" {
+			c.SensorWebhook.Path = "/v1/webhooks/sensors"
+		}
+		for name, sensor := range c.SensorWebhook.Sensors {
+			if sensor.EntityID == "" {
+				sensor.EntityID = "sensor.webhook_" + name
+			}
+			if sensor.Field == "" {
+				sensor.Field = "state"
+			}
+			c.SensorWebhook.Sensors[name] = sensor
+		}
+	}
+
 	for i := range c.Models.Available {
 		if c.Models.Available[i].Provider == "" && c.Models.Available[i].Resource == "" {
 			c.Models.Available[i].Provider = "ollama"
@@ -2626,6 +3533,22 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("timezone %q invalid (expected IANA timezone, e.g. America/Chicago): %w", c.Timezone, err)
 		}
 	}
+	if c.Search.Default != "" && !slices.Contains(searchProviderNames, c.Search.Default) {
+		return fmt.Errorf("search.default %q invalid (expected one of %s)", c.Search.Default, strings.Join(searchProviderNames, ", "))
+	}
+	if (c.Search.Google.APIKey == "") != (c.Search.Google.CX == "") {
+		return fmt.Errorf("search.google requires both api_key and cx")
+	}
+	if c.Search.Cache.TTL != "" {
+		if d, err := time.ParseDuration(c.Search.Cache.TTL); err != nil {
+			return fmt.Errorf("search.cache.ttl %q: %w", c.Search.Cache.TTL, err)
+		} else if d < 0 {
+			return fmt.Errorf("search.cache.ttl %q must not be negative", c.Search.Cache.TTL)
+		}
+	}
+	if c.Search.Cache.MaxEntries < 0 {
+		return fmt.Errorf("search.cache.max_entries %d must not be negative", c.Search.Cache.MaxEntries)
+	}
 	if c.MQTT.Configured() {
 		u, err := url.Parse(c.MQTT.Broker)
 		if err != nil {
@@ -2634,11 +3557,12 @@ func (c *Config) Validate() error {
 		if u.Scheme == "" || u.Host == "" {
 			return fmt.Errorf("mqtt.broker %q must include a scheme and host", c.MQTT.Broker)
 		}
-		switch u.Scheme {
-		case "mqtt", "mqtts", "ssl", "ws", "wss":
-			// supported schemes
-		default:
-			return fmt.Errorf("mqtt.broker scheme %q invalid (expected one of mqtt, mqtts, ssl, ws, wss)", u.Scheme)
+		if !slices.Contains(mqttPlainSchemes, u.Scheme) && !MQTTSchemeUsesTLS(u.Scheme) {
+			return fmt.Errorf("mqtt.broker scheme %q invalid (expected one of %s)", u.Scheme,
+				strings.Join(slices.Concat(mqttPlainSchemes, mqttTLSSchemes), ", "))
+		}
+		if err := c.MQTT.TLS.validate(u.Scheme); err != nil {
+			return err
 		}
 		if c.MQTT.PublishIntervalSec < 10 {
 			return fmt.Errorf("mqtt.publish_interval %d too low (minimum 10 seconds)", c.MQTT.PublishIntervalSec)
@@ -2680,6 +3604,9 @@ func (c *Config) Validate() error {
 		}
 	}
 	for tagName, tagCfg := range c.CapabilityTags {
+		if !capabilityTagNameRe.MatchString(tagName) {
+			return fmt.Errorf("capability_tags: tag name %q is invalid (want lowercase letters, digits, _ or -, starting with a letter)", tagName)
+		}
 		builtin := toolcatalog.HasBuiltinTag(tagName) || allowedTags[tagName]
 		if err := tagCfg.Validate(tagName, builtin); err != nil {
 			return err
@@ -2688,6 +3615,9 @@ func (c *Config) Validate() error {
 	}
 	for channel, tagNames := range c.ChannelTags {
 		for _, tagName := range tagNames {
+			if !capabilityTagNameRe.MatchString(tagName) {
+				return fmt.Errorf("channel_tags.%s: tag name %q is invalid (want lowercase letters, digits, _ or -, starting with a letter)", channel, tagName)
+			}
 			if !allowedTags[tagName] {
 				return fmt.Errorf("channel_tags.%s references undefined capability tag %q", channel, tagName)
 			}
@@ -2702,18 +3632,42 @@ func (c *Config) Validate() error {
 	if c.Archive.SessionIdleMinutes != nil && *c.Archive.SessionIdleMinutes < 0 {
 		return fmt.Errorf("archive.session_idle_minutes %d must be non-negative", *c.Archive.SessionIdleMinutes)
 	}
+	if c.Archive.RetentionDays < 0 {
+		return fmt.Errorf("archive.retention_days %d must be non-negative", c.Archive.RetentionDays)
+	}
+	if c.Archive.MaxMessages < 0 {
+		return fmt.Errorf("archive.max_messages %d must be non-negative", c.Archive.MaxMessages)
+	}
+	if c.Archive.SemanticSearch && !c.Embeddings.Enabled {
+		return fmt.Errorf("archive.semantic_search requires embeddings.enabled")
+	}
 	for i, id := range c.Person.Track {
-		if !strings.HasPrefix(id, "person.") {
+		// Entities on a secondary Home Assistant instance are tracked
+		// by their qualified ID, e.g. "cabin:person.alice".
+		bare := id
+		if instance, rest, qualified := strings.Cut(id, ":"); qualified {
+			if _, ok := c.HomeAssistant.Instances[instance]; !ok && instance != c.HomeAssistant.Name {
+				return fmt.Errorf("person.track[%d] %q references unknown homeassistant instance %q", i, id, instance)
+			}
+			bare = rest
+		}
+		if !strings.HasPrefix(bare, "person.") {
 			return fmt.Errorf("person.track[%d] %q must start with \"person.\"", i, id)
 		}
 	}
-	// Validate person.devices references only tracked entities.
+	// Validate person.devices references only tracked entities,
+	// comparing canonical IDs so "home:person.alice" on the primary
+	// matches person.alice.
 	tracked := make(map[string]bool, len(c.Person.Track))
-	for _, id := range c.Person.Track {
-		tracked[id] = true
+	for i, id := range c.Person.Track {
+		canonical := c.HomeAssistant.CanonicalEntityID(id)
+		if tracked[canonical] {
+			return fmt.Errorf("person.track[%d] %q is already tracked", i, id)
+		}
+		tracked[canonical] = true
 	}
 	for entityID := range c.Person.Devices {
-		if !tracked[entityID] {
+		if !tracked[c.HomeAssistant.CanonicalEntityID(entityID)] {
 			return fmt.Errorf("person.devices references untracked entity %q", entityID)
 		}
 	}
@@ -2759,6 +3713,41 @@ func (c *Config) Validate() error {
 	if c.StateWindow.MaxAgeMinutes < 1 {
 		return fmt.Errorf("state_window.max_age_minutes %d must be positive", c.StateWindow.MaxAgeMinutes)
 	}
+	if c.Agent.ToolErrorReflection < 0 {
+		return fmt.Errorf("agent.tool_error_reflection %d must not be negative", c.Agent.ToolErrorReflection)
+	}
+	if c.Agent.ParallelToolCalls < 0 {
+		return fmt.Errorf("agent.parallel_tool_calls %d must not be negative", c.Agent.ParallelToolCalls)
+	}
+	if c.Agent.MaxIterations < 1 {
+		return fmt.Errorf("agent.max_iterations %d must be positive", c.Agent.MaxIterations)
+	}
+	for channel, n := range c.Agent.ChannelMaxIterations {
+		if n < 1 {
+			return fmt.Errorf("agent.channel_max_iterations.%s %d must be positive", channel, n)
+		}
+	}
+	for name, fu := range c.Agent.FollowUps {
+		if fu.Window <= 0 {
+			return fmt.Errorf("agent.follow_ups.%s.window must be positive", name)
+		}
+	}
+	for name, limit := range c.Agent.ToolRateLimits {
+		if limit.Calls < 0 {
+			return fmt.Errorf("agent.tool_rate_limits.%s.calls %d must not be negative", name, limit.Calls)
+		}
+		if limit.Calls > 0 && limit.Per <= 0 {
+			return fmt.Errorf("agent.tool_rate_limits.%s.per must be positive", name)
+		}
+	}
+	for name, n := range c.Agent.ToolResultLimits {
+		if n < 0 {
+			return fmt.Errorf("agent.tool_result_limits.%s %d must not be negative", name, n)
+		}
+	}
+	if err := c.validateSensorWebhook(); err != nil {
+		return err
+	}
 	if c.Prewarm.Enabled && c.Prewarm.MaxFacts < 1 {
 		return fmt.Errorf("prewarm.max_facts %d must be positive when prewarm is enabled", c.Prewarm.MaxFacts)
 	}
@@ -2791,6 +3780,12 @@ func (c *Config) Validate() error {
 	if err := c.validateEgo(); err != nil {
 		return err
 	}
+	if err := c.validateContextBudget(); err != nil {
+		return err
+	}
+	if err := c.validateConfidenceGate(); err != nil {
+		return err
+	}
 	if err := c.validateLoops(); err != nil {
 		return err
 	}
@@ -2837,6 +3832,80 @@ func (c *Config) validateModels() error {
 		default:
 			return fmt.Errorf("models.available[%d] (%s): min_complexity %q invalid (expected simple, moderate, complex)", i, m.Name, m.MinComplexity)
 		}
+		if m.Timeout < 0 {
+			return fmt.Errorf("models.available[%d] (%s): timeout must be >= 0", i, m.Name)
+		}
+	}
+	if c.Models.Budget.DailyUSD < 0 {
+		return fmt.Errorf("models.budget.daily_usd must be >= 0")
+	}
+	if c.Models.Budget.MonthlyUSD < 0 {
+		return fmt.Errorf("models.budget.monthly_usd must be >= 0")
+	}
+	if m := c.Models.Budget.Margin; m != nil && (*m < 0 || *m > 1.0) {
+		return fmt.Errorf("models.budget.margin %.2f must be in [0.0, 1.0]", *m)
+	}
+	if r := c.Models.Retry.MaxRetries; r != nil && *r < 0 {
+		return fmt.Errorf("models.retry.max_retries must be >= 0")
+	}
+	if c.Models.Retry.InitialDelay < 0 || c.Models.Retry.MaxDelay < 0 {
+		return fmt.Errorf("models.retry delays must be >= 0")
+	}
+	if c.Models.Retry.MaxDelay < c.Models.Retry.InitialDelay {
+		return fmt.Errorf("models.retry.max_delay %s must be >= initial_delay %s", c.Models.Retry.MaxDelay, c.Models.Retry.InitialDelay)
+	}
+	models := make([]string, 0, len(c.Pricing))
+	for model := range c.Pricing {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+	for _, model := range models {
+		p := c.Pricing[model]
+		if p.InputPerMillion < 0 || p.OutputPerMillion < 0 || p.CacheReadPerMillion < 0 || p.CacheWritePerMillion < 0 || p.CacheWrite1hPerMillion < 0 {
+			return fmt.Errorf("pricing.%s: prices must be >= 0", model)
+		}
+	}
+	if c.UsageAlerts.DailyUSD < 0 {
+		return fmt.Errorf("usage_alerts.daily_usd must be >= 0")
+	}
+	if c.UsageAlerts.MonthlyUSD < 0 {
+		return fmt.Errorf("usage_alerts.monthly_usd must be >= 0")
+	}
+	for _, t := range c.UsageAlerts.Thresholds {
+		if t <= 0 {
+			return fmt.Errorf("usage_alerts.thresholds: %g must be > 0", t)
+		}
+	}
+	if c.UsageAlerts.Interval != "" {
+		d, err := time.ParseDuration(c.UsageAlerts.Interval)
+		if err != nil {
+			return fmt.Errorf("usage_alerts.interval %q: %w", c.UsageAlerts.Interval, err)
+		}
+		if d < time.Minute {
+			return fmt.Errorf("usage_alerts.interval %s must be at least 1m", d)
+		}
+	}
+	if c.Checkpoints.FullEvery < 0 {
+		return fmt.Errorf("checkpoints.full_every must be >= 0")
+	}
+	if c.Database.BusyTimeout < 0 {
+		return fmt.Errorf("database.busy_timeout must be >= 0")
+	}
+	if c.Database.WALCheckpointInterval < 0 {
+		return fmt.Errorf("database.wal_checkpoint_interval must be >= 0")
+	}
+	for primary, chain := range c.Models.FallbackChains {
+		if strings.TrimSpace(primary) == "" {
+			return fmt.Errorf("models.fallback_chains contains an empty model name")
+		}
+		for i, hop := range chain {
+			switch strings.TrimSpace(hop) {
+			case "":
+				return fmt.Errorf("models.fallback_chains.%s[%d] must not be empty", primary, i)
+			case primary:
+				return fmt.Errorf("models.fallback_chains.%s[%d] must not name the primary model", primary, i)
+			}
+		}
 	}
 	return nil
 }
@@ -3136,6 +4205,70 @@ func (c *Config) validateLoops() error {
 	return nil
 }
 
+// validateContextBudget checks the context-budget fraction and trim
+// order.
+func (c *Config) validateContextBudget() error {
+	b := c.Agent.ContextBudget
+	if b.Fraction < 0 || b.Fraction > 1.0 {
+		return fmt.Errorf("agent.context_budget.fraction %.2f must be in [0.0, 1.0]", b.Fraction)
+	}
+	valid := []string{"dynamic_context", "tagged_guidance", "continuity_context", "related_context", "live_state", "history"}
+	seen := make(map[string]bool, len(b.TrimOrder))
+	for _, entry := range b.TrimOrder {
+		if !slices.Contains(valid, entry) {
+			return fmt.Errorf("agent.context_budget.trim_order entry %q is not one of %s", entry, strings.Join(valid, ", "))
+		}
+		if seen[entry] {
+			return fmt.Errorf("agent.context_budget.trim_order lists %q more than once", entry)
+		}
+		seen[entry] = true
+	}
+	return nil
+}
+
+// validateConfidenceGate checks the autonomous-action confidence gate.
+func (c *Config) validateConfidenceGate() error {
+	g := c.Agent.ConfidenceGate
+	if !g.Enabled {
+		return nil
+	}
+	if g.Threshold <= 0 || g.Threshold > 1.0 {
+		return fmt.Errorf("agent.confidence_gate.threshold %.2f must be in (0.0, 1.0]", g.Threshold)
+	}
+	if strings.TrimSpace(g.NotifyRecipient) == "" {
+		return fmt.Errorf("agent.confidence_gate.notify_recipient required when agent.confidence_gate.enabled is true")
+	}
+	return nil
+}
+
+// validateSensorWebhook checks the sensor webhook receiver when it is
+// enabled: the path must be a mountable URL prefix, the shared secret
+// is mandatory, and every sensor must map to a domain-qualified entity.
+func (c *Config) validateSensorWebhook() error {
+	w := c.SensorWebhook
+	if !w.Enabled {
+		return nil
+	}
+	if !strings.HasPrefix(w.Path, "/") || strings.HasSuffix(w.Path, "/") || strings.ContainsAny(w.Path, "{} \t") {
+		return fmt.Errorf("sensor_webhook.path %q must start with /, must not end with /, and must not contain spaces or braces", w.Path)
+	}
+	if strings.TrimSpace(w.Secret) == "" {
+		return fmt.Errorf("sensor_webhook.secret required when sensor_webhook.enabled is true")
+	}
+	if len(w.Sensors) == 0 {
+		return fmt.Errorf("sensor_webhook.sensors must define at least one sensor when sensor_webhook.enabled is true")
+	}
+	for name, sensor := range w.Sensors {
+		if name == "" || strings.ContainsAny(name, "/{} ") {
+			return fmt.Errorf("sensor_webhook.sensors name %q must be a single non-empty path segment", name)
+		}
+		if domain, object, ok := strings.Cut(sensor.EntityID, "."); !ok || domain == "" || object == "" {
+			return fmt.Errorf("sensor_webhook.sensors.%s.entity_id %q must be of the form domain.object_id", name, sensor.EntityID)
+		}
+	}
+	return nil
+}
+
 // validateDelegate checks delegate profile overrides for invalid values.
 func (c *Config) validateDelegate() error {
 	for name, p := range c.Delegate.Profiles {
@@ -3172,17 +4305,35 @@ func (c *Config) validateMCP() error {
 			if srv.Command == "" {
 				return fmt.Errorf("mcp.servers[%d] (%s): stdio transport requires a command", i, srv.Name)
 			}
-		case "http":
+		case "http", "sse":
 			if srv.URL == "" {
-				return fmt.Errorf("mcp.servers[%d] (%s): http transport requires a url", i, srv.Name)
+				return fmt.Errorf("mcp.servers[%d] (%s): %s transport requires a url", i, srv.Name, srv.Transport)
 			}
 		default:
-			return fmt.Errorf("mcp.servers[%d] (%s): transport %q invalid (expected stdio or http)", i, srv.Name, srv.Transport)
+			return fmt.Errorf("mcp.servers[%d] (%s): transport %q invalid (expected stdio, http, or sse)", i, srv.Name, srv.Transport)
+		}
+
+		if o := srv.OAuth2; o != nil {
+			if srv.Transport != "http" {
+				return fmt.Errorf("mcp.servers[%d] (%s): oauth2 requires the http transport", i, srv.Name)
+			}
+			if o.TokenURL == "" {
+				return fmt.Errorf("mcp.servers[%d] (%s): oauth2.token_url must not be empty", i, srv.Name)
+			}
+			if o.ClientID == "" {
+				return fmt.Errorf("mcp.servers[%d] (%s): oauth2.client_id must not be empty", i, srv.Name)
+			}
 		}
 
 		if len(srv.IncludeTools) > 0 && len(srv.ExcludeTools) > 0 {
 			return fmt.Errorf("mcp.servers[%d] (%s): cannot set both include_tools and exclude_tools", i, srv.Name)
 		}
+
+		for j, uri := range srv.Resources {
+			if strings.TrimSpace(uri) == "" {
+				return fmt.Errorf("mcp.servers[%d] (%s): resources[%d] must not be empty", i, srv.Name, j)
+			}
+		}
 	}
 	return nil
 }
@@ -3195,6 +4346,33 @@ func (c *Config) validateSubscribe() error {
 	if c.HomeAssistant.IngestRateLimitPerMinute < 0 {
 		return fmt.Errorf("homeassistant.ingest_rate_limit_per_minute %d must be non-negative", c.HomeAssistant.IngestRateLimitPerMinute)
 	}
+	return c.validateHomeAssistantInstances()
+}
+
+// validateHomeAssistantInstances checks the federated instance names
+// and connections. Instance names become entity ID prefixes, so they
+// follow the same syntax as capability tag names.
+func (c *Config) validateHomeAssistantInstances() error {
+	if len(c.HomeAssistant.Instances) == 0 {
+		return nil
+	}
+	if !c.HomeAssistant.Configured() {
+		return fmt.Errorf("homeassistant.instances requires the primary homeassistant.url and homeassistant.token")
+	}
+	if !capabilityTagNameRe.MatchString(c.HomeAssistant.Name) {
+		return fmt.Errorf("homeassistant.name %q must match %s", c.HomeAssistant.Name, capabilityTagNameRe)
+	}
+	for name, inst := range c.HomeAssistant.Instances {
+		if !capabilityTagNameRe.MatchString(name) {
+			return fmt.Errorf("homeassistant.instances: name %q must match %s", name, capabilityTagNameRe)
+		}
+		if name == c.HomeAssistant.Name {
+			return fmt.Errorf("homeassistant.instances.%s: name collides with the primary instance name", name)
+		}
+		if inst.URL == "" || inst.Token == "" {
+			return fmt.Errorf("homeassistant.instances.%s: url and token are required", name)
+		}
+	}
 	return nil
 }
 
@@ -3232,6 +4410,9 @@ func (c *Config) validateSignal() error {
 			return fmt.Errorf("signal.routing.quality_floor %q is not a valid integer", c.Signal.Routing.QualityFloor)
 		}
 	}
+	if c.Signal.Routing.MaxLatencyMs < 0 {
+		return fmt.Errorf("signal.routing.max_latency_ms %d must be non-negative", c.Signal.Routing.MaxLatencyMs)
+	}
 	profile := c.Signal.Routing.LoopProfile()
 	if err := profile.Validate(); err != nil {
 		return fmt.Errorf("signal.routing: %w", err)
@@ -3,9 +3,13 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/integrations/search"
+	"github.com/nugget/thane-ai-agent/internal/model/router"
 )
 
 func TestFindConfig_Explicit(t *testing.T) {
@@ -242,7 +246,7 @@ func TestAgentConfig_DefaultOrchestratorTools(t *testing.T) {
 		t.Fatal("expected delegation_required to be true")
 	}
 
-	want := []string{"thane_now", "thane_assign", "recall_fact", "remember_fact", "contact_save", "contact_lookup", "contact_owner", "session_working_memory", "session_close", "archive_search"}
+	want := []string{"thane_now", "thane_fanout", "thane_assign", "recall_fact", "remember_fact", "contact_save", "contact_lookup", "contact_owner", "session_working_memory", "session_close", "archive_search"}
 	if len(cfg.Agent.OrchestratorTools) != len(want) {
 		t.Fatalf("orchestrator_tools length = %d, want %d; got %v", len(cfg.Agent.OrchestratorTools), len(want), cfg.Agent.OrchestratorTools)
 	}
@@ -286,6 +290,354 @@ func TestAgentConfig_NoDefaultsWhenDisabled(t *testing.T) {
 	}
 }
 
+func TestAgentConfig_ConfidenceGateDefaults(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	os.WriteFile(path, []byte("agent:\n  confidence_gate:\n    enabled: true\n    notify_recipient: nugget\n"), 0600)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	g := cfg.Agent.ConfidenceGate
+	if g.Threshold != 0.7 {
+		t.Errorf("threshold = %v, want 0.7", g.Threshold)
+	}
+	if !slices.Contains(g.MutatingTools, "ha_call_service") {
+		t.Errorf("mutating_tools = %v, want default set including ha_call_service", g.MutatingTools)
+	}
+	if !slices.Contains(g.AutonomousSources, "scheduler") || slices.Contains(g.AutonomousSources, "signal") {
+		t.Errorf("autonomous_sources = %v, want autonomous defaults only", g.AutonomousSources)
+	}
+	// Commands published over MQTT run without a human in the loop.
+	if !slices.Contains(g.AutonomousSources, "mqtt_command") || !slices.Contains(g.AutonomousSources, "unifi") {
+		t.Errorf("autonomous_sources = %v, want mqtt_command and unifi gated", g.AutonomousSources)
+	}
+}
+
+func TestValidate_ConfidenceGate(t *testing.T) {
+	tests := []struct {
+		name    string
+		gate    ConfidenceGateConfig
+		wantErr string
+	}{
+		{"disabled", ConfidenceGateConfig{}, ""},
+		{"valid", ConfidenceGateConfig{Enabled: true, Threshold: 0.8, NotifyRecipient: "nugget"}, ""},
+		{"threshold_above_one", ConfidenceGateConfig{Enabled: true, Threshold: 1.5, NotifyRecipient: "nugget"}, "threshold"},
+		{"missing_recipient", ConfidenceGateConfig{Enabled: true, Threshold: 0.7}, "notify_recipient"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Agent.ConfidenceGate = tt.gate
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected validation error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_ContextBudget(t *testing.T) {
+	tests := []struct {
+		name    string
+		budget  ContextBudgetConfig
+		wantErr string
+	}{
+		{"disabled", ContextBudgetConfig{}, ""},
+		{"valid", ContextBudgetConfig{Fraction: 0.8, TrimOrder: []string{"related_context", "dynamic_context", "history"}}, ""},
+		{"fraction_above_one", ContextBudgetConfig{Fraction: 1.2}, "fraction"},
+		{"unknown_entry", ContextBudgetConfig{Fraction: 0.8, TrimOrder: []string{"persona"}}, `"persona" is not one of`},
+		{"duplicate_entry", ContextBudgetConfig{Fraction: 0.8, TrimOrder: []string{"history", "history"}}, "more than once"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Agent.ContextBudget = tt.budget
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected validation error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSensorWebhookDefaults(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	os.WriteFile(path, []byte("sensor_webhook:\n  enabled: true\n  secret: hunter2\n  sensors:\n    mailbox: {}\n"), 0600)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	w := cfg.SensorWebhook
+	if w.Path != "/v1/webhooks/sensors" {
+		t.Errorf("path = %q, want /v1/webhooks/sensors", w.Path)
+	}
+	got := w.Sensors["mailbox"]
+	if got.EntityID != "sensor.webhook_mailbox" || got.Field != "state" {
+		t.Errorf("mailbox sensor = %+v, want default entity_id and field", got)
+	}
+}
+
+func TestValidate_SensorWebhook(t *testing.T) {
+	sensors := map[string]WebhookSensorConfig{"mailbox": {EntityID: "binary_sensor.mailbox", Field: "state"}}
+	tests := []struct {
+		name    string
+		hook    SensorWebhookConfig
+		wantErr string
+	}{
+		{"disabled", SensorWebhookConfig{}, ""},
+		{"valid", SensorWebhookConfig{Enabled: true, Path: "/hooks", Secret: "s", Sensors: sensors}, ""},
+		{"missing_secret", SensorWebhookConfig{Enabled: true, Path: "/hooks", Sensors: sensors}, "secret"},
+		{"relative_path", SensorWebhookConfig{Enabled: true, Path: "hooks", Secret: "s", Sensors: sensors}, "path"},
+		{"no_sensors", SensorWebhookConfig{Enabled: true, Path: "/hooks", Secret: "s"}, "at least one sensor"},
+		{"bad_entity", SensorWebhookConfig{Enabled: true, Path: "/hooks", Secret: "s",
+			Sensors: map[string]WebhookSensorConfig{"mailbox": {EntityID: "mailbox", Field: "state"}}}, "entity_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.SensorWebhook = tt.hook
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected validation error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_MQTTTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := filepath.Join(dir, "ca.pem")
+	cert := filepath.Join(dir, "client.pem")
+	key := filepath.Join(dir, "client.key")
+	for _, f := range []string{ca, cert, key} {
+		if err := os.WriteFile(f, []byte("pem"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	missing := filepath.Join(dir, "missing.pem")
+
+	tests := []struct {
+		name    string
+		broker  string
+		tls     MQTTTLSConfig
+		wantErr string
+	}{
+		{"unset", "mqtts://broker:8883", MQTTTLSConfig{}, ""},
+		{"mutual", "mqtts://broker:8883", MQTTTLSConfig{CAFile: ca, CertFile: cert, KeyFile: key}, ""},
+		{"wss", "wss://broker/mqtt", MQTTTLSConfig{CAFile: ca}, ""},
+		{"plaintext_scheme", "mqtt://broker:1883", MQTTTLSConfig{CAFile: ca}, "not TLS"},
+		// Every scheme the client dials over TLS takes TLS settings.
+		{"tls_scheme", "tls://broker:8883", MQTTTLSConfig{CAFile: ca}, ""},
+		{"tcps_scheme", "tcps://broker:8883", MQTTTLSConfig{CAFile: ca}, ""},
+		{"mqtt_ssl_scheme", "mqtt+ssl://broker:8883", MQTTTLSConfig{CAFile: ca}, ""},
+		{"tcp_scheme", "tcp://broker:1883", MQTTTLSConfig{}, ""},
+		{"tcp_scheme_with_tls", "tcp://broker:1883", MQTTTLSConfig{CAFile: ca}, "not TLS"},
+		{"unknown_scheme", "http://broker:1883", MQTTTLSConfig{}, "invalid"},
+		{"cert_without_key", "mqtts://broker:8883", MQTTTLSConfig{CertFile: cert}, "set together"},
+		{"missing_ca", "mqtts://broker:8883", MQTTTLSConfig{CAFile: missing}, "ca_file"},
+		{"missing_key", "mqtts://broker:8883", MQTTTLSConfig{CertFile: cert, KeyFile: missing}, "key_file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.MQTT.Broker = tt.broker
+			cfg.MQTT.DeviceName = "thane"
+			cfg.MQTT.TLS = tt.tls
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected validation error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_HomeAssistantInstances(t *testing.T) {
+	cabin := map[string]HomeAssistantInstanceConfig{"cabin": {URL: "http://cabin:8123", Token: "t"}}
+	tests := []struct {
+		name      string
+		primary   bool
+		instances map[string]HomeAssistantInstanceConfig
+		track     []string
+		wantErr   string
+	}{
+		{"none", false, nil, nil, ""},
+		{"valid", true, cabin, []string{"person.alice", "cabin:person.alice", "home:person.bob"}, ""},
+		{"no_primary", false, cabin, nil, "primary"},
+		{"bad_name", true, map[string]HomeAssistantInstanceConfig{"Cabin": {URL: "u", Token: "t"}}, nil, "must match"},
+		{"primary_collision", true, map[string]HomeAssistantInstanceConfig{"home": {URL: "u", Token: "t"}}, nil, "collides"},
+		{"missing_token", true, map[string]HomeAssistantInstanceConfig{"cabin": {URL: "u"}}, nil, "token"},
+		{"unknown_track_instance", true, cabin, []string{"barn:person.alice"}, "unknown homeassistant instance"},
+		{"qualified_non_person", true, cabin, []string{"cabin:light.kitchen"}, "must start with"},
+		{"primary_qualified_duplicate", true, cabin, []string{"person.bob", "home:person.bob"}, "already tracked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			if tt.primary {
+				cfg.HomeAssistant.URL = "http://home:8123"
+				cfg.HomeAssistant.Token = "t"
+			}
+			cfg.HomeAssistant.Instances = tt.instances
+			cfg.Person.Track = tt.track
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected validation error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_Search(t *testing.T) {
+	tests := []struct {
+		name    string
+		search  SearchConfig
+		wantErr string
+	}{
+		{"unset", SearchConfig{}, ""},
+		{"duckduckgo_default", SearchConfig{Default: "duckduckgo", DuckDuckGo: search.DuckDuckGoConfig{Enabled: true}}, ""},
+		{"google", SearchConfig{Default: "google", Google: search.GoogleConfig{APIKey: "k", CX: "cx"}}, ""},
+		{"unknown_default", SearchConfig{Default: "bing"}, "search.default"},
+		{"google_missing_cx", SearchConfig{Google: search.GoogleConfig{APIKey: "k"}}, "cx"},
+		{"cache", SearchConfig{Cache: SearchCacheConfig{TTL: "5m", MaxEntries: 64}}, ""},
+		{"cache_disabled", SearchConfig{Cache: SearchCacheConfig{TTL: "0"}}, ""},
+		{"cache_bad_ttl", SearchConfig{Cache: SearchCacheConfig{TTL: "soon"}}, "search.cache.ttl"},
+		{"cache_negative_entries", SearchConfig{Cache: SearchCacheConfig{MaxEntries: -1}}, "max_entries"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Search = tt.search
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected validation error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_MCPResourcesEmptyURI(t *testing.T) {
+	cfg := Default()
+	cfg.MCP.Servers = []MCPServerConfig{{
+		Name:      "docs",
+		Transport: "stdio",
+		Command:   "/bin/true",
+		Resources: []string{"file:///README.md", " "},
+	}}
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "resources[1]") {
+		t.Fatalf("Validate() = %v, want error naming resources[1]", err)
+	}
+}
+
+func TestValidate_MCPOAuth2(t *testing.T) {
+	tests := []struct {
+		name      string
+		transport string
+		oauth     MCPOAuth2Config
+		wantErr   string
+	}{
+		{"valid", "http", MCPOAuth2Config{TokenURL: "https://auth.example/token", ClientID: "thane"}, ""},
+		{"sse transport", "sse", MCPOAuth2Config{TokenURL: "https://auth.example/token", ClientID: "thane"}, "requires the http transport"},
+		{"missing token url", "http", MCPOAuth2Config{ClientID: "thane"}, "oauth2.token_url"},
+		{"missing client id", "http", MCPOAuth2Config{TokenURL: "https://auth.example/token"}, "oauth2.client_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			oauth := tt.oauth
+			cfg.MCP.Servers = []MCPServerConfig{{
+				Name:      "gateway",
+				Transport: tt.transport,
+				URL:       "https://mcp.example/mcp",
+				OAuth2:    &oauth,
+			}}
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_PersonTrackNormalizesPrimaryQualifiedIDs(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	os.WriteFile(path, []byte(`homeassistant:
+  url: http://home:8123
+  token: t
+  instances:
+    cabin:
+      url: http://cabin:8123
+      token: t
+person:
+  track: ["home:person.alice", "cabin:person.bob"]
+  devices:
+    home:person.alice:
+      - mac: "AA:BB:CC:DD:EE:FF"
+`), 0600)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if want := []string{"person.alice", "cabin:person.bob"}; !slices.Equal(cfg.Person.Track, want) {
+		t.Errorf("person.track = %v, want %v", cfg.Person.Track, want)
+	}
+	if _, ok := cfg.Person.Devices["person.alice"]; !ok || len(cfg.Person.Devices) != 1 {
+		t.Errorf("person.devices keys = %v, want person.alice", cfg.Person.Devices)
+	}
+}
+
 func TestValidate_PersonDevicesUntrackedEntity(t *testing.T) {
 	cfg := Default()
 	cfg.Person.Track = []string{"person.alice"}
@@ -418,6 +770,70 @@ func TestApplyDefaults_EmbeddingsBaseURLUsesImplicitOllamaServer(t *testing.T) {
 	}
 }
 
+func TestApplyDefaults_ModelRetry(t *testing.T) {
+	cfg := Default()
+	if r := cfg.Models.Retry.MaxRetries; r == nil || *r != 3 {
+		t.Fatalf("models.retry.max_retries = %v, want 3", r)
+	}
+	if cfg.Models.Retry.InitialDelay != 2*time.Second || cfg.Models.Retry.MaxDelay != 30*time.Second {
+		t.Fatalf("models.retry delays = %s/%s, want 2s/30s", cfg.Models.Retry.InitialDelay, cfg.Models.Retry.MaxDelay)
+	}
+
+	zero := 0
+	cfg = &Config{}
+	cfg.Models.Retry.MaxRetries = &zero
+	cfg.applyDefaults()
+	if *cfg.Models.Retry.MaxRetries != 0 {
+		t.Fatalf("explicit max_retries 0 overwritten with %d", *cfg.Models.Retry.MaxRetries)
+	}
+}
+
+func TestValidate_ModelRetry(t *testing.T) {
+	neg := -1
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+		want   string
+	}{
+		{"negative retries", func(c *Config) { c.Models.Retry.MaxRetries = &neg }, "max_retries"},
+		{"negative delay", func(c *Config) { c.Models.Retry.InitialDelay = -time.Second }, "delays"},
+		{"max below initial", func(c *Config) { c.Models.Retry.MaxDelay = time.Second }, "max_delay"},
+		{"negative model timeout", func(c *Config) {
+			c.Models.Available = []ModelConfig{{Name: "qwen3:4b", Timeout: -time.Second}}
+		}, "timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.mutate(cfg)
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Validate() = %v, want error mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyDefaults_OpenAIResourceURL(t *testing.T) {
+	cfg := &Config{
+		Models: ModelsConfig{
+			Resources: map[string]ModelServerConfig{
+				"openai": {Provider: "openai", APIKey: "sk-test"},
+				"groq":   {Provider: "openai", URL: "https://api.groq.com/openai/v1"},
+			},
+		},
+	}
+
+	cfg.applyDefaults()
+
+	if got := cfg.Models.Resources["openai"].URL; got != "https://api.openai.com/v1" {
+		t.Fatalf("models.resources.openai.url = %q, want OpenAI default", got)
+	}
+	if got := cfg.Models.Resources["groq"].URL; got != "https://api.groq.com/openai/v1" {
+		t.Fatalf("models.resources.groq.url = %q, want explicit URL preserved", got)
+	}
+}
+
 func TestValidate_ModelResourceIdleTTLNegative(t *testing.T) {
 	cfg := Default()
 	cfg.Models.Resources = map[string]ModelServerConfig{
@@ -437,6 +853,185 @@ func TestValidate_ModelResourceIdleTTLNegative(t *testing.T) {
 	}
 }
 
+func TestValidate_ModelBudget(t *testing.T) {
+	margin := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name    string
+		budget  ModelBudgetConfig
+		wantErr string
+	}{
+		{"unset", ModelBudgetConfig{}, ""},
+		{"valid", ModelBudgetConfig{DailyUSD: 5, MonthlyUSD: 100, Margin: margin(0.2)}, ""},
+		{"negative_daily", ModelBudgetConfig{DailyUSD: -1}, "models.budget.daily_usd"},
+		{"negative_monthly", ModelBudgetConfig{MonthlyUSD: -1}, "models.budget.monthly_usd"},
+		{"margin_above_one", ModelBudgetConfig{DailyUSD: 5, Margin: margin(1.5)}, "models.budget.margin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Models.Budget = tt.budget
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected validation error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_UsageAlerts(t *testing.T) {
+	tests := []struct {
+		name    string
+		alerts  UsageAlertsConfig
+		wantErr string
+	}{
+		{"unset", UsageAlertsConfig{}, ""},
+		{"valid", UsageAlertsConfig{DailyUSD: 5, MonthlyUSD: 100, Thresholds: []float64{75, 100}, Interval: "10m"}, ""},
+		{"negative_daily", UsageAlertsConfig{DailyUSD: -1}, "usage_alerts.daily_usd"},
+		{"negative_monthly", UsageAlertsConfig{MonthlyUSD: -1}, "usage_alerts.monthly_usd"},
+		{"zero_threshold", UsageAlertsConfig{DailyUSD: 5, Thresholds: []float64{0, 100}}, "usage_alerts.thresholds"},
+		{"bad_interval", UsageAlertsConfig{DailyUSD: 5, Interval: "often"}, "usage_alerts.interval"},
+		{"interval_too_short", UsageAlertsConfig{DailyUSD: 5, Interval: "10s"}, "at least 1m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.UsageAlerts = tt.alerts
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected validation error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUsageAlertsDefaults(t *testing.T) {
+	cfg := Default()
+	if got := cfg.UsageAlerts.Thresholds; len(got) != 3 || got[0] != 50 || got[1] != 80 || got[2] != 100 {
+		t.Errorf("usage_alerts.thresholds default = %v, want [50 80 100]", got)
+	}
+	if got := cfg.UsageAlerts.ParsedInterval(); got != 5*time.Minute {
+		t.Errorf("usage_alerts interval default = %v, want 5m", got)
+	}
+	if cfg.UsageAlerts.Enabled() {
+		t.Error("usage alerts enabled with no budget set")
+	}
+}
+
+func TestCheckpointsConfig(t *testing.T) {
+	cfg := Default()
+	if cfg.Checkpoints.Incremental {
+		t.Error("checkpoints.incremental defaults on, want opt-in")
+	}
+	if cfg.Checkpoints.FullEvery != 10 {
+		t.Errorf("checkpoints.full_every default = %d, want 10", cfg.Checkpoints.FullEvery)
+	}
+	cfg.Checkpoints.FullEvery = -1
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "checkpoints.full_every") {
+		t.Errorf("Validate() = %v, want checkpoints.full_every error", err)
+	}
+}
+
+func TestDatabaseConfig(t *testing.T) {
+	cfg := Default()
+	if cfg.Database.BusyTimeout != 5*time.Second {
+		t.Errorf("database.busy_timeout default = %v, want 5s", cfg.Database.BusyTimeout)
+	}
+	if cfg.Database.WALCheckpointInterval != 10*time.Minute {
+		t.Errorf("database.wal_checkpoint_interval default = %v, want 10m", cfg.Database.WALCheckpointInterval)
+	}
+	cfg.Database.BusyTimeout = -time.Second
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "database.busy_timeout") {
+		t.Errorf("Validate() = %v, want database.busy_timeout error", err)
+	}
+}
+
+func TestValidate_FallbackChains(t *testing.T) {
+	tests := []struct {
+		name    string
+		chains  map[string][]string
+		wantErr string
+	}{
+		{"valid", map[string][]string{"opus": {"sonnet", "qwen3:32b"}}, ""},
+		{"empty_primary", map[string][]string{" ": {"sonnet"}}, "empty model name"},
+		{"empty_hop", map[string][]string{"opus": {"sonnet", ""}}, "models.fallback_chains.opus[1]"},
+		{"self_reference", map[string][]string{"opus": {"opus"}}, "must not name the primary model"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Models.FallbackChains = tt.chains
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected validation error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModelBudgetMarginDefault(t *testing.T) {
+	cfg := Default()
+	if cfg.Models.Budget.Margin == nil || *cfg.Models.Budget.Margin != 0.1 {
+		t.Errorf("models.budget.margin default = %v, want 0.1", cfg.Models.Budget.Margin)
+	}
+}
+
+func TestValidate_PricingNegative(t *testing.T) {
+	cfg := Default()
+	cfg.Pricing["claude-opus-4-8"] = PricingEntry{InputPerMillion: 5, OutputPerMillion: 25, CacheReadPerMillion: -0.5}
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "pricing.claude-opus-4-8") {
+		t.Errorf("Validate() error = %v, want pricing.claude-opus-4-8", err)
+	}
+}
+
+func TestValidate_ArchiveRetentionNegative(t *testing.T) {
+	for _, tt := range []struct {
+		field string
+		set   func(*ArchiveConfig)
+	}{
+		{"archive.retention_days", func(a *ArchiveConfig) { a.RetentionDays = -1 }},
+		{"archive.max_messages", func(a *ArchiveConfig) { a.MaxMessages = -1 }},
+	} {
+		cfg := Default()
+		tt.set(&cfg.Archive)
+		err := cfg.Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.field) {
+			t.Errorf("Validate() error = %v, want %s", err, tt.field)
+		}
+	}
+}
+
+func TestValidate_ArchiveSemanticSearchNeedsEmbeddings(t *testing.T) {
+	cfg := Default()
+	cfg.Archive.SemanticSearch = true
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "embeddings.enabled") {
+		t.Fatalf("Validate() error = %v, want embeddings.enabled requirement", err)
+	}
+
+	cfg.Embeddings.Enabled = true
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() with embeddings enabled: %v", err)
+	}
+}
+
 func TestValidate_PersonDevicesValid(t *testing.T) {
 	cfg := Default()
 	cfg.Person.Track = []string{"person.alice"}
@@ -533,6 +1128,17 @@ func TestValidate_SignalRoutingQualityFloorNonNumeric(t *testing.T) {
 	}
 }
 
+func TestSignalRoutingConfig_MaxLatencyHint(t *testing.T) {
+	profile := SignalRoutingConfig{QualityFloor: "6", MaxLatencyMs: 20000}.LoopProfile()
+	if got := profile.RoutingFactors()[router.HintMaxLatencyMs]; got != "20000" {
+		t.Errorf("max_latency_ms hint = %q, want 20000", got)
+	}
+	profile = SignalRoutingConfig{QualityFloor: "6"}.LoopProfile()
+	if _, ok := profile.RoutingFactors()[router.HintMaxLatencyMs]; ok {
+		t.Error("max_latency_ms hint set with no budget configured")
+	}
+}
+
 func TestValidate_SignalEnabledMissingCommand(t *testing.T) {
 	cfg := Default()
 	cfg.Signal = SignalConfig{
@@ -687,6 +1293,53 @@ func TestValidate_ChannelTagsUndefinedTag(t *testing.T) {
 	}
 }
 
+func TestValidate_CapabilityTagNameSyntax(t *testing.T) {
+	tests := []struct {
+		name    string
+		capTags map[string]CapabilityTagConfig
+		chTags  map[string][]string
+		wantErr string
+	}{
+		{
+			name:    "valid custom tag",
+			capTags: map[string]CapabilityTagConfig{"garden_ops": {Description: "Garden"}},
+			chTags:  map[string][]string{"signal": {"garden_ops"}},
+		},
+		{
+			name:    "uppercase capability tag",
+			capTags: map[string]CapabilityTagConfig{"Garden": {Description: "Garden"}},
+			wantErr: `capability_tags: tag name "Garden" is invalid`,
+		},
+		{
+			name:    "whitespace capability tag",
+			capTags: map[string]CapabilityTagConfig{"garden ops": {Description: "Garden"}},
+			wantErr: `tag name "garden ops" is invalid`,
+		},
+		{
+			name:    "padded channel tag",
+			chTags:  map[string][]string{"signal": {" ha"}},
+			wantErr: `channel_tags.signal: tag name " ha" is invalid`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.CapabilityTags = tt.capTags
+			cfg.ChannelTags = tt.chTags
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected validation error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidate_ChannelTagsValid(t *testing.T) {
 	cfg := Default()
 	cfg.CapabilityTags = map[string]CapabilityTagConfig{
@@ -739,6 +1392,74 @@ func TestApplyDefaults_SignalRateLimit(t *testing.T) {
 	}
 }
 
+func TestApplyDefaults_ToolRateLimits(t *testing.T) {
+	cfg := Default()
+	if got := cfg.Agent.ToolRateLimits["web_fetch"]; got.Calls != 30 || got.Per != time.Minute {
+		t.Errorf("web_fetch default = %+v, want 30 per minute", got)
+	}
+
+	// Explicit entries win, including calls: 0 to lift a default cap.
+	cfg = &Config{Agent: AgentConfig{ToolRateLimits: map[string]ToolRateLimitConfig{
+		"exec":      {Calls: 0},
+		"web_fetch": {Calls: 5, Per: time.Hour},
+	}}}
+	cfg.applyDefaults()
+	if got := cfg.Agent.ToolRateLimits["exec"]; got.Calls != 0 {
+		t.Errorf("exec = %+v, want the explicit zero kept", got)
+	}
+	if got := cfg.Agent.ToolRateLimits["web_fetch"]; got.Calls != 5 || got.Per != time.Hour {
+		t.Errorf("web_fetch = %+v, want 5 per hour", got)
+	}
+	if got := cfg.Agent.ToolRateLimits["web_search"]; got.Calls != 20 {
+		t.Errorf("web_search = %+v, want default 20", got)
+	}
+}
+
+func TestValidate_ToolRateLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   ToolRateLimitConfig
+		wantErr string
+	}{
+		{"valid", ToolRateLimitConfig{Calls: 10, Per: time.Minute}, ""},
+		{"disabled", ToolRateLimitConfig{}, ""},
+		{"negative calls", ToolRateLimitConfig{Calls: -1, Per: time.Minute}, "must not be negative"},
+		{"missing window", ToolRateLimitConfig{Calls: 10}, "per must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Agent.ToolRateLimits["my_tool"] = tt.limit
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestToolResultLimits(t *testing.T) {
+	cfg := Default()
+	if cfg.Agent.MaxToolResultBytes != 64*1024 {
+		t.Errorf("agent.max_tool_result_bytes default = %d, want 65536", cfg.Agent.MaxToolResultBytes)
+	}
+
+	cfg.Agent.ToolResultLimits = map[string]int{"ha_list_entities": 0}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	cfg.Agent.ToolResultLimits["web_fetch"] = -1
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "agent.tool_result_limits.web_fetch") {
+		t.Errorf("Validate() = %v, want agent.tool_result_limits.web_fetch error", err)
+	}
+}
+
 func TestApplyDefaults_Logging(t *testing.T) {
 	cfg := Default()
 
@@ -895,6 +1616,16 @@ func TestLoggingConfig_StdoutAndDatasetDefaults(t *testing.T) {
 	}
 }
 
+func TestSignalConfig_TypingIndicatorsEnabled(t *testing.T) {
+	if !(SignalConfig{}).TypingIndicatorsEnabled() {
+		t.Error("TypingIndicatorsEnabled() = false, want true by default")
+	}
+	off := false
+	if (SignalConfig{TypingIndicators: &off}).TypingIndicatorsEnabled() {
+		t.Error("TypingIndicatorsEnabled() = true, want false when disabled")
+	}
+}
+
 func TestValidate_LoggingInvalidStdoutLevel(t *testing.T) {
 	cfg := Default()
 	cfg.Logging.Stdout.Level = "loud"
@@ -1635,3 +2366,21 @@ homeassistant:
 		t.Errorf("error %q should name the retired block and the replacement", err)
 	}
 }
+
+func TestDiff(t *testing.T) {
+	old := Default()
+	if got := Diff(old, Default()); len(got) != 0 {
+		t.Fatalf("Diff of identical configs = %v, want none", got)
+	}
+
+	next := Default()
+	next.Listen.Port = old.Listen.Port + 1
+	next.Logging.Stdout.Level = "debug"
+	next.Pricing = map[string]PricingEntry{"m": {InputPerMillion: 1}}
+	next.Models.Available = append(next.Models.Available, ModelConfig{Name: "extra"})
+
+	want := []string{"listen.port", "logging.stdout.level", "models.available", "pricing"}
+	if got := Diff(old, next); !slices.Equal(got, want) {
+		t.Errorf("Diff = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Diff reports which settings differ between two loaded configs, as
+// dotted YAML key paths (e.g. "listen.port", "logging.stdout.level").
+// Nested sections are compared field by field; maps, slices, and
+// pointers are compared as a whole and reported at their own key, so a
+// change anywhere under "pricing" is reported as "pricing". The result
+// is sorted and empty when the configs are equivalent.
+//
+// Diff is used by the serve command's SIGHUP reload to decide which
+// changes it can apply in place and which need a restart.
+func Diff(old, next *Config) []string {
+	if old == nil || next == nil {
+		return nil
+	}
+	var changed []string
+	diffStruct(reflect.ValueOf(*old), reflect.ValueOf(*next), "", &changed)
+	sort.Strings(changed)
+	return changed
+}
+
+func diffStruct(a, b reflect.Value, prefix string, changed *[]string) {
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		key := prefix + name
+
+		av, bv := a.Field(i), b.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			diffStruct(av, bv, key+".", changed)
+			continue
+		}
+		if !reflect.DeepEqual(av.Interface(), bv.Interface()) {
+			*changed = append(*changed, key)
+		}
+	}
+}
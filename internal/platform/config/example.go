@@ -33,6 +33,8 @@ func ExampleConfig() *Config {
 	archiveDays := 90
 	sessionIdle := 30
 	stdoutEnabled := true
+	signalTyping := true
+	emailIdle := true
 	eventsEnabled := true
 	requestsEnabled := true
 	accessEnabled := false
@@ -100,8 +102,22 @@ func ExampleConfig() *Config {
 					Quality:       9,
 					CostTier:      0,
 					MinComplexity: "moderate",
+					Timeout:       10 * time.Minute,
 				},
 			},
+			Budget: ModelBudgetConfig{
+				DailyUSD:   5,
+				MonthlyUSD: 100,
+				Margin:     floatPtr(0.1),
+			},
+			FallbackChains: map[string][]string{
+				"qwen2.5:72b": {"qwen3:4b"},
+			},
+			Retry: ModelRetryConfig{
+				MaxRetries:   intPtr(3),
+				InitialDelay: 2 * time.Second,
+				MaxDelay:     30 * time.Second,
+			},
 		},
 
 		DataDir:    "./db",
@@ -247,10 +263,12 @@ func ExampleConfig() *Config {
 			RateLimitPerMinute: 10,
 			SessionIdleMinutes: 30,
 			HandleTimeout:      10 * time.Minute,
+			TypingIndicators:   &signalTyping,
 			Routing: SignalRoutingConfig{
 				QualityFloor:     "6",
 				Mission:          "conversation",
 				DelegationGating: "disabled",
+				MaxLatencyMs:     20000,
 			},
 		},
 
@@ -341,6 +359,8 @@ func ExampleConfig() *Config {
 		},
 
 		Email: email.Config{
+			Idle:            &emailIdle,
+			MaxAttachmentMB: 25,
 			Accounts: []email.AccountConfig{
 				{
 					Name: "primary",
@@ -376,6 +396,18 @@ func ExampleConfig() *Config {
 			TimeoutSeconds: 30,
 		},
 
+		ConversationTitles: ConversationTitlesConfig{
+			Enabled:            false,
+			Model:              "",
+			CheckEveryTurns:    4,
+			MinIntervalSeconds: 300,
+			TimeoutSeconds:     30,
+		},
+
+		Prompts: PromptsConfig{
+			OverrideDir: "./prompts",
+		},
+
 		Episodic: EpisodicConfig{
 			DailyDir:      "~/Thane/generated/daily",
 			LookbackDays:  2,
@@ -390,6 +422,17 @@ func ExampleConfig() *Config {
 			Brave: search.BraveConfig{
 				APIKey: "your-brave-api-key",
 			},
+			DuckDuckGo: search.DuckDuckGoConfig{
+				Enabled: false,
+			},
+			Google: search.GoogleConfig{
+				APIKey: "your-google-api-key",
+				CX:     "your-search-engine-id",
+			},
+			Cache: SearchCacheConfig{
+				TTL:        "10m",
+				MaxEntries: 256,
+			},
 		},
 
 		Media: MediaConfig{
@@ -437,6 +480,10 @@ func ExampleConfig() *Config {
 
 		Agent: AgentConfig{
 			DelegationRequired: false,
+			ContextBudget: ContextBudgetConfig{
+				Fraction:  0.85,
+				TrimOrder: []string{"dynamic_context", "history"},
+			},
 		},
 
 		Delegate: DelegateConfig{
@@ -489,21 +536,43 @@ func ExampleConfig() *Config {
 
 		Pricing: map[string]PricingEntry{
 			"claude-opus-4-8": {
-				InputPerMillion:  5.0,
-				OutputPerMillion: 25.0,
+				InputPerMillion:        5.0,
+				OutputPerMillion:       25.0,
+				CacheReadPerMillion:    0.5,
+				CacheWritePerMillion:   6.25,
+				CacheWrite1hPerMillion: 10.0,
 			},
 			"claude-sonnet-4-6": {
-				InputPerMillion:  3.0,
-				OutputPerMillion: 15.0,
+				InputPerMillion:        3.0,
+				OutputPerMillion:       15.0,
+				CacheReadPerMillion:    0.3,
+				CacheWritePerMillion:   3.75,
+				CacheWrite1hPerMillion: 6.0,
 			},
 			"claude-haiku-4-5": {
-				InputPerMillion:  1.0,
-				OutputPerMillion: 5.0,
+				InputPerMillion:        1.0,
+				OutputPerMillion:       5.0,
+				CacheReadPerMillion:    0.1,
+				CacheWritePerMillion:   1.25,
+				CacheWrite1hPerMillion: 2.0,
 			},
 		},
 
+		UsageAlerts: UsageAlertsConfig{
+			DailyUSD:   5,
+			MonthlyUSD: 100,
+			Thresholds: []float64{50, 80, 100},
+			Interval:   "5m",
+		},
+
+		Checkpoints: CheckpointsConfig{
+			Incremental: false,
+			FullEvery:   10,
+		},
+
 		Debug: DebugConfig{
-			DemoLoops: false,
+			DemoLoops:      false,
+			PromptEndpoint: false,
 		},
 	}
 }
@@ -512,3 +581,5 @@ func ExampleConfig() *Config {
 // fields (e.g. Metacognitive.Jitter) so values are explicitly set rather
 // than ambiguously zero.
 func floatPtr(v float64) *float64 { return &v }
+
+func intPtr(v int) *int { return &v }
@@ -50,7 +50,10 @@ var optionalKeys = map[string]bool{
 	"forge":           true,
 	"email":           true,
 	"archive":         true,
+	"audit":           true,
+	"database":        true,
 	"extraction":      true,
+	"prompts":         true,
 	"episodic":        true,
 	"agent":           true,
 	"delegate":        true,
@@ -7,29 +7,72 @@ import (
 	"database/sql"
 	"fmt"
 	"sync/atomic"
+	"time"
 )
 
+// DefaultBusyTimeout is how long a connection waits on a locked
+// database before failing with "database is locked", unless
+// [SetBusyTimeout] says otherwise.
+const DefaultBusyTimeout = 5 * time.Second
+
+// busyTimeoutMs is the busy timeout applied by [Open] and
+// [OpenMemory], in milliseconds.
+var busyTimeoutMs atomic.Int64
+
+func init() {
+	busyTimeoutMs.Store(DefaultBusyTimeout.Milliseconds())
+}
+
+// SetBusyTimeout changes the busy timeout for databases opened after
+// the call. Non-positive values restore [DefaultBusyTimeout]. It is
+// meant to be called once at startup, before any store opens.
+func SetBusyTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultBusyTimeout
+	}
+	busyTimeoutMs.Store(d.Milliseconds())
+}
+
+// BusyTimeout returns the busy timeout [Open] currently applies.
+func BusyTimeout() time.Duration {
+	return time.Duration(busyTimeoutMs.Load()) * time.Millisecond
+}
+
+// busyTimeoutPragma is the DSN parameter for the current busy timeout.
+func busyTimeoutPragma() string {
+	return fmt.Sprintf("_pragma=busy_timeout(%d)", busyTimeoutMs.Load())
+}
+
 // Open opens a SQLite database at the given path with standard
-// production settings: WAL journal mode and a 5-second busy timeout. It
-// uses the DriverName wrapper so time.Time values serialize to the
-// canonical SQLiteTimestampLayout. The file: URI scheme is required for
-// the _pragma query parameters to be parsed rather than treated as part
-// of the filename.
+// production settings: WAL journal mode and the configured busy timeout
+// (see [SetBusyTimeout]). It uses the DriverName wrapper so time.Time
+// values serialize to the canonical SQLiteTimestampLayout. The file:
+// URI scheme is required for the _pragma query parameters to be parsed
+// rather than treated as part of the filename. Every store opens its
+// database through here so connection settings stay in one place.
 func Open(path string) (*sql.DB, error) {
-	return sql.Open(DriverName, "file:"+path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	return sql.Open(DriverName, "file:"+path+"?_pragma=journal_mode(WAL)&"+busyTimeoutPragma())
+}
+
+// OpenReadOnly opens the existing SQLite database at path without write
+// access, for commands that only inspect a database and must not
+// create or migrate it. Opening a missing file fails on first use.
+func OpenReadOnly(path string) (*sql.DB, error) {
+	return sql.Open(DriverName, "file:"+path+"?mode=ro&"+busyTimeoutPragma())
 }
 
 // memoryDBSeq generates unique names for in-memory test databases.
 var memoryDBSeq uint64
 
 // OpenMemory opens an isolated shared-cache in-memory SQLite database
-// suitable for tests. Each call gets a unique database name so parallel
+// suitable for tests and for running without a usable database file.
+// Each call gets a unique database name so parallel
 // test packages cannot contaminate each other. MaxOpenConns and
 // MaxIdleConns are set to 1 to prevent the pool from dropping the last
 // connection and silently losing the in-memory state.
 func OpenMemory() (*sql.DB, error) {
 	id := atomic.AddUint64(&memoryDBSeq, 1)
-	dsn := fmt.Sprintf("file:thane_test_%d?mode=memory&cache=shared&_pragma=busy_timeout(5000)", id)
+	dsn := fmt.Sprintf("file:thane_test_%d?mode=memory&cache=shared&%s", id, busyTimeoutPragma())
 	db, err := sql.Open(DriverName, dsn)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,241 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrCorrupt reports that a database file failed SQLite's integrity
+// check or is not a database at all.
+var ErrCorrupt = errors.New("database is corrupt")
+
+// maxIntegrityProblems caps how many quick_check findings are quoted in
+// the returned error; a badly damaged file can report thousands.
+const maxIntegrityProblems = 5
+
+// CheckIntegrity runs PRAGMA quick_check against db. quick_check finds
+// the same page- and record-level damage as integrity_check without
+// cross-checking every index against its table, so it stays fast on a
+// large database at startup. It returns an error wrapping [ErrCorrupt]
+// when SQLite reports damage or cannot read the file as a database,
+// and the underlying error unchanged for anything else (a locked
+// database is not a corrupt one).
+func CheckIntegrity(db *sql.DB) error {
+	rows, err := db.Query("PRAGMA quick_check")
+	if err != nil {
+		return classifyOpenError(err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return classifyOpenError(err)
+		}
+		if line == "ok" {
+			continue
+		}
+		if len(problems) < maxIntegrityProblems {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return classifyOpenError(err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrCorrupt, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// classifyOpenError wraps the SQLite errors that mean the file itself
+// is damaged (SQLITE_CORRUPT, SQLITE_NOTADB) in [ErrCorrupt].
+func classifyOpenError(err error) error {
+	msg := err.Error()
+	if strings.Contains(msg, "malformed") || strings.Contains(msg, "not a database") {
+		return fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	return err
+}
+
+// SetAside renames a damaged database file, along with its -wal and
+// -shm sidecars when present, to "<path>.corrupt-<timestamp>" so a
+// fresh database can be created in its place without destroying the
+// evidence. It returns the new path of the main file.
+func SetAside(path string, now time.Time) (string, error) {
+	suffix := ".corrupt-" + now.UTC().Format("20060102T150405Z")
+	dest := path + suffix
+	if err := os.Rename(path, dest); err != nil {
+		return "", fmt.Errorf("rename %s: %w", path, err)
+	}
+	for _, sidecar := range []string{"-wal", "-shm"} {
+		err := os.Rename(path+sidecar, dest+sidecar)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return dest, fmt.Errorf("rename %s: %w", path+sidecar, err)
+		}
+	}
+	return dest, nil
+}
+
+// SalvageResult reports what [Salvage] copied out of a damaged
+// database.
+type SalvageResult struct {
+	Recovered []string // tables copied in full
+	Partial   []string // tables copied with unreadable rows left behind
+	Lost      []string // tables that could not be read
+}
+
+// Salvage copies every ordinary table that can still be read from the
+// damaged database at srcPath into dst, which should be a fresh,
+// empty database. Each table is recreated from its original schema,
+// filled in one statement, and then given its original indexes. When
+// the single copy hits a damaged page, the table is copied again by
+// rowid range instead: ranges that fail are halved until the
+// unreadable rows are isolated and skipped, and the table is reported
+// in Partial. A table whose rowids cannot be read at all (or one
+// declared WITHOUT ROWID) is reported in Lost. Virtual tables
+// (FTS indexes) and their shadow tables are skipped, as are triggers
+// and views: the owning stores recreate those on open and rebuild the
+// indexes from the copied rows.
+//
+// An error means nothing could be read at all, typically because the
+// schema itself is damaged or srcPath is not a database.
+func Salvage(ctx context.Context, dst *sql.DB, srcPath string) (SalvageResult, error) {
+	var res SalvageResult
+
+	// ATTACH is per connection, so pin one for the whole copy.
+	conn, err := dst.Conn(ctx)
+	if err != nil {
+		return res, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS salvage", srcPath); err != nil {
+		return res, classifyOpenError(err)
+	}
+	defer func() { _, _ = conn.ExecContext(context.Background(), "DETACH DATABASE salvage") }()
+
+	type schemaObject struct{ kind, name, table, sql string }
+	rows, err := conn.QueryContext(ctx, `
+		SELECT type, name, tbl_name, sql FROM salvage.sqlite_master
+		WHERE type IN ('table', 'index') AND sql IS NOT NULL AND name NOT LIKE 'sqlite_%'
+		ORDER BY type = 'index', rowid`)
+	if err != nil {
+		return res, classifyOpenError(err)
+	}
+	var objects []schemaObject
+	for rows.Next() {
+		var o schemaObject
+		if err := rows.Scan(&o.kind, &o.name, &o.table, &o.sql); err != nil {
+			rows.Close()
+			return res, classifyOpenError(err)
+		}
+		objects = append(objects, o)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return res, classifyOpenError(err)
+	}
+
+	var virtual []string
+	for _, o := range objects {
+		if o.kind == "table" && strings.HasPrefix(strings.ToUpper(o.sql), "CREATE VIRTUAL TABLE") {
+			virtual = append(virtual, o.name)
+		}
+	}
+	skip := func(table string) bool {
+		for _, v := range virtual {
+			if table == v || strings.HasPrefix(table, v+"_") {
+				return true
+			}
+		}
+		return false
+	}
+
+	copied := make(map[string]bool)
+	for _, o := range objects {
+		if skip(o.table) {
+			continue
+		}
+		if o.kind == "index" {
+			// Indexes are derived data; one that fails to build only
+			// costs speed until its store recreates it.
+			if copied[o.table] {
+				_, _ = conn.ExecContext(ctx, o.sql)
+			}
+			continue
+		}
+		complete, err := salvageTable(ctx, conn, o.name, o.sql)
+		switch {
+		case err != nil:
+			res.Lost = append(res.Lost, o.name)
+			continue
+		case complete:
+			res.Recovered = append(res.Recovered, o.name)
+		default:
+			res.Partial = append(res.Partial, o.name)
+		}
+		copied[o.name] = true
+	}
+	return res, nil
+}
+
+// salvageTable recreates one table in the main database and copies its
+// rows from the attached salvage database, falling back to
+// [salvageRange] when the whole-table copy fails. complete reports
+// whether every row came across. On an error the empty table is
+// dropped again so the owning store creates it normally.
+func salvageTable(ctx context.Context, conn *sql.Conn, name, createSQL string) (complete bool, err error) {
+	if _, err := conn.ExecContext(ctx, createSQL); err != nil {
+		return false, err
+	}
+	quoted := `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	copyAll := fmt.Sprintf("INSERT INTO main.%s SELECT * FROM salvage.%s", quoted, quoted)
+	if _, err := conn.ExecContext(ctx, copyAll); err == nil {
+		return true, nil
+	}
+
+	// A failed INSERT rolls back as a whole, so the table is empty
+	// again. The rowid bounds come from the two edges of the b-tree
+	// and usually survive damage in the middle; they are read one at a
+	// time because SQLite only seeks for a lone min() or max() and
+	// scans the whole table for both together.
+	var lo, hi sql.NullInt64
+	err = conn.QueryRowContext(ctx, fmt.Sprintf("SELECT min(rowid) FROM salvage.%s", quoted)).Scan(&lo)
+	if err == nil {
+		err = conn.QueryRowContext(ctx, fmt.Sprintf("SELECT max(rowid) FROM salvage.%s", quoted)).Scan(&hi)
+	}
+	if err == nil && !lo.Valid {
+		err = fmt.Errorf("no readable rowids in %s", name)
+	}
+	if err != nil {
+		_, _ = conn.ExecContext(ctx, "DROP TABLE main."+quoted)
+		return false, err
+	}
+	copyRange := copyAll + " WHERE rowid BETWEEN ? AND ?"
+	return salvageRange(ctx, conn, copyRange, lo.Int64, hi.Int64), nil
+}
+
+// salvageRange copies the rows with rowids in [lo, hi] using the copy
+// statement. A range that cannot be read is split in half and each
+// half retried, down to single rowids, so only the rows on damaged
+// pages are skipped. It reports whether the whole range was copied.
+func salvageRange(ctx context.Context, conn *sql.Conn, copyRange string, lo, hi int64) bool {
+	if _, err := conn.ExecContext(ctx, copyRange, lo, hi); err == nil {
+		return true
+	}
+	if lo == hi || ctx.Err() != nil {
+		return false
+	}
+	mid := lo + int64((uint64(hi)-uint64(lo))/2) // hi-lo can overflow int64
+	left := salvageRange(ctx, conn, copyRange, lo, mid)
+	right := salvageRange(ctx, conn, copyRange, mid+1, hi)
+	return left && right
+}
@@ -0,0 +1,205 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckIntegrity_Healthy(t *testing.T) {
+	db := openTestDB(t)
+	if err := CheckIntegrity(db); err != nil {
+		t.Fatalf("CheckIntegrity() = %v, want nil", err)
+	}
+}
+
+func TestCheckIntegrity_NotADatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "garbage.db")
+	if err := os.WriteFile(path, []byte(strings.Repeat("this is not sqlite ", 512)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = CheckIntegrity(db)
+	if !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("CheckIntegrity() = %v, want ErrCorrupt", err)
+	}
+}
+
+func TestSetAside(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "thane.db")
+	for _, name := range []string{path, path + "-wal"} {
+		if err := os.WriteFile(name, []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	moved, err := SetAside(path, at)
+	if err != nil {
+		t.Fatalf("SetAside() error = %v", err)
+	}
+	if want := path + ".corrupt-20260304T050607Z"; moved != want {
+		t.Errorf("moved = %q, want %q", moved, want)
+	}
+	for _, name := range []string{moved, moved + "-wal"} {
+		if _, err := os.Stat(name); err != nil {
+			t.Errorf("expected %s to exist: %v", filepath.Base(name), err)
+		}
+	}
+	for _, name := range []string{path, path + "-wal", moved + "-shm"} {
+		if _, err := os.Stat(name); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected %s to be gone, stat err = %v", filepath.Base(name), err)
+		}
+	}
+}
+
+func TestSalvage(t *testing.T) {
+	dir := t.TempDir()
+	srcPath := filepath.Join(dir, "damaged.db")
+	src, err := Open(srcPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE sessions (id TEXT PRIMARY KEY, title TEXT)`,
+		`CREATE INDEX idx_sessions_title ON sessions(title)`,
+		`INSERT INTO sessions VALUES ('s1', 'garage'), ('s2', 'lights')`,
+		`CREATE TABLE usage (id INTEGER PRIMARY KEY AUTOINCREMENT, tokens INTEGER)`,
+		`INSERT INTO usage (tokens) VALUES (10), (20), (30)`,
+		`CREATE VIRTUAL TABLE sessions_fts USING fts5(title)`,
+		`INSERT INTO sessions_fts VALUES ('garage')`,
+	} {
+		if _, err := src.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	src.Close()
+
+	dst, err := Open(filepath.Join(dir, "fresh.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+
+	res, err := Salvage(context.Background(), dst, srcPath)
+	if err != nil {
+		t.Fatalf("Salvage() error = %v", err)
+	}
+	if got := strings.Join(res.Recovered, ","); got != "sessions,usage" {
+		t.Errorf("Recovered = %q, want sessions,usage", got)
+	}
+	if len(res.Lost) != 0 {
+		t.Errorf("Lost = %v, want none", res.Lost)
+	}
+
+	var n int
+	if err := dst.QueryRow(`SELECT COUNT(*) FROM usage`).Scan(&n); err != nil || n != 3 {
+		t.Errorf("usage rows = %d (err %v), want 3", n, err)
+	}
+	if err := dst.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'idx_sessions_title'`).Scan(&n); err != nil || n != 1 {
+		t.Errorf("index copied = %d (err %v), want 1", n, err)
+	}
+	// FTS tables are left for their store to recreate and rebuild.
+	if err := dst.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name LIKE 'sessions_fts%'`).Scan(&n); err != nil || n != 0 {
+		t.Errorf("fts objects = %d (err %v), want 0", n, err)
+	}
+}
+
+func TestSalvage_SkipsDamagedRowRange(t *testing.T) {
+	dir := t.TempDir()
+	srcPath := filepath.Join(dir, "damaged.db")
+	src, err := Open(srcPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const total = 400
+	for _, stmt := range []string{
+		`PRAGMA journal_mode = DELETE`,
+		`CREATE TABLE messages (id INTEGER PRIMARY KEY, body TEXT)`,
+	} {
+		if _, err := src.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	for i := 1; i <= total; i++ {
+		body := fmt.Sprintf("row-%04d-", i) + strings.Repeat("x", 500)
+		if _, err := src.Exec(`INSERT INTO messages VALUES (?, ?)`, i, body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var pageSize int
+	if err := src.QueryRow(`PRAGMA page_size`).Scan(&pageSize); err != nil {
+		t.Fatal(err)
+	}
+	src.Close()
+
+	// Scribble over the leaf page holding a row in the middle of the
+	// table.
+	raw, err := os.ReadFile(srcPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := bytes.Index(raw, []byte("row-0200-"))
+	if at < 0 {
+		t.Fatal("marker row not found in file")
+	}
+	page := at / pageSize * pageSize
+	copy(raw[page:page+pageSize], bytes.Repeat([]byte{0xff}, pageSize))
+	if err := os.WriteFile(srcPath, raw, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	dst, err := Open(filepath.Join(dir, "fresh.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+
+	res, err := Salvage(context.Background(), dst, srcPath)
+	if err != nil {
+		t.Fatalf("Salvage() error = %v", err)
+	}
+	if len(res.Partial) != 1 || res.Partial[0] != "messages" {
+		t.Fatalf("Salvage() = %+v, want messages in Partial", res)
+	}
+
+	var n, first, last int
+	if err := dst.QueryRow(`SELECT COUNT(*), MIN(id), MAX(id) FROM messages`).Scan(&n, &first, &last); err != nil {
+		t.Fatal(err)
+	}
+	if n == 0 || n >= total || first != 1 || last != total {
+		t.Errorf("copied %d rows (ids %d..%d), want all but the damaged page of 1..%d", n, first, last, total)
+	}
+	if err := dst.QueryRow(`SELECT COUNT(*) FROM messages WHERE id = 200`).Scan(&n); err != nil || n != 0 {
+		t.Errorf("damaged row copied = %d (err %v), want 0", n, err)
+	}
+}
+
+func TestSalvage_NotADatabase(t *testing.T) {
+	dir := t.TempDir()
+	srcPath := filepath.Join(dir, "garbage.db")
+	if err := os.WriteFile(srcPath, []byte(strings.Repeat("this is not sqlite ", 512)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	dst, err := Open(filepath.Join(dir, "fresh.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+
+	if _, err := Salvage(context.Background(), dst, srcPath); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("Salvage() = %v, want ErrCorrupt", err)
+	}
+}
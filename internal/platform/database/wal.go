@@ -0,0 +1,193 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultWALCheckpointInterval is how often [WALCheckpointer.Run]
+// checkpoints when given a non-positive interval.
+const DefaultWALCheckpointInterval = 10 * time.Minute
+
+// CheckpointResult is the outcome of one PRAGMA wal_checkpoint call.
+type CheckpointResult struct {
+	// Busy is true when the checkpoint could not finish because another
+	// connection held a lock past the busy timeout. The WAL is then left
+	// in place and the next pass tries again.
+	Busy bool
+	// LogFrames is the number of frames in the WAL when the checkpoint ran.
+	LogFrames int
+	// CheckpointedFrames is how many of them were written back to the
+	// database file.
+	CheckpointedFrames int
+}
+
+// Checkpoint copies the WAL of db back into the database file and
+// truncates the WAL to zero bytes (PRAGMA wal_checkpoint(TRUNCATE)).
+// SQLite's automatic checkpoints never shrink the WAL file, so on a
+// long-running process it only ever grows to its high-water mark.
+func Checkpoint(ctx context.Context, db *sql.DB) (CheckpointResult, error) {
+	var busy int
+	var res CheckpointResult
+	err := db.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)").
+		Scan(&busy, &res.LogFrames, &res.CheckpointedFrames)
+	if err != nil {
+		return CheckpointResult{}, fmt.Errorf("wal checkpoint: %w", err)
+	}
+	res.Busy = busy != 0
+	return res, nil
+}
+
+// WALSize returns the size in bytes of the write-ahead log next to the
+// database file at path, or 0 when there is none.
+func WALSize(path string) (int64, error) {
+	info, err := os.Stat(path + "-wal")
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// WALStats describes one database's write-ahead log for health
+// reporting.
+type WALStats struct {
+	Name           string    `json:"name"`
+	Path           string    `json:"path"`
+	WALBytes       int64     `json:"wal_bytes"`
+	LastCheckpoint time.Time `json:"last_checkpoint,omitzero"`
+	LastError      string    `json:"last_error,omitempty"`
+}
+
+// WALCheckpointer periodically checkpoints and truncates the WAL of
+// every database registered with [WALCheckpointer.Add], and reports
+// their WAL sizes. It is safe for concurrent use.
+type WALCheckpointer struct {
+	logger *slog.Logger
+
+	mu  sync.Mutex
+	dbs []*walEntry
+}
+
+type walEntry struct {
+	name    string
+	path    string
+	db      *sql.DB
+	last    time.Time
+	lastErr error
+}
+
+// NewWALCheckpointer creates a checkpointer with no databases. A nil
+// logger falls back to [slog.Default].
+func NewWALCheckpointer(logger *slog.Logger) *WALCheckpointer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &WALCheckpointer{logger: logger}
+}
+
+// Add registers a file-backed database opened with [Open]. name labels
+// it in logs and stats (e.g. "main", "knowledge"); path is the file the
+// database was opened from, used to measure the WAL. Nil receivers and
+// nil databases are ignored, so callers can register optional stores
+// unconditionally.
+func (c *WALCheckpointer) Add(name, path string, db *sql.DB) {
+	if c == nil || db == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dbs = append(c.dbs, &walEntry{name: name, path: path, db: db})
+}
+
+// Run checkpoints every registered database each interval until ctx is
+// cancelled. A non-positive interval uses
+// [DefaultWALCheckpointInterval].
+func (c *WALCheckpointer) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultWALCheckpointInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.CheckpointAll(ctx)
+		}
+	}
+}
+
+// CheckpointAll runs one checkpoint pass over every registered
+// database. Failures are logged and recorded for [WALCheckpointer.Stats];
+// one database failing does not stop the others.
+func (c *WALCheckpointer) CheckpointAll(ctx context.Context) {
+	c.mu.Lock()
+	entries := append([]*walEntry(nil), c.dbs...)
+	c.mu.Unlock()
+
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+		before, _ := WALSize(e.path)
+		res, err := Checkpoint(ctx, e.db)
+		if err == nil && res.Busy {
+			err = errors.New("checkpoint incomplete: database busy")
+		}
+
+		c.mu.Lock()
+		e.lastErr = err
+		if err == nil {
+			e.last = time.Now()
+		}
+		c.mu.Unlock()
+
+		if err != nil {
+			c.logger.Warn("wal checkpoint failed",
+				"db", e.name, "wal_bytes", before, "error", err)
+			continue
+		}
+		c.logger.Debug("wal checkpointed",
+			"db", e.name, "wal_bytes_before", before, "frames", res.CheckpointedFrames)
+	}
+}
+
+// Stats reports the current WAL size of every registered database,
+// sorted by name, with the outcome of its most recent checkpoint.
+func (c *WALCheckpointer) Stats() []WALStats {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	out := make([]WALStats, 0, len(c.dbs))
+	for _, e := range c.dbs {
+		st := WALStats{Name: e.name, Path: e.path, LastCheckpoint: e.last}
+		if e.lastErr != nil {
+			st.LastError = e.lastErr.Error()
+		}
+		out = append(out, st)
+	}
+	c.mu.Unlock()
+
+	for i := range out {
+		size, err := WALSize(out[i].Path)
+		if err != nil {
+			c.logger.Debug("stat wal failed", "db", out[i].Name, "error", err)
+			continue
+		}
+		out[i].WALBytes = size
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// openWALTestDB opens a file-backed database and writes enough rows to
+// leave a non-empty WAL behind.
+func openWALTestDB(t *testing.T) (string, *WALCheckpointer) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "wal.db")
+	db, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	// Keep SQLite's own auto-checkpoint out of the way.
+	if _, err := db.Exec("PRAGMA wal_autocheckpoint=0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("CREATE TABLE t (v TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 50 {
+		if _, err := db.Exec("INSERT INTO t (v) VALUES (?)", fmt.Sprint("row-", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := NewWALCheckpointer(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	c.Add("test", path, db)
+	return path, c
+}
+
+func TestWALCheckpointer_TruncatesWAL(t *testing.T) {
+	path, c := openWALTestDB(t)
+
+	if size, err := WALSize(path); err != nil || size == 0 {
+		t.Fatalf("WALSize before checkpoint = %d, %v; want a non-empty WAL", size, err)
+	}
+	before := c.Stats()
+	if len(before) != 1 || before[0].WALBytes == 0 || !before[0].LastCheckpoint.IsZero() {
+		t.Fatalf("Stats() before checkpoint = %+v", before)
+	}
+
+	c.CheckpointAll(context.Background())
+
+	after := c.Stats()
+	if len(after) != 1 {
+		t.Fatalf("Stats() = %+v, want one database", after)
+	}
+	st := after[0]
+	if st.WALBytes != 0 {
+		t.Errorf("WALBytes after checkpoint = %d, want 0", st.WALBytes)
+	}
+	if st.LastCheckpoint.IsZero() || st.LastError != "" {
+		t.Errorf("LastCheckpoint = %v, LastError = %q; want a successful checkpoint", st.LastCheckpoint, st.LastError)
+	}
+}
+
+func TestWALSize_NoWAL(t *testing.T) {
+	size, err := WALSize(filepath.Join(t.TempDir(), "absent.db"))
+	if err != nil || size != 0 {
+		t.Errorf("WALSize() = %d, %v; want 0, nil", size, err)
+	}
+}
+
+func TestWALCheckpointer_NilSafe(t *testing.T) {
+	var c *WALCheckpointer
+	c.Add("x", "x.db", nil)
+	if st := c.Stats(); st != nil {
+		t.Errorf("nil Stats() = %v, want nil", st)
+	}
+}
+
+func TestSetBusyTimeout(t *testing.T) {
+	t.Cleanup(func() { SetBusyTimeout(DefaultBusyTimeout) })
+
+	SetBusyTimeout(12 * time.Second)
+	if got := BusyTimeout(); got != 12*time.Second {
+		t.Errorf("BusyTimeout() = %v, want 12s", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "busy.db")
+	db, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var ms int
+	if err := db.QueryRow("PRAGMA busy_timeout").Scan(&ms); err != nil {
+		t.Fatal(err)
+	}
+	if ms != 12000 {
+		t.Errorf("PRAGMA busy_timeout = %d, want 12000", ms)
+	}
+
+	SetBusyTimeout(0)
+	if got := BusyTimeout(); got != DefaultBusyTimeout {
+		t.Errorf("BusyTimeout() after reset = %v, want %v", got, DefaultBusyTimeout)
+	}
+}
@@ -7,6 +7,7 @@ package events
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -33,6 +34,9 @@ const (
 	// KindRequestStart signals the beginning of an agent request.
 	// Data: request_id, conversation_id, channel.
 	KindRequestStart = "request_start"
+	// KindModelSelected signals the model chosen for an agent request.
+	// Data: request_id, conversation_id, model, routed.
+	KindModelSelected = "model_selected"
 	// KindLLMCall signals the start of an LLM API call.
 	// Data: request_id, iter, model.
 	KindLLMCall = "llm_call"
@@ -50,6 +54,10 @@ const (
 	// Data: request_id, model, iterations, total_tokens_in,
 	// total_tokens_out, total_cost_usd, elapsed_ms.
 	KindRequestComplete = "request_complete"
+	// KindCompaction signals that compaction of a conversation was
+	// triggered. Data: request_id, conversation_id, tokens_before,
+	// messages_before.
+	KindCompaction = "compaction"
 
 	// KindMessageReceived signals an incoming Signal message.
 	// Data: sender, conversation_id, message_len.
@@ -128,6 +136,9 @@ const (
 	// KindLoopToolStart signals a tool execution has begun within a
 	// loop iteration. Data: loop_id, loop_name, tool.
 	KindLoopToolStart = "loop_tool_start"
+	// KindLoopToolProgress signals a running tool reported progress
+	// within a loop iteration. Data: loop_id, loop_name, tool, message.
+	KindLoopToolProgress = "loop_tool_progress"
 	// KindLoopToolDone signals a tool execution has completed within a
 	// loop iteration. Data: loop_id, loop_name, tool, error (if failed).
 	KindLoopToolDone = "loop_tool_done"
@@ -167,10 +178,12 @@ type Event struct {
 
 // Bus is a non-blocking broadcast event bus. Subscribers receive events
 // on buffered channels; slow subscribers miss events rather than
-// blocking publishers.
+// blocking publishers, and [Bus.TakeDropped] tells them how many.
 type Bus struct {
-	mu   sync.RWMutex
-	subs map[chan Event]struct{}
+	mu sync.RWMutex
+	// subs maps each subscriber channel to its filter and its count of
+	// events dropped because the channel was full.
+	subs map[chan Event]*subscription
 	// recvToSend maps the receive-only channel returned by Subscribe
 	// back to the bidirectional channel stored in subs. This allows
 	// Unsubscribe to accept <-chan Event (the caller's view) without
@@ -178,10 +191,20 @@ type Bus struct {
 	recvToSend map[<-chan Event]chan Event
 }
 
+// subscription is the bus-side state of one subscriber.
+type subscription struct {
+	// filter selects the events delivered to the subscriber. Nil
+	// delivers every event.
+	filter func(Event) bool
+	// dropped counts events that passed filter but found the channel
+	// full.
+	dropped atomic.Uint64
+}
+
 // New creates a new event bus ready for use.
 func New() *Bus {
 	return &Bus{
-		subs:       make(map[chan Event]struct{}),
+		subs:       make(map[chan Event]*subscription),
 		recvToSend: make(map[<-chan Event]chan Event),
 	}
 }
@@ -195,11 +218,15 @@ func (b *Bus) Publish(e Event) {
 	}
 	b.mu.RLock()
 	defer b.mu.RUnlock()
-	for ch := range b.subs {
+	for ch, sub := range b.subs {
+		if sub.filter != nil && !sub.filter(e) {
+			continue
+		}
 		select {
 		case ch <- e:
 		default:
 			// Subscriber is full — drop the event rather than block.
+			sub.dropped.Add(1)
 		}
 	}
 }
@@ -209,10 +236,19 @@ func (b *Bus) Publish(e Event) {
 // bufSize controls the channel buffer; 64 is a reasonable default for
 // WebSocket consumers.
 func (b *Bus) Subscribe(bufSize int) <-chan Event {
+	return b.SubscribeFiltered(bufSize, nil)
+}
+
+// SubscribeFiltered is like [Bus.Subscribe] but delivers only events
+// for which filter returns true. Events the filter rejects never take
+// buffer space and are not counted by [Bus.TakeDropped]. filter runs
+// on the publisher's goroutine, so it must be fast and must not
+// publish. A nil filter delivers every event.
+func (b *Bus) SubscribeFiltered(bufSize int, filter func(Event) bool) <-chan Event {
 	ch := make(chan Event, bufSize)
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	b.subs[ch] = struct{}{}
+	b.subs[ch] = &subscription{filter: filter}
 	b.recvToSend[ch] = ch
 	return ch
 }
@@ -231,6 +267,25 @@ func (b *Bus) Unsubscribe(ch <-chan Event) {
 	close(sendCh)
 }
 
+// TakeDropped returns how many events were dropped for the
+// subscription since the last call, because its channel was full, and
+// resets the count. Only events the subscription's filter accepted
+// count. Consumers that must not miss events silently (a
+// live stream to a client) check it to mark the gap. Unknown channels
+// report zero.
+func (b *Bus) TakeDropped(ch <-chan Event) uint64 {
+	if b == nil {
+		return 0
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	sendCh, ok := b.recvToSend[ch]
+	if !ok {
+		return 0
+	}
+	return b.subs[sendCh].dropped.Swap(0)
+}
+
 // SubscriberCount returns the number of active subscribers.
 func (b *Bus) SubscriberCount() int {
 	if b == nil {
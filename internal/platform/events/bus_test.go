@@ -97,6 +97,53 @@ func TestDropOnFull(t *testing.T) {
 	}
 }
 
+func TestTakeDropped(t *testing.T) {
+	b := New()
+	slow := b.Subscribe(1)
+	defer b.Unsubscribe(slow)
+	fast := b.Subscribe(8)
+	defer b.Unsubscribe(fast)
+
+	for i := 0; i < 4; i++ {
+		b.Publish(Event{Kind: "tick"})
+	}
+
+	if got := b.TakeDropped(slow); got != 3 {
+		t.Errorf("TakeDropped(slow) = %d, want 3", got)
+	}
+	if got := b.TakeDropped(slow); got != 0 {
+		t.Errorf("second TakeDropped(slow) = %d, want 0 after reset", got)
+	}
+	if got := b.TakeDropped(fast); got != 0 {
+		t.Errorf("TakeDropped(fast) = %d, want 0", got)
+	}
+
+	var nilBus *Bus
+	if got := nilBus.TakeDropped(slow); got != 0 {
+		t.Errorf("TakeDropped on nil bus = %d, want 0", got)
+	}
+}
+
+func TestSubscribeFiltered(t *testing.T) {
+	b := New()
+	ch := b.SubscribeFiltered(1, func(e Event) bool { return e.Source == SourceAgent })
+	defer b.Unsubscribe(ch)
+
+	b.Publish(Event{Source: SourceLoop, Kind: "tick"})
+	b.Publish(Event{Source: SourceAgent, Kind: "first"})
+	for i := 0; i < 5; i++ {
+		b.Publish(Event{Source: SourceLoop, Kind: "tick"})
+	}
+	b.Publish(Event{Source: SourceAgent, Kind: "second"})
+
+	if got := (<-ch).Kind; got != "first" {
+		t.Errorf("received %q, want first (filtered events must not take buffer space)", got)
+	}
+	if got := b.TakeDropped(ch); got != 1 {
+		t.Errorf("TakeDropped = %d, want 1: only the accepted event that found the buffer full", got)
+	}
+}
+
 func TestUnsubscribeClosesChannel(t *testing.T) {
 	b := New()
 	ch := b.Subscribe(8)
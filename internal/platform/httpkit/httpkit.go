@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math/rand/v2"
 	"net"
 	"net/http"
 	"strconv"
@@ -61,6 +62,7 @@ type clientConfig struct {
 	retryCount            int
 	retryDelay            time.Duration
 	retryStatuses         map[int]bool
+	retryMaxDelay         time.Duration
 	logger                *slog.Logger
 }
 
@@ -154,6 +156,20 @@ func WithRetryOnStatus(statuses ...int) ClientOption {
 	}
 }
 
+// WithRetryBackoff switches [WithRetry] from a fixed delay to jittered
+// exponential backoff: the configured delay doubles on each attempt,
+// capped at maxDelay, and each wait is drawn uniformly from the upper
+// half of that window so concurrent callers don't retry in lockstep.
+// A zero or negative maxDelay leaves the fixed delay in place.
+//
+// With backoff enabled, maxDelay also bounds how long a Retry-After
+// header may hold a status retry. When the server asks for a longer
+// wait, the response is returned to the caller immediately rather
+// than stalling the request, so the agent loop can fail over instead.
+func WithRetryBackoff(maxDelay time.Duration) ClientOption {
+	return func(c *clientConfig) { c.retryMaxDelay = maxDelay }
+}
+
 // WithLogger sets a logger for retry diagnostics.
 func WithLogger(l *slog.Logger) ClientOption {
 	return func(c *clientConfig) { c.logger = l }
@@ -217,6 +233,7 @@ func NewClient(opts ...ClientOption) *http.Client {
 			count:         cfg.retryCount,
 			delay:         cfg.retryDelay,
 			retryStatuses: cfg.retryStatuses,
+			maxDelay:      cfg.retryMaxDelay,
 			logger:        cfg.logger,
 		}
 	}
@@ -262,14 +279,37 @@ func DrainAndClose(rc io.ReadCloser, limit int64) {
 // 5xx). Status-based retries drain and close the prior response body
 // before re-sending; Retry-After is honored when present, otherwise
 // the configured delay is used.
+//
+// When maxDelay is positive, the delay grows exponentially per attempt
+// with jitter (see [WithRetryBackoff]).
 type retryTransport struct {
 	base          http.RoundTripper
 	count         int
 	delay         time.Duration
 	retryStatuses map[int]bool
+	maxDelay      time.Duration
 	logger        *slog.Logger
 }
 
+// backoff returns the wait before the given 1-based retry attempt.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	if t.maxDelay <= 0 {
+		return t.delay
+	}
+	d := t.delay
+	for i := 1; i < attempt && d < t.maxDelay; i++ {
+		d *= 2
+	}
+	if d > t.maxDelay {
+		d = t.maxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
 func (t *retryTransport) shouldRetryStatus(resp *http.Response) bool {
 	if resp == nil || len(t.retryStatuses) == 0 {
 		return false
@@ -323,11 +363,25 @@ func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		statusRetry := err == nil && t.shouldRetryStatus(resp)
 
 		// Determine the backoff for this attempt. Default to the
-		// configured delay; let Retry-After win for HTTP-level retries
-		// when the server supplies one (including "Retry-After: 0").
-		wait := t.delay
+		// configured delay (or exponential backoff); let Retry-After
+		// win for HTTP-level retries when the server supplies one
+		// (including "Retry-After: 0"), unless it exceeds the backoff
+		// cap, in which case the response goes back to the caller.
+		wait := t.backoff(attempt)
 		if statusRetry {
 			if d := retryAfterDelay(resp); d >= 0 {
+				if t.maxDelay > 0 && d > t.maxDelay {
+					if t.logger != nil {
+						t.logger.Debug("not retrying: Retry-After exceeds backoff cap",
+							"method", req.Method,
+							"url", req.URL.String(),
+							"status", resp.StatusCode,
+							"retry_after_ms", d.Milliseconds(),
+							"max_delay_ms", t.maxDelay.Milliseconds(),
+						)
+					}
+					return resp, nil
+				}
 				wait = d
 			}
 		}
@@ -629,3 +629,70 @@ func TestRetryTransport_HonorsRetryAfterSeconds(t *testing.T) {
 		t.Fatal("RoundTrip did not honor Retry-After: 0 (waited on configured 1h delay instead)")
 	}
 }
+
+func TestRetryTransport_BackoffGrowsAndCaps(t *testing.T) {
+	rt := &retryTransport{delay: 100 * time.Millisecond, maxDelay: 350 * time.Millisecond}
+
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 175 * time.Millisecond, 350 * time.Millisecond},
+		{10, 175 * time.Millisecond, 350 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		for range 50 {
+			if got := rt.backoff(tt.attempt); got < tt.min || got > tt.max {
+				t.Fatalf("backoff(%d) = %v, want within [%v, %v]", tt.attempt, got, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+func TestRetryTransport_FixedDelayWithoutBackoff(t *testing.T) {
+	rt := &retryTransport{delay: 100 * time.Millisecond}
+	for attempt := 1; attempt <= 3; attempt++ {
+		if got := rt.backoff(attempt); got != 100*time.Millisecond {
+			t.Fatalf("backoff(%d) = %v, want fixed 100ms", attempt, got)
+		}
+	}
+}
+
+func TestRetryTransport_RetryAfterBeyondCapReturnsResponse(t *testing.T) {
+	base := &statusRoundTripper{
+		statuses:   []int{http.StatusTooManyRequests},
+		retryAfter: "3600",
+	}
+	rt := &retryTransport{
+		base:          base,
+		count:         3,
+		delay:         time.Millisecond,
+		maxDelay:      time.Second,
+		retryStatuses: map[int]bool{http.StatusTooManyRequests: true},
+	}
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429 returned without waiting", resp.StatusCode)
+	}
+	if base.calls != 1 {
+		t.Errorf("expected 1 call, got %d", base.calls)
+	}
+}
+
+func TestNewClient_WithRetryBackoff(t *testing.T) {
+	c := NewClient(WithRetry(3, time.Second), WithRetryBackoff(30*time.Second))
+	rt, ok := c.Transport.(*retryTransport)
+	if !ok {
+		t.Fatalf("Transport = %T, want *retryTransport", c.Transport)
+	}
+	if rt.maxDelay != 30*time.Second {
+		t.Errorf("maxDelay = %v, want 30s", rt.maxDelay)
+	}
+}
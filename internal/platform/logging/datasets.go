@@ -11,10 +11,12 @@ import (
 )
 
 // DatasetHandlerOptions controls how slog records are split between filesystem
-// datasets and operator-facing stdout.
+// datasets and operator-facing stdout. The levels are [slog.Leveler]s so
+// a [slog.LevelVar] can change them at runtime; nil means
+// [slog.LevelInfo].
 type DatasetHandlerOptions struct {
-	DatasetLevel    slog.Level
-	StdoutLevel     slog.Level
+	DatasetLevel    slog.Leveler
+	StdoutLevel     slog.Leveler
 	StdoutEnabled   bool
 	EventsEnabled   bool
 	RequestsEnabled bool
@@ -46,7 +48,7 @@ func (h *DatasetHandler) Enabled(ctx context.Context, level slog.Level) bool {
 	if h.inner != nil && h.options.StdoutEnabled && h.inner.Enabled(ctx, level) {
 		return true
 	}
-	return h.writer != nil && level >= h.options.DatasetLevel
+	return h.writer != nil && level >= levelOf(h.options.DatasetLevel)
 }
 
 // Handle routes one slog record into the configured dataset stream and stdout.
@@ -58,7 +60,7 @@ func (h *DatasetHandler) Handle(ctx context.Context, r slog.Record) error {
 	decision := classifyDataset(projection)
 
 	var writeErr error
-	if h.writer != nil && r.Level >= h.options.DatasetLevel && datasetWriteEnabled(h.options, decision.Dataset) {
+	if h.writer != nil && r.Level >= levelOf(h.options.DatasetLevel) && datasetWriteEnabled(h.options, decision.Dataset) {
 		if err := h.writer.WriteRecord(projection.toDatasetRecord(decision)); err != nil {
 			writeErr = err
 		}
@@ -272,7 +274,7 @@ func stdoutShouldEmit(options DatasetHandlerOptions, dataset string, level slog.
 	if !options.StdoutEnabled {
 		return false
 	}
-	if level < options.StdoutLevel {
+	if level < levelOf(options.StdoutLevel) {
 		return false
 	}
 	if level >= slog.LevelWarn {
@@ -281,6 +283,13 @@ func stdoutShouldEmit(options DatasetHandlerOptions, dataset string, level slog.
 	return dataset == DatasetEvents
 }
 
+func levelOf(l slog.Leveler) slog.Level {
+	if l == nil {
+		return slog.LevelInfo
+	}
+	return l.Level()
+}
+
 func normalizeDatasetKind(message string) string {
 	message = strings.TrimSpace(strings.ToLower(message))
 	if message == "" {
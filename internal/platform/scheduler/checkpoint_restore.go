@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/checkpoint"
+)
+
+// RestoreCheckpoint rewinds the task table to the tasks in state,
+// implementing [checkpoint.Restorer]. Snapshot tasks are recreated or
+// reset to their captured definition, and tasks the snapshot lacks are
+// deleted with their execution history. Restored tasks come back
+// unarmed, so the scheduler computes their next fire on start instead
+// of treating a stale next_run_at as a missed run. The whole restore is
+// one transaction.
+//
+// Checkpoints taken before task definitions were captured cannot
+// rebuild a task's schedule or payload; the store is skipped for them.
+func (s *Store) RestoreCheckpoint(state *checkpoint.State, dryRun bool) (checkpoint.StoreChange, error) {
+	change := checkpoint.StoreChange{Store: "scheduler"}
+
+	tasks := make([]Task, len(state.Tasks))
+	for i, ct := range state.Tasks {
+		if len(ct.Definition) == 0 {
+			change.Skipped = "checkpoint predates task definitions"
+			return change, nil
+		}
+		if err := json.Unmarshal(ct.Definition, &tasks[i]); err != nil {
+			return change, fmt.Errorf("decode task %s: %w", ct.ID, err)
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return change, fmt.Errorf("begin restore tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().Format(time.RFC3339Nano)
+	seen := make(map[string]bool, len(tasks))
+	for _, t := range tasks {
+		seen[t.ID] = true
+
+		scheduleJSON, err := json.Marshal(t.Schedule)
+		if err != nil {
+			return change, fmt.Errorf("marshal schedule: %w", err)
+		}
+		payloadJSON, err := json.Marshal(t.Payload)
+		if err != nil {
+			return change, fmt.Errorf("marshal payload: %w", err)
+		}
+		enabled := 0
+		if t.Enabled {
+			enabled = 1
+		}
+		var completedAt *string
+		if t.CompletedAt != nil {
+			v := t.CompletedAt.Format(time.RFC3339Nano)
+			completedAt = &v
+		}
+
+		var name, curSchedule, curPayload string
+		var curEnabled int
+		var curCompleted sql.NullString
+		err = tx.QueryRow(`
+			SELECT name, schedule_json, payload_json, enabled, completed_at FROM tasks WHERE id = ?
+		`, t.ID).Scan(&name, &curSchedule, &curPayload, &curEnabled, &curCompleted)
+		switch {
+		case err == sql.ErrNoRows:
+			change.Added++
+			if dryRun {
+				continue
+			}
+			_, err = tx.Exec(`
+				INSERT INTO tasks (id, name, schedule_json, payload_json, enabled, created_at, created_by, updated_at, completed_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`, t.ID, t.Name, string(scheduleJSON), string(payloadJSON), enabled,
+				t.CreatedAt.Format(time.RFC3339Nano), t.CreatedBy, now, completedAt)
+		case err != nil:
+			return change, fmt.Errorf("look up task %s: %w", t.ID, err)
+		case name == t.Name && curSchedule == string(scheduleJSON) && curPayload == string(payloadJSON) &&
+			curEnabled == enabled && curCompleted.Valid == (completedAt != nil):
+			change.Unchanged++
+			continue
+		default:
+			change.Updated++
+			if dryRun {
+				continue
+			}
+			_, err = tx.Exec(`
+				UPDATE tasks SET name = ?, schedule_json = ?, payload_json = ?, enabled = ?,
+					completed_at = ?, next_run_at = NULL, updated_at = ?
+				WHERE id = ?
+			`, t.Name, string(scheduleJSON), string(payloadJSON), enabled, completedAt, now, t.ID)
+		}
+		if err != nil {
+			return change, fmt.Errorf("restore task %s: %w", t.ID, err)
+		}
+	}
+
+	rows, err := tx.Query(`SELECT id FROM tasks`)
+	if err != nil {
+		return change, fmt.Errorf("query tasks: %w", err)
+	}
+	var stale []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return change, fmt.Errorf("scan task: %w", err)
+		}
+		if !seen[id] {
+			stale = append(stale, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return change, fmt.Errorf("query tasks: %w", err)
+	}
+	change.Removed = len(stale)
+
+	if dryRun {
+		return change, nil
+	}
+	for _, id := range stale {
+		if _, err := tx.Exec(`DELETE FROM tasks WHERE id = ?`, id); err != nil {
+			return change, fmt.Errorf("delete task %s: %w", id, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return change, fmt.Errorf("commit restore: %w", err)
+	}
+	return change, nil
+}
@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/checkpoint"
+)
+
+// snapshotTasks captures the store the way the app's checkpoint
+// provider does.
+func snapshotTasks(t *testing.T, s *Store) *checkpoint.State {
+	t.Helper()
+	tasks, err := s.ListTasks(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &checkpoint.State{}
+	for _, task := range tasks {
+		def, err := json.Marshal(task)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.Tasks = append(state.Tasks, checkpoint.Task{
+			ID: checkpoint.ParseUUID(task.ID), Name: task.Name, Definition: def,
+		})
+	}
+	return state
+}
+
+func TestRestoreCheckpoint(t *testing.T) {
+	s := newTestStore(t)
+	hourly := Schedule{Kind: ScheduleEvery, Every: &Duration{Duration: time.Hour}}
+	keep := &Task{Name: "keep", Schedule: hourly, Payload: Payload{Kind: PayloadWake}, Enabled: true}
+	edit := &Task{Name: "edit", Schedule: hourly, Payload: Payload{Kind: PayloadWake}, Enabled: true}
+	drop := &Task{Name: "drop", Schedule: hourly, Payload: Payload{Kind: PayloadWake}, Enabled: true}
+	for _, task := range []*Task{keep, edit, drop} {
+		if err := s.CreateTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	state := snapshotTasks(t, s)
+
+	edit.Schedule = Schedule{Kind: ScheduleEvery, Every: &Duration{Duration: 5 * time.Minute}}
+	edit.Enabled = false
+	if err := s.UpdateTask(edit); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteTask(drop.ID); err != nil {
+		t.Fatal(err)
+	}
+	later := &Task{Name: "later", Schedule: hourly, Payload: Payload{Kind: PayloadWake}, Enabled: true}
+	if err := s.CreateTask(later); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := s.RestoreCheckpoint(state, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Unchanged != 1 || plan.Updated != 1 || plan.Added != 1 || plan.Removed != 1 {
+		t.Fatalf("plan = %+v, want one of each", plan)
+	}
+	if got, _ := s.GetTask(later.ID); got == nil {
+		t.Fatal("dry run deleted a task")
+	}
+
+	if _, err := s.RestoreCheckpoint(state, false); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.GetTask(edit.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Enabled || got.Schedule.Every.Duration != time.Hour {
+		t.Errorf("edited task = %+v, want the hourly, enabled snapshot", got)
+	}
+	if _, err := s.GetTask(drop.ID); err != nil {
+		t.Errorf("deleted task not recreated: %v", err)
+	}
+	if got, _ := s.GetTask(later.ID); got != nil {
+		t.Error("task created after the snapshot still exists")
+	}
+}
+
+func TestRestoreCheckpoint_SkipsLegacyTasks(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.CreateTask(&Task{Name: "live", Schedule: Schedule{Kind: ScheduleCron, Cron: "0 * * * *"}, Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	state := &checkpoint.State{Tasks: []checkpoint.Task{{Name: "old", Schedule: "0 * * * *", Action: "wake"}}}
+
+	change, err := s.RestoreCheckpoint(state, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.Skipped == "" || change.Changed() {
+		t.Errorf("change = %+v, want the store skipped", change)
+	}
+	if tasks, _ := s.ListTasks(false); len(tasks) != 1 {
+		t.Errorf("tasks = %d, want the live task untouched", len(tasks))
+	}
+}
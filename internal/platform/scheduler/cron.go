@@ -0,0 +1,220 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchDays bounds how far ahead [CronExpr.Next] looks for a
+// matching day. Five years covers every satisfiable expression,
+// including 29 February; anything beyond it never fires.
+const cronSearchDays = 5 * 366
+
+// cronShortcuts maps the supported @ shortcuts to their five-field
+// equivalents.
+var cronShortcuts = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// CronExpr is a parsed five-field cron expression: minute, hour, day
+// of month, month, and day of week. Each field is a bitset of the
+// values it matches.
+type CronExpr struct {
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny record whether the day fields were written
+	// with a leading "*". As in classic cron, when both day fields are
+	// restricted a day matches if either one does.
+	domAny, dowAny bool
+}
+
+// ParseCron parses a five-field cron expression such as "0 7 * * *" or
+// one of the shortcuts @yearly, @annually, @monthly, @weekly, @daily,
+// @midnight, and @hourly. Fields accept "*", single values, ranges
+// ("1-5"), lists ("1,15"), and steps ("*/15", "9-17/2"). Months and
+// weekdays also accept three-letter English names, and 7 means Sunday.
+func ParseCron(expr string) (CronExpr, error) {
+	spec := strings.TrimSpace(expr)
+	if strings.HasPrefix(spec, "@") {
+		full, ok := cronShortcuts[strings.ToLower(spec)]
+		if !ok {
+			return CronExpr{}, fmt.Errorf("unknown cron shortcut %q", spec)
+		}
+		spec = full
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return CronExpr{}, fmt.Errorf("cron expression %q has %d fields, want 5", expr, len(fields))
+	}
+
+	var c CronExpr
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return CronExpr{}, fmt.Errorf("minute: %w", err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return CronExpr{}, fmt.Errorf("hour: %w", err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return CronExpr{}, fmt.Errorf("day of month: %w", err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return CronExpr{}, fmt.Errorf("month: %w", err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return CronExpr{}, fmt.Errorf("day of week: %w", err)
+	}
+	// Fold 7 onto Sunday.
+	if c.dow&(1<<7) != 0 {
+		c.dow = c.dow&^(1<<7) | 1
+	}
+	c.domAny = strings.HasPrefix(fields[2], "*")
+	c.dowAny = strings.HasPrefix(fields[4], "*")
+	return c, nil
+}
+
+// parseCronField parses one comma-separated cron field into a bitset
+// of the values in [min, max] it matches.
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, term := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(term, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*":
+			lo, hi = min, max
+		case strings.Contains(rangePart, "-"):
+			loPart, hiPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseCronValue(loPart, min, max, names); err != nil {
+				return 0, err
+			}
+			if hi, err = parseCronValue(hiPart, min, max, names); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("range %q is backwards", rangePart)
+			}
+		default:
+			v, err := parseCronValue(rangePart, min, max, names)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = v, v
+			if hasStep {
+				// "5/15" means every 15 starting at 5.
+				hi = max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// parseCronValue parses a single numeric or named field value.
+func parseCronValue(s string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, min, max)
+	}
+	return v, nil
+}
+
+// Next returns the first time strictly after after that the expression
+// matches, evaluated on the wall clock of loc. A wall-clock time that a
+// daylight-saving jump skips fires the length of the jump later rather
+// than not at all; a time that a fall-back repeats fires once. ok is
+// false when the expression never matches, such as "0 0 30 2 *".
+func (c CronExpr) Next(after time.Time, loc *time.Location) (time.Time, bool) {
+	if loc == nil {
+		loc = time.Local
+	}
+	start := after.In(loc).Truncate(time.Minute).Add(time.Minute)
+	y, m, d := start.Date()
+
+	for i := 0; i < cronSearchDays; i++ {
+		day := time.Date(y, m, d+i, 0, 0, 0, 0, loc)
+		if !c.matchesDay(day) {
+			continue
+		}
+		for h := 0; h < 24; h++ {
+			if c.hour&(1<<uint(h)) == 0 {
+				continue
+			}
+			for min := 0; min < 60; min++ {
+				if c.minute&(1<<uint(min)) == 0 {
+					continue
+				}
+				t := cronWallTime(day, h, min, loc)
+				if t.After(after) {
+					return t, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// cronWallTime returns the instant the wall-clock time h:min on day
+// names in loc. A time inside a daylight-saving gap does not exist, and
+// time.Date resolves it inconsistently, so it is read with the offset
+// in effect before the jump, which lands it the length of the jump
+// later: 02:30 on a spring-forward night becomes 03:30.
+func cronWallTime(day time.Time, h, min int, loc *time.Location) time.Time {
+	t := time.Date(day.Year(), day.Month(), day.Day(), h, min, 0, 0, loc)
+	if t.Hour() == h && t.Minute() == min {
+		return t
+	}
+	_, offset := t.Add(-3 * time.Hour).Zone()
+	wall := time.Date(day.Year(), day.Month(), day.Day(), h, min, 0, 0, time.UTC)
+	return wall.Add(-time.Duration(offset) * time.Second).In(loc)
+}
+
+// matchesDay reports whether the month and day fields match day.
+func (c CronExpr) matchesDay(day time.Time) bool {
+	if c.month&(1<<uint(day.Month())) == 0 {
+		return false
+	}
+	domMatch := c.dom&(1<<uint(day.Day())) != 0
+	dowMatch := c.dow&(1<<uint(day.Weekday())) != 0
+	if !c.domAny && !c.dowAny {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
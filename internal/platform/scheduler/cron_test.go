@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	return loc
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"@fortnightly",
+		"* * * smarch *",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	utc := time.UTC
+	tests := []struct {
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"0 7 * * *", time.Date(2026, 3, 10, 6, 59, 0, 0, utc), time.Date(2026, 3, 10, 7, 0, 0, 0, utc)},
+		{"0 7 * * *", time.Date(2026, 3, 10, 7, 0, 0, 0, utc), time.Date(2026, 3, 11, 7, 0, 0, 0, utc)},
+		{"*/15 * * * *", time.Date(2026, 3, 10, 7, 1, 30, 0, utc), time.Date(2026, 3, 10, 7, 15, 0, 0, utc)},
+		{"5/20 * * * *", time.Date(2026, 3, 10, 7, 26, 0, 0, utc), time.Date(2026, 3, 10, 7, 45, 0, 0, utc)},
+		{"30 9 * * mon-fri", time.Date(2026, 3, 13, 10, 0, 0, 0, utc), time.Date(2026, 3, 16, 9, 30, 0, 0, utc)},
+		{"0 0 * * 7", time.Date(2026, 3, 10, 0, 0, 0, 0, utc), time.Date(2026, 3, 15, 0, 0, 0, 0, utc)},
+		{"0 12 1,15 * *", time.Date(2026, 3, 2, 0, 0, 0, 0, utc), time.Date(2026, 3, 15, 12, 0, 0, 0, utc)},
+		// Both day fields restricted: either one matches.
+		{"0 0 13 * fri", time.Date(2026, 3, 1, 0, 0, 0, 0, utc), time.Date(2026, 3, 6, 0, 0, 0, 0, utc)},
+		{"0 0 29 feb *", time.Date(2026, 3, 1, 0, 0, 0, 0, utc), time.Date(2028, 2, 29, 0, 0, 0, 0, utc)},
+		{"@daily", time.Date(2026, 3, 10, 13, 0, 0, 0, utc), time.Date(2026, 3, 11, 0, 0, 0, 0, utc)},
+		{"@hourly", time.Date(2026, 3, 10, 13, 0, 0, 0, utc), time.Date(2026, 3, 10, 14, 0, 0, 0, utc)},
+		{"@monthly", time.Date(2026, 12, 5, 0, 0, 0, 0, utc), time.Date(2027, 1, 1, 0, 0, 0, 0, utc)},
+	}
+	for _, tt := range tests {
+		expr, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		got, ok := expr.Next(tt.after, utc)
+		if !ok || !got.Equal(tt.want) {
+			t.Errorf("%q Next(%v) = %v, %v; want %v", tt.expr, tt.after, got, ok, tt.want)
+		}
+	}
+}
+
+func TestCronNext_Never(t *testing.T) {
+	expr, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron: %v", err)
+	}
+	if got, ok := expr.Next(time.Now(), time.UTC); ok {
+		t.Errorf("Next() = %v, want no match for 30 February", got)
+	}
+}
+
+func TestCronNext_Timezone(t *testing.T) {
+	chicago := mustLoadLocation(t, "America/Chicago")
+	expr, _ := ParseCron("0 7 * * *")
+
+	// 12:30 UTC is 07:30 CDT, so 07:00 Chicago has passed for the day.
+	after := time.Date(2026, 6, 1, 12, 30, 0, 0, time.UTC)
+	got, _ := expr.Next(after, chicago)
+	want := time.Date(2026, 6, 2, 7, 0, 0, 0, chicago)
+	if !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+}
+
+func TestCronNext_DaylightSaving(t *testing.T) {
+	chicago := mustLoadLocation(t, "America/Chicago")
+
+	// 2026-03-08 02:30 does not exist in Chicago; the run moves to just
+	// after the jump rather than being skipped.
+	spring, _ := ParseCron("30 2 * * *")
+	got, _ := spring.Next(time.Date(2026, 3, 8, 0, 0, 0, 0, chicago), chicago)
+	if want := time.Date(2026, 3, 8, 8, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("spring-forward Next() = %v, want %v", got, want.In(chicago))
+	}
+
+	// 2026-11-01 01:30 happens twice in Chicago; it fires once.
+	fall, _ := ParseCron("30 1 * * *")
+	first, _ := fall.Next(time.Date(2026, 11, 1, 0, 0, 0, 0, chicago), chicago)
+	second, _ := fall.Next(first, chicago)
+	if want := time.Date(2026, 11, 2, 1, 30, 0, 0, chicago); !second.Equal(want) {
+		t.Errorf("fall-back runs = %v then %v, want the second on %v", first, second, want)
+	}
+}
+
+func TestTaskNextRun_Cron(t *testing.T) {
+	task := &Task{Schedule: Schedule{Kind: ScheduleCron, Cron: "0 7 * * *", Timezone: "UTC"}}
+	got, ok := task.NextRun(time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC))
+	if want := time.Date(2026, 3, 11, 7, 0, 0, 0, time.UTC); !ok || !got.Equal(want) {
+		t.Errorf("NextRun() = %v, %v; want %v", got, ok, want)
+	}
+}
+
+func TestScheduleValidate(t *testing.T) {
+	bad := []Schedule{
+		{Kind: ScheduleCron, Cron: "not a cron"},
+		{Kind: ScheduleCron, Cron: "@daily", Timezone: "Mars/Olympus_Mons"},
+		{Kind: ScheduleEvery},
+		{Kind: ScheduleAt},
+		{Kind: "sometimes"},
+	}
+	for _, s := range bad {
+		if err := s.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", s)
+		}
+	}
+	if err := (Schedule{Kind: ScheduleCron, Cron: "0 7 * * *", Timezone: "UTC"}).Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
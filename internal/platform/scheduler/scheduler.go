@@ -2,12 +2,24 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"sync"
 	"time"
 )
 
-// ExecuteFunc is called when a task fires.
+// missedRunWindow bounds how late a missed run may still be caught up
+// after downtime. Older misses are recorded as skipped.
+const missedRunWindow = 24 * time.Hour
+
+// lateRunGrace is how far past its scheduled time a run may start
+// before it is annotated as late in the payload handed to ExecuteFunc.
+const lateRunGrace = time.Minute
+
+// ExecuteFunc is called when a task fires. A [ScheduleOnce] run that
+// starts more than lateRunGrace after its scheduled time, such as one
+// caught up after downtime, carries "late": true and "scheduled_for" (RFC 3339) in its
+// payload data.
 type ExecuteFunc func(ctx context.Context, task *Task, execution *Execution) error
 
 // Scheduler manages task scheduling and execution.
@@ -16,6 +28,10 @@ type Scheduler struct {
 	store   *Store
 	execute ExecuteFunc
 
+	// location is the timezone new cron tasks are pinned to when they
+	// do not name one. Nil leaves them on local time.
+	location *time.Location
+
 	mu      sync.Mutex
 	timers  map[string]*time.Timer // taskID -> timer
 	running bool
@@ -34,7 +50,27 @@ func New(logger *slog.Logger, store *Store, execute ExecuteFunc) *Scheduler {
 	}
 }
 
+// SetLocation sets the timezone that cron tasks created without a
+// Timezone of their own are evaluated in, normally the household
+// timezone from config. It must be called before tasks are created.
+func (s *Scheduler) SetLocation(loc *time.Location) {
+	s.location = loc
+}
+
+// Location returns the scheduler's timezone: the one passed to
+// SetLocation, or local time when none was set.
+func (s *Scheduler) Location() *time.Location {
+	if s.location == nil {
+		return time.Local
+	}
+	return s.location
+}
+
 // Start begins the scheduler, loading tasks and setting up timers.
+// A task whose persisted next run passed while the scheduler was down
+// is caught up once, or recorded as skipped if it is older than
+// missedRunWindow, before resuming its normal schedule. A missed
+// [ScheduleOnce] task always fires, late.
 func (s *Scheduler) Start(ctx context.Context) error {
 	s.mu.Lock()
 	if s.running {
@@ -52,8 +88,9 @@ func (s *Scheduler) Start(ctx context.Context) error {
 		return err
 	}
 
+	now := time.Now()
 	for _, task := range tasks {
-		s.scheduleTask(task)
+		s.resumeTask(task, now)
 	}
 
 	s.logger.Debug("scheduler started", "tasks", len(tasks))
@@ -88,12 +125,19 @@ func (s *Scheduler) Stop() {
 
 // CreateTask adds a new task and schedules it.
 func (s *Scheduler) CreateTask(task *Task) error {
+	if err := task.Schedule.Validate(); err != nil {
+		return err
+	}
+	if task.Schedule.Kind == ScheduleCron && task.Schedule.Timezone == "" && s.location != nil {
+		task.Schedule.Timezone = s.location.String()
+	}
+
 	if err := s.store.CreateTask(task); err != nil {
 		return err
 	}
 
 	if task.Enabled {
-		s.scheduleTask(task)
+		s.scheduleTask(task, time.Now())
 	}
 
 	s.logger.Info("task created",
@@ -107,6 +151,9 @@ func (s *Scheduler) CreateTask(task *Task) error {
 
 // UpdateTask modifies a task and reschedules it.
 func (s *Scheduler) UpdateTask(task *Task) error {
+	if err := task.Schedule.Validate(); err != nil {
+		return err
+	}
 	if err := s.store.UpdateTask(task); err != nil {
 		return err
 	}
@@ -116,7 +163,9 @@ func (s *Scheduler) UpdateTask(task *Task) error {
 
 	// Reschedule if enabled
 	if task.Enabled {
-		s.scheduleTask(task)
+		s.scheduleTask(task, time.Now())
+	} else {
+		s.setNextRun(task, nil)
 	}
 
 	s.logger.Info("task updated", "id", task.ID, "name", task.Name)
@@ -166,13 +215,77 @@ func (s *Scheduler) TriggerTask(ctx context.Context, taskID string) (*Execution,
 	return s.executeTask(ctx, task, time.Now())
 }
 
-// scheduleTask sets up a timer for the next execution.
-func (s *Scheduler) scheduleTask(task *Task) {
-	next, ok := task.NextRun(time.Now())
+// resumeTask arms a task loaded at startup. If its persisted next run
+// passed while the scheduler was down, that one run is caught up (or
+// recorded as skipped when older than missedRunWindow) instead of being
+// silently dropped; runs in between are not replayed. [ScheduleOnce]
+// tasks are handled by resumeOneShot.
+func (s *Scheduler) resumeTask(task *Task, now time.Time) {
+	if task.Schedule.Kind == ScheduleOnce {
+		s.resumeOneShot(task, now)
+		return
+	}
+
+	missed := task.NextRunAt
+	if missed == nil || !missed.Before(now) {
+		s.scheduleTask(task, now)
+		return
+	}
+
+	if now.Sub(*missed) > missedRunWindow {
+		exec := &Execution{
+			ID:          NewID(),
+			TaskID:      task.ID,
+			ScheduledAt: *missed,
+			Status:      StatusSkipped,
+			Result:      fmt.Sprintf("missed execution window (>%s)", missedRunWindow),
+		}
+		if err := s.store.CreateExecution(exec); err != nil {
+			s.logger.Error("failed to record skipped execution", "id", task.ID, "error", err)
+		}
+		s.logger.Info("skipped stale run", "task", task.Name, "scheduled", *missed)
+		s.scheduleTask(task, now)
+		return
+	}
+
+	s.logger.Info("catching up missed run", "task", task.Name, "scheduled", *missed)
+	s.armTask(task, *missed)
+}
+
+// resumeOneShot arms a [ScheduleOnce] task loaded at startup. A
+// one-shot has no later run to fall back on, so one whose time passed
+// while the scheduler was down fires late however long ago that was,
+// and onTaskFire completes it. Schedule.At stands in for a next run
+// that was never persisted.
+func (s *Scheduler) resumeOneShot(task *Task, now time.Time) {
+	due := task.NextRunAt
+	if due == nil {
+		due = task.Schedule.At
+	}
+	if due == nil || !due.Before(now) {
+		s.scheduleTask(task, now)
+		return
+	}
+
+	s.logger.Info("running missed one-shot late", "task", task.Name, "scheduled", *due)
+	s.armTask(task, *due)
+}
+
+// scheduleTask sets up a timer for the first run strictly after after.
+func (s *Scheduler) scheduleTask(task *Task, after time.Time) {
+	next, ok := task.NextRun(after)
 	if !ok {
+		s.setNextRun(task, nil)
 		s.logger.Debug("task has no future runs", "id", task.ID, "name", task.Name)
 		return
 	}
+	s.armTask(task, next)
+}
+
+// armTask persists next as the task's next run and sets its timer.
+// A next already in the past fires immediately.
+func (s *Scheduler) armTask(task *Task, next time.Time) {
+	s.setNextRun(task, &next)
 
 	delay := time.Until(next)
 	if delay < 0 {
@@ -188,7 +301,7 @@ func (s *Scheduler) scheduleTask(task *Task) {
 	}
 
 	s.timers[task.ID] = time.AfterFunc(delay, func() {
-		s.onTaskFire(task.ID)
+		s.onTaskFire(task.ID, next)
 	})
 
 	s.logger.Debug("task scheduled",
@@ -199,8 +312,17 @@ func (s *Scheduler) scheduleTask(task *Task) {
 	)
 }
 
-// onTaskFire is called when a task's timer fires.
-func (s *Scheduler) onTaskFire(taskID string) {
+// setNextRun persists a task's next run, logging rather than failing:
+// the in-memory timer is authoritative while the scheduler runs.
+func (s *Scheduler) setNextRun(task *Task, next *time.Time) {
+	task.NextRunAt = next
+	if err := s.store.SetNextRun(task.ID, next); err != nil {
+		s.logger.Warn("failed to persist next run", "id", task.ID, "error", err)
+	}
+}
+
+// onTaskFire is called when a task's timer for scheduledAt fires.
+func (s *Scheduler) onTaskFire(taskID string, scheduledAt time.Time) {
 	s.wg.Add(1)
 	defer s.wg.Done()
 
@@ -224,19 +346,63 @@ func (s *Scheduler) onTaskFire(taskID string) {
 		return
 	}
 
+	// Advance the persisted next run before executing, so a crash
+	// mid-run does not replay this run on restart. Computing from no
+	// earlier than scheduledAt keeps a timer that fires a hair early
+	// from landing on the same run again. A one-shot is marked
+	// completed instead, which takes it off the schedule for good.
+	after := time.Now()
+	if scheduledAt.After(after) {
+		after = scheduledAt
+	}
+	if task.Schedule.Kind == ScheduleOnce {
+		completed := time.Now()
+		if err := s.store.CompleteTask(task.ID, completed); err != nil {
+			s.logger.Error("failed to mark one-shot task completed", "id", taskID, "error", err)
+		}
+		task.Enabled = false
+		task.NextRunAt = nil
+		task.CompletedAt = &completed
+	} else if next, ok := task.NextRun(after); ok {
+		s.setNextRun(task, &next)
+	} else {
+		s.setNextRun(task, nil)
+	}
+
+	if late := after.Sub(scheduledAt); task.Schedule.Kind == ScheduleOnce && late > lateRunGrace {
+		task.Payload.Data = annotateLate(task.Payload.Data, scheduledAt)
+		s.logger.Info("running task late", "id", taskID, "name", task.Name, "scheduled", scheduledAt, "late", late.Round(time.Second))
+	}
+
 	// Execute
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
-	_, err = s.executeTask(ctx, task, time.Now())
+	_, err = s.executeTask(ctx, task, scheduledAt)
 	if err != nil {
 		s.logger.Error("task execution failed", "id", taskID, "error", err)
 	}
 
-	// Reschedule for repeating tasks
-	if task.Schedule.Kind != ScheduleAt {
-		s.scheduleTask(task)
+	// Reschedule for repeating tasks. Runs that came due while this
+	// one executed are skipped rather than fired back to back.
+	if task.Schedule.Kind != ScheduleAt && task.Schedule.Kind != ScheduleOnce {
+		if now := time.Now(); now.After(after) {
+			after = now
+		}
+		s.scheduleTask(task, after)
+	}
+}
+
+// annotateLate returns a copy of data marked as a late run of the
+// occurrence scheduled for scheduledAt.
+func annotateLate(data map[string]any, scheduledAt time.Time) map[string]any {
+	annotated := make(map[string]any, len(data)+2)
+	for k, v := range data {
+		annotated[k] = v
 	}
+	annotated["late"] = true
+	annotated["scheduled_for"] = scheduledAt.Format(time.RFC3339)
+	return annotated
 }
 
 // executeTask runs a task and records the execution.
@@ -318,7 +484,7 @@ func (s *Scheduler) checkMissedExecutions(ctx context.Context) {
 	}
 
 	for _, exec := range pending {
-		if time.Since(exec.ScheduledAt) > 24*time.Hour {
+		if time.Since(exec.ScheduledAt) > missedRunWindow {
 			// Too old, skip it
 			exec.Status = StatusSkipped
 			exec.Result = "missed execution window (>24h)"
@@ -0,0 +1,395 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fireRecorder collects the scheduled times of executions.
+type fireRecorder struct {
+	mu       sync.Mutex
+	fired    []time.Time
+	payloads []map[string]any
+	done     chan struct{}
+}
+
+func newFireRecorder() *fireRecorder {
+	return &fireRecorder{done: make(chan struct{}, 10)}
+}
+
+func (f *fireRecorder) execute(ctx context.Context, task *Task, exec *Execution) error {
+	f.mu.Lock()
+	f.fired = append(f.fired, exec.ScheduledAt)
+	f.payloads = append(f.payloads, task.Payload.Data)
+	f.mu.Unlock()
+	f.done <- struct{}{}
+	return nil
+}
+
+func (f *fireRecorder) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-f.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("task did not fire")
+	}
+}
+
+func newCronTask(t *testing.T, store *Store, next time.Time) *Task {
+	t.Helper()
+	task := &Task{
+		Name:     "morning_briefing",
+		Schedule: Schedule{Kind: ScheduleCron, Cron: "0 7 * * *", Timezone: "UTC"},
+		Payload:  Payload{Kind: PayloadWake, Data: map[string]any{"message": "brief me"}},
+		Enabled:  true,
+	}
+	if err := store.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if err := store.SetNextRun(task.ID, &next); err != nil {
+		t.Fatalf("SetNextRun: %v", err)
+	}
+	return task
+}
+
+func TestSchedulerCreateTask_PinsCronToLocation(t *testing.T) {
+	chicago := mustLoadLocation(t, "America/Chicago")
+	store := newTestStore(t)
+	s := New(slog.Default(), store, nil)
+	s.SetLocation(chicago)
+
+	task := &Task{
+		Name:     "briefing",
+		Schedule: Schedule{Kind: ScheduleCron, Cron: "0 7 * * *"},
+		Enabled:  true,
+	}
+	if err := s.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	t.Cleanup(func() { s.cancelTimer(task.ID) })
+
+	got, err := store.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if got.Schedule.Timezone != "America/Chicago" {
+		t.Errorf("Timezone = %q, want America/Chicago", got.Schedule.Timezone)
+	}
+	if got.NextRunAt == nil {
+		t.Fatal("NextRunAt not persisted")
+	}
+	if local := got.NextRunAt.In(chicago); local.Hour() != 7 || local.Minute() != 0 {
+		t.Errorf("NextRunAt = %v, want 07:00 Chicago", local)
+	}
+
+	if err := s.CreateTask(&Task{Name: "bad", Schedule: Schedule{Kind: ScheduleCron, Cron: "0 25 * * *"}}); err == nil {
+		t.Error("CreateTask accepted an invalid cron expression")
+	}
+}
+
+func TestSchedulerStart_CatchesUpMissedRunOnce(t *testing.T) {
+	store := newTestStore(t)
+	missed := time.Now().Add(-2 * time.Hour).Truncate(time.Minute)
+	task := newCronTask(t, store, missed)
+
+	rec := newFireRecorder()
+	s := New(slog.Default(), store, rec.execute)
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	rec.wait(t)
+	s.Stop()
+
+	rec.mu.Lock()
+	fired := append([]time.Time(nil), rec.fired...)
+	rec.mu.Unlock()
+	if len(fired) != 1 || !fired[0].Equal(missed) {
+		t.Fatalf("fired = %v, want one catch-up run for %v", fired, missed)
+	}
+
+	got, err := store.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if got.NextRunAt == nil || !got.NextRunAt.After(time.Now()) {
+		t.Fatalf("NextRunAt = %v, want a future run after catching up", got.NextRunAt)
+	}
+
+	// A second restart finds nothing missed and does not fire again.
+	rec2 := newFireRecorder()
+	s2 := New(slog.Default(), store, rec2.execute)
+	if err := s2.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	s2.Stop()
+	if len(rec2.fired) != 0 {
+		t.Errorf("second start fired %v, want nothing", rec2.fired)
+	}
+}
+
+func TestSchedulerStart_SkipsStaleMissedRun(t *testing.T) {
+	store := newTestStore(t)
+	missed := time.Now().Add(-3 * missedRunWindow)
+	task := newCronTask(t, store, missed)
+
+	rec := newFireRecorder()
+	s := New(slog.Default(), store, rec.execute)
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	s.Stop()
+
+	if len(rec.fired) != 0 {
+		t.Errorf("fired = %v, want the stale run skipped", rec.fired)
+	}
+	execs, err := store.ListExecutions(task.ID, 10)
+	if err != nil {
+		t.Fatalf("ListExecutions: %v", err)
+	}
+	if len(execs) != 1 || execs[0].Status != StatusSkipped {
+		t.Errorf("executions = %+v, want one skipped record", execs)
+	}
+}
+
+func TestSchedulerOneShot_CompletesAfterFiring(t *testing.T) {
+	store := newTestStore(t)
+	rec := newFireRecorder()
+	s := New(slog.Default(), store, rec.execute)
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	at := time.Now().Add(50 * time.Millisecond)
+	task := &Task{
+		Name:     "reminder",
+		Schedule: Schedule{Kind: ScheduleOnce, At: &at},
+		Payload:  Payload{Kind: PayloadWake, Data: map[string]any{"message": "call the vet"}},
+		Enabled:  true,
+	}
+	if err := s.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	rec.wait(t)
+	s.Stop()
+
+	got, err := store.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if got.Enabled || got.CompletedAt == nil || got.NextRunAt != nil {
+		t.Errorf("task = enabled %v, completed %v, next %v; want disabled and completed", got.Enabled, got.CompletedAt, got.NextRunAt)
+	}
+	if late, _ := rec.payloads[0]["late"].(bool); late {
+		t.Error("on-time run was annotated late")
+	}
+
+	// Restarting does not fire it again.
+	rec2 := newFireRecorder()
+	s2 := New(slog.Default(), store, rec2.execute)
+	if err := s2.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	s2.Stop()
+	if len(rec2.fired) != 0 {
+		t.Errorf("completed one-shot fired again: %v", rec2.fired)
+	}
+}
+
+func TestSchedulerOneShot_MissedDuringDowntimeFiresLate(t *testing.T) {
+	store := newTestStore(t)
+	at := time.Now().Add(-30 * time.Minute)
+	task := &Task{
+		Name:     "reminder",
+		Schedule: Schedule{Kind: ScheduleOnce, At: &at},
+		Payload:  Payload{Kind: PayloadWake, Data: map[string]any{"message": "call the vet"}},
+		Enabled:  true,
+	}
+	if err := store.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if err := store.SetNextRun(task.ID, &at); err != nil {
+		t.Fatalf("SetNextRun: %v", err)
+	}
+
+	rec := newFireRecorder()
+	s := New(slog.Default(), store, rec.execute)
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	rec.wait(t)
+	s.Stop()
+
+	data := rec.payloads[0]
+	if late, _ := data["late"].(bool); !late || data["scheduled_for"] != at.Format(time.RFC3339) {
+		t.Errorf("payload = %+v, want a late annotation for %v", data, at)
+	}
+	got, err := store.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if got.CompletedAt == nil {
+		t.Error("late one-shot not marked completed")
+	}
+	if _, ok := got.Payload.Data["late"]; ok {
+		t.Error("late annotation was persisted to the task")
+	}
+}
+
+func TestSchedulerOneShot_MissedBeyondWindowStillFiresLate(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		persisted bool // next_run_at saved; false falls back to Schedule.At
+	}{
+		{name: "next run persisted", persisted: true},
+		{name: "next run not persisted", persisted: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newTestStore(t)
+			at := time.Now().Add(-3 * missedRunWindow)
+			task := &Task{
+				Name:     "reminder",
+				Schedule: Schedule{Kind: ScheduleOnce, At: &at},
+				Payload:  Payload{Kind: PayloadWake, Data: map[string]any{"message": "renew the passport"}},
+				Enabled:  true,
+			}
+			if err := store.CreateTask(task); err != nil {
+				t.Fatalf("CreateTask: %v", err)
+			}
+			if tc.persisted {
+				if err := store.SetNextRun(task.ID, &at); err != nil {
+					t.Fatalf("SetNextRun: %v", err)
+				}
+			}
+
+			rec := newFireRecorder()
+			s := New(slog.Default(), store, rec.execute)
+			if err := s.Start(context.Background()); err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			rec.wait(t)
+			s.Stop()
+
+			data := rec.payloads[0]
+			if late, _ := data["late"].(bool); !late || data["scheduled_for"] != at.Format(time.RFC3339) {
+				t.Errorf("payload = %+v, want a late annotation for %v", data, at)
+			}
+			got, err := store.GetTask(task.ID)
+			if err != nil {
+				t.Fatalf("GetTask: %v", err)
+			}
+			if got.Enabled || got.CompletedAt == nil {
+				t.Errorf("task = enabled %v, completed %v; want completed after the late run", got.Enabled, got.CompletedAt)
+			}
+			execs, err := store.ListExecutions(task.ID, 10)
+			if err != nil {
+				t.Fatalf("ListExecutions: %v", err)
+			}
+			for _, e := range execs {
+				if e.Status == StatusSkipped {
+					t.Errorf("executions = %+v, want no skipped record", execs)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulerAt_StaysEnabledAfterFiring(t *testing.T) {
+	store := newTestStore(t)
+	rec := newFireRecorder()
+	s := New(slog.Default(), store, rec.execute)
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	at := time.Now().Add(50 * time.Millisecond)
+	task := &Task{
+		Name:     "porch_lights_off",
+		Schedule: Schedule{Kind: ScheduleAt, At: &at},
+		Payload:  Payload{Kind: PayloadWake, Data: map[string]any{"message": "turn off the porch lights"}},
+		Enabled:  true,
+	}
+	if err := s.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	rec.wait(t)
+	s.Stop()
+
+	got, err := store.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if !got.Enabled || got.CompletedAt != nil || got.NextRunAt != nil {
+		t.Errorf("task = enabled %v, completed %v, next %v; want enabled, not completed, nothing armed", got.Enabled, got.CompletedAt, got.NextRunAt)
+	}
+}
+
+func TestSchedulerAt_MissedRunFollowsCatchUpWindow(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		missedBy time.Duration
+		wantFire bool
+	}{
+		{name: "within window", missedBy: 30 * time.Minute, wantFire: true},
+		{name: "beyond window", missedBy: 3 * missedRunWindow, wantFire: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newTestStore(t)
+			at := time.Now().Add(-tc.missedBy)
+			task := &Task{
+				Name:     "porch_lights_off",
+				Schedule: Schedule{Kind: ScheduleAt, At: &at},
+				Payload:  Payload{Kind: PayloadWake, Data: map[string]any{"message": "turn off the porch lights"}},
+				Enabled:  true,
+			}
+			if err := store.CreateTask(task); err != nil {
+				t.Fatalf("CreateTask: %v", err)
+			}
+			if err := store.SetNextRun(task.ID, &at); err != nil {
+				t.Fatalf("SetNextRun: %v", err)
+			}
+
+			rec := newFireRecorder()
+			s := New(slog.Default(), store, rec.execute)
+			if err := s.Start(context.Background()); err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			if tc.wantFire {
+				rec.wait(t)
+			}
+			s.Stop()
+
+			rec.mu.Lock()
+			fired, payloads := len(rec.fired), rec.payloads
+			rec.mu.Unlock()
+			if tc.wantFire {
+				if fired != 1 {
+					t.Fatalf("fired %d times, want one catch-up run", fired)
+				}
+				if _, ok := payloads[0]["late"]; ok {
+					t.Errorf("payload = %+v, want no late annotation on an at task", payloads[0])
+				}
+			} else {
+				if fired != 0 {
+					t.Errorf("fired %d times, want the stale run skipped", fired)
+				}
+				execs, err := store.ListExecutions(task.ID, 10)
+				if err != nil {
+					t.Fatalf("ListExecutions: %v", err)
+				}
+				if len(execs) != 1 || execs[0].Status != StatusSkipped {
+					t.Errorf("executions = %+v, want one skipped record", execs)
+				}
+			}
+			got, err := store.GetTask(task.ID)
+			if err != nil {
+				t.Fatalf("GetTask: %v", err)
+			}
+			if !got.Enabled || got.CompletedAt != nil {
+				t.Errorf("task = enabled %v, completed %v; want it left enabled", got.Enabled, got.CompletedAt)
+			}
+		})
+	}
+}
@@ -20,6 +20,12 @@ var schema = database.Schema{
 				updated_at TEXT NOT NULL
 			)`,
 		},
+		// next_run_at holds the time the task's timer is armed for, so a
+		// restart can tell which fire it missed.
+		database.ColumnAdd{Table: "tasks", Column: "next_run_at", Typedef: "TEXT"},
+		// completed_at marks a one-shot task that has fired. Completed
+		// tasks stay in the table for audit.
+		database.ColumnAdd{Table: "tasks", Column: "completed_at", Typedef: "TEXT"},
 		database.TableCreate{
 			Table: "executions",
 			SQL: `CREATE TABLE IF NOT EXISTS executions (
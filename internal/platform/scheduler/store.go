@@ -72,7 +72,7 @@ func (s *Store) CreateTask(t *Task) error {
 // GetTask retrieves a task by ID.
 func (s *Store) GetTask(id string) (*Task, error) {
 	row := s.db.QueryRow(`
-		SELECT id, name, schedule_json, payload_json, enabled, created_at, created_by, updated_at
+		SELECT id, name, schedule_json, payload_json, enabled, created_at, created_by, updated_at, next_run_at, completed_at
 		FROM tasks WHERE id = ?
 	`, id)
 
@@ -85,7 +85,7 @@ func (s *Store) GetTask(id string) (*Task, error) {
 // returns an error to surface the data integrity problem.
 func (s *Store) GetTaskByName(name string) (*Task, error) {
 	rows, err := s.db.Query(`
-		SELECT id, name, schedule_json, payload_json, enabled, created_at, created_by, updated_at
+		SELECT id, name, schedule_json, payload_json, enabled, created_at, created_by, updated_at, next_run_at, completed_at
 		FROM tasks WHERE name = ? ORDER BY updated_at DESC
 	`, name)
 	if err != nil {
@@ -111,7 +111,7 @@ func (s *Store) GetTaskByName(name string) (*Task, error) {
 
 // ListTasks returns all tasks, optionally filtered by enabled status.
 func (s *Store) ListTasks(enabledOnly bool) ([]*Task, error) {
-	query := `SELECT id, name, schedule_json, payload_json, enabled, created_at, created_by, updated_at FROM tasks`
+	query := `SELECT id, name, schedule_json, payload_json, enabled, created_at, created_by, updated_at, next_run_at, completed_at FROM tasks`
 	if enabledOnly {
 		query += ` WHERE enabled = 1`
 	}
@@ -163,6 +163,28 @@ func (s *Store) UpdateTask(t *Task) error {
 	return err
 }
 
+// SetNextRun records when a task's timer is next armed to fire. A nil
+// next clears it.
+func (s *Store) SetNextRun(id string, next *time.Time) error {
+	var nextRunAt *string
+	if next != nil {
+		v := next.Format(time.RFC3339Nano)
+		nextRunAt = &v
+	}
+	_, err := s.db.Exec(`UPDATE tasks SET next_run_at = ? WHERE id = ?`, nextRunAt, id)
+	return err
+}
+
+// CompleteTask marks a one-shot task as done: it is disabled so it
+// never loads or fires again, but kept with its execution history.
+func (s *Store) CompleteTask(id string, at time.Time) error {
+	_, err := s.db.Exec(`
+		UPDATE tasks SET enabled = 0, next_run_at = NULL, completed_at = ?, updated_at = ?
+		WHERE id = ?
+	`, at.Format(time.RFC3339Nano), time.Now().Format(time.RFC3339Nano), id)
+	return err
+}
+
 // DeleteTask removes a task and its executions.
 func (s *Store) DeleteTask(id string) error {
 	_, err := s.db.Exec(`DELETE FROM tasks WHERE id = ?`, id)
@@ -282,8 +304,9 @@ func (s *Store) scanTask(row *sql.Row) (*Task, error) {
 	var scheduleJSON, payloadJSON string
 	var enabled int
 	var createdAt, updatedAt string
+	var nextRunAt, completedAt sql.NullString
 
-	err := row.Scan(&t.ID, &t.Name, &scheduleJSON, &payloadJSON, &enabled, &createdAt, &t.CreatedBy, &updatedAt)
+	err := row.Scan(&t.ID, &t.Name, &scheduleJSON, &payloadJSON, &enabled, &createdAt, &t.CreatedBy, &updatedAt, &nextRunAt, &completedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -302,6 +325,20 @@ func (s *Store) scanTask(row *sql.Row) (*Task, error) {
 	if t.UpdatedAt, err = database.ParseTimestamp(updatedAt); err != nil {
 		return nil, fmt.Errorf("parse updated_at: %w", err)
 	}
+	if nextRunAt.Valid {
+		ts, tsErr := database.ParseTimestamp(nextRunAt.String)
+		if tsErr != nil {
+			return nil, fmt.Errorf("parse next_run_at: %w", tsErr)
+		}
+		t.NextRunAt = &ts
+	}
+	if completedAt.Valid {
+		ts, tsErr := database.ParseTimestamp(completedAt.String)
+		if tsErr != nil {
+			return nil, fmt.Errorf("parse completed_at: %w", tsErr)
+		}
+		t.CompletedAt = &ts
+	}
 
 	return &t, nil
 }
@@ -311,8 +348,9 @@ func (s *Store) scanTaskRow(rows *sql.Rows) (*Task, error) {
 	var scheduleJSON, payloadJSON string
 	var enabled int
 	var createdAt, updatedAt string
+	var nextRunAt, completedAt sql.NullString
 
-	err := rows.Scan(&t.ID, &t.Name, &scheduleJSON, &payloadJSON, &enabled, &createdAt, &t.CreatedBy, &updatedAt)
+	err := rows.Scan(&t.ID, &t.Name, &scheduleJSON, &payloadJSON, &enabled, &createdAt, &t.CreatedBy, &updatedAt, &nextRunAt, &completedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -331,6 +369,20 @@ func (s *Store) scanTaskRow(rows *sql.Rows) (*Task, error) {
 	if t.UpdatedAt, err = database.ParseTimestamp(updatedAt); err != nil {
 		return nil, fmt.Errorf("parse updated_at: %w", err)
 	}
+	if nextRunAt.Valid {
+		ts, tsErr := database.ParseTimestamp(nextRunAt.String)
+		if tsErr != nil {
+			return nil, fmt.Errorf("parse next_run_at: %w", tsErr)
+		}
+		t.NextRunAt = &ts
+	}
+	if completedAt.Valid {
+		ts, tsErr := database.ParseTimestamp(completedAt.String)
+		if tsErr != nil {
+			return nil, fmt.Errorf("parse completed_at: %w", tsErr)
+		}
+		t.CompletedAt = &ts
+	}
 
 	return &t, nil
 }
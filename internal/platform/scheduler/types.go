@@ -2,6 +2,7 @@
 package scheduler
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -15,22 +16,76 @@ type Task struct {
 	CreatedAt time.Time `json:"created_at"`
 	CreatedBy string    `json:"created_by"` // Session or user ID
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// NextRunAt is the persisted time of the next scheduled fire, set
+	// when the scheduler arms the task's timer. After downtime it tells
+	// the scheduler which fire was missed, so the task neither skips it
+	// nor runs it twice. Nil when the task is not armed.
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+
+	// CompletedAt is when a one-shot ([ScheduleOnce]) task fired. A
+	// completed task is disabled and kept for audit rather than
+	// deleted. Nil for recurring and not-yet-fired tasks.
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
 }
 
 // Schedule defines when a task should run.
 type Schedule struct {
 	Kind     ScheduleKind `json:"kind"`
-	At       *time.Time   `json:"at,omitempty"`       // For "at" kind
+	At       *time.Time   `json:"at,omitempty"`       // For "at" and "once" kinds
 	Every    *Duration    `json:"every,omitempty"`    // For "every" kind
 	Cron     string       `json:"cron,omitempty"`     // For "cron" kind
 	Timezone string       `json:"timezone,omitempty"` // IANA timezone
 }
 
+// Validate checks that the schedule carries what its kind needs and
+// that any cron expression and timezone parse.
+func (s Schedule) Validate() error {
+	switch s.Kind {
+	case ScheduleAt, ScheduleOnce:
+		if s.At == nil {
+			return fmt.Errorf("schedule kind %q requires at", s.Kind)
+		}
+	case ScheduleEvery:
+		if s.Every == nil || s.Every.Duration <= 0 {
+			return fmt.Errorf("schedule kind %q requires a positive every", s.Kind)
+		}
+	case ScheduleCron:
+		if _, err := ParseCron(s.Cron); err != nil {
+			return fmt.Errorf("invalid cron expression: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown schedule kind %q", s.Kind)
+	}
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", s.Timezone, err)
+		}
+	}
+	return nil
+}
+
+// Location returns the timezone cron expressions are evaluated in:
+// Timezone when set and valid, else local time.
+func (s Schedule) Location() *time.Location {
+	if s.Timezone != "" {
+		if loc, err := time.LoadLocation(s.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
 // ScheduleKind identifies the schedule type.
 type ScheduleKind string
 
+// ScheduleAt and ScheduleOnce both fire once at Schedule.At. An "at"
+// task stays enabled after firing and follows the catch-up window like
+// any other task. A "once" task is completed after firing and kept for
+// audit, and one missed during downtime always fires, late.
 const (
-	ScheduleAt    ScheduleKind = "at"    // One-shot at specific time
+	ScheduleAt    ScheduleKind = "at"    // Fire at specific time
+	ScheduleOnce  ScheduleKind = "once"  // Fire at specific time, then complete
 	ScheduleEvery ScheduleKind = "every" // Recurring interval
 	ScheduleCron  ScheduleKind = "cron"  // Cron expression
 )
@@ -71,10 +126,12 @@ type Payload struct {
 type PayloadKind string
 
 const (
-	PayloadWake       PayloadKind = "wake"       // Wake the agent with a message
-	PayloadService    PayloadKind = "service"    // Call an HA service
-	PayloadAutomation PayloadKind = "automation" // Trigger an HA automation
-	PayloadWebhook    PayloadKind = "webhook"    // Call external webhook
+	PayloadWake        PayloadKind = "wake"        // Wake the agent with a message
+	PayloadService     PayloadKind = "service"     // Call an HA service
+	PayloadAutomation  PayloadKind = "automation"  // Trigger an HA automation
+	PayloadWebhook     PayloadKind = "webhook"     // Call external webhook
+	PayloadUsageCheck  PayloadKind = "usage_check" // Check spend against usage alert budgets
+	PayloadMaintenance PayloadKind = "maintenance" // Run the built-in housekeeping job named by Target
 )
 
 // Execution represents a single run of a task.
@@ -102,7 +159,7 @@ const (
 // NextRun calculates the next execution time for a task.
 func (t *Task) NextRun(after time.Time) (time.Time, bool) {
 	switch t.Schedule.Kind {
-	case ScheduleAt:
+	case ScheduleAt, ScheduleOnce:
 		if t.Schedule.At != nil && t.Schedule.At.After(after) {
 			return *t.Schedule.At, true
 		}
@@ -128,9 +185,11 @@ func (t *Task) NextRun(after time.Time) (time.Time, bool) {
 		return next, true
 
 	case ScheduleCron:
-		// TODO: Implement cron parsing
-		// For now, return false
-		return time.Time{}, false
+		expr, err := ParseCron(t.Schedule.Cron)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return expr.Next(after, t.Schedule.Location())
 
 	default:
 		return time.Time{}, false
@@ -82,13 +82,17 @@ func sanitizeLoopName(name string) string {
 	return r.Replace(name)
 }
 
+// LoopSensorPrefix begins the entity suffix of every per-loop sensor
+// built by [SensorBuilder.LoopSensors].
+const LoopSensorPrefix = "loop_"
+
 // LoopSensors returns sensor definitions for a single named loop.
 // Two sensors per loop: state (enum) and iterations (measurement).
 // Loop names are sanitized to avoid MQTT topic separator conflicts.
 func (b *SensorBuilder) LoopSensors(loopName string) []mqtt.DynamicSensor {
 	slug := sanitizeLoopName(loopName)
-	stateSuffix := "loop_" + slug + "_state"
-	iterSuffix := "loop_" + slug + "_iterations"
+	stateSuffix := LoopSensorPrefix + slug + "_state"
+	iterSuffix := LoopSensorPrefix + slug + "_iterations"
 
 	return []mqtt.DynamicSensor{
 		b.buildSensor(sensorSpec{
@@ -0,0 +1,191 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// alertNamespace is the opstate namespace that records which budget
+// thresholds have already fired in each period.
+const alertNamespace = "usage_alerts"
+
+// Budget periods an [Alert] can report on.
+const (
+	PeriodDaily   = "daily"
+	PeriodMonthly = "monthly"
+)
+
+// Alert reports that spend in a budget period crossed a threshold.
+type Alert struct {
+	Period      string    `json:"period"`       // PeriodDaily or PeriodMonthly
+	PeriodStart time.Time `json:"period_start"` // local midnight or the first of the month
+	Threshold   float64   `json:"threshold"`    // percent of the budget, e.g. 80
+	SpentUSD    float64   `json:"spent_usd"`
+	BudgetUSD   float64   `json:"budget_usd"`
+}
+
+// Message renders the alert as a one-line human-readable notice.
+func (a Alert) Message() string {
+	return fmt.Sprintf("%s LLM spend of $%.2f has reached %s of the $%.2f %s budget.",
+		periodLabel(a.Period), a.SpentUSD, formatPercent(a.Threshold), a.BudgetUSD, a.Period)
+}
+
+func periodLabel(period string) string {
+	if period == PeriodMonthly {
+		return "This month's"
+	}
+	return "Today's"
+}
+
+func formatPercent(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64) + "%"
+}
+
+// AlertState is the subset of opstate.Store the watcher uses to
+// remember fired thresholds across restarts.
+type AlertState interface {
+	Get(namespace, key string) (string, error)
+	SetWithTTL(namespace, key, value string, ttl time.Duration) error
+}
+
+// AlertNotifier delivers an alert through one channel (Signal, MQTT).
+type AlertNotifier func(ctx context.Context, alert Alert) error
+
+// WatcherConfig configures a [Watcher].
+type WatcherConfig struct {
+	// DailyUSD and MonthlyUSD are the budgets thresholds are measured
+	// against. Zero disables alerts for that period.
+	DailyUSD   float64
+	MonthlyUSD float64
+
+	// Thresholds are percentages of a budget, e.g. 50, 80, 100.
+	Thresholds []float64
+
+	// Location is the timezone periods start in. Nil means local time.
+	Location *time.Location
+
+	// Spend returns total spend in USD for [start, end).
+	Spend func(start, end time.Time) (float64, error)
+
+	State     AlertState
+	Notifiers []AlertNotifier
+	Logger    *slog.Logger
+}
+
+// Watcher compares spend against budget thresholds and notifies when
+// one is crossed. Each threshold fires at most once per period: the
+// highest threshold already reported is recorded in opstate under the
+// period's key, so restarts and repeated checks stay quiet until spend
+// crosses the next threshold or a new period begins. Check is meant to
+// be driven periodically, e.g. by a scheduler task.
+type Watcher struct {
+	cfg WatcherConfig
+	now func() time.Time
+}
+
+// NewWatcher creates a usage watcher. Thresholds are sorted ascending.
+func NewWatcher(cfg WatcherConfig) *Watcher {
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.Local
+	}
+	cfg.Thresholds = slices.Clone(cfg.Thresholds)
+	slices.Sort(cfg.Thresholds)
+	return &Watcher{cfg: cfg, now: time.Now}
+}
+
+// Check measures spend for each budgeted period and sends at most one
+// alert per period: for the highest threshold crossed that has not yet
+// fired. A threshold is recorded as fired only when at least one
+// notifier delivered it, so an alert that reached no channel is
+// retried on the next check.
+func (w *Watcher) Check(ctx context.Context) error {
+	now := w.now()
+	local := now.In(w.cfg.Location)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, w.cfg.Location)
+	monthStart := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, w.cfg.Location)
+
+	var errs []error
+	if w.cfg.DailyUSD > 0 {
+		key := PeriodDaily + ":" + dayStart.Format("2006-01-02")
+		errs = append(errs, w.checkPeriod(ctx, PeriodDaily, key, dayStart, now, w.cfg.DailyUSD, 48*time.Hour))
+	}
+	if w.cfg.MonthlyUSD > 0 {
+		key := PeriodMonthly + ":" + monthStart.Format("2006-01")
+		errs = append(errs, w.checkPeriod(ctx, PeriodMonthly, key, monthStart, now, w.cfg.MonthlyUSD, 32*24*time.Hour))
+	}
+	return errors.Join(errs...)
+}
+
+// checkPeriod handles one budget period. ttl keeps the fired marker
+// past the end of its period and lets opstate expire it afterwards.
+func (w *Watcher) checkPeriod(ctx context.Context, period, key string, start, now time.Time, budget float64, ttl time.Duration) error {
+	spent, err := w.cfg.Spend(start, now)
+	if err != nil {
+		return fmt.Errorf("%s spend: %w", period, err)
+	}
+
+	crossed := -1
+	for i, t := range w.cfg.Thresholds {
+		if spent >= budget*t/100 {
+			crossed = i
+		}
+	}
+	if crossed < 0 {
+		return nil
+	}
+	threshold := w.cfg.Thresholds[crossed]
+
+	raw, err := w.cfg.State.Get(alertNamespace, key)
+	if err != nil {
+		return fmt.Errorf("%s alert state: %w", period, err)
+	}
+	if raw != "" {
+		if fired, err := strconv.ParseFloat(raw, 64); err == nil && fired >= threshold {
+			return nil
+		}
+	}
+
+	alert := Alert{
+		Period:      period,
+		PeriodStart: start,
+		Threshold:   threshold,
+		SpentUSD:    spent,
+		BudgetUSD:   budget,
+	}
+	delivered := 0
+	var errs []error
+	for _, notify := range w.cfg.Notifiers {
+		if err := notify(ctx, alert); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		delivered++
+	}
+	if delivered == 0 {
+		errs = append(errs, fmt.Errorf("%s %s alert not delivered", period, formatPercent(threshold)))
+		return errors.Join(errs...)
+	}
+	for _, err := range errs {
+		w.cfg.Logger.Warn("usage alert channel failed", "period", period, "threshold", threshold, "error", err)
+	}
+
+	w.cfg.Logger.Info("usage budget alert sent",
+		"period", period,
+		"threshold", threshold,
+		"spent_usd", spent,
+		"budget_usd", budget,
+		"channels", delivered,
+	)
+	if err := w.cfg.State.SetWithTTL(alertNamespace, key, strconv.FormatFloat(threshold, 'f', -1, 64), ttl); err != nil {
+		return fmt.Errorf("%s alert state: %w", period, err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memAlertState is an in-memory AlertState.
+type memAlertState map[string]string
+
+func (m memAlertState) Get(namespace, key string) (string, error) {
+	return m[namespace+"/"+key], nil
+}
+
+func (m memAlertState) SetWithTTL(namespace, key, value string, _ time.Duration) error {
+	m[namespace+"/"+key] = value
+	return nil
+}
+
+type alertHarness struct {
+	watcher *Watcher
+	state   memAlertState
+	spend   map[string]float64 // period start (RFC 3339) → spend
+	sent    []Alert
+	fail    error
+}
+
+func newAlertHarness(daily, monthly float64) *alertHarness {
+	h := &alertHarness{state: memAlertState{}, spend: map[string]float64{}}
+	h.watcher = NewWatcher(WatcherConfig{
+		DailyUSD:   daily,
+		MonthlyUSD: monthly,
+		Thresholds: []float64{100, 50, 80},
+		Location:   time.UTC,
+		Spend: func(start, _ time.Time) (float64, error) {
+			return h.spend[start.Format(time.RFC3339)], nil
+		},
+		State: h.state,
+		Notifiers: []AlertNotifier{func(_ context.Context, a Alert) error {
+			if h.fail != nil {
+				return h.fail
+			}
+			h.sent = append(h.sent, a)
+			return nil
+		}},
+	})
+	h.watcher.now = func() time.Time { return time.Date(2026, 3, 14, 15, 0, 0, 0, time.UTC) }
+	return h
+}
+
+func (h *alertHarness) check(t *testing.T) {
+	t.Helper()
+	if err := h.watcher.Check(context.Background()); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+}
+
+const (
+	testDay   = "2026-03-14T00:00:00Z"
+	testMonth = "2026-03-01T00:00:00Z"
+)
+
+func TestWatcher_FiresEachThresholdOncePerPeriod(t *testing.T) {
+	h := newAlertHarness(10, 0)
+
+	h.spend[testDay] = 4
+	h.check(t)
+	if len(h.sent) != 0 {
+		t.Fatalf("alerts below every threshold = %v, want none", h.sent)
+	}
+
+	h.spend[testDay] = 5.5
+	h.check(t)
+	h.check(t)
+	if len(h.sent) != 1 || h.sent[0].Threshold != 50 || h.sent[0].Period != PeriodDaily {
+		t.Fatalf("alerts after crossing 50%% = %+v, want one daily 50%% alert", h.sent)
+	}
+
+	// Jumping past two thresholds reports only the highest.
+	h.spend[testDay] = 12
+	h.check(t)
+	h.check(t)
+	if len(h.sent) != 2 || h.sent[1].Threshold != 100 {
+		t.Fatalf("alerts after crossing 100%% = %+v, want a single 100%% alert", h.sent)
+	}
+	if got := h.sent[1].Message(); !strings.Contains(got, "$12.00") || !strings.Contains(got, "100%") {
+		t.Errorf("Message() = %q, want spend and threshold", got)
+	}
+
+	// A new day starts over.
+	h.watcher.now = func() time.Time { return time.Date(2026, 3, 15, 1, 0, 0, 0, time.UTC) }
+	h.spend["2026-03-15T00:00:00Z"] = 6
+	h.check(t)
+	if len(h.sent) != 3 || h.sent[2].Threshold != 50 {
+		t.Fatalf("alerts on the next day = %+v, want a fresh 50%% alert", h.sent)
+	}
+}
+
+func TestWatcher_DailyAndMonthlyAreIndependent(t *testing.T) {
+	h := newAlertHarness(10, 100)
+	h.spend[testDay] = 9
+	h.spend[testMonth] = 85
+	h.check(t)
+
+	if len(h.sent) != 2 {
+		t.Fatalf("alerts = %+v, want one daily and one monthly", h.sent)
+	}
+	if h.sent[0].Period != PeriodDaily || h.sent[0].Threshold != 80 {
+		t.Errorf("daily alert = %+v, want 80%%", h.sent[0])
+	}
+	if h.sent[1].Period != PeriodMonthly || h.sent[1].Threshold != 80 {
+		t.Errorf("monthly alert = %+v, want 80%%", h.sent[1])
+	}
+}
+
+func TestWatcher_UndeliveredAlertIsRetried(t *testing.T) {
+	h := newAlertHarness(10, 0)
+	h.spend[testDay] = 5
+	h.fail = errors.New("signal down")
+
+	if err := h.watcher.Check(context.Background()); err == nil {
+		t.Fatal("Check succeeded with every channel failing")
+	}
+	if len(h.state) != 0 {
+		t.Fatalf("undelivered alert recorded as fired: %v", h.state)
+	}
+
+	h.fail = nil
+	h.check(t)
+	if len(h.sent) != 1 {
+		t.Fatalf("alerts after recovery = %+v, want the retried 50%% alert", h.sent)
+	}
+}
@@ -54,5 +54,14 @@ var schema = database.Schema{
 		// correlation. Rows from before this migration have "" — fine,
 		// the column is informational and never participates in joins.
 		database.ColumnAdd{Table: "usage_records", Column: "upstream_request_id", Typedef: "TEXT NOT NULL DEFAULT ''"},
+		// Failover hops: a "failover" role row per model that errored
+		// and was handed off to the next model in its fallback chain.
+		database.ColumnAdd{Table: "usage_records", Column: "failover_to", Typedef: "TEXT NOT NULL DEFAULT ''"},
+		database.ColumnAdd{Table: "usage_records", Column: "failover_reason", Typedef: "TEXT NOT NULL DEFAULT ''"},
+		// Model that failed before a failover row's fallback call.
+		database.ColumnAdd{Table: "usage_records", Column: "failover_from", Typedef: "TEXT NOT NULL DEFAULT ''"},
+		// Version tag of the prompt template behind internal calls
+		// (fact extraction, session metadata); see prompts.Registry.
+		database.ColumnAdd{Table: "usage_records", Column: "prompt_version", Typedef: "TEXT NOT NULL DEFAULT ''"},
 	},
 }
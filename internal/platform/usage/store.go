@@ -48,8 +48,20 @@ type Record struct {
 	CacheCreation1hInputTokens int
 	CacheReadInputTokens       int
 	CostUSD                    float64
-	Role                       string // "interactive", "delegate", "scheduled", "auxiliary"
+	Role                       string // "interactive", "delegate", "scheduled", "auxiliary", "recovery", "failover"
 	TaskName                   string // "email_poll", "periodic_reflection", etc. (empty for interactive)
+	// FailoverFrom, FailoverTo, and FailoverReason are set on
+	// "failover" records, one per fallback call: FailoverFrom failed
+	// with FailoverReason and the request moved on to FailoverTo, which
+	// is also the record's Model. The record carries that call's tokens
+	// and cost; a fallback call that failed in turn has none.
+	FailoverFrom   string
+	FailoverTo     string
+	FailoverReason string
+	// PromptVersion is the version tag of the prompt template behind
+	// an internal call, such as fact extraction, so output quality can
+	// be compared across prompt revisions. Empty for agent runs.
+	PromptVersion string
 }
 
 // ModelIdentity is the normalized usage-facing identity for a selected
@@ -130,8 +142,9 @@ func (s *Store) Record(ctx context.Context, rec Record) error {
 		`INSERT INTO usage_records
 			(id, timestamp, request_id, upstream_request_id, session_id, conversation_id, model, upstream_model, resource, provider,
 			 input_tokens, output_tokens, cache_creation_input_tokens, cache_creation_5m_input_tokens,
-			 cache_creation_1h_input_tokens, cache_read_input_tokens, cost_usd, role, task_name)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			 cache_creation_1h_input_tokens, cache_read_input_tokens, cost_usd, role, task_name,
+			 failover_from, failover_to, failover_reason, prompt_version)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		rec.ID,
 		rec.Timestamp.UTC().Format(time.RFC3339),
 		rec.RequestID,
@@ -151,6 +164,10 @@ func (s *Store) Record(ctx context.Context, rec Record) error {
 		rec.CostUSD,
 		rec.Role,
 		rec.TaskName,
+		rec.FailoverFrom,
+		rec.FailoverTo,
+		rec.FailoverReason,
+		rec.PromptVersion,
 	)
 	if err != nil {
 		return fmt.Errorf("insert usage record: %w", err)
@@ -214,6 +231,28 @@ func (s *Store) SummaryByTask(start, end time.Time) ([]GroupedSummary, error) {
 	return s.summaryGroupedBy("task_name", start, end)
 }
 
+// SummaryByFailoverReason returns per-reason totals for records within
+// [start, end), ordered by cost descending. Ordinary usage records are
+// grouped under the key "".
+func (s *Store) SummaryByFailoverReason(start, end time.Time) ([]GroupedSummary, error) {
+	return s.summaryGroupedBy("failover_reason", start, end)
+}
+
+// SummaryByFailoverFrom returns per-model totals of the fallback calls
+// made when that model failed, for records within [start, end),
+// ordered by cost descending. Ordinary usage records are grouped under
+// the key "".
+func (s *Store) SummaryByFailoverFrom(start, end time.Time) ([]GroupedSummary, error) {
+	return s.summaryGroupedBy("failover_from", start, end)
+}
+
+// SummaryByPromptVersion returns per-prompt-version totals for records
+// within [start, end), ordered by cost descending. Records without a
+// prompt version are grouped under the key "".
+func (s *Store) SummaryByPromptVersion(start, end time.Time) ([]GroupedSummary, error) {
+	return s.summaryGroupedBy("prompt_version", start, end)
+}
+
 // SummaryByGroup dispatches the grouped summary query based on the
 // caller-provided grouping key.
 func (s *Store) SummaryByGroup(groupBy string, start, end time.Time) ([]GroupedSummary, error) {
@@ -230,9 +269,45 @@ func (s *Store) SummaryByGroup(groupBy string, start, end time.Time) ([]GroupedS
 		return s.SummaryByRole(start, end)
 	case "task":
 		return s.SummaryByTask(start, end)
+	case "failover_reason":
+		return s.SummaryByFailoverReason(start, end)
+	case "failover_from":
+		return s.SummaryByFailoverFrom(start, end)
+	case "prompt_version":
+		return s.SummaryByPromptVersion(start, end)
 	default:
-		return nil, fmt.Errorf("unsupported group_by %q; use one of [\"deployment\" \"model\" \"upstream_model\" \"provider\" \"resource\" \"role\" \"task\"]", groupBy)
+		return nil, fmt.Errorf("unsupported group_by %q; use one of [\"deployment\" \"model\" \"upstream_model\" \"provider\" \"resource\" \"role\" \"task\" \"failover_reason\" \"failover_from\" \"prompt_version\"]", groupBy)
+	}
+}
+
+// SummaryByDay returns one summary per calendar day in loc that
+// overlaps [start, end), oldest first, keyed "2006-01-02". Days with no
+// usage are included with zero totals so the result is a gapless time
+// series. The first and last days are clipped to the window.
+func (s *Store) SummaryByDay(start, end time.Time, loc *time.Location) ([]GroupedSummary, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	var result []GroupedSummary
+	local := start.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	for day.Before(end) {
+		next := day.AddDate(0, 0, 1)
+		from, to := day, next
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		summary, err := s.Summary(from, to)
+		if err != nil {
+			return nil, fmt.Errorf("query usage for %s: %w", day.Format("2006-01-02"), err)
+		}
+		result = append(result, GroupedSummary{Key: day.Format("2006-01-02"), Summary: *summary})
+		day = next
 	}
+	return result, nil
 }
 
 func (s *Store) summaryGroupedBy(column string, start, end time.Time) ([]GroupedSummary, error) {
@@ -323,6 +398,12 @@ const (
 	// default when the provider doesn't attribute the writes.
 	anthropicCacheWriteMultiplier = anthropicCacheWrite5mMultiplier
 	anthropicCacheReadMultiplier  = 0.10
+
+	// openAICacheReadMultiplier is OpenAI's cached-input discount for
+	// the GPT-4o generation. Newer models discount further; set
+	// cache_read_per_million for those. OpenAI caches automatically and
+	// charges nothing extra to write.
+	openAICacheReadMultiplier = 0.50
 )
 
 // ComputeDetailedCostForIdentity calculates USD cost for a resolved model
@@ -343,7 +424,8 @@ func ComputeDetailedCostForIdentity(identity ModelIdentity, inputTokens, cacheCr
 // exposes it. The unattributed portion of cacheCreationInputTokens
 // (that is, tokens not accounted for in the 5m or 1h buckets) is
 // charged at the 5m rate to avoid retroactive price spikes on legacy
-// records.
+// records. Cache traffic uses the pricing entry's cache rates, see
+// [cacheRates].
 func ComputeDetailedCostForIdentityWithTTL(identity ModelIdentity, inputTokens, cacheCreationTotal, cacheCreation5m, cacheCreation1h, cacheReadInputTokens, outputTokens int, pricing map[string]config.PricingEntry) float64 {
 	if len(pricing) == 0 {
 		return 0
@@ -369,16 +451,41 @@ func ComputeDetailedCostForIdentityWithTTL(identity ModelIdentity, inputTokens,
 		if unattributed < 0 {
 			unattributed = 0
 		}
-		cost += float64(cacheCreation5m+unattributed) / 1_000_000.0 * (entry.InputPerMillion * anthropicCacheWrite5mMultiplier)
-		cost += float64(cacheCreation1h) / 1_000_000.0 * (entry.InputPerMillion * anthropicCacheWrite1hMultiplier)
+		read, write5m, write1h := cacheRates(entry, identity.Provider)
+		cost += float64(cacheCreation5m+unattributed) / 1_000_000.0 * write5m
+		cost += float64(cacheCreation1h) / 1_000_000.0 * write1h
 
-		cost += float64(cacheReadInputTokens) / 1_000_000.0 * (entry.InputPerMillion * anthropicCacheReadMultiplier)
+		cost += float64(cacheReadInputTokens) / 1_000_000.0 * read
 		cost += float64(outputTokens) / 1_000_000.0 * entry.OutputPerMillion
 		return cost
 	}
 	return 0
 }
 
+// cacheRates returns the per-million-token cache-read, 5m cache-write,
+// and 1h cache-write prices for entry. Rates the entry leaves at zero
+// are derived from its input price using the provider's multipliers:
+// OpenAI's for provider "openai", Anthropic's otherwise.
+func cacheRates(entry config.PricingEntry, provider string) (read, write5m, write1h float64) {
+	readMul, write5mMul, write1hMul := anthropicCacheReadMultiplier, anthropicCacheWrite5mMultiplier, anthropicCacheWrite1hMultiplier
+	if provider == "openai" {
+		readMul, write5mMul, write1hMul = openAICacheReadMultiplier, 1, 1
+	}
+	read = entry.CacheReadPerMillion
+	if read == 0 {
+		read = entry.InputPerMillion * readMul
+	}
+	write5m = entry.CacheWritePerMillion
+	if write5m == 0 {
+		write5m = entry.InputPerMillion * write5mMul
+	}
+	write1h = entry.CacheWrite1hPerMillion
+	if write1h == 0 {
+		write1h = entry.InputPerMillion * write1hMul
+	}
+	return read, write5m, write1h
+}
+
 // ComputeCostForIdentity calculates USD cost for a resolved model
 // identity. The selected deployment ID is checked first, then the
 // upstream model as a fallback so deployment-qualified IDs can reuse
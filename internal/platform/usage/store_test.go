@@ -283,6 +283,48 @@ func TestQueryByPeriod_Filters(t *testing.T) {
 	}
 }
 
+func TestSummaryByDay(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	// UTC-5: 03:00Z on the 16th is still the 15th locally.
+	loc := time.FixedZone("test", -5*60*60)
+	recs := []Record{
+		{Timestamp: time.Date(2025, 6, 15, 14, 0, 0, 0, time.UTC), RequestID: "d1", Model: "m", Provider: "p", Role: "interactive", CostUSD: 1.0},
+		{Timestamp: time.Date(2025, 6, 16, 3, 0, 0, 0, time.UTC), RequestID: "d1-late", Model: "m", Provider: "p", Role: "interactive", CostUSD: 0.5},
+		{Timestamp: time.Date(2025, 6, 17, 18, 0, 0, 0, time.UTC), RequestID: "d3", Model: "m", Provider: "p", Role: "scheduled", CostUSD: 2.0},
+	}
+	for _, rec := range recs {
+		if err := s.Record(ctx, rec); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	start := time.Date(2025, 6, 15, 9, 0, 0, 0, loc)
+	end := time.Date(2025, 6, 17, 23, 0, 0, 0, loc)
+	days, err := s.SummaryByDay(start, end, loc)
+	if err != nil {
+		t.Fatalf("SummaryByDay: %v", err)
+	}
+
+	want := []struct {
+		key  string
+		cost float64
+	}{
+		{"2025-06-15", 1.5},
+		{"2025-06-16", 0},
+		{"2025-06-17", 2.0},
+	}
+	if len(days) != len(want) {
+		t.Fatalf("got %d days, want %d: %+v", len(days), len(want), days)
+	}
+	for i, w := range want {
+		if days[i].Key != w.key || days[i].Summary.TotalCostUSD != w.cost {
+			t.Errorf("day %d = %s $%.2f, want %s $%.2f", i, days[i].Key, days[i].Summary.TotalCostUSD, w.key, w.cost)
+		}
+	}
+}
+
 func TestSummary_EmptyDB(t *testing.T) {
 	s := testStore(t)
 
@@ -537,6 +579,54 @@ func TestComputeDetailedCostForIdentityWithTTL_UnattributedFallsBackTo5m(t *test
 	}
 }
 
+func TestComputeDetailedCostForIdentityWithTTL_ExplicitCacheRates(t *testing.T) {
+	pricing := map[string]config.PricingEntry{
+		"claude-opus-4-8": {
+			InputPerMillion:        5.0,
+			OutputPerMillion:       25.0,
+			CacheReadPerMillion:    0.4,
+			CacheWritePerMillion:   6.0,
+			CacheWrite1hPerMillion: 9.0,
+		},
+		// Only the read rate set; writes derive from input.
+		"claude-sonnet-4-6": {InputPerMillion: 3.0, OutputPerMillion: 15.0, CacheReadPerMillion: 0.25},
+	}
+
+	got := ComputeDetailedCostForIdentityWithTTL(ModelIdentity{Model: "claude-opus-4-8"},
+		1_000_000, 2_000_000, 1_000_000, 1_000_000, 1_000_000, 100_000, pricing)
+	want := 5.0 + 6.0 + 9.0 + 0.4 + 2.5
+	if diff := got - want; diff > 0.0001 || diff < -0.0001 {
+		t.Errorf("explicit rates cost = %f, want %f", got, want)
+	}
+
+	got = ComputeDetailedCostForIdentityWithTTL(ModelIdentity{Model: "claude-sonnet-4-6"},
+		0, 2_000_000, 1_000_000, 1_000_000, 1_000_000, 0, pricing)
+	want = (3.0 * 1.25) + (3.0 * 2.00) + 0.25
+	if diff := got - want; diff > 0.0001 || diff < -0.0001 {
+		t.Errorf("partial rates cost = %f, want %f", got, want)
+	}
+}
+
+func TestComputeDetailedCostForIdentityWithTTL_OpenAICacheRates(t *testing.T) {
+	pricing := map[string]config.PricingEntry{
+		"gpt-4o":  {InputPerMillion: 2.5, OutputPerMillion: 10.0},
+		"gpt-5.1": {InputPerMillion: 1.25, OutputPerMillion: 10.0, CacheReadPerMillion: 0.125},
+	}
+
+	got := ComputeDetailedCostForIdentityWithTTL(ModelIdentity{Model: "gpt-4o", Provider: "openai"},
+		1_000_000, 0, 0, 0, 1_000_000, 0, pricing)
+	want := 2.5 + 2.5*0.5
+	if diff := got - want; diff > 0.0001 || diff < -0.0001 {
+		t.Errorf("default OpenAI cache cost = %f, want %f", got, want)
+	}
+
+	got = ComputeDetailedCostForIdentityWithTTL(ModelIdentity{Model: "gpt-5.1", Provider: "openai"},
+		0, 0, 0, 0, 1_000_000, 0, pricing)
+	if want := 0.125; got < want-0.0001 || got > want+0.0001 {
+		t.Errorf("explicit OpenAI cache read cost = %f, want %f", got, want)
+	}
+}
+
 func TestComputeCost_NilPricing(t *testing.T) {
 	got := ComputeCost("claude-opus-4-20250514", 1000, 500, nil)
 	if got != 0 {
@@ -778,3 +868,78 @@ func TestMigrate_AddsUpstreamRequestIDColumn(t *testing.T) {
 		t.Fatal("expected upstream_request_id column after migration")
 	}
 }
+
+func TestRecord_PersistsFailoverHop(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	for _, rec := range []Record{
+		{Timestamp: now, RequestID: "r_1", Model: "sonnet", Provider: "anthropic", Role: "failover", InputTokens: 80, OutputTokens: 10, FailoverFrom: "opus", FailoverTo: "sonnet", FailoverReason: "overloaded"},
+		{Timestamp: now, RequestID: "r_1", Model: "sonnet", Provider: "anthropic", Role: "interactive", InputTokens: 100, OutputTokens: 20},
+	} {
+		if err := s.Record(ctx, rec); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	var from, to, reason string
+	row := s.db.QueryRow(`SELECT failover_from, failover_to, failover_reason FROM usage_records WHERE role = 'failover'`)
+	if err := row.Scan(&from, &to, &reason); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if from != "opus" || to != "sonnet" || reason != "overloaded" {
+		t.Errorf("failover_from, failover_to, failover_reason = %q, %q, %q, want opus, sonnet, overloaded", from, to, reason)
+	}
+
+	byFrom, err := s.SummaryByGroup("failover_from", now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SummaryByGroup(failover_from): %v", err)
+	}
+	for _, g := range byFrom {
+		if g.Key == "opus" && g.Summary.TotalInputTokens != 80 {
+			t.Errorf("opus failover input tokens = %d, want 80", g.Summary.TotalInputTokens)
+		}
+	}
+
+	groups, err := s.SummaryByGroup("failover_reason", now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SummaryByGroup: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, g := range groups {
+		counts[g.Key] = g.Summary.TotalRecords
+	}
+	if counts["overloaded"] != 1 || counts[""] != 1 {
+		t.Errorf("records by failover reason = %v, want one overloaded and one ordinary", counts)
+	}
+}
+
+func TestSummaryByGroup_PromptVersion(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	for _, rec := range []Record{
+		{Timestamp: now, RequestID: "r_1", Model: "local", Provider: "ollama", Role: "auxiliary", TaskName: "fact_extraction", PromptVersion: "extract-a"},
+		{Timestamp: now, RequestID: "r_2", Model: "local", Provider: "ollama", Role: "auxiliary", TaskName: "fact_extraction", PromptVersion: "extract-b"},
+		{Timestamp: now, RequestID: "r_3", Model: "local", Provider: "ollama", Role: "auxiliary", TaskName: "fact_extraction", PromptVersion: "extract-b"},
+		{Timestamp: now, RequestID: "r_4", Model: "sonnet", Provider: "anthropic", Role: "interactive"},
+	} {
+		if err := s.Record(ctx, rec); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	groups, err := s.SummaryByGroup("prompt_version", now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SummaryByGroup: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, g := range groups {
+		counts[g.Key] = g.Summary.TotalRecords
+	}
+	if counts["extract-a"] != 1 || counts["extract-b"] != 2 || counts[""] != 1 {
+		t.Errorf("records by prompt version = %v, want extract-a 1, extract-b 2, none 1", counts)
+	}
+}
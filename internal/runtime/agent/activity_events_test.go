@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/platform/events"
+)
+
+func TestRun_PublishesActivityEvents(t *testing.T) {
+	tc := llm.ToolCall{ID: "call-1"}
+	tc.Function.Name = "web_search"
+	tc.Function.Arguments = map[string]any{"query": "weather"}
+	mock := &mockLLM{
+		responses: []*llm.ChatResponse{
+			{
+				Model:        "test-model",
+				Message:      llm.Message{Role: "assistant", ToolCalls: []llm.ToolCall{tc}},
+				InputTokens:  100,
+				OutputTokens: 10,
+			},
+			{
+				Model:        "test-model",
+				Message:      llm.Message{Role: "assistant", Content: "Sunny."},
+				InputTokens:  200,
+				OutputTokens: 5,
+			},
+		},
+	}
+	loop := buildTestLoop(mock, []string{"web_search"})
+	bus := events.New()
+	loop.SetEventBus(bus)
+	ch := bus.Subscribe(64)
+	defer bus.Unsubscribe(ch)
+
+	if _, err := loop.Run(context.Background(), &Request{
+		ConversationID: "conv-events",
+		Messages:       []Message{{Role: "user", Content: "check the weather"}},
+	}, nil); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	var kinds []string
+	var requestIDs = map[string]bool{}
+	for len(ch) > 0 {
+		evt := <-ch
+		if evt.Source != events.SourceAgent {
+			continue
+		}
+		kinds = append(kinds, evt.Kind)
+		if id, _ := evt.Data["request_id"].(string); id != "" {
+			requestIDs[id] = true
+		}
+		if evt.Kind == events.KindToolDone && (evt.Data["tool"] != "web_search" || evt.Data["ok"] != true) {
+			t.Errorf("tool_done data = %v", evt.Data)
+		}
+	}
+	want := "request_start,model_selected,tool_call,tool_done,request_complete"
+	if got := strings.Join(kinds, ","); got != want {
+		t.Errorf("event kinds = %s, want %s", got, want)
+	}
+	if len(requestIDs) != 1 {
+		t.Errorf("request IDs = %v, want every event tied to one request", requestIDs)
+	}
+}
@@ -0,0 +1,178 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/model/prompts"
+	"github.com/nugget/thane-ai-agent/internal/platform/logging"
+)
+
+// confidenceCheckTimeout bounds the rating call so a slow model cannot
+// stall an autonomous run indefinitely before the real tool executes.
+const confidenceCheckTimeout = 30 * time.Second
+
+// ConfidenceGate configures the pre-action confidence check for
+// autonomous runs. Before a tool named in MutatingTools executes on a
+// run whose source is listed in AutonomousSources, the loop asks the
+// model to rate its confidence in the action. The source is the run's
+// origin (see [tools.OriginSource]), so a delegate or fanout branch
+// launched from an autonomous run is gated like the run itself. A rating
+// below Threshold defers the action: the tool does not run, Notify is
+// called so a human can approve it, and the model receives a tool
+// result explaining the deferral. Interactive runs are never gated.
+type ConfidenceGate struct {
+	// Threshold is the minimum self-rated confidence in [0, 1] an
+	// autonomous action needs to proceed unattended.
+	Threshold float64
+
+	// MutatingTools names the tools subject to the gate.
+	MutatingTools []string
+
+	// AutonomousSources lists the "source" routing-hint values that
+	// identify autonomous (non-user) runs.
+	AutonomousSources []string
+
+	// Notify delivers a deferred action to a human for approval. Nil
+	// defers without notifying.
+	Notify func(ctx context.Context, action DeferredAction) error
+}
+
+// DeferredAction describes a mutating tool call the confidence gate
+// withheld pending human approval.
+type DeferredAction struct {
+	ConversationID string  `json:"conversation_id"`
+	Source         string  `json:"source"`
+	Tool           string  `json:"tool"`
+	Arguments      string  `json:"arguments"`
+	Task           string  `json:"task"`
+	Confidence     float64 `json:"confidence"`
+	Threshold      float64 `json:"threshold"`
+}
+
+// Summary renders the action as a short human-readable approval
+// request suitable for a push notification body.
+func (d DeferredAction) Summary() string {
+	return fmt.Sprintf("Thane held back %s (confidence %.2f < %.2f) during an autonomous %s run.\nArguments: %s\nTask: %s",
+		d.Tool, d.Confidence, d.Threshold, d.Source, d.Arguments, truncateRunes(d.Task, 300))
+}
+
+// ConfigureConfidenceGate installs the autonomous-action confidence
+// gate. A nil gate, a non-positive threshold, or an empty tool or
+// source set disables gating.
+func (l *Loop) ConfigureConfidenceGate(g *ConfidenceGate) {
+	if g == nil || g.Threshold <= 0 || len(g.MutatingTools) == 0 || len(g.AutonomousSources) == 0 {
+		l.confidenceGate = nil
+		return
+	}
+	gate := *g
+	gate.MutatingTools = append([]string(nil), g.MutatingTools...)
+	gate.AutonomousSources = append([]string(nil), g.AutonomousSources...)
+	l.confidenceGate = &gate
+}
+
+// confidenceGateApplies reports whether a call to tool on a run that
+// originated from source must pass the confidence check.
+func (l *Loop) confidenceGateApplies(source, tool string) bool {
+	g := l.confidenceGate
+	if g == nil || source == "" {
+		return false
+	}
+	return containsString(g.AutonomousSources, source) && containsString(g.MutatingTools, tool)
+}
+
+// gateAutonomousAction runs the confidence check for one tool call.
+// It returns the deferral tool result and true when the action must
+// not execute; false means the caller should execute the tool.
+func (l *Loop) gateAutonomousAction(ctx context.Context, model string, action DeferredAction) (string, bool) {
+	g := l.confidenceGate
+	log := logging.Logger(ctx)
+
+	confidence, err := l.rateActionConfidence(ctx, model, action)
+	if err != nil {
+		// Fail closed: an unrated autonomous mutation is exactly the
+		// case the gate exists to catch.
+		log.Warn("confidence check failed; deferring action",
+			"tool", action.Tool, "source", action.Source, "error", err)
+		confidence = 0
+	}
+	if confidence >= g.Threshold {
+		log.Debug("confidence gate passed",
+			"tool", action.Tool, "source", action.Source, "confidence", confidence)
+		return "", false
+	}
+
+	action.Confidence = confidence
+	action.Threshold = g.Threshold
+	notifyErr := ""
+	if g.Notify != nil {
+		if err := g.Notify(ctx, action); err != nil {
+			notifyErr = err.Error()
+			log.Error("failed to deliver deferred action for approval",
+				"tool", action.Tool, "error", err)
+		}
+	}
+	log.Info("autonomous action deferred by confidence gate",
+		"tool", action.Tool,
+		"source", action.Source,
+		"conversation_id", action.ConversationID,
+		"confidence", confidence,
+		"threshold", g.Threshold,
+		"notified", g.Notify != nil && notifyErr == "",
+	)
+	return prompts.ConfidenceGateDeferred(action.Tool, confidence, g.Threshold, notifyErr), true
+}
+
+// rateActionConfidence asks the model to score a proposed action.
+func (l *Loop) rateActionConfidence(ctx context.Context, model string, action DeferredAction) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, confidenceCheckTimeout)
+	defer cancel()
+
+	resp, err := l.sideChat(ctx, "confidence_gate", model, []llm.Message{
+		{Role: "system", Content: prompts.ConfidenceGateSystem},
+		{Role: "user", Content: prompts.ConfidenceGatePrompt(action.Task, action.Tool, action.Arguments)},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return parseConfidence(resp.Message.Content)
+}
+
+// confidenceNumberRe finds the first decimal number in a rating reply;
+// models often wrap the bare number in prose despite instructions.
+var confidenceNumberRe = regexp.MustCompile(`\d+(?:\.\d+)?|\.\d+`)
+
+// parseConfidence extracts a confidence score in [0, 1] from a model
+// reply. Percentages ("85" or "85%") are scaled down.
+func parseConfidence(reply string) (float64, error) {
+	match := confidenceNumberRe.FindString(reply)
+	if match == "" {
+		return 0, fmt.Errorf("no confidence score in reply %q", truncateRunes(strings.TrimSpace(reply), 80))
+	}
+	v, err := strconv.ParseFloat(match, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse confidence %q: %w", match, err)
+	}
+	if v > 1 && v <= 100 {
+		v /= 100
+	}
+	if v < 0 || v > 1 {
+		return 0, fmt.Errorf("confidence %v out of range", v)
+	}
+	return v, nil
+}
+
+// truncateRunes caps s at max runes, appending "..." when cut, without
+// splitting multi-byte characters.
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "..."
+}
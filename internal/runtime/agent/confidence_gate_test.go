@@ -0,0 +1,279 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/platform/usage"
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+func gatedToolCallResponse(name string) *llm.ChatResponse {
+	tc := llm.ToolCall{ID: "call-1"}
+	tc.Function.Name = name
+	tc.Function.Arguments = map[string]any{"entity_id": "lock.front_door"}
+	return &llm.ChatResponse{
+		Model:   "test-model",
+		Message: llm.Message{Role: "assistant", ToolCalls: []llm.ToolCall{tc}},
+	}
+}
+
+func textResponse(content string) *llm.ChatResponse {
+	return &llm.ChatResponse{
+		Model:   "test-model",
+		Message: llm.Message{Role: "assistant", Content: content},
+	}
+}
+
+// setupGatedLoop builds a loop with a counting ha_call_service tool and
+// a confidence gate that records every deferred action it is asked to
+// deliver.
+func setupGatedLoop(mock *mockLLM) (*Loop, *int, *[]DeferredAction) {
+	loop := buildTestLoop(mock, nil)
+	executed := 0
+	loop.tools.Register(&tools.Tool{
+		Name:        "ha_call_service",
+		Description: "call a service",
+		Parameters:  map[string]any{"type": "object", "properties": map[string]any{}},
+		Handler: func(context.Context, map[string]any) (string, error) {
+			executed++
+			return "service called", nil
+		},
+	})
+	var notified []DeferredAction
+	loop.ConfigureConfidenceGate(&ConfidenceGate{
+		Threshold:         0.7,
+		MutatingTools:     []string{"ha_call_service"},
+		AutonomousSources: []string{"loop", "scheduler"},
+		Notify: func(_ context.Context, a DeferredAction) error {
+			notified = append(notified, a)
+			return nil
+		},
+	})
+	return loop, &executed, &notified
+}
+
+func toolResultContent(t *testing.T, mock *mockLLM) string {
+	t.Helper()
+	last := mock.calls[len(mock.calls)-1].Messages
+	for i := len(last) - 1; i >= 0; i-- {
+		if last[i].Role == "tool" {
+			return last[i].Content
+		}
+	}
+	t.Fatal("no tool result in final LLM call")
+	return ""
+}
+
+func TestConfidenceGate_LowConfidenceAutonomousActionDeferred(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		gatedToolCallResponse("ha_call_service"),
+		textResponse("0.3"),
+		textResponse("Asked for approval."),
+	}}
+	loop, executed, notified := setupGatedLoop(mock)
+
+	_, err := loop.Run(context.Background(), &Request{
+		ConversationID: "loop-porch",
+		Messages:       []Message{{Role: "user", Content: "Check the porch after sunset"}},
+		RoutingFactors: map[string]string{"source": "loop"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	if *executed != 0 {
+		t.Errorf("tool executed %d times, want 0", *executed)
+	}
+	if len(*notified) != 1 {
+		t.Fatalf("notifications = %d, want 1", len(*notified))
+	}
+	got := (*notified)[0]
+	if got.Tool != "ha_call_service" || got.Source != "loop" || got.ConversationID != "loop-porch" {
+		t.Errorf("deferred action = %+v", got)
+	}
+	if got.Confidence != 0.3 || got.Threshold != 0.7 {
+		t.Errorf("confidence/threshold = %v/%v, want 0.3/0.7", got.Confidence, got.Threshold)
+	}
+	if !strings.Contains(got.Arguments, "lock.front_door") {
+		t.Errorf("arguments = %q, want entity id", got.Arguments)
+	}
+	if result := toolResultContent(t, mock); !strings.Contains(result, "Action deferred") {
+		t.Errorf("tool result = %q, want deferral notice", result)
+	}
+}
+
+func TestConfidenceGate_HighConfidenceAutonomousActionProceeds(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		gatedToolCallResponse("ha_call_service"),
+		textResponse("Confidence: 0.92"),
+		textResponse("Done."),
+	}}
+	loop, executed, notified := setupGatedLoop(mock)
+
+	_, err := loop.Run(context.Background(), &Request{
+		Messages:       []Message{{Role: "user", Content: "Lock up at bedtime"}},
+		RoutingFactors: map[string]string{"source": "scheduler"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if *executed != 1 {
+		t.Errorf("tool executed %d times, want 1", *executed)
+	}
+	if len(*notified) != 0 {
+		t.Errorf("notifications = %d, want 0", len(*notified))
+	}
+	if result := toolResultContent(t, mock); result != "service called" {
+		t.Errorf("tool result = %q, want handler output", result)
+	}
+}
+
+func TestConfidenceGate_RatingCallRecordsUsage(t *testing.T) {
+	rating := textResponse("0.9")
+	rating.InputTokens, rating.OutputTokens = 30, 2
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		gatedToolCallResponse("ha_call_service"),
+		rating,
+		textResponse("Done."),
+	}}
+	loop, _, _ := setupGatedLoop(mock)
+	db, err := database.OpenMemory()
+	if err != nil {
+		t.Fatalf("database.OpenMemory: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := usage.NewStore(db, nil)
+	if err != nil {
+		t.Fatalf("usage.NewStore: %v", err)
+	}
+	loop.usageStore = store
+
+	if _, err := loop.Run(context.Background(), &Request{
+		Messages:       []Message{{Role: "user", Content: "Lock up at bedtime"}},
+		RoutingFactors: map[string]string{"source": "loop"},
+	}, nil); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	byTask, err := store.SummaryByTask(time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SummaryByTask: %v", err)
+	}
+	for _, g := range byTask {
+		if g.Key == "confidence_gate" {
+			if g.Summary.TotalInputTokens != 30 || g.Summary.TotalOutputTokens != 2 {
+				t.Errorf("confidence_gate usage = %+v, want the rating call's 30/2 tokens", g.Summary)
+			}
+			return
+		}
+	}
+	t.Errorf("usage by task = %+v, want a confidence_gate record", byTask)
+}
+
+func TestConfidenceGate_DelegateOfAutonomousRunDeferred(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		gatedToolCallResponse("ha_call_service"),
+		textResponse("0.2"),
+		textResponse("Asked for approval."),
+	}}
+	loop, executed, notified := setupGatedLoop(mock)
+
+	// A delegate launched from a scheduler run: its own source is
+	// "delegate", but the work is still autonomous.
+	_, err := loop.Run(context.Background(), &Request{
+		Messages: []Message{{Role: "user", Content: "Lock the front door"}},
+		RoutingFactors: map[string]string{
+			"source":               "delegate",
+			tools.HintParentSource: "scheduler",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if *executed != 0 {
+		t.Errorf("tool executed %d times, want 0", *executed)
+	}
+	if len(*notified) != 1 || (*notified)[0].Source != "scheduler" {
+		t.Errorf("notified = %+v, want one deferral from scheduler", *notified)
+	}
+}
+
+func TestConfidenceGate_InteractiveRunsAreNotGated(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		gatedToolCallResponse("ha_call_service"),
+		textResponse("Done."),
+	}}
+	loop, executed, notified := setupGatedLoop(mock)
+
+	_, err := loop.Run(context.Background(), &Request{
+		Messages:       []Message{{Role: "user", Content: "Lock the front door"}},
+		RoutingFactors: map[string]string{"source": "signal"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if *executed != 1 || len(*notified) != 0 {
+		t.Errorf("executed=%d notified=%d, want 1/0", *executed, len(*notified))
+	}
+	if len(mock.calls) != 2 {
+		t.Errorf("LLM calls = %d, want 2 (no confidence check)", len(mock.calls))
+	}
+}
+
+func TestConfidenceGate_NotifyFailureStillDefers(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		gatedToolCallResponse("ha_call_service"),
+		textResponse("not sure"),
+		textResponse("Noted."),
+	}}
+	loop, executed, _ := setupGatedLoop(mock)
+	loop.confidenceGate.Notify = func(context.Context, DeferredAction) error {
+		return errors.New("no provider")
+	}
+
+	_, err := loop.Run(context.Background(), &Request{
+		Messages:       []Message{{Role: "user", Content: "tidy up"}},
+		RoutingFactors: map[string]string{"source": "loop"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if *executed != 0 {
+		t.Errorf("tool executed %d times, want 0 (unparseable rating fails closed)", *executed)
+	}
+	if result := toolResultContent(t, mock); !strings.Contains(result, "could not be delivered") {
+		t.Errorf("tool result = %q, want delivery failure notice", result)
+	}
+}
+
+func TestParseConfidence(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		reply   string
+		want    float64
+		wantErr bool
+	}{
+		{"0.8", 0.8, false},
+		{"Confidence: .45", 0.45, false},
+		{"85%", 0.85, false},
+		{"1", 1, false},
+		{"I am not sure", 0, true},
+		{"250", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseConfidence(tt.reply)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseConfidence(%q) err = %v, wantErr %v", tt.reply, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseConfidence(%q) = %v, want %v", tt.reply, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package agent
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/runtime/agentctx"
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
+)
+
+// Trim order entries beyond the individual context bucket names.
+const (
+	// trimDynamicContext trims every context bucket section together,
+	// each in proportion to its size.
+	trimDynamicContext = "dynamic_context"
+
+	// trimHistory drops the oldest stored history messages.
+	trimHistory = "history"
+)
+
+// defaultTrimOrder is used when no trim order is configured.
+var defaultTrimOrder = []string{trimDynamicContext, trimHistory}
+
+// trimNoteFormat replaces the tail of a trimmed prompt section so the
+// model knows the section was cut rather than complete.
+const trimNoteFormat = "\n[%d bytes trimmed to fit the context budget]"
+
+// trimNoteReserve is the room left for the trim note when sizing a cut.
+var trimNoteReserve = len(fmt.Sprintf(trimNoteFormat, 999999))
+
+// contextBudget caps the estimated context of a run's messages at a
+// fraction of the selected model's context window.
+type contextBudget struct {
+	fraction  float64
+	trimOrder []string
+}
+
+// SetContextBudget caps a run's estimated context at fraction of the
+// selected model's context window. When a request would exceed it, the
+// prompt sections and history named by trimOrder are trimmed, lowest
+// priority first, until it fits. Entries are context bucket names
+// (e.g. "live_state"), "dynamic_context" for all buckets at once, and
+// "history". A fraction of zero disables trimming; an empty trimOrder
+// trims dynamic context, then history.
+func (l *Loop) SetContextBudget(fraction float64, trimOrder []string) {
+	if len(trimOrder) == 0 {
+		trimOrder = defaultTrimOrder
+	}
+	l.contextBudget = contextBudget{fraction: max(fraction, 0), trimOrder: trimOrder}
+}
+
+// modelContextWindow returns the context window of model's deployment,
+// falling back to the default model's window.
+func (l *Loop) modelContextWindow(model string) int {
+	if cat := l.currentModelCatalog(); cat != nil {
+		if dep, err := cat.ResolveDeploymentRef(model); err == nil && dep.ContextWindow > 0 {
+			return dep.ContextWindow
+		}
+	}
+	return l.contextWindow
+}
+
+// fitContextBudget installs the system prompt text, assembled with the
+// given section boundaries, as the first of msgs and trims the result
+// to the context budget for model. history is the number of stored
+// history messages that follow the system message; zero leaves history
+// untouched. msgs itself is not modified.
+func (l *Loop) fitContextBudget(log *slog.Logger, model, text string, sections []promptSection, msgs []llm.Message, history int) (string, []llm.PromptSection, []llm.Message) {
+	out := slices.Clone(msgs)
+	if len(out) == 0 || out[0].Role != "system" {
+		return text, promptSectionsFromBoundaries(text, sections), out
+	}
+	setSystem := func() {
+		out[0].Content = text
+		out[0].Sections = promptSectionsFromBoundaries(text, sections)
+	}
+	setSystem()
+
+	window := l.modelContextWindow(model)
+	if l.contextBudget.fraction <= 0 || window <= 0 {
+		return text, out[0].Sections, out
+	}
+	budget := int(float64(window) * l.contextBudget.fraction)
+	before := estimateLLMMessagesContextTokens(out)
+	if before <= budget {
+		return text, out[0].Sections, out
+	}
+
+	var trimmed []string
+	dropped := 0
+	for _, entry := range l.contextBudget.trimOrder {
+		over := estimateLLMMessagesContextTokens(out) - budget
+		if over <= 0 {
+			break
+		}
+		if entry == trimHistory {
+			var n int
+			out, n = trimHistoryMessages(out, history, over)
+			history -= n
+			dropped += n
+			continue
+		}
+		var cut map[string]int
+		text, sections, cut = trimPromptSections(text, sections, trimSectionNames(entry), over*4)
+		for _, s := range sections {
+			if n := cut[s.name]; n > 0 {
+				trimmed = append(trimmed, fmt.Sprintf("%s:%d", s.name, n))
+			}
+		}
+		setSystem()
+	}
+
+	after := estimateLLMMessagesContextTokens(out)
+	log.Info("request trimmed to context budget",
+		"model", model,
+		"context_window", window,
+		"budget_tokens", budget,
+		"tokens_before", before,
+		"tokens_after", after,
+		"trimmed_sections", trimmed,
+		"history_dropped", dropped,
+	)
+	if after > budget {
+		log.Warn("request exceeds context budget after trimming",
+			"model", model,
+			"budget_tokens", budget,
+			"tokens", after,
+			"trim_order", l.contextBudget.trimOrder,
+		)
+	}
+	return text, out[0].Sections, out
+}
+
+// trimHistoryMessages drops the oldest of the history messages that
+// follow the system message in msgs until about over tokens are freed,
+// and returns the remaining messages and the number dropped. A
+// compaction summary is kept: it stands in for everything before it. A
+// tool call goes together with the results that follow it, so the model
+// never sees a result without its call or a call without its result.
+func trimHistoryMessages(msgs []llm.Message, history, over int) ([]llm.Message, int) {
+	kept := msgs[:1:1]
+	i := 1
+	for end := 1 + history; i < end && over > 0; {
+		j := i + 1
+		for j < end && isToolResultMessage(msgs[j]) {
+			j++
+		}
+		if isCompactionSummaryMessage(msgs[i]) {
+			kept = append(kept, msgs[i:j]...)
+		} else {
+			over -= estimateLLMMessagesContextTokens(msgs[i:j])
+		}
+		i = j
+	}
+	dropped := i - 1 - (len(kept) - 1)
+	return append(kept, msgs[i:]...), dropped
+}
+
+// isCompactionSummaryMessage reports whether m carries a compaction
+// summary, either as a native system message or as the stored memory
+// note history is rendered into.
+func isCompactionSummaryMessage(m llm.Message) bool {
+	if m.Role == "system" {
+		return strings.HasPrefix(m.Content, memory.CompactionSummaryPrefix)
+	}
+	return memory.IsStoredCompactionSummary(m.Content)
+}
+
+// isToolResultMessage reports whether m is the result of an earlier
+// tool call, native or rendered from stored history.
+func isToolResultMessage(m llm.Message) bool {
+	return m.Role == "tool" || memory.IsStoredToolResult(m.Content)
+}
+
+// trimSectionNames maps a trim order entry to the prompt section names
+// it covers.
+func trimSectionNames(entry string) map[string]bool {
+	buckets := []agentctx.ContextBucket{agentctx.ContextBucket(entry)}
+	if entry == trimDynamicContext {
+		buckets = []agentctx.ContextBucket{
+			agentctx.ContextBucketTaggedGuidance,
+			agentctx.ContextBucketContinuity,
+			agentctx.ContextBucketRelated,
+			agentctx.ContextBucketLiveState,
+		}
+	}
+	names := make(map[string]bool, len(buckets))
+	for _, b := range buckets {
+		if b.Valid() {
+			names[strings.ToUpper(b.Title())] = true
+		}
+	}
+	return names
+}
+
+// trimPromptSections cuts about cut bytes from the sections of text
+// named in names, sharing the cut in proportion to section size. It
+// returns the new text, its section boundaries, and the bytes removed
+// from each section.
+func trimPromptSections(text string, sections []promptSection, names map[string]bool, cut int) (string, []promptSection, map[string]int) {
+	total := 0
+	for _, s := range sections {
+		if names[s.name] {
+			total += s.end - s.start
+		}
+	}
+	if total == 0 || cut <= 0 {
+		return text, sections, nil
+	}
+
+	var sb strings.Builder
+	out := make([]promptSection, 0, len(sections))
+	removed := make(map[string]int)
+	prev := 0
+	for _, s := range sections {
+		sb.WriteString(text[prev:s.start])
+		content := text[s.start:s.end]
+		if names[s.name] {
+			share := (cut*len(content) + total - 1) / total
+			var n int
+			content, n = truncatePromptSection(content, share)
+			removed[s.name] += n
+		}
+		start := sb.Len()
+		sb.WriteString(content)
+		out = append(out, promptSection{name: s.name, start: start, end: sb.Len()})
+		prev = s.end
+	}
+	sb.WriteString(text[prev:])
+	return sb.String(), out, removed
+}
+
+// truncatePromptSection shortens content by at least cut bytes where
+// possible, keeping its first line (the section heading) and ending on
+// a line boundary, and appends a note giving the bytes removed.
+func truncatePromptSection(content string, cut int) (string, int) {
+	head, _, _ := strings.Cut(content, "\n")
+	keep := max(len(content)-cut-trimNoteReserve, len(head))
+	if keep >= len(content) {
+		return content, 0
+	}
+	if i := strings.LastIndexByte(content[:keep], '\n'); i >= len(head) {
+		keep = i
+	}
+	removed := len(content) - keep
+	return content[:keep] + fmt.Sprintf(trimNoteFormat, removed), removed
+}
@@ -0,0 +1,188 @@
+package agent
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
+)
+
+// budgetTestPrompt assembles a prompt with a fixed persona and two
+// context bucket sections of the given line counts.
+func budgetTestPrompt(liveLines, relatedLines int) (string, []promptSection) {
+	var sb strings.Builder
+	var sections []promptSection
+	add := func(name, content string) {
+		if sb.Len() > 0 {
+			sb.WriteString("\n\n")
+		}
+		sections = append(sections, promptSection{name: name, start: sb.Len()})
+		sb.WriteString(content)
+		sections[len(sections)-1].end = sb.Len()
+	}
+	lines := func(title string, n int) string {
+		out := "## " + title
+		for i := range n {
+			out += fmt.Sprintf("\n- %s entry %02d %s", title, i, strings.Repeat("x", 30))
+		}
+		return out
+	}
+	add("PERSONA", strings.Repeat("p", 400))
+	add("LIVE STATE", lines("Live State", liveLines))
+	add("RELATED CONTEXT", lines("Related Context", relatedLines))
+	return sb.String(), sections
+}
+
+func sectionContent(sections []llm.PromptSection, name string) string {
+	for _, s := range sections {
+		if s.Name == name {
+			return s.Content
+		}
+	}
+	return ""
+}
+
+func TestFitContextBudget_TrimsDynamicContextProportionally(t *testing.T) {
+	l := &Loop{logger: slog.Default(), contextWindow: 1000}
+	l.SetContextBudget(0.5, nil)
+
+	text, bounds := budgetTestPrompt(30, 15)
+	msgs := []llm.Message{{Role: "system"}, {Role: "user", Content: "what changed?"}}
+	untrimmed := promptSectionsFromBoundaries(text, bounds)
+
+	prompt, sections, out := l.fitContextBudget(slog.Default(), "small-model", text, bounds, msgs, 0)
+
+	if got := estimateLLMMessagesContextTokens(out); got > 500 {
+		t.Errorf("estimate = %d tokens, want within the 500-token budget", got)
+	}
+	if out[0].Content != prompt || msgs[0].Content != "" {
+		t.Error("trimmed prompt not installed in a copy of the messages")
+	}
+	if sectionContent(sections, "PERSONA") != sectionContent(untrimmed, "PERSONA") {
+		t.Error("PERSONA trimmed, want only dynamic context trimmed")
+	}
+	cut := map[string]int{}
+	for _, name := range []string{"LIVE STATE", "RELATED CONTEXT"} {
+		content := sectionContent(sections, name)
+		if !strings.Contains(content, "trimmed to fit the context budget]") {
+			t.Errorf("%s has no trim note:\n%s", name, content)
+		}
+		if !strings.HasPrefix(content, "## ") {
+			t.Errorf("%s lost its heading", name)
+		}
+		cut[name] = len(sectionContent(untrimmed, name)) - len(content)
+	}
+	if cut["LIVE STATE"] <= cut["RELATED CONTEXT"] {
+		t.Errorf("cuts = %v, want the larger LIVE STATE cut more", cut)
+	}
+}
+
+func TestFitContextBudget_DropsOldestHistory(t *testing.T) {
+	l := &Loop{logger: slog.Default(), contextWindow: 1000}
+	l.SetContextBudget(0.5, nil)
+
+	text, bounds := budgetTestPrompt(30, 15)
+	msgs := []llm.Message{{Role: "system"}}
+	for i := range 4 {
+		msgs = append(msgs, llm.Message{Role: "user", Content: fmt.Sprintf("old %d %s", i, strings.Repeat("h", 400))})
+	}
+	msgs = append(msgs, llm.Message{Role: "user", Content: "what changed?"})
+
+	_, _, out := l.fitContextBudget(slog.Default(), "small-model", text, bounds, msgs, 4)
+
+	if got := estimateLLMMessagesContextTokens(out); got > 500 {
+		t.Errorf("estimate = %d tokens, want within the 500-token budget", got)
+	}
+	if len(out) != len(msgs)-1 {
+		t.Fatalf("messages = %d, want one history message dropped from %d", len(out), len(msgs))
+	}
+	if !strings.HasPrefix(out[1].Content, "old 1 ") {
+		t.Errorf("first history message = %.8q, want the oldest dropped", out[1].Content)
+	}
+	if out[len(out)-1].Content != "what changed?" {
+		t.Error("current turn dropped")
+	}
+}
+
+func TestFitContextBudget_KeepsCompactionSummary(t *testing.T) {
+	l := &Loop{logger: slog.Default(), contextWindow: 1000}
+	l.SetContextBudget(0.5, []string{"history"})
+
+	text, bounds := budgetTestPrompt(5, 5)
+	summary, _ := memory.FormatStoredHistoryMessage(memory.Message{
+		Role:    "system",
+		Content: memory.CompactionSummaryPrefix + "\nWe set up the garage sensors.",
+	}, time.Time{})
+	msgs := []llm.Message{{Role: "system"}, {Role: summary.Role, Content: summary.Content}}
+	for i := range 3 {
+		msgs = append(msgs, llm.Message{Role: "user", Content: fmt.Sprintf("old %d %s", i, strings.Repeat("h", 600))})
+	}
+	msgs = append(msgs, llm.Message{Role: "user", Content: "what changed?"})
+
+	_, _, out := l.fitContextBudget(slog.Default(), "small-model", text, bounds, msgs, 4)
+
+	if len(out) >= len(msgs) {
+		t.Fatalf("messages = %d, want history trimmed from %d", len(out), len(msgs))
+	}
+	if out[1].Content != summary.Content {
+		t.Errorf("first history message = %.40q, want the compaction summary kept", out[1].Content)
+	}
+	if !strings.HasPrefix(out[2].Content, "old ") || strings.HasPrefix(out[2].Content, "old 0 ") {
+		t.Errorf("message after the summary = %.8q, want the oldest turn dropped instead", out[2].Content)
+	}
+}
+
+func TestFitContextBudget_DropsToolCallWithItsResults(t *testing.T) {
+	l := &Loop{logger: slog.Default(), contextWindow: 1000}
+	l.SetContextBudget(0.5, []string{"history"})
+
+	text, bounds := budgetTestPrompt(5, 5)
+	call := llm.ToolCall{ID: "call_1"}
+	call.Function.Name = "get_state"
+	msgs := []llm.Message{
+		{Role: "system"},
+		{Role: "assistant", Content: "checking", ToolCalls: []llm.ToolCall{call}},
+		{Role: "tool", ToolCallID: "call_1", Content: strings.Repeat("r", 200)},
+		{Role: "user", Content: "old " + strings.Repeat("h", 1400)},
+		{Role: "assistant", Content: "recent answer"},
+		{Role: "user", Content: "what changed?"},
+	}
+
+	_, _, out := l.fitContextBudget(slog.Default(), "small-model", text, bounds, msgs, 4)
+
+	// Dropping the small call alone would not fit; the trim must take the
+	// call and its result together and move on, never splitting them.
+	for i, m := range out {
+		if m.Role == "tool" && (i == 0 || len(out[i-1].ToolCalls) == 0) {
+			t.Fatalf("tool result at %d kept without its call: %+v", i, out)
+		}
+		if len(m.ToolCalls) > 0 && (i+1 >= len(out) || out[i+1].Role != "tool") {
+			t.Fatalf("tool call at %d kept without its result: %+v", i, out)
+		}
+	}
+	if len(out) != 3 || out[1].Content != "recent answer" {
+		t.Errorf("messages = %+v, want the call, its result, and the old turn dropped", out)
+	}
+
+	// Even a one-token overage takes the result along with the call.
+	kept, n := trimHistoryMessages(slices.Clone(msgs), 4, 1)
+	if n != 2 || kept[1].Role != "user" {
+		t.Errorf("trimHistoryMessages dropped %d, left %+v; want the call and its result only", n, kept)
+	}
+}
+
+func TestFitContextBudget_Disabled(t *testing.T) {
+	l := &Loop{logger: slog.Default(), contextWindow: 1000}
+	text, bounds := budgetTestPrompt(30, 15)
+	msgs := []llm.Message{{Role: "system"}, {Role: "user", Content: strings.Repeat("h", 8000)}}
+
+	prompt, _, out := l.fitContextBudget(slog.Default(), "small-model", text, bounds, msgs, 1)
+	if prompt != text || len(out) != len(msgs) {
+		t.Error("request trimmed with no context budget configured")
+	}
+}
@@ -0,0 +1,300 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/model/router"
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/platform/usage"
+)
+
+type recordingFailoverHandler struct {
+	mu   sync.Mutex
+	hops [][2]string
+}
+
+func (h *recordingFailoverHandler) OnFailover(_ context.Context, from, to, _ string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hops = append(h.hops, [2]string{from, to})
+	return nil
+}
+
+func newFailoverChainRouter() *router.Router {
+	r := router.NewRouter(slog.Default(), router.Config{
+		DefaultModel: "local",
+		Models: []router.Model{
+			{Name: "opus", ResourceID: "anthropic"},
+			{Name: "sonnet", ResourceID: "anthropic"},
+			{Name: "spark/qwen", ResourceID: "spark"},
+			{Name: "local", ResourceID: "default"},
+		},
+	})
+	r.SetFallbackChains(map[string][]string{"opus": {"sonnet", "spark/qwen"}})
+	return r
+}
+
+func TestFailoverChain(t *testing.T) {
+	t.Parallel()
+
+	loop := buildTestLoopWithLLM(&mockTimeoutLLM{}, nil)
+	loop.model = "static"
+	if got, want := loop.failoverChain("opus"), []string{"static"}; !slices.Equal(got, want) {
+		t.Errorf("failoverChain() without router = %v, want %v", got, want)
+	}
+
+	loop.router = newFailoverChainRouter()
+	if got, want := loop.failoverChain("opus"), []string{"sonnet", "spark/qwen", "local"}; !slices.Equal(got, want) {
+		t.Errorf("failoverChain(opus) = %v, want %v", got, want)
+	}
+	if got := loop.failoverChain("local"); len(got) != 0 {
+		t.Errorf("failoverChain(default) = %v, want empty", got)
+	}
+
+	loop.SetDefaultModel("pinned")
+	if got, want := loop.failoverChain("sonnet"), []string{"pinned"}; !slices.Equal(got, want) {
+		t.Errorf("failoverChain(sonnet) with pinned default = %v, want %v", got, want)
+	}
+}
+
+func TestFailoverChain_BudgetCeiling(t *testing.T) {
+	t.Parallel()
+
+	r := router.NewRouter(slog.Default(), router.Config{
+		DefaultModel: "sonnet",
+		Models: []router.Model{
+			{Name: "opus", CostTier: 4},
+			{Name: "sonnet", CostTier: 3},
+			{Name: "spark/qwen", CostTier: 0, Quality: 6},
+			{Name: "local", CostTier: 0, Quality: 4},
+		},
+	})
+	r.SetFallbackChains(map[string][]string{"opus": {"sonnet", "local"}})
+	r.SetBudget(router.Budget{
+		DailyUSD: 5,
+		Spend:    func(start, end time.Time) (float64, error) { return 5, nil },
+	})
+
+	loop := buildTestLoopWithLLM(&mockTimeoutLLM{}, nil)
+	loop.router = r
+
+	// The paid hop is dropped and the paid default gives way to the
+	// best free model.
+	if got, want := loop.failoverChain("opus"), []string{"local", "spark/qwen"}; !slices.Equal(got, want) {
+		t.Errorf("failoverChain(opus) at ceiling = %v, want %v", got, want)
+	}
+}
+
+func TestLLMErrorHandler_WalksFallbackChain(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockTimeoutLLM{
+		responses: []*llm.ChatResponse{
+			{Model: "local", Message: llm.Message{Role: "assistant", Content: "ok"}},
+		},
+		errors: []error{
+			errors.New("sonnet: connection refused"),
+			nil,
+		},
+	}
+	handler := &recordingFailoverHandler{}
+
+	loop := buildTestLoopWithLLM(mock, nil)
+	loop.router = newFailoverChainRouter()
+	loop.router.SetResourceReady(func(resourceID string) bool { return resourceID != "spark" })
+	loop.failoverHandler = handler
+
+	store := newUsageTestStore(t)
+	loop.usageStore = store
+
+	var recovery timeoutRecovery
+	var failovers failoverLog
+	onErr := loop.buildLLMErrorHandler(context.Background(), nil, "opus", &Request{}, &recovery, &failovers)
+	resp, model, err := onErr(context.Background(), errors.New("opus: 500 internal error"), "opus", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("handler error = %v, want failover to succeed", err)
+	}
+	if model != "local" || resp.Message.Content != "ok" {
+		t.Errorf("handler answered from %q with %q, want local", model, resp.Message.Content)
+	}
+
+	mock.mu.Lock()
+	var called []string
+	for _, c := range mock.calls {
+		called = append(called, c.Model)
+	}
+	mock.mu.Unlock()
+	if want := []string{"sonnet", "local"}; !slices.Equal(called, want) {
+		t.Errorf("models called = %v, want %v (spark/qwen is unreachable)", called, want)
+	}
+	if want := [][2]string{{"opus", "sonnet"}, {"sonnet", "local"}}; !slices.Equal(handler.hops, want) {
+		t.Errorf("failover handler hops = %v, want %v", handler.hops, want)
+	}
+
+	loop.recordFailovers(context.Background(), &Request{}, &failovers, "conv-1", "", "r_1")
+	byReason, err := store.SummaryByFailoverReason(time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SummaryByFailoverReason: %v", err)
+	}
+	got := map[string]int{}
+	for _, g := range byReason {
+		got[g.Key] = g.Summary.TotalRecords
+	}
+	if got["opus: 500 internal error"] != 1 || got["sonnet: connection refused"] != 1 || len(got) != 2 {
+		t.Errorf("failover records by reason = %v, want one per hop", got)
+	}
+}
+
+func TestLLMErrorHandler_FallbackChainExhausted(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockTimeoutLLM{
+		errors: []error{
+			errors.New("sonnet down"),
+			errors.New("qwen down"),
+			errors.New("local down"),
+		},
+	}
+	loop := buildTestLoopWithLLM(mock, nil)
+	loop.router = newFailoverChainRouter()
+
+	var recovery timeoutRecovery
+	var failovers failoverLog
+	onErr := loop.buildLLMErrorHandler(context.Background(), nil, "opus", &Request{}, &recovery, &failovers)
+	_, _, err := onErr(context.Background(), errors.New("opus down"), "opus", nil, nil, nil)
+	if err == nil || err.Error() != "local down" {
+		t.Fatalf("handler error = %v, want the last hop's error", err)
+	}
+	if len(failovers.hops) != 3 {
+		t.Errorf("recorded %d hops, want 3", len(failovers.hops))
+	}
+}
+
+// newUsageTestStore returns a usage store on a fresh in-memory database.
+func newUsageTestStore(t *testing.T) *usage.Store {
+	t.Helper()
+	db, err := database.OpenMemory()
+	if err != nil {
+		t.Fatalf("database.OpenMemory: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := usage.NewStore(db, nil)
+	if err != nil {
+		t.Fatalf("usage.NewStore: %v", err)
+	}
+	return store
+}
+
+func TestRun_FailoverUsageCarriesTheFallbackCallsTokens(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockTimeoutLLM{
+		responses: []*llm.ChatResponse{
+			{Model: "sonnet", Message: llm.Message{Role: "assistant", Content: "answered"}, InputTokens: 70, OutputTokens: 7},
+		},
+		errors: []error{errors.New("opus: 500 internal error"), nil},
+	}
+	store := newUsageTestStore(t)
+	loop := buildTestLoopWithLLM(mock, nil)
+	loop.router = newFailoverChainRouter()
+	loop.usageStore = store
+	loop.SetDefaultModel("opus")
+
+	if _, err := loop.Run(context.Background(), &Request{
+		Messages: []Message{{Role: "user", Content: "summarize the garage sensors"}},
+	}, nil); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	window := [2]time.Time{time.Now().Add(-time.Hour), time.Now().Add(time.Hour)}
+	byRole, err := store.SummaryByRole(window[0], window[1])
+	if err != nil {
+		t.Fatalf("SummaryByRole: %v", err)
+	}
+	got := map[string]usage.Summary{}
+	for _, g := range byRole {
+		got[g.Key] = g.Summary
+	}
+	if s := got["failover"]; s.TotalRecords != 1 || s.TotalInputTokens != 70 || s.TotalOutputTokens != 7 {
+		t.Errorf("failover usage = %+v, want one record with the fallback call's 70/7 tokens", s)
+	}
+	if s := got["interactive"]; s.TotalInputTokens != 0 || s.TotalOutputTokens != 0 {
+		t.Errorf("interactive usage = %+v, want the fallback call's tokens not counted twice", s)
+	}
+
+	byFrom, err := store.SummaryByFailoverFrom(window[0], window[1])
+	if err != nil {
+		t.Fatalf("SummaryByFailoverFrom: %v", err)
+	}
+	for _, g := range byFrom {
+		if g.Key == "opus" && g.Summary.TotalInputTokens != 70 {
+			t.Errorf("failover from opus = %+v, want 70 input tokens", g.Summary)
+		}
+	}
+}
+
+// newBlockedBudgetRouter returns a router with one paid and one free
+// model whose budget ceiling has been reached.
+func newBlockedBudgetRouter() *router.Router {
+	r := router.NewRouter(slog.Default(), router.Config{
+		DefaultModel: "local",
+		Models: []router.Model{
+			{Name: "cloud", CostTier: 3, SupportsTools: true, Quality: 10, ContextWindow: 200000},
+			{Name: "local", CostTier: 0, SupportsTools: true, Quality: 5, ContextWindow: 200000},
+		},
+	})
+	r.SetBudget(router.Budget{
+		DailyUSD: 5,
+		Spend:    func(start, end time.Time) (float64, error) { return 6, nil },
+	})
+	return r
+}
+
+func TestRun_BudgetCeilingRefusesExplicitPaidModel(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockTimeoutLLM{}
+	loop := buildTestLoopWithLLM(mock, nil)
+	loop.router = newBlockedBudgetRouter()
+
+	_, err := loop.Run(context.Background(), &Request{
+		Model:    "cloud",
+		Messages: []Message{{Role: "user", Content: "summarize the garage sensors"}},
+	}, nil)
+	if err == nil || !strings.Contains(err.Error(), "budget ceiling") {
+		t.Fatalf("Run() error = %v, want a budget ceiling refusal", err)
+	}
+	if len(mock.calls) != 0 {
+		t.Errorf("LLM called %d times, want none", len(mock.calls))
+	}
+}
+
+func TestRun_BudgetCeilingOverridesPinnedPaidDefault(t *testing.T) {
+	t.Parallel()
+
+	mock := &mockTimeoutLLM{
+		responses: []*llm.ChatResponse{
+			{Model: "local", Message: llm.Message{Role: "assistant", Content: "ok"}},
+		},
+	}
+	loop := buildTestLoopWithLLM(mock, nil)
+	loop.router = newBlockedBudgetRouter()
+	loop.SetDefaultModel("cloud")
+
+	if _, err := loop.Run(context.Background(), &Request{
+		Messages: []Message{{Role: "user", Content: "summarize the garage sensors"}},
+	}, nil); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if len(mock.calls) != 1 || mock.calls[0].Model != "local" {
+		t.Errorf("models called = %v, want only local", mock.calls)
+	}
+}
@@ -0,0 +1,29 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/logging"
+)
+
+// anticipateFollowUp schedules the verification wake for a tool that
+// declared a follow-up. Failures are logged, never surfaced: the
+// action itself already succeeded and its result must reach the model
+// unchanged.
+func (l *Loop) anticipateFollowUp(ctx context.Context, convID, name, argsJSON, result string) {
+	task, err := l.tools.ScheduleFollowUp(ctx, convID, name, argsJSON, result)
+	if err != nil {
+		logging.Logger(ctx).Warn("failed to schedule tool follow-up",
+			"tool", name, "conversation_id", convID, "error", err)
+		return
+	}
+	if task == nil {
+		return
+	}
+	logging.Logger(ctx).Info("tool follow-up scheduled",
+		"tool", name,
+		"conversation_id", convID,
+		"task_id", task.ID,
+		"due", task.Schedule.At,
+	)
+}
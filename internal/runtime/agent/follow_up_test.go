@@ -0,0 +1,165 @@
+package agent
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/platform/scheduler"
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+// setupFollowUpLoop builds a loop whose registry is backed by a real
+// scheduler, with a dishwasher tool and an unrelated tool registered.
+func setupFollowUpLoop(t *testing.T, mock *mockLLM) (*Loop, *scheduler.Scheduler) {
+	t.Helper()
+	db, err := database.Open(filepath.Join(t.TempDir(), "sched.db"))
+	if err != nil {
+		t.Fatalf("database.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := scheduler.NewStore(db, nil)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	sched := scheduler.New(slog.Default(), store, nil)
+
+	loop := buildTestLoop(mock, nil)
+	loop.tools = tools.NewRegistry(nil, sched, nil)
+	for _, name := range []string{"start_dishwasher", "get_weather"} {
+		loop.tools.Register(&tools.Tool{
+			Name:        name,
+			Description: "test tool " + name,
+			Parameters:  map[string]any{"type": "object", "properties": map[string]any{}},
+			Handler: func(context.Context, map[string]any) (string, error) {
+				return "ok", nil
+			},
+		})
+	}
+	return loop, sched
+}
+
+func runSingleToolCall(t *testing.T, loop *Loop) {
+	t.Helper()
+	_, err := loop.Run(context.Background(), &Request{
+		ConversationID: "kitchen",
+		Messages:       []Message{{Role: "user", Content: "Run the dishes"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+}
+
+func TestFollowUp_DeclaredToolSchedulesVerificationWake(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		gatedToolCallResponse("start_dishwasher"),
+		textResponse("Dishwasher started."),
+	}}
+	loop, sched := setupFollowUpLoop(t, mock)
+	if err := loop.tools.DeclareFollowUp("start_dishwasher", tools.FollowUp{
+		Window: 90 * time.Minute,
+		Expect: "the dishwasher reports its cycle complete",
+	}); err != nil {
+		t.Fatalf("DeclareFollowUp: %v", err)
+	}
+
+	before := time.Now()
+	runSingleToolCall(t, loop)
+
+	tasks, err := sched.ListTasks(true)
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("scheduled tasks = %d, want 1", len(tasks))
+	}
+	task := tasks[0]
+	if task.Schedule.Kind != scheduler.ScheduleOnce || task.Schedule.At == nil {
+		t.Fatalf("schedule = %+v, want one-shot", task.Schedule)
+	}
+	if due := task.Schedule.At.Sub(before); due < 90*time.Minute || due > 91*time.Minute {
+		t.Errorf("due in %v, want ~90m", due)
+	}
+	if task.Payload.Kind != scheduler.PayloadWake {
+		t.Errorf("payload kind = %q, want wake", task.Payload.Kind)
+	}
+	if got := task.Payload.Data["conversation_id"]; got != "kitchen" {
+		t.Errorf("conversation_id = %v, want kitchen", got)
+	}
+	msg, _ := task.Payload.Data["message"].(string)
+	for _, want := range []string{"start_dishwasher", "the dishwasher reports its cycle complete", "lock.front_door"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("wake message missing %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestFollowUp_UndeclaredToolSchedulesNothing(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		gatedToolCallResponse("get_weather"),
+		textResponse("Sunny."),
+	}}
+	loop, sched := setupFollowUpLoop(t, mock)
+	if err := loop.tools.DeclareFollowUp("start_dishwasher", tools.FollowUp{Window: time.Hour}); err != nil {
+		t.Fatalf("DeclareFollowUp: %v", err)
+	}
+
+	runSingleToolCall(t, loop)
+
+	tasks, err := sched.ListTasks(false)
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("scheduled tasks = %d, want 0", len(tasks))
+	}
+}
+
+func TestDeclareFollowUp_Validation(t *testing.T) {
+	loop, _ := setupFollowUpLoop(t, &mockLLM{})
+	if err := loop.tools.DeclareFollowUp("no_such_tool", tools.FollowUp{Window: time.Hour}); err == nil {
+		t.Error("DeclareFollowUp(unknown tool) = nil, want error")
+	}
+	if err := loop.tools.DeclareFollowUp("start_dishwasher", tools.FollowUp{}); err == nil {
+		t.Error("DeclareFollowUp(zero window) = nil, want error")
+	}
+}
+
+func TestFollowUp_SurvivesReregistrationAndCarriesCheck(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		gatedToolCallResponse("start_dishwasher"),
+		textResponse("Dishwasher started."),
+	}}
+	loop, sched := setupFollowUpLoop(t, mock)
+	check := "{{ is_state('sensor.dishwasher', 'complete') }}"
+	if err := loop.tools.DeclareFollowUp("start_dishwasher", tools.FollowUp{Window: time.Hour, Check: check}); err != nil {
+		t.Fatalf("DeclareFollowUp: %v", err)
+	}
+	// A reconnecting integration re-registers its tools.
+	loop.tools.Register(&tools.Tool{
+		Name:        "start_dishwasher",
+		Description: "test tool start_dishwasher",
+		Parameters:  map[string]any{"type": "object", "properties": map[string]any{}},
+		Handler: func(context.Context, map[string]any) (string, error) {
+			return "ok", nil
+		},
+	})
+
+	runSingleToolCall(t, loop)
+
+	tasks, err := sched.ListTasks(true)
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("scheduled tasks = %d, want 1 after re-registration", len(tasks))
+	}
+	if got := tasks[0].Payload.Data[tools.FollowUpCheckKey]; got != check {
+		t.Errorf("%s = %v, want the declared check", tools.FollowUpCheckKey, got)
+	}
+}
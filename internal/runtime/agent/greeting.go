@@ -0,0 +1,220 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+)
+
+// Simple greeting patterns that don't need tool calls
+var greetingPatterns = []string{
+	"hi", "hello", "hey", "howdy", "hiya", "yo",
+	"good morning", "good afternoon", "good evening",
+	"what's up", "whats up", "sup",
+}
+
+// isSimpleGreeting checks if the message is a simple greeting
+func isSimpleGreeting(msg string) bool {
+	lower := strings.ToLower(strings.TrimSpace(msg))
+	// Remove punctuation
+	lower = strings.TrimRight(lower, "!?.,")
+	for _, pattern := range greetingPatterns {
+		if lower == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// fallbackGreetings are used when no persona is configured or when
+// generating persona-voiced greetings fails.
+var fallbackGreetings = []string{
+	"Hey! What can I help you with?",
+	"Hi there! How can I help?",
+	"Hello! What would you like me to do?",
+	"Hey! Ready to help.",
+}
+
+const (
+	// greetingVariants is how many greetings are requested per
+	// generation so repeated hellos don't get the same reply.
+	greetingVariants = 4
+
+	// maxGreetingLen drops generated lines that are too long to be a
+	// greeting (usually preamble the model added despite instructions).
+	maxGreetingLen = 200
+
+	// greetingGenerateTimeout bounds a background regeneration.
+	greetingGenerateTimeout = 30 * time.Second
+)
+
+const greetingPrompt = `Someone has just greeted you with a bare "hi" and nothing else.
+Write %d different short replies you might give, each one or two sentences, in your own voice.
+Put each reply on its own line. No numbering, quotes, or commentary.`
+
+// greetingCache holds the replies served by the greeting fast-path
+// along with the persona they were generated for, so a persona change
+// can be detected and the cache regenerated. The zero value is ready
+// to use and serves fallbackGreetings.
+type greetingCache struct {
+	mu         sync.Mutex
+	responses  []string
+	persona    string
+	primed     bool
+	refreshing bool
+	next       int
+}
+
+// currentPersona returns the persona content the system prompt is
+// built from: the persona file when one is configured and readable,
+// otherwise the static persona, otherwise "".
+func (l *Loop) currentPersona(ctx context.Context) string {
+	if l.coreContextProvider != nil {
+		if persona := l.coreContextProvider.personaContent(ctx); persona != "" {
+			return persona
+		}
+	}
+	return l.persona
+}
+
+// SetGreetingCache replaces the greeting fast-path replies with the
+// given greetings, attributed to the current persona. An empty slice
+// restores the built-in fallbacks.
+func (l *Loop) SetGreetingCache(greetings []string) {
+	l.setGreetings(l.currentPersona(context.Background()), greetings)
+}
+
+func (l *Loop) setGreetings(persona string, greetings []string) {
+	l.greetings.mu.Lock()
+	defer l.greetings.mu.Unlock()
+	l.greetings.responses = append([]string(nil), greetings...)
+	l.greetings.persona = persona
+	l.greetings.primed = true
+	l.greetings.next = 0
+}
+
+// RefreshGreetings asks the pinned default model, or the router's
+// background pick, with the persona as its system prompt, for a fresh
+// set of greeting replies and installs them. When no persona is
+// configured the fallbacks are installed without a model call. On
+// failure the fallbacks are installed and the error is returned; the
+// cache is not retried until the persona changes.
+func (l *Loop) RefreshGreetings(ctx context.Context) error {
+	l.greetings.mu.Lock()
+	l.greetings.refreshing = true
+	l.greetings.mu.Unlock()
+	defer func() {
+		l.greetings.mu.Lock()
+		l.greetings.refreshing = false
+		l.greetings.mu.Unlock()
+	}()
+
+	persona := l.currentPersona(ctx)
+	if persona == "" {
+		l.setGreetings(persona, nil)
+		return nil
+	}
+
+	greetings, err := l.generateGreetings(ctx, persona)
+	if err != nil {
+		l.setGreetings(persona, nil)
+		return err
+	}
+	l.setGreetings(persona, greetings)
+	l.logger.Info("persona greetings generated", "count", len(greetings))
+	return nil
+}
+
+func (l *Loop) generateGreetings(ctx context.Context, persona string) ([]string, error) {
+	resp, err := l.sideChat(ctx, "greeting", l.defaultModelOverride(), []llm.Message{
+		{Role: "system", Content: persona},
+		{Role: "user", Content: fmt.Sprintf(greetingPrompt, greetingVariants)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generate greetings: %w", err)
+	}
+	greetings := parseGreetings(resp.Message.Content)
+	if len(greetings) == 0 {
+		return nil, errors.New("generate greetings: model returned no usable lines")
+	}
+	return greetings, nil
+}
+
+// parseGreetings splits a generated reply into individual greetings,
+// stripping list markers and wrapping quotes the model adds anyway.
+func parseGreetings(content string) []string {
+	var greetings []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimLeft(line, "-*•0123456789.) ")
+		line = strings.Trim(line, `"“”'`)
+		line = strings.TrimSpace(line)
+		if line == "" || len(line) > maxGreetingLen {
+			continue
+		}
+		greetings = append(greetings, line)
+		if len(greetings) == greetingVariants {
+			break
+		}
+	}
+	return greetings
+}
+
+// RefreshPersona reports whether the persona content differs from the
+// persona the greeting cache was generated for and, when it does,
+// starts regenerating the cache in the background. The persona itself
+// is read fresh on every turn; a config reload calls this so a persona
+// edit shows up in the reload summary and greetings catch up without
+// waiting for the next bare "hi". An unprimed cache (startup generation
+// still pending) reports no change.
+func (l *Loop) RefreshPersona(ctx context.Context) bool {
+	persona := l.currentPersona(ctx)
+
+	l.greetings.mu.Lock()
+	defer l.greetings.mu.Unlock()
+	if !l.greetings.primed || l.greetings.persona == persona {
+		return false
+	}
+	if !l.greetings.refreshing {
+		l.greetings.refreshing = true
+		go l.backgroundRefreshGreetings(context.WithoutCancel(ctx))
+	}
+	return true
+}
+
+// greetingResponse returns the next greeting reply, cycling through the
+// cache. When the persona has changed since the cache was filled, a
+// background regeneration starts and the current replies are served
+// until it completes.
+func (l *Loop) greetingResponse(ctx context.Context) string {
+	persona := l.currentPersona(ctx)
+
+	l.greetings.mu.Lock()
+	stale := !l.greetings.primed || l.greetings.persona != persona
+	if stale && !l.greetings.refreshing {
+		l.greetings.refreshing = true
+		go l.backgroundRefreshGreetings(context.WithoutCancel(ctx))
+	}
+	responses := l.greetings.responses
+	if len(responses) == 0 {
+		responses = fallbackGreetings
+	}
+	resp := responses[l.greetings.next%len(responses)]
+	l.greetings.next++
+	l.greetings.mu.Unlock()
+
+	return resp
+}
+
+func (l *Loop) backgroundRefreshGreetings(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, greetingGenerateTimeout)
+	defer cancel()
+	if err := l.RefreshGreetings(ctx); err != nil {
+		l.logger.Warn("persona greeting generation failed, using fallbacks", "error", err)
+	}
+}
@@ -0,0 +1,147 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+)
+
+func greet(t *testing.T, loop *Loop) string {
+	t.Helper()
+	resp, err := loop.Run(context.Background(), &Request{
+		Messages: []Message{{Role: "user", Content: "Hi!"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if resp.Model != "greeting-handler" {
+		t.Fatalf("Model = %q, want the greeting fast-path", resp.Model)
+	}
+	return resp.Content
+}
+
+func TestRefreshGreetings_UsesPersonaVoice(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		textResponse("1. Well met, traveler.\n- \"Ahoy! What brings you here?\"\n\nGreetings, friend."),
+	}}
+	loop := buildTestLoop(mock, nil)
+	loop.persona = "You are a salty ship's captain."
+
+	if err := loop.RefreshGreetings(context.Background()); err != nil {
+		t.Fatalf("RefreshGreetings() error = %v", err)
+	}
+	if len(mock.calls) != 1 {
+		t.Fatalf("LLM calls = %d, want 1", len(mock.calls))
+	}
+	if msgs := mock.calls[0].Messages; msgs[0].Role != "system" || msgs[0].Content != loop.persona {
+		t.Errorf("generation system message = %+v, want the persona", msgs[0])
+	}
+
+	want := []string{"Well met, traveler.", "Ahoy! What brings you here?", "Greetings, friend.", "Well met, traveler."}
+	for i, w := range want {
+		if got := greet(t, loop); got != w {
+			t.Errorf("greeting %d = %q, want %q", i, got, w)
+		}
+	}
+	if len(mock.calls) != 1 {
+		t.Errorf("LLM calls after greetings = %d, want no further generation", len(mock.calls))
+	}
+}
+
+func TestRefreshGreetings_FailureFallsBack(t *testing.T) {
+	loop := buildTestLoop(&mockLLM{}, nil) // no responses: Chat fails
+	loop.persona = "You are a salty ship's captain."
+
+	if err := loop.RefreshGreetings(context.Background()); err == nil {
+		t.Fatal("RefreshGreetings() succeeded with a failing model")
+	}
+	if got := greet(t, loop); got != fallbackGreetings[0] {
+		t.Errorf("greeting = %q, want fallback %q", got, fallbackGreetings[0])
+	}
+}
+
+func TestRefreshGreetings_NoPersonaSkipsGeneration(t *testing.T) {
+	mock := &mockLLM{}
+	loop := buildTestLoop(mock, nil)
+
+	if err := loop.RefreshGreetings(context.Background()); err != nil {
+		t.Fatalf("RefreshGreetings() error = %v", err)
+	}
+	if len(mock.calls) != 0 {
+		t.Errorf("LLM calls = %d, want none without a persona", len(mock.calls))
+	}
+	if got := greet(t, loop); got != fallbackGreetings[0] {
+		t.Errorf("greeting = %q, want fallback %q", got, fallbackGreetings[0])
+	}
+}
+
+func TestGreetingResponse_RegeneratesOnPersonaChange(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{textResponse("Beep boop, hello human.")}}
+	loop := buildTestLoop(mock, nil)
+	loop.persona = "You are a salty ship's captain."
+	loop.SetGreetingCache([]string{"Ahoy!"})
+
+	if got := greet(t, loop); got != "Ahoy!" {
+		t.Fatalf("greeting = %q, want the cached reply", got)
+	}
+
+	loop.persona = "You are a friendly robot."
+	if got := greet(t, loop); got != "Ahoy!" {
+		t.Errorf("greeting during regeneration = %q, want the previous reply", got)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		loop.greetings.mu.Lock()
+		done := loop.greetings.persona == loop.persona && !loop.greetings.refreshing
+		loop.greetings.mu.Unlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("greeting cache was not regenerated after the persona changed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := greet(t, loop); got != "Beep boop, hello human." {
+		t.Errorf("greeting = %q, want the regenerated reply", got)
+	}
+}
+
+func TestRefreshPersona(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{textResponse("Beep boop, hello human.")}}
+	loop := buildTestLoop(mock, nil)
+	loop.persona = "You are a salty ship's captain."
+
+	if loop.RefreshPersona(context.Background()) {
+		t.Error("RefreshPersona() = true before the cache was primed")
+	}
+	loop.SetGreetingCache([]string{"Ahoy!"})
+	if loop.RefreshPersona(context.Background()) {
+		t.Error("RefreshPersona() = true with an unchanged persona")
+	}
+
+	loop.persona = "You are a friendly robot."
+	if !loop.RefreshPersona(context.Background()) {
+		t.Fatal("RefreshPersona() = false after the persona changed")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		loop.greetings.mu.Lock()
+		done := loop.greetings.persona == loop.persona && !loop.greetings.refreshing
+		loop.greetings.mu.Unlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("greeting cache was not regenerated after RefreshPersona")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := greet(t, loop); got != "Beep boop, hello human." {
+		t.Errorf("greeting = %q, want the regenerated reply", got)
+	}
+}
@@ -0,0 +1,46 @@
+package agent
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IDGenerator returns a new unique identifier on each call. The loop
+// uses one for internal tool call IDs; see [Loop.SetIDGenerator].
+// Implementations must be safe for concurrent use, since one loop
+// serves concurrent runs.
+type IDGenerator func() string
+
+// NewUUIDv7 is the default [IDGenerator]. UUIDv7 IDs sort by creation
+// time, which keeps tool call records in execution order.
+func NewUUIDv7() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return fmt.Sprintf("%016x", time.Now().UnixNano())
+	}
+	return id.String()
+}
+
+// NewSequentialIDGenerator returns a deterministic [IDGenerator] that
+// yields prefix-0001, prefix-0002, and so on. Two loops given the same
+// inputs, a stub provider, and fresh sequential generators produce
+// byte-identical tool call sequences, which makes whole turns suitable
+// for golden-file tests and replay.
+func NewSequentialIDGenerator(prefix string) IDGenerator {
+	var n atomic.Uint64
+	return func() string {
+		return fmt.Sprintf("%s-%04d", prefix, n.Add(1))
+	}
+}
+
+// toolCallID returns a new internal tool call ID from the configured
+// generator, falling back to [NewUUIDv7].
+func (l *Loop) toolCallID() string {
+	if l.newToolCallID != nil {
+		return l.newToolCallID()
+	}
+	return NewUUIDv7()
+}
@@ -0,0 +1,123 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+// replayTurn runs one scripted turn (a batch of three tool calls, then
+// a text answer) against a fresh stub and returns the tool-call trace
+// as JSON: every tool execution with its internal ID, plus the stream's
+// tool events, in the order they happened.
+func replayTurn(t *testing.T, gen IDGenerator) []byte {
+	t.Helper()
+	batch := &llm.ChatResponse{Model: "test-model", Message: llm.Message{Role: "assistant"}}
+	for i, name := range []string{"lookup", "compute", "lookup"} {
+		tc := llm.ToolCall{ID: fmt.Sprintf("call-%d", i+1)}
+		tc.Function.Name = name
+		tc.Function.Arguments = map[string]any{"step": i + 1}
+		batch.Message.ToolCalls = append(batch.Message.ToolCalls, tc)
+	}
+	mock := &mockLLM{responses: []*llm.ChatResponse{batch, textResponse("done")}}
+	loop := buildTestLoop(mock, nil)
+	if gen != nil {
+		loop.SetIDGenerator(gen)
+	}
+
+	type execution struct {
+		Tool       string         `json:"tool"`
+		ToolCallID string         `json:"tool_call_id"`
+		Args       map[string]any `json:"args"`
+	}
+	var trace struct {
+		Executions []execution `json:"executions"`
+		Events     []string    `json:"events"`
+	}
+	for _, name := range []string{"lookup", "compute"} {
+		name := name
+		loop.tools.Register(&tools.Tool{
+			Name:        name,
+			Description: "test tool " + name,
+			Parameters:  map[string]any{"type": "object", "properties": map[string]any{}},
+			Handler: func(ctx context.Context, args map[string]any) (string, error) {
+				trace.Executions = append(trace.Executions, execution{
+					Tool:       name,
+					ToolCallID: tools.ToolCallIDFromContext(ctx),
+					Args:       args,
+				})
+				return name + " ok", nil
+			},
+		})
+	}
+
+	stream := func(e StreamEvent) {
+		switch e.Kind {
+		case llm.KindToolCallStart:
+			trace.Events = append(trace.Events, "start "+e.ToolCall.ID+" "+e.ToolCall.Function.Name)
+		case llm.KindToolCallDone:
+			trace.Events = append(trace.Events, "done "+e.ToolName+" "+e.ToolResult)
+		}
+	}
+	if _, err := loop.Run(context.Background(), &Request{
+		ConversationID: "replay",
+		Messages:       []Message{{Role: "user", Content: "run the steps"}},
+	}, stream); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	out, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatalf("marshal trace: %v", err)
+	}
+	return out
+}
+
+func TestRun_InjectedIDGeneratorIsDeterministic(t *testing.T) {
+	first := replayTurn(t, NewSequentialIDGenerator("tc"))
+	second := replayTurn(t, NewSequentialIDGenerator("tc"))
+	if string(first) != string(second) {
+		t.Fatalf("identical runs diverged:\n%s\n%s", first, second)
+	}
+
+	// Calls execute in model order with IDs assigned in that order.
+	want := []string{
+		`{"tool":"lookup","tool_call_id":"tc-0001","args":{"step":1}}`,
+		`{"tool":"compute","tool_call_id":"tc-0002","args":{"step":2}}`,
+		`{"tool":"lookup","tool_call_id":"tc-0003","args":{"step":3}}`,
+		`"start call-1 lookup","done lookup lookup ok","start call-2 compute"`,
+	}
+	for _, w := range want {
+		if !strings.Contains(string(first), w) {
+			t.Errorf("trace missing %s:\n%s", w, first)
+		}
+	}
+}
+
+func TestRun_DefaultToolCallIDsAreUUIDv7(t *testing.T) {
+	var trace struct {
+		Executions []struct {
+			ToolCallID string `json:"tool_call_id"`
+		} `json:"executions"`
+	}
+	if err := json.Unmarshal(replayTurn(t, nil), &trace); err != nil {
+		t.Fatalf("unmarshal trace: %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, e := range trace.Executions {
+		id, err := uuid.Parse(e.ToolCallID)
+		if err != nil || id.Version() != 7 {
+			t.Errorf("tool call ID %q is not a UUIDv7", e.ToolCallID)
+		}
+		if seen[e.ToolCallID] {
+			t.Errorf("duplicate tool call ID %q", e.ToolCallID)
+		}
+		seen[e.ToolCallID] = true
+	}
+}
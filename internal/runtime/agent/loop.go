@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -23,6 +24,7 @@ import (
 	"github.com/nugget/thane-ai-agent/internal/model/router"
 	"github.com/nugget/thane-ai-agent/internal/model/talents"
 	"github.com/nugget/thane-ai-agent/internal/model/toolcatalog"
+	"github.com/nugget/thane-ai-agent/internal/platform/audit"
 	"github.com/nugget/thane-ai-agent/internal/platform/config"
 	"github.com/nugget/thane-ai-agent/internal/platform/events"
 	"github.com/nugget/thane-ai-agent/internal/platform/logging"
@@ -67,6 +69,7 @@ type Request struct {
 	UsageTaskName    string                              `json:"-"`                           // Optional usage task name override
 	FallbackContent  string                              `json:"-"`                           // Optional static fallback text when the run yields no content
 	PromptMode       agentctx.PromptMode                 `json:"-"`                           // Optional system-prompt shape override.
+	ClientStream     bool                                `json:"-"`                           // The stream callback feeds a connected client (HTTP, SSE, WebSocket), which receives heartbeat keepalives
 
 	// SystemPrompt, when non-empty, replaces the output of
 	// buildSystemPrompt(). Used by callers that assemble their own
@@ -92,12 +95,14 @@ type StreamCallback = llm.StreamCallback
 
 // Stream event kinds re-exported for consumers.
 const (
-	KindToken         = llm.KindToken
-	KindToolCallStart = llm.KindToolCallStart
-	KindToolCallDone  = llm.KindToolCallDone
-	KindDone          = llm.KindDone
-	KindLLMResponse   = llm.KindLLMResponse
-	KindLLMStart      = llm.KindLLMStart
+	KindToken            = llm.KindToken
+	KindToolCallStart    = llm.KindToolCallStart
+	KindToolCallProgress = llm.KindToolCallProgress
+	KindToolCallDone     = llm.KindToolCallDone
+	KindDone             = llm.KindDone
+	KindLLMResponse      = llm.KindLLMResponse
+	KindLLMStart         = llm.KindLLMStart
+	KindHeartbeat        = llm.KindHeartbeat
 )
 
 // maxAxiomsBytes is the maximum size of axioms.md content published as
@@ -268,27 +273,48 @@ type Loop struct {
 	failoverHandler     FailoverHandler
 	archiver            SessionArchiver
 	extractor           *memory.Extractor
+	titler              *memory.Titler
 	orchestratorTools   []string                       // Restricted tool set for orchestrator mode (nil = all tools)
 	dynamicTools        DynamicToolSource              // nil = no dynamically-sourced tools (e.g. companion)
+	confidenceGate      *ConfidenceGate                // nil = autonomous actions run ungated
+	streamHeartbeat     time.Duration                  // 0 = no keepalive events on streaming runs
+	toolErrorReflection int                            // consecutive tool errors before a reflection nudge; 0 = disabled
+	parallelToolCalls   int                            // max concurrent read-only tool calls per batch; 0 or 1 = sequential
+	maxIterations       int                            // default iteration cap per run; 0 = iterate.DefaultMaxIterations
+	channelMaxIters     map[string]int                 // source channel → iteration cap override
+	contextBudget       contextBudget                  // zero fraction = no context-budget trimming
+	toolResultCap       toolResultCap                  // zero value = tool results are not capped
+	greetings           greetingCache                  // persona-voiced replies for the greeting fast-path
+	newToolCallID       IDGenerator                    // nil = UUIDv7; see SetIDGenerator
 	liveRequestRecorder logging.RequestRecordFunc      // nil = no live request detail prefill
 	requestRecorder     logging.RequestRecordFunc      // nil = request detail inspection disabled
 	usageStore          *usage.Store                   // nil = no usage recording
+	toolAudit           *audit.Store                   // nil = no tool audit log
+	capturedPrompts     *promptCapture                 // nil = assembled prompts not retained
 	pricing             map[string]config.PricingEntry // model→cost for usage recording
 	usageCatalog        *fleet.Catalog
 	modelRegistry       *fleet.Registry
 	modelRuntime        *fleet.Runtime
 
+	// reloadMu guards capTags, parsedTalents, capSurface, and pricing,
+	// which a config reload can replace while runs are in flight.
+	// Readers go through the capabilityTags, talentSet,
+	// capabilitySurface, and pricingTable accessors.
+	reloadMu sync.RWMutex
+
 	// Capability tags — per-Run tool/talent filtering.
 	//
 	// Each Run() creates its own capabilityScope (stored in context)
 	// seeded with core + channel-pinned tags. Tool handlers
 	// mutate the scope via context, so concurrent Run() calls from
 	// different channels are fully isolated.
-	capTags       map[string]config.CapabilityTagConfig // tag definitions from config (static)
-	parsedTalents []talents.Talent                      // pre-loaded talent structs for tag filtering (static)
+	capTags       map[string]config.CapabilityTagConfig // tag definitions from config; guarded by reloadMu
+	parsedTalents []talents.Talent                      // pre-loaded talent structs for tag filtering; guarded by reloadMu
 	channelTags   map[string][]string                   // channel name → tag names (static)
 	contactLookup ContactLookup                         // trust-gated contact profile lookup for origin context
 	capTagStore   CapabilityTagStore                    // persists activated tags per conversation (nil = no persistence)
+	toolPolicy    ToolPolicyStore                       // per-conversation tool allowlists (nil = unrestricted)
+	eventBus      *events.Bus                           // live activity events (nil = not published)
 	lensProvider  func() []string                       // returns active global lenses (nil = none)
 	capSurface    []toolcatalog.CapabilitySurface       // resolved capability surface for model-facing rendering
 
@@ -297,6 +323,12 @@ type Loop struct {
 	lastRunTagsMu sync.Mutex
 	lastRunTags   map[string]bool
 
+	// defaultOverride is the runtime default model set with
+	// SetDefaultModel. When non-empty it pins requests that do not
+	// name a model, bypassing the router. Guarded by defaultModelMu.
+	defaultModelMu  sync.RWMutex
+	defaultOverride string
+
 	// pendingProviders holds context-provider registrations made
 	// before SetTagContextAssembler is called. SetTagContextAssembler
 	// drains them into the assembler at wiring time. After that, the
@@ -547,7 +579,9 @@ type CapabilityWiring struct {
 // no-ops so callers can stage the wiring incrementally.
 func (l *Loop) ConfigureCapabilityWiring(w CapabilityWiring) {
 	if w.ParsedTalents != nil {
+		l.reloadMu.Lock()
 		l.parsedTalents = w.ParsedTalents
+		l.reloadMu.Unlock()
 	}
 	if len(w.Tags) > 0 {
 		l.SetCapabilityTags(w.Tags, w.ParsedTalents)
@@ -567,22 +601,30 @@ func (l *Loop) ConfigureCapabilityWiring(w CapabilityWiring) {
 // initialized after the channel/storage subsystem comes online.
 type SessionStoreWiring struct {
 	Extractor    *memory.Extractor
+	Titler       *memory.Titler
 	UsageStore   *usage.Store
 	Pricing      map[string]config.PricingEntry
 	UsageCatalog *fleet.Catalog
+	ToolAudit    *audit.Store
 }
 
-// ConfigureSessionStores applies extractor and usage-recording wiring.
-// Each field is independently optional.
+// ConfigureSessionStores applies extractor, titler, usage-recording,
+// and tool audit wiring. Each field is independently optional.
 func (l *Loop) ConfigureSessionStores(w SessionStoreWiring) {
 	if w.Extractor != nil {
 		l.extractor = w.Extractor
 	}
+	if w.Titler != nil {
+		l.titler = w.Titler
+	}
 	if w.UsageStore != nil {
 		l.usageStore = w.UsageStore
-		l.pricing = w.Pricing
+		l.SetPricing(w.Pricing)
 		l.usageCatalog = w.UsageCatalog
 	}
+	if w.ToolAudit != nil {
+		l.toolAudit = w.ToolAudit
+	}
 }
 
 // ChannelDelegationWiring bundles the late-binding routing and
@@ -665,14 +707,84 @@ func (l *Loop) contextAssemblerForPrompt() *TagContextAssembler {
 // SetOrchestratorTools configures the restricted tool set for all
 // iterations of the agent loop. When set, only the named tools are
 // advertised on every LLM call, keeping the primary model in
-// orchestrator mode and steering it toward delegation. If neither
-// thane_now nor thane_assign is registered in the tool registry,
-// gating is silently disabled to avoid leaving the agent without
+// orchestrator mode and steering it toward delegation. If none of
+// thane_now, thane_fanout, or thane_assign is registered in the tool
+// registry, gating is silently disabled to avoid leaving the agent without
 // any way to delegate.
 func (l *Loop) SetOrchestratorTools(names []string) {
 	l.orchestratorTools = names
 }
 
+// SetToolErrorReflection sets how many consecutive tool errors in one
+// turn trigger a reflection nudge asking the model to diagnose the
+// failures before retrying, switching tools, or giving up. Zero or a
+// negative count disables reflection.
+func (l *Loop) SetToolErrorReflection(after int) {
+	if after < 0 {
+		after = 0
+	}
+	l.toolErrorReflection = after
+}
+
+// SetParallelToolCalls sets how many read-only tool calls from one
+// model response may execute concurrently. Batches containing any
+// tool not marked [tools.Tool.ReadOnly] still run sequentially. Zero or
+// one disables parallel execution.
+func (l *Loop) SetParallelToolCalls(n int) {
+	if n < 0 {
+		n = 0
+	}
+	l.parallelToolCalls = n
+}
+
+// SetMaxIterations sets the default iteration cap for a run and
+// optional per-channel overrides keyed by the request's "source"
+// routing factor. A request's own MaxIterations takes precedence over
+// both. Zero or negative values fall back to the next level.
+func (l *Loop) SetMaxIterations(n int, perChannel map[string]int) {
+	l.maxIterations = max(n, 0)
+	l.channelMaxIters = perChannel
+}
+
+// iterationLimit resolves the iteration cap for req: the request's
+// own limit, then the source channel's override, then the loop
+// default.
+func (l *Loop) iterationLimit(req *Request) int {
+	if req.MaxIterations > 0 {
+		return req.MaxIterations
+	}
+	if n := l.channelMaxIters[req.RoutingFactors["source"]]; n > 0 {
+		return n
+	}
+	if l.maxIterations > 0 {
+		return l.maxIterations
+	}
+	return iterate.DefaultMaxIterations
+}
+
+// SetIDGenerator replaces the generator for internal tool call IDs,
+// which link tool execution records, logs, and tool contexts. Tests
+// and replay inject a deterministic generator such as
+// [NewSequentialIDGenerator] so that identical inputs against a stub
+// provider yield identical tool call IDs. Nil restores the UUIDv7
+// default.
+func (l *Loop) SetIDGenerator(gen IDGenerator) {
+	l.newToolCallID = gen
+}
+
+// SetStreamHeartbeat sets the silence interval after which streaming
+// runs emit a [KindHeartbeat] keepalive event, so intermediaries do not
+// close a slow model's connection before the first token. Zero or a
+// negative interval disables heartbeats. Only runs whose request sets
+// [Request.ClientStream] emit heartbeats; background runs, whose stream
+// feeds internal progress telemetry, never do.
+func (l *Loop) SetStreamHeartbeat(interval time.Duration) {
+	if interval < 0 {
+		interval = 0
+	}
+	l.streamHeartbeat = interval
+}
+
 // DynamicToolSource supplies tools that appear and disappear at runtime,
 // outside the startup-static tool registry — companion (macOS) tools that
 // come and go as laptops connect and disconnect are the motivating case.
@@ -680,7 +792,7 @@ func (l *Loop) SetOrchestratorTools(names []string) {
 // Snapshot returns the current set of tools plus a tag→tool-name map. Both
 // are layered onto the per-run tool registry at the start of every run (see
 // [Loop.Run]); they are NOT registered on the shared registry, so a source
-// that changes between runs never races the lock-free registry. The tag
+// that changes between runs never races the lock-free tag index. The tag
 // additions feed FilterByTags so the dynamic tools stay tag-gated rather
 // than always-on. Returning empty values is a no-op.
 type DynamicToolSource interface {
@@ -720,8 +832,10 @@ func (l *Loop) SetCapabilityTags(capTags map[string]config.CapabilityTagConfig,
 	if len(capTags) == 0 {
 		return
 	}
+	l.reloadMu.Lock()
 	l.capTags = capTags
 	l.parsedTalents = parsedTalents
+	l.reloadMu.Unlock()
 
 	// Build tag index for tool filtering.
 	tagIndex := make(map[string][]string, len(capTags))
@@ -745,7 +859,31 @@ func (l *Loop) SetCapabilityTags(capTags map[string]config.CapabilityTagConfig,
 // shared model-facing renderers such as prompt summaries and capability
 // manifest/help generation.
 func (l *Loop) UseCapabilitySurface(surface []toolcatalog.CapabilitySurface) {
-	l.capSurface = toolcatalog.SortCapabilitySurface(surface)
+	sorted := toolcatalog.SortCapabilitySurface(surface)
+	l.reloadMu.Lock()
+	l.capSurface = sorted
+	l.reloadMu.Unlock()
+}
+
+// capabilityTags returns the current capability tag definitions.
+func (l *Loop) capabilityTags() map[string]config.CapabilityTagConfig {
+	l.reloadMu.RLock()
+	defer l.reloadMu.RUnlock()
+	return l.capTags
+}
+
+// talentSet returns the current parsed talents.
+func (l *Loop) talentSet() []talents.Talent {
+	l.reloadMu.RLock()
+	defer l.reloadMu.RUnlock()
+	return l.parsedTalents
+}
+
+// capabilitySurface returns the current resolved capability surface.
+func (l *Loop) capabilitySurface() []toolcatalog.CapabilitySurface {
+	l.reloadMu.RLock()
+	defer l.reloadMu.RUnlock()
+	return l.capSurface
 }
 
 // SetUsageRecorder configures persistent token usage recording. When
@@ -753,10 +891,24 @@ func (l *Loop) UseCapabilitySurface(surface []toolcatalog.CapabilitySurface) {
 // attribution and analysis.
 func (l *Loop) SetUsageRecorder(store *usage.Store, pricing map[string]config.PricingEntry, cat *fleet.Catalog) {
 	l.usageStore = store
-	l.pricing = pricing
+	l.SetPricing(pricing)
 	l.usageCatalog = cat
 }
 
+// SetPricing replaces the model pricing table used to cost usage
+// records. Safe to call while runs are in flight.
+func (l *Loop) SetPricing(pricing map[string]config.PricingEntry) {
+	l.reloadMu.Lock()
+	l.pricing = pricing
+	l.reloadMu.Unlock()
+}
+
+func (l *Loop) pricingTable() map[string]config.PricingEntry {
+	l.reloadMu.RLock()
+	defer l.reloadMu.RUnlock()
+	return l.pricing
+}
+
 // SetChannelTags configures channel-pinned tag activation. When a
 // Run() request carries a "source" hint matching a key in channelTags,
 // the listed capability tags are activated for that run in addition to
@@ -782,12 +934,13 @@ func (l *Loop) filterRuntimePinnedTags(origin SessionOrigin, tags []string) []st
 }
 
 func (l *Loop) filterOriginPinnedTagsForSource(origin SessionOrigin, tags []string, policySource string) []string {
-	if len(tags) == 0 || l.capTags == nil {
+	capTags := l.capabilityTags()
+	if len(tags) == 0 || capTags == nil {
 		return nil
 	}
 	filtered := make([]string, 0, len(tags))
 	for _, tag := range cleanUnique(tags) {
-		cfg, ok := l.capTags[tag]
+		cfg, ok := capTags[tag]
 		if !ok {
 			if l.logger != nil {
 				l.logger.Warn("session origin policy referenced inactive capability tag", "tag", tag)
@@ -859,6 +1012,32 @@ func (l *Loop) SetCapabilityTagStore(store CapabilityTagStore) {
 	l.capTagStore = store
 }
 
+// SetToolPolicyStore configures per-conversation tool allowlists. A
+// matching policy narrows the run's tools before capability tag
+// filtering, so a withheld tool is neither advertised to the model nor
+// executable, whatever tags are active.
+func (l *Loop) SetToolPolicyStore(store ToolPolicyStore) {
+	l.toolPolicy = store
+}
+
+// SetEventBus configures the bus the loop publishes live activity
+// events on: request start and completion, model selection, tool
+// calls, and compaction. They mirror the loop's own log lines, for
+// live consumers such as the /events stream.
+func (l *Loop) SetEventBus(bus *events.Bus) {
+	l.eventBus = bus
+}
+
+// publishEvent publishes an agent activity event. A nil bus drops it.
+func (l *Loop) publishEvent(kind string, data map[string]any) {
+	l.eventBus.Publish(events.Event{
+		Timestamp: time.Now(),
+		Source:    events.SourceAgent,
+		Kind:      kind,
+		Data:      data,
+	})
+}
+
 // HAInject returns the HA entity state fetcher used for resolving
 // ha-inject directives in context files. May be nil when HA is not
 // configured.
@@ -908,7 +1087,7 @@ func (l *Loop) RequestCapability(ctx context.Context, tag string) error {
 	l.updateLastRunTags(scope)
 
 	configured := "ad-hoc"
-	if _, ok := l.capTags[tag]; ok {
+	if _, ok := l.capabilityTags()[tag]; ok {
 		configured = "configured"
 	}
 	l.logger.Info("capability activated", "tag", tag, "type", configured)
@@ -975,43 +1154,6 @@ func (l *Loop) Router() *router.Router {
 	return l.router
 }
 
-// Simple greeting patterns that don't need tool calls
-var greetingPatterns = []string{
-	"hi", "hello", "hey", "howdy", "hiya", "yo",
-	"good morning", "good afternoon", "good evening",
-	"what's up", "whats up", "sup",
-}
-
-// isSimpleGreeting checks if the message is a simple greeting
-func isSimpleGreeting(msg string) bool {
-	lower := strings.ToLower(strings.TrimSpace(msg))
-	// Remove punctuation
-	lower = strings.TrimRight(lower, "!?.,")
-	for _, pattern := range greetingPatterns {
-		if lower == pattern {
-			return true
-		}
-	}
-	return false
-}
-
-// Greeting responses to cycle through
-var greetingResponses = []string{
-	"Hey! What can I help you with?",
-	"Hi there! How can I help?",
-	"Hello! What would you like me to do?",
-	"Hey! Ready to help.",
-}
-
-var greetingIndex int
-
-// getGreetingResponse returns a friendly greeting response
-func getGreetingResponse() string {
-	resp := greetingResponses[greetingIndex%len(greetingResponses)]
-	greetingIndex++
-	return resp
-}
-
 // promptSection records the name and byte boundaries of one section in
 // the assembled system prompt. Used for content retention (prompt
 // archival with section metadata) and future section-level diffing.
@@ -1032,6 +1174,14 @@ func (l *Loop) buildSystemPromptWithProfile(ctx context.Context, userMessage str
 }
 
 func (l *Loop) buildSystemPromptWithProfileSections(ctx context.Context, userMessage string, profile llm.ModelInteractionProfile) (string, []llm.PromptSection) {
+	text, sections := l.buildSystemPromptTracked(ctx, userMessage, profile)
+	return text, promptSectionsFromBoundaries(text, sections)
+}
+
+// buildSystemPromptTracked assembles the system prompt and returns it
+// with the raw section boundaries, which context-budget trimming edits
+// before they become [llm.PromptSection] values.
+func (l *Loop) buildSystemPromptTracked(ctx context.Context, userMessage string, profile llm.ModelInteractionProfile) (string, []promptSection) {
 	var sb strings.Builder
 	promptMode := agentctx.PromptModeFromContext(ctx)
 	taskPrompt := promptMode == agentctx.PromptModeTask
@@ -1072,16 +1222,8 @@ func (l *Loop) buildSystemPromptWithProfileSections(ctx context.Context, userMes
 	appendTracked("PERSONA", func() {
 		if taskPrompt {
 			sb.WriteString(prompts.DelegateSystemPrompt())
-		} else if l.coreContextProvider != nil {
-			if persona := l.coreContextProvider.personaContent(ctx); persona != "" {
-				sb.WriteString(persona)
-			} else if l.persona != "" {
-				sb.WriteString(l.persona)
-			} else {
-				sb.WriteString(prompts.BaseSystemPrompt())
-			}
-		} else if l.persona != "" {
-			sb.WriteString(l.persona)
+		} else if persona := l.currentPersona(ctx); persona != "" {
+			sb.WriteString(persona)
 		} else {
 			sb.WriteString(prompts.BaseSystemPrompt())
 		}
@@ -1114,7 +1256,7 @@ func (l *Loop) buildSystemPromptWithProfileSections(ctx context.Context, userMes
 	// 4. Talents (behavior — how should I act)
 	// Keep always-on guidance ahead of volatile context so provider-side
 	// prompt caching can retain the stable behavioral prefix.
-	alwaysOnTalents, taggedTalents := talents.SplitByTags(l.parsedTalents, tags)
+	alwaysOnTalents, taggedTalents := talents.SplitByTags(l.talentSet(), tags)
 	if !taskPrompt && alwaysOnTalents != "" {
 		appendTracked("TALENTS ALWAYS ON", func() {
 			sb.WriteString("## Behavioral Guidance\n\n")
@@ -1133,7 +1275,7 @@ func (l *Loop) buildSystemPromptWithProfileSections(ctx context.Context, userMes
 	}
 
 	// 5. Active tags (dynamic runtime state).
-	if activeSummary := toolcatalog.RenderLoadedCapabilitySummary(l.capSurface, tags); activeSummary != "" {
+	if activeSummary := toolcatalog.RenderLoadedCapabilitySummary(l.capabilitySurface(), tags); activeSummary != "" {
 		appendTracked("ACTIVE TAGS", func() {
 			sb.WriteString("## Active Tags\n\n")
 			sb.WriteString(activeSummary)
@@ -1178,8 +1320,7 @@ func (l *Loop) buildSystemPromptWithProfileSections(ctx context.Context, userMes
 		sb.WriteString(awareness.CurrentConditions(l.timezone))
 	})
 
-	text := sb.String()
-	return text, promptSectionsFromBoundaries(text, sections)
+	return sb.String(), sections
 }
 
 func (l *Loop) renderSessionOriginContext(ctx context.Context) string {
@@ -1412,12 +1553,12 @@ func (l *Loop) resolveCapabilityTagAlias(alias string) (string, bool) {
 		candidates = append(candidates, trimmed)
 	}
 	for _, candidate := range candidates {
-		for _, entry := range l.capSurface {
+		for _, entry := range l.capabilitySurface() {
 			if canonicalCapabilityAlias(entry.Tag) == candidate {
 				return entry.Tag, true
 			}
 		}
-		for tag := range l.capTags {
+		for tag := range l.capabilityTags() {
 			if canonicalCapabilityAlias(tag) == candidate {
 				return tag, true
 			}
@@ -1476,6 +1617,15 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 		convID = "default"
 	}
 
+	// Keep slow client connections alive across long silences (e.g. a
+	// slow model's time to first token). Only a stream serving a client
+	// needs keepalives; the wrapper stops when the run returns.
+	if req.ClientStream {
+		var stopHeartbeat func()
+		stream, stopHeartbeat = llm.WithHeartbeat(stream, l.streamHeartbeat)
+		defer stopHeartbeat()
+	}
+
 	// Track session activity on successful completion.
 	// Skip for lightweight requests (auxiliary) to avoid session noise.
 	defer func() {
@@ -1534,6 +1684,22 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 				attrs = append(attrs, "tools_used", resp.ToolsUsed)
 			}
 		}
+		completeData := map[string]any{
+			"request_id":      requestID,
+			"conversation_id": convID,
+			"ok":              err == nil,
+			"elapsed_ms":      time.Since(runStarted).Milliseconds(),
+		}
+		if resp != nil {
+			completeData["model"] = resp.Model
+			completeData["iterations"] = resp.Iterations
+			completeData["input_tokens"] = resp.InputTokens
+			completeData["output_tokens"] = resp.OutputTokens
+		}
+		if err != nil {
+			completeData["error"] = err.Error()
+		}
+		l.publishEvent(events.KindRequestComplete, completeData)
 		if err != nil {
 			log.Warn("request complete", append(attrs, "error", err.Error())...)
 			return
@@ -1547,8 +1713,14 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 		"mission", req.RoutingFactors["mission"],
 		"prompt_mode", req.PromptMode.OrDefault(),
 		"skip_context", req.SkipContext,
-		"max_iterations", req.MaxIterations,
+		"max_iterations", l.iterationLimit(req),
 	)
+	l.publishEvent(events.KindRequestStart, map[string]any{
+		"request_id":      requestID,
+		"conversation_id": convID,
+		"source":          req.RoutingFactors["source"],
+		"message_count":   len(req.Messages),
+	})
 
 	// Always use Thane's memory as the source of truth.
 	// For externally-managed conversations (owu-), the client sends full history
@@ -1593,7 +1765,7 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 	// Fast-path: handle simple greetings without tool calls
 	if isSimpleGreeting(userMessage) {
 		log.Debug("simple greeting detected, responding directly")
-		response := getGreetingResponse()
+		response := l.greetingResponse(ctx)
 		if err := l.memory.AddMessage(convID, "assistant", response); err != nil {
 			log.Warn("failed to store greeting response", "error", err)
 		}
@@ -1614,7 +1786,18 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 
 		// Resolve model via router (same logic as full path, but inline)
 		liteModel := req.Model
+		if liteModel == "" || liteModel == "thane" {
+			if pinned := l.defaultModelOverride(); pinned != "" {
+				liteModel = pinned
+			}
+		}
 		var liteDecision *router.Decision
+		if liteModel != "" && liteModel != "thane" && l.router != nil {
+			if reason := l.router.BudgetRejection(liteModel); reason != "" {
+				log.Warn("auxiliary model ruled out by budget; routing instead", "model", liteModel, "reason", reason)
+				liteModel = ""
+			}
+		}
 		if (liteModel == "" || liteModel == "thane") && l.router != nil {
 			liteModel, liteDecision = l.router.Route(ctx, router.Request{
 				Query:          userMessage,
@@ -1625,6 +1808,12 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 		if liteModel == "" {
 			liteModel = l.model
 		}
+		l.publishEvent(events.KindModelSelected, map[string]any{
+			"request_id":      requestID,
+			"conversation_id": convID,
+			"model":           liteModel,
+			"routed":          liteDecision != nil,
+		})
 
 		log.Info("llm call",
 			"kind", events.KindLLMCall,
@@ -1708,12 +1897,12 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 	// conversation bindings so tool gating can rely on Go-validated
 	// metadata instead of prompt reconstruction.
 	var scope *capabilityScope
-	if l.capTags != nil {
+	if capTags := l.capabilityTags(); capTags != nil {
 		var lenses []string
 		if l.lensProvider != nil {
 			lenses = l.lensProvider()
 		}
-		scope = newCapabilityScope(l.capTags, lenses)
+		scope = newCapabilityScope(capTags, lenses)
 		// Seed tags carried forward from previous loop iterations.
 		for _, tag := range req.InitialTags {
 			_ = scope.Request(tag)
@@ -1820,6 +2009,11 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 	}
 
 	llmMessages := buildInitialLLMMessages(systemPrompt, systemSections, history, req.Messages, convID, l.now())
+	// Budget trimming starts from the untrimmed messages on every
+	// rebuild, so rerouting to a larger model restores what a smaller
+	// one had to drop.
+	untrimmedMessages := llmMessages
+	historyMessages := len(llmMessages) - 1 - len(triggerMessagesForRequest(convID, req.Messages))
 	updateSystemMessage := func() {
 		if len(llmMessages) > 0 && llmMessages[0].Role == "system" {
 			llmMessages[0].Content = systemPrompt
@@ -1831,8 +2025,8 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 			return
 		}
 		usageInfo.Model = model
-		systemPrompt, systemSections = l.buildSystemPromptWithProfileSections(promptCtx, userMessage, l.modelInteractionProfileForModel(model))
-		updateSystemMessage()
+		text, bounds := l.buildSystemPromptTracked(promptCtx, userMessage, l.modelInteractionProfileForModel(model))
+		systemPrompt, systemSections, llmMessages = l.fitContextBudget(log, model, text, bounds, untrimmedMessages, historyMessages)
 	}
 
 	// Request-level tool restrictions are static for the run. Apply them
@@ -1855,11 +2049,40 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 		baseTools = baseTools.FilteredCopyExcluding(req.ExcludeTools)
 		log.Info("tools excluded from run", "excluded", req.ExcludeTools)
 	}
+	// policyAllow is the tool policy this run is held to, carried into
+	// the context of every tool call so delegates and other child work
+	// inherit it; nil when no policy applies.
+	var policyAllow []string
+	if l.toolPolicy != nil {
+		channel := req.RoutingFactors["source"]
+		if channelBinding != nil && channelBinding.Channel != "" {
+			channel = channelBinding.Channel
+		}
+		allow, matched, err := l.toolPolicy.ToolPolicyFor(convID, channel)
+		if err != nil {
+			// Fail closed: a policy we can't read may be the one
+			// keeping this conversation away from sensitive tools.
+			log.Error("failed to load conversation tool policy; withholding all tools",
+				"conversation_id", convID, "channel", channel, "error", err)
+			baseTools = baseTools.FilteredCopy(nil)
+			policyAllow = []string{}
+		} else if len(matched) > 0 {
+			policyAllow = append([]string{}, allow...)
+			var withheld []string
+			baseTools, withheld = applyToolPolicy(baseTools, allow)
+			if len(withheld) > 0 {
+				log.Info("conversation tool policy narrowed tools",
+					"policy", matched,
+					"allowed", len(baseTools.AllToolNames()),
+					"withheld", withheld)
+			}
+		}
+	}
 
 	// Determine whether tool gating is active before model selection so
 	// both router decisions and explicit-model preflight can reason
 	// about the actual tool surface this run will expose.
-	gatingActive := len(l.orchestratorTools) > 0 && (l.tools.Get("thane_now") != nil || l.tools.Get("thane_assign") != nil)
+	gatingActive := len(l.orchestratorTools) > 0 && (l.tools.Get("thane_now") != nil || l.tools.Get("thane_fanout") != nil || l.tools.Get("thane_assign") != nil)
 	if req.DelegationGating == "disabled" {
 		gatingActive = false
 	}
@@ -1906,7 +2129,27 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 	model := req.Model
 	var routerDecision *router.Decision
 
-	log.Debug("model selection start", "req_model", req.Model, "default_model", l.model)
+	log.Debug("model selection start", "req_model", req.Model, "default_model", l.DefaultModel())
+
+	if model == "" || model == "thane" {
+		if pinned := l.defaultModelOverride(); pinned != "" {
+			model = pinned
+			log.Debug("model pinned by runtime default", "model", model)
+		}
+	}
+
+	// The budget ceiling holds for models the router did not pick too.
+	// An explicit request for a paid model is refused; a pinned default
+	// gives way to routing, which picks a free model.
+	if model != "" && model != "thane" && l.router != nil {
+		if reason := l.router.BudgetRejection(model); reason != "" {
+			if model == req.Model {
+				return nil, fmt.Errorf("model %s unavailable: %s", model, reason)
+			}
+			log.Warn("pinned default model ruled out by budget; routing instead", "model", model, "reason", reason)
+			model = ""
+		}
+	}
 
 	if model == "" || model == "thane" {
 		if l.router != nil {
@@ -1969,11 +2212,13 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 
 	usageInfo.Model = model
 	usageInfo.Routed = routerDecision != nil
-	if cat := l.currentModelCatalog(); cat != nil {
-		if dep, err := cat.ResolveDeploymentRef(model); err == nil && dep.ContextWindow > 0 {
-			usageInfo.ContextWindow = dep.ContextWindow
-		}
-	}
+	l.publishEvent(events.KindModelSelected, map[string]any{
+		"request_id":      requestID,
+		"conversation_id": convID,
+		"model":           model,
+		"routed":          routerDecision != nil,
+	})
+	usageInfo.ContextWindow = l.modelContextWindow(model)
 	usageInfo.TokenCount = estimateLLMMessagesContextTokens(llmMessages)
 	if line := awareness.FormatContextUsage(usageInfo); line != "" {
 		systemPrompt += "\n" + line
@@ -1983,6 +2228,7 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 		})
 	}
 	updateSystemMessage()
+	l.capturedPrompts.capture(convID, requestID, model, systemPrompt, systemSections)
 
 	l.seedLiveRequestDetail(ctx, requestID, systemPrompt, userMessage, model, 0, llmMessages)
 
@@ -1994,19 +2240,17 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 	// Check if memory store supports tool call recording.
 	recorder, hasRecorder := l.memory.(ToolCallRecorder)
 
-	// Track whether the error handler triggered timeout recovery.
-	var timeoutRecovered bool
+	// Track whether the error handler triggered timeout recovery, and
+	// which fallback models it failed over to.
+	var recovery timeoutRecovery
+	var failovers failoverLog
 
-	// Optional per-tool timeout wrapper for request-scoped runs such as
-	// delegates. Cancelled after each tool completes.
-	var currentToolCancel context.CancelFunc
-	var currentToolStart time.Time
-	var currentToolCallID string
+	// Tool handlers report progress through tools.ReportProgress; the
+	// relay turns those reports into stream events. Nil when not
+	// streaming.
+	progressRelay := newToolProgressRelay(stream)
 
-	maxIterations := 50
-	if req.MaxIterations > 0 {
-		maxIterations = req.MaxIterations
-	}
+	maxIterations := l.iterationLimit(req)
 
 	currentTools := func() *tools.Registry {
 		toolsForIter := baseTools
@@ -2102,6 +2346,9 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 		NudgePrompt:     prompts.EmptyResponseNudge,
 		FallbackContent: firstNonEmpty(req.FallbackContent, prompts.EmptyResponseFallback),
 
+		ToolErrorReflectAfter:  l.toolErrorReflection,
+		ToolErrorReflectPrompt: prompts.ToolErrorReflection,
+
 		// Per-iteration tool definitions: recompute effective tools each
 		// iteration so tags activated via tag_activate are reflected.
 		ToolDefs: func(i int) []map[string]any {
@@ -2161,13 +2408,53 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 			return pulled
 		},
 
+		// Read-only batches may run concurrently. A call the confidence
+		// gate covers or that schedules a follow-up wake has side
+		// effects beyond the tool itself, so it is never treated as
+		// read-only here and its batch runs sequentially.
+		MaxParallelTools: l.parallelToolCalls,
+		ReadOnlyTool: func(toolName string) bool {
+			if l.confidenceGateApplies(tools.OriginSource(req.RoutingFactors), toolName) || l.tools.HasFollowUp(toolName) {
+				return false
+			}
+			t := currentTools().Get(toolName)
+			return t != nil && t.ReadOnly
+		},
+
+		// In a parallel batch only Exec runs concurrently; the engine
+		// calls OnBeforeToolExec and OnToolCallDone (audit, recorder,
+		// events) one call at a time in the model's order. Exec stays
+		// safe to overlap: each call's timing rides on its own context,
+		// currentTools reads the capability scope and registry under
+		// their locks, capToolResult spills to a uniquely named temp
+		// file, and ReadOnlyTool keeps the confidence gate and
+		// anticipateFollowUp out of parallel batches.
 		Executor: &iterate.DirectExecutor{
 			Exec: func(execCtx context.Context, name, argsJSON string) (string, error) {
+				if timing, ok := execCtx.Value(toolCallTimingKey{}).(*toolCallTiming); ok {
+					defer timing.stop()
+				}
+				if source := tools.OriginSource(req.RoutingFactors); l.confidenceGateApplies(source, name) {
+					if deferred, held := l.gateAutonomousAction(execCtx, model, DeferredAction{
+						ConversationID: convID,
+						Source:         source,
+						Tool:           name,
+						Arguments:      argsJSON,
+						Task:           userMessage,
+					}); held {
+						return deferred, nil
+					}
+				}
 				toolsForExec := currentTools()
 				if gatingActive {
 					toolsForExec = toolsForExec.FilteredCopy(l.orchestratorTools)
 				}
-				return toolsForExec.Execute(execCtx, name, argsJSON)
+				result, err := toolsForExec.Execute(execCtx, name, argsJSON)
+				if err == nil {
+					l.anticipateFollowUp(execCtx, convID, name, argsJSON, result)
+					result = l.capToolResult(execCtx, convID, name, result)
+				}
+				return result, err
 			},
 		},
 
@@ -2182,10 +2469,14 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 			// Skip rebuild when a custom SystemPrompt is in use for callers
 			// that assemble their own context externally.
 			if i > 0 && len(msgs) > 0 && msgs[0].Role == "system" && req.SystemPrompt == "" {
-				rebuilt := l.buildSystemPromptWithProfile(iterCtx, userMessage, l.modelInteractionProfileForModel(currentModel))
+				rebuilt, rebuiltBounds := l.buildSystemPromptTracked(iterCtx, userMessage, l.modelInteractionProfileForModel(currentModel))
+				// History was already fitted before the first
+				// iteration; only prompt sections are trimmed here.
+				rebuilt, rebuiltSections, _ := l.fitContextBudget(iterLog, currentModel, rebuilt, rebuiltBounds, msgs, 0)
 				// Omit FormatContextUsage — usageInfo was computed before the
 				// run and would be misleading after prompt content changes.
 				msgs[0].Content = rebuilt
+				l.capturedPrompts.capture(convID, requestID, currentModel, rebuilt, rebuiltSections)
 				systemPrompt = rebuilt // keep retained content in sync
 				systemTokens = len(rebuilt) / 4
 			}
@@ -2260,12 +2551,11 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 		},
 
 		// Error handling: timeout retry, recovery model, failover.
-		OnLLMError: l.buildLLMErrorHandler(ctx, stream, model, req, &timeoutRecovered),
+		OnLLMError: l.buildLLMErrorHandler(ctx, stream, model, req, &recovery, &failovers),
 
 		// Enrich context before each tool execution.
 		OnBeforeToolExec: func(iterCtx context.Context, i int, tc llm.ToolCall) context.Context {
-			toolCallID, _ := uuid.NewV7()
-			toolCallIDStr := toolCallID.String()
+			toolCallIDStr := l.toolCallID()
 
 			toolCtx := tools.WithConversationID(iterCtx, convID)
 			toolCtx = tools.WithChannelBinding(toolCtx, channelBinding)
@@ -2273,42 +2563,84 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 			if scope != nil {
 				toolCtx = tools.WithInheritableCapabilityTags(toolCtx, scope.InheritableTags())
 			}
+			if policyAllow != nil {
+				toolCtx = tools.WithToolPolicy(toolCtx, policyAllow)
+			}
+			sessionID := ""
 			if l.archiver != nil {
 				if sid := l.archiver.ActiveSessionID(convID); sid != "" {
+					sessionID = sid
 					toolCtx = tools.WithSessionID(toolCtx, sid)
 				}
 			}
 			toolCtx = tools.WithToolCallID(toolCtx, toolCallIDStr)
+			toolCtx = tools.WithProgressReporter(toolCtx, progressRelay.reporter(tc.Function.Name, toolCallIDStr))
 			toolCtx = tools.WithIterationIndex(toolCtx, i)
 			toolCtx = tools.WithRequestID(toolCtx, requestID)
-			if lid := loop.LoopIDFromContext(ctx); lid != "" {
-				toolCtx = tools.WithLoopID(toolCtx, lid)
-			} else if lid := req.RoutingFactors["loop_id"]; lid != "" {
-				toolCtx = tools.WithLoopID(toolCtx, lid)
+			loopID := loop.LoopIDFromContext(ctx)
+			if loopID == "" {
+				loopID = req.RoutingFactors["loop_id"]
+			}
+			if loopID != "" {
+				toolCtx = tools.WithLoopID(toolCtx, loopID)
 			}
+			// Optional per-tool timeout for request-scoped runs such as
+			// delegates. The start time and cancel func ride on the
+			// call's own context, since a parallel batch prepares every
+			// call before any of them finishes.
+			timing := &toolCallTiming{start: time.Now()}
 			if req.ToolTimeout > 0 {
-				toolCtx, currentToolCancel = context.WithTimeout(toolCtx, req.ToolTimeout)
+				toolCtx, timing.cancel = context.WithTimeout(toolCtx, req.ToolTimeout)
 			}
-			currentToolStart = time.Now()
-			currentToolCallID = toolCallIDStr
+			toolCtx = context.WithValue(toolCtx, toolCallTimingKey{}, timing)
 			logging.Logger(toolCtx).Info("tool call",
 				"kind", events.KindToolCall,
 				"iteration", i,
 				"tool", tc.Function.Name,
 				"tool_call_id", toolCallIDStr,
 			)
+			l.publishEvent(events.KindToolCall, map[string]any{
+				"request_id":      requestID,
+				"conversation_id": convID,
+				"iteration":       i,
+				"tool":            tc.Function.Name,
+				"tool_call_id":    toolCallIDStr,
+			})
 
 			// Record tool call start.
+			argsJSON := ""
+			if tc.Function.Arguments != nil {
+				argsBytes, _ := json.Marshal(tc.Function.Arguments)
+				argsJSON = string(argsBytes)
+			}
 			if hasRecorder {
-				argsJSON := ""
-				if tc.Function.Arguments != nil {
-					argsBytes, _ := json.Marshal(tc.Function.Arguments)
-					argsJSON = string(argsBytes)
-				}
 				if err := recorder.RecordToolCall(convID, "", toolCallIDStr, tc.Function.Name, argsJSON); err != nil {
 					logging.Logger(iterCtx).Warn("failed to record tool call", "error", err)
 				}
 			}
+			if l.toolAudit != nil {
+				entry := audit.Entry{
+					ID:             toolCallIDStr,
+					Tool:           tc.Function.Name,
+					Arguments:      argsJSON,
+					ConversationID: convID,
+					SessionID:      sessionID,
+					RequestID:      requestID,
+					LoopID:         loopID,
+					Iteration:      i,
+					Source:         req.RoutingFactors["source"],
+				}
+				if channelBinding != nil {
+					entry.Channel = channelBinding.Channel
+					entry.Sender = channelBinding.ContactName
+					if entry.Sender == "" {
+						entry.Sender = channelBinding.Address
+					}
+				}
+				if err := l.toolAudit.Start(iterCtx, entry); err != nil {
+					logging.Logger(iterCtx).Warn("failed to write tool audit entry", "error", err)
+				}
+			}
 
 			return toolCtx
 		},
@@ -2323,20 +2655,15 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 		},
 
 		OnToolCallDone: func(iterCtx context.Context, toolName, result, errMsg string) {
-			if currentToolCancel != nil {
-				currentToolCancel()
-				currentToolCancel = nil
-			}
 			durationMS := int64(0)
-			if !currentToolStart.IsZero() {
-				durationMS = time.Since(currentToolStart).Milliseconds()
+			if timing, ok := iterCtx.Value(toolCallTimingKey{}).(*toolCallTiming); ok {
+				if timing.cancel != nil {
+					timing.cancel()
+				}
+				durationMS = timing.duration().Milliseconds()
 			}
-			currentToolStart = time.Time{}
 			toolCallIDStr := tools.ToolCallIDFromContext(iterCtx)
-			if toolCallIDStr == "" {
-				toolCallIDStr = currentToolCallID
-			}
-			currentToolCallID = ""
+			progressRelay.finish(toolCallIDStr)
 			if stream != nil {
 				doneData := map[string]any{
 					"active_tags":     activeTagList(),
@@ -2350,6 +2677,18 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 					Data:       doneData,
 				})
 			}
+			doneEvent := map[string]any{
+				"request_id":      requestID,
+				"conversation_id": convID,
+				"tool":            toolName,
+				"tool_call_id":    toolCallIDStr,
+				"ok":              errMsg == "",
+				"duration_ms":     durationMS,
+			}
+			if kind, _, ok := tools.ParseToolError(errMsg); ok {
+				doneEvent["error_kind"] = string(kind)
+			}
+			l.publishEvent(events.KindToolDone, doneEvent)
 			toolLog := logging.Logger(iterCtx)
 			if errMsg != "" {
 				toolLog.Warn("tool done",
@@ -2377,9 +2716,17 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 					}
 				}
 			}
+			if l.toolAudit != nil && toolCallIDStr != "" {
+				// The tool context may already be cancelled by its
+				// per-tool timeout; the audit write must still land.
+				errKind, _, _ := tools.ParseToolError(errMsg)
+				if err := l.toolAudit.Complete(context.WithoutCancel(iterCtx), toolCallIDStr, result, string(errKind), errMsg); err != nil {
+					logging.Logger(iterCtx).Warn("failed to complete tool audit entry", "error", err)
+				}
+			}
 		},
 
-		// Post-response: memory storage, fact extraction, compaction.
+		// Post-response: memory storage, fact extraction, titling, compaction.
 		OnTextResponse: func(iterCtx context.Context, content string, msgs []llm.Message) {
 			if err := l.memory.AddMessage(convID, "assistant", content); err != nil {
 				logging.Logger(iterCtx).Warn("failed to store response", "error", err)
@@ -2398,6 +2745,24 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 					}
 				}()
 			}
+			// Async running title. Auxiliary requests have no stored
+			// history and never retitle the conversation.
+			if l.titler != nil && !req.SkipContext {
+				recent := recentSlice(history, 6)
+				titleMsgs := make([]memory.Message, 0, len(recent)+2)
+				titleMsgs = append(titleMsgs, recent...)
+				titleMsgs = append(titleMsgs,
+					memory.Message{Role: "user", Content: userMessage},
+					memory.Message{Role: "assistant", Content: content},
+				)
+				go func() {
+					titleCtx, cancel := context.WithTimeout(context.Background(), l.titler.Timeout())
+					defer cancel()
+					if _, err := l.titler.Update(titleCtx, convID, titleMsgs); err != nil {
+						log.Warn("conversation title update failed", "error", err)
+					}
+				}()
+			}
 			// Compaction.
 			if l.compactor != nil && l.compactor.NeedsCompaction(convID) {
 				preTokens := l.memory.GetTokenCount(convID)
@@ -2406,6 +2771,12 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 					"tokens_before", preTokens,
 					"messages_before", preMessages,
 				)
+				l.publishEvent(events.KindCompaction, map[string]any{
+					"request_id":      requestID,
+					"conversation_id": convID,
+					"tokens_before":   preTokens,
+					"messages_before": preMessages,
+				})
 				go func() {
 					compactStart := time.Now()
 					if err := l.compactor.Compact(context.Background(), convID); err != nil {
@@ -2428,6 +2799,7 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 
 	engine := &iterate.Engine{}
 	iterResult, err := engine.Run(ctx, iterCfg, llmMessages)
+	l.recordFailovers(ctx, req, &failovers, convID, sessionTag, requestID)
 	if err != nil {
 		if l.router != nil && routerDecision != nil {
 			latency := time.Since(startTime).Milliseconds()
@@ -2457,7 +2829,7 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 		}
 	}
 	// Detect when the error handler triggered timeout recovery.
-	if timeoutRecovered {
+	if recovery.recovered {
 		finishReason = "timeout_recovery"
 	}
 
@@ -2499,12 +2871,12 @@ func (l *Loop) Run(ctx context.Context, req *Request, stream StreamCallback) (re
 		SessionID:                sessionID,
 		RequestID:                requestID,
 		ActiveTags:               activeTags,
-		LoadedCapabilities:       toolcatalog.BuildLoadedCapabilityEntries(l.capSurface, activeTags),
+		LoadedCapabilities:       toolcatalog.BuildLoadedCapabilityEntries(l.capabilitySurface(), activeTags),
 	}
 
 	l.recordLiveRequestDetail(ctx, requestID, systemPrompt, userMessage, iterResult)
 
-	l.recordUsage(ctx, req, iterResult.Model, iterResult.InputTokens, iterResult.OutputTokens, iterResult.CacheCreationInputTokens, iterResult.CacheCreation5mInputTokens, iterResult.CacheCreation1hInputTokens, iterResult.CacheReadInputTokens, convID, sessionTag, requestID, iterResult.UpstreamRequestID)
+	l.recordRunUsage(ctx, req, iterResult, &recovery, &failovers, convID, sessionTag, requestID)
 	l.archiveIterations(log, convID, iterResult.Iterations)
 
 	// Content retention is fire-and-forget with a short deadline so it
@@ -2537,9 +2909,125 @@ func (l *Loop) conversationChannelBinding(conversationID string) *memory.Channel
 	return conv.Metadata.ChannelBinding.Clone()
 }
 
+// toolCallTimingKey carries a call's [toolCallTiming] on its tool
+// context from OnBeforeToolExec to OnToolCallDone.
+type toolCallTimingKey struct{}
+
+// toolCallTiming is the per-call state OnToolCallDone needs: when the
+// call started and finished, and the cancel func of its per-tool
+// timeout, if any. The engine reports a parallel batch only once every
+// call in it is done, so the end is stamped by the executor.
+type toolCallTiming struct {
+	start  time.Time
+	end    time.Time
+	cancel context.CancelFunc
+}
+
+// stop records the end of the call's execution.
+func (t *toolCallTiming) stop() { t.end = time.Now() }
+
+// duration returns how long the call ran. A call that never reached
+// the executor (skipped or blocked) counts until now.
+func (t *toolCallTiming) duration() time.Duration {
+	if t.end.IsZero() {
+		return time.Since(t.start)
+	}
+	return t.end.Sub(t.start)
+}
+
+// timeoutRecovery records how the LLM error handler salvaged a run
+// whose model kept timing out.
+type timeoutRecovery struct {
+	// recovered is set when a recovery summary or static fallback
+	// replaced the timed-out model's response.
+	recovered bool
+
+	// failedModel is the model that timed out.
+	failedModel string
+
+	// response is the recovery model's reply, kept so its usage is
+	// recorded under its own role instead of the run's. Nil when no
+	// recovery model answered.
+	response *llm.ChatResponse
+}
+
+// failoverLog collects the fallback hops the LLM error handler took
+// during a run so they can be recorded as usage.
+type failoverLog struct {
+	hops []failoverHop
+}
+
+// failoverHop is one move from a failed model to the next one tried.
+type failoverHop struct {
+	from, to, reason string
+
+	// response is the reply of the call to to, kept so its usage is
+	// recorded under the failover role. Nil when that call failed too.
+	response *llm.ChatResponse
+}
+
+func (f *failoverLog) add(from, to string, err error) {
+	if f == nil {
+		return
+	}
+	f.hops = append(f.hops, failoverHop{from: from, to: to, reason: err.Error()})
+}
+
+// answered records resp as the reply to the most recent hop's call.
+func (f *failoverLog) answered(resp *llm.ChatResponse) {
+	if f == nil || len(f.hops) == 0 {
+		return
+	}
+	f.hops[len(f.hops)-1].response = resp
+}
+
+// answers returns the replies of every fallback call that answered.
+func (f *failoverLog) answers() []*llm.ChatResponse {
+	if f == nil {
+		return nil
+	}
+	var out []*llm.ChatResponse
+	for _, hop := range f.hops {
+		if hop.response != nil {
+			out = append(out, hop.response)
+		}
+	}
+	return out
+}
+
+// failoverChain returns the models to try, in order, after model fails
+// with a non-timeout error: its configured fallback chain, then the
+// default model as the last resort. The default is the pinned runtime
+// default when set, else the router's default, else the loop's static
+// startup default. Once the budget ceiling is reached, paid hops are
+// dropped and a paid default gives way to the router's free fallback.
+func (l *Loop) failoverChain(model string) []string {
+	var chain []string
+	fallbackModel := l.model
+	if l.router != nil {
+		chain = l.router.FallbackChain(model)
+		if def := l.router.DefaultModel(); def != "" {
+			fallbackModel = def
+		}
+	}
+	if pinned := l.defaultModelOverride(); pinned != "" {
+		fallbackModel = pinned
+	}
+	if l.router != nil && l.router.BudgetRejection(fallbackModel) != "" {
+		fallbackModel = l.router.BudgetFallback()
+		if l.router.BudgetRejection(fallbackModel) != "" {
+			return chain // no free model to fall back to
+		}
+	}
+	if fallbackModel != model && !slices.Contains(chain, fallbackModel) {
+		chain = append(chain, fallbackModel)
+	}
+	return chain
+}
+
 // buildLLMErrorHandler returns the OnLLMError callback that implements
 // the agent's timeout retry, recovery model downshift, and failover logic.
-func (l *Loop) buildLLMErrorHandler(ctx context.Context, stream llm.StreamCallback, defaultModel string, req *Request, timeoutRecovered *bool) func(context.Context, error, string, []llm.Message, []map[string]any, llm.StreamCallback) (*llm.ChatResponse, string, error) {
+func (l *Loop) buildLLMErrorHandler(ctx context.Context, stream llm.StreamCallback, defaultModel string, req *Request, recovery *timeoutRecovery, failovers *failoverLog) func(context.Context, error, string, []llm.Message, []map[string]any, llm.StreamCallback) (*llm.ChatResponse, string, error) {
 	explicitModelRequested := strings.TrimSpace(req.Model) != ""
 
 	return func(iterCtx context.Context, err error, model string,
@@ -2580,41 +3068,44 @@ func (l *Loop) buildLLMErrorHandler(ctx context.Context, stream llm.StreamCallba
 					return nil, "", retryErr
 				}
 			}
-			// Retries exhausted. Downshift to recovery model only if
-			// configured AND tool calls were already completed — a plain
-			// timeout on the first LLM call (no tool work done) should
-			// surface the static fallback, not a misleading "recovery" summary.
-			if l.recoveryModel != "" && len(toolsUsedFromMessages(msgs)) > 0 {
+			// Retries exhausted. Downshift to a recovery model only if
+			// one is available AND tool calls were already completed — a
+			// plain timeout on the first LLM call (no tool work done)
+			// should surface the static fallback, not a misleading
+			// "recovery" summary.
+			recovery.failedModel = model
+			used := toolsUsedFromMessages(msgs)
+			if recoveryModel := l.timeoutRecoveryModel(iterCtx, model); recoveryModel != "" && len(used) > 0 {
 				iterLog.Warn("retries exhausted, downshifting to recovery model",
-					"recovery_model", l.recoveryModel,
+					"recovery_model", recoveryModel,
 				)
-				recoveryMessages := buildRecoveryPrompt(msgs, toolsUsedFromMessages(msgs))
+				recoveryMessages := buildRecoveryPrompt(msgs, used)
 				recoveryCtx, recoveryCancel := context.WithTimeout(context.Background(), timeoutRecoveryDeadline)
-				resp, recoveryErr := l.llm.ChatStream(recoveryCtx, l.recoveryModel, recoveryMessages, nil, stream)
+				resp, recoveryErr := l.llm.ChatStream(recoveryCtx, recoveryModel, recoveryMessages, nil, stream)
 				recoveryCancel()
 				if recoveryErr != nil {
 					iterLog.Error("recovery model also failed",
 						"error", recoveryErr,
-						"recovery_model", l.recoveryModel,
+						"recovery_model", recoveryModel,
 					)
 					// Return a static recovery response as content.
 					return &llm.ChatResponse{
-						Model:   l.recoveryModel,
+						Model:   recoveryModel,
 						Message: llm.Message{Role: "assistant", Content: prompts.TimeoutRecoveryEmpty},
-					}, l.recoveryModel, nil
+					}, recoveryModel, nil
 				}
-				iterLog.Info("timeout recovery successful", "recovery_model", l.recoveryModel)
+				iterLog.Info("timeout recovery successful", "recovery_model", recoveryModel)
 				if resp.Message.Content == "" {
 					resp.Message.Content = prompts.TimeoutRecoveryEmpty
 				}
-				*timeoutRecovered = true
-				return resp, l.recoveryModel, nil
+				recovery.recovered = true
+				recovery.response = resp
+				return resp, recoveryModel, nil
 			}
 			// No recovery model — return a static fallback response
 			// so the user sees something rather than an error.
 			iterLog.Error("LLM timeout with no recovery model, returning static fallback")
-			*timeoutRecovered = true
-			used := toolsUsedFromMessages(msgs)
+			recovery.recovered = true
 			names := make([]string, 0, len(used))
 			for name := range used {
 				names = append(names, name)
@@ -2665,28 +3156,32 @@ func (l *Loop) buildLLMErrorHandler(ctx context.Context, stream llm.StreamCallba
 			return nil, "", err
 		}
 
-		// Non-timeout error: failover to the router's current default
-		// model when available so live routing policy updates apply here
-		// too. Fall back to the loop's static startup default only when
-		// no router is configured.
-		fallbackModel := l.model
-		if l.router != nil && l.router.DefaultModel() != "" {
-			fallbackModel = l.router.DefaultModel()
-		}
-		if model != fallbackModel {
-			iterLog.Info("attempting failover", "from", model, "to", fallbackModel)
+		// Non-timeout error: walk the model's fallback chain, then the
+		// runtime default when one is pinned, else the router's current
+		// default so live routing policy updates apply here too.
+		from, lastErr := model, err
+		for _, next := range l.failoverChain(model) {
+			iterLog.Info("attempting failover", "from", from, "to", next)
+			failovers.add(from, next, lastErr)
 			if l.failoverHandler != nil {
-				if ferr := l.failoverHandler.OnFailover(iterCtx, model, fallbackModel, err.Error()); ferr != nil {
+				if ferr := l.failoverHandler.OnFailover(iterCtx, from, next, lastErr.Error()); ferr != nil {
 					iterLog.Warn("failover handler failed", "error", ferr)
 				}
 			}
-			resp, failErr := l.llm.ChatStream(iterCtx, fallbackModel, msgs, toolDefs, stream)
-			if failErr != nil {
-				iterLog.Error("failover also failed", "error", failErr, "model", fallbackModel)
-				return nil, "", failErr
+			resp, failErr := l.llm.ChatStream(iterCtx, next, msgs, toolDefs, stream)
+			if failErr == nil {
+				iterLog.Info("failover successful", "model", next)
+				failovers.answered(resp)
+				return resp, next, nil
+			}
+			if cancelErr := canceledContextError(failErr, ctx, iterCtx); cancelErr != nil {
+				return nil, "", cancelErr
 			}
-			iterLog.Info("failover successful", "model", fallbackModel)
-			return resp, fallbackModel, nil
+			iterLog.Error("failover also failed", "error", failErr, "model", next)
+			from, lastErr = next, failErr
+		}
+		if from != model {
+			return nil, "", lastErr
 		}
 
 		return nil, "", err
@@ -2808,6 +3303,34 @@ const (
 	maxToolResultPreview = 200
 )
 
+// timeoutRecoveryModel picks the model that summarizes a timed-out
+// run: the configured recovery model when set and not ruled out by the
+// budget ceiling, otherwise the router's pick for a tool-less request
+// carrying [router.HintRecovery]. Returns "" when neither is available.
+func (l *Loop) timeoutRecoveryModel(ctx context.Context, failedModel string) string {
+	if l.router == nil {
+		return l.recoveryModel
+	}
+	if l.recoveryModel != "" {
+		reason := l.router.BudgetRejection(l.recoveryModel)
+		if reason == "" {
+			return l.recoveryModel
+		}
+		logging.Logger(ctx).Warn("recovery model ruled out by budget; routing instead", "model", l.recoveryModel, "reason", reason)
+	}
+	model, decision := l.router.Route(ctx, router.Request{
+		Priority:       router.PriorityInteractive,
+		RoutingFactors: map[string]string{router.HintRecovery: "true"},
+	})
+	if decision != nil && decision.NoEligible {
+		return ""
+	}
+	if model == failedModel {
+		logging.Logger(ctx).Warn("router picked the timed-out model for recovery", "model", model)
+	}
+	return model
+}
+
 // isTimeout reports whether err is a timeout or deadline-exceeded error.
 // It checks for context.DeadlineExceeded and common provider-level
 // timeout indicators (Anthropic overload, HTTP 529). String matching
@@ -2871,7 +3394,7 @@ func buildRecoveryPrompt(messages []llm.Message, toolsUsed map[string]int) []llm
 		}
 		status := "success"
 		preview := msg.Content
-		if strings.HasPrefix(preview, "Error:") {
+		if _, _, ok := tools.ParseToolError(preview); ok || strings.HasPrefix(preview, "Error:") {
 			status = "error"
 		}
 		if runes := []rune(preview); len(runes) > maxToolResultPreview {
@@ -3383,9 +3906,112 @@ func (l *Loop) recordUsage(ctx context.Context, req *Request, model string, tota
 	if l.usageStore == nil {
 		return
 	}
+	role, taskName := usageRole(req)
+	l.recordUsageAs(ctx, role, taskName, model, totalIn, totalOut, cacheCreateIn, cacheCreate5m, cacheCreate1h, cacheReadIn, convID, sessionTag, requestID, upstreamRequestID)
+}
+
+// recordRunUsage records usage for a completed iteration run. Calls
+// recorded under their own role come out of the run's record so nothing
+// is counted twice: a recovery model's reply to a timed-out run (role
+// "recovery", with the remainder attributed to the model that timed
+// out) and each fallback call that answered after a failover (role
+// "failover", written by [Loop.recordFailovers]).
+func (l *Loop) recordRunUsage(ctx context.Context, req *Request, res *iterate.Result, recovery *timeoutRecovery, failovers *failoverLog, convID, sessionTag, requestID string) {
+	rec := recovery.response
+	split := failovers.answers()
+	if rec != nil {
+		split = append(split, rec)
+	}
+	if len(split) == 0 {
+		l.recordUsage(ctx, req, res.Model, res.InputTokens, res.OutputTokens, res.CacheCreationInputTokens, res.CacheCreation5mInputTokens, res.CacheCreation1hInputTokens, res.CacheReadInputTokens, convID, sessionTag, requestID, res.UpstreamRequestID)
+		return
+	}
+	if l.usageStore == nil {
+		return
+	}
+
+	in, out := res.InputTokens, res.OutputTokens
+	cacheCreate, cache5m, cache1h, cacheRead := res.CacheCreationInputTokens, res.CacheCreation5mInputTokens, res.CacheCreation1hInputTokens, res.CacheReadInputTokens
+	splitIDs := make(map[string]bool, len(split))
+	for _, r := range split {
+		in -= r.InputTokens
+		out -= r.OutputTokens
+		cacheCreate -= r.CacheCreationInputTokens
+		cache5m -= r.CacheCreation5mInputTokens
+		cache1h -= r.CacheCreation1hInputTokens
+		cacheRead -= r.CacheReadInputTokens
+		if r.UpstreamRequestID != "" {
+			splitIDs[r.UpstreamRequestID] = true
+		}
+	}
+
+	// The run's upstream ID may be a split call's; attribute the run's
+	// record to its own last call instead.
+	upstreamID := ""
+	for i := len(res.Iterations) - 1; i >= 0; i-- {
+		if id := res.Iterations[i].UpstreamRequestID; id != "" && !splitIDs[id] {
+			upstreamID = id
+			break
+		}
+	}
+
+	model := res.Model
+	if rec != nil {
+		model = recovery.failedModel
+	}
+	role, taskName := usageRole(req)
+	l.recordUsageAs(ctx, role, taskName, model, in, out, cacheCreate, cache5m, cache1h, cacheRead, convID, sessionTag, requestID, upstreamID)
+	if rec != nil {
+		l.recordUsageAs(ctx, "recovery", taskName, rec.Model, rec.InputTokens, rec.OutputTokens, rec.CacheCreationInputTokens, rec.CacheCreation5mInputTokens, rec.CacheCreation1hInputTokens, rec.CacheReadInputTokens, convID, sessionTag, requestID, rec.UpstreamRequestID)
+	}
+}
+
+// recordFailovers persists one "failover" usage record per fallback
+// call made during the run. Each record is the call to the fallback
+// model: FailoverFrom names the model that failed and FailoverReason
+// why. A call that answered carries its real tokens and cost; one that
+// failed in turn reports none.
+func (l *Loop) recordFailovers(ctx context.Context, req *Request, failovers *failoverLog, convID, sessionTag, requestID string) {
+	if l.usageStore == nil || len(failovers.hops) == 0 {
+		return
+	}
+	_, taskName := usageRole(req)
+	for _, hop := range failovers.hops {
+		identity := usage.ResolveModelIdentity(hop.to, l.currentModelCatalog())
+		rec := usage.Record{
+			Timestamp:      time.Now(),
+			RequestID:      requestID,
+			SessionID:      sessionTag,
+			ConversationID: convID,
+			Model:          identity.Model,
+			UpstreamModel:  identity.UpstreamModel,
+			Resource:       identity.Resource,
+			Provider:       identity.Provider,
+			Role:           "failover",
+			TaskName:       taskName,
+			FailoverFrom:   hop.from,
+			FailoverTo:     hop.to,
+			FailoverReason: hop.reason,
+		}
+		if r := hop.response; r != nil {
+			rec.UpstreamRequestID = r.UpstreamRequestID
+			rec.InputTokens = r.InputTokens
+			rec.OutputTokens = r.OutputTokens
+			rec.CacheCreationInputTokens = r.CacheCreationInputTokens
+			rec.CacheCreation5mInputTokens = r.CacheCreation5mInputTokens
+			rec.CacheCreation1hInputTokens = r.CacheCreation1hInputTokens
+			rec.CacheReadInputTokens = r.CacheReadInputTokens
+			rec.CostUSD = usage.ComputeDetailedCostForIdentityWithTTL(identity, r.InputTokens, r.CacheCreationInputTokens, r.CacheCreation5mInputTokens, r.CacheCreation1hInputTokens, r.CacheReadInputTokens, r.OutputTokens, l.pricingTable())
+		}
+		if err := l.usageStore.Record(ctx, rec); err != nil {
+			l.logger.Warn("failed to record failover", "error", err, "from", hop.from, "to", hop.to)
+		}
+	}
+}
 
-	role := "interactive"
-	taskName := ""
+// usageRole returns the usage role and task name for req.
+func usageRole(req *Request) (role, taskName string) {
+	role = "interactive"
 	if req.UsageRole != "" {
 		role = req.UsageRole
 	}
@@ -3401,9 +4027,14 @@ func (l *Loop) recordUsage(ctx context.Context, req *Request, model string, tota
 			taskName = req.RoutingFactors["task"]
 		}
 	}
+	return role, taskName
+}
 
+// recordUsageAs persists a usage record under an explicit role. See
+// [Loop.recordUsage] for the token arguments.
+func (l *Loop) recordUsageAs(ctx context.Context, role, taskName, model string, totalIn, totalOut, cacheCreateIn, cacheCreate5m, cacheCreate1h, cacheReadIn int, convID, sessionTag, requestID, upstreamRequestID string) {
 	identity := usage.ResolveModelIdentity(model, l.currentModelCatalog())
-	cost := usage.ComputeDetailedCostForIdentityWithTTL(identity, totalIn, cacheCreateIn, cacheCreate5m, cacheCreate1h, cacheReadIn, totalOut, l.pricing)
+	cost := usage.ComputeDetailedCostForIdentityWithTTL(identity, totalIn, cacheCreateIn, cacheCreate5m, cacheCreate1h, cacheReadIn, totalOut, l.pricingTable())
 	rec := usage.Record{
 		Timestamp:                  time.Now(),
 		RequestID:                  requestID,
@@ -3432,3 +4063,41 @@ func (l *Loop) recordUsage(ctx context.Context, req *Request, model string, tota
 		)
 	}
 }
+
+// RecordPromptUsage persists usage for an internal LLM call made outside
+// the agent loop, such as fact extraction or session metadata, under the
+// "auxiliary" role. taskName names the call and promptVersion is the
+// version of the prompt template it rendered, so usage can be compared
+// across prompt revisions. A no-op when usage recording is disabled.
+func (l *Loop) RecordPromptUsage(ctx context.Context, taskName, model, promptVersion string, resp *llm.ChatResponse) {
+	if l.usageStore == nil || resp == nil {
+		return
+	}
+	identity := usage.ResolveModelIdentity(model, l.currentModelCatalog())
+	cost := usage.ComputeDetailedCostForIdentityWithTTL(identity, resp.InputTokens, resp.CacheCreationInputTokens, resp.CacheCreation5mInputTokens, resp.CacheCreation1hInputTokens, resp.CacheReadInputTokens, resp.OutputTokens, l.pricingTable())
+	rec := usage.Record{
+		Timestamp:                  time.Now(),
+		RequestID:                  generateRequestID(),
+		UpstreamRequestID:          resp.UpstreamRequestID,
+		Model:                      identity.Model,
+		UpstreamModel:              identity.UpstreamModel,
+		Resource:                   identity.Resource,
+		Provider:                   identity.Provider,
+		InputTokens:                resp.InputTokens,
+		OutputTokens:               resp.OutputTokens,
+		CacheCreationInputTokens:   resp.CacheCreationInputTokens,
+		CacheCreation5mInputTokens: resp.CacheCreation5mInputTokens,
+		CacheCreation1hInputTokens: resp.CacheCreation1hInputTokens,
+		CacheReadInputTokens:       resp.CacheReadInputTokens,
+		CostUSD:                    cost,
+		Role:                       "auxiliary",
+		TaskName:                   taskName,
+		PromptVersion:              promptVersion,
+	}
+	if err := l.usageStore.Record(ctx, rec); err != nil {
+		l.logger.Warn("failed to record usage",
+			"error", err,
+			"task", taskName,
+		)
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/nugget/thane-ai-agent/internal/model/fleet"
 	"github.com/nugget/thane-ai-agent/internal/model/llm"
 	"github.com/nugget/thane-ai-agent/internal/model/router"
+	"github.com/nugget/thane-ai-agent/internal/platform/logging"
 )
 
 const estimatedImageContextTokens = 1536
@@ -64,6 +66,41 @@ func (e *NoEligibleModelError) Error() string {
 	return base
 }
 
+// SetDefaultModel switches the model used for requests that do not
+// name one. Choosing any model other than the startup default pins
+// those requests to it, bypassing the router, as if they had named it
+// explicitly; choosing the startup default (or "") clears the pin and
+// restores normal routing. The caller validates name against the
+// configured models. Safe to call concurrently with Run.
+func (l *Loop) SetDefaultModel(name string) {
+	name = strings.TrimSpace(name)
+	if name == l.model {
+		name = ""
+	}
+	l.defaultModelMu.Lock()
+	l.defaultOverride = name
+	l.defaultModelMu.Unlock()
+	l.logger.Info("default model changed", "model", l.DefaultModel(), "pinned", name != "")
+}
+
+// DefaultModel returns the model used for requests that do not name
+// one: the runtime default set with [Loop.SetDefaultModel], else the
+// startup default.
+func (l *Loop) DefaultModel() string {
+	if pinned := l.defaultModelOverride(); pinned != "" {
+		return pinned
+	}
+	return l.model
+}
+
+// defaultModelOverride returns the runtime default model, or "" when
+// none is pinned.
+func (l *Loop) defaultModelOverride() string {
+	l.defaultModelMu.RLock()
+	defer l.defaultModelMu.RUnlock()
+	return l.defaultOverride
+}
+
 func (l *Loop) currentModelCatalog() *fleet.Catalog {
 	if l == nil {
 		return nil
@@ -74,6 +111,56 @@ func (l *Loop) currentModelCatalog() *fleet.Catalog {
 	return l.usageCatalog
 }
 
+// sideChat makes a tool-less model call on the loop's behalf outside a
+// conversation turn, such as generating greetings or rating an action.
+// model is the caller's preference; "" or "thane" routes, and a
+// preference the budget ceiling rules out is replaced by the router's
+// background pick. The outcome is reported to the router and usage is
+// recorded under the auxiliary role as taskName, like any other call.
+func (l *Loop) sideChat(ctx context.Context, taskName, model string, msgs []llm.Message) (*llm.ChatResponse, error) {
+	var decision *router.Decision
+	if model != "" && model != "thane" && l.router != nil {
+		if reason := l.router.BudgetRejection(model); reason != "" {
+			logging.Logger(ctx).Warn("auxiliary model ruled out by budget; routing instead",
+				"task", taskName, "model", model, "reason", reason)
+			model = ""
+		}
+	}
+	if (model == "" || model == "thane") && l.router != nil {
+		model, decision = l.router.Route(ctx, router.Request{
+			Priority:       router.PriorityBackground,
+			RoutingFactors: map[string]string{router.FactorMission: "background"},
+		})
+		if decision != nil && decision.NoEligible {
+			return nil, fmt.Errorf("%s: no eligible model", taskName)
+		}
+	}
+	if model == "" || model == "thane" {
+		model = l.model
+	}
+
+	start := time.Now()
+	resp, err := l.llm.Chat(ctx, model, msgs, nil)
+	if err != nil {
+		if decision != nil {
+			l.router.RecordFailure(decision.RequestID, time.Since(start).Milliseconds(), 0, isTimeout(err))
+		}
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("empty %s response", taskName)
+	}
+	if decision != nil {
+		l.router.RecordOutcome(decision.RequestID, time.Since(start).Milliseconds(), resp.InputTokens+resp.OutputTokens, true)
+	}
+	usedModel := resp.Model
+	if usedModel == "" {
+		usedModel = model
+	}
+	l.RecordPromptUsage(ctx, taskName, usedModel, "", resp)
+	return resp, nil
+}
+
 func (l *Loop) preflightExplicitModel(ref string, needsTools, needsStreaming, needsImages bool, contextSize int) (string, error) {
 	ref = strings.TrimSpace(ref)
 	if ref == "" || ref == "thane" {
@@ -1281,3 +1281,61 @@ func TestRun_RoutedImageRequestRejectsWhenNoEligibleImageCapableDeploymentExists
 		t.Fatalf("llm calls = %d, want 0 when routing rejects", len(mock.calls))
 	}
 }
+
+func TestRun_RuntimeDefaultModelPinsUnnamedRequests(t *testing.T) {
+	reply := func(model string) *llm.ChatResponse {
+		return &llm.ChatResponse{Model: model, Message: llm.Message{Role: "assistant", Content: "ok"}}
+	}
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		reply("claude-sonnet-4-20250514"),
+		reply("qwen3:8b"),
+	}}
+	loop := buildTestLoop(mock, nil)
+	loop.model = "qwen3:8b"
+
+	cfg := &config.Config{
+		Models: config.ModelsConfig{
+			Default:    "qwen3:8b",
+			LocalFirst: true,
+			Resources: map[string]config.ModelServerConfig{
+				"local": {URL: "http://localhost:11434", Provider: "ollama"},
+				"cloud": {URL: "https://api.anthropic.com", Provider: "anthropic"},
+			},
+			Available: []config.ModelConfig{
+				{Name: "qwen3:8b", Resource: "local", SupportsTools: true, ContextWindow: 200000, Speed: 10, Quality: 7, CostTier: 0},
+				{Name: "claude-sonnet-4-20250514", Resource: "cloud", SupportsTools: true, ContextWindow: 200000, Speed: 4, Quality: 9, CostTier: 3},
+			},
+		},
+	}
+	registry := testModelRegistryFromConfig(t, cfg)
+	loop.UseModelRegistry(registry)
+	loop.router = router.NewRouter(slog.Default(), registry.Catalog().RouterConfig(32))
+
+	run := func() {
+		t.Helper()
+		if _, err := loop.Run(context.Background(), &Request{
+			Messages: []Message{{Role: "user", Content: "what is the status"}},
+		}, nil); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+
+	loop.SetDefaultModel("claude-sonnet-4-20250514")
+	if got := loop.DefaultModel(); got != "claude-sonnet-4-20250514" {
+		t.Fatalf("DefaultModel() = %q after SetDefaultModel", got)
+	}
+	run()
+	if got := mock.calls[0].Model; got != "claude-sonnet-4-20250514" {
+		t.Errorf("pinned call model = %q, want claude-sonnet-4-20250514", got)
+	}
+
+	// Choosing the startup default clears the pin and routes again.
+	loop.SetDefaultModel("qwen3:8b")
+	if got := loop.defaultModelOverride(); got != "" {
+		t.Errorf("override = %q after selecting the startup default, want cleared", got)
+	}
+	run()
+	if got := mock.calls[1].Model; got != "qwen3:8b" {
+		t.Errorf("routed call model = %q, want qwen3:8b", got)
+	}
+}
@@ -0,0 +1,188 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/platform/events"
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+func multiToolCallResponse(names ...string) *llm.ChatResponse {
+	calls := make([]llm.ToolCall, 0, len(names))
+	for _, name := range names {
+		tc := llm.ToolCall{ID: "call-" + name}
+		tc.Function.Name = name
+		calls = append(calls, tc)
+	}
+	return &llm.ChatResponse{
+		Model:   "test-model",
+		Message: llm.Message{Role: "assistant", ToolCalls: calls},
+	}
+}
+
+// registerRendezvousTools registers read-only tools that each block
+// until all of them are running, so a batch of them only completes when
+// executed concurrently.
+func registerRendezvousTools(loop *Loop, names ...string) {
+	var started sync.WaitGroup
+	started.Add(len(names))
+	all := make(chan struct{})
+	go func() { started.Wait(); close(all) }()
+
+	for _, name := range names {
+		loop.tools.Register(&tools.Tool{
+			Name:        name,
+			ReadOnly:    true,
+			Description: "test tool " + name,
+			Parameters:  map[string]any{"type": "object", "properties": map[string]any{}},
+			Handler: func(context.Context, map[string]any) (string, error) {
+				started.Done()
+				select {
+				case <-all:
+					return name + " state", nil
+				case <-time.After(2 * time.Second):
+					return "", fmt.Errorf("%s ran alone", name)
+				}
+			},
+		})
+	}
+}
+
+func TestRun_ParallelToolCallsRunsReadOnlyBatchConcurrently(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		multiToolCallResponse("read_kitchen", "read_garage"),
+		textResponse("Both are fine."),
+	}}
+	loop := buildTestLoop(mock, nil)
+	registerRendezvousTools(loop, "read_kitchen", "read_garage")
+	loop.SetParallelToolCalls(4)
+
+	if _, err := loop.Run(context.Background(), &Request{
+		Messages: []Message{{Role: "user", Content: "check the house"}},
+	}, nil); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	var results []string
+	for _, m := range mock.calls[1].Messages {
+		if m.Role == "tool" {
+			results = append(results, m.Content)
+		}
+	}
+	if len(results) != 2 || results[0] != "read_kitchen state" || results[1] != "read_garage state" {
+		t.Errorf("tool results = %q, want both in call order", results)
+	}
+}
+
+func TestRun_ParallelToolCallsTimeEachCallSeparately(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		multiToolCallResponse("read_fast", "read_slow"),
+		textResponse("Done."),
+	}}
+	loop := buildTestLoop(mock, nil)
+	loop.SetParallelToolCalls(4)
+	bus := events.New()
+	loop.SetEventBus(bus)
+	ch := bus.Subscribe(64)
+	defer bus.Unsubscribe(ch)
+
+	var mu sync.Mutex
+	toolCtxs := map[string]context.Context{}
+	for name, d := range map[string]time.Duration{"read_fast": 0, "read_slow": 150 * time.Millisecond} {
+		loop.tools.Register(&tools.Tool{
+			Name:        name,
+			ReadOnly:    true,
+			Description: "test tool " + name,
+			Parameters:  map[string]any{"type": "object", "properties": map[string]any{}},
+			Handler: func(ctx context.Context, _ map[string]any) (string, error) {
+				mu.Lock()
+				toolCtxs[name] = ctx
+				mu.Unlock()
+				time.Sleep(d)
+				return name + " state", nil
+			},
+		})
+	}
+
+	if _, err := loop.Run(context.Background(), &Request{
+		Messages:    []Message{{Role: "user", Content: "check the house"}},
+		ToolTimeout: time.Minute,
+	}, nil); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	durations := map[string]int64{}
+	for len(ch) > 0 {
+		evt := <-ch
+		if evt.Kind == events.KindToolDone {
+			name, _ := evt.Data["tool"].(string)
+			durations[name], _ = evt.Data["duration_ms"].(int64)
+		}
+	}
+	if durations["read_slow"] < 150 {
+		t.Errorf("read_slow duration = %dms, want at least 150", durations["read_slow"])
+	}
+	if durations["read_fast"] >= 150 {
+		t.Errorf("read_fast duration = %dms, want its own time, not the batch's", durations["read_fast"])
+	}
+
+	// Every call's per-tool timeout is released when it finishes, not
+	// just the last one prepared.
+	if len(toolCtxs) != 2 {
+		t.Fatalf("tool contexts = %d, want 2", len(toolCtxs))
+	}
+	for name, ctx := range toolCtxs {
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			t.Errorf("%s tool context err = %v, want cancelled", name, ctx.Err())
+		}
+	}
+}
+
+func TestRun_ParallelToolCallsSerializeFollowUpTools(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		multiToolCallResponse("read_kitchen", "read_dishwasher"),
+		textResponse("Done."),
+	}}
+	loop := buildTestLoop(mock, nil)
+	loop.SetParallelToolCalls(4)
+
+	var mu sync.Mutex
+	running, peak := 0, 0
+	for _, name := range []string{"read_kitchen", "read_dishwasher"} {
+		loop.tools.Register(&tools.Tool{
+			Name:        name,
+			ReadOnly:    true,
+			Description: "test tool " + name,
+			Parameters:  map[string]any{"type": "object", "properties": map[string]any{}},
+			Handler: func(context.Context, map[string]any) (string, error) {
+				mu.Lock()
+				running++
+				peak = max(peak, running)
+				mu.Unlock()
+				time.Sleep(50 * time.Millisecond)
+				mu.Lock()
+				running--
+				mu.Unlock()
+				return name + " state", nil
+			},
+		})
+	}
+	if err := loop.tools.DeclareFollowUp("read_dishwasher", tools.FollowUp{Window: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loop.Run(context.Background(), &Request{
+		Messages: []Message{{Role: "user", Content: "check the dishwasher"}},
+	}, nil); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if peak != 1 {
+		t.Errorf("peak concurrent calls = %d, want 1 for a batch with a follow-up tool", peak)
+	}
+}
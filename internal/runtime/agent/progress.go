@@ -43,6 +43,11 @@ func BuildProgressStream(progressFn func(string, map[string]any)) StreamCallback
 				}
 				progressFn(events.KindLoopToolStart, data)
 			}
+		case KindToolCallProgress:
+			progressFn(events.KindLoopToolProgress, map[string]any{
+				"tool":    e.ToolName,
+				"message": e.Progress,
+			})
 		case KindToolCallDone:
 			data := map[string]any{"tool": e.ToolName}
 			if e.ToolError != "" {
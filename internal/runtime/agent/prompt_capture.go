@@ -0,0 +1,118 @@
+package agent
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+)
+
+// maxCapturedPrompts bounds how many conversations keep their last
+// assembled system prompt. The least recently assembled is evicted.
+const maxCapturedPrompts = 64
+
+// PromptSnapshot is the system prompt most recently assembled for a
+// conversation, with the size of each section so context bloat can be
+// traced to the section causing it.
+type PromptSnapshot struct {
+	ConversationID string    `json:"conversation_id"`
+	RequestID      string    `json:"request_id"`
+	Model          string    `json:"model,omitempty"`
+	AssembledAt    time.Time `json:"assembled_at"`
+	Bytes          int       `json:"bytes"`
+
+	// Sections lists the tracked sections in prompt order. Separators
+	// between sections are not counted, so the section sizes sum to
+	// slightly less than Bytes. Empty when the request supplied its
+	// own system prompt.
+	Sections []PromptSectionSize `json:"sections"`
+
+	Prompt string `json:"prompt"`
+}
+
+// PromptSectionSize is the size of one section of an assembled system
+// prompt.
+type PromptSectionSize struct {
+	Name     string `json:"name"`
+	Bytes    int    `json:"bytes"`
+	CacheTTL string `json:"cache_ttl,omitempty"`
+}
+
+// promptCapture retains the last assembled system prompt per
+// conversation. A nil *promptCapture retains nothing.
+type promptCapture struct {
+	mu    sync.Mutex
+	byID  map[string]*PromptSnapshot
+	limit int
+}
+
+func newPromptCapture(limit int) *promptCapture {
+	return &promptCapture{byID: make(map[string]*PromptSnapshot), limit: limit}
+}
+
+// capture records prompt as the latest for convID, replacing any
+// earlier one. model is empty before the run has selected a model.
+func (c *promptCapture) capture(convID, requestID, model, prompt string, sections []llm.PromptSection) {
+	if c == nil {
+		return
+	}
+	snap := &PromptSnapshot{
+		ConversationID: convID,
+		RequestID:      requestID,
+		Model:          model,
+		AssembledAt:    time.Now(),
+		Bytes:          len(prompt),
+		Sections:       make([]PromptSectionSize, 0, len(sections)),
+		Prompt:         prompt,
+	}
+	for _, s := range sections {
+		snap.Sections = append(snap.Sections, PromptSectionSize{Name: s.Name, Bytes: len(s.Content), CacheTTL: s.CacheTTL})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byID[convID] = snap
+	if len(c.byID) <= c.limit {
+		return
+	}
+	var oldest *PromptSnapshot
+	for _, s := range c.byID {
+		if oldest == nil || s.AssembledAt.Before(oldest.AssembledAt) {
+			oldest = s
+		}
+	}
+	delete(c.byID, oldest.ConversationID)
+}
+
+func (c *promptCapture) get(convID string) (PromptSnapshot, bool) {
+	if c == nil {
+		return PromptSnapshot{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	snap, ok := c.byID[convID]
+	if !ok {
+		return PromptSnapshot{}, false
+	}
+	return *snap, true
+}
+
+// EnablePromptCapture makes the loop retain the last system prompt it
+// assembled for each of the most recent conversations, for
+// [Loop.LastPrompt]. Call during setup, before the loop serves runs.
+func (l *Loop) EnablePromptCapture() {
+	l.capturedPrompts = newPromptCapture(maxCapturedPrompts)
+}
+
+// PromptCaptureEnabled reports whether [Loop.EnablePromptCapture] was
+// called.
+func (l *Loop) PromptCaptureEnabled() bool {
+	return l.capturedPrompts != nil
+}
+
+// LastPrompt returns the system prompt most recently assembled for
+// convID. It reports false when capture is disabled or the
+// conversation has not run since startup (or was evicted).
+func (l *Loop) LastPrompt(convID string) (PromptSnapshot, bool) {
+	return l.capturedPrompts.get(convID)
+}
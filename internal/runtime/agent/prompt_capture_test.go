@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+)
+
+func TestLastPrompt(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{{
+		Model:   "test-model",
+		Message: llm.Message{Role: "assistant", Content: "Done."},
+	}}}
+	loop := buildTestLoop(mock, nil)
+
+	run := func() {
+		t.Helper()
+		if _, err := loop.Run(context.Background(), &Request{
+			ConversationID: "conv-1",
+			Messages:       []Message{{Role: "user", Content: "turn on the lights"}},
+		}, nil); err != nil {
+			t.Fatalf("Run() error: %v", err)
+		}
+	}
+
+	run()
+	if _, ok := loop.LastPrompt("conv-1"); ok {
+		t.Fatal("prompt retained without capture enabled")
+	}
+
+	loop.EnablePromptCapture()
+	mock.responses = append(mock.responses, mock.responses[0])
+	run()
+
+	snap, ok := loop.LastPrompt("conv-1")
+	if !ok {
+		t.Fatal("no prompt captured for conv-1")
+	}
+	sent := mock.calls[len(mock.calls)-1].Messages[0]
+	if sent.Role != "system" || snap.Prompt != sent.Content {
+		t.Errorf("captured prompt differs from the system message sent to the model")
+	}
+	if snap.Bytes != len(snap.Prompt) || snap.RequestID == "" {
+		t.Errorf("snapshot = %d bytes, request %q; want the prompt length and a request ID", snap.Bytes, snap.RequestID)
+	}
+	if len(snap.Sections) == 0 {
+		t.Fatal("no sections captured")
+	}
+	total := 0
+	for _, s := range snap.Sections {
+		if s.Name == "" || s.Bytes <= 0 {
+			t.Errorf("section = %+v, want a name and a size", s)
+		}
+		total += s.Bytes
+	}
+	if total > snap.Bytes {
+		t.Errorf("section sizes sum to %d, more than the %d-byte prompt", total, snap.Bytes)
+	}
+	if _, ok := loop.LastPrompt("conv-2"); ok {
+		t.Error("prompt reported for a conversation that never ran")
+	}
+}
+
+func TestPromptCaptureEvictsOldest(t *testing.T) {
+	c := newPromptCapture(2)
+	for i := range 3 {
+		c.capture(fmt.Sprintf("conv-%d", i), "r", "", "prompt", nil)
+	}
+	if _, ok := c.get("conv-0"); ok {
+		t.Error("oldest conversation not evicted")
+	}
+	for _, id := range []string{"conv-1", "conv-2"} {
+		if _, ok := c.get(id); !ok {
+			t.Errorf("%s evicted, want retained", id)
+		}
+	}
+}
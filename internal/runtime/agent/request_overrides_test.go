@@ -345,3 +345,67 @@ func TestToolTimeout_CancelsToolExecution(t *testing.T) {
 		t.Fatalf("tool error content = %q, want deadline exceeded", last.Content)
 	}
 }
+
+// endlessToolRounds returns n tool-call responses followed by the text
+// the force-text recovery call produces.
+func endlessToolRounds(n int) []*llm.ChatResponse {
+	var responses []*llm.ChatResponse
+	for i := range n {
+		resp := gatedToolCallResponse("probe")
+		resp.Message.ToolCalls[0].ID = "call-" + string(rune('a'+i))
+		resp.Message.ToolCalls[0].Function.Arguments = map[string]any{"round": i}
+		responses = append(responses, resp)
+	}
+	return append(responses, textResponse("Here is what I found so far."))
+}
+
+func TestMaxIterations_RequestLimitForcesRecovery(t *testing.T) {
+	mock := &mockLLM{responses: endlessToolRounds(2)}
+	loop := buildTestLoop(mock, []string{"probe"})
+
+	resp, err := loop.Run(context.Background(), &Request{
+		Messages:      []Message{{Role: "user", Content: "keep probing"}},
+		MaxIterations: 2,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !resp.Exhausted || resp.FinishReason != "max_iterations" {
+		t.Errorf("Exhausted = %v, FinishReason = %q; want exhaustion at max_iterations", resp.Exhausted, resp.FinishReason)
+	}
+	if resp.Content != "Here is what I found so far." {
+		t.Errorf("Content = %q, want the force-text recovery response", resp.Content)
+	}
+	if len(mock.calls) != 3 {
+		t.Fatalf("LLM calls = %d, want 2 tool rounds plus the recovery call", len(mock.calls))
+	}
+	if mock.calls[2].Tools != nil {
+		t.Errorf("recovery call offered %d tools, want none", len(mock.calls[2].Tools))
+	}
+}
+
+func TestMaxIterations_ChannelOverrideAndPrecedence(t *testing.T) {
+	loop := buildTestLoop(&mockLLM{}, nil)
+	loop.SetMaxIterations(20, map[string]int{"signal": 4})
+
+	tests := []struct {
+		name string
+		req  *Request
+		want int
+	}{
+		{"loop default", &Request{}, 20},
+		{"channel override", &Request{RoutingFactors: map[string]string{"source": "signal"}}, 4},
+		{"request wins", &Request{MaxIterations: 8, RoutingFactors: map[string]string{"source": "signal"}}, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := loop.iterationLimit(tt.req); got != tt.want {
+				t.Errorf("iterationLimit = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if got := buildTestLoop(&mockLLM{}, nil).iterationLimit(&Request{}); got != iterate.DefaultMaxIterations {
+		t.Errorf("unconfigured iterationLimit = %d, want %d", got, iterate.DefaultMaxIterations)
+	}
+}
@@ -0,0 +1,117 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+)
+
+// slowLLM delays every response to simulate a slow model's time to
+// first token.
+type slowLLM struct {
+	*mockLLM
+	delay time.Duration
+}
+
+func (s *slowLLM) Chat(ctx context.Context, model string, msgs []llm.Message, td []map[string]any) (*llm.ChatResponse, error) {
+	return s.ChatStream(ctx, model, msgs, td, nil)
+}
+
+func (s *slowLLM) ChatStream(ctx context.Context, model string, msgs []llm.Message, td []map[string]any, cb llm.StreamCallback) (*llm.ChatResponse, error) {
+	time.Sleep(s.delay)
+	return s.mockLLM.ChatStream(ctx, model, msgs, td, cb)
+}
+
+func TestRun_StreamHeartbeatDuringSlowGeneration(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{textResponse("Finally.")}}
+	loop := buildTestLoop(mock, nil)
+	loop.llm = &slowLLM{mockLLM: mock, delay: 80 * time.Millisecond}
+	loop.SetStreamHeartbeat(10 * time.Millisecond)
+
+	var (
+		mu         sync.Mutex
+		heartbeats int
+	)
+	stream := func(e StreamEvent) {
+		if e.Kind == KindHeartbeat {
+			mu.Lock()
+			heartbeats++
+			mu.Unlock()
+		}
+	}
+
+	if _, err := loop.Run(context.Background(), &Request{
+		Messages:     []Message{{Role: "user", Content: "take your time"}},
+		ClientStream: true,
+	}, stream); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	mu.Lock()
+	during := heartbeats
+	mu.Unlock()
+	if during < 2 {
+		t.Fatalf("heartbeats during slow generation = %d, want >= 2", during)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	after := heartbeats
+	mu.Unlock()
+	if after != during {
+		t.Errorf("heartbeats continued after the response completed: %d -> %d", during, after)
+	}
+}
+
+func TestRun_StreamHeartbeatDisabledByDefault(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{textResponse("ok")}}
+	loop := buildTestLoop(mock, nil)
+	loop.llm = &slowLLM{mockLLM: mock, delay: 30 * time.Millisecond}
+
+	var heartbeats int
+	_, err := loop.Run(context.Background(), &Request{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	}, func(e StreamEvent) {
+		if e.Kind == KindHeartbeat {
+			heartbeats++
+		}
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if heartbeats != 0 {
+		t.Errorf("heartbeats = %d, want 0 with no interval configured", heartbeats)
+	}
+}
+
+func TestRun_StreamHeartbeatOnlyForClientStreams(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{textResponse("ok")}}
+	loop := buildTestLoop(mock, nil)
+	loop.llm = &slowLLM{mockLLM: mock, delay: 50 * time.Millisecond}
+	loop.SetStreamHeartbeat(5 * time.Millisecond)
+
+	var (
+		mu         sync.Mutex
+		heartbeats int
+	)
+	_, err := loop.Run(context.Background(), &Request{
+		Messages: []Message{{Role: "user", Content: "background wake"}},
+	}, func(e StreamEvent) {
+		if e.Kind == KindHeartbeat {
+			mu.Lock()
+			heartbeats++
+			mu.Unlock()
+		}
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if heartbeats != 0 {
+		t.Errorf("heartbeats = %d, want 0 for a stream not serving a client", heartbeats)
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/model/router"
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/platform/usage"
 )
 
 // --- isTimeout tests ---
@@ -54,7 +58,7 @@ func TestBuildRecoveryPrompt(t *testing.T) {
 		{Role: "user", Content: "Move these files"},
 		{Role: "assistant", Content: "I'll move them now."},
 		{Role: "tool", Content: "wrote config.yaml successfully", ToolCallID: "call-1"},
-		{Role: "tool", Content: "Error: file not found", ToolCallID: "call-2"},
+		{Role: "tool", Content: "[error:not_found] file not found", ToolCallID: "call-2"},
 		{Role: "tool", Content: strings.Repeat("x", 300), ToolCallID: "call-3"},
 	}
 	toolsUsed := map[string]int{
@@ -366,6 +370,94 @@ func TestTimeoutRecovery_StaticFallbackWhenNoRecoveryModel(t *testing.T) {
 	}
 }
 
+func TestTimeoutRecovery_RoutesRecoveryAndRecordsUsageSeparately(t *testing.T) {
+	t.Parallel()
+
+	toolCall := llm.ToolCall{ID: "call-1"}
+	toolCall.Function.Name = "recall_fact"
+	mock := &mockTimeoutLLM{
+		responses: []*llm.ChatResponse{
+			{
+				Model:        "test-model",
+				Message:      llm.Message{Role: "assistant", ToolCalls: []llm.ToolCall{toolCall}},
+				InputTokens:  100,
+				OutputTokens: 50,
+			},
+			{
+				Model:        "fast-model",
+				Message:      llm.Message{Role: "assistant", Content: "I looked up one fact before the model stalled."},
+				InputTokens:  40,
+				OutputTokens: 12,
+			},
+		},
+		errors: []error{
+			nil,                      // call 0: tool call succeeds
+			context.DeadlineExceeded, // call 1: timeout
+			context.DeadlineExceeded, // call 2: retry 1 timeout
+			context.DeadlineExceeded, // call 3: retry 2 timeout
+			nil,                      // call 4: routed recovery model succeeds
+		},
+	}
+
+	db, err := database.OpenMemory()
+	if err != nil {
+		t.Fatalf("database.OpenMemory: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := usage.NewStore(db, nil)
+	if err != nil {
+		t.Fatalf("usage.NewStore: %v", err)
+	}
+
+	loop := buildTestLoopWithLLM(mock, []string{"recall_fact"})
+	loop.usageStore = store
+	loop.router = router.NewRouter(slog.Default(), router.Config{
+		DefaultModel: "test-model",
+		Models: []router.Model{
+			{Name: "test-model", SupportsTools: true, Speed: 3, Quality: 9, CostTier: 2},
+			{Name: "fast-model", SupportsTools: true, Speed: 9, Quality: 5, CostTier: 2},
+		},
+	})
+
+	// An explicit model skips routing for the main run, so the only
+	// routed call is the recovery.
+	resp, err := loop.Run(context.Background(), &Request{
+		Model:    "test-model",
+		Messages: []Message{{Role: "user", Content: "recall something"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if resp.FinishReason != "timeout_recovery" {
+		t.Errorf("FinishReason = %q, want timeout_recovery", resp.FinishReason)
+	}
+
+	mock.mu.Lock()
+	last := mock.calls[len(mock.calls)-1]
+	mock.mu.Unlock()
+	if last.Model != "fast-model" {
+		t.Errorf("recovery call model = %q, want the router's fast pick", last.Model)
+	}
+	if last.Tools != nil {
+		t.Errorf("recovery call offered %d tools, want none", len(last.Tools))
+	}
+
+	byRole, err := store.SummaryByRole(time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SummaryByRole: %v", err)
+	}
+	got := map[string]usage.Summary{}
+	for _, g := range byRole {
+		got[g.Key] = g.Summary
+	}
+	if s := got["interactive"]; s.TotalInputTokens != 100 || s.TotalOutputTokens != 50 {
+		t.Errorf("interactive usage = %+v, want the primary model's 100/50 tokens", s)
+	}
+	if s := got["recovery"]; s.TotalInputTokens != 40 || s.TotalOutputTokens != 12 {
+		t.Errorf("recovery usage = %+v, want the recovery call's 40/12 tokens", s)
+	}
+}
+
 func TestAmbiguousModelError_DoesNotFailOver(t *testing.T) {
 	t.Parallel()
 
@@ -443,8 +535,8 @@ func TestCanceledContext_DoesNotFailOver(t *testing.T) {
 	iterCtx, cancelIter := context.WithCancel(context.Background())
 	cancelIter()
 
-	timeoutRecovered := false
-	handler := loop.buildLLMErrorHandler(reqCtx, nil, loop.model, &Request{}, &timeoutRecovered)
+	var recovery timeoutRecovery
+	handler := loop.buildLLMErrorHandler(reqCtx, nil, loop.model, &Request{}, &recovery, nil)
 
 	_, _, err := handler(
 		iterCtx,
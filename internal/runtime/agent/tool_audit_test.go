@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/platform/audit"
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
+)
+
+func TestToolAudit_RecordsEveryCall(t *testing.T) {
+	db, err := database.OpenMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := audit.NewStore(db, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call := func(id, name string, args map[string]any) llm.ToolCall {
+		tc := llm.ToolCall{ID: id}
+		tc.Function.Name = name
+		tc.Function.Arguments = args
+		return tc
+	}
+	mock := &mockLLM{
+		responses: []*llm.ChatResponse{
+			{
+				Model: "test-model",
+				Message: llm.Message{
+					Role: "assistant",
+					ToolCalls: []llm.ToolCall{
+						call("call-1", "web_search", map[string]any{"query": "weather"}),
+						// Not registered: blocked, but still audited.
+						call("call-2", "shell_exec", map[string]any{"command": "rm -rf /"}),
+					},
+				},
+				InputTokens:  100,
+				OutputTokens: 10,
+			},
+			{
+				Model:        "test-model",
+				Message:      llm.Message{Role: "assistant", Content: "Done."},
+				InputTokens:  200,
+				OutputTokens: 5,
+			},
+		},
+	}
+	loop := buildTestLoop(mock, []string{"web_search"})
+	loop.ConfigureSessionStores(SessionStoreWiring{ToolAudit: store})
+
+	_, err = loop.Run(context.Background(), &Request{
+		ConversationID: "signal-42",
+		Messages:       []Message{{Role: "user", Content: "check the weather"}},
+		RoutingFactors: map[string]string{"source": "signal"},
+		ChannelBinding: &memory.ChannelBinding{Channel: "signal", ContactName: "Alice"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	entries, err := store.Query(context.Background(), audit.Filter{ConversationID: "signal-42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byTool := map[string]audit.Entry{}
+	for _, e := range entries {
+		byTool[e.Tool] = e
+	}
+	if len(byTool) != 2 {
+		t.Fatalf("audited tools = %v, want web_search and shell_exec", entries)
+	}
+
+	search := byTool["web_search"]
+	if search.Arguments != `{"query":"weather"}` || search.CompletedAt == nil || search.Error != "" {
+		t.Errorf("web_search entry = %+v", search)
+	}
+	if search.Source != "signal" || search.Channel != "signal" || search.Sender != "Alice" || search.RequestID == "" {
+		t.Errorf("web_search attribution = %+v", search)
+	}
+
+	blocked := byTool["shell_exec"]
+	if blocked.CompletedAt == nil || blocked.Error == "" || blocked.ErrorKind != "not_found" {
+		t.Errorf("blocked shell_exec entry = %+v, want a completed not_found error", blocked)
+	}
+}
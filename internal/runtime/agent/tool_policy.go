@@ -0,0 +1,194 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/opstate"
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+const toolPolicyNamespace = "conversation_tool_policy"
+
+// ToolPolicyChannelPrefix marks a tool policy selector that matches a
+// conversation's channel ("channel:signal") rather than a prefix of
+// its conversation ID.
+const ToolPolicyChannelPrefix = "channel:"
+
+// ToolPolicy restricts the conversations its selector matches to a
+// fixed set of tools, whatever capability tags are active. It narrows
+// the tool surface and never widens it.
+type ToolPolicy struct {
+	Allow     []string  `json:"allow"`
+	Reason    string    `json:"reason,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// ToolPolicyStore resolves the tool policy for a conversation. When
+// more than one policy matches, the conversation gets only the tools
+// every one of them allows. matched lists the selectors that applied;
+// it is empty when no policy restricts the conversation.
+type ToolPolicyStore interface {
+	ToolPolicyFor(conversationID, channel string) (allow []string, matched []string, err error)
+}
+
+// OpstateToolPolicyStore implements ToolPolicyStore using opstate.
+// Each policy is stored as JSON under its selector: a conversation ID
+// prefix, or [ToolPolicyChannelPrefix] and a channel name. Policies are
+// read on every run, so changes apply to the next turn without a
+// restart.
+type OpstateToolPolicyStore struct {
+	state *opstate.Store
+}
+
+// NewOpstateToolPolicyStore creates a tool policy store backed by opstate.
+func NewOpstateToolPolicyStore(state *opstate.Store) *OpstateToolPolicyStore {
+	return &OpstateToolPolicyStore{state: state}
+}
+
+// Set stores the policy for selector, replacing any existing one.
+func (s *OpstateToolPolicyStore) Set(selector string, policy ToolPolicy) error {
+	selector, err := normalizeToolPolicySelector(selector)
+	if err != nil {
+		return err
+	}
+	policy.Allow = normalizeToolNames(policy.Allow)
+	policy.Reason = strings.TrimSpace(policy.Reason)
+	if policy.UpdatedAt.IsZero() {
+		policy.UpdatedAt = time.Now()
+	}
+	policy.UpdatedAt = policy.UpdatedAt.UTC()
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	return s.state.Set(toolPolicyNamespace, selector, string(data))
+}
+
+// Delete removes the policy for selector. No error is returned if
+// there is none.
+func (s *OpstateToolPolicyStore) Delete(selector string) error {
+	selector, err := normalizeToolPolicySelector(selector)
+	if err != nil {
+		return err
+	}
+	return s.state.Delete(toolPolicyNamespace, selector)
+}
+
+// List returns every stored policy keyed by selector.
+func (s *OpstateToolPolicyStore) List() (map[string]ToolPolicy, error) {
+	entries, err := s.state.List(toolPolicyNamespace)
+	if err != nil {
+		return nil, err
+	}
+	policies := make(map[string]ToolPolicy, len(entries))
+	for selector, raw := range entries {
+		var p ToolPolicy
+		if err := json.Unmarshal([]byte(raw), &p); err != nil {
+			return nil, fmt.Errorf("decode tool policy %q: %w", selector, err)
+		}
+		policies[selector] = p
+	}
+	return policies, nil
+}
+
+// ToolPolicyFor returns the intersection of the allowlists of every
+// policy matching the conversation.
+func (s *OpstateToolPolicyStore) ToolPolicyFor(conversationID, channel string) ([]string, []string, error) {
+	policies, err := s.List()
+	if err != nil {
+		return nil, nil, err
+	}
+	return resolveToolPolicies(policies, conversationID, channel)
+}
+
+// resolveToolPolicies intersects the allowlists of the policies that
+// match conversationID or channel.
+func resolveToolPolicies(policies map[string]ToolPolicy, conversationID, channel string) ([]string, []string, error) {
+	channel = strings.ToLower(strings.TrimSpace(channel))
+	var matched []string
+	for selector := range policies {
+		if name, ok := strings.CutPrefix(selector, ToolPolicyChannelPrefix); ok {
+			if channel != "" && strings.EqualFold(name, channel) {
+				matched = append(matched, selector)
+			}
+			continue
+		}
+		if strings.HasPrefix(conversationID, selector) {
+			matched = append(matched, selector)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, nil, nil
+	}
+	sort.Strings(matched)
+
+	allowed := make(map[string]bool)
+	for _, name := range policies[matched[0]].Allow {
+		allowed[name] = true
+	}
+	for _, selector := range matched[1:] {
+		next := make(map[string]bool)
+		for _, name := range policies[selector].Allow {
+			if allowed[name] {
+				next[name] = true
+			}
+		}
+		allowed = next
+	}
+	allow := make([]string, 0, len(allowed))
+	for name := range allowed {
+		allow = append(allow, name)
+	}
+	sort.Strings(allow)
+	return allow, matched, nil
+}
+
+// applyToolPolicy narrows registry to allow, returning the narrowed
+// registry and the sorted names of the tools it withheld.
+func applyToolPolicy(registry *tools.Registry, allow []string) (*tools.Registry, []string) {
+	narrowed := registry.FilteredCopy(allow)
+	var withheld []string
+	for _, name := range registry.AllToolNames() {
+		if narrowed.Get(name) == nil {
+			withheld = append(withheld, name)
+		}
+	}
+	return narrowed, withheld
+}
+
+// normalizeToolPolicySelector trims selector and lowercases a channel
+// selector's channel name.
+func normalizeToolPolicySelector(selector string) (string, error) {
+	selector = strings.TrimSpace(selector)
+	if name, ok := strings.CutPrefix(selector, ToolPolicyChannelPrefix); ok {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			return "", fmt.Errorf("tool policy selector %q names no channel", selector)
+		}
+		return ToolPolicyChannelPrefix + name, nil
+	}
+	if selector == "" {
+		return "", fmt.Errorf("tool policy selector is empty")
+	}
+	return selector, nil
+}
+
+// normalizeToolNames trims, drops empties, and dedupes names.
+func normalizeToolNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,270 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/platform/opstate"
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+func newTestToolPolicyStore(t *testing.T) *OpstateToolPolicyStore {
+	t.Helper()
+	db, err := database.OpenMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	state, err := opstate.NewStore(db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewOpstateToolPolicyStore(state)
+}
+
+func TestToolPolicyStore_Matching(t *testing.T) {
+	store := newTestToolPolicyStore(t)
+	if err := store.Set("signal-", ToolPolicy{Allow: []string{"web_search", "recall_fact", "file_read"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("channel:Signal", ToolPolicy{Allow: []string{"web_search", "recall_fact"}, Reason: "untrusted senders"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		convID      string
+		channel     string
+		wantAllow   []string
+		wantMatched []string
+	}{
+		{"no match", "owner-1", "ha", nil, nil},
+		{"prefix only", "signal-123", "", []string{"file_read", "recall_fact", "web_search"}, []string{"signal-"}},
+		{"channel only", "c-9", "SIGNAL", []string{"recall_fact", "web_search"}, []string{"channel:signal"}},
+		{"both intersect", "signal-123", "signal", []string{"recall_fact", "web_search"}, []string{"channel:signal", "signal-"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allow, matched, err := store.ToolPolicyFor(tt.convID, tt.channel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tt.wantMatched) == 0 {
+				if len(matched) != 0 {
+					t.Errorf("matched = %v, want none", matched)
+				}
+				return
+			}
+			if !reflect.DeepEqual(allow, tt.wantAllow) {
+				t.Errorf("allow = %v, want %v", allow, tt.wantAllow)
+			}
+			if !reflect.DeepEqual(matched, tt.wantMatched) {
+				t.Errorf("matched = %v, want %v", matched, tt.wantMatched)
+			}
+		})
+	}
+}
+
+func TestToolPolicyStore_SetListDelete(t *testing.T) {
+	store := newTestToolPolicyStore(t)
+	if err := store.Set("  ", ToolPolicy{}); err == nil {
+		t.Error("Set with empty selector should fail")
+	}
+	if err := store.Set("channel:", ToolPolicy{}); err == nil {
+		t.Error("Set with empty channel should fail")
+	}
+	if err := store.Set("kiosk", ToolPolicy{Allow: []string{" web_search ", "web_search", ""}}); err != nil {
+		t.Fatal(err)
+	}
+
+	policies, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, ok := policies["kiosk"]
+	if !ok {
+		t.Fatalf("List() = %v, want kiosk policy", policies)
+	}
+	if !reflect.DeepEqual(p.Allow, []string{"web_search"}) {
+		t.Errorf("Allow = %v, want [web_search]", p.Allow)
+	}
+	if p.UpdatedAt.IsZero() {
+		t.Error("UpdatedAt not set")
+	}
+
+	if err := store.Delete("kiosk"); err != nil {
+		t.Fatal(err)
+	}
+	if _, matched, _ := store.ToolPolicyFor("kiosk-1", ""); len(matched) != 0 {
+		t.Errorf("deleted policy still matches: %v", matched)
+	}
+}
+
+type failingToolPolicyStore struct{}
+
+func (failingToolPolicyStore) ToolPolicyFor(string, string) ([]string, []string, error) {
+	return nil, nil, errors.New("database is locked")
+}
+
+func TestToolPolicy_WithholdsToolsFromRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ToolPolicyStore
+	}{
+		{"narrowed", nil},
+		{"unreadable policy fails closed", failingToolPolicyStore{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toolExecuted := false
+			mock := &mockLLM{
+				responses: []*llm.ChatResponse{
+					{
+						Model: "test-model",
+						Message: llm.Message{
+							Role: "assistant",
+							ToolCalls: []llm.ToolCall{{
+								ID: "call-1",
+								Function: struct {
+									Name      string         `json:"name"`
+									Arguments map[string]any `json:"arguments"`
+								}{
+									Name:      "file_read",
+									Arguments: map[string]any{},
+								},
+							}},
+						},
+						InputTokens:  100,
+						OutputTokens: 10,
+					},
+					{
+						Model:        "test-model",
+						Message:      llm.Message{Role: "assistant", Content: "OK."},
+						InputTokens:  200,
+						OutputTokens: 5,
+					},
+				},
+			}
+
+			loop := buildTestLoop(mock, []string{"web_search"})
+			loop.tools.Register(&tools.Tool{
+				Name:        "file_read",
+				Description: "Read a file",
+				Parameters:  map[string]any{"type": "object", "properties": map[string]any{}},
+				Handler: func(_ context.Context, _ map[string]any) (string, error) {
+					toolExecuted = true
+					return "file contents", nil
+				},
+			})
+
+			policy := tt.policy
+			if policy == nil {
+				store := newTestToolPolicyStore(t)
+				if err := store.Set("channel:signal", ToolPolicy{Allow: []string{"web_search"}}); err != nil {
+					t.Fatal(err)
+				}
+				policy = store
+			}
+			loop.SetToolPolicyStore(policy)
+
+			_, err := loop.Run(context.Background(), &Request{
+				ConversationID: "signal-15125551234",
+				Messages:       []Message{{Role: "user", Content: "read the file"}},
+				RoutingFactors: map[string]string{"source": "signal"},
+			}, nil)
+			if err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+			if toolExecuted {
+				t.Error("file_read executed despite the conversation tool policy")
+			}
+			if len(mock.calls) < 2 {
+				t.Fatalf("LLM calls = %d, want 2", len(mock.calls))
+			}
+			if names := toolNames(mock.calls[0].Tools); hasName(names, "file_read") {
+				t.Errorf("file_read advertised despite policy: %v", names)
+			}
+			found := false
+			for _, m := range mock.calls[1].Messages {
+				if m.Role == "tool" && strings.Contains(m.Content, "not available") {
+					found = true
+				}
+			}
+			if !found {
+				t.Error("expected a 'not available' tool result for file_read")
+			}
+		})
+	}
+}
+
+func TestToolPolicy_CarriedIntoToolContext(t *testing.T) {
+	call := llm.ToolCall{ID: "call-1"}
+	call.Function.Name = "thane_now"
+	call.Function.Arguments = map[string]any{}
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		{Model: "test-model", Message: llm.Message{Role: "assistant", ToolCalls: []llm.ToolCall{call}}},
+		{Model: "test-model", Message: llm.Message{Role: "assistant", Content: "Done."}},
+	}}
+	loop := buildTestLoop(mock, []string{"web_search"})
+	var inherited []string
+	var ok bool
+	loop.tools.Register(&tools.Tool{
+		Name:        "thane_now",
+		Description: "Delegate now",
+		Parameters:  map[string]any{"type": "object", "properties": map[string]any{}},
+		Handler: func(ctx context.Context, _ map[string]any) (string, error) {
+			inherited, ok = tools.ToolPolicyFromContext(ctx)
+			return "delegated", nil
+		},
+	})
+	store := newTestToolPolicyStore(t)
+	if err := store.Set("signal-", ToolPolicy{Allow: []string{"thane_now", "web_search"}}); err != nil {
+		t.Fatal(err)
+	}
+	loop.SetToolPolicyStore(store)
+
+	if _, err := loop.Run(context.Background(), &Request{
+		ConversationID:   "signal-15125551234",
+		Messages:         []Message{{Role: "user", Content: "look into it"}},
+		DelegationGating: "disabled",
+	}, nil); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if !ok || !reflect.DeepEqual(inherited, []string{"thane_now", "web_search"}) {
+		t.Errorf("tool context policy = %v (present %v), want the conversation's allowlist", inherited, ok)
+	}
+}
+
+func TestToolPolicy_OtherConversationsUnaffected(t *testing.T) {
+	mock := &mockLLM{
+		responses: []*llm.ChatResponse{{
+			Model:        "test-model",
+			Message:      llm.Message{Role: "assistant", Content: "Hi."},
+			InputTokens:  10,
+			OutputTokens: 2,
+		}},
+	}
+	loop := buildTestLoop(mock, []string{"file_read", "web_search"})
+	store := newTestToolPolicyStore(t)
+	if err := store.Set("signal-", ToolPolicy{Allow: []string{"web_search"}}); err != nil {
+		t.Fatal(err)
+	}
+	loop.SetToolPolicyStore(store)
+
+	if _, err := loop.Run(context.Background(), &Request{
+		ConversationID: "owner-1",
+		Messages:       []Message{{Role: "user", Content: "what is on the calendar"}},
+	}, nil); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	names := toolNames(mock.calls[0].Tools)
+	if !hasName(names, "file_read") || !hasName(names, "web_search") {
+		t.Errorf("tools = %v, want file_read and web_search", names)
+	}
+}
@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"sync"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+// toolProgressRelay forwards tool progress reports to a run's stream as
+// [KindToolCallProgress] events. Reports arrive from tool handler
+// goroutines — several at once when a read-only batch runs in parallel
+// — so emission is serialized, and a call's reporter goes quiet once
+// the call finishes so no progress event trails its KindToolCallDone.
+type toolProgressRelay struct {
+	stream StreamCallback
+
+	mu     sync.Mutex
+	active map[string]bool // tool call ID → still running
+}
+
+func newToolProgressRelay(stream StreamCallback) *toolProgressRelay {
+	if stream == nil {
+		return nil
+	}
+	return &toolProgressRelay{stream: stream, active: make(map[string]bool)}
+}
+
+// reporter returns the progress sink for one tool call. Nil-safe: a nil
+// relay (non-streaming run) returns nil, which leaves the tool context
+// without a reporter.
+func (r *toolProgressRelay) reporter(toolName, toolCallID string) tools.ProgressFunc {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	r.active[toolCallID] = true
+	r.mu.Unlock()
+
+	return func(msg string) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if !r.active[toolCallID] {
+			return
+		}
+		r.stream(llm.StreamEvent{
+			Kind:     llm.KindToolCallProgress,
+			ToolName: toolName,
+			Progress: msg,
+			Data:     map[string]any{"tool_call_id": toolCallID},
+		})
+	}
+}
+
+// finish stops forwarding reports for the tool call. It waits for any
+// report being emitted, so events after finish returns are ordered
+// after the call's progress.
+func (r *toolProgressRelay) finish(toolCallID string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	delete(r.active, toolCallID)
+	r.mu.Unlock()
+}
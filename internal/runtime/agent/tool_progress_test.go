@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+func registerProgressTool(loop *Loop, name string, reports ...string) {
+	loop.tools.Register(&tools.Tool{
+		Name:        name,
+		Description: "test tool " + name,
+		Parameters:  map[string]any{"type": "object", "properties": map[string]any{}},
+		Handler: func(ctx context.Context, _ map[string]any) (string, error) {
+			for _, msg := range reports {
+				tools.ReportProgress(ctx, msg)
+			}
+			return "fetched", nil
+		},
+	})
+}
+
+func TestRun_ToolProgressStreamsBetweenStartAndDone(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		gatedToolCallResponse("media_fetch"),
+		textResponse("Got it."),
+	}}
+	loop := buildTestLoop(mock, nil)
+	registerProgressTool(loop, "media_fetch", "downloading…", "transcribing chunk 1/2…")
+
+	var trace []string
+	stream := func(e StreamEvent) {
+		switch e.Kind {
+		case KindToolCallStart:
+			trace = append(trace, "start")
+		case KindToolCallProgress:
+			if e.ToolName != "media_fetch" {
+				t.Errorf("progress ToolName = %q, want media_fetch", e.ToolName)
+			}
+			if id, _ := e.Data["tool_call_id"].(string); id == "" {
+				t.Error("progress event missing tool_call_id")
+			}
+			trace = append(trace, e.Progress)
+		case KindToolCallDone:
+			trace = append(trace, "done")
+		}
+	}
+
+	if _, err := loop.Run(context.Background(), &Request{
+		Messages: []Message{{Role: "user", Content: "fetch the video"}},
+	}, stream); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []string{"start", "downloading…", "transcribing chunk 1/2…", "done"}
+	if !slices.Equal(trace, want) {
+		t.Errorf("stream trace = %q, want %q", trace, want)
+	}
+}
+
+func TestRun_ToolProgressWithoutStreamIsNoop(t *testing.T) {
+	mock := &mockLLM{responses: []*llm.ChatResponse{
+		gatedToolCallResponse("media_fetch"),
+		textResponse("Got it."),
+	}}
+	loop := buildTestLoop(mock, nil)
+	registerProgressTool(loop, "media_fetch", "downloading…")
+
+	resp, err := loop.Run(context.Background(), &Request{
+		Messages: []Message{{Role: "user", Content: "fetch the video"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if resp.Content != "Got it." {
+		t.Errorf("Content = %q, want Got it.", resp.Content)
+	}
+}
+
+func TestToolProgressRelay_DropsReportsAfterFinish(t *testing.T) {
+	var got []string
+	relay := newToolProgressRelay(func(e StreamEvent) { got = append(got, e.Progress) })
+
+	report := relay.reporter("media_fetch", "call-1")
+	report("downloading…")
+	relay.finish("call-1")
+	report("late report")
+
+	if !slices.Equal(got, []string{"downloading…"}) {
+		t.Errorf("relayed = %q, want only the report before finish", got)
+	}
+	if newToolProgressRelay(nil).reporter("media_fetch", "call-2") != nil {
+		t.Error("nil relay should return a nil reporter")
+	}
+}
@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+func TestToolRateLimit_BlocksOverLimitCalls(t *testing.T) {
+	fetchCall := func(id, url string) llm.ToolCall {
+		tc := llm.ToolCall{ID: id}
+		tc.Function.Name = "web_fetch"
+		tc.Function.Arguments = map[string]any{"url": url}
+		return tc
+	}
+	mock := &mockLLM{
+		responses: []*llm.ChatResponse{
+			{
+				Model: "test-model",
+				Message: llm.Message{
+					Role: "assistant",
+					// Varied arguments, so the identical-call loop
+					// detector doesn't catch it.
+					ToolCalls: []llm.ToolCall{
+						fetchCall("call-1", "https://example.com/1"),
+						fetchCall("call-2", "https://example.com/2"),
+						fetchCall("call-3", "https://example.com/3"),
+					},
+				},
+				InputTokens:  100,
+				OutputTokens: 10,
+			},
+			{
+				Model:        "test-model",
+				Message:      llm.Message{Role: "assistant", Content: "Fetched what I could."},
+				InputTokens:  200,
+				OutputTokens: 5,
+			},
+		},
+	}
+
+	loop := buildTestLoop(mock, nil)
+	fetched := 0
+	loop.tools.Register(&tools.Tool{
+		Name:        "web_fetch",
+		Description: "Fetch a URL",
+		Parameters:  map[string]any{"type": "object", "properties": map[string]any{}},
+		Handler: func(_ context.Context, _ map[string]any) (string, error) {
+			fetched++
+			return "page", nil
+		},
+	})
+	loop.tools.SetToolRateLimit("web_fetch", 2, time.Minute)
+
+	if _, err := loop.Run(context.Background(), &Request{
+		Messages: []Message{{Role: "user", Content: "read these three pages"}},
+	}, nil); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	if fetched != 2 {
+		t.Errorf("web_fetch executed %d times, want 2", fetched)
+	}
+	if len(mock.calls) < 2 {
+		t.Fatalf("LLM calls = %d, want 2", len(mock.calls))
+	}
+	limited := 0
+	for _, m := range mock.calls[1].Messages {
+		if m.Role == "tool" && strings.Contains(m.Content, "rate limited") && strings.Contains(m.Content, "try again later") {
+			limited++
+		}
+	}
+	if limited != 1 {
+		t.Errorf("rate-limited tool results = %d, want 1", limited)
+	}
+}
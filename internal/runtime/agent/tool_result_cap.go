@@ -0,0 +1,119 @@
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/logging"
+)
+
+// DefaultMaxToolResultBytes is the tool result cap used when
+// [Loop.SetToolResultLimits] is given zero.
+const DefaultMaxToolResultBytes = 64 * 1024
+
+// toolResultCap bounds the size of a single tool result before it is
+// appended to the conversation.
+type toolResultCap struct {
+	max     int            // default cap in bytes; negative = unlimited
+	perTool map[string]int // tool name → cap override; 0 = unlimited
+}
+
+// SetToolResultLimits caps how many bytes of one tool result the model
+// sees. A longer result is cut at the cap and ends with a marker
+// saying how much was dropped; when a temp file store is configured,
+// the full result is saved there first and the marker names the file
+// so the model can read the rest with file_read. perTool overrides the
+// cap by tool name, with 0 lifting it for that tool. A zero n uses
+// [DefaultMaxToolResultBytes]; a negative n disables the cap.
+func (l *Loop) SetToolResultLimits(n int, perTool map[string]int) {
+	if n == 0 {
+		n = DefaultMaxToolResultBytes
+	}
+	l.toolResultCap = toolResultCap{max: n, perTool: perTool}
+}
+
+// limit returns the cap for toolName, or 0 when its results are not
+// capped.
+func (c toolResultCap) limit(toolName string) int {
+	if n, ok := c.perTool[toolName]; ok {
+		return max(n, 0)
+	}
+	return max(c.max, 0)
+}
+
+// capToolResult returns result cut to the configured cap for toolName,
+// saving the full text as a temp file in convID when it is cut. Results
+// within the cap are returned unchanged.
+func (l *Loop) capToolResult(ctx context.Context, convID, toolName, result string) string {
+	limit := l.toolResultCap.limit(toolName)
+	if limit == 0 || len(result) <= limit {
+		return result
+	}
+
+	var savedPath string
+	if tfs := l.tools.TempFileStore(); tfs != nil {
+		path, err := tfs.CreateWith(ctx, convID, toolResultLabel(toolName), ".txt", func(w io.Writer) error {
+			_, err := io.WriteString(w, result)
+			return err
+		})
+		if err != nil {
+			logging.Logger(ctx).Warn("save full tool result failed",
+				"tool", toolName,
+				"error", err,
+			)
+		} else {
+			savedPath = path
+		}
+	}
+
+	logging.Logger(ctx).Info("tool result truncated",
+		"tool", toolName,
+		"bytes", len(result),
+		"limit", limit,
+		"saved_to", savedPath,
+	)
+	return truncateToolResult(result, limit, savedPath)
+}
+
+// truncateToolResult cuts result to at most limit bytes, backing off to
+// a UTF-8 boundary, and appends a marker telling the model the result
+// is incomplete and, when savedPath is set, where the full text is.
+func truncateToolResult(result string, limit int, savedPath string) string {
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(result[cut]) {
+		cut--
+	}
+
+	var b strings.Builder
+	b.WriteString(result[:cut])
+	fmt.Fprintf(&b, "\n[tool result truncated: showing %d of %d bytes.", cut, len(result))
+	if savedPath != "" {
+		fmt.Fprintf(&b, " The full result is saved at %s; read the rest with file_read using offset and limit (line numbers).", savedPath)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// toolResultLabel returns a fresh temp file label for a saved result of
+// toolName. Characters the temp file store does not accept in labels
+// are replaced, and the name is shortened to keep the label in bounds.
+func toolResultLabel(toolName string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, toolName)
+	if len(name) > 40 {
+		name = name[:40]
+	}
+	var suffix [4]byte
+	_, _ = rand.Read(suffix[:])
+	return "result-" + name + "-" + hex.EncodeToString(suffix[:])
+}
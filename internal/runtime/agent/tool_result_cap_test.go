@@ -0,0 +1,128 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/platform/opstate"
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+func TestTruncateToolResult(t *testing.T) {
+	t.Run("marker without saved file", func(t *testing.T) {
+		got := truncateToolResult(strings.Repeat("a", 100), 10, "")
+		if !strings.HasPrefix(got, strings.Repeat("a", 10)+"\n[tool result truncated: showing 10 of 100 bytes.") {
+			t.Errorf("truncateToolResult() = %q", got)
+		}
+		if strings.Contains(got, "file_read") {
+			t.Errorf("marker mentions file_read without a saved file: %q", got)
+		}
+	})
+
+	t.Run("marker names saved file", func(t *testing.T) {
+		got := truncateToolResult(strings.Repeat("a", 100), 10, "/ws/.tmp/result.txt")
+		if !strings.Contains(got, "/ws/.tmp/result.txt") || !strings.Contains(got, "file_read") {
+			t.Errorf("truncateToolResult() = %q, want saved path and file_read hint", got)
+		}
+	})
+
+	t.Run("cuts at rune boundary", func(t *testing.T) {
+		// "é" is two bytes; a cap of 4 lands mid-rune.
+		got := truncateToolResult("aéé", 4, "")
+		body, _, _ := strings.Cut(got, "\n[")
+		if body != "aé" || !utf8.ValidString(got) {
+			t.Errorf("body = %q, want %q", body, "aé")
+		}
+	})
+}
+
+func TestToolResultCapLimit(t *testing.T) {
+	c := toolResultCap{max: 100, perTool: map[string]int{"big": 0, "small": 10}}
+	for name, want := range map[string]int{"other": 100, "big": 0, "small": 10} {
+		if got := c.limit(name); got != want {
+			t.Errorf("limit(%q) = %d, want %d", name, got, want)
+		}
+	}
+	if got := (toolResultCap{max: -1}).limit("other"); got != 0 {
+		t.Errorf("disabled limit = %d, want 0", got)
+	}
+}
+
+func TestToolResultCap_SavesFullResult(t *testing.T) {
+	db, err := database.OpenMemory()
+	if err != nil {
+		t.Fatalf("database.OpenMemory: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	state, err := opstate.NewStore(db, nil)
+	if err != nil {
+		t.Fatalf("opstate.NewStore: %v", err)
+	}
+
+	dumpCall := llm.ToolCall{ID: "call-1"}
+	dumpCall.Function.Name = "dump"
+	dumpCall.Function.Arguments = map[string]any{}
+	mock := &mockLLM{
+		responses: []*llm.ChatResponse{
+			{
+				Model:        "test-model",
+				Message:      llm.Message{Role: "assistant", ToolCalls: []llm.ToolCall{dumpCall}},
+				InputTokens:  100,
+				OutputTokens: 10,
+			},
+			{
+				Model:        "test-model",
+				Message:      llm.Message{Role: "assistant", Content: "That was a lot."},
+				InputTokens:  200,
+				OutputTokens: 5,
+			},
+		},
+	}
+
+	full := strings.Repeat("line of output\n", 100)
+	loop := buildTestLoop(mock, nil)
+	loop.tools.SetTempFileStore(tools.NewTempFileStore(filepath.Join(t.TempDir(), ".tmp"), state, nil))
+	loop.tools.Register(&tools.Tool{
+		Name:        "dump",
+		Description: "Dump everything",
+		Parameters:  map[string]any{"type": "object", "properties": map[string]any{}},
+		Handler: func(_ context.Context, _ map[string]any) (string, error) {
+			return full, nil
+		},
+	})
+	loop.SetToolResultLimits(200, nil)
+
+	if _, err := loop.Run(context.Background(), &Request{
+		Messages: []Message{{Role: "user", Content: "dump it"}},
+	}, nil); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if len(mock.calls) < 2 {
+		t.Fatalf("LLM calls = %d, want 2", len(mock.calls))
+	}
+
+	var result string
+	for _, m := range mock.calls[1].Messages {
+		if m.Role == "tool" && m.ToolCallID == "call-1" {
+			result = m.Content
+		}
+	}
+	if !strings.HasPrefix(result, full[:200]) || !strings.Contains(result, "tool result truncated") {
+		t.Fatalf("tool result = %q, want the first 200 bytes and a truncation marker", result)
+	}
+	_, rest, _ := strings.Cut(result, "saved at ")
+	path, _, _ := strings.Cut(rest, ";")
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read saved result %q: %v", path, err)
+	}
+	if string(saved) != full {
+		t.Errorf("saved result is %d bytes, want the full %d", len(saved), len(full))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"math"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -32,10 +33,12 @@ import (
 // must appear here: a delegate that can call any of them can spawn
 // another delegate, which is exactly the structural recursion the
 // exclusion is meant to prevent. The family currently includes
-// thane_now (sync) and thane_assign (async). When adding a new
-// family member, add its name here in the same change.
+// thane_now (sync), thane_fanout (parallel sync), and thane_assign
+// (async). When adding a new family member, add its name here in the
+// same change.
 var delegateFamilyToolNames = []string{
 	"thane_now",
+	"thane_fanout",
 	"thane_assign",
 }
 
@@ -70,6 +73,24 @@ type executionOptions struct {
 	inheritCallerTags bool
 	explicitTagScope  bool
 	promptMode        agentctx.PromptMode
+
+	// model, when set, bypasses model selection.
+	model string
+
+	// restrictTools limits the run to allowedTools, exposing no tools
+	// at all when allowedTools is empty. Used by replay to hold a
+	// rerun to the tools the original execution called.
+	restrictTools bool
+	allowedTools  []string
+
+	// runtimeTools are request-scoped tools that override same-named
+	// registry tools for this run. Replay uses them to serve recorded
+	// results instead of calling tools live.
+	runtimeTools []looppkg.RuntimeTool
+
+	// replayOf is the archive session ID of the execution being
+	// replayed, recorded with the new execution.
+	replayOf string
 }
 
 func defaultExecutionOptions() executionOptions {
@@ -195,7 +216,7 @@ func applyRunPolicyDefaultTags(scopeTags []string, policy *RunPolicy, explicitSc
 func (e *Executor) delegateToolRegistry(scopeTags []string, explicitScopeRequested bool) *tools.Registry {
 	reg := e.parentReg
 	if len(scopeTags) > 0 || explicitScopeRequested {
-		merged := mergeTagLists(scopeTags, e.coreTags)
+		merged := mergeTagLists(scopeTags, e.currentCoreTags())
 		if len(merged) > 0 {
 			reg = reg.FilterByTags(merged)
 		} else {
@@ -255,6 +276,10 @@ type Result struct {
 	ExhaustReason            string            `json:"exhaust_reason,omitempty"`
 	ToolCalls                []ToolCallOutcome `json:"tool_calls,omitempty"`
 	Duration                 time.Duration     `json:"duration"`
+	// SessionID is the archive session holding the run's transcript
+	// and execution record, the ID [Executor.Replay] takes. Empty when
+	// no archive is configured.
+	SessionID string `json:"session_id,omitempty"`
 }
 
 // labelExpander expands temp file labels in task descriptions. Defined
@@ -274,6 +299,7 @@ type Executor struct {
 	defaultModel    string
 	archiver        *memory.ArchiveStore
 	tempFiles       labelExpander
+	coreTagsMu      sync.RWMutex // guards coreTags, replaced on config reload
 	coreTags        []string
 	lensProvider    func() []string // returns active global lenses (nil = none)
 	eventBus        *events.Bus
@@ -388,7 +414,18 @@ func (e *Executor) SetEventBus(bus *events.Bus) {
 // pinned in every scope so the delegate's surface stays consistent
 // with the parent loop's baseline.
 func (e *Executor) SetCoreTags(tags []string) {
+	tags = append([]string(nil), tags...)
+	e.coreTagsMu.Lock()
 	e.coreTags = tags
+	e.coreTagsMu.Unlock()
+}
+
+// currentCoreTags returns the core tag set in effect. The slice is
+// replaced whole by [Executor.SetCoreTags] and never mutated.
+func (e *Executor) currentCoreTags() []string {
+	e.coreTagsMu.RLock()
+	defer e.coreTagsMu.RUnlock()
+	return e.coreTags
 }
 
 // SetLensProvider configures a function that returns the currently
@@ -477,7 +514,7 @@ func (e *Executor) startBackground(ctx context.Context, task, profileName, guida
 		return "", prep.runPolicy.Name, fmt.Errorf("background delegation requires a target conversation")
 	}
 
-	loopName := "delegate-" + promptfmt.ShortIDPrefix(prep.id)
+	loopName := "delegate-" + promptfmt.ShortIDSuffix(prep.id)
 	loopMaxDuration := prep.maxDuration + 5*time.Second
 	if loopMaxDuration <= 0 {
 		loopMaxDuration = prep.maxDuration
@@ -514,12 +551,18 @@ type preparedExecution struct {
 	channelBinding   *memory.ChannelBinding
 	runPolicy        *RunPolicy
 	routeHints       map[string]string
+	parentSource     string
 	log              *slog.Logger
+	task             string
+	guidance         string
 	userMessage      string
 	model            string
 	scopeTags        []string
 	filterTags       []string
 	excludeTools     []string
+	allowedTools     []string
+	runtimeTools     []looppkg.RuntimeTool
+	replayOf         string
 	tagFilterActive  bool
 	effectiveTags    []string
 	maxIterations    int
@@ -535,7 +578,7 @@ func (e *Executor) executeViaLoop(ctx context.Context, task, profileName, guidan
 		return nil, err
 	}
 
-	loopName := "delegate-" + promptfmt.ShortIDPrefix(prep.id)
+	loopName := "delegate-" + promptfmt.ShortIDSuffix(prep.id)
 	loopMaxDuration := prep.maxDuration + 5*time.Second
 	if loopMaxDuration <= 0 {
 		loopMaxDuration = prep.maxDuration
@@ -567,7 +610,7 @@ func (e *Executor) executeViaLoop(ctx context.Context, task, profileName, guidan
 			e.finishLoopExecution(prep)
 			toolCallsMu.Lock()
 			defer toolCallsMu.Unlock()
-			return &Result{
+			result := &Result{
 				RunPolicyName: prep.runPolicy.Name,
 				Content:       "Delegate was unable to complete the task within its time limit.",
 				Model:         prep.model,
@@ -575,7 +618,9 @@ func (e *Executor) executeViaLoop(ctx context.Context, task, profileName, guidan
 				ExhaustReason: ExhaustWallClock,
 				ToolCalls:     append([]ToolCallOutcome(nil), toolCalls...),
 				Duration:      time.Since(runStart),
-			}, nil
+			}
+			e.recordExecution(prep, result)
+			return result, nil
 		}
 		if errors.Is(err, context.Canceled) {
 			shouldFinish := e.stopLoopForCancellation(launchResult.LoopID, prep)
@@ -604,7 +649,7 @@ func (e *Executor) executeViaLoop(ctx context.Context, task, profileName, guidan
 		exhaustReason = ExhaustNoOutput
 	}
 
-	return &Result{
+	result := &Result{
 		RunPolicyName:            prep.runPolicy.Name,
 		Content:                  resp.Content,
 		Model:                    resp.Model,
@@ -617,15 +662,20 @@ func (e *Executor) executeViaLoop(ctx context.Context, task, profileName, guidan
 		ExhaustReason:            exhaustReason,
 		ToolCalls:                append([]ToolCallOutcome(nil), toolCalls...),
 		Duration:                 time.Since(runStart),
-	}, nil
+	}
+	e.recordExecution(prep, result)
+	return result, nil
 }
 
 func (e *Executor) buildLoopLaunch(prep *preparedExecution, task, guidance string, operation looppkg.Operation, completion looppkg.Completion, completionConversationID string, completionChannel *looppkg.CompletionChannelTarget, loopName string, loopMaxDuration time.Duration, onProgress func(kind string, data map[string]any)) looppkg.Launch {
-	factors := make(map[string]string, len(prep.routeHints)+1)
+	factors := make(map[string]string, len(prep.routeHints)+2)
 	for k, v := range prep.routeHints {
 		factors[k] = v
 	}
 	factors["source"] = "delegate"
+	if prep.parentSource != "" {
+		factors[tools.HintParentSource] = prep.parentSource
+	}
 
 	return looppkg.Launch{
 		Spec: looppkg.Spec{
@@ -647,12 +697,14 @@ func (e *Executor) buildLoopLaunch(prep *preparedExecution, task, guidance strin
 				"delegate_guidance": truncate(guidance, 500),
 				"prompt_mode":       string(prep.promptMode),
 			},
+			RuntimeTools: prep.runtimeTools,
 		},
 		Task:                     prep.userMessage,
 		ParentID:                 prep.parentLoopID,
 		ConversationID:           prep.conversationID,
 		ChannelBinding:           prep.channelBinding.Clone(),
 		RoutingFactors:           factors,
+		AllowedTools:             append([]string(nil), prep.allowedTools...),
 		ExcludeTools:             append([]string(nil), prep.excludeTools...),
 		SkipTagFilter:            !prep.tagFilterActive,
 		InitialTags:              append([]string(nil), prep.effectiveTags...),
@@ -771,7 +823,7 @@ func (e *Executor) prepareExecution(ctx context.Context, task, profileName, guid
 
 	delegateID, _ := uuid.NewV7()
 	did := delegateID.String()
-	conversationID := "delegate-" + promptfmt.ShortIDPrefix(did)
+	conversationID := "delegate-" + promptfmt.ShortIDSuffix(did)
 
 	log := logging.Logger(ctx).With(
 		"subsystem", logging.SubsystemDelegate,
@@ -810,9 +862,10 @@ func (e *Executor) prepareExecution(ctx context.Context, task, profileName, guid
 	policy := e.runPolicyForScope(profileName, scopeTags)
 	scopeTags, policyDefaultTags := applyRunPolicyDefaultTags(scopeTags, policy, explicitScopeRequested)
 
+	coreTags := e.currentCoreTags()
 	reg := e.delegateToolRegistry(scopeTags, explicitScopeRequested)
 	toolDefs := reg.List()
-	filterTags := mergeTagLists(scopeTags, e.coreTags)
+	filterTags := mergeTagLists(scopeTags, coreTags)
 	// Delegate tool exclusions must hold on every code path. The
 	// loop-backed launch rebuilds the catalog from the parent registry
 	// filtered by the launched loop's request, so the exclusions have to
@@ -822,8 +875,21 @@ func (e *Executor) prepareExecution(ctx context.Context, task, profileName, guid
 	// The explicit-empty-scope branch (AllToolNames) already includes the
 	// excluded names, so dedup before sorting to avoid duplicate entries
 	// when both sources contribute.
+	//
+	// A caller held to a conversation tool policy passes it on: the
+	// delegate may use only tools the policy allows, on top of any
+	// restriction of its own.
+	restrictTools, allowedTools := opts.restrictTools, append([]string(nil), opts.allowedTools...)
+	if policyAllow, ok := tools.ToolPolicyFromContext(ctx); ok {
+		if restrictTools {
+			allowedTools = slices.DeleteFunc(allowedTools, func(name string) bool { return !slices.Contains(policyAllow, name) })
+		} else {
+			restrictTools, allowedTools = true, policyAllow
+		}
+		log.Info("delegate held to caller's tool policy", "allowed", allowedTools)
+	}
 	var excludeTools []string
-	if explicitScopeRequested && len(filterTags) == 0 {
+	if (explicitScopeRequested && len(filterTags) == 0) || (restrictTools && len(allowedTools) == 0) {
 		excludeTools = e.parentReg.AllToolNames()
 	}
 	excludeTools = mergeExcludeToolNames(excludeTools, delegateToolExclusions())
@@ -840,11 +906,11 @@ func (e *Executor) prepareExecution(ctx context.Context, task, profileName, guid
 		"tools_available", len(toolDefs),
 	)
 
-	effectiveTagsMap := make(map[string]bool, len(scopeTags)+len(e.coreTags))
+	effectiveTagsMap := make(map[string]bool, len(scopeTags)+len(coreTags))
 	for _, t := range scopeTags {
 		effectiveTagsMap[t] = true
 	}
-	for _, t := range e.coreTags {
+	for _, t := range coreTags {
 		effectiveTagsMap[t] = true
 	}
 	if e.lensProvider != nil {
@@ -873,6 +939,11 @@ func (e *Executor) prepareExecution(ctx context.Context, task, profileName, guid
 		userMsg.WriteString(guidance)
 	}
 
+	model := opts.model
+	if model == "" {
+		model = e.selectModel(ctx, task, policy, len(toolDefs))
+	}
+
 	maxIterations := policy.MaxIter
 	if maxIterations <= 0 {
 		maxIterations = defaultMaxIter
@@ -898,12 +969,18 @@ func (e *Executor) prepareExecution(ctx context.Context, task, profileName, guid
 		channelBinding:   tools.ChannelBindingFromContext(ctx),
 		runPolicy:        policy,
 		routeHints:       e.effectiveDelegateRouterHints(ctx, policy),
+		parentSource:     tools.OriginSource(tools.HintsFromContext(ctx)),
 		log:              log,
+		task:             task,
+		guidance:         guidance,
 		userMessage:      userMsg.String(),
-		model:            e.selectModel(ctx, task, policy, len(toolDefs)),
+		model:            model,
 		scopeTags:        append([]string(nil), scopeTags...),
 		filterTags:       filterTags,
 		excludeTools:     excludeTools,
+		allowedTools:     allowedTools,
+		runtimeTools:     opts.runtimeTools,
+		replayOf:         opts.replayOf,
 		tagFilterActive:  tagFilterActive,
 		effectiveTags:    effectiveTags,
 		maxIterations:    maxIterations,
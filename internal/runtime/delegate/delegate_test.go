@@ -134,6 +134,30 @@ func (m *mockLoopRunner) Run(_ context.Context, req looppkg.Request, _ looppkg.S
 	return m.resp, m.err
 }
 
+func TestExecute_CarriesParentSource(t *testing.T) {
+	t.Parallel()
+
+	var captured looppkg.Request
+	exec := NewExecutor(slog.Default(), nil, nil, newTestRegistry(), "spark/gpt-oss:20b")
+	exec.ConfigureLoopExecution(&mockLoopRunner{
+		onRun: func(req looppkg.Request) { captured = req },
+		resp:  &looppkg.Response{Content: "done", Model: "spark/gpt-oss:20b"},
+	}, looppkg.NewRegistry())
+
+	// A fanout branch of a delegate of a scheduler run still reports
+	// the scheduler as its origin.
+	ctx := tools.WithHints(context.Background(), map[string]string{
+		"source":               "delegate",
+		tools.HintParentSource: "scheduler",
+	})
+	if _, err := exec.Execute(ctx, "Lock the front door", "", "", nil); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if captured.RoutingFactors["source"] != "delegate" || captured.RoutingFactors[tools.HintParentSource] != "scheduler" {
+		t.Fatalf("RoutingFactors = %v, want source delegate from parent scheduler", captured.RoutingFactors)
+	}
+}
+
 func TestExecute_LoopBackedPathUsesLaunch(t *testing.T) {
 	t.Parallel()
 
@@ -386,6 +410,41 @@ func TestExecute_LoopBackedTagScopedExcludesDelegateFamily(t *testing.T) {
 	}
 }
 
+func TestExecute_LoopBackedInheritsCallerToolPolicy(t *testing.T) {
+	t.Parallel()
+
+	run := func(t *testing.T, allow []string) looppkg.Request {
+		t.Helper()
+		var captured looppkg.Request
+		runner := &mockLoopRunner{
+			onRun: func(req looppkg.Request) {
+				captured = req
+			},
+			resp: &looppkg.Response{Content: "delegate answer", Model: "deepslate/google/gemma-3-4b"},
+		}
+		exec := NewExecutor(slog.Default(), nil, nil, newTestRegistry(), "spark/gpt-oss:20b")
+		exec.ConfigureLoopExecution(runner, looppkg.NewRegistry())
+		ctx := tools.WithToolPolicy(context.Background(), allow)
+		if _, err := exec.execute(ctx, "task", "", "", nil, defaultExecutionOptions()); err != nil {
+			t.Fatalf("execute() error = %v", err)
+		}
+		return captured
+	}
+
+	got := run(t, []string{"web_search"})
+	if len(got.AllowedTools) != 1 || got.AllowedTools[0] != "web_search" {
+		t.Errorf("AllowedTools = %#v, want only the policy's web_search", got.AllowedTools)
+	}
+
+	// A policy that allows nothing leaves the delegate with no tools.
+	got = run(t, nil)
+	for _, want := range []string{"ha_get_state", "web_search"} {
+		if !containsString(got.ExcludeTools, want) {
+			t.Errorf("ExcludeTools = %#v, want %s withheld under an empty policy", got.ExcludeTools, want)
+		}
+	}
+}
+
 func TestExecute_LoopBackedTagScopedExcludesDirectHumanEgress(t *testing.T) {
 	t.Parallel()
 
@@ -1635,6 +1694,36 @@ func TestApplyRunPolicyDefaultTags(t *testing.T) {
 	}
 }
 
+// TestSetCoreTags_ReloadDuringDelegation swaps the core tag set, as a
+// config reload does, while delegations build their tool registries.
+// Run it with -race.
+func TestSetCoreTags_ReloadDuringDelegation(t *testing.T) {
+	t.Parallel()
+
+	exec := NewExecutor(slog.Default(), nil, nil, newTestRegistry(), "spark/gpt-oss:20b")
+	exec.SetCoreTags([]string{"ha"})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			if i%2 == 0 {
+				exec.SetCoreTags(nil)
+			} else {
+				exec.SetCoreTags([]string{"ha"})
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			_ = exec.delegateToolRegistry([]string{"web"}, false)
+		}
+	}()
+	wg.Wait()
+}
+
 // TestDelegateToolRegistry_BranchCoverage covers the four
 // scopeTags × explicitScopeRequested × coreTags branches of
 // [Executor.delegateToolRegistry] that the existing exclusion-focused
@@ -0,0 +1,236 @@
+package delegate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fan-out limits. Each branch is a full delegate run with its own
+// budget, so the caps bound how much work one tool call can start.
+const (
+	maxFanoutBranches       = 8
+	defaultFanoutConcurrent = 3
+	maxFanoutConcurrent     = 5
+	defaultFanoutTimeout    = 3 * time.Minute
+	maxFanoutTimeout        = 10 * time.Minute
+)
+
+// FanoutToolDescription is the LLM-facing description for thane_fanout,
+// the parallel sync member of the thane_* family.
+const FanoutToolDescription = "Run several independent bounded tasks as concurrent sub-agents and return all of their results inline, in order. " +
+	"Use when one request splits into parallel pieces that do not depend on each other — summarize these five documents, check each of these rooms, compare these three options. " +
+	"Each task runs as its own delegate with its own budget; a failed or exhausted task is reported in its section without failing the others. " +
+	"Shared guidance, tags, and context_mode apply to every task; a task's own guidance is appended and its own tags replace the shared ones. " +
+	"For a single task use thane_now; for background work use thane_assign."
+
+// FanoutToolDefinition returns the JSON schema for thane_fanout.
+func FanoutToolDefinition() map[string]any {
+	props := commonDelegateProperties()
+	delete(props, "task")
+	props["tasks"] = map[string]any{
+		"type":     "array",
+		"minItems": 1,
+		"maxItems": maxFanoutBranches,
+		"items": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"task": map[string]any{
+					"type":        "string",
+					"description": "Plain English description of what this branch should accomplish.",
+				},
+				"guidance": map[string]any{
+					"type":        "string",
+					"description": "Optional hints for this branch only, appended to the shared guidance.",
+				},
+				"tags": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Optional capability tags for this branch, replacing the shared tags.",
+				},
+			},
+			"required": []string{"task"},
+		},
+		"description": fmt.Sprintf("The independent tasks to run, at most %d.", maxFanoutBranches),
+	}
+	props["max_concurrency"] = map[string]any{
+		"type":        "integer",
+		"minimum":     1,
+		"maximum":     maxFanoutConcurrent,
+		"default":     defaultFanoutConcurrent,
+		"description": "How many tasks may run at the same time.",
+	}
+	props["timeout_seconds"] = map[string]any{
+		"type":        "integer",
+		"minimum":     1,
+		"maximum":     int(maxFanoutTimeout / time.Second),
+		"default":     int(defaultFanoutTimeout / time.Second),
+		"description": "Overall time limit for the whole batch. Tasks still running when it expires are stopped and reported as timed out; tasks not yet started are skipped.",
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": props,
+		"required":   []string{"tasks"},
+	}
+}
+
+// FanoutToolHandler returns the handler for thane_fanout.
+func FanoutToolHandler(exec *Executor) func(ctx context.Context, args map[string]any) (string, error) {
+	return func(ctx context.Context, args map[string]any) (string, error) {
+		req, errMsg := parseFanoutArgs(args)
+		if errMsg != "" {
+			return errMsg, nil
+		}
+		return runFanout(ctx, exec, req), nil
+	}
+}
+
+// fanoutRequest captures the parsed arguments of thane_fanout. Each
+// branch is a complete [delegateRequest] with shared arguments already
+// folded in.
+type fanoutRequest struct {
+	branches    []delegateRequest
+	concurrency int
+	timeout     time.Duration
+}
+
+// parseFanoutArgs extracts the branches and batch limits. Shared args
+// go through [parseDelegateOptions] so validation matches thane_now.
+func parseFanoutArgs(args map[string]any) (fanoutRequest, string) {
+	req := fanoutRequest{concurrency: defaultFanoutConcurrent, timeout: defaultFanoutTimeout}
+
+	rawTasks, _ := args["tasks"].([]any)
+	if len(rawTasks) == 0 {
+		return req, "Error: tasks is required"
+	}
+	if len(rawTasks) > maxFanoutBranches {
+		return req, fmt.Sprintf("Error: at most %d tasks may run in one fan-out, got %d", maxFanoutBranches, len(rawTasks))
+	}
+
+	base, errMsg := parseDelegateOptions(args)
+	if errMsg != "" {
+		return req, errMsg
+	}
+
+	for i, raw := range rawTasks {
+		item, _ := raw.(map[string]any)
+		task, _ := item["task"].(string)
+		if strings.TrimSpace(task) == "" {
+			return req, fmt.Sprintf("Error: tasks[%d].task is required", i)
+		}
+		branch := base
+		branch.task = task
+		if guidance, _ := item["guidance"].(string); guidance != "" {
+			if branch.guidance != "" {
+				branch.guidance += "\n\n" + guidance
+			} else {
+				branch.guidance = guidance
+			}
+		}
+		if rawTags, ok := item["tags"].([]any); ok {
+			branch.tagsProvided = true
+			branch.tags = make([]string, 0, len(rawTags))
+			for _, rt := range rawTags {
+				if s, ok := rt.(string); ok {
+					branch.tags = append(branch.tags, s)
+				}
+			}
+		}
+		req.branches = append(req.branches, branch)
+	}
+
+	if raw, ok := args["max_concurrency"].(float64); ok && raw > 0 {
+		req.concurrency = min(int(raw), maxFanoutConcurrent)
+	}
+	if raw, ok := args["timeout_seconds"].(float64); ok && raw > 0 {
+		req.timeout = min(time.Duration(raw)*time.Second, maxFanoutTimeout)
+	}
+	return req, ""
+}
+
+// fanoutBranch is one branch's outcome. Exactly one of result and err
+// is set once the branch has run; skipped marks a branch the batch
+// timeout reached before it could start.
+type fanoutBranch struct {
+	result  *Result
+	err     error
+	skipped bool
+}
+
+// succeeded reports whether the branch produced a usable answer.
+func (b fanoutBranch) succeeded() bool {
+	return b.err == nil && !b.skipped && b.result != nil && !b.result.Exhausted && b.result.Content != ""
+}
+
+// runFanout executes every branch through the same sync path as
+// thane_now, at most req.concurrency at a time and all under one
+// batch deadline. Each branch is its own delegate execution — its own
+// archive session linked to the caller's, its own loop, and its own
+// usage records — so a failure stays local to the branch and token
+// spend is attributed per branch.
+func runFanout(ctx context.Context, exec *Executor, req fanoutRequest) string {
+	ctx, cancel := context.WithTimeout(ctx, req.timeout)
+	defer cancel()
+
+	start := time.Now()
+	outcomes := make([]fanoutBranch, len(req.branches))
+	sem := make(chan struct{}, req.concurrency)
+	var wg sync.WaitGroup
+	for i, branch := range req.branches {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			outcomes[i].skipped = true
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			opts := executionOptions{
+				inheritCallerTags: branch.inheritCallerTags,
+				explicitTagScope:  branch.tagsProvided,
+				promptMode:        branch.contextMode,
+			}
+			outcomes[i].result, outcomes[i].err = exec.execute(ctx, branch.task, branch.profileName, branch.guidance, branch.tags, opts)
+		}()
+	}
+	wg.Wait()
+
+	return formatFanoutResult(req, outcomes, time.Since(start))
+}
+
+// formatFanoutResult renders the batch header followed by one section
+// per branch in request order. Branch sections reuse the thane_now
+// format so the calling model reads each outcome the same way.
+func formatFanoutResult(req fanoutRequest, outcomes []fanoutBranch, elapsed time.Duration) string {
+	var succeeded, tokensIn, tokensOut int
+	for _, o := range outcomes {
+		if o.succeeded() {
+			succeeded++
+		}
+		if o.result != nil {
+			tokensIn += o.result.InputTokens
+			tokensOut += o.result.OutputTokens
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "[Delegate FANOUT: branches=%d, succeeded=%d, failed=%d, concurrency=%d, duration=%s, tokens_in=%s, tokens_out=%s]",
+		len(outcomes), succeeded, len(outcomes)-succeeded, req.concurrency,
+		formatDuration(elapsed), formatTokens(tokensIn), formatTokens(tokensOut))
+
+	for i, o := range outcomes {
+		branch := req.branches[i]
+		fmt.Fprintf(&out, "\n\n=== Branch %d/%d: %s ===\n", i+1, len(outcomes), truncate(branch.task, 80))
+		if o.skipped {
+			fmt.Fprintf(&out, "[Delegate SKIPPED: profile=%s] The fan-out time limit (%s) expired before this task started.",
+				branch.profileName, formatDuration(req.timeout))
+			continue
+		}
+		out.WriteString(strings.TrimRight(formatNowResult(branch.profileName, o.result, o.err), "\n"))
+	}
+	return out.String()
+}
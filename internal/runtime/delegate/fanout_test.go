@@ -0,0 +1,194 @@
+package delegate
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	looppkg "github.com/nugget/thane-ai-agent/internal/runtime/loop"
+)
+
+// fanoutLoopRunner answers each delegate run by its task text and
+// records the peak number of runs in flight.
+type fanoutLoopRunner struct {
+	delay time.Duration
+
+	mu      sync.Mutex
+	active  int
+	peak    int
+	convIDs []string
+}
+
+func (r *fanoutLoopRunner) Run(ctx context.Context, req looppkg.Request, _ looppkg.StreamCallback) (*looppkg.Response, error) {
+	r.mu.Lock()
+	r.active++
+	r.peak = max(r.peak, r.active)
+	r.convIDs = append(r.convIDs, req.ConversationID)
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.active--
+		r.mu.Unlock()
+	}()
+
+	// The task is the last line of the delegate's message, after the
+	// run instructions.
+	msg := req.Messages[len(req.Messages)-1].Content
+	task := msg[strings.LastIndex(msg, "\n")+1:]
+	switch {
+	case strings.Contains(task, "hang"):
+		<-ctx.Done()
+		return nil, ctx.Err()
+	case strings.Contains(task, "break"):
+		return nil, errors.New("model unavailable")
+	}
+	select {
+	case <-time.After(r.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &looppkg.Response{
+		Content:      "summary of " + task,
+		Model:        "test-model",
+		InputTokens:  100,
+		OutputTokens: 10,
+	}, nil
+}
+
+func newFanoutTestExecutor(runner looppkg.Runner) *Executor {
+	exec := NewExecutor(slog.Default(), nil, nil, newTestRegistry(), "test-model")
+	exec.ConfigureLoopExecution(runner, looppkg.NewRegistry())
+	return exec
+}
+
+func TestFanoutToolHandler_AggregatesBranchesAndIsolatesFailures(t *testing.T) {
+	t.Parallel()
+
+	runner := &fanoutLoopRunner{delay: 20 * time.Millisecond}
+	exec := newFanoutTestExecutor(runner)
+
+	result, err := FanoutToolHandler(exec)(context.Background(), map[string]any{
+		"tasks": []any{
+			map[string]any{"task": "doc one"},
+			map[string]any{"task": "doc two"},
+			map[string]any{"task": "break doc three"},
+			map[string]any{"task": "doc four"},
+		},
+		"max_concurrency": float64(2),
+	})
+	if err != nil {
+		t.Fatalf("FanoutToolHandler error: %v", err)
+	}
+
+	for _, want := range []string{
+		"[Delegate FANOUT: branches=4, succeeded=3, failed=1, concurrency=2",
+		"tokens_in=300, tokens_out=30",
+		"=== Branch 1/4: doc one ===\n[Delegate SUCCEEDED:",
+		"summary of doc two",
+		"=== Branch 3/4: break doc three ===\n[Delegate error: profile=general] delegate failed:",
+		"model unavailable",
+		"summary of doc four",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("result missing %q:\n%s", want, result)
+		}
+	}
+	if strings.Index(result, "doc one") > strings.Index(result, "doc four") {
+		t.Errorf("branches out of request order:\n%s", result)
+	}
+
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	if runner.peak > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", runner.peak)
+	}
+	seen := make(map[string]bool)
+	for _, id := range runner.convIDs {
+		if seen[id] {
+			t.Errorf("conversation %s shared between branches; usage must be attributed per branch", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestRunFanout_TimeoutSkipsPendingBranches(t *testing.T) {
+	t.Parallel()
+
+	exec := newFanoutTestExecutor(&fanoutLoopRunner{})
+	req, errMsg := parseFanoutArgs(map[string]any{
+		"tasks": []any{
+			map[string]any{"task": "hang forever"},
+			map[string]any{"task": "never started"},
+		},
+		"max_concurrency": float64(1),
+	})
+	if errMsg != "" {
+		t.Fatal(errMsg)
+	}
+	req.timeout = 50 * time.Millisecond
+
+	result := runFanout(context.Background(), exec, req)
+	for _, want := range []string{
+		"succeeded=0, failed=2",
+		"=== Branch 1/2: hang forever ===\n[Delegate FAILED:",
+		"=== Branch 2/2: never started ===\n[Delegate SKIPPED: profile=general]",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("result missing %q:\n%s", want, result)
+		}
+	}
+}
+
+func TestParseFanoutArgs(t *testing.T) {
+	t.Parallel()
+
+	req, errMsg := parseFanoutArgs(map[string]any{
+		"tasks": []any{
+			map[string]any{"task": "a", "guidance": "only a"},
+			map[string]any{"task": "b", "tags": []any{"web"}},
+		},
+		"guidance":        "be brief",
+		"tags":            []any{"documents"},
+		"context_mode":    "full",
+		"max_concurrency": float64(50),
+		"timeout_seconds": float64(30),
+	})
+	if errMsg != "" {
+		t.Fatal(errMsg)
+	}
+	if req.concurrency != maxFanoutConcurrent {
+		t.Errorf("concurrency = %d, want clamped to %d", req.concurrency, maxFanoutConcurrent)
+	}
+	if req.timeout != 30*time.Second {
+		t.Errorf("timeout = %v, want 30s", req.timeout)
+	}
+	a, b := req.branches[0], req.branches[1]
+	if a.guidance != "be brief\n\nonly a" || b.guidance != "be brief" {
+		t.Errorf("guidance = %q / %q", a.guidance, b.guidance)
+	}
+	if !stringSliceEqual(a.tags, []string{"documents"}) || !stringSliceEqual(b.tags, []string{"web"}) {
+		t.Errorf("tags = %v / %v", a.tags, b.tags)
+	}
+	if a.contextMode != "full" || b.contextMode != "full" {
+		t.Errorf("context modes = %q / %q, want shared full", a.contextMode, b.contextMode)
+	}
+
+	tooMany := make([]any, maxFanoutBranches+1)
+	for i := range tooMany {
+		tooMany[i] = map[string]any{"task": "x"}
+	}
+	for name, args := range map[string]map[string]any{
+		"no tasks":       {},
+		"empty task":     {"tasks": []any{map[string]any{"task": " "}}},
+		"too many tasks": {"tasks": tooMany},
+		"bad mode":       {"tasks": []any{map[string]any{"task": "x"}}, "context_mode": "huge"},
+	} {
+		if _, errMsg := parseFanoutArgs(args); !strings.HasPrefix(errMsg, "Error:") {
+			t.Errorf("%s: errMsg = %q, want an error", name, errMsg)
+		}
+	}
+}
@@ -4,7 +4,8 @@
 // set. thane_now is the synchronous front door (the caller blocks for the
 // result); thane_assign is the async one-shot front door (the result is
 // delivered back through the conversation/channel when the delegate
-// completes).
+// completes). thane_fanout runs several thane_now-style tasks
+// concurrently under one batch deadline and returns them together.
 //
 // # Tag scope and inheritance
 //
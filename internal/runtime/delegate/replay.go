@@ -0,0 +1,261 @@
+package delegate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/platform/logging"
+	"github.com/nugget/thane-ai-agent/internal/runtime/agentctx"
+	looppkg "github.com/nugget/thane-ai-agent/internal/runtime/loop"
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
+)
+
+// ErrExecutionNotFound is returned by [Executor.Replay] when the ID
+// names no archive session, or a session without a delegate execution
+// record.
+var ErrExecutionNotFound = errors.New("delegate execution not found")
+
+// ReplayResult pairs a stored delegate execution with a rerun of it,
+// for comparing how two models handle the same delegation.
+type ReplayResult struct {
+	// SessionID is the archive session of the original execution.
+	SessionID string `json:"session_id"`
+	// Original is the original execution's record.
+	Original *memory.DelegationMetadata `json:"original"`
+	// Replay is the rerun. Its SessionID is the replay's own archive
+	// session, whose execution record links back through ReplayOf.
+	Replay *Result `json:"replay"`
+}
+
+// recordExecution stores the execution record for a finished delegate
+// run in its archive session, so it can later be replayed or compared.
+func (e *Executor) recordExecution(prep *preparedExecution, result *Result) {
+	if e.archiver == nil || prep.archiveSessionID == "" {
+		return
+	}
+	result.SessionID = prep.archiveSessionID
+
+	toolCalls := make([]string, 0, len(result.ToolCalls))
+	for _, tc := range result.ToolCalls {
+		toolCalls = append(toolCalls, tc.Name)
+	}
+	err := e.archiver.SetSessionDelegation(prep.archiveSessionID, &memory.DelegationMetadata{
+		Task:          prep.task,
+		Guidance:      prep.guidance,
+		Profile:       prep.runPolicy.Name,
+		Tags:          append([]string(nil), prep.scopeTags...),
+		PromptMode:    string(prep.promptMode),
+		Model:         result.Model,
+		Iterations:    result.Iterations,
+		MaxIterations: prep.maxIterations,
+		InputTokens:   result.InputTokens,
+		OutputTokens:  result.OutputTokens,
+		Exhausted:     result.Exhausted,
+		ExhaustReason: result.ExhaustReason,
+		ResultContent: result.Content,
+		DurationMs:    result.Duration.Milliseconds(),
+		ToolCalls:     toolCalls,
+		ReplayOf:      prep.replayOf,
+	})
+	if err != nil {
+		prep.log.Warn("failed to record delegate execution", "error", err)
+	}
+}
+
+// Replay reruns the stored delegate execution executionID, an archive
+// session ID or unique prefix of one, and returns the original record
+// alongside the new result. The rerun uses the original task, guidance,
+// run policy, tags, and prompt mode, and may call only the tools the
+// original called. It runs on overrideModel, or on the original's model
+// when overrideModel is empty.
+//
+// Tools do not execute live. Each is replaced by a stub that serves the
+// result the original execution recorded for the same arguments, so a
+// replayed delegation that changed something (called a service, sent a
+// message) does not do so again. A call the original never made gets
+// an error instead. The rerun is recorded in its own archive session,
+// linked to the original.
+func (e *Executor) Replay(ctx context.Context, executionID, overrideModel string) (*ReplayResult, error) {
+	if e.archiver == nil {
+		return nil, fmt.Errorf("delegate replay requires the session archive")
+	}
+	sessionID, err := e.archiver.ResolveSessionID(executionID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExecutionNotFound, err)
+	}
+	sess, err := e.archiver.GetSession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("load delegate session: %w", err)
+	}
+	if sess.Metadata == nil || sess.Metadata.Delegation == nil {
+		return nil, fmt.Errorf("%w: session %s has no execution record", ErrExecutionNotFound, memory.ShortID(sessionID))
+	}
+	original := sess.Metadata.Delegation
+
+	model := overrideModel
+	if model == "" {
+		model = original.Model
+	}
+	recorded, err := e.recordedToolCalls(sessionID, original)
+	if err != nil {
+		return nil, fmt.Errorf("load recorded tool calls: %w", err)
+	}
+	allowed := recordedToolNames(original)
+	opts := executionOptions{
+		explicitTagScope: len(original.Tags) > 0,
+		promptMode:       agentctx.PromptMode(original.PromptMode),
+		model:            model,
+		restrictTools:    true,
+		allowedTools:     allowed,
+		runtimeTools:     e.replayTools(allowed, recorded),
+		replayOf:         sessionID,
+	}
+
+	logging.Logger(ctx).Info("delegate replay started",
+		"subsystem", logging.SubsystemDelegate,
+		"session_id", memory.ShortID(sessionID),
+		"original_model", original.Model,
+		"model", model,
+		"tools", opts.allowedTools,
+		"recorded_calls", len(recorded.calls),
+	)
+	result, err := e.execute(ctx, original.Task, original.Profile, original.Guidance, original.Tags, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayResult{SessionID: sessionID, Original: original, Replay: result}, nil
+}
+
+// recordedCall is one tool call of a stored execution with its outcome.
+type recordedCall struct {
+	name   string
+	args   string // canonical JSON of the arguments
+	result string
+	err    string
+	served bool
+}
+
+// recordedCalls serves a replay's tool calls from the original
+// execution's record. Handlers run concurrently when the model batches
+// calls, so access is serialized.
+type recordedCalls struct {
+	mu    sync.Mutex
+	calls []*recordedCall
+}
+
+// serve returns the recorded outcome of a call to name with args. The
+// first unserved call with identical arguments wins; once all of those
+// are served, the last one is repeated, so a model that re-reads a value
+// gets the same answer.
+func (r *recordedCalls) serve(name string, args map[string]any) (string, error) {
+	key := canonicalToolArgs(args)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var match *recordedCall
+	for _, c := range r.calls {
+		if c.name != name || c.args != key {
+			continue
+		}
+		match = c
+		if !c.served {
+			break
+		}
+	}
+	if match == nil {
+		return "", fmt.Errorf("replay: the original execution made no %s call with these arguments, and tools do not run live during a replay", name)
+	}
+	match.served = true
+	if match.err != "" {
+		return "", errors.New(match.err)
+	}
+	return match.result, nil
+}
+
+// recordedToolCalls loads the tool calls the stored execution in
+// sessionID made, in order, with their results. Records imported from
+// the legacy delegations table have no archived tool calls, so their
+// serialized message history is read instead.
+func (e *Executor) recordedToolCalls(sessionID string, d *memory.DelegationMetadata) (*recordedCalls, error) {
+	archived, err := e.archiver.GetSessionToolCalls(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	rec := &recordedCalls{}
+	for _, tc := range archived {
+		var args map[string]any
+		if tc.Arguments != "" {
+			_ = json.Unmarshal([]byte(tc.Arguments), &args)
+		}
+		rec.calls = append(rec.calls, &recordedCall{
+			name:   tc.ToolName,
+			args:   canonicalToolArgs(args),
+			result: tc.Result,
+			err:    tc.Error,
+		})
+	}
+	if len(rec.calls) > 0 || d.Messages == "" {
+		return rec, nil
+	}
+
+	var msgs []llm.Message
+	if err := json.Unmarshal([]byte(d.Messages), &msgs); err != nil {
+		return rec, nil
+	}
+	byID := make(map[string]*recordedCall)
+	for _, msg := range msgs {
+		for _, tc := range msg.ToolCalls {
+			c := &recordedCall{name: tc.Function.Name, args: canonicalToolArgs(tc.Function.Arguments)}
+			rec.calls = append(rec.calls, c)
+			if tc.ID != "" {
+				byID[tc.ID] = c
+			}
+		}
+		if msg.Role == "tool" && msg.ToolCallID != "" {
+			if c := byID[msg.ToolCallID]; c != nil {
+				c.result = msg.Content
+			}
+		}
+	}
+	return rec, nil
+}
+
+// replayTools builds the request-scoped stand-ins for the named tools.
+// They keep the real tools' descriptions and schemas, so the replay
+// model sees the same surface, but answer from rec.
+func (e *Executor) replayTools(names []string, rec *recordedCalls) []looppkg.RuntimeTool {
+	stubs := make([]looppkg.RuntimeTool, 0, len(names))
+	for _, name := range names {
+		stub := looppkg.RuntimeTool{
+			Name:               name,
+			Description:        "Replays the recorded result of " + name + ".",
+			Parameters:         map[string]any{"type": "object"},
+			SkipContentResolve: true,
+		}
+		if t := e.parentReg.Get(name); t != nil {
+			stub.Description = t.Description
+			stub.Parameters = t.Parameters
+		}
+		stub.Handler = func(_ context.Context, args map[string]any) (string, error) {
+			return rec.serve(name, args)
+		}
+		stubs = append(stubs, stub)
+	}
+	return stubs
+}
+
+// canonicalToolArgs renders tool arguments as JSON with sorted keys, so
+// equal arguments compare equal however they were produced.
+func canonicalToolArgs(args map[string]any) string {
+	if len(args) == 0 {
+		return "{}"
+	}
+	b, err := json.Marshal(args)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,196 @@
+package delegate
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/agentctx"
+	looppkg "github.com/nugget/thane-ai-agent/internal/runtime/loop"
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
+	"github.com/nugget/thane-ai-agent/internal/tools"
+)
+
+func TestReplay_RerunsRecordedExecution(t *testing.T) {
+	t.Parallel()
+
+	archive, err := memory.NewArchiveStore(filepath.Join(t.TempDir(), "archive.db"), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { archive.Close() })
+
+	var captured looppkg.Request
+	runner := &mockLoopRunner{
+		onRun: func(req looppkg.Request) { captured = req },
+		resp: &looppkg.Response{
+			Content:    "garage door is closed",
+			Model:      "cloud/big-model",
+			Iterations: 2,
+		},
+	}
+	exec := NewExecutor(slog.Default(), nil, nil, newTestRegistry(), "cloud/big-model")
+	exec.ConfigureLoopExecution(runner, looppkg.NewRegistry())
+	exec.SetArchiver(archive)
+
+	original, err := exec.Execute(context.Background(), "Is the garage door closed?", "ha", "Be brief", []string{"ha"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if original.SessionID == "" {
+		t.Fatal("SessionID = empty, want the archive session")
+	}
+
+	runner.resp = &looppkg.Response{Content: "closed", Model: "local/small-model", Iterations: 3}
+	replay, err := exec.Replay(context.Background(), memory.ShortID(original.SessionID), "local/small-model")
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	if replay.SessionID != original.SessionID {
+		t.Errorf("SessionID = %q, want the original %q", replay.SessionID, original.SessionID)
+	}
+	if replay.Original.ResultContent != "garage door is closed" || replay.Original.Model != "cloud/big-model" {
+		t.Errorf("Original = %+v, want the first run's record", replay.Original)
+	}
+	if replay.Replay.Content != "closed" || replay.Replay.SessionID == "" || replay.Replay.SessionID == original.SessionID {
+		t.Errorf("Replay = %+v, want the rerun in its own session", replay.Replay)
+	}
+	if captured.Model != "local/small-model" {
+		t.Errorf("replay model = %q, want the override", captured.Model)
+	}
+	if len(captured.AllowedTools) != 1 || captured.AllowedTools[0] != "ha_get_state" {
+		t.Errorf("AllowedTools = %v, want only the tools the original called", captured.AllowedTools)
+	}
+	if captured.PromptMode != agentctx.PromptModeTask || !containsString(captured.InitialTags, "ha") {
+		t.Errorf("prompt mode %q, tags %v; want the original's", captured.PromptMode, captured.InitialTags)
+	}
+
+	sess, err := archive.GetSession(replay.Replay.SessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := sess.Metadata.Delegation; d == nil || d.ReplayOf != original.SessionID || d.Model != "local/small-model" {
+		t.Errorf("replay record = %+v, want it linked to the original", d)
+	}
+}
+
+func TestReplay_NoToolsWhenOriginalCalledNone(t *testing.T) {
+	t.Parallel()
+
+	archive, err := memory.NewArchiveStore(filepath.Join(t.TempDir(), "archive.db"), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { archive.Close() })
+	sess, err := archive.StartSession("delegate-legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.SetSessionDelegation(sess.ID, &memory.DelegationMetadata{Task: "say hello", Profile: "general", Model: "cloud/big-model"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var captured looppkg.Request
+	runner := &mockLoopRunner{
+		onRun: func(req looppkg.Request) { captured = req },
+		resp:  &looppkg.Response{Content: "hello", Model: "cloud/big-model"},
+	}
+	exec := NewExecutor(slog.Default(), nil, nil, newTestRegistry(), "default-model")
+	exec.ConfigureLoopExecution(runner, looppkg.NewRegistry())
+	exec.SetArchiver(archive)
+
+	if _, err := exec.Replay(context.Background(), sess.ID, ""); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if captured.Model != "cloud/big-model" {
+		t.Errorf("model = %q, want the original's with no override", captured.Model)
+	}
+	for _, name := range []string{"ha_get_state", "web_search"} {
+		if !containsString(captured.ExcludeTools, name) {
+			t.Errorf("ExcludeTools = %v, want %s excluded", captured.ExcludeTools, name)
+		}
+	}
+
+	plain, _ := archive.StartSession("conv-1")
+	if _, err := exec.Replay(context.Background(), plain.ID, ""); !errors.Is(err, ErrExecutionNotFound) {
+		t.Errorf("Replay() of a non-delegate session error = %v, want ErrExecutionNotFound", err)
+	}
+}
+
+func TestReplay_ServesRecordedToolResults(t *testing.T) {
+	t.Parallel()
+
+	archive, err := memory.NewArchiveStore(filepath.Join(t.TempDir(), "archive.db"), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { archive.Close() })
+	sess, err := archive.StartSession("delegate-recorded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.SetSessionDelegation(sess.ID, &memory.DelegationMetadata{
+		Task: "open the garage", Profile: "ha", Model: "cloud/big-model", ToolCalls: []string{"ha_call_service"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.ArchiveToolCalls([]memory.ArchivedToolCall{{
+		ID:             "tc-1",
+		ConversationID: "delegate-recorded",
+		SessionID:      sess.ID,
+		ToolName:       "ha_call_service",
+		Arguments:      `{"service":"open_cover","entity_id":"cover.garage"}`,
+		Result:         "cover.garage opened",
+		StartedAt:      time.Now(),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var liveCalls atomic.Int32
+	reg := newTestRegistry()
+	reg.Register(&tools.Tool{
+		Name:        "ha_call_service",
+		Description: "Call a Home Assistant service",
+		Parameters:  map[string]any{"type": "object", "properties": map[string]any{}},
+		Handler: func(context.Context, map[string]any) (string, error) {
+			liveCalls.Add(1)
+			return "live", nil
+		},
+	})
+
+	var captured looppkg.Request
+	runner := &mockLoopRunner{
+		onRun: func(req looppkg.Request) { captured = req },
+		resp:  &looppkg.Response{Content: "opened", Model: "cloud/big-model"},
+	}
+	exec := NewExecutor(slog.Default(), nil, nil, reg, "default-model")
+	exec.ConfigureLoopExecution(runner, looppkg.NewRegistry())
+	exec.SetArchiver(archive)
+
+	if _, err := exec.Replay(context.Background(), sess.ID, ""); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if len(captured.RuntimeTools) != 1 || captured.RuntimeTools[0].Name != "ha_call_service" {
+		t.Fatalf("RuntimeTools = %+v, want a stand-in for ha_call_service", captured.RuntimeTools)
+	}
+	stub := captured.RuntimeTools[0]
+	if stub.Description != "Call a Home Assistant service" {
+		t.Errorf("stub description = %q, want the real tool's", stub.Description)
+	}
+
+	got, err := stub.Handler(context.Background(), map[string]any{"entity_id": "cover.garage", "service": "open_cover"})
+	if err != nil || got != "cover.garage opened" {
+		t.Errorf("recorded call = (%q, %v), want the recorded result", got, err)
+	}
+	if _, err := stub.Handler(context.Background(), map[string]any{"entity_id": "cover.gate", "service": "open_cover"}); err == nil {
+		t.Error("unrecorded call error = nil, want an error")
+	}
+	if n := liveCalls.Load(); n != 0 {
+		t.Errorf("live handler called %d times during replay, want 0", n)
+	}
+}
@@ -1,7 +1,11 @@
 package delegate
 
 import (
+	"encoding/json"
+	"sort"
+
 	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
 )
 
 // ExtractToolsCalled scans a message history and returns a map of tool
@@ -20,3 +24,30 @@ func ExtractToolsCalled(messages []llm.Message) map[string]int {
 	}
 	return counts
 }
+
+// recordedToolNames returns the distinct tools a stored delegate
+// execution called, sorted. Records imported from the legacy
+// delegations table carry no tool list, so their serialized message
+// history is scanned instead.
+func recordedToolNames(d *memory.DelegationMetadata) []string {
+	seen := make(map[string]bool, len(d.ToolCalls))
+	for _, name := range d.ToolCalls {
+		seen[name] = true
+	}
+	if len(d.ToolCalls) == 0 && d.Messages != "" {
+		var msgs []llm.Message
+		if err := json.Unmarshal([]byte(d.Messages), &msgs); err == nil {
+			for name := range ExtractToolsCalled(msgs) {
+				seen[name] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -48,7 +48,8 @@ func AssignToolDefinition() map[string]any {
 }
 
 // commonDelegateProperties returns the JSON schema property block
-// shared by thane_now and thane_assign.
+// shared by thane_now and thane_assign. thane_fanout reuses it without
+// task, which moves into its per-branch tasks array.
 func commonDelegateProperties() map[string]any {
 	return map[string]any{
 		"task": map[string]any{
@@ -118,13 +119,19 @@ type delegateRequest struct {
 
 // parseDelegateArgs extracts the shared args for the family.
 func parseDelegateArgs(args map[string]any) (delegateRequest, string) {
-	req := delegateRequest{inheritCallerTags: true, profileName: "general", contextMode: agentctx.PromptModeTask}
-
 	task, _ := args["task"].(string)
 	if task == "" {
-		return req, "Error: task is required"
+		return delegateRequest{}, "Error: task is required"
 	}
+	req, errMsg := parseDelegateOptions(args)
 	req.task = task
+	return req, errMsg
+}
+
+// parseDelegateOptions extracts the family's shared args other than
+// task: guidance, tags, inherit_caller_tags, and context_mode.
+func parseDelegateOptions(args map[string]any) (delegateRequest, string) {
+	req := delegateRequest{inheritCallerTags: true, profileName: "general", contextMode: agentctx.PromptModeTask}
 
 	req.guidance, _ = args["guidance"].(string)
 	if rawInherit, ok := args["inherit_caller_tags"].(bool); ok {
@@ -176,10 +183,16 @@ func runNow(ctx context.Context, exec *Executor, req delegateRequest) string {
 		promptMode:        req.contextMode,
 	}
 	result, err := exec.execute(ctx, req.task, req.profileName, req.guidance, req.tags, opts)
+	return formatNowResult(req.profileName, result, err)
+}
+
+// formatNowResult renders one synchronous delegate outcome the way
+// thane_now reports it. thane_fanout reuses it for each branch.
+func formatNowResult(requestedProfile string, result *Result, err error) string {
 	if err != nil {
-		return fmt.Sprintf("[Delegate error: profile=%s] %s", req.profileName, err.Error())
+		return fmt.Sprintf("[Delegate error: profile=%s] %s", requestedProfile, err.Error())
 	}
-	profileName := req.profileName
+	profileName := requestedProfile
 	if result.RunPolicyName != "" {
 		profileName = result.RunPolicyName
 	}
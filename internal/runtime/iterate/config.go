@@ -56,6 +56,18 @@ type Config struct {
 	// Executor runs individual tool calls. If nil, the engine panics.
 	Executor ToolExecutor
 
+	// MaxParallelTools bounds how many tool calls from one batch run
+	// concurrently when every call in the batch is read-only (see
+	// ReadOnlyTool). Results are still recorded and appended in the
+	// model's order. Zero or one keeps all execution sequential; the
+	// Executor must be safe for concurrent use when this is above one.
+	MaxParallelTools int
+
+	// ReadOnlyTool reports whether a tool is side-effect-free and may
+	// run concurrently with other read-only calls. Nil treats every
+	// tool as mutating.
+	ReadOnlyTool func(name string) bool
+
 	// --- Callbacks ---
 
 	// OnIterationStart fires at the top of each iteration before the
@@ -142,6 +154,20 @@ type Config struct {
 	// NudgePrompt is the user-role message injected on empty responses.
 	NudgePrompt string
 
+	// ToolErrorReflectAfter enables post-error reflection: after this
+	// many consecutive failed tool calls in one run, the engine injects
+	// ToolErrorReflectPrompt so the model diagnoses the failures before
+	// acting again. A successful call resets the count, as does each
+	// injected reflection. Unavailable-tool calls and loop-break errors
+	// are handled separately and do not count. Zero disables.
+	ToolErrorReflectAfter int
+
+	// ToolErrorReflectPrompt is the user-role message injected for
+	// reflection. Each "%d" in it is replaced with the consecutive error
+	// count; it is not a format string, so any other % passes through
+	// unchanged. Empty uses [prompts.ToolErrorReflection].
+	ToolErrorReflectPrompt string
+
 	// FallbackContent is the static text returned when the model fails
 	// to produce content even after nudging.
 	FallbackContent string
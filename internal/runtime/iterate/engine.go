@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nugget/thane-ai-agent/internal/model/llm"
@@ -43,6 +45,7 @@ func (e *Engine) Run(ctx context.Context, cfg Config, messages []llm.Message) (*
 		totalCacheCreate1h int
 		totalCacheRead     int
 		illegalStrikes     int
+		toolErrorStreak    int
 		emptyRetried       bool
 		deferredText       string
 		breakReason        string
@@ -255,39 +258,44 @@ func (e *Engine) Run(ctx context.Context, cfg Config, messages []llm.Message) (*
 			var batchHasNonMetaTool bool
 			var toolLoopDetected bool
 
+			// Tool calls run one at a time in the order the model
+			// returned them, unless every call in the batch is read-only
+			// and parallel execution is enabled. Either way, callbacks
+			// that record results and the tool-result messages follow
+			// the model's order; replay and golden-file tests depend on
+			// it.
+			calls := make([]*toolCallRun, 0, len(llmResp.Message.ToolCalls))
 			for _, tc := range llmResp.Message.ToolCalls {
+				calls = append(calls, &toolCallRun{tc: tc})
+			}
+			prepare := func(run *toolCallRun) {
+				tc := run.tc
 				toolName := tc.Function.Name
 
 				// Marshal arguments to JSON.
-				argsJSON := ""
 				if tc.Function.Arguments != nil {
 					argsBytes, _ := json.Marshal(tc.Function.Arguments)
-					argsJSON = string(argsBytes)
+					run.argsJSON = string(argsBytes)
 				}
 
-				// Detect tool call loops. Append an error result but
+				// Detect tool call loops. Record an error result but
 				// continue processing remaining calls in the batch so
 				// every tool call has a matching result — the API
 				// requires a 1:1 correspondence.
-				callKey := toolName + ":" + argsJSON
+				callKey := toolName + ":" + run.argsJSON
 				toolCallCounts[callKey]++
 				if toolCallCounts[callKey] > cfg.MaxToolRepeat {
 					iterLog.Warn("tool call loop detected",
 						"tool", toolName,
 						"repeat_count", toolCallCounts[callKey],
 					)
-					messages = append(messages, llm.Message{
-						Role:       "tool",
-						Content:    fmt.Sprintf("Error: tool '%s' has been called %d times with the same arguments. Stop calling tools and provide your response to the user.", toolName, toolCallCounts[callKey]),
-						ToolCallID: tc.ID,
-					})
-					toolLoopDetected = true
-					continue // skip execution; move to next tool in batch
+					run.loopMsg = fmt.Sprintf("Error: tool '%s' has been called %d times with the same arguments. Stop calling tools and provide your response to the user.", toolName, toolCallCounts[callKey])
+					return // skip execution; move to next tool in batch
 				}
 
 				iterLog.Info("tool exec", "tool", toolName)
 				if iterLog.Enabled(iterCtx, slog.LevelDebug) {
-					argPreview := argsJSON
+					argPreview := run.argsJSON
 					if len(argPreview) > 200 {
 						argPreview = argPreview[:200] + "..."
 					}
@@ -300,45 +308,63 @@ func (e *Engine) Run(ctx context.Context, cfg Config, messages []llm.Message) (*
 				}
 
 				// Enrich context before execution.
-				toolCtx := iterCtx
+				run.ctx = iterCtx
 				if cfg.OnBeforeToolExec != nil {
-					toolCtx = cfg.OnBeforeToolExec(iterCtx, i, tc)
+					run.ctx = cfg.OnBeforeToolExec(iterCtx, i, tc)
 				}
 
 				// Record tool call ID. Prefer the internal ID injected
-				// into toolCtx by OnBeforeToolExec (e.g. a UUID stored
-				// by the agent for DB linking), falling back to the
-				// LLM-assigned tc.ID.
-				toolCallRecordID := tc.ID
-				if id := tools.ToolCallIDFromContext(toolCtx); id != "" {
-					toolCallRecordID = id
+				// into the tool context by OnBeforeToolExec (e.g. a UUID
+				// stored by the agent for DB linking), falling back to
+				// the LLM-assigned tc.ID.
+				run.recordID = tc.ID
+				if id := tools.ToolCallIDFromContext(run.ctx); id != "" {
+					run.recordID = id
 				}
 
 				// Check tool availability.
-				var result string
-				var toolErr error
 				if cfg.CheckToolAvail != nil && !cfg.CheckToolAvail(toolName) {
-					toolErr = &tools.ErrToolUnavailable{ToolName: toolName}
+					run.err = &tools.ErrToolUnavailable{ToolName: toolName}
+					run.blocked = true
 					iterLog.Warn("blocked call to unavailable tool", "tool", toolName)
-				} else {
-					result, toolErr = cfg.Executor.Execute(toolCtx, toolName, argsJSON)
 				}
+			}
+			finish := func(run *toolCallRun) {
+				tc := run.tc
+				toolName := tc.Function.Name
+
+				if run.loopMsg != "" {
+					messages = append(messages, llm.Message{
+						Role:       "tool",
+						Content:    run.loopMsg,
+						ToolCallID: tc.ID,
+					})
+					toolLoopDetected = true
+					return
+				}
+
+				result, toolErr := run.result, run.err
 				toolsUsed[toolName]++
-				iterRec.ToolCallIDs = append(iterRec.ToolCallIDs, toolCallRecordID)
+				iterRec.ToolCallIDs = append(iterRec.ToolCallIDs, run.recordID)
 
 				errMsg := ""
 				if toolErr != nil {
-					errMsg = toolErr.Error()
+					// Classified as "[error:<kind>] <message>" so the
+					// model, the archive, and the audit log all see
+					// why the call failed, not just that it did.
+					errMsg = tools.FormatToolError(toolErr)
 					var unavail *tools.ErrToolUnavailable
 					if errors.As(toolErr, &unavail) {
 						illegalCall = true
 						result = fmt.Sprintf(prompts.IllegalToolMessage, toolName)
 						iterLog.Warn("illegal tool call", "tool", toolName)
 					} else {
-						result = "Error: " + errMsg
+						result = errMsg
+						toolErrorStreak++
 						iterLog.Error("tool exec failed", "tool", toolName, "error", toolErr)
 					}
 				} else {
+					toolErrorStreak = 0
 					iterLog.Debug("tool exec done", "tool", toolName, "result_len", len(result))
 					if toolName != "tag_activate" &&
 						toolName != "tag_deactivate" &&
@@ -352,10 +378,11 @@ func (e *Engine) Run(ctx context.Context, cfg Config, messages []llm.Message) (*
 				}
 
 				// --- Callback: tool call done ---
-				// Pass toolCtx so the callback can access values injected
-				// by OnBeforeToolExec (tool_call_id, per-tool deadline, etc.).
+				// Pass the tool context so the callback can access values
+				// injected by OnBeforeToolExec (tool_call_id, per-tool
+				// deadline, etc.).
 				if cfg.OnToolCallDone != nil {
-					cfg.OnToolCallDone(toolCtx, toolName, result, errMsg)
+					cfg.OnToolCallDone(run.ctx, toolName, result, errMsg)
 				}
 
 				// Add tool result message.
@@ -366,10 +393,45 @@ func (e *Engine) Run(ctx context.Context, cfg Config, messages []llm.Message) (*
 				})
 			}
 
+			if workers := parallelWorkers(cfg, calls); workers > 1 {
+				iterLog.Debug("executing read-only tool calls in parallel",
+					"calls", len(calls), "workers", workers)
+				for _, run := range calls {
+					prepare(run)
+				}
+				executeParallel(cfg.Executor, calls, workers)
+				for _, run := range calls {
+					finish(run)
+				}
+			} else {
+				for _, run := range calls {
+					prepare(run)
+					run.execute(cfg.Executor)
+					finish(run)
+				}
+			}
+
 			if toolLoopDetected {
 				iterRec.BreakReason = "tool_loop"
 			}
 
+			// Post-error reflection. Injected after the whole batch so
+			// every tool call already has its matching result.
+			if cfg.ToolErrorReflectAfter > 0 && toolErrorStreak >= cfg.ToolErrorReflectAfter {
+				iterLog.Warn("consecutive tool errors, prompting reflection",
+					"errors", toolErrorStreak)
+				prompt := cfg.ToolErrorReflectPrompt
+				if prompt == "" {
+					prompt = prompts.ToolErrorReflection
+				}
+				prompt = strings.ReplaceAll(prompt, "%d", strconv.Itoa(toolErrorStreak))
+				messages = append(messages, llm.Message{
+					Role:    "user",
+					Content: prompt,
+				})
+				toolErrorStreak = 0
+			}
+
 			// Illegal tool strike counting.
 			if illegalCall {
 				illegalStrikes++
@@ -494,7 +556,7 @@ func (e *Engine) Run(ctx context.Context, cfg Config, messages []llm.Message) (*
 	if breakReason == "" {
 		breakReason = ExhaustMaxIterations
 	}
-	log.Warn("iteration loop ended", "reason", breakReason)
+	log.Warn("iteration loop ended", "reason", breakReason, "max_iterations", cfg.MaxIterations)
 
 	return e.forceText(ctx, cfg, model, messages, &Result{
 		Model:                      model,
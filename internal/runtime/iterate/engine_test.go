@@ -7,6 +7,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/nugget/thane-ai-agent/internal/model/llm"
 	"github.com/nugget/thane-ai-agent/internal/tools"
@@ -862,11 +863,135 @@ func TestEngine_ToolExecError(t *testing.T) {
 	}
 	lastMsgs := mock.calls[1].Messages
 	toolResultMsg := lastMsgs[len(lastMsgs)-1]
-	if !strings.Contains(toolResultMsg.Content, "Error: connection refused") {
+	if !strings.Contains(toolResultMsg.Content, "[error:transient] connection refused") {
 		t.Errorf("tool result = %q, want error content", toolResultMsg.Content)
 	}
 }
 
+// hasReflectionNudge reports whether msgs contains the tool-error
+// reflection prompt.
+func hasReflectionNudge(msgs []llm.Message) bool {
+	for _, m := range msgs {
+		if m.Role == "user" && strings.Contains(m.Content, "stop and reflect") {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEngine_ToolErrorReflectionAfterConsecutiveErrors(t *testing.T) {
+	mock := &mockLLM{
+		responses: []*llm.ChatResponse{
+			toolCallResponse(makeToolCall("flaky", map[string]any{"attempt": 1})),
+			toolCallResponse(makeToolCall("flaky", map[string]any{"attempt": 2})),
+			textResponse("giving up"),
+		},
+	}
+	exec := &mockExecutor{
+		errors: map[string]error{"flaky": errors.New("connection refused")},
+	}
+	cfg := baseCfg(mock, exec)
+	cfg.ToolErrorReflectAfter = 2
+
+	engine := &Engine{}
+	if _, err := engine.Run(context.Background(), cfg, baseMessages()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mock.calls) != 3 {
+		t.Fatalf("LLM calls = %d, want 3", len(mock.calls))
+	}
+	if hasReflectionNudge(mock.calls[1].Messages) {
+		t.Error("reflection injected after 1 error, want it only after 2")
+	}
+	final := mock.calls[2].Messages
+	last := final[len(final)-1]
+	if !hasReflectionNudge([]llm.Message{last}) {
+		t.Fatalf("last message = %+v, want reflection nudge", last)
+	}
+	if !strings.Contains(last.Content, "last 2 tool calls failed") {
+		t.Errorf("nudge = %q, want the error count", last.Content)
+	}
+	if prev := final[len(final)-2]; prev.Role != "tool" {
+		t.Errorf("message before nudge has role %q, want tool result", prev.Role)
+	}
+}
+
+func TestEngine_ToolErrorReflectionPromptIsNotAFormatString(t *testing.T) {
+	mock := &mockLLM{
+		responses: []*llm.ChatResponse{
+			toolCallResponse(makeToolCall("flaky", map[string]any{"attempt": 1})),
+			toolCallResponse(makeToolCall("flaky", map[string]any{"attempt": 2})),
+			textResponse("giving up"),
+		},
+	}
+	exec := &mockExecutor{
+		errors: map[string]error{"flaky": errors.New("connection refused")},
+	}
+	cfg := baseCfg(mock, exec)
+	cfg.ToolErrorReflectAfter = 2
+	cfg.ToolErrorReflectPrompt = "%d calls failed; stop and reflect. Be 100% sure (%s) before retrying."
+
+	engine := &Engine{}
+	if _, err := engine.Run(context.Background(), cfg, baseMessages()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	final := mock.calls[len(mock.calls)-1].Messages
+	want := "2 calls failed; stop and reflect. Be 100% sure (%s) before retrying."
+	if got := final[len(final)-1].Content; got != want {
+		t.Errorf("nudge = %q, want %q", got, want)
+	}
+}
+
+func TestEngine_ToolErrorReflectionResetBySuccess(t *testing.T) {
+	mock := &mockLLM{
+		responses: []*llm.ChatResponse{
+			toolCallResponse(makeToolCall("flaky", nil)),
+			toolCallResponse(makeToolCall("search", nil)),
+			toolCallResponse(makeToolCall("flaky", map[string]any{"retry": true})),
+			textResponse("done"),
+		},
+	}
+	exec := &mockExecutor{
+		errors: map[string]error{"flaky": errors.New("connection refused")},
+	}
+	cfg := baseCfg(mock, exec)
+	cfg.ToolErrorReflectAfter = 2
+
+	engine := &Engine{}
+	if _, err := engine.Run(context.Background(), cfg, baseMessages()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, call := range mock.calls {
+		if hasReflectionNudge(call.Messages) {
+			t.Errorf("LLM call %d saw a reflection nudge; errors were not consecutive", i)
+		}
+	}
+}
+
+func TestEngine_ToolErrorReflectionDisabledByDefault(t *testing.T) {
+	mock := &mockLLM{
+		responses: []*llm.ChatResponse{
+			toolCallResponse(makeToolCall("flaky", map[string]any{"attempt": 1})),
+			toolCallResponse(makeToolCall("flaky", map[string]any{"attempt": 2})),
+			toolCallResponse(makeToolCall("flaky", map[string]any{"attempt": 3})),
+			textResponse("giving up"),
+		},
+	}
+	exec := &mockExecutor{
+		errors: map[string]error{"flaky": errors.New("connection refused")},
+	}
+
+	engine := &Engine{}
+	if _, err := engine.Run(context.Background(), baseCfg(mock, exec), baseMessages()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, call := range mock.calls {
+		if hasReflectionNudge(call.Messages) {
+			t.Errorf("LLM call %d saw a reflection nudge with reflection disabled", i)
+		}
+	}
+}
+
 func TestEngine_CallbacksFired(t *testing.T) {
 	mock := &mockLLM{
 		responses: []*llm.ChatResponse{
@@ -1169,3 +1294,172 @@ func TestLatestUpstreamRequestID(t *testing.T) {
 		})
 	}
 }
+
+// funcExecutor adapts a function to [ToolExecutor].
+type funcExecutor func(ctx context.Context, name, argsJSON string) (string, error)
+
+func (f funcExecutor) Execute(ctx context.Context, name, argsJSON string) (string, error) {
+	return f(ctx, name, argsJSON)
+}
+
+func readOnlyNames(names ...string) func(string) bool {
+	return func(name string) bool {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestEngine_ParallelReadOnlyPreservesOrder(t *testing.T) {
+	mock := &mockLLM{
+		responses: []*llm.ChatResponse{
+			toolCallResponse(
+				makeToolCall("read_a", nil),
+				makeToolCall("read_b", nil),
+				makeToolCall("read_c", nil),
+			),
+			textResponse("done"),
+		},
+	}
+
+	// Every call blocks until all three are running, so the batch only
+	// completes if it executes concurrently. Later calls finish first.
+	var started sync.WaitGroup
+	started.Add(3)
+	allStarted := make(chan struct{})
+	go func() { started.Wait(); close(allStarted) }()
+	delays := map[string]time.Duration{"read_a": 30 * time.Millisecond, "read_b": 15 * time.Millisecond}
+
+	cfg := baseCfg(mock, nil)
+	cfg.Executor = funcExecutor(func(ctx context.Context, name, _ string) (string, error) {
+		started.Done()
+		select {
+		case <-allStarted:
+		case <-time.After(5 * time.Second):
+			return "", fmt.Errorf("%s ran without its batch", name)
+		}
+		time.Sleep(delays[name])
+		return "result " + name, nil
+	})
+	cfg.MaxParallelTools = 4
+	cfg.ReadOnlyTool = readOnlyNames("read_a", "read_b", "read_c")
+	var doneOrder []string
+	cfg.OnToolCallDone = func(_ context.Context, name, _, _ string) {
+		doneOrder = append(doneOrder, name)
+	}
+
+	result, err := (&Engine{}).Run(context.Background(), cfg, baseMessages())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var toolMsgs []llm.Message
+	for _, m := range result.Messages {
+		if m.Role == "tool" {
+			toolMsgs = append(toolMsgs, m)
+		}
+	}
+	want := []string{"read_a", "read_b", "read_c"}
+	if len(toolMsgs) != len(want) {
+		t.Fatalf("tool messages = %d, want %d", len(toolMsgs), len(want))
+	}
+	for i, name := range want {
+		if toolMsgs[i].ToolCallID != "tc_"+name || toolMsgs[i].Content != "result "+name {
+			t.Errorf("tool message %d = %+v, want result for %s", i, toolMsgs[i], name)
+		}
+	}
+	if strings.Join(doneOrder, ",") != strings.Join(want, ",") {
+		t.Errorf("OnToolCallDone order = %v, want %v", doneOrder, want)
+	}
+	if ids := result.Iterations[0].ToolCallIDs; strings.Join(ids, ",") != "tc_read_a,tc_read_b,tc_read_c" {
+		t.Errorf("ToolCallIDs = %v, want model order", ids)
+	}
+}
+
+func TestEngine_ParallelToolPanicFailsOnlyThatCall(t *testing.T) {
+	mock := &mockLLM{
+		responses: []*llm.ChatResponse{
+			toolCallResponse(
+				makeToolCall("read_a", nil),
+				makeToolCall("boom", nil),
+				makeToolCall("read_c", nil),
+			),
+			textResponse("recovered"),
+		},
+	}
+	cfg := baseCfg(mock, nil)
+	cfg.Executor = funcExecutor(func(_ context.Context, name, _ string) (string, error) {
+		if name == "boom" {
+			panic("nil map write")
+		}
+		return "result " + name, nil
+	})
+	cfg.MaxParallelTools = 3
+	cfg.ReadOnlyTool = readOnlyNames("read_a", "boom", "read_c")
+
+	result, err := (&Engine{}).Run(context.Background(), cfg, baseMessages())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content != "recovered" {
+		t.Errorf("content = %q, want the post-tool response", result.Content)
+	}
+
+	got := map[string]string{}
+	for _, m := range result.Messages {
+		if m.Role == "tool" {
+			got[m.ToolCallID] = m.Content
+		}
+	}
+	if got["tc_read_a"] != "result read_a" || got["tc_read_c"] != "result read_c" {
+		t.Errorf("sibling results = %v, want both to succeed", got)
+	}
+	if !strings.Contains(got["tc_boom"], "tool boom panicked: nil map write") {
+		t.Errorf("panicking tool result = %q, want a panic error", got["tc_boom"])
+	}
+}
+
+func TestEngine_MutatingToolKeepsBatchSequential(t *testing.T) {
+	mock := &mockLLM{
+		responses: []*llm.ChatResponse{
+			toolCallResponse(
+				makeToolCall("read_a", nil),
+				makeToolCall("write_b", nil),
+				makeToolCall("read_c", nil),
+			),
+			textResponse("done"),
+		},
+	}
+
+	var mu sync.Mutex
+	var running, maxRunning int
+	var order []string
+	cfg := baseCfg(mock, nil)
+	cfg.Executor = funcExecutor(func(_ context.Context, name, _ string) (string, error) {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		order = append(order, name)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return "ok", nil
+	})
+	cfg.MaxParallelTools = 4
+	cfg.ReadOnlyTool = readOnlyNames("read_a", "read_c")
+
+	if _, err := (&Engine{}).Run(context.Background(), cfg, baseMessages()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if maxRunning != 1 {
+		t.Errorf("max concurrent calls = %d, want 1 with a mutating tool in the batch", maxRunning)
+	}
+	if strings.Join(order, ",") != "read_a,write_b,read_c" {
+		t.Errorf("execution order = %v, want model order", order)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime/debug"
+	"sync"
 
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
 	"github.com/nugget/thane-ai-agent/internal/platform/logging"
 )
 
@@ -61,3 +64,69 @@ func (d *DeadlineExecutor) Execute(ctx context.Context, name, argsJSON string) (
 		return "", fmt.Errorf("tool %s %s: %w", name, reason, err)
 	}
 }
+
+// toolCallRun carries one tool call from a batch through preparation,
+// execution, and result handling.
+type toolCallRun struct {
+	tc       llm.ToolCall
+	argsJSON string
+	ctx      context.Context // tool context from OnBeforeToolExec
+	recordID string
+	loopMsg  string // set when loop detection skipped the call
+	blocked  bool   // set when CheckToolAvail rejected the call
+	result   string
+	err      error
+}
+
+// execute runs the call unless preparation skipped it. A panicking
+// handler is recovered and reported as the call's error, so one broken
+// tool fails its own call rather than the whole run.
+func (run *toolCallRun) execute(exec ToolExecutor) {
+	if run.loopMsg != "" || run.blocked {
+		return
+	}
+	name := run.tc.Function.Name
+	defer func() {
+		if r := recover(); r != nil {
+			logging.Logger(run.ctx).Error("tool handler panicked",
+				"tool", name, "panic", r, "stack", string(debug.Stack()))
+			run.result = ""
+			run.err = fmt.Errorf("tool %s panicked: %v", name, r)
+		}
+	}()
+	run.result, run.err = exec.Execute(run.ctx, name, run.argsJSON)
+}
+
+// parallelWorkers returns how many of calls may execute concurrently:
+// [Config.MaxParallelTools] capped at the batch size when every call
+// is read-only, and 1 otherwise. A batch containing any mutating call
+// runs sequentially so its side effects happen in the model's order.
+func parallelWorkers(cfg Config, calls []*toolCallRun) int {
+	if cfg.MaxParallelTools <= 1 || cfg.ReadOnlyTool == nil || len(calls) < 2 {
+		return 1
+	}
+	for _, run := range calls {
+		if !cfg.ReadOnlyTool(run.tc.Function.Name) {
+			return 1
+		}
+	}
+	return min(cfg.MaxParallelTools, len(calls))
+}
+
+// executeParallel runs the prepared calls on a pool of workers and
+// returns when all have finished. Results stay on each run, so the
+// caller handles them in the original order.
+func executeParallel(exec ToolExecutor, calls []*toolCallRun, workers int) {
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for _, run := range calls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			run.execute(exec)
+		}()
+	}
+	wg.Wait()
+}
@@ -496,6 +496,12 @@ type IterationResult struct {
 	SupervisorTrigger SupervisorTrigger
 	// Sleep is the computed sleep duration before the next iteration.
 	Sleep time.Duration
+	// SleepRequested is the sleep the model asked for during this
+	// iteration, before clamping to the loop's bounds; zero when it
+	// made no request. See [Loop.RecordSleepRequest].
+	SleepRequested time.Duration
+	// SleepReason is the model's stated reason for its sleep request.
+	SleepReason string
 }
 
 // IterationSnapshot is a serializable summary of a completed loop
@@ -558,6 +564,9 @@ type IterationSnapshot struct {
 	// WaitAfter is true when the loop entered WaitFunc after this
 	// iteration instead of sleeping.
 	WaitAfter bool `json:"wait_after,omitempty"`
+	// SleepReason is the model's stated reason for the sleep it
+	// requested during this iteration, if any.
+	SleepReason string `json:"sleep_reason,omitempty"`
 	// Summary holds handler-reported metrics for this iteration,
 	// written by handlers via [IterationSummary] during execution.
 	// Values should be small scalars (int, string, bool).
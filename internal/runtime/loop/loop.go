@@ -69,6 +69,11 @@ type Request struct {
 	// mid-turn input. Runtime-only.
 	PullInput func(ctx context.Context) []llm.Message `yaml:"-" json:"-"`
 
+	// ClientStream marks a run whose stream callback feeds a connected
+	// client (HTTP, SSE, WebSocket). Only such runs emit heartbeat
+	// keepalives during long silences. Runtime-only.
+	ClientStream bool `yaml:"-" json:"-"`
+
 	MaxIterations   int                 `yaml:"max_iterations,omitempty" json:"max_iterations,omitempty"`
 	MaxOutputTokens int                 `yaml:"max_output_tokens,omitempty" json:"max_output_tokens,omitempty"`
 	ToolTimeout     time.Duration       `yaml:"tool_timeout,omitempty" json:"tool_timeout,omitempty"`
@@ -333,6 +338,12 @@ type Loop struct {
 	// tool handler) to override the default sleep for one cycle.
 	nextSleep time.Duration
 
+	// sleepRequested and sleepReason record the sleep the model asked
+	// for this cycle, before clamping, and why. See
+	// [Loop.RecordSleepRequest].
+	sleepRequested time.Duration
+	sleepReason    string
+
 	// consecutiveErrors tracks sequential failures for backoff.
 	consecutiveErrors int
 
@@ -805,6 +816,17 @@ func (l *Loop) SetNextSleep(d time.Duration) {
 	l.mu.Unlock()
 }
 
+// RecordSleepRequest notes the sleep duration the model requested for
+// the next cycle, before any clamping, and its stated reason. Tool
+// handlers call it alongside [Loop.SetNextSleep]; the values are
+// reported on the iteration snapshot and the PostIterate result.
+func (l *Loop) RecordSleepRequest(requested time.Duration, reason string) {
+	l.mu.Lock()
+	l.sleepRequested = requested
+	l.sleepReason = reason
+	l.mu.Unlock()
+}
+
 // SetActiveTagsFunc configures an optional callback that returns the
 // currently active capability tags. When set, [Status] includes the
 // result so the dashboard can display dynamically activated capabilities.
@@ -1451,6 +1473,8 @@ func (l *Loop) run(ctx context.Context) {
 		convID := fmt.Sprintf("loop-%s-%d-%d", l.config.Name, attemptCount+1, time.Now().UnixMilli())
 		l.mu.Lock()
 		l.nextSleep = 0
+		l.sleepRequested = 0
+		l.sleepReason = ""
 		l.currentConvID = convID
 		l.mu.Unlock()
 
@@ -1856,6 +1880,10 @@ func (l *Loop) run(ctx context.Context) {
 			} else {
 				snap.SleepAfterMs = sleep.Milliseconds()
 			}
+			l.mu.Lock()
+			sleepRequested, sleepReason := l.sleepRequested, l.sleepReason
+			l.mu.Unlock()
+			snap.SleepReason = sleepReason
 
 			// Attach handler summary if available.
 			if len(handlerSummary) > 0 {
@@ -1875,16 +1903,19 @@ func (l *Loop) run(ctx context.Context) {
 			// per-iteration logger correlation.
 			if err == nil && l.config.PostIterate != nil {
 				postResult := IterationResult{
-					ConvID:         convID,
-					Model:          result.Model,
-					InputTokens:    result.InputTokens,
-					OutputTokens:   result.OutputTokens,
-					ToolsUsed:      result.ToolsUsed,
-					EffectiveTools: append([]string(nil), result.EffectiveTools...),
-					ActiveTags:     append([]string(nil), result.ActiveTags...),
-					Elapsed:        result.Elapsed,
-					Supervisor:     result.Supervisor,
-					Sleep:          sleep,
+					ConvID:            convID,
+					Model:             result.Model,
+					InputTokens:       result.InputTokens,
+					OutputTokens:      result.OutputTokens,
+					ToolsUsed:         result.ToolsUsed,
+					EffectiveTools:    append([]string(nil), result.EffectiveTools...),
+					ActiveTags:        append([]string(nil), result.ActiveTags...),
+					Elapsed:           result.Elapsed,
+					Supervisor:        result.Supervisor,
+					SupervisorTrigger: result.SupervisorTrigger,
+					Sleep:             sleep,
+					SleepRequested:    sleepRequested,
+					SleepReason:       sleepReason,
 				}
 				if postErr := l.config.PostIterate(iterCtx, postResult); postErr != nil {
 					iterLog.Warn("PostIterate callback failed", "error", postErr)
@@ -1314,6 +1314,72 @@ func TestPostIterate(t *testing.T) {
 	}
 }
 
+// sleepRequestRunner requests a sleep on its loop during the first
+// iteration only, as the set_next_sleep tool does.
+type sleepRequestRunner struct {
+	loop  *Loop
+	calls int
+}
+
+func (r *sleepRequestRunner) Run(ctx context.Context, req RunRequest, stream StreamCallback) (*RunResponse, error) {
+	r.calls++
+	if r.calls == 1 {
+		r.loop.SetNextSleep(2 * time.Millisecond)
+		r.loop.RecordSleepRequest(time.Hour, "nothing to watch")
+	}
+	return (&noopRunner{}).Run(ctx, req, stream)
+}
+
+func TestPostIterateSleepRequest(t *testing.T) {
+	t.Parallel()
+
+	var results []IterationResult
+	var mu sync.Mutex
+
+	runner := &sleepRequestRunner{}
+	l, err := New(Config{
+		Name:         "post-iterate-sleep",
+		Task:         "test",
+		SleepMin:     1 * time.Millisecond,
+		SleepMax:     2 * time.Millisecond,
+		SleepDefault: 1 * time.Millisecond,
+		Jitter:       Float64Ptr(0),
+		MaxIter:      2,
+		PostIterate: func(_ context.Context, result IterationResult) error {
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+			return nil
+		},
+	}, Deps{Runner: runner})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	runner.loop = l
+
+	_ = l.Start(context.Background())
+	<-l.Done()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(results) != 2 {
+		t.Fatalf("PostIterate called %d times, want 2", len(results))
+	}
+	if r := results[0]; r.SleepRequested != time.Hour || r.SleepReason != "nothing to watch" {
+		t.Errorf("first result sleep request = %v %q, want 1h with reason", r.SleepRequested, r.SleepReason)
+	}
+	if r := results[1]; r.SleepRequested != 0 || r.SleepReason != "" {
+		t.Errorf("second result sleep request = %v %q, want none (reset per iteration)", r.SleepRequested, r.SleepReason)
+	}
+
+	// RecentIterations is newest first.
+	iters := l.Status().RecentIterations
+	if len(iters) != 2 || iters[1].SleepReason != "nothing to watch" || iters[0].SleepReason != "" {
+		t.Errorf("snapshot sleep reasons = %+v, want reason on the first iteration only", iters)
+	}
+}
+
 func TestPostIterateError(t *testing.T) {
 	t.Parallel()
 
@@ -2,6 +2,7 @@ package loop
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -119,6 +120,156 @@ type EntitySubscription struct {
 	// slower ask here bounds only how fast THIS subscription's
 	// changes demand a wake.
 	WakeDebounceSeconds int `yaml:"wake_debounce_seconds,omitempty" json:"wake_debounce_seconds,omitempty"`
+
+	// WakeWhen turns the wake feed into an alert: instead of waking
+	// on every change, the loop wakes only when a change takes the
+	// entity into the condition (it was not true before and is true
+	// now). Requires Wake. Nil keeps the wake-on-any-change default.
+	WakeWhen *WakeCondition `yaml:"wake_when,omitempty" json:"wake_when,omitempty"`
+
+	// WakeCooldownSeconds is the minimum time between two WakeWhen
+	// alerts for the same entity, so a value flapping across a
+	// threshold alerts once rather than on every crossing. Zero uses
+	// [DefaultWakeCooldown]. Only meaningful with WakeWhen.
+	WakeCooldownSeconds int `yaml:"wake_cooldown_seconds,omitempty" json:"wake_cooldown_seconds,omitempty"`
+}
+
+// DefaultWakeCooldown is the alert cooldown for WakeWhen subscriptions
+// that don't set [EntitySubscription.WakeCooldownSeconds].
+const DefaultWakeCooldown = 10 * time.Minute
+
+// WakeCondition is the condition of an alerting wake subscription
+// ([EntitySubscription.WakeWhen]). Set either State, or one or both
+// of Above and Below; From narrows a State condition to one specific
+// transition, or on its own alerts when the entity leaves a state.
+type WakeCondition struct {
+	// State matches when the entity's state equals this value,
+	// compared case-insensitively against both the raw Home Assistant
+	// state ("on") and its class-aware rendering ("open").
+	State string `yaml:"state,omitempty" json:"state,omitempty"`
+
+	// From requires the state before the change to equal this value,
+	// compared like State. With State it describes one transition
+	// ("off" → "on" only, not "unavailable" → "on"); alone it fires
+	// when the entity leaves From for anything else. Not combinable
+	// with Above/Below.
+	From string `yaml:"from,omitempty" json:"from,omitempty"`
+
+	// Above matches numeric states strictly greater than this value.
+	Above *float64 `yaml:"above,omitempty" json:"above,omitempty"`
+
+	// Below matches numeric states strictly less than this value.
+	// Combined with Above, the condition is the band between them.
+	Below *float64 `yaml:"below,omitempty" json:"below,omitempty"`
+}
+
+// Validate reports whether the condition is well formed.
+func (c WakeCondition) Validate() error {
+	state := strings.TrimSpace(c.State)
+	from := strings.TrimSpace(c.From)
+	switch {
+	case state == "" && from == "" && c.Above == nil && c.Below == nil:
+		return fmt.Errorf("wake_when needs state, from, above, or below")
+	case state != "" && (c.Above != nil || c.Below != nil):
+		return fmt.Errorf("wake_when takes either state or above/below, not both")
+	case from != "" && (c.Above != nil || c.Below != nil):
+		return fmt.Errorf("wake_when from describes a state transition and cannot be combined with above/below")
+	case from != "" && strings.EqualFold(from, state):
+		return fmt.Errorf("wake_when from and state are both %q — a change never goes from a state to itself", state)
+	case c.Above != nil && c.Below != nil && *c.Above >= *c.Below:
+		return fmt.Errorf("wake_when above (%g) must be less than below (%g) — together they describe the band between them", *c.Above, *c.Below)
+	}
+	return nil
+}
+
+// Matches reports whether any of the given spellings of one state
+// (raw and class-aware) satisfies the condition's State or thresholds.
+// Non-numeric states such as "unavailable" never satisfy a threshold.
+// From is not consulted; see [WakeCondition.Fires].
+func (c WakeCondition) Matches(states ...string) bool {
+	if want := strings.TrimSpace(c.State); want != "" {
+		return stateIn(want, states)
+	}
+	if c.Above == nil && c.Below == nil {
+		return false
+	}
+	for _, st := range states {
+		v, err := strconv.ParseFloat(strings.TrimSpace(st), 64)
+		if err != nil {
+			continue
+		}
+		if (c.Above == nil || v > *c.Above) && (c.Below == nil || v < *c.Below) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fires reports whether a change from a state spelled before (raw and
+// class-aware) to one spelled after trips the condition. Without From
+// the change must enter the condition: false before, true after. With
+// From the previous state must be From, and the new one must match
+// State (or, with no State, merely differ from From).
+func (c WakeCondition) Fires(before, after []string) bool {
+	from := strings.TrimSpace(c.From)
+	if from == "" {
+		return c.Matches(after...) && !c.Matches(before...)
+	}
+	if !stateIn(from, before) {
+		return false
+	}
+	if strings.TrimSpace(c.State) == "" {
+		return !stateIn(from, after)
+	}
+	return c.Matches(after...)
+}
+
+// stateIn reports whether any spelling equals want, ignoring case and
+// surrounding space.
+func stateIn(want string, states []string) bool {
+	for _, st := range states {
+		if strings.EqualFold(strings.TrimSpace(st), want) {
+			return true
+		}
+	}
+	return false
+}
+
+// String describes the condition for wake payloads and tool replies.
+func (c WakeCondition) String() string {
+	from := strings.TrimSpace(c.From)
+	switch {
+	case from != "" && strings.TrimSpace(c.State) != "":
+		return "state changes from " + from + " to " + strings.TrimSpace(c.State)
+	case from != "":
+		return "state leaves " + from
+	case strings.TrimSpace(c.State) != "":
+		return "state is " + strings.TrimSpace(c.State)
+	case c.Above != nil && c.Below != nil:
+		return fmt.Sprintf("between %g and %g", *c.Above, *c.Below)
+	case c.Above != nil:
+		return fmt.Sprintf("above %g", *c.Above)
+	case c.Below != nil:
+		return fmt.Sprintf("below %g", *c.Below)
+	}
+	return ""
+}
+
+// Clone returns a deep copy of the condition. Nil stays nil.
+func (c *WakeCondition) Clone() *WakeCondition {
+	if c == nil {
+		return nil
+	}
+	out := WakeCondition{State: c.State, From: c.From}
+	if c.Above != nil {
+		above := *c.Above
+		out.Above = &above
+	}
+	if c.Below != nil {
+		below := *c.Below
+		out.Below = &below
+	}
+	return &out
 }
 
 // IsExpired reports whether this subscription's TTL has elapsed
@@ -144,8 +295,8 @@ func cloneEntitySubscriptions(src []EntitySubscription) []EntitySubscription {
 	return out
 }
 
-// Clone returns a deep copy of the subscription (History and Include
-// are the only reference-typed fields).
+// Clone returns a deep copy of the subscription (History, Include,
+// and WakeWhen are the only reference-typed fields).
 func (s EntitySubscription) Clone() EntitySubscription {
 	out := s
 	if len(s.History) > 0 {
@@ -155,6 +306,7 @@ func (s EntitySubscription) Clone() EntitySubscription {
 		include := *s.Include
 		out.Include = &include
 	}
+	out.WakeWhen = s.WakeWhen.Clone()
 	return out
 }
 
@@ -218,6 +370,25 @@ func (s EntitySubscription) WantsTransitions() bool {
 	return s.Transitions > 0 || s.TransitionsWindowSeconds > 0
 }
 
+// ValidateWakeCondition checks the alerting wake options: WakeWhen
+// must be well formed and ride a wake subscription, and a cooldown
+// needs a WakeWhen to pace.
+func (s EntitySubscription) ValidateWakeCondition() error {
+	if s.WakeCooldownSeconds < 0 {
+		return fmt.Errorf("wake_cooldown_seconds must be >= 0, got %d", s.WakeCooldownSeconds)
+	}
+	if s.WakeWhen == nil {
+		if s.WakeCooldownSeconds > 0 {
+			return fmt.Errorf("wake_cooldown_seconds paces wake_when alerts — set wake_when, or drop the cooldown")
+		}
+		return nil
+	}
+	if !s.Wake {
+		return fmt.Errorf("wake_when narrows the wake feed — set wake as well")
+	}
+	return s.WakeWhen.Validate()
+}
+
 // RendersState reports whether this subscription renders live state
 // into context each turn (mode render — stored as "" — or both).
 func (s EntitySubscription) RendersState() bool {
@@ -304,6 +475,9 @@ func normalizeSubscriptionsOnLoad(subs []EntitySubscription, now time.Time) ([]E
 		if sub.WakeDebounceSeconds < 0 {
 			return nil, fmt.Errorf("subscriptions[%d] (entity_id=%q): wake_debounce_seconds must be >= 0, got %d", i, sub.EntityID, sub.WakeDebounceSeconds)
 		}
+		if err := sub.ValidateWakeCondition(); err != nil {
+			return nil, fmt.Errorf("subscriptions[%d] (entity_id=%q): %w", i, sub.EntityID, err)
+		}
 		// The wake feed must not follow tag state (#1213's boundary
 		// extended to #1211: capture-adjacent behavior stays
 		// unconditional) and cannot ride registry targets (their
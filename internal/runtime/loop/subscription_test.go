@@ -513,3 +513,102 @@ func TestNormalizeSubscriptionsOnLoadWakeCombos(t *testing.T) {
 		t.Errorf("wake declaration rejected: %v", err)
 	}
 }
+
+func TestWakeConditionMatches(t *testing.T) {
+	t.Parallel()
+
+	above, below := 30.0, 18.0
+	low, high := 18.0, 24.0
+	tests := []struct {
+		name   string
+		cond   WakeCondition
+		states []string
+		want   bool
+	}{
+		{name: "state raw", cond: WakeCondition{State: "on"}, states: []string{"on", "open"}, want: true},
+		{name: "state rendered", cond: WakeCondition{State: "Open"}, states: []string{"on", "open"}, want: true},
+		{name: "state miss", cond: WakeCondition{State: "open"}, states: []string{"off", "closed"}},
+		{name: "above", cond: WakeCondition{Above: &above}, states: []string{"30.5"}, want: true},
+		{name: "above boundary", cond: WakeCondition{Above: &above}, states: []string{"30"}},
+		{name: "below", cond: WakeCondition{Below: &below}, states: []string{"17.9"}, want: true},
+		{name: "band inside", cond: WakeCondition{Above: &low, Below: &high}, states: []string{"21"}, want: true},
+		{name: "band outside", cond: WakeCondition{Above: &low, Below: &high}, states: []string{"25"}},
+		{name: "threshold non-numeric", cond: WakeCondition{Above: &above}, states: []string{"unavailable"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cond.Matches(tt.states...); got != tt.want {
+				t.Errorf("Matches(%v) = %v, want %v", tt.states, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWakeConditionFires(t *testing.T) {
+	t.Parallel()
+
+	limit := 30.0
+	tests := []struct {
+		name          string
+		cond          WakeCondition
+		before, after []string
+		want          bool
+	}{
+		{name: "threshold entered", cond: WakeCondition{Above: &limit}, before: []string{"25"}, after: []string{"31"}, want: true},
+		{name: "threshold already true", cond: WakeCondition{Above: &limit}, before: []string{"31"}, after: []string{"33"}},
+		{name: "state entered from anywhere", cond: WakeCondition{State: "on"}, before: []string{"unavailable"}, after: []string{"on"}, want: true},
+		{name: "transition", cond: WakeCondition{From: "off", State: "on"}, before: []string{"off", "closed"}, after: []string{"on", "open"}, want: true},
+		{name: "transition rendered", cond: WakeCondition{From: "Closed", State: "open"}, before: []string{"off", "closed"}, after: []string{"on", "open"}, want: true},
+		{name: "transition wrong origin", cond: WakeCondition{From: "off", State: "on"}, before: []string{"unavailable"}, after: []string{"on"}},
+		{name: "transition wrong target", cond: WakeCondition{From: "off", State: "on"}, before: []string{"off"}, after: []string{"unavailable"}},
+		{name: "leaves", cond: WakeCondition{From: "home"}, before: []string{"home"}, after: []string{"not_home"}, want: true},
+		{name: "leaves wrong origin", cond: WakeCondition{From: "home"}, before: []string{"work"}, after: []string{"not_home"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cond.Fires(tt.before, tt.after); got != tt.want {
+				t.Errorf("Fires(%v, %v) = %v, want %v", tt.before, tt.after, got, tt.want)
+			}
+		})
+	}
+
+	if got := (WakeCondition{From: "off", State: "on"}).String(); got != "state changes from off to on" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestNormalizeSubscriptionsOnLoadWakeWhen(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 7, 5, 12, 0, 0, 0, time.UTC)
+	limit, lower := 30.0, 40.0
+	rejected := []EntitySubscription{
+		{EntityID: "sensor.a", WakeWhen: &WakeCondition{Above: &limit}},
+		{EntityID: "sensor.a", Wake: true, WakeWhen: &WakeCondition{}},
+		{EntityID: "sensor.a", Wake: true, WakeWhen: &WakeCondition{State: "on", Above: &limit}},
+		{EntityID: "sensor.a", Wake: true, WakeWhen: &WakeCondition{Above: &lower, Below: &limit}},
+		{EntityID: "sensor.a", Wake: true, WakeWhen: &WakeCondition{From: "off", Above: &limit}},
+		{EntityID: "sensor.a", Wake: true, WakeWhen: &WakeCondition{From: "on", State: "ON"}},
+		{EntityID: "sensor.a", Wake: true, WakeCooldownSeconds: 60},
+		{EntityID: "sensor.a", Wake: true, WakeWhen: &WakeCondition{State: "on"}, WakeCooldownSeconds: -1},
+	}
+	for _, sub := range rejected {
+		if _, err := normalizeSubscriptionsOnLoad([]EntitySubscription{sub}, now); err == nil {
+			t.Errorf("%+v survived hydration", sub)
+		}
+	}
+	if _, err := normalizeSubscriptionsOnLoad([]EntitySubscription{
+		{EntityID: "sensor.garage_temp", Wake: true, WakeWhen: &WakeCondition{Above: &limit}, WakeCooldownSeconds: 900},
+		{EntityID: "switch.pump", Wake: true, WakeWhen: &WakeCondition{From: "off", State: "on"}},
+		{EntityID: "person.dan", Wake: true, WakeWhen: &WakeCondition{From: "home"}},
+	}, now); err != nil {
+		t.Errorf("wake_when declaration rejected: %v", err)
+	}
+
+	sub := EntitySubscription{EntityID: "sensor.a", Wake: true, WakeWhen: &WakeCondition{Above: &limit}}
+	clone := sub.Clone()
+	*clone.WakeWhen.Above = 99
+	if *sub.WakeWhen.Above != 30 {
+		t.Error("Clone shares WakeWhen with the original")
+	}
+}
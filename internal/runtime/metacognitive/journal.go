@@ -0,0 +1,218 @@
+package metacognitive
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/loop"
+)
+
+// DefaultJournalSize is the number of iterations a [Journal] keeps.
+// At the minimum sleep this spans several hours; at typical quiet-period
+// sleeps it spans days.
+const DefaultJournalSize = 200
+
+// JournalEntry records one completed metacognitive iteration. Durations
+// are in milliseconds on the wire, matching the loop iteration
+// snapshots.
+type JournalEntry struct {
+	// At is when the iteration completed.
+	At time.Time `json:"at"`
+
+	ConversationID    string `json:"conversation_id,omitempty"`
+	Model             string `json:"model,omitempty"`
+	Supervisor        bool   `json:"supervisor"`
+	SupervisorTrigger string `json:"supervisor_trigger,omitempty"`
+
+	// Reasoning is the reason the model gave with its sleep request.
+	Reasoning string `json:"reasoning,omitempty"`
+
+	// SleepRequestedMs is the sleep the model asked for, before
+	// clamping; zero when it made no request and the default applied.
+	SleepRequestedMs int64 `json:"sleep_requested_ms,omitempty"`
+
+	// SleepMs is the sleep actually scheduled after clamping and
+	// jitter.
+	SleepMs int64 `json:"sleep_ms"`
+
+	// Clamped is "min" or "max" when the requested sleep fell outside
+	// the loop's sleep bounds and was raised or lowered to fit.
+	Clamped string `json:"clamped,omitempty"`
+
+	ElapsedMs    int64          `json:"elapsed_ms"`
+	InputTokens  int            `json:"input_tokens,omitempty"`
+	OutputTokens int            `json:"output_tokens,omitempty"`
+	ToolsUsed    map[string]int `json:"tools_used,omitempty"`
+}
+
+// JournalStats summarizes the entries of a [JournalReport], for
+// comparing the loop's observed cadence with its configured bounds.
+type JournalStats struct {
+	Iterations int `json:"iterations"`
+	Supervisor int `json:"supervisor"`
+
+	// NoSleepRequest counts iterations where the model did not call
+	// set_next_sleep and the default sleep applied.
+	NoSleepRequest int `json:"no_sleep_request"`
+
+	// ClampedMin and ClampedMax count sleep requests below MinSleep
+	// and above MaxSleep.
+	ClampedMin int `json:"clamped_min"`
+	ClampedMax int `json:"clamped_max"`
+
+	MinSleepMs    int64 `json:"min_sleep_ms"`
+	MedianSleepMs int64 `json:"median_sleep_ms"`
+	MaxSleepMs    int64 `json:"max_sleep_ms"`
+}
+
+// JournalReport is the recent history returned by [Journal.Report],
+// newest first.
+type JournalReport struct {
+	Entries []JournalEntry `json:"entries"`
+	Stats   JournalStats   `json:"stats"`
+
+	// Capacity is the most entries the journal keeps.
+	Capacity int `json:"capacity"`
+
+	// MinSleepMs and MaxSleepMs are the loop's configured sleep bounds.
+	MinSleepMs int64 `json:"min_sleep_ms"`
+	MaxSleepMs int64 `json:"max_sleep_ms"`
+}
+
+// Journal is an in-memory ring buffer of recent metacognitive
+// iterations, so the loop's attention history can be reviewed without
+// reading logs. It does not survive a restart. All methods are safe
+// for concurrent use, and a nil *Journal records nothing.
+type Journal struct {
+	mu       sync.Mutex
+	entries  []JournalEntry // ring; next is the slot to overwrite
+	next     int
+	full     bool
+	minSleep time.Duration
+	maxSleep time.Duration
+}
+
+// NewJournal creates a journal holding up to capacity entries
+// (DefaultJournalSize when capacity is not positive).
+func NewJournal(capacity int) *Journal {
+	if capacity <= 0 {
+		capacity = DefaultJournalSize
+	}
+	return &Journal{entries: make([]JournalEntry, capacity)}
+}
+
+// setBounds records the loop's configured sleep bounds, reported with
+// every [JournalReport].
+func (j *Journal) setBounds(minSleep, maxSleep time.Duration) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	j.minSleep, j.maxSleep = minSleep, maxSleep
+	j.mu.Unlock()
+}
+
+// record adds the iteration described by result, completed at now. The
+// sleep bounds are those of the loop spec that ran it.
+func (j *Journal) record(result *loop.IterationResult, minSleep, maxSleep time.Duration, now time.Time) {
+	if j == nil {
+		return
+	}
+	entry := JournalEntry{
+		At:                now,
+		ConversationID:    result.ConvID,
+		Model:             result.Model,
+		Supervisor:        result.Supervisor,
+		SupervisorTrigger: string(result.SupervisorTrigger),
+		Reasoning:         result.SleepReason,
+		SleepRequestedMs:  result.SleepRequested.Milliseconds(),
+		SleepMs:           result.Sleep.Milliseconds(),
+		ElapsedMs:         result.Elapsed.Milliseconds(),
+		InputTokens:       result.InputTokens,
+		OutputTokens:      result.OutputTokens,
+	}
+	switch {
+	case result.SleepRequested <= 0:
+	case minSleep > 0 && result.SleepRequested < minSleep:
+		entry.Clamped = "min"
+	case maxSleep > 0 && result.SleepRequested > maxSleep:
+		entry.Clamped = "max"
+	}
+	if len(result.ToolsUsed) > 0 {
+		entry.ToolsUsed = make(map[string]int, len(result.ToolsUsed))
+		for name, n := range result.ToolsUsed {
+			entry.ToolsUsed[name] = n
+		}
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries[j.next] = entry
+	j.next = (j.next + 1) % len(j.entries)
+	if j.next == 0 {
+		j.full = true
+	}
+	j.minSleep, j.maxSleep = minSleep, maxSleep
+}
+
+// Report returns up to limit of the most recent entries, newest first,
+// with stats over those entries. A limit that is not positive returns
+// every entry.
+func (j *Journal) Report(limit int) JournalReport {
+	if j == nil {
+		return JournalReport{Entries: []JournalEntry{}}
+	}
+	j.mu.Lock()
+	count := j.next
+	if j.full {
+		count = len(j.entries)
+	}
+	if limit <= 0 || limit > count {
+		limit = count
+	}
+	entries := make([]JournalEntry, 0, limit)
+	for i := 1; i <= limit; i++ {
+		idx := (j.next - i + len(j.entries)) % len(j.entries)
+		entries = append(entries, j.entries[idx])
+	}
+	report := JournalReport{
+		Entries:    entries,
+		Capacity:   len(j.entries),
+		MinSleepMs: j.minSleep.Milliseconds(),
+		MaxSleepMs: j.maxSleep.Milliseconds(),
+	}
+	j.mu.Unlock()
+
+	report.Stats = journalStats(entries)
+	return report
+}
+
+// journalStats summarizes entries.
+func journalStats(entries []JournalEntry) JournalStats {
+	stats := JournalStats{Iterations: len(entries)}
+	if len(entries) == 0 {
+		return stats
+	}
+	sleeps := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		if e.Supervisor {
+			stats.Supervisor++
+		}
+		if e.SleepRequestedMs == 0 {
+			stats.NoSleepRequest++
+		}
+		switch e.Clamped {
+		case "min":
+			stats.ClampedMin++
+		case "max":
+			stats.ClampedMax++
+		}
+		sleeps = append(sleeps, e.SleepMs)
+	}
+	slices.Sort(sleeps)
+	stats.MinSleepMs = sleeps[0]
+	stats.MedianSleepMs = sleeps[len(sleeps)/2]
+	stats.MaxSleepMs = sleeps[len(sleeps)-1]
+	return stats
+}
@@ -0,0 +1,177 @@
+package metacognitive
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/loop"
+)
+
+func TestJournal_RingNewestFirst(t *testing.T) {
+	j := NewJournal(3)
+	base := time.Date(2026, 6, 24, 14, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		j.record(&loop.IterationResult{
+			ConvID: string(rune('a' + i)),
+			Sleep:  time.Duration(i+1) * time.Minute,
+		}, 2*time.Minute, 30*time.Minute, base.Add(time.Duration(i)*time.Minute))
+	}
+
+	report := j.Report(0)
+	if report.Capacity != 3 {
+		t.Errorf("Capacity = %d, want 3", report.Capacity)
+	}
+	var got []string
+	for _, e := range report.Entries {
+		got = append(got, e.ConversationID)
+	}
+	if want := []string{"e", "d", "c"}; !slices.Equal(got, want) {
+		t.Errorf("entries = %v, want %v", got, want)
+	}
+
+	if limited := j.Report(2); len(limited.Entries) != 2 || limited.Entries[0].ConversationID != "e" {
+		t.Errorf("Report(2) = %+v, want the two newest", limited.Entries)
+	}
+	if over := j.Report(10); len(over.Entries) != 3 {
+		t.Errorf("Report(10) returned %d entries, want 3", len(over.Entries))
+	}
+}
+
+func TestJournal_ClampingAndStats(t *testing.T) {
+	j := NewJournal(10)
+	now := time.Now()
+	minSleep, maxSleep := 2*time.Minute, 30*time.Minute
+	for _, r := range []loop.IterationResult{
+		{Sleep: 10 * time.Minute},
+		{Sleep: 2 * time.Minute, SleepRequested: 30 * time.Second, SleepReason: "doorbell activity"},
+		{Sleep: 30 * time.Minute, SleepRequested: 2 * time.Hour, SleepReason: "everyone asleep", Supervisor: true, SupervisorTrigger: loop.SupervisorTriggerRandom},
+		{Sleep: 15 * time.Minute, SleepRequested: 15 * time.Minute},
+	} {
+		j.record(&r, minSleep, maxSleep, now)
+	}
+
+	report := j.Report(0)
+	if report.MinSleepMs != minSleep.Milliseconds() || report.MaxSleepMs != maxSleep.Milliseconds() {
+		t.Errorf("bounds = %d/%d, want %d/%d", report.MinSleepMs, report.MaxSleepMs, minSleep.Milliseconds(), maxSleep.Milliseconds())
+	}
+	clamped := map[string]string{}
+	for _, e := range report.Entries {
+		clamped[e.Reasoning] = e.Clamped
+	}
+	if clamped["doorbell activity"] != "min" || clamped["everyone asleep"] != "max" {
+		t.Errorf("clamped by reason = %v, want min and max", clamped)
+	}
+	if report.Entries[1].SupervisorTrigger != string(loop.SupervisorTriggerRandom) {
+		t.Errorf("SupervisorTrigger = %q, want %q", report.Entries[1].SupervisorTrigger, loop.SupervisorTriggerRandom)
+	}
+
+	want := JournalStats{
+		Iterations:     4,
+		Supervisor:     1,
+		NoSleepRequest: 1,
+		ClampedMin:     1,
+		ClampedMax:     1,
+		MinSleepMs:     (2 * time.Minute).Milliseconds(),
+		MedianSleepMs:  (15 * time.Minute).Milliseconds(),
+		MaxSleepMs:     (30 * time.Minute).Milliseconds(),
+	}
+	if report.Stats != want {
+		t.Errorf("Stats = %+v, want %+v", report.Stats, want)
+	}
+}
+
+func TestJournal_Nil(t *testing.T) {
+	var j *Journal
+	j.setBounds(time.Minute, time.Hour)
+	j.record(&loop.IterationResult{}, 0, 0, time.Now())
+	report := j.Report(5)
+	if report.Entries == nil || len(report.Entries) != 0 {
+		t.Errorf("nil journal Entries = %#v, want empty", report.Entries)
+	}
+}
+
+func TestHydratedConfig_PostIterateRecordsJournal(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := testConfig()
+	journal := NewJournal(DefaultJournalSize)
+	spec := HydrateSpec(DefinitionSpec(cfg), cfg, Opts{
+		WorkspacePath: tmpDir,
+		StateFilePath: filepath.Join(tmpDir, cfg.StateFile),
+		Journal:       journal,
+	})
+
+	if report := journal.Report(0); report.MaxSleepMs != cfg.MaxSleep.Milliseconds() {
+		t.Errorf("bounds before first iteration: max = %d, want %d", report.MaxSleepMs, cfg.MaxSleep.Milliseconds())
+	}
+
+	err := spec.ToConfig().PostIterate(context.Background(), loop.IterationResult{
+		ConvID:         "metacog-journal",
+		Sleep:          30 * time.Minute,
+		SleepRequested: time.Hour,
+		SleepReason:    "house is quiet",
+	})
+	if err != nil {
+		t.Fatalf("PostIterate: %v", err)
+	}
+
+	entries := journal.Report(0).Entries
+	if len(entries) != 1 {
+		t.Fatalf("journal entries = %d, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.ConversationID != "metacog-journal" || e.Reasoning != "house is quiet" || e.Clamped != "max" {
+		t.Errorf("entry = %+v, want the iteration with its reason, clamped to max", e)
+	}
+}
+
+func TestJournalToolHandler(t *testing.T) {
+	j := NewJournal(DefaultJournalSize)
+	j.setBounds(2*time.Minute, 30*time.Minute)
+	for range 25 {
+		j.record(&loop.IterationResult{
+			Sleep:          30 * time.Minute,
+			SleepRequested: 45 * time.Minute,
+			SleepReason:    "nothing changing",
+			Elapsed:        12 * time.Second,
+		}, 2*time.Minute, 30*time.Minute, time.Now().Add(-time.Minute))
+	}
+
+	out, err := JournalToolHandler(j)(context.Background(), map[string]any{})
+	if err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	var got struct {
+		Entries []journalToolEntry `json:"entries"`
+		Summary map[string]any     `json:"summary"`
+		Config  map[string]any     `json:"config"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal %s: %v", out, err)
+	}
+	if len(got.Entries) != defaultJournalToolLimit {
+		t.Errorf("entries = %d, want default limit %d", len(got.Entries), defaultJournalToolLimit)
+	}
+	e := got.Entries[0]
+	if e.At != "-60s" || e.Sleep != "30m0s" || e.SleepRequested != "45m0s" || e.Clamped != "max" || e.Reasoning != "nothing changing" {
+		t.Errorf("entry = %+v", e)
+	}
+	if got.Summary["clamped_max"] != float64(defaultJournalToolLimit) || got.Config["max_sleep"] != "30m0s" {
+		t.Errorf("summary = %v, config = %v", got.Summary, got.Config)
+	}
+
+	out, err = JournalToolHandler(j)(context.Background(), map[string]any{"limit": float64(5)})
+	if err != nil {
+		t.Fatalf("handler(limit=5): %v", err)
+	}
+	got.Entries = nil
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(got.Entries) != 5 {
+		t.Errorf("entries with limit 5 = %d", len(got.Entries))
+	}
+}
@@ -0,0 +1,104 @@
+package metacognitive
+
+import (
+	"context"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/promptfmt"
+)
+
+// Journal tool limits.
+const (
+	defaultJournalToolLimit = 20
+	maxJournalToolLimit     = DefaultJournalSize
+)
+
+// JournalToolDescription is the LLM-facing description for
+// metacognitive_history.
+const JournalToolDescription = "Review the metacognitive loop's recent iterations, newest first: when each ran, the reasoning it gave for its sleep, the sleep it requested and the sleep actually scheduled, whether a supervisor turn fired, and the tools it used. " +
+	"Includes a summary of the observed cadence against the configured min/max sleep, counting requests that were clamped. " +
+	"History is kept in memory and starts empty after a restart."
+
+// JournalToolDefinition returns the JSON schema for
+// metacognitive_history.
+func JournalToolDefinition() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"limit": map[string]any{
+				"type":        "integer",
+				"description": "Maximum iterations to return (default 20, max 200).",
+			},
+		},
+	}
+}
+
+// journalToolEntry is the model-facing form of a [JournalEntry]:
+// times as deltas and durations as Go duration strings.
+type journalToolEntry struct {
+	At                string         `json:"at"`
+	Supervisor        bool           `json:"supervisor,omitempty"`
+	SupervisorTrigger string         `json:"supervisor_trigger,omitempty"`
+	Reasoning         string         `json:"reasoning,omitempty"`
+	SleepRequested    string         `json:"sleep_requested,omitempty"`
+	Sleep             string         `json:"sleep"`
+	Clamped           string         `json:"clamped,omitempty"`
+	Model             string         `json:"model,omitempty"`
+	Elapsed           string         `json:"elapsed"`
+	ToolsUsed         map[string]int `json:"tools_used,omitempty"`
+}
+
+// JournalToolHandler returns the handler for metacognitive_history.
+func JournalToolHandler(j *Journal) func(ctx context.Context, args map[string]any) (string, error) {
+	return func(_ context.Context, args map[string]any) (string, error) {
+		limit := defaultJournalToolLimit
+		if v, ok := args["limit"].(float64); ok && v > 0 {
+			limit = min(int(v), maxJournalToolLimit)
+		}
+		return formatJournalReport(j.Report(limit), time.Now()), nil
+	}
+}
+
+// formatJournalReport renders a report for the model.
+func formatJournalReport(report JournalReport, now time.Time) string {
+	ms := func(v int64) string {
+		return (time.Duration(v) * time.Millisecond).Round(time.Second).String()
+	}
+	entries := make([]journalToolEntry, len(report.Entries))
+	for i, e := range report.Entries {
+		entries[i] = journalToolEntry{
+			At:                promptfmt.FormatDeltaOnly(e.At, now),
+			Supervisor:        e.Supervisor,
+			SupervisorTrigger: e.SupervisorTrigger,
+			Reasoning:         e.Reasoning,
+			Sleep:             ms(e.SleepMs),
+			Clamped:           e.Clamped,
+			Model:             e.Model,
+			Elapsed:           ms(e.ElapsedMs),
+			ToolsUsed:         e.ToolsUsed,
+		}
+		if e.SleepRequestedMs > 0 {
+			entries[i].SleepRequested = ms(e.SleepRequestedMs)
+		}
+	}
+	stats := report.Stats
+	out := map[string]any{
+		"entries": entries,
+		"summary": map[string]any{
+			"iterations":       stats.Iterations,
+			"supervisor":       stats.Supervisor,
+			"no_sleep_request": stats.NoSleepRequest,
+			"clamped_min":      stats.ClampedMin,
+			"clamped_max":      stats.ClampedMax,
+			"sleep_min":        ms(stats.MinSleepMs),
+			"sleep_median":     ms(stats.MedianSleepMs),
+			"sleep_max":        ms(stats.MaxSleepMs),
+		},
+		"config": map[string]any{
+			"min_sleep": ms(report.MinSleepMs),
+			"max_sleep": ms(report.MaxSleepMs),
+			"capacity":  report.Capacity,
+		},
+	}
+	return promptfmt.MarshalCompact(out)
+}
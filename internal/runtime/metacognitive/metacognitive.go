@@ -16,8 +16,9 @@
 // The loop lifecycle is managed by the [loop] package. This loop is
 // declarative: the per-iteration prompt is the spec Task plus the
 // supervisor-turn [loop.Spec.SupervisorProfile] Instructions. The only
-// runtime-only hook is the PostIterate iteration-log writer, which
-// [HydrateSpec] attaches to a durable loop definition.
+// runtime-only hook is PostIterate, which [HydrateSpec] attaches to a
+// durable loop definition to write the iteration log and record each
+// iteration in a [Journal] for later review.
 package metacognitive
 
 import (
@@ -155,6 +156,10 @@ type Opts struct {
 	// for provenance store reads and writes. Ignored when ProvenanceStore
 	// is nil.
 	StateFileName string
+
+	// Journal, when non-nil, receives a record of every completed
+	// iteration.
+	Journal *Journal
 }
 
 // DefinitionSpec returns the persistable loop definition for the
@@ -216,15 +221,19 @@ func supervisorProfile(qualityFloor int) *router.LoopProfile {
 // metacognitive service needs from a durable loop definition: the
 // PostIterate iteration-log writer, which appends a provenance-signed
 // telemetry block to the state document each cycle and needs the
-// resolved state-file path from opts. The prompt itself is declarative
-// (the spec Task and SupervisorProfile.Instructions).
+// resolved state-file path from opts, and records the iteration in
+// opts.Journal. The prompt itself is declarative (the spec Task and
+// SupervisorProfile.Instructions).
 func HydrateSpec(spec loop.Spec, _ Config, opts Opts) loop.Spec {
 	if strings.TrimSpace(spec.Name) == "" {
 		spec.Name = DefinitionName
 	}
+	minSleep, maxSleep := spec.SleepMin, spec.SleepMax
+	opts.Journal.setBounds(minSleep, maxSleep)
 	spec.PostIterate = func(ctx context.Context, result loop.IterationResult) error {
 		log := logging.Logger(ctx)
 		appendIterationLog(ctx, log, opts.StateFilePath, opts.ProvenanceStore, opts.StateFileName, &result)
+		opts.Journal.record(&result, minSleep, maxSleep, time.Now())
 		return nil
 	}
 	return spec
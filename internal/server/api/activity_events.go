@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/events"
+)
+
+// activityEventBuffer bounds how many events one /events client may
+// fall behind before the bus starts dropping events for it. A stalled
+// client costs at most this much memory and never blocks the loop.
+const activityEventBuffer = 256
+
+// activityEventSources are the event sources streamed by /events: the
+// agent loop's request lifecycle and the scheduler. Loop and delegate
+// lifecycle events have their own stream at /v1/loops/events.
+var activityEventSources = map[string]bool{
+	events.SourceAgent:     true,
+	events.SourceScheduler: true,
+}
+
+// activityGap is the payload of a "gap" event, sent when a client fell
+// behind and the bus dropped events for it.
+type activityGap struct {
+	Dropped uint64    `json:"dropped"`
+	Ts      time.Time `json:"ts"`
+}
+
+// handleActivityEvents serves GET /events: a read-only Server-Sent
+// Events stream of what the agent is doing — requests starting and
+// completing, model selection, tool calls, compaction, and scheduled
+// tasks firing. Each SSE event is named after the event kind and
+// carries the [events.Event] as JSON. Every client gets its own bounded
+// buffer; a client that can't keep up loses events instead of slowing
+// the agent, and is sent a "gap" event with the number it missed.
+func (s *Server) handleActivityEvents(w http.ResponseWriter, r *http.Request) {
+	if s.eventBus == nil {
+		s.errorResponse(w, http.StatusServiceUnavailable, "event stream not available")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.errorResponse(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	// Omit Connection: keep-alive — it's hop-by-hop and forbidden in HTTP/2.
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	rc := http.NewResponseController(w)
+	// Filter on the bus so events from other sources neither fill the
+	// buffer nor count toward a gap.
+	ch := s.eventBus.SubscribeFiltered(activityEventBuffer, func(evt events.Event) bool {
+		return activityEventSources[evt.Source]
+	})
+	defer s.eventBus.Unsubscribe(ch)
+
+	write := func(name string, payload any) bool {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			s.logger.Warn("failed to marshal activity event", "event", name, "error", err)
+			return true
+		}
+		_ = rc.SetWriteDeadline(time.Now().Add(30 * time.Second))
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+	// reportGap marks events dropped since the last check. The bus
+	// drops only while the buffer is full, so the missing events fall
+	// after everything buffered at the time; checking once the buffer
+	// has drained puts the marker in about the right place.
+	reportGap := func() bool {
+		dropped := s.eventBus.TakeDropped(ch)
+		if dropped == 0 {
+			return true
+		}
+		s.logger.Warn("activity stream client fell behind; events dropped",
+			"dropped", dropped,
+			"remote_addr", r.RemoteAddr,
+		)
+		return write("gap", activityGap{Dropped: dropped, Ts: time.Now()})
+	}
+
+	_ = rc.SetWriteDeadline(time.Now().Add(30 * time.Second))
+	if _, err := fmt.Fprint(w, ": connected\n\n"); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	keepalive := time.NewTicker(25 * time.Second)
+	defer keepalive.Stop()
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-ch:
+			if !ok {
+				return
+			}
+			if !write(evt.Kind, evt) {
+				return
+			}
+			if len(ch) == 0 && !reportGap() {
+				return
+			}
+		case <-keepalive.C:
+			if !reportGap() {
+				return
+			}
+			_ = rc.SetWriteDeadline(time.Now().Add(30 * time.Second))
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
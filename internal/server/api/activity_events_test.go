@@ -0,0 +1,100 @@
+package api
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/events"
+)
+
+func TestHandleActivityEvents_StreamsAgentAndSchedulerEvents(t *testing.T) {
+	s := &Server{logger: testAPILogger(), eventBus: events.New()}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /events", s.handleActivityEvents)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/events")
+	if err != nil {
+		t.Fatalf("GET /events: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	lines := make(chan string, 64)
+	go func() {
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+		close(lines)
+	}()
+	next := func() string {
+		t.Helper()
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatal("stream closed")
+			}
+			return line
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for stream data")
+		}
+		return ""
+	}
+
+	// The subscription exists once the connected comment arrives.
+	if got := next(); got != ": connected" {
+		t.Fatalf("first line = %q, want the connected comment", got)
+	}
+
+	now := time.Now()
+	s.eventBus.Publish(events.Event{Timestamp: now, Source: events.SourceLoop, Kind: events.KindLoopStarted})
+	s.eventBus.Publish(events.Event{Timestamp: now, Source: events.SourceAgent, Kind: events.KindToolDone,
+		Data: map[string]any{"tool": "web_search", "ok": true}})
+	s.eventBus.Publish(events.Event{Timestamp: now, Source: events.SourceScheduler, Kind: events.KindTaskFired,
+		Data: map[string]any{"task_name": "morning-briefing"}})
+
+	var got []string
+	for len(got) < 4 {
+		if line := next(); line != "" {
+			got = append(got, line)
+		}
+	}
+	if got[0] != "event: tool_done" || !strings.Contains(got[1], `"tool":"web_search"`) {
+		t.Errorf("first event = %q, want tool_done with its data", got[:2])
+	}
+	if got[2] != "event: task_fired" || !strings.Contains(got[3], `"task_name":"morning-briefing"`) {
+		t.Errorf("second event = %q, want task_fired with its data (loop events are filtered)", got[2:])
+	}
+
+	// A flood from a filtered source neither fills the client's buffer
+	// nor is reported as a gap.
+	for i := 0; i < 4*activityEventBuffer; i++ {
+		s.eventBus.Publish(events.Event{Timestamp: now, Source: events.SourceLoop, Kind: events.KindLoopToolStart})
+	}
+	s.eventBus.Publish(events.Event{Timestamp: now, Source: events.SourceAgent, Kind: events.KindRequestStart})
+	line := next()
+	for line == "" {
+		line = next()
+	}
+	if line != "event: request_start" {
+		t.Errorf("after a filtered flood got %q, want request_start with no gap", line)
+	}
+}
+
+func TestHandleActivityEvents_NoBus(t *testing.T) {
+	s := &Server{logger: testAPILogger()}
+	rr := httptest.NewRecorder()
+	s.handleActivityEvents(rr, httptest.NewRequest(http.MethodGet, "/events", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rr.Code)
+	}
+}
@@ -57,6 +57,7 @@ func loopRequestFromAgent(req *agent.Request) loop.Request {
 		FallbackContent:       req.FallbackContent,
 		PromptMode:            req.PromptMode,
 		SuppressAlwaysContext: req.SuppressAlwaysContext,
+		ClientStream:          req.ClientStream,
 	}
 }
 
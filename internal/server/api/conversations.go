@@ -255,3 +255,134 @@ func decodeConvCursor(s string) (*memory.ConvCursor, error) {
 	}
 	return &c, nil
 }
+
+// handleConversationDelete serves DELETE /v1/conversations/{id}: it
+// archives the conversation's messages, ends its archive session, and
+// clears it from working memory via the agent loop's ResetConversation, so
+// a deleted conversation stays recoverable from the archive. Other
+// conversations are untouched. Guarded by [Server.requireAdmin].
+func (s *Server) handleConversationDelete(w http.ResponseWriter, r *http.Request) {
+	if !s.requireAdmin(w, r) {
+		return
+	}
+	if s.memoryStore == nil || s.loop == nil {
+		s.errorResponse(w, http.StatusServiceUnavailable, "memory store not configured")
+		return
+	}
+
+	id := r.PathValue("id")
+	if s.memoryStore.GetConversation(id) == nil {
+		s.errorResponse(w, http.StatusNotFound, "conversation not found")
+		return
+	}
+	archived := len(s.memoryStore.GetAllMessages(id))
+
+	if err := s.loop.ResetConversation(id); err != nil {
+		s.logger.Error("conversation delete failed", "conversation_id", id, "error", err)
+		s.errorResponse(w, http.StatusInternalServerError, "delete failed")
+		return
+	}
+	s.logger.Info("conversation deleted via API", "conversation_id", id, "archived_messages", archived)
+
+	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, map[string]any{
+		"status":            "ok",
+		"conversation_id":   id,
+		"archived_messages": archived,
+	}, s.logger)
+}
+
+// conversationExportToolCallPage is how many working-memory tool call
+// records one export reads at a time.
+const conversationExportToolCallPage = 500
+
+// handleConversationExport serves GET /v1/conversations/{id}/export:
+// the conversation's full message history, tool calls and results
+// included, as OpenAI chat-completions messages — the machine-readable
+// counterpart of the archive's markdown export. A conversation still in
+// working memory is exported as its archived sessions, oldest first,
+// followed by the tail not yet archived; otherwise its archived
+// sessions alone are. ?session=<id> exports one archived session of the
+// conversation instead.
+func (s *Server) handleConversationExport(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	sessionID := strings.TrimSpace(r.URL.Query().Get("session"))
+
+	var (
+		messages []memory.OpenAIMessage
+		source   string
+		err      error
+	)
+	switch {
+	case sessionID != "":
+		if s.archiveStore == nil {
+			s.errorResponse(w, http.StatusServiceUnavailable, "archive not configured")
+			return
+		}
+		sess, getErr := s.archiveStore.GetSession(sessionID)
+		if getErr != nil || sess == nil || sess.ConversationID != id {
+			s.errorResponse(w, http.StatusNotFound, "session not found")
+			return
+		}
+		messages, err = s.archiveStore.ExportSessionOpenAI(sessionID)
+		source = "archive"
+
+	case s.memoryStore != nil && s.memoryStore.GetConversation(id) != nil:
+		var (
+			archived      []memory.Message
+			archivedCalls []memory.ArchivedToolCall
+		)
+		if s.archiveStore != nil {
+			archived, archivedCalls, err = s.archiveStore.ConversationHistory(id)
+			if err != nil {
+				break
+			}
+		}
+		var calls []memory.ToolCall
+		for offset := 0; ; offset += conversationExportToolCallPage {
+			page := s.memoryStore.GetToolCallsPage(id, offset, conversationExportToolCallPage)
+			calls = append(calls, page...)
+			if len(page) < conversationExportToolCallPage {
+				break
+			}
+		}
+		messages = memory.ExportHistoryOpenAI(archived, s.memoryStore.GetUnarchivedMessages(id), archivedCalls, memory.WorkingToolCalls(calls))
+		source = "memory"
+
+	default:
+		if s.archiveStore == nil {
+			if s.memoryStore == nil {
+				s.errorResponse(w, http.StatusServiceUnavailable, "memory store not configured")
+			} else {
+				s.errorResponse(w, http.StatusNotFound, "conversation not found")
+			}
+			return
+		}
+		sessions, listErr := s.archiveStore.ListSessions(id, 1)
+		if listErr == nil && len(sessions) == 0 {
+			s.errorResponse(w, http.StatusNotFound, "conversation not found")
+			return
+		}
+		messages, err = s.archiveStore.ExportConversationOpenAI(id)
+		source = "archive"
+	}
+	if err != nil {
+		s.logger.Error("conversation export failed", "conversation_id", id, "session_id", sessionID, "error", err)
+		s.errorResponse(w, http.StatusInternalServerError, "export failed")
+		return
+	}
+	if messages == nil {
+		messages = []memory.OpenAIMessage{}
+	}
+
+	resp := map[string]any{
+		"conversation_id": id,
+		"source":          source,
+		"messages":        messages,
+	}
+	if sessionID != "" {
+		resp["session_id"] = sessionID
+	}
+	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, resp, s.logger)
+}
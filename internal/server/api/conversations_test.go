@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -9,7 +10,10 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/runtime/agent"
 	"github.com/nugget/thane-ai-agent/internal/state/memory"
 )
 
@@ -201,3 +205,316 @@ func TestHandleConversationListRelativeTime(t *testing.T) {
 		t.Fatalf("total = %v, want 1 (conv created within 1h)", body["total"])
 	}
 }
+
+// recordingArchiver captures what the agent loop archives on reset so
+// deletion tests can assert archive-before-clear without a real
+// archive database.
+type recordingArchiver struct {
+	archived map[string][]memory.Message
+	ended    []string
+}
+
+func (a *recordingArchiver) ArchiveConversation(id string, msgs []memory.Message, _ string) error {
+	if a.archived == nil {
+		a.archived = make(map[string][]memory.Message)
+	}
+	a.archived[id] = append(a.archived[id], msgs...)
+	return nil
+}
+func (a *recordingArchiver) StartSession(string) (string, error)                { return "sess", nil }
+func (a *recordingArchiver) EndSession(id, _ string) error                      { a.ended = append(a.ended, id); return nil }
+func (a *recordingArchiver) ActiveSessionID(string) string                      { return "sess" }
+func (a *recordingArchiver) EnsureSession(string) string                        { return "sess" }
+func (a *recordingArchiver) ArchiveIterations([]memory.ArchivedIteration) error { return nil }
+func (a *recordingArchiver) LinkPendingIterationToolCalls(string) error         { return nil }
+func (a *recordingArchiver) OnMessage(string)                                   {}
+func (a *recordingArchiver) ActiveSessionStartedAt(string) time.Time            { return time.Time{} }
+
+type unusedLLM struct{}
+
+func (unusedLLM) Chat(context.Context, string, []llm.Message, []map[string]any) (*llm.ChatResponse, error) {
+	return nil, fmt.Errorf("unexpected Chat call")
+}
+func (unusedLLM) ChatStream(context.Context, string, []llm.Message, []map[string]any, llm.StreamCallback) (*llm.ChatResponse, error) {
+	return nil, fmt.Errorf("unexpected ChatStream call")
+}
+func (unusedLLM) Ping(context.Context) error { return nil }
+
+func newConvDeleteTestServer(t *testing.T) (*Server, *memory.SQLiteStore, *recordingArchiver) {
+	t.Helper()
+	s, store := newConvTestServer(t)
+	archiver := &recordingArchiver{}
+	loop, err := agent.NewLoop(agent.LoopOptions{
+		Logger:   testAPILogger(),
+		Memory:   store,
+		LLM:      unusedLLM{},
+		Model:    "test-model",
+		Archiver: archiver,
+	})
+	if err != nil {
+		t.Fatalf("NewLoop: %v", err)
+	}
+	s.loop = loop
+	return s, store, archiver
+}
+
+func doConvDelete(s *Server, id, auth string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodDelete, "/v1/conversations/"+id, nil)
+	req.SetPathValue("id", id)
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	rr := httptest.NewRecorder()
+	s.handleConversationDelete(rr, req)
+	return rr
+}
+
+func TestHandleConversationListReflectsActiveConversations(t *testing.T) {
+	s, store := newConvTestServer(t)
+	addConv(t, store, "alpha", 2, nil)
+	addConv(t, store, "beta", 3, nil)
+
+	_, body := doConvList(t, s, "sort=message_count&order=asc")
+	convs := body["conversations"].([]any)
+	if len(convs) != 2 {
+		t.Fatalf("conversations = %d, want 2", len(convs))
+	}
+	for i, want := range []struct {
+		id    string
+		count int
+	}{{"alpha", 2}, {"beta", 3}} {
+		c := convs[i].(map[string]any)
+		if c["id"] != want.id || int(c["message_count"].(float64)) != want.count {
+			t.Errorf("conversations[%d] = %v/%v, want %s/%d", i, c["id"], c["message_count"], want.id, want.count)
+		}
+		if c["updated_at"] == nil || c["updated_at"] == "" {
+			t.Errorf("conversations[%d] missing updated_at", i)
+		}
+	}
+}
+
+func TestHandleConversationDeleteArchivesThenClears(t *testing.T) {
+	s, store, archiver := newConvDeleteTestServer(t)
+	addConv(t, store, "doomed", 3, nil)
+	addConv(t, store, "keeper", 2, nil)
+
+	rr := doConvDelete(s, "doomed", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rr.Code, rr.Body.String())
+	}
+	var body map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if got := int(body["archived_messages"].(float64)); got != 3 {
+		t.Errorf("archived_messages = %d, want 3", got)
+	}
+
+	if got := len(archiver.archived["doomed"]); got != 3 {
+		t.Errorf("archived doomed messages = %d, want 3", got)
+	}
+	if _, ok := archiver.archived["keeper"]; ok {
+		t.Error("keeper was archived, want untouched")
+	}
+	if got := len(store.GetAllMessages("doomed")); got != 0 {
+		t.Errorf("doomed messages after delete = %d, want 0", got)
+	}
+	if got := len(store.GetAllMessages("keeper")); got != 2 {
+		t.Errorf("keeper messages after delete = %d, want 2", got)
+	}
+
+	_, list := doConvList(t, s, "")
+	convs := list["conversations"].([]any)
+	if len(convs) != 1 || convs[0].(map[string]any)["id"] != "keeper" {
+		t.Errorf("conversations after delete = %v, want only keeper", convs)
+	}
+}
+
+func TestHandleConversationDeleteNotFound(t *testing.T) {
+	s, _, archiver := newConvDeleteTestServer(t)
+	if rr := doConvDelete(s, "ghost", ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rr.Code)
+	}
+	if len(archiver.archived) != 0 {
+		t.Errorf("archived = %v, want nothing", archiver.archived)
+	}
+}
+
+func TestHandleConversationDeleteRequiresAdminToken(t *testing.T) {
+	s, store, _ := newConvDeleteTestServer(t)
+	s.SetAdminToken("s3cret")
+	addConv(t, store, "guarded", 1, nil)
+
+	for _, auth := range []string{"", "Bearer wrong", "s3cret"} {
+		rr := doConvDelete(s, "guarded", auth)
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("auth %q: status = %d, want 401", auth, rr.Code)
+		}
+	}
+	if got := len(store.GetAllMessages("guarded")); got != 1 {
+		t.Fatalf("messages after rejected deletes = %d, want 1", got)
+	}
+
+	if rr := doConvDelete(s, "guarded", "Bearer s3cret"); rr.Code != http.StatusOK {
+		t.Fatalf("authorized delete status = %d, want 200 (body=%s)", rr.Code, rr.Body.String())
+	}
+}
+
+func doConvExport(t *testing.T, s *Server, id, rawquery string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/v1/conversations/"+id+"/export?"+rawquery, nil)
+	req.SetPathValue("id", id)
+	rr := httptest.NewRecorder()
+	s.handleConversationExport(rr, req)
+	var body map[string]any
+	if rr.Code == http.StatusOK {
+		if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode body: %v (raw=%s)", err, rr.Body.String())
+		}
+	}
+	return rr, body
+}
+
+func TestHandleConversationExport_WorkingMemory(t *testing.T) {
+	s, store := newConvTestServer(t)
+	addConv(t, store, "signal-1", 1, nil)
+	if err := store.RecordToolCall("signal-1", "", "call-1", "get_state", `{"entity_id":"light.porch"}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CompleteToolCall("call-1", `{"state":"on"}`, ""); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond) // keep the reply after the tool call
+	if err := store.AddMessage("signal-1", "assistant", "It is on."); err != nil {
+		t.Fatal(err)
+	}
+
+	rr, body := doConvExport(t, s, "signal-1", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rr.Code, rr.Body.String())
+	}
+	if body["source"] != "memory" {
+		t.Errorf("source = %v, want memory", body["source"])
+	}
+	msgs, _ := body["messages"].([]any)
+	var roles []string
+	for _, m := range msgs {
+		roles = append(roles, m.(map[string]any)["role"].(string))
+	}
+	if got := strings.Join(roles, ","); got != "user,assistant,tool,assistant" {
+		t.Fatalf("roles = %s, want user,assistant,tool,assistant (body %s)", got, rr.Body.String())
+	}
+	call := msgs[1].(map[string]any)["tool_calls"].([]any)[0].(map[string]any)
+	fn := call["function"].(map[string]any)
+	if call["id"] != "call-1" || fn["name"] != "get_state" || fn["arguments"] != `{"entity_id":"light.porch"}` {
+		t.Errorf("tool call = %v", call)
+	}
+	if reply := msgs[2].(map[string]any); reply["tool_call_id"] != "call-1" || reply["content"] != `{"state":"on"}` {
+		t.Errorf("tool reply = %v", reply)
+	}
+}
+
+func TestHandleConversationExport_Archive(t *testing.T) {
+	s, _ := newConvTestServer(t)
+	archive, err := memory.NewArchiveStore(t.TempDir()+"/archive.db", nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { archive.Close() })
+	s.archiveStore = archive
+
+	base := time.Date(2026, 2, 12, 10, 0, 0, 0, time.UTC)
+	var sessionIDs []string
+	for i, text := range []string{"first", "second"} {
+		sess, err := archive.StartSessionAt("signal-9", base.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessionIDs = append(sessionIDs, sess.ID)
+		if err := archive.ArchiveMessages([]memory.Message{{
+			ID: "m-" + text, ConversationID: "signal-9", SessionID: sess.ID, Role: "user",
+			Content: text, Timestamp: base.Add(time.Duration(i) * time.Hour), ArchiveReason: "reset",
+		}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	other, err := archive.StartSession("signal-other")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents := func(body map[string]any) string {
+		var out []string
+		for _, m := range body["messages"].([]any) {
+			out = append(out, m.(map[string]any)["content"].(string))
+		}
+		return strings.Join(out, ",")
+	}
+
+	rr, body := doConvExport(t, s, "signal-9", "")
+	if rr.Code != http.StatusOK || body["source"] != "archive" || contents(body) != "first,second" {
+		t.Errorf("whole conversation: status %d, body %s", rr.Code, rr.Body.String())
+	}
+
+	rr, body = doConvExport(t, s, "signal-9", "session="+url.QueryEscape(sessionIDs[1]))
+	if rr.Code != http.StatusOK || body["session_id"] != sessionIDs[1] || contents(body) != "second" {
+		t.Errorf("one session: status %d, body %s", rr.Code, rr.Body.String())
+	}
+
+	if rr, _ := doConvExport(t, s, "signal-9", "session="+other.ID); rr.Code != http.StatusNotFound {
+		t.Errorf("session of another conversation: status = %d, want 404", rr.Code)
+	}
+	if rr, _ := doConvExport(t, s, "missing", ""); rr.Code != http.StatusNotFound {
+		t.Errorf("unknown conversation: status = %d, want 404", rr.Code)
+	}
+}
+
+func TestHandleConversationExport_ArchivedSessionsThenTail(t *testing.T) {
+	s, store := newConvTestServer(t)
+	archive, err := memory.NewArchiveStore(t.TempDir()+"/archive.db", nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { archive.Close() })
+	s.archiveStore = archive
+
+	old := time.Now().Add(-24 * time.Hour)
+	sess, err := archive.StartSessionAt("signal-2", old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.ArchiveMessages([]memory.Message{{
+		ID: "m-old", ConversationID: "signal-2", SessionID: sess.ID, Role: "user",
+		Content: "yesterday", Timestamp: old, ArchiveReason: "reset",
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	addConv(t, store, "signal-2", 1, nil)
+	// More tool calls than one page, so the export must page.
+	const calls = conversationExportToolCallPage + 20
+	for i := range calls {
+		id := fmt.Sprintf("call-%d", i)
+		if err := store.RecordToolCall("signal-2", "", id, "get_state", "{}"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rr, body := doConvExport(t, s, "signal-2", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rr.Code, rr.Body.String())
+	}
+	msgs, _ := body["messages"].([]any)
+	if len(msgs) == 0 || msgs[0].(map[string]any)["content"] != "yesterday" {
+		t.Fatalf("first message = %v, want the archived session first", msgs)
+	}
+	tools := 0
+	for _, m := range msgs {
+		if m.(map[string]any)["role"] == "tool" {
+			tools++
+		}
+	}
+	if tools != calls {
+		t.Errorf("tool results = %d, want all %d tool calls", tools, calls)
+	}
+}
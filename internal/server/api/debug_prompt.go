@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+)
+
+// handleDebugPrompt serves GET /debug/prompt?conversation=<id>: the
+// system prompt most recently assembled for the conversation, with the
+// byte size of each section. Capture is off unless debug.prompt_endpoint
+// is set, since it keeps recent prompts in memory and they carry the
+// persona, injected documents, and live home state. Unlike the other
+// admin endpoints it stays disabled until listen.admin_token is set,
+// and then requires it via [Server.requireAdmin].
+func (s *Server) handleDebugPrompt(w http.ResponseWriter, r *http.Request) {
+	if s.loop == nil || !s.loop.PromptCaptureEnabled() {
+		s.errorResponse(w, http.StatusNotFound, "prompt debugging is disabled; set debug.prompt_endpoint to enable it")
+		return
+	}
+	if s.adminToken == "" {
+		s.errorResponse(w, http.StatusNotFound, "prompt debugging is disabled; set listen.admin_token to enable it")
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+
+	convID := strings.TrimSpace(r.URL.Query().Get("conversation"))
+	if convID == "" {
+		s.errorResponse(w, http.StatusBadRequest, "conversation parameter is required")
+		return
+	}
+	snap, ok := s.loop.LastPrompt(convID)
+	if !ok {
+		s.errorResponse(w, http.StatusNotFound, "no prompt assembled for this conversation since startup")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, snap, s.logger)
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/runtime/agent"
+)
+
+// replyLLM answers every call with a fixed assistant message.
+type replyLLM struct{ unusedLLM }
+
+func (replyLLM) Chat(context.Context, string, []llm.Message, []map[string]any) (*llm.ChatResponse, error) {
+	return &llm.ChatResponse{Message: llm.Message{Role: "assistant", Content: "ok"}}, nil
+}
+
+func (r replyLLM) ChatStream(ctx context.Context, model string, msgs []llm.Message, tools []map[string]any, _ llm.StreamCallback) (*llm.ChatResponse, error) {
+	return r.Chat(ctx, model, msgs, tools)
+}
+
+func doDebugPrompt(s *Server, rawquery, auth string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/debug/prompt?"+rawquery, nil)
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	rr := httptest.NewRecorder()
+	s.handleDebugPrompt(rr, req)
+	return rr
+}
+
+func TestHandleDebugPrompt(t *testing.T) {
+	s, store := newConvTestServer(t)
+	loop, err := agent.NewLoop(agent.LoopOptions{
+		Logger: testAPILogger(),
+		Memory: store,
+		LLM:    replyLLM{},
+		Model:  "test-model",
+	})
+	if err != nil {
+		t.Fatalf("NewLoop: %v", err)
+	}
+	s.loop = loop
+
+	if rr := doDebugPrompt(s, "conversation=conv-1", ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("disabled status = %d, want 404", rr.Code)
+	}
+
+	loop.EnablePromptCapture()
+	if rr := doDebugPrompt(s, "conversation=conv-1", ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("no admin token status = %d, want 404", rr.Code)
+	}
+
+	s.SetAdminToken("s3cret")
+	if rr := doDebugPrompt(s, "conversation=conv-1", ""); rr.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated status = %d, want 401", rr.Code)
+	}
+	if rr := doDebugPrompt(s, "", "Bearer s3cret"); rr.Code != http.StatusBadRequest {
+		t.Errorf("missing conversation status = %d, want 400", rr.Code)
+	}
+	if rr := doDebugPrompt(s, "conversation=conv-1", "Bearer s3cret"); rr.Code != http.StatusNotFound {
+		t.Errorf("unknown conversation status = %d, want 404", rr.Code)
+	}
+
+	if _, err := loop.Run(context.Background(), &agent.Request{
+		ConversationID: "conv-1",
+		Messages:       []agent.Message{{Role: "user", Content: "what is the kitchen temperature?"}},
+	}, nil); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	rr := doDebugPrompt(s, "conversation=conv-1", "Bearer s3cret")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rr.Code, rr.Body.String())
+	}
+	var snap agent.PromptSnapshot
+	if err := json.Unmarshal(rr.Body.Bytes(), &snap); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if snap.ConversationID != "conv-1" || snap.Prompt == "" || snap.Bytes != len(snap.Prompt) || len(snap.Sections) == 0 {
+		t.Errorf("snapshot = %+v, want conv-1's prompt with section sizes", snap)
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/delegate"
+)
+
+// delegateReplayWriteTimeout bounds how long a replay may hold its
+// response open. A replay runs a whole delegation, which can outlast
+// the server's default write timeout.
+const delegateReplayWriteTimeout = 15 * time.Minute
+
+type delegateReplayRequest struct {
+	Model string `json:"model"`
+}
+
+// ConfigureDelegateReplay configures the replay function behind
+// POST /v1/archive/sessions/{id}/replay, normally
+// [delegate.Executor.Replay].
+func (s *Server) ConfigureDelegateReplay(replay func(ctx context.Context, executionID, overrideModel string) (*delegate.ReplayResult, error)) {
+	s.replayDelegate = replay
+}
+
+// handleDelegateReplay serves POST /v1/archive/sessions/{id}/replay:
+// it reruns the delegate execution archived as session id, optionally
+// on another model, and returns the original record with the new
+// result. The replay spends model tokens and reads archived tool
+// results, so it is guarded by [Server.requireAdmin].
+func (s *Server) handleDelegateReplay(w http.ResponseWriter, r *http.Request) {
+	if !s.requireAdmin(w, r) {
+		return
+	}
+	if s.replayDelegate == nil {
+		s.errorResponse(w, http.StatusServiceUnavailable, "delegate replay not configured")
+		return
+	}
+
+	var req delegateReplayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		s.errorResponse(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(delegateReplayWriteTimeout))
+	result, err := s.replayDelegate(r.Context(), r.PathValue("id"), strings.TrimSpace(req.Model))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, delegate.ErrExecutionNotFound) {
+			status = http.StatusNotFound
+		}
+		s.errorResponse(w, status, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, result, s.logger)
+}
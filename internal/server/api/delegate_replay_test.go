@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/delegate"
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
+)
+
+func doDelegateReplay(s *Server, id, body, auth string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/v1/archive/sessions/"+id+"/replay", strings.NewReader(body))
+	req.SetPathValue("id", id)
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	rr := httptest.NewRecorder()
+	s.handleDelegateReplay(rr, req)
+	return rr
+}
+
+func TestHandleDelegateReplay(t *testing.T) {
+	s := &Server{logger: testAPILogger()}
+	if rr := doDelegateReplay(s, "abc", "", ""); rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("unconfigured status = %d, want 503", rr.Code)
+	}
+
+	var gotID, gotModel string
+	s.ConfigureDelegateReplay(func(_ context.Context, id, model string) (*delegate.ReplayResult, error) {
+		if id != "abc" {
+			return nil, fmt.Errorf("%w: no session", delegate.ErrExecutionNotFound)
+		}
+		gotID, gotModel = id, model
+		return &delegate.ReplayResult{
+			SessionID: "abc-full",
+			Original:  &memory.DelegationMetadata{Task: "check the garage", Model: "big-model", ResultContent: "closed"},
+			Replay:    &delegate.Result{Model: model, Content: "it is closed", SessionID: "def-full"},
+		}, nil
+	})
+	s.SetAdminToken("s3cret")
+
+	if rr := doDelegateReplay(s, "abc", `{"model":"small-model"}`, ""); rr.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated status = %d, want 401", rr.Code)
+	}
+	if rr := doDelegateReplay(s, "abc", `{"model":`, "Bearer s3cret"); rr.Code != http.StatusBadRequest {
+		t.Errorf("malformed body status = %d, want 400", rr.Code)
+	}
+	if rr := doDelegateReplay(s, "zzz", "", "Bearer s3cret"); rr.Code != http.StatusNotFound {
+		t.Errorf("unknown execution status = %d, want 404", rr.Code)
+	}
+
+	rr := doDelegateReplay(s, "abc", `{"model":" small-model "}`, "Bearer s3cret")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rr.Code, rr.Body.String())
+	}
+	if gotID != "abc" || gotModel != "small-model" {
+		t.Errorf("replayed %q on %q, want abc on small-model", gotID, gotModel)
+	}
+	var result delegate.ReplayResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if result.Original.ResultContent != "closed" || result.Replay.Content != "it is closed" || result.Replay.SessionID != "def-full" {
+		t.Errorf("result = %+v, want the original and replay outputs", result)
+	}
+
+	if rr := doDelegateReplay(s, "abc", "", "Bearer s3cret"); rr.Code != http.StatusOK || gotModel != "" {
+		t.Errorf("empty body status = %d, model %q; want 200 on the original model", rr.Code, gotModel)
+	}
+}
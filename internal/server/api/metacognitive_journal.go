@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/metacognitive"
+)
+
+// ConfigureMetacognitiveJournal configures the report function behind
+// GET /v1/metacognitive/journal, normally [metacognitive.Journal.Report].
+func (s *Server) ConfigureMetacognitiveJournal(report func(limit int) metacognitive.JournalReport) {
+	s.metacognitiveJournal = report
+}
+
+// handleMetacognitiveJournal serves GET /v1/metacognitive/journal: the
+// metacognitive loop's recent iterations, newest first, with cadence
+// stats. An optional ?limit= caps the entries returned; without it
+// every kept entry is returned.
+func (s *Server) handleMetacognitiveJournal(w http.ResponseWriter, r *http.Request) {
+	if s.metacognitiveJournal == nil {
+		s.errorResponse(w, http.StatusServiceUnavailable, "metacognitive journal not configured")
+		return
+	}
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			s.errorResponse(w, http.StatusBadRequest, "limit must be a non-negative integer")
+			return
+		}
+		limit = n
+	}
+	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, s.metacognitiveJournal(limit), s.logger)
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/metacognitive"
+)
+
+func TestHandleMetacognitiveJournal(t *testing.T) {
+	get := func(s *Server, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/metacognitive/journal"+query, nil)
+		rr := httptest.NewRecorder()
+		s.handleMetacognitiveJournal(rr, req)
+		return rr
+	}
+
+	s := &Server{logger: testAPILogger()}
+	if rr := get(s, ""); rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("unconfigured status = %d, want 503", rr.Code)
+	}
+
+	gotLimit := -1
+	s.ConfigureMetacognitiveJournal(func(limit int) metacognitive.JournalReport {
+		gotLimit = limit
+		return metacognitive.JournalReport{
+			Entries:  []metacognitive.JournalEntry{{Reasoning: "quiet evening", SleepMs: 1800000, Clamped: "max"}},
+			Capacity: metacognitive.DefaultJournalSize,
+		}
+	})
+
+	if rr := get(s, "?limit=abc"); rr.Code != http.StatusBadRequest {
+		t.Errorf("invalid limit status = %d, want 400", rr.Code)
+	}
+	if rr := get(s, "?limit=-1"); rr.Code != http.StatusBadRequest {
+		t.Errorf("negative limit status = %d, want 400", rr.Code)
+	}
+
+	rr := get(s, "?limit=5")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rr.Code, rr.Body.String())
+	}
+	if gotLimit != 5 {
+		t.Errorf("limit = %d, want 5", gotLimit)
+	}
+	var report metacognitive.JournalReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if len(report.Entries) != 1 || report.Entries[0].Reasoning != "quiet evening" || report.Entries[0].Clamped != "max" {
+		t.Errorf("report = %+v", report)
+	}
+
+	if get(s, ""); gotLimit != 0 {
+		t.Errorf("limit without query = %d, want 0 (all entries)", gotLimit)
+	}
+}
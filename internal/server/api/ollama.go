@@ -323,6 +323,7 @@ func handleOllamaStreamingChatShared(w http.ResponseWriter, r *http.Request, req
 	if req.Model != "" {
 		model = req.Model
 	}
+	req.ClientStream = true
 
 	// Buffer to detect tool calls at the start of streaming
 	// Note: Tool call detection relies on KindToolCallStart events which are emitted
@@ -350,6 +351,25 @@ func handleOllamaStreamingChatShared(w http.ResponseWriter, r *http.Request, req
 
 	// Create stream callback that buffers initially, then streams if no tool calls
 	streamCallback := func(event agent.StreamEvent) {
+		// A heartbeat is an empty chunk, which clients append as
+		// nothing, so idle-timeout proxies keep the stream open while
+		// a slow model or a tool-call fallback goes quiet.
+		if event.Kind == agent.KindHeartbeat {
+			chunk := OllamaChatResponse{
+				Model:     model,
+				CreatedAt: time.Now().UTC().Format(time.RFC3339Nano),
+				Message: OllamaChatMessage{
+					Role:    "assistant",
+					Content: "",
+				},
+				Done: false,
+			}
+			data, _ := json.Marshal(chunk)
+			fmt.Fprintf(w, "%s\n", data)
+			flusher.Flush()
+			return
+		}
+
 		// If we've already detected tool calls, stop processing events
 		if hasToolCalls {
 			return
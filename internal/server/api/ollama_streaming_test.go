@@ -115,3 +115,37 @@ func TestHandleOllamaStreamingChatShared_StreamsFirstTokenImmediately(t *testing
 		t.Fatal("timed out waiting for streaming handler to finish")
 	}
 }
+
+func TestHandleOllamaStreamingChatShared_HeartbeatWritesEmptyChunk(t *testing.T) {
+	t.Parallel()
+
+	rec := newStreamingResponseRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/chat", nil)
+	agentReq := &agent.Request{
+		Messages: []agent.Message{{Role: "user", Content: "check the garage"}},
+	}
+
+	var lines int
+	run := func(_ context.Context, req *agent.Request, cb agent.StreamCallback) (*agent.Response, error) {
+		if !req.ClientStream {
+			t.Error("streaming handler did not mark the request as serving a client stream")
+		}
+		// Heartbeats keep the stream alive before the first token and
+		// after a tool call has switched the handler to buffering.
+		before := strings.Count(rec.String(), "\n")
+		cb(agent.StreamEvent{Kind: agent.KindHeartbeat})
+		cb(agent.StreamEvent{Kind: agent.KindToolCallStart})
+		cb(agent.StreamEvent{Kind: agent.KindHeartbeat})
+		lines = strings.Count(rec.String(), "\n") - before
+		return &agent.Response{Content: "closed", Model: "thane:latest"}, nil
+	}
+
+	handleOllamaStreamingChatShared(rec, req, agentReq, time.Now(), run, slog.Default())
+
+	if lines != 2 {
+		t.Errorf("heartbeats wrote %d lines, want 2", lines)
+	}
+	if body := rec.String(); strings.Count(body, `"content":""`) < 3 || !strings.Contains(body, `"content":"closed"`) {
+		t.Errorf("body = %q, want empty heartbeat chunks and the final content", body)
+	}
+}
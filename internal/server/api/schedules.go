@@ -25,8 +25,9 @@ type SchedulerReader interface {
 func (s *Server) UseScheduler(r SchedulerReader) { s.schedulerReader = r }
 
 // scheduleView augments a scheduled task with its next computed fire time.
-// NextRun is derived from the schedule (not persisted) and is omitted for a
-// task with no future run, e.g. a one-shot whose time has already passed.
+// NextRun is derived from the schedule and is omitted for a task with no
+// future run, e.g. a one-shot whose time has already passed. The task's
+// own next_run_at is the persisted time its timer is armed for.
 type scheduleView struct {
 	*scheduler.Task
 	NextRun *time.Time `json:"next_run,omitempty"`
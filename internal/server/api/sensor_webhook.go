@@ -0,0 +1,163 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// sensorWebhookSecretHeader carries the shared secret on sensor
+// webhook requests.
+const sensorWebhookSecretHeader = "X-Webhook-Secret"
+
+// maxSensorWebhookBody caps a webhook payload; sensor pushes are tiny.
+const maxSensorWebhookBody = 64 << 10
+
+// WebhookSensor maps one webhook sensor name to the virtual entity it
+// feeds.
+type WebhookSensor struct {
+	// EntityID is the virtual entity ID the state change is reported
+	// under.
+	EntityID string
+
+	// Field is the dotted path of the state value in the JSON payload.
+	Field string
+
+	// DeviceClass is passed through to the sink for semantic rendering.
+	DeviceClass string
+}
+
+// SensorStateSink receives virtual sensor transitions. It matches the
+// homeassistant.StateWatchHandler signature so the state window and
+// other watcher taps can be wired in directly.
+type SensorStateSink func(entityID, oldState, newState, deviceClass string)
+
+// sensorWebhook holds the receiver configuration and the last state
+// seen per virtual entity, which supplies oldState on the next push.
+type sensorWebhook struct {
+	path    string
+	secret  string
+	sensors map[string]WebhookSensor
+	sink    SensorStateSink
+
+	mu   sync.Mutex
+	last map[string]string
+}
+
+// ConfigureSensorWebhook enables the sensor webhook receiver at
+// POST <path>/{name}. Each accepted payload is reduced to the named
+// sensor's field value and delivered to sink as a state change. An
+// empty secret, no sensors, or a nil sink leaves the receiver off.
+func (s *Server) ConfigureSensorWebhook(path, secret string, sensors map[string]WebhookSensor, sink SensorStateSink) {
+	secret = strings.TrimSpace(secret)
+	if secret == "" || len(sensors) == 0 || sink == nil {
+		s.sensorWebhook = nil
+		return
+	}
+	copied := make(map[string]WebhookSensor, len(sensors))
+	for name, sensor := range sensors {
+		copied[name] = sensor
+	}
+	s.sensorWebhook = &sensorWebhook{
+		path:    strings.TrimSuffix(path, "/"),
+		secret:  secret,
+		sensors: copied,
+		sink:    sink,
+		last:    make(map[string]string),
+	}
+}
+
+// handleSensorWebhook accepts a pushed sensor reading and forwards it
+// into the state change pipeline as a virtual entity transition.
+func (s *Server) handleSensorWebhook(w http.ResponseWriter, r *http.Request) {
+	hook := s.sensorWebhook
+	if hook == nil {
+		s.errorResponse(w, http.StatusServiceUnavailable, "sensor webhook not configured")
+		return
+	}
+	got := r.Header.Get(sensorWebhookSecretHeader)
+	if subtle.ConstantTimeCompare([]byte(got), []byte(hook.secret)) != 1 {
+		s.errorResponse(w, http.StatusUnauthorized, "invalid webhook secret")
+		return
+	}
+
+	name := r.PathValue("name")
+	sensor, ok := hook.sensors[name]
+	if !ok {
+		s.errorResponse(w, http.StatusNotFound, "unknown sensor: "+name)
+		return
+	}
+
+	var payload any
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSensorWebhookBody))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "invalid JSON payload: "+err.Error())
+		return
+	}
+	state, err := webhookFieldValue(payload, sensor.Field)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	hook.mu.Lock()
+	old := hook.last[sensor.EntityID]
+	hook.last[sensor.EntityID] = state
+	hook.mu.Unlock()
+
+	hook.sink(sensor.EntityID, old, state, sensor.DeviceClass)
+	s.logger.Debug("sensor webhook state received",
+		"sensor", name,
+		"entity_id", sensor.EntityID,
+		"old_state", old,
+		"new_state", state,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, map[string]string{
+		"status":    "accepted",
+		"entity_id": sensor.EntityID,
+		"state":     state,
+	}, s.logger)
+}
+
+// webhookFieldValue walks a dotted field path through a decoded JSON
+// payload and renders the scalar it finds as a state string.
+func webhookFieldValue(payload any, field string) (string, error) {
+	cur := payload
+	for _, key := range strings.Split(field, ".") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("payload field %q: %q is not an object", field, key)
+		}
+		if cur, ok = obj[key]; !ok {
+			return "", fmt.Errorf("payload field %q not found", field)
+		}
+	}
+	switch v := cur.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		if v {
+			return "on", nil
+		}
+		return "off", nil
+	case nil:
+		return "unknown", nil
+	default:
+		return "", fmt.Errorf("payload field %q must be a string, number, or boolean", field)
+	}
+}
+
+// route returns the mux pattern for the configured receiver. It lives
+// outside the literal route table because the prefix is
+// operator-configured, so native.yaml does not document it.
+func (h *sensorWebhook) route() string {
+	return "POST " + h.path + "/{name}"
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
+	"github.com/nugget/thane-ai-agent/internal/runtime/agentctx"
+)
+
+// newSensorWebhookMux returns a mux serving a receiver wired to a real
+// state window, so tests can assert on the injected context.
+func newSensorWebhookMux(t *testing.T) (*http.ServeMux, *homeassistant.StateWindowProvider) {
+	t.Helper()
+	window := homeassistant.NewStateWindowProvider(10, 30*time.Minute, nil, nil)
+	s := &Server{logger: testAPILogger()}
+	s.ConfigureSensorWebhook("/hooks/sensors", "s3cret", map[string]WebhookSensor{
+		"mailbox": {EntityID: "binary_sensor.webhook_mailbox", Field: "state"},
+		"pool":    {EntityID: "sensor.pool_temperature", Field: "data.temp_f"},
+	}, window.HandleStateChange)
+	if s.sensorWebhook == nil {
+		t.Fatal("ConfigureSensorWebhook left the receiver disabled")
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(s.sensorWebhook.route(), s.handleSensorWebhook)
+	return mux, window
+}
+
+func postSensorWebhook(mux *http.ServeMux, path, secret, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if secret != "" {
+		req.Header.Set(sensorWebhookSecretHeader, secret)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func windowContext(t *testing.T, window *homeassistant.StateWindowProvider) string {
+	t.Helper()
+	got, err := window.TagContext(context.Background(), agentctx.ContextRequest{})
+	if err != nil {
+		t.Fatalf("TagContext: %v", err)
+	}
+	return got
+}
+
+func TestSensorWebhook_PayloadAppearsInStateWindowContext(t *testing.T) {
+	mux, window := newSensorWebhookMux(t)
+
+	rec := postSensorWebhook(mux, "/hooks/sensors/pool", "s3cret", `{"data":{"temp_f":82.5}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	rec = postSensorWebhook(mux, "/hooks/sensors/mailbox", "s3cret", `{"state":true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	rec = postSensorWebhook(mux, "/hooks/sensors/mailbox", "s3cret", `{"state":false}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	got := windowContext(t, window)
+	for _, want := range []string{
+		"### Recent State Changes",
+		`"entity":"sensor.pool_temperature"`,
+		`"to":"82.5"`,
+		`"entity":"binary_sensor.webhook_mailbox","from":"on","to":"off"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("context missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestSensorWebhook_RejectsBadSecret(t *testing.T) {
+	mux, window := newSensorWebhookMux(t)
+
+	for _, secret := range []string{"", "wrong"} {
+		rec := postSensorWebhook(mux, "/hooks/sensors/mailbox", secret, `{"state":"on"}`)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("secret %q: status = %d, want 401", secret, rec.Code)
+		}
+	}
+	if got := windowContext(t, window); got != "" {
+		t.Errorf("rejected payload reached the state window:\n%s", got)
+	}
+}
+
+func TestSensorWebhook_UnknownSensorAndMissingField(t *testing.T) {
+	mux, _ := newSensorWebhookMux(t)
+
+	if rec := postSensorWebhook(mux, "/hooks/sensors/garage", "s3cret", `{"state":"on"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown sensor: status = %d, want 404", rec.Code)
+	}
+	if rec := postSensorWebhook(mux, "/hooks/sensors/pool", "s3cret", `{"temp_f":80}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("missing field: status = %d, want 422", rec.Code)
+	}
+	if rec := postSensorWebhook(mux, "/hooks/sensors/pool", "s3cret", `not json`); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad JSON: status = %d, want 400", rec.Code)
+	}
+}
@@ -6,6 +6,7 @@ package api
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -16,6 +17,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nugget/thane-ai-agent/internal/integrations/search"
 	"github.com/nugget/thane-ai-agent/internal/model/fleet"
 	"github.com/nugget/thane-ai-agent/internal/model/llm"
 	"github.com/nugget/thane-ai-agent/internal/model/router"
@@ -23,11 +25,14 @@ import (
 	"github.com/nugget/thane-ai-agent/internal/platform/buildinfo"
 	"github.com/nugget/thane-ai-agent/internal/platform/checkpoint"
 	"github.com/nugget/thane-ai-agent/internal/platform/config"
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
 	"github.com/nugget/thane-ai-agent/internal/platform/events"
 	"github.com/nugget/thane-ai-agent/internal/platform/logging"
 	"github.com/nugget/thane-ai-agent/internal/platform/usage"
 	"github.com/nugget/thane-ai-agent/internal/runtime/agent"
+	"github.com/nugget/thane-ai-agent/internal/runtime/delegate"
 	looppkg "github.com/nugget/thane-ai-agent/internal/runtime/loop"
+	"github.com/nugget/thane-ai-agent/internal/runtime/metacognitive"
 	"github.com/nugget/thane-ai-agent/internal/server/legacyroute"
 	"github.com/nugget/thane-ai-agent/internal/server/openapi"
 	"github.com/nugget/thane-ai-agent/internal/state/contacts"
@@ -52,21 +57,38 @@ func writeJSON(w http.ResponseWriter, v any, logger *slog.Logger) {
 }
 
 // DependencyStatus describes the health of a single watched dependency.
+// LastSuccess, LastProbeMs, and SuccessRate let a status page tell a
+// flapping dependency (probes succeed intermittently) from a hard-down
+// one. SuccessRate covers the last RecentProbes probes and is nil
+// before the first probe.
 type DependencyStatus struct {
-	Name      string `json:"name"`
-	Ready     bool   `json:"ready"`
-	LastCheck string `json:"last_check,omitempty"`
-	LastError string `json:"last_error,omitempty"`
+	Name         string   `json:"name"`
+	Ready        bool     `json:"ready"`
+	LastCheck    string   `json:"last_check,omitempty"`
+	LastError    string   `json:"last_error,omitempty"`
+	LastSuccess  string   `json:"last_success,omitempty"`
+	LastProbeMs  int64    `json:"last_probe_ms"`
+	RecentProbes int      `json:"recent_probes"`
+	SuccessRate  *float64 `json:"success_rate,omitempty"`
 }
 
 // HealthStatusFunc returns dependency health information for the /health endpoint.
 type HealthStatusFunc func() map[string]DependencyStatus
 
+// DatabaseStatsFunc returns per-database WAL statistics for the
+// /health endpoint.
+type DatabaseStatsFunc func() []database.WALStats
+
+// WebCacheStatsFunc returns web search/fetch result cache statistics
+// for the /health endpoint, keyed by cache ("search", "fetch").
+type WebCacheStatsFunc func() map[string]search.CacheStats
+
 // TokenObserver is notified after each LLM completion with the token
-// counts from that request. Implementations must be safe for
+// counts from that request and the provider that served it (e.g.,
+// "ollama", "anthropic"). Implementations must be safe for
 // concurrent use.
 type TokenObserver interface {
-	OnTokens(inputTokens, outputTokens int)
+	OnTokens(provider string, inputTokens, outputTokens int)
 }
 
 // Server is the HTTP API server.
@@ -79,6 +101,8 @@ type Server struct {
 	memoryStore                        *memory.SQLiteStore
 	archiveStore                       *memory.ArchiveStore
 	healthDeps                         HealthStatusFunc
+	webCacheStats                      WebCacheStatsFunc
+	databaseStats                      DatabaseStatsFunc
 	tokenObserver                      TokenObserver
 	eventBus                           *events.Bus
 	owuTracker                         *OWUTracker
@@ -105,7 +129,11 @@ type Server struct {
 	reconcileLoopDefinition            func(context.Context, string) error
 	launchLoopDefinition               func(context.Context, string, looppkg.Launch) (looppkg.LaunchResult, error)
 	launchChatLoop                     func(context.Context, looppkg.Launch) (looppkg.LaunchResult, error)
+	replayDelegate                     func(context.Context, string, string) (*delegate.ReplayResult, error)
+	metacognitiveJournal               func(limit int) metacognitive.JournalReport
 	anthropicRateLimitSnapshot         func() *fleet.AnthropicRateLimitSnapshot
+	adminToken                         string
+	sensorWebhook                      *sensorWebhook
 	logger                             *slog.Logger
 	server                             *http.Server
 	stats                              *SessionStats
@@ -121,6 +149,18 @@ func (s *Server) SetConnManager(fn HealthStatusFunc) {
 	s.healthDeps = fn
 }
 
+// SetDatabaseStats sets the SQLite WAL statistics provider for the
+// /health endpoint.
+func (s *Server) SetDatabaseStats(fn DatabaseStatsFunc) {
+	s.databaseStats = fn
+}
+
+// SetWebCacheStats sets the web result cache statistics provider for
+// the /health endpoint.
+func (s *Server) SetWebCacheStats(fn WebCacheStatsFunc) {
+	s.webCacheStats = fn
+}
+
 // ConfigureAnthropicRateLimitSnapshotSource configures the provider for the
 // latest Anthropic rate-limit snapshot included in router stats.
 func (s *Server) ConfigureAnthropicRateLimitSnapshotSource(fn func() *fleet.AnthropicRateLimitSnapshot) {
@@ -288,10 +328,18 @@ func (s *Server) recordUsage(model string, inputTokens, outputTokens, cacheCreat
 	identity := usage.ResolveModelIdentity(model, cat)
 	s.stats.Record(identity, inputTokens, outputTokens, cacheCreationInputTokens, cacheReadInputTokens)
 	if s.tokenObserver != nil {
-		s.tokenObserver.OnTokens(inputTokens, outputTokens)
+		s.tokenObserver.OnTokens(identity.Provider, inputTokens, outputTokens)
 	}
 }
 
+// SetPricing replaces the pricing table used to cost requests recorded
+// from now on. Costs already accumulated are not recomputed.
+func (s *SessionStats) SetPricing(pricing map[string]config.PricingEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pricing = pricing
+}
+
 func (s *SessionStats) SetBalance(balance float64) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -433,6 +481,18 @@ func (s *Server) SetArchiveStore(as *memory.ArchiveStore) {
 	s.archiveStore = as
 }
 
+// SetAdminToken configures the bearer token required by destructive
+// endpoints. An empty token leaves those endpoints unauthenticated.
+func (s *Server) SetAdminToken(token string) {
+	s.adminToken = strings.TrimSpace(token)
+}
+
+// SetPricing replaces the pricing table used for session cost
+// estimates, e.g. after a config reload.
+func (s *Server) SetPricing(pricing map[string]config.PricingEntry) {
+	s.stats.SetPricing(pricing)
+}
+
 // Start begins serving HTTP requests.
 func (s *Server) Start(ctx context.Context) error {
 	mux := http.NewServeMux()
@@ -446,6 +506,9 @@ func (s *Server) Start(ctx context.Context) error {
 	mux.HandleFunc("GET /v1/system", s.handleSystem)
 	mux.HandleFunc("GET /v1/system/logs", s.handleSystemLogs)
 
+	// Live agent activity stream (SSE), read-only observability
+	mux.HandleFunc("GET /events", s.handleActivityEvents)
+
 	// Telemetry — consolidated router, tool, and usage analytics
 	mux.HandleFunc("GET /v1/telemetry/router", s.handleRouterTelemetry)
 	mux.HandleFunc("GET /v1/telemetry/tools", s.handleToolTelemetry)
@@ -488,6 +551,7 @@ func (s *Server) Start(ctx context.Context) error {
 	mux.HandleFunc("GET /v1/loops/events", s.handleLoopEvents)
 	mux.HandleFunc("GET /v1/loops/{id}", s.handleLoop)
 	mux.HandleFunc("GET /v1/loops/{id}/logs", s.handleLoopLogs)
+	mux.HandleFunc("GET /v1/metacognitive/journal", s.handleMetacognitiveJournal)
 
 	// Scheduler: durable scheduled tasks and their execution history,
 	// surfacing the previously internal-only scheduler subsystem.
@@ -505,6 +569,8 @@ func (s *Server) Start(ctx context.Context) error {
 	// History endpoints
 	mux.HandleFunc("GET /v1/conversations", s.handleConversationList)
 	mux.HandleFunc("GET /v1/conversations/{id}", s.handleConversationGet)
+	mux.HandleFunc("GET /v1/conversations/{id}/export", s.handleConversationExport)
+	mux.HandleFunc("DELETE /v1/conversations/{id}", s.handleConversationDelete)
 
 	// Session stats
 	mux.HandleFunc("GET /v1/sessions/stats", s.handleSessionStats)
@@ -517,10 +583,22 @@ func (s *Server) Start(ctx context.Context) error {
 	mux.HandleFunc("GET /v1/archive/sessions", s.handleArchiveSessions)
 	mux.HandleFunc("GET /v1/archive/sessions/{id}", s.handleArchiveSessionGet)
 	mux.HandleFunc("GET /v1/archive/sessions/{id}/export", s.handleArchiveSessionExport)
+	mux.HandleFunc("POST /v1/archive/sessions/{id}/replay", s.handleDelegateReplay)
 	mux.HandleFunc("GET /v1/archive/search", s.handleArchiveSearch)
 	mux.HandleFunc("GET /v1/archive/messages", s.handleArchiveMessages)
 	mux.HandleFunc("GET /v1/archive/stats", s.handleArchiveStats)
 
+	// Debug: last assembled system prompt per conversation
+	// (debug.prompt_endpoint).
+	mux.HandleFunc("GET /debug/prompt", s.handleDebugPrompt)
+
+	// Sensor webhook receiver for push-only data sources. The prefix
+	// is configurable (sensor_webhook.path), so the route is built
+	// rather than literal and is documented in docs/reference/api.md.
+	if s.sensorWebhook != nil {
+		mux.HandleFunc(s.sensorWebhook.route(), s.handleSensorWebhook)
+	}
+
 	// First-party realtime WebSocket. /v1/realtime/ws is the canonical
 	// path (per native.yaml); the legacy aliases for existing
 	// thane-agent-macos installs are wired from the legacyroute registry
@@ -619,6 +697,27 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
+	// Spend against the router budget, for alerting. Reaching a
+	// ceiling is a routing policy, not a health problem, so it does not
+	// degrade the status.
+	if s.router != nil {
+		if budget, ok := s.router.BudgetStatus(); ok {
+			health["budget"] = budget
+		}
+	}
+	// WAL sizes are informational too: a growing WAL is worth a look,
+	// not an outage.
+	if s.databaseStats != nil {
+		if stats := s.databaseStats(); len(stats) > 0 {
+			health["databases"] = stats
+		}
+	}
+	// Cache effectiveness is informational; it never degrades status.
+	if s.webCacheStats != nil {
+		if stats := s.webCacheStats(); len(stats) > 0 {
+			health["web_cache"] = stats
+		}
+	}
 	writeJSON(w, health, s.logger)
 }
 
@@ -892,6 +991,7 @@ func (s *Server) handleStreamingCompletion(w http.ResponseWriter, r *http.Reques
 	completionID := fmt.Sprintf("chatcmpl-%d", time.Now().UnixNano())
 	created := time.Now().Unix()
 	modelName := "thane" // Will be updated when we get the response
+	agentReq.ClientStream = true
 	var writeMu sync.Mutex
 
 	// Send initial chunk with role
@@ -936,7 +1036,7 @@ func (s *Server) handleStreamingCompletion(w http.ResponseWriter, r *http.Reques
 			flusher.Flush()
 			writeMu.Unlock()
 
-		case agent.KindToolCallStart, agent.KindToolCallDone:
+		case agent.KindToolCallStart, agent.KindToolCallProgress, agent.KindToolCallDone, agent.KindHeartbeat:
 			// Send SSE comment as keepalive to prevent write timeout
 			writeMu.Lock()
 			fmt.Fprintf(w, ": keepalive\n\n")
@@ -1017,6 +1117,23 @@ func (s *Server) errorResponse(w http.ResponseWriter, code int, message string)
 	}, s.logger)
 }
 
+// requireAdmin enforces the configured admin token on destructive
+// endpoints. It writes a 401 and returns false when a token is
+// configured and the request does not present it as a bearer
+// credential; with no token configured every request passes.
+func (s *Server) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if s.adminToken == "" {
+		return true
+	}
+	presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if ok && subtle.ConstantTimeCompare([]byte(strings.TrimSpace(presented)), []byte(s.adminToken)) == 1 {
+		return true
+	}
+	w.Header().Set("WWW-Authenticate", `Bearer realm="thane"`)
+	s.errorResponse(w, http.StatusUnauthorized, "admin token required")
+	return false
+}
+
 // Router introspection handlers
 
 type routerStatsResponse struct {
@@ -1458,6 +1575,9 @@ func (s *Server) handleCheckpointDelete(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleCheckpointRestore refuses: a restore rewrites the stores this
+// server is using, so it only runs offline through
+// `thane checkpoint restore` with the server stopped.
 func (s *Server) handleCheckpointRestore(w http.ResponseWriter, r *http.Request) {
 	if s.checkpointer == nil {
 		s.errorResponse(w, http.StatusServiceUnavailable, "checkpointing not configured")
@@ -1465,24 +1585,13 @@ func (s *Server) handleCheckpointRestore(w http.ResponseWriter, r *http.Request)
 	}
 
 	idStr := r.PathValue("id")
-	id, err := uuid.Parse(idStr)
-	if err != nil {
+	if _, err := uuid.Parse(idStr); err != nil {
 		s.errorResponse(w, http.StatusBadRequest, "invalid checkpoint id")
 		return
 	}
 
-	if err := s.checkpointer.Restore(id); err != nil {
-		s.logger.Error("checkpoint restore failed", "error", err, "id", idStr)
-		s.errorResponse(w, http.StatusInternalServerError, "failed to restore checkpoint")
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	writeJSON(w, map[string]any{
-		"status":  "restored",
-		"id":      idStr,
-		"message": "checkpoint restored successfully",
-	}, s.logger)
+	s.errorResponse(w, http.StatusConflict,
+		"cannot restore a checkpoint while the server is running; stop it and run `thane checkpoint restore "+idStr+"`")
 }
 
 // History endpoints
@@ -1693,7 +1802,7 @@ func (s *Server) handleArchiveSessionExport(w http.ResponseWriter, r *http.Reque
 
 	switch format {
 	case "markdown", "md":
-		md, err := s.archiveStore.ExportSessionMarkdown(id)
+		md, err := s.archiveStore.ExportSessionMarkdown(id, memory.MarkdownExportOptions{})
 		if err != nil {
 			s.errorResponse(w, http.StatusInternalServerError, "export: "+err.Error())
 			return
@@ -146,11 +146,17 @@ func harnessLoops() []looppkg.Status {
 // system status reads "degraded" and the mqtt loop renders in its degraded
 // styling (the graph matches loop names against service keys).
 func harnessHealth() map[string]DependencyStatus {
-	stamp := time.Now().Format(time.RFC3339)
+	now := time.Now()
+	stamp := now.Format(time.RFC3339)
+	full, flapping := 1.0, 0.35
 	return map[string]DependencyStatus{
-		"signal":         {Name: "signal", Ready: true, LastCheck: stamp},
-		"home_assistant": {Name: "home_assistant", Ready: true, LastCheck: stamp},
-		"mqtt":           {Name: "mqtt", Ready: false, LastCheck: stamp, LastError: "broker unreachable"},
+		"signal": {Name: "signal", Ready: true, LastCheck: stamp, LastSuccess: stamp,
+			LastProbeMs: 12, RecentProbes: 20, SuccessRate: &full},
+		"home_assistant": {Name: "home_assistant", Ready: true, LastCheck: stamp, LastSuccess: stamp,
+			LastProbeMs: 48, RecentProbes: 20, SuccessRate: &full},
+		"mqtt": {Name: "mqtt", Ready: false, LastCheck: stamp, LastError: "broker unreachable",
+			LastSuccess: now.Add(-3 * time.Minute).Format(time.RFC3339),
+			LastProbeMs: 10000, RecentProbes: 20, SuccessRate: &flapping},
 	}
 }
 
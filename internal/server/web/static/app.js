@@ -4124,7 +4124,9 @@ function renderLoopGlanceHeader(loop) {
   if (loop.state === 'processing') {
     const running = (loop._liveTools || []).find((t) => !t.status || t.status === 'running');
     let label = '';
-    if (running && running.tool) label = 'waiting on ' + running.tool;
+    if (running && running.tool) {
+      label = 'waiting on ' + running.tool + (running.progress ? ' · ' + running.progress : '');
+    }
     else if (loop._liveModel) label = 'thinking on ' + shortModelName(loop._liveModel);
     if (label) {
       const phase = document.createElement('div');
@@ -4366,7 +4368,7 @@ function renderLiveToolFeed(loop) {
     th.appendChild(name);
     const status = document.createElement('span');
     status.className = 'live-tool__status';
-    status.textContent = t.error ? 'error' : (t.status || 'done');
+    status.textContent = t.error ? 'error' : (t.status === 'running' && t.progress ? t.progress : (t.status || 'done'));
     th.appendChild(status);
     card.appendChild(th);
     const args = formatToolPayload(t.args);
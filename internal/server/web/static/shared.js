@@ -1637,6 +1637,17 @@ function applyLoopEventToLoop(evt, ctx) {
       loop._liveTools.push({ tool: d.tool, status: 'running', args: d.args || null });
       return null;
 
+    case 'loop_tool_progress':
+      if (loop._liveTools) {
+        for (let i = loop._liveTools.length - 1; i >= 0; i--) {
+          if (loop._liveTools[i].tool === d.tool && loop._liveTools[i].status === 'running') {
+            loop._liveTools[i].progress = d.message || null;
+            break;
+          }
+        }
+      }
+      return null;
+
     case 'loop_tool_done':
       if (loop._liveTools) {
         for (let i = loop._liveTools.length - 1; i >= 0; i--) {
@@ -366,6 +366,8 @@ type subscriptionOptionsWire struct {
 	TransitionsWindowSeconds int                                   `json:"transitions_window_seconds,omitempty"`
 	Wake                     bool                                  `json:"wake,omitempty"`
 	WakeDebounceSeconds      int                                   `json:"wake_debounce_seconds,omitempty"`
+	WakeWhen                 *looppkg.WakeCondition                `json:"wake_when,omitempty"`
+	WakeCooldownSeconds      int                                   `json:"wake_cooldown_seconds,omitempty"`
 }
 
 func marshalSubscriptionOptions(sub looppkg.EntitySubscription) ([]byte, error) {
@@ -386,6 +388,9 @@ func marshalSubscriptionOptions(sub looppkg.EntitySubscription) ([]byte, error)
 	if sub.WakeDebounceSeconds < 0 {
 		return nil, fmt.Errorf("wake_debounce_seconds must be >= 0, got %d", sub.WakeDebounceSeconds)
 	}
+	if err := sub.ValidateWakeCondition(); err != nil {
+		return nil, err
+	}
 	wire := subscriptionOptionsWire{
 		History:                  append([]int(nil), sub.History...),
 		Forecast:                 forecast,
@@ -398,6 +403,8 @@ func marshalSubscriptionOptions(sub looppkg.EntitySubscription) ([]byte, error)
 		TransitionsWindowSeconds: sub.TransitionsWindowSeconds,
 		Wake:                     sub.Wake,
 		WakeDebounceSeconds:      sub.WakeDebounceSeconds,
+		WakeWhen:                 sub.WakeWhen.Clone(),
+		WakeCooldownSeconds:      sub.WakeCooldownSeconds,
 	}
 	if wire.Include != nil && !wire.Include.Any() {
 		wire.Include = nil
@@ -427,6 +434,14 @@ func parseSubscriptionOptions(optsJSON string, addedAt time.Time) looppkg.Entity
 	if wire.WakeDebounceSeconds > 0 {
 		sub.WakeDebounceSeconds = wire.WakeDebounceSeconds
 	}
+	// A malformed condition degrades to wake-on-any-change rather
+	// than dropping the wake, matching the tolerant read posture.
+	if wire.WakeWhen != nil && wire.WakeWhen.Validate() == nil {
+		sub.WakeWhen = wire.WakeWhen
+		if wire.WakeCooldownSeconds > 0 {
+			sub.WakeCooldownSeconds = wire.WakeCooldownSeconds
+		}
+	}
 	if wire.Transitions > 0 {
 		sub.Transitions = wire.Transitions
 	}
@@ -160,6 +160,20 @@ func (w *WatchlistTools) Tools() []*tools.Tool {
 						"type":        "integer",
 						"description": "How long this subscription's changes coalesce before waking its loop (default a few seconds). A loop's effective cadence follows its twitchiest wake subscription — one wake drains everything pending.",
 					},
+					"wake_when": map[string]any{
+						"type":        "object",
+						"description": "Turn the wake into an alert: the loop wakes only when a change takes the entity INTO this condition, not on every change and not again while it stays true. Set state (matches the raw or class-aware state, e.g. \"open\"), or above and/or below for numeric thresholds (both together mean the band between them). Add from to state for one specific transition (from \"off\" to \"on\", not \"unavailable\" to \"on\"), or set from alone to alert when the entity leaves that state. Implies wake.",
+						"properties": map[string]any{
+							"state": map[string]any{"type": "string", "description": "Alert when the state becomes this value."},
+							"from":  map[string]any{"type": "string", "description": "Require the previous state to be this value. Not combinable with above/below."},
+							"above": map[string]any{"type": "number", "description": "Alert when a numeric state rises above this value."},
+							"below": map[string]any{"type": "number", "description": "Alert when a numeric state falls below this value."},
+						},
+					},
+					"wake_cooldown_seconds": map[string]any{
+						"type":        "integer",
+						"description": "Minimum seconds between two wake_when alerts for the same entity, so a value flapping across a threshold alerts once (default 600).",
+					},
 					"include": tools.EntityMetadataIncludeParameter(),
 				},
 				"required": []string{"entity_id"},
@@ -283,6 +297,17 @@ func (w *WatchlistTools) handleAddEntitySubscription(ctx context.Context, args m
 	if wakeDebounce < 0 {
 		return "", fmt.Errorf("wake_debounce_seconds must be >= 0")
 	}
+	wakeWhen, err := parseWakeWhenArg(args["wake_when"])
+	if err != nil {
+		return "", err
+	}
+	if wakeWhen != nil {
+		wake = true
+	}
+	wakeCooldown, err := watchlistIntArg(args["wake_cooldown_seconds"], "wake_cooldown_seconds")
+	if err != nil {
+		return "", err
+	}
 
 	sub := looppkg.EntitySubscription{
 		EntityID:                 entityID,
@@ -298,6 +323,11 @@ func (w *WatchlistTools) handleAddEntitySubscription(ctx context.Context, args m
 		TransitionsWindowSeconds: transitionsWindow,
 		Wake:                     wake,
 		WakeDebounceSeconds:      wakeDebounce,
+		WakeWhen:                 wakeWhen,
+		WakeCooldownSeconds:      wakeCooldown,
+	}
+	if err := sub.ValidateWakeCondition(); err != nil {
+		return "", err
 	}
 
 	if sub.Wake {
@@ -400,7 +430,13 @@ func (w *WatchlistTools) handleAddEntitySubscription(ctx context.Context, args m
 	if selfOnly {
 		msg += " (self_only: not inherited by descendant loops)"
 	}
-	if wake {
+	if wakeWhen != nil {
+		cooldown := looppkg.DefaultWakeCooldown
+		if wakeCooldown > 0 {
+			cooldown = time.Duration(wakeCooldown) * time.Second
+		}
+		msg += fmt.Sprintf(" (wake alert: the loop wakes when it becomes %s, at most once per %s)", wakeWhen, cooldown)
+	} else if wake {
 		if wakeDebounce > 0 {
 			msg += fmt.Sprintf(" (wake: the loop wakes on change, coalesced over %ds)", wakeDebounce)
 		} else {
@@ -529,6 +565,12 @@ func (w *WatchlistTools) handleListEntitySubscriptions(ctx context.Context, args
 		if row.WakeDebounceSeconds > 0 {
 			item["wake_debounce_seconds"] = row.WakeDebounceSeconds
 		}
+		if row.WakeWhen != nil {
+			item["wake_when"] = row.WakeWhen
+		}
+		if row.WakeCooldownSeconds > 0 {
+			item["wake_cooldown_seconds"] = row.WakeCooldownSeconds
+		}
 		if row.TTLSeconds > 0 && !row.AddedAt.IsZero() {
 			expiresAt := row.AddedAt.Add(time.Duration(row.TTLSeconds) * time.Second)
 			item["expires_delta"] = promptfmt.FormatDeltaOnly(expiresAt, now)
@@ -688,6 +730,51 @@ func parseWatchlistHistoryArg(raw any) ([]int, error) {
 	return history, nil
 }
 
+// parseWakeWhenArg parses the add_entity_subscription wake_when
+// object. Absent means no condition.
+func parseWakeWhenArg(raw any) (*looppkg.WakeCondition, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	obj, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("wake_when must be an object with state, from, above, or below, got %T", raw)
+	}
+	var cond looppkg.WakeCondition
+	for _, field := range []struct {
+		name string
+		dst  *string
+	}{{"state", &cond.State}, {"from", &cond.From}} {
+		v, present := obj[field.name]
+		if !present {
+			continue
+		}
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("wake_when.%s must be a string, got %T", field.name, v)
+		}
+		*field.dst = strings.TrimSpace(s)
+	}
+	for _, field := range []struct {
+		name string
+		dst  **float64
+	}{{"above", &cond.Above}, {"below", &cond.Below}} {
+		v, present := obj[field.name]
+		if !present || v == nil {
+			continue
+		}
+		n, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("wake_when.%s must be a number, got %T", field.name, v)
+		}
+		*field.dst = &n
+	}
+	if err := cond.Validate(); err != nil {
+		return nil, err
+	}
+	return &cond, nil
+}
+
 func watchlistIntArg(raw any, field string) (int, error) {
 	switch v := raw.(type) {
 	case nil:
@@ -871,3 +871,64 @@ func TestAddEntitySubscription_WakeOptions(t *testing.T) {
 		t.Fatalf("IngestGlobs = %v, want wake-derived entry", globs)
 	}
 }
+
+// TestAddEntitySubscription_WakeWhen covers alert subscriptions:
+// wake_when implies wake, malformed conditions teach, and the
+// condition and cooldown round-trip through the store into the list.
+func TestAddEntitySubscription_WakeWhen(t *testing.T) {
+	p, store, mutator := setupWatchlistProvider(t)
+
+	for _, tc := range []struct {
+		args map[string]any
+		want string
+	}{
+		{map[string]any{"wake_when": "hot"}, "must be an object"},
+		{map[string]any{"wake_when": map[string]any{}}, "wake_when needs"},
+		{map[string]any{"wake_when": map[string]any{"above": "30"}}, "must be a number"},
+		{map[string]any{"wake_when": map[string]any{"above": 50.0, "below": 10.0}}, "must be less than below"},
+		{map[string]any{"wake_cooldown_seconds": 60}, "wake_when"},
+	} {
+		args := map[string]any{"entity_id": "sensor.freezer_temp", "owner": "watcher"}
+		for k, v := range tc.args {
+			args[k] = v
+		}
+		if _, err := p.handleAddEntitySubscription(context.Background(), args); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("args %v: error = %v, want %q", tc.args, err, tc.want)
+		}
+	}
+
+	result, err := p.handleAddEntitySubscription(context.Background(), map[string]any{
+		"entity_id":             "sensor.freezer_temp",
+		"owner":                 "watcher",
+		"wake_when":             map[string]any{"above": 30.0},
+		"wake_cooldown_seconds": 900,
+	})
+	if err != nil {
+		t.Fatalf("alert add: %v", err)
+	}
+	if !strings.Contains(result, "above 30") || !strings.Contains(result, "15m0s") {
+		t.Fatalf("result = %q, want condition and cooldown acknowledged", result)
+	}
+	subs := mutator.subs["watcher"]
+	if len(subs) != 1 || !subs[0].Wake || subs[0].WakeWhen == nil || subs[0].WakeCooldownSeconds != 900 {
+		t.Fatalf("loop subs = %+v, want alert options on the spec", subs)
+	}
+
+	if err := store.Upsert("watcher", subs[0]); err != nil {
+		t.Fatalf("mirror upsert: %v", err)
+	}
+	rows, err := store.ListAll()
+	if err != nil {
+		t.Fatalf("ListAll: %v", err)
+	}
+	if len(rows) != 1 || rows[0].WakeWhen == nil || rows[0].WakeWhen.Above == nil || *rows[0].WakeWhen.Above != 30 {
+		t.Fatalf("stored rows = %+v, want the condition persisted", rows)
+	}
+	raw, err := p.handleListEntitySubscriptions(context.Background(), map[string]any{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if !strings.Contains(raw, `"wake_when":{"above":30}`) || !strings.Contains(raw, `"wake_cooldown_seconds":900`) {
+		t.Fatalf("list = %s, want alert options surfaced", raw)
+	}
+}
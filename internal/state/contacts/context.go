@@ -83,13 +83,21 @@ func (p *ContextProvider) TagContext(ctx context.Context, req agentctx.ContextRe
 			})
 		}
 
+		var duplicates []string
+		if dups, err := p.store.FindDuplicates(c.ID); err == nil {
+			for _, d := range dups {
+				duplicates = append(duplicates, d.FormattedName)
+			}
+		}
+
 		matches = append(matches, contextfmt.Match{
-			Name:       c.FormattedName,
-			Org:        c.Org,
-			Summary:    c.AISummary,
-			TrustZone:  c.TrustZone,
-			Score:      scores[i],
-			Properties: viewProps,
+			Name:               c.FormattedName,
+			Org:                c.Org,
+			Summary:            c.AISummary,
+			TrustZone:          c.TrustZone,
+			Score:              scores[i],
+			Properties:         viewProps,
+			PossibleDuplicates: duplicates,
 		})
 	}
 
@@ -17,18 +17,27 @@ import (
 	"github.com/nugget/thane-ai-agent/internal/model/promptfmt"
 )
 
+// duplicateHint follows the payload when a match has possible
+// duplicates.
+const duplicateHint = "Contacts with possible_duplicates share a phone number or email with another record. " +
+	"If they are the same person, offer to merge them with contact_merge."
+
 // Match is one contact ready for rendering: contact-card fields plus the
 // similarity score that selected it and any structured properties to
 // surface. Score is a similarity in [0, 1]; the renderer emits it as a
 // float so the model can sort or threshold without re-deriving it from
 // prose.
+//
+// PossibleDuplicates names other contacts that share a phone number or
+// email with this one, so the model can propose merging them.
 type Match struct {
-	Name       string     `json:"name"`
-	Org        string     `json:"org,omitempty"`
-	Summary    string     `json:"summary,omitempty"`
-	TrustZone  string     `json:"trust_zone,omitempty"`
-	Score      float32    `json:"score"`
-	Properties []Property `json:"properties,omitempty"`
+	Name               string     `json:"name"`
+	Org                string     `json:"org,omitempty"`
+	Summary            string     `json:"summary,omitempty"`
+	TrustZone          string     `json:"trust_zone,omitempty"`
+	Score              float32    `json:"score"`
+	Properties         []Property `json:"properties,omitempty"`
+	PossibleDuplicates []string   `json:"possible_duplicates,omitempty"`
 }
 
 // Property is one structured property of a contact. Kind is the property
@@ -45,7 +54,8 @@ type Property struct {
 // suitable for the system prompt. Returns "" when no matches are given,
 // so callers can decide whether to emit anything without parsing the
 // JSON envelope. The heading is markdown (a section boundary); the
-// payload is JSON (typed runtime data).
+// payload is JSON (typed runtime data). When any match has possible
+// duplicates, a one-line merge suggestion follows the payload.
 func Format(matches []Match) string {
 	if len(matches) == 0 {
 		return ""
@@ -57,5 +67,11 @@ func Format(matches []Match) string {
 	var sb strings.Builder
 	sb.WriteString("### Relevant Contacts\n\n")
 	sb.WriteString(promptfmt.MarshalCompact(envelope))
+	for _, m := range matches {
+		if len(m.PossibleDuplicates) > 0 {
+			sb.WriteString("\n\n" + duplicateHint)
+			break
+		}
+	}
 	return sb.String()
 }
@@ -0,0 +1,158 @@
+package contacts
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Merge collapses the contact mergeID into keepID and returns the kept
+// contact. In one transaction it:
+//
+//   - moves the merged contact's properties onto the kept one, dropping
+//     any the kept contact already has (case-insensitive on value);
+//   - fills the kept contact's empty fields from the merged one, keeps
+//     the more privileged of the two trust zones, and the most recent
+//     interaction, and the merged contact's Home Assistant person link
+//     when the kept contact has none;
+//   - appends the merged contact's AI summary to the kept one, and
+//     records its name as the kept contact's nickname when that is
+//     unset, so the old name keeps resolving;
+//   - clears both embeddings, so semantic search can neither return the
+//     merged record nor match the kept one on stale text (callers with
+//     an embedding client regenerate the kept contact's); and
+//   - soft-deletes the merged contact.
+func (s *Store) Merge(keepID, mergeID uuid.UUID) (*Contact, error) {
+	if keepID == mergeID {
+		return nil, fmt.Errorf("cannot merge a contact into itself")
+	}
+	keep, err := s.Get(keepID)
+	if err != nil {
+		return nil, fmt.Errorf("get kept contact %s: %w", keepID, err)
+	}
+	merged, err := s.Get(mergeID)
+	if err != nil {
+		return nil, fmt.Errorf("get merged contact %s: %w", mergeID, err)
+	}
+
+	mergeContactFields(keep, merged)
+	if zoneRank(merged.TrustZone) < zoneRank(keep.TrustZone) {
+		keep.TrustZone = merged.TrustZone
+	}
+	keep.AISummary = mergeSummaries(keep.AISummary, merged.AISummary)
+	if keep.Nickname == "" && !strings.EqualFold(merged.FormattedName, keep.FormattedName) {
+		keep.Nickname = merged.FormattedName
+	}
+	if merged.LastInteraction.After(keep.LastInteraction) {
+		keep.LastInteraction = merged.LastInteraction
+		keep.LastInteractionMeta = merged.LastInteractionMeta
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // best-effort on defer
+
+	now := time.Now().UTC()
+	nowStr := now.Format(time.RFC3339)
+
+	if _, err := tx.Exec(`
+		DELETE FROM contact_properties
+		WHERE contact_id = ?
+		  AND EXISTS (
+			SELECT 1 FROM contact_properties k
+			WHERE k.contact_id = ?
+			  AND k.property = contact_properties.property
+			  AND LOWER(k.value) = LOWER(contact_properties.value)
+		  )
+	`, mergeID.String(), keepID.String()); err != nil {
+		return nil, fmt.Errorf("drop duplicate properties: %w", err)
+	}
+	if _, err := tx.Exec(
+		`UPDATE contact_properties SET contact_id = ?, updated_at = ? WHERE contact_id = ?`,
+		keepID.String(), nowStr, mergeID.String()); err != nil {
+		return nil, fmt.Errorf("move properties: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE contacts SET ha_person_entity = (SELECT ha_person_entity FROM contacts WHERE id = ?)
+		WHERE id = ? AND ha_person_entity IS NULL
+	`, mergeID.String(), keepID.String()); err != nil {
+		return nil, fmt.Errorf("move person link: %w", err)
+	}
+
+	// Soft-delete the merged contact and drop its embedding and link.
+	if _, err := tx.Exec(
+		`UPDATE contacts SET deleted_at = ?, embedding = NULL, ha_person_entity = NULL WHERE id = ?`,
+		nowStr, mergeID.String()); err != nil {
+		return nil, fmt.Errorf("delete merged contact: %w", err)
+	}
+
+	keep.Rev = nowStr
+	if err := updateContactRow(tx, keep, now); err != nil {
+		return nil, fmt.Errorf("update kept contact: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE contacts SET embedding = NULL WHERE id = ?`, keepID.String()); err != nil {
+		return nil, fmt.Errorf("clear embedding: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	s.rebuildFTS()
+
+	return s.GetWithProperties(keepID)
+}
+
+// FindDuplicates returns other active contacts that share a phone
+// number or email address with the given contact — likely candidates
+// for [Store.Merge]. Those two identify a person well enough that a
+// shared value usually means a shared person.
+func (s *Store) FindDuplicates(contactID uuid.UUID) ([]*Contact, error) {
+	rows, err := s.db.Query(`
+		SELECT DISTINCT `+qualifiedContactColumns+`
+		FROM contact_properties mine
+		JOIN contact_properties theirs
+		  ON theirs.property = mine.property
+		 AND LOWER(theirs.value) = LOWER(mine.value)
+		 AND theirs.contact_id != mine.contact_id
+		JOIN contacts ON contacts.id = theirs.contact_id
+		WHERE mine.contact_id = ?
+		  AND mine.property IN ('TEL', 'EMAIL')
+		  AND contacts.`+activeFilter+`
+		ORDER BY contacts.formatted_name
+	`, contactID.String())
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanContacts(rows)
+}
+
+// zoneRank returns a trust zone's position in [zoneHierarchy]; lower
+// is more privileged. Unrecognized zones rank below all others.
+func zoneRank(zone string) int {
+	for i, z := range zoneHierarchy {
+		if z == zone {
+			return i
+		}
+	}
+	return len(zoneHierarchy)
+}
+
+// mergeSummaries combines two AI summaries, dropping the second when
+// the first already contains it.
+func mergeSummaries(keep, merged string) string {
+	switch {
+	case merged == "" || strings.Contains(keep, merged):
+		return keep
+	case keep == "":
+		return merged
+	default:
+		return keep + " " + merged
+	}
+}
@@ -0,0 +1,215 @@
+package contacts
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/agentctx"
+)
+
+func TestMerge(t *testing.T) {
+	store := newTestStore(t)
+
+	keep, err := store.Upsert(&Contact{
+		FormattedName: "Robert Smith",
+		TrustZone:     ZoneKnown,
+		AISummary:     "Neighbor two doors down.",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = store.AddProperty(keep.ID, &Property{Property: "EMAIL", Value: "bob@example.com"})
+	_ = store.SetEmbedding(keep.ID, []float32{1, 0, 0})
+
+	dup, err := store.Upsert(&Contact{
+		FormattedName:   "Bob",
+		TrustZone:       ZoneTrusted,
+		Org:             "Acme",
+		AISummary:       "Fixes bikes.",
+		LastInteraction: time.Now().UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = store.AddProperty(dup.ID, &Property{Property: "EMAIL", Value: "BOB@example.com"})
+	_ = store.AddProperty(dup.ID, &Property{Property: "TEL", Value: "+15125550100"})
+	_ = store.SetEmbedding(dup.ID, []float32{0, 1, 0})
+
+	merged, err := store.Merge(keep.ID, dup.ID)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	if merged.TrustZone != ZoneTrusted {
+		t.Errorf("TrustZone = %q, want the higher %q", merged.TrustZone, ZoneTrusted)
+	}
+	if merged.Org != "Acme" {
+		t.Errorf("Org = %q, want filled from duplicate", merged.Org)
+	}
+	if merged.AISummary != "Neighbor two doors down. Fixes bikes." {
+		t.Errorf("AISummary = %q", merged.AISummary)
+	}
+	if merged.Nickname != "Bob" {
+		t.Errorf("Nickname = %q, want the duplicate's name", merged.Nickname)
+	}
+	if !merged.LastInteraction.Equal(dup.LastInteraction) {
+		t.Errorf("LastInteraction = %v, want %v", merged.LastInteraction, dup.LastInteraction)
+	}
+
+	counts := map[string]int{}
+	for _, p := range merged.Properties {
+		counts[p.Property]++
+	}
+	if counts["EMAIL"] != 1 || counts["TEL"] != 1 {
+		t.Errorf("properties = %v, want one EMAIL and one TEL", counts)
+	}
+
+	if _, err := store.Get(dup.ID); err == nil {
+		t.Error("merged contact still active")
+	}
+	if c, err := store.ResolveContact("Bob"); err != nil || c.ID != keep.ID {
+		t.Errorf("ResolveContact(Bob) = %v, %v; want the kept contact", c, err)
+	}
+	if matches, err := store.FindByPropertyExact("TEL", "+15125550100"); err != nil || len(matches) != 1 || matches[0].ID != keep.ID {
+		t.Errorf("TEL now resolves to %v, %v; want the kept contact", matches, err)
+	}
+
+	// Neither record is left for semantic search to return.
+	found, _, err := store.SemanticSearch([]float32{0, 1, 0}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 0 {
+		t.Errorf("SemanticSearch returned %d contacts, want none until re-embedded", len(found))
+	}
+	missing, err := store.GetContactsWithoutEmbeddings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 1 || missing[0].ID != keep.ID {
+		t.Errorf("contacts needing embeddings = %v, want only the kept contact", missing)
+	}
+}
+
+func TestMerge_Errors(t *testing.T) {
+	store := newTestStore(t)
+
+	c, err := store.Upsert(&Contact{FormattedName: "Solo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Merge(c.ID, c.ID); err == nil {
+		t.Error("expected error merging a contact into itself")
+	}
+
+	other, err := store.Upsert(&Contact{FormattedName: "Gone"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(other.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Merge(c.ID, other.ID); err == nil {
+		t.Error("expected error merging a deleted contact")
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	store := newTestStore(t)
+
+	a, _ := store.Upsert(&Contact{FormattedName: "Alice A"})
+	b, _ := store.Upsert(&Contact{FormattedName: "Alice B"})
+	c, _ := store.Upsert(&Contact{FormattedName: "Carol"})
+	_ = store.AddProperty(a.ID, &Property{Property: "TEL", Value: "+15125550100"})
+	_ = store.AddProperty(b.ID, &Property{Property: "TEL", Value: "+15125550100"})
+	_ = store.AddProperty(a.ID, &Property{Property: "timezone", Value: "America/Chicago"})
+	_ = store.AddProperty(c.ID, &Property{Property: "timezone", Value: "America/Chicago"})
+
+	dups, err := store.FindDuplicates(a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dups) != 1 || dups[0].ID != b.ID {
+		t.Errorf("FindDuplicates = %v, want only Alice B (timezone is not identifying)", dups)
+	}
+}
+
+func TestMergeContactsTool(t *testing.T) {
+	tools := newTestTools(t)
+
+	if _, err := tools.SaveContact(`{"name":"Robert Smith","facts":{"email":"bob@example.com"}}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tools.SaveContact(`{"name":"Bob","trust_zone":"household","facts":{"phone":"+15125550100"}}`); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tools.MergeContacts(`{"keep":"Robert Smith"}`); err == nil {
+		t.Error("expected error without merge")
+	}
+	if _, err := tools.MergeContacts(`{"keep":"Robert Smith","merge":"Nobody"}`); err == nil {
+		t.Error("expected error for unknown contact")
+	}
+
+	out, err := tools.MergeContacts(`{"keep":"Robert Smith","merge":"Bob"}`)
+	if err != nil {
+		t.Fatalf("MergeContacts: %v", err)
+	}
+	for _, want := range []string{"Merged Bob into Robert Smith", "raised from known to household", "+15125550100"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestContextProvider_SuggestsDuplicates(t *testing.T) {
+	store := newTestStore(t)
+
+	a, _ := store.Upsert(&Contact{FormattedName: "Robert Smith"})
+	b, _ := store.Upsert(&Contact{FormattedName: "Bob"})
+	_ = store.SetEmbedding(a.ID, []float32{1, 0, 0})
+	_ = store.AddProperty(a.ID, &Property{Property: "EMAIL", Value: "bob@example.com"})
+	_ = store.AddProperty(b.ID, &Property{Property: "EMAIL", Value: "bob@example.com"})
+
+	cp := NewContextProvider(store, &fakeEmbedder{embedding: []float32{1, 0, 0}})
+	out, err := cp.TagContext(context.Background(), agentctx.ContextRequest{UserMessage: "what about Robert?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, `"possible_duplicates":["Bob"]`) {
+		t.Errorf("context missing duplicate suggestion:\n%s", out)
+	}
+	if !strings.Contains(out, "contact_merge") {
+		t.Errorf("context missing merge hint:\n%s", out)
+	}
+}
+
+func TestMerge_MovesPersonLink(t *testing.T) {
+	store := newTestStore(t)
+
+	keep, err := store.Upsert(&Contact{FormattedName: "Robert Smith"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dup, err := store.Upsert(&Contact{FormattedName: "Bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.LinkPersonEntity(dup.ID, "person.bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Merge(keep.ID, dup.ID); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	got, err := store.ContactsForPersonEntities([]string{"person.bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["person.bob"] != keep.ID {
+		t.Errorf("person.bob links to %v, want kept contact %s", got["person.bob"], keep.ID)
+	}
+}
@@ -19,29 +19,35 @@ import (
 // person in context output. Richer than the default entity JSON
 // because the tracker has room data from UniFi AP associations.
 type PersonPresenceContext struct {
-	Entity string `json:"entity"`
-	Name   string `json:"name"`
-	State  string `json:"state"`
-	Since  string `json:"since"`
-	Room   string `json:"room,omitempty"`
-	RoomSr string `json:"room_source,omitempty"`
+	Entity      string `json:"entity"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	Since       string `json:"since"`
+	Room        string `json:"room,omitempty"`
+	RoomSr      string `json:"room_source,omitempty"`
+	RoomSince   string `json:"room_since,omitempty"`
+	LastArrival string `json:"last_arrival,omitempty"`
 }
 
 // FormatPersonPresence formats a tracked person as compact JSON with
-// delta-annotated timestamps.
-func FormatPersonPresence(entityID, name, state string, since time.Time, room, roomSource string, now time.Time) string {
-	displayState := state
-	if strings.EqualFold(state, "not_home") {
-		displayState = "away"
-	}
+// delta-annotated timestamps. Since is how long the person has been
+// in their current state; roomSince and lastArrival are omitted when
+// zero.
+func FormatPersonPresence(entityID, name, state string, since time.Time, room, roomSource string, roomSince, lastArrival, now time.Time) string {
 	pc := PersonPresenceContext{
 		Entity: entityID,
 		Name:   name,
-		State:  displayState,
+		State:  displayPresenceState(state),
 		Since:  promptfmt.FormatDeltaOnly(since, now),
 		Room:   room,
 		RoomSr: roomSource,
 	}
+	if room != "" && !roomSince.IsZero() {
+		pc.RoomSince = promptfmt.FormatDeltaOnly(roomSince, now)
+	}
+	if !lastArrival.IsZero() {
+		pc.LastArrival = promptfmt.FormatDeltaOnly(lastArrival, now)
+	}
 	return promptfmt.MarshalCompact(pc)
 }
 
@@ -60,6 +66,23 @@ type Person struct {
 	RoomSource   string    // AP name that determined the room (e.g., "ap-hor-office")
 }
 
+// PresenceEvent is one entry in the tracker's presence log: the
+// person's state and room as of a state or room transition.
+type PresenceEvent struct {
+	EntityID string    `json:"entity"`
+	State    string    `json:"state"`
+	Room     string    `json:"room,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// presenceHistoryRetention is how far back the presence log reaches.
+// Older events are pruned as new ones arrive.
+const presenceHistoryRetention = 7 * 24 * time.Hour
+
+// maxPresenceEvents caps the presence log regardless of age, so a
+// flapping device tracker cannot grow it without bound.
+const maxPresenceEvents = 2000
+
 // StateGetter abstracts the Home Assistant REST client for fetching
 // entity state. Using an interface keeps the tracker testable without
 // a real HA instance.
@@ -83,6 +106,8 @@ type PresenceTracker struct {
 	people    map[string]*Person // entity_id → Person
 	order     []string           // insertion order for deterministic output
 	observers []RoomObserver     // called on room changes
+	history   []PresenceEvent    // oldest first, bounded by retention and count
+	primary   string             // primary HA instance name; see SetPrimaryInstance
 	mu        sync.RWMutex
 	loc       *time.Location
 	logger    *slog.Logger
@@ -173,8 +198,14 @@ func (t *PresenceTracker) Initialize(ctx context.Context, ha StateGetter) error
 		}
 
 		p := t.people[r.id]
+		changed := p.State != r.state.State
 		p.State = r.state.State
 		p.Since = r.state.LastChanged
+		if changed {
+			// Seed the log (or catch it up after a reconnect) with the
+			// transition HA last reported.
+			t.recordLocked(p, p.Since)
+		}
 
 		if name, ok := r.state.Attributes["friendly_name"].(string); ok && name != "" {
 			p.FriendlyName = name
@@ -191,6 +222,42 @@ func (t *PresenceTracker) Initialize(ctx context.Context, ha StateGetter) error
 	return firstErr
 }
 
+// SetPrimaryInstance names the primary Home Assistant instance, whose
+// entities the state watchers report by bare ID. A tracked or looked-up
+// ID qualified with that name ("home:person.alice") is normalized to
+// the bare form, so it matches the events and states the tracker
+// receives. IDs on other instances stay qualified. Call before
+// Initialize.
+func (t *PresenceTracker) SetPrimaryInstance(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.primary = name
+	people := make(map[string]*Person, len(t.people))
+	order := t.order[:0]
+	for _, id := range t.order {
+		canonical := t.canonicalLocked(id)
+		if _, dup := people[canonical]; dup {
+			continue
+		}
+		p := t.people[id]
+		p.EntityID = canonical
+		people[canonical] = p
+		order = append(order, canonical)
+	}
+	t.people = people
+	t.order = order
+}
+
+// canonicalLocked returns entityID with a primary-instance qualifier
+// removed. Callers hold t.mu.
+func (t *PresenceTracker) canonicalLocked(entityID string) string {
+	if instance, bare := homeassistant.SplitEntityID(entityID); instance != "" && instance == t.primary {
+		return bare
+	}
+	return entityID
+}
+
 // HandleStateChange updates the tracked person's state when a
 // state_changed event is received. It matches the
 // homeassistant.StateWatchHandler function signature; the old-state and
@@ -201,6 +268,7 @@ func (t *PresenceTracker) HandleStateChange(entityID, _, newState, _ string) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	entityID = t.canonicalLocked(entityID)
 	p, ok := t.people[entityID]
 	if !ok {
 		return
@@ -227,6 +295,7 @@ func (t *PresenceTracker) HandleStateChange(entityID, _, newState, _ string) {
 		p.RoomSince = time.Time{}
 		p.RoomSource = ""
 	}
+	t.recordLocked(p, p.Since)
 }
 
 // TagContext returns a formatted presence block for injection into the
@@ -257,9 +326,15 @@ func (t *PresenceTracker) TagContext(_ context.Context, _ agentctx.ContextReques
 		if p.State == "Unknown" || p.Since.IsZero() {
 			fmt.Fprintf(&sb, "- **%s**: unknown\n", displayName)
 		} else {
+			// Someone home has arrived at Since; for everyone else the
+			// last arrival says how long ago they were home.
+			var lastArrival time.Time
+			if !isHomeState(p.State) {
+				lastArrival = t.lastArrivalLocked(p)
+			}
 			sb.WriteString(FormatPersonPresence(
 				p.EntityID, displayName, p.State, p.Since,
-				p.Room, p.RoomSource, now,
+				p.Room, p.RoomSource, p.RoomSince, lastArrival, now,
 			))
 			sb.WriteByte('\n')
 		}
@@ -290,6 +365,7 @@ func (t *PresenceTracker) UpdateRoom(entityID, room, source string) {
 	var notify bool
 
 	t.mu.Lock()
+	entityID = t.canonicalLocked(entityID)
 	p, ok := t.people[entityID]
 	if !ok {
 		t.mu.Unlock()
@@ -311,11 +387,13 @@ func (t *PresenceTracker) UpdateRoom(entityID, room, source string) {
 
 	p.Room = room
 	p.RoomSource = source
+	now := time.Now()
 	if room != "" {
-		p.RoomSince = time.Now()
+		p.RoomSince = now
 	} else {
 		p.RoomSince = time.Time{}
 	}
+	t.recordLocked(p, now)
 
 	notify = len(t.observers) > 0
 	// Copy observer slice reference under lock. The slice is append-only
@@ -339,7 +417,7 @@ func (t *PresenceTracker) SetDeviceMACs(entityID string, macs []string) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	p, ok := t.people[entityID]
+	p, ok := t.people[t.canonicalLocked(entityID)]
 	if !ok {
 		return
 	}
@@ -360,6 +438,109 @@ func (t *PresenceTracker) EntityIDs() []string {
 	return ids
 }
 
+// DwellTime returns how long a tracked person has been in their
+// current state ("home", "not_home", or a zone). It returns zero for
+// untracked entities and people whose state is not yet known.
+func (t *PresenceTracker) DwellTime(entityID string) time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	p, ok := t.people[t.canonicalLocked(entityID)]
+	if !ok || p.State == "Unknown" || p.Since.IsZero() {
+		return 0
+	}
+	return time.Since(p.Since)
+}
+
+// LastArrival returns when a tracked person last arrived home: the
+// start of the current stay when they are home, otherwise the most
+// recent arrival in the presence log. It returns the zero time when
+// no arrival is known.
+func (t *PresenceTracker) LastArrival(entityID string) time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	p, ok := t.people[t.canonicalLocked(entityID)]
+	if !ok {
+		return time.Time{}
+	}
+	return t.lastArrivalLocked(p)
+}
+
+// History returns the presence log for entityID from since onward,
+// oldest first. An empty entityID returns every tracked person's
+// events. The returned slice is a copy.
+func (t *PresenceTracker) History(entityID string, since time.Time) []PresenceEvent {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	entityID = t.canonicalLocked(entityID)
+	var events []PresenceEvent
+	for _, ev := range t.history {
+		if ev.At.Before(since) || (entityID != "" && ev.EntityID != entityID) {
+			continue
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+// lastArrivalLocked implements [PresenceTracker.LastArrival]. An
+// arrival is an event at home whose predecessor for the same person
+// was not, or the person's first logged event when it is at home.
+// Caller must hold t.mu.
+func (t *PresenceTracker) lastArrivalLocked(p *Person) time.Time {
+	if isHomeState(p.State) && !p.Since.IsZero() {
+		return p.Since
+	}
+	var arrival time.Time
+	wasHome := false
+	for _, ev := range t.history {
+		if ev.EntityID != p.EntityID {
+			continue
+		}
+		home := isHomeState(ev.State)
+		if home && !wasHome {
+			arrival = ev.At
+		}
+		wasHome = home
+	}
+	return arrival
+}
+
+// recordLocked appends p's current state and room to the presence log
+// and prunes events past the retention window or the count cap.
+// Caller must hold t.mu for writing.
+func (t *PresenceTracker) recordLocked(p *Person, at time.Time) {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	t.history = append(t.history, PresenceEvent{
+		EntityID: p.EntityID,
+		State:    p.State,
+		Room:     p.Room,
+		At:       at,
+	})
+
+	cutoff := time.Now().Add(-presenceHistoryRetention)
+	drop := 0
+	for drop < len(t.history) && t.history[drop].At.Before(cutoff) {
+		drop++
+	}
+	if over := len(t.history) - drop - maxPresenceEvents; over > 0 {
+		drop += over
+	}
+	if drop > 0 {
+		t.history = append(t.history[:0:0], t.history[drop:]...)
+	}
+}
+
+// isHomeState reports whether a Home Assistant person state means the
+// person is at home.
+func isHomeState(state string) bool {
+	return strings.EqualFold(state, "home")
+}
+
 // formatState converts a Home Assistant person state to a
 // human-readable display string. "not_home" becomes "Away"; other
 // states are title-cased.
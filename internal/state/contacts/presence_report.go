@@ -0,0 +1,118 @@
+package contacts
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/promptfmt"
+)
+
+// personPresenceReport is one person's entry in a presence report.
+type personPresenceReport struct {
+	Entity      string                `json:"entity"`
+	Name        string                `json:"name"`
+	State       string                `json:"state"`
+	Dwell       string                `json:"dwell,omitempty"`
+	Room        string                `json:"room,omitempty"`
+	RoomDwell   string                `json:"room_dwell,omitempty"`
+	LastArrival string                `json:"last_arrival,omitempty"`
+	Events      []presenceEventReport `json:"events"`
+}
+
+// presenceEventReport is one presence transition, delta-annotated.
+type presenceEventReport struct {
+	State string `json:"state"`
+	Room  string `json:"room,omitempty"`
+	Ago   string `json:"ago"`
+}
+
+// PresenceReport renders the presence history for person (an entity
+// ID or a name; empty for everyone tracked) as compact JSON: current
+// state and room with their dwell times, the last arrival home, and
+// the transitions within lookback, newest first.
+func (t *PresenceTracker) PresenceReport(person string, lookback time.Duration) (string, error) {
+	ids := t.EntityIDs()
+	if strings.TrimSpace(person) != "" {
+		id, ok := t.resolvePerson(person)
+		if !ok {
+			return "", fmt.Errorf("%q is not a tracked person; tracked: %s", person, strings.Join(ids, ", "))
+		}
+		ids = []string{id}
+	}
+
+	now := time.Now()
+	reports := make([]personPresenceReport, 0, len(ids))
+	for _, id := range ids {
+		p, ok := t.person(id)
+		if !ok {
+			continue
+		}
+		r := personPresenceReport{
+			Entity: p.EntityID,
+			Name:   TitleCase(p.FriendlyName),
+			State:  displayPresenceState(p.State),
+			Room:   p.Room,
+			Events: []presenceEventReport{},
+		}
+		if dwell := t.DwellTime(id); dwell > 0 {
+			r.Dwell = dwell.Round(time.Minute).String()
+		}
+		if p.Room != "" && !p.RoomSince.IsZero() {
+			r.RoomDwell = now.Sub(p.RoomSince).Round(time.Minute).String()
+		}
+		if arrival := t.LastArrival(id); !arrival.IsZero() {
+			r.LastArrival = promptfmt.FormatDeltaOnly(arrival, now)
+		}
+		events := t.History(id, now.Add(-lookback))
+		for i := len(events) - 1; i >= 0; i-- {
+			ev := events[i]
+			r.Events = append(r.Events, presenceEventReport{
+				State: displayPresenceState(ev.State),
+				Room:  ev.Room,
+				Ago:   promptfmt.FormatDeltaOnly(ev.At, now),
+			})
+		}
+		reports = append(reports, r)
+	}
+	return promptfmt.MarshalCompact(map[string]any{"people": reports}), nil
+}
+
+// displayPresenceState renders HA's "not_home" as "away", matching
+// the presence context block.
+func displayPresenceState(state string) string {
+	if strings.EqualFold(state, "not_home") {
+		return "away"
+	}
+	return state
+}
+
+// person returns a copy of the tracked person for entityID.
+func (t *PresenceTracker) person(entityID string) (Person, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	p, ok := t.people[t.canonicalLocked(entityID)]
+	if !ok {
+		return Person{}, false
+	}
+	return *p, true
+}
+
+// resolvePerson maps an entity ID or a case-insensitive name to a
+// tracked entity ID.
+func (t *PresenceTracker) resolvePerson(ref string) (string, bool) {
+	ref = strings.TrimSpace(ref)
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if id := t.canonicalLocked(ref); t.people[id] != nil {
+		return id, true
+	}
+	for _, id := range t.order {
+		if strings.EqualFold(t.people[id].FriendlyName, ref) || strings.EqualFold(friendlyNameFromEntityID(id), ref) {
+			return id, true
+		}
+	}
+	return "", false
+}
@@ -3,6 +3,7 @@ package contacts
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -50,6 +51,31 @@ func TestNewPresenceTracker(t *testing.T) {
 	}
 }
 
+func TestTracker_PrimaryQualifiedIDsMatchBareEvents(t *testing.T) {
+	tracker := NewPresenceTracker([]string{"home:person.alice", "person.alice", "cabin:person.bob"}, "UTC", nil)
+	tracker.SetPrimaryInstance("home")
+
+	if ids := tracker.EntityIDs(); !slices.Equal(ids, []string{"person.alice", "cabin:person.bob"}) {
+		t.Fatalf("EntityIDs() = %v, want the primary ID bare and deduplicated", ids)
+	}
+
+	// The primary's watcher reports bare IDs; other instances stay
+	// qualified.
+	tracker.HandleStateChange("person.alice", "not_home", "home", "")
+	tracker.HandleStateChange("cabin:person.bob", "not_home", "home", "")
+	for _, ref := range []string{"person.alice", "home:person.alice", "cabin:person.bob"} {
+		if tracker.DwellTime(ref) == 0 {
+			t.Errorf("DwellTime(%q) = 0, want the state change applied", ref)
+		}
+	}
+	if id, ok := tracker.resolvePerson("home:person.alice"); !ok || id != "person.alice" {
+		t.Errorf("resolvePerson(home:person.alice) = %q, %v; want person.alice", id, ok)
+	}
+	if tracker.DwellTime("person.bob") != 0 {
+		t.Error("bare person.bob matched the cabin's person.bob")
+	}
+}
+
 func TestTracker_Initialize(t *testing.T) {
 	now := time.Date(2026, 2, 15, 16, 30, 0, 0, time.UTC)
 	getter := &mockStateGetter{
@@ -673,3 +699,101 @@ func TestTracker_RoomObserverCalledOutsideLock(t *testing.T) {
 		t.Fatal("UpdateRoom deadlocked — observer is likely called under the write lock")
 	}
 }
+
+func TestTracker_PresenceHistory(t *testing.T) {
+	arrived := time.Now().Add(-2 * time.Hour)
+	getter := &mockStateGetter{
+		states: map[string]*homeassistant.State{
+			"person.alice": {
+				EntityID:    "person.alice",
+				State:       "home",
+				Attributes:  map[string]any{"friendly_name": "Alice"},
+				LastChanged: arrived,
+			},
+		},
+	}
+
+	tracker := NewPresenceTracker([]string{"person.alice"}, "UTC", nil)
+	_ = tracker.Initialize(context.Background(), getter)
+	// A reconnect with no change must not duplicate the seed event.
+	_ = tracker.Initialize(context.Background(), getter)
+
+	if got := tracker.LastArrival("person.alice"); !got.Equal(arrived) {
+		t.Errorf("LastArrival while home = %v, want %v", got, arrived)
+	}
+	if dwell := tracker.DwellTime("person.alice"); dwell < 2*time.Hour || dwell > 2*time.Hour+time.Minute {
+		t.Errorf("DwellTime = %v, want about 2h", dwell)
+	}
+
+	tracker.UpdateRoom("person.alice", "office", "ap-hor-office")
+	tracker.HandleStateChange("person.alice", "home", "not_home", "")
+
+	events := tracker.History("person.alice", time.Time{})
+	if len(events) != 3 {
+		t.Fatalf("History = %+v, want seed, room, and departure events", events)
+	}
+	if events[1].Room != "office" || events[2].State != "not_home" || events[2].Room != "" {
+		t.Errorf("History = %+v, want room change then departure with room cleared", events)
+	}
+
+	// Away: the last arrival comes from the log, and the context
+	// block carries it.
+	if got := tracker.LastArrival("person.alice"); !got.Equal(arrived) {
+		t.Errorf("LastArrival while away = %v, want %v", got, arrived)
+	}
+	if dwell := tracker.DwellTime("person.alice"); dwell > time.Minute {
+		t.Errorf("DwellTime after leaving = %v, want the new away stay", dwell)
+	}
+	result, _ := tracker.TagContext(context.Background(), agentctx.ContextRequest{})
+	if !strings.Contains(result, `"last_arrival":"-2h"`) {
+		t.Errorf("expected last_arrival in context, got:\n%s", result)
+	}
+
+	tracker.HandleStateChange("person.alice", "not_home", "home", "")
+	if got := tracker.LastArrival("person.alice"); time.Since(got) > time.Minute {
+		t.Errorf("LastArrival after returning = %v, want just now", got)
+	}
+
+	if tracker.DwellTime("person.unknown") != 0 || !tracker.LastArrival("person.unknown").IsZero() {
+		t.Error("untracked entity should have no dwell or arrival")
+	}
+}
+
+func TestTracker_PresenceHistoryBounded(t *testing.T) {
+	tracker := NewPresenceTracker([]string{"person.alice"}, "UTC", nil)
+
+	states := []string{"home", "not_home"}
+	for i := 0; i < maxPresenceEvents+10; i++ {
+		tracker.HandleStateChange("person.alice", "", states[i%2], "")
+	}
+	events := tracker.History("", time.Time{})
+	if len(events) != maxPresenceEvents {
+		t.Fatalf("History length = %d, want capped at %d", len(events), maxPresenceEvents)
+	}
+	if events[len(events)-1].State != states[(maxPresenceEvents+9)%2] {
+		t.Errorf("newest event = %+v, want the latest transition kept", events[len(events)-1])
+	}
+}
+
+func TestTracker_PresenceReport(t *testing.T) {
+	tracker := NewPresenceTracker([]string{"person.alice", "person.bob"}, "UTC", nil)
+	tracker.HandleStateChange("person.alice", "", "home", "")
+	tracker.UpdateRoom("person.alice", "kitchen", "ap-kitchen")
+
+	report, err := tracker.PresenceReport("alice", time.Hour)
+	if err != nil {
+		t.Fatalf("PresenceReport: %v", err)
+	}
+	for _, want := range []string{`"entity":"person.alice"`, `"state":"home"`, `"room":"kitchen"`, `"last_arrival":`, `"events":[{"state":"home","room":"kitchen"`} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report = %s, missing %s", report, want)
+		}
+	}
+	if strings.Contains(report, "person.bob") {
+		t.Errorf("report = %s, want only the named person", report)
+	}
+
+	if _, err := tracker.PresenceReport("carol", time.Hour); err == nil || !strings.Contains(err.Error(), "not a tracked person") {
+		t.Errorf("unknown person error = %v", err)
+	}
+}
@@ -42,10 +42,17 @@ var schema = database.Schema{
 				deleted_at TEXT
 			)`,
 		},
+		// Additive columns for contacts that pre-date the latest schema.
+		database.ColumnAdd{Table: "contacts", Column: "embedding_model", Typedef: "TEXT"},
+		database.ColumnAdd{Table: "contacts", Column: "embedding_dim", Typedef: "INTEGER"},
+		// Home Assistant person entity (person.*) this contact is, so
+		// presence and contact facts can be joined. See LinkPersonEntity.
+		database.ColumnAdd{Table: "contacts", Column: "ha_person_entity", Typedef: "TEXT"},
 		database.IndexCreate{Name: "idx_contacts_kind", SQL: `CREATE INDEX IF NOT EXISTS idx_contacts_kind ON contacts(kind)`},
 		database.IndexCreate{Name: "idx_contacts_fn", SQL: `CREATE INDEX IF NOT EXISTS idx_contacts_fn ON contacts(formatted_name)`},
 		database.IndexCreate{Name: "idx_contacts_deleted", SQL: `CREATE INDEX IF NOT EXISTS idx_contacts_deleted ON contacts(deleted_at)`},
 		database.IndexCreate{Name: "idx_contacts_trust_zone", SQL: `CREATE INDEX IF NOT EXISTS idx_contacts_trust_zone ON contacts(trust_zone)`},
+		database.IndexCreate{Name: "idx_contacts_ha_person", SQL: `CREATE INDEX IF NOT EXISTS idx_contacts_ha_person ON contacts(ha_person_entity)`},
 		database.TableCreate{
 			Table: "contact_properties",
 			SQL: `CREATE TABLE IF NOT EXISTS contact_properties (
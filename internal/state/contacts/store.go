@@ -116,6 +116,10 @@ type Store struct {
 	db         *sql.DB
 	ftsEnabled bool
 	logger     *slog.Logger
+	// embeddingModel tags vectors written by SetEmbedding and filters
+	// the ones semantic search will compare against. Empty disables
+	// model tracking.
+	embeddingModel string
 }
 
 // NewStore creates a contact store backed by db. The caller owns db's
@@ -230,8 +234,23 @@ func (s *Store) Upsert(c *Contact) (*Contact, error) {
 	}
 
 	// Update existing (resurrect if soft-deleted).
+	if err := updateContactRow(s.db, c, now); err != nil {
+		return nil, fmt.Errorf("update: %w", err)
+	}
+	s.rebuildFTS()
+	return c, nil
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// updateContactRow writes every scalar field of an existing contact,
+// resurrecting it if soft-deleted. The caller sets c.Rev.
+func updateContactRow(ex execer, c *Contact, now time.Time) error {
 	c.UpdatedAt = now
-	_, err := s.db.Exec(`
+	_, err := ex.Exec(`
 		UPDATE contacts SET kind = ?, formatted_name = ?, family_name = ?, given_name = ?,
 			additional_names = ?, name_prefix = ?, name_suffix = ?, nickname = ?,
 			birthday = ?, anniversary = ?, gender = ?, org = ?, title = ?, role = ?,
@@ -248,11 +267,7 @@ func (s *Store) Upsert(c *Contact) (*Contact, error) {
 		nullTime(c.LastInteraction), nullInteractionMeta(c.LastInteractionMeta),
 		now.Format(time.RFC3339),
 		c.ID.String())
-	if err != nil {
-		return nil, fmt.Errorf("update: %w", err)
-	}
-	s.rebuildFTS()
-	return c, nil
+	return err
 }
 
 // FindByName returns the first active contact with a case-insensitive
@@ -672,6 +687,76 @@ func (s *Store) UpdateLastInteraction(contactID uuid.UUID, t time.Time, meta *In
 	return nil
 }
 
+// --- Home Assistant person links ---
+
+// LinkPersonEntity records that the contact is the Home Assistant
+// person entity entityID (e.g. "person.alice"). An entity links to at
+// most one contact, so any other contact holding it is unlinked first.
+// An empty entityID removes the contact's link. The link lives outside
+// the vCard fields, so Upsert and UpsertWithProperties leave it alone.
+func (s *Store) LinkPersonEntity(contactID uuid.UUID, entityID string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // best-effort on defer
+
+	link := sql.NullString{String: entityID, Valid: entityID != ""}
+	if link.Valid {
+		if _, err := tx.Exec(
+			`UPDATE contacts SET ha_person_entity = NULL WHERE ha_person_entity = ? AND id != ?`,
+			entityID, contactID.String()); err != nil {
+			return fmt.Errorf("unlink previous contact: %w", err)
+		}
+	}
+	result, err := tx.Exec(
+		`UPDATE contacts SET ha_person_entity = ? WHERE id = ? AND `+activeFilter,
+		link, contactID.String())
+	if err != nil {
+		return fmt.Errorf("link person entity: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+	if affected == 0 {
+		return fmt.Errorf("contact not found or deleted: %s", contactID)
+	}
+	return tx.Commit()
+}
+
+// ContactsForPersonEntities maps each of the given Home Assistant
+// person entity IDs that is linked to an active contact to that
+// contact's ID. Unlinked entities are absent from the result.
+func (s *Store) ContactsForPersonEntities(entityIDs []string) (map[string]uuid.UUID, error) {
+	out := make(map[string]uuid.UUID)
+	if len(entityIDs) == 0 {
+		return out, nil
+	}
+	placeholders := strings.TrimRight(strings.Repeat("?,", len(entityIDs)), ",")
+	args := make([]any, len(entityIDs))
+	for i, id := range entityIDs {
+		args[i] = id
+	}
+	rows, err := s.db.Query(
+		`SELECT ha_person_entity, id FROM contacts WHERE `+activeFilter+
+			` AND ha_person_entity IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query person links: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entityID, idStr string
+		if err := rows.Scan(&entityID, &idStr); err != nil {
+			return nil, fmt.Errorf("scan person link: %w", err)
+		}
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			return nil, fmt.Errorf("parse contact id %q: %w", idStr, err)
+		}
+		out[entityID] = id
+	}
+	return out, rows.Err()
+}
+
 // --- Property CRUD ---
 
 // AddProperty adds a vCard property to a contact. If the exact
@@ -891,21 +976,75 @@ func (s *Store) FindByTrustZoneLimit(zone string, limit int) ([]*Contact, error)
 
 // --- Embeddings ---
 
-// SetEmbedding updates a contact's embedding vector.
+// SetEmbeddingModel sets the embedding model whose vectors the store
+// writes and searches. Vectors from any other model are treated as
+// stale: semantic search skips them and GetContactsWithoutEmbeddings
+// returns their contacts for re-embedding. Call it before the store is
+// shared.
+func (s *Store) SetEmbeddingModel(model string) {
+	s.embeddingModel = model
+}
+
+// SetEmbedding updates a contact's embedding vector, tagging it with
+// the store's embedding model and the vector's dimension.
 func (s *Store) SetEmbedding(id uuid.UUID, embedding []float32) error {
 	blob := knowledge.EncodeEmbedding(embedding)
-	_, err := s.db.Exec(`UPDATE contacts SET embedding = ? WHERE id = ?`, blob, id.String())
+	_, err := s.db.Exec(`UPDATE contacts SET embedding = ?, embedding_model = ?, embedding_dim = ? WHERE id = ?`,
+		blob, nullStr(s.embeddingModel), len(embedding), id.String())
 	return err
 }
 
+// AdoptLegacyEmbeddings tags vectors stored before the embedding
+// model was tracked with the store's current model when their
+// dimension matches, returning how many it tagged.
+func (s *Store) AdoptLegacyEmbeddings(dimension int) (int64, error) {
+	if s.embeddingModel == "" || dimension <= 0 {
+		return 0, nil
+	}
+	res, err := s.db.Exec(`UPDATE contacts SET embedding_model = ?, embedding_dim = ?
+		WHERE embedding IS NOT NULL AND embedding_model IS NULL AND length(embedding) = ?`,
+		s.embeddingModel, dimension, dimension*4)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// EmbeddingModels counts active contacts' vectors by the model and
+// dimension that produced them.
+func (s *Store) EmbeddingModels() ([]knowledge.EmbeddingModelCount, error) {
+	rows, err := s.db.Query(`SELECT COALESCE(embedding_model, ''), COALESCE(embedding_dim, length(embedding) / 4), COUNT(*)
+		FROM contacts WHERE ` + activeFilter + ` AND embedding IS NOT NULL
+		GROUP BY 1, 2 ORDER BY 3 DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []knowledge.EmbeddingModelCount
+	for rows.Next() {
+		var c knowledge.EmbeddingModelCount
+		if err := rows.Scan(&c.Model, &c.Dimension, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
 // SemanticSearch finds contacts similar to the query embedding.
 func (s *Store) SemanticSearch(queryEmbedding []float32, limit int) ([]*Contact, []float32, error) {
 	if limit <= 0 {
 		return nil, nil, nil
 	}
 
-	rows, err := s.db.Query(
-		`SELECT ` + contactColumnsWithEmbed + ` FROM contacts WHERE ` + activeFilter + ` AND embedding IS NOT NULL`)
+	query := `SELECT ` + contactColumnsWithEmbed + ` FROM contacts WHERE ` + activeFilter + ` AND embedding IS NOT NULL`
+	var args []any
+	if s.embeddingModel != "" {
+		query += ` AND (embedding_model = ? OR embedding_model IS NULL)`
+		args = append(args, s.embeddingModel)
+	}
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -922,7 +1061,8 @@ func (s *Store) SemanticSearch(queryEmbedding []float32, limit int) ([]*Contact,
 		if err != nil {
 			continue
 		}
-		if len(c.Embedding) > 0 {
+		// A vector of another dimension came from a different model.
+		if len(c.Embedding) == len(queryEmbedding) {
 			sim := knowledge.CosineSimilarity(queryEmbedding, c.Embedding)
 			scores = append(scores, scored{contact: c, score: sim})
 		}
@@ -952,10 +1092,17 @@ func (s *Store) SemanticSearch(queryEmbedding []float32, limit int) ([]*Contact,
 	return resultContacts, resultScores, nil
 }
 
-// GetContactsWithoutEmbeddings returns contacts that need embeddings.
+// GetContactsWithoutEmbeddings returns contacts that need embeddings:
+// those with no vector, and, when an embedding model is set, those
+// whose vector came from another model.
 func (s *Store) GetContactsWithoutEmbeddings() ([]*Contact, error) {
-	rows, err := s.db.Query(
-		`SELECT ` + contactColumns + ` FROM contacts WHERE ` + activeFilter + ` AND embedding IS NULL`)
+	query := `SELECT ` + contactColumns + ` FROM contacts WHERE ` + activeFilter + ` AND embedding IS NULL`
+	var args []any
+	if s.embeddingModel != "" {
+		query = `SELECT ` + contactColumns + ` FROM contacts WHERE ` + activeFilter + ` AND (embedding IS NULL OR embedding_model IS NOT ?)`
+		args = append(args, s.embeddingModel)
+	}
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -606,6 +606,42 @@ func TestGetContactsWithoutEmbeddings(t *testing.T) {
 	}
 }
 
+func TestEmbeddingModelChange(t *testing.T) {
+	store := newTestStore(t)
+	store.SetEmbeddingModel("old-model")
+
+	created, err := store.Upsert(&Contact{FormattedName: "Old Vector", Kind: "individual"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetEmbedding(created.ID, []float32{1.0, 0.0, 0.0}); err != nil {
+		t.Fatal(err)
+	}
+
+	store.SetEmbeddingModel("new-model")
+	found, _, err := store.SemanticSearch([]float32{1.0, 0.0, 0.0}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 0 {
+		t.Errorf("SemanticSearch returned %d contacts with stale vectors, want 0", len(found))
+	}
+	pending, err := store.GetContactsWithoutEmbeddings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].ID != created.ID {
+		t.Errorf("GetContactsWithoutEmbeddings = %d contacts, want the stale one", len(pending))
+	}
+	models, err := store.EmbeddingModels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(models) != 1 || models[0].Model != "old-model" || models[0].Dimension != 3 {
+		t.Errorf("EmbeddingModels = %+v, want one old-model/3 entry", models)
+	}
+}
+
 func TestStats(t *testing.T) {
 	store := newTestStore(t)
 
@@ -1377,3 +1413,53 @@ func TestForeignKeysEnabled(t *testing.T) {
 		t.Error("expected foreign key violation for bogus contact_id")
 	}
 }
+
+func TestLinkPersonEntity(t *testing.T) {
+	store := newTestStore(t)
+
+	alice, err := store.Upsert(&Contact{FormattedName: "Alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := store.Upsert(&Contact{FormattedName: "Alicia"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.LinkPersonEntity(other.ID, "person.alice"); err != nil {
+		t.Fatalf("LinkPersonEntity: %v", err)
+	}
+	// Relinking the entity moves it to the new contact.
+	if err := store.LinkPersonEntity(alice.ID, "person.alice"); err != nil {
+		t.Fatalf("LinkPersonEntity: %v", err)
+	}
+
+	// The link survives a regular update of the contact.
+	alice.Note = "Likes tea"
+	if _, err := store.Upsert(alice); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.ContactsForPersonEntities([]string{"person.alice", "person.bob"})
+	if err != nil {
+		t.Fatalf("ContactsForPersonEntities: %v", err)
+	}
+	if len(got) != 1 || got["person.alice"] != alice.ID {
+		t.Errorf("links = %v, want person.alice -> %s", got, alice.ID)
+	}
+
+	if err := store.LinkPersonEntity(alice.ID, ""); err != nil {
+		t.Fatalf("unlink: %v", err)
+	}
+	got, err = store.ContactsForPersonEntities([]string{"person.alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("links after unlink = %v, want none", got)
+	}
+
+	if err := store.LinkPersonEntity(uuid.New(), "person.alice"); err == nil {
+		t.Error("expected error linking a non-existent contact")
+	}
+}
@@ -146,6 +146,7 @@ type SaveContactArgs struct {
 	AISummary         string            `json:"ai_summary,omitempty"`          // AI-generated context
 	OriginTags        []string          `json:"origin_tags,omitempty"`         // tags pinned when this contact is the session origin
 	OriginContextRefs []string          `json:"origin_context_refs,omitempty"` // refs injected when this contact is the session origin
+	HAPerson          string            `json:"ha_person,omitempty"`           // Home Assistant person entity ID
 	Facts             map[string]string `json:"facts,omitempty"`               // freeform AI metadata
 }
 
@@ -166,7 +167,7 @@ var saveContactKnownFields = map[string]bool{
 	"given_name": true, "family_name": true, "nickname": true,
 	"org": true, "title": true, "role": true,
 	"note": true, "ai_summary": true, "origin_tags": true,
-	"origin_context_refs": true, "facts": true,
+	"origin_context_refs": true, "ha_person": true, "facts": true,
 }
 
 // SaveContact creates or updates a contact. When a contact with the
@@ -212,6 +213,10 @@ func (t *Tools) SaveContact(argsJSON string) (string, error) {
 	if args.Name == "" {
 		return "", fmt.Errorf("name is required")
 	}
+	args.HAPerson = strings.TrimSpace(args.HAPerson)
+	if args.HAPerson != "" && !strings.HasPrefix(args.HAPerson, "person.") {
+		return "", fmt.Errorf("ha_person must be a person entity ID (person.*), got %q", args.HAPerson)
+	}
 
 	// Look for existing contact by name.
 	existing, err := t.store.FindByName(args.Name)
@@ -264,6 +269,10 @@ func (t *Tools) SaveContact(argsJSON string) (string, error) {
 			return "", err
 		}
 
+		if err := t.linkPersonEntity(updated.ID, args.HAPerson); err != nil {
+			return "", err
+		}
+
 		t.generateEmbedding(updated)
 
 		return fmt.Sprintf("Updated contact: **%s** (%s)", updated.FormattedName, updated.Kind), nil
@@ -296,11 +305,28 @@ func (t *Tools) SaveContact(argsJSON string) (string, error) {
 		return "", err
 	}
 
+	if err := t.linkPersonEntity(created.ID, args.HAPerson); err != nil {
+		return "", err
+	}
+
 	t.generateEmbedding(created)
 
 	return fmt.Sprintf("Saved new contact: **%s** (%s)", created.FormattedName, created.Kind), nil
 }
 
+// linkPersonEntity links the contact to a Home Assistant person
+// entity when one was given. An empty entityID leaves any existing
+// link in place.
+func (t *Tools) linkPersonEntity(contactID uuid.UUID, entityID string) error {
+	if entityID == "" {
+		return nil
+	}
+	if err := t.store.LinkPersonEntity(contactID, entityID); err != nil {
+		return fmt.Errorf("link person entity: %w", err)
+	}
+	return nil
+}
+
 func (t *Tools) saveOriginPolicyProperties(contactID uuid.UUID, tags, refs []string) error {
 	if tags != nil {
 		if err := t.store.DeleteContactProperties(contactID, PropertyOriginTag); err != nil {
@@ -467,6 +493,68 @@ func (t *Tools) ForgetContact(argsJSON string) (string, error) {
 	return fmt.Sprintf("Forgot contact: %s", args.Name), nil
 }
 
+// MergeContactsArgs are arguments for the contact_merge tool.
+type MergeContactsArgs struct {
+	Keep  string `json:"keep"`  // name or ID of the contact that survives
+	Merge string `json:"merge"` // name or ID of the duplicate folded into it
+}
+
+// MergeContacts collapses a duplicate contact into another via
+// [Store.Merge] and regenerates the survivor's embedding.
+func (t *Tools) MergeContacts(argsJSON string) (string, error) {
+	var args MergeContactsArgs
+	if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+		return "", fmt.Errorf("parse args: %w", err)
+	}
+
+	if args.Keep == "" || args.Merge == "" {
+		return "", fmt.Errorf("keep and merge are required")
+	}
+
+	keep, err := t.resolveContactRef(args.Keep)
+	if err != nil {
+		return "", err
+	}
+	merge, err := t.resolveContactRef(args.Merge)
+	if err != nil {
+		return "", err
+	}
+
+	merged, err := t.store.Merge(keep.ID, merge.ID)
+	if err != nil {
+		return "", fmt.Errorf("merge contacts: %w", err)
+	}
+	t.generateEmbedding(merged)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Merged %s into %s.", merge.FormattedName, merged.FormattedName))
+	if merged.TrustZone != keep.TrustZone {
+		sb.WriteString(fmt.Sprintf(" Trust zone raised from %s to %s.", keep.TrustZone, merged.TrustZone))
+	}
+	sb.WriteString("\n\n")
+	sb.WriteString(formatContact(merged))
+	return sb.String(), nil
+}
+
+// resolveContactRef finds a contact by ID or, failing that, by name.
+func (t *Tools) resolveContactRef(ref string) (*Contact, error) {
+	if id, err := uuid.Parse(ref); err == nil {
+		c, err := t.store.Get(id)
+		if err != nil {
+			return nil, fmt.Errorf("contact %s not found", ref)
+		}
+		return c, nil
+	}
+	c, err := t.store.ResolveContact(ref)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("contact %q not found", ref)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resolve contact: %w", err)
+	}
+	return c, nil
+}
+
 // ListContactsArgs are arguments for the contact_list tool.
 type ListContactsArgs struct {
 	Kind  string `json:"kind,omitempty"`
@@ -521,7 +609,7 @@ func (t *Tools) GenerateMissingEmbeddings() (int, error) {
 	count := 0
 	for _, c := range contacts {
 		props, _ := t.store.GetProperties(c.ID)
-		embText := buildEmbeddingText(c, props)
+		embText := EmbeddingText(c, props)
 		emb, err := t.embeddings.Generate(context.Background(), embText)
 		if err != nil {
 			continue
@@ -542,7 +630,7 @@ func (t *Tools) generateEmbedding(c *Contact) {
 	}
 
 	props, _ := t.store.GetProperties(c.ID)
-	embText := buildEmbeddingText(c, props)
+	embText := EmbeddingText(c, props)
 	emb, err := t.embeddings.Generate(context.Background(), embText)
 	if err != nil {
 		return
@@ -550,9 +638,9 @@ func (t *Tools) generateEmbedding(c *Contact) {
 	_ = t.store.SetEmbedding(c.ID, emb)
 }
 
-// buildEmbeddingText creates text for embedding from a contact and its
+// EmbeddingText creates text for embedding from a contact and its
 // properties.
-func buildEmbeddingText(c *Contact, props []Property) string {
+func EmbeddingText(c *Contact, props []Property) string {
 	var sb strings.Builder
 	sb.WriteString(c.FormattedName)
 	if c.Kind != "" {
@@ -732,10 +820,11 @@ type ImportVCFArgs struct {
 	DryRun bool   `json:"dry_run,omitempty"`
 }
 
-// ImportVCF imports contacts from a vCard file or text. When merge is
-// true (default), existing contacts are matched by EMAIL then by name,
-// and only empty fields are filled. TrustZone and AISummary are never
-// overwritten during merge. Properties are additive.
+// ImportVCF imports contacts from a vCard file or text via
+// [Store.ImportVCard]. When merge is true (default), existing contacts
+// are matched by phone, then email, then name, and only empty fields
+// are filled. TrustZone and AISummary are never overwritten during
+// merge. Properties are additive.
 func (t *Tools) ImportVCF(argsJSON string) (string, error) {
 	var args ImportVCFArgs
 	if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
@@ -758,153 +847,36 @@ func (t *Tools) ImportVCF(argsJSON string) (string, error) {
 		return "", fmt.Errorf("one of path or text is required")
 	}
 
-	decoded, allProps, err := DecodeVCards(r)
+	result, err := t.store.ImportVCard(r, VCardImportOptions{
+		DryRun:  args.DryRun,
+		NoMerge: !merge,
+	})
 	if err != nil {
 		return "", fmt.Errorf("decode vcards: %w", err)
 	}
 
-	var created, updated, skipped int
-	var summary strings.Builder
-
-	for i, incoming := range decoded {
-		props := allProps[i]
-
-		// Try to find existing contact for merge.
-		var existing *Contact
-		if merge {
-			existing = t.findExistingForMerge(incoming, props)
-		}
-
-		if args.DryRun {
-			if existing != nil {
-				summary.WriteString(fmt.Sprintf("Would merge: %s → %s\n", incoming.FormattedName, existing.FormattedName))
-				updated++
-			} else {
-				summary.WriteString(fmt.Sprintf("Would create: %s\n", incoming.FormattedName))
-				created++
-			}
-			continue
-		}
-
-		if existing != nil {
-			// Merge: fill empty scalar fields only.
-			t.mergeContact(existing, incoming)
-			if _, err := t.store.Upsert(existing); err != nil {
-				skipped++
-				continue
-			}
-			// Add properties additively.
-			for _, p := range props {
-				_ = t.store.AddProperty(existing.ID, &p)
-			}
-			t.generateEmbedding(existing)
-			updated++
-		} else {
-			// Create new contact.
-			if incoming.FormattedName == "" {
-				skipped++
-				continue
-			}
-			c, err := t.store.Upsert(incoming)
-			if err != nil {
-				skipped++
-				continue
-			}
-			for _, p := range props {
-				_ = t.store.AddProperty(c.ID, &p)
-			}
-			t.generateEmbedding(c)
-			created++
-		}
-	}
-
 	if args.DryRun {
-		return fmt.Sprintf("Dry run — %d would be created, %d would be merged:\n\n%s",
-			created, updated, summary.String()), nil
-	}
-
-	return fmt.Sprintf("Imported %d contacts: %d created, %d merged, %d skipped",
-		created+updated, created, updated, skipped), nil
-}
-
-// findExistingForMerge looks for an existing contact that matches the
-// incoming contact. It first tries EMAIL matching, then falls back to
-// formatted name.
-func (t *Tools) findExistingForMerge(incoming *Contact, props []Property) *Contact {
-	// Try EMAIL match first (exact, case-insensitive).
-	for _, p := range props {
-		if p.Property == "EMAIL" && p.Value != "" {
-			matches, err := t.store.FindByPropertyExact("EMAIL", p.Value)
-			if err == nil && len(matches) == 1 {
-				full, err := t.store.GetWithProperties(matches[0].ID)
-				if err == nil {
-					return full
-				}
+		var summary strings.Builder
+		for _, e := range result.Entries {
+			switch e.Action {
+			case ImportMerged:
+				summary.WriteString(fmt.Sprintf("Would merge: %s → %s\n", e.Name, e.MergedInto))
+			case ImportCreated:
+				summary.WriteString(fmt.Sprintf("Would create: %s\n", e.Name))
 			}
 		}
+		return fmt.Sprintf("Dry run — %d would be created, %d would be merged:\n\n%s",
+			result.Created, result.Merged, summary.String()), nil
 	}
 
-	// Fall back to name match.
-	if incoming.FormattedName != "" {
-		existing, err := t.store.FindByName(incoming.FormattedName)
-		if err == nil && existing != nil {
-			return existing
+	for _, e := range result.Entries {
+		if e.Contact != nil {
+			t.generateEmbedding(e.Contact)
 		}
 	}
 
-	return nil
-}
-
-// mergeContact fills empty scalar fields on existing from incoming.
-// TrustZone and AISummary are never overwritten.
-func (t *Tools) mergeContact(existing, incoming *Contact) {
-	if existing.Kind == "" && incoming.Kind != "" {
-		existing.Kind = incoming.Kind
-	}
-	// Never overwrite TrustZone.
-	// Never overwrite AISummary.
-	if existing.GivenName == "" && incoming.GivenName != "" {
-		existing.GivenName = incoming.GivenName
-	}
-	if existing.FamilyName == "" && incoming.FamilyName != "" {
-		existing.FamilyName = incoming.FamilyName
-	}
-	if existing.AdditionalNames == "" && incoming.AdditionalNames != "" {
-		existing.AdditionalNames = incoming.AdditionalNames
-	}
-	if existing.NamePrefix == "" && incoming.NamePrefix != "" {
-		existing.NamePrefix = incoming.NamePrefix
-	}
-	if existing.NameSuffix == "" && incoming.NameSuffix != "" {
-		existing.NameSuffix = incoming.NameSuffix
-	}
-	if existing.Nickname == "" && incoming.Nickname != "" {
-		existing.Nickname = incoming.Nickname
-	}
-	if existing.Birthday == "" && incoming.Birthday != "" {
-		existing.Birthday = incoming.Birthday
-	}
-	if existing.Anniversary == "" && incoming.Anniversary != "" {
-		existing.Anniversary = incoming.Anniversary
-	}
-	if existing.Gender == "" && incoming.Gender != "" {
-		existing.Gender = incoming.Gender
-	}
-	if existing.Org == "" && incoming.Org != "" {
-		existing.Org = incoming.Org
-	}
-	if existing.Title == "" && incoming.Title != "" {
-		existing.Title = incoming.Title
-	}
-	if existing.Role == "" && incoming.Role != "" {
-		existing.Role = incoming.Role
-	}
-	if existing.Note == "" && incoming.Note != "" {
-		existing.Note = incoming.Note
-	}
-	if existing.PhotoURI == "" && incoming.PhotoURI != "" {
-		existing.PhotoURI = incoming.PhotoURI
-	}
+	return fmt.Sprintf("Imported %d contacts: %d created, %d merged, %d skipped",
+		result.Created+result.Merged, result.Created, result.Merged, result.Skipped), nil
 }
 
 // ExportVCFQRArgs are arguments for the contact_export_vcf_qr tool.
@@ -1447,3 +1447,34 @@ func TestImportExportVCF_RoundTrip(t *testing.T) {
 		t.Errorf("GivenName = %q, want %q", c.GivenName, "Round")
 	}
 }
+
+func TestSaveContact_LinksPersonEntity(t *testing.T) {
+	tools := newTestTools(t)
+	store := tools.store
+
+	if _, err := tools.SaveContact(`{"name": "Alice", "ha_person": "person.alice"}`); err != nil {
+		t.Fatalf("SaveContact: %v", err)
+	}
+	alice, err := store.FindByName("Alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.ContactsForPersonEntities([]string{"person.alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["person.alice"] != alice.ID {
+		t.Errorf("person.alice links to %v, want %s", got["person.alice"], alice.ID)
+	}
+	facts, err := store.GetPropertiesMap(alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := facts["ha_person"]; ok {
+		t.Error("ha_person was stored as a property, want only the link")
+	}
+
+	if _, err := tools.SaveContact(`{"name": "Alice", "ha_person": "sensor.alice"}`); err == nil {
+		t.Error("expected error for a non-person entity")
+	}
+}
@@ -45,6 +45,11 @@ var skipProperties = map[string]bool{
 	"PRODID":    true, // vCard generator identifier, not contact data
 }
 
+// appleRelatedNames is the Apple Contacts property for related people.
+// It is imported as RELATED, with the relationship taken from the
+// X-ABLABEL sharing its group (e.g. "item1.X-ABLabel:_$!<Spouse>!$_").
+const appleRelatedNames = "X-ABRELATEDNAMES"
+
 // ContactToCard converts a Contact with its Properties into a
 // vcard.Card.  The contact must have Properties populated (via
 // GetWithProperties).
@@ -202,6 +207,10 @@ func CardToContact(card vcard.Card) (*Contact, []Property) {
 			if types := f.Params.Types(); len(types) > 0 {
 				p.Type = strings.Join(types, ",")
 			}
+			if name == appleRelatedNames {
+				p.Property = vcard.FieldRelated
+				p.Type, p.Label = appleRelation(card, f.Group)
+			}
 			if prefStr := f.Params.Get(vcard.ParamPreferred); prefStr != "" {
 				if pref, err := strconv.Atoi(prefStr); err == nil {
 					p.Pref = pref
@@ -217,6 +226,27 @@ func CardToContact(card vcard.Card) (*Contact, []Property) {
 	return c, props
 }
 
+// appleRelation returns the relationship recorded by the X-ABLABEL in
+// group. Apple's built-in labels ("_$!<Mother>!$_") become a RELATED
+// TYPE ("mother"); custom labels are kept verbatim as the label.
+func appleRelation(card vcard.Card, group string) (typ, label string) {
+	if group == "" {
+		return "", ""
+	}
+	for _, f := range card["X-ABLABEL"] {
+		if !strings.EqualFold(f.Group, group) {
+			continue
+		}
+		if inner, ok := strings.CutPrefix(f.Value, "_$!<"); ok {
+			if inner, ok = strings.CutSuffix(inner, ">!$_"); ok {
+				return strings.ToLower(inner), ""
+			}
+		}
+		return "", f.Value
+	}
+	return "", ""
+}
+
 // EncodeVCard serializes a Contact (with Properties populated) into
 // vCard 4.0 text.
 func EncodeVCard(c *Contact) (string, error) {
@@ -0,0 +1,283 @@
+package contacts
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Import actions reported in [VCardImportEntry.Action].
+const (
+	ImportCreated = "created"
+	ImportMerged  = "merged"
+	ImportSkipped = "skipped"
+)
+
+// VCardImportOptions controls [Store.ImportVCard].
+type VCardImportOptions struct {
+	// DryRun reports what would be created or merged without writing.
+	DryRun bool
+
+	// NoMerge creates every card as a new contact instead of merging
+	// it into an existing match.
+	NoMerge bool
+
+	// DefaultCountryCode is the calling code (e.g. "1", "44") assumed
+	// for phone numbers written without one, so that national-format
+	// numbers normalize to the E.164 form channel resolvers match on.
+	// Empty leaves such numbers as bare digits.
+	DefaultCountryCode string
+}
+
+// VCardImportEntry describes what happened to one imported card.
+type VCardImportEntry struct {
+	Name       string `json:"name"` // the card's FN
+	Action     string `json:"action"`
+	MergedInto string `json:"merged_into,omitempty"` // existing contact's name when merged
+	MatchedBy  string `json:"matched_by,omitempty"`  // TEL, EMAIL, or name when merged
+	Reason     string `json:"reason,omitempty"`      // why the card was skipped
+
+	// Contact is the created or merged contact. Nil for skipped cards
+	// and for creations in a dry run.
+	Contact *Contact `json:"-"`
+}
+
+// VCardImportResult summarizes a [Store.ImportVCard] run.
+type VCardImportResult struct {
+	DryRun  bool               `json:"dry_run,omitempty"`
+	Created int                `json:"created"`
+	Merged  int                `json:"merged"`
+	Skipped int                `json:"skipped"`
+	Entries []VCardImportEntry `json:"entries"`
+}
+
+// ImportVCard reads one or more vCard 3.0 or 4.0 cards from r and
+// creates or updates contacts from them. Phone and email properties
+// are normalized to the forms the channel resolvers look up (E.164
+// TEL, bare lowercase EMAIL), so imported contacts gate Signal and
+// email trust the same as ones saved by the agent.
+//
+// Unless opts.NoMerge is set, each card is matched to an existing
+// contact by phone number, then email, then formatted name, so
+// re-importing the same file updates rather than duplicates. A merge
+// fills only empty fields — trust zone and AI summary are never
+// overwritten — and adds properties the contact lacks.
+func (s *Store) ImportVCard(r io.Reader, opts VCardImportOptions) (*VCardImportResult, error) {
+	decoded, allProps, err := DecodeVCards(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VCardImportResult{DryRun: opts.DryRun}
+	for i, incoming := range decoded {
+		props, written := normalizeImportProperties(allProps[i], opts.DefaultCountryCode)
+		entry := VCardImportEntry{Name: incoming.FormattedName}
+
+		var existing *Contact
+		if !opts.NoMerge {
+			existing, entry.MatchedBy = s.findImportMatch(incoming, props, written)
+		}
+
+		switch {
+		case existing == nil && incoming.FormattedName == "":
+			entry.Action, entry.Reason = ImportSkipped, "card has no FN"
+		case opts.DryRun && existing != nil:
+			entry.Action, entry.Contact = ImportMerged, existing
+		case opts.DryRun:
+			entry.Action = ImportCreated
+		case existing != nil:
+			mergeContactFields(existing, incoming)
+			entry.Contact, err = s.importContact(existing, props)
+			entry.Action = ImportMerged
+		default:
+			entry.Contact, err = s.importContact(incoming, props)
+			entry.Action = ImportCreated
+		}
+		if err != nil {
+			entry.Action, entry.Reason, entry.Contact = ImportSkipped, err.Error(), nil
+			err = nil
+		}
+		if entry.Action == ImportMerged {
+			entry.MergedInto = existing.FormattedName
+		}
+
+		switch entry.Action {
+		case ImportCreated:
+			result.Created++
+		case ImportMerged:
+			result.Merged++
+		default:
+			result.Skipped++
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+	return result, nil
+}
+
+// importContact upserts c and adds props to it.
+func (s *Store) importContact(c *Contact, props []Property) (*Contact, error) {
+	saved, err := s.Upsert(c)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range props {
+		if err := s.AddProperty(saved.ID, &p); err != nil {
+			return nil, err
+		}
+	}
+	return saved, nil
+}
+
+// ExportVCard serializes the contact with the given ID, including its
+// properties, as vCard 4.0 text.
+func (s *Store) ExportVCard(contactID uuid.UUID) (string, error) {
+	c, err := s.GetWithProperties(contactID)
+	if err != nil {
+		return "", fmt.Errorf("get contact: %w", err)
+	}
+	return EncodeVCard(c)
+}
+
+// findImportMatch returns the existing contact an incoming card should
+// merge into, and which identifier matched. Phone and email only count
+// when they identify exactly one contact; a shared household number is
+// not evidence of identity. Values are compared both normalized and as
+// written (written[i] for props[i]), since contacts saved before
+// normalization keep their original form.
+func (s *Store) findImportMatch(incoming *Contact, props []Property, written []string) (*Contact, string) {
+	for _, property := range []string{"TEL", "EMAIL"} {
+		for i, p := range props {
+			if p.Property != property {
+				continue
+			}
+			for _, value := range []string{p.Value, written[i]} {
+				matches, err := s.FindByPropertyExact(property, value)
+				if err != nil || len(matches) != 1 {
+					continue
+				}
+				if full, err := s.GetWithProperties(matches[0].ID); err == nil {
+					return full, property
+				}
+			}
+		}
+	}
+
+	if incoming.FormattedName != "" {
+		if existing, err := s.FindByName(incoming.FormattedName); err == nil && existing != nil {
+			return existing, "name"
+		}
+	}
+	return nil, ""
+}
+
+// mergeContactFields fills empty scalar fields on existing from
+// incoming. TrustZone and AISummary are never overwritten.
+func mergeContactFields(existing, incoming *Contact) {
+	fill := func(dst *string, src string) {
+		if *dst == "" && src != "" {
+			*dst = src
+		}
+	}
+	fill(&existing.Kind, incoming.Kind)
+	fill(&existing.GivenName, incoming.GivenName)
+	fill(&existing.FamilyName, incoming.FamilyName)
+	fill(&existing.AdditionalNames, incoming.AdditionalNames)
+	fill(&existing.NamePrefix, incoming.NamePrefix)
+	fill(&existing.NameSuffix, incoming.NameSuffix)
+	fill(&existing.Nickname, incoming.Nickname)
+	fill(&existing.Birthday, incoming.Birthday)
+	fill(&existing.Anniversary, incoming.Anniversary)
+	fill(&existing.Gender, incoming.Gender)
+	fill(&existing.Org, incoming.Org)
+	fill(&existing.Title, incoming.Title)
+	fill(&existing.Role, incoming.Role)
+	fill(&existing.Note, incoming.Note)
+	fill(&existing.PhotoURI, incoming.PhotoURI)
+}
+
+// normalizeImportProperties rewrites TEL, EMAIL, and Signal IMPP values
+// into the forms channel resolvers match on. It also returns each
+// property's value as written, for dedup against contacts stored
+// before normalization. Properties left empty are dropped.
+func normalizeImportProperties(props []Property, countryCode string) ([]Property, []string) {
+	out := make([]Property, 0, len(props))
+	written := make([]string, 0, len(props))
+	for _, p := range props {
+		raw := p.Value
+		switch p.Property {
+		case "TEL":
+			p.Value = normalizePhone(raw, countryCode)
+		case "EMAIL":
+			p.Value = normalizeEmail(raw)
+		case "IMPP":
+			if scheme, addr, ok := strings.Cut(raw, ":"); ok && strings.EqualFold(scheme, "signal") && !strings.HasPrefix(addr, "group:") {
+				p.Value = "signal:" + normalizePhone(addr, countryCode)
+			}
+		}
+		if p.Value == "" {
+			continue
+		}
+		out = append(out, p)
+		written = append(written, raw)
+	}
+	return out, written
+}
+
+// normalizePhone reduces a vCard TEL value ("(512) 555-0100",
+// "tel:+1-512-555-0100;ext=12", "0044 20 7946 0000") to E.164 with a
+// leading "+", the form Signal reports senders in. A number written
+// without a country code gets countryCode after its national trunk
+// prefix is dropped; with no countryCode it is left as bare digits.
+// Values with no digits are returned trimmed but otherwise unchanged.
+func normalizePhone(raw, countryCode string) string {
+	v := strings.TrimSpace(raw)
+	if len(v) >= 4 && strings.EqualFold(v[:4], "tel:") {
+		v = v[4:]
+	}
+	// Drop extensions and URI parameters.
+	if i := strings.IndexFunc(v, func(r rune) bool { return r == ';' || r == ',' || unicode.IsLetter(r) }); i >= 0 {
+		v = v[:i]
+	}
+
+	international := strings.HasPrefix(strings.TrimSpace(v), "+")
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, v)
+	switch {
+	case digits == "":
+		return strings.TrimSpace(raw)
+	case international:
+		return "+" + digits
+	case strings.HasPrefix(digits, "00") && len(digits) > 2:
+		return "+" + digits[2:]
+	case countryCode == "" || len(digits) < 7:
+		// Short codes and numbers of unknown origin stay as written.
+		return digits
+	}
+
+	// North American numbers carry "1" as their trunk prefix; most
+	// other plans use "0".
+	trunk := "0"
+	if countryCode == "1" {
+		trunk = "1"
+	}
+	if len(digits) > 10 || trunk == "0" {
+		digits = strings.TrimPrefix(digits, trunk)
+	}
+	return "+" + countryCode + digits
+}
+
+// normalizeEmail strips a mailto: scheme and lowercases the address.
+func normalizeEmail(raw string) string {
+	v := strings.TrimSpace(raw)
+	if len(v) >= 7 && strings.EqualFold(v[:7], "mailto:") {
+		v = v[7:]
+	}
+	return strings.ToLower(strings.TrimSpace(v))
+}
@@ -0,0 +1,193 @@
+package contacts
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		raw, cc, want string
+	}{
+		{raw: "+1 (512) 555-0100", want: "+15125550100"},
+		{raw: "tel:+1-512-555-0100", want: "+15125550100"},
+		{raw: "TEL:+1-512-555-0100;ext=12", want: "+15125550100"},
+		{raw: "+1 512 555 0100 x12", want: "+15125550100"},
+		{raw: "0044 20 7946 0000", want: "+442079460000"},
+		{raw: "(512) 555-0100", cc: "1", want: "+15125550100"},
+		{raw: "1-512-555-0100", cc: "1", want: "+15125550100"},
+		{raw: "020 7946 0000", cc: "44", want: "+442079460000"},
+		{raw: "(512) 555-0100", want: "5125550100"},
+		{raw: "55555", cc: "1", want: "55555"},
+		{raw: " unknown ", want: "unknown"},
+	}
+	for _, tt := range tests {
+		if got := normalizePhone(tt.raw, tt.cc); got != tt.want {
+			t.Errorf("normalizePhone(%q, %q) = %q, want %q", tt.raw, tt.cc, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	if got := normalizeEmail(" mailto:Alice@Example.COM "); got != "alice@example.com" {
+		t.Errorf("normalizeEmail = %q", got)
+	}
+}
+
+func TestImportVCard_NormalizesForResolvers(t *testing.T) {
+	store := newTestStore(t)
+
+	// vCard 3.0 as exported by a phone: formatted numbers, typed
+	// EMAIL, and an Apple related-name group.
+	text := "BEGIN:VCARD\r\n" +
+		"VERSION:3.0\r\n" +
+		"FN:Alice Example\r\n" +
+		"N:Example;Alice;;;\r\n" +
+		"ORG:Acme Corp\r\n" +
+		"TEL;TYPE=CELL:(512) 555-0100\r\n" +
+		"EMAIL;TYPE=INTERNET:Alice@Example.com\r\n" +
+		"item1.X-ABRELATEDNAMES:Bob Example\r\n" +
+		"item1.X-ABLabel:_$!<Spouse>!$_\r\n" +
+		"END:VCARD\r\n"
+
+	result, err := store.ImportVCard(strings.NewReader(text), VCardImportOptions{DefaultCountryCode: "1"})
+	if err != nil {
+		t.Fatalf("ImportVCard: %v", err)
+	}
+	if result.Created != 1 || result.Merged != 0 || result.Skipped != 0 {
+		t.Fatalf("result = %+v, want 1 created", result)
+	}
+
+	// The same lookups the Signal and email resolvers perform.
+	if matches, err := store.FindByPropertyExact("TEL", "+15125550100"); err != nil || len(matches) != 1 {
+		t.Errorf("TEL lookup = %v, %v; want one match", matches, err)
+	}
+	if matches, err := store.FindByPropertyExact("EMAIL", "alice@example.com"); err != nil || len(matches) != 1 {
+		t.Errorf("EMAIL lookup = %v, %v; want one match", matches, err)
+	}
+
+	c, err := store.GetWithProperties(result.Entries[0].Contact.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Org != "Acme Corp" {
+		t.Errorf("Org = %q", c.Org)
+	}
+	if c.TrustZone != ZoneKnown {
+		t.Errorf("TrustZone = %q, want %q", c.TrustZone, ZoneKnown)
+	}
+	var related *Property
+	for i, p := range c.Properties {
+		if p.Property == "RELATED" {
+			related = &c.Properties[i]
+		}
+		if p.Property == "X-ABRELATEDNAMES" {
+			t.Errorf("Apple related name stored raw: %+v", p)
+		}
+	}
+	if related == nil || related.Value != "Bob Example" || related.Type != "spouse" {
+		t.Errorf("RELATED = %+v, want Bob Example (spouse)", related)
+	}
+}
+
+func TestImportVCard_ReimportDedupes(t *testing.T) {
+	store := newTestStore(t)
+
+	// A contact the agent saved earlier, with the phone in E.164 and
+	// an elevated trust zone.
+	existing, err := store.Upsert(&Contact{FormattedName: "Robert Smith", TrustZone: ZoneTrusted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddProperty(existing.ID, &Property{Property: "TEL", Value: "+15125550199"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The phone knows him as "Bob" — the phone number is the match.
+	text := "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Bob\r\nTEL;VALUE=uri:tel:+1-512-555-0199\r\nEMAIL:bob@example.com\r\nNOTE:Met at the lake\r\nEND:VCARD\r\n"
+	for range 2 {
+		result, err := store.ImportVCard(strings.NewReader(text), VCardImportOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Merged != 1 || result.Created != 0 {
+			t.Fatalf("result = %+v, want 1 merged", result)
+		}
+		if e := result.Entries[0]; e.MatchedBy != "TEL" || e.MergedInto != "Robert Smith" {
+			t.Errorf("entry = %+v, want merged into Robert Smith by TEL", e)
+		}
+	}
+
+	all, err := store.ListAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("contacts = %d, want 1", len(all))
+	}
+	c, err := store.GetWithProperties(existing.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.TrustZone != ZoneTrusted {
+		t.Errorf("TrustZone = %q, want preserved %q", c.TrustZone, ZoneTrusted)
+	}
+	if c.Note != "Met at the lake" {
+		t.Errorf("Note = %q, want filled from import", c.Note)
+	}
+	counts := map[string]int{}
+	for _, p := range c.Properties {
+		counts[p.Property]++
+	}
+	if counts["TEL"] != 1 || counts["EMAIL"] != 1 {
+		t.Errorf("property counts = %v, want one TEL and one EMAIL", counts)
+	}
+}
+
+func TestImportVCard_DryRunAndSkip(t *testing.T) {
+	store := newTestStore(t)
+
+	text := "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Dry Run\r\nEND:VCARD\r\n" +
+		"BEGIN:VCARD\r\nVERSION:4.0\r\nTEL:+15125550100\r\nEND:VCARD\r\n"
+	result, err := store.ImportVCard(strings.NewReader(text), VCardImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.DryRun || result.Created != 1 || result.Skipped != 1 {
+		t.Errorf("result = %+v, want 1 created and 1 skipped", result)
+	}
+	if all, _ := store.ListAll(); len(all) != 0 {
+		t.Errorf("dry run wrote %d contacts", len(all))
+	}
+}
+
+func TestExportVCard(t *testing.T) {
+	store := newTestStore(t)
+
+	c, err := store.Upsert(&Contact{FormattedName: "Export Me", Org: "Acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddProperty(c.ID, &Property{Property: "TEL", Value: "+15125550100"}); err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := store.ExportVCard(c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"FN:Export Me", "ORG:Acme", "TEL:+15125550100", "UID:" + c.ID.String()} {
+		if !strings.Contains(text, want) {
+			t.Errorf("export missing %q:\n%s", want, text)
+		}
+	}
+
+	// Exported cards re-import onto the same contact.
+	result, err := store.ImportVCard(strings.NewReader(text), VCardImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Merged != 1 {
+		t.Errorf("re-import result = %+v, want 1 merged", result)
+	}
+}
@@ -0,0 +1,84 @@
+package knowledge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// DefaultBackfillBatchSize is how many records [Backfill] embeds per
+// request when the caller doesn't choose.
+const DefaultBackfillBatchSize = 32
+
+// BatchEmbeddingClient generates embeddings for several texts per
+// request. [Client] implements it.
+type BatchEmbeddingClient interface {
+	GenerateBatch(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// BackfillItem is one record awaiting an embedding.
+type BackfillItem struct {
+	ID   uuid.UUID
+	Text string
+}
+
+// BackfillProgress counts the records a [Backfill] run has handled.
+type BackfillProgress struct {
+	Total    int `json:"total"`
+	Embedded int `json:"embedded"`
+	Failed   int `json:"failed"`
+}
+
+// Done is the number of records handled so far, successfully or not.
+func (p BackfillProgress) Done() int {
+	return p.Embedded + p.Failed
+}
+
+// Backfill embeds items batchSize at a time and passes each vector to
+// save, calling progress (when non-nil) after every batch. Records the
+// client or save can't handle are counted as failed and skipped; the
+// run only stops early when ctx is cancelled or a whole batch fails
+// for a reason other than its inputs (e.g. the embedding server is
+// unreachable), in which case the progress so far is returned with the
+// error.
+func Backfill(ctx context.Context, client BatchEmbeddingClient, items []BackfillItem, batchSize int, save func(id uuid.UUID, embedding []float32) error, progress func(BackfillProgress)) (BackfillProgress, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultBackfillBatchSize
+	}
+	p := BackfillProgress{Total: len(items)}
+
+	for start := 0; start < len(items); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return p, err
+		}
+		batch := items[start:min(start+batchSize, len(items))]
+		texts := make([]string, len(batch))
+		for i, item := range batch {
+			texts[i] = item.Text
+		}
+
+		vectors, err := client.GenerateBatch(ctx, texts)
+		var batchErr *BatchError
+		if err != nil && !errors.As(err, &batchErr) {
+			return p, fmt.Errorf("embed batch at %d: %w", start, err)
+		}
+
+		for i, item := range batch {
+			if i >= len(vectors) || vectors[i] == nil {
+				p.Failed++
+				continue
+			}
+			if err := save(item.ID, vectors[i]); err != nil {
+				p.Failed++
+				continue
+			}
+			p.Embedded++
+		}
+		if progress != nil {
+			progress(p)
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,63 @@
+package knowledge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBackfill_BatchesAndCountsFailures(t *testing.T) {
+	srv, batchCalls := fakeOllamaEmbed(t, map[string]bool{"bad": true}, false)
+	c := New(Config{BaseURL: srv.URL})
+
+	items := make([]BackfillItem, 5)
+	for i := range items {
+		items[i] = BackfillItem{ID: uuid.New(), Text: "fact"}
+	}
+	items[3].Text = "bad"
+
+	saved := make(map[uuid.UUID][]float32)
+	var updates []BackfillProgress
+	got, err := Backfill(context.Background(), c, items, 2,
+		func(id uuid.UUID, emb []float32) error {
+			saved[id] = emb
+			return nil
+		},
+		func(p BackfillProgress) { updates = append(updates, p) })
+	if err != nil {
+		t.Fatalf("Backfill() error = %v", err)
+	}
+	want := BackfillProgress{Total: 5, Embedded: 4, Failed: 1}
+	if got != want {
+		t.Errorf("progress = %+v, want %+v", got, want)
+	}
+	if len(saved) != 4 {
+		t.Errorf("saved %d vectors, want 4", len(saved))
+	}
+	if _, ok := saved[items[3].ID]; ok {
+		t.Error("failed item was saved")
+	}
+	if *batchCalls != 3 {
+		t.Errorf("batch requests = %d, want 3", *batchCalls)
+	}
+	if len(updates) != 3 || updates[2].Done() != 5 {
+		t.Errorf("progress updates = %+v, want 3 ending at 5 done", updates)
+	}
+}
+
+func TestBackfill_StopsWhenServerUnreachable(t *testing.T) {
+	c := New(Config{BaseURL: "http://127.0.0.1:1"})
+	items := []BackfillItem{{ID: uuid.New(), Text: "a"}}
+
+	_, err := Backfill(context.Background(), c, items, 0,
+		func(uuid.UUID, []float32) error { return nil }, nil)
+	if err == nil {
+		t.Fatal("Backfill() succeeded against an unreachable server")
+	}
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) {
+		t.Fatalf("error = %v, want the transport error rather than per-item failures", err)
+	}
+}
@@ -0,0 +1,133 @@
+package knowledge
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/checkpoint"
+)
+
+// RestoreCheckpoint rewinds the fact store to the facts in state,
+// implementing [checkpoint.Restorer]. Snapshot facts are recreated or
+// reset to their captured value, source, confidence, subjects, and
+// expiry (resurrecting them if forgotten since), and active facts the
+// snapshot lacks are soft-deleted. A fact whose captured expiry has
+// passed since the snapshot comes back already expired. Refs are not
+// captured by checkpoints and are left as they are. A fact whose value
+// changes loses its embedding, so the embedding backfill re-embeds the
+// restored value instead of semantic search matching the old one. The
+// whole restore is one transaction.
+func (s *Store) RestoreCheckpoint(state *checkpoint.State, dryRun bool) (checkpoint.StoreChange, error) {
+	change := checkpoint.StoreChange{Store: "facts"}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return change, fmt.Errorf("begin restore tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	seen := make(map[string]bool, len(state.Facts))
+	for _, f := range state.Facts {
+		seen[f.Category+"\x00"+f.Key] = true
+
+		subjects, err := subjectsColumn(NormalizeSubjects(f.Subjects))
+		if err != nil {
+			return change, fmt.Errorf("restore fact %s/%s: %w", f.Category, f.Key, err)
+		}
+		var expires *string
+		if f.ExpiresAt != nil {
+			formatted := f.ExpiresAt.UTC().Format(time.RFC3339)
+			expires = &formatted
+		}
+
+		var id, value string
+		var source, curSubjects, curExpires sql.NullString
+		var confidence float64
+		var active bool
+		err = tx.QueryRow(`
+			SELECT id, value, source, confidence, subjects, expires_at, (`+activeFilter+`)
+			FROM facts WHERE category = ? AND key = ?
+		`, f.Category, f.Key).Scan(&id, &value, &source, &confidence, &curSubjects, &curExpires, &active)
+		switch {
+		case err == sql.ErrNoRows:
+			change.Added++
+			if dryRun {
+				continue
+			}
+			_, err = tx.Exec(`
+				INSERT INTO facts (id, category, key, value, source, confidence, subjects, created_at, updated_at, accessed_at, expires_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`, f.ID.String(), f.Category, f.Key, f.Value, f.Source, f.Confidence, subjects,
+				f.CreatedAt.UTC().Format(time.RFC3339), f.UpdatedAt.UTC().Format(time.RFC3339), now, expires)
+		case err != nil:
+			return change, fmt.Errorf("look up fact %s/%s: %w", f.Category, f.Key, err)
+		case active && value == f.Value && source.String == f.Source && confidence == f.Confidence &&
+			sameNullString(curSubjects, subjects) && sameNullString(curExpires, expires):
+			change.Unchanged++
+			continue
+		default:
+			change.Updated++
+			if dryRun {
+				continue
+			}
+			_, err = tx.Exec(`
+				UPDATE facts SET value = ?, source = ?, confidence = ?, subjects = ?, expires_at = ?,
+					updated_at = ?, deleted_at = NULL,
+					embedding = CASE WHEN value = ? THEN embedding END,
+					embedding_model = CASE WHEN value = ? THEN embedding_model END,
+					embedding_dim = CASE WHEN value = ? THEN embedding_dim END
+				WHERE id = ?
+			`, f.Value, f.Source, f.Confidence, subjects, expires, f.UpdatedAt.UTC().Format(time.RFC3339),
+				f.Value, f.Value, f.Value, id)
+		}
+		if err != nil {
+			return change, fmt.Errorf("restore fact %s/%s: %w", f.Category, f.Key, err)
+		}
+	}
+
+	rows, err := tx.Query(`SELECT id, category, key FROM facts WHERE ` + activeFilter)
+	if err != nil {
+		return change, fmt.Errorf("query active facts: %w", err)
+	}
+	var stale []string
+	for rows.Next() {
+		var id, category, key string
+		if err := rows.Scan(&id, &category, &key); err != nil {
+			rows.Close()
+			return change, fmt.Errorf("scan active fact: %w", err)
+		}
+		if !seen[category+"\x00"+key] {
+			stale = append(stale, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return change, fmt.Errorf("query active facts: %w", err)
+	}
+	change.Removed = len(stale)
+
+	if dryRun {
+		return change, nil
+	}
+	for _, id := range stale {
+		if _, err := tx.Exec(`UPDATE facts SET deleted_at = ? WHERE id = ?`, now, id); err != nil {
+			return change, fmt.Errorf("forget fact %s: %w", id, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return change, fmt.Errorf("commit restore: %w", err)
+	}
+	s.rebuildFTS()
+	return change, nil
+}
+
+// sameNullString reports whether a scanned column holds want, with nil
+// standing for NULL.
+func sameNullString(got sql.NullString, want *string) bool {
+	if want == nil {
+		return !got.Valid
+	}
+	return got.Valid && got.String == *want
+}
@@ -0,0 +1,140 @@
+package knowledge
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/checkpoint"
+)
+
+func TestRestoreCheckpoint(t *testing.T) {
+	store := newTestStore(t)
+
+	for _, f := range []struct{ key, value string }{
+		{"kept", "unchanged"}, {"edited", "original"}, {"forgotten", "still true"},
+	} {
+		if _, err := store.Set(CategoryUser, f.key, f.value, "test", 0.9, nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, err := store.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &checkpoint.State{}
+	for _, f := range all {
+		state.Facts = append(state.Facts, checkpoint.Fact{
+			ID: f.ID, Category: string(f.Category), Key: f.Key, Value: f.Value,
+			Source: f.Source, Confidence: f.Confidence, CreatedAt: f.CreatedAt, UpdatedAt: f.UpdatedAt,
+		})
+	}
+
+	if _, err := store.Set(CategoryUser, "edited", "rewritten", "test", 0.9, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"kept", "edited"} {
+		f, err := store.Get(CategoryUser, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.SetEmbedding(f.ID, []float32{1, 0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Delete(CategoryUser, "forgotten"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Set(CategoryHome, "later", "learned after", "test", 1, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := store.RestoreCheckpoint(state, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Unchanged != 1 || plan.Updated != 2 || plan.Removed != 1 || plan.Added != 0 {
+		t.Fatalf("plan = %+v, want 1 unchanged, 2 updated, 1 removed", plan)
+	}
+	if f, _ := store.Get(CategoryUser, "edited"); f == nil || f.Value != "rewritten" {
+		t.Fatalf("dry run changed facts: %+v", f)
+	}
+
+	if _, err := store.RestoreCheckpoint(state, false); err != nil {
+		t.Fatal(err)
+	}
+	if f, err := store.Get(CategoryUser, "edited"); err != nil || f.Value != "original" {
+		t.Errorf("edited fact = %+v, %v; want original value", f, err)
+	}
+	if _, err := store.Get(CategoryUser, "forgotten"); err != nil {
+		t.Errorf("forgotten fact not resurrected: %v", err)
+	}
+	if _, err := store.Get(CategoryHome, "later"); err == nil {
+		t.Error("fact learned after the snapshot is still active")
+	}
+	pending, err := store.GetFactsWithoutEmbeddings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	needsEmbedding := map[string]bool{}
+	for _, f := range pending {
+		needsEmbedding[f.Key] = true
+	}
+	if !needsEmbedding["edited"] || needsEmbedding["kept"] {
+		t.Errorf("facts needing embeddings = %v, want edited (value restored) but not kept", needsEmbedding)
+	}
+	if results, err := store.Search("original"); err != nil || len(results) != 1 {
+		t.Errorf("search after restore = %d results, %v; want the restored fact", len(results), err)
+	}
+}
+
+func TestRestoreCheckpoint_ExpiryAndSubjectsRoundTrip(t *testing.T) {
+	store := newTestStore(t)
+
+	if _, err := store.SetWithTTL(CategoryHome, "guest", "staying the weekend", "test", 0.9, []string{"zone:guest_room"}, "", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := store.Get(CategoryHome, "guest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &checkpoint.State{Facts: []checkpoint.Fact{{
+		ID: snap.ID, Category: string(snap.Category), Key: snap.Key, Value: snap.Value,
+		Source: snap.Source, Confidence: snap.Confidence, CreatedAt: snap.CreatedAt, UpdatedAt: snap.UpdatedAt,
+		Subjects: snap.Subjects, ExpiresAt: snap.ExpiresAt,
+	}}}
+
+	raw, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded checkpoint.State
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if plan, err := store.RestoreCheckpoint(&decoded, true); err != nil || plan.Unchanged != 1 {
+		t.Fatalf("plan for an untouched store = %+v, %v; want 1 unchanged", plan, err)
+	}
+
+	if err := store.ClearExpiry(CategoryHome, "guest"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Set(CategoryHome, "guest", "staying the weekend", "test", 0.9, []string{"zone:attic"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.RestoreCheckpoint(&decoded, false); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.Get(CategoryHome, "guest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ExpiresAt == nil || !got.ExpiresAt.Equal(*snap.ExpiresAt) {
+		t.Errorf("expires_at = %v, want %v", got.ExpiresAt, snap.ExpiresAt)
+	}
+	if len(got.Subjects) != 1 || got.Subjects[0] != "zone:guest_room" {
+		t.Errorf("subjects = %v, want [zone:guest_room]", got.Subjects)
+	}
+}
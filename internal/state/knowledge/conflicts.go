@@ -0,0 +1,295 @@
+package knowledge
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/runtime/agentctx"
+)
+
+// ConflictConfidence is the confidence at or above which a stored fact
+// is protected from silent overwrite by [Store.SetWithConflictCheck].
+// Facts below it are still simply corrected.
+const ConflictConfidence = 0.8
+
+// maxConflictsInContext caps how many pending conflicts the
+// [ConflictContextProvider] lists in one turn.
+const maxConflictsInContext = 5
+
+// Conflict resolutions accepted by [Store.ResolveConflict].
+const (
+	ResolutionKeep   = "keep"   // the stored value stands
+	ResolutionAccept = "accept" // the observed value replaces it
+	ResolutionValue  = "value"  // a reconciled value replaces both
+)
+
+// ResolutionSuperseded marks a conflict closed because the fact was
+// set directly while it was pending. It is recorded by
+// [Store.SetWithTTL], not accepted by [Store.ResolveConflict].
+const ResolutionSuperseded = "superseded"
+
+// Conflict records an observed value that contradicts a high-confidence
+// fact. The stored fact is left untouched until the conflict is
+// resolved.
+type Conflict struct {
+	ID                 int64     `json:"id"`
+	Category           Category  `json:"category"`
+	Key                string    `json:"key"`
+	CurrentValue       string    `json:"current_value"`
+	CurrentConfidence  float64   `json:"current_confidence"`
+	ProposedValue      string    `json:"proposed_value"`
+	ProposedConfidence float64   `json:"proposed_confidence"`
+	Source             string    `json:"source,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// SetWithConflictCheck is [Store.SetWithTTL] for automated writers
+// such as fact extraction. When an active fact with confidence of at least
+// [ConflictConfidence] already holds a different value, the new value
+// is not applied: the disagreement is recorded as a pending [Conflict]
+// and returned alongside the unchanged fact, so the agent can
+// reconcile it deliberately. Otherwise the value is set as usual and
+// the returned conflict is nil.
+//
+// A second disagreement for the same fact updates its pending conflict
+// rather than opening another. An observation that is applied leaves
+// pending conflicts open: re-observing a value is not a decision
+// between the two.
+func (s *Store) SetWithConflictCheck(category Category, key, value, source string, confidence float64, subjects []string, ref string, ttl time.Duration) (*Fact, *Conflict, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, nil, fmt.Errorf("begin set tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	existing, err := s.scanFact(tx.QueryRow(
+		`SELECT `+factColumns+` FROM facts WHERE `+activeFilter+` AND category = ? AND key = ?`,
+		category, key))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("check existing: %w", err)
+	}
+	if existing == nil || existing.Value == value || existing.Confidence < ConflictConfidence {
+		fact, err := s.setFact(tx, category, key, value, source, confidence, subjects, ref, ttl)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, nil, fmt.Errorf("commit set: %w", err)
+		}
+		s.syncFTS(category, key, value, source)
+		return fact, nil, nil
+	}
+
+	conflict, err := recordConflict(tx, existing, value, source, confidence)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("commit conflict: %w", err)
+	}
+	return existing, conflict, nil
+}
+
+// recordConflict opens or updates the pending conflict for existing.
+func recordConflict(tx *sql.Tx, existing *Fact, value, source string, confidence float64) (*Conflict, error) {
+	now := time.Now().UTC()
+	c := &Conflict{
+		Category:           existing.Category,
+		Key:                existing.Key,
+		CurrentValue:       existing.Value,
+		CurrentConfidence:  existing.Confidence,
+		ProposedValue:      value,
+		ProposedConfidence: confidence,
+		Source:             source,
+		CreatedAt:          now,
+	}
+
+	err := tx.QueryRow(`SELECT id FROM fact_conflicts WHERE category = ? AND key = ? AND resolved_at IS NULL`,
+		existing.Category, existing.Key).Scan(&c.ID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		result, err := tx.Exec(`
+			INSERT INTO fact_conflicts (category, key, current_value, current_confidence, proposed_value, proposed_confidence, source, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, c.Category, c.Key, c.CurrentValue, c.CurrentConfidence, c.ProposedValue, c.ProposedConfidence, c.Source, now.Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("insert conflict: %w", err)
+		}
+		c.ID, _ = result.LastInsertId()
+	case err != nil:
+		return nil, fmt.Errorf("check pending conflict: %w", err)
+	default:
+		_, err = tx.Exec(`
+			UPDATE fact_conflicts SET current_value = ?, current_confidence = ?, proposed_value = ?, proposed_confidence = ?, source = ?, created_at = ?
+			WHERE id = ?
+		`, c.CurrentValue, c.CurrentConfidence, c.ProposedValue, c.ProposedConfidence, c.Source, now.Format(time.RFC3339), c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("update conflict: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// PendingConflicts returns unresolved conflicts, oldest first.
+func (s *Store) PendingConflicts() ([]*Conflict, error) {
+	rows, err := s.db.Query(`
+		SELECT id, category, key, current_value, current_confidence, proposed_value, proposed_confidence, source, created_at
+		FROM fact_conflicts WHERE resolved_at IS NULL ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("query conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	var conflicts []*Conflict
+	for rows.Next() {
+		var c Conflict
+		var catStr, createdStr string
+		var source sql.NullString
+		if err := rows.Scan(&c.ID, &catStr, &c.Key, &c.CurrentValue, &c.CurrentConfidence,
+			&c.ProposedValue, &c.ProposedConfidence, &source, &createdStr); err != nil {
+			return nil, err
+		}
+		c.Category = Category(catStr)
+		c.Source = source.String
+		if c.CreatedAt, err = database.ParseTimestamp(createdStr); err != nil {
+			return nil, fmt.Errorf("parse created_at: %w", err)
+		}
+		conflicts = append(conflicts, &c)
+	}
+	return conflicts, rows.Err()
+}
+
+// ResolveConflict settles a pending conflict. [ResolutionKeep] leaves
+// the stored fact as it is; [ResolutionAccept] applies the observed
+// value; [ResolutionValue] applies value, a reconciliation of the two.
+// The fact keeps its subjects and ref. The fact update and the
+// resolution commit together. It returns the fact as it stands after
+// resolution.
+func (s *Store) ResolveConflict(id int64, resolution, value string) (*Fact, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin resolve tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var c Conflict
+	var catStr string
+	var source sql.NullString
+	err = tx.QueryRow(`
+		SELECT category, key, proposed_value, proposed_confidence, source
+		FROM fact_conflicts WHERE id = ? AND resolved_at IS NULL`, id).
+		Scan(&catStr, &c.Key, &c.ProposedValue, &c.ProposedConfidence, &source)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no pending fact conflict %d", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load conflict: %w", err)
+	}
+	c.Category = Category(catStr)
+
+	current, err := s.scanFact(tx.QueryRow(
+		`SELECT `+factColumns+` FROM facts WHERE `+activeFilter+` AND category = ? AND key = ?`,
+		c.Category, c.Key))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("load fact: %w", err)
+	}
+
+	var fact *Fact
+	switch resolution {
+	case ResolutionKeep:
+		fact = current
+	case ResolutionAccept, ResolutionValue:
+		newValue, confidence := c.ProposedValue, c.ProposedConfidence
+		if resolution == ResolutionValue {
+			if strings.TrimSpace(value) == "" {
+				return nil, fmt.Errorf("a value is required to resolve with %q", ResolutionValue)
+			}
+			// A reconciled value is the agent's deliberate judgment.
+			newValue, confidence = value, 1.0
+		}
+		var subjects []string
+		var ref string
+		if current != nil {
+			subjects, ref = current.Subjects, current.Ref
+		}
+		if fact, err = s.setFact(tx, c.Category, c.Key, newValue, source.String, confidence, subjects, ref, 0); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown resolution %q (want %s, %s, or %s)", resolution, ResolutionKeep, ResolutionAccept, ResolutionValue)
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := tx.Exec(`UPDATE fact_conflicts SET resolved_at = ?, resolution = ? WHERE id = ?`, now, resolution, id); err != nil {
+		return nil, fmt.Errorf("mark conflict resolved: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit resolution: %w", err)
+	}
+	if fact != nil && resolution != ResolutionKeep {
+		s.syncFTS(fact.Category, fact.Key, fact.Value, fact.Source)
+	}
+	return fact, nil
+}
+
+// closeConflicts marks every pending conflict on category/key resolved
+// with resolution.
+func closeConflicts(tx *sql.Tx, category Category, key, resolution string) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := tx.Exec(`UPDATE fact_conflicts SET resolved_at = ?, resolution = ? WHERE category = ? AND key = ? AND resolved_at IS NULL`,
+		now, resolution, category, key); err != nil {
+		return fmt.Errorf("close conflicts: %w", err)
+	}
+	return nil
+}
+
+// ConflictContextProvider nudges the agent to reconcile pending fact
+// conflicts. While any are unresolved, each turn's context lists them
+// with a pointer to resolve_fact_conflict; once resolved, it goes
+// quiet.
+type ConflictContextProvider struct {
+	store *Store
+}
+
+// NewConflictContextProvider creates a conflict nudge provider.
+func NewConflictContextProvider(store *Store) *ConflictContextProvider {
+	return &ConflictContextProvider{store: store}
+}
+
+// TagContextBucket places conflict nudges in continuity context: they
+// carry unfinished memory work forward from earlier turns.
+func (p *ConflictContextProvider) TagContextBucket() agentctx.ContextBucket {
+	return agentctx.ContextBucketContinuity
+}
+
+// TagContext returns the pending-conflict nudge, or empty when there
+// is nothing to reconcile.
+func (p *ConflictContextProvider) TagContext(_ context.Context, _ agentctx.ContextRequest) (string, error) {
+	conflicts, err := p.store.PendingConflicts()
+	if err != nil {
+		return "", fmt.Errorf("query fact conflicts: %w", err)
+	}
+	if len(conflicts) == 0 {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Fact Conflicts\n\n")
+	sb.WriteString("New observations contradict facts you hold with high confidence. They were not applied. ")
+	sb.WriteString("When it fits the conversation, reconcile each with resolve_fact_conflict — keep the stored value, accept the new one, or record a value that reconciles both.\n\n")
+	for i, c := range conflicts {
+		if i == maxConflictsInContext {
+			fmt.Fprintf(&sb, "- …and %d more (list_fact_conflicts)\n", len(conflicts)-i)
+			break
+		}
+		fmt.Fprintf(&sb, "- #%d [%s] %s: stored %q (%.1f) vs observed %q (%.1f)\n",
+			c.ID, c.Category, c.Key, c.CurrentValue, c.CurrentConfidence, c.ProposedValue, c.ProposedConfidence)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,279 @@
+package knowledge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/agentctx"
+)
+
+func TestSetWithConflictCheck_HighConfidenceChangeIsHeld(t *testing.T) {
+	store := newTestStore(t)
+
+	if _, err := store.Set(CategoryUser, "home_city", "Austin", "extraction", 0.9, []string{"contact:alice"}, "kb:people/alice.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	fact, conflict, err := store.SetWithConflictCheck(CategoryUser, "home_city", "Denver", "extraction", 0.7, nil, "", 0)
+	if err != nil {
+		t.Fatalf("SetWithConflictCheck: %v", err)
+	}
+	if conflict == nil {
+		t.Fatal("expected a conflict")
+	}
+	if fact.Value != "Austin" {
+		t.Errorf("returned fact value = %q, want unchanged Austin", fact.Value)
+	}
+	if conflict.CurrentValue != "Austin" || conflict.ProposedValue != "Denver" {
+		t.Errorf("conflict = %q vs %q, want Austin vs Denver", conflict.CurrentValue, conflict.ProposedValue)
+	}
+
+	stored, err := store.Get(CategoryUser, "home_city")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Value != "Austin" {
+		t.Errorf("stored value = %q, want Austin", stored.Value)
+	}
+
+	// A second disagreement updates the pending conflict.
+	if _, again, err := store.SetWithConflictCheck(CategoryUser, "home_city", "Boulder", "extraction", 0.6, nil, "", 0); err != nil {
+		t.Fatal(err)
+	} else if again.ID != conflict.ID {
+		t.Errorf("second conflict ID = %d, want %d", again.ID, conflict.ID)
+	}
+	pending, err := store.PendingConflicts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].ProposedValue != "Boulder" {
+		t.Fatalf("pending = %+v, want one conflict proposing Boulder", pending)
+	}
+}
+
+func TestSetWithConflictCheck_OverwritesWhenNoConflict(t *testing.T) {
+	store := newTestStore(t)
+
+	if _, err := store.Set(CategoryUser, "home_city", "Austin", "extraction", 0.5, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Low-confidence facts are corrected in place.
+	fact, conflict, err := store.SetWithConflictCheck(CategoryUser, "home_city", "Denver", "extraction", 0.9, nil, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conflict != nil {
+		t.Errorf("unexpected conflict %+v", conflict)
+	}
+	if fact.Value != "Denver" {
+		t.Errorf("value = %q, want Denver", fact.Value)
+	}
+
+	// The same value at high confidence is a reinforcement, not a conflict.
+	if _, conflict, err = store.SetWithConflictCheck(CategoryUser, "home_city", "Denver", "extraction", 0.95, nil, "", 0); err != nil {
+		t.Fatal(err)
+	} else if conflict != nil {
+		t.Errorf("unexpected conflict on identical value %+v", conflict)
+	}
+
+	// New facts are simply stored.
+	if _, conflict, err = store.SetWithConflictCheck(CategoryUser, "timezone", "America/Denver", "extraction", 0.9, nil, "", 0); err != nil {
+		t.Fatal(err)
+	} else if conflict != nil {
+		t.Errorf("unexpected conflict on new fact %+v", conflict)
+	}
+
+	pending, err := store.PendingConflicts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("pending = %d, want 0", len(pending))
+	}
+}
+
+func TestResolveConflict(t *testing.T) {
+	tests := []struct {
+		resolution string
+		value      string
+		want       string
+	}{
+		{resolution: ResolutionKeep, want: "Austin"},
+		{resolution: ResolutionAccept, want: "Denver"},
+		{resolution: ResolutionValue, value: "Denver (moved 2026)", want: "Denver (moved 2026)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resolution, func(t *testing.T) {
+			store := newTestStore(t)
+			if _, err := store.Set(CategoryUser, "home_city", "Austin", "extraction", 0.9, []string{"contact:alice"}, "kb:people/alice.md"); err != nil {
+				t.Fatal(err)
+			}
+			_, conflict, err := store.SetWithConflictCheck(CategoryUser, "home_city", "Denver", "extraction", 0.7, nil, "", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fact, err := store.ResolveConflict(conflict.ID, tt.resolution, tt.value)
+			if err != nil {
+				t.Fatalf("ResolveConflict: %v", err)
+			}
+			if fact.Value != tt.want {
+				t.Errorf("value = %q, want %q", fact.Value, tt.want)
+			}
+			if fact.Ref != "kb:people/alice.md" || len(fact.Subjects) != 1 || fact.Subjects[0] != "contact:alice" {
+				t.Errorf("subjects/ref not preserved: %v %q", fact.Subjects, fact.Ref)
+			}
+
+			pending, err := store.PendingConflicts()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pending) != 0 {
+				t.Errorf("pending = %d after resolution, want 0", len(pending))
+			}
+			if _, err := store.ResolveConflict(conflict.ID, tt.resolution, tt.value); err == nil {
+				t.Error("expected error resolving an already-resolved conflict")
+			}
+		})
+	}
+}
+
+func TestResolveConflict_Invalid(t *testing.T) {
+	store := newTestStore(t)
+	if _, err := store.Set(CategoryUser, "home_city", "Austin", "extraction", 0.9, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	_, conflict, err := store.SetWithConflictCheck(CategoryUser, "home_city", "Denver", "extraction", 0.7, nil, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.ResolveConflict(conflict.ID, "maybe", ""); err == nil {
+		t.Error("expected error for unknown resolution")
+	}
+	if _, err := store.ResolveConflict(conflict.ID, ResolutionValue, " "); err == nil {
+		t.Error("expected error for value resolution without a value")
+	}
+	if _, err := store.ResolveConflict(conflict.ID+100, ResolutionKeep, ""); err == nil {
+		t.Error("expected error for unknown conflict")
+	}
+}
+
+func TestSet_SupersedesPendingConflict(t *testing.T) {
+	store := newTestStore(t)
+	if _, err := store.Set(CategoryUser, "home_city", "Austin", "extraction", 0.9, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	_, conflict, err := store.SetWithConflictCheck(CategoryUser, "home_city", "Denver", "extraction", 0.7, nil, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-observing the stored value is not a decision; the conflict stays.
+	if _, again, err := store.SetWithConflictCheck(CategoryUser, "home_city", "Austin", "extraction", 0.9, nil, "", 0); err != nil || again != nil {
+		t.Fatalf("re-observe = %+v, %v; want applied without conflict", again, err)
+	}
+	if pending, err := store.PendingConflicts(); err != nil || len(pending) != 1 {
+		t.Fatalf("pending after re-observe = %d, %v; want 1", len(pending), err)
+	}
+
+	if _, err := store.Set(CategoryUser, "home_city", "Boulder", "user", 1.0, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := store.PendingConflicts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("pending after direct set = %+v, want none", pending)
+	}
+	var resolution string
+	if err := store.db.QueryRow(`SELECT resolution FROM fact_conflicts WHERE id = ?`, conflict.ID).Scan(&resolution); err != nil {
+		t.Fatal(err)
+	}
+	if resolution != ResolutionSuperseded {
+		t.Errorf("resolution = %q, want %q", resolution, ResolutionSuperseded)
+	}
+	if _, err := store.ResolveConflict(conflict.ID, ResolutionAccept, ""); err == nil {
+		t.Error("expected error resolving a superseded conflict")
+	}
+}
+
+func TestConflictTools(t *testing.T) {
+	store := newTestStore(t)
+	tools := NewTools(store)
+
+	out, err := tools.ListConflicts("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "No pending") {
+		t.Errorf("empty list = %q", out)
+	}
+
+	if _, err := store.Set(CategoryUser, "home_city", "Austin", "extraction", 0.9, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	_, conflict, err := store.SetWithConflictCheck(CategoryUser, "home_city", "Denver", "extraction", 0.7, nil, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err = tools.ListConflicts("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"home_city", "Austin", "Denver"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("list missing %q: %s", want, out)
+		}
+	}
+
+	if _, err := tools.ResolveConflict(`{"resolution":"keep"}`); err == nil {
+		t.Error("expected error without id")
+	}
+	out, err = tools.ResolveConflict(fmt.Sprintf(`{"id":%d,"resolution":"accept"}`, conflict.ID))
+	if err != nil {
+		t.Fatalf("ResolveConflict: %v", err)
+	}
+	if !strings.Contains(out, "Denver") {
+		t.Errorf("resolve output = %q", out)
+	}
+}
+
+func TestConflictContextProvider(t *testing.T) {
+	store := newTestStore(t)
+	provider := NewConflictContextProvider(store)
+
+	if got := provider.TagContextBucket(); got != agentctx.ContextBucketContinuity {
+		t.Errorf("bucket = %q", got)
+	}
+
+	out, err := provider.TagContext(context.Background(), agentctx.ContextRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "" {
+		t.Errorf("expected no context without conflicts, got %q", out)
+	}
+
+	if _, err := store.Set(CategoryUser, "home_city", "Austin", "extraction", 0.9, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := store.SetWithConflictCheck(CategoryUser, "home_city", "Denver", "extraction", 0.7, nil, "", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err = provider.TagContext(context.Background(), agentctx.ContextRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"resolve_fact_conflict", "home_city", `"Austin"`, `"Denver"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("context missing %q: %s", want, out)
+		}
+	}
+}
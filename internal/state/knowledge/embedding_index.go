@@ -0,0 +1,68 @@
+package knowledge
+
+import "fmt"
+
+// EmbeddingModelCount is the number of stored vectors produced by one
+// embedding model at one dimension. Model is empty for vectors written
+// before the store recorded which model produced them.
+type EmbeddingModelCount struct {
+	Model     string `json:"model"`
+	Dimension int    `json:"dimension"`
+	Count     int    `json:"count"`
+}
+
+// EmbeddingIndex is a store of vectors tagged with the model that
+// produced them. The fact and contact stores both implement it.
+type EmbeddingIndex interface {
+	// AdoptLegacyEmbeddings tags untagged vectors of the given
+	// dimension with the store's current model, returning how many
+	// were adopted.
+	AdoptLegacyEmbeddings(dimension int) (int64, error)
+	// EmbeddingModels counts the active records' vectors by model and
+	// dimension.
+	EmbeddingModels() ([]EmbeddingModelCount, error)
+}
+
+// EmbeddingIndexStatus summarizes how a store's vectors line up with
+// the configured embedding model.
+type EmbeddingIndexStatus struct {
+	Model     string `json:"model"`
+	Dimension int    `json:"dimension"`
+	// Adopted is the number of untagged vectors that matched the
+	// model's dimension and were tagged with it.
+	Adopted int64 `json:"adopted,omitempty"`
+	// Current is the number of vectors usable for semantic search.
+	Current int `json:"current"`
+	// Stale is the number of vectors from another model or of another
+	// dimension. Search ignores them; a backfill re-embeds them.
+	Stale int `json:"stale"`
+	// StaleModels lists where the stale vectors came from.
+	StaleModels []EmbeddingModelCount `json:"stale_models,omitempty"`
+}
+
+// CheckEmbeddingIndex reconciles idx with the configured embedding
+// model. Vectors stored before models were tracked are assumed to come
+// from model when their dimension matches; everything else that
+// doesn't match model and dimension is reported as stale.
+func CheckEmbeddingIndex(idx EmbeddingIndex, model string, dimension int) (EmbeddingIndexStatus, error) {
+	status := EmbeddingIndexStatus{Model: model, Dimension: dimension}
+	adopted, err := idx.AdoptLegacyEmbeddings(dimension)
+	if err != nil {
+		return status, fmt.Errorf("adopt legacy embeddings: %w", err)
+	}
+	status.Adopted = adopted
+
+	counts, err := idx.EmbeddingModels()
+	if err != nil {
+		return status, fmt.Errorf("count embeddings: %w", err)
+	}
+	for _, c := range counts {
+		if c.Model == model && c.Dimension == dimension {
+			status.Current += c.Count
+			continue
+		}
+		status.Stale += c.Count
+		status.StaleModels = append(status.StaleModels, c)
+	}
+	return status, nil
+}
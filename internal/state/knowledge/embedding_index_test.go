@@ -0,0 +1,75 @@
+package knowledge
+
+import "testing"
+
+func TestStore_EmbeddingModelChange(t *testing.T) {
+	store := newTestStore(t)
+
+	legacy, err := store.Set(CategoryHome, "legacy", "embedded before models were tracked", "user", 1.0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldDim, err := store.Set(CategoryHome, "old_dim", "embedded by a smaller model", "user", 1.0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// No model set: vectors are stored untagged, as before.
+	if err := store.SetEmbedding(legacy.ID, []float32{1, 0, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetEmbedding(oldDim.ID, []float32{1, 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	store.SetEmbeddingModel("old-model")
+	other, err := store.Set(CategoryHome, "other", "embedded by the old model", "user", 1.0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetEmbedding(other.ID, []float32{0, 1, 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	store.SetEmbeddingModel("new-model")
+	status, err := CheckEmbeddingIndex(store, "new-model", 3)
+	if err != nil {
+		t.Fatalf("CheckEmbeddingIndex() error = %v", err)
+	}
+	if status.Adopted != 1 || status.Current != 1 || status.Stale != 2 {
+		t.Errorf("status = %+v, want 1 adopted, 1 current, 2 stale", status)
+	}
+
+	facts, _, err := store.SemanticSearch([]float32{1, 0, 0}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(facts) != 1 || facts[0].Key != "legacy" {
+		t.Errorf("SemanticSearch returned %d facts, want only the adopted legacy one", len(facts))
+	}
+
+	pending, err := store.GetFactsWithoutEmbeddings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := map[string]bool{}
+	for _, f := range pending {
+		keys[f.Key] = true
+	}
+	if len(pending) != 2 || !keys["old_dim"] || !keys["other"] {
+		t.Errorf("GetFactsWithoutEmbeddings = %v, want old_dim and other", keys)
+	}
+
+	// Re-embedding with the new model clears the stale set.
+	for _, f := range pending {
+		if err := store.SetEmbedding(f.ID, []float32{0, 0, 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	status, err = CheckEmbeddingIndex(store, "new-model", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Current != 3 || status.Stale != 0 {
+		t.Errorf("after reindex status = %+v, want 3 current, 0 stale", status)
+	}
+}
@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/nugget/thane-ai-agent/internal/platform/httpkit"
@@ -17,6 +22,7 @@ type Client struct {
 	baseURL string
 	model   string
 	client  *http.Client
+	dim     atomic.Int64 // length of the last vector the model returned
 }
 
 // Config for embedding client.
@@ -39,6 +45,26 @@ func New(cfg Config) *Client {
 	}
 }
 
+// Model returns the embedding model this client asks Ollama for.
+func (c *Client) Model() string {
+	return c.model
+}
+
+// Dimension returns the length of the vectors the model produces, as
+// observed on the most recent successful call. It is 0 until the
+// client has generated at least one embedding; call [Client.Generate]
+// once to probe it.
+func (c *Client) Dimension() int {
+	return int(c.dim.Load())
+}
+
+// observe records the dimension of a vector the model returned.
+func (c *Client) observe(vec []float32) {
+	if len(vec) > 0 {
+		c.dim.Store(int64(len(vec)))
+	}
+}
+
 // embedRequest is the Ollama embedding API request.
 type embedRequest struct {
 	Model  string `json:"model"`
@@ -84,22 +110,122 @@ func (c *Client) Generate(ctx context.Context, text string) ([]float32, error) {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
+	c.observe(embedResp.Embedding)
 	return embedResp.Embedding, nil
 }
 
-// GenerateBatch creates embeddings for multiple texts.
+// embedBatchRequest is the Ollama batch embedding API request.
+type embedBatchRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embedBatchResponse is the Ollama batch embedding API response.
+type embedBatchResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// BatchError reports the texts a [Client.GenerateBatch] call could
+// not embed, keyed by their index in the input.
+type BatchError struct {
+	Failed map[int]error
+}
+
+func (e *BatchError) Error() string {
+	idx := make([]int, 0, len(e.Failed))
+	for i := range e.Failed {
+		idx = append(idx, i)
+	}
+	sort.Ints(idx)
+	parts := make([]string, 0, min(len(idx), 3))
+	for _, i := range idx[:min(len(idx), 3)] {
+		parts = append(parts, fmt.Sprintf("text %d: %v", i, e.Failed[i]))
+	}
+	msg := fmt.Sprintf("%d of batch failed to embed: %s", len(idx), strings.Join(parts, "; "))
+	if len(idx) > 3 {
+		msg += "; ..."
+	}
+	return msg
+}
+
+// GenerateBatch creates embeddings for multiple texts, sending them in
+// a single request to Ollama's batch endpoint. If that request fails
+// (an older Ollama without /api/embed, or one input the model
+// rejects), each text is retried on its own so one bad input doesn't
+// sink the rest. When some texts still fail, results holds the vectors
+// that succeeded, nil for the rest, and err is a *[BatchError] naming
+// the failures. If Ollama can't be reached at all, the transport error
+// is returned as is.
 func (c *Client) GenerateBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	if results, err := c.generateBatch(ctx, texts); err == nil {
+		return results, nil
+	} else if ctx.Err() != nil {
+		return nil, err
+	}
+
 	results := make([][]float32, len(texts))
+	failed := make(map[int]error)
 	for i, text := range texts {
 		emb, err := c.Generate(ctx, text)
 		if err != nil {
-			return nil, fmt.Errorf("embed text %d: %w", i, err)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				return nil, err
+			}
+			failed[i] = err
+			continue
 		}
 		results[i] = emb
 	}
+	if len(failed) > 0 {
+		return results, &BatchError{Failed: failed}
+	}
 	return results, nil
 }
 
+// generateBatch embeds all texts in one /api/embed request.
+func (c *Client) generateBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(embedBatchRequest{Model: c.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/embed", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errBody := httpkit.ReadErrorBody(resp.Body, 512)
+		return nil, fmt.Errorf("ollama returned status %d: %s", resp.StatusCode, errBody)
+	}
+
+	var embedResp embedBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if len(embedResp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama returned %d embeddings for %d inputs", len(embedResp.Embeddings), len(texts))
+	}
+	if len(embedResp.Embeddings) > 0 {
+		c.observe(embedResp.Embeddings[0])
+	}
+	return embedResp.Embeddings, nil
+}
+
 // CosineSimilarity computes cosine similarity between two vectors.
 func CosineSimilarity(a, b []float32) float32 {
 	if len(a) != len(b) || len(a) == 0 {
@@ -1,7 +1,12 @@
 package knowledge
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"math"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -79,3 +84,110 @@ func TestTopK(t *testing.T) {
 		t.Errorf("expected index 3 (similar) second, got %d", top2[1])
 	}
 }
+
+// fakeOllamaEmbed serves /api/embed and /api/embeddings, embedding each
+// text as [len(text)]. Texts listed in reject fail both endpoints, and
+// batchDown makes /api/embed fail outright.
+func fakeOllamaEmbed(t *testing.T, reject map[string]bool, batchDown bool) (*httptest.Server, *int) {
+	t.Helper()
+	batchCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/embed":
+			batchCalls++
+			var req embedBatchRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode batch request: %v", err)
+			}
+			if batchDown {
+				http.NotFound(w, r)
+				return
+			}
+			resp := embedBatchResponse{}
+			for _, text := range req.Input {
+				if reject[text] {
+					http.Error(w, "input too long", http.StatusBadRequest)
+					return
+				}
+				resp.Embeddings = append(resp.Embeddings, []float32{float32(len(text))})
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		case "/api/embeddings":
+			var req embedRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode request: %v", err)
+			}
+			if reject[req.Prompt] {
+				http.Error(w, "input too long", http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(embedResponse{Embedding: []float32{float32(len(req.Prompt))}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &batchCalls
+}
+
+func TestGenerateBatch_SingleRequest(t *testing.T) {
+	srv, batchCalls := fakeOllamaEmbed(t, nil, false)
+	c := New(Config{BaseURL: srv.URL})
+
+	got, err := c.GenerateBatch(context.Background(), []string{"a", "bb", "ccc"})
+	if err != nil {
+		t.Fatalf("GenerateBatch() error = %v", err)
+	}
+	if *batchCalls != 1 {
+		t.Errorf("batch requests = %d, want 1", *batchCalls)
+	}
+	for i, want := range []float32{1, 2, 3} {
+		if len(got[i]) != 1 || got[i][0] != want {
+			t.Errorf("got[%d] = %v, want [%v]", i, got[i], want)
+		}
+	}
+}
+
+func TestGenerateBatch_RetriesFailedBatchIndividually(t *testing.T) {
+	srv, _ := fakeOllamaEmbed(t, map[string]bool{"bad": true}, false)
+	c := New(Config{BaseURL: srv.URL})
+
+	got, err := c.GenerateBatch(context.Background(), []string{"a", "bad", "ccc"})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("GenerateBatch() error = %v, want *BatchError", err)
+	}
+	if len(batchErr.Failed) != 1 || batchErr.Failed[1] == nil {
+		t.Errorf("Failed = %v, want only index 1", batchErr.Failed)
+	}
+	if got[0] == nil || got[1] != nil || got[2] == nil {
+		t.Errorf("results = %v, want vectors for 0 and 2 only", got)
+	}
+}
+
+func TestGenerateBatch_FallsBackWithoutBatchEndpoint(t *testing.T) {
+	srv, _ := fakeOllamaEmbed(t, nil, true)
+	c := New(Config{BaseURL: srv.URL})
+
+	got, err := c.GenerateBatch(context.Background(), []string{"a", "bb"})
+	if err != nil {
+		t.Fatalf("GenerateBatch() error = %v", err)
+	}
+	if len(got) != 2 || got[1][0] != 2 {
+		t.Errorf("results = %v", got)
+	}
+}
+
+func TestClient_Dimension(t *testing.T) {
+	srv, _ := fakeOllamaEmbed(t, nil, false)
+	c := New(Config{BaseURL: srv.URL})
+	if c.Dimension() != 0 {
+		t.Fatalf("Dimension() before any call = %d, want 0", c.Dimension())
+	}
+	if _, err := c.Generate(context.Background(), "probe"); err != nil {
+		t.Fatal(err)
+	}
+	if c.Dimension() != 1 {
+		t.Errorf("Dimension() = %d, want 1", c.Dimension())
+	}
+}
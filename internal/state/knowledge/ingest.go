@@ -4,23 +4,29 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
 
-// MarkdownIngester parses markdown documents into
-type MarkdownIngester struct {
+// Ingester parses documents — markdown, plain text, or PDF — into
+// facts, generating an embedding for each when an embedding client is
+// set.
+type Ingester struct {
 	store      *Store
 	embeddings EmbeddingClient
 	source     string
 	category   Category
+	keyPrefix  string
 }
 
-// NewMarkdownIngester creates a markdown document ingester.
-// Category determines how facts are categorized (e.g., CategoryArchitecture).
-func NewMarkdownIngester(store *Store, embeddings EmbeddingClient, source string, category Category) *MarkdownIngester {
-	return &MarkdownIngester{
+// NewIngester creates a document ingester. Category determines how
+// facts are categorized (e.g., CategoryArchitecture); markdown
+// documents can override it with category directives.
+func NewIngester(store *Store, embeddings EmbeddingClient, source string, category Category) *Ingester {
+	return &Ingester{
 		store:      store,
 		embeddings: embeddings,
 		source:     source,
@@ -28,45 +34,152 @@ func NewMarkdownIngester(store *Store, embeddings EmbeddingClient, source string
 	}
 }
 
+// SetKeyPrefix prefixes every fact key the ingester writes with
+// prefix and a slash, keeping facts from different documents with the
+// same headings apart.
+func (m *Ingester) SetKeyPrefix(prefix string) {
+	m.keyPrefix = prefix
+}
+
+// IngestFormat is a document format the ingester can parse.
+type IngestFormat string
+
+// Supported ingest formats.
+const (
+	IngestMarkdown IngestFormat = "markdown"
+	IngestText     IngestFormat = "text"
+	IngestPDF      IngestFormat = "pdf"
+)
+
+// IngestFormatForPath picks the ingest format from a file's extension
+// (.md/.markdown, .txt/.text, .pdf), or returns an error naming the
+// supported types.
+func IngestFormatForPath(path string) (IngestFormat, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".md", ".markdown":
+		return IngestMarkdown, nil
+	case ".txt", ".text":
+		return IngestText, nil
+	case ".pdf":
+		return IngestPDF, nil
+	case "":
+		return "", fmt.Errorf("cannot ingest %s: no file extension (supported: .md, .txt, .pdf)", filepath.Base(path))
+	default:
+		return "", fmt.Errorf("cannot ingest %s: unsupported file type %s (supported: .md, .txt, .pdf)", filepath.Base(path), ext)
+	}
+}
+
 // Chunk represents a semantic unit from the document.
 type Chunk struct {
 	Key     string
 	Content string
 	Section string
+	// Path is the heading hierarchy the chunk sits under, outermost
+	// first (e.g. ["Network", "VLANs", "Guest"]).
+	Path []string
+	// Category overrides the ingester's category for this chunk. It
+	// is set by a category directive in the document's frontmatter or
+	// under an enclosing heading; empty means the ingester default.
+	Category Category
+}
+
+// Heading returns the chunk's heading path for display, e.g.
+// "Network > VLANs > Guest".
+func (c Chunk) Heading() string {
+	return strings.Join(c.Path, " > ")
 }
 
-// IngestFile reads and processes a markdown file into
-func (m *MarkdownIngester) IngestFile(ctx context.Context, path string) (int, error) {
+// Subjects returns the topic subject keys for the chunk: one per
+// level of its heading path, so a fact under "Network > VLANs > Guest"
+// is retrieved for topic:network, topic:network/vlans, and
+// topic:network/vlans/guest.
+func (c Chunk) Subjects() []string {
+	subjects := make([]string, 0, len(c.Path))
+	var key string
+	for _, h := range c.Path {
+		slug := slugify(h)
+		if slug == "" {
+			continue
+		}
+		if key != "" {
+			key += "/"
+		}
+		key += slug
+		subjects = append(subjects, SubjectTopic+":"+key)
+	}
+	return subjects
+}
+
+// IngestFile reads a document and stores its facts, choosing the
+// parser by file extension (see [IngestFormatForPath]). Plain-text and
+// PDF facts are keyed under the file's base name.
+func (m *Ingester) IngestFile(ctx context.Context, path string) (int, error) {
+	format, err := IngestFormatForPath(path)
+	if err != nil {
+		return 0, err
+	}
+	if format == IngestPDF {
+		pages, err := extractPDFText(path)
+		if err != nil {
+			return 0, err
+		}
+		return m.ingestChunks(ctx, pdfChunks(pages, documentTitle(path)))
+	}
+
 	file, err := os.Open(path)
 	if err != nil {
 		return 0, fmt.Errorf("open file: %w", err)
 	}
 	defer file.Close()
 
+	if format == IngestText {
+		return m.IngestText(ctx, file, documentTitle(path))
+	}
 	chunks := parseMarkdown(file)
 	return m.ingestChunks(ctx, chunks)
 }
 
 // IngestString processes markdown content from a string.
-func (m *MarkdownIngester) IngestString(ctx context.Context, content string) (int, error) {
+func (m *Ingester) IngestString(ctx context.Context, content string) (int, error) {
 	chunks := parseMarkdown(strings.NewReader(content))
 	return m.ingestChunks(ctx, chunks)
 }
 
-func (m *MarkdownIngester) ingestChunks(ctx context.Context, chunks []Chunk) (int, error) {
+// IngestText processes plain text, one fact per paragraph, keyed
+// under title.
+func (m *Ingester) IngestText(ctx context.Context, r io.Reader, title string) (int, error) {
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return 0, fmt.Errorf("read text: %w", err)
+	}
+	return m.ingestChunks(ctx, textChunks(string(text), []string{title}))
+}
+
+// ingestChunks replaces the source's facts with chunks. Every format
+// ends here, so facts from any document embed the same way.
+func (m *Ingester) ingestChunks(ctx context.Context, chunks []Chunk) (int, error) {
 	// Delete existing facts from this source (enables clean re-imports)
 	_ = m.store.DeleteBySource(m.source)
 
 	count := 0
 	for _, chunk := range chunks {
+		category := m.category
+		if chunk.Category != "" {
+			category = chunk.Category
+		}
+		key := chunk.Key
+		if m.keyPrefix != "" {
+			key = m.keyPrefix + "/" + key
+		}
+
 		// Store the fact
 		fact, err := m.store.Set(
-			m.category,
-			chunk.Key,
+			category,
+			key,
 			chunk.Content,
 			m.source,
 			1.0,
-			nil,
+			chunk.Subjects(),
 			"",
 		)
 		if err != nil {
@@ -75,7 +188,7 @@ func (m *MarkdownIngester) ingestChunks(ctx context.Context, chunks []Chunk) (in
 
 		// Generate and store embedding
 		if m.embeddings != nil {
-			embText := fmt.Sprintf("%s: %s - %s", m.category, chunk.Key, chunk.Content)
+			embText := fmt.Sprintf("%s: %s - %s", category, chunk.Heading(), chunk.Content)
 			if emb, err := m.embeddings.Generate(ctx, embText); err == nil {
 				_ = m.store.SetEmbedding(fact.ID, emb)
 			}
@@ -87,37 +200,88 @@ func (m *MarkdownIngester) ingestChunks(ctx context.Context, chunks []Chunk) (in
 	return count, nil
 }
 
-// parseMarkdown extracts semantic chunks from markdown content.
-func parseMarkdown(r interface{ Read([]byte) (int, error) }) []Chunk {
+var (
+	headingPattern   = regexp.MustCompile(`^(#{1,6})\s+(.+?)(?:\s+#+)?\s*$`)
+	codeBlockPattern = regexp.MustCompile("^```")
+	// categoryDirectivePattern matches an inline category override,
+	// "<!-- category: device -->", on a line of its own.
+	categoryDirectivePattern = regexp.MustCompile(`^\s*<!--\s*category:\s*([A-Za-z0-9_-]+)\s*-->\s*$`)
+)
+
+// headingFrame is one open heading while parsing: its level, title,
+// and the category in force beneath it.
+type headingFrame struct {
+	level    int
+	title    string
+	category Category
+}
+
+// parseMarkdown extracts semantic chunks from markdown content. Each
+// heading starts a chunk keyed by the slugs of the headings enclosing
+// it ("network/vlans/guest"). A "category:" line in YAML frontmatter
+// sets the category for the whole document, and a
+// "<!-- category: name -->" line under a heading overrides it for that
+// heading and everything nested below it.
+func parseMarkdown(r io.Reader) []Chunk {
 	var chunks []Chunk
 	scanner := bufio.NewScanner(r)
 
-	var currentH1, currentH2 string
+	var stack []headingFrame
+	var docCategory Category
 	var currentContent strings.Builder
-	var lastKey string
+
+	current := func() Category {
+		if len(stack) == 0 {
+			return docCategory
+		}
+		return stack[len(stack)-1].category
+	}
 
 	flushChunk := func() {
 		content := strings.TrimSpace(currentContent.String())
-		if content != "" && lastKey != "" {
-			chunks = append(chunks, Chunk{
-				Key:     lastKey,
-				Content: content,
-				Section: currentH1,
-			})
-		}
 		currentContent.Reset()
+		if content == "" || len(stack) == 0 {
+			return
+		}
+		path := make([]string, len(stack))
+		slugs := make([]string, len(stack))
+		for i, f := range stack {
+			path[i] = f.title
+			slugs[i] = slugify(f.title)
+		}
+		section := ""
+		if stack[0].level == 1 {
+			section = stack[0].title
+		}
+		chunks = append(chunks, Chunk{
+			Key:      strings.Join(slugs, "/"),
+			Content:  content,
+			Section:  section,
+			Path:     path,
+			Category: current(),
+		})
 	}
 
-	h1Pattern := regexp.MustCompile(`^#\s+(.+)$`)
-	h2Pattern := regexp.MustCompile(`^##\s+(.+)$`)
-	h3Pattern := regexp.MustCompile(`^###\s+(.+)$`)
-	codeBlockPattern := regexp.MustCompile("^```")
-
 	inCodeBlock := false
+	inFrontmatter := false
 
-	for scanner.Scan() {
+	for lineNo := 0; scanner.Scan(); lineNo++ {
 		line := scanner.Text()
 
+		// YAML frontmatter: only the category key is read.
+		if lineNo == 0 && strings.TrimSpace(line) == "---" {
+			inFrontmatter = true
+			continue
+		}
+		if inFrontmatter {
+			if strings.TrimSpace(line) == "---" {
+				inFrontmatter = false
+			} else if k, v, ok := strings.Cut(line, ":"); ok && strings.TrimSpace(k) == "category" {
+				docCategory = Category(strings.ToLower(strings.Trim(strings.TrimSpace(v), `"'`)))
+			}
+			continue
+		}
+
 		// Track code blocks
 		if codeBlockPattern.MatchString(line) {
 			inCodeBlock = !inCodeBlock
@@ -130,36 +294,24 @@ func parseMarkdown(r interface{ Read([]byte) (int, error) }) []Chunk {
 			continue
 		}
 
-		// Check for headers
-		if m := h1Pattern.FindStringSubmatch(line); m != nil {
-			flushChunk()
-			currentH1 = m[1]
-			currentH2 = ""
-			lastKey = slugify(currentH1)
-			continue
-		}
-
-		if m := h2Pattern.FindStringSubmatch(line); m != nil {
-			flushChunk()
-			currentH2 = m[1]
-			if currentH1 != "" {
-				lastKey = slugify(currentH1) + "/" + slugify(currentH2)
+		if m := categoryDirectivePattern.FindStringSubmatch(line); m != nil {
+			category := Category(strings.ToLower(m[1]))
+			if len(stack) == 0 {
+				docCategory = category
 			} else {
-				lastKey = slugify(currentH2)
+				stack[len(stack)-1].category = category
 			}
 			continue
 		}
 
-		if m := h3Pattern.FindStringSubmatch(line); m != nil {
+		// A heading closes every open heading at its level or deeper.
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
 			flushChunk()
-			h3 := m[1]
-			if currentH2 != "" {
-				lastKey = slugify(currentH1) + "/" + slugify(currentH2) + "/" + slugify(h3)
-			} else if currentH1 != "" {
-				lastKey = slugify(currentH1) + "/" + slugify(h3)
-			} else {
-				lastKey = slugify(h3)
+			level := len(m[1])
+			for len(stack) > 0 && stack[len(stack)-1].level >= level {
+				stack = stack[:len(stack)-1]
 			}
+			stack = append(stack, headingFrame{level: level, title: m[2], category: current()})
 			continue
 		}
 
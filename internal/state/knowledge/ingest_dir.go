@@ -0,0 +1,341 @@
+package knowledge
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IngestSource records the state of an ingested document when its
+// facts were last written.
+type IngestSource struct {
+	Source      string    `json:"source"`
+	ContentHash string    `json:"content_hash"`
+	FactCount   int       `json:"fact_count"`
+	IngestedAt  time.Time `json:"ingested_at"`
+}
+
+// RecordIngest stores the content hash and fact count source had when
+// it was ingested, replacing any earlier record.
+func (s *Store) RecordIngest(source, contentHash string, factCount int) error {
+	_, err := s.db.Exec(`
+		INSERT INTO ingest_sources (source, content_hash, fact_count, ingested_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(source) DO UPDATE SET
+			content_hash = excluded.content_hash,
+			fact_count = excluded.fact_count,
+			ingested_at = excluded.ingested_at
+	`, source, contentHash, factCount, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+// IngestSources returns the recorded ingest sources whose name starts
+// with prefix, ordered by source.
+func (s *Store) IngestSources(prefix string) ([]IngestSource, error) {
+	return queryIngestSources(s.db, prefix)
+}
+
+// queryIngestSources implements [Store.IngestSources] against db.
+func queryIngestSources(db *sql.DB, prefix string) ([]IngestSource, error) {
+	rows, err := db.Query(`
+		SELECT source, content_hash, fact_count, ingested_at FROM ingest_sources
+		WHERE substr(source, 1, length(?)) = ? ORDER BY source
+	`, prefix, prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sources []IngestSource
+	for rows.Next() {
+		var src IngestSource
+		var ingestedStr string
+		if err := rows.Scan(&src.Source, &src.ContentHash, &src.FactCount, &ingestedStr); err != nil {
+			return nil, err
+		}
+		src.IngestedAt, _ = time.Parse(time.RFC3339, ingestedStr)
+		sources = append(sources, src)
+	}
+	return sources, rows.Err()
+}
+
+// ForgetIngest removes source's facts and its ingest record, for a
+// document that no longer exists.
+func (s *Store) ForgetIngest(source string) error {
+	if err := s.DeleteBySource(source); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`DELETE FROM ingest_sources WHERE source = ?`, source)
+	return err
+}
+
+// FileSource is the fact source name for a document ingested from
+// path: "file:" and the absolute path.
+func FileSource(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return "file:" + path
+}
+
+// ContentHash returns the hex SHA-256 of data, the hash recorded for
+// ingested documents.
+func ContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// DirIngestOptions controls [IngestDir].
+type DirIngestOptions struct {
+	// Category is the default category for ingested facts.
+	Category Category
+	// Exclude lists glob patterns (see [filepath.Match]) for files and
+	// directories to skip. A pattern is matched against both the path
+	// relative to the ingested directory and the entry's base name.
+	Exclude []string
+}
+
+// DirIngestResult reports what [IngestDir] did, or what
+// [PreviewIngestDir] found it would do. Paths are relative to the ingested directory.
+type DirIngestResult struct {
+	Ingested  []string          `json:"ingested,omitempty"`
+	Unchanged []string          `json:"unchanged,omitempty"`
+	Removed   []string          `json:"removed,omitempty"`
+	Failed    map[string]string `json:"failed,omitempty"`
+	Facts     int               `json:"facts"`
+}
+
+// IngestDir ingests every supported document (see
+// [IngestFormatForPath]) under dir, recursively, and keeps the fact
+// store in sync with it on later runs. A file is re-ingested only when
+// its content hash differs from the one recorded last time, and facts
+// from files that have since been deleted (or excluded) are removed.
+// Hidden files and directories are skipped, as are files of
+// unsupported types.
+//
+// Fact keys are prefixed with the file's path relative to dir so
+// documents with the same headings don't overwrite each other. A file
+// that fails to ingest is reported in the result and doesn't stop the
+// rest.
+func IngestDir(ctx context.Context, store *Store, embeddings EmbeddingClient, dir string, opts DirIngestOptions) (DirIngestResult, error) {
+	if opts.Category == "" {
+		opts.Category = CategoryArchitecture
+	}
+	plan, err := planIngestDir(ctx, dir, opts, store.IngestSources)
+	if err != nil {
+		return plan.result, err
+	}
+	result := plan.result
+
+	for _, f := range plan.changed {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		path := filepath.Join(plan.root, f.rel)
+		source := FileSource(path)
+		ing := NewIngester(store, embeddings, source, opts.Category)
+		ing.SetKeyPrefix(dirKeyPrefix(f.rel))
+		n, err := ing.IngestFile(ctx, path)
+		if err != nil {
+			result.fail(f.rel, err)
+			continue
+		}
+		if err := store.RecordIngest(source, f.hash, n); err != nil {
+			return result, fmt.Errorf("record ingest of %s: %w", f.rel, err)
+		}
+		result.Ingested = append(result.Ingested, f.rel)
+		result.Facts += n
+	}
+
+	for _, source := range plan.gone {
+		rel := strings.TrimPrefix(source, plan.prefix)
+		if err := store.ForgetIngest(source); err != nil {
+			return result, fmt.Errorf("remove facts from %s: %w", rel, err)
+		}
+		result.Removed = append(result.Removed, rel)
+	}
+	sort.Strings(result.Removed)
+	return result, nil
+}
+
+// PreviewIngestDir reports what [IngestDir] would change for dir
+// without writing anything. It reads the ingest records straight from
+// db, which may be opened read-only, and never creates or migrates the
+// schema. A nil db, or one without ingest records, means nothing has
+// been ingested yet.
+func PreviewIngestDir(ctx context.Context, db *sql.DB, dir string, opts DirIngestOptions) (DirIngestResult, error) {
+	list := func(prefix string) ([]IngestSource, error) {
+		if db == nil {
+			return nil, nil
+		}
+		var tables int
+		err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'ingest_sources'`).Scan(&tables)
+		if err != nil || tables == 0 {
+			return nil, err
+		}
+		return queryIngestSources(db, prefix)
+	}
+	plan, err := planIngestDir(ctx, dir, opts, list)
+	if err != nil {
+		return plan.result, err
+	}
+	result := plan.result
+	for _, f := range plan.changed {
+		result.Ingested = append(result.Ingested, f.rel)
+	}
+	for _, source := range plan.gone {
+		result.Removed = append(result.Removed, strings.TrimPrefix(source, plan.prefix))
+	}
+	sort.Strings(result.Removed)
+	return result, nil
+}
+
+// dirIngestPlan is the difference between a directory and its ingest
+// records: the files whose content changed and the recorded sources
+// that are no longer there.
+type dirIngestPlan struct {
+	root    string // absolute path of the ingested directory
+	prefix  string // source prefix shared by every file under root
+	changed []changedFile
+	gone    []string
+	// result holds the unchanged files and the ones that could not be
+	// read.
+	result DirIngestResult
+}
+
+// changedFile is a file due for ingest and the content hash it had.
+type changedFile struct {
+	rel  string
+	hash string
+}
+
+// planIngestDir walks dir and compares it with the ingest records that
+// list returns for its source prefix.
+func planIngestDir(ctx context.Context, dir string, opts DirIngestOptions, list func(prefix string) ([]IngestSource, error)) (dirIngestPlan, error) {
+	var plan dirIngestPlan
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return plan, fmt.Errorf("resolve %s: %w", dir, err)
+	}
+	plan.root = root
+	for _, pattern := range opts.Exclude {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return plan, fmt.Errorf("bad exclude pattern %q: %w", pattern, err)
+		}
+	}
+
+	var files []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		if strings.HasPrefix(d.Name(), ".") || excluded(opts.Exclude, rel, d.Name()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		if _, err := IngestFormatForPath(path); err != nil {
+			return nil
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return plan, fmt.Errorf("walk %s: %w", dir, err)
+	}
+
+	plan.prefix = FileSource(root) + string(filepath.Separator)
+	known, err := list(plan.prefix)
+	if err != nil {
+		return plan, fmt.Errorf("list ingested sources: %w", err)
+	}
+	hashes := make(map[string]string, len(known))
+	for _, src := range known {
+		hashes[src.Source] = src.ContentHash
+	}
+
+	seen := make(map[string]bool)
+	for _, rel := range files {
+		if err := ctx.Err(); err != nil {
+			return plan, err
+		}
+		path := filepath.Join(root, rel)
+		source := FileSource(path)
+		seen[source] = true
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			plan.result.fail(rel, err)
+			continue
+		}
+		hash := ContentHash(data)
+		if hashes[source] == hash {
+			plan.result.Unchanged = append(plan.result.Unchanged, rel)
+			continue
+		}
+		plan.changed = append(plan.changed, changedFile{rel: rel, hash: hash})
+	}
+
+	for _, src := range known {
+		if !seen[src.Source] {
+			plan.gone = append(plan.gone, src.Source)
+		}
+	}
+	return plan, nil
+}
+
+// fail records a per-file failure.
+func (r *DirIngestResult) fail(rel string, err error) {
+	if r.Failed == nil {
+		r.Failed = make(map[string]string)
+	}
+	r.Failed[rel] = err.Error()
+}
+
+// excluded reports whether any pattern matches rel or name.
+func excluded(patterns []string, rel, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, rel); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// dirKeyPrefix is the key prefix for facts from the file at rel in a
+// directory ingest. Markdown keys are heading paths, so they get the
+// whole relative path; plain-text and PDF keys already start with the
+// file name, so they get only its directory.
+func dirKeyPrefix(rel string) string {
+	dir := filepath.Dir(rel)
+	if format, _ := IngestFormatForPath(rel); format == IngestMarkdown {
+		dir = strings.TrimSuffix(rel, filepath.Ext(rel))
+	}
+	if dir == "." {
+		return ""
+	}
+	parts := strings.Split(filepath.ToSlash(dir), "/")
+	for i, p := range parts {
+		parts[i] = slugify(p)
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,140 @@
+package knowledge
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIngestDir_Incremental(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "network.md"), "# Setup\n\nRouter in the closet.\n")
+	writeFile(t, filepath.Join(dir, "garage", "door.md"), "# Setup\n\nOpener on circuit 12.\n")
+	writeFile(t, filepath.Join(dir, "manuals", "boiler.txt"), "Bleed radiators.\n\nService yearly.\n")
+	writeFile(t, filepath.Join(dir, "drafts", "wip.md"), "# WIP\n\nNot ready.\n")
+	writeFile(t, filepath.Join(dir, ".obsidian", "cache.md"), "# Cache\n\nIgnored.\n")
+	writeFile(t, filepath.Join(dir, "photo.jpg"), "not a document")
+
+	store := newTestStore(t)
+	ctx := context.Background()
+	opts := DirIngestOptions{Exclude: []string{"drafts"}}
+
+	first, err := IngestDir(ctx, store, nil, dir, opts)
+	if err != nil {
+		t.Fatalf("IngestDir: %v", err)
+	}
+	want := []string{"garage/door.md", "manuals/boiler.txt", "network.md"}
+	if !slices.Equal(first.Ingested, want) {
+		t.Errorf("first run ingested %v, want %v", first.Ingested, want)
+	}
+	if first.Facts != 4 {
+		t.Errorf("first run facts = %d, want 4", first.Facts)
+	}
+	// Same heading in two documents: keys stay apart.
+	for _, key := range []string{"network/setup", "garage/door/setup", "manuals/boiler/1"} {
+		if _, err := store.Get(CategoryArchitecture, key); err != nil {
+			t.Errorf("Get(%q): %v", key, err)
+		}
+	}
+
+	// Nothing changed: nothing re-ingested.
+	second, err := IngestDir(ctx, store, nil, dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Ingested) != 0 || len(second.Unchanged) != 3 {
+		t.Errorf("second run = %+v, want 3 unchanged", second)
+	}
+
+	// Edit one file, delete another.
+	writeFile(t, filepath.Join(dir, "network.md"), "# Setup\n\nRouter moved to the office.\n")
+	if err := os.Remove(filepath.Join(dir, "garage", "door.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	dry, err := PreviewIngestDir(ctx, store.db, dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(dry.Ingested, []string{"network.md"}) || !slices.Equal(dry.Removed, []string{"garage/door.md"}) {
+		t.Errorf("dry run = %+v", dry)
+	}
+	if f, err := store.Get(CategoryArchitecture, "network/setup"); err != nil || f.Value != "Router in the closet." {
+		t.Errorf("dry run changed facts: %v %v", f, err)
+	}
+
+	third, err := IngestDir(ctx, store, nil, dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(third.Ingested, []string{"network.md"}) || !slices.Equal(third.Removed, []string{"garage/door.md"}) {
+		t.Errorf("third run = %+v", third)
+	}
+	if f, err := store.Get(CategoryArchitecture, "network/setup"); err != nil || f.Value != "Router moved to the office." {
+		t.Errorf("network/setup = %v, %v; want updated value", f, err)
+	}
+	if _, err := store.Get(CategoryArchitecture, "garage/door/setup"); err == nil {
+		t.Error("facts from deleted file still present")
+	}
+	sources, err := store.IngestSources(FileSource(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 2 {
+		t.Errorf("tracked sources = %d, want 2", len(sources))
+	}
+}
+
+func TestPreviewIngestDir_LeavesDatabaseUntouched(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "network.md"), "# Setup\n\nRouter in the closet.\n")
+	ctx := context.Background()
+
+	// No database yet.
+	res, err := PreviewIngestDir(ctx, nil, dir, DirIngestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(res.Ingested, []string{"network.md"}) {
+		t.Errorf("preview without a database = %+v", res)
+	}
+
+	// A database that predates ingest tracking is read, not migrated.
+	dbPath := filepath.Join(t.TempDir(), "knowledge.db")
+	writeFile(t, dbPath, "")
+	db, err := database.OpenReadOnly(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	res, err = PreviewIngestDir(ctx, db, dir, DirIngestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(res.Ingested, []string{"network.md"}) {
+		t.Errorf("preview against an empty database = %+v", res)
+	}
+	if info, err := os.Stat(dbPath); err != nil || info.Size() != 0 {
+		t.Errorf("database written by preview: %v, %v", info, err)
+	}
+}
+
+func TestIngestDir_BadExclude(t *testing.T) {
+	if _, err := IngestDir(context.Background(), newTestStore(t), nil, t.TempDir(), DirIngestOptions{Exclude: []string{"["}}); err == nil {
+		t.Fatal("IngestDir accepted a malformed exclude pattern")
+	}
+}
@@ -0,0 +1,153 @@
+package knowledge
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIngestFormatForPath(t *testing.T) {
+	tests := []struct {
+		path      string
+		want      IngestFormat
+		wantError string
+	}{
+		{path: "notes/layout.md", want: IngestMarkdown},
+		{path: "README.MARKDOWN", want: IngestMarkdown},
+		{path: "manual.txt", want: IngestText},
+		{path: "manual.PDF", want: IngestPDF},
+		{path: "manual.docx", wantError: "unsupported file type .docx"},
+		{path: "Makefile", wantError: "no file extension"},
+	}
+	for _, tt := range tests {
+		got, err := IngestFormatForPath(tt.path)
+		if tt.wantError != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("IngestFormatForPath(%q) error = %v, want %q", tt.path, err, tt.wantError)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("IngestFormatForPath(%q) = %q, %v; want %q", tt.path, got, err, tt.want)
+		}
+	}
+}
+
+func TestTextChunks(t *testing.T) {
+	long := strings.Repeat("The filter light blinks after 90 days of use.\n", 60)
+	text := "Dishwasher Manual\r\n\r\nRun the cleaning cycle monthly.\nUse the top rack for plastics.\n\n\n\n" + long
+
+	chunks := textChunks(text, []string{"Dishwasher Manual"})
+
+	if len(chunks) < 4 {
+		t.Fatalf("expected the long paragraph to be split, got %d chunks", len(chunks))
+	}
+	if chunks[0].Key != "dishwasher-manual/1" || chunks[0].Content != "Dishwasher Manual" {
+		t.Errorf("chunk 0 = %q %q", chunks[0].Key, chunks[0].Content)
+	}
+	if chunks[1].Key != "dishwasher-manual/2" || !strings.Contains(chunks[1].Content, "top rack") {
+		t.Errorf("chunk 1 = %q %q", chunks[1].Key, chunks[1].Content)
+	}
+	for i, c := range chunks {
+		if len(c.Content) > maxTextChunk {
+			t.Errorf("chunk %d is %d bytes, over the %d cap", i, len(c.Content), maxTextChunk)
+		}
+		if got := strings.Join(c.Subjects(), ","); got != "topic:dishwasher-manual" {
+			t.Errorf("chunk %d subjects = %s", i, got)
+		}
+	}
+}
+
+func TestIngestFile_TextAndPDF(t *testing.T) {
+	dir := t.TempDir()
+	txtPath := filepath.Join(dir, "boiler.txt")
+	if err := os.WriteFile(txtPath, []byte("Bleed the radiators each autumn.\n\nService the boiler yearly.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pdfPath := filepath.Join(dir, "Heat Pump.pdf")
+	if err := os.WriteFile(pdfPath, minimalPDF("Defrost runs every 40 minutes.", "Clear snow from the outdoor unit."), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := newTestStore(t)
+	mock := &mockIngestEmbedder{}
+	ctx := context.Background()
+
+	n, err := NewIngester(store, mock, "file:"+txtPath, CategoryHome).IngestFile(ctx, txtPath)
+	if err != nil {
+		t.Fatalf("IngestFile(txt): %v", err)
+	}
+	if n != 2 {
+		t.Errorf("txt facts = %d, want 2", n)
+	}
+
+	n, err = NewIngester(store, mock, "file:"+pdfPath, CategoryDevice).IngestFile(ctx, pdfPath)
+	if err != nil {
+		t.Fatalf("IngestFile(pdf): %v", err)
+	}
+	if n != 2 {
+		t.Errorf("pdf facts = %d, want 2", n)
+	}
+	if mock.calls != 4 {
+		t.Errorf("embedding calls = %d, want 4", mock.calls)
+	}
+
+	page2, err := store.Get(CategoryDevice, "heat-pump/page-2/1")
+	if err != nil {
+		t.Fatalf("Get(page 2): %v", err)
+	}
+	if !strings.Contains(page2.Value, "Clear snow") {
+		t.Errorf("page 2 value = %q", page2.Value)
+	}
+	scoped, err := store.GetBySubjects([]string{"topic:heat-pump"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scoped) != 2 {
+		t.Errorf("GetBySubjects(topic:heat-pump) = %d facts, want 2", len(scoped))
+	}
+
+	if _, err := NewIngester(store, nil, "x", CategoryHome).IngestFile(ctx, filepath.Join(dir, "a.docx")); err == nil {
+		t.Error("IngestFile(.docx) succeeded, want unsupported type error")
+	}
+}
+
+// minimalPDF builds a PDF with one page per line of text.
+func minimalPDF(pages ...string) []byte {
+	var objs []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objs = append(objs,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	)
+	for i, text := range pages {
+		stream := fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+		objs = append(objs,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs))
+	for i, o := range objs {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return buf.Bytes()
+}
@@ -80,7 +80,7 @@ Steep for 4 minutes, then press slowly to avoid agitation.
 
 	// Create ingester with mock embedder
 	mock := &mockIngestEmbedder{}
-	ingester := NewMarkdownIngester(store, mock, "test:integration", CategoryArchitecture)
+	ingester := NewIngester(store, mock, "test:integration", CategoryArchitecture)
 
 	// Run ingestion
 	ctx := context.Background()
@@ -123,6 +123,19 @@ Steep for 4 minutes, then press slowly to avoid agitation.
 			t.Errorf("result %d: expected similarity ~1.0, got %f", i, s)
 		}
 	}
+
+	// Facts are scoped by their heading path.
+	scoped, err := store.GetBySubjects([]string{"topic:coffee-brewing-methods/french-press"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := map[string]bool{}
+	for _, f := range scoped {
+		keys[f.Key] = true
+	}
+	if len(scoped) != 2 || !keys["coffee-brewing-methods/french-press"] || !keys["coffee-brewing-methods/french-press/steep-time"] {
+		t.Errorf("GetBySubjects(french-press) = %v, want the section and its steep-time child", keys)
+	}
 }
 
 func TestArchitectureIngesterReimport(t *testing.T) {
@@ -136,7 +149,7 @@ func TestArchitectureIngesterReimport(t *testing.T) {
 
 	store := openFileBackedStore(t, tmpDB.Name())
 
-	ingester := NewMarkdownIngester(store, nil, "test:reimport", CategoryArchitecture)
+	ingester := NewIngester(store, nil, "test:reimport", CategoryArchitecture)
 	ctx := context.Background()
 
 	// First import
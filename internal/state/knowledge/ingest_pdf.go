@@ -0,0 +1,45 @@
+package knowledge
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// extractPDFText returns the text of each page of the PDF at path.
+// Scanned PDFs without a text layer yield empty pages.
+func extractPDFText(path string) (pages []string, err error) {
+	f, r, err := pdf.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open pdf: %w", err)
+	}
+	defer f.Close()
+
+	// The parser panics on some malformed content streams rather than
+	// returning an error.
+	defer func() {
+		if p := recover(); p != nil {
+			pages, err = nil, fmt.Errorf("read pdf: %v", p)
+		}
+	}()
+
+	for i := 1; i <= r.NumPage(); i++ {
+		text, err := r.Page(i).GetPlainText(nil)
+		if err != nil {
+			return nil, fmt.Errorf("read pdf page %d: %w", i, err)
+		}
+		pages = append(pages, text)
+	}
+	return pages, nil
+}
+
+// pdfChunks splits extracted PDF text into paragraph chunks filed
+// under title and the page they came from: "manual/page-3/1".
+func pdfChunks(pages []string, title string) []Chunk {
+	var chunks []Chunk
+	for i, text := range pages {
+		chunks = append(chunks, textChunks(text, []string{title, "Page " + strconv.Itoa(i+1)})...)
+	}
+	return chunks
+}
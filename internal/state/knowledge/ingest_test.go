@@ -111,3 +111,70 @@ func TestSlugify(t *testing.T) {
 		}
 	}
 }
+
+func TestParseMarkdownHeadingPath(t *testing.T) {
+	content := `---
+title: Network notes
+category: home
+---
+# Network
+
+Two switches and a router.
+
+## VLANs
+
+<!-- category: device -->
+Tagged on the trunk ports.
+
+### Guest
+
+Isolated, internet only.
+
+### IoT
+
+Cameras and sensors.
+
+## Wi-Fi
+
+Three access points.
+`
+
+	chunks := parseMarkdown(strings.NewReader(content))
+
+	expected := []struct {
+		key      string
+		heading  string
+		category Category
+		subjects []string
+	}{
+		{"network", "Network", CategoryHome, []string{"topic:network"}},
+		{"network/vlans", "Network > VLANs", CategoryDevice, []string{"topic:network", "topic:network/vlans"}},
+		{"network/vlans/guest", "Network > VLANs > Guest", CategoryDevice,
+			[]string{"topic:network", "topic:network/vlans", "topic:network/vlans/guest"}},
+		{"network/vlans/iot", "Network > VLANs > IoT", CategoryDevice,
+			[]string{"topic:network", "topic:network/vlans", "topic:network/vlans/iot"}},
+		{"network/wi-fi", "Network > Wi-Fi", CategoryHome, []string{"topic:network", "topic:network/wi-fi"}},
+	}
+
+	if len(chunks) != len(expected) {
+		t.Fatalf("expected %d chunks, got %d: %+v", len(expected), len(chunks), chunks)
+	}
+	for i, exp := range expected {
+		c := chunks[i]
+		if c.Key != exp.key {
+			t.Errorf("chunk %d: key = %q, want %q", i, c.Key, exp.key)
+		}
+		if c.Heading() != exp.heading {
+			t.Errorf("chunk %d: heading = %q, want %q", i, c.Heading(), exp.heading)
+		}
+		if c.Category != exp.category {
+			t.Errorf("chunk %d: category = %q, want %q", i, c.Category, exp.category)
+		}
+		if got := strings.Join(c.Subjects(), ","); got != strings.Join(exp.subjects, ",") {
+			t.Errorf("chunk %d: subjects = %s, want %s", i, got, strings.Join(exp.subjects, ","))
+		}
+		if strings.Contains(c.Content, "category:") {
+			t.Errorf("chunk %d: directive leaked into content: %q", i, c.Content)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package knowledge
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// maxTextChunk caps the size of one plain-text fact in bytes. A
+// paragraph longer than this — common in text extracted from PDFs,
+// which rarely has blank lines — is split at line breaks.
+const maxTextChunk = 2000
+
+// documentTitle is the heading plain-text and PDF facts are filed
+// under: the file's base name without its extension.
+func documentTitle(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// textChunks splits plain text into one chunk per paragraph, keyed
+// under path: "manual/1", "manual/2", and so on.
+func textChunks(text string, path []string) []Chunk {
+	slugs := make([]string, len(path))
+	for i, h := range path {
+		slugs[i] = slugify(h)
+	}
+	prefix := strings.Join(slugs, "/")
+
+	var chunks []Chunk
+	for _, para := range splitParagraphs(text) {
+		chunks = append(chunks, Chunk{
+			Key:     fmt.Sprintf("%s/%d", prefix, len(chunks)+1),
+			Content: para,
+			Section: path[0],
+			Path:    path,
+		})
+	}
+	return chunks
+}
+
+// splitParagraphs splits text on blank lines, breaking paragraphs
+// longer than maxTextChunk at line boundaries.
+func splitParagraphs(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var paras []string
+	var cur strings.Builder
+	flush := func() {
+		if p := strings.TrimSpace(cur.String()); p != "" {
+			paras = append(paras, p)
+		}
+		cur.Reset()
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if cur.Len() > 0 && cur.Len()+len(line) > maxTextChunk {
+			flush()
+		}
+		cur.WriteString(line)
+		cur.WriteString("\n")
+	}
+	flush()
+	return paras
+}
@@ -2,7 +2,9 @@ package knowledge
 
 import "github.com/nugget/thane-ai-agent/internal/platform/database"
 
-// schema declares the knowledge facts table and its additive history.
+// schema declares the knowledge facts table, its additive history,
+// the fact_conflicts table of disagreements awaiting review, and the
+// ingest_sources table of ingested documents.
 // FTS5 is set up separately in tryEnableFTS — it's allowed to fail
 // (graceful LIKE fallback) and so does not belong in the schema.
 var schema = database.Schema{
@@ -46,5 +48,44 @@ var schema = database.Schema{
 		database.ColumnAdd{Table: "facts", Column: "deleted_at", Typedef: "TEXT"},
 		database.ColumnAdd{Table: "facts", Column: "subjects", Typedef: "TEXT"},
 		database.ColumnAdd{Table: "facts", Column: "ref", Typedef: "TEXT"},
+		database.ColumnAdd{Table: "facts", Column: "expires_at", Typedef: "TEXT"},
+		database.ColumnAdd{Table: "facts", Column: "embedding_model", Typedef: "TEXT"},
+		database.ColumnAdd{Table: "facts", Column: "embedding_dim", Typedef: "INTEGER"},
+		database.IndexCreate{
+			Name: "idx_facts_expires",
+			SQL:  `CREATE INDEX IF NOT EXISTS idx_facts_expires ON facts(expires_at)`,
+		},
+		database.TableCreate{
+			Table: "fact_conflicts",
+			SQL: `CREATE TABLE IF NOT EXISTS fact_conflicts (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				category TEXT NOT NULL,
+				key TEXT NOT NULL,
+				current_value TEXT NOT NULL,
+				current_confidence REAL NOT NULL,
+				proposed_value TEXT NOT NULL,
+				proposed_confidence REAL NOT NULL,
+				source TEXT,
+				created_at TEXT NOT NULL,
+				resolved_at TEXT,
+				resolution TEXT
+			)`,
+		},
+		database.IndexCreate{
+			Name: "idx_fact_conflicts_pending",
+			SQL:  `CREATE INDEX IF NOT EXISTS idx_fact_conflicts_pending ON fact_conflicts(resolved_at, category, key)`,
+		},
+		// Ingest tracking: the content hash each ingested document had
+		// when its facts were last written, so re-ingesting a
+		// directory skips unchanged files and retires deleted ones.
+		database.TableCreate{
+			Table: "ingest_sources",
+			SQL: `CREATE TABLE IF NOT EXISTS ingest_sources (
+				source TEXT PRIMARY KEY,
+				content_hash TEXT NOT NULL,
+				fact_count INTEGER NOT NULL,
+				ingested_at TEXT NOT NULL
+			)`,
+		},
 	},
 }
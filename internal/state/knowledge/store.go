@@ -30,15 +30,18 @@ const (
 // SQL fragments for query building.
 const (
 	// Base columns for fact queries (without embedding).
-	factColumns = "id, category, key, value, source, confidence, subjects, created_at, updated_at, accessed_at, ref"
+	factColumns = "id, category, key, value, source, confidence, subjects, created_at, updated_at, accessed_at, ref, expires_at"
 	// Columns including embedding.
-	factColumnsWithEmbed = "id, category, key, value, source, confidence, subjects, embedding, created_at, updated_at, accessed_at, ref"
+	factColumnsWithEmbed = "id, category, key, value, source, confidence, subjects, embedding, created_at, updated_at, accessed_at, ref, expires_at"
 	// Qualified columns for FTS5 JOIN queries where facts and facts_fts
 	// share column names (key, value, source). Without table prefixes,
 	// SQLite raises "ambiguous column name" errors.
-	factColumnsFTS = "facts.id, facts.category, facts.key, facts.value, facts.source, facts.confidence, facts.subjects, facts.created_at, facts.updated_at, facts.accessed_at, facts.ref"
-	// Filter for active facts (currently: not soft-deleted).
-	activeFilter = "deleted_at IS NULL"
+	factColumnsFTS = "facts.id, facts.category, facts.key, facts.value, facts.source, facts.confidence, facts.subjects, facts.created_at, facts.updated_at, facts.accessed_at, facts.ref, facts.expires_at"
+	// Filter for active facts: not soft-deleted and not past expiry.
+	// Expired facts drop out of every read immediately; the sweep
+	// soft-deletes them later. Timestamps are stored as UTC RFC 3339,
+	// so they compare correctly as strings.
+	activeFilter = "deleted_at IS NULL AND (expires_at IS NULL OR expires_at > strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))"
 )
 
 // Fact represents a piece of long-term memory.
@@ -55,6 +58,9 @@ type Fact struct {
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 	AccessedAt time.Time `json:"accessed_at"` // For LRU-style relevance
+	// ExpiresAt is when an ephemeral fact stops being true. Nil means
+	// the fact never expires.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
 // Store manages fact persistence.
@@ -62,6 +68,10 @@ type Store struct {
 	db         *sql.DB
 	ftsEnabled bool
 	logger     *slog.Logger
+	// embeddingModel tags vectors written by SetEmbedding and filters
+	// the ones semantic search will compare against. Empty disables
+	// model tracking.
+	embeddingModel string
 }
 
 // NewStore creates a fact store backed by db. The caller owns db's
@@ -109,20 +119,55 @@ func (s *Store) tryEnableFTS() {
 
 // Set creates or updates a fact. Resurrects soft-deleted facts if they exist.
 // Subjects is an optional list of subject keys (e.g., "entity:foo",
-// "zone:bar") stored as a JSON array. Pass nil to leave subjects unset.
+// "zone:bar") stored as a JSON array after [NormalizeSubjects], so a
+// bare "climate.thermostat" links as "entity:climate.thermostat". Pass
+// nil to leave subjects unset.
 // Ref is an optional knowledge-base-relative path (e.g., "dossiers/foo.md").
 // Pass "" to leave ref unset.
+//
+// Set stores a lasting fact: any expiry the fact had is cleared. Use
+// [Store.SetWithTTL] to keep knowledge ephemeral.
 func (s *Store) Set(category Category, key, value, source string, confidence float64, subjects []string, ref string) (*Fact, error) {
-	now := time.Now().UTC()
+	return s.SetWithTTL(category, key, value, source, confidence, subjects, ref, 0)
+}
 
-	var subjectsJSON *string
-	if len(subjects) > 0 {
-		b, err := json.Marshal(subjects)
-		if err != nil {
-			return nil, fmt.Errorf("marshal subjects: %w", err)
-		}
-		s := string(b)
-		subjectsJSON = &s
+// SetWithTTL is [Store.Set] for ephemeral knowledge: a positive ttl
+// makes the fact expire ttl from now. A zero ttl stores the fact
+// without an expiry, clearing any it had, so re-setting a fact
+// decides its lifetime afresh. Callers that re-observe ephemeral
+// knowledge renew it by passing the fact's [Fact.Lifetime]. Setting a
+// fact closes any pending [Conflict] on it as [ResolutionSuperseded].
+func (s *Store) SetWithTTL(category Category, key, value, source string, confidence float64, subjects []string, ref string, ttl time.Duration) (*Fact, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin set tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	fact, err := s.setFact(tx, category, key, value, source, confidence, subjects, ref, ttl)
+	if err != nil {
+		return nil, err
+	}
+	// A direct set is a deliberate decision about the fact, so any
+	// pending conflict over its old value is moot.
+	if err := closeConflicts(tx, category, key, ResolutionSuperseded); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit set: %w", err)
+	}
+	s.syncFTS(category, key, value, source)
+	return fact, nil
+}
+
+// setFact writes a fact within tx for [Store.SetWithTTL] and the
+// conflict paths. The caller commits and then calls [Store.syncFTS].
+func (s *Store) setFact(tx *sql.Tx, category Category, key, value, source string, confidence float64, subjects []string, ref string, ttl time.Duration) (*Fact, error) {
+	now := time.Now().UTC()
+	subjects = NormalizeSubjects(subjects)
+	subjectsJSON, err := subjectsColumn(subjects)
+	if err != nil {
+		return nil, err
 	}
 
 	var refSQL *string
@@ -132,8 +177,15 @@ func (s *Store) Set(category Category, key, value, source string, confidence flo
 
 	// Check if exists (including soft-deleted)
 	var existingID string
-	var isDeleted bool
-	err := s.db.QueryRow(`SELECT id, deleted_at IS NOT NULL FROM facts WHERE category = ? AND key = ?`, category, key).Scan(&existingID, &isDeleted)
+	err = tx.QueryRow(`SELECT id FROM facts WHERE category = ? AND key = ?`, category, key).Scan(&existingID)
+
+	var expiresAt *time.Time
+	var expiresSQL *string
+	if ttl > 0 {
+		t := now.Add(ttl)
+		formatted := t.Format(time.RFC3339)
+		expiresAt, expiresSQL = &t, &formatted
+	}
 
 	if err == sql.ErrNoRows {
 		// Create new
@@ -150,31 +202,30 @@ func (s *Store) Set(category Category, key, value, source string, confidence flo
 			CreatedAt:  now,
 			UpdatedAt:  now,
 			AccessedAt: now,
+			ExpiresAt:  expiresAt,
 		}
 
-		_, err = s.db.Exec(`
-			INSERT INTO facts (id, category, key, value, source, confidence, subjects, ref, created_at, updated_at, accessed_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		_, err = tx.Exec(`
+			INSERT INTO facts (id, category, key, value, source, confidence, subjects, ref, created_at, updated_at, accessed_at, expires_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`, id.String(), category, key, value, source, confidence, subjectsJSON, refSQL,
-			now.Format(time.RFC3339), now.Format(time.RFC3339), now.Format(time.RFC3339))
+			now.Format(time.RFC3339), now.Format(time.RFC3339), now.Format(time.RFC3339), expiresSQL)
 		if err != nil {
 			return nil, fmt.Errorf("insert: %w", err)
 		}
-		s.syncFTS(category, key, value, source)
 		return fact, nil
 	} else if err != nil {
 		return nil, fmt.Errorf("check existing: %w", err)
 	}
 
 	// Update existing (resurrect if soft-deleted)
-	_, err = s.db.Exec(`
-		UPDATE facts SET value = ?, source = ?, confidence = ?, subjects = ?, ref = ?, updated_at = ?, accessed_at = ?, deleted_at = NULL
+	_, err = tx.Exec(`
+		UPDATE facts SET value = ?, source = ?, confidence = ?, subjects = ?, ref = ?, updated_at = ?, accessed_at = ?, expires_at = ?, deleted_at = NULL
 		WHERE category = ? AND key = ?
-	`, value, source, confidence, subjectsJSON, refSQL, now.Format(time.RFC3339), now.Format(time.RFC3339), category, key)
+	`, value, source, confidence, subjectsJSON, refSQL, now.Format(time.RFC3339), now.Format(time.RFC3339), expiresSQL, category, key)
 	if err != nil {
 		return nil, fmt.Errorf("update: %w", err)
 	}
-	s.syncFTS(category, key, value, source)
 
 	id, _ := uuid.Parse(existingID)
 	return &Fact{
@@ -188,9 +239,33 @@ func (s *Store) Set(category Category, key, value, source string, confidence flo
 		Ref:        ref,
 		UpdatedAt:  now,
 		AccessedAt: now,
+		ExpiresAt:  expiresAt,
 	}, nil
 }
 
+// Lifetime returns the lifetime an ephemeral fact was last set with:
+// the span from its last update to its expiry. It is zero for a
+// lasting fact.
+func (f *Fact) Lifetime() time.Duration {
+	if f.ExpiresAt == nil {
+		return 0
+	}
+	return max(f.ExpiresAt.Sub(f.UpdatedAt), 0)
+}
+
+// ClearExpiry makes an ephemeral fact lasting without otherwise
+// changing it.
+func (s *Store) ClearExpiry(category Category, key string) error {
+	result, err := s.db.Exec(`UPDATE facts SET expires_at = NULL WHERE `+activeFilter+` AND category = ? AND key = ?`, category, key)
+	if err != nil {
+		return fmt.Errorf("clear expiry: %w", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("fact not found: %s/%s", category, key)
+	}
+	return nil
+}
+
 // Get retrieves a fact by category and key.
 func (s *Store) Get(category Category, key string) (*Fact, error) {
 	fact, err := s.scanFact(s.db.QueryRow(
@@ -251,19 +326,24 @@ func (s *Store) GetAll() ([]*Fact, error) {
 
 // GetBySubjects retrieves all active facts associated with any of the
 // given subject keys. Subjects are stored as JSON arrays and queried
-// using SQLite's json_each() function. Returns nil when no subjects are
-// provided or no facts match.
+// using SQLite's json_each() function. Keys are normalized with
+// [NormalizeSubject], and facts stored before normalization (a bare
+// entity ID) still match. Returns nil when no subjects are provided or
+// no facts match.
 func (s *Store) GetBySubjects(subjects []string) ([]*Fact, error) {
+	subjects = NormalizeSubjects(subjects)
 	if len(subjects) == 0 {
 		return nil, nil
 	}
 
 	// Build query with IN clause for json_each matching.
-	placeholders := make([]string, len(subjects))
-	args := make([]any, len(subjects))
-	for i, sub := range subjects {
-		placeholders[i] = "?"
-		args[i] = sub
+	var placeholders []string
+	var args []any
+	for _, sub := range subjects {
+		for _, form := range append([]string{sub}, legacySubjectForms(sub)...) {
+			placeholders = append(placeholders, "?")
+			args = append(args, form)
+		}
 	}
 
 	// updated_at is stored at second precision, so multiple facts set
@@ -395,7 +475,7 @@ func sanitizeFTS5Query(query string) string {
 // Delete soft-deletes a fact (sets deleted_at timestamp).
 func (s *Store) Delete(category Category, key string) error {
 	now := time.Now().UTC().Format(time.RFC3339)
-	result, err := s.db.Exec(`UPDATE facts SET deleted_at = ? WHERE category = ? AND key = ? AND deleted_at IS NULL`, now, category, key)
+	result, err := s.db.Exec(`UPDATE facts SET deleted_at = ?, expires_at = NULL WHERE category = ? AND key = ? AND deleted_at IS NULL`, now, category, key)
 	if err != nil {
 		return fmt.Errorf("delete: %w", err)
 	}
@@ -410,6 +490,25 @@ func (s *Store) Delete(category Category, key string) error {
 	return nil
 }
 
+// SweepExpired soft-deletes facts whose expiry has passed and returns
+// how many were removed. Expired facts are already excluded from reads;
+// the sweep retires them so they no longer count as stored and can be
+// resurrected by a later [Store.Set] like any forgotten fact. The
+// expiry is cleared with the delete so a resurrected fact starts with
+// no lifetime of its own.
+func (s *Store) SweepExpired() (int, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	result, err := s.db.Exec(`UPDATE facts SET deleted_at = ?, expires_at = NULL WHERE deleted_at IS NULL AND expires_at IS NOT NULL AND expires_at <= ?`, now, now)
+	if err != nil {
+		return 0, fmt.Errorf("sweep expired: %w", err)
+	}
+	affected, _ := result.RowsAffected()
+	if affected > 0 {
+		s.rebuildFTS()
+	}
+	return int(affected), nil
+}
+
 // DeleteBySource soft-deletes all facts from a given source.
 // Used for re-importing documents without duplicates.
 func (s *Store) DeleteBySource(source string) error {
@@ -447,9 +546,9 @@ func (s *Store) Stats() map[string]any {
 func (s *Store) scanFact(row *sql.Row) (*Fact, error) {
 	var f Fact
 	var idStr, catStr, createdStr, updatedStr, accessedStr string
-	var source, subjectsRaw, refRaw sql.NullString
+	var source, subjectsRaw, refRaw, expiresRaw sql.NullString
 
-	err := row.Scan(&idStr, &catStr, &f.Key, &f.Value, &source, &f.Confidence, &subjectsRaw, &createdStr, &updatedStr, &accessedStr, &refRaw)
+	err := row.Scan(&idStr, &catStr, &f.Key, &f.Value, &source, &f.Confidence, &subjectsRaw, &createdStr, &updatedStr, &accessedStr, &refRaw, &expiresRaw)
 	if err != nil {
 		return nil, err
 	}
@@ -474,16 +573,37 @@ func (s *Store) scanFact(row *sql.Row) (*Fact, error) {
 	if f.AccessedAt, err = database.ParseTimestamp(accessedStr); err != nil {
 		return nil, fmt.Errorf("parse accessed_at: %w", err)
 	}
+	if expiresRaw.Valid {
+		expires, err := database.ParseTimestamp(expiresRaw.String)
+		if err != nil {
+			return nil, fmt.Errorf("parse expires_at: %w", err)
+		}
+		f.ExpiresAt = &expires
+	}
 
 	return &f, nil
 }
 
+// subjectsColumn encodes normalized subjects for the subjects column,
+// which holds NULL rather than an empty array.
+func subjectsColumn(subjects []string) (*string, error) {
+	if len(subjects) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(subjects)
+	if err != nil {
+		return nil, fmt.Errorf("marshal subjects: %w", err)
+	}
+	s := string(b)
+	return &s, nil
+}
+
 func (s *Store) scanFactRow(rows *sql.Rows) (*Fact, error) {
 	var f Fact
 	var idStr, catStr, createdStr, updatedStr, accessedStr string
-	var source, subjectsRaw, refRaw sql.NullString
+	var source, subjectsRaw, refRaw, expiresRaw sql.NullString
 
-	err := rows.Scan(&idStr, &catStr, &f.Key, &f.Value, &source, &f.Confidence, &subjectsRaw, &createdStr, &updatedStr, &accessedStr, &refRaw)
+	err := rows.Scan(&idStr, &catStr, &f.Key, &f.Value, &source, &f.Confidence, &subjectsRaw, &createdStr, &updatedStr, &accessedStr, &refRaw, &expiresRaw)
 	if err != nil {
 		return nil, err
 	}
@@ -508,21 +628,84 @@ func (s *Store) scanFactRow(rows *sql.Rows) (*Fact, error) {
 	if f.AccessedAt, err = database.ParseTimestamp(accessedStr); err != nil {
 		return nil, fmt.Errorf("parse accessed_at: %w", err)
 	}
+	if expiresRaw.Valid {
+		expires, err := database.ParseTimestamp(expiresRaw.String)
+		if err != nil {
+			return nil, fmt.Errorf("parse expires_at: %w", err)
+		}
+		f.ExpiresAt = &expires
+	}
 
 	return &f, nil
 }
 
-// SetEmbedding updates a fact's embedding vector.
+// SetEmbeddingModel sets the embedding model whose vectors the store
+// writes and searches. Vectors from any other model are treated as
+// stale: semantic search skips them and GetFactsWithoutEmbeddings
+// returns their facts for re-embedding. Call it before the store is
+// shared.
+func (s *Store) SetEmbeddingModel(model string) {
+	s.embeddingModel = model
+}
+
+// SetEmbedding updates a fact's embedding vector, tagging it with the
+// store's embedding model and the vector's dimension.
 func (s *Store) SetEmbedding(id uuid.UUID, embedding []float32) error {
 	blob := EncodeEmbedding(embedding)
-	_, err := s.db.Exec(`UPDATE facts SET embedding = ? WHERE id = ?`, blob, id.String())
+	_, err := s.db.Exec(`UPDATE facts SET embedding = ?, embedding_model = ?, embedding_dim = ? WHERE id = ?`,
+		blob, sql.NullString{String: s.embeddingModel, Valid: s.embeddingModel != ""}, len(embedding), id.String())
 	return err
 }
 
-// GetAllWithEmbeddings returns all facts that have
+// AdoptLegacyEmbeddings tags vectors stored before the embedding
+// model was tracked with the store's current model when their
+// dimension matches, returning how many it tagged. Untagged vectors of
+// any other dimension are left for a backfill to replace.
+func (s *Store) AdoptLegacyEmbeddings(dimension int) (int64, error) {
+	if s.embeddingModel == "" || dimension <= 0 {
+		return 0, nil
+	}
+	res, err := s.db.Exec(`UPDATE facts SET embedding_model = ?, embedding_dim = ?
+		WHERE embedding IS NOT NULL AND embedding_model IS NULL AND length(embedding) = ?`,
+		s.embeddingModel, dimension, dimension*4)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// EmbeddingModels counts active facts' vectors by the model and
+// dimension that produced them.
+func (s *Store) EmbeddingModels() ([]EmbeddingModelCount, error) {
+	rows, err := s.db.Query(`SELECT COALESCE(embedding_model, ''), COALESCE(embedding_dim, length(embedding) / 4), COUNT(*)
+		FROM facts WHERE ` + activeFilter + ` AND embedding IS NOT NULL
+		GROUP BY 1, 2 ORDER BY 3 DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []EmbeddingModelCount
+	for rows.Next() {
+		var c EmbeddingModelCount
+		if err := rows.Scan(&c.Model, &c.Dimension, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// GetAllWithEmbeddings returns all facts that have an embedding from
+// the store's embedding model, or one that predates model tracking.
 func (s *Store) GetAllWithEmbeddings() ([]*Fact, error) {
-	rows, err := s.db.Query(
-		`SELECT ` + factColumnsWithEmbed + ` FROM facts WHERE ` + activeFilter + ` AND embedding IS NOT NULL`)
+	query := `SELECT ` + factColumnsWithEmbed + ` FROM facts WHERE ` + activeFilter + ` AND embedding IS NOT NULL`
+	var args []any
+	if s.embeddingModel != "" {
+		query += ` AND (embedding_model = ? OR embedding_model IS NULL)`
+		args = append(args, s.embeddingModel)
+	}
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -557,7 +740,9 @@ func (s *Store) SemanticSearch(queryEmbedding []float32, limit int) ([]*Fact, []
 	}
 	scores := make([]scored, 0, len(facts))
 	for _, f := range facts {
-		if len(f.Embedding) > 0 {
+		// A vector of another dimension came from a different model;
+		// comparing against it would be meaningless.
+		if len(f.Embedding) == len(queryEmbedding) {
 			sim := CosineSimilarity(queryEmbedding, f.Embedding)
 			scores = append(scores, scored{fact: f, score: sim})
 		}
@@ -585,10 +770,17 @@ func (s *Store) SemanticSearch(queryEmbedding []float32, limit int) ([]*Fact, []
 	return resultFacts, resultScores, nil
 }
 
-// GetFactsWithoutEmbeddings returns facts that need embeddings generated.
+// GetFactsWithoutEmbeddings returns facts that need embeddings
+// generated: those with no vector, and, when an embedding model is
+// set, those whose vector came from another model.
 func (s *Store) GetFactsWithoutEmbeddings() ([]*Fact, error) {
-	rows, err := s.db.Query(
-		`SELECT ` + factColumns + ` FROM facts WHERE ` + activeFilter + ` AND embedding IS NULL`)
+	query := `SELECT ` + factColumns + ` FROM facts WHERE ` + activeFilter + ` AND embedding IS NULL`
+	var args []any
+	if s.embeddingModel != "" {
+		query = `SELECT ` + factColumns + ` FROM facts WHERE ` + activeFilter + ` AND (embedding IS NULL OR embedding_model IS NOT ?)`
+		args = append(args, s.embeddingModel)
+	}
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -608,10 +800,10 @@ func (s *Store) GetFactsWithoutEmbeddings() ([]*Fact, error) {
 func (s *Store) scanFactWithEmbedding(rows *sql.Rows) (*Fact, error) {
 	var f Fact
 	var idStr, catStr, createdStr, updatedStr, accessedStr string
-	var source, subjectsRaw, refRaw sql.NullString
+	var source, subjectsRaw, refRaw, expiresRaw sql.NullString
 	var embeddingBlob []byte
 
-	err := rows.Scan(&idStr, &catStr, &f.Key, &f.Value, &source, &f.Confidence, &subjectsRaw, &embeddingBlob, &createdStr, &updatedStr, &accessedStr, &refRaw)
+	err := rows.Scan(&idStr, &catStr, &f.Key, &f.Value, &source, &f.Confidence, &subjectsRaw, &embeddingBlob, &createdStr, &updatedStr, &accessedStr, &refRaw, &expiresRaw)
 	if err != nil {
 		return nil, err
 	}
@@ -637,6 +829,13 @@ func (s *Store) scanFactWithEmbedding(rows *sql.Rows) (*Fact, error) {
 	if f.AccessedAt, err = database.ParseTimestamp(accessedStr); err != nil {
 		return nil, fmt.Errorf("parse accessed_at: %w", err)
 	}
+	if expiresRaw.Valid {
+		expires, err := database.ParseTimestamp(expiresRaw.String)
+		if err != nil {
+			return nil, fmt.Errorf("parse expires_at: %w", err)
+		}
+		f.ExpiresAt = &expires
+	}
 
 	return &f, nil
 }
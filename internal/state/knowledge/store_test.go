@@ -1,9 +1,12 @@
 package knowledge
 
 import (
+	"database/sql"
+	"fmt"
 	"log/slog"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/nugget/thane-ai-agent/internal/platform/database"
 	_ "modernc.org/sqlite"
@@ -289,3 +292,158 @@ func TestSet_UpdateRef(t *testing.T) {
 		t.Errorf("ref not updated: got %q, want %q", got.Ref, "sensors/temp_v2.md")
 	}
 }
+
+// backdateFact shifts a fact's updated_at and expires_at into the past,
+// as if it had been set by long ago.
+func backdateFact(t *testing.T, store *Store, category Category, key string, by time.Duration) {
+	t.Helper()
+	_, err := store.db.Exec(`
+		UPDATE facts SET
+			updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', updated_at, ?),
+			expires_at = strftime('%Y-%m-%dT%H:%M:%SZ', expires_at, ?)
+		WHERE category = ? AND key = ?`,
+		fmt.Sprintf("-%d seconds", int(by.Seconds())),
+		fmt.Sprintf("-%d seconds", int(by.Seconds())),
+		category, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetWithTTL_ExpiredFactsHiddenAndSwept(t *testing.T) {
+	store := newTestStore(t)
+
+	if _, err := store.SetWithTTL(CategoryHome, "guest_room", "Occupied this weekend", "user", 1.0, []string{"zone:guest_room"}, "", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Set(CategoryHome, "guest_bed", "Queen size", "user", 1.0, []string{"zone:guest_room"}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	fact, err := store.Get(CategoryHome, "guest_room")
+	if err != nil {
+		t.Fatalf("Get before expiry: %v", err)
+	}
+	if fact.ExpiresAt == nil || time.Until(*fact.ExpiresAt) <= 0 {
+		t.Fatalf("ExpiresAt = %v, want about an hour out", fact.ExpiresAt)
+	}
+
+	backdateFact(t, store, CategoryHome, "guest_room", 2*time.Hour)
+
+	if _, err := store.Get(CategoryHome, "guest_room"); err == nil {
+		t.Error("Get returned an expired fact")
+	}
+	facts, err := store.GetBySubjects([]string{"zone:guest_room"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(facts) != 1 || facts[0].Key != "guest_bed" {
+		t.Errorf("GetBySubjects = %v, want only the permanent fact", facts)
+	}
+	if results, _ := store.Search("Occupied"); len(results) != 0 {
+		t.Errorf("Search returned %d expired facts", len(results))
+	}
+
+	n, err := store.SweepExpired()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("SweepExpired = %d, want 1", n)
+	}
+	if n, _ := store.SweepExpired(); n != 0 {
+		t.Errorf("second SweepExpired = %d, want 0", n)
+	}
+	if total := store.Stats()["total"]; total != 1 {
+		t.Errorf("total = %v, want 1 after sweep", total)
+	}
+}
+
+func TestSet_ClearsExpiryAndLifetimeRenews(t *testing.T) {
+	store := newTestStore(t)
+
+	if _, err := store.SetWithTTL(CategoryHome, "guest_room", "Occupied", "user", 0.5, nil, "", 2*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	backdateFact(t, store, CategoryHome, "guest_room", time.Hour)
+
+	// Re-observed with its own lifetime: the two hours restart now.
+	existing, err := store.Get(CategoryHome, "guest_room")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := existing.Lifetime(); got != 2*time.Hour {
+		t.Fatalf("Lifetime = %v, want 2h", got)
+	}
+	fact, err := store.SetWithTTL(CategoryHome, "guest_room", "Occupied", "user", 0.6, nil, "", existing.Lifetime())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fact.ExpiresAt == nil {
+		t.Fatal("renewed fact lost its expiry")
+	}
+	if left := time.Until(*fact.ExpiresAt); left < 110*time.Minute || left > 2*time.Hour {
+		t.Errorf("expiry in %v, want about 2h", left)
+	}
+
+	// Re-set without a TTL: the fact becomes lasting.
+	fact, err = store.Set(CategoryHome, "guest_room", "Occupied", "user", 0.6, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fact.ExpiresAt != nil {
+		t.Errorf("ExpiresAt = %v, want nil after a plain Set", fact.ExpiresAt)
+	}
+	if got, _ := store.Get(CategoryHome, "guest_room"); got == nil || got.ExpiresAt != nil {
+		t.Errorf("stored fact = %+v, want no expiry", got)
+	}
+}
+
+func TestClearExpiry(t *testing.T) {
+	store := newTestStore(t)
+
+	if _, err := store.SetWithTTL(CategoryHome, "guest_room", "Occupied", "user", 1.0, nil, "", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ClearExpiry(CategoryHome, "guest_room"); err != nil {
+		t.Fatalf("ClearExpiry: %v", err)
+	}
+	fact, err := store.Get(CategoryHome, "guest_room")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fact.ExpiresAt != nil || fact.Value != "Occupied" {
+		t.Errorf("fact = %+v, want unchanged value with no expiry", fact)
+	}
+	if err := store.ClearExpiry(CategoryHome, "missing"); err == nil {
+		t.Error("ClearExpiry on a missing fact succeeded")
+	}
+}
+
+func TestSweepExpired_ResurrectedFactIsLasting(t *testing.T) {
+	store := newTestStore(t)
+
+	if _, err := store.SetWithTTL(CategoryHome, "guest_room", "Occupied", "user", 1.0, nil, "", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	backdateFact(t, store, CategoryHome, "guest_room", 2*time.Hour)
+	if n, err := store.SweepExpired(); err != nil || n != 1 {
+		t.Fatalf("SweepExpired = %d, %v; want 1", n, err)
+	}
+
+	var expires sql.NullString
+	if err := store.db.QueryRow(`SELECT expires_at FROM facts WHERE key = 'guest_room'`).Scan(&expires); err != nil {
+		t.Fatal(err)
+	}
+	if expires.Valid {
+		t.Errorf("swept fact kept expires_at %q", expires.String)
+	}
+
+	fact, err := store.Set(CategoryHome, "guest_room", "Free", "user", 1.0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fact.ExpiresAt != nil {
+		t.Errorf("resurrected fact ExpiresAt = %v, want nil", fact.ExpiresAt)
+	}
+}
@@ -0,0 +1,130 @@
+package knowledge
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Subject key kinds. A subject key is "<kind>:<id>"; these are the
+// kinds the store links precisely — Home Assistant entities, contacts,
+// and zones. Other prefixes (camera:, phone:, location:) are stored
+// verbatim.
+const (
+	SubjectEntity  = "entity"
+	SubjectContact = "contact"
+	SubjectZone    = "zone"
+)
+
+// SubjectTopic is the subject kind for a heading path in an ingested
+// markdown document ("topic:network/vlans"). Topic keys are stored
+// verbatim.
+const SubjectTopic = "topic"
+
+// maxTextSubjects caps how many entity mentions [SubjectsInText]
+// returns, so a pasted state dump cannot balloon the subject query.
+const maxTextSubjects = 20
+
+// entityMentionRe matches Home Assistant entity IDs (domain.object_id)
+// in free text. False positives ("e.g", "example.com") are harmless:
+// they link to no fact.
+var entityMentionRe = regexp.MustCompile(`\b[a-z][a-z0-9_]*\.[a-z0-9_]+\b`)
+
+// NormalizeSubject returns the canonical subject key for s so facts
+// link to the same key a turn resolves:
+//
+//   - "zone.home" and "zone:home" become "zone:home"
+//   - a bare entity ID ("climate.thermostat") becomes
+//     "entity:climate.thermostat"; "entity:zone.home" becomes
+//     "zone:home"
+//   - known kinds are lowercased and trimmed ("Entity: light.x" →
+//     "entity:light.x"); contact IDs keep their case
+//
+// Anything else is returned trimmed but otherwise unchanged.
+func NormalizeSubject(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	kind, id, ok := strings.Cut(s, ":")
+	if !ok {
+		if isEntityID(strings.ToLower(s)) {
+			return entitySubject(strings.ToLower(s))
+		}
+		return s
+	}
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	id = strings.TrimSpace(id)
+	switch kind {
+	case SubjectEntity:
+		return entitySubject(strings.ToLower(id))
+	case SubjectZone:
+		return SubjectZone + ":" + strings.TrimPrefix(strings.ToLower(id), "zone.")
+	case SubjectContact:
+		return SubjectContact + ":" + id
+	}
+	return s
+}
+
+// NormalizeSubjects applies [NormalizeSubject] to each key, dropping
+// empties and duplicates while preserving order. Returns nil when
+// nothing remains.
+func NormalizeSubjects(subjects []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(subjects))
+	for _, s := range subjects {
+		n := NormalizeSubject(s)
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		out = append(out, n)
+	}
+	return out
+}
+
+// SubjectsInText resolves the Home Assistant entity IDs mentioned in
+// text to subject keys, in order of first mention. Zone entities
+// resolve to zone: keys.
+func SubjectsInText(text string) []string {
+	matches := entityMentionRe.FindAllString(strings.ToLower(text), -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	subjects := NormalizeSubjects(matches)
+	if len(subjects) > maxTextSubjects {
+		subjects = subjects[:maxTextSubjects]
+	}
+	return subjects
+}
+
+// legacySubjectForms returns the stored forms a canonical key may
+// predate normalization as: facts saved with a bare entity ID, or a
+// zone as "entity:zone.x", still match.
+func legacySubjectForms(key string) []string {
+	kind, id, ok := strings.Cut(key, ":")
+	if !ok {
+		return nil
+	}
+	switch kind {
+	case SubjectEntity:
+		return []string{id}
+	case SubjectZone:
+		return []string{"zone." + id, "entity:zone." + id}
+	}
+	return nil
+}
+
+// entitySubject builds the subject key for an entity ID, mapping zone
+// entities to zone: keys.
+func entitySubject(entityID string) string {
+	if zone, ok := strings.CutPrefix(entityID, "zone."); ok {
+		return SubjectZone + ":" + zone
+	}
+	return SubjectEntity + ":" + entityID
+}
+
+// isEntityID reports whether s is shaped like a Home Assistant entity
+// ID.
+func isEntityID(s string) bool {
+	return entityMentionRe.FindString(s) == s
+}
@@ -35,14 +35,23 @@ func SubjectsFromContext(ctx context.Context) []string {
 // the system prompt. Used for pre-warming cold-start loops with relevant
 // context before the model sees the triggering event.
 //
-// Subject keys are passed through the context via [WithSubjects].
-// When no subjects are present in the context, TagContext returns empty.
+// Subject keys come from three places: the context via
+// [WithSubjects], Home Assistant entity IDs mentioned in the turn's
+// user message (see [SubjectsInText]), and any sources added with
+// [SubjectContextProvider.AddSubjectSource]. When none yield a subject,
+// TagContext returns empty.
 type SubjectContextProvider struct {
 	store    *Store
 	maxFacts int
+	sources  []SubjectSource
 	logger   *slog.Logger
 }
 
+// SubjectSource returns subject keys relevant to a turn beyond those
+// carried on the context — for example, the entities on the watchlist
+// or the tracked household members.
+type SubjectSource func(ctx context.Context, req agentctx.ContextRequest) []string
+
 // NewSubjectContextProvider creates a subject context provider with
 // default settings (maxFacts=10).
 func NewSubjectContextProvider(store *Store, logger *slog.Logger) *SubjectContextProvider {
@@ -59,6 +68,13 @@ func (p *SubjectContextProvider) TagContextBucket() agentctx.ContextBucket {
 	return agentctx.ContextBucketRelated
 }
 
+// AddSubjectSource registers an additional source of subject keys
+// consulted on every turn. Must be called before the provider is
+// registered with the loop.
+func (p *SubjectContextProvider) AddSubjectSource(src SubjectSource) {
+	p.sources = append(p.sources, src)
+}
+
 // SetMaxFacts configures the maximum number of subject-matched facts
 // to include in the context.
 func (p *SubjectContextProvider) SetMaxFacts(n int) {
@@ -71,11 +87,12 @@ func (p *SubjectContextProvider) SetMaxFacts(n int) {
 // [contextfmt.FormatSubjectKeyed] as compact JSON under a markdown
 // heading.
 //
-// Subjects are extracted from the context via [SubjectsFromContext].
-// If no subjects are present, returns empty. The request is unused —
-// subject matching is purely key-based, not semantic.
-func (p *SubjectContextProvider) TagContext(ctx context.Context, _ agentctx.ContextRequest) (string, error) {
-	subjects := SubjectsFromContext(ctx)
+// Subjects are gathered by [SubjectContextProvider.subjects]. If none
+// are present, returns empty. Matching is purely key-based, not
+// semantic: a fact linked to entity:climate.thermostat is injected
+// when a turn mentions climate.thermostat, and not otherwise.
+func (p *SubjectContextProvider) TagContext(ctx context.Context, req agentctx.ContextRequest) (string, error) {
+	subjects := p.subjects(ctx, req)
 	if len(subjects) == 0 {
 		return "", nil
 	}
@@ -114,3 +131,15 @@ func (p *SubjectContextProvider) TagContext(ctx context.Context, _ agentctx.Cont
 
 	return contextfmt.FormatSubjectKeyed(views), nil
 }
+
+// subjects merges the context's subjects, entities mentioned in the
+// user message, and each registered source, normalized and
+// deduplicated in that order of precedence.
+func (p *SubjectContextProvider) subjects(ctx context.Context, req agentctx.ContextRequest) []string {
+	subjects := append([]string{}, SubjectsFromContext(ctx)...)
+	subjects = append(subjects, SubjectsInText(req.UserMessage)...)
+	for _, src := range p.sources {
+		subjects = append(subjects, src(ctx, req)...)
+	}
+	return NormalizeSubjects(subjects)
+}
@@ -378,3 +378,110 @@ func TestGetContext_WithoutRef_NoAnnotation(t *testing.T) {
 		t.Errorf("expected ref field to be omitted when empty, got:\n%s", got)
 	}
 }
+
+func TestSubjectContextProvider_EntityMentions(t *testing.T) {
+	store := newTestStore(t)
+	if _, err := store.Set(CategoryDevice, "thermostat_quirk", "Overshoots by two degrees in heat mode", "test", 1.0,
+		[]string{"climate.thermostat"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Set(CategoryHome, "home_zone_radius", "Radius is tight; arrivals fire late", "test", 1.0,
+		[]string{"zone:home"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	provider := NewSubjectContextProvider(store, slog.Default())
+
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "entity referenced",
+			message: "Set climate.thermostat to 70",
+			want:    []string{"thermostat_quirk"},
+			notWant: []string{"home_zone_radius"},
+		},
+		{
+			name:    "zone entity referenced",
+			message: "Did anyone enter zone.home?",
+			want:    []string{"home_zone_radius"},
+			notWant: []string{"thermostat_quirk"},
+		},
+		{
+			name:    "free-form mention does not link",
+			message: "Is the thermostat on?",
+			notWant: []string{"thermostat_quirk", "home_zone_radius"},
+		},
+		{
+			name:    "other entity referenced",
+			message: "Turn on light.kitchen",
+			notWant: []string{"thermostat_quirk", "home_zone_radius"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := provider.TagContext(context.Background(), agentctx.ContextRequest{UserMessage: tt.message})
+			if err != nil {
+				t.Fatalf("TagContext: %v", err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("output missing %q:\n%s", w, got)
+				}
+			}
+			for _, nw := range tt.notWant {
+				if strings.Contains(got, nw) {
+					t.Errorf("output unexpectedly contains %q:\n%s", nw, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSubjectContextProvider_SubjectSource(t *testing.T) {
+	store := newTestStore(t)
+	if _, err := store.Set(CategoryUser, "alice_schedule", "Works late on Thursdays", "test", 1.0,
+		[]string{"entity:person.alice"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	provider := NewSubjectContextProvider(store, slog.Default())
+
+	got, err := provider.TagContext(context.Background(), agentctx.ContextRequest{UserMessage: "hello"})
+	if err != nil {
+		t.Fatalf("TagContext: %v", err)
+	}
+	if got != "" {
+		t.Fatalf("expected empty without a source, got %q", got)
+	}
+
+	provider.AddSubjectSource(func(context.Context, agentctx.ContextRequest) []string {
+		return []string{"person.alice"}
+	})
+	got, err = provider.TagContext(context.Background(), agentctx.ContextRequest{UserMessage: "hello"})
+	if err != nil {
+		t.Fatalf("TagContext: %v", err)
+	}
+	if !strings.Contains(got, "alice_schedule") {
+		t.Errorf("source subject did not link its fact:\n%s", got)
+	}
+}
+
+func TestGetBySubjects_MatchesLegacyBareEntity(t *testing.T) {
+	store := newTestStore(t)
+	if _, err := store.Set(CategoryDevice, "legacy", "Stored before normalization", "test", 1.0, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.db.Exec(`UPDATE facts SET subjects = ? WHERE key = ?`, `["sensor.garage_door"]`, "legacy"); err != nil {
+		t.Fatal(err)
+	}
+
+	facts, err := store.GetBySubjects([]string{"entity:sensor.garage_door"})
+	if err != nil {
+		t.Fatalf("GetBySubjects: %v", err)
+	}
+	if len(facts) != 1 || facts[0].Key != "legacy" {
+		t.Fatalf("GetBySubjects = %v, want the legacy fact", facts)
+	}
+}
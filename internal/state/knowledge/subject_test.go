@@ -0,0 +1,53 @@
+package knowledge
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeSubject(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"climate.thermostat", "entity:climate.thermostat"},
+		{" Climate.Thermostat ", "entity:climate.thermostat"},
+		{"entity:light.kitchen", "entity:light.kitchen"},
+		{"Entity: light.kitchen", "entity:light.kitchen"},
+		{"zone.home", "zone:home"},
+		{"entity:zone.home", "zone:home"},
+		{"zone:Home", "zone:home"},
+		{"zone:zone.home", "zone:home"},
+		{"contact:7A1B-Mixed", "contact:7A1B-Mixed"},
+		{"camera:driveway_3040", "camera:driveway_3040"},
+		{"the thermostat", "the thermostat"},
+		{"dossiers/foo.md", "dossiers/foo.md"},
+		{"  ", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeSubject(tt.in); got != tt.want {
+			t.Errorf("NormalizeSubject(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeSubjects_Dedup(t *testing.T) {
+	got := NormalizeSubjects([]string{"light.kitchen", "entity:light.kitchen", "", "zone.home", "zone:home"})
+	want := []string{"entity:light.kitchen", "zone:home"}
+	if !slices.Equal(got, want) {
+		t.Errorf("NormalizeSubjects = %v, want %v", got, want)
+	}
+	if got := NormalizeSubjects([]string{" "}); got != nil {
+		t.Errorf("NormalizeSubjects(blank) = %v, want nil", got)
+	}
+}
+
+func TestSubjectsInText(t *testing.T) {
+	got := SubjectsInText("Is climate.thermostat heating? Also check zone.home and climate.thermostat again.")
+	want := []string{"entity:climate.thermostat", "zone:home"}
+	if !slices.Equal(got, want) {
+		t.Errorf("SubjectsInText = %v, want %v", got, want)
+	}
+	if got := SubjectsInText("no entity ids here"); got != nil {
+		t.Errorf("SubjectsInText(plain) = %v, want nil", got)
+	}
+}
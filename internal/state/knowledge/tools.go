@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // EmbeddingClient generates embeddings for semantic search.
@@ -36,6 +38,7 @@ type RememberArgs struct {
 	Source   string   `json:"source,omitempty"`   // Where this came from
 	Subjects []string `json:"subjects,omitempty"` // Subject keys (e.g., "entity:foo", "zone:bar")
 	Ref      string   `json:"ref,omitempty"`      // KB-relative path (e.g., "dossiers/openclawssy.md")
+	TTL      string   `json:"ttl,omitempty"`      // Lifetime for ephemeral facts (e.g., "48h", "3d"); "none" makes the fact lasting
 }
 
 // Remember stores a fact for later recall.
@@ -55,8 +58,13 @@ func (t *Tools) Remember(argsJSON string) (string, error) {
 		return "", fmt.Errorf("value is required")
 	}
 
+	ttl, err := parseTTL(args.TTL)
+	if err != nil {
+		return "", err
+	}
+
 	cat := Category(args.Category)
-	fact, err := t.store.Set(cat, args.Key, args.Value, args.Source, 1.0, args.Subjects, args.Ref)
+	fact, err := t.store.SetWithTTL(cat, args.Key, args.Value, args.Source, 1.0, args.Subjects, args.Ref, ttl)
 	if err != nil {
 		return "", fmt.Errorf("store fact: %w", err)
 	}
@@ -69,7 +77,39 @@ func (t *Tools) Remember(argsJSON string) (string, error) {
 		}
 	}
 
-	return fmt.Sprintf("Remembered: [%s] %s = %s", fact.Category, fact.Key, fact.Value), nil
+	result := fmt.Sprintf("Remembered: [%s] %s = %s", fact.Category, fact.Key, fact.Value)
+	if fact.ExpiresAt != nil {
+		result += " (expires " + fact.ExpiresAt.Format(time.RFC3339) + ")"
+	}
+	return result, nil
+}
+
+// parseTTL parses a fact lifetime: a Go duration ("36h", "90m") or a
+// whole number of days ("3d"). An empty string or "none" means no
+// expiry, which also clears one the fact already had.
+func parseTTL(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.EqualFold(raw, "none") {
+		return 0, nil
+	}
+	var ttl time.Duration
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ttl %q: want a duration like \"48h\" or \"3d\"", raw)
+		}
+		ttl = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ttl %q: want a duration like \"48h\" or \"3d\"", raw)
+		}
+		ttl = d
+	}
+	if ttl <= 0 {
+		return 0, fmt.Errorf("ttl must be positive, got %q", raw)
+	}
+	return ttl, nil
 }
 
 // RecallArgs are arguments for the recall_fact tool.
@@ -94,6 +134,9 @@ func (t *Tools) Recall(argsJSON string) (string, error) {
 		}
 		result := fmt.Sprintf("[%s] %s = %s (confidence: %.1f)",
 			fact.Category, fact.Key, fact.Value, fact.Confidence)
+		if fact.ExpiresAt != nil {
+			result += fmt.Sprintf("\n  expires %s", fact.ExpiresAt.Format(time.RFC3339))
+		}
 		if fact.Ref != "" {
 			result += fmt.Sprintf("\n  → kb:%s", fact.Ref)
 		}
@@ -161,6 +204,57 @@ func (t *Tools) Forget(argsJSON string) (string, error) {
 	return fmt.Sprintf("Forgot: [%s] %s", args.Category, args.Key), nil
 }
 
+// ListConflicts lists fact conflicts awaiting resolution.
+func (t *Tools) ListConflicts(_ string) (string, error) {
+	conflicts, err := t.store.PendingConflicts()
+	if err != nil {
+		return "", err
+	}
+	if len(conflicts) == 0 {
+		return "No pending fact conflicts.", nil
+	}
+
+	var sb strings.Builder
+	for _, c := range conflicts {
+		fmt.Fprintf(&sb, "#%d [%s] %s\n", c.ID, c.Category, c.Key)
+		fmt.Fprintf(&sb, "  stored:   %s (confidence %.1f)\n", c.CurrentValue, c.CurrentConfidence)
+		fmt.Fprintf(&sb, "  observed: %s (confidence %.1f", c.ProposedValue, c.ProposedConfidence)
+		if c.Source != "" {
+			fmt.Fprintf(&sb, ", source %s", c.Source)
+		}
+		fmt.Fprintf(&sb, ", %s)\n", c.CreatedAt.Format(time.RFC3339))
+	}
+	return sb.String(), nil
+}
+
+// ResolveConflictArgs are arguments for resolve_fact_conflict tool.
+type ResolveConflictArgs struct {
+	ID         int64  `json:"id"`
+	Resolution string `json:"resolution"`
+	Value      string `json:"value,omitempty"`
+}
+
+// ResolveConflict settles a pending fact conflict.
+func (t *Tools) ResolveConflict(argsJSON string) (string, error) {
+	var args ResolveConflictArgs
+	if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+		return "", fmt.Errorf("parse args: %w", err)
+	}
+
+	if args.ID <= 0 {
+		return "", fmt.Errorf("id is required")
+	}
+
+	fact, err := t.store.ResolveConflict(args.ID, args.Resolution, args.Value)
+	if err != nil {
+		return "", err
+	}
+	if fact == nil {
+		return fmt.Sprintf("Resolved conflict #%d (%s); the fact no longer exists.", args.ID, args.Resolution), nil
+	}
+	return fmt.Sprintf("Resolved conflict #%d (%s): [%s] %s = %s", args.ID, args.Resolution, fact.Category, fact.Key, fact.Value), nil
+}
+
 // SemanticRecallArgs are arguments for semantic_recall tool.
 type SemanticRecallArgs struct {
 	Query string `json:"query"`
@@ -216,6 +310,9 @@ func formatFacts(facts []*Fact) string {
 	var sb strings.Builder
 	for _, f := range facts {
 		sb.WriteString(fmt.Sprintf("[%s] %s = %s\n", f.Category, f.Key, f.Value))
+		if f.ExpiresAt != nil {
+			sb.WriteString(fmt.Sprintf("  expires %s\n", f.ExpiresAt.Format(time.RFC3339)))
+		}
 		if f.Ref != "" {
 			sb.WriteString(fmt.Sprintf("  → kb:%s\n", f.Ref))
 		}
@@ -223,6 +320,12 @@ func formatFacts(facts []*Fact) string {
 	return sb.String()
 }
 
+// FactEmbeddingText is the text embedded for a fact's semantic search
+// vector.
+func FactEmbeddingText(f *Fact) string {
+	return fmt.Sprintf("%s: %s - %s", f.Category, f.Key, f.Value)
+}
+
 // GenerateMissingEmbeddings creates embeddings for facts that don't have them.
 // Returns count of facts embedded.
 func (t *Tools) GenerateMissingEmbeddings() (int, error) {
@@ -237,8 +340,7 @@ func (t *Tools) GenerateMissingEmbeddings() (int, error) {
 
 	count := 0
 	for _, f := range facts {
-		embText := fmt.Sprintf("%s: %s - %s", f.Category, f.Key, f.Value)
-		emb, err := t.embeddings.Generate(context.Background(), embText)
+		emb, err := t.embeddings.Generate(context.Background(), FactEmbeddingText(f))
 		if err != nil {
 			continue // Skip failures, don't halt
 		}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
@@ -236,3 +237,42 @@ func TestRecall_ShowsRef(t *testing.T) {
 		t.Errorf("Recall result should contain ref annotation, got: %s", result)
 	}
 }
+
+func TestRemember_WithTTL(t *testing.T) {
+	store := newTestStore(t)
+	tools := NewTools(store)
+
+	result, err := tools.Remember(`{"category":"home","key":"guest_room","value":"Occupied","ttl":"3d"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "expires") {
+		t.Errorf("Remember result should mention expiry, got: %s", result)
+	}
+	fact, err := store.Get(CategoryHome, "guest_room")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fact.ExpiresAt == nil {
+		t.Fatal("ExpiresAt = nil, want set")
+	}
+	if left := time.Until(*fact.ExpiresAt); left < 71*time.Hour || left > 72*time.Hour {
+		t.Errorf("expiry in %v, want about 72h", left)
+	}
+
+	if _, err := tools.Remember(`{"category":"home","key":"guest_room","value":"Occupied","ttl":"none"}`); err != nil {
+		t.Fatal(err)
+	}
+	if fact, err = store.Get(CategoryHome, "guest_room"); err != nil {
+		t.Fatal(err)
+	}
+	if fact.ExpiresAt != nil {
+		t.Errorf("ExpiresAt = %v after ttl \"none\", want nil", fact.ExpiresAt)
+	}
+
+	for _, bad := range []string{"soon", "0h", "-2d"} {
+		if _, err := tools.Remember(`{"key":"k","value":"v","ttl":"` + bad + `"}`); err == nil {
+			t.Errorf("Remember with ttl %q succeeded, want error", bad)
+		}
+	}
+}
@@ -1,17 +1,21 @@
 package memory
 
 import (
+	"cmp"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"math"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/nugget/thane-ai-agent/internal/model/promptfmt"
 	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/state/knowledge"
 )
 
 // ArchiveReason describes why messages were archived.
@@ -69,6 +73,13 @@ type ArchiveStore struct {
 	// deliveries.
 	sessionCloseCallback func(sessionID, reason string)
 
+	// Optional vector index over archived messages. Both are set by
+	// SetEmbeddingClient; a nil embedder disables semantic search. The
+	// model name keys stored vectors so a model change re-embeds rather
+	// than comparing incompatible spaces.
+	embedder       knowledge.EmbeddingClient
+	embeddingModel string
+
 	// Context expansion defaults
 	defaultSilenceThreshold time.Duration
 	defaultMaxMessages      int
@@ -176,17 +187,30 @@ type SessionMetadata struct {
 	// Model(s) used, if known.
 	Models []string `json:"models,omitempty"`
 
-	// Legacy delegation execution details, preserved from the delegations
-	// table migration (#446). Only populated for imported delegation records.
+	// Delegation holds the execution record of a delegate session:
+	// the inputs needed to replay it and the outcome to compare a
+	// replay against. Also populated for records imported from the
+	// legacy delegations table (#446).
 	Delegation *DelegationMetadata `json:"delegation,omitempty"`
+
+	// Merges records sessions folded into this one by
+	// [ArchiveStore.MergeSessions], oldest first.
+	Merges []SessionMerge `json:"merges,omitempty"`
 }
 
-// DelegationMetadata holds delegation-specific fields preserved from the
-// legacy delegations table during migration (#446).
+// DelegationMetadata records one delegate execution. Set by
+// [ArchiveStore.SetSessionDelegation] when a delegate finishes, and
+// preserved from the legacy delegations table during migration (#446).
 type DelegationMetadata struct {
-	Task          string `json:"task"`
-	Guidance      string `json:"guidance,omitempty"`
-	Profile       string `json:"profile"`
+	Task     string   `json:"task"`
+	Guidance string   `json:"guidance,omitempty"`
+	Profile  string   `json:"profile"`
+	Tags     []string `json:"tags,omitempty"`
+
+	// PromptMode is the prompt shape the delegate ran with ("task" or
+	// "full"). Empty for legacy records.
+	PromptMode string `json:"prompt_mode,omitempty"`
+
 	Model         string `json:"model"`
 	Iterations    int    `json:"iterations"`
 	MaxIterations int    `json:"max_iterations"`
@@ -198,6 +222,13 @@ type DelegationMetadata struct {
 	DurationMs    int64  `json:"duration_ms"`
 	Error         string `json:"error,omitempty"`
 
+	// ToolCalls lists the tools the delegate called, in call order.
+	ToolCalls []string `json:"tool_calls,omitempty"`
+
+	// ReplayOf is the session ID of the delegate execution this one
+	// replayed. Empty for original executions.
+	ReplayOf string `json:"replay_of,omitempty"`
+
 	// Messages is the raw JSON-serialized conversation history from the
 	// legacy delegations table. Preserved to avoid data loss — these
 	// messages predate the per-message storage in the messages table.
@@ -257,11 +288,15 @@ type SearchResult struct {
 	// derived from the FTS5 BM25 rank, negated so larger means more
 	// relevant. Comparable within a MatchType bucket but not across
 	// buckets (phrase and terms passes score against different MATCH
-	// expressions). Zero on the LIKE fallback path.
+	// expressions). When SearchOptions.RecencyHalfLife is set, the
+	// score is recency-weighted. Zero on the LIKE fallback path.
+	// Semantic hits score by cosine similarity and hybrid results by
+	// fused rank; see [SearchMode].
 	Score float64 `json:"score"`
 	// MatchType records which pass produced the hit: "phrase" (the
-	// literal-phrase precision pass) or "terms" (the OR-of-terms
-	// recall backfill). Empty on the LIKE fallback path.
+	// literal-phrase precision pass), "terms" (the OR-of-terms
+	// recall backfill), or "semantic" (embedding similarity). Empty
+	// on the LIKE fallback path.
 	MatchType string `json:"match_type,omitempty"`
 }
 
@@ -292,8 +327,28 @@ type SearchOptions struct {
 	// reaching for. Set this true when an operator or diagnostic
 	// caller explicitly wants to inspect wake events.
 	IncludeAnticipations bool
+
+	// Mode selects how raw messages are ranked: keyword (FTS5, the
+	// default), semantic (embedding cosine similarity), or hybrid (both
+	// rankings fused). Only [MemorySearch] honors it; the distilled
+	// surfaces and [ArchiveStore.Search] are always keyword.
+	Mode SearchMode
+
+	// RecencyHalfLife, when positive, blends recency into the FTS
+	// ranking: each hit's BM25 score is multiplied by
+	// 0.5^(age/RecencyHalfLife), so a hit one half-life old needs
+	// twice the raw relevance to tie a hit from just now. The top
+	// Limit×recencyCandidateFactor raw matches are re-ranked and the
+	// best Limit kept. Phrase hits still rank ahead of terms backfill.
+	// Zero keeps pure BM25 ordering.
+	RecencyHalfLife time.Duration
 }
 
+// recencyCandidateFactor is how many raw BM25 matches per requested
+// result are fetched when recency weighting is on, so an older
+// top-ranked hit can be displaced by a newer one just below the cut.
+const recencyCandidateFactor = 5
+
 // NewArchiveStore creates a new archive store at the given database path.
 // Pass nil for cfg to use DefaultArchiveConfig().
 // Pass nil for logger to suppress startup logging.
@@ -341,6 +396,11 @@ func NewArchiveStore(dbPath string, messagesDB *sql.DB, cfg *ArchiveConfig, logg
 	// Apply incremental migrations for existing databases
 	s.migrateSchema()
 
+	if err := s.migrateEmbeddings(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("archive migrate: %w", err)
+	}
+
 	// Try to enable FTS5 — gracefully degrade if not available
 	s.ftsEnabled = s.tryEnableFTS()
 
@@ -407,6 +467,9 @@ func NewArchiveStoreFromDB(db *sql.DB, cfg *ArchiveConfig, logger *slog.Logger)
 	}
 
 	s.migrateSchema()
+	if err := s.migrateEmbeddings(); err != nil {
+		return nil, fmt.Errorf("archive migrate: %w", err)
+	}
 	s.ftsEnabled = s.tryEnableFTS()
 	s.sessionsFTSEnabled = s.trySetupSessionsFTS()
 
@@ -1382,6 +1445,18 @@ func (s *ArchiveStore) Search(opts SearchOptions) ([]SearchResult, error) {
 	if strings.TrimSpace(opts.Query) == "" {
 		return nil, fmt.Errorf("query is required")
 	}
+	opts = s.normalizeSearchOptions(opts)
+
+	matches, err := s.keywordMatches(opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.expandMatches(matches, opts), nil
+}
+
+// normalizeSearchOptions fills unset limits and context-expansion
+// bounds from the store defaults.
+func (s *ArchiveStore) normalizeSearchOptions(opts SearchOptions) SearchOptions {
 	if opts.Limit <= 0 {
 		opts.Limit = 10
 	}
@@ -1394,23 +1469,32 @@ func (s *ArchiveStore) Search(opts SearchOptions) ([]SearchResult, error) {
 	if opts.MaxDuration == 0 {
 		opts.MaxDuration = s.defaultMaxDuration
 	}
+	return opts
+}
 
-	// Run the search. FTS5 path uses phrase-first + OR-of-terms
-	// backfill so multi-word queries get phrase-anchored precision
-	// at the top with recall headroom when the phrase is sparse.
-	// LIKE path is the FTS5-unavailable fallback.
-	var matches []matchWithHighlight
-	var err error
-	if s.ftsEnabled {
-		matches, err = s.searchFTS(opts)
-	} else {
-		matches, err = s.searchLIKE(opts)
+// keywordMatches collects up to opts.Limit keyword hits. The FTS5
+// path uses phrase-first + OR-of-terms backfill so multi-word queries
+// get phrase-anchored precision at the top with recall headroom when
+// the phrase is sparse. The LIKE path is the FTS5-unavailable fallback.
+func (s *ArchiveStore) keywordMatches(opts SearchOptions) ([]matchWithHighlight, error) {
+	if s.ftsEnabled && opts.RecencyHalfLife > 0 {
+		candidates := opts
+		candidates.Limit = opts.Limit * recencyCandidateFactor
+		matches, err := s.searchFTS(candidates)
+		if err != nil {
+			return nil, err
+		}
+		return rankByRecency(matches, opts.RecencyHalfLife, time.Now(), opts.Limit), nil
 	}
-	if err != nil {
-		return nil, err
+	if s.ftsEnabled {
+		return s.searchFTS(opts)
 	}
+	return s.searchLIKE(opts)
+}
 
-	// Now expand context for each match (safe to query again)
+// expandMatches turns ranked matches into search results, attaching
+// the gap-aware context window around each unless opts.NoContext.
+func (s *ArchiveStore) expandMatches(matches []matchWithHighlight, opts SearchOptions) []SearchResult {
 	var results []SearchResult
 	for _, mh := range matches {
 		var before, after []Message
@@ -1429,8 +1513,7 @@ func (s *ArchiveStore) Search(opts SearchOptions) ([]SearchResult, error) {
 			MatchType:     mh.matchType,
 		})
 	}
-
-	return results, nil
+	return results
 }
 
 // matchWithHighlight is the per-row shape both searchFTS and
@@ -1552,8 +1635,18 @@ const ftsMatchColumns = "am.id, am.conversation_id, COALESCE(am.session_id, '')
 // already constrained by the FTS MATCH, so the per-row datetime() call
 // is cheap.
 func (s *ArchiveStore) ftsConditions(ftsExpr string, opts SearchOptions) ([]string, []any) {
-	conditions := []string{s.msgFTSName + " MATCH ?"}
-	args := []any{ftsExpr}
+	conditions, args := messageFilterConditions(opts)
+	return append([]string{s.msgFTSName + " MATCH ?"}, conditions...), append([]any{ftsExpr}, args...)
+}
+
+// messageFilterConditions builds the non-MATCH half of the search
+// WHERE clause against the message table aliased am: conversation
+// scope, anticipation exclusion, and time-range bounds. Semantic
+// search applies the same filters so every mode ranks over the same
+// candidate set.
+func messageFilterConditions(opts SearchOptions) ([]string, []any) {
+	var conditions []string
+	var args []any
 	if opts.ConversationID != "" {
 		conditions = append(conditions, "am.conversation_id = ?")
 		args = append(args, opts.ConversationID)
@@ -1731,6 +1824,39 @@ func mergeFTSMatches(phrase, backfill []matchWithHighlight, limit int) []matchWi
 	return out
 }
 
+// rankByRecency decays each match's score by its age with the given
+// half-life, re-sorts within each match-type bucket (phrase hits stay
+// ahead of terms backfill, since their scores are not comparable), and
+// keeps the best limit. Ties keep their BM25 order.
+func rankByRecency(matches []matchWithHighlight, halfLife time.Duration, now time.Time, limit int) []matchWithHighlight {
+	for i := range matches {
+		age := now.Sub(matches[i].msg.Timestamp)
+		if age < 0 {
+			age = 0
+		}
+		matches[i].score *= math.Exp2(-float64(age) / float64(halfLife))
+	}
+	slices.SortStableFunc(matches, func(a, b matchWithHighlight) int {
+		if a.matchType != b.matchType {
+			return cmp.Compare(matchTypeTier(a.matchType), matchTypeTier(b.matchType))
+		}
+		return cmp.Compare(b.score, a.score)
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// matchTypeTier orders match-type buckets for [rankByRecency]:
+// phrase-precision hits before OR-of-terms backfill.
+func matchTypeTier(matchType string) int {
+	if matchType == "phrase" {
+		return 0
+	}
+	return 1
+}
+
 // expandContext walks messages outward from a timestamp, stopping at silence gaps.
 func (s *ArchiveStore) expandContext(
 	conversationID string,
@@ -1994,7 +2120,15 @@ func (s *ArchiveStore) ClaimActiveMessages(conversationID, sessionID string) (in
 // but were started before the given cutoff time. This recovers sessions orphaned
 // by crashes (SIGKILL, OOM, panics) where EndSession was never called. Returns
 // the number of sessions closed.
+//
+// Active messages and tool calls of an orphaned session's conversation
+// that were never stamped with a session (batched archiving, or rows
+// stored before the session started) are claimed by that session first,
+// so its transcript is complete when the summarizer picks it up.
 func (s *ArchiveStore) CloseOrphanedSessions(before time.Time) (int64, error) {
+	if err := s.claimOrphanedMessages(before); err != nil {
+		return 0, err
+	}
 	result, err := s.db.Exec(`
 		UPDATE sessions
 		SET ended_at = ?, end_reason = 'crash_recovery'
@@ -2006,6 +2140,46 @@ func (s *ArchiveStore) CloseOrphanedSessions(before time.Time) (int64, error) {
 	return result.RowsAffected()
 }
 
+// claimOrphanedMessages stamps the unclaimed active messages of each
+// conversation with an open session started before the cutoff onto
+// that session. When a conversation has more than one such session,
+// the newest claims them. A no-op in legacy mode, where messages always
+// carry their session.
+func (s *ArchiveStore) claimOrphanedMessages(before time.Time) error {
+	if s.messagesDB == nil {
+		return nil
+	}
+	rows, err := s.db.Query(`
+		SELECT id, conversation_id FROM sessions
+		WHERE ended_at IS NULL AND started_at < ?
+		ORDER BY started_at DESC
+	`, before.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("query orphaned sessions: %w", err)
+	}
+	type orphan struct{ sessionID, conversationID string }
+	var orphans []orphan
+	for rows.Next() {
+		var o orphan
+		if err := rows.Scan(&o.sessionID, &o.conversationID); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan orphaned session: %w", err)
+		}
+		orphans = append(orphans, o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("query orphaned sessions: %w", err)
+	}
+
+	for _, o := range orphans {
+		if _, err := s.ClaimActiveMessages(o.conversationID, o.sessionID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // SetSessionSummary updates only the summary text for a session.
 // For richer metadata, use SetSessionMetadata.
 func (s *ArchiveStore) SetSessionSummary(sessionID string, summary string) error {
@@ -2030,6 +2204,25 @@ func (s *ArchiveStore) SetSessionMetadata(sessionID string, meta *SessionMetadat
 			meta.ChannelBinding = existingMeta.ChannelBinding.Clone()
 		}
 	}
+	// Merge provenance is an audit record, not summary output; keep it
+	// across re-summarization.
+	if existingMeta != nil && len(existingMeta.Merges) > 0 {
+		if meta == nil {
+			meta = &SessionMetadata{}
+		}
+		if meta.Merges == nil {
+			meta.Merges = existingMeta.Merges
+		}
+	}
+	// Likewise the delegation record, which replay depends on.
+	if existingMeta != nil && existingMeta.Delegation != nil {
+		if meta == nil {
+			meta = &SessionMetadata{}
+		}
+		if meta.Delegation == nil {
+			meta.Delegation = existingMeta.Delegation
+		}
+	}
 
 	metaJSON, err := sessionMetadataJSON(meta)
 	if err != nil {
@@ -2061,6 +2254,27 @@ func (s *ArchiveStore) SetSessionMetadata(sessionID string, meta *SessionMetadat
 	return err
 }
 
+// SetSessionDelegation records the delegate execution details for a
+// session, leaving the rest of its metadata, title, and tags untouched.
+func (s *ArchiveStore) SetSessionDelegation(sessionID string, d *DelegationMetadata) error {
+	meta, err := s.sessionMetadata(sessionID)
+	if err != nil {
+		return err
+	}
+	if meta == nil {
+		meta = &SessionMetadata{}
+	}
+	meta.Delegation = d
+	metaJSON, err := sessionMetadataJSON(meta)
+	if err != nil {
+		return fmt.Errorf("marshal metadata: %w", err)
+	}
+	if _, err := s.db.Exec(`UPDATE sessions SET metadata = ? WHERE id = ?`, string(metaJSON), sessionID); err != nil {
+		return fmt.Errorf("set session delegation: %w", err)
+	}
+	return nil
+}
+
 func sessionMetadataJSON(meta *SessionMetadata) ([]byte, error) {
 	if meta == nil {
 		return nil, nil
@@ -2154,8 +2368,9 @@ func (s *ArchiveStore) ActiveSessionsWithLastActivity() ([]IdleSessionInfo, erro
 		info.LastActivity = startedAt // default: session start time
 
 		// Query most recent message timestamp from the messages DB.
-		// In unified mode (messagesDB != nil), active messages have
-		// session_id=NULL until archival, so also match on
+		// In unified mode (messagesDB != nil), active messages may have
+		// session_id=NULL until archival (batched archiving, or rows
+		// stored before the session started), so also match on
 		// conversation_id + status='active'. In legacy mode
 		// (archive_messages table), session_id is always set and
 		// there's no status column.
@@ -2590,20 +2805,40 @@ func (s *ArchiveStore) queryMessagesDesc(conversationID, excludeSessionID string
 	return s.scanMessages(rows)
 }
 
+// MarkdownExportOptions controls [ArchiveStore.ExportSessionMarkdown]
+// and [ArchiveStore.ExportConversationMarkdown]. The zero value exports
+// the full record.
+type MarkdownExportOptions struct {
+	// OmitToolCalls drops tool results and the assistant turns that only
+	// carried tool calls, leaving a cleaner reading transcript.
+	OmitToolCalls bool
+}
+
 // ExportSessionMarkdown exports a session transcript as human-readable markdown.
-// Includes tool call records interleaved chronologically with messages.
-func (s *ArchiveStore) ExportSessionMarkdown(sessionID string) (string, error) {
+// Unless opts.OmitToolCalls is set, tool call records are interleaved
+// chronologically with messages.
+func (s *ArchiveStore) ExportSessionMarkdown(sessionID string, opts MarkdownExportOptions) (string, error) {
 	sess, err := s.GetSession(sessionID)
 	if err != nil {
 		return "", fmt.Errorf("get session: %w", err)
 	}
+	if sess == nil {
+		return "", fmt.Errorf("session %s not found", sessionID)
+	}
 
 	messages, err := s.GetSessionTranscript(sessionID)
 	if err != nil {
 		return "", fmt.Errorf("get transcript: %w", err)
 	}
 
-	toolCalls, _ := s.GetSessionToolCalls(sessionID)
+	var toolCalls []ArchivedToolCall
+	if !opts.OmitToolCalls {
+		toolCalls, _ = s.GetSessionToolCalls(sessionID)
+	} else {
+		messages = slices.DeleteFunc(messages, func(m Message) bool {
+			return m.Role == "tool" || (m.Role == "assistant" && strings.TrimSpace(m.Content) == "")
+		})
+	}
 
 	// Build a lookup of tool calls by start time for interleaving
 	type toolCallEntry struct {
@@ -2680,6 +2915,85 @@ func (s *ArchiveStore) ExportSessionMarkdown(sessionID string) (string, error) {
 	return sb.String(), nil
 }
 
+// ExportConversationMarkdown exports every archived session of a
+// conversation as markdown, oldest session first. Each session renders
+// exactly as [ArchiveStore.ExportSessionMarkdown] would.
+func (s *ArchiveStore) ExportConversationMarkdown(conversationID string, opts MarkdownExportOptions) (string, error) {
+	ids, err := s.conversationSessionIDs(conversationID)
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		md, err := s.ExportSessionMarkdown(id, opts)
+		if err != nil {
+			return "", fmt.Errorf("export session %s: %w", ShortID(id), err)
+		}
+		parts = append(parts, md)
+	}
+	return strings.Join(parts, "\n"), nil
+}
+
+// conversationSessionIDs returns the IDs of every archived session of
+// a conversation, oldest first. It is an error for there to be none.
+func (s *ArchiveStore) conversationSessionIDs(conversationID string) ([]string, error) {
+	// Order through datetime() because stored session timestamps mix
+	// RFC3339 local-offset and driver-native forms (#761).
+	rows, err := s.db.Query(`
+		SELECT id FROM sessions
+		WHERE conversation_id = ?
+		ORDER BY datetime(started_at) ASC, id ASC
+	`, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("list conversation sessions: %w", err)
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan session id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list conversation sessions: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no sessions found for conversation %q", conversationID)
+	}
+	return ids, nil
+}
+
+// ResolveSessionID expands a session ID prefix, such as the 8-character
+// [ShortID] form, to the full session ID. A complete ID resolves to
+// itself. It returns an error when no session or more than one session
+// matches. Unlike a scan of [ArchiveStore.ListSessions], it finds
+// sessions of any age.
+func (s *ArchiveStore) ResolveSessionID(prefix string) (string, error) {
+	if prefix == "" {
+		return "", fmt.Errorf("session ID prefix is required")
+	}
+
+	var count int
+	var id sql.NullString
+	if err := s.db.QueryRow(`
+		SELECT COUNT(*), MIN(id) FROM sessions WHERE substr(id, 1, ?) = ?
+	`, len(prefix), prefix).Scan(&count, &id); err != nil {
+		return "", fmt.Errorf("resolve session prefix: %w", err)
+	}
+
+	switch count {
+	case 0:
+		return "", fmt.Errorf("no session found with prefix %q", prefix)
+	case 1:
+		return id.String, nil
+	default:
+		return "", fmt.Errorf("ambiguous prefix %q matches %d sessions", prefix, count)
+	}
+}
+
 // Stats returns archive statistics.
 func (s *ArchiveStore) Stats() (map[string]any, error) {
 	stats := make(map[string]any)
@@ -2939,6 +3253,9 @@ func (s *ArchiveStore) PurgeImported(sourceType string) (int, error) {
 	if s.messagesDB != nil {
 		wdb := s.msgDB()
 		for _, sid := range sessionIDs {
+			if err := deleteMessageEmbeddings(wdb, s.msgTableName, "session_id = ?", sid); err != nil {
+				return 0, fmt.Errorf("delete embeddings for session %s: %w", ShortID(sid), err)
+			}
 			if _, err := wdb.Exec(fmt.Sprintf(`DELETE FROM %s WHERE session_id = ?`, s.msgTableName), sid); err != nil {
 				return 0, fmt.Errorf("delete messages for session %s: %w", ShortID(sid), err)
 			}
@@ -2959,6 +3276,9 @@ func (s *ArchiveStore) PurgeImported(sourceType string) (int, error) {
 	for _, sid := range sessionIDs {
 		// In legacy mode (messagesDB == nil), messages and tool calls are in archive.db.
 		if s.messagesDB == nil {
+			if err := deleteMessageEmbeddings(tx, s.msgTableName, "session_id = ?", sid); err != nil {
+				return 0, fmt.Errorf("delete embeddings for session %s: %w", ShortID(sid), err)
+			}
 			if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE session_id = ?`, s.msgTableName), sid); err != nil {
 				return 0, fmt.Errorf("delete messages for session %s: %w", ShortID(sid), err)
 			}
@@ -283,3 +283,90 @@ func TestAdapter_ActiveConversationIDs(t *testing.T) {
 		t.Fatalf("ActiveConversationIDs() = %v, want %v", got, want)
 	}
 }
+
+func TestAdapter_IncrementalArchiveSurvivesCrash(t *testing.T) {
+	adapter, archiveStore, workingStore := newTestAdapter(t)
+	workingStore.SetSessionResolver(adapter.ActiveSessionID)
+
+	// The first message arrives before any session exists, as on a
+	// conversation's first turn; the rest are stamped as stored.
+	if err := workingStore.AddMessage("conv-1", "user", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	sid, err := adapter.StartSession("conv-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := workingStore.AddMessage("conv-1", "assistant", "hi there!"); err != nil {
+		t.Fatal(err)
+	}
+	if err := workingStore.RecordToolCall("conv-1", "", "call-1", "get_state", `{}`); err != nil {
+		t.Fatal(err)
+	}
+
+	var stamped int
+	if err := workingStore.DB().QueryRow(
+		`SELECT COUNT(*) FROM messages WHERE session_id = ? AND status = 'active'`, sid,
+	).Scan(&stamped); err != nil {
+		t.Fatal(err)
+	}
+	if stamped != 1 {
+		t.Errorf("messages stamped at insert = %d, want 1", stamped)
+	}
+
+	// Crash: the session is never archived or ended. Recovery on the
+	// next start must leave it with its full transcript.
+	if _, err := archiveStore.CloseOrphanedSessions(time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	transcript, err := archiveStore.GetSessionTranscript(sid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transcript) != 2 {
+		t.Fatalf("recovered transcript has %d messages, want 2", len(transcript))
+	}
+	calls, err := archiveStore.GetSessionToolCalls(sid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 {
+		t.Errorf("recovered tool calls = %d, want 1", len(calls))
+	}
+}
+
+// BenchmarkAddMessage compares storing a message with and without
+// incremental archiving, which adds a cached session lookup to every
+// insert.
+func BenchmarkAddMessage(b *testing.B) {
+	for _, incremental := range []bool{false, true} {
+		name := "batched"
+		if incremental {
+			name = "incremental"
+		}
+		b.Run(name, func(b *testing.B) {
+			workingStore, err := NewSQLiteStore(b.TempDir()+"/working.db", 100)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer workingStore.Close()
+			archiveStore, err := NewArchiveStoreFromDB(workingStore.DB(), nil, nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+			adapter := NewArchiveAdapter(archiveStore, workingStore, workingStore, slog.New(slog.DiscardHandler))
+			if _, err := adapter.StartSession("conv-1"); err != nil {
+				b.Fatal(err)
+			}
+			if incremental {
+				workingStore.SetSessionResolver(adapter.ActiveSessionID)
+			}
+
+			for b.Loop() {
+				if err := workingStore.AddMessage("conv-1", "user", "hello"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package memory
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// SessionMerge records one [ArchiveStore.MergeSessions] operation in the
+// surviving session's metadata, so a stitched-together session can be
+// audited back to the sessions it absorbed.
+type SessionMerge struct {
+	MergedAt   time.Time `json:"merged_at"`
+	SessionIDs []string  `json:"session_ids"`
+	Messages   int       `json:"messages"` // messages reassigned to the primary
+}
+
+// MergeSessions folds the secondary sessions into primaryID. Every
+// message, tool call, and iteration of a secondary is reassigned to the
+// primary — iteration indexes are shifted past the primary's so they
+// stay unique, with tool-call iteration links shifted to match — and the
+// secondary session rows are deleted. The primary's span widens to
+// cover the secondaries (an active primary stays open), its message
+// count is recomputed, and a [SessionMerge] note is appended to its
+// metadata.
+//
+// Secondaries must exist and be ended; merging a live session would
+// strand the messages its conversation is still writing. A session
+// cannot be merged into itself.
+func (s *ArchiveStore) MergeSessions(primaryID string, secondaryIDs ...string) error {
+	if primaryID == "" {
+		return fmt.Errorf("merge sessions: primary session ID is required")
+	}
+	if len(secondaryIDs) == 0 {
+		return fmt.Errorf("merge sessions: at least one secondary session is required")
+	}
+
+	primary, err := s.GetSession(primaryID)
+	if err != nil {
+		return fmt.Errorf("merge sessions: load primary %s: %w", ShortID(primaryID), err)
+	}
+	if primary == nil {
+		return fmt.Errorf("merge sessions: session not found: %s", primaryID)
+	}
+
+	seen := make(map[string]bool, len(secondaryIDs))
+	secondaries := make([]*Session, 0, len(secondaryIDs))
+	for _, id := range secondaryIDs {
+		if id == primaryID {
+			return fmt.Errorf("merge sessions: cannot merge session %s into itself", ShortID(id))
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		sess, err := s.GetSession(id)
+		if err != nil {
+			return fmt.Errorf("merge sessions: load %s: %w", ShortID(id), err)
+		}
+		if sess == nil {
+			return fmt.Errorf("merge sessions: session not found: %s", id)
+		}
+		if sess.EndedAt == nil {
+			return fmt.Errorf("merge sessions: session %s is still active", ShortID(id))
+		}
+		secondaries = append(secondaries, sess)
+	}
+	// Fold in chronological order so appended iterations read in the
+	// order they happened.
+	slices.SortFunc(secondaries, func(a, b *Session) int { return a.StartedAt.Compare(b.StartedAt) })
+
+	sessTx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("merge sessions: begin tx: %w", err)
+	}
+	defer func() { _ = sessTx.Rollback() }()
+
+	// Messages and tool calls may live in a separate database (unified
+	// mode with its own messages DB). Share the transaction when they
+	// don't so the whole merge is atomic.
+	msgTx := sessTx
+	if s.msgDB() != s.db {
+		msgTx, err = s.msgDB().Begin()
+		if err != nil {
+			return fmt.Errorf("merge sessions: begin message tx: %w", err)
+		}
+		defer func() { _ = msgTx.Rollback() }()
+	}
+
+	startedAt := primary.StartedAt
+	endedAt := primary.EndedAt
+	moved := 0
+	ids := make([]string, 0, len(secondaries))
+	for _, sec := range secondaries {
+		offset, err := nextIterationIndex(sessTx, primaryID)
+		if err != nil {
+			return fmt.Errorf("merge sessions: %w", err)
+		}
+
+		if _, err := sessTx.Exec(`
+			UPDATE archive_iterations
+			SET session_id = ?, iteration_index = iteration_index + ?
+			WHERE session_id = ?
+		`, primaryID, offset, sec.ID); err != nil {
+			return fmt.Errorf("merge sessions: move iterations of %s: %w", ShortID(sec.ID), err)
+		}
+		if _, err := msgTx.Exec(fmt.Sprintf(`
+			UPDATE %s
+			SET session_id = ?, iteration_index = iteration_index + ?
+			WHERE session_id = ?
+		`, s.tcTableName), primaryID, offset, sec.ID); err != nil {
+			return fmt.Errorf("merge sessions: move tool calls of %s: %w", ShortID(sec.ID), err)
+		}
+		res, err := msgTx.Exec(fmt.Sprintf(`UPDATE %s SET session_id = ? WHERE session_id = ?`, s.msgTableName),
+			primaryID, sec.ID)
+		if err != nil {
+			return fmt.Errorf("merge sessions: move messages of %s: %w", ShortID(sec.ID), err)
+		}
+		n, _ := res.RowsAffected()
+		moved += int(n)
+
+		// Keep references to the secondary pointing at a live row.
+		if _, err := sessTx.Exec(`UPDATE sessions SET parent_session_id = ? WHERE parent_session_id = ?`, primaryID, sec.ID); err != nil {
+			return fmt.Errorf("merge sessions: reparent children of %s: %w", ShortID(sec.ID), err)
+		}
+		if _, err := sessTx.Exec(`UPDATE import_metadata SET archive_session_id = ? WHERE archive_session_id = ?`, primaryID, sec.ID); err != nil {
+			return fmt.Errorf("merge sessions: repoint imports of %s: %w", ShortID(sec.ID), err)
+		}
+		if _, err := sessTx.Exec(`DELETE FROM sessions WHERE id = ?`, sec.ID); err != nil {
+			return fmt.Errorf("merge sessions: delete %s: %w", ShortID(sec.ID), err)
+		}
+
+		if sec.StartedAt.Before(startedAt) {
+			startedAt = sec.StartedAt
+		}
+		if endedAt != nil && sec.EndedAt.After(*endedAt) {
+			endedAt = sec.EndedAt
+		}
+		ids = append(ids, sec.ID)
+	}
+
+	var count int
+	if err := msgTx.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE session_id = ?`, s.msgTableName), primaryID).Scan(&count); err != nil {
+		return fmt.Errorf("merge sessions: count messages: %w", err)
+	}
+
+	meta := primary.Metadata
+	if meta == nil {
+		meta = &SessionMetadata{}
+	}
+	meta.Merges = append(meta.Merges, SessionMerge{
+		MergedAt:   time.Now().UTC(),
+		SessionIDs: ids,
+		Messages:   moved,
+	})
+	metaJSON, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("merge sessions: marshal metadata: %w", err)
+	}
+
+	var endedArg any
+	if endedAt != nil {
+		endedArg = endedAt.UTC().Format(time.RFC3339Nano)
+	}
+	if _, err := sessTx.Exec(`
+		UPDATE sessions SET started_at = ?, ended_at = ?, message_count = ?, metadata = ?
+		WHERE id = ?
+	`, startedAt.UTC().Format(time.RFC3339Nano), endedArg, count, string(metaJSON), primaryID); err != nil {
+		return fmt.Errorf("merge sessions: update primary: %w", err)
+	}
+
+	if msgTx != sessTx {
+		if err := msgTx.Commit(); err != nil {
+			return fmt.Errorf("merge sessions: commit messages: %w", err)
+		}
+	}
+	if err := sessTx.Commit(); err != nil {
+		return fmt.Errorf("merge sessions: commit: %w", err)
+	}
+
+	if s.logger != nil {
+		s.logger.Info("sessions merged",
+			"primary", ShortID(primaryID),
+			"merged", len(ids),
+			"messages_moved", moved,
+		)
+	}
+	return nil
+}
+
+// nextIterationIndex returns the first free iteration index in a session.
+func nextIterationIndex(tx *sql.Tx, sessionID string) (int, error) {
+	var maxIdx int
+	if err := tx.QueryRow(
+		`SELECT COALESCE(MAX(iteration_index), -1) FROM archive_iterations WHERE session_id = ?`,
+		sessionID,
+	).Scan(&maxIdx); err != nil {
+		return 0, fmt.Errorf("query max iteration index: %w", err)
+	}
+	return maxIdx + 1, nil
+}
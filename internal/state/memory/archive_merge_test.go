@@ -0,0 +1,297 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// mergeFixture seeds two sessions of one conversation with messages,
+// tool calls, and iterations, ending both. seedMessages and
+// seedToolCall insert rows the way the storage mode under test does.
+type mergeFixture struct {
+	store     *ArchiveStore
+	primary   *Session
+	secondary *Session
+	base      time.Time
+}
+
+func newMergeFixture(t *testing.T, store *ArchiveStore, seedMessage func(id, sessionID string, ts time.Time), seedToolCall func(id, sessionID string, ts time.Time, iteration int)) *mergeFixture {
+	t.Helper()
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	primary, err := store.StartSessionAt("conv-merge", base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondary, err := store.StartSessionAt("conv-merge", base.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seedMessage("p-1", primary.ID, base.Add(time.Minute))
+	seedMessage("p-2", primary.ID, base.Add(2*time.Minute))
+	seedMessage("s-1", secondary.ID, base.Add(61*time.Minute))
+	seedMessage("s-2", secondary.ID, base.Add(62*time.Minute))
+	seedMessage("s-3", secondary.ID, base.Add(63*time.Minute))
+	seedToolCall("tc-p", primary.ID, base.Add(time.Minute), 0)
+	seedToolCall("tc-s", secondary.ID, base.Add(61*time.Minute), 1)
+
+	for _, it := range []ArchivedIteration{
+		{SessionID: primary.ID, Model: "m", StartedAt: base, ToolCallIDs: []string{"tc-p"}},
+		{SessionID: primary.ID, Model: "m", StartedAt: base.Add(time.Minute)},
+	} {
+		if err := store.ArchiveIterations([]ArchivedIteration{it}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.ArchiveIterations([]ArchivedIteration{
+		{SessionID: secondary.ID, Model: "m", StartedAt: base.Add(time.Hour)},
+		{SessionID: secondary.ID, IterationIndex: 1, Model: "m", StartedAt: base.Add(61 * time.Minute), ToolCallIDs: []string{"tc-s"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.EndSessionAt(primary.ID, "idle", base.Add(10*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.EndSessionAt(secondary.ID, "idle", base.Add(70*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	return &mergeFixture{store: store, primary: primary, secondary: secondary, base: base}
+}
+
+func (f *mergeFixture) verify(t *testing.T) {
+	t.Helper()
+	store := f.store
+
+	if err := store.MergeSessions(f.primary.ID, f.secondary.ID); err != nil {
+		t.Fatalf("MergeSessions: %v", err)
+	}
+
+	gone, err := store.GetSession(f.secondary.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gone != nil {
+		t.Error("secondary session row should be deleted")
+	}
+
+	merged, err := store.GetSession(f.primary.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.MessageCount != 5 {
+		t.Errorf("MessageCount = %d, want 5", merged.MessageCount)
+	}
+	if merged.EndedAt == nil || !merged.EndedAt.Equal(f.base.Add(70*time.Minute)) {
+		t.Errorf("EndedAt = %v, want the secondary's end %v", merged.EndedAt, f.base.Add(70*time.Minute))
+	}
+	if merged.Metadata == nil || len(merged.Metadata.Merges) != 1 {
+		t.Fatalf("Metadata.Merges = %+v, want one merge note", merged.Metadata)
+	}
+	note := merged.Metadata.Merges[0]
+	if len(note.SessionIDs) != 1 || note.SessionIDs[0] != f.secondary.ID || note.Messages != 3 {
+		t.Errorf("merge note = %+v, want secondary %s with 3 messages", note, f.secondary.ID)
+	}
+
+	transcript, err := store.GetSessionTranscript(f.primary.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, m := range transcript {
+		ids = append(ids, m.ID)
+	}
+	if got := strings.Join(ids, ","); got != "p-1,p-2,s-1,s-2,s-3" {
+		t.Errorf("transcript = %s, want both sessions in order", got)
+	}
+
+	calls, err := store.GetSessionToolCalls(f.primary.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("tool calls = %d, want 2", len(calls))
+	}
+	for _, c := range calls {
+		if c.ID == "tc-s" && (c.IterationIndex == nil || *c.IterationIndex != 3) {
+			t.Errorf("tc-s iteration_index = %v, want 3 (shifted past the primary's iterations)", c.IterationIndex)
+		}
+	}
+
+	iters, err := store.GetSessionIterations(f.primary.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(iters) != 4 {
+		t.Fatalf("iterations = %d, want 4", len(iters))
+	}
+	for i, it := range iters {
+		if it.IterationIndex != i {
+			t.Errorf("iteration %d has index %d, want contiguous indexes", i, it.IterationIndex)
+		}
+	}
+	if len(iters[3].ToolCallIDs) != 1 || iters[3].ToolCallIDs[0] != "tc-s" {
+		t.Errorf("iteration 3 tool calls = %v, want [tc-s]", iters[3].ToolCallIDs)
+	}
+}
+
+func TestMergeSessions_Legacy(t *testing.T) {
+	store := newTestArchiveStore(t)
+	f := newMergeFixture(t, store,
+		func(id, sessionID string, ts time.Time) {
+			if err := store.ArchiveMessages([]Message{{
+				ID: id, ConversationID: "conv-merge", SessionID: sessionID, Role: "user",
+				Content: "message " + id, Timestamp: ts, ArchiveReason: string(ArchiveReasonReset),
+			}}); err != nil {
+				t.Fatal(err)
+			}
+		},
+		func(id, sessionID string, ts time.Time, iteration int) {
+			if err := store.ArchiveToolCalls([]ArchivedToolCall{{
+				ID: id, ConversationID: "conv-merge", SessionID: sessionID, ToolName: "probe",
+				Arguments: "{}", StartedAt: ts,
+			}}); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.LinkToolCallsToIteration(sessionID, iteration, []string{id}); err != nil {
+				t.Fatal(err)
+			}
+		},
+	)
+	f.verify(t)
+}
+
+func TestMergeSessions_Unified(t *testing.T) {
+	workingStore, err := NewSQLiteStore(t.TempDir()+"/working.db", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer workingStore.Close()
+	store, err := NewArchiveStoreFromDB(workingStore.DB(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := workingStore.DB()
+	if _, err := db.Exec(`INSERT INTO conversations (id, created_at, updated_at) VALUES ('conv-merge', ?, ?)`,
+		time.Now().UTC(), time.Now().UTC()); err != nil {
+		t.Fatal(err)
+	}
+
+	f := newMergeFixture(t, store,
+		func(id, sessionID string, ts time.Time) {
+			if _, err := db.Exec(`
+				INSERT INTO messages (id, conversation_id, session_id, role, content, timestamp, status, archived_at, archive_reason)
+				VALUES (?, 'conv-merge', ?, 'user', ?, ?, 'archived', ?, 'reset')
+			`, id, sessionID, "message "+id, ts.Format(time.RFC3339Nano), ts.Format(time.RFC3339Nano)); err != nil {
+				t.Fatal(err)
+			}
+		},
+		func(id, sessionID string, ts time.Time, iteration int) {
+			if _, err := db.Exec(`
+				INSERT INTO tool_calls (id, conversation_id, session_id, tool_name, arguments, started_at, status, iteration_index)
+				VALUES (?, 'conv-merge', ?, 'probe', '{}', ?, 'archived', ?)
+			`, id, sessionID, ts.Format(time.RFC3339Nano), iteration); err != nil {
+				t.Fatal(err)
+			}
+		},
+	)
+	f.verify(t)
+}
+
+func TestMergeSessions_Guards(t *testing.T) {
+	store := newTestArchiveStore(t)
+	primary, err := store.StartSession("conv-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	active, err := store.StartSession("conv-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		primary   string
+		secondary []string
+		wantErr   string
+	}{
+		{"into itself", primary.ID, []string{primary.ID}, "into itself"},
+		{"no secondaries", primary.ID, nil, "at least one"},
+		{"unknown primary", "missing", []string{active.ID}, "not found"},
+		{"unknown secondary", primary.ID, []string{"missing"}, "not found"},
+		{"active secondary", primary.ID, []string{active.ID}, "still active"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.MergeSessions(tt.primary, tt.secondary...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("MergeSessions error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if sess, _ := store.GetSession(active.ID); sess == nil {
+		t.Error("rejected merge must leave the secondary in place")
+	}
+}
+
+func TestSetSessionMetadata_PreservesMergeNotes(t *testing.T) {
+	store := newTestArchiveStore(t)
+	primary, _ := store.StartSession("conv-1")
+	secondary, _ := store.StartSession("conv-1")
+	if err := store.EndSession(secondary.ID, "idle"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.MergeSessions(primary.ID, secondary.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetSessionMetadata(primary.ID, &SessionMetadata{OneLiner: "stitched"}, "Stitched", nil); err != nil {
+		t.Fatal(err)
+	}
+	sess, err := store.GetSession(primary.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess.Metadata == nil || len(sess.Metadata.Merges) != 1 {
+		t.Errorf("Merges after re-summarization = %+v, want the merge note kept", sess.Metadata)
+	}
+}
+
+func TestSetSessionDelegation_SurvivesResummarization(t *testing.T) {
+	store := newTestArchiveStore(t)
+	sess, _ := store.StartSession("delegate-1")
+	binding := &ChannelBinding{Channel: "signal", ContactName: "Alice"}
+	if err := store.SetSessionMetadata(sess.ID, &SessionMetadata{ChannelBinding: binding}, "", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetSessionDelegation(sess.ID, &DelegationMetadata{
+		Task:      "check the garage door",
+		Model:     "big-model",
+		ToolCalls: []string{"ha_get_state"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetSessionMetadata(sess.ID, &SessionMetadata{OneLiner: "garage check"}, "Garage", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.GetSession(sess.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := got.Metadata.Delegation
+	if d == nil || d.Task != "check the garage door" || len(d.ToolCalls) != 1 {
+		t.Errorf("Delegation after re-summarization = %+v, want the record kept", d)
+	}
+	if got.Metadata.ChannelBinding == nil || got.Metadata.OneLiner != "garage check" {
+		t.Errorf("metadata = %+v, want binding and summary alongside the delegation", got.Metadata)
+	}
+
+	if err := store.SetSessionDelegation("missing", &DelegationMetadata{}); err == nil {
+		t.Error("SetSessionDelegation on a missing session succeeded, want error")
+	}
+}
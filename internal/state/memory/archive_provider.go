@@ -84,7 +84,7 @@ func (p *ArchiveContextProvider) TagContext(ctx context.Context, req agentctx.Co
 	)
 
 	start := time.Now()
-	bundle, err := p.searcher.Search(SearchOptions{
+	bundle, err := p.searcher.Search(ctx, SearchOptions{
 		Query: query,
 		Limit: p.maxResults,
 	})
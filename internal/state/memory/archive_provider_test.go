@@ -27,7 +27,7 @@ type mockArchiveSearcher struct {
 	callCount     int
 }
 
-func (m *mockArchiveSearcher) Search(opts SearchOptions) (*SearchBundle, error) {
+func (m *mockArchiveSearcher) Search(_ context.Context, opts SearchOptions) (*SearchBundle, error) {
 	m.callCount++
 	m.lastQuery = opts.Query
 	m.lastLimit = opts.Limit
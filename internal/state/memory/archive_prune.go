@@ -0,0 +1,296 @@
+package memory
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+)
+
+// PruneKeepTag is the session tag that exempts a session from pruning.
+const PruneKeepTag = "keep"
+
+// Prune reasons reported in [PrunedSession.Reason].
+const (
+	PruneReasonAge        = "age"
+	PruneReasonMessageCap = "message_cap"
+)
+
+// PrunePolicy describes which archived sessions [ArchiveStore.Prune]
+// removes. Both limits are optional; a zero policy prunes nothing.
+// Active sessions and sessions tagged [PruneKeepTag] are never pruned.
+type PrunePolicy struct {
+	// MaxAge deletes sessions that ended longer ago than this.
+	MaxAge time.Duration
+
+	// MaxMessages caps the total number of archived messages. When the
+	// archive holds more, whole sessions are dropped oldest first until
+	// it fits (or no prunable sessions remain).
+	MaxMessages int
+
+	// DryRun reports what would be deleted without deleting anything.
+	DryRun bool
+}
+
+// PrunedSession describes one session selected by [ArchiveStore.Prune].
+type PrunedSession struct {
+	ID             string    `json:"id"`
+	ConversationID string    `json:"conversation_id"`
+	StartedAt      time.Time `json:"started_at"`
+	EndedAt        time.Time `json:"ended_at"`
+	Reason         string    `json:"reason"`
+	Messages       int       `json:"messages"`
+	ToolCalls      int       `json:"tool_calls"`
+	Iterations     int       `json:"iterations"`
+}
+
+// PruneReport summarizes a [ArchiveStore.Prune] run. In dry-run mode it
+// describes what would have been deleted.
+type PruneReport struct {
+	DryRun     bool            `json:"dry_run"`
+	Sessions   []PrunedSession `json:"sessions"`
+	Messages   int             `json:"messages"`
+	ToolCalls  int             `json:"tool_calls"`
+	Iterations int             `json:"iterations"`
+}
+
+// Prune deletes archived sessions according to policy. Age-expired
+// sessions go first; if the archive still exceeds MaxMessages, the
+// oldest remaining sessions follow. Each session is deleted in its own
+// transaction together with its messages, message embeddings, tool
+// calls, iterations, and import metadata, so a failure part-way leaves
+// every session either fully present or fully gone. In legacy mode the
+// message FTS index is rebuilt once at the end; unified mode keeps it
+// in sync via triggers.
+//
+// The returned report lists the sessions deleted before any error.
+func (s *ArchiveStore) Prune(policy PrunePolicy) (*PruneReport, error) {
+	report := &PruneReport{DryRun: policy.DryRun, Sessions: []PrunedSession{}}
+	if policy.MaxAge <= 0 && policy.MaxMessages <= 0 {
+		return report, nil
+	}
+
+	candidates, err := s.pruneCandidates()
+	if err != nil {
+		return report, fmt.Errorf("prune: %w", err)
+	}
+	if err := s.countPruneRows(candidates); err != nil {
+		return report, fmt.Errorf("prune: %w", err)
+	}
+
+	var selected, remaining []PrunedSession
+	if policy.MaxAge > 0 {
+		cutoff := time.Now().Add(-policy.MaxAge)
+		for _, c := range candidates {
+			if c.EndedAt.Before(cutoff) {
+				c.Reason = PruneReasonAge
+				selected = append(selected, c)
+			} else {
+				remaining = append(remaining, c)
+			}
+		}
+	} else {
+		remaining = candidates
+	}
+
+	if policy.MaxMessages > 0 {
+		var total int
+		if err := s.msgDB().QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %s`, s.msgTableName)).Scan(&total); err != nil {
+			return report, fmt.Errorf("prune: count messages: %w", err)
+		}
+		for _, c := range selected {
+			total -= c.Messages
+		}
+		for _, c := range remaining {
+			if total <= policy.MaxMessages {
+				break
+			}
+			c.Reason = PruneReasonMessageCap
+			selected = append(selected, c)
+			total -= c.Messages
+		}
+	}
+
+	if policy.DryRun {
+		for _, c := range selected {
+			report.add(c)
+		}
+		return report, nil
+	}
+
+	for _, c := range selected {
+		if err := s.pruneSession(c.ID); err != nil {
+			return report, fmt.Errorf("prune session %s: %w", ShortID(c.ID), err)
+		}
+		report.add(c)
+	}
+
+	// Legacy mode writes FTS rows explicitly and has no delete triggers,
+	// so rebuild the index from what is left.
+	if len(report.Sessions) > 0 && s.messagesDB == nil && s.ftsEnabled {
+		ftsTable := s.msgFTSName
+		if _, err := s.db.Exec(fmt.Sprintf(`INSERT INTO %s(%s) VALUES('rebuild')`, ftsTable, ftsTable)); err != nil {
+			return report, fmt.Errorf("prune: rebuild FTS: %w", err)
+		}
+	}
+
+	if s.logger != nil && len(report.Sessions) > 0 {
+		s.logger.Info("archive pruned",
+			"sessions", len(report.Sessions),
+			"messages", report.Messages,
+			"tool_calls", report.ToolCalls,
+			"iterations", report.Iterations,
+		)
+	}
+	return report, nil
+}
+
+func (r *PruneReport) add(c PrunedSession) {
+	r.Sessions = append(r.Sessions, c)
+	r.Messages += c.Messages
+	r.ToolCalls += c.ToolCalls
+	r.Iterations += c.Iterations
+}
+
+// pruneCandidates returns every ended session not tagged
+// [PruneKeepTag], oldest first.
+func (s *ArchiveStore) pruneCandidates() ([]PrunedSession, error) {
+	rows, err := s.db.Query(`
+		SELECT id, conversation_id, started_at, ended_at, tags
+		FROM sessions
+		WHERE ended_at IS NOT NULL
+		ORDER BY started_at ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []PrunedSession
+	for rows.Next() {
+		var c PrunedSession
+		var startStr, endStr string
+		var tagsJSON sql.NullString
+		if err := rows.Scan(&c.ID, &c.ConversationID, &startStr, &endStr, &tagsJSON); err != nil {
+			return nil, fmt.Errorf("scan session: %w", err)
+		}
+		if tagsJSON.Valid {
+			var tags []string
+			if err := json.Unmarshal([]byte(tagsJSON.String), &tags); err != nil {
+				// A session whose tags cannot be read might carry the keep
+				// tag; leave it alone rather than guess.
+				continue
+			}
+			if slices.Contains(tags, PruneKeepTag) {
+				continue
+			}
+		}
+		if c.StartedAt, err = database.ParseTimestamp(startStr); err != nil {
+			return nil, fmt.Errorf("parse session started_at: %w", err)
+		}
+		if c.EndedAt, err = database.ParseTimestamp(endStr); err != nil {
+			return nil, fmt.Errorf("parse session ended_at: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// countPruneRows fills in the message, tool call, and iteration counts
+// of each candidate from the live tables rather than the cached
+// message_count, so the cap arithmetic matches what is actually stored.
+func (s *ArchiveStore) countPruneRows(candidates []PrunedSession) error {
+	counts := []struct {
+		db    *sql.DB
+		table string
+		set   func(*PrunedSession, int)
+	}{
+		{s.msgDB(), s.msgTableName, func(c *PrunedSession, n int) { c.Messages = n }},
+		{s.msgDB(), s.tcTableName, func(c *PrunedSession, n int) { c.ToolCalls = n }},
+		{s.db, "archive_iterations", func(c *PrunedSession, n int) { c.Iterations = n }},
+	}
+	for _, q := range counts {
+		byID, err := countBySession(q.db, q.table)
+		if err != nil {
+			return err
+		}
+		for i := range candidates {
+			q.set(&candidates[i], byID[candidates[i].ID])
+		}
+	}
+	return nil
+}
+
+func countBySession(db *sql.DB, table string) (map[string]int, error) {
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT session_id, COUNT(*) FROM %s
+		WHERE session_id IS NOT NULL
+		GROUP BY session_id
+	`, table))
+	if err != nil {
+		return nil, fmt.Errorf("count %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var id string
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, fmt.Errorf("scan %s count: %w", table, err)
+		}
+		counts[id] = n
+	}
+	return counts, rows.Err()
+}
+
+// pruneSession deletes one session and everything hanging off it.
+// Messages and tool calls share the session transaction unless they
+// live in a separate database, in which case they are committed first
+// so a crash can only leave an empty session row behind, never orphaned
+// messages.
+func (s *ArchiveStore) pruneSession(sessionID string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	msgTx := tx
+	if s.msgDB() != s.db {
+		msgTx, err = s.msgDB().Begin()
+		if err != nil {
+			return fmt.Errorf("begin message tx: %w", err)
+		}
+		defer func() { _ = msgTx.Rollback() }()
+	}
+
+	if err := deleteMessageEmbeddings(msgTx, s.msgTableName, "session_id = ?", sessionID); err != nil {
+		return err
+	}
+	for _, table := range []string{s.msgTableName, s.tcTableName} {
+		if _, err := msgTx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE session_id = ?`, table), sessionID); err != nil {
+			return fmt.Errorf("delete from %s: %w", table, err)
+		}
+	}
+	if msgTx != tx {
+		if err := msgTx.Commit(); err != nil {
+			return fmt.Errorf("commit message tx: %w", err)
+		}
+	}
+
+	stmts := []string{
+		`DELETE FROM archive_iterations WHERE session_id = ?`,
+		`DELETE FROM import_metadata WHERE archive_session_id = ?`,
+		`DELETE FROM sessions WHERE id = ?`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt, sessionID); err != nil {
+			return fmt.Errorf("delete session rows: %w", err)
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,241 @@
+package memory
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// seedPruneSession creates a session spanning start..end (end zero
+// leaves it active) with n archived messages, one tool call, and one
+// iteration, optionally tagged.
+func seedPruneSession(t *testing.T, store *ArchiveStore, start, end time.Time, n int, tags ...string) string {
+	t.Helper()
+	sess, err := store.StartSessionAt("conv-prune", start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs := make([]Message, n)
+	for i := range msgs {
+		msgs[i] = Message{
+			ID: fmt.Sprintf("%s-m%d", sess.ID, i), ConversationID: "conv-prune", SessionID: sess.ID,
+			Role: "user", Content: fmt.Sprintf("pruneword message %d", i),
+			Timestamp: start.Add(time.Duration(i) * time.Second), ArchiveReason: string(ArchiveReasonReset),
+		}
+	}
+	if err := store.ArchiveMessages(msgs); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ArchiveToolCalls([]ArchivedToolCall{{
+		ID: sess.ID + "-tc", ConversationID: "conv-prune", SessionID: sess.ID,
+		ToolName: "probe", Arguments: "{}", StartedAt: start,
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ArchiveIterations([]ArchivedIteration{{SessionID: sess.ID, Model: "m", StartedAt: start}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordImport("src-"+sess.ID, "test", sess.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) > 0 {
+		if err := store.SetSessionMetadata(sess.ID, &SessionMetadata{}, "", tags); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !end.IsZero() {
+		if err := store.EndSessionAt(sess.ID, "idle", end); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return sess.ID
+}
+
+func prunedIDs(r *PruneReport) []string {
+	ids := make([]string, len(r.Sessions))
+	for i, s := range r.Sessions {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+func TestPrune_MaxAge(t *testing.T) {
+	store := newTestArchiveStore(t)
+	now := time.Now()
+	old := seedPruneSession(t, store, now.Add(-100*24*time.Hour), now.Add(-99*24*time.Hour), 3)
+	kept := seedPruneSession(t, store, now.Add(-120*24*time.Hour), now.Add(-119*24*time.Hour), 2, "keep")
+	recent := seedPruneSession(t, store, now.Add(-2*24*time.Hour), now.Add(-24*time.Hour), 2)
+	active := seedPruneSession(t, store, now.Add(-200*24*time.Hour), time.Time{}, 2)
+
+	report, err := store.Prune(PrunePolicy{MaxAge: 30 * 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if ids := prunedIDs(report); len(ids) != 1 || ids[0] != old {
+		t.Fatalf("pruned %v, want only %s", ids, old)
+	}
+	got := report.Sessions[0]
+	if got.Reason != PruneReasonAge || got.Messages != 3 || got.ToolCalls != 1 || got.Iterations != 1 {
+		t.Errorf("pruned session = %+v, want age / 3 messages / 1 tool call / 1 iteration", got)
+	}
+
+	if sess, _ := store.GetSession(old); sess != nil {
+		t.Error("old session should be deleted")
+	}
+	for _, id := range []string{kept, recent, active} {
+		if sess, _ := store.GetSession(id); sess == nil {
+			t.Errorf("session %s should survive", ShortID(id))
+		}
+	}
+	if msgs, _ := store.GetSessionTranscript(old); len(msgs) != 0 {
+		t.Errorf("old session still has %d messages", len(msgs))
+	}
+	if calls, _ := store.GetSessionToolCalls(old); len(calls) != 0 {
+		t.Errorf("old session still has %d tool calls", len(calls))
+	}
+	if iters, _ := store.GetSessionIterations(old); len(iters) != 0 {
+		t.Errorf("old session still has %d iterations", len(iters))
+	}
+	if imported, _ := store.IsImported("src-"+old, "test"); imported {
+		t.Error("import metadata for the pruned session should be removed")
+	}
+
+	// The legacy FTS index is rebuilt, so search no longer sees the
+	// pruned session's messages.
+	results, err := store.Search(SearchOptions{Query: "pruneword", Limit: 50, NoContext: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.SessionID == old {
+			t.Fatalf("search still returns pruned message %s", r.Match.ID)
+		}
+	}
+	if len(results) != 6 {
+		t.Errorf("search results = %d, want the 6 surviving messages", len(results))
+	}
+}
+
+func TestPrune_MaxMessagesDropsOldestFirst(t *testing.T) {
+	store := newTestArchiveStore(t)
+	base := time.Now().Add(-10 * 24 * time.Hour)
+	first := seedPruneSession(t, store, base, base.Add(time.Hour), 4)
+	second := seedPruneSession(t, store, base.Add(24*time.Hour), base.Add(25*time.Hour), 4)
+	third := seedPruneSession(t, store, base.Add(48*time.Hour), base.Add(49*time.Hour), 4)
+
+	// 12 messages, cap 5: the first two sessions must go.
+	report, err := store.Prune(PrunePolicy{MaxMessages: 5})
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	ids := prunedIDs(report)
+	if len(ids) != 2 || ids[0] != first || ids[1] != second {
+		t.Fatalf("pruned %v, want [%s %s]", ids, ShortID(first), ShortID(second))
+	}
+	if report.Sessions[0].Reason != PruneReasonMessageCap || report.Messages != 8 {
+		t.Errorf("report = %+v, want message_cap pruning of 8 messages", report)
+	}
+	if sess, _ := store.GetSession(third); sess == nil {
+		t.Error("newest session should survive")
+	}
+}
+
+func TestPrune_DryRunDeletesNothing(t *testing.T) {
+	store := newTestArchiveStore(t)
+	now := time.Now()
+	old := seedPruneSession(t, store, now.Add(-100*24*time.Hour), now.Add(-99*24*time.Hour), 3)
+
+	report, err := store.Prune(PrunePolicy{MaxAge: 24 * time.Hour, DryRun: true})
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if !report.DryRun || len(report.Sessions) != 1 || report.Messages != 3 {
+		t.Fatalf("report = %+v, want a dry run listing one session with 3 messages", report)
+	}
+	if sess, _ := store.GetSession(old); sess == nil {
+		t.Error("dry run must not delete the session")
+	}
+	if msgs, _ := store.GetSessionTranscript(old); len(msgs) != 3 {
+		t.Errorf("dry run left %d messages, want 3", len(msgs))
+	}
+}
+
+func TestPrune_ZeroPolicyIsNoop(t *testing.T) {
+	store := newTestArchiveStore(t)
+	now := time.Now()
+	seedPruneSession(t, store, now.Add(-1000*24*time.Hour), now.Add(-999*24*time.Hour), 1)
+
+	report, err := store.Prune(PrunePolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Sessions) != 0 {
+		t.Errorf("zero policy pruned %d sessions", len(report.Sessions))
+	}
+}
+
+func TestPrune_Unified(t *testing.T) {
+	workingStore, err := NewSQLiteStore(t.TempDir()+"/working.db", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer workingStore.Close()
+	store, err := NewArchiveStoreFromDB(workingStore.DB(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := workingStore.DB()
+
+	now := time.Now()
+	sessions := map[string]time.Time{
+		"old":    now.Add(-90 * 24 * time.Hour),
+		"recent": now.Add(-time.Hour),
+	}
+	ids := map[string]string{}
+	for name, start := range sessions {
+		sess, err := store.StartSessionAt("conv-prune", start)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[name] = sess.ID
+		if _, err := db.Exec(`
+			INSERT INTO messages (id, conversation_id, session_id, role, content, timestamp, status)
+			VALUES (?, 'conv-prune', ?, 'user', ?, ?, 'archived')
+		`, name+"-m", sess.ID, "pruneword "+name, start.UTC().Format(time.RFC3339Nano)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(`
+			INSERT INTO tool_calls (id, conversation_id, session_id, tool_name, arguments, started_at, status)
+			VALUES (?, 'conv-prune', ?, 'probe', '{}', ?, 'archived')
+		`, name+"-tc", sess.ID, start.UTC().Format(time.RFC3339Nano)); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.EndSessionAt(sess.ID, "idle", start.Add(time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := store.Prune(PrunePolicy{MaxAge: 30 * 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if got := prunedIDs(report); len(got) != 1 || got[0] != ids["old"] {
+		t.Fatalf("pruned %v, want the old session", got)
+	}
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM messages WHERE session_id = ?`, ids["old"]).Scan(&n); err != nil || n != 0 {
+		t.Errorf("old session messages = %d (err %v), want 0", n, err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM tool_calls WHERE session_id = ?`, ids["old"]).Scan(&n); err != nil || n != 0 {
+		t.Errorf("old session tool calls = %d (err %v), want 0", n, err)
+	}
+	if store.FTSEnabled() {
+		results, err := store.Search(SearchOptions{Query: "pruneword", Limit: 10, NoContext: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].Match.ID != "recent-m" {
+			t.Errorf("search after prune = %+v, want only the recent message", results)
+		}
+	}
+}
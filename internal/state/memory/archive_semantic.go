@@ -0,0 +1,424 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/state/knowledge"
+)
+
+// SearchMode selects how raw archived messages are ranked.
+type SearchMode string
+
+const (
+	// SearchModeKeyword ranks by FTS5 BM25 relevance. The default.
+	SearchModeKeyword SearchMode = "keyword"
+
+	// SearchModeSemantic ranks by cosine similarity between the query
+	// embedding and each message's stored embedding, so paraphrases
+	// match without sharing any words.
+	SearchModeSemantic SearchMode = "semantic"
+
+	// SearchModeHybrid runs both rankings and fuses them by reciprocal
+	// rank, so a message near the top of either list surfaces.
+	SearchModeHybrid SearchMode = "hybrid"
+)
+
+// ParseSearchMode validates a mode name. The empty string is keyword.
+func ParseSearchMode(s string) (SearchMode, error) {
+	switch mode := SearchMode(s); mode {
+	case "":
+		return SearchModeKeyword, nil
+	case SearchModeKeyword, SearchModeSemantic, SearchModeHybrid:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown search mode %q (want keyword, semantic, or hybrid)", s)
+	}
+}
+
+// ErrSemanticSearchUnavailable is returned by semantic and hybrid
+// searches when no embedding client has been configured.
+var ErrSemanticSearchUnavailable = errors.New("semantic search is not enabled")
+
+// messageEmbeddingsTable holds one vector per archived message. It
+// lives beside the message table (in [ArchiveStore.msgDB]) so searches
+// and the backfill can join against messages directly. Every path that
+// deletes messages deletes their vectors with them
+// ([deleteMessageEmbeddings]).
+const messageEmbeddingsTable = "message_embeddings"
+
+// messageEmbedFailuresTable records messages the embedding model
+// rejected, so [ArchiveStore.EmbedPending] passes over them until
+// embedRetryAfter has elapsed instead of retrying them every pass.
+const messageEmbedFailuresTable = "message_embedding_failures"
+
+const (
+	// maxEmbedChars bounds the message text sent to the embedding
+	// model. Local embedding models have small context windows, and
+	// the opening of a long message carries most of its topic anyway.
+	maxEmbedChars = 2000
+
+	// hybridCandidateFactor is how many candidates per requested
+	// result each ranking contributes to a hybrid fusion.
+	hybridCandidateFactor = 2
+
+	// hybridRankConstant is the k in reciprocal rank fusion,
+	// 1/(k+rank). The conventional 60 keeps the top few ranks of each
+	// list from overwhelming agreement between the lists.
+	hybridRankConstant = 60
+
+	// embedRetryAfter is how long a message that failed to embed is
+	// skipped before the backfill tries it again. Long enough that a
+	// message the model always rejects costs one call a day; short
+	// enough that an outage does not leave a gap in the index.
+	embedRetryAfter = 24 * time.Hour
+)
+
+func (s *ArchiveStore) migrateEmbeddings() error {
+	_, err := s.msgDB().Exec(`
+		CREATE TABLE IF NOT EXISTS ` + messageEmbeddingsTable + ` (
+			message_id TEXT PRIMARY KEY,
+			model TEXT NOT NULL,
+			embedding BLOB NOT NULL,
+			embedded_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", messageEmbeddingsTable, err)
+	}
+	_, err = s.msgDB().Exec(`
+		CREATE TABLE IF NOT EXISTS ` + messageEmbedFailuresTable + ` (
+			message_id TEXT PRIMARY KEY,
+			model TEXT NOT NULL,
+			error TEXT NOT NULL,
+			failed_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", messageEmbedFailuresTable, err)
+	}
+	return nil
+}
+
+// sqlExecer is the subset of *sql.DB and *sql.Tx that
+// [deleteMessageEmbeddings] needs.
+type sqlExecer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// deleteMessageEmbeddings removes the stored vectors and embed
+// failures of the messages in msgTable matching where. Call it before
+// deleting those messages, in the same transaction. It is a no-op when
+// the embedding tables do not exist, which is the case for a
+// conversation store that no archive has been opened against.
+func deleteMessageEmbeddings(db sqlExecer, msgTable, where string, args ...any) error {
+	for _, table := range []string{messageEmbeddingsTable, messageEmbedFailuresTable} {
+		var exists int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&exists); err != nil {
+			return fmt.Errorf("check %s: %w", table, err)
+		}
+		if exists == 0 {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf(`
+			DELETE FROM %s WHERE message_id IN (SELECT id FROM %s WHERE %s)
+		`, table, msgTable, where), args...); err != nil {
+			return fmt.Errorf("delete from %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// SetEmbeddingClient enables semantic search using client. model names
+// the embedding model; stored vectors are keyed by it, so switching
+// models re-embeds the archive instead of comparing vectors from
+// different spaces. Existing messages are embedded by
+// [ArchiveStore.EmbedPending]. Call before the store is shared.
+func (s *ArchiveStore) SetEmbeddingClient(client knowledge.EmbeddingClient, model string) {
+	s.embedder = client
+	s.embeddingModel = model
+}
+
+// SemanticEnabled reports whether an embedding client is configured.
+func (s *ArchiveStore) SemanticEnabled() bool {
+	return s.embedder != nil
+}
+
+// SemanticSearch embeds query and returns the limit archived messages
+// most similar to it, with the same context windows as [Search].
+// Messages not yet embedded by [ArchiveStore.EmbedPending] cannot
+// match.
+func (s *ArchiveStore) SemanticSearch(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	return s.searchMessages(ctx, SearchOptions{Query: query, Limit: limit, Mode: SearchModeSemantic})
+}
+
+// searchMessages runs the raw-message search in opts.Mode.
+func (s *ArchiveStore) searchMessages(ctx context.Context, opts SearchOptions) ([]SearchResult, error) {
+	if strings.TrimSpace(opts.Query) == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	opts = s.normalizeSearchOptions(opts)
+
+	var matches []matchWithHighlight
+	var err error
+	switch opts.Mode {
+	case "", SearchModeKeyword:
+		matches, err = s.keywordMatches(opts)
+	case SearchModeSemantic:
+		matches, err = s.semanticMatches(ctx, opts)
+	case SearchModeHybrid:
+		candidates := opts
+		candidates.Limit = opts.Limit * hybridCandidateFactor
+		var keyword, semantic []matchWithHighlight
+		if keyword, err = s.keywordMatches(candidates); err != nil {
+			return nil, err
+		}
+		if semantic, err = s.semanticMatches(ctx, candidates); err != nil {
+			return nil, err
+		}
+		matches = fuseRankings(opts.Limit, keyword, semantic)
+	default:
+		return nil, fmt.Errorf("unknown search mode %q", opts.Mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.expandMatches(matches, opts), nil
+}
+
+// semanticMatches ranks embedded messages by cosine similarity to the
+// query under the same filters as the keyword path. Vectors are
+// scored in Go, mirroring the fact store's semantic search; archives
+// are small enough that a full scan beats maintaining an ANN index.
+func (s *ArchiveStore) semanticMatches(ctx context.Context, opts SearchOptions) ([]matchWithHighlight, error) {
+	if s.embedder == nil {
+		return nil, ErrSemanticSearchUnavailable
+	}
+	queryVec, err := s.embedder.Generate(ctx, opts.Query)
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+
+	conditions, args := messageFilterConditions(opts)
+	conditions = append([]string{"e.model = ?"}, conditions...)
+	args = append([]any{s.embeddingModel}, args...)
+	rows, err := s.msgDB().QueryContext(ctx, fmt.Sprintf(`
+		SELECT e.message_id, e.embedding
+		FROM %s e
+		JOIN %s am ON am.id = e.message_id
+		WHERE %s
+	`, messageEmbeddingsTable, s.msgTableName, strings.Join(conditions, " AND ")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("semantic search: %w", err)
+	}
+
+	type scored struct {
+		id    string
+		score float64
+	}
+	var ranked []scored
+	for rows.Next() {
+		var id string
+		var blob []byte
+		if err := rows.Scan(&id, &blob); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan embedding: %w", err)
+		}
+		sim := knowledge.CosineSimilarity(queryVec, knowledge.DecodeEmbedding(blob))
+		ranked = append(ranked, scored{id: id, score: float64(sim)})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate embeddings: %w", err)
+	}
+
+	slices.SortStableFunc(ranked, func(a, b scored) int { return cmp.Compare(b.score, a.score) })
+	keep := opts.Limit
+	if opts.RecencyHalfLife > 0 {
+		keep = opts.Limit * recencyCandidateFactor
+	}
+	ranked = ranked[:min(keep, len(ranked))]
+	if len(ranked) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(ranked))
+	for i, r := range ranked {
+		ids[i] = r.id
+	}
+	byID, err := s.matchesByID(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]matchWithHighlight, 0, len(ranked))
+	for _, r := range ranked {
+		m, ok := byID[r.id]
+		if !ok {
+			continue
+		}
+		m.score = r.score
+		m.matchType = string(SearchModeSemantic)
+		matches = append(matches, m)
+	}
+
+	if opts.RecencyHalfLife > 0 {
+		matches = rankByRecency(matches, opts.RecencyHalfLife, time.Now(), opts.Limit)
+	}
+	return matches, nil
+}
+
+// matchesByID loads the given messages in the shape the keyword path
+// produces, keyed by message ID.
+func (s *ArchiveStore) matchesByID(ctx context.Context, ids []string) (map[string]matchWithHighlight, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := s.msgDB().QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s, '' AS highlight, 0.0 AS score
+		FROM %s am
+		WHERE am.id IN (%s)
+	`, ftsMatchColumns, s.msgTableName, placeholders), args...)
+	if err != nil {
+		return nil, fmt.Errorf("load semantic matches: %w", err)
+	}
+	defer rows.Close()
+
+	matches, err := scanFTSMatches(rows)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]matchWithHighlight, len(matches))
+	for _, m := range matches {
+		byID[m.msg.ID] = m
+	}
+	return byID, nil
+}
+
+// fuseRankings merges ranked lists by reciprocal rank fusion: each
+// message scores the sum of 1/(k+rank) over the lists it appears in,
+// so agreement between rankings lifts a message above one that tops
+// only a single list. Raw scores are ignored — BM25 and cosine
+// similarity are not on comparable scales. A message keeps the match
+// (highlight, match type) from the first list that produced it, and
+// its score becomes the fused score.
+func fuseRankings(limit int, lists ...[]matchWithHighlight) []matchWithHighlight {
+	index := make(map[string]int)
+	var fused []matchWithHighlight
+	var scores []float64
+	for _, list := range lists {
+		for rank, m := range list {
+			contribution := 1 / float64(hybridRankConstant+rank+1)
+			if i, ok := index[m.msg.ID]; ok {
+				scores[i] += contribution
+				continue
+			}
+			index[m.msg.ID] = len(fused)
+			fused = append(fused, m)
+			scores = append(scores, contribution)
+		}
+	}
+	for i := range fused {
+		fused[i].score = scores[i]
+	}
+	slices.SortStableFunc(fused, func(a, b matchWithHighlight) int { return cmp.Compare(b.score, a.score) })
+	return fused[:min(limit, len(fused))]
+}
+
+// EmbedPending embeds up to limit archived messages that have no
+// vector for the current model yet, newest first, and returns how many
+// it stored. Only user and assistant messages with text are embedded.
+// It is the lazy backfill behind semantic search: a background worker
+// calls it periodically, so enabling the feature never requires a full
+// reindex upfront and freshly archived messages are picked up on the
+// next pass.
+//
+// A message that fails to embed is recorded and skipped for
+// embedRetryAfter, and the pass moves on to the rest of the batch, so
+// one message the model rejects cannot stall the backfill. The
+// returned error joins the per-message failures.
+func (s *ArchiveStore) EmbedPending(ctx context.Context, limit int) (int, error) {
+	if s.embedder == nil {
+		return 0, ErrSemanticSearchUnavailable
+	}
+
+	retryBefore := time.Now().Add(-embedRetryAfter).UTC().Format(time.RFC3339Nano)
+	rows, err := s.msgDB().QueryContext(ctx, fmt.Sprintf(`
+		SELECT am.id, am.content
+		FROM %s am
+		WHERE am.role IN ('user', 'assistant')
+		  AND TRIM(COALESCE(am.content, '')) != ''
+		  AND am.content NOT LIKE 'Anticipation matched:%%'
+		  AND NOT EXISTS (
+			SELECT 1 FROM %s e WHERE e.message_id = am.id AND e.model = ?
+		  )
+		  AND NOT EXISTS (
+			SELECT 1 FROM %s f WHERE f.message_id = am.id AND f.model = ? AND f.failed_at > ?
+		  )
+		ORDER BY am.timestamp DESC
+		LIMIT ?
+	`, s.msgTableName, messageEmbeddingsTable, messageEmbedFailuresTable),
+		s.embeddingModel, s.embeddingModel, retryBefore, limit)
+	if err != nil {
+		return 0, fmt.Errorf("query unembedded messages: %w", err)
+	}
+	type pending struct{ id, content string }
+	var batch []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.content); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan message: %w", err)
+		}
+		batch = append(batch, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate messages: %w", err)
+	}
+
+	stored := 0
+	var failures []error
+	for _, p := range batch {
+		if err := ctx.Err(); err != nil {
+			return stored, err
+		}
+		text := p.content
+		if len(text) > maxEmbedChars {
+			text = strings.ToValidUTF8(text[:maxEmbedChars], "")
+		}
+		vec, err := s.embedder.Generate(ctx, text)
+		if err != nil {
+			if ctx.Err() != nil {
+				return stored, ctx.Err()
+			}
+			failures = append(failures, fmt.Errorf("embed message %s: %w", ShortID(p.id), err))
+			if _, err := s.msgDB().ExecContext(ctx, `
+				INSERT OR REPLACE INTO `+messageEmbedFailuresTable+` (message_id, model, error, failed_at)
+				VALUES (?, ?, ?, ?)
+			`, p.id, s.embeddingModel, err.Error(), time.Now().UTC().Format(time.RFC3339Nano)); err != nil {
+				return stored, fmt.Errorf("record embed failure for %s: %w", ShortID(p.id), err)
+			}
+			continue
+		}
+		if _, err := s.msgDB().ExecContext(ctx, `
+			INSERT OR REPLACE INTO `+messageEmbeddingsTable+` (message_id, model, embedding, embedded_at)
+			VALUES (?, ?, ?, ?)
+		`, p.id, s.embeddingModel, knowledge.EncodeEmbedding(vec), time.Now().UTC().Format(time.RFC3339Nano)); err != nil {
+			return stored, fmt.Errorf("store embedding for %s: %w", ShortID(p.id), err)
+		}
+		if _, err := s.msgDB().ExecContext(ctx, `DELETE FROM `+messageEmbedFailuresTable+` WHERE message_id = ?`, p.id); err != nil {
+			return stored, fmt.Errorf("clear embed failure for %s: %w", ShortID(p.id), err)
+		}
+		stored++
+	}
+	return stored, errors.Join(failures...)
+}
@@ -0,0 +1,300 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// topicEmbedder is a deterministic stand-in for an embedding model:
+// each vector dimension counts words from one topic, so texts about
+// the same topic land close together without sharing exact words.
+type topicEmbedder struct {
+	calls int
+}
+
+var embedTopics = [][]string{
+	{"pool", "swim", "swimming", "heater", "lukewarm", "chlorine"},
+	{"garage", "car", "driveway", "parked", "door"},
+	{"coffee", "espresso", "grinder", "beans"},
+}
+
+func (e *topicEmbedder) Generate(_ context.Context, text string) ([]float32, error) {
+	e.calls++
+	vec := make([]float32, len(embedTopics)+1)
+	vec[len(embedTopics)] = 0.1 // keep every vector non-zero
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		for dim, topic := range embedTopics {
+			for _, w := range topic {
+				if strings.Trim(word, ".,?!") == w {
+					vec[dim]++
+				}
+			}
+		}
+	}
+	return vec, nil
+}
+
+func archiveSemanticMessages(t *testing.T, store *ArchiveStore, base time.Time) {
+	t.Helper()
+	msgs := []Message{
+		{ID: "pool", ConversationID: "conv-a", SessionID: "s1", Role: "user", Content: "The pool heater quit and the water is lukewarm", Timestamp: base},
+		{ID: "garage", ConversationID: "conv-a", SessionID: "s1", Role: "assistant", Content: "I closed the garage door after you parked the car", Timestamp: base.Add(time.Minute)},
+		{ID: "coffee", ConversationID: "conv-b", SessionID: "s2", Role: "user", Content: "Order more espresso beans for the grinder", Timestamp: base.Add(2 * time.Minute)},
+		{ID: "tool", ConversationID: "conv-b", SessionID: "s2", Role: "tool", Content: "pool status: ok", Timestamp: base.Add(3 * time.Minute)},
+	}
+	for i := range msgs {
+		msgs[i].ArchiveReason = string(ArchiveReasonReset)
+	}
+	if err := store.ArchiveMessages(msgs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSemanticSearch_RanksByMeaning(t *testing.T) {
+	store := newTestArchiveStore(t)
+	archiveSemanticMessages(t, store, time.Now().Add(-time.Hour))
+	store.SetEmbeddingClient(&topicEmbedder{}, "topic-v1")
+
+	n, err := store.EmbedPending(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("EmbedPending: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("embedded %d messages, want 3 (tool messages are skipped)", n)
+	}
+
+	// No query word appears in the pool message, so keyword search
+	// cannot find it; semantic search must.
+	query := "swimming chlorine"
+	if kw, err := store.Search(SearchOptions{Query: query, Limit: 5, NoContext: true}); err != nil || len(kw) != 0 {
+		t.Fatalf("keyword search = %d results (err %v), want none for the paraphrase", len(kw), err)
+	}
+	results, err := store.SemanticSearch(context.Background(), query, 2)
+	if err != nil {
+		t.Fatalf("SemanticSearch: %v", err)
+	}
+	if len(results) != 2 || results[0].Match.ID != "pool" {
+		t.Fatalf("results = %+v, want pool first of 2", results)
+	}
+	if results[0].MatchType != "semantic" || results[0].Score <= results[1].Score {
+		t.Errorf("top hit match_type=%q score=%v, want semantic and above %v",
+			results[0].MatchType, results[0].Score, results[1].Score)
+	}
+}
+
+func TestSemanticSearch_HonorsConversationScope(t *testing.T) {
+	store := newTestArchiveStore(t)
+	archiveSemanticMessages(t, store, time.Now().Add(-time.Hour))
+	store.SetEmbeddingClient(&topicEmbedder{}, "topic-v1")
+	if _, err := store.EmbedPending(context.Background(), 100); err != nil {
+		t.Fatal(err)
+	}
+
+	searcher := NewMemorySearch(store, nil, nil)
+	bundle, err := searcher.Search(context.Background(), SearchOptions{
+		Query: "swim", ConversationID: "conv-b", Limit: 5, NoContext: true, Mode: SearchModeSemantic,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range bundle.Messages {
+		if r.Match.ConversationID != "conv-b" {
+			t.Errorf("hit %s from %s leaked past the conversation scope", r.Match.ID, r.Match.ConversationID)
+		}
+	}
+	if bundle.TotalMessages != 0 {
+		t.Errorf("TotalMessages = %d, want 0 (no match count in semantic mode)", bundle.TotalMessages)
+	}
+}
+
+func TestSemanticSearch_Hybrid(t *testing.T) {
+	store := newTestArchiveStore(t)
+	archiveSemanticMessages(t, store, time.Now().Add(-time.Hour))
+	store.SetEmbeddingClient(&topicEmbedder{}, "topic-v1")
+	if _, err := store.EmbedPending(context.Background(), 100); err != nil {
+		t.Fatal(err)
+	}
+
+	// "garage" hits the garage message by keyword; "swimming" pulls
+	// the pool message in semantically. Hybrid surfaces both.
+	bundle, err := NewMemorySearch(store, nil, nil).Search(context.Background(), SearchOptions{
+		Query: "garage swimming", Limit: 2, NoContext: true, Mode: SearchModeHybrid,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]bool{}
+	for _, r := range bundle.Messages {
+		got[r.Match.ID] = true
+	}
+	if len(bundle.Messages) != 2 || !got["garage"] || !got["pool"] {
+		t.Errorf("hybrid hits = %v, want garage and pool", got)
+	}
+}
+
+func TestSemanticSearch_Unavailable(t *testing.T) {
+	store := newTestArchiveStore(t)
+	archiveSemanticMessages(t, store, time.Now())
+
+	if _, err := store.SemanticSearch(context.Background(), "pool", 5); !errors.Is(err, ErrSemanticSearchUnavailable) {
+		t.Errorf("SemanticSearch error = %v, want ErrSemanticSearchUnavailable", err)
+	}
+	_, err := NewMemorySearch(store, nil, nil).Search(context.Background(), SearchOptions{Query: "pool", Mode: SearchModeHybrid})
+	if !errors.Is(err, ErrSemanticSearchUnavailable) {
+		t.Errorf("hybrid error = %v, want ErrSemanticSearchUnavailable", err)
+	}
+	if _, err := store.EmbedPending(context.Background(), 10); !errors.Is(err, ErrSemanticSearchUnavailable) {
+		t.Errorf("EmbedPending error = %v, want ErrSemanticSearchUnavailable", err)
+	}
+}
+
+func TestEmbedPending_BackfillsNewestFirstAndReembedsOnModelChange(t *testing.T) {
+	store := newTestArchiveStore(t)
+	archiveSemanticMessages(t, store, time.Now().Add(-time.Hour))
+	embedder := &topicEmbedder{}
+	store.SetEmbeddingClient(embedder, "topic-v1")
+	ctx := context.Background()
+
+	if n, err := store.EmbedPending(ctx, 1); err != nil || n != 1 {
+		t.Fatalf("first pass embedded %d (err %v), want 1", n, err)
+	}
+	// The newest eligible message goes first.
+	if results, _ := store.SemanticSearch(ctx, "espresso", 5); len(results) != 1 || results[0].Match.ID != "coffee" {
+		t.Errorf("after one pass, searchable = %+v, want only coffee", results)
+	}
+	if n, _ := store.EmbedPending(ctx, 100); n != 2 {
+		t.Errorf("second pass embedded %d, want the remaining 2", n)
+	}
+	if n, _ := store.EmbedPending(ctx, 100); n != 0 {
+		t.Errorf("third pass embedded %d, want 0 once caught up", n)
+	}
+
+	store.SetEmbeddingClient(embedder, "topic-v2")
+	if n, _ := store.EmbedPending(ctx, 100); n != 3 {
+		t.Errorf("after a model change embedded %d, want all 3 again", n)
+	}
+}
+
+// pickyEmbedder fails on any text containing reject and otherwise
+// defers to topicEmbedder.
+type pickyEmbedder struct {
+	topicEmbedder
+	reject string
+}
+
+func (e *pickyEmbedder) Generate(ctx context.Context, text string) ([]float32, error) {
+	if strings.Contains(text, e.reject) {
+		e.calls++
+		return nil, errors.New("input rejected")
+	}
+	return e.topicEmbedder.Generate(ctx, text)
+}
+
+func TestEmbedPending_SkipsFailedMessages(t *testing.T) {
+	store := newTestArchiveStore(t)
+	archiveSemanticMessages(t, store, time.Now().Add(-time.Hour))
+	embedder := &pickyEmbedder{reject: "espresso"}
+	store.SetEmbeddingClient(embedder, "topic-v1")
+	ctx := context.Background()
+
+	// The newest message fails; the rest of the batch still embeds.
+	n, err := store.EmbedPending(ctx, 100)
+	if n != 2 {
+		t.Errorf("first pass embedded %d, want 2", n)
+	}
+	if err == nil || !strings.Contains(err.Error(), "input rejected") {
+		t.Errorf("first pass error = %v, want the rejection", err)
+	}
+
+	// The failed message is not retried on the next pass.
+	calls := embedder.calls
+	if n, err := store.EmbedPending(ctx, 1); n != 0 || err != nil {
+		t.Errorf("second pass = %d, %v; want 0, nil", n, err)
+	}
+	if embedder.calls != calls {
+		t.Errorf("second pass called the embedder %d times, want 0", embedder.calls-calls)
+	}
+}
+
+func TestClear_DeletesMessageEmbeddings(t *testing.T) {
+	workingStore, err := NewSQLiteStore(t.TempDir()+"/working.db", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer workingStore.Close()
+	store, err := NewArchiveStoreFromDB(workingStore.DB(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Now().Add(-time.Hour)
+	for i, m := range []struct{ id, conv, content string }{
+		{"pool", "conv-a", "The pool heater quit"},
+		{"garage", "conv-a", "I closed the garage door"},
+		{"coffee", "conv-b", "Order more espresso beans"},
+	} {
+		if _, err := workingStore.DB().Exec(`
+			INSERT INTO messages (id, conversation_id, role, content, timestamp, status)
+			VALUES (?, ?, 'user', ?, ?, 'archived')
+		`, m.id, m.conv, m.content, base.Add(time.Duration(i)*time.Minute).UTC().Format(time.RFC3339Nano)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.SetEmbeddingClient(&topicEmbedder{}, "topic-v1")
+	if n, err := store.EmbedPending(context.Background(), 100); err != nil || n != 3 {
+		t.Fatalf("embedded %d (err %v), want 3", n, err)
+	}
+
+	if err := workingStore.Clear("conv-a"); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	rows, err := workingStore.DB().Query(`SELECT message_id FROM ` + messageEmbeddingsTable + ` ORDER BY message_id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) != 1 || ids[0] != "coffee" {
+		t.Errorf("embeddings after Clear(conv-a) = %v, want only coffee", ids)
+	}
+}
+
+func TestFuseRankings(t *testing.T) {
+	m := func(id, matchType string) matchWithHighlight {
+		return matchWithHighlight{msg: Message{ID: id}, matchType: matchType, score: 100}
+	}
+	keyword := []matchWithHighlight{m("a", "phrase"), m("b", "terms")}
+	semantic := []matchWithHighlight{m("b", "semantic"), m("c", "semantic")}
+
+	fused := fuseRankings(2, keyword, semantic)
+	if len(fused) != 2 || fused[0].msg.ID != "b" || fused[1].msg.ID != "a" {
+		t.Fatalf("fused = %+v, want b (in both lists) then a", fused)
+	}
+	if fused[0].matchType != "terms" {
+		t.Errorf("b match type = %q, want the keyword list's %q", fused[0].matchType, "terms")
+	}
+	want := 1.0/62 + 1.0/61
+	if diff := fused[0].score - want; diff > 1e-12 || diff < -1e-12 {
+		t.Errorf("b fused score = %v, want %v", fused[0].score, want)
+	}
+}
+
+func TestParseSearchMode(t *testing.T) {
+	for in, want := range map[string]SearchMode{"": SearchModeKeyword, "keyword": SearchModeKeyword, "semantic": SearchModeSemantic, "hybrid": SearchModeHybrid} {
+		if got, err := ParseSearchMode(in); err != nil || got != want {
+			t.Errorf("ParseSearchMode(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseSearchMode("fuzzy"); err == nil {
+		t.Error("ParseSearchMode(fuzzy) should fail")
+	}
+}
@@ -2,6 +2,8 @@ package memory
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -369,7 +371,7 @@ func TestExportSessionMarkdown(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	md, err := store.ExportSessionMarkdown(sess.ID)
+	md, err := store.ExportSessionMarkdown(sess.ID, MarkdownExportOptions{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -384,6 +386,145 @@ func TestExportSessionMarkdown(t *testing.T) {
 	}
 }
 
+func TestExportSessionMarkdown_OmitToolCalls(t *testing.T) {
+	store := newTestArchiveStore(t)
+
+	sess, _ := store.StartSession("conv-1")
+	base := time.Date(2026, 2, 12, 10, 0, 0, 0, time.UTC)
+	msgs := []Message{
+		{ID: "m1", ConversationID: "conv-1", SessionID: sess.ID, Role: "user",
+			Content: "is the porch light on?", Timestamp: base, ArchiveReason: "manual"},
+		{ID: "m2", ConversationID: "conv-1", SessionID: sess.ID, Role: "assistant",
+			ToolCalls: `[{"id":"call-1"}]`, Timestamp: base.Add(time.Second), ArchiveReason: "manual"},
+		{ID: "m3", ConversationID: "conv-1", SessionID: sess.ID, Role: "tool", ToolCallID: "call-1",
+			Content: `{"state":"on"}`, Timestamp: base.Add(2 * time.Second), ArchiveReason: "manual"},
+		{ID: "m4", ConversationID: "conv-1", SessionID: sess.ID, Role: "assistant",
+			Content: "Yes, it is on.", Timestamp: base.Add(3 * time.Second), ArchiveReason: "manual"},
+	}
+	if err := store.ArchiveMessages(msgs); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ArchiveToolCalls([]ArchivedToolCall{{
+		ID: "call-1", ConversationID: "conv-1", SessionID: sess.ID, ToolName: "get_state",
+		Arguments: `{"entity_id":"light.porch"}`, StartedAt: base.Add(time.Second),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	full, err := store.ExportSessionMarkdown(sess.ID, MarkdownExportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !containsAll(full, "get_state", "light.porch", "**Tool Calls:** 1") {
+		t.Errorf("full export missing tool call record:\n%s", full)
+	}
+
+	clean, err := store.ExportSessionMarkdown(sess.ID, MarkdownExportOptions{OmitToolCalls: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !containsAll(clean, "porch light", "Yes, it is on.", "**Messages:** 2") {
+		t.Errorf("clean export missing conversation:\n%s", clean)
+	}
+	for _, unwanted := range []string{"get_state", "light.porch", "Tool Calls", "🔧"} {
+		if strings.Contains(clean, unwanted) {
+			t.Errorf("clean export contains %q:\n%s", unwanted, clean)
+		}
+	}
+	if got := strings.Count(clean, "🤖"); got != 1 {
+		t.Errorf("clean export has %d assistant turns, want 1", got)
+	}
+}
+
+func TestExportSessionMarkdown_UnknownSession(t *testing.T) {
+	store := newTestArchiveStore(t)
+
+	if _, err := store.ExportSessionMarkdown("no-such-session", MarkdownExportOptions{}); err == nil {
+		t.Fatal("expected error for unknown session")
+	}
+}
+
+func TestExportConversationMarkdown(t *testing.T) {
+	store := newTestArchiveStore(t)
+
+	base := time.Date(2026, 2, 12, 10, 0, 0, 0, time.UTC)
+	// Start the later session first so insertion order cannot pass for
+	// chronological order.
+	second, _ := store.StartSessionAt("conv-1", base.Add(time.Hour))
+	first, _ := store.StartSessionAt("conv-1", base)
+	other, _ := store.StartSessionAt("conv-2", base)
+	msgs := []Message{
+		{ID: "m1", ConversationID: "conv-1", SessionID: first.ID, Role: "user",
+			Content: "first session", Timestamp: base, ArchiveReason: "manual"},
+		{ID: "m2", ConversationID: "conv-1", SessionID: second.ID, Role: "user",
+			Content: "second session", Timestamp: base.Add(time.Hour), ArchiveReason: "manual"},
+		{ID: "m3", ConversationID: "conv-2", SessionID: other.ID, Role: "user",
+			Content: "other conversation", Timestamp: base, ArchiveReason: "manual"},
+	}
+	if err := store.ArchiveMessages(msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	md, err := store.ExportConversationMarkdown("conv-1", MarkdownExportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	i, j := strings.Index(md, "first session"), strings.Index(md, "second session")
+	if i < 0 || j < 0 || i > j {
+		t.Errorf("sessions missing or out of order:\n%s", md)
+	}
+	if strings.Contains(md, "other conversation") {
+		t.Errorf("export leaked another conversation:\n%s", md)
+	}
+
+	if _, err := store.ExportConversationMarkdown("conv-none", MarkdownExportOptions{}); err == nil {
+		t.Error("expected error for conversation without sessions")
+	}
+}
+
+func TestResolveSessionID(t *testing.T) {
+	store := newTestArchiveStore(t)
+
+	a, _ := store.StartSession("conv-1")
+	b, _ := store.StartSession("conv-1")
+	// Session IDs are UUIDv7, so two created back to back share their
+	// leading timestamp characters but differ in the random tail.
+	shared := 0
+	for shared < len(a.ID) && a.ID[shared] == b.ID[shared] {
+		shared++
+	}
+
+	tests := []struct {
+		name      string
+		prefix    string
+		want      string
+		wantError string
+	}{
+		{name: "full id", prefix: a.ID, want: a.ID},
+		{name: "unique prefix", prefix: b.ID[:shared+1], want: b.ID},
+		{name: "ambiguous", prefix: a.ID[:shared], wantError: "ambiguous prefix"},
+		{name: "not found", prefix: "zzzzzzzz", wantError: "no session found"},
+		{name: "empty", prefix: "", wantError: "required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.ResolveSessionID(tt.prefix)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveSessionID(%q) = %q, want %q", tt.prefix, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestArchiveStats(t *testing.T) {
 	store := newTestArchiveStore(t)
 
@@ -2339,3 +2480,97 @@ func TestEndSession_PanickingCallbackDoesNotPoisonCaller(t *testing.T) {
 		t.Error("session ended_at not set — DB write was rolled back by panic")
 	}
 }
+
+// TestSearch_RecencyHalfLifeReordersByAge verifies recency-weighted
+// ranking: a months-old message with the strongest BM25 match leads
+// under pure BM25 and long half-lives, and yields to newer, weaker
+// matches as the half-life shrinks.
+func TestSearch_RecencyHalfLifeReordersByAge(t *testing.T) {
+	store := newTestArchiveStore(t)
+
+	now := time.Now()
+	day := 24 * time.Hour
+	msgs := []Message{
+		{ID: "six-months", ConversationID: "conv-1", SessionID: "sess-1", Role: "user",
+			Content:   "thermostat thermostat thermostat schedule",
+			Timestamp: now.Add(-180 * day), ArchiveReason: string(ArchiveReasonReset)},
+		{ID: "two-months", ConversationID: "conv-2", SessionID: "sess-2", Role: "user",
+			Content:   "the thermostat schedule changed again after the thermostat update",
+			Timestamp: now.Add(-60 * day), ArchiveReason: string(ArchiveReasonReset)},
+		{ID: "yesterday", ConversationID: "conv-3", SessionID: "sess-3", Role: "user",
+			Content:   "we talked about the thermostat and a lot of other things about the weekend plans and the garden",
+			Timestamp: now.Add(-1 * day), ArchiveReason: string(ArchiveReasonReset)},
+	}
+	if err := store.ArchiveMessages(msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		halfLife time.Duration
+		want     []string
+	}{
+		{"pure BM25", 0, []string{"six-months", "two-months", "yesterday"}},
+		{"ten-year half-life", 3650 * day, []string{"six-months", "two-months", "yesterday"}},
+		{"quarterly half-life", 90 * day, []string{"two-months", "yesterday", "six-months"}},
+		{"weekly half-life", 7 * day, []string{"yesterday", "two-months", "six-months"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := store.Search(SearchOptions{
+				Query:           "thermostat",
+				Limit:           3,
+				NoContext:       true,
+				RecencyHalfLife: tt.halfLife,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, r := range res {
+				got = append(got, r.Match.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearch_RecencyHalfLifeKeepsLimit(t *testing.T) {
+	store := newTestArchiveStore(t)
+
+	now := time.Now()
+	var msgs []Message
+	for i := range 8 {
+		msgs = append(msgs, Message{
+			ID: fmt.Sprintf("m%d", i), ConversationID: "conv-1", SessionID: "sess-1", Role: "user",
+			Content:   "sprinkler zone check",
+			Timestamp: now.Add(-time.Duration(i) * 24 * time.Hour), ArchiveReason: string(ArchiveReasonReset),
+		})
+	}
+	if err := store.ArchiveMessages(msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := store.Search(SearchOptions{
+		Query:           "sprinkler zone",
+		Limit:           3,
+		NoContext:       true,
+		RecencyHalfLife: 7 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 3 {
+		t.Fatalf("results = %d, want limit 3", len(res))
+	}
+	for i, r := range res {
+		if want := fmt.Sprintf("m%d", i); r.Match.ID != want {
+			t.Errorf("result %d = %s, want %s (equal relevance ranks newest first)", i, r.Match.ID, want)
+		}
+		if r.Score <= 0 {
+			t.Errorf("result %d score = %v, want > 0", i, r.Score)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nugget/thane-ai-agent/internal/model/llm"
+	"github.com/nugget/thane-ai-agent/internal/platform/checkpoint"
+)
+
+// restoreArchiveReason is the archive_reason recorded on active
+// messages a checkpoint restore displaces.
+const restoreArchiveReason = "checkpoint_restore"
+
+// RestoreCheckpoint rewinds working memory to the conversations in
+// state, implementing [checkpoint.Restorer]. A conversation whose
+// active messages differ from the snapshot has them archived and the
+// snapshot's messages written in their place; an active conversation
+// the snapshot does not know is archived. Nothing is deleted, so the
+// displaced history stays searchable in the archive. The whole restore
+// is one transaction.
+func (s *SQLiteStore) RestoreCheckpoint(state *checkpoint.State, dryRun bool) (checkpoint.StoreChange, error) {
+	change := checkpoint.StoreChange{Store: "memory"}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return change, fmt.Errorf("begin restore tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	current, err := activeMessagesByConversation(tx)
+	if err != nil {
+		return change, err
+	}
+
+	now := time.Now().UTC()
+	seen := make(map[string]bool, len(state.Conversations))
+	for _, conv := range state.Conversations {
+		seen[conv.ID] = true
+		existing, ok := current[conv.ID]
+		switch {
+		case !ok && len(conv.Messages) == 0, ok && sameMessages(existing, conv.Messages):
+			change.Unchanged++
+			continue
+		case ok:
+			change.Updated++
+		default:
+			change.Added++
+		}
+		if dryRun {
+			continue
+		}
+		if err := restoreConversation(tx, conv, now); err != nil {
+			return change, fmt.Errorf("restore conversation %s: %w", conv.ID, err)
+		}
+	}
+
+	for id := range current {
+		if seen[id] {
+			continue
+		}
+		change.Removed++
+		if dryRun {
+			continue
+		}
+		if err := archiveActive(tx, id, now); err != nil {
+			return change, fmt.Errorf("archive conversation %s: %w", id, err)
+		}
+	}
+
+	if dryRun {
+		return change, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return change, fmt.Errorf("commit restore: %w", err)
+	}
+	return change, nil
+}
+
+// activeMessagesByConversation returns every active message, grouped
+// by conversation in chronological order.
+func activeMessagesByConversation(tx *sql.Tx) (map[string][]Message, error) {
+	rows, err := tx.Query(`
+		SELECT conversation_id, role, content, timestamp, COALESCE(tool_call_id, '')
+		FROM messages
+		WHERE status = 'active'
+		ORDER BY conversation_id, timestamp ASC, id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query active messages: %w", err)
+	}
+	defer rows.Close()
+
+	byConv := make(map[string][]Message)
+	for rows.Next() {
+		var m Message
+		if err := rows.Scan(&m.ConversationID, &m.Role, &m.Content, &m.Timestamp, &m.ToolCallID); err != nil {
+			return nil, fmt.Errorf("scan active message: %w", err)
+		}
+		byConv[m.ConversationID] = append(byConv[m.ConversationID], m)
+	}
+	return byConv, rows.Err()
+}
+
+// sameMessages reports whether the active messages match a snapshot
+// conversation. Message IDs are not compared: checkpoints assign their
+// own.
+func sameMessages(active []Message, snapshot []checkpoint.Message) bool {
+	if len(active) != len(snapshot) {
+		return false
+	}
+	for i, m := range active {
+		if m.Role != snapshot[i].Role || m.Content != snapshot[i].Content ||
+			m.ToolCallID != snapshot[i].ToolID || !m.Timestamp.Equal(snapshot[i].Timestamp) {
+			return false
+		}
+	}
+	return true
+}
+
+// restoreConversation replaces a conversation's active messages with
+// the snapshot's, creating the conversation if it no longer exists.
+func restoreConversation(tx *sql.Tx, conv checkpoint.Conversation, now time.Time) error {
+	if _, err := tx.Exec(`
+		INSERT OR IGNORE INTO conversations (id, created_at, updated_at)
+		VALUES (?, ?, ?)
+	`, conv.ID, conv.CreatedAt, conv.UpdatedAt); err != nil {
+		return fmt.Errorf("create conversation: %w", err)
+	}
+	if err := archiveActive(tx, conv.ID, now); err != nil {
+		return err
+	}
+
+	for _, m := range conv.Messages {
+		msgID, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("generate message ID: %w", err)
+		}
+		var toolCalls, toolCallID *string
+		if len(m.ToolCalls) > 0 {
+			data, err := json.Marshal(m.ToolCalls)
+			if err != nil {
+				return fmt.Errorf("encode tool calls: %w", err)
+			}
+			encoded := string(data)
+			toolCalls = &encoded
+		}
+		if m.ToolID != "" {
+			toolCallID = &m.ToolID
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO messages (id, conversation_id, role, content, timestamp, token_count, tool_calls, tool_call_id, status)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, 'active')
+		`, msgID.String(), conv.ID, m.Role, m.Content, m.Timestamp, llm.EstimateTokens(m.Content), toolCalls, toolCallID); err != nil {
+			return fmt.Errorf("insert message: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE conversations SET updated_at = ? WHERE id = ?`, conv.UpdatedAt, conv.ID); err != nil {
+		return fmt.Errorf("update conversation: %w", err)
+	}
+	return nil
+}
+
+// archiveActive archives a conversation's active messages as displaced
+// by a restore.
+func archiveActive(tx *sql.Tx, conversationID string, now time.Time) error {
+	if _, err := tx.Exec(`
+		UPDATE messages
+		SET status = 'archived', archived_at = ?, archive_reason = ?
+		WHERE conversation_id = ? AND status = 'active'
+	`, now.Format(time.RFC3339Nano), restoreArchiveReason, conversationID); err != nil {
+		return fmt.Errorf("archive active messages: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/checkpoint"
+)
+
+// snapshotConversations captures the store the way the app's
+// checkpoint provider does.
+func snapshotConversations(t *testing.T, store *SQLiteStore) *checkpoint.State {
+	t.Helper()
+	state := &checkpoint.State{}
+	for _, c := range store.GetAllConversations() {
+		msgs := make([]checkpoint.SourceMessage, len(c.Messages))
+		for i, m := range c.Messages {
+			msgs[i] = checkpoint.SourceMessage{
+				Role: m.Role, Content: m.Content, Timestamp: m.Timestamp,
+				ToolCalls: m.ToolCalls, ToolCallID: m.ToolCallID,
+			}
+		}
+		conv, err := checkpoint.ConvertConversation(c.ID, c.CreatedAt, c.UpdatedAt, msgs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.Conversations = append(state.Conversations, conv)
+	}
+	return state
+}
+
+func TestRestoreCheckpoint(t *testing.T) {
+	store, err := NewSQLiteStore(t.TempDir()+"/memory.db", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	for _, m := range []struct{ conv, content string }{
+		{"kept", "hello"}, {"kept", "hi there"}, {"changed", "before"},
+	} {
+		if err := store.AddMessage(m.conv, "user", m.content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	state := snapshotConversations(t, store)
+
+	if err := store.AddMessage("changed", "assistant", "after the snapshot"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddMessage("new", "user", "not in the snapshot"); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := store.RestoreCheckpoint(state, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Unchanged != 1 || plan.Updated != 1 || plan.Removed != 1 || plan.Added != 0 {
+		t.Fatalf("plan = %+v, want 1 unchanged, 1 updated, 1 removed", plan)
+	}
+	if got := len(store.GetMessages("changed")); got != 2 {
+		t.Fatalf("dry run changed memory: %d messages, want 2", got)
+	}
+
+	if _, err := store.RestoreCheckpoint(state, false); err != nil {
+		t.Fatal(err)
+	}
+	if msgs := store.GetMessages("changed"); len(msgs) != 1 || msgs[0].Content != "before" {
+		t.Errorf("restored conversation = %+v, want only the snapshot message", msgs)
+	}
+	if msgs := store.GetMessages("new"); len(msgs) != 0 {
+		t.Errorf("conversation absent from the snapshot still active: %+v", msgs)
+	}
+
+	var archived int
+	if err := store.DB().QueryRow(`SELECT COUNT(*) FROM messages WHERE archive_reason = ?`, restoreArchiveReason).Scan(&archived); err != nil {
+		t.Fatal(err)
+	}
+	if archived != 3 {
+		t.Errorf("archived %d displaced messages, want 3", archived)
+	}
+
+	again, err := store.RestoreCheckpoint(state, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Changed() {
+		t.Errorf("second restore plan = %+v, want no changes", again)
+	}
+}
+
+func TestRestoreCheckpoint_KeepsToolStructure(t *testing.T) {
+	store, err := NewSQLiteStore(t.TempDir()+"/memory.db", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if err := store.AddMessage("tools", "user", "is the porch light on?"); err != nil {
+		t.Fatal(err)
+	}
+	base := time.Now().UTC()
+	for i, m := range []struct{ role, content, toolCalls, toolCallID string }{
+		{"assistant", "", `[{"id":"call-1","function":{"name":"get_state","arguments":{"entity_id":"light.porch"}}}]`, ""},
+		{"tool", `{"state":"on"}`, "", "call-1"},
+	} {
+		if _, err := store.DB().Exec(`
+			INSERT INTO messages (id, conversation_id, role, content, timestamp, tool_calls, tool_call_id, status)
+			VALUES (?, 'tools', ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), 'active')
+		`, fmt.Sprintf("tool-msg-%d", i), m.role, m.content, base.Add(time.Duration(i+1)*time.Second), m.toolCalls, m.toolCallID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	state := snapshotConversations(t, store)
+
+	if err := store.AddMessage("tools", "assistant", "after the snapshot"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.RestoreCheckpoint(state, false); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := store.GetMessages("tools")
+	if len(msgs) != 3 {
+		t.Fatalf("restored %d messages, want 3", len(msgs))
+	}
+	want := `[{"id":"call-1","name":"get_state","arguments":"{\"entity_id\":\"light.porch\"}"}]`
+	if msgs[1].ToolCalls != want {
+		t.Errorf("assistant tool_calls = %s, want %s", msgs[1].ToolCalls, want)
+	}
+	if msgs[2].ToolCallID != "call-1" {
+		t.Errorf("tool message tool_call_id = %q, want call-1", msgs[2].ToolCallID)
+	}
+
+	// The restored form round-trips: a second restore is a no-op.
+	if again, err := store.RestoreCheckpoint(snapshotConversations(t, store), true); err != nil || again.Changed() {
+		t.Errorf("restore of the restored state = %+v, %v; want no changes", again, err)
+	}
+}
@@ -54,6 +54,17 @@ type CompactableStore interface {
 	// ApplyCompaction atomically marks compactedIDs compacted and
 	// inserts the replacement summary at summaryTS.
 	ApplyCompaction(conversationID string, compactedIDs []string, summary string, summaryTS time.Time) error
+	// CompactionState reports the persisted compaction count and time,
+	// which ApplyCompaction advances in the same transaction.
+	CompactionState(conversationID string) (CompactionState, error)
+}
+
+// CompactionState is a conversation's persisted compaction history.
+// It lives in the store alongside the compacted rows and the summary,
+// so it survives restarts with them.
+type CompactionState struct {
+	Count           int
+	LastCompactedAt time.Time
 }
 
 // WorkingMemoryReader is the subset of WorkingMemoryStore needed by the
@@ -66,7 +77,8 @@ type WorkingMemoryReader interface {
 // Compactor handles conversation compaction.
 type Compactor struct {
 	store         CompactableStore
-	config        CompactionConfig
+	configMu      sync.RWMutex
+	config        CompactionConfig // guarded by configMu; see SetConfig
 	summarizer    Summarizer
 	workingMemory WorkingMemoryReader // optional — include in compaction prompt
 	logger        *slog.Logger
@@ -104,9 +116,40 @@ func (c *Compactor) SetWorkingMemoryStore(wm WorkingMemoryReader) {
 	c.workingMemory = wm
 }
 
+// Config returns the compactor's current configuration.
+func (c *Compactor) Config() CompactionConfig {
+	c.configMu.RLock()
+	defer c.configMu.RUnlock()
+	return c.config
+}
+
+// SetConfig replaces the compactor's configuration at runtime, e.g.
+// when the operator tunes TriggerRatio or KeepRecent from Home
+// Assistant. Compactions already in progress finish under the config
+// they started with; the next check uses the new one.
+func (c *Compactor) SetConfig(cfg CompactionConfig) {
+	c.configMu.Lock()
+	old := c.config
+	c.config = cfg
+	c.configMu.Unlock()
+	if c.logger != nil {
+		c.logger.Info("compaction config updated",
+			"trigger_ratio", cfg.TriggerRatio,
+			"keep_recent", cfg.KeepRecent,
+			"previous_trigger_ratio", old.TriggerRatio,
+			"previous_keep_recent", old.KeepRecent,
+		)
+	}
+}
+
 // CompactionThreshold returns the token count at which compaction triggers.
 func (c *Compactor) CompactionThreshold() int {
-	return int(float64(c.config.MaxTokens) * c.config.TriggerRatio)
+	return c.Config().threshold()
+}
+
+// threshold is the token count at which compaction triggers under cfg.
+func (cfg CompactionConfig) threshold() int {
+	return int(float64(cfg.MaxTokens) * cfg.TriggerRatio)
 }
 
 // NeedsCompaction checks if a conversation needs compaction. It fires on
@@ -116,11 +159,12 @@ func (c *Compactor) CompactionThreshold() int {
 // exact precondition of the working-memory freeze — so the predicate is
 // a strict superset of the prior token-only behavior.
 func (c *Compactor) NeedsCompaction(conversationID string) bool {
-	if c.store.GetTokenCount(conversationID) > c.CompactionThreshold() {
+	cfg := c.Config()
+	if c.store.GetTokenCount(conversationID) > cfg.threshold() {
 		return true
 	}
-	if c.config.MaxActiveMessages > 0 &&
-		c.store.ActiveMessageCount(conversationID) >= c.config.MaxActiveMessages {
+	if cfg.MaxActiveMessages > 0 &&
+		c.store.ActiveMessageCount(conversationID) >= cfg.MaxActiveMessages {
 		return true
 	}
 	return false
@@ -166,7 +210,8 @@ func (c *Compactor) Compact(ctx context.Context, conversationID string) error {
 	}
 
 	// Get messages to compact (older ones)
-	messages := c.store.GetMessagesForCompaction(conversationID, c.config.KeepRecent)
+	cfg := c.Config()
+	messages := c.store.GetMessagesForCompaction(conversationID, cfg.KeepRecent)
 
 	// Snap the compaction boundary to a turn edge: a trailing user
 	// message here means its reply sits in the keep window (or hasn't
@@ -178,24 +223,24 @@ func (c *Compactor) Compact(ctx context.Context, conversationID string) error {
 	for len(trimmed) > 0 && trimmed[len(trimmed)-1].Role == "user" {
 		trimmed = trimmed[:len(trimmed)-1]
 	}
-	if len(trimmed) >= c.config.MinMessagesToCompact || len(messages) < c.config.MinMessagesToCompact {
+	if len(trimmed) >= cfg.MinMessagesToCompact || len(messages) < cfg.MinMessagesToCompact {
 		messages = trimmed
 	}
 
 	c.logger.Debug("compaction check",
 		"conversation_id", conversationID,
 		"eligible_messages", len(messages),
-		"min_required", c.config.MinMessagesToCompact,
-		"keep_recent", c.config.KeepRecent,
+		"min_required", cfg.MinMessagesToCompact,
+		"keep_recent", cfg.KeepRecent,
 		"token_count", c.store.GetTokenCount(conversationID),
-		"max_tokens", c.config.MaxTokens,
+		"max_tokens", cfg.MaxTokens,
 	)
 
-	if len(messages) < c.config.MinMessagesToCompact {
+	if len(messages) < cfg.MinMessagesToCompact {
 		c.logger.Debug("compaction skipped: not enough messages",
 			"conversation_id", conversationID,
 			"eligible", len(messages),
-			"required", c.config.MinMessagesToCompact,
+			"required", cfg.MinMessagesToCompact,
 		)
 		return nil // Not enough to bother
 	}
@@ -283,25 +328,37 @@ func formatCompactionSummary(messages []Message, summary string) string {
 
 // CompactionStats returns stats about compaction for a conversation.
 func (c *Compactor) CompactionStats(conversationID string) map[string]any {
+	cfg := c.Config()
 	tokenCount := c.store.GetTokenCount(conversationID)
-	threshold := int(float64(c.config.MaxTokens) * c.config.TriggerRatio)
+	threshold := cfg.threshold()
 	activeCount := c.store.ActiveMessageCount(conversationID)
 
 	// needs_compaction must reflect BOTH gates (token budget OR active
 	// count), matching NeedsCompaction — otherwise the stat lies whenever
 	// the count gate is the reason compaction fires.
 	needsToken := tokenCount > threshold
-	needsCount := c.config.MaxActiveMessages > 0 && activeCount >= c.config.MaxActiveMessages
+	needsCount := cfg.MaxActiveMessages > 0 && activeCount >= cfg.MaxActiveMessages
 
-	return map[string]any{
+	stats := map[string]any{
 		"token_count":          tokenCount,
-		"max_tokens":           c.config.MaxTokens,
+		"max_tokens":           cfg.MaxTokens,
 		"trigger_at":           threshold,
 		"active_message_count": activeCount,
-		"max_active_messages":  c.config.MaxActiveMessages,
+		"max_active_messages":  cfg.MaxActiveMessages,
 		"needs_compaction":     needsToken || needsCount,
-		"ratio":                float64(tokenCount) / float64(c.config.MaxTokens),
+		"ratio":                float64(tokenCount) / float64(cfg.MaxTokens),
+	}
+	state, err := c.store.CompactionState(conversationID)
+	if err != nil {
+		c.logger.Warn("failed to read compaction state",
+			"conversation_id", conversationID, "error", err)
+		return stats
+	}
+	stats["compaction_count"] = state.Count
+	if !state.LastCompactedAt.IsZero() {
+		stats["last_compacted_at"] = state.LastCompactedAt
 	}
+	return stats
 }
 
 // LLMSummarizer uses an LLM to generate summaries.
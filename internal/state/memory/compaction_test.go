@@ -156,6 +156,68 @@ func TestCompaction_FoldsPriorSummariesIntoOne(t *testing.T) {
 	}
 }
 
+func TestCompaction_SurvivesRestart(t *testing.T) {
+	path := t.TempDir() + "/restart.db"
+	base := time.Now().Add(-3 * time.Hour).Truncate(time.Second)
+
+	store, err := NewSQLiteStore(path, 100)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	if _, err := store.GetOrCreateConversation("conv-1"); err != nil {
+		t.Fatalf("GetOrCreateConversation: %v", err)
+	}
+	for i := range 15 {
+		ts := base.Add(time.Duration(2*i) * time.Minute)
+		insertMessageAt(t, store, "conv-1", "user", "question with enough padding to count tokens", ts)
+		insertMessageAt(t, store, "conv-1", "assistant", "answer with enough padding to count tokens", ts.Add(time.Minute))
+	}
+	if err := compactorFor(store, &countingSummarizer{}).Compact(context.Background(), "conv-1"); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	before := store.GetMessages("conv-1")
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopen as a restarted process would: fresh store, fresh compactor.
+	store, err = NewSQLiteStore(path, 100)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer store.Close()
+	sum := &countingSummarizer{}
+	c := compactorFor(store, sum)
+
+	if c.NeedsCompaction("conv-1") {
+		t.Error("NeedsCompaction = true after restart, want the earlier compaction honored")
+	}
+	if err := c.Compact(context.Background(), "conv-1"); err != nil {
+		t.Fatalf("Compact after restart: %v", err)
+	}
+	if got := sum.calls.Load(); got != 0 {
+		t.Errorf("summarizer calls after restart = %d, want 0 (no double summary)", got)
+	}
+	after := store.GetMessages("conv-1")
+	if len(after) != len(before) || after[0].ID != before[0].ID || !strings.HasPrefix(after[0].Content, CompactionSummaryPrefix) {
+		t.Errorf("history after restart = %d messages led by %q, want the same %d led by the summary", len(after), after[0].ID, len(before))
+	}
+
+	stats := c.CompactionStats("conv-1")
+	if stats["compaction_count"] != 1 {
+		t.Errorf("compaction_count = %v, want 1", stats["compaction_count"])
+	}
+	if last, ok := stats["last_compacted_at"].(time.Time); !ok || time.Since(last) > time.Minute {
+		t.Errorf("last_compacted_at = %v, want the recent compaction", stats["last_compacted_at"])
+	}
+	if got := store.Stats()["compactions"]; got != 1 {
+		t.Errorf("store compactions = %v, want 1", got)
+	}
+	if _, ok := store.Stats()["last_compaction"].(time.Time); !ok {
+		t.Error("store stats missing last_compaction")
+	}
+}
+
 func TestCompaction_SummaryTakesCompactedRegionPosition(t *testing.T) {
 	base := time.Now().Add(-4 * time.Hour).Truncate(time.Second)
 	store := newCompactionTestStore(t, "conv-1", base, 15)
@@ -288,3 +350,31 @@ func TestCompactionStats(t *testing.T) {
 		t.Errorf("needs_compaction = %v, want true (count gate fires under token threshold)", cs["needs_compaction"])
 	}
 }
+
+func TestCompactor_SetConfigAppliesToNextCheck(t *testing.T) {
+	store, err := NewSQLiteStore(t.TempDir()+"/setconfig.db", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	compactor := NewCompactor(store, CompactionConfig{MaxTokens: 1000, TriggerRatio: 0.7}, &SimpleSummarizer{}, slog.Default())
+	if got := compactor.CompactionThreshold(); got != 700 {
+		t.Fatalf("CompactionThreshold() = %d, want 700", got)
+	}
+
+	cfg := compactor.Config()
+	cfg.TriggerRatio = 0.5
+	cfg.KeepRecent = 4
+	compactor.SetConfig(cfg)
+
+	if got := compactor.CompactionThreshold(); got != 500 {
+		t.Errorf("CompactionThreshold() after SetConfig = %d, want 500", got)
+	}
+	if got := compactor.CompactionStats("none")["trigger_at"]; got != 500 {
+		t.Errorf("trigger_at = %v, want 500", got)
+	}
+	if got := compactor.Config().KeepRecent; got != 4 {
+		t.Errorf("KeepRecent = %d, want 4", got)
+	}
+}
@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nugget/thane-ai-agent/internal/model/promptfmt"
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/runtime/agentctx"
+)
+
+// MaxConversationEgoBytes bounds one conversation's self-notes. The
+// global ego.md is capped at 16 KB; conversation notes refine it for a
+// single thread, so they get a quarter of that.
+const MaxConversationEgoBytes = 4 * 1024
+
+// ConversationEgoStore persists per-conversation self-notes: what the
+// agent has learned about how to behave in one thread ("in this thread
+// the user prefers brevity"). They blend with the global ego.md, which
+// reflects across all conversations. The table lives in thane.db
+// beside working memory.
+type ConversationEgoStore struct {
+	db *sql.DB
+}
+
+// NewConversationEgoStore creates a conversation ego store using the
+// given database connection, creating the conversation_ego table if it
+// does not already exist.
+func NewConversationEgoStore(db *sql.DB) (*ConversationEgoStore, error) {
+	s := &ConversationEgoStore{db: db}
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS conversation_ego (
+			conversation_id TEXT NOT NULL PRIMARY KEY,
+			content         TEXT NOT NULL,
+			updated_at      TEXT NOT NULL
+		)
+	`); err != nil {
+		return nil, fmt.Errorf("conversation ego migration: %w", err)
+	}
+	return s, nil
+}
+
+// Get returns the self-notes and last-updated timestamp for a
+// conversation. If none exist, it returns an empty string and zero time
+// with no error.
+func (s *ConversationEgoStore) Get(conversationID string) (string, time.Time, error) {
+	var content, updatedAtStr string
+	err := s.db.QueryRow(`
+		SELECT content, updated_at FROM conversation_ego
+		WHERE conversation_id = ?
+	`, conversationID).Scan(&content, &updatedAtStr)
+	if err == sql.ErrNoRows {
+		return "", time.Time{}, nil
+	}
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("get conversation ego: %w", err)
+	}
+	updatedAt, err := database.ParseTimestamp(updatedAtStr)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("parse conversation ego updated_at: %w", err)
+	}
+	return content, updatedAt, nil
+}
+
+// Set writes or replaces the self-notes for a conversation. Content
+// larger than [MaxConversationEgoBytes] is rejected so the caller
+// condenses it rather than having it silently cut.
+func (s *ConversationEgoStore) Set(conversationID, content string) error {
+	if len(content) > MaxConversationEgoBytes {
+		return fmt.Errorf("conversation ego is %d bytes, exceeds the %d byte limit; condense it", len(content), MaxConversationEgoBytes)
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO conversation_ego (conversation_id, content, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(conversation_id) DO UPDATE SET
+			content = excluded.content,
+			updated_at = excluded.updated_at
+	`, conversationID, content, time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("set conversation ego: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the self-notes for a conversation.
+func (s *ConversationEgoStore) Delete(conversationID string) error {
+	if _, err := s.db.Exec(`
+		DELETE FROM conversation_ego WHERE conversation_id = ?
+	`, conversationID); err != nil {
+		return fmt.Errorf("delete conversation ego: %w", err)
+	}
+	return nil
+}
+
+// ConversationEgoProvider implements [agent.TagContextProvider] for
+// auto-injecting the current conversation's self-notes. Registered via
+// [agent.Loop.RegisterAlwaysContextProvider].
+type ConversationEgoProvider struct {
+	store            *ConversationEgoStore
+	conversationFunc func(context.Context) string
+}
+
+// NewConversationEgoProvider creates a context provider that injects
+// the self-notes of the conversation returned by convFunc — typically
+// [tools.ConversationIDFromContext].
+func NewConversationEgoProvider(store *ConversationEgoStore, convFunc func(context.Context) string) *ConversationEgoProvider {
+	return &ConversationEgoProvider{store: store, conversationFunc: convFunc}
+}
+
+// TagContextBucket places conversation self-notes in continuity context
+// beside working memory; both carry durable state for the active
+// conversation.
+func (p *ConversationEgoProvider) TagContextBucket() agentctx.ContextBucket {
+	return agentctx.ContextBucketContinuity
+}
+
+// TagContext returns the current conversation's self-notes formatted
+// for system prompt injection, or an empty string when there are none.
+func (p *ConversationEgoProvider) TagContext(ctx context.Context, _ agentctx.ContextRequest) (string, error) {
+	convID := p.conversationFunc(ctx)
+	if convID == "" {
+		return "", nil
+	}
+	content, updatedAt, err := p.store.Get(convID)
+	if err != nil {
+		return "", fmt.Errorf("read conversation ego: %w", err)
+	}
+	if content == "" {
+		return "", nil
+	}
+	if len(content) > MaxConversationEgoBytes {
+		cut := MaxConversationEgoBytes
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		content = content[:cut] + "\n\n[conversation ego truncated]"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("### Conversation Self-Notes\n\n")
+	sb.WriteString("*What you have learned about yourself in this conversation. These refine ego.md for this thread only; where they conflict, they win here.*\n\n")
+	if !updatedAt.IsZero() {
+		sb.WriteString(fmt.Sprintf("*Last updated: %s*\n\n", promptfmt.FormatDeltaOnly(updatedAt, time.Now())))
+	}
+	sb.WriteString(content)
+	return sb.String(), nil
+}
@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/nugget/thane-ai-agent/internal/runtime/agentctx"
+	_ "modernc.org/sqlite"
+)
+
+type convCtxKey struct{}
+
+func openConversationEgoDB(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite-thane", path)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newTestConversationEgo(t *testing.T) (*ConversationEgoProvider, *ConversationEgoStore) {
+	t.Helper()
+	store, err := NewConversationEgoStore(openConversationEgoDB(t, filepath.Join(t.TempDir(), "test.db")))
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	convFunc := func(ctx context.Context) string {
+		id, _ := ctx.Value(convCtxKey{}).(string)
+		return id
+	}
+	return NewConversationEgoProvider(store, convFunc), store
+}
+
+func convContext(id string) context.Context {
+	return context.WithValue(context.Background(), convCtxKey{}, id)
+}
+
+func TestConversationEgoProvider_InjectedForOwnConversationOnly(t *testing.T) {
+	p, store := newTestConversationEgo(t)
+
+	if err := store.Set("conv-a", "The user prefers brevity in this thread."); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	got, err := p.TagContext(convContext("conv-a"), agentctx.ContextRequest{})
+	if err != nil {
+		t.Fatalf("TagContext(conv-a): %v", err)
+	}
+	if !strings.Contains(got, "### Conversation Self-Notes") {
+		t.Errorf("missing header in %q", got)
+	}
+	if !strings.Contains(got, "prefers brevity") {
+		t.Errorf("conv-a notes not injected: %q", got)
+	}
+
+	for _, other := range []string{"conv-b", ""} {
+		got, err := p.TagContext(convContext(other), agentctx.ContextRequest{})
+		if err != nil {
+			t.Fatalf("TagContext(%q): %v", other, err)
+		}
+		if got != "" {
+			t.Errorf("conversation %q got conv-a notes: %q", other, got)
+		}
+	}
+}
+
+func TestConversationEgoProvider_SurvivesAcrossTurns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	store, err := NewConversationEgoStore(openConversationEgoDB(t, path))
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	if err := store.Set("conv-a", "Stay in planning mode; no device changes."); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	convFunc := func(context.Context) string { return "conv-a" }
+	p := NewConversationEgoProvider(store, convFunc)
+	for turn := range 3 {
+		got, err := p.TagContext(context.Background(), agentctx.ContextRequest{UserMessage: "next"})
+		if err != nil {
+			t.Fatalf("turn %d: %v", turn, err)
+		}
+		if !strings.Contains(got, "planning mode") {
+			t.Fatalf("turn %d: notes missing: %q", turn, got)
+		}
+	}
+
+	// A fresh store on the same database, as after a restart.
+	reopened, err := NewConversationEgoStore(openConversationEgoDB(t, path))
+	if err != nil {
+		t.Fatalf("reopen store: %v", err)
+	}
+	got, err := NewConversationEgoProvider(reopened, convFunc).TagContext(context.Background(), agentctx.ContextRequest{})
+	if err != nil {
+		t.Fatalf("TagContext after reopen: %v", err)
+	}
+	if !strings.Contains(got, "planning mode") {
+		t.Errorf("notes lost after reopen: %q", got)
+	}
+}
+
+func TestConversationEgoStore_SizeBound(t *testing.T) {
+	_, store := newTestConversationEgo(t)
+
+	if err := store.Set("conv-a", strings.Repeat("x", MaxConversationEgoBytes)); err != nil {
+		t.Fatalf("Set at limit: %v", err)
+	}
+	if err := store.Set("conv-a", strings.Repeat("x", MaxConversationEgoBytes+1)); err == nil {
+		t.Fatal("Set over limit succeeded, want error")
+	}
+	content, _, err := store.Get("conv-a")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(content) != MaxConversationEgoBytes {
+		t.Errorf("content len = %d, want the at-limit write preserved (%d)", len(content), MaxConversationEgoBytes)
+	}
+}
+
+func TestConversationEgoProvider_TruncatesOnRuneBoundary(t *testing.T) {
+	p, store := newTestConversationEgo(t)
+
+	// Oversized notes can only predate the Set bound; write them
+	// directly. The byte limit falls inside a two-byte rune.
+	content := "x" + strings.Repeat("é", MaxConversationEgoBytes)
+	if _, err := store.db.Exec(`INSERT INTO conversation_ego (conversation_id, content, updated_at) VALUES (?, ?, ?)`,
+		"conv-a", content, "2026-01-01T00:00:00Z"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := p.TagContext(convContext("conv-a"), agentctx.ContextRequest{})
+	if err != nil {
+		t.Fatalf("TagContext: %v", err)
+	}
+	if !utf8.ValidString(got) {
+		t.Error("truncated notes are not valid UTF-8")
+	}
+	if !strings.Contains(got, "é\n\n[conversation ego truncated]") {
+		t.Errorf("want notes cut after a whole rune, got tail %q", got[len(got)-40:])
+	}
+}
+
+func TestConversationEgoStore_Delete(t *testing.T) {
+	p, store := newTestConversationEgo(t)
+
+	if err := store.Set("conv-a", "notes"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := store.Delete("conv-a"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	got, err := p.TagContext(convContext("conv-a"), agentctx.ContextRequest{})
+	if err != nil {
+		t.Fatalf("TagContext: %v", err)
+	}
+	if got != "" {
+		t.Errorf("expected no injection after delete, got %q", got)
+	}
+}
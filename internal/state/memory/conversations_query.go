@@ -30,7 +30,7 @@ const (
 	// (flat and outer-wrapped). Aliased so the outer min/max or message_count
 	// wrapper can reference the columns; scanning is positional either way.
 	convSummarySelect = "c.id AS id, " + convCountExpr + " AS message_count, " +
-		convCreatedNorm + " AS created_norm, " + convUpdatedNorm + " AS updated_norm, c.metadata AS metadata"
+		convCreatedNorm + " AS created_norm, " + convUpdatedNorm + " AS updated_norm, c.metadata AS metadata, c.title AS title"
 )
 
 func normConvTime(t time.Time) string {
@@ -51,7 +51,7 @@ type ConversationQuery struct {
 	Channel   string // metadata.channel_binding.channel
 	ContactID string // metadata.channel_binding.contact_id
 	Address   string // metadata.channel_binding.address
-	Q         string // substring over id + contact_name + address (metadata only)
+	Q         string // substring over id + title + contact_name + address
 
 	UpdatedAfter  *time.Time
 	UpdatedBefore *time.Time
@@ -78,11 +78,12 @@ type ConvCursor struct {
 }
 
 // ConversationSummary is a lightweight conversation descriptor — identity,
-// active message count, timestamps, and channel binding — with no message
-// content. message_count is the TRUE active count (uncapped), unlike the
-// legacy list which reported min(active, maxMessages).
+// running title, active message count, timestamps, and channel binding —
+// with no message content. message_count is the TRUE active count
+// (uncapped), unlike the legacy list which reported min(active, maxMessages).
 type ConversationSummary struct {
 	ID             string          `json:"id"`
+	Title          string          `json:"title,omitempty"`
 	MessageCount   int             `json:"message_count"`
 	CreatedAt      time.Time       `json:"created_at"`
 	UpdatedAt      time.Time       `json:"updated_at"`
@@ -145,8 +146,8 @@ func (s *SQLiteStore) conversationFilters(q ConversationQuery) convFilters {
 	}
 	if q.Q != "" {
 		like := "%" + escapeLikePattern(q.Q) + "%"
-		f.inner = append(f.inner, `(c.id LIKE ? ESCAPE '\' OR (json_valid(c.metadata) AND (COALESCE(json_extract(c.metadata,'$.channel_binding.contact_name'),'') LIKE ? ESCAPE '\' OR COALESCE(json_extract(c.metadata,'$.channel_binding.address'),'') LIKE ? ESCAPE '\')))`)
-		f.innerArgs = append(f.innerArgs, like, like, like)
+		f.inner = append(f.inner, `(c.id LIKE ? ESCAPE '\' OR COALESCE(c.title,'') LIKE ? ESCAPE '\' OR (json_valid(c.metadata) AND (COALESCE(json_extract(c.metadata,'$.channel_binding.contact_name'),'') LIKE ? ESCAPE '\' OR COALESCE(json_extract(c.metadata,'$.channel_binding.address'),'') LIKE ? ESCAPE '\')))`)
+		f.innerArgs = append(f.innerArgs, like, like, like, like)
 	}
 
 	// Time ranges, compared against the normalized expression (index-friendly).
@@ -274,7 +275,7 @@ func (s *SQLiteStore) QueryConversations(q ConversationQuery) (*ConversationPage
 			innerSQL.WriteString(" WHERE " + strings.Join(inner, " AND "))
 		}
 
-		sb.WriteString("SELECT id, message_count, created_norm, updated_norm, metadata FROM (")
+		sb.WriteString("SELECT id, message_count, created_norm, updated_norm, metadata, title FROM (")
 		sb.WriteString(innerSQL.String())
 		sb.WriteString(")")
 		if len(outer) > 0 {
@@ -305,12 +306,12 @@ func (s *SQLiteStore) QueryConversations(q ConversationQuery) (*ConversationPage
 		// NullString so one odd row degrades to a zero timestamp (mirroring
 		// GetAllConversations' tolerate-and-continue) rather than 500-ing the
 		// whole page.
-		var createdNorm, updatedNorm, metadata sql.NullString
+		var createdNorm, updatedNorm, metadata, title sql.NullString
 		var msgCount int
-		if err := rows.Scan(&id, &msgCount, &createdNorm, &updatedNorm, &metadata); err != nil {
+		if err := rows.Scan(&id, &msgCount, &createdNorm, &updatedNorm, &metadata, &title); err != nil {
 			return nil, fmt.Errorf("scan conversation summary: %w", err)
 		}
-		sum := ConversationSummary{ID: id, MessageCount: msgCount}
+		sum := ConversationSummary{ID: id, MessageCount: msgCount, Title: title.String}
 		if createdNorm.Valid {
 			if t, err := database.ParseTimestamp(createdNorm.String); err == nil {
 				sum.CreatedAt = t
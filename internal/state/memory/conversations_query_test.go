@@ -317,6 +317,42 @@ func TestQueryConversationsFiltersAndEmpty(t *testing.T) {
 	})
 }
 
+// TestQueryConversationsTitle covers the running title: it is returned in
+// summaries (both query forms), searchable via Q, and setting it does not
+// bump updated_at and so does not reorder the list.
+func TestQueryConversationsTitle(t *testing.T) {
+	s := newConvQueryStore(t, 100)
+	seedConv(t, s, "loop-1", "2026-06-24T10:00:00Z", "2026-06-24T10:00:00Z", "")
+	seedConv(t, s, "loop-2", "2026-06-24T11:00:00Z", "2026-06-24T11:00:00Z", "")
+	if err := s.SetConversationTitle("loop-1", "Garden irrigation planning"); err != nil {
+		t.Fatalf("SetConversationTitle: %v", err)
+	}
+
+	for _, sort := range []string{"updated_at", "message_count"} {
+		page, err := s.QueryConversations(ConversationQuery{Sort: sort, Limit: 10})
+		if err != nil {
+			t.Fatalf("query sort=%s: %v", sort, err)
+		}
+		titles := map[string]string{}
+		for _, c := range page.Conversations {
+			titles[c.ID] = c.Title
+		}
+		if titles["loop-1"] != "Garden irrigation planning" || titles["loop-2"] != "" {
+			t.Errorf("sort=%s titles = %v", sort, titles)
+		}
+	}
+
+	page, _ := s.QueryConversations(ConversationQuery{Limit: 10})
+	if got := convIDs(page); !equalSlice(got, []string{"loop-2", "loop-1"}) {
+		t.Errorf("order after retitle = %v, want unchanged", got)
+	}
+
+	page, _ = s.QueryConversations(ConversationQuery{Q: "irrigation", Limit: 10})
+	if got := convIDs(page); !equalSlice(got, []string{"loop-1"}) {
+		t.Errorf("q=irrigation = %v, want title match", got)
+	}
+}
+
 // TestQueryConversationsUnparseableTimestamp covers a row whose timestamp
 // strftime cannot parse (→ NULL). It must never crash the page; it is excluded
 // from TIME-sorted views (no valid position in a time ordering, and it would
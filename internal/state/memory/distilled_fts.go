@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -42,7 +43,7 @@ type SearchBundle struct {
 // fault. Returning a non-nil error means the bundle value is
 // undefined and callers should not read it.
 type MemorySearcher interface {
-	Search(opts SearchOptions) (*SearchBundle, error)
+	Search(ctx context.Context, opts SearchOptions) (*SearchBundle, error)
 }
 
 // MemorySearch is the production MemorySearcher: composes
@@ -98,13 +99,18 @@ func NewMemorySearch(archive *ArchiveStore, working *WorkingMemoryStore, logger
 // Filtering after BM25 ranking is acceptable because the distilled
 // hit count is capped small (5 sessions, 3 working memory) — the
 // extra fetched-then-discarded rows are bounded and cheap.
-func (m *MemorySearch) Search(opts SearchOptions) (*SearchBundle, error) {
+//
+// opts.Mode selects the raw-message ranking (see [SearchMode]); the
+// distilled surfaces are always keyword. Semantic and hybrid modes
+// fail with [ErrSemanticSearchUnavailable] when the archive has no
+// embedding client.
+func (m *MemorySearch) Search(ctx context.Context, opts SearchOptions) (*SearchBundle, error) {
 	bundle := &SearchBundle{}
 	if m.archive == nil {
 		return bundle, nil
 	}
 
-	msgs, err := m.archive.Search(opts)
+	msgs, err := m.archive.searchMessages(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -112,10 +118,14 @@ func (m *MemorySearch) Search(opts SearchOptions) (*SearchBundle, error) {
 
 	// Total raw-message matches (pre-limit) for the overflow gauge.
 	// Soft-fail: an estimate failure must not drop the hits we have.
-	if total, err := m.archive.CountMatches(opts); err == nil {
-		bundle.TotalMessages = total
-	} else if m.logger != nil {
-		m.logger.Warn("message match count failed", "query", opts.Query, "error", err)
+	// A pure semantic search has no match count — every embedded
+	// message is a candidate — so the gauge stays unknown.
+	if opts.Mode != SearchModeSemantic {
+		if total, err := m.archive.CountMatches(opts); err == nil {
+			bundle.TotalMessages = total
+		} else if m.logger != nil {
+			m.logger.Warn("message match count failed", "query", opts.Query, "error", err)
+		}
 	}
 
 	// Session summaries. Soft-fail: a sessions_fts query error
@@ -183,6 +193,11 @@ const sessionsFTSTable = "sessions_fts"
 // signal-density.
 const workingMemoryFTSTable = "working_memory_fts"
 
+// taggedWorkingMemoryFTSTable is the FTS5 virtual table name covering
+// working_memory_tagged.content, the tag-scoped entries that live
+// beside the one untagged row per conversation.
+const taggedWorkingMemoryFTSTable = "working_memory_tagged_fts"
+
 // trySetupSessionsFTS creates the sessions_fts virtual table, the
 // AI/AD/AU sync triggers, and backfills any rows that exist in
 // sessions but not yet in sessions_fts. Returns true on success.
@@ -410,15 +425,16 @@ func (s *ArchiveStore) SearchSessions(query string, limit int) ([]SessionMatch,
 	return out, nil
 }
 
-// trySetupWorkingMemoryFTS creates working_memory_fts, its sync
-// triggers, and backfills existing rows. Same shape as
-// [ArchiveStore.trySetupSessionsFTS] but for the per-conversation
-// living-distillation table.
+// trySetupWorkingMemoryFTS creates ftsTable as an external-content
+// index over source's content column, its sync triggers, and
+// backfills existing rows. Same shape as
+// [ArchiveStore.trySetupSessionsFTS] but for the working-memory tables:
+// working_memory and working_memory_tagged each get their own index.
 //
 // Called from [WorkingMemoryStore]'s migration path; takes the shared
 // FTS5-availability gate as an argument so the working-memory store
 // doesn't need to re-probe FTS5 separately.
-func trySetupWorkingMemoryFTS(db *sql.DB, ftsEnabled bool) bool {
+func trySetupWorkingMemoryFTS(db *sql.DB, ftsEnabled bool, ftsTable, source string) bool {
 	if !ftsEnabled || db == nil {
 		return false
 	}
@@ -426,25 +442,25 @@ func trySetupWorkingMemoryFTS(db *sql.DB, ftsEnabled bool) bool {
 		fmt.Sprintf(`
 			CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(
 				content,
-				content=working_memory, content_rowid=rowid
+				content=%s, content_rowid=rowid
 			)
-		`, workingMemoryFTSTable),
+		`, ftsTable, source),
 		fmt.Sprintf(`
-			CREATE TRIGGER IF NOT EXISTS working_memory_fts_ai AFTER INSERT ON working_memory BEGIN
+			CREATE TRIGGER IF NOT EXISTS %s_ai AFTER INSERT ON %s BEGIN
 				INSERT INTO %s(rowid, content) VALUES (new.rowid, new.content);
 			END
-		`, workingMemoryFTSTable),
+		`, ftsTable, source, ftsTable),
 		fmt.Sprintf(`
-			CREATE TRIGGER IF NOT EXISTS working_memory_fts_ad AFTER DELETE ON working_memory BEGIN
+			CREATE TRIGGER IF NOT EXISTS %s_ad AFTER DELETE ON %s BEGIN
 				INSERT INTO %s(%s, rowid, content) VALUES ('delete', old.rowid, old.content);
 			END
-		`, workingMemoryFTSTable, workingMemoryFTSTable),
+		`, ftsTable, source, ftsTable, ftsTable),
 		fmt.Sprintf(`
-			CREATE TRIGGER IF NOT EXISTS working_memory_fts_au AFTER UPDATE ON working_memory BEGIN
+			CREATE TRIGGER IF NOT EXISTS %s_au AFTER UPDATE ON %s BEGIN
 				INSERT INTO %s(%s, rowid, content) VALUES ('delete', old.rowid, old.content);
 				INSERT INTO %s(rowid, content) VALUES (new.rowid, new.content);
 			END
-		`, workingMemoryFTSTable, workingMemoryFTSTable, workingMemoryFTSTable),
+		`, ftsTable, source, ftsTable, ftsTable, ftsTable),
 	}
 	for _, stmt := range stmts {
 		if _, err := db.Exec(stmt); err != nil {
@@ -457,11 +473,11 @@ func trySetupWorkingMemoryFTS(db *sql.DB, ftsEnabled bool) bool {
 	// so only `_docsize` is honest about whether the index has
 	// tokenized rows.
 	var docCount int
-	if err := db.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %s_docsize`, workingMemoryFTSTable)).Scan(&docCount); err != nil {
+	if err := db.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %s_docsize`, ftsTable)).Scan(&docCount); err != nil {
 		return true
 	}
 	if docCount == 0 {
-		_, _ = db.Exec(fmt.Sprintf(`INSERT INTO %s(%s) VALUES('rebuild')`, workingMemoryFTSTable, workingMemoryFTSTable))
+		_, _ = db.Exec(fmt.Sprintf(`INSERT INTO %s(%s) VALUES('rebuild')`, ftsTable, ftsTable))
 	}
 	return true
 }
@@ -470,10 +486,12 @@ func trySetupWorkingMemoryFTS(db *sql.DB, ftsEnabled bool) bool {
 // [WorkingMemoryStore.Search]. Working memory is keyed by
 // conversation_id, so the match identifies which conversation's
 // distillation matched and when it was last updated; the caller
-// can follow up with [WorkingMemoryStore.Get] to pull the full
-// content if the snippet looks promising.
+// can follow up with [WorkingMemoryStore.Get] (or
+// [WorkingMemoryStore.GetTagged] for a tag-scoped match) to pull the
+// full content if the snippet looks promising.
 type WorkingMemoryMatch struct {
 	ConversationID string    `json:"conversation_id"`
+	Tag            string    `json:"tag,omitempty"` // Empty for the untagged entry
 	UpdatedAt      time.Time `json:"updated_at"`
 	Content        string    `json:"content"`
 	Highlight      string    `json:"highlight"`
@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
@@ -227,6 +228,41 @@ func TestWorkingMemorySearch_UpdateReindexes(t *testing.T) {
 	}
 }
 
+// TestWorkingMemorySearch_IncludesTaggedEntries verifies tag-scoped
+// entries are indexed alongside the untagged row, report their tag,
+// and drop out of the index when the conversation is deleted.
+func TestWorkingMemorySearch_IncludesTaggedEntries(t *testing.T) {
+	store, _ := newTestWorkingMemoryStoreWithFTS(t)
+
+	if err := store.Set("conv-tagged", "general thread about weekend plans"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetTagged("conv-tagged", "ha", "the garage door sensor reports open when it is closed"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.Search("garage door", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ConversationID != "conv-tagged" || got[0].Tag != "ha" {
+		t.Fatalf("Search = %+v, want the ha-tagged entry", got)
+	}
+	if !strings.Contains(got[0].Highlight, "**garage door**") {
+		t.Errorf("highlight = %q, want the matched phrase marked", got[0].Highlight)
+	}
+	if got, _ := store.Search("weekend plans", 5); len(got) != 1 || got[0].Tag != "" {
+		t.Errorf("untagged Search = %+v, want the untagged entry", got)
+	}
+
+	if err := store.Delete("conv-tagged"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := store.Search("garage door", 5); len(got) != 0 {
+		t.Errorf("after delete, tagged entry still in FTS index: %+v", got)
+	}
+}
+
 // newTestWorkingMemoryStoreWithFTS spins up a backing archive (which
 // owns the FTS5-availability gate) and a working memory store on the
 // same connection. Returns both because tests need to check
@@ -320,7 +356,7 @@ func TestMemorySearch_AllSurfacesReachModelEnvelope(t *testing.T) {
 
 	// Unified search across all three surfaces.
 	searcher := NewMemorySearch(archive, working, nil)
-	bundle, err := searcher.Search(SearchOptions{Query: "freezer alarm", Limit: 5})
+	bundle, err := searcher.Search(context.Background(), SearchOptions{Query: "freezer alarm", Limit: 5})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -381,7 +417,7 @@ func TestMemorySearch_DistilledFailureDoesNotBlockMessages(t *testing.T) {
 	// working_memory available" branch. Sessions search is in the
 	// same DB, healthy.
 	searcher := NewMemorySearch(archive, nil, nil)
-	bundle, err := searcher.Search(SearchOptions{Query: "keeper", Limit: 5})
+	bundle, err := searcher.Search(context.Background(), SearchOptions{Query: "keeper", Limit: 5})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,236 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nugget/thane-ai-agent/internal/model/promptfmt"
+	"github.com/nugget/thane-ai-agent/internal/platform/paths"
+)
+
+// Daily-note search bounds. A matching paragraph longer than
+// dailyExcerptMaxRunes is cut to a window around its first match.
+const (
+	defaultDailySearchLimit = 10
+	maxDailySearchLimit     = 50
+	maxExcerptsPerDailyNote = 5
+	dailyExcerptMaxRunes    = 600
+	dailyNoteFileDateFormat = "2006-01-02"
+	dailyNoteFileExt        = ".md"
+)
+
+// DailyNoteSearch describes a keyword search over the daily notes.
+type DailyNoteSearch struct {
+	// Query holds the keywords. A paragraph matches when it contains
+	// every whitespace-separated term, case-insensitively.
+	Query string
+
+	// Since and Until bound the note dates, inclusive. Each is a bare
+	// date ("2026-03-01") or anything [promptfmt.ParseTimeOrDelta]
+	// accepts, read as a date in the provider's timezone. Empty leaves
+	// that side unbounded.
+	Since, Until string
+
+	// Limit caps the number of notes returned, newest first. Zero
+	// means the default of 10.
+	Limit int
+}
+
+// DailyNoteExcerpt is one matching paragraph of a daily note.
+type DailyNoteExcerpt struct {
+	// Line is the 1-based line where the paragraph starts.
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// DailyNoteMatch is a daily note with the paragraphs that matched.
+type DailyNoteMatch struct {
+	Date     string             `json:"date"`
+	Excerpts []DailyNoteExcerpt `json:"excerpts"`
+	// MoreExcerpts counts matching paragraphs beyond those returned.
+	MoreExcerpts int `json:"more_excerpts,omitempty"`
+}
+
+// DailyNoteResults is the outcome of [EpisodicProvider.SearchDailyNotes].
+type DailyNoteResults struct {
+	Matches []DailyNoteMatch `json:"matches"`
+	// NotesSearched counts the notes in the date range that were read.
+	NotesSearched int `json:"notes_searched"`
+	// Truncated is set when more notes matched than Limit allowed.
+	Truncated bool `json:"truncated,omitempty"`
+	// Unreadable lists notes in range that could not be read.
+	Unreadable []string `json:"unreadable,omitempty"`
+}
+
+// SearchDailyNotes searches the daily note files in the configured
+// daily directory, newest first, for paragraphs containing every
+// query term. Unlike [EpisodicProvider.TagContext] it is not limited
+// to the lookback window. A missing directory yields no matches; a
+// note that cannot be read is skipped and reported.
+func (p *EpisodicProvider) SearchDailyNotes(search DailyNoteSearch) (*DailyNoteResults, error) {
+	if p.dailyDir == "" {
+		return nil, fmt.Errorf("daily notes are not configured (episodic.daily_dir)")
+	}
+	terms := strings.Fields(strings.ToLower(search.Query))
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("query is required")
+	}
+	limit := search.Limit
+	if limit <= 0 {
+		limit = defaultDailySearchLimit
+	}
+	limit = min(limit, maxDailySearchLimit)
+
+	loc := p.loadLocation()
+	now := p.nowFunc().In(loc)
+	from, err := dailyNoteBound(search.Since, now)
+	if err != nil {
+		return nil, fmt.Errorf("since: %w", err)
+	}
+	to, err := dailyNoteBound(search.Until, now)
+	if err != nil {
+		return nil, fmt.Errorf("until: %w", err)
+	}
+
+	dir := paths.ExpandHome(p.dailyDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &DailyNoteResults{Matches: []DailyNoteMatch{}}, nil
+		}
+		return nil, fmt.Errorf("read daily notes directory: %w", err)
+	}
+
+	var dates []string
+	for _, e := range entries {
+		date, ok := strings.CutSuffix(e.Name(), dailyNoteFileExt)
+		if !ok || e.IsDir() {
+			continue
+		}
+		if _, err := time.Parse(dailyNoteFileDateFormat, date); err != nil {
+			continue
+		}
+		if (from != "" && date < from) || (to != "" && date > to) {
+			continue
+		}
+		dates = append(dates, date)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(dates)))
+
+	results := &DailyNoteResults{Matches: []DailyNoteMatch{}}
+	for _, date := range dates {
+		path := filepath.Join(dir, date+dailyNoteFileExt)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			p.logger.Warn("daily memory file unreadable", "path", path, "error", err)
+			results.Unreadable = append(results.Unreadable, date)
+			continue
+		}
+		results.NotesSearched++
+
+		excerpts := matchDailyParagraphs(string(data), terms)
+		if len(excerpts) == 0 {
+			continue
+		}
+		if len(results.Matches) == limit {
+			results.Truncated = true
+			break
+		}
+		match := DailyNoteMatch{Date: date, Excerpts: excerpts}
+		if len(excerpts) > maxExcerptsPerDailyNote {
+			match.Excerpts = excerpts[:maxExcerptsPerDailyNote]
+			match.MoreExcerpts = len(excerpts) - maxExcerptsPerDailyNote
+		}
+		results.Matches = append(results.Matches, match)
+	}
+	return results, nil
+}
+
+// dailyNoteBound resolves a search bound to a YYYY-MM-DD date in now's
+// location. Empty stays empty.
+func dailyNoteBound(raw string, now time.Time) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	if _, err := time.Parse(dailyNoteFileDateFormat, raw); err == nil {
+		return raw, nil
+	}
+	t, err := promptfmt.ParseTimeOrDelta(raw, now)
+	if err != nil {
+		return "", err
+	}
+	return t.In(now.Location()).Format(dailyNoteFileDateFormat), nil
+}
+
+// matchDailyParagraphs splits a note into blank-line separated
+// paragraphs and returns those containing every term, each trimmed to
+// [dailyExcerptMaxRunes] around its first match.
+func matchDailyParagraphs(content string, terms []string) []DailyNoteExcerpt {
+	var out []DailyNoteExcerpt
+	var para []string
+	start := 0
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+		text := strings.Join(para, "\n")
+		lower := strings.ToLower(text)
+		first := -1
+		for _, term := range terms {
+			i := strings.Index(lower, term)
+			if i < 0 {
+				para = nil
+				return
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+		out = append(out, DailyNoteExcerpt{Line: start, Text: excerptAround(text, first)})
+		para = nil
+	}
+	for i, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if len(para) == 0 {
+			start = i + 1
+		}
+		para = append(para, line)
+	}
+	flush()
+	return out
+}
+
+// excerptAround returns text, or when it exceeds
+// [dailyExcerptMaxRunes] a window of that size starting a little
+// before byte offset at, with ellipses marking the cuts. The window is
+// cut on rune boundaries.
+func excerptAround(text string, at int) string {
+	runes := []rune(text)
+	if len(runes) <= dailyExcerptMaxRunes {
+		return text
+	}
+	pos := utf8.RuneCountInString(text[:min(at, len(text))])
+	begin := max(0, pos-dailyExcerptMaxRunes/4)
+	end := min(len(runes), begin+dailyExcerptMaxRunes)
+	begin = max(0, end-dailyExcerptMaxRunes)
+
+	excerpt := strings.TrimSpace(string(runes[begin:end]))
+	if begin > 0 {
+		excerpt = "…" + excerpt
+	}
+	if end < len(runes) {
+		excerpt += "…"
+	}
+	return excerpt
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newDailySearchProvider(t *testing.T, notes map[string]string) (*EpisodicProvider, string) {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range notes {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p := NewEpisodicProvider(nil, slog.Default(), EpisodicConfig{
+		Timezone:     "UTC",
+		DailyDir:     dir,
+		LookbackDays: 1,
+	})
+	p.nowFunc = func() time.Time { return time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC) }
+	return p, dir
+}
+
+func TestSearchDailyNotes(t *testing.T) {
+	p, _ := newDailySearchProvider(t, map[string]string{
+		"2026-04-12.md": "# Sunday\n\nPlanted tomatoes.\n\nDecided the Garden beds\nget drip irrigation.",
+		"2026-05-03.md": "Garden: moved the herbs.\n\nNothing decided.",
+		"2026-09-30.md": "Garden decided to skip fall planting.",
+		"notes.md":      "garden decided, but not a daily note",
+		"2026-13-01.md": "garden decided, but not a date",
+	})
+
+	res, err := p.SearchDailyNotes(DailyNoteSearch{Query: "garden DECIDED"})
+	if err != nil {
+		t.Fatalf("SearchDailyNotes: %v", err)
+	}
+	if res.NotesSearched != 3 {
+		t.Errorf("NotesSearched = %d, want 3 dated notes", res.NotesSearched)
+	}
+	if len(res.Matches) != 2 || res.Matches[0].Date != "2026-09-30" || res.Matches[1].Date != "2026-04-12" {
+		t.Fatalf("matches = %+v, want the two notes with both words in one paragraph, newest first", res.Matches)
+	}
+	ex := res.Matches[1].Excerpts
+	if len(ex) != 1 || ex[0].Line != 5 || ex[0].Text != "Decided the Garden beds\nget drip irrigation." {
+		t.Errorf("excerpts = %+v, want the whole matching paragraph from line 5", ex)
+	}
+
+	// Date range: a bare date and a delta, both inclusive.
+	res, err = p.SearchDailyNotes(DailyNoteSearch{Query: "garden", Since: "2026-04-12", Until: "-150d"})
+	if err != nil {
+		t.Fatalf("ranged search: %v", err)
+	}
+	if len(res.Matches) != 2 || res.Matches[0].Date != "2026-05-03" || res.Matches[1].Date != "2026-04-12" {
+		t.Errorf("ranged matches = %+v, want spring notes only", res.Matches)
+	}
+
+	res, err = p.SearchDailyNotes(DailyNoteSearch{Query: "garden", Limit: 1})
+	if err != nil {
+		t.Fatalf("limited search: %v", err)
+	}
+	if len(res.Matches) != 1 || !res.Truncated {
+		t.Errorf("limited result = %+v, want one match flagged truncated", res)
+	}
+}
+
+func TestSearchDailyNotes_Errors(t *testing.T) {
+	p, dir := newDailySearchProvider(t, map[string]string{"2026-04-12.md": "garden"})
+
+	if _, err := p.SearchDailyNotes(DailyNoteSearch{Query: "  "}); err == nil {
+		t.Error("empty query should error")
+	}
+	if _, err := p.SearchDailyNotes(DailyNoteSearch{Query: "garden", Since: "last spring"}); err == nil || !strings.Contains(err.Error(), "since") {
+		t.Errorf("bad bound error = %v, want it named", err)
+	}
+
+	unconfigured := NewEpisodicProvider(nil, slog.Default(), EpisodicConfig{})
+	if _, err := unconfigured.SearchDailyNotes(DailyNoteSearch{Query: "garden"}); err == nil || !strings.Contains(err.Error(), "daily_dir") {
+		t.Errorf("unconfigured error = %v, want config teaching", err)
+	}
+
+	// A missing directory is an empty result, not an error.
+	missing := NewEpisodicProvider(nil, slog.Default(), EpisodicConfig{DailyDir: filepath.Join(dir, "absent")})
+	res, err := missing.SearchDailyNotes(DailyNoteSearch{Query: "garden"})
+	if err != nil || len(res.Matches) != 0 {
+		t.Errorf("missing dir = %+v, %v; want empty result", res, err)
+	}
+}
+
+func TestExcerptAround(t *testing.T) {
+	long := strings.Repeat("a", 1000) + "garden" + strings.Repeat("b", 1000)
+	got := excerptAround(long, 1000)
+	if !strings.Contains(got, "garden") || !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("excerpt should window the match with ellipses, got %d runes", len([]rune(got)))
+	}
+	if n := len([]rune(got)); n > dailyExcerptMaxRunes+2 {
+		t.Errorf("excerpt is %d runes, want at most %d plus ellipses", n, dailyExcerptMaxRunes)
+	}
+}
@@ -282,10 +282,12 @@ type SessionMatchView struct {
 
 // WorkingMemoryMatchView is the JSON-facing projection of a
 // working_memory_fts hit. Working memory is one row per conversation
-// — the conversation_id identifies which thread's living
-// distillation matched, and content carries the full snapshot.
+// plus any tag-scoped entries — the conversation_id (and tag, for a
+// tag-scoped entry) identifies which thread's living distillation
+// matched, and content carries the full snapshot.
 type WorkingMemoryMatchView struct {
 	ConversationID string `json:"conversation_id"`
+	Tag            string `json:"tag,omitempty"`
 	Updated        string `json:"updated"`
 	Content        string `json:"content"`
 	Highlight      string `json:"highlight,omitempty"`
@@ -332,6 +334,7 @@ func FormatMultiKindResults(b *SearchBundle, now time.Time, truncated bool) []by
 	for _, w := range b.WorkingMemory {
 		wmViews = append(wmViews, WorkingMemoryMatchView{
 			ConversationID: w.ConversationID,
+			Tag:            w.Tag,
 			Updated:        promptfmt.FormatDeltaOnly(w.UpdatedAt, now),
 			Content:        w.Content,
 			Highlight:      w.Highlight,
@@ -443,6 +446,26 @@ func dropSenderName(metadata map[string]any) {
 	}
 }
 
+// Kind labels that open the metadata header of a rendered stored
+// history message, for callers that must recognize one afterwards.
+const (
+	StoredMemoryNoteKind = "stored conversation memory note"
+	StoredToolResultKind = "stored historical tool result"
+)
+
+// IsStoredCompactionSummary reports whether content is a compaction
+// summary rendered by [FormatStoredHistoryMessage].
+func IsStoredCompactionSummary(content string) bool {
+	return strings.HasPrefix(content, "["+StoredMemoryNoteKind) &&
+		strings.Contains(content, "<conversation_message>\n"+CompactionSummaryPrefix)
+}
+
+// IsStoredToolResult reports whether content is a tool result rendered
+// by [FormatStoredHistoryMessage].
+func IsStoredToolResult(content string) bool {
+	return strings.HasPrefix(content, "["+StoredToolResultKind)
+}
+
 // StoredHistoryMessage is a provider-neutral message entry built from
 // stored conversation history. Role is the provider role to use in
 // messages[]. Content is the model-facing body for that role.
@@ -468,14 +491,14 @@ func FormatStoredHistoryMessage(m Message, now time.Time) (StoredHistoryMessage,
 		// Native provider role carries the speaker identity.
 	case "system":
 		providerRole = "assistant"
-		kind = "stored conversation memory note"
+		kind = StoredMemoryNoteKind
 		metadata = append(metadata,
 			metadataPart{key: "original_role", value: "system"},
 			metadataPart{key: "not_active_instruction", value: "true"},
 		)
 	case "tool":
 		providerRole = "assistant"
-		kind = "stored historical tool result"
+		kind = StoredToolResultKind
 		metadata = append(metadata,
 			metadataPart{key: "original_role", value: "tool"},
 			metadataPart{key: "context_only", value: "true"},
@@ -0,0 +1,253 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OpenAIMessage is one message in the OpenAI chat-completions format,
+// the shape [ExportOpenAIMessages] produces for handing a conversation
+// to other tooling. Content is null on assistant turns that only
+// carried tool calls, as in the OpenAI API.
+type OpenAIMessage struct {
+	Role       string           `json:"role"`
+	Content    *string          `json:"content"`
+	ToolCalls  []OpenAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+// OpenAIToolCall is a function call requested by an assistant message.
+type OpenAIToolCall struct {
+	ID       string             `json:"id"`
+	Type     string             `json:"type"` // always "function"
+	Function OpenAIFunctionCall `json:"function"`
+}
+
+// OpenAIFunctionCall names the called tool. Arguments is the call's
+// JSON arguments as a string, as in the OpenAI API.
+type OpenAIFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ExportOpenAIMessages renders a transcript and its tool call records
+// as OpenAI chat-completions messages, in chronological order. Tool
+// calls come from the records: each run of calls between two messages
+// (split by iteration when the records carry one) becomes an assistant
+// message with tool_calls, followed by one tool message per call.
+// Tool-call-only assistant turns in the transcript are dropped in
+// favor of the records, and tool messages answering a recorded call
+// supply that call's result.
+func ExportOpenAIMessages(messages []Message, calls []ArchivedToolCall) []OpenAIMessage {
+	calls = append([]ArchivedToolCall(nil), calls...)
+	sort.SliceStable(calls, func(i, j int) bool { return calls[i].StartedAt.Before(calls[j].StartedAt) })
+
+	recorded := make(map[string]bool, len(calls))
+	for _, tc := range calls {
+		recorded[tc.ID] = true
+	}
+	// What the model actually saw as each recorded call's result.
+	results := make(map[string]string)
+	for _, m := range messages {
+		if m.Role == "tool" && recorded[m.ToolCallID] {
+			results[m.ToolCallID] = m.Content
+		}
+	}
+
+	out := make([]OpenAIMessage, 0, len(messages)+2*len(calls))
+	next := 0
+	flushCalls := func(m *Message) {
+		for next < len(calls) && (m == nil || calls[next].StartedAt.Before(m.Timestamp)) {
+			end := next + 1
+			for end < len(calls) && sameIteration(calls[next], calls[end]) &&
+				(m == nil || calls[end].StartedAt.Before(m.Timestamp)) {
+				end++
+			}
+			out = append(out, openAIToolTurn(calls[next:end], results)...)
+			next = end
+		}
+	}
+
+	for i := range messages {
+		m := &messages[i]
+		flushCalls(m)
+		switch {
+		case m.Role == "tool" && recorded[m.ToolCallID]:
+			continue
+		case m.Role == "assistant" && strings.TrimSpace(m.Content) == "":
+			continue
+		}
+		content := m.Content
+		out = append(out, OpenAIMessage{Role: m.Role, Content: &content, ToolCallID: m.ToolCallID})
+	}
+	flushCalls(nil)
+	return out
+}
+
+// sameIteration reports whether two adjacent tool call records belong
+// in one assistant turn. Records without an iteration index (working
+// memory does not track one) group with their neighbors.
+func sameIteration(a, b ArchivedToolCall) bool {
+	if a.IterationIndex == nil || b.IterationIndex == nil {
+		return true
+	}
+	return *a.IterationIndex == *b.IterationIndex
+}
+
+// openAIToolTurn renders one batch of tool calls as an assistant
+// message followed by the calls' tool results.
+func openAIToolTurn(calls []ArchivedToolCall, results map[string]string) []OpenAIMessage {
+	assistant := OpenAIMessage{Role: "assistant", ToolCalls: make([]OpenAIToolCall, 0, len(calls))}
+	replies := make([]OpenAIMessage, 0, len(calls))
+	for _, tc := range calls {
+		args := tc.Arguments
+		if strings.TrimSpace(args) == "" {
+			args = "{}"
+		}
+		assistant.ToolCalls = append(assistant.ToolCalls, OpenAIToolCall{
+			ID:       tc.ID,
+			Type:     "function",
+			Function: OpenAIFunctionCall{Name: tc.ToolName, Arguments: args},
+		})
+
+		content, ok := results[tc.ID]
+		if !ok {
+			content = tc.Result
+			if content == "" {
+				content = tc.Error
+			}
+		}
+		replies = append(replies, OpenAIMessage{Role: "tool", Content: &content, ToolCallID: tc.ID})
+	}
+	return append([]OpenAIMessage{assistant}, replies...)
+}
+
+// WorkingToolCalls converts working-memory tool call records to the
+// archive shape [ExportOpenAIMessages] takes.
+func WorkingToolCalls(calls []ToolCall) []ArchivedToolCall {
+	out := make([]ArchivedToolCall, len(calls))
+	for i, tc := range calls {
+		out[i] = ArchivedToolCall{
+			ID:             tc.ID,
+			ConversationID: tc.ConversationID,
+			ToolName:       tc.ToolName,
+			Arguments:      tc.Arguments,
+			Result:         tc.Result,
+			Error:          tc.Error,
+			StartedAt:      tc.StartedAt,
+			CompletedAt:    tc.CompletedAt,
+			DurationMs:     tc.DurationMs,
+		}
+	}
+	return out
+}
+
+// ExportSessionOpenAI exports a session transcript, tool calls
+// included, as OpenAI chat-completions messages.
+func (s *ArchiveStore) ExportSessionOpenAI(sessionID string) ([]OpenAIMessage, error) {
+	sess, err := s.GetSession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+	if sess == nil {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	messages, err := s.GetSessionTranscript(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get transcript: %w", err)
+	}
+	calls, err := s.GetSessionToolCalls(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get tool calls: %w", err)
+	}
+	return ExportOpenAIMessages(messages, calls), nil
+}
+
+// ExportConversationOpenAI exports every archived session of a
+// conversation as one OpenAI message list, oldest session first.
+func (s *ArchiveStore) ExportConversationOpenAI(conversationID string) ([]OpenAIMessage, error) {
+	ids, err := s.conversationSessionIDs(conversationID)
+	if err != nil {
+		return nil, err
+	}
+	var out []OpenAIMessage
+	for _, id := range ids {
+		msgs, err := s.ExportSessionOpenAI(id)
+		if err != nil {
+			return nil, fmt.Errorf("export session %s: %w", ShortID(id), err)
+		}
+		out = append(out, msgs...)
+	}
+	return out, nil
+}
+
+// ConversationHistory returns the transcripts and tool calls of every
+// archived session of a conversation, oldest session first. A
+// conversation with no sessions yields nothing and no error.
+func (s *ArchiveStore) ConversationHistory(conversationID string) ([]Message, []ArchivedToolCall, error) {
+	sessions, err := s.ListSessions(conversationID, 1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list sessions: %w", err)
+	}
+	if len(sessions) == 0 {
+		return nil, nil, nil
+	}
+	ids, err := s.conversationSessionIDs(conversationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		messages []Message
+		calls    []ArchivedToolCall
+	)
+	for _, id := range ids {
+		transcript, err := s.GetSessionTranscript(id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("session %s: %w", ShortID(id), err)
+		}
+		sessionCalls, err := s.GetSessionToolCalls(id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("session %s: %w", ShortID(id), err)
+		}
+		messages = append(messages, transcript...)
+		calls = append(calls, sessionCalls...)
+	}
+	return messages, calls, nil
+}
+
+// ExportHistoryOpenAI renders a conversation's full history as OpenAI
+// chat-completions messages: its archived sessions, oldest first, then
+// the working-memory tail not yet archived. Rows present in both (the
+// unified store stamps live rows with their session as they are
+// written) appear once. Compaction summaries are dropped, since the
+// turns they replace are exported in full.
+func ExportHistoryOpenAI(archived, tail []Message, archivedCalls, tailCalls []ArchivedToolCall) []OpenAIMessage {
+	seen := make(map[string]bool, len(archived)+len(tail))
+	messages := make([]Message, 0, len(archived)+len(tail))
+	for _, part := range [][]Message{archived, tail} {
+		for _, m := range part {
+			if seen[m.ID] {
+				continue
+			}
+			seen[m.ID] = true
+			if m.Role == "system" && strings.HasPrefix(m.Content, CompactionSummaryPrefix) {
+				continue
+			}
+			messages = append(messages, m)
+		}
+	}
+
+	seenCalls := make(map[string]bool, len(archivedCalls)+len(tailCalls))
+	calls := make([]ArchivedToolCall, 0, len(archivedCalls)+len(tailCalls))
+	for _, part := range [][]ArchivedToolCall{archivedCalls, tailCalls} {
+		for _, tc := range part {
+			if seenCalls[tc.ID] {
+				continue
+			}
+			seenCalls[tc.ID] = true
+			calls = append(calls, tc)
+		}
+	}
+	return ExportOpenAIMessages(messages, calls)
+}
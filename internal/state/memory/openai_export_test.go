@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportOpenAIMessages(t *testing.T) {
+	base := time.Date(2026, 2, 12, 10, 0, 0, 0, time.UTC)
+	iter := func(n int) *int { return &n }
+	messages := []Message{
+		{Role: "user", Content: "is the porch light on?", Timestamp: base},
+		// Tool-call-only turn and its reply: replaced by the records.
+		{Role: "assistant", ToolCalls: `[{"id":"call-1"}]`, Timestamp: base.Add(time.Second)},
+		{Role: "tool", ToolCallID: "call-1", Content: `{"state":"on"}`, Timestamp: base.Add(2 * time.Second)},
+		{Role: "assistant", Content: "Yes, and the door is locked.", Timestamp: base.Add(5 * time.Second)},
+	}
+	calls := []ArchivedToolCall{
+		{ID: "call-3", ToolName: "get_state", Arguments: `{"entity_id":"lock.front"}`,
+			Result: `{"state":"locked"}`, StartedAt: base.Add(3 * time.Second), IterationIndex: iter(1)},
+		{ID: "call-1", ToolName: "get_state", Arguments: `{"entity_id":"light.porch"}`,
+			StartedAt: base.Add(time.Second), IterationIndex: iter(0)},
+		{ID: "call-2", ToolName: "list_areas", Error: "[error:transient] timed out",
+			StartedAt: base.Add(time.Second), IterationIndex: iter(0)},
+	}
+
+	got, err := json.Marshal(ExportOpenAIMessages(messages, calls))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[` +
+		`{"role":"user","content":"is the porch light on?"},` +
+		`{"role":"assistant","content":null,"tool_calls":[` +
+		`{"id":"call-1","type":"function","function":{"name":"get_state","arguments":"{\"entity_id\":\"light.porch\"}"}},` +
+		`{"id":"call-2","type":"function","function":{"name":"list_areas","arguments":"{}"}}]},` +
+		`{"role":"tool","content":"{\"state\":\"on\"}","tool_call_id":"call-1"},` +
+		`{"role":"tool","content":"[error:transient] timed out","tool_call_id":"call-2"},` +
+		`{"role":"assistant","content":null,"tool_calls":[` +
+		`{"id":"call-3","type":"function","function":{"name":"get_state","arguments":"{\"entity_id\":\"lock.front\"}"}}]},` +
+		`{"role":"tool","content":"{\"state\":\"locked\"}","tool_call_id":"call-3"},` +
+		`{"role":"assistant","content":"Yes, and the door is locked."}` +
+		`]`
+	if string(got) != want {
+		t.Errorf("export =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestExportConversationOpenAI(t *testing.T) {
+	store := newTestArchiveStore(t)
+	base := time.Date(2026, 2, 12, 10, 0, 0, 0, time.UTC)
+
+	for i, text := range []string{"first", "second"} {
+		sess, err := store.StartSessionAt("conv-1", base.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.ArchiveMessages([]Message{{
+			ID: "m" + text, ConversationID: "conv-1", SessionID: sess.ID, Role: "user",
+			Content: text, Timestamp: base.Add(time.Duration(i) * time.Hour), ArchiveReason: "manual",
+		}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	msgs, err := store.ExportConversationOpenAI("conv-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 || *msgs[0].Content != "first" || *msgs[1].Content != "second" {
+		t.Errorf("messages = %+v, want first then second", msgs)
+	}
+
+	if _, err := store.ExportConversationOpenAI("conv-missing"); err == nil {
+		t.Error("export of a conversation with no sessions should fail")
+	}
+}
+
+func TestExportHistoryOpenAI(t *testing.T) {
+	base := time.Date(2026, 2, 12, 10, 0, 0, 0, time.UTC)
+	archived := []Message{
+		{ID: "m1", Role: "user", Content: "from last week", Timestamp: base},
+	}
+	tail := []Message{
+		// The unified store stamps live rows with their session, so
+		// the archive can hand back a tail row too.
+		{ID: "m1", Role: "user", Content: "from last week", Timestamp: base},
+		{ID: "m2", Role: "user", Content: "compacted turn", Timestamp: base.Add(time.Hour)},
+		{ID: "m3", Role: "system", Content: CompactionSummaryPrefix + "\nthey asked about a turn", Timestamp: base.Add(2 * time.Hour)},
+		{ID: "m4", Role: "user", Content: "live turn", Timestamp: base.Add(3 * time.Hour)},
+	}
+	calls := []ArchivedToolCall{{ID: "call-1", ToolName: "get_state", Result: "on", StartedAt: base.Add(time.Minute)}}
+
+	msgs := ExportHistoryOpenAI(archived, tail, calls, calls)
+	var got []string
+	for _, m := range msgs {
+		if m.Content != nil {
+			got = append(got, m.Role+":"+*m.Content)
+		} else {
+			got = append(got, m.Role+":calls")
+		}
+	}
+	want := []string{"user:from last week", "assistant:calls", "tool:on", "user:compacted turn", "user:live turn"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("export = %q, want %q", got, want)
+	}
+}
@@ -109,6 +109,8 @@ var schema = database.Schema{
 		// Lifecycle columns added by storage unification (#434). New
 		// databases get these from the CREATE TABLE above; existing
 		// pre-unification databases get them via these idempotent adds.
+		database.ColumnAdd{Table: "conversations", Column: "title", Typedef: "TEXT"},
+		database.ColumnAdd{Table: "conversations", Column: "title_updated_at", Typedef: "TIMESTAMP"},
 		database.ColumnAdd{Table: "messages", Column: "session_id", Typedef: "TEXT"},
 		database.ColumnAdd{Table: "messages", Column: "status", Typedef: "TEXT DEFAULT 'active' CHECK (status IN ('active', 'compacted', 'archived'))"},
 		database.ColumnAdd{Table: "messages", Column: "archived_at", Typedef: "TIMESTAMP"},
@@ -119,5 +121,10 @@ var schema = database.Schema{
 		database.ColumnAdd{Table: "tool_calls", Column: "status", Typedef: "TEXT DEFAULT 'active' CHECK (status IN ('active', 'archived'))"},
 		database.ColumnAdd{Table: "tool_calls", Column: "archived_at", Typedef: "TIMESTAMP"},
 		database.ColumnAdd{Table: "tool_calls", Column: "iteration_index", Typedef: "INTEGER"},
+		// Compaction markers, bumped in the same transaction that applies
+		// a compaction. Conversations compacted before these columns
+		// existed start from zero.
+		database.ColumnAdd{Table: "conversations", Column: "compaction_count", Typedef: "INTEGER DEFAULT 0"},
+		database.ColumnAdd{Table: "conversations", Column: "last_compacted_at", Typedef: "TIMESTAMP"},
 	},
 }
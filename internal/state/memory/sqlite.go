@@ -2,6 +2,7 @@ package memory
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -26,6 +27,12 @@ type SQLiteStore struct {
 	// an unthrottled warning would spam. Guarded by clipWarnMu.
 	clipWarnMu sync.Mutex
 	clipWarnAt map[string]time.Time
+
+	// sessionFor returns the session new messages and tool calls of a
+	// conversation are stamped with as they are stored. Nil leaves
+	// session_id NULL until the conversation is archived. Set once at
+	// startup via SetSessionResolver.
+	sessionFor func(conversationID string) string
 }
 
 // NewSQLiteStore creates a new SQLite-backed store.
@@ -48,7 +55,32 @@ func NewSQLiteStoreWithLogger(dbPath string, maxMessages int, logger *slog.Logge
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
+	return newSQLiteStore(db, maxMessages, logger)
+}
+
+// NewInMemorySQLiteStore creates a store backed by a private in-memory
+// SQLite database. Nothing survives a restart; it exists so the server
+// can keep running when thane.db cannot be opened. The returned
+// store's [SQLiteStore.DB] is a normal connection other stores can
+// share, as with the file-backed store.
+func NewInMemorySQLiteStore(maxMessages int, logger *slog.Logger) (*SQLiteStore, error) {
+	if maxMessages <= 0 {
+		maxMessages = 100
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
 
+	db, err := database.OpenMemory()
+	if err != nil {
+		return nil, fmt.Errorf("open in-memory database: %w", err)
+	}
+	return newSQLiteStore(db, maxMessages, logger)
+}
+
+// newSQLiteStore wraps an open database and migrates it, closing db on
+// failure.
+func newSQLiteStore(db *sql.DB, maxMessages int, logger *slog.Logger) (*SQLiteStore, error) {
 	store := &SQLiteStore{
 		db:          db,
 		maxMessages: maxMessages,
@@ -126,6 +158,29 @@ func (s *SQLiteStore) GetOrCreateConversation(id string) (*Conversation, error)
 	return &conv, nil
 }
 
+// SetSessionResolver enables incremental archiving: every message and
+// tool call is stamped with the session fn returns for its
+// conversation at insert time, instead of when the session is archived
+// on compaction, reset, or shutdown. A crash mid-session then leaves
+// the session's transcript complete for crash recovery and the
+// summarizer. fn returning "" leaves the row unstamped, as does a nil
+// fn. Call before the store is in use.
+func (s *SQLiteStore) SetSessionResolver(fn func(conversationID string) string) {
+	s.sessionFor = fn
+}
+
+// sessionID returns the session to stamp on a new row of
+// conversationID, or nil (NULL) when there is none.
+func (s *SQLiteStore) sessionID(conversationID string) any {
+	if s.sessionFor == nil {
+		return nil
+	}
+	if id := s.sessionFor(conversationID); id != "" {
+		return id
+	}
+	return nil
+}
+
 // AddMessage adds a message to a conversation.
 func (s *SQLiteStore) AddMessage(conversationID, role, content string) error {
 	return s.addMessage(conversationID, role, content, false)
@@ -159,9 +214,9 @@ func (s *SQLiteStore) addMessage(conversationID, role, content string, midTurn b
 
 	// Insert message
 	_, err = s.db.Exec(`
-		INSERT INTO messages (id, conversation_id, role, content, timestamp, token_count, mid_turn)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`, msgID.String(), conversationID, role, content, now, llm.EstimateTokens(content), midTurnVal)
+		INSERT INTO messages (id, conversation_id, session_id, role, content, timestamp, token_count, mid_turn)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, msgID.String(), conversationID, s.sessionID(conversationID), role, content, now, llm.EstimateTokens(content), midTurnVal)
 	if err != nil {
 		return fmt.Errorf("insert message: %w", err)
 	}
@@ -198,15 +253,15 @@ func (s *SQLiteStore) addMessage(conversationID, role, content string, midTurn b
 func (s *SQLiteStore) GetMessages(conversationID string) []Message {
 	rows, err := s.db.Query(`
 		WITH recent AS (
-			SELECT id, role, content, timestamp, COALESCE(mid_turn, 0) AS mid_turn
+			SELECT id, role, content, timestamp, tool_calls, tool_call_id, COALESCE(mid_turn, 0) AS mid_turn
 			FROM messages
 			WHERE conversation_id = ? AND status = 'active'
 			ORDER BY timestamp DESC, id DESC
 			LIMIT ?
 		)
-		SELECT id, role, content, timestamp, mid_turn FROM recent
+		SELECT id, role, content, timestamp, tool_calls, tool_call_id, mid_turn FROM recent
 		UNION
-		SELECT id, role, content, timestamp, COALESCE(mid_turn, 0)
+		SELECT id, role, content, timestamp, tool_calls, tool_call_id, COALESCE(mid_turn, 0)
 		FROM messages
 		WHERE conversation_id = ? AND status = 'active' AND role = 'system'
 		  AND content LIKE ? || '%'
@@ -220,10 +275,13 @@ func (s *SQLiteStore) GetMessages(conversationID string) []Message {
 	var messages []Message
 	for rows.Next() {
 		var m Message
+		var toolCalls, toolCallID sql.NullString
 		var midTurn int
-		if err := rows.Scan(&m.ID, &m.Role, &m.Content, &m.Timestamp, &midTurn); err != nil {
+		if err := rows.Scan(&m.ID, &m.Role, &m.Content, &m.Timestamp, &toolCalls, &toolCallID, &midTurn); err != nil {
 			continue
 		}
+		m.ToolCalls = toolCalls.String
+		m.ToolCallID = toolCallID.String
 		m.MidTurn = midTurn != 0
 		messages = append(messages, m)
 	}
@@ -324,6 +382,10 @@ func (s *SQLiteStore) Clear(conversationID string) error {
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	if err := deleteMessageEmbeddings(tx, "messages", "conversation_id = ?", conversationID); err != nil {
+		return err
+	}
+
 	_, err = tx.Exec(`DELETE FROM messages WHERE conversation_id = ?`, conversationID)
 	if err != nil {
 		return err
@@ -351,19 +413,29 @@ func (s *SQLiteStore) Clear(conversationID string) error {
 
 // Stats returns memory statistics.
 func (s *SQLiteStore) Stats() map[string]any {
-	var convCount, msgCount, tokenCount int
+	var convCount, msgCount, tokenCount, compactions int
+	var lastCompaction sql.NullString
 
 	_ = s.db.QueryRow(`SELECT COUNT(*) FROM conversations`).Scan(&convCount)
 	_ = s.db.QueryRow(`SELECT COUNT(*) FROM messages WHERE status = 'active'`).Scan(&msgCount)
 	_ = s.db.QueryRow(`SELECT COALESCE(SUM(token_count), 0) FROM messages WHERE status = 'active'`).Scan(&tokenCount)
 
-	return map[string]any{
+	_ = s.db.QueryRow(`SELECT COALESCE(SUM(compaction_count), 0), MAX(last_compacted_at) FROM conversations`).Scan(&compactions, &lastCompaction)
+
+	stats := map[string]any{
 		"conversations": convCount,
 		"messages":      msgCount,
 		"total_tokens":  tokenCount,
 		"max_per_conv":  s.maxMessages,
 		"storage":       "sqlite",
+		"compactions":   compactions,
+	}
+	if lastCompaction.Valid {
+		if t, err := database.ParseTimestamp(lastCompaction.String); err == nil {
+			stats["last_compaction"] = t
+		}
 	}
+	return stats
 }
 
 // GetAllConversations returns all conversations for checkpointing.
@@ -452,6 +524,39 @@ func (s *SQLiteStore) BindConversationChannel(conversationID string, binding *Ch
 	return s.PutConversationMetadata(conversationID, metadata)
 }
 
+// ConversationTitle returns the running title of a conversation, or ""
+// when it is untitled or does not exist.
+func (s *SQLiteStore) ConversationTitle(conversationID string) (string, error) {
+	var title sql.NullString
+	err := s.db.QueryRow(`SELECT title FROM conversations WHERE id = ?`, conversationID).Scan(&title)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get conversation title: %w", err)
+	}
+	return title.String, nil
+}
+
+// SetConversationTitle replaces the running title of a conversation,
+// creating the conversation row if needed. It leaves updated_at alone
+// so retitling does not reorder the conversation list.
+func (s *SQLiteStore) SetConversationTitle(conversationID, title string) error {
+	if _, err := s.GetOrCreateConversation(conversationID); err != nil {
+		return err
+	}
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	_, err := s.db.Exec(`
+		UPDATE conversations
+		SET title = ?, title_updated_at = ?
+		WHERE id = ?
+	`, title, now, conversationID)
+	if err != nil {
+		return fmt.Errorf("update conversation title: %w", err)
+	}
+	return nil
+}
+
 // GetAllMessages retrieves ALL messages for a conversation, including compacted ones.
 // Includes tool call data for full-fidelity archiving — never lose primary sources.
 func (s *SQLiteStore) GetAllMessages(conversationID string) []Message {
@@ -487,6 +592,41 @@ func (s *SQLiteStore) GetAllMessages(conversationID string) []Message {
 	return messages
 }
 
+// GetUnarchivedMessages returns the messages of a conversation that
+// have not been archived yet, compacted ones included, oldest first.
+func (s *SQLiteStore) GetUnarchivedMessages(conversationID string) []Message {
+	rows, err := s.db.Query(`
+		SELECT id, role, content, timestamp, tool_calls, tool_call_id, COALESCE(mid_turn, 0)
+		FROM messages
+		WHERE conversation_id = ? AND status IN ('active', 'compacted')
+		ORDER BY timestamp ASC
+	`, conversationID)
+	if err != nil {
+		return []Message{}
+	}
+	defer rows.Close()
+
+	var messages []Message
+	for rows.Next() {
+		var m Message
+		var toolCalls, toolCallID sql.NullString
+		var midTurn int
+		if err := rows.Scan(&m.ID, &m.Role, &m.Content, &m.Timestamp, &toolCalls, &toolCallID, &midTurn); err != nil {
+			continue
+		}
+		if toolCalls.Valid {
+			m.ToolCalls = toolCalls.String
+		}
+		if toolCallID.Valid {
+			m.ToolCallID = toolCallID.String
+		}
+		m.MidTurn = midTurn != 0
+		messages = append(messages, m)
+	}
+
+	return messages
+}
+
 // GetTokenCount returns the total token count for a conversation.
 func (s *SQLiteStore) GetTokenCount(conversationID string) int {
 	var count int
@@ -617,9 +757,44 @@ func (s *SQLiteStore) ApplyCompaction(conversationID string, compactedIDs []stri
 		return fmt.Errorf("insert summary: %w", err)
 	}
 
+	if _, err := tx.Exec(`
+		UPDATE conversations
+		SET compaction_count = COALESCE(compaction_count, 0) + 1, last_compacted_at = ?
+		WHERE id = ?
+	`, time.Now(), conversationID); err != nil {
+		return fmt.Errorf("record compaction: %w", err)
+	}
+
 	return tx.Commit()
 }
 
+// CompactionState returns how many times a conversation has been
+// compacted and when it last was. Both are zero for a conversation
+// never compacted (or unknown to the store).
+func (s *SQLiteStore) CompactionState(conversationID string) (CompactionState, error) {
+	var count int
+	var last sql.NullString
+	err := s.db.QueryRow(`
+		SELECT COALESCE(compaction_count, 0), last_compacted_at
+		FROM conversations WHERE id = ?
+	`, conversationID).Scan(&count, &last)
+	if errors.Is(err, sql.ErrNoRows) {
+		return CompactionState{}, nil
+	}
+	if err != nil {
+		return CompactionState{}, fmt.Errorf("query compaction state: %w", err)
+	}
+	state := CompactionState{Count: count}
+	if last.Valid {
+		t, err := database.ParseTimestamp(last.String)
+		if err != nil {
+			return CompactionState{}, fmt.Errorf("parse last_compacted_at: %w", err)
+		}
+		state.LastCompactedAt = t
+	}
+	return state, nil
+}
+
 // AddCompactionSummary adds a compaction summary message stamped now.
 // Used for session handoffs and other unpositioned system notes; the
 // compactor itself uses ApplyCompaction to place the summary at the
@@ -663,9 +838,9 @@ func (s *SQLiteStore) RecordToolCall(conversationID, messageID, toolCallID, tool
 	} // else nil (NULL)
 
 	_, err := s.db.Exec(`
-		INSERT INTO tool_calls (id, message_id, conversation_id, tool_name, arguments, started_at)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`, toolCallID, msgID, conversationID, toolName, arguments, now)
+		INSERT INTO tool_calls (id, message_id, conversation_id, session_id, tool_name, arguments, started_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, toolCallID, msgID, conversationID, s.sessionID(conversationID), toolName, arguments, now)
 
 	return err
 }
@@ -730,6 +905,33 @@ func (s *SQLiteStore) GetToolCalls(conversationID string, limit int) []ToolCall
 	}
 	defer rows.Close()
 
+	return scanToolCallRows(rows)
+}
+
+// GetToolCallsPage returns one page of a conversation's tool calls
+// that have not been archived yet, oldest first. Callers page through
+// them by advancing offset by limit until a short page comes back.
+func (s *SQLiteStore) GetToolCallsPage(conversationID string, offset, limit int) []ToolCall {
+	rows, err := s.db.Query(`
+		SELECT id, message_id, conversation_id, tool_name, arguments,
+		       result, error, started_at, completed_at, duration_ms
+		FROM tool_calls
+		WHERE conversation_id = ? AND status = 'active'
+		ORDER BY started_at ASC, id ASC
+		LIMIT ? OFFSET ?
+	`, conversationID, limit, offset)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	return scanToolCallRows(rows)
+}
+
+// scanToolCallRows reads tool call rows selected with the column list
+// shared by the working-memory tool call queries, skipping rows that
+// fail to scan.
+func scanToolCallRows(rows *sql.Rows) []ToolCall {
 	var calls []ToolCall
 	for rows.Next() {
 		var tc ToolCall
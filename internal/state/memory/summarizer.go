@@ -80,6 +80,10 @@ const maxTranscriptBytes = 8000
 // Parameters: conversationID, sessionID, endedAt, topics (tags).
 type InteractionCallback func(conversationID string, sessionID string, endedAt time.Time, topics []string)
 
+// UsageCallback records the token usage of a summarizer LLM call made
+// with model, tagged with the version of the prompt template it used.
+type UsageCallback func(ctx context.Context, model, promptVersion string, resp *llm.ChatResponse)
+
 // SummarizerWorker periodically scans for unsummarized sessions and
 // generates metadata (title, tags, summaries) using an LLM via the
 // model router.
@@ -100,6 +104,12 @@ type SummarizerWorker struct {
 	// metadata generation is unaffected either way.
 	archivistEnqueue func(ctx context.Context, sessionID, conversationID, reason string) error
 
+	// prompts resolves the metadata prompt; nil uses the built-in
+	// template. recordUsage, when set, persists each metadata call's
+	// token usage with the prompt version that produced it.
+	prompts     *prompts.Registry
+	recordUsage UsageCallback
+
 	cancel context.CancelFunc
 	done   chan struct{}
 }
@@ -132,6 +142,18 @@ func (w *SummarizerWorker) SetArchivistEnqueue(cb func(ctx context.Context, sess
 	w.archivistEnqueue = cb
 }
 
+// SetPrompts sets the registry the metadata prompt is resolved
+// through. Call before Start.
+func (w *SummarizerWorker) SetPrompts(r *prompts.Registry) {
+	w.prompts = r
+}
+
+// SetUsageRecorder registers a callback invoked after each metadata
+// call with its response. Call before Start.
+func (w *SummarizerWorker) SetUsageRecorder(cb UsageCallback) {
+	w.recordUsage = cb
+}
+
 // SetInteractionCallback registers a callback invoked after each
 // successful session summarization. The callback receives the
 // conversation ID, session ID, session end time, and LLM-generated
@@ -405,7 +427,7 @@ func (w *SummarizerWorker) summarizeSession(ctx context.Context, sess *Session)
 		toolUsage[tc.ToolName]++
 	}
 
-	prompt := prompts.MetadataPrompt(transcript)
+	prompt, promptVersion := w.prompts.MetadataPrompt(transcript)
 	msgs := []llm.Message{{Role: "user", Content: prompt}}
 
 	resp, err := w.llmClient.Chat(ctx, model, msgs, nil)
@@ -420,6 +442,9 @@ func (w *SummarizerWorker) summarizeSession(ctx context.Context, sess *Session)
 		// rate-limit before the next session.
 		return true
 	}
+	if w.recordUsage != nil {
+		w.recordUsage(ctx, model, promptVersion, resp)
+	}
 
 	meta, title, tags := parseMetadataResponse(resp.Message.Content, toolUsage, w.logger)
 
@@ -435,6 +460,7 @@ func (w *SummarizerWorker) summarizeSession(ctx context.Context, sess *Session)
 		"session", ShortID(sess.ID),
 		"title", title,
 		"model", model,
+		"prompt_version", promptVersion,
 		"tags", len(tags),
 	)
 
@@ -0,0 +1,223 @@
+package memory
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxTitleLen bounds a stored title. Model output past this is cut at a
+// word boundary so a rambling response cannot become a paragraph-long
+// list entry.
+const maxTitleLen = 80
+
+// maxTitledConversations bounds the per-conversation throttle state a
+// [Titler] keeps in memory. When exceeded, the least recently checked
+// conversation is forgotten; it is simply re-evaluated on its next turn.
+const maxTitledConversations = 1024
+
+// TitleResult is the structured JSON response from an LLM title call.
+// TopicShifted reports whether the conversation has moved on from the
+// current title; it is ignored for untitled conversations.
+type TitleResult struct {
+	Title        string `json:"title"`
+	TopicShifted bool   `json:"topic_shifted"`
+}
+
+// TitleFunc calls an LLM to title a conversation. It receives the
+// current title ("" when untitled) and the recent conversation history.
+type TitleFunc func(ctx context.Context, currentTitle string, recentHistory []Message) (*TitleResult, error)
+
+// TitleStore reads and persists running conversation titles.
+type TitleStore interface {
+	ConversationTitle(conversationID string) (string, error)
+	SetConversationTitle(conversationID, title string) error
+}
+
+// TitlerConfig holds the throttle settings for a [Titler].
+type TitlerConfig struct {
+	// CheckEvery is the number of turns between re-evaluations of a
+	// titled conversation. Zero or negative means every turn.
+	CheckEvery int
+
+	// MinInterval is the minimum time between title calls for one
+	// conversation, titled or not.
+	MinInterval time.Duration
+
+	// Timeout bounds a single title call.
+	Timeout time.Duration
+}
+
+// titleState is the throttle state for one conversation.
+type titleState struct {
+	turns     int       // turns since the last title call
+	lastCheck time.Time // when the last title call started
+	inFlight  bool      // a title call is running
+}
+
+// Titler maintains a running title for live conversations. A new
+// conversation is titled after its first turn; after that the title is
+// re-evaluated only every CheckEvery turns and at most once per
+// MinInterval, and replaced only when the model reports a topic shift.
+// Like [Extractor], it is best-effort — failures are logged and never
+// reach the user-facing response.
+type Titler struct {
+	store    TitleStore
+	generate TitleFunc
+	logger   *slog.Logger
+	cfg      TitlerConfig
+	now      func() time.Time
+
+	mu    sync.Mutex
+	convs map[string]*titleState
+}
+
+// NewTitler creates a Titler that persists titles via store.
+func NewTitler(store TitleStore, logger *slog.Logger, cfg TitlerConfig) *Titler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &Titler{
+		store:  store,
+		logger: logger,
+		cfg:    cfg,
+		now:    time.Now,
+		convs:  make(map[string]*titleState),
+	}
+}
+
+// SetTitleFunc configures the LLM title function.
+func (t *Titler) SetTitleFunc(fn TitleFunc) {
+	t.generate = fn
+}
+
+// Timeout returns the configured title call timeout.
+func (t *Titler) Timeout() time.Duration {
+	return t.cfg.Timeout
+}
+
+// Update records a completed turn in conversationID and, when the
+// throttle allows, asks the model for a title. recentHistory should
+// include the turn just completed. Returns the title now stored, which
+// is unchanged when no call was due or the topic has not shifted.
+func (t *Titler) Update(ctx context.Context, conversationID string, recentHistory []Message) (string, error) {
+	if t.generate == nil || conversationID == "" {
+		return "", nil
+	}
+
+	current, err := t.store.ConversationTitle(conversationID)
+	if err != nil {
+		return "", err
+	}
+	if !t.begin(conversationID, current == "") {
+		return current, nil
+	}
+	defer t.finish(conversationID)
+
+	result, err := t.generate(ctx, current, recentHistory)
+	if err != nil {
+		return current, err
+	}
+	title := cleanTitle(result.Title)
+	switch {
+	case title == "" || title == current:
+		return current, nil
+	case current != "" && !result.TopicShifted:
+		t.logger.Debug("conversation title kept: no topic shift",
+			"conversation_id", conversationID, "title", current)
+		return current, nil
+	}
+
+	if err := t.store.SetConversationTitle(conversationID, title); err != nil {
+		return current, err
+	}
+	t.logger.Info("conversation title updated",
+		"conversation_id", conversationID,
+		"previous", current,
+		"title", title)
+	return title, nil
+}
+
+// begin counts a turn and reports whether a title call is due, marking
+// the conversation in flight if so. An untitled conversation is due on
+// its first turn and then at most once per MinInterval until a title
+// sticks; a titled one waits for CheckEvery turns as well.
+func (t *Titler) begin(conversationID string, untitled bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st, ok := t.convs[conversationID]
+	if !ok {
+		t.evictLocked()
+		st = &titleState{}
+		t.convs[conversationID] = st
+	}
+	st.turns++
+	if st.inFlight {
+		return false
+	}
+
+	now := t.now()
+	intervalOK := st.lastCheck.IsZero() || now.Sub(st.lastCheck) >= t.cfg.MinInterval
+	due := intervalOK && (untitled || st.turns >= t.cfg.CheckEvery)
+	if !due {
+		return false
+	}
+	st.turns = 0
+	st.lastCheck = now
+	st.inFlight = true
+	return true
+}
+
+// finish clears the in-flight mark set by begin.
+func (t *Titler) finish(conversationID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if st, ok := t.convs[conversationID]; ok {
+		st.inFlight = false
+	}
+}
+
+// evictLocked drops the least recently checked idle conversation when
+// the throttle map is full. Must be called with t.mu held.
+func (t *Titler) evictLocked() {
+	if len(t.convs) < maxTitledConversations {
+		return
+	}
+	var oldestID string
+	var oldest time.Time
+	for id, st := range t.convs {
+		if st.inFlight {
+			continue
+		}
+		if oldestID == "" || st.lastCheck.Before(oldest) {
+			oldestID, oldest = id, st.lastCheck
+		}
+	}
+	if oldestID != "" {
+		delete(t.convs, oldestID)
+	}
+}
+
+// cleanTitle normalizes model output into a single-line title: quotes
+// and trailing punctuation stripped, whitespace collapsed, and length
+// capped at [maxTitleLen] runes.
+func cleanTitle(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	s = strings.Trim(s, "\"'`")
+	s = strings.TrimRight(s, ".!?:;, ")
+	runes := []rune(s)
+	if len(runes) <= maxTitleLen {
+		return s
+	}
+	cut := string(runes[:maxTitleLen])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, ".!?:;, ")
+}
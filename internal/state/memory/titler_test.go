@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scriptedTitles is a [TitleFunc] that returns the next queued result
+// and records how often it was called.
+type scriptedTitles struct {
+	calls   int
+	results []TitleResult
+}
+
+func (s *scriptedTitles) fn(_ context.Context, _ string, _ []Message) (*TitleResult, error) {
+	s.calls++
+	if len(s.results) == 0 {
+		return nil, errors.New("no scripted result")
+	}
+	r := s.results[0]
+	s.results = s.results[1:]
+	return &r, nil
+}
+
+// newTestTitler returns a Titler over a real store with a controllable
+// clock, re-checking every 3 turns and at most once per 5 minutes.
+func newTestTitler(t *testing.T, gen *scriptedTitles) (*Titler, *SQLiteStore, *time.Time) {
+	t.Helper()
+	store := newConvQueryStore(t, 100)
+	titler := NewTitler(store, nil, TitlerConfig{CheckEvery: 3, MinInterval: 5 * time.Minute})
+	titler.SetTitleFunc(gen.fn)
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	titler.now = func() time.Time { return now }
+	return titler, store, &now
+}
+
+func turn(t *testing.T, titler *Titler, convID string) string {
+	t.Helper()
+	title, err := titler.Update(context.Background(), convID, []Message{{Role: "user", Content: "hi"}})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	return title
+}
+
+func TestTitler_TitlesNewConversation(t *testing.T) {
+	gen := &scriptedTitles{results: []TitleResult{{Title: `"Kitchen lights schedule."`}}}
+	titler, store, _ := newTestTitler(t, gen)
+
+	if got := turn(t, titler, "conv-a"); got != "Kitchen lights schedule" {
+		t.Errorf("title = %q, want cleaned model title", got)
+	}
+	stored, err := store.ConversationTitle("conv-a")
+	if err != nil {
+		t.Fatalf("ConversationTitle: %v", err)
+	}
+	if stored != "Kitchen lights schedule" {
+		t.Errorf("stored title = %q", stored)
+	}
+}
+
+func TestTitler_NotCalledEveryTurn(t *testing.T) {
+	gen := &scriptedTitles{results: []TitleResult{
+		{Title: "Kitchen lights schedule"},
+		{Title: "Kitchen lighting plan", TopicShifted: false},
+	}}
+	titler, _, now := newTestTitler(t, gen)
+
+	turn(t, titler, "conv-a")
+	for range 5 {
+		*now = now.Add(30 * time.Second)
+		if got := turn(t, titler, "conv-a"); got != "Kitchen lights schedule" {
+			t.Fatalf("title changed to %q inside the throttle window", got)
+		}
+	}
+	if gen.calls != 1 {
+		t.Errorf("title calls = %d over 6 quick turns, want 1", gen.calls)
+	}
+
+	// Interval elapsed and enough turns: re-checked, but no shift means
+	// the title stays.
+	*now = now.Add(10 * time.Minute)
+	if got := turn(t, titler, "conv-a"); got != "Kitchen lights schedule" {
+		t.Errorf("title = %q, want unchanged without a topic shift", got)
+	}
+	if gen.calls != 2 {
+		t.Errorf("title calls = %d, want a re-check once throttle allows", gen.calls)
+	}
+}
+
+func TestTitler_RetitlesOnTopicShift(t *testing.T) {
+	gen := &scriptedTitles{results: []TitleResult{
+		{Title: "Kitchen lights schedule"},
+		{Title: "Garden irrigation planning", TopicShifted: true},
+	}}
+	titler, store, now := newTestTitler(t, gen)
+
+	turn(t, titler, "conv-a")
+	*now = now.Add(10 * time.Minute)
+	turn(t, titler, "conv-a")
+	turn(t, titler, "conv-a")
+	if gen.calls != 1 {
+		t.Fatalf("title calls = %d before CheckEvery turns, want 1", gen.calls)
+	}
+	if got := turn(t, titler, "conv-a"); got != "Garden irrigation planning" {
+		t.Errorf("title = %q, want retitled after topic shift", got)
+	}
+	stored, _ := store.ConversationTitle("conv-a")
+	if stored != "Garden irrigation planning" {
+		t.Errorf("stored title = %q", stored)
+	}
+}
+
+func TestTitler_ConversationsThrottledIndependently(t *testing.T) {
+	gen := &scriptedTitles{results: []TitleResult{{Title: "First topic"}, {Title: "Second topic"}}}
+	titler, _, _ := newTestTitler(t, gen)
+
+	if got := turn(t, titler, "conv-a"); got != "First topic" {
+		t.Errorf("conv-a title = %q", got)
+	}
+	if got := turn(t, titler, "conv-b"); got != "Second topic" {
+		t.Errorf("conv-b title = %q", got)
+	}
+}
+
+func TestCleanTitle(t *testing.T) {
+	tests := map[string]string{
+		"  Plan   the\nweek  ":      "Plan the week",
+		`"Quoted title."`:           "Quoted title",
+		"":                          "",
+		strings.Repeat("word ", 40): strings.TrimSpace(strings.Repeat("word ", 16)),
+	}
+	for in, want := range tests {
+		if got := cleanTitle(in); got != want {
+			t.Errorf("cleanTitle(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
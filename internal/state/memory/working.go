@@ -3,6 +3,7 @@ package memory
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/nugget/thane-ai-agent/internal/platform/database"
@@ -16,22 +17,34 @@ import (
 type WorkingMemoryStore struct {
 	db         *sql.DB
 	ftsEnabled bool
+
+	// taggedFTSEnabled reports whether working_memory_tagged_fts was
+	// set up. Tracked apart from ftsEnabled so a failure on the tagged
+	// index still leaves untagged entries searchable.
+	taggedFTSEnabled bool
 }
 
+// workingMemoryTagRe is the syntax a working-memory tag must match:
+// the capability tag name syntax the config loader enforces, so an
+// entry can only be scoped to a tag that could ever be active.
+var workingMemoryTagRe = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
 // NewWorkingMemoryStore creates a working memory store using the given
 // database connection (typically from [ArchiveStore.DB]). It creates
 // the working_memory table if it does not already exist. ftsEnabled
 // should be the value returned by [ArchiveStore.FTSEnabled] on the
 // archive store sharing this connection — when true, the constructor
-// also sets up working_memory_fts with sync triggers and backfills
-// any existing rows.
+// also sets up working_memory_fts and working_memory_tagged_fts with
+// sync triggers and backfills any existing rows.
 func NewWorkingMemoryStore(db *sql.DB, ftsEnabled bool) (*WorkingMemoryStore, error) {
 	s := &WorkingMemoryStore{db: db}
 	if err := s.migrate(); err != nil {
 		return nil, fmt.Errorf("working memory migration: %w", err)
 	}
 	if ftsEnabled {
-		s.ftsEnabled = trySetupWorkingMemoryFTS(db, ftsEnabled)
+		s.ftsEnabled = trySetupWorkingMemoryFTS(db, ftsEnabled, workingMemoryFTSTable, "working_memory")
+		s.taggedFTSEnabled = s.ftsEnabled &&
+			trySetupWorkingMemoryFTS(db, ftsEnabled, taggedWorkingMemoryFTSTable, "working_memory_tagged")
 	}
 	return s, nil
 }
@@ -45,9 +58,32 @@ func (s *WorkingMemoryStore) migrate() error {
 			updated_at      TEXT NOT NULL
 		)
 	`)
+	if err != nil {
+		return err
+	}
+	// Tag-scoped entries live beside the untagged row so the existing
+	// table keeps its one-row-per-conversation shape. They get their
+	// own FTS index.
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS working_memory_tagged (
+			conversation_id TEXT NOT NULL,
+			tag             TEXT NOT NULL,
+			content         TEXT NOT NULL,
+			updated_at      TEXT NOT NULL,
+			PRIMARY KEY (conversation_id, tag)
+		)
+	`)
 	return err
 }
 
+// TaggedWorkingMemory is a working-memory entry scoped to one
+// capability tag. It is injected only while that tag is active.
+type TaggedWorkingMemory struct {
+	Tag       string
+	Content   string
+	UpdatedAt time.Time
+}
+
 // FTSEnabled reports whether the working_memory_fts virtual table
 // was successfully created at startup.
 func (s *WorkingMemoryStore) FTSEnabled() bool {
@@ -95,7 +131,8 @@ func (s *WorkingMemoryStore) Set(conversationID, content string) error {
 	return nil
 }
 
-// Delete removes the working memory for a conversation.
+// Delete removes the working memory for a conversation, including
+// every tag-scoped entry.
 func (s *WorkingMemoryStore) Delete(conversationID string) error {
 	_, err := s.db.Exec(`
 		DELETE FROM working_memory WHERE conversation_id = ?
@@ -103,19 +140,112 @@ func (s *WorkingMemoryStore) Delete(conversationID string) error {
 	if err != nil {
 		return fmt.Errorf("delete working memory: %w", err)
 	}
+	_, err = s.db.Exec(`
+		DELETE FROM working_memory_tagged WHERE conversation_id = ?
+	`, conversationID)
+	if err != nil {
+		return fmt.Errorf("delete tagged working memory: %w", err)
+	}
+	return nil
+}
+
+// GetTagged returns the working memory scoped to one capability tag.
+// If none exists, it returns an empty string and zero time with no
+// error. A tag that is not a valid capability tag name is an error.
+func (s *WorkingMemoryStore) GetTagged(conversationID, tag string) (string, time.Time, error) {
+	if err := checkWorkingMemoryTag(tag); err != nil {
+		return "", time.Time{}, err
+	}
+	var content, updatedAtStr string
+	err := s.db.QueryRow(`
+		SELECT content, updated_at FROM working_memory_tagged
+		WHERE conversation_id = ? AND tag = ?
+	`, conversationID, tag).Scan(&content, &updatedAtStr)
+	if err == sql.ErrNoRows {
+		return "", time.Time{}, nil
+	}
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("get tagged working memory: %w", err)
+	}
+	updatedAt, err := database.ParseTimestamp(updatedAtStr)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("parse tagged working memory updated_at: %w", err)
+	}
+	return content, updatedAt, nil
+}
+
+// SetTagged writes or replaces the working memory scoped to one
+// capability tag for a conversation. The tag must be a valid
+// capability tag name.
+func (s *WorkingMemoryStore) SetTagged(conversationID, tag, content string) error {
+	if err := checkWorkingMemoryTag(tag); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO working_memory_tagged (conversation_id, tag, content, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(conversation_id, tag) DO UPDATE SET
+			content = excluded.content,
+			updated_at = excluded.updated_at
+	`, conversationID, tag, content, time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("set tagged working memory: %w", err)
+	}
 	return nil
 }
 
-// Search runs an FTS5 query against working_memory_fts and returns
-// the highest-ranking working-memory snapshots by BM25. Query is
-// wrapped as a phrase token for the same precision reasons the raw
-// archive search uses [phraseFTS5Query].
+// checkWorkingMemoryTag rejects a tag that is not a valid capability
+// tag name.
+func checkWorkingMemoryTag(tag string) error {
+	if !workingMemoryTagRe.MatchString(tag) {
+		return fmt.Errorf("invalid tag %q: must match %s", tag, workingMemoryTagRe)
+	}
+	return nil
+}
+
+// ListTagged returns every tag-scoped working-memory entry for a
+// conversation, ordered by tag.
+func (s *WorkingMemoryStore) ListTagged(conversationID string) ([]TaggedWorkingMemory, error) {
+	rows, err := s.db.Query(`
+		SELECT tag, content, updated_at FROM working_memory_tagged
+		WHERE conversation_id = ?
+		ORDER BY tag
+	`, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("list tagged working memory: %w", err)
+	}
+	defer rows.Close()
+
+	var out []TaggedWorkingMemory
+	for rows.Next() {
+		var e TaggedWorkingMemory
+		var updatedStr string
+		if err := rows.Scan(&e.Tag, &e.Content, &updatedStr); err != nil {
+			return nil, fmt.Errorf("scan tagged working memory: %w", err)
+		}
+		if e.UpdatedAt, err = database.ParseTimestamp(updatedStr); err != nil {
+			return nil, fmt.Errorf("parse tagged working memory updated_at: %w", err)
+		}
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate tagged working memory: %w", err)
+	}
+	return out, nil
+}
+
+// Search runs an FTS5 query against working_memory_fts and
+// working_memory_tagged_fts and returns the highest-ranking
+// working-memory snapshots by BM25, untagged and tag-scoped entries
+// ranked together. Query is wrapped as a phrase token for the same
+// precision reasons the raw archive search uses [phraseFTS5Query].
 //
 // Returns an empty slice when FTS5 isn't available, the query is
 // blank, or no rows match. The shape mirrors [SessionMatch]: caller
 // gets the conversation_id (which doubles as a foreign key into
-// [WorkingMemoryStore.Get] for the full content), an updated_at
-// timestamp, the content, and the snippet highlight.
+// [WorkingMemoryStore.Get] for the full content), the tag of a
+// tag-scoped entry, an updated_at timestamp, the content, and the
+// snippet highlight.
 func (s *WorkingMemoryStore) Search(query string, limit int) ([]WorkingMemoryMatch, error) {
 	if !s.ftsEnabled {
 		return nil, nil
@@ -128,15 +258,34 @@ func (s *WorkingMemoryStore) Search(query string, limit int) ([]WorkingMemoryMat
 		limit = 5
 	}
 
-	rows, err := s.db.Query(fmt.Sprintf(`
-		SELECT w.conversation_id, w.content, w.updated_at,
-		       snippet(%s, 0, '**', '**', '...', 32) AS highlight
+	stmt := fmt.Sprintf(`
+		SELECT w.conversation_id, '' AS tag, w.content, w.updated_at,
+		       snippet(%s, 0, '**', '**', '...', 32) AS highlight,
+		       bm25(%s) AS score
 		FROM %s
 		JOIN working_memory w ON w.rowid = %s.rowid
 		WHERE %s MATCH ?
-		ORDER BY rank
+	`, workingMemoryFTSTable, workingMemoryFTSTable, workingMemoryFTSTable, workingMemoryFTSTable, workingMemoryFTSTable)
+	args := []any{q}
+	if s.taggedFTSEnabled {
+		stmt += fmt.Sprintf(`
+		UNION ALL
+		SELECT t.conversation_id, t.tag, t.content, t.updated_at,
+		       snippet(%s, 0, '**', '**', '...', 32) AS highlight,
+		       bm25(%s) AS score
+		FROM %s
+		JOIN working_memory_tagged t ON t.rowid = %s.rowid
+		WHERE %s MATCH ?
+	`, taggedWorkingMemoryFTSTable, taggedWorkingMemoryFTSTable, taggedWorkingMemoryFTSTable, taggedWorkingMemoryFTSTable, taggedWorkingMemoryFTSTable)
+		args = append(args, q)
+	}
+	stmt += `
+		ORDER BY score
 		LIMIT ?
-	`, workingMemoryFTSTable, workingMemoryFTSTable, workingMemoryFTSTable, workingMemoryFTSTable), q, limit)
+	`
+	args = append(args, limit)
+
+	rows, err := s.db.Query(stmt, args...)
 	if err != nil {
 		return nil, fmt.Errorf("search working memory: %w", err)
 	}
@@ -146,7 +295,8 @@ func (s *WorkingMemoryStore) Search(query string, limit int) ([]WorkingMemoryMat
 	for rows.Next() {
 		var m WorkingMemoryMatch
 		var updatedStr string
-		if err := rows.Scan(&m.ConversationID, &m.Content, &updatedStr, &m.Highlight); err != nil {
+		var score float64
+		if err := rows.Scan(&m.ConversationID, &m.Tag, &m.Content, &updatedStr, &m.Highlight, &score); err != nil {
 			return nil, fmt.Errorf("scan working memory match: %w", err)
 		}
 		if m.UpdatedAt, err = database.ParseTimestamp(updatedStr); err != nil {
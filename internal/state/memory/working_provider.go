@@ -39,27 +39,52 @@ func (p *WorkingMemoryProvider) TagContextBucket() agentctx.ContextBucket {
 }
 
 // TagContext returns the working memory content for the current
-// conversation, formatted for system prompt injection. Returns empty
-// string if no working memory exists. Implements
+// conversation, formatted for system prompt injection. The untagged
+// entry always injects; tag-scoped entries inject only while their
+// capability tag is active, each under its own subheading. Returns
+// empty string if nothing applies. Implements
 // [agent.TagContextProvider]; registered via
 // RegisterAlwaysContextProvider.
-func (p *WorkingMemoryProvider) TagContext(ctx context.Context, _ agentctx.ContextRequest) (string, error) {
+func (p *WorkingMemoryProvider) TagContext(ctx context.Context, req agentctx.ContextRequest) (string, error) {
 	convID := p.conversationFunc(ctx)
 
 	content, updatedAt, err := p.store.Get(convID)
 	if err != nil {
 		return "", fmt.Errorf("read working memory: %w", err)
 	}
-	if content == "" {
+	tagged, err := p.store.ListTagged(convID)
+	if err != nil {
+		return "", fmt.Errorf("read tagged working memory: %w", err)
+	}
+	var scoped []TaggedWorkingMemory
+	for _, e := range tagged {
+		if req.ActiveTags[e.Tag] && e.Content != "" {
+			scoped = append(scoped, e)
+		}
+	}
+	if content == "" && len(scoped) == 0 {
 		return "", nil
 	}
 
+	now := time.Now()
 	var sb strings.Builder
 	sb.WriteString("### Working Memory\n\n")
-	if !updatedAt.IsZero() {
-		sb.WriteString(fmt.Sprintf("*Last updated: %s*\n\n", promptfmt.FormatDeltaOnly(updatedAt, time.Now())))
+	if content != "" {
+		if !updatedAt.IsZero() {
+			sb.WriteString(fmt.Sprintf("*Last updated: %s*\n\n", promptfmt.FormatDeltaOnly(updatedAt, now)))
+		}
+		sb.WriteString(content)
+	}
+	for i, e := range scoped {
+		if content != "" || i > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(fmt.Sprintf("#### Tag: %s\n\n", e.Tag))
+		if !e.UpdatedAt.IsZero() {
+			sb.WriteString(fmt.Sprintf("*Last updated: %s*\n\n", promptfmt.FormatDeltaOnly(e.UpdatedAt, now)))
+		}
+		sb.WriteString(e.Content)
 	}
-	sb.WriteString(content)
 
 	return sb.String(), nil
 }
@@ -92,3 +92,68 @@ func TestWorkingMemoryProvider_ConversationScoped(t *testing.T) {
 		t.Error("expected conv-a working memory content")
 	}
 }
+
+func TestWorkingMemoryProvider_TagScopedEntries(t *testing.T) {
+	p, store := newTestWorkingMemoryProvider(t, "default")
+
+	if err := store.Set("default", "Owner is tired tonight."); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetTagged("default", "ha", "Porch light automation is flaky; double-check."); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetTagged("default", "forge", "PR 42 awaits review."); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := p.TagContext(context.Background(), agentctx.ContextRequest{
+		ActiveTags: map[string]bool{"ha": true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(got, "Owner is tired tonight.") {
+		t.Error("untagged entry should always inject")
+	}
+	if !strings.Contains(got, "#### Tag: ha") || !strings.Contains(got, "Porch light automation") {
+		t.Errorf("active-tag entry missing:\n%s", got)
+	}
+	if strings.Contains(got, "PR 42") {
+		t.Errorf("inactive-tag entry injected:\n%s", got)
+	}
+
+	got, err = p.TagContext(context.Background(), agentctx.ContextRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(got, "Owner is tired tonight.") {
+		t.Error("untagged entry should inject with no active tags")
+	}
+	if strings.Contains(got, "Porch light") || strings.Contains(got, "PR 42") {
+		t.Errorf("tag-scoped entries injected with no active tags:\n%s", got)
+	}
+}
+
+func TestWorkingMemoryProvider_TagScopedOnly(t *testing.T) {
+	p, store := newTestWorkingMemoryProvider(t, "default")
+
+	if err := store.SetTagged("default", "ha", "Garage sensor battery low."); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := p.TagContext(context.Background(), agentctx.ContextRequest{ActiveTags: map[string]bool{"ha": true}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(got, "### Working Memory\n\n#### Tag: ha") {
+		t.Errorf("unexpected layout:\n%s", got)
+	}
+
+	got, err = p.TagContext(context.Background(), agentctx.ContextRequest{ActiveTags: map[string]bool{"web": true}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "" {
+		t.Errorf("expected empty context when no entry applies, got %q", got)
+	}
+}
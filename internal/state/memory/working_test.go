@@ -133,3 +133,52 @@ func TestWorkingMemory_DeleteNonexistent(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestWorkingMemory_TaggedEntries(t *testing.T) {
+	s := newTestWorkingMemoryStore(t)
+
+	if err := s.SetTagged("default", "ha", "first"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetTagged("default", "ha", "second"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetTagged("default", "forge", "pr notes"); err != nil {
+		t.Fatal(err)
+	}
+
+	content, updatedAt, err := s.GetTagged("default", "ha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != "second" || updatedAt.IsZero() {
+		t.Errorf("GetTagged = %q at %v, want upserted content", content, updatedAt)
+	}
+	if global, _, _ := s.Get("default"); global != "" {
+		t.Errorf("tagged write leaked into untagged entry: %q", global)
+	}
+
+	entries, err := s.ListTagged("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Tag != "forge" || entries[1].Tag != "ha" {
+		t.Errorf("ListTagged = %+v, want forge then ha", entries)
+	}
+
+	for _, tag := range []string{"HA", "ha tools", "", "-ha"} {
+		if err := s.SetTagged("default", tag, "notes"); err == nil {
+			t.Errorf("SetTagged(%q) succeeded, want an invalid tag error", tag)
+		}
+		if _, _, err := s.GetTagged("default", tag); err == nil {
+			t.Errorf("GetTagged(%q) succeeded, want an invalid tag error", tag)
+		}
+	}
+
+	if err := s.Delete("default"); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := s.ListTagged("default"); len(entries) != 0 {
+		t.Errorf("tagged entries survived Delete: %+v", entries)
+	}
+}
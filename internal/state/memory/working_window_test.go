@@ -304,6 +304,9 @@ func (f fakeCompactable) GetActiveCompactionSummaries(string) ([]Message, error)
 	return nil, nil
 }
 func (f fakeCompactable) ApplyCompaction(string, []string, string, time.Time) error { return nil }
+func (f fakeCompactable) CompactionState(string) (CompactionState, error) {
+	return CompactionState{}, nil
+}
 
 func TestNeedsCompaction_TokenOrCountTrigger(t *testing.T) {
 	// threshold = 2000 * 0.5 = 1000; count trigger at 6.
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/nugget/thane-ai-agent/internal/model/promptfmt"
@@ -60,6 +59,12 @@ const archiveResultByteCap = 16000
 // session transcripts, which routinely run longer than search hits.
 const archiveTranscriptByteCap = 32000
 
+// archiveSearchRecencyHalfLife weights archive_search's raw-message
+// ranking toward recent conversations, which is almost always what a
+// memory lookup means. Searches scoped with min_time/max_time rank by
+// relevance alone.
+const archiveSearchRecencyHalfLife = 30 * 24 * time.Hour
+
 // SetArchiveStore registers the four archive tools on the registry.
 // Together they form Thane's long-term memory surface: search across
 // past conversations, browse the catalog of sessions, pull a single
@@ -100,11 +105,13 @@ func (r *Registry) registerArchiveSearch(searcher memory.MemorySearcher) {
 			"windows (bounded by natural silence gaps); sessions[] carries the summarizer's " +
 			"per-session distilled metadata (title, summary, tags); working_memory[] carries " +
 			"the per-conversation living distillation written by the metacog loop. " +
-			"Results are ordered best-first; each message hit carries a relevance score and a " +
+			"Results are ordered best-first, favoring recent conversations; each message hit carries a relevance score and a " +
 			"match_type (phrase = exact-phrase precision, terms = broader OR-of-terms recall). " +
 			"total_estimated reports how many messages matched so you can tell when you are seeing a " +
 			"capped slice. Scope the raw-message search to a window with min_time/max_time " +
-			"(RFC3339 or a signed delta like -7d); the distilled surfaces stay unscoped. " +
+			"(RFC3339 or a signed delta like -7d) — a scoped search ranks by relevance alone, so use max_time to dig " +
+			"into older history; the distilled surfaces stay unscoped. " +
+			"Set mode to semantic or hybrid when the words you remember may not be the words that were used. " +
 			"Use this when something jogs a memory or you need context from a prior " +
 			"conversation — the distilled surfaces are higher signal per byte and worth " +
 			"reading first when they have hits. Pair with archive_session_transcript when " +
@@ -141,18 +148,31 @@ func (r *Registry) registerArchiveSearch(searcher memory.MemorySearcher) {
 					"type":        "number",
 					"description": "Max raw-message results. Default: 5. Distilled surfaces have their own internal caps.",
 				},
+				"mode": map[string]any{
+					"type": "string",
+					"enum": []string{"keyword", "semantic", "hybrid"},
+					"description": "How raw messages are ranked. keyword (default) matches your words; semantic matches meaning, " +
+						"finding paraphrases that share no words with the query; hybrid blends both. Semantic and hybrid need " +
+						"embeddings enabled. Distilled surfaces are always keyword.",
+				},
 			},
 			"required": []string{"query"},
 		},
-		Handler: func(_ context.Context, args map[string]any) (string, error) {
+		Handler: func(ctx context.Context, args map[string]any) (string, error) {
 			query, _ := args["query"].(string)
 			if query == "" {
 				return "", fmt.Errorf("query is required")
 			}
+			modeArg, _ := args["mode"].(string)
+			mode, err := memory.ParseSearchMode(modeArg)
+			if err != nil {
+				return "", err
+			}
 
 			opts := memory.SearchOptions{
 				Query: query,
 				Limit: 5,
+				Mode:  mode,
 			}
 			if convID, ok := args["conversation_id"].(string); ok && convID != "" {
 				opts.ConversationID = convID
@@ -181,8 +201,11 @@ func (r *Registry) registerArchiveSearch(searcher memory.MemorySearcher) {
 				}
 				opts.To = t
 			}
+			if opts.From.IsZero() && opts.To.IsZero() {
+				opts.RecencyHalfLife = archiveSearchRecencyHalfLife
+			}
 
-			bundle, err := searcher.Search(opts)
+			bundle, err := searcher.Search(ctx, opts)
 			if err != nil {
 				return "", fmt.Errorf("archive search: %w", err)
 			}
@@ -329,7 +352,7 @@ func (r *Registry) registerArchiveSessionTranscript(store *memory.ArchiveStore)
 				return "", fmt.Errorf("session_id is required")
 			}
 			if len(sessionID) <= 8 {
-				fullID, err := resolveShortSessionID(store, sessionID)
+				fullID, err := store.ResolveSessionID(sessionID)
 				if err != nil {
 					return "", err
 				}
@@ -444,25 +467,3 @@ func (r *Registry) registerArchiveRange(store *memory.ArchiveStore) {
 		},
 	})
 }
-
-// resolveShortSessionID finds a full session ID from a prefix.
-func resolveShortSessionID(store *memory.ArchiveStore, prefix string) (string, error) {
-	sessions, err := store.ListSessions("", 100)
-	if err != nil {
-		return "", fmt.Errorf("list sessions: %w", err)
-	}
-	var matches []string
-	for _, s := range sessions {
-		if strings.HasPrefix(s.ID, prefix) {
-			matches = append(matches, s.ID)
-		}
-	}
-	switch len(matches) {
-	case 0:
-		return "", fmt.Errorf("no session found with prefix %q", prefix)
-	case 1:
-		return matches[0], nil
-	default:
-		return "", fmt.Errorf("ambiguous prefix %q matches %d sessions", prefix, len(matches))
-	}
-}
@@ -378,3 +378,56 @@ func TestArchiveSearchTool_NeverEmptyWhenResultsExist(t *testing.T) {
 		t.Fatalf("results empty across all surfaces despite real matches existing — regression of the production bug:\n%s", out)
 	}
 }
+
+// wordEmbedder embeds text as a one-hot over a tiny vocabulary,
+// treating "automobile" as a synonym of "car" so semantic search can
+// match a message keyword search cannot.
+type wordEmbedder struct{}
+
+func (wordEmbedder) Generate(_ context.Context, text string) ([]float32, error) {
+	vec := []float32{0, 0, 0.1}
+	for _, w := range strings.Fields(strings.ToLower(text)) {
+		switch w {
+		case "car", "automobile":
+			vec[0]++
+		case "coffee":
+			vec[1]++
+		}
+	}
+	return vec, nil
+}
+
+func TestArchiveSearchTool_Mode(t *testing.T) {
+	r, store, insert := newArchiveTestRegistry(t)
+	now := time.Now()
+	insert("conv-1", "sess-1", "user", "the car needs new tires", now.Add(-2*time.Hour))
+	insert("conv-1", "sess-1", "user", "brew more coffee", now.Add(-time.Hour))
+	tool := r.Get("archive_search")
+
+	if _, err := tool.Handler(context.Background(), map[string]any{"query": "automobile", "mode": "semantic"}); err == nil ||
+		!strings.Contains(err.Error(), "semantic search is not enabled") {
+		t.Fatalf("semantic without embeddings error = %v, want not enabled", err)
+	}
+	if _, err := tool.Handler(context.Background(), map[string]any{"query": "automobile", "mode": "fuzzy"}); err == nil {
+		t.Fatal("unknown mode should be rejected")
+	}
+
+	store.SetEmbeddingClient(wordEmbedder{}, "words")
+	if _, err := store.EmbedPending(context.Background(), 10); err != nil {
+		t.Fatalf("EmbedPending: %v", err)
+	}
+
+	out, err := tool.Handler(context.Background(), map[string]any{"query": "automobile", "mode": "semantic", "limit": float64(1)})
+	if err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	var parsed struct {
+		Messages []memory.SearchResultView `json:"messages"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("unmarshal: %v\noutput: %s", err, out)
+	}
+	if len(parsed.Messages) != 1 || !strings.Contains(out, "new tires") {
+		t.Errorf("semantic archive_search = %s, want the car message", out)
+	}
+}
@@ -81,6 +81,10 @@ func (r *Registry) registerContactTools() {
 					"items":       map[string]any{"type": "string"},
 					"description": "Supplemental managed document refs to inject when this contact is the session origin, such as kb:projects/current.md. Store person identity in the contact fields and ai_summary instead.",
 				},
+				"ha_person": map[string]any{
+					"type":        "string",
+					"description": "Home Assistant person entity this contact is (e.g. person.alice). Links the contact to that person's presence, so facts about the contact surface while they are tracked. An entity links to one contact at a time.",
+				},
 				"facts": map[string]any{
 					"type":                 "object",
 					"description":          "Attributes as key-value pairs. All entries are stored as contact properties. Standard keys like 'email' and 'phone' are mapped to vCard property names (EMAIL, TEL); others use their key as-is (e.g., {\"email\": \"alice@example.com\", \"phone\": \"555-1234\", \"ha_companion_app\": \"mobile_app_phone\"}).",
@@ -174,6 +178,32 @@ func (r *Registry) registerContactTools() {
 		},
 	})
 
+	r.Register(&Tool{
+		Name:        "contact_merge",
+		Description: "Merge a duplicate contact into another. The kept contact gains the duplicate's properties (identical ones deduplicated), fills its empty fields, keeps the more privileged trust zone, and takes the duplicate's name as a nickname when it has none. The duplicate is removed. Confirm with the user before merging contacts with different trust zones.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"keep": map[string]any{
+					"type":        "string",
+					"description": "Name or ID of the contact to keep",
+				},
+				"merge": map[string]any{
+					"type":        "string",
+					"description": "Name or ID of the duplicate to fold into it",
+				},
+			},
+			"required": []string{"keep", "merge"},
+		},
+		Handler: func(ctx context.Context, args map[string]any) (string, error) {
+			argsJSON, err := json.Marshal(args)
+			if err != nil {
+				return "", fmt.Errorf("failed to serialize arguments: %w", err)
+			}
+			return r.contactTools.MergeContacts(string(argsJSON))
+		},
+	})
+
 	r.Register(&Tool{
 		Name:        "contact_list",
 		Description: "List contacts from the directory. Optionally filter by kind and limit the number of results.",
@@ -261,7 +291,7 @@ func (r *Registry) registerContactTools() {
 
 	r.Register(&Tool{
 		Name:        "contact_import_vcf",
-		Description: "Import contacts from a vCard (.vcf) file or text. Supports single and multi-contact vCards. By default, merges with existing contacts matched by email or name — only empty fields are filled, TrustZone and AISummary are never overwritten. Use dry_run to preview changes.",
+		Description: "Import contacts from a vCard (.vcf) file or text. Supports single and multi-contact vCards. By default, merges with existing contacts matched by phone, email, or name — only empty fields are filled, TrustZone and AISummary are never overwritten. Use dry_run to preview changes.",
 		Parameters: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
@@ -19,6 +19,8 @@ const loopIDKey contextKey = "loop_id"
 const channelBindingKey contextKey = "channel_binding"
 const inheritableCapabilityTagsKey contextKey = "inheritable_capability_tags"
 const requestIDKey contextKey = "request_id"
+const progressReporterKey contextKey = "progress_reporter"
+const toolPolicyKey contextKey = "tool_policy"
 
 // WithConversationID adds the conversation ID to the context.
 func WithConversationID(ctx context.Context, id string) context.Context {
@@ -81,6 +83,29 @@ func ToolCallIDFromContext(ctx context.Context) string {
 	return ""
 }
 
+// ProgressFunc receives a short status message from a running tool.
+type ProgressFunc func(msg string)
+
+// WithProgressReporter attaches a progress sink for the tool call about
+// to run. The agent loop installs one per tool call when the request is
+// streaming.
+func WithProgressReporter(ctx context.Context, fn ProgressFunc) context.Context {
+	if fn == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressReporterKey, fn)
+}
+
+// ReportProgress sends a short status message (e.g. "downloading…")
+// for the running tool call to whoever is watching it. It is a no-op
+// when no reporter is attached, so handlers can call it
+// unconditionally. Safe for concurrent use.
+func ReportProgress(ctx context.Context, msg string) {
+	if fn, ok := ctx.Value(progressReporterKey).(ProgressFunc); ok && msg != "" {
+		fn(msg)
+	}
+}
+
 // WithIterationIndex adds the current loop iteration index to the context.
 func WithIterationIndex(ctx context.Context, idx int) context.Context {
 	return context.WithValue(ctx, iterationIndexKey, idx)
@@ -132,6 +157,23 @@ func HintsFromContext(ctx context.Context) map[string]string {
 	return nil
 }
 
+// HintParentSource is the routing hint that carries the "source" of
+// the run a delegate was launched from. A delegate's own source is
+// always "delegate", so policies keyed on where work came from — the
+// confidence gate for autonomous actions, for one — read the origin
+// through [OriginSource] instead.
+const HintParentSource = "parent_source"
+
+// OriginSource returns the source a run ultimately came from: its
+// [HintParentSource] hint when set, otherwise its own "source" hint.
+// Nested delegates keep the outermost run's source.
+func OriginSource(hints map[string]string) string {
+	if src := hints[HintParentSource]; src != "" {
+		return src
+	}
+	return hints["source"]
+}
+
 // suppressAlwaysContextKey is the context key for the per-Run flag
 // controlling whether the always-on bucket of TagContextAssembler runs
 // during system-prompt assembly. Default false (include always);
@@ -197,6 +239,23 @@ func InheritableCapabilityTagsFromContext(ctx context.Context) []string {
 	return append([]string(nil), tags...)
 }
 
+// WithToolPolicy records that the calling conversation is restricted
+// to allow by a conversation tool policy, so child work it starts is
+// held to the same tools. An empty allow means no tools at all.
+func WithToolPolicy(ctx context.Context, allow []string) context.Context {
+	return context.WithValue(ctx, toolPolicyKey, append([]string{}, allow...))
+}
+
+// ToolPolicyFromContext returns the caller's tool policy allowlist. ok
+// is false when the caller is not restricted by a policy.
+func ToolPolicyFromContext(ctx context.Context) (allow []string, ok bool) {
+	allow, ok = ctx.Value(toolPolicyKey).([]string)
+	if !ok {
+		return nil, false
+	}
+	return append([]string{}, allow...), true
+}
+
 // LoopCompletionTargetFromContext derives the most natural detached
 // completion target for the current tool call context. The returned
 // conversation ID always reflects the current live conversation when one
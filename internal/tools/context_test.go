@@ -320,3 +320,22 @@ func TestSuppressAlwaysContext(t *testing.T) {
 		}
 	})
 }
+
+func TestReportProgress(t *testing.T) {
+	// No reporter attached: must be a silent no-op.
+	ReportProgress(context.Background(), "downloading…")
+
+	var got []string
+	ctx := WithProgressReporter(context.Background(), func(msg string) { got = append(got, msg) })
+	ReportProgress(ctx, "downloading…")
+	ReportProgress(ctx, "")
+	ReportProgress(ctx, "transcribing chunk 3/12…")
+
+	want := []string{"downloading…", "transcribing chunk 3/12…"}
+	if !slices.Equal(got, want) {
+		t.Errorf("reported = %q, want %q", got, want)
+	}
+	if WithProgressReporter(ctx, nil) != ctx {
+		t.Error("WithProgressReporter(nil) should return ctx unchanged")
+	}
+}
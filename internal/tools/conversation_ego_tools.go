@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/model/promptfmt"
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
+)
+
+// SetConversationEgoStore adds the session_ego tool to the registry.
+//
+// This tool lets the agent keep self-notes scoped to the current
+// conversation — adjustments to its own behavior that apply in this
+// thread but not everywhere. They are auto-injected beside the global
+// ego.md and bounded by [memory.MaxConversationEgoBytes].
+func (r *Registry) SetConversationEgoStore(store *memory.ConversationEgoStore) {
+	r.Register(&Tool{
+		Name: "session_ego",
+		Description: "Read, write, or clear your self-notes for this conversation. " +
+			"These are notes about how you should behave in this thread specifically " +
+			"(e.g. 'the user prefers terse answers here', 'stay in planning mode, no device changes'). " +
+			"They refine your global ego.md for this conversation only, persist across turns and compaction, " +
+			"and are auto-injected into your context each turn. " +
+			"Use 'write' to replace them entirely and keep them short; " +
+			fmt.Sprintf("content over %d bytes is rejected.", memory.MaxConversationEgoBytes),
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"action": map[string]any{
+					"type":        "string",
+					"enum":        []string{"read", "write", "clear"},
+					"description": "Action to perform: 'read' returns current notes, 'write' replaces them entirely, 'clear' removes them",
+				},
+				"content": map[string]any{
+					"type":        "string",
+					"description": "New self-notes (required for 'write'). Write in first person as notes to yourself.",
+				},
+			},
+			"required": []string{"action"},
+		},
+		Handler: func(ctx context.Context, args map[string]any) (string, error) {
+			action, _ := args["action"].(string)
+			convID := ConversationIDFromContext(ctx)
+
+			switch action {
+			case "read":
+				content, updatedAt, err := store.Get(convID)
+				if err != nil {
+					return "", fmt.Errorf("read conversation ego: %w", err)
+				}
+				if content == "" {
+					return "(no self-notes for this conversation)", nil
+				}
+				return fmt.Sprintf("Last updated: %s\n\n%s", promptfmt.FormatDeltaOnly(updatedAt, time.Now()), content), nil
+
+			case "write":
+				content, _ := args["content"].(string)
+				if content == "" {
+					return "", fmt.Errorf("content is required for write action")
+				}
+				if err := store.Set(convID, content); err != nil {
+					return "", fmt.Errorf("write conversation ego: %w", err)
+				}
+				return "Conversation self-notes updated.", nil
+
+			case "clear":
+				if err := store.Delete(convID); err != nil {
+					return "", fmt.Errorf("clear conversation ego: %w", err)
+				}
+				return "Conversation self-notes cleared.", nil
+
+			default:
+				return "", fmt.Errorf("unknown action %q: expected 'read', 'write', or 'clear'", action)
+			}
+		},
+	})
+}
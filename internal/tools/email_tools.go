@@ -2,6 +2,8 @@ package tools
 
 import (
 	"context"
+	"fmt"
+	"io"
 
 	"github.com/nugget/thane-ai-agent/internal/channels/email"
 )
@@ -9,9 +11,32 @@ import (
 // SetEmailTools adds email tools to the registry.
 func (r *Registry) SetEmailTools(et *email.Tools) {
 	r.emailTools = et
+	et.SetFileStore(emailFileStore{r: r})
 	r.registerEmailTools()
 }
 
+// emailFileStore adapts the registry's temp file store to
+// [email.FileStore], scoping labels to the calling conversation. The
+// store is looked up per call because it is configured after email.
+type emailFileStore struct {
+	r *Registry
+}
+
+func (s emailFileStore) Create(ctx context.Context, label, name, ext string, write func(io.Writer) error) (string, error) {
+	if s.r.tempFileStore == nil {
+		return "", fmt.Errorf("temp file store not configured")
+	}
+	return s.r.tempFileStore.CreateNamed(ctx, ConversationIDFromContext(ctx), label, name, ext, write)
+}
+
+func (s emailFileStore) Path(ctx context.Context, label string) (string, string) {
+	if s.r.tempFileStore == nil {
+		return "", ""
+	}
+	convID := ConversationIDFromContext(ctx)
+	return s.r.tempFileStore.Resolve(convID, label), s.r.tempFileStore.Name(convID, label)
+}
+
 func (r *Registry) registerEmailTools() {
 	if r.emailTools == nil {
 		return
@@ -194,6 +219,11 @@ func (r *Registry) registerEmailTools() {
 					"type":        "string",
 					"description": "Email body in markdown format. Will be converted to text/plain and text/html automatically.",
 				},
+				"attachments": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Temp file labels to attach (bare labels such as 'attachment_42_2', without the temp: prefix). A downloaded attachment keeps its original file name; other files are sent under their label with their extension.",
+				},
 				"account": map[string]any{
 					"type":        "string",
 					"description": "Email account name (default: primary account)",
@@ -228,6 +258,11 @@ func (r *Registry) registerEmailTools() {
 					"type":        "boolean",
 					"description": "Reply to all original recipients (default: false)",
 				},
+				"attachments": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Temp file labels to attach (bare labels such as 'attachment_42_2', without the temp: prefix). A downloaded attachment keeps its original file name; other files are sent under their label with their extension.",
+				},
 				"account": map[string]any{
 					"type":        "string",
 					"description": "Email account name (default: primary account)",
@@ -277,4 +312,68 @@ func (r *Registry) registerEmailTools() {
 			return r.emailTools.HandleMove(ctx, args)
 		},
 	})
+
+	r.Register(&Tool{
+		Name:        "email_attachments",
+		Description: "List the attachments of an email by UID: part number, file name, MIME type, and approximate size. Nothing is downloaded. Use the part number with email_attachment_download.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"uid": map[string]any{
+					"type":        "integer",
+					"description": "Message UID to inspect",
+				},
+				"folder": map[string]any{
+					"type":        "string",
+					"description": "Mailbox folder containing the message (default: INBOX)",
+				},
+				"account": map[string]any{
+					"type":        "string",
+					"description": "Email account name (default: primary account)",
+				},
+			},
+			"required": []string{"uid"},
+		},
+		Handler: func(ctx context.Context, args map[string]any) (string, error) {
+			return r.emailTools.HandleAttachments(ctx, args)
+		},
+	})
+
+	r.Register(&Tool{
+		Name:        "email_attachment_download",
+		Description: "Download one email attachment to a temp file and return its label and path, e.g. to summarize a PDF invoice with the media tools. The sender must be in the contact directory with an appropriate trust zone, the same gating email_send applies to recipients. Attachments over the configured size limit are refused.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"uid": map[string]any{
+					"type":        "integer",
+					"description": "Message UID containing the attachment",
+				},
+				"part": map[string]any{
+					"type":        "string",
+					"description": "Attachment part number from email_attachments (e.g., '2'). Required unless `filename` is provided.",
+				},
+				"filename": map[string]any{
+					"type":        "string",
+					"description": "Attachment file name, used when `part` is omitted",
+				},
+				"label": map[string]any{
+					"type":        "string",
+					"description": "Temp file label to save under (default: attachment_<uid>_<part>)",
+				},
+				"folder": map[string]any{
+					"type":        "string",
+					"description": "Mailbox folder containing the message (default: INBOX)",
+				},
+				"account": map[string]any{
+					"type":        "string",
+					"description": "Email account name (default: primary account)",
+				},
+			},
+			"required": []string{"uid"},
+		},
+		Handler: func(ctx context.Context, args map[string]any) (string, error) {
+			return r.emailTools.HandleDownloadAttachment(ctx, args)
+		},
+	})
 }
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/nugget/thane-ai-agent/internal/model/promptfmt"
+	"github.com/nugget/thane-ai-agent/internal/state/memory"
+)
+
+// SetEpisodicProvider registers episodic_search, an on-demand search
+// over the daily notes the episodic provider injects only for its
+// short lookback window.
+func (r *Registry) SetEpisodicProvider(p *memory.EpisodicProvider) {
+	if p == nil {
+		return
+	}
+	r.Register(&Tool{
+		Name: "episodic_search",
+		Description: "Search your daily notes (the per-day journal whose last day or two appear under Daily Notes) " +
+			"across their whole history. Returns matching paragraphs newest first, grouped by date — use this to " +
+			"answer 'what did we decide about the garden last spring?' when the answer is older than the notes in " +
+			"context. A paragraph matches when it contains every query word (case-insensitive), so search with one " +
+			"or two distinctive words and narrow with since/until. For past conversations rather than notes, use archive_search.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]any{
+					"type":        "string",
+					"description": "Keywords to find. Every word must appear in a paragraph for it to match.",
+				},
+				"since": map[string]any{
+					"type":        "string",
+					"description": "Optional: earliest note date. A date (\"2026-03-01\"), RFC3339 timestamp, or signed delta (\"-180d\").",
+				},
+				"until": map[string]any{
+					"type":        "string",
+					"description": "Optional: latest note date. Same formats as since.",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"description": "Maximum number of notes to return (default 10, max 50).",
+				},
+			},
+			"required": []string{"query"},
+		},
+		Handler: func(_ context.Context, args map[string]any) (string, error) {
+			query, _ := args["query"].(string)
+			since, _ := args["since"].(string)
+			until, _ := args["until"].(string)
+			limit, err := boundedIntArg(args, "limit", 0, 50)
+			if err != nil {
+				return "", err
+			}
+			results, err := p.SearchDailyNotes(memory.DailyNoteSearch{
+				Query: query,
+				Since: since,
+				Until: until,
+				Limit: limit,
+			})
+			if err != nil {
+				return "", err
+			}
+			return promptfmt.MarshalCompact(results), nil
+		},
+	})
+}
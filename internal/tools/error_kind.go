@@ -0,0 +1,157 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// ErrorKind classifies a failed tool call so the model can decide what
+// to do next without parsing free-form error text: retry later, fix
+// its arguments, try something else, or give up.
+type ErrorKind string
+
+const (
+	// ErrorTransient means the call may succeed if retried later
+	// (timeouts, refused connections, rate limits).
+	ErrorTransient ErrorKind = "transient"
+	// ErrorPermanent means retrying the same call will fail the same
+	// way. It is also the kind of any error that isn't classified.
+	ErrorPermanent ErrorKind = "permanent"
+	// ErrorNotFound means the thing the call referred to (file, entity,
+	// document, tool) does not exist.
+	ErrorNotFound ErrorKind = "not_found"
+	// ErrorPermissionDenied means the call was refused for lack of
+	// access, not because anything was wrong with it.
+	ErrorPermissionDenied ErrorKind = "permission_denied"
+	// ErrorBadInput means the arguments were wrong; the model should
+	// correct them before calling again.
+	ErrorBadInput ErrorKind = "bad_input"
+)
+
+// toolErrorPrefix opens every classified tool error result, as in
+// "[error:bad_input] path must be relative".
+const toolErrorPrefix = "[error:"
+
+// ToolError attaches an [ErrorKind] to an error returned by a tool
+// handler. Handlers that know why they failed should return one (see
+// [NewToolError]) rather than leave the kind to [ClassifyError]'s
+// guesswork.
+type ToolError struct {
+	Kind ErrorKind
+	Err  error
+}
+
+// NewToolError wraps err with the given kind. A nil err stays nil.
+func NewToolError(kind ErrorKind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ToolError{Kind: kind, Err: err}
+}
+
+// Errorf formats an error of the given kind, like [fmt.Errorf].
+func Errorf(kind ErrorKind, format string, args ...any) error {
+	return &ToolError{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// Error implements the error interface.
+func (e *ToolError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the underlying error.
+func (e *ToolError) Unwrap() error { return e.Err }
+
+// ErrorKind reports the error's classification.
+func (e *ToolError) ErrorKind() ErrorKind { return e.Kind }
+
+// ErrorKind reports an unavailable tool as not found: it doesn't exist
+// in the caller's effective registry.
+func (e *ErrToolUnavailable) ErrorKind() ErrorKind { return ErrorNotFound }
+
+// ErrorKind reports a rate-limited call as transient: the same call
+// succeeds once the window frees up.
+func (e *ErrToolRateLimited) ErrorKind() ErrorKind { return ErrorTransient }
+
+// ClassifyError returns the kind of a tool error. An error in err's
+// chain that reports its own kind (a [*ToolError],
+// [*ErrToolUnavailable], [*ErrToolRateLimited]) wins; otherwise well
+// known standard library errors are recognized, then a few common
+// message shapes. Anything else is [ErrorPermanent].
+func ClassifyError(err error) ErrorKind {
+	if err == nil {
+		return ""
+	}
+	var kinded interface{ ErrorKind() ErrorKind }
+	if errors.As(err, &kinded) {
+		if kind := kinded.ErrorKind(); kind != "" {
+			return kind
+		}
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorTransient
+	case errors.Is(err, fs.ErrNotExist):
+		return ErrorNotFound
+	case errors.Is(err, fs.ErrPermission):
+		return ErrorPermissionDenied
+	}
+
+	// Most handlers build errors with fmt.Errorf, so fall back to the
+	// message. The patterns are deliberately narrow: a wrong guess
+	// misleads the model more than an honest "permanent".
+	msg := strings.ToLower(err.Error())
+	switch {
+	case containsAny(msg, "timed out", "timeout", "connection refused", "connection reset",
+		"temporarily unavailable", "try again later"):
+		return ErrorTransient
+	case containsAny(msg, "not found", "no such file", "does not exist"):
+		return ErrorNotFound
+	case containsAny(msg, "permission denied", "forbidden", "unauthorized", "read-only",
+		"escapes allowed directories"):
+		return ErrorPermissionDenied
+	case containsAny(msg, "invalid argument", "is required", "must be", "must not"):
+		return ErrorBadInput
+	}
+	return ErrorPermanent
+}
+
+// FormatToolError renders err as the tool result the model sees:
+// "[error:<kind>] <message>".
+func FormatToolError(err error) string {
+	if err == nil {
+		return ""
+	}
+	return toolErrorPrefix + string(ClassifyError(err)) + "] " + err.Error()
+}
+
+// ParseToolError splits a result produced by [FormatToolError] into its
+// kind and message. ok is false when s is not a classified tool error.
+func ParseToolError(s string) (kind ErrorKind, msg string, ok bool) {
+	rest, found := strings.CutPrefix(s, toolErrorPrefix)
+	if !found {
+		return "", s, false
+	}
+	k, msg, found := strings.Cut(rest, "] ")
+	if !found || k == "" || strings.ContainsAny(k, " \n") {
+		return "", s, false
+	}
+	return ErrorKind(k), msg, true
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{"nil", nil, ""},
+		{"explicit kind", Errorf(ErrorBadInput, "path must be relative"), ErrorBadInput},
+		{"wrapped explicit kind", fmt.Errorf("read: %w", NewToolError(ErrorPermissionDenied, errors.New("nope"))), ErrorPermissionDenied},
+		{"explicit kind beats message", Errorf(ErrorPermanent, "entity not found"), ErrorPermanent},
+		{"unavailable tool", &ErrToolUnavailable{ToolName: "exec"}, ErrorNotFound},
+		{"rate limited", &ErrToolRateLimited{ToolName: "web_search"}, ErrorTransient},
+		{"deadline", fmt.Errorf("fetch: %w", context.DeadlineExceeded), ErrorTransient},
+		{"connection refused", &os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED}, ErrorTransient},
+		{"missing file", &fs.PathError{Op: "open", Path: "a.md", Err: fs.ErrNotExist}, ErrorNotFound},
+		{"no permission", &fs.PathError{Op: "open", Path: "a.md", Err: fs.ErrPermission}, ErrorPermissionDenied},
+		{"invalid arguments", fmt.Errorf("invalid arguments: unexpected end of JSON input"), ErrorBadInput},
+		{"required argument", errors.New("entity_id is required"), ErrorBadInput},
+		{"not found message", errors.New("document kb:x.md not found"), ErrorNotFound},
+		{"HTTP 503 message", errors.New("service temporarily unavailable"), ErrorTransient},
+		{"unclassified", errors.New("exit status 1"), ErrorPermanent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyError(tt.err); got != tt.want {
+				t.Errorf("ClassifyError(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatAndParseToolError(t *testing.T) {
+	got := FormatToolError(Errorf(ErrorBadInput, "path must be relative"))
+	if want := "[error:bad_input] path must be relative"; got != want {
+		t.Fatalf("FormatToolError() = %q, want %q", got, want)
+	}
+	kind, msg, ok := ParseToolError(got)
+	if !ok || kind != ErrorBadInput || msg != "path must be relative" {
+		t.Errorf("ParseToolError(%q) = %q, %q, %v", got, kind, msg, ok)
+	}
+
+	for _, s := range []string{"", "Error: boom", "[error:] boom", "[error:bad input] boom", "[errors] boom"} {
+		if kind, msg, ok := ParseToolError(s); ok || kind != "" || msg != s {
+			t.Errorf("ParseToolError(%q) = %q, %q, %v; want not a tool error", s, kind, msg, ok)
+		}
+	}
+	if FormatToolError(nil) != "" {
+		t.Error("FormatToolError(nil) should be empty")
+	}
+}
+
+func TestRegistryExecute_InvalidArgumentsIsBadInput(t *testing.T) {
+	r := NewEmptyRegistry()
+	r.Register(&Tool{
+		Name:    "noop",
+		Handler: func(context.Context, map[string]any) (string, error) { return "ok", nil },
+	})
+	_, err := r.Execute(context.Background(), "noop", "{not json")
+	if got := ClassifyError(err); got != ErrorBadInput {
+		t.Errorf("ClassifyError(%v) = %q, want %q", err, got, ErrorBadInput)
+	}
+}
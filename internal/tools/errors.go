@@ -1,6 +1,9 @@
 package tools
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // ErrToolUnavailable is returned when a tool call targets a tool that
 // is not present in the effective registry. This indicates a capability
@@ -15,3 +18,23 @@ type ErrToolUnavailable struct {
 func (e *ErrToolUnavailable) Error() string {
 	return fmt.Sprintf("tool %q is not available in this context", e.ToolName)
 }
+
+// ErrToolRateLimited is returned when a tool call exceeds the tool's
+// rate limit (see [Registry.SetToolRateLimit]). Its message is written
+// for the model: the limit, when the tool frees up, and what to do
+// meanwhile, so it can change course instead of retrying blindly.
+type ErrToolRateLimited struct {
+	ToolName   string
+	Limit      ToolRateLimit
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *ErrToolRateLimited) Error() string {
+	retry := e.RetryAfter.Round(time.Second)
+	if retry < time.Second {
+		retry = time.Second
+	}
+	return fmt.Sprintf("rate limited: tool %q is limited to %d calls per %s and has reached that limit; try again later (in %s). Work with the results you already have, use a different tool, or tell the user the limit was reached — do not retry %s immediately.",
+		e.ToolName, e.Limit.Calls, e.Limit.Per, retry, e.ToolName)
+}
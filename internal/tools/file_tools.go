@@ -212,7 +212,7 @@ func (ft *FileTools) resolvePath(path string) (string, bool, error) {
 		}
 	}
 
-	return "", false, fmt.Errorf("path escapes allowed directories: %s", path)
+	return "", false, Errorf(ErrorPermissionDenied, "path escapes allowed directories: %s", path)
 }
 
 // Read reads the contents of a file.
@@ -229,7 +229,7 @@ func (ft *FileTools) Read(ctx context.Context, path string, offset, limit int) (
 	data, err := os.ReadFile(absPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", fmt.Errorf("file not found: %s", path)
+			return "", Errorf(ErrorNotFound, "file not found: %s", path)
 		}
 		return "", fmt.Errorf("failed to read file: %w", err)
 	}
@@ -278,7 +278,7 @@ func (ft *FileTools) Write(ctx context.Context, path, content string) error {
 		return err
 	}
 	if readOnly {
-		return fmt.Errorf("path is read-only: %s", path)
+		return Errorf(ErrorPermissionDenied, "path is read-only: %s", path)
 	}
 
 	if err := ft.verifyMutationPath(ctx, absPath, "file_tools_write"); err != nil {
@@ -306,7 +306,7 @@ func (ft *FileTools) Edit(ctx context.Context, path, oldText, newText string) er
 		return err
 	}
 	if readOnly {
-		return fmt.Errorf("path is read-only: %s", path)
+		return Errorf(ErrorPermissionDenied, "path is read-only: %s", path)
 	}
 
 	if err := ft.verifyMutationPath(ctx, absPath, "file_tools_edit"); err != nil {
@@ -317,7 +317,7 @@ func (ft *FileTools) Edit(ctx context.Context, path, oldText, newText string) er
 	data, err := os.ReadFile(absPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return fmt.Errorf("file not found: %s", path)
+			return Errorf(ErrorNotFound, "file not found: %s", path)
 		}
 		return fmt.Errorf("failed to read file: %w", err)
 	}
@@ -336,7 +336,7 @@ func (ft *FileTools) Edit(ctx context.Context, path, oldText, newText string) er
 	// Count occurrences
 	count := strings.Count(content, oldText)
 	if count > 1 {
-		return fmt.Errorf("old text appears %d times in file; must be unique for safe editing", count)
+		return Errorf(ErrorBadInput, "old text appears %d times in file; must be unique for safe editing", count)
 	}
 
 	// Perform replacement
@@ -37,6 +37,7 @@ func (r *Registry) registerFindEntity() {
 
 	r.Register(&Tool{
 		Name:        "ha_find_entity",
+		ReadOnly:    true,
 		Description: "Find a Home Assistant entity by description and area. Use this when the user refers to a device by description rather than entity_id. Returns the best matching entity or explains what was found.",
 		Parameters: map[string]any{
 			"type": "object",
@@ -88,8 +89,13 @@ func (r *Registry) executeFindEntityHandler(ctx context.Context, argsMap map[str
 		args.Domain = inferDomainFromDescription(args.Description)
 	}
 
+	ha, instance, _, err := r.haReadyClient(argsMap, "")
+	if err != nil {
+		return "", err
+	}
+
 	// Get entities, optionally filtered by domain
-	entities, err := r.ha.GetEntities(ctx, args.Domain)
+	entities, err := ha.GetEntities(ctx, args.Domain)
 	if err != nil {
 		return "", fmt.Errorf("get entities: %w", err)
 	}
@@ -101,7 +107,7 @@ func (r *Registry) executeFindEntityHandler(ctx context.Context, argsMap map[str
 	}
 	var metadata *haEntityMetadataBundle
 	if lookupInclude.Any() {
-		metadata, err = fetchHAEntityMetadataBundle(ctx, r.ha, lookupInclude)
+		metadata, err = fetchHAEntityMetadataBundle(ctx, ha, lookupInclude)
 		if err != nil {
 			return "", err
 		}
@@ -141,7 +147,7 @@ func (r *Registry) executeFindEntityHandler(ctx context.Context, argsMap map[str
 			}
 			name := e.FriendlyName
 			if name == "" {
-				name = e.EntityID
+				name = homeassistant.QualifyEntityID(instance, e.EntityID)
 			}
 			candidates = append(candidates, name)
 		}
@@ -157,7 +163,7 @@ func (r *Registry) executeFindEntityHandler(ctx context.Context, argsMap map[str
 	best := matches[0]
 	result := FindEntityResult{
 		Found:        true,
-		EntityID:     best.EntityID,
+		EntityID:     homeassistant.QualifyEntityID(instance, best.EntityID),
 		FriendlyName: best.FriendlyName,
 		Confidence:   best.Score,
 	}
@@ -186,7 +192,7 @@ func (r *Registry) executeFindEntityHandler(ctx context.Context, argsMap map[str
 	if len(matches) > 1 && matches[1].Score > 0.5 {
 		candidates := make([]string, 0, len(matches))
 		for _, m := range matches {
-			candidates = append(candidates, m.EntityID)
+			candidates = append(candidates, homeassistant.QualifyEntityID(instance, m.EntityID))
 		}
 		result.Candidates = candidates
 	}
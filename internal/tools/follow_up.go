@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/platform/scheduler"
+)
+
+// FollowUp declares that a tool's action has a delayed expected
+// outcome. After a successful call to a tool carrying a FollowUp, the
+// agent loop asks the registry to schedule a one-shot verification
+// wake Window later, so fire-and-forget actions ("I started the
+// dishwasher") are checked rather than assumed.
+type FollowUp struct {
+	// Window is how long after the action the outcome should be
+	// verified.
+	Window time.Duration
+
+	// Expect describes the outcome the action should produce, in
+	// terms the verifying run can check (e.g. "the dishwasher reports
+	// its cycle complete").
+	Expect string
+
+	// Check is an optional Home Assistant template that renders true
+	// once the expected outcome has occurred. When set, the wake
+	// evaluates it first and only runs the verification when the
+	// outcome is missing or the check cannot be evaluated.
+	Check string
+}
+
+// FollowUpCheckKey is the wake payload field carrying a follow-up's
+// [FollowUp.Check] template.
+const FollowUpCheckKey = "follow_up_check"
+
+// toolFollowUps holds follow-up declarations keyed by tool name. Like
+// [toolRateLimiter], one set is created with each root registry and
+// shared by every copy derived from it, and it lives apart from the
+// [Tool] values so re-registering a tool keeps its declaration.
+type toolFollowUps struct {
+	mu     sync.RWMutex
+	byName map[string]FollowUp
+}
+
+// followUpSet returns the registry's follow-up declarations, creating
+// the set for a registry built without one.
+func (r *Registry) followUpSet() *toolFollowUps {
+	r.toolsMu.RLock()
+	fs := r.followUps
+	r.toolsMu.RUnlock()
+	if fs != nil {
+		return fs
+	}
+	r.toolsMu.Lock()
+	defer r.toolsMu.Unlock()
+	if r.followUps == nil {
+		r.followUps = &toolFollowUps{}
+	}
+	return r.followUps
+}
+
+// DeclareFollowUp opts the named tool into automatic follow-up
+// verification. The tool must already be registered.
+func (r *Registry) DeclareFollowUp(name string, f FollowUp) error {
+	if r.Get(name) == nil {
+		return &ErrToolUnavailable{ToolName: name}
+	}
+	if f.Window <= 0 {
+		return fmt.Errorf("follow-up for %s: window must be positive", name)
+	}
+	f.Expect = strings.TrimSpace(f.Expect)
+	f.Check = strings.TrimSpace(f.Check)
+	fs := r.followUpSet()
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.byName == nil {
+		fs.byName = make(map[string]FollowUp)
+	}
+	fs.byName[name] = f
+	return nil
+}
+
+// followUp returns the named tool's declared follow-up.
+func (r *Registry) followUp(name string) (FollowUp, bool) {
+	fs := r.followUpSet()
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	f, ok := fs.byName[name]
+	return f, ok
+}
+
+// HasFollowUp reports whether the named tool declared a [FollowUp].
+func (r *Registry) HasFollowUp(name string) bool {
+	_, ok := r.followUp(name)
+	return ok
+}
+
+// ScheduleFollowUp creates the verification wake for a completed call
+// to a tool with a declared [FollowUp]. It returns nil, nil when the
+// tool has no follow-up. conversationID records which conversation
+// took the action; the wake runs in it so the check's outcome lands
+// alongside the action.
+func (r *Registry) ScheduleFollowUp(ctx context.Context, conversationID, name, argsJSON, result string) (*scheduler.Task, error) {
+	f, ok := r.followUp(name)
+	if !ok {
+		return nil, nil
+	}
+	if r.scheduler == nil {
+		return nil, fmt.Errorf("scheduler not configured")
+	}
+
+	now := time.Now()
+	due := now.Add(f.Window)
+	data := map[string]any{
+		"message":         followUpWakeMessage(name, f, argsJSON, result, now),
+		"follow_up_tool":  name,
+		"conversation_id": conversationID,
+	}
+	if f.Check != "" {
+		data[FollowUpCheckKey] = f.Check
+	}
+	task := &scheduler.Task{
+		Name:     fmt.Sprintf("follow_up:%s:%d", name, now.UnixNano()),
+		Schedule: scheduler.Schedule{Kind: scheduler.ScheduleOnce, At: &due},
+		Payload: scheduler.Payload{
+			Kind: scheduler.PayloadWake,
+			Data: data,
+		},
+		Enabled:   true,
+		CreatedBy: "follow_up",
+	}
+	if err := r.scheduler.CreateTask(task); err != nil {
+		return nil, fmt.Errorf("schedule follow-up for %s: %w", name, err)
+	}
+	return task, nil
+}
+
+// followUpWakeMessage renders the prompt the verification wake runs
+// with: what was done, what should have happened, and what to do if
+// it did not.
+func followUpWakeMessage(name string, f FollowUp, argsJSON, result string, at time.Time) string {
+	expect := f.Expect
+	if expect == "" {
+		expect = "the action's intended effect is visible"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Follow-up check: %s ago you called %s.\n", f.Window, name)
+	if argsJSON != "" {
+		fmt.Fprintf(&sb, "Arguments: %s\n", argsJSON)
+	}
+	if result = strings.TrimSpace(result); result != "" {
+		runes := []rune(result)
+		if len(runes) > 300 {
+			result = string(runes[:300]) + "..."
+		}
+		fmt.Fprintf(&sb, "Result at the time: %s\n", result)
+	}
+	fmt.Fprintf(&sb, "Expected outcome: %s.\n", expect)
+	fmt.Fprintf(&sb, "Action taken at %s. Verify whether the expected outcome occurred. If it did, note it briefly and stop. If it did not, investigate and tell the operator what went wrong.", at.Format(time.RFC3339))
+	return sb.String()
+}
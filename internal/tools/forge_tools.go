@@ -48,7 +48,7 @@ func (r *Registry) registerForgeTools() {
 
 	r.Register(&Tool{
 		Name: "forge_issue_create",
-		Description: "Create a new issue on a code forge (GitHub/Gitea). " +
+		Description: "Create a new issue on a code forge (GitHub/GitLab/Gitea). " +
 			"Returns the issue number and URL.",
 		Parameters: map[string]any{
 			"type": "object",
@@ -223,6 +223,11 @@ func (r *Registry) registerForgeTools() {
 					"type":        "integer",
 					"description": "Issue or PR number",
 				},
+				"kind": map[string]any{
+					"type":        "string",
+					"enum":        []string{"issue", "pr"},
+					"description": "Whether number is an issue or a PR (merge request on GitLab, where the two are numbered separately)",
+				},
 				"body": map[string]any{
 					"type":        "string",
 					"description": "Comment body (markdown). Supports temp:LABEL references.",
@@ -232,7 +237,7 @@ func (r *Registry) registerForgeTools() {
 					"description": "Forge account name (default: primary)",
 				},
 			},
-			"required": []string{"repo", "number", "body"},
+			"required": []string{"repo", "number", "kind", "body"},
 		},
 		Handler: func(ctx context.Context, args map[string]any) (string, error) {
 			return r.forgeTools.HandleIssueComment(ctx, args)
@@ -442,11 +447,12 @@ func (r *Registry) registerForgeTools() {
 			"properties": map[string]any{
 				"repo":       map[string]any{"type": "string", "description": "Repository name"},
 				"number":     map[string]any{"type": "integer", "description": "Issue or PR number"},
+				"kind":       map[string]any{"type": "string", "enum": []string{"issue", "pr"}, "description": "Whether number is an issue or a PR (merge request on GitLab)"},
 				"comment_id": map[string]any{"type": "integer", "description": "React to a specific comment (omit for issue/PR)"},
 				"emoji":      map[string]any{"type": "string", "description": "Reaction: +1, -1, laugh, confused, heart, hooray, rocket, eyes"},
 				"account":    map[string]any{"type": "string", "description": "Forge account name"},
 			},
-			"required": []string{"repo", "number", "emoji"},
+			"required": []string{"repo", "number", "kind", "emoji"},
 		},
 		Handler: func(ctx context.Context, args map[string]any) (string, error) {
 			return r.forgeTools.HandleReact(ctx, args)
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
+)
+
+const haAreaEntitiesTruncationNote = "Result exceeded the tool byte cap; pass domain to narrow the listing."
+
+// haAreaEntitiesResult is the ha_area_entities payload: the area the
+// reference resolved to and the entity IDs assigned to it.
+type haAreaEntitiesResult struct {
+	AreaID    string   `json:"area_id"`
+	Area      string   `json:"area"`
+	Domain    string   `json:"domain,omitempty"`
+	Count     int      `json:"count"`
+	EntityIDs []string `json:"entity_ids"`
+}
+
+// registerHAAreaEntities wires the ha_area_entities tool: "the kitchen
+// lights" resolves through the area registry (name, alias, or ID) to
+// the entity IDs actually assigned there, replacing the guess-and-check
+// loop of ha_find_entity calls that natural-language control otherwise
+// takes.
+func (r *Registry) registerHAAreaEntities() {
+	if r.ha == nil {
+		return
+	}
+	r.Register(&Tool{
+		Name:     "ha_area_entities",
+		ReadOnly: true,
+		Description: "List the entity IDs assigned to a Home Assistant area, resolved by area name, alias, or ID (e.g. \"kitchen\"). " +
+			"Follows HA's own rule: an entity's area wins, otherwise it inherits its device's area; disabled and hidden entities are skipped. " +
+			"Pass domain to narrow (e.g. \"light\" for \"the kitchen lights\"), then act with ha_call_service. " +
+			"An unknown area fails with the list of known area names.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"area": map[string]any{
+					"type":        "string",
+					"description": "Area name, alias, or area_id (case-insensitive), e.g. \"Kitchen\".",
+				},
+				"domain": map[string]any{
+					"type":        "string",
+					"description": "Only return entities in this domain (e.g., light, switch, climate).",
+				},
+			},
+			"required": []string{"area"},
+		},
+		Handler: r.handleHAAreaEntities,
+	})
+}
+
+func (r *Registry) handleHAAreaEntities(ctx context.Context, args map[string]any) (string, error) {
+	area := strings.TrimSpace(stringArgValue(args, "area"))
+	if area == "" {
+		return "", fmt.Errorf("area is required")
+	}
+	domain := strings.TrimSuffix(strings.TrimSpace(stringArgValue(args, "domain")), ".")
+
+	ha, instance, _, err := r.haReadyClient(args, "")
+	if err != nil {
+		return "", err
+	}
+
+	resolved, err := ha.ResolveArea(ctx, area)
+	var unknown *homeassistant.UnknownAreaError
+	if errors.As(err, &unknown) {
+		return "", fmt.Errorf("unknown area %q; known areas: %s", unknown.Area, joinCapped(unknown.Known, 15))
+	}
+	if err != nil {
+		return "", err
+	}
+	ids, err := ha.EntitiesInArea(ctx, resolved.AreaID)
+	if err != nil {
+		return "", err
+	}
+
+	out := haAreaEntitiesResult{
+		AreaID:    resolved.AreaID,
+		Area:      resolved.Name,
+		Domain:    domain,
+		EntityIDs: []string{},
+	}
+	for _, id := range ids {
+		if domain != "" && entityDomainOf(id) != domain {
+			continue
+		}
+		out.EntityIDs = append(out.EntityIDs, homeassistant.QualifyEntityID(instance, id))
+	}
+	out.Count = len(out.EntityIDs)
+	return toIndentedJSONWithTruncationNote(out, haAreaEntitiesTruncationNote), nil
+}
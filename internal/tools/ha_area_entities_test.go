@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func areaEntitiesFake(t *testing.T) *fakeHAServer {
+	t.Helper()
+	fake := newFakeHAServer(t)
+	fake.areas = []map[string]any{
+		{"area_id": "kitchen", "name": "Kitchen", "aliases": []any{"cook room"}},
+		{"area_id": "office", "name": "Office"},
+	}
+	fake.devices = []map[string]any{
+		{"id": "dev-pendant", "name": "Pendant", "area_id": "kitchen"},
+	}
+	fake.entityRows = []map[string]any{
+		{"entity_id": "light.kitchen_ceiling", "area_id": "kitchen"},
+		{"entity_id": "light.pendant", "device_id": "dev-pendant"},
+		// An entity's own area overrides its device's.
+		{"entity_id": "light.pendant_nightlight", "device_id": "dev-pendant", "area_id": "office"},
+		{"entity_id": "switch.kettle", "area_id": "kitchen"},
+		{"entity_id": "light.kitchen_old", "area_id": "kitchen", "disabled_by": "user"},
+		{"entity_id": "sensor.kitchen_diag", "area_id": "kitchen", "hidden_by": "user"},
+	}
+	return fake
+}
+
+func TestHAAreaEntities_ResolvesByNameAndDevice(t *testing.T) {
+	reg := areaEntitiesFake(t).registry(t)
+
+	raw, err := reg.Execute(context.Background(), "ha_area_entities", `{"area":"kitchen","domain":"light"}`)
+	if err != nil {
+		t.Fatalf("ha_area_entities: %v", err)
+	}
+	var res haAreaEntitiesResult
+	if err := json.Unmarshal([]byte(raw), &res); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, raw)
+	}
+	want := []string{"light.kitchen_ceiling", "light.pendant"}
+	if res.AreaID != "kitchen" || res.Area != "Kitchen" || res.Count != 2 || !reflect.DeepEqual(res.EntityIDs, want) {
+		t.Errorf("result = %+v, want kitchen lights %v", res, want)
+	}
+}
+
+func TestHAAreaEntities_ResolvesAlias(t *testing.T) {
+	reg := areaEntitiesFake(t).registry(t)
+
+	raw, err := reg.Execute(context.Background(), "ha_area_entities", `{"area":"Cook Room"}`)
+	if err != nil {
+		t.Fatalf("ha_area_entities: %v", err)
+	}
+	var res haAreaEntitiesResult
+	if err := json.Unmarshal([]byte(raw), &res); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, raw)
+	}
+	if res.Count != 3 {
+		t.Errorf("count = %d, want 3 (disabled and hidden skipped): %v", res.Count, res.EntityIDs)
+	}
+}
+
+func TestHAAreaEntities_UnknownAreaListsKnown(t *testing.T) {
+	reg := areaEntitiesFake(t).registry(t)
+
+	_, err := reg.Execute(context.Background(), "ha_area_entities", `{"area":"garage"}`)
+	if err == nil {
+		t.Fatal("expected an error for an unknown area")
+	}
+	if !strings.Contains(err.Error(), "Kitchen") || !strings.Contains(err.Error(), "Office") {
+		t.Errorf("error = %v, want the known area names", err)
+	}
+}
@@ -160,6 +160,7 @@ func (r *Registry) registerHAAutomationTools() {
 
 	r.Register(&Tool{
 		Name:        "ha_registry_search",
+		ReadOnly:    true,
 		Description: "Search Home Assistant registry metadata across areas, labels, devices, and entities. Use this before authoring automations so triggers, conditions, actions, area placement, and labels use polished Home Assistant-native names and IDs instead of guesses.",
 		Parameters: map[string]any{
 			"type": "object",
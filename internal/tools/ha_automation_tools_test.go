@@ -26,6 +26,7 @@ type fakeHAServer struct {
 	traceDetails    map[string]map[string]any
 	servicePayloads []map[string]any
 	serviceChanged  []homeassistant.State
+	responseData    map[string]any // by "domain/service"; services that return data
 	targetTriggers  []string
 	targetConds     []string
 	targetServices  []string
@@ -75,6 +76,13 @@ func newFakeHAServer(t *testing.T) *fakeHAServer {
 
 func (f *fakeHAServer) registry(t *testing.T) *Registry {
 	t.Helper()
+	return NewRegistry(f.client(t), nil, nil)
+}
+
+// client returns a Home Assistant client connected to the fake over
+// both REST and WebSocket.
+func (f *fakeHAServer) client(t *testing.T) *homeassistant.Client {
+	t.Helper()
 
 	client := homeassistant.NewClient(f.server.URL, "test-token", nil)
 	ws := homeassistant.NewWSClient(f.server.URL, "test-token", nil)
@@ -87,7 +95,7 @@ func (f *fakeHAServer) registry(t *testing.T) *Registry {
 	}
 	t.Cleanup(func() { _ = ws.Close() })
 
-	return NewRegistry(client, nil, nil)
+	return client
 }
 
 func (f *fakeHAServer) handleServicesCatalog(w http.ResponseWriter, r *http.Request) {
@@ -169,6 +177,22 @@ func (f *fakeHAServer) handleServiceCall(w http.ResponseWriter, r *http.Request)
 
 	f.serviceCalls = append(f.serviceCalls, strings.TrimPrefix(r.URL.Path, "/api/services/"))
 	f.servicePayloads = append(f.servicePayloads, payload)
+
+	// Mirror HA's response-support checks: response services reject
+	// plain calls, and other services reject ?return_response.
+	response, returnsResponse := f.responseData[strings.TrimPrefix(r.URL.Path, "/api/services/")]
+	if r.URL.Query().Has("return_response") {
+		if !returnsResponse {
+			http.Error(w, `{"message":"Service does not support responses. Remove return_response from request."}`, http.StatusBadRequest)
+			return
+		}
+		writeJSON(f.t, w, map[string]any{"changed_states": []homeassistant.State{}, "service_response": response})
+		return
+	}
+	if returnsResponse {
+		http.Error(w, `{"message":"Service call requires responses but caller did not ask for responses. Add ?return_response to query parameters."}`, http.StatusBadRequest)
+		return
+	}
 	entityID, _ := payload["entity_id"].(string)
 	switch {
 	case strings.HasSuffix(r.URL.Path, "/turn_off"):
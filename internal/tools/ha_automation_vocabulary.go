@@ -71,7 +71,7 @@ func (r *Registry) handleHAAutomationVocabulary(ctx context.Context, args map[st
 	if !ok {
 		return "", fmt.Errorf("target must be an object like {\"area_id\": \"office\"}")
 	}
-	resolution, err := r.resolveServiceTarget(ctx, targetRaw)
+	resolution, err := r.resolveServiceTarget(ctx, r.ha, nil, "", targetRaw)
 	if err != nil {
 		return "", err
 	}
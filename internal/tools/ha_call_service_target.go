@@ -54,7 +54,14 @@ type targetResolution struct {
 // failure mode this tool exists to prevent. Each registry is fetched
 // once per call (and served from the client's TTL cache besides), no
 // matter how many values a key carries.
-func (r *Registry) resolveServiceTarget(ctx context.Context, raw map[string]any) (targetResolution, error) {
+//
+// ha is the client of the named instance (empty for the primary) that
+// [Registry.haForCall] chose from args. Target entity IDs resolve
+// against args the same way, so a qualified ID such as
+// "cabin:light.kitchen" or a bare one under an instance argument must
+// land on that instance; it is sent to it bare, since one service call
+// addresses one instance.
+func (r *Registry) resolveServiceTarget(ctx context.Context, ha *homeassistant.Client, args map[string]any, instance string, raw map[string]any) (targetResolution, error) {
 	for key := range raw {
 		if !slicesContains(haTargetKeys, key) {
 			return targetResolution{}, fmt.Errorf("unknown target key %q; valid keys: %s", key, strings.Join(haTargetKeys, ", "))
@@ -79,19 +86,26 @@ func (r *Registry) resolveServiceTarget(ctx context.Context, raw map[string]any)
 		switch key {
 		case "entity_id":
 			for _, v := range values {
+				_, inst, bare, err := r.haForCall(args, v)
+				if err != nil {
+					return targetResolution{}, fmt.Errorf("target.entity_id: %w", err)
+				}
+				if inst != instance {
+					return targetResolution{}, fmt.Errorf("target entity %q is on a different Home Assistant instance than the rest of the call; call each instance separately", v)
+				}
 				// Same phantom-success guard as the single-entity
 				// path, with the same recoverable outcome: HA accepts
 				// unknown entity ids and silently no-ops.
-				if _, err := r.ha.GetState(ctx, v); err != nil {
+				if _, err := ha.GetState(ctx, bare); err != nil {
 					if IsHAEntityNotFound(err) {
-						return targetResolution{Suggestion: SuggestEntityNotFound(ctx, r.ha, v)}, nil
+						return targetResolution{Suggestion: SuggestEntityNotFound(ctx, ha, bare)}, nil
 					}
 					return targetResolution{}, fmt.Errorf("verify target entity %q: %w", v, err)
 				}
-				out = append(out, v)
+				out = append(out, bare)
 			}
 		default:
-			out, err = r.resolveRegistryTargets(ctx, key, values)
+			out, err = r.resolveRegistryTargets(ctx, ha, key, values)
 			if err != nil {
 				return targetResolution{}, err
 			}
@@ -109,6 +123,30 @@ func (r *Registry) resolveServiceTarget(ctx context.Context, raw map[string]any)
 	return targetResolution{Resolved: resolved}, nil
 }
 
+// qualifyTargetEntities returns target with its entity IDs qualified
+// by instance, for reporting back in the same form the caller uses.
+// The primary instance's target is returned as is.
+func qualifyTargetEntities(instance string, target map[string]any) map[string]any {
+	if instance == "" || target == nil {
+		return target
+	}
+	out := make(map[string]any, len(target))
+	for k, v := range target {
+		out[k] = v
+	}
+	switch ids := target["entity_id"].(type) {
+	case string:
+		out["entity_id"] = homeassistant.QualifyEntityID(instance, ids)
+	case []string:
+		qualified := make([]string, len(ids))
+		for i, id := range ids {
+			qualified[i] = homeassistant.QualifyEntityID(instance, id)
+		}
+		out["entity_id"] = qualified
+	}
+	return out
+}
+
 // registryTargetEntry is one resolvable row of a registry: its ID plus
 // the names and aliases a caller may reference it by.
 type registryTargetEntry struct {
@@ -118,8 +156,8 @@ type registryTargetEntry struct {
 
 // resolveRegistryTargets resolves every value for one registry-backed
 // target key against a single registry fetch.
-func (r *Registry) resolveRegistryTargets(ctx context.Context, key string, values []string) ([]string, error) {
-	entries, kind, err := r.registryTargetEntries(ctx, key)
+func (r *Registry) resolveRegistryTargets(ctx context.Context, ha *homeassistant.Client, key string, values []string) ([]string, error) {
+	entries, kind, err := r.registryTargetEntries(ctx, ha, key)
 	if err != nil {
 		return nil, fmt.Errorf("resolve %s %q: %w", kind, values[0], err)
 	}
@@ -159,10 +197,10 @@ func matchRegistryTarget(entries []registryTargetEntry, value string) (string, b
 	return "", false
 }
 
-func (r *Registry) registryTargetEntries(ctx context.Context, key string) ([]registryTargetEntry, string, error) {
+func (r *Registry) registryTargetEntries(ctx context.Context, ha *homeassistant.Client, key string) ([]registryTargetEntry, string, error) {
 	switch key {
 	case "area_id":
-		areas, err := r.ha.GetAreas(ctx)
+		areas, err := ha.GetAreas(ctx)
 		if err != nil {
 			return nil, "area", err
 		}
@@ -172,7 +210,7 @@ func (r *Registry) registryTargetEntries(ctx context.Context, key string) ([]reg
 		}
 		return entries, "area", nil
 	case "floor_id":
-		floors, err := r.ha.GetFloorRegistry(ctx)
+		floors, err := ha.GetFloorRegistry(ctx)
 		if err != nil {
 			return nil, "floor", err
 		}
@@ -182,7 +220,7 @@ func (r *Registry) registryTargetEntries(ctx context.Context, key string) ([]reg
 		}
 		return entries, "floor", nil
 	case "label_id":
-		labels, err := r.ha.GetLabelRegistry(ctx)
+		labels, err := ha.GetLabelRegistry(ctx)
 		if err != nil {
 			return nil, "label", err
 		}
@@ -192,7 +230,7 @@ func (r *Registry) registryTargetEntries(ctx context.Context, key string) ([]reg
 		}
 		return entries, "label", nil
 	case "device_id":
-		devices, err := r.ha.GetDeviceRegistry(ctx)
+		devices, err := ha.GetDeviceRegistry(ctx)
 		if err != nil {
 			return nil, "device", err
 		}
@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
+)
+
+// haInstanceTools are the native HA tools that accept an optional
+// instance argument once more than one Home Assistant instance is
+// configured.
+var haInstanceTools = []string{"ha_get_state", "ha_list_entities", "ha_call_service", "ha_get_service_response", "ha_render_template", "ha_area_entities", "ha_find_entity", "ha_control_device"}
+
+// SetHomeAssistantInstances enables federated Home Assistant access.
+// With more than one instance configured, ha_get_state,
+// ha_list_entities, ha_call_service, ha_get_service_response,
+// ha_render_template, ha_area_entities, ha_find_entity, and
+// ha_control_device gain an optional instance parameter (defaulting to
+// the primary) and accept instance-qualified entity IDs such as
+// "cabin:light.kitchen". The automation tools and the remaining HA
+// helpers address the primary instance only.
+func (r *Registry) SetHomeAssistantInstances(instances *homeassistant.Instances) {
+	r.haInstances = instances
+	if instances == nil {
+		return
+	}
+	names := instances.Names()
+	if len(names) < 2 {
+		return
+	}
+	param := map[string]any{
+		"type": "string",
+		"enum": names,
+		"description": fmt.Sprintf("Home Assistant instance to address (default %q). Entity IDs from other instances are qualified as instance:entity_id (e.g., %q), which selects the instance without this argument.",
+			instances.Primary(), homeassistant.QualifyEntityID(names[1], "light.kitchen")),
+	}
+	for _, name := range haInstanceTools {
+		tool := r.Get(name)
+		if tool == nil {
+			continue
+		}
+		if props, ok := tool.Parameters["properties"].(map[string]any); ok {
+			props["instance"] = param
+		}
+	}
+}
+
+// haForCall selects the Home Assistant client for a tool call and
+// strips any instance qualifier from entityID. An instance-qualified
+// entity ID selects its instance; otherwise the instance argument
+// does, defaulting to the primary. A qualified ID that disagrees with
+// an explicit instance argument is an error. Without configured
+// instances every call goes to the primary client. The returned
+// instance name is empty for the primary.
+func (r *Registry) haForCall(args map[string]any, entityID string) (ha *homeassistant.Client, instance, bare string, err error) {
+	requested := strings.TrimSpace(stringArgValue(args, "instance"))
+	qualifier, bare := homeassistant.SplitEntityID(entityID)
+	if r.haInstances == nil {
+		if requested != "" || qualifier != "" {
+			return nil, "", "", fmt.Errorf("multiple home assistant instances are not configured")
+		}
+		return r.ha, "", entityID, nil
+	}
+	if qualifier != "" && requested != "" && qualifier != requested {
+		return nil, "", "", fmt.Errorf("entity_id %q belongs to instance %q, not %q", entityID, qualifier, requested)
+	}
+	if qualifier == "" {
+		qualifier = requested
+	}
+	ha, err = r.haInstances.Client(qualifier)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if qualifier == r.haInstances.Primary() {
+		qualifier = ""
+	}
+	return ha, qualifier, bare, nil
+}
+
+// haReadyClient is [Registry.haForCall] plus the configured and
+// reachable checks every HA tool handler begins with.
+func (r *Registry) haReadyClient(args map[string]any, entityID string) (ha *homeassistant.Client, instance, bare string, err error) {
+	if r.ha == nil {
+		return nil, "", "", fmt.Errorf("home assistant not configured")
+	}
+	ha, instance, bare, err = r.haForCall(args, entityID)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if !ha.IsReady() {
+		if instance != "" {
+			return nil, "", "", fmt.Errorf("home assistant instance %q is currently unreachable (reconnecting in background)", instance)
+		}
+		return nil, "", "", fmt.Errorf("home assistant is currently unreachable (reconnecting in background)")
+	}
+	return ha, instance, bare, nil
+}
@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
+)
+
+// federatedRegistry returns a registry whose primary instance "home"
+// and secondary instance "cabin" each have a light.kitchen.
+func federatedRegistry(t *testing.T) (*Registry, *fakeHAServer, *fakeHAServer) {
+	t.Helper()
+	home := newFakeHAServer(t)
+	home.states = []homeassistant.State{
+		{EntityID: "light.kitchen", State: "on", Attributes: map[string]any{"friendly_name": "Home Kitchen"}},
+	}
+	cabin := newFakeHAServer(t)
+	cabin.states = []homeassistant.State{
+		{EntityID: "light.kitchen", State: "off", Attributes: map[string]any{"friendly_name": "Cabin Kitchen"}},
+	}
+
+	reg := home.registry(t)
+	instances := homeassistant.NewInstances("home", reg.ha)
+	if err := instances.Add("cabin", cabin.client(t)); err != nil {
+		t.Fatalf("Add(cabin): %v", err)
+	}
+	reg.SetHomeAssistantInstances(instances)
+	return reg, home, cabin
+}
+
+func TestHACallService_InstanceQualifiedEntityRoutesToInstance(t *testing.T) {
+	reg, home, cabin := federatedRegistry(t)
+	cabin.serviceChanged = []homeassistant.State{{EntityID: "light.kitchen", State: "on"}}
+
+	raw, err := reg.Execute(context.Background(), "ha_call_service", `{"domain":"light","service":"turn_on","entity_id":"cabin:light.kitchen"}`)
+	if err != nil {
+		t.Fatalf("qualified call: %v", err)
+	}
+	if len(home.servicePayloads) != 0 {
+		t.Errorf("primary instance received %v, want nothing", home.servicePayloads)
+	}
+	if len(cabin.servicePayloads) != 1 || cabin.servicePayloads[0]["entity_id"] != "light.kitchen" {
+		t.Fatalf("cabin payloads = %v, want bare light.kitchen", cabin.servicePayloads)
+	}
+	res := decodeCallResult(t, raw)
+	if res.EntityID != "cabin:light.kitchen" {
+		t.Errorf("entity_id = %q, want the qualified ID echoed", res.EntityID)
+	}
+	if len(res.Changed) != 1 || res.Changed[0] != "cabin:light.kitchen" {
+		t.Errorf("changed = %v, want qualified cabin:light.kitchen", res.Changed)
+	}
+}
+
+func TestHACallService_InstanceArgumentAndDefault(t *testing.T) {
+	reg, home, cabin := federatedRegistry(t)
+
+	if _, err := reg.Execute(context.Background(), "ha_call_service", `{"domain":"light","service":"turn_off","entity_id":"light.kitchen"}`); err != nil {
+		t.Fatalf("unqualified call: %v", err)
+	}
+	if len(home.servicePayloads) != 1 || len(cabin.servicePayloads) != 0 {
+		t.Fatalf("unqualified call should default to primary: home=%v cabin=%v", home.servicePayloads, cabin.servicePayloads)
+	}
+
+	if _, err := reg.Execute(context.Background(), "ha_call_service", `{"domain":"light","service":"turn_off","entity_id":"light.kitchen","instance":"cabin"}`); err != nil {
+		t.Fatalf("instance argument call: %v", err)
+	}
+	if len(cabin.servicePayloads) != 1 {
+		t.Errorf("instance argument should route to cabin: cabin=%v", cabin.servicePayloads)
+	}
+
+	for name, args := range map[string]string{
+		"unknown instance":     `{"domain":"light","service":"turn_off","entity_id":"barn:light.kitchen"}`,
+		"conflicting instance": `{"domain":"light","service":"turn_off","entity_id":"cabin:light.kitchen","instance":"home"}`,
+	} {
+		if _, err := reg.Execute(context.Background(), "ha_call_service", args); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestHACallService_QualifiedTargetEntityRoutesToInstance(t *testing.T) {
+	reg, home, cabin := federatedRegistry(t)
+
+	raw, err := reg.Execute(context.Background(), "ha_call_service", `{"domain":"light","service":"turn_on","target":{"entity_id":["cabin:light.kitchen"]}}`)
+	if err != nil {
+		t.Fatalf("qualified target call: %v", err)
+	}
+	if len(home.servicePayloads) != 0 {
+		t.Errorf("primary instance received %v, want nothing", home.servicePayloads)
+	}
+	if len(cabin.servicePayloads) != 1 || cabin.servicePayloads[0]["entity_id"] != "light.kitchen" {
+		t.Fatalf("cabin payloads = %v, want bare light.kitchen", cabin.servicePayloads)
+	}
+	if res := decodeCallResult(t, raw); res.Target["entity_id"] != "cabin:light.kitchen" {
+		t.Errorf("target = %v, want the qualified ID echoed", res.Target)
+	}
+
+	// One call addresses one instance.
+	if _, err := reg.Execute(context.Background(), "ha_call_service", `{"domain":"light","service":"turn_on","target":{"entity_id":["cabin:light.kitchen","light.kitchen"]}}`); err == nil {
+		t.Error("mixed-instance target: expected error")
+	}
+}
+
+func TestHAGetState_QualifiedEntityReportsQualifiedID(t *testing.T) {
+	reg, _, _ := federatedRegistry(t)
+
+	out, err := reg.Execute(context.Background(), "ha_get_state", `{"entity_id":"cabin:light.kitchen"}`)
+	if err != nil {
+		t.Fatalf("ha_get_state: %v", err)
+	}
+	if !strings.Contains(out, "cabin:light.kitchen") || !strings.Contains(out, "Cabin Kitchen") {
+		t.Errorf("output should describe the cabin entity by qualified ID:\n%s", out)
+	}
+}
+
+func TestHAListEntities_InstanceQualifiesIDs(t *testing.T) {
+	reg, _, _ := federatedRegistry(t)
+
+	raw, err := reg.Execute(context.Background(), "ha_list_entities", `{"domain":"light","instance":"cabin"}`)
+	if err != nil {
+		t.Fatalf("ha_list_entities: %v", err)
+	}
+	var res haListEntitiesResult
+	if err := json.Unmarshal([]byte(raw), &res); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, raw)
+	}
+	if len(res.Items) != 1 || res.Items[0].EntityID != "cabin:light.kitchen" {
+		t.Errorf("items = %+v, want cabin:light.kitchen", res.Items)
+	}
+}
+
+func TestHAFindEntity_InstanceQualifiesMatch(t *testing.T) {
+	reg, _, _ := federatedRegistry(t)
+
+	raw, err := reg.Execute(context.Background(), "ha_find_entity", `{"description":"kitchen light","instance":"cabin"}`)
+	if err != nil {
+		t.Fatalf("ha_find_entity: %v", err)
+	}
+	var res FindEntityResult
+	if err := json.Unmarshal([]byte(raw), &res); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, raw)
+	}
+	if !res.Found || res.EntityID != "cabin:light.kitchen" || res.FriendlyName != "Cabin Kitchen" {
+		t.Errorf("result = %+v, want the cabin kitchen light by qualified ID", res)
+	}
+}
+
+func TestSetHomeAssistantInstances_AdvertisesInstanceParameter(t *testing.T) {
+	reg, _, _ := federatedRegistry(t)
+
+	for _, name := range haInstanceTools {
+		props := reg.Get(name).Parameters["properties"].(map[string]any)
+		param, ok := props["instance"].(map[string]any)
+		if !ok {
+			t.Errorf("%s: missing instance parameter", name)
+			continue
+		}
+		if enum, _ := param["enum"].([]string); len(enum) != 2 || enum[0] != "home" || enum[1] != "cabin" {
+			t.Errorf("%s: instance enum = %v, want [home cabin]", name, param["enum"])
+		}
+	}
+}
@@ -38,11 +38,15 @@ type haServicesDetail struct {
 type haServiceDetail struct {
 	// Service is the callable domain.service identifier — exactly what
 	// ha_call_service takes.
-	Service       string           `json:"service"`
-	Name          string           `json:"name,omitempty"`
-	Description   string           `json:"description,omitempty"`
-	AcceptsTarget bool             `json:"accepts_target"`
-	Fields        []haServiceField `json:"fields,omitempty"`
+	Service       string `json:"service"`
+	Name          string `json:"name,omitempty"`
+	Description   string `json:"description,omitempty"`
+	AcceptsTarget bool   `json:"accepts_target"`
+	// ReturnsResponse is "required" for query services that must be
+	// called with ha_get_service_response, "optional" for services
+	// that act and can also return data, and empty otherwise.
+	ReturnsResponse string           `json:"returns_response,omitempty"`
+	Fields          []haServiceField `json:"fields,omitempty"`
 }
 
 type haServiceField struct {
@@ -64,10 +68,11 @@ func (r *Registry) registerHAListServices() {
 		return
 	}
 	r.Register(&Tool{
-		Name: "ha_list_services",
+		Name:     "ha_list_services",
+		ReadOnly: true,
 		Description: "Discover what Home Assistant services can be called. " +
 			"Without arguments: a directory of every domain and its service names. " +
-			"With domain: full detail for that domain's services — description, fields (with required/example), and whether the service accepts a target block (area/floor/label/device/entity). " +
+			"With domain: full detail for that domain's services — description, fields (with required/example), whether the service accepts a target block (area/floor/label/device/entity), and whether it returns response data (returns_response). " +
 			"With a specific service — either domain plus service, or just service in the combined \"light.turn_on\" form — returns that single service. " +
 			"Use this before ha_call_service when unsure of a service name or its fields.",
 		Parameters: map[string]any{
@@ -176,6 +181,12 @@ func haServicesDomainResult(d homeassistant.ServiceDomain, service string) (stri
 			Description:   desc.Description,
 			AcceptsTarget: desc.Target != nil,
 		}
+		if desc.Response != nil {
+			detail.ReturnsResponse = "required"
+			if desc.Response.Optional {
+				detail.ReturnsResponse = "optional"
+			}
+		}
 		fieldKeys := make([]string, 0, len(desc.Fields))
 		for k := range desc.Fields {
 			fieldKeys = append(fieldKeys, k)
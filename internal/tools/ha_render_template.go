@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
+)
+
+// maxHATemplateBytes caps the template source a caller may submit.
+// Templates that need more than this are programs, not queries.
+const maxHATemplateBytes = 8 * 1024
+
+// registerHARenderTemplate wires the ha_render_template tool: Jinja
+// evaluated by Home Assistant itself, so aggregate questions ("average
+// temperature across the climate sensors", "which doors are open")
+// are one call with HA doing the arithmetic, instead of a dozen state
+// reads and model-side math.
+func (r *Registry) registerHARenderTemplate() {
+	if r.ha == nil {
+		return
+	}
+	r.Register(&Tool{
+		Name:     "ha_render_template",
+		ReadOnly: true,
+		Description: "Evaluate a Home Assistant Jinja template server-side and return the rendered text. " +
+			"Use for aggregate or computed questions across many entities in one call — " +
+			"e.g. {{ states.sensor | selectattr('attributes.device_class', 'eq', 'temperature') | map(attribute='state') | map('float', 0) | average | round(1) }} " +
+			"or {{ states.binary_sensor | selectattr('state', 'eq', 'on') | selectattr('attributes.device_class', 'eq', 'door') | map(attribute='name') | join(', ') }}. " +
+			"Has access to states(), state_attr(), area_entities(), now(), and the rest of HA's template environment. " +
+			"A template error returns HA's message so the template can be fixed and retried. " +
+			"Output is capped at 32 KB — render a summary, not a state dump.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"template": map[string]any{
+					"type":        "string",
+					"description": "The Jinja template to render.",
+				},
+			},
+			"required": []string{"template"},
+		},
+		Handler: r.handleHARenderTemplate,
+	})
+}
+
+func (r *Registry) handleHARenderTemplate(ctx context.Context, args map[string]any) (string, error) {
+	template := stringArgValue(args, "template")
+	if strings.TrimSpace(template) == "" {
+		return "", fmt.Errorf("template is required")
+	}
+	if len(template) > maxHATemplateBytes {
+		return "", fmt.Errorf("template is %d bytes; the limit is %d", len(template), maxHATemplateBytes)
+	}
+
+	ha, _, _, err := r.haReadyClient(args, "")
+	if err != nil {
+		return "", err
+	}
+
+	rendered, err := ha.RenderTemplate(ctx, template)
+	if errors.Is(err, homeassistant.ErrTemplateOutputTooLarge) {
+		return "", fmt.Errorf("%w; aggregate or filter inside the template (count, join a subset, average) instead of rendering raw states", err)
+	}
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(rendered) == "" {
+		return "(template rendered empty output)", nil
+	}
+	return rendered, nil
+}
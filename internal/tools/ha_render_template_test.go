@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
+)
+
+func templateRegistry(t *testing.T, handler http.HandlerFunc) *Registry {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewRegistry(homeassistant.NewClient(server.URL, "test-token", nil), nil, nil)
+}
+
+func TestHARenderTemplate_ReturnsRenderedText(t *testing.T) {
+	reg := templateRegistry(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("70.8"))
+	})
+
+	out, err := reg.Execute(context.Background(), "ha_render_template", `{"template":"{{ [70.5, 71.1] | average }}"}`)
+	if err != nil {
+		t.Fatalf("ha_render_template: %v", err)
+	}
+	if out != "70.8" {
+		t.Errorf("output = %q, want 70.8", out)
+	}
+}
+
+func TestHARenderTemplate_TemplateErrorReachesCaller(t *testing.T) {
+	reg := templateRegistry(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"Error rendering template: UndefinedError: 'sensorz' is undefined"}`))
+	})
+
+	_, err := reg.Execute(context.Background(), "ha_render_template", `{"template":"{{ sensorz }}"}`)
+	if err == nil || !strings.Contains(err.Error(), "'sensorz' is undefined") {
+		t.Errorf("error = %v, want HA's template error text", err)
+	}
+}
+
+func TestHARenderTemplate_RejectsOversizedTemplate(t *testing.T) {
+	called := false
+	reg := templateRegistry(t, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	args := `{"template":"` + strings.Repeat("x", maxHATemplateBytes+1) + `"}`
+	if _, err := reg.Execute(context.Background(), "ha_render_template", args); err == nil {
+		t.Error("expected an error for an oversized template")
+	}
+	if called {
+		t.Error("oversized template reached Home Assistant")
+	}
+}
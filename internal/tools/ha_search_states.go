@@ -72,7 +72,8 @@ func (r *Registry) registerHASearchStates() {
 		return
 	}
 	r.Register(&Tool{
-		Name: "ha_search_states",
+		Name:     "ha_search_states",
+		ReadOnly: true,
 		Description: "Search Home Assistant entities by live state across all domains. " +
 			"Answers 'what's on right now', 'what doors are open', 'which sensors are unavailable', 'what batteries are low'. " +
 			"Filter by state value(s), by a numeric attribute predicate (e.g. battery < 20, temperature > 80), by domain, and/or by area — filters compose (AND). " +
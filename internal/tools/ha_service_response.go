@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
+)
+
+const haServiceResponseTruncationNote = "Result exceeded the tool byte cap; narrow the request (fewer entities, a shorter time window) for a smaller payload."
+
+// haServiceResponseResult is the ha_get_service_response payload: the
+// service that ran and its response data verbatim.
+type haServiceResponseResult struct {
+	Service  string          `json:"service"`
+	Response json.RawMessage `json:"response"`
+}
+
+// registerHAServiceResponse wires the ha_get_service_response tool:
+// service calls that return structured data (calendar agendas,
+// weather forecasts, to-do items) rather than acting on devices.
+// ha_call_service reports only changed states, so the data these
+// services produce would otherwise be unreachable.
+func (r *Registry) registerHAServiceResponse() {
+	if r.ha == nil {
+		return
+	}
+	r.Register(&Tool{
+		Name:     "ha_get_service_response",
+		ReadOnly: true,
+		Description: "Call a Home Assistant service that returns data and read its response — e.g. calendar.get_events for the agenda, weather.get_forecasts for a forecast, todo.get_items for a list. " +
+			"The response is returned verbatim, usually keyed by entity ID. " +
+			"Only pure query services work here (ha_list_services shows returns_response: required); a service that acts — including ones whose response is optional, such as scripts — fails with an error instead of running, so call those with ha_call_service.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"domain": map[string]any{
+					"type":        "string",
+					"description": "The service domain (e.g., calendar, weather, todo).",
+				},
+				"service": map[string]any{
+					"type":        "string",
+					"description": "The service to call (e.g., get_events, get_forecasts). Accepts the combined \"calendar.get_events\" form with no domain argument.",
+				},
+				"entity_id": map[string]any{
+					"type":        []string{"string", "array"},
+					"items":       map[string]any{"type": "string"},
+					"description": "The entity or entities to query (e.g., \"calendar.family\" or [\"calendar.family\", \"calendar.work\"]).",
+				},
+				"data": map[string]any{
+					"type":        "object",
+					"description": "Service data, e.g. {\"start_date_time\": \"2026-05-01T00:00:00\", \"end_date_time\": \"2026-05-08T00:00:00\"} for calendar.get_events or {\"type\": \"daily\"} for weather.get_forecasts.",
+				},
+			},
+			"required": []string{"service"},
+		},
+		Handler: r.handleHAServiceResponse,
+	})
+}
+
+func (r *Registry) handleHAServiceResponse(ctx context.Context, args map[string]any) (string, error) {
+	domain := strings.TrimSpace(stringArgValue(args, "domain"))
+	service := strings.TrimSpace(stringArgValue(args, "service"))
+	if d, s, ok := strings.Cut(service, "."); ok {
+		if domain != "" && domain != d {
+			return "", fmt.Errorf("service %q names domain %q but domain argument says %q — drop one or make them agree", service, d, domain)
+		}
+		domain, service = d, s
+	}
+	if domain == "" || service == "" {
+		return "", fmt.Errorf("domain and service are required (or the combined \"domain.service\" form)")
+	}
+
+	entityIDs := stringListArg(args["entity_id"])
+	first := ""
+	if len(entityIDs) > 0 {
+		first = entityIDs[0]
+	}
+	ha, instance, _, err := r.haReadyClient(args, first)
+	if err != nil {
+		return "", err
+	}
+
+	data := map[string]any{}
+	if extra, ok := args["data"].(map[string]any); ok {
+		for k, v := range extra {
+			if k == "entity_id" {
+				return "", fmt.Errorf("data.entity_id is addressing, not service data — use the entity_id argument")
+			}
+			data[k] = v
+		}
+	}
+	if len(entityIDs) > 0 {
+		bare := make([]string, len(entityIDs))
+		for i, id := range entityIDs {
+			_, inst, b, err := r.haForCall(args, id)
+			if err != nil {
+				return "", err
+			}
+			if inst != instance {
+				return "", fmt.Errorf("entity_id %q is on a different Home Assistant instance than %q; query each instance separately", id, first)
+			}
+			bare[i] = b
+		}
+		if len(bare) == 1 {
+			data["entity_id"] = bare[0]
+		} else {
+			data["entity_id"] = bare
+		}
+	}
+
+	if err := checkQueryOnlyService(ctx, ha, domain, service); err != nil {
+		return "", err
+	}
+
+	response, err := ha.CallServiceWithResponse(ctx, domain, service, data)
+	if errors.Is(err, homeassistant.ErrNoServiceResponse) {
+		return "", fmt.Errorf("%w; it only acts — use ha_call_service to run it, or ha_list_services to find services with returns_response", err)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return toIndentedJSONWithTruncationNote(haServiceResponseResult{
+		Service:  domain + "." + service,
+		Response: response,
+	}, haServiceResponseTruncationNote), nil
+}
+
+// checkQueryOnlyService refuses services whose response data is
+// optional. Those services act (script.turn_on runs the script) and
+// merely may return data, so running them here would skip the
+// confidence gate and parallel read-only batching would treat them as
+// side-effect-free. The catalog is authoritative; when it can't be
+// read the call fails closed.
+func checkQueryOnlyService(ctx context.Context, ha *homeassistant.Client, domain, service string) error {
+	catalog, err := ha.GetServices(ctx)
+	if err != nil {
+		return fmt.Errorf("check %s.%s response support: %w", domain, service, err)
+	}
+	for _, d := range catalog {
+		if d.Domain != domain {
+			continue
+		}
+		desc, ok := d.Services[service]
+		if ok && desc.Response != nil && desc.Response.Optional {
+			return fmt.Errorf("%s.%s acts and only optionally returns data; call it with ha_call_service", domain, service)
+		}
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
+)
+
+func TestHAGetServiceResponse_ReturnsResponseData(t *testing.T) {
+	fake := newFakeHAServer(t)
+	fake.responseData = map[string]any{
+		"calendar/get_events": map[string]any{
+			"calendar.family": map[string]any{
+				"events": []any{map[string]any{"summary": "Dentist", "start": "2026-05-04T09:00:00"}},
+			},
+		},
+	}
+	reg := fake.registry(t)
+
+	raw, err := reg.Execute(context.Background(), "ha_get_service_response",
+		`{"service":"calendar.get_events","entity_id":"calendar.family","data":{"duration":{"days":7}}}`)
+	if err != nil {
+		t.Fatalf("ha_get_service_response: %v", err)
+	}
+	var res haServiceResponseResult
+	if err := json.Unmarshal([]byte(raw), &res); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, raw)
+	}
+	if res.Service != "calendar.get_events" || !strings.Contains(string(res.Response), "Dentist") {
+		t.Errorf("result = %s", raw)
+	}
+
+	payload := fake.servicePayloads[0]
+	if payload["entity_id"] != "calendar.family" || payload["duration"] == nil {
+		t.Errorf("payload = %v, want entity_id and duration", payload)
+	}
+}
+
+func TestHAGetServiceResponse_MultipleEntities(t *testing.T) {
+	fake := newFakeHAServer(t)
+	fake.responseData = map[string]any{"weather/get_forecasts": map[string]any{}}
+	reg := fake.registry(t)
+
+	if _, err := reg.Execute(context.Background(), "ha_get_service_response",
+		`{"domain":"weather","service":"get_forecasts","entity_id":["weather.home","weather.cabin"],"data":{"type":"daily"}}`); err != nil {
+		t.Fatalf("ha_get_service_response: %v", err)
+	}
+	ids, _ := fake.servicePayloads[0]["entity_id"].([]any)
+	if len(ids) != 2 {
+		t.Errorf("entity_id = %v, want both entities", fake.servicePayloads[0]["entity_id"])
+	}
+}
+
+func TestHAGetServiceResponse_ActionOnlyServiceErrors(t *testing.T) {
+	fake := newFakeHAServer(t)
+	reg := fake.registry(t)
+
+	_, err := reg.Execute(context.Background(), "ha_get_service_response",
+		`{"domain":"light","service":"turn_on","entity_id":"light.kitchen"}`)
+	if err == nil {
+		t.Fatal("expected an error for a service without response data")
+	}
+	if !strings.Contains(err.Error(), "does not return response data") || !strings.Contains(err.Error(), "ha_call_service") {
+		t.Errorf("error = %v, want a no-response error pointing at ha_call_service", err)
+	}
+}
+
+func TestHAGetServiceResponse_OptionalResponseServiceRefused(t *testing.T) {
+	fake := newFakeHAServer(t)
+	fake.services = []homeassistant.ServiceDomain{{
+		Domain: "script",
+		Services: map[string]homeassistant.ServiceDescription{
+			"turn_on": {Response: &homeassistant.ServiceResponseSupport{Optional: true}},
+		},
+	}}
+	fake.responseData = map[string]any{"script/turn_on": map[string]any{}}
+	reg := fake.registry(t)
+
+	_, err := reg.Execute(context.Background(), "ha_get_service_response",
+		`{"service":"script.turn_on","entity_id":"script.garage_open"}`)
+	if err == nil || !strings.Contains(err.Error(), "ha_call_service") {
+		t.Fatalf("error = %v, want a refusal pointing at ha_call_service", err)
+	}
+	if len(fake.serviceCalls) != 0 {
+		t.Errorf("service calls = %v, want none for a refused acting service", fake.serviceCalls)
+	}
+}
+
+func TestHAGetServiceResponse_ArgumentErrors(t *testing.T) {
+	fake := newFakeHAServer(t)
+	reg := fake.registry(t)
+
+	for name, args := range map[string]string{
+		"missing domain":     `{"service":"get_events"}`,
+		"disagreeing domain": `{"domain":"weather","service":"calendar.get_events"}`,
+		"addressing in data": `{"service":"calendar.get_events","data":{"entity_id":"calendar.family"}}`,
+	} {
+		if _, err := reg.Execute(context.Background(), "ha_get_service_response", args); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if len(fake.serviceCalls) != 0 {
+		t.Errorf("invalid calls reached HA: %v", fake.serviceCalls)
+	}
+}
+
+func TestHACallService_ResponseOnlyServicePointsAtResponseTool(t *testing.T) {
+	fake := newFakeHAServer(t)
+	fake.states = []homeassistant.State{{EntityID: "calendar.family", State: "off"}}
+	fake.responseData = map[string]any{"calendar/get_events": map[string]any{}}
+	reg := fake.registry(t)
+
+	_, err := reg.Execute(context.Background(), "ha_call_service",
+		`{"domain":"calendar","service":"get_events","entity_id":"calendar.family"}`)
+	if err == nil || !strings.Contains(err.Error(), "ha_get_service_response") {
+		t.Errorf("error = %v, want a pointer to ha_get_service_response", err)
+	}
+}
@@ -87,7 +87,7 @@ func (r *Registry) registerLoopRuntimeTools() {
 				},
 				"reason": map[string]any{
 					"type":        "string",
-					"description": "Optional short explanation of why this duration was chosen. Logged for operator visibility.",
+					"description": "Optional short explanation of why this duration was chosen. Logged and kept with the iteration in the loop's history for operator review.",
 				},
 			},
 			"required": []string{"duration"},
@@ -285,6 +285,7 @@ func (r *Registry) handleSetNextSleep(ctx context.Context, args map[string]any)
 	reason := toolargs.TrimmedString(args, "reason")
 	clamped := applied != requested
 	live.SetNextSleep(applied)
+	live.RecordSleepRequest(requested, reason)
 
 	logging.Logger(ctx).Info(
 		"loop next sleep set",
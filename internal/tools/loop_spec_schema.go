@@ -131,6 +131,17 @@ func loopSpecSchema(description string) map[string]any {
 						"transitions_window_seconds": map[string]any{"type": "integer", "description": "Bound the transition log to a trailing window in seconds; usable with or without transitions (still capped)."},
 						"wake":                       map[string]any{"type": "boolean", "description": "Wake the owning loop when the entity changes — debounced/coalesced via the shared queue; capture derives automatically. Incompatible with requires_tag and registry targets."},
 						"wake_debounce_seconds":      map[string]any{"type": "integer", "description": "Coalescing window before the wake fires; the loop's cadence follows its twitchiest wake subscription."},
+						"wake_when": map[string]any{
+							"type":        "object",
+							"description": "Alert condition: wake only on the change that enters it — state equals a value, or a numeric state above and/or below thresholds. from requires the previous state: with state it is one specific transition, alone it fires on leaving that state. Requires wake.",
+							"properties": map[string]any{
+								"state": map[string]any{"type": "string"},
+								"from":  map[string]any{"type": "string"},
+								"above": map[string]any{"type": "number"},
+								"below": map[string]any{"type": "number"},
+							},
+						},
+						"wake_cooldown_seconds": map[string]any{"type": "integer", "description": "Minimum seconds between wake_when alerts for one entity (default 600)."},
 					},
 				},
 			},
@@ -16,7 +16,7 @@ func (r *Registry) handleModelRouteExplain(_ context.Context, args map[string]an
 
 	toolCount, ok := toolargs.IntOK(args, "tool_count")
 	if !ok || toolCount < 0 {
-		toolCount = len(r.tools)
+		toolCount = len(r.AllToolNames())
 	}
 	priority := mrParseRoutePriority(toolargs.String(args, "priority"))
 	hints := mrExtractRouteHints(args)
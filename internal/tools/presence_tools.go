@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/nugget/thane-ai-agent/internal/state/contacts"
+)
+
+// maxPresenceLookbackSeconds matches the person tracker's one-week
+// presence log; asking further back cannot return more.
+const maxPresenceLookbackSeconds = 7 * 24 * 60 * 60
+
+// SetPresenceTracker adds the person_presence tool, backed by the
+// person tracker's presence log, to the registry.
+func (r *Registry) SetPresenceTracker(pt *contacts.PresenceTracker) {
+	r.presenceTracker = pt
+	r.registerPresenceTools()
+}
+
+func (r *Registry) registerPresenceTools() {
+	if r.presenceTracker == nil {
+		return
+	}
+
+	r.Register(&Tool{
+		Name: "person_presence",
+		Description: "Presence history for tracked household members — the answer to 'how long has Alice been home?' " +
+			"or 'when did Bob last get home?'. Returns each person's current state and room with how long they have " +
+			"been in each (dwell), their last arrival home, and their presence transitions (home/away/zone and room " +
+			"changes) within the lookback window, newest first. History covers the last week at most and starts " +
+			"when the agent started.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"person": map[string]any{
+					"type":        "string",
+					"description": "Optional person to report on: the entity ID (e.g. \"person.alice\") or the name. Omit for everyone tracked.",
+				},
+				"lookback_seconds": map[string]any{
+					"type":        "integer",
+					"description": "How far back to list transitions. Defaults to 86400 (one day); at most one week.",
+				},
+			},
+		},
+		Handler: func(_ context.Context, args map[string]any) (string, error) {
+			lookback, err := boundedIntArg(args, "lookback_seconds", 24*60*60, maxPresenceLookbackSeconds)
+			if err != nil {
+				return "", err
+			}
+			person, _ := args["person"].(string)
+			return r.presenceTracker.PresenceReport(person, time.Duration(lookback)*time.Second)
+		},
+	})
+}
@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"sync"
+	"time"
+)
+
+// ToolRateLimit caps how often one tool may run: at most Calls
+// executions in any sliding window of length Per, counted across all
+// conversations.
+type ToolRateLimit struct {
+	Calls int
+	Per   time.Duration
+}
+
+// toolRateLimiter tracks recent executions of rate-limited tools. One
+// limiter is created with each root registry and shared by every copy
+// derived from it, so limits hold across runs and across the filtered
+// copies each run, delegate, and child loop executes against.
+type toolRateLimiter struct {
+	mu     sync.Mutex
+	limits map[string]ToolRateLimit
+	calls  map[string][]time.Time // recent call times, oldest first
+}
+
+// SetToolRateLimit limits the named tool to n executions per sliding
+// window of length per. A non-positive n or per removes the limit. The
+// tool need not be registered yet, so limits can be declared for tools
+// that arrive later (MCP servers, companion apps).
+func (r *Registry) SetToolRateLimit(name string, n int, per time.Duration) {
+	rl := r.limiter()
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if n <= 0 || per <= 0 {
+		delete(rl.limits, name)
+		delete(rl.calls, name)
+		return
+	}
+	if rl.limits == nil {
+		rl.limits = make(map[string]ToolRateLimit)
+		rl.calls = make(map[string][]time.Time)
+	}
+	rl.limits[name] = ToolRateLimit{Calls: n, Per: per}
+}
+
+// limiter returns the registry's rate limiter, creating it for a
+// registry built without one.
+func (r *Registry) limiter() *toolRateLimiter {
+	r.toolsMu.RLock()
+	rl := r.rateLimits
+	r.toolsMu.RUnlock()
+	if rl != nil {
+		return rl
+	}
+	r.toolsMu.Lock()
+	defer r.toolsMu.Unlock()
+	if r.rateLimits == nil {
+		r.rateLimits = &toolRateLimiter{}
+	}
+	return r.rateLimits
+}
+
+// ToolRateLimits returns the configured limits keyed by tool name.
+func (r *Registry) ToolRateLimits() map[string]ToolRateLimit {
+	rl := r.limiter()
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	out := make(map[string]ToolRateLimit, len(rl.limits))
+	for name, limit := range rl.limits {
+		out[name] = limit
+	}
+	return out
+}
+
+// AcquireToolCall reserves one execution of the named tool against its
+// rate limit. It returns an [*ErrToolRateLimited] when the tool has
+// used its allowance for the current window; the rejected call does
+// not count against the limit. Tools without a limit always succeed.
+func (r *Registry) AcquireToolCall(name string) error {
+	return r.acquireToolCall(name, time.Now())
+}
+
+func (r *Registry) acquireToolCall(name string, now time.Time) error {
+	rl := r.limiter()
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	limit, ok := rl.limits[name]
+	if !ok {
+		return nil
+	}
+
+	recent := rl.calls[name]
+	cutoff := now.Add(-limit.Per)
+	drop := 0
+	for drop < len(recent) && !recent[drop].After(cutoff) {
+		drop++
+	}
+	recent = recent[drop:]
+
+	if len(recent) >= limit.Calls {
+		rl.calls[name] = recent
+		return &ErrToolRateLimited{
+			ToolName:   name,
+			Limit:      limit,
+			RetryAfter: recent[0].Add(limit.Per).Sub(now),
+		}
+	}
+	rl.calls[name] = append(recent, now)
+	return nil
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToolRateLimit_SlidingWindow(t *testing.T) {
+	reg := NewEmptyRegistry()
+	reg.SetToolRateLimit("web_fetch", 2, time.Minute)
+	t0 := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+
+	if err := reg.acquireToolCall("web_fetch", t0); err != nil {
+		t.Fatalf("call 1: %v", err)
+	}
+	if err := reg.acquireToolCall("web_fetch", t0.Add(10*time.Second)); err != nil {
+		t.Fatalf("call 2: %v", err)
+	}
+
+	err := reg.acquireToolCall("web_fetch", t0.Add(20*time.Second))
+	var limited *ErrToolRateLimited
+	if !errors.As(err, &limited) {
+		t.Fatalf("call 3 error = %v, want *ErrToolRateLimited", err)
+	}
+	if limited.RetryAfter != 40*time.Second {
+		t.Errorf("RetryAfter = %v, want 40s", limited.RetryAfter)
+	}
+
+	// The rejected call didn't count: once the first call ages out of
+	// the window, exactly one slot frees up.
+	if err := reg.acquireToolCall("web_fetch", t0.Add(61*time.Second)); err != nil {
+		t.Fatalf("call after window: %v", err)
+	}
+	if err := reg.acquireToolCall("web_fetch", t0.Add(62*time.Second)); err == nil {
+		t.Fatal("second call after window should still be limited")
+	}
+
+	// Other tools are unaffected.
+	for i := 0; i < 10; i++ {
+		if err := reg.acquireToolCall("web_search", t0); err != nil {
+			t.Fatalf("unlimited tool: %v", err)
+		}
+	}
+}
+
+func TestToolRateLimit_Remove(t *testing.T) {
+	reg := NewEmptyRegistry()
+	reg.SetToolRateLimit("exec", 1, time.Hour)
+	if err := reg.AcquireToolCall("exec"); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.AcquireToolCall("exec"); err == nil {
+		t.Fatal("second call should be limited")
+	}
+
+	reg.SetToolRateLimit("exec", 0, time.Hour)
+	if err := reg.AcquireToolCall("exec"); err != nil {
+		t.Errorf("call after removing limit: %v", err)
+	}
+	if limits := reg.ToolRateLimits(); len(limits) != 0 {
+		t.Errorf("ToolRateLimits() = %v, want none", limits)
+	}
+}
+
+func TestErrToolRateLimited_Error(t *testing.T) {
+	err := &ErrToolRateLimited{
+		ToolName:   "web_search",
+		Limit:      ToolRateLimit{Calls: 20, Per: time.Minute},
+		RetryAfter: 1500 * time.Millisecond,
+	}
+	msg := err.Error()
+	for _, want := range []string{"rate limited", `"web_search"`, "20 calls per 1m0s", "try again later", "in 2s"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Error() = %q, missing %q", msg, want)
+		}
+	}
+}
+
+func TestToolRateLimit_SharedAcrossCopies(t *testing.T) {
+	reg := NewEmptyRegistry()
+	ran := 0
+	reg.Register(&Tool{
+		Name: "web_fetch",
+		Handler: func(context.Context, map[string]any) (string, error) {
+			ran++
+			return "page", nil
+		},
+	})
+	reg.SetToolRateLimit("web_fetch", 2, time.Minute)
+
+	// A run's narrowed copy and a delegate's further-filtered copy
+	// draw on the same allowance as the root registry.
+	runCopy := reg.FilteredCopy([]string{"web_fetch"})
+	delegateCopy := runCopy.FilteredCopyExcluding([]string{"thane_now"})
+	for i, r := range []*Registry{runCopy, delegateCopy} {
+		if _, err := r.Execute(context.Background(), "web_fetch", "{}"); err != nil {
+			t.Fatalf("call %d: %v", i+1, err)
+		}
+	}
+	_, err := reg.Execute(context.Background(), "web_fetch", "{}")
+	var limited *ErrToolRateLimited
+	if !errors.As(err, &limited) {
+		t.Fatalf("third call error = %v, want *ErrToolRateLimited", err)
+	}
+	if ran != 2 {
+		t.Errorf("handler ran %d times, want 2", ran)
+	}
+}
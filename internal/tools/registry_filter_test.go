@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"sort"
+	"sync"
 	"testing"
 )
 
@@ -212,6 +213,42 @@ func TestFilteredCopyExcluding_PreservesTagIndex(t *testing.T) {
 	}
 }
 
+// TestSetTagIndex_ReloadDuringRun swaps the tag index, as a config
+// reload does, while runs filter by tag and derive shallow copies.
+// Run it with -race: the swap must never overlap a read of the index.
+func TestSetTagIndex_ReloadDuringRun(t *testing.T) {
+	r := newTestRegistry()
+	r.SetTagIndex(map[string][]string{"a": {"alpha"}})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			if i%2 == 0 {
+				r.SetTagIndex(map[string][]string{"a": {"alpha"}, "b": {"beta"}})
+			} else {
+				r.SetTagIndex(map[string][]string{"a": {"alpha"}})
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			run := r.FilterByTags([]string{"a", "b"})
+			_ = run.TaggedToolNames("b")
+			_ = r.FilteredCopy([]string{"alpha"}).FilterByTags([]string{"a"})
+			_ = r.FilteredCopyExcluding([]string{"gamma"}).TaggedToolNames("a")
+			_ = r.WithDynamicTools(nil, map[string][]string{"c": {"gamma"}}).TaggedToolNames("c")
+		}
+	}()
+	wg.Wait()
+
+	if got := r.FilterByTags([]string{"a"}).AllToolNames(); len(got) != 1 || got[0] != "alpha" {
+		t.Fatalf("FilterByTags(a) after reloads = %v, want [alpha]", got)
+	}
+}
+
 func containsName(names []string, target string) bool {
 	for _, n := range names {
 		if n == target {
@@ -234,3 +271,19 @@ func TestFilteredCopy_DoesNotMutateSource(t *testing.T) {
 		t.Error("FilteredCopy mutated the source registry")
 	}
 }
+
+func TestUnregister_RemovesFromCopies(t *testing.T) {
+	r := newTestRegistry()
+	r.Unregister("beta")
+	r.Unregister("missing")
+
+	if r.Get("beta") != nil {
+		t.Error("beta still registered after Unregister")
+	}
+	if names := r.FilterByTags(nil).AllToolNames(); containsName(names, "beta") {
+		t.Errorf("copy after Unregister = %v, want beta absent", names)
+	}
+	if got := len(r.AllToolNames()); got != 2 {
+		t.Errorf("len(AllToolNames()) = %d, want 2", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -48,11 +49,41 @@ func tempfileNamespace(convID string) string {
 	return "tempfile:" + convID
 }
 
+// tempfileNameNamespace returns the opstate namespace for the original
+// names of a conversation's temp files, kept by [TempFileStore.CreateNamed].
+func tempfileNameNamespace(convID string) string {
+	return "tempfile-name:" + convID
+}
+
 // Create writes content to a temp file and maps the label to its path.
 // The returned string is the label itself (not the path). If a label
 // already exists for this conversation, the old file is removed and the
 // mapping updated.
 func (s *TempFileStore) Create(ctx context.Context, convID, label, content string) (string, error) {
+	_, err := s.CreateWith(ctx, convID, label, ".md", func(w io.Writer) error {
+		_, err := io.WriteString(w, content)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return label, nil
+}
+
+// CreateWith streams a temp file through write, so large content (such
+// as a downloaded attachment) never has to sit in memory, and maps the
+// label to it. ext is the file extension including the dot. It returns
+// the file's path. If write fails, the partial file is removed and any
+// existing file for the label is left in place.
+func (s *TempFileStore) CreateWith(ctx context.Context, convID, label, ext string, write func(io.Writer) error) (string, error) {
+	return s.CreateNamed(ctx, convID, label, "", ext, write)
+}
+
+// CreateNamed is [TempFileStore.CreateWith] for a file with a name of
+// its own, such as a downloaded attachment. The name is kept with the
+// label and reported by [TempFileStore.Name]; an empty name clears any
+// the label had.
+func (s *TempFileStore) CreateNamed(ctx context.Context, convID, label, name, ext string, write func(io.Writer) error) (string, error) {
 	if !labelPattern.MatchString(label) {
 		return "", fmt.Errorf("invalid label %q: must be 1-63 alphanumeric/underscore/hyphen characters starting with alphanumeric", label)
 	}
@@ -63,7 +94,7 @@ func (s *TempFileStore) Create(ctx context.Context, convID, label, content strin
 	}
 
 	safeConvID := sanitizeForFilesystem(convID)
-	filename := fmt.Sprintf("%s_%s_%s.md", safeConvID, label, suffix)
+	filename := fmt.Sprintf("%s_%s_%s%s", safeConvID, label, suffix, sanitizeExt(ext))
 	absPath := filepath.Join(s.baseDir, filename)
 
 	// Ensure base directory exists.
@@ -71,29 +102,53 @@ func (s *TempFileStore) Create(ctx context.Context, convID, label, content strin
 		return "", fmt.Errorf("create temp directory: %w", err)
 	}
 
-	// If label already exists, remove the old file first.
-	ns := tempfileNamespace(convID)
-	if existing, _ := s.state.Get(ns, label); existing != "" {
-		_ = os.Remove(existing) // best-effort
+	f, err := os.OpenFile(absPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+	counter := &countingWriter{w: f}
+	writeErr := write(counter)
+	if closeErr := f.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		_ = os.Remove(absPath)
+		return "", fmt.Errorf("write temp file: %w", writeErr)
 	}
 
-	if err := os.WriteFile(absPath, []byte(content), 0o644); err != nil {
-		return "", fmt.Errorf("write temp file: %w", err)
+	// If label already exists, remove the old file now that the new one
+	// is in place.
+	ns := tempfileNamespace(convID)
+	if existing, _ := s.state.Get(ns, label); existing != "" && existing != absPath {
+		_ = os.Remove(existing) // best-effort
 	}
 
 	if err := s.state.Set(ns, label, absPath); err != nil {
 		_ = os.Remove(absPath) // rollback on mapping failure
 		return "", fmt.Errorf("store label mapping: %w", err)
 	}
+	nameNS := tempfileNameNamespace(convID)
+	if name != "" {
+		err = s.state.Set(nameNS, label, name)
+	} else {
+		err = s.state.Delete(nameNS, label)
+	}
+	if err != nil {
+		s.logger.Warn("failed to store temp file name",
+			"conversation_id", convID,
+			"label", label,
+			"error", err,
+		)
+	}
 
 	s.logger.Info("temp file created",
 		"conversation_id", convID,
 		"label", label,
 		"path", absPath,
-		"bytes", len(content),
+		"bytes", counter.n,
 	)
 
-	return label, nil
+	return absPath, nil
 }
 
 // Resolve returns the filesystem path for a label in the given
@@ -104,6 +159,13 @@ func (s *TempFileStore) Resolve(convID, label string) string {
 	return path
 }
 
+// Name returns the original name recorded for a label by
+// [TempFileStore.CreateNamed], or "" if it has none.
+func (s *TempFileStore) Name(convID, label string) string {
+	name, _ := s.state.Get(tempfileNameNamespace(convID), label)
+	return name
+}
+
 // ExpandLabels replaces all occurrences of "temp:LABEL" in text with
 // the corresponding file path for the given conversation. Unknown labels
 // are left as-is.
@@ -166,6 +228,9 @@ func (s *TempFileStore) Cleanup(convID string) error {
 	if err := s.state.DeleteNamespace(ns); err != nil {
 		return fmt.Errorf("delete temp file namespace: %w", err)
 	}
+	if err := s.state.DeleteNamespace(tempfileNameNamespace(convID)); err != nil {
+		return fmt.Errorf("delete temp file name namespace: %w", err)
+	}
 
 	s.logger.Info("temp files cleaned up",
 		"conversation_id", convID,
@@ -174,6 +239,33 @@ func (s *TempFileStore) Cleanup(convID string) error {
 	return nil
 }
 
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// sanitizeExt returns ext if it is a short dot-prefixed alphanumeric
+// extension, and ".bin" otherwise, so attacker-chosen attachment names
+// cannot smuggle path separators into temp file names.
+func sanitizeExt(ext string) string {
+	if len(ext) < 2 || len(ext) > 16 || ext[0] != '.' {
+		return ".bin"
+	}
+	for _, r := range ext[1:] {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+			return ".bin"
+		}
+	}
+	return ext
+}
+
 // randomSuffix generates a 4-byte (8 hex char) random string.
 func randomSuffix() (string, error) {
 	b := make([]byte, 4)
@@ -2,6 +2,8 @@ package tools
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -325,3 +327,84 @@ func TestSanitizeForFilesystem_Truncation(t *testing.T) {
 		t.Errorf("len = %d, want 64", len(got))
 	}
 }
+
+func TestTempFileStore_CreateWith(t *testing.T) {
+	tfs, _ := testTempFileStore(t)
+	ctx := context.Background()
+
+	path, err := tfs.CreateWith(ctx, "conv-1", "invoice", ".pdf", func(w io.Writer) error {
+		_, err := io.WriteString(w, "%PDF-1.7")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("CreateWith: %v", err)
+	}
+	if filepath.Ext(path) != ".pdf" {
+		t.Errorf("path = %q, want a .pdf extension", path)
+	}
+	if got := tfs.Resolve("conv-1", "invoice"); got != path {
+		t.Errorf("Resolve = %q, want %q", got, path)
+	}
+
+	// A failed write removes the partial file and keeps the old one.
+	_, err = tfs.CreateWith(ctx, "conv-1", "invoice", ".pdf", func(w io.Writer) error {
+		_, _ = io.WriteString(w, "partial")
+		return errors.New("connection lost")
+	})
+	if err == nil {
+		t.Fatal("CreateWith succeeded despite a write error")
+	}
+	if got := tfs.Resolve("conv-1", "invoice"); got != path {
+		t.Errorf("Resolve after failure = %q, want the original %q", got, path)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temp dir has %d files, want only the original", len(entries))
+	}
+
+	// Unsafe extensions are replaced.
+	path, err = tfs.CreateWith(ctx, "conv-1", "odd", "./../x", func(w io.Writer) error { return nil })
+	if err != nil {
+		t.Fatalf("CreateWith: %v", err)
+	}
+	if filepath.Ext(path) != ".bin" {
+		t.Errorf("path = %q, want a .bin extension", path)
+	}
+}
+
+func TestTempFileStore_CreateNamed(t *testing.T) {
+	tfs, _ := testTempFileStore(t)
+	ctx := context.Background()
+	write := func(w io.Writer) error {
+		_, err := io.WriteString(w, "%PDF-1.7")
+		return err
+	}
+
+	if _, err := tfs.CreateNamed(ctx, "conv-1", "invoice", "Rechnung März.pdf", ".pdf", write); err != nil {
+		t.Fatalf("CreateNamed: %v", err)
+	}
+	if got := tfs.Name("conv-1", "invoice"); got != "Rechnung März.pdf" {
+		t.Errorf("Name = %q, want the original name", got)
+	}
+	if got := tfs.Name("conv-2", "invoice"); got != "" {
+		t.Errorf("Name in another conversation = %q, want empty", got)
+	}
+
+	// Reusing the label for an unnamed file drops the old name.
+	if _, err := tfs.CreateWith(ctx, "conv-1", "invoice", ".pdf", write); err != nil {
+		t.Fatalf("CreateWith: %v", err)
+	}
+	if got := tfs.Name("conv-1", "invoice"); got != "" {
+		t.Errorf("Name after unnamed overwrite = %q, want empty", got)
+	}
+
+	if _, err := tfs.CreateNamed(ctx, "conv-1", "invoice", "Rechnung.pdf", ".pdf", write); err != nil {
+		t.Fatalf("CreateNamed: %v", err)
+	}
+	if err := tfs.Cleanup("conv-1"); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+	if got := tfs.Name("conv-1", "invoice"); got != "" {
+		t.Errorf("Name after cleanup = %q, want empty", got)
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/nugget/thane-ai-agent/internal/channels/email"
@@ -23,6 +25,7 @@ import (
 	routepkg "github.com/nugget/thane-ai-agent/internal/model/router"
 	"github.com/nugget/thane-ai-agent/internal/model/toolcatalog"
 	"github.com/nugget/thane-ai-agent/internal/platform/buildinfo"
+	"github.com/nugget/thane-ai-agent/internal/platform/logging"
 	"github.com/nugget/thane-ai-agent/internal/platform/scheduler"
 	"github.com/nugget/thane-ai-agent/internal/platform/usage"
 	looppkg "github.com/nugget/thane-ai-agent/internal/runtime/loop"
@@ -53,17 +56,29 @@ type Tool struct {
 	Source               string   `json:"-"`
 	Origin               string   `json:"-"`
 	Tags                 []string `json:"-"`
+	// ReadOnly marks the tool as side-effect-free: it only reads state,
+	// so calls to it may run concurrently with other read-only calls in
+	// the same batch. Leave it false for anything that writes, sends,
+	// or controls a device.
+	ReadOnly bool `json:"-"`
 }
 
 // Registry holds available tools.
 type Registry struct {
+	// toolsMu guards tools and tagIndex. The tool set is mostly
+	// assembled during startup, but bridged MCP servers re-register
+	// their tools after a reconnect and a config reload swaps the tag
+	// index while runs are reading the registry.
+	toolsMu            sync.RWMutex
 	tools              map[string]*Tool
-	tagIndex           map[string][]string // tag → tool names
+	tagIndex           map[string][]string // tag → tool names; replaced whole, never mutated
 	ha                 *homeassistant.Client
+	haInstances        *homeassistant.Instances
 	scheduler          *scheduler.Scheduler
 	logger             *slog.Logger
 	factTools          *knowledge.Tools
 	contactTools       *contacts.Tools
+	presenceTracker    *contacts.PresenceTracker
 	emailTools         *email.Tools
 	notifier           *notifications.Sender
 	notifRecords       *notifications.RecordStore
@@ -78,6 +93,8 @@ type Registry struct {
 	usageStore         *usage.Store
 	lensStore          *LensStore
 	logIndexDB         *sql.DB
+	rateLimits         *toolRateLimiter
+	followUps          *toolFollowUps
 	workingMemoryStore *memory.WorkingMemoryStore
 	archiveStore       *memory.ArchiveStore
 
@@ -110,7 +127,7 @@ type Registry struct {
 // NewEmptyRegistry creates an empty tool registry with no built-in tools.
 // Use this for testing or when constructing a registry manually.
 func NewEmptyRegistry() *Registry {
-	return &Registry{tools: make(map[string]*Tool)}
+	return &Registry{tools: make(map[string]*Tool), rateLimits: &toolRateLimiter{}, followUps: &toolFollowUps{}}
 }
 
 // NewRegistry creates a tool registry with HA integration.
@@ -123,15 +140,20 @@ func NewRegistry(ha *homeassistant.Client, sched *scheduler.Scheduler, logger *s
 		logger = slog.Default()
 	}
 	r := &Registry{
-		tools:     make(map[string]*Tool),
-		ha:        ha,
-		scheduler: sched,
-		logger:    logger,
+		tools:      make(map[string]*Tool),
+		ha:         ha,
+		scheduler:  sched,
+		logger:     logger,
+		rateLimits: &toolRateLimiter{},
+		followUps:  &toolFollowUps{},
 	}
 	r.registerBuiltins()
-	r.registerFindEntity()     // Smart entity discovery
-	r.registerHASearchStates() // Predicate search across live state
-	r.registerHAListServices() // Service-catalog discovery (#1177)
+	r.registerFindEntity()        // Smart entity discovery
+	r.registerHASearchStates()    // Predicate search across live state
+	r.registerHAListServices()    // Service-catalog discovery (#1177)
+	r.registerHAServiceResponse() // Data-returning service calls
+	r.registerHARenderTemplate()  // Server-side Jinja evaluation
+	r.registerHAAreaEntities()    // Area name → assigned entity IDs
 	r.registerHAAutomationTools()
 	r.registerHAAutomationTraces()     // Run-level debugging (#1178)
 	r.registerHAAutomationVocabulary() // Target-scoped 2026.7 vocabulary discovery (#1176)
@@ -179,7 +201,8 @@ func (r *Registry) SetShellExec(se *ShellExec) {
 func (r *Registry) SetSearchManager(mgr *search.Manager) {
 	r.Register(&Tool{
 		Name:        "web_search",
-		Description: "Search the web for information. Returns titles, URLs, and snippets.",
+		ReadOnly:    true,
+		Description: "Search the web for information. Returns titles, URLs, and snippets. For factual queries the provider may also supply an instant answer, returned first under \"answer\"; prefer it over synthesizing one from snippets.",
 		Parameters:  search.ToolDefinition(),
 		Handler:     search.ToolHandler(mgr),
 	})
@@ -199,12 +222,20 @@ func (r *Registry) SetFetcher(f *search.Fetcher) {
 func (r *Registry) SetMediaClient(c *media.Client) {
 	r.Register(&Tool{
 		Name:        "media_transcript",
-		Description: "Retrieve the transcript of a video or podcast episode. Supports YouTube, Vimeo, and other sources via yt-dlp. Returns metadata and cleaned transcript text. Transcripts are saved to disk for future reference.",
+		Description: "Retrieve the transcript of a video or podcast episode. Supports YouTube, Vimeo, and other sources via yt-dlp. Returns metadata and cleaned transcript text. Transcripts are saved to disk and reused on later requests for the same video and language; set force_refresh to fetch again.",
 		Parameters:  media.ToolDefinition(),
-		Handler:     media.ToolHandler(c),
+		Handler:     withMediaProgress(media.ToolHandler(c)),
 	})
 }
 
+// withMediaProgress relays the media client's phase reports to the
+// tool call's progress reporter.
+func withMediaProgress(h func(context.Context, map[string]any) (string, error)) func(context.Context, map[string]any) (string, error) {
+	return func(ctx context.Context, args map[string]any) (string, error) {
+		return h(media.WithProgress(ctx, func(msg string) { ReportProgress(ctx, msg) }), args)
+	}
+}
+
 // SetAttachmentTools adds attachment query and analysis tools to the
 // registry.
 func (r *Registry) SetAttachmentTools(at *attachments.Tools) {
@@ -339,7 +370,11 @@ func (r *Registry) registerFactTools() {
 					"items": map[string]any{
 						"type": "string",
 					},
-					"description": "Subject keys this fact relates to. Prefix with type: entity:, contact:, phone:, zone:, camera:, location:. Example: [\"entity:binary_sensor.driveway\", \"zone:driveway\"]",
+					"description": "Subject keys this fact relates to. Prefix with type: entity:, contact:, phone:, zone:, camera:, location:. A bare Home Assistant entity ID is linked as entity: (zone.* as zone:). Linked facts surface automatically when a turn mentions that entity. Example: [\"entity:binary_sensor.driveway\", \"zone:driveway\"]",
+				},
+				"ttl": map[string]any{
+					"type":        "string",
+					"description": "Lifetime for knowledge that stops being true, such as \"the guest room is occupied this weekend\" — e.g. \"48h\" or \"3d\". The fact is dropped from memory once it expires. Each remember_fact call sets the lifetime afresh: pass ttl again to renew it, and omit it (or pass \"none\") to make the fact lasting.",
 				},
 			},
 			"required": []string{"key", "value"},
@@ -355,6 +390,7 @@ func (r *Registry) registerFactTools() {
 
 	r.Register(&Tool{
 		Name:        "recall_fact",
+		ReadOnly:    true,
 		Description: "Retrieve information from long-term memory. Can look up specific facts, list a category, or search.",
 		Parameters: map[string]any{
 			"type": "object",
@@ -407,6 +443,50 @@ func (r *Registry) registerFactTools() {
 			return r.factTools.Forget(string(argsJSON))
 		},
 	})
+
+	r.Register(&Tool{
+		Name:        "list_fact_conflicts",
+		ReadOnly:    true,
+		Description: "List pending fact conflicts: observations that contradicted a high-confidence fact and were held back instead of overwriting it. Resolve each with resolve_fact_conflict.",
+		Parameters: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+		Handler: func(ctx context.Context, args map[string]any) (string, error) {
+			return r.factTools.ListConflicts("")
+		},
+	})
+
+	r.Register(&Tool{
+		Name:        "resolve_fact_conflict",
+		Description: "Resolve a pending fact conflict. Use 'keep' when the stored value is still right, 'accept' when the new observation supersedes it, or 'value' with a reconciled value that replaces both.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id": map[string]any{
+					"type":        "integer",
+					"description": "Conflict ID from list_fact_conflicts or the conflict notice",
+				},
+				"resolution": map[string]any{
+					"type":        "string",
+					"enum":        []string{"keep", "accept", "value"},
+					"description": "How to resolve the conflict",
+				},
+				"value": map[string]any{
+					"type":        "string",
+					"description": "Reconciled value (required when resolution is 'value')",
+				},
+			},
+			"required": []string{"id", "resolution"},
+		},
+		Handler: func(ctx context.Context, args map[string]any) (string, error) {
+			argsJSON, err := json.Marshal(args)
+			if err != nil {
+				return "", fmt.Errorf("failed to serialize arguments: %w", err)
+			}
+			return r.factTools.ResolveConflict(string(argsJSON))
+		},
+	})
 }
 
 func (r *Registry) registerFileTools() {
@@ -416,6 +496,7 @@ func (r *Registry) registerFileTools() {
 
 	r.Register(&Tool{
 		Name:               "file_read",
+		ReadOnly:           true,
 		SkipContentResolve: true,
 		Description:        "Read the contents of a file from the workspace. Use for accessing configuration, memory files, documentation, or any text file.",
 		Parameters: map[string]any{
@@ -513,6 +594,7 @@ func (r *Registry) registerFileTools() {
 
 	r.Register(&Tool{
 		Name:               "file_list",
+		ReadOnly:           true,
 		SkipContentResolve: true,
 		Description:        "List files and directories in a workspace path.",
 		Parameters: map[string]any{
@@ -543,6 +625,7 @@ func (r *Registry) registerFileTools() {
 
 	r.Register(&Tool{
 		Name:               "file_search",
+		ReadOnly:           true,
 		SkipContentResolve: true,
 		Description:        "Search for files by name using glob patterns. Recursively searches a directory tree and returns matching file paths. Useful for finding configuration files, specific file types, or files with certain naming patterns.",
 		Parameters: map[string]any{
@@ -579,6 +662,7 @@ func (r *Registry) registerFileTools() {
 
 	r.Register(&Tool{
 		Name:               "file_grep",
+		ReadOnly:           true,
 		SkipContentResolve: true,
 		Description:        "Search file contents for a regular expression pattern. Recursively searches files and returns matching lines with file paths and line numbers. Skips binary files and files larger than 1MB.",
 		Parameters: map[string]any{
@@ -623,6 +707,7 @@ func (r *Registry) registerFileTools() {
 
 	r.Register(&Tool{
 		Name:               "file_stat",
+		ReadOnly:           true,
 		SkipContentResolve: true,
 		Description:        "Get detailed information about one or more files or directories. Returns type, size, permissions, and modification time. Supports batch queries with comma-separated paths.",
 		Parameters: map[string]any{
@@ -643,6 +728,7 @@ func (r *Registry) registerFileTools() {
 
 	r.Register(&Tool{
 		Name:               "file_tree",
+		ReadOnly:           true,
 		SkipContentResolve: true,
 		Description:        "Display a directory tree structure with indentation. Shows the hierarchy of files and directories with a summary count. Useful for understanding project layout.",
 		Parameters: map[string]any{
@@ -739,6 +825,7 @@ func (r *Registry) registerBuiltins() {
 	// Get entity state
 	r.Register(&Tool{
 		Name:        "ha_get_state",
+		ReadOnly:    true,
 		Description: "Get the current state of a Home Assistant entity. Use this to check if lights are on, doors are open, temperatures, etc.",
 		Parameters: map[string]any{
 			"type": "object",
@@ -757,6 +844,7 @@ func (r *Registry) registerBuiltins() {
 	// List entities by domain or entity_id glob
 	r.Register(&Tool{
 		Name:        "ha_list_entities",
+		ReadOnly:    true,
 		Description: "List Home Assistant entities by domain and/or an entity_id glob. Use domain for a whole domain (all lights); use pattern for substring/cross-domain matching (e.g. binary_sensor.*door*, *_temperature). At least one of domain or pattern is required; when both are given they combine (AND).",
 		Parameters: map[string]any{
 			"type": "object",
@@ -879,7 +967,7 @@ func (r *Registry) registerBuiltins() {
 				},
 				"when": map[string]any{
 					"type":        "string",
-					"description": "When to run: ISO timestamp, duration (e.g., '30m', '2h'), or 'in 30 minutes'",
+					"description": "When to run: ISO timestamp, duration (e.g., '30m', '2h'), 'in 30 minutes', or a recurring 5-field cron expression in the household timezone (e.g., '0 7 * * *' for 7:00 daily, '@hourly')",
 				},
 				"action": map[string]any{
 					"type":        "string",
@@ -895,6 +983,31 @@ func (r *Registry) registerBuiltins() {
 		Handler: r.handleScheduleTask,
 	})
 
+	// Create reminder
+	r.Register(&Tool{
+		Name:        "create_reminder",
+		Description: "Set a one-time reminder, e.g. 'remind me at 3pm to call the vet'. Fires once, then is kept as completed. If Thane is down at the due time, the reminder fires on startup marked as late.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"message": map[string]any{
+					"type":        "string",
+					"description": "What to remind about, as it should be delivered",
+				},
+				"when": map[string]any{
+					"type":        "string",
+					"description": "When to remind: ISO timestamp, time of day (e.g., '15:00', '3:00pm', '3pm'), duration (e.g., '20m'), or 'in 2 hours'",
+				},
+				"name": map[string]any{
+					"type":        "string",
+					"description": "Optional short label for the reminder in task listings",
+				},
+			},
+			"required": []string{"message", "when"},
+		},
+		Handler: r.handleCreateReminder,
+	})
+
 	// List tasks
 	r.Register(&Tool{
 		Name:        "task_list",
@@ -931,6 +1044,7 @@ func (r *Registry) registerBuiltins() {
 	// Get version/build info
 	r.Register(&Tool{
 		Name:        "get_version",
+		ReadOnly:    true,
 		Description: "Get Thane's version, build info, git commit, and uptime. Use when asked about your version or to diagnose issues.",
 		Parameters: map[string]any{
 			"type":       "object",
@@ -961,27 +1075,61 @@ func (r *Registry) Register(t *Tool) {
 	if t.Source == "" {
 		t.Source = string(toolcatalog.NativeToolSource)
 	}
+	r.toolsMu.Lock()
 	r.tools[t.Name] = t
+	r.toolsMu.Unlock()
+}
+
+// Unregister removes the named tool from the registry. Removing a name
+// that is not registered is a no-op.
+func (r *Registry) Unregister(name string) {
+	r.toolsMu.Lock()
+	delete(r.tools, name)
+	r.toolsMu.Unlock()
 }
 
 // Get retrieves a tool by name.
 func (r *Registry) Get(name string) *Tool {
+	r.toolsMu.RLock()
+	defer r.toolsMu.RUnlock()
 	return r.tools[name]
 }
 
+// snapshot returns a copy of the name-to-tool map, so callers can
+// iterate without holding the lock.
+func (r *Registry) snapshot() map[string]*Tool {
+	r.toolsMu.RLock()
+	defer r.toolsMu.RUnlock()
+	out := make(map[string]*Tool, len(r.tools))
+	for name, t := range r.tools {
+		out[name] = t
+	}
+	return out
+}
+
+// currentTagIndex returns the tag index in effect. The map is never
+// mutated after it is installed, so callers may read it without the
+// lock.
+func (r *Registry) currentTagIndex() map[string][]string {
+	r.toolsMu.RLock()
+	defer r.toolsMu.RUnlock()
+	return r.tagIndex
+}
+
 // List returns all tools for the LLM, sorted by name. Deterministic
 // order is required for Anthropic prompt caching: tools land first in
 // the cache key, so a randomized order (Go map iteration) makes every
 // turn miss the prefix even when the tool set is unchanged.
 func (r *Registry) List() []map[string]any {
-	names := make([]string, 0, len(r.tools))
-	for name := range r.tools {
+	all := r.snapshot()
+	names := make([]string, 0, len(all))
+	for name := range all {
 		names = append(names, name)
 	}
 	sort.Strings(names)
 	result := make([]map[string]any, 0, len(names))
 	for _, name := range names {
-		t := r.tools[name]
+		t := all[name]
 		result = append(result, map[string]any{
 			"type": "function",
 			"function": map[string]any{
@@ -996,8 +1144,9 @@ func (r *Registry) List() []map[string]any {
 
 // AllToolNames returns the names of all registered tools, sorted.
 func (r *Registry) AllToolNames() []string {
-	names := make([]string, 0, len(r.tools))
-	for name := range r.tools {
+	all := r.snapshot()
+	names := make([]string, 0, len(all))
+	for name := range all {
 		names = append(names, name)
 	}
 	sort.Strings(names)
@@ -1011,11 +1160,13 @@ func (r *Registry) FilteredCopy(names []string) *Registry {
 	filtered := &Registry{
 		tools:           make(map[string]*Tool, len(names)),
 		contentResolver: r.contentResolver,
-		tagIndex:        r.tagIndex,
+		tagIndex:        r.currentTagIndex(),
 		logger:          r.logger,
+		rateLimits:      r.rateLimits,
+		followUps:       r.followUps,
 	}
 	for _, name := range names {
-		if t := r.tools[name]; t != nil {
+		if t := r.Get(name); t != nil {
 			filtered.tools[name] = t
 		}
 	}
@@ -1029,13 +1180,16 @@ func (r *Registry) FilteredCopyExcluding(exclude []string) *Registry {
 	for _, name := range exclude {
 		skip[name] = true
 	}
+	all := r.snapshot()
 	filtered := &Registry{
-		tools:           make(map[string]*Tool, len(r.tools)),
+		tools:           make(map[string]*Tool, len(all)),
 		contentResolver: r.contentResolver,
-		tagIndex:        r.tagIndex,
+		tagIndex:        r.currentTagIndex(),
 		logger:          r.logger,
+		rateLimits:      r.rateLimits,
+		followUps:       r.followUps,
 	}
-	for name, t := range r.tools {
+	for name, t := range all {
 		if !skip[name] {
 			filtered.tools[name] = t
 		}
@@ -1052,13 +1206,12 @@ func (r *Registry) WithRuntimeTools(runtime []*Tool) *Registry {
 		return r
 	}
 	filtered := &Registry{
-		tools:           make(map[string]*Tool, len(r.tools)+len(runtime)),
+		tools:           r.snapshot(),
 		contentResolver: r.contentResolver,
-		tagIndex:        r.tagIndex,
+		tagIndex:        r.currentTagIndex(),
 		logger:          r.logger,
-	}
-	for name, t := range r.tools {
-		filtered.tools[name] = t
+		rateLimits:      r.rateLimits,
+		followUps:       r.followUps,
 	}
 	for _, t := range runtime {
 		if t == nil || strings.TrimSpace(t.Name) == "" {
@@ -1083,9 +1236,9 @@ func (r *Registry) WithRuntimeTools(runtime []*Tool) *Registry {
 // companion (macOS) source adds or drops tools — turns that have not
 // activated the companion tag are unaffected.
 //
-// The shared registry and its tag index are never mutated (both are
-// lock-free and assumed frozen after startup); a copy is taken only when
-// there is something to add. Returns the receiver unchanged when both
+// The shared registry and its tag index are never mutated (an installed
+// tag index is only ever replaced whole); a copy is taken
+// only when there is something to add. Returns the receiver unchanged when both
 // inputs are empty.
 func (r *Registry) WithDynamicTools(extra []*Tool, tagAdditions map[string][]string) *Registry {
 	if len(extra) == 0 && len(tagAdditions) == 0 {
@@ -1093,12 +1246,11 @@ func (r *Registry) WithDynamicTools(extra []*Tool, tagAdditions map[string][]str
 	}
 
 	filtered := &Registry{
-		tools:           make(map[string]*Tool, len(r.tools)+len(extra)),
+		tools:           r.snapshot(),
 		contentResolver: r.contentResolver,
 		logger:          r.logger,
-	}
-	for name, t := range r.tools {
-		filtered.tools[name] = t
+		rateLimits:      r.rateLimits,
+		followUps:       r.followUps,
 	}
 	for _, t := range extra {
 		if t == nil || strings.TrimSpace(t.Name) == "" {
@@ -1117,11 +1269,12 @@ func (r *Registry) WithDynamicTools(extra []*Tool, tagAdditions map[string][]str
 	// Carry the tag index forward. Share it when there are no additions
 	// (matching the other shallow-copy helpers); otherwise merge additions
 	// into a fresh map so the shared index is left untouched.
+	tagIndex := r.currentTagIndex()
 	if len(tagAdditions) == 0 {
-		filtered.tagIndex = r.tagIndex
+		filtered.tagIndex = tagIndex
 	} else {
-		merged := make(map[string][]string, len(r.tagIndex)+len(tagAdditions))
-		for tag, names := range r.tagIndex {
+		merged := make(map[string][]string, len(tagIndex)+len(tagAdditions))
+		for tag, names := range tagIndex {
 			merged[tag] = names
 		}
 		for tag, names := range tagAdditions {
@@ -1136,11 +1289,12 @@ func (r *Registry) WithDynamicTools(extra []*Tool, tagAdditions map[string][]str
 // MetadataTagIndex builds a tag-to-tool mapping from per-tool default
 // metadata. Tags with no registered tools are omitted.
 func (r *Registry) MetadataTagIndex() map[string][]string {
-	if len(r.tools) == 0 {
+	all := r.snapshot()
+	if len(all) == 0 {
 		return nil
 	}
 	tagIndex := make(map[string][]string)
-	for name, t := range r.tools {
+	for name, t := range all {
 		for _, tag := range t.Tags {
 			tag = strings.TrimSpace(tag)
 			if tag == "" {
@@ -1162,11 +1316,18 @@ func (r *Registry) MetadataTagIndex() map[string][]string {
 // name maps to a list of tool names. Tools not found in the registry
 // are silently skipped (they may not be registered yet or the MCP
 // server may be down).
+//
+// The new index is built in full and then swapped in, so runs that
+// already hold the previous index keep reading a consistent map while
+// a config reload installs the next one.
 func (r *Registry) SetTagIndex(tags map[string][]string) {
-	r.tagIndex = make(map[string][]string, len(tags))
+	tagIndex := make(map[string][]string, len(tags))
 	for tag, toolNames := range tags {
-		r.tagIndex[tag] = toolNames
+		tagIndex[tag] = toolNames
 	}
+	r.toolsMu.Lock()
+	r.tagIndex = tagIndex
+	r.toolsMu.Unlock()
 }
 
 // FilterByTags creates a new Registry containing only the tools that
@@ -1184,23 +1345,22 @@ func (r *Registry) SetTagIndex(tags map[string][]string) {
 // instead of the intended subset. Either failure mode is a latent
 // bug; carrying tagIndex forward prevents both.
 func (r *Registry) FilterByTags(tags []string) *Registry {
-	if len(tags) == 0 || r.tagIndex == nil {
+	tagIndex := r.currentTagIndex()
+	if len(tags) == 0 || tagIndex == nil {
 		// No filtering — return a shallow copy with all tools.
-		filtered := &Registry{
-			tools:           make(map[string]*Tool, len(r.tools)),
+		return &Registry{
+			tools:           r.snapshot(),
 			contentResolver: r.contentResolver,
-			tagIndex:        r.tagIndex,
+			tagIndex:        tagIndex,
 			logger:          r.logger,
+			rateLimits:      r.rateLimits,
+			followUps:       r.followUps,
 		}
-		for name, t := range r.tools {
-			filtered.tools[name] = t
-		}
-		return filtered
 	}
 
 	allowed := make(map[string]bool)
 	for _, tag := range tags {
-		for _, name := range r.tagIndex[tag] {
+		for _, name := range tagIndex[tag] {
 			allowed[name] = true
 		}
 	}
@@ -1208,10 +1368,12 @@ func (r *Registry) FilterByTags(tags []string) *Registry {
 	filtered := &Registry{
 		tools:           make(map[string]*Tool, len(allowed)),
 		contentResolver: r.contentResolver,
-		tagIndex:        r.tagIndex,
+		tagIndex:        tagIndex,
 		logger:          r.logger,
+		rateLimits:      r.rateLimits,
+		followUps:       r.followUps,
 	}
-	for name, t := range r.tools {
+	for name, t := range r.snapshot() {
 		if allowed[name] || t.Core {
 			filtered.tools[name] = t
 		}
@@ -1222,15 +1384,16 @@ func (r *Registry) FilterByTags(tags []string) *Registry {
 // TaggedToolNames returns the tool names belonging to a tag. Returns
 // nil for unknown tags.
 func (r *Registry) TaggedToolNames(tag string) []string {
-	if r.tagIndex == nil {
+	tagIndex := r.currentTagIndex()
+	if tagIndex == nil {
 		return nil
 	}
-	return r.tagIndex[tag]
+	return tagIndex[tag]
 }
 
 // Execute runs a tool by name with given arguments.
 func (r *Registry) Execute(ctx context.Context, name string, argsJSON string) (string, error) {
-	tool := r.tools[name]
+	tool := r.Get(name)
 	if tool == nil {
 		return "", &ErrToolUnavailable{ToolName: name}
 	}
@@ -1238,7 +1401,7 @@ func (r *Registry) Execute(ctx context.Context, name string, argsJSON string) (s
 	var args map[string]any
 	if argsJSON != "" {
 		if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
-			return "", fmt.Errorf("invalid arguments: %w", err)
+			return "", Errorf(ErrorBadInput, "invalid arguments: %w", err)
 		}
 	}
 
@@ -1280,6 +1443,12 @@ func (r *Registry) Execute(ctx context.Context, name string, argsJSON string) (s
 		}
 	}
 
+	// Rate limits are counted on a limiter every copy of the registry
+	// shares, so they hold across runs, delegates, and child loops.
+	if err := r.AcquireToolCall(name); err != nil {
+		logging.Logger(ctx).Warn("tool call rate limited", "tool", name, "error", err)
+		return "", err
+	}
 	return tool.Handler(ctx, args)
 }
 
@@ -1302,22 +1471,19 @@ func mergeUniqueStrings(parts ...[]string) []string {
 // Tool handlers
 
 func (r *Registry) handleGetState(ctx context.Context, args map[string]any) (string, error) {
-	if r.ha == nil {
-		return "", fmt.Errorf("home assistant not configured")
-	}
-	if !r.ha.IsReady() {
-		return "", fmt.Errorf("home assistant is currently unreachable (reconnecting in background)")
-	}
-
 	entityID, _ := args["entity_id"].(string)
-	if entityID == "" {
+	ha, instance, bareID, err := r.haReadyClient(args, entityID)
+	if err != nil {
+		return "", err
+	}
+	if bareID == "" {
 		return "", fmt.Errorf("entity_id is required")
 	}
 
-	state, err := r.ha.GetState(ctx, entityID)
+	state, err := ha.GetState(ctx, bareID)
 	if err != nil {
 		if IsHAEntityNotFound(err) {
-			return SuggestEntityNotFound(ctx, r.ha, entityID), nil
+			return SuggestEntityNotFound(ctx, ha, bareID), nil
 		}
 		return "", err
 	}
@@ -1328,13 +1494,14 @@ func (r *Registry) handleGetState(ctx context.Context, args map[string]any) (str
 	}
 	var metadata *homeassistant.EntityMetadata
 	if include.Any() {
-		bundle, err := fetchHAEntityMetadataBundleForEntityIDs(ctx, r.ha, include, []string{entityID})
+		bundle, err := fetchHAEntityMetadataBundleForEntityIDs(ctx, ha, include, []string{bareID})
 		if err != nil {
 			return "", err
 		}
-		metadata = bundle.metadata(entityID, state)
+		metadata = bundle.metadata(bareID, state)
 	}
 
+	state.EntityID = homeassistant.QualifyEntityID(instance, state.EntityID)
 	return FormatEntityStateWithMetadata(state, metadata), nil
 }
 
@@ -1353,11 +1520,9 @@ func FormatEntityStateWithMetadata(state *homeassistant.State, metadata *homeass
 }
 
 func (r *Registry) handleListEntities(ctx context.Context, args map[string]any) (string, error) {
-	if r.ha == nil {
-		return "", fmt.Errorf("home assistant not configured")
-	}
-	if !r.ha.IsReady() {
-		return "", fmt.Errorf("home assistant is currently unreachable (reconnecting in background)")
+	ha, instance, _, err := r.haReadyClient(args, "")
+	if err != nil {
+		return "", err
 	}
 
 	domain := strings.TrimSpace(stringArgValue(args, "domain"))
@@ -1384,7 +1549,7 @@ func (r *Registry) handleListEntities(ctx context.Context, args map[string]any)
 	}
 	includeHidden, _ := args["include_hidden"].(bool)
 
-	states, err := r.ha.GetStates(ctx)
+	states, err := ha.GetStates(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -1394,7 +1559,7 @@ func (r *Registry) handleListEntities(ctx context.Context, args map[string]any)
 	// page. Registry is TTL-cached (#1185). Fail open on a registry
 	// error: keep enumeration usable and show everything (nil map =
 	// "no visibility info, no filtering") rather than erroring the tool.
-	visEntries, regErr := entityRegistryByID(ctx, r.ha)
+	visEntries, regErr := entityRegistryByID(ctx, ha)
 	if regErr != nil {
 		r.log().Warn("ha_list_entities: visibility filter degraded; entity registry unavailable", "error", regErr)
 		visEntries = nil
@@ -1443,7 +1608,7 @@ func (r *Registry) handleListEntities(ctx context.Context, args map[string]any)
 		matchStates = append(matchStates, s)
 	}
 	if include.Any() && len(matches) > 0 {
-		bundle, err := fetchHAEntityMetadataBundleForEntityIDs(ctx, r.ha, include, matchEntityIDs)
+		bundle, err := fetchHAEntityMetadataBundleForEntityIDs(ctx, ha, include, matchEntityIDs)
 		if err != nil {
 			return "", err
 		}
@@ -1451,6 +1616,9 @@ func (r *Registry) handleListEntities(ctx context.Context, args map[string]any)
 			matches[i].Metadata = bundle.metadata(matches[i].EntityID, &matchStates[i])
 		}
 	}
+	for i := range matches {
+		matches[i].EntityID = homeassistant.QualifyEntityID(instance, matches[i].EntityID)
+	}
 
 	result := haListEntitiesResult{
 		Domain:         domain,
@@ -1465,16 +1633,21 @@ func (r *Registry) handleListEntities(ctx context.Context, args map[string]any)
 }
 
 func (r *Registry) handleCallService(ctx context.Context, args map[string]any) (string, error) {
-	if r.ha == nil {
-		return "", fmt.Errorf("home assistant not configured")
-	}
-	if !r.ha.IsReady() {
-		return "", fmt.Errorf("home assistant is currently unreachable (reconnecting in background)")
-	}
-
 	domain, _ := args["domain"].(string)
 	service, _ := args["service"].(string)
 	entityID, _ := args["entity_id"].(string)
+	// A qualified target entity selects the instance just as a
+	// qualified entity_id does.
+	routeID := entityID
+	if target, ok := args["target"].(map[string]any); ok && routeID == "" {
+		if ids, err := stringOrList(target["entity_id"]); err == nil && len(ids) > 0 {
+			routeID = ids[0]
+		}
+	}
+	ha, instance, bareID, err := r.haReadyClient(args, routeID)
+	if err != nil {
+		return "", err
+	}
 
 	// A present-but-malformed target must say so, not fall through to
 	// the generic "provide entity_id or target" error.
@@ -1520,15 +1693,15 @@ func (r *Registry) handleCallService(ctx context.Context, args map[string]any) (
 		// no-ops, so a typo'd or stale id otherwise vanishes without feedback.
 		// Probe the entity first (a 404 here is authoritative) and return a
 		// recoverable "did you mean?" suggestion instead of a phantom success.
-		if _, err := r.ha.GetState(ctx, entityID); err != nil {
+		if _, err := ha.GetState(ctx, bareID); err != nil {
 			if IsHAEntityNotFound(err) {
-				return SuggestEntityNotFound(ctx, r.ha, entityID), nil
+				return SuggestEntityNotFound(ctx, ha, bareID), nil
 			}
 			return "", fmt.Errorf("verify entity_id %q before calling %s.%s: %w", entityID, domain, service, err)
 		}
-		data["entity_id"] = entityID
+		data["entity_id"] = bareID
 	} else {
-		resolution, err := r.resolveServiceTarget(ctx, targetRaw)
+		resolution, err := r.resolveServiceTarget(ctx, ha, args, instance, targetRaw)
 		if err != nil {
 			return "", err
 		}
@@ -1541,20 +1714,24 @@ func (r *Registry) handleCallService(ctx context.Context, args map[string]any) (
 		}
 	}
 
-	changed, err := r.ha.CallServiceWithResponse(ctx, domain, service, data)
+	changed, err := ha.CallServiceChangedStates(ctx, domain, service, data)
+	if errors.Is(err, homeassistant.ErrServiceResponseRequired) {
+		return "", fmt.Errorf("%w; use ha_get_service_response to call it and read the data it returns", err)
+	}
 	if err != nil {
 		return "", err
 	}
+	for i := range changed {
+		changed[i].EntityID = homeassistant.QualifyEntityID(instance, changed[i].EntityID)
+	}
 
-	return haCallServiceResponse(domain, service, entityID, resolvedTarget, changed), nil
+	return haCallServiceResponse(domain, service, entityID, qualifyTargetEntities(instance, resolvedTarget), changed), nil
 }
 
 func (r *Registry) handleControlDevice(ctx context.Context, args map[string]any) (string, error) {
-	if r.ha == nil {
-		return "", fmt.Errorf("home assistant not configured")
-	}
-	if !r.ha.IsReady() {
-		return "", fmt.Errorf("home assistant is currently unreachable (reconnecting in background)")
+	ha, instance, _, err := r.haReadyClient(args, "")
+	if err != nil {
+		return "", err
 	}
 
 	description, _ := args["description"].(string)
@@ -1587,7 +1764,7 @@ func (r *Registry) handleControlDevice(ctx context.Context, args map[string]any)
 	// fetching all and deriving the domain slice locally keeps this to a
 	// single bulk GetStates — the inferred-domain match and the broaden-on-
 	// miss suggestion both read from the same payload.
-	allEntities, err := r.ha.GetEntities(ctx, "")
+	allEntities, err := ha.GetEntities(ctx, "")
 	if err != nil {
 		return "", fmt.Errorf("failed to get entities: %w", err)
 	}
@@ -1620,7 +1797,7 @@ func (r *Registry) handleControlDevice(ctx context.Context, args map[string]any)
 				break
 			}
 			candidates = append(candidates, EntitySuggestion{
-				EntityID:     m.EntityID,
+				EntityID:     homeassistant.QualifyEntityID(instance, m.EntityID),
 				FriendlyName: m.FriendlyName,
 				Score:        m.Score,
 			})
@@ -1642,7 +1819,7 @@ func (r *Registry) handleControlDevice(ctx context.Context, args map[string]any)
 	entityID := best.EntityID
 	foundName := best.FriendlyName
 	if foundName == "" {
-		foundName = entityID
+		foundName = homeassistant.QualifyEntityID(instance, entityID)
 	}
 
 	// Build service call
@@ -1670,7 +1847,7 @@ func (r *Registry) handleControlDevice(ctx context.Context, args map[string]any)
 	}
 
 	// Execute the service call
-	if err := r.ha.CallService(ctx, domain, service, data); err != nil {
+	if err := ha.CallService(ctx, domain, service, data); err != nil {
 		return "", fmt.Errorf("failed to control %s: %w", foundName, err)
 	}
 
@@ -1705,12 +1882,13 @@ func (r *Registry) handleControlDevice(ctx context.Context, args map[string]any)
 	case <-timer.C:
 	}
 
-	state, err := r.ha.GetState(ctx, entityID)
+	state, err := ha.GetState(ctx, entityID)
 	if err != nil {
 		// State fetch is best-effort; the action itself succeeded.
 		result += fmt.Sprintf("\n(Could not verify state: %v)", err)
 		return result, nil
 	}
+	state.EntityID = homeassistant.QualifyEntityID(instance, state.EntityID)
 	result += "\nPost-action state:\n" + FormatEntityState(state)
 
 	return result, nil
@@ -1731,7 +1909,7 @@ func (r *Registry) handleScheduleTask(ctx context.Context, args map[string]any)
 	}
 
 	// Parse the "when" parameter
-	schedule, err := parseWhen(when, repeat)
+	schedule, err := parseWhen(when, repeat, r.scheduler.Location())
 	if err != nil {
 		return "", fmt.Errorf("invalid schedule: %w", err)
 	}
@@ -1760,6 +1938,56 @@ func (r *Registry) handleScheduleTask(ctx context.Context, args map[string]any)
 	return fmt.Sprintf("Task '%s' scheduled (ID: %s). Next run: %s", name, task.ID, next), nil
 }
 
+func (r *Registry) handleCreateReminder(ctx context.Context, args map[string]any) (string, error) {
+	if r.scheduler == nil {
+		return "", fmt.Errorf("scheduler not configured")
+	}
+
+	message, _ := args["message"].(string)
+	when, _ := args["when"].(string)
+	name, _ := args["name"].(string)
+	message = strings.TrimSpace(message)
+	if message == "" || when == "" {
+		return "", fmt.Errorf("message and when are required")
+	}
+
+	schedule, err := parseWhen(when, "", r.scheduler.Location())
+	if err != nil {
+		return "", fmt.Errorf("invalid reminder time: %w", err)
+	}
+	if schedule.Kind != scheduler.ScheduleAt {
+		return "", fmt.Errorf("reminders fire once; use task_schedule for a recurring schedule")
+	}
+	schedule.Kind = scheduler.ScheduleOnce
+	now := time.Now()
+	if !schedule.At.After(now) {
+		return "", fmt.Errorf("reminder time %s is in the past", schedule.At.Format(time.RFC3339))
+	}
+
+	if name == "" {
+		name = fmt.Sprintf("reminder:%d", now.UnixNano())
+	}
+	task := &scheduler.Task{
+		Name:     name,
+		Schedule: schedule,
+		Payload: scheduler.Payload{
+			Kind: scheduler.PayloadWake,
+			Data: map[string]any{
+				"message":  "A reminder you set is due now. Deliver it to the person who asked for it: " + message,
+				"reminder": message,
+			},
+		},
+		Enabled:   true,
+		CreatedBy: "agent",
+	}
+	if err := r.scheduler.CreateTask(task); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Reminder set for %s (%s). ID: %s",
+		schedule.At.Format(time.RFC3339), promptfmt.FormatDelta(*schedule.At, now), task.ID), nil
+}
+
 func (r *Registry) handleListTasks(ctx context.Context, args map[string]any) (string, error) {
 	if r.scheduler == nil {
 		return "", fmt.Errorf("scheduler not configured")
@@ -1786,7 +2014,10 @@ func (r *Registry) handleListTasks(ctx context.Context, args map[string]any) (st
 	for _, t := range tasks {
 		next, hasNext := t.NextRun(now)
 		status := "enabled"
-		if !t.Enabled {
+		switch {
+		case t.CompletedAt != nil:
+			status = "completed " + promptfmt.FormatDelta(*t.CompletedAt, now)
+		case !t.Enabled:
 			status = "disabled"
 		}
 
@@ -1835,9 +2066,26 @@ func (r *Registry) handleCancelTask(ctx context.Context, args map[string]any) (s
 }
 
 // parseWhen converts a human-friendly time specification to a Schedule.
-func parseWhen(when, repeat string) (scheduler.Schedule, error) {
+// Dates and times of day without an explicit offset are read in loc,
+// the scheduler's timezone.
+func parseWhen(when, repeat string, loc *time.Location) (scheduler.Schedule, error) {
 	now := time.Now()
 
+	// Cron expressions ("0 7 * * 1-5", "@daily") carry their own
+	// recurrence, so repeat does not apply. Only an expression that
+	// parses is taken as cron; anything else with five words falls
+	// through to the formats below.
+	var cronErr error
+	expr := strings.TrimSpace(when)
+	if strings.HasPrefix(expr, "@") || len(strings.Fields(expr)) == 5 {
+		if _, cronErr = scheduler.ParseCron(expr); cronErr == nil {
+			return scheduler.Schedule{
+				Kind: scheduler.ScheduleCron,
+				Cron: expr,
+			}, nil
+		}
+	}
+
 	// Try parsing as duration first (e.g., "30m", "2h")
 	if dur, err := time.ParseDuration(when); err == nil {
 		if repeat != "" {
@@ -1880,23 +2128,25 @@ func parseWhen(when, repeat string) (scheduler.Schedule, error) {
 		}, nil
 	}
 
-	// Try common date formats
-	formats := []string{
-		"2006-01-02 15:04",
-		"2006-01-02T15:04",
-		"15:04",
-		"3:04pm",
-		"3:04 pm",
-	}
-	for _, format := range formats {
-		if t, err := time.Parse(format, when); err == nil {
-			// For time-only formats, use today's date
-			if format == "15:04" || format == "3:04pm" || format == "3:04 pm" {
-				t = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
-				// If time has passed today, schedule for tomorrow
-				if t.Before(now) {
-					t = t.Add(24 * time.Hour)
-				}
+	// Try common date formats, read as wall-clock time in loc
+	for _, format := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(format, when, loc); err == nil {
+			return scheduler.Schedule{
+				Kind: scheduler.ScheduleAt,
+				At:   &t,
+			}, nil
+		}
+	}
+
+	// Time-of-day formats mean the next occurrence: today, or
+	// tomorrow if that time has already passed.
+	clock := strings.ToLower(strings.TrimSpace(when))
+	for _, format := range []string{"15:04", "3:04pm", "3:04 pm", "3pm", "3 pm"} {
+		if t, err := time.Parse(format, clock); err == nil {
+			local := now.In(loc)
+			t = time.Date(local.Year(), local.Month(), local.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+			if t.Before(now) {
+				t = time.Date(local.Year(), local.Month(), local.Day()+1, t.Hour(), t.Minute(), 0, 0, loc)
 			}
 			return scheduler.Schedule{
 				Kind: scheduler.ScheduleAt,
@@ -1905,6 +2155,9 @@ func parseWhen(when, repeat string) (scheduler.Schedule, error) {
 		}
 	}
 
+	if cronErr != nil {
+		return scheduler.Schedule{}, fmt.Errorf("could not parse time: %s (as cron: %w)", when, cronErr)
+	}
 	return scheduler.Schedule{}, fmt.Errorf("could not parse time: %s", when)
 }
 
@@ -3,11 +3,15 @@ package tools
 import (
 	"context"
 	"errors"
+	"log/slog"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/nugget/thane-ai-agent/internal/integrations/homeassistant"
+	"github.com/nugget/thane-ai-agent/internal/platform/database"
+	"github.com/nugget/thane-ai-agent/internal/platform/scheduler"
 )
 
 func TestExecute_UnknownToolReturnsErrToolUnavailable(t *testing.T) {
@@ -242,3 +246,113 @@ func extractListNames(defs []map[string]any) []string {
 	}
 	return out
 }
+
+func newSchedulerTestRegistry(t *testing.T) (*Registry, *scheduler.Scheduler) {
+	t.Helper()
+	db, err := database.Open(filepath.Join(t.TempDir(), "sched.db"))
+	if err != nil {
+		t.Fatalf("database.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := scheduler.NewStore(db, nil)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	sched := scheduler.New(slog.Default(), store, nil)
+	t.Cleanup(func() {
+		tasks, _ := sched.ListTasks(false)
+		for _, task := range tasks {
+			_ = sched.DeleteTask(task.ID)
+		}
+	})
+	return NewRegistry(nil, sched, nil), sched
+}
+
+func TestParseWhen_Cron(t *testing.T) {
+	for _, when := range []string{"0 7 * * *", "30 9 * * mon-fri", "@daily"} {
+		got, err := parseWhen(when, "", time.UTC)
+		if err != nil {
+			t.Fatalf("parseWhen(%q): %v", when, err)
+		}
+		if got.Kind != scheduler.ScheduleCron || got.Cron != when {
+			t.Errorf("parseWhen(%q) = %+v, want a cron schedule", when, got)
+		}
+	}
+	if _, err := parseWhen("0 25 * * *", "", time.UTC); err == nil {
+		t.Error("parseWhen accepted an out-of-range cron hour")
+	}
+	// Five words that are not cron fall through to the other formats.
+	got, err := parseWhen("in 2 hours from now", "", time.UTC)
+	if err != nil {
+		t.Fatalf("parseWhen(five-word duration): %v", err)
+	}
+	if got.Kind != scheduler.ScheduleAt {
+		t.Errorf("parseWhen(five-word duration) kind = %q, want at", got.Kind)
+	}
+}
+
+func TestParseWhen_TimeOfDayInLocation(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*3600)
+	for _, when := range []string{"3pm", "3 PM", "3:00pm", "15:00"} {
+		got, err := parseWhen(when, "", loc)
+		if err != nil {
+			t.Fatalf("parseWhen(%q): %v", when, err)
+		}
+		if got.Kind != scheduler.ScheduleAt {
+			t.Fatalf("parseWhen(%q) kind = %q, want at", when, got.Kind)
+		}
+		at := got.At.In(loc)
+		if at.Hour() != 15 || at.Minute() != 0 {
+			t.Errorf("parseWhen(%q) = %s, want 15:00 in %s", when, at, loc)
+		}
+		if !got.At.After(time.Now()) || got.At.After(time.Now().Add(24*time.Hour)) {
+			t.Errorf("parseWhen(%q) = %s, want the next 15:00", when, at)
+		}
+	}
+
+	got, err := parseWhen("2030-06-01 09:30", "", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2030, 6, 1, 9, 30, 0, 0, loc); !got.At.Equal(want) {
+		t.Errorf("dated time = %s, want %s", got.At, want)
+	}
+}
+
+func TestCreateReminder(t *testing.T) {
+	reg, sched := newSchedulerTestRegistry(t)
+
+	out, err := reg.Execute(context.Background(), "create_reminder", `{"message":"call the vet","when":"2h"}`)
+	if err != nil {
+		t.Fatalf("create_reminder: %v", err)
+	}
+	if !strings.Contains(out, "Reminder set for") {
+		t.Errorf("output = %q, want a confirmation", out)
+	}
+
+	tasks, err := sched.ListTasks(true)
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("ListTasks = %v, %v; want one task", tasks, err)
+	}
+	task := tasks[0]
+	if task.Schedule.Kind != scheduler.ScheduleOnce || task.Schedule.At == nil {
+		t.Errorf("schedule = %+v, want a one-shot", task.Schedule)
+	}
+	if task.Payload.Data["reminder"] != "call the vet" {
+		t.Errorf("payload = %+v, want the reminder text", task.Payload.Data)
+	}
+}
+
+func TestCreateReminder_Rejects(t *testing.T) {
+	reg, _ := newSchedulerTestRegistry(t)
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	for _, args := range []string{
+		`{"message":"stand up","when":"0 9 * * *"}`,
+		`{"message":"too late","when":"` + past + `"}`,
+		`{"message":"","when":"2h"}`,
+	} {
+		if _, err := reg.Execute(context.Background(), "create_reminder", args); err == nil {
+			t.Errorf("create_reminder(%s) succeeded, want error", args)
+		}
+	}
+}
@@ -18,7 +18,7 @@ func (r *Registry) registerCostSummary() {
 
 	r.Register(&Tool{
 		Name:        "cost_summary",
-		Description: "Query your own token usage and API costs. Returns totals and optional breakdown by deployment, upstream model, provider, resource, role, or task. Use to understand spending patterns and resource consumption.",
+		Description: "Query your own token usage and API costs. Returns totals and optional breakdown by deployment, upstream model, provider, resource, role, task, failover reason, failed model, or prompt version. Use to understand spending patterns and resource consumption.",
 		Parameters: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
@@ -29,8 +29,8 @@ func (r *Registry) registerCostSummary() {
 				},
 				"group_by": map[string]any{
 					"type":        "string",
-					"enum":        []string{"deployment", "model", "upstream_model", "provider", "resource", "role", "task"},
-					"description": "Optional: group results by deployment ID (deployment or model), upstream model, provider, resource, role, or task name.",
+					"enum":        []string{"deployment", "model", "upstream_model", "provider", "resource", "role", "task", "failover_reason", "failover_from", "prompt_version"},
+					"description": "Optional: group results by deployment ID (deployment or model), upstream model, provider, resource, role, task name, failover reason, the model that failed (failover_from), or prompt version. Each failover is one record under the failover role for the call to the fallback model, with that call's tokens and cost. Prompt versions tag internal calls such as fact extraction, so their cost can be compared across prompt revisions.",
 				},
 			},
 			"required": []string{"period"},
@@ -117,8 +117,11 @@ func queryGrouped(store *usage.Store, groupBy string, start, end time.Time) ([]u
 	case "task":
 		result, err := store.SummaryByGroup(groupBy, start, end)
 		return result, "Task", err
+	case "prompt_version":
+		result, err := store.SummaryByGroup(groupBy, start, end)
+		return result, "Prompt Version", err
 	default:
-		return nil, "", fmt.Errorf("unsupported group_by %q; use one of: deployment, model, upstream_model, provider, resource, role, task", groupBy)
+		return nil, "", fmt.Errorf("unsupported group_by %q; use one of: deployment, model, upstream_model, provider, resource, role, task, prompt_version", groupBy)
 	}
 }
 
@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/nugget/thane-ai-agent/internal/model/promptfmt"
@@ -28,7 +29,9 @@ func (r *Registry) SetWorkingMemoryStore(store *memory.WorkingMemoryStore) {
 			"Working memory is your private scratchpad for experiential context: " +
 			"emotional tone, conversational arc, relationship dynamics, and unresolved threads. " +
 			"It persists across compaction and is auto-injected into your context each turn. " +
-			"Use 'read' to see current contents, 'write' to replace entirely.",
+			"Use 'read' to see current contents, 'write' to replace entirely. " +
+			"Pass 'tag' to scope an entry to a capability tag: it is injected only while that tag is active, " +
+			"keeping role-specific notes out of unrelated work. Untagged memory is always injected.",
 		Parameters: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
@@ -41,20 +44,38 @@ func (r *Registry) SetWorkingMemoryStore(store *memory.WorkingMemoryStore) {
 					"type":        "string",
 					"description": "New working memory content (required for 'write'). Write in first person as notes to your future self.",
 				},
+				"tag": map[string]any{
+					"type":        "string",
+					"description": "Optional capability tag name to scope this entry to (lowercase, e.g. 'ha'). Omit for the always-injected conversation memory.",
+				},
 			},
 			"required": []string{"action"},
 		},
 		Handler: func(ctx context.Context, args map[string]any) (string, error) {
 			action, _ := args["action"].(string)
+			tag, _ := args["tag"].(string)
+			tag = strings.TrimSpace(tag)
 			convID := ConversationIDFromContext(ctx)
 
 			switch action {
 			case "read":
-				content, updatedAt, err := store.Get(convID)
+				var (
+					content   string
+					updatedAt time.Time
+					err       error
+				)
+				if tag != "" {
+					content, updatedAt, err = store.GetTagged(convID, tag)
+				} else {
+					content, updatedAt, err = store.Get(convID)
+				}
 				if err != nil {
 					return "", fmt.Errorf("read working memory: %w", err)
 				}
 				if content == "" {
+					if tag != "" {
+						return fmt.Sprintf("(no working memory for tag %q in this conversation)", tag), nil
+					}
 					return "(no working memory for this conversation)", nil
 				}
 				return fmt.Sprintf("Last updated: %s\n\n%s", promptfmt.FormatDeltaOnly(updatedAt, time.Now()), content), nil
@@ -64,6 +85,12 @@ func (r *Registry) SetWorkingMemoryStore(store *memory.WorkingMemoryStore) {
 				if content == "" {
 					return "", fmt.Errorf("content is required for write action")
 				}
+				if tag != "" {
+					if err := store.SetTagged(convID, tag, content); err != nil {
+						return "", fmt.Errorf("write working memory: %w", err)
+					}
+					return fmt.Sprintf("Working memory for tag %q updated.", tag), nil
+				}
 				if err := store.Set(convID, content); err != nil {
 					return "", fmt.Errorf("write working memory: %w", err)
 				}